@@ -35,6 +35,7 @@ const (
 	NamespaceCapabilityAllocExec            = "alloc-exec"
 	NamespaceCapabilityAllocNodeExec        = "alloc-node-exec"
 	NamespaceCapabilityAllocLifecycle       = "alloc-lifecycle"
+	NamespaceCapabilityAllocDebugShell      = "alloc-debug-shell"
 	NamespaceCapabilitySentinelOverride     = "sentinel-override"
 	NamespaceCapabilityCSIRegisterPlugin    = "csi-register-plugin"
 	NamespaceCapabilityCSIWriteVolume       = "csi-write-volume"
@@ -46,6 +47,7 @@ const (
 	NamespaceCapabilityReadJobScaling       = "read-job-scaling"
 	NamespaceCapabilityScaleJob             = "scale-job"
 	NamespaceCapabilitySubmitRecommendation = "submit-recommendation"
+	NamespaceCapabilityUnfreeze             = "unfreeze"
 )
 
 var (
@@ -170,9 +172,10 @@ func isNamespaceCapabilityValid(cap string) bool {
 	case NamespaceCapabilityDeny, NamespaceCapabilityParseJob, NamespaceCapabilityListJobs, NamespaceCapabilityReadJob,
 		NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob, NamespaceCapabilityReadLogs,
 		NamespaceCapabilityReadFS, NamespaceCapabilityAllocLifecycle,
-		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec,
+		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec, NamespaceCapabilityAllocDebugShell,
 		NamespaceCapabilityCSIReadVolume, NamespaceCapabilityCSIWriteVolume, NamespaceCapabilityCSIListVolume, NamespaceCapabilityCSIMountVolume, NamespaceCapabilityCSIRegisterPlugin,
-		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy, NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob:
+		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy, NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob,
+		NamespaceCapabilityUnfreeze:
 		return true
 	// Separate the enterprise-only capabilities
 	case NamespaceCapabilitySentinelOverride, NamespaceCapabilitySubmitRecommendation:
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 // Agent encapsulates an API client which talks to Nomad's
@@ -24,7 +25,11 @@ type Agent struct {
 type KeyringResponse struct {
 	Messages map[string]string
 	Keys     map[string]int
-	NumNodes int
+
+	// PrimaryKeys maps each key to the number of nodes using it as their
+	// primary encryption key.
+	PrimaryKeys map[string]int
+	NumNodes    int
 }
 
 // KeyringRequest is request objects for serf key operations.
@@ -55,6 +60,64 @@ func (a *Agent) Self() (*AgentSelf, error) {
 	return out, nil
 }
 
+// EffectiveConfig returns the redacted configuration of the agent flattened
+// by field path, with each value annotated by where it was set.
+func (a *Agent) EffectiveConfig() (map[string]*AgentConfigValue, error) {
+	var out map[string]*AgentConfigValue
+	q := &QueryOptions{Params: map[string]string{"effective": "true"}}
+	if _, err := a.client.query("/v1/agent/config", &out, q); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LogLevelOverride is a log level set at runtime for a subsystem of the agent.
+// It applies to the named logger and the loggers named after it.
+type LogLevelOverride struct {
+	Name      string
+	Level     string
+	ExpiresAt time.Time
+}
+
+// LogLevels returns the log levels overridden at runtime on the agent.
+func (a *Agent) LogLevels(q *QueryOptions) ([]*LogLevelOverride, error) {
+	var out []*LogLevelOverride
+	if _, err := a.client.query("/v1/agent/log-levels", &out, q); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SetLogLevel overrides the log level of the named logger on the agent until
+// the duration elapses. The agent picks a default duration if it is zero.
+func (a *Agent) SetLogLevel(name, level string, duration time.Duration, q *WriteOptions) ([]*LogLevelOverride, error) {
+	v := url.Values{}
+	v.Set("name", name)
+	v.Set("level", level)
+	if duration != 0 {
+		v.Set("duration", duration.String())
+	}
+
+	var out []*LogLevelOverride
+	if _, err := a.client.write("/v1/agent/log-levels?"+v.Encode(), nil, &out, q); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResetLogLevel removes the log level override of the named logger on the
+// agent.
+func (a *Agent) ResetLogLevel(name string, q *WriteOptions) ([]*LogLevelOverride, error) {
+	v := url.Values{}
+	v.Set("name", name)
+
+	var out []*LogLevelOverride
+	if _, err := a.client.delete("/v1/agent/log-levels?"+v.Encode(), nil, &out, q); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // populateCache is used to insert various pieces of static
 // data into the agent handle. This is used during subsequent
 // lookups for the same data later on to save the round trip.
@@ -164,6 +227,18 @@ func (a *Agent) ForceLeave(node string) error {
 	return err
 }
 
+// PrepareUpgrade flushes the state of a client agent before it is restarted in
+// place, such as to upgrade it, and reports the running tasks the restarted
+// client won't be able to reattach to.
+func (a *Agent) PrepareUpgrade() (*PrepareUpgradeResponse, error) {
+	var resp PrepareUpgradeResponse
+	_, err := a.client.write("/v1/agent/prepare-upgrade", nil, &resp, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // Servers is used to query the list of servers on a client node.
 func (a *Agent) Servers() ([]string, error) {
 	var resp []string
@@ -426,6 +501,19 @@ type AgentSelf struct {
 	Stats  map[string]map[string]string `json:"stats"`
 }
 
+// AgentConfigValue is the effective value of an agent configuration field
+// along with its source.
+type AgentConfigValue struct {
+	Value interface{}
+
+	// Source is one of default, file, env or flag.
+	Source string
+
+	// Name is the configuration file, environment variable or flag that set
+	// the value, if known.
+	Name string
+}
+
 // AgentMember represents a cluster member known to the agent
 type AgentMember struct {
 	Name        string
@@ -460,6 +548,17 @@ func (a AgentMembersNameSort) Less(i, j int) bool {
 
 }
 
+// PrepareUpgradeResponse is the response from the PrepareUpgrade endpoint.
+type PrepareUpgradeResponse struct {
+	// Recoverable is the number of running tasks the restarted client would
+	// reattach to.
+	Recoverable int
+
+	// Unrecoverable are the running tasks the restarted client wouldn't be
+	// able to reattach to.
+	Unrecoverable []*NodeTaskRestoreFailure
+}
+
 // AgentHealthResponse is the response from the Health endpoint describing an
 // agent's health.
 type AgentHealthResponse struct {
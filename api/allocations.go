@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"sort"
 	"time"
 )
@@ -99,6 +100,34 @@ func (a *Allocations) Exec(ctx context.Context,
 	return s.run(ctx)
 }
 
+// Shell runs a debug shell in a task of the allocation. The debug shell
+// binary is provided by the client, so tasks without a shell of their own can
+// be debugged. The command is passed as arguments to the debug shell binary
+// and defaults to an interactive shell if empty.
+func (a *Allocations) Shell(ctx context.Context,
+	alloc *Allocation, task string, tty bool, command []string,
+	stdin io.Reader, stdout, stderr io.Writer,
+	terminalSizeCh <-chan TerminalSize, q *QueryOptions) (exitCode int, err error) {
+
+	s := &execSession{
+		client:     a.client,
+		alloc:      alloc,
+		task:       task,
+		tty:        tty,
+		command:    command,
+		debugShell: true,
+
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+
+		terminalSizeCh: terminalSizeCh,
+		q:              q,
+	}
+
+	return s.run(ctx)
+}
+
 func (a *Allocations) Stats(alloc *Allocation, q *QueryOptions) (*AllocResourceUsage, error) {
 	var resp AllocResourceUsage
 	path := fmt.Sprintf("/v1/client/allocation/%s/stats", alloc.ID)
@@ -136,6 +165,38 @@ type AllocStopResponse struct {
 	WriteMeta
 }
 
+// Pin pins the allocation to its node, so that it is not migrated off its
+// node when draining nor replaced while its node is down, until it is unpinned,
+// stopped, or the drain deadline of its node is reached.
+func (a *Allocations) Pin(alloc *Allocation, q *WriteOptions) (*AllocPinResponse, error) {
+	var resp AllocPinResponse
+	wm, err := a.client.write("/v1/allocation/"+alloc.ID+"/pin", nil, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
+// Unpin unpins the allocation, allowing it to be migrated again.
+func (a *Allocations) Unpin(alloc *Allocation, q *WriteOptions) (*AllocPinResponse, error) {
+	var resp AllocPinResponse
+	wm, err := a.client.write("/v1/allocation/"+alloc.ID+"/unpin", nil, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
+// AllocPinResponse is the response to pinning or unpinning an allocation.
+type AllocPinResponse struct {
+	// EvalID is the id of the follow up evaluation for the job of the alloc.
+	EvalID string
+
+	WriteMeta
+}
+
 func (a *Allocations) Signal(alloc *Allocation, q *QueryOptions, task, signal string) error {
 	req := AllocSignalRequest{
 		Signal: signal,
@@ -155,6 +216,38 @@ func (a *Allocations) Services(allocID string, q *QueryOptions) ([]*ServiceRegis
 	return resp, qm, err
 }
 
+// Templates is used to return the rendering state of the templates of the
+// allocation and the dependencies they watch, keyed by task. If task is set,
+// only the templates of the task are returned.
+func (a *Allocations) Templates(alloc *Allocation, task string, q *QueryOptions) (map[string][]*TemplateStatus, error) {
+	var resp map[string][]*TemplateStatus
+	path := "/v1/client/allocation/" + alloc.ID + "/templates"
+	if task != "" {
+		path += "?task=" + url.QueryEscape(task)
+	}
+	_, err := a.client.query(path, &resp, q)
+	return resp, err
+}
+
+// TemplateStatus is the rendering state of a template and of the
+// dependencies it watches.
+type TemplateStatus struct {
+	DestPath     string
+	Rendered     bool
+	LastRendered time.Time
+	LastUpdate   time.Time
+	Error        string
+	Dependencies []*TemplateDependency
+}
+
+// TemplateDependency is the state of a dependency watched by a template.
+type TemplateDependency struct {
+	Type   string
+	Name   string
+	Status string
+	Error  string
+}
+
 // Allocation is used for serialization of allocations.
 type Allocation struct {
 	ID                    string
@@ -173,6 +266,7 @@ type Allocation struct {
 	Metrics               *AllocationMetric
 	DesiredStatus         string
 	DesiredDescription    string
+	StopReason            string
 	DesiredTransition     DesiredTransition
 	ClientStatus          string
 	ClientDescription     string
@@ -232,8 +326,10 @@ func (a *Allocation) Stub() *AllocationListStub {
 		JobType:               *a.Job.Type,
 		JobVersion:            *a.Job.Version,
 		TaskGroup:             a.TaskGroup,
+		KillPriority:          a.KillPriority(),
 		DesiredStatus:         a.DesiredStatus,
 		DesiredDescription:    a.DesiredDescription,
+		StopReason:            a.StopReason,
 		ClientStatus:          a.ClientStatus,
 		ClientDescription:     a.ClientDescription,
 		TaskStates:            a.TaskStates,
@@ -249,6 +345,24 @@ func (a *Allocation) Stub() *AllocationListStub {
 	}
 }
 
+// KillPriority returns the priority of the allocation when several allocations
+// of a node must be stopped. Allocations with a lower kill priority are stopped
+// first. It is the job priority plus the shutdown priority of the task group.
+func (a *Allocation) KillPriority() int {
+	if a.Job == nil {
+		return 0
+	}
+
+	priority := 0
+	if a.Job.Priority != nil {
+		priority = *a.Job.Priority
+	}
+	if tg := a.Job.LookupTaskGroup(a.TaskGroup); tg != nil && tg.ShutdownPriority != nil {
+		priority += *tg.ShutdownPriority
+	}
+	return priority
+}
+
 // ServerTerminalStatus returns true if the desired state of the allocation is
 // terminal.
 func (a *Allocation) ServerTerminalStatus() bool {
@@ -284,9 +398,11 @@ type AllocationListStub struct {
 	JobType               string
 	JobVersion            uint64
 	TaskGroup             string
+	KillPriority          int
 	AllocatedResources    *AllocatedResources `json:",omitempty"`
 	DesiredStatus         string
 	DesiredDescription    string
+	StopReason            string
 	ClientStatus          string
 	ClientDescription     string
 	TaskStates            map[string]*TaskState
@@ -305,10 +421,11 @@ type AllocationListStub struct {
 // deployment. This can include things like if the allocation has been marked as
 // healthy.
 type AllocDeploymentStatus struct {
-	Healthy     *bool
-	Timestamp   time.Time
-	Canary      bool
-	ModifyIndex uint64
+	Healthy      *bool
+	Timestamp    time.Time
+	Canary       bool
+	FailedChecks []string
+	ModifyIndex  uint64
 }
 
 type AllocatedResources struct {
@@ -444,6 +561,10 @@ type DesiredTransition struct {
 	// Reschedule is used to indicate that this allocation is eligible to be
 	// rescheduled.
 	Reschedule *bool
+
+	// Pinned is used to indicate that this allocation must not be migrated
+	// off a draining node nor replaced while its node is down.
+	Pinned *bool
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -451,6 +572,12 @@ func (d DesiredTransition) ShouldMigrate() bool {
 	return d.Migrate != nil && *d.Migrate
 }
 
+// ShouldPin returns whether the transition object dictates that the
+// allocation stays on its node.
+func (d DesiredTransition) ShouldPin() bool {
+	return d.Pinned != nil && *d.Pinned
+}
+
 // ExecStreamingIOOperation represents a stream write operation: either appending data or close (exclusively)
 type ExecStreamingIOOperation struct {
 	Data  []byte `json:"data,omitempty"`
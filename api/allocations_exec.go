@@ -26,6 +26,8 @@ type execSession struct {
 	tty     bool
 	command []string
 
+	debugShell bool
+
 	stdin  io.Reader
 	stdout io.Writer
 	stderr io.Writer
@@ -91,6 +93,9 @@ func (s *execSession) startConnection() (*websocket.Conn, error) {
 	q.Params["tty"] = strconv.FormatBool(s.tty)
 	q.Params["task"] = s.task
 	q.Params["command"] = string(commandBytes)
+	if s.debugShell {
+		q.Params["debug_shell"] = "true"
+	}
 
 	reqPath := fmt.Sprintf("/v1/client/allocation/%s/exec", s.alloc.ID)
 
@@ -2,6 +2,7 @@ package api
 
 const (
 	ConstraintDistinctProperty  = "distinct_property"
+	ConstraintDistinctTopology  = "distinct_topology"
 	ConstraintDistinctHosts     = "distinct_hosts"
 	ConstraintRegex             = "regexp"
 	ConstraintVersion           = "version"
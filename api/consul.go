@@ -78,17 +78,19 @@ func (css *ConsulSidecarService) Canonicalize() {
 // SidecarTask represents a subset of Task fields that can be set to override
 // the fields of the Task generated for the sidecar
 type SidecarTask struct {
-	Name          string                 `hcl:"name,optional"`
-	Driver        string                 `hcl:"driver,optional"`
-	User          string                 `hcl:"user,optional"`
-	Config        map[string]interface{} `hcl:"config,block"`
-	Env           map[string]string      `hcl:"env,block"`
-	Resources     *Resources             `hcl:"resources,block"`
-	Meta          map[string]string      `hcl:"meta,block"`
-	KillTimeout   *time.Duration         `mapstructure:"kill_timeout" hcl:"kill_timeout,optional"`
-	LogConfig     *LogConfig             `mapstructure:"logs" hcl:"logs,block"`
-	ShutdownDelay *time.Duration         `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
-	KillSignal    string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
+	Name               string                 `hcl:"name,optional"`
+	Driver             string                 `hcl:"driver,optional"`
+	User               string                 `hcl:"user,optional"`
+	Config             map[string]interface{} `hcl:"config,block"`
+	Env                map[string]string      `hcl:"env,block"`
+	Resources          *Resources             `hcl:"resources,block"`
+	Meta               map[string]string      `hcl:"meta,block"`
+	KillTimeout        *time.Duration         `mapstructure:"kill_timeout" hcl:"kill_timeout,optional"`
+	LogConfig          *LogConfig             `mapstructure:"logs" hcl:"logs,block"`
+	ShutdownDelay      *time.Duration         `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
+	KillSignal         string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
+	EnvoyVersion       string                 `mapstructure:"envoy_version" hcl:"envoy_version,optional"`
+	ScaleWithUpstreams bool                   `mapstructure:"scale_with_upstreams" hcl:"scale_with_upstreams,optional"`
 }
 
 func (st *SidecarTask) Canonicalize() {
@@ -104,8 +106,12 @@ func (st *SidecarTask) Canonicalize() {
 		st.Env = nil
 	}
 
+	// Resources scaled with the number of upstreams of the proxy are set by
+	// the server.
 	if st.Resources == nil {
-		st.Resources = DefaultResources()
+		if !st.ScaleWithUpstreams {
+			st.Resources = DefaultResources()
+		}
 	} else {
 		st.Resources.Canonicalize()
 	}
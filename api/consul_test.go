@@ -235,6 +235,12 @@ func TestSidecarTask_Canonicalize(t *testing.T) {
 		st.Canonicalize()
 		require.Equal(t, exp, st.Resources)
 	})
+
+	t.Run("scale_with_upstreams", func(t *testing.T) {
+		st := &SidecarTask{ScaleWithUpstreams: true}
+		st.Canonicalize()
+		require.Nil(t, st.Resources)
+	})
 }
 
 func TestConsulGateway_Canonicalize(t *testing.T) {
@@ -26,6 +26,17 @@ func (e *Evaluations) List(q *QueryOptions) ([]*Evaluation, *QueryMeta, error) {
 	return resp, qm, nil
 }
 
+// BlockedResources is used to query the resources requested by blocked
+// evaluations by datacenter and node class.
+func (e *Evaluations) BlockedResources(q *QueryOptions) ([]*BlockedResourcesDemand, *QueryMeta, error) {
+	var resp []*BlockedResourcesDemand
+	qm, err := e.client.query("/v1/evaluations/blocked-resources", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
 func (e *Evaluations) PrefixList(prefix string) ([]*Evaluation, *QueryMeta, error) {
 	return e.List(&QueryOptions{Prefix: prefix})
 }
@@ -52,6 +63,22 @@ func (e *Evaluations) Delete(evalIDs []string, w *WriteOptions) (*WriteMeta, err
 	return wm, nil
 }
 
+// Retry is used to re-create a blocked or failed evaluation. The overrides
+// are optional and replace the placement options of the job for the new
+// evaluation.
+func (e *Evaluations) Retry(evalID string, overrides *EvalOverrides, w *WriteOptions) (*EvalRetryResponse, *WriteMeta, error) {
+	req := &EvalRetryRequest{
+		EvalID:    evalID,
+		Overrides: overrides,
+	}
+	var resp EvalRetryResponse
+	wm, err := e.client.write("/v1/evaluation/"+evalID+"/retry", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Allocations is used to retrieve a set of allocations given
 // an evaluation ID.
 func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
@@ -68,6 +95,7 @@ func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*Allocation
 type Evaluation struct {
 	ID                   string
 	Priority             int
+	Deadline             int64
 	Type                 string
 	TriggeredBy          string
 	Namespace            string
@@ -90,6 +118,7 @@ type Evaluation struct {
 	QuotaLimitReached    string
 	AnnotatePlan         bool
 	QueuedAllocations    map[string]int
+	Overrides            *EvalOverrides
 	SnapshotIndex        uint64
 	CreateIndex          uint64
 	ModifyIndex          uint64
@@ -125,6 +154,35 @@ type EvalDeleteRequest struct {
 	WriteRequest
 }
 
+// EvalOverrides are placement options which replace those of the job when
+// a retried evaluation is processed.
+type EvalOverrides struct {
+	Datacenters           []string
+	IgnoreSoftConstraints bool
+}
+
+type EvalRetryRequest struct {
+	EvalID    string
+	Overrides *EvalOverrides
+	WriteRequest
+}
+
+type EvalRetryResponse struct {
+	EvalID          string
+	EvalCreateIndex uint64
+	WriteMeta
+}
+
+// BlockedResourcesDemand is the resources requested by the evaluations
+// blocked on the nodes of a node class in a datacenter.
+type BlockedResourcesDemand struct {
+	Datacenter string
+	NodeClass  string
+	Evals      int
+	CPU        int
+	MemoryMB   int
+}
+
 // EvalIndexSort is a wrapper to sort evaluations by CreateIndex.
 // We reverse the test so that we get the highest index first.
 type EvalIndexSort []*Evaluation
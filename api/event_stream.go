@@ -202,3 +202,28 @@ func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, ind
 
 	return eventsCh, nil
 }
+
+// EventBrokerMetrics summarizes the events published by the event broker of a
+// server since it started, and how its subscribers keep up with them.
+type EventBrokerMetrics struct {
+	Published            map[Topic]map[string]uint64
+	LatestIndex          uint64
+	BufferLen            int
+	Subscribers          int
+	LaggingSubscribers   int
+	MaxSubscriberLag     uint64
+	Subscriptions        uint64
+	DroppedSubscriptions uint64
+	ClosedSubscriptions  uint64
+}
+
+// Metrics returns the metrics of the event broker of the leader, or of the
+// queried server if the query allows stale reads.
+func (e *EventStream) Metrics(q *QueryOptions) (*EventBrokerMetrics, *QueryMeta, error) {
+	var resp EventBrokerMetrics
+	qm, err := e.client.query("/v1/event/metrics", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -49,6 +50,31 @@ func TestEvent_Stream(t *testing.T) {
 	}
 }
 
+func TestEvent_Metrics(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	// register job to generate events
+	job := testJob()
+	_, _, err := c.Jobs().Register(job, nil)
+	require.NoError(t, err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		m, _, err := c.EventStream().Metrics(nil)
+		if err != nil {
+			return false, err
+		}
+		if m.Published[TopicJob]["JobRegistered"] < 1 {
+			return false, fmt.Errorf("expected published job event, got %#v", m.Published)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
 func TestEvent_Stream_Err_InvalidQueryParam(t *testing.T) {
 	testutil.Parallel(t)
 
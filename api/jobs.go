@@ -190,6 +190,12 @@ func (j *Jobs) Scale(jobID, group string, count *int, message string, error bool
 		Message: message,
 		Meta:    meta,
 	}
+	return j.ScaleWithRequest(jobID, req, q)
+}
+
+// ScaleWithRequest is used to scale a job with a full scaling request, for
+// example to enforce the job modify index.
+func (j *Jobs) ScaleWithRequest(jobID string, req *ScalingRequest, q *WriteOptions) (*JobRegisterResponse, *WriteMeta, error) {
 	var resp JobRegisterResponse
 	qm, err := j.client.write(fmt.Sprintf("/v1/job/%s/scale", url.PathEscape(jobID)), req, &resp, q)
 	if err != nil {
@@ -260,6 +266,17 @@ func (j *Jobs) Deployments(jobID string, all bool, q *QueryOptions) ([]*Deployme
 	return resp, qm, nil
 }
 
+// Lineage is used to query the lineage of a job: the evaluations,
+// deployments and allocations caused by its versions, as a forest.
+func (j *Jobs) Lineage(jobID string, q *QueryOptions) ([]*JobLineageNode, *QueryMeta, error) {
+	var resp []*JobLineageNode
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/lineage", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
 // LatestDeployment is used to query for the latest deployment associated with
 // the given job ID.
 func (j *Jobs) LatestDeployment(jobID string, q *QueryOptions) (*Deployment, *QueryMeta, error) {
@@ -317,6 +334,11 @@ type DeregisterOptions struct {
 	// task shutdown_delay configuration and ignore the delay for any
 	// allocations stopped as a result of this Deregister call.
 	NoShutdownDelay bool
+
+	// If EnforceIndex is set the job will only be deregistered if the passed
+	// ModifyIndex matches the current job's index.
+	EnforceIndex bool
+	ModifyIndex  uint64
 }
 
 // DeregisterOpts is used to remove an existing job. See DeregisterOptions
@@ -332,6 +354,9 @@ func (j *Jobs) DeregisterOpts(jobID string, opts *DeregisterOptions, q *WriteOpt
 	if opts != nil {
 		endpoint += fmt.Sprintf("?purge=%t&global=%t&eval_priority=%v&no_shutdown_delay=%t",
 			opts.Purge, opts.Global, opts.EvalPriority, opts.NoShutdownDelay)
+		if opts.EnforceIndex {
+			endpoint += fmt.Sprintf("&job_modify_index=%d", opts.ModifyIndex)
+		}
 	}
 
 	wm, err := j.client.delete(endpoint, nil, &resp, q)
@@ -381,6 +406,9 @@ func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta,
 type PlanOptions struct {
 	Diff           bool
 	PolicyOverride bool
+
+	// Verbose explains the placements of the plan in its annotations.
+	Verbose bool
 }
 
 func (j *Jobs) Plan(job *Job, diff bool, q *WriteOptions) (*JobPlanResponse, *WriteMeta, error) {
@@ -400,6 +428,7 @@ func (j *Jobs) PlanOpts(job *Job, opts *PlanOptions, q *WriteOptions) (*JobPlanR
 	if opts != nil {
 		req.Diff = opts.Diff
 		req.PolicyOverride = opts.PolicyOverride
+		req.Verbose = opts.Verbose
 	}
 
 	var resp JobPlanResponse
@@ -472,6 +501,43 @@ func (j *Jobs) Stable(jobID string, version uint64, stable bool,
 	return &resp, wm, nil
 }
 
+// Freeze is used to freeze or unfreeze a job. Updates, scaling and
+// dispatches of a frozen job are rejected unless the token has the unfreeze
+// capability.
+func (j *Jobs) Freeze(jobID string, frozen bool, q *WriteOptions) (*JobFreezeResponse, *WriteMeta, error) {
+	var resp JobFreezeResponse
+	req := &JobFreezeRequest{
+		JobID:  jobID,
+		Frozen: frozen,
+	}
+	wm, err := j.client.write("/v1/job/"+url.PathEscape(jobID)+"/freeze", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Restore is used to restore a purged job from the job trash. The job is
+// restored stopped along with its versions.
+func (j *Jobs) Restore(jobID string, q *WriteOptions) (*JobRestoreResponse, *WriteMeta, error) {
+	var resp JobRestoreResponse
+	wm, err := j.client.write("/v1/job/"+url.PathEscape(jobID)+"/restore", nil, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// ListTrash is used to list the purged jobs in the job trash.
+func (j *Jobs) ListTrash(q *QueryOptions) ([]*TrashedJobListStub, *QueryMeta, error) {
+	var resp []*TrashedJobListStub
+	qm, err := j.client.query("/v1/jobs/trash", &resp, q)
+	if err != nil {
+		return nil, qm, err
+	}
+	return resp, qm, nil
+}
+
 // Services is used to return a list of service registrations associated to the
 // specified jobID.
 func (j *Jobs) Services(jobID string, q *QueryOptions) ([]*ServiceRegistration, *QueryMeta, error) {
@@ -496,6 +562,7 @@ type UpdateStrategy struct {
 	Canary           *int           `mapstructure:"canary" hcl:"canary,optional"`
 	AutoRevert       *bool          `mapstructure:"auto_revert" hcl:"auto_revert,optional"`
 	AutoPromote      *bool          `mapstructure:"auto_promote" hcl:"auto_promote,optional"`
+	PromotionGate    *string        `mapstructure:"promotion_gate" hcl:"promotion_gate,optional"`
 }
 
 // DefaultUpdateStrategy provides a baseline that can be used to upgrade
@@ -511,6 +578,7 @@ func DefaultUpdateStrategy() *UpdateStrategy {
 		AutoRevert:       boolToPtr(false),
 		Canary:           intToPtr(0),
 		AutoPromote:      boolToPtr(false),
+		PromotionGate:    stringToPtr(""),
 	}
 }
 
@@ -557,6 +625,10 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.AutoPromote = boolToPtr(*u.AutoPromote)
 	}
 
+	if u.PromotionGate != nil {
+		copy.PromotionGate = stringToPtr(*u.PromotionGate)
+	}
+
 	return copy
 }
 
@@ -600,6 +672,10 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 	if o.AutoPromote != nil {
 		u.AutoPromote = boolToPtr(*o.AutoPromote)
 	}
+
+	if o.PromotionGate != nil {
+		u.PromotionGate = stringToPtr(*o.PromotionGate)
+	}
 }
 
 func (u *UpdateStrategy) Canonicalize() {
@@ -640,6 +716,10 @@ func (u *UpdateStrategy) Canonicalize() {
 	if u.AutoPromote == nil {
 		u.AutoPromote = d.AutoPromote
 	}
+
+	if u.PromotionGate == nil {
+		u.PromotionGate = d.PromotionGate
+	}
 }
 
 // Empty returns whether the UpdateStrategy is empty or has user defined values.
@@ -680,6 +760,10 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.PromotionGate != nil && *u.PromotionGate != "" {
+		return false
+	}
+
 	if u.Canary != nil && *u.Canary != 0 {
 		return false
 	}
@@ -825,9 +909,17 @@ func (p *PeriodicConfig) GetLocation() (*time.Location, error) {
 
 // ParameterizedJobConfig is used to configure the parameterized job.
 type ParameterizedJobConfig struct {
-	Payload      string   `hcl:"payload,optional"`
-	MetaRequired []string `mapstructure:"meta_required" hcl:"meta_required,optional"`
-	MetaOptional []string `mapstructure:"meta_optional" hcl:"meta_optional,optional"`
+	Payload      string          `hcl:"payload,optional"`
+	MetaRequired []string        `mapstructure:"meta_required" hcl:"meta_required,optional"`
+	MetaOptional []string        `mapstructure:"meta_optional" hcl:"meta_optional,optional"`
+	Dispatch     *DispatchConfig `hcl:"dispatch,block"`
+}
+
+// DispatchConfig is used to configure the purging of completed dispatched
+// jobs.
+type DispatchConfig struct {
+	PurgeAfter *time.Duration `mapstructure:"purge_after" hcl:"purge_after,optional"`
+	KeepLast   *int           `mapstructure:"keep_last" hcl:"keep_last,optional"`
 }
 
 // Job is used to serialize a job.
@@ -840,6 +932,7 @@ type Job struct {
 	Name             *string                 `hcl:"name,optional"`
 	Type             *string                 `hcl:"type,optional"`
 	Priority         *int                    `hcl:"priority,optional"`
+	Deadline         *time.Duration          `mapstructure:"deadline" hcl:"deadline,optional"`
 	AllAtOnce        *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
 	Datacenters      []string                `hcl:"datacenters,optional"`
 	Constraints      []*Constraint           `hcl:"constraint,block"`
@@ -869,6 +962,7 @@ type Job struct {
 	Status                   *string
 	StatusDescription        *string
 	Stable                   *bool
+	Frozen                   *bool
 	Version                  *uint64
 	SubmitTime               *int64
 	CreateIndex              *uint64
@@ -1259,6 +1353,7 @@ type JobPlanRequest struct {
 	Job            *Job
 	Diff           bool
 	PolicyOverride bool
+	Verbose        bool
 	WriteRequest
 }
 
@@ -1315,8 +1410,31 @@ type ObjectDiff struct {
 }
 
 type PlanAnnotations struct {
-	DesiredTGUpdates map[string]*DesiredUpdates
-	PreemptedAllocs  []*AllocationListStub
+	DesiredTGUpdates      map[string]*DesiredUpdates
+	PreemptedAllocs       []*AllocationListStub
+	PlacementExplanations []*PlacementExplanation
+}
+
+// PlacementExplanation explains the placement of an allocation in a verbose
+// plan. NodeID is the selected node, or empty if the allocation couldn't be
+// placed.
+type PlacementExplanation struct {
+	TaskGroup string
+	AllocName string
+	NodeID    string
+	Nodes     []*NodePlacementExplanation
+}
+
+// NodePlacementExplanation is the outcome of a node evaluated for a placement.
+// The node was rejected if FilteredBy or ExhaustedDimension is set.
+type NodePlacementExplanation struct {
+	NodeID             string
+	NodeName           string
+	NodeClass          string
+	FilteredBy         string
+	ExhaustedDimension string
+	Scores             map[string]float64
+	NormScore          float64
 }
 
 type DesiredUpdates struct {
@@ -1368,6 +1486,61 @@ type JobStabilityResponse struct {
 	WriteMeta
 }
 
+// JobFreezeRequest is used to freeze or unfreeze a job.
+type JobFreezeRequest struct {
+	// Job to freeze or unfreeze
+	JobID string
+
+	// Frozen is the desired freeze state of the job
+	Frozen bool
+	WriteRequest
+}
+
+// JobFreezeResponse is the response when freezing or unfreezing a job.
+type JobFreezeResponse struct {
+	WriteMeta
+}
+
+// JobRestoreResponse is the response when restoring a purged job.
+type JobRestoreResponse struct {
+	JobModifyIndex uint64
+	WriteMeta
+}
+
+// TrashedJobListStub is used to return a subset of information about a purged
+// job in the job trash.
+type TrashedJobListStub struct {
+	ID          string
+	Namespace   string
+	Name        string
+	Type        string
+	Version     uint64
+	Versions    int
+	ExpiresAt   time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+const (
+	JobLineageNodeTypeJobVersion = "job-version"
+	JobLineageNodeTypeEvaluation = "evaluation"
+	JobLineageNodeTypeDeployment = "deployment"
+	JobLineageNodeTypeAllocation = "allocation"
+)
+
+// JobLineageNode is an object of a job in its lineage: a job version, an
+// evaluation, a deployment or an allocation. Its children are the objects it
+// caused.
+type JobLineageNode struct {
+	Type        string
+	ID          string
+	Status      string
+	Description string
+	CreateIndex uint64
+	CreateTime  int64
+	Children    []*JobLineageNode
+}
+
 // JobEvaluateRequest is used when we just need to re-evaluate a target job
 type JobEvaluateRequest struct {
 	JobID       string
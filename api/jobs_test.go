@@ -319,6 +319,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
 					AutoPromote:      boolToPtr(false),
+					PromotionGate:    stringToPtr(""),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -356,6 +357,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       boolToPtr(false),
 							Canary:           intToPtr(0),
 							AutoPromote:      boolToPtr(false),
+							PromotionGate:    stringToPtr(""),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -492,6 +494,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
 					AutoPromote:      boolToPtr(false),
+					PromotionGate:    stringToPtr(""),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -529,6 +532,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       boolToPtr(false),
 							Canary:           intToPtr(0),
 							AutoPromote:      boolToPtr(false),
+							PromotionGate:    stringToPtr(""),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -664,6 +668,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
 					AutoPromote:      boolToPtr(true),
+					PromotionGate:    stringToPtr(""),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -701,6 +706,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       boolToPtr(true),
 							Canary:           intToPtr(0),
 							AutoPromote:      boolToPtr(true),
+							PromotionGate:    stringToPtr(""),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -769,6 +775,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 										RightDelim:   stringToPtr("}}"),
 										Envvars:      boolToPtr(false),
 										VaultGrace:   timeToPtr(0),
+
+										OnRenderError:  stringToPtr("fail"),
+										RenderMaxStale: timeToPtr(0),
 									},
 									{
 										SourcePath:   stringToPtr(""),
@@ -782,6 +791,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 										RightDelim:   stringToPtr("}}"),
 										Envvars:      boolToPtr(true),
 										VaultGrace:   timeToPtr(0),
+
+										OnRenderError:  stringToPtr("fail"),
+										RenderMaxStale: timeToPtr(0),
 									},
 								},
 							},
@@ -829,6 +841,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
 					AutoPromote:      boolToPtr(false),
+					PromotionGate:    stringToPtr(""),
 				},
 				Periodic: &PeriodicConfig{
 					Enabled:         boolToPtr(true),
@@ -856,6 +869,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
 					AutoPromote:      boolToPtr(false),
+					PromotionGate:    stringToPtr(""),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -920,6 +934,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
 					AutoPromote:      boolToPtr(false),
+					PromotionGate:    stringToPtr(""),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -957,6 +972,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       boolToPtr(true),
 							Canary:           intToPtr(1),
 							AutoPromote:      boolToPtr(true),
+							PromotionGate:    stringToPtr(""),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -1004,6 +1020,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       boolToPtr(false),
 							Canary:           intToPtr(0),
 							AutoPromote:      boolToPtr(false),
+							PromotionGate:    stringToPtr(""),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -1096,6 +1113,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
 					AutoPromote:      boolToPtr(false),
+					PromotionGate:    stringToPtr(""),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -1133,6 +1151,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       boolToPtr(false),
 							Canary:           intToPtr(0),
 							AutoPromote:      boolToPtr(false),
+							PromotionGate:    stringToPtr(""),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -1185,6 +1204,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       boolToPtr(false),
 							Canary:           intToPtr(0),
 							AutoPromote:      boolToPtr(false),
+							PromotionGate:    stringToPtr(""),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -1267,6 +1287,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
 					AutoPromote:      boolToPtr(false),
+					PromotionGate:    stringToPtr(""),
 				},
 			},
 		},
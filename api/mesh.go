@@ -0,0 +1,21 @@
+package api
+
+// Mesh integrates a task group with an external service mesh. A sidecar task
+// is injected in the group, and the mesh hook registered on the clients for
+// the type of the mesh renders its configuration.
+type Mesh struct {
+	Type      string                 `mapstructure:"type" hcl:"type,optional"`
+	Driver    string                 `mapstructure:"driver" hcl:"driver,optional"`
+	Resources *Resources             `hcl:"resources,block"`
+	Config    map[string]interface{} `hcl:"config,block"`
+}
+
+// Canonicalize Mesh into a canonical form.
+func (m *Mesh) Canonicalize() {
+	if m.Driver == "" {
+		m.Driver = "docker"
+	}
+	if m.Resources != nil {
+		m.Resources.Canonicalize()
+	}
+}
@@ -468,6 +468,17 @@ func (n *Nodes) Stats(nodeID string, q *QueryOptions) (*HostStats, error) {
 	return &resp, nil
 }
 
+// Inventory returns the tasks running on the node.
+func (n *Nodes) Inventory(nodeID string, q *QueryOptions) (*ClientInventory, error) {
+	var resp ClientInventory
+	path := fmt.Sprintf("/v1/client/inventory?node_id=%s", nodeID)
+	if _, err := n.client.query(path, &resp, q); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
 func (n *Nodes) GC(nodeID string, q *QueryOptions) error {
 	path := fmt.Sprintf("/v1/client/gc?node_id=%s", nodeID)
 	_, err := n.client.query(path, nil, q)
@@ -493,6 +504,40 @@ func (n *Nodes) Purge(nodeID string, q *QueryOptions) (*NodePurgeResponse, *Quer
 	return &resp, qm, nil
 }
 
+// CreateIntroToken is used to create a single-use node introduction token,
+// which allows a client to register for the first time when servers require
+// node introduction. The servers' default TTL is used if ttl is zero.
+func (n *Nodes) CreateIntroToken(ttl time.Duration, q *WriteOptions) (*NodeIntroToken, *WriteMeta, error) {
+	req := &NodeIntroTokenCreateRequest{TTL: ttl}
+	var resp NodeIntroTokenCreateResponse
+	wm, err := n.client.write("/v1/node/intro-token", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Token == nil {
+		return nil, nil, fmt.Errorf("no node introduction token returned")
+	}
+	return resp.Token, wm, nil
+}
+
+// NodeIntroToken is a single-use token used by a client to register for the
+// first time.
+type NodeIntroToken struct {
+	SecretID    string
+	AccessorID  string
+	ExpiresAt   time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+type NodeIntroTokenCreateRequest struct {
+	TTL time.Duration
+}
+
+type NodeIntroTokenCreateResponse struct {
+	Token *NodeIntroToken
+}
+
 // NodePurgeResponse is used to deserialize a Purge response.
 type NodePurgeResponse struct {
 	EvalIDs         []string
@@ -563,10 +608,26 @@ type Node struct {
 	CSIControllerPlugins  map[string]*CSIInfo
 	CSINodePlugins        map[string]*CSIInfo
 	LastDrain             *DrainMetadata
+	LastRestore           *NodeRestoreReport
 	CreateIndex           uint64
 	ModifyIndex           uint64
 }
 
+// NodeRestoreReport describes the outcome of a client reattaching to the tasks
+// it was running before it restarted.
+type NodeRestoreReport struct {
+	RestoredAt time.Time
+	Reattached int
+	Failed     []*NodeTaskRestoreFailure
+}
+
+// NodeTaskRestoreFailure is a task a client couldn't reattach to.
+type NodeTaskRestoreFailure struct {
+	AllocID  string
+	TaskName string
+	Error    string
+}
+
 type NodeResources struct {
 	Cpu      NodeCpuResources
 	Memory   NodeMemoryResources
@@ -773,6 +834,28 @@ type NodeEvent struct {
 	CreateIndex uint64
 }
 
+// ClientInventory is the inventory of the tasks running on a Nomad client
+type ClientInventory struct {
+	NodeID string
+	Tasks  []*TaskInventory
+}
+
+// TaskInventory describes a task running on a Nomad client: the labels
+// identifying it and the resources allocated to it
+type TaskInventory struct {
+	Namespace   string
+	JobID       string
+	TaskGroup   string
+	Task        string
+	AllocID     string
+	AllocName   string
+	StartedAt   time.Time
+	CPU         int64
+	Cores       []uint16
+	MemoryMB    int64
+	MemoryMaxMB int64
+}
+
 // HostStats represents resource usage stats of the host running a Nomad client
 type HostStats struct {
 	Memory           *HostMemoryStats
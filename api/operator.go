@@ -130,6 +130,14 @@ type SchedulerConfiguration struct {
 	// MemoryOversubscriptionEnabled specifies whether memory oversubscription is enabled
 	MemoryOversubscriptionEnabled bool
 
+	// ReservedHeadroom specifies the share of every node kept free for
+	// system and high priority jobs.
+	ReservedHeadroom ReservedHeadroomConfig
+
+	// EvalFairness configures how the eval broker interleaves the pending
+	// evaluations of different namespaces.
+	EvalFairness EvalFairnessConfig
+
 	// RejectJobRegistration disables new job registrations except with a
 	// management ACL token
 	RejectJobRegistration bool
@@ -138,6 +146,20 @@ type SchedulerConfiguration struct {
 	// until the configuration is updated and written to the Nomad servers.
 	PauseEvalBroker bool
 
+	// PauseScheduling stops the scheduler workers from dequeuing evaluations
+	// while still allowing evaluations to be created and enqueued.
+	PauseScheduling bool
+
+	// PauseSchedulingDuration is the duration after which a scheduling pause
+	// expires automatically. A zero duration pauses scheduling until the
+	// configuration is updated.
+	PauseSchedulingDuration time.Duration
+
+	// PauseSchedulingUntil is the time, as a UnixNano, at which the scheduling
+	// pause expires. It is set by the servers and must be left as zero to
+	// start a new pause.
+	PauseSchedulingUntil int64
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -179,6 +201,29 @@ type PreemptionConfig struct {
 	ServiceSchedulerEnabled  bool
 }
 
+// ReservedHeadroomConfig specifies the percentage of the CPU and memory of
+// the nodes that placements of routine jobs may not use.
+type ReservedHeadroomConfig struct {
+	Percent           int
+	NodeClassPercent  map[string]int
+	PriorityThreshold int
+}
+
+// NodePercent returns the headroom percentage effective for a node.
+func (r *ReservedHeadroomConfig) NodePercent(node *Node) int {
+	if pct, ok := r.NodeClassPercent[node.NodeClass]; ok {
+		return pct
+	}
+	return r.Percent
+}
+
+// EvalFairnessConfig configures weighted fair queueing of evaluations across
+// namespaces in the eval broker.
+type EvalFairnessConfig struct {
+	Enabled         bool
+	NamespaceShares map[string]int
+}
+
 // SchedulerGetConfiguration is used to query the current Scheduler configuration.
 func (op *Operator) SchedulerGetConfiguration(q *QueryOptions) (*SchedulerConfigurationResponse, *QueryMeta, error) {
 	var resp SchedulerConfigurationResponse
@@ -126,6 +126,11 @@ type QuotaLimit struct {
 	// useful for once we support GPUs
 	RegionLimit *Resources
 
+	// SoftLimit is a limit below the RegionLimit. It does not block
+	// placements; usage exceeding it is reported by the quota status
+	// command. A value of zero is treated as unset.
+	SoftLimit *Resources
+
 	// Hash is the hash of the object and is used to make replication efficient.
 	Hash []byte
 }
@@ -16,6 +16,15 @@ const (
 	ErrVariableMissingItems = "secure variable missing Items field"
 )
 
+const (
+	// SecureVariableOpSet creates or updates a secure variable in a
+	// transaction.
+	SecureVariableOpSet = "set"
+
+	// SecureVariableOpDelete deletes a secure variable in a transaction.
+	SecureVariableOpDelete = "delete"
+)
+
 // SecureVariables is used to access secure variables.
 type SecureVariables struct {
 	client *Client
@@ -130,6 +139,44 @@ func (sv *SecureVariables) CheckedDelete(path string, checkIndex uint64, qo *Wri
 	return wm, nil
 }
 
+// Txn is used to apply multiple secure variable operations atomically. If
+// the check index of any operation doesn't match, none of the operations are
+// applied and an ErrCASConflicts is returned.
+func (sv *SecureVariables) Txn(ops []*SecureVariableTxnOp, qo *WriteOptions) (*WriteMeta, error) {
+
+	for _, op := range ops {
+		if op.Var != nil {
+			op.Var.Path = cleanPathString(op.Var.Path)
+		}
+	}
+
+	r, err := sv.client.newRequest("PUT", "/v1/vars/txn")
+	if err != nil {
+		return nil, err
+	}
+	r.setWriteOptions(qo)
+	r.obj = ops
+
+	checkFn := requireStatusIn(http.StatusOK, http.StatusConflict)
+	rtt, resp, err := checkFn(sv.client.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	parseWriteMeta(resp, wm)
+
+	if resp.StatusCode == http.StatusConflict {
+		var conflicts []*SecureVariable
+		if err := decodeBody(resp, &conflicts); err != nil {
+			return nil, err
+		}
+		return nil, ErrCASConflicts{Ops: ops, Conflicts: conflicts}
+	}
+	return wm, nil
+}
+
 // List is used to dump all of the secure variables, can be used to pass prefix
 // via QueryOptions rather than as a parameter
 func (sv *SecureVariables) List(qo *QueryOptions) ([]*SecureVariableMetadata, *QueryMeta, error) {
@@ -400,6 +447,43 @@ func (sv SecureVariable) AsPrettyJSON() string {
 	return string(b)
 }
 
+// SecureVariableTxnOp is an operation of a secure variables transaction.
+type SecureVariableTxnOp struct {
+	// Op is the operation, either SecureVariableOpSet or
+	// SecureVariableOpDelete.
+	Op string
+
+	// Var is the secure variable to set. Only its namespace and path are
+	// used by delete operations.
+	Var *SecureVariable
+
+	// CheckIndex is the modify index the secure variable must have for the
+	// transaction to be applied, or 0 if it must not exist. If nil, the
+	// operation is applied regardless of the current secure variable.
+	CheckIndex *uint64 `json:",omitempty"`
+}
+
+// ErrCASConflicts is returned when a secure variables transaction isn't
+// applied because the check index of some of its operations didn't match.
+// Conflicts has the current secure variable for each conflicting operation,
+// at the operation's position, and nil for the other operations.
+type ErrCASConflicts struct {
+	Ops       []*SecureVariableTxnOp
+	Conflicts []*SecureVariable
+}
+
+func (e ErrCASConflicts) Error() string {
+	var parts []string
+	for i, conflict := range e.Conflicts {
+		if conflict == nil || i >= len(e.Ops) || e.Ops[i].CheckIndex == nil {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: expected ModifyIndex %v; found %v",
+			conflict.Path, *e.Ops[i].CheckIndex, conflict.ModifyIndex))
+	}
+	return fmt.Sprintf("cas conflicts: %s", strings.Join(parts, ", "))
+}
+
 type ErrCASConflict struct {
 	CheckIndex uint64
 	Conflict   *SecureVariable
@@ -193,6 +193,60 @@ func TestSecureVariables_CRUDWithCAS(t *testing.T) {
 
 }
 
+func TestSecureVariables_Txn(t *testing.T) {
+	testutil.Parallel(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	nsv := c.SecureVariables()
+	sv1 := &SecureVariable{
+		Path:  "txn/variable/a",
+		Items: map[string]string{"key1": "value1"},
+	}
+	sv2 := &SecureVariable{
+		Path:  "txn/variable/b",
+		Items: map[string]string{"key2": "value2"},
+	}
+
+	_, err := nsv.Create(sv1, nil)
+	require.NoError(t, err)
+	sv1, _, err = nsv.Read(sv1.Path, nil)
+	require.NoError(t, err)
+
+	// A stale check index fails the whole transaction and returns an
+	// ErrCASConflicts with the current value of the variable.
+	stale := sv1.ModifyIndex - 1
+	zero := uint64(0)
+	_, err = nsv.Txn([]*SecureVariableTxnOp{
+		{Op: SecureVariableOpSet, Var: sv2, CheckIndex: &zero},
+		{Op: SecureVariableOpDelete, Var: sv1, CheckIndex: &stale},
+	}, nil)
+	require.Error(t, err)
+
+	var conflictsErr ErrCASConflicts
+	require.ErrorAs(t, err, &conflictsErr)
+	require.Len(t, conflictsErr.Conflicts, 2)
+	require.Nil(t, conflictsErr.Conflicts[0])
+	require.Equal(t, sv1, conflictsErr.Conflicts[1])
+
+	_, _, err = nsv.Read(sv2.Path, nil)
+	require.EqualError(t, err, ErrVariableNotFound)
+
+	// The current check index applies every operation.
+	current := sv1.ModifyIndex
+	_, err = nsv.Txn([]*SecureVariableTxnOp{
+		{Op: SecureVariableOpSet, Var: sv2, CheckIndex: &zero},
+		{Op: SecureVariableOpDelete, Var: sv1, CheckIndex: &current},
+	}, nil)
+	require.NoError(t, err)
+
+	_, _, err = nsv.Read(sv1.Path, nil)
+	require.EqualError(t, err, ErrVariableNotFound)
+	get, _, err := nsv.Read(sv2.Path, nil)
+	require.NoError(t, err)
+	require.Equal(t, sv2.Items, get.Items)
+}
+
 func TestSecureVariables_Read(t *testing.T) {
 	testutil.Parallel(t)
 	c, s := makeClient(t, nil, nil)
@@ -1,5 +1,7 @@
 package api
 
+import "fmt"
+
 // Status is used to query the status-related endpoints.
 type System struct {
 	client *Client
@@ -21,3 +23,56 @@ func (s *System) ReconcileSummaries() error {
 	_, err := s.client.write("/v1/system/reconcile/summaries", &req, nil, nil)
 	return err
 }
+
+// ReconcileOptions is used to pass through reconciliation options.
+type ReconcileOptions struct {
+	// DryRun computes the changes without applying them.
+	DryRun bool
+}
+
+// ReconcileResponse lists the changes made by a reconciliation, or that would
+// be made on a dry run.
+type ReconcileResponse struct {
+	Changes []*ReconcileChange
+	WriteMeta
+}
+
+// ReconcileChange describes the reconciliation of a single object. Type is
+// "Edited" if the object is updated, in which case Fields lists the fields
+// which change, and "Deleted" if it is deleted.
+type ReconcileChange struct {
+	Type      string
+	Namespace string
+	ID        string
+	Fields    []*FieldDiff
+}
+
+// ReconcileSummariesOpts reconciles the summaries of all the jobs with their
+// allocations, returning the summaries which change.
+func (s *System) ReconcileSummariesOpts(opts *ReconcileOptions, q *WriteOptions) (*ReconcileResponse, error) {
+	return s.reconcile("summaries", opts, q)
+}
+
+// ReconcileDeployments reconciles the allocation counts of the active
+// deployments with their allocations, returning the deployments which change.
+func (s *System) ReconcileDeployments(opts *ReconcileOptions, q *WriteOptions) (*ReconcileResponse, error) {
+	return s.reconcile("deployments", opts, q)
+}
+
+// ReconcileServices deletes the native service registrations of allocations
+// which are missing or terminal, returning the deleted registrations.
+func (s *System) ReconcileServices(opts *ReconcileOptions, q *WriteOptions) (*ReconcileResponse, error) {
+	return s.reconcile("services", opts, q)
+}
+
+func (s *System) reconcile(target string, opts *ReconcileOptions, q *WriteOptions) (*ReconcileResponse, error) {
+	dryRun := opts != nil && opts.DryRun
+
+	var resp ReconcileResponse
+	wm, err := s.client.write(fmt.Sprintf("/v1/system/reconcile/%s?dry_run=%t", target, dryRun), nil, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
@@ -15,3 +15,25 @@ func TestSystem_GarbageCollect(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSystem_Reconcile(t *testing.T) {
+	testutil.Parallel(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	e := c.System()
+
+	resp, err := e.ReconcileSummariesOpts(&ReconcileOptions{DryRun: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Changes) != 0 {
+		t.Fatalf("expected no changes, got %d", len(resp.Changes))
+	}
+
+	if _, err := e.ReconcileDeployments(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ReconcileServices(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+}
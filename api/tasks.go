@@ -27,6 +27,8 @@ type MemoryStats struct {
 	MaxUsage       uint64
 	KernelUsage    uint64
 	KernelMaxUsage uint64
+	Limit          uint64
+	Reservation    uint64
 	Measured       []string
 }
 
@@ -46,6 +48,16 @@ type ResourceUsage struct {
 	MemoryStats *MemoryStats
 	CpuStats    *CpuStats
 	DeviceStats []*DeviceGroupStats
+
+	// Process identifies the process the usage belongs to. It is only set
+	// for the per-process usages in TaskResourceUsage.Pids.
+	Process *ProcessInfo
+}
+
+// ProcessInfo identifies a process of a task
+type ProcessInfo struct {
+	PPID    int
+	Command string
 }
 
 // TaskResourceUsage holds aggregated resource usage of all processes in a Task
@@ -101,7 +113,8 @@ type ReschedulePolicy struct {
 	Delay *time.Duration `mapstructure:"delay" hcl:"delay,optional"`
 
 	// DelayFunction determines how the delay progressively changes on subsequent reschedule
-	// attempts. Valid values are "exponential", "constant", and "fibonacci".
+	// attempts. Valid values are "exponential", "constant", "fibonacci" and
+	// "exponential_jitter".
 	DelayFunction *string `mapstructure:"delay_function" hcl:"delay_function,optional"`
 
 	// MaxDelay is an upper bound on the delay.
@@ -180,6 +193,25 @@ func (a *Affinity) Canonicalize() {
 	}
 }
 
+const (
+	// AntiAffinityScopeNode prevents allocations from sharing a node.
+	AntiAffinityScopeNode = "node"
+)
+
+// AntiAffinity is used to serialize task group anti-affinities, which keep
+// the allocations of the group off the nodes running allocations of other
+// jobs.
+type AntiAffinity struct {
+	JobRegexp string `mapstructure:"job_regexp" hcl:"job_regexp,optional"` // Matched against the IDs of the other jobs of the namespace
+	Scope     string `hcl:"scope,optional"`                                // Placement level at which jobs must not co-locate
+}
+
+func (a *AntiAffinity) Canonicalize() {
+	if a.Scope == "" {
+		a.Scope = AntiAffinityScopeNode
+	}
+}
+
 func NewDefaultReschedulePolicy(jobType string) *ReschedulePolicy {
 	var dp *ReschedulePolicy
 	switch jobType {
@@ -418,6 +450,7 @@ type TaskGroup struct {
 	Count                     *int                      `hcl:"count,optional"`
 	Constraints               []*Constraint             `hcl:"constraint,block"`
 	Affinities                []*Affinity               `hcl:"affinity,block"`
+	AntiAffinities            []*AntiAffinity           `hcl:"anti_affinity,block"`
 	Tasks                     []*Task                   `hcl:"task,block"`
 	Spreads                   []*Spread                 `hcl:"spread,block"`
 	Volumes                   map[string]*VolumeRequest `hcl:"volume,block"`
@@ -432,8 +465,11 @@ type TaskGroup struct {
 	ShutdownDelay             *time.Duration            `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
 	StopAfterClientDisconnect *time.Duration            `mapstructure:"stop_after_client_disconnect" hcl:"stop_after_client_disconnect,optional"`
 	MaxClientDisconnect       *time.Duration            `mapstructure:"max_client_disconnect" hcl:"max_client_disconnect,optional"`
+	ShutdownPriority          *int                      `mapstructure:"shutdown_priority" hcl:"shutdown_priority,optional"`
+	Priority                  *int                      `hcl:"priority,optional"`
 	Scaling                   *ScalingPolicy            `hcl:"scaling,block"`
 	Consul                    *Consul                   `hcl:"consul,block"`
+	Mesh                      *Mesh                     `hcl:"mesh,block"`
 }
 
 // NewTaskGroup creates a new TaskGroup.
@@ -473,6 +509,10 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	g.Consul.MergeNamespace(job.ConsulNamespace)
 	g.Consul.Canonicalize()
 
+	if g.Mesh != nil {
+		g.Mesh.Canonicalize()
+	}
+
 	// Merge the update policy from the job
 	if ju, tu := job.Update != nil, g.Update != nil; ju && tu {
 		// Merge the jobs and task groups definition of the update strategy
@@ -546,6 +586,9 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	for _, a := range g.Affinities {
 		a.Canonicalize()
 	}
+	for _, a := range g.AntiAffinities {
+		a.Canonicalize()
+	}
 	for _, n := range g.Networks {
 		n.Canonicalize()
 	}
@@ -658,6 +701,21 @@ func (l *TaskLifecycle) Empty() bool {
 	return l == nil || (l.Hook == "")
 }
 
+// TaskProcess configures attributes of the task process.
+type TaskProcess struct {
+	Umask       string            `hcl:"umask,optional"`
+	Nice        int               `hcl:"nice,optional"`
+	IOniceClass string            `mapstructure:"ionice_class" hcl:"ionice_class,optional"`
+	Rlimits     map[string]uint64 `hcl:"rlimits,block"`
+}
+
+// WorkloadIdentity configures how the workload identity of a task is exposed
+// to the task.
+type WorkloadIdentity struct {
+	File    bool `hcl:"file,optional"`
+	TaskAPI bool `mapstructure:"task_api" hcl:"task_api,optional"`
+}
+
 // Task is a single process in a task group.
 type Task struct {
 	Name            string                 `hcl:"name,label"`
@@ -683,8 +741,11 @@ type Task struct {
 	Leader          bool                   `hcl:"leader,optional"`
 	ShutdownDelay   time.Duration          `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
 	KillSignal      string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
+	ReadinessFile   string                 `mapstructure:"readiness_file" hcl:"readiness_file,optional"`
 	Kind            string                 `hcl:"kind,optional"`
 	ScalingPolicies []*ScalingPolicy       `hcl:"scaling,block"`
+	Process         *TaskProcess           `hcl:"process,block"`
+	Identity        *WorkloadIdentity      `hcl:"identity,block"`
 }
 
 func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
@@ -804,6 +865,9 @@ type Template struct {
 	Envvars      *bool          `mapstructure:"env" hcl:"env,optional"`
 	VaultGrace   *time.Duration `mapstructure:"vault_grace" hcl:"vault_grace,optional"`
 	Wait         *WaitConfig    `mapstructure:"wait" hcl:"wait,block"`
+
+	OnRenderError  *string        `mapstructure:"on_render_error" hcl:"on_render_error,optional"`
+	RenderMaxStale *time.Duration `mapstructure:"render_max_stale" hcl:"render_max_stale,optional"`
 }
 
 func (tmpl *Template) Canonicalize() {
@@ -844,6 +908,12 @@ func (tmpl *Template) Canonicalize() {
 	if tmpl.Envvars == nil {
 		tmpl.Envvars = boolToPtr(false)
 	}
+	if tmpl.OnRenderError == nil {
+		tmpl.OnRenderError = stringToPtr("fail")
+	}
+	if tmpl.RenderMaxStale == nil {
+		tmpl.RenderMaxStale = timeToPtr(0)
+	}
 
 	//COMPAT(0.12) VaultGrace is deprecated and unused as of Vault 0.5
 	if tmpl.VaultGrace == nil {
@@ -949,26 +1019,34 @@ type TaskHandle struct {
 }
 
 const (
-	TaskSetup                  = "Task Setup"
-	TaskSetupFailure           = "Setup Failure"
-	TaskDriverFailure          = "Driver Failure"
-	TaskDriverMessage          = "Driver"
-	TaskReceived               = "Received"
-	TaskFailedValidation       = "Failed Validation"
-	TaskStarted                = "Started"
-	TaskTerminated             = "Terminated"
-	TaskKilling                = "Killing"
-	TaskKilled                 = "Killed"
-	TaskRestarting             = "Restarting"
-	TaskNotRestarting          = "Not Restarting"
-	TaskDownloadingArtifacts   = "Downloading Artifacts"
-	TaskArtifactDownloadFailed = "Failed Artifact Download"
-	TaskSiblingFailed          = "Sibling Task Failed"
-	TaskSignaling              = "Signaling"
-	TaskRestartSignal          = "Restart Signaled"
-	TaskLeaderDead             = "Leader Task Dead"
-	TaskBuildingTaskDir        = "Building Task Directory"
-	TaskClientReconnected      = "Reconnected"
+	TaskSetup                    = "Task Setup"
+	TaskSetupFailure             = "Setup Failure"
+	TaskDriverFailure            = "Driver Failure"
+	TaskDriverMessage            = "Driver"
+	TaskDriverUnhealthy          = "Driver Unhealthy"
+	TaskDriverHealthy            = "Driver Healthy"
+	TaskCheckpointed             = "Checkpointed"
+	TaskRestoredCheckpoint       = "Restored Checkpoint"
+	TaskReceived                 = "Received"
+	TaskFailedValidation         = "Failed Validation"
+	TaskStarted                  = "Started"
+	TaskTerminated               = "Terminated"
+	TaskKilling                  = "Killing"
+	TaskKilled                   = "Killed"
+	TaskRestarting               = "Restarting"
+	TaskNotRestarting            = "Not Restarting"
+	TaskDownloadingArtifacts     = "Downloading Artifacts"
+	TaskArtifactDownloadFailed   = "Failed Artifact Download"
+	TaskArtifactDownloadProgress = "Artifact Download Progress"
+	TaskSiblingFailed            = "Sibling Task Failed"
+	TaskSignaling                = "Signaling"
+	TaskRestartSignal            = "Restart Signaled"
+	TaskLeaderDead               = "Leader Task Dead"
+	TaskBuildingTaskDir          = "Building Task Directory"
+	TaskClientReconnected        = "Reconnected"
+	TaskPluginUnhealthy          = "Plugin became unhealthy"
+	TaskPluginHealthy            = "Plugin became healthy"
+	TaskMarkedUnhealthy          = "Marked Unhealthy"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -976,6 +1054,7 @@ const (
 type TaskEvent struct {
 	Type           string
 	Time           int64
+	Kind           string
 	DisplayMessage string
 	Details        map[string]string
 	Message        string
@@ -1043,6 +1122,18 @@ type TaskCSIPluginConfig struct {
 	// HealthTimeout is the time after which the CSI plugin tasks will be killed
 	// if the CSI Plugin is not healthy.
 	HealthTimeout time.Duration `mapstructure:"health_timeout" hcl:"health_timeout,optional"`
+
+	// ProbeInterval is the interval at which a healthy CSI plugin is probed.
+	//
+	// Default is 30s.
+	ProbeInterval time.Duration `mapstructure:"probe_interval" hcl:"probe_interval,optional"`
+
+	// MaxProbeFailures is the number of consecutive failed probes after which
+	// the CSI plugin task is restarted.
+	//
+	// Default is 0, which never restarts the task and only reports the
+	// plugin as unhealthy.
+	MaxProbeFailures int `mapstructure:"max_probe_failures" hcl:"max_probe_failures,optional"`
 }
 
 func (t *TaskCSIPluginConfig) Canonicalize() {
@@ -1053,4 +1144,8 @@ func (t *TaskCSIPluginConfig) Canonicalize() {
 	if t.HealthTimeout == 0 {
 		t.HealthTimeout = 30 * time.Second
 	}
+
+	if t.ProbeInterval == 0 {
+		t.ProbeInterval = 30 * time.Second
+	}
 }
@@ -529,6 +529,7 @@ func TestTaskGroup_Canonicalize_Update(t *testing.T) {
 		Update: &UpdateStrategy{
 			AutoRevert:       boolToPtr(false),
 			AutoPromote:      boolToPtr(false),
+			PromotionGate:    stringToPtr(""),
 			Canary:           intToPtr(0),
 			HealthCheck:      stringToPtr(""),
 			HealthyDeadline:  timeToPtr(0),
@@ -626,6 +627,7 @@ func TestTaskGroup_Merge_Update(t *testing.T) {
 	require.Equal(t, &UpdateStrategy{
 		AutoRevert:       boolToPtr(true),
 		AutoPromote:      boolToPtr(false),
+		PromotionGate:    stringToPtr(""),
 		Canary:           intToPtr(5),
 		HealthCheck:      stringToPtr("foo"),
 		HealthyDeadline:  timeToPtr(5 * time.Minute),
@@ -6,11 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/armon/go-metrics"
 	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/allocdir"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -159,6 +162,33 @@ func (a *Allocations) Checks(args *cstructs.AllocChecksRequest, reply *cstructs.
 	return nil
 }
 
+// Templates is used to retrieve the rendering state of the templates of an
+// allocation and the dependencies they watch.
+func (a *Allocations) Templates(args *cstructs.AllocTemplatesRequest, reply *cstructs.AllocTemplatesResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "templates"}, time.Now())
+
+	ar, err := a.c.getAllocRunner(args.AllocID)
+	if err != nil {
+		return err
+	}
+	alloc := ar.Alloc()
+
+	// Check read-job permission
+	if aclObj, aclErr := a.c.ResolveToken(args.AuthToken); aclErr != nil {
+		return aclErr
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadJob) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	tasks, err := ar.TemplateStatus(args.Task)
+	if err != nil {
+		return err
+	}
+
+	reply.Tasks = tasks
+	return nil
+}
+
 // exec is used to execute command in a running task
 func (a *Allocations) exec(conn io.ReadWriteCloser) {
 	defer metrics.MeasureSince([]string{"client", "allocations", "exec"}, time.Now())
@@ -218,15 +248,21 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 			"task", req.Task,
 			"command", req.Cmd,
 			"tty", req.Tty,
+			"debug_shell", req.DebugShell,
 			"access_token_name", tokenName,
 			"access_token_id", tokenID,
 		)
 	}
 
-	// Check alloc-exec permission.
+	// Check alloc-exec permission, or alloc-debug-shell permission for debug
+	// shells.
+	capability := acl.NamespaceCapabilityAllocExec
+	if req.DebugShell {
+		capability = acl.NamespaceCapabilityAllocDebugShell
+	}
 	if err != nil {
 		return nil, err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) {
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, capability) {
 		return nil, nstructs.ErrPermissionDenied
 	}
 
@@ -234,7 +270,7 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 	if req.Task == "" {
 		return helper.Int64ToPtr(400), taskNotPresentErr
 	}
-	if len(req.Cmd) == 0 {
+	if len(req.Cmd) == 0 && !req.DebugShell {
 		return helper.Int64ToPtr(400), errors.New("command is not present")
 	}
 
@@ -284,7 +320,35 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 		return helper.Int64ToPtr(404), fmt.Errorf("task %q is not running.", req.Task)
 	}
 
-	err = h(ctx, req.Cmd, req.Tty, newExecStream(decoder, encoder))
+	cmd := req.Cmd
+	if req.DebugShell {
+		var cleanup func()
+		cmd, cleanup, err = a.prepareDebugShell(ar, req.Task, execID, capabilities.FSIsolation, req.Cmd)
+		if err != nil {
+			return helper.Int64ToPtr(400), err
+		}
+		defer cleanup()
+
+		if handler := ar.GetTaskEventHandler(req.Task); handler != nil {
+			tokenID := ""
+			if token != nil {
+				tokenID = token.AccessorID
+			}
+			handler(&drivers.TaskEvent{
+				TaskID:    req.Task,
+				AllocID:   req.AllocID,
+				TaskName:  req.Task,
+				Timestamp: time.Now(),
+				Message:   "Debug shell session started",
+				Annotations: map[string]string{
+					"exec_id":         execID,
+					"access_token_id": tokenID,
+				},
+			})
+		}
+	}
+
+	err = h(ctx, cmd, req.Tty, newExecStream(decoder, encoder))
 	if err != nil {
 		code := helper.Int64ToPtr(500)
 		return code, err
@@ -293,6 +357,65 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 	return nil, nil
 }
 
+// prepareDebugShell copies the debug shell binary of the client into the
+// local directory of the task, so it can be run even if the task has no
+// shell of its own. It returns the command running args with the debug shell
+// and a function removing the binary once the session ends.
+func (a *Allocations) prepareDebugShell(ar AllocRunner, task, execID string,
+	fsi drivers.FSIsolation, args []string) ([]string, func(), error) {
+
+	src := a.c.GetConfig().DebugShellPath
+	if src == "" {
+		return nil, nil, errors.New("debug shell is not enabled on the client")
+	}
+
+	taskDir := ar.GetAllocDir().TaskDirs[task]
+	if taskDir == nil {
+		return nil, nil, fmt.Errorf("unknown task name %q", task)
+	}
+
+	name := ".nomad-debug-shell-" + execID
+	dst := filepath.Join(taskDir.LocalDir, name)
+	if err := copyDebugShell(src, dst); err != nil {
+		return nil, nil, fmt.Errorf("failed to copy debug shell into task: %v", err)
+	}
+	cleanup := func() {
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			a.c.logger.Warn("failed to remove debug shell from task", "path", dst, "error", err)
+		}
+	}
+
+	// Without filesystem isolation the task sees the host paths
+	path := dst
+	if fsi != drivers.FSIsolationNone {
+		path = filepath.Join(allocdir.TaskLocalContainerPath, name)
+	}
+
+	if len(args) == 0 {
+		args = []string{"sh"}
+	}
+	return append([]string{path}, args...), cleanup, nil
+}
+
+func copyDebugShell(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
 // newExecStream returns a new exec stream as expected by drivers that interpolate with RPC streaming format
 func newExecStream(decoder *codec.Decoder, encoder *codec.Encoder) drivers.ExecTaskStream {
 	buf := new(bytes.Buffer)
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -458,6 +460,38 @@ func TestAllocations_Stats(t *testing.T) {
 	})
 }
 
+func TestAllocations_Templates(t *testing.T) {
+	ci.Parallel(t)
+
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	a := mock.Alloc()
+	require.NoError(t, client.addAlloc(a, ""))
+
+	// Try with bad alloc
+	req := &cstructs.AllocTemplatesRequest{AllocID: uuid.Generate()}
+	var resp cstructs.AllocTemplatesResponse
+	err := client.ClientRPC("Allocations.Templates", req, &resp)
+	require.True(t, nstructs.IsErrUnknownAllocation(err))
+
+	// Try with bad task
+	req.AllocID = a.ID
+	req.Task = "bad"
+	err = client.ClientRPC("Allocations.Templates", req, &resp)
+	require.EqualError(t, err, `task "bad" not found`)
+
+	// Try with good alloc, whose task has no templates
+	req.Task = ""
+	require.NoError(t, client.ClientRPC("Allocations.Templates", req, &resp))
+	require.Empty(t, resp.Tasks)
+
+	req.Task = "web"
+	require.NoError(t, client.ClientRPC("Allocations.Templates", req, &resp))
+	require.Contains(t, resp.Tasks, "web")
+	require.Empty(t, resp.Tasks["web"])
+}
+
 func TestAllocations_Stats_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -932,6 +966,123 @@ func TestAlloc_ExecStreaming_ACL_Basic(t *testing.T) {
 	}
 }
 
+func TestAlloc_ExecStreaming_DebugShell(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	debugShell := filepath.Join(t.TempDir(), "busybox")
+	require.NoError(t, os.WriteFile(debugShell, []byte("#!/bin/sh\n"), 0755))
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+		c.DebugShellPath = debugShell
+
+		pluginConfig := []*nconfig.PluginConfig{
+			{
+				Name: "mock_driver",
+				Config: map[string]interface{}{
+					"fs_isolation": string(drivers.FSIsolationImage),
+				},
+			},
+		}
+
+		c.PluginLoader = catalog.TestPluginLoaderWithOptions(t, "", map[string]string{}, pluginConfig)
+	})
+	defer cleanupC()
+
+	// alloc-exec doesn't allow debug shells
+	policyExec := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocExec})
+	tokenExec := mock.CreatePolicyAndToken(t, s.State(), 1005, "exec", policyExec)
+
+	policyShell := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocDebugShell})
+	tokenShell := mock.CreatePolicyAndToken(t, s.State(), 1009, "shell", policyShell)
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+		"exec_command": map[string]interface{}{
+			"run_for":       "1ms",
+			"stdout_string": "debugging\n",
+		},
+	}
+
+	// Wait for client to be running job
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+	task := job.TaskGroups[0].Tasks[0].Name
+
+	exec := func(token string) (string, error) {
+		req := &cstructs.AllocExecRequest{
+			AllocID:    alloc.ID,
+			Task:       task,
+			DebugShell: true,
+			QueryOptions: nstructs.QueryOptions{
+				Region:    "global",
+				AuthToken: token,
+				Namespace: nstructs.DefaultNamespace,
+			},
+		}
+
+		handler, err := client.StreamingRpcHandler("Allocations.Exec")
+		require.NoError(t, err)
+
+		p1, p2 := net.Pipe()
+		defer p1.Close()
+		defer p2.Close()
+
+		errCh := make(chan error)
+		frames := make(chan *drivers.ExecTaskStreamingResponseMsg)
+
+		go handler(p2)
+		go decodeFrames(t, p1, frames, errCh)
+
+		encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+		require.NoError(t, encoder.Encode(req))
+
+		stdout := ""
+		timeout := time.After(3 * time.Second)
+		for {
+			select {
+			case <-timeout:
+				require.FailNow(t, "timed out")
+			case err := <-errCh:
+				return stdout, err
+			case f := <-frames:
+				if f.Stdout != nil {
+					stdout += string(f.Stdout.Data)
+				}
+				if f.Exited {
+					return stdout, nil
+				}
+			}
+		}
+	}
+
+	_, err := exec(tokenExec.SecretID)
+	require.Error(t, err)
+	require.True(t, nstructs.IsErrPermissionDenied(err), "expected permission denied error but found: %v", err)
+
+	stdout, err := exec(tokenShell.SecretID)
+	require.NoError(t, err)
+	require.Equal(t, "debugging\n", stdout)
+
+	// the debug shell is removed from the task once the session ends
+	ar, err := client.getAllocRunner(alloc.ID)
+	require.NoError(t, err)
+	localDir := ar.GetAllocDir().TaskDirs[task].LocalDir
+	require.Eventually(t, func() bool {
+		matches, _ := filepath.Glob(filepath.Join(localDir, ".nomad-debug-shell-*"))
+		return len(matches) == 0
+	}, 3*time.Second, 50*time.Millisecond)
+}
+
 // TestAlloc_ExecStreaming_ACL_WithIsolation_Image asserts that token only needs
 // alloc-exec acl policy when image isolation is used
 func TestAlloc_ExecStreaming_ACL_WithIsolation_Image(t *testing.T) {
@@ -3,6 +3,9 @@ package allochealth
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -66,6 +69,10 @@ type Tracker struct {
 	// checkStore is used to lookup the status of Nomad service checks
 	checkStore checkstore.Shim
 
+	// allocDir is the shared alloc dir against which the readiness files of
+	// tasks are resolved
+	allocDir string
+
 	// healthy is used to signal whether we have determined the allocation to be
 	// healthy or unhealthy
 	healthy chan bool
@@ -95,6 +102,11 @@ type Tracker struct {
 	// name -> state
 	taskHealth map[string]*taskHealthState
 
+	// failedChecks contains the checks that were seen failing while tracking
+	// the health of the allocation, and their last failing status
+	// "service/check" -> status
+	failedChecks map[string]string
+
 	// logger is for logging things
 	logger hclog.Logger
 }
@@ -105,6 +117,7 @@ type Tracker struct {
 // - An alloc listener
 // - Consul checks (via consul API)
 // - Nomad checks (via client state)
+// - Task readiness files (via the shared alloc dir)
 func NewTracker(
 	parentCtx context.Context,
 	logger hclog.Logger,
@@ -112,6 +125,7 @@ func NewTracker(
 	allocUpdates *cstructs.AllocListener,
 	consulClient serviceregistration.Handler,
 	checkStore checkstore.Shim,
+	allocDir string,
 	minHealthyTime time.Duration,
 	useChecks bool,
 ) *Tracker {
@@ -126,14 +140,16 @@ func NewTracker(
 		allocUpdates:        allocUpdates,
 		consulClient:        consulClient,
 		checkStore:          checkStore,
+		allocDir:            allocDir,
 		checkLookupInterval: checkLookupInterval,
 		logger:              logger,
 		lifecycleTasks:      map[string]string{},
+		failedChecks:        map[string]string{},
 	}
 
 	t.taskHealth = make(map[string]*taskHealthState, len(t.tg.Tasks))
 	for _, task := range t.tg.Tasks {
-		t.taskHealth[task.Name] = &taskHealthState{task: task}
+		t.taskHealth[task.Name] = &taskHealthState{task: task, allocDir: allocDir}
 
 		if task.Lifecycle != nil && !task.Lifecycle.Sidecar {
 			t.lifecycleTasks[task.Name] = task.Lifecycle.Hook
@@ -158,12 +174,25 @@ func countChecks(services []*structs.Service) (consul, nomad int) {
 		case structs.ServiceProviderNomad:
 			nomad += len(service.Checks)
 		default:
-			consul += len(service.Checks)
+			consul += len(service.Checks) + countSidecarChecks(service)
 		}
 	}
 	return
 }
 
+// countSidecarChecks returns the number of checks registered in Consul for the
+// Connect sidecar proxy of the service: an alias check of the service, and a
+// TCP check of the proxy unless it is disabled.
+func countSidecarChecks(service *structs.Service) int {
+	if !service.Connect.HasSidecar() {
+		return 0
+	}
+	if service.Connect.SidecarService.DisableDefaultTCPCheck {
+		return 1
+	}
+	return 2
+}
+
 // Start starts the watcher.
 func (t *Tracker) Start() {
 	go t.watchTaskEvents()
@@ -216,6 +245,32 @@ func (t *Tracker) TaskEvents() map[string]*structs.TaskEvent {
 	return events
 }
 
+// FailedChecks returns the checks that were seen failing while tracking the
+// health of the allocation, even if they passed later on, formatted as
+// "service/check: status" and sorted.
+func (t *Tracker) FailedChecks() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.failedChecks) == 0 {
+		return nil
+	}
+
+	checks := make([]string, 0, len(t.failedChecks))
+	for check, status := range t.failedChecks {
+		checks = append(checks, fmt.Sprintf("%s: %s", check, status))
+	}
+	sort.Strings(checks)
+	return checks
+}
+
+// recordFailedCheck records that a check was seen failing with status.
+func (t *Tracker) recordFailedCheck(service, check, status string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.failedChecks[service+"/"+check] = status
+}
+
 // setTaskHealth is used to set the tasks health as healthy or unhealthy. If the
 // allocation is terminal, health is immediately broadcast.
 func (t *Tracker) setTaskHealth(healthy, terminal bool) {
@@ -341,6 +396,13 @@ func (t *Tracker) watchTaskEvents() {
 				return
 			}
 
+			// One of the tasks marked itself unhealthy through the task API
+			if markedUnhealthy(state) != nil {
+				t.logger.Trace("task marked itself unhealthy", "alloc_id", alloc.ID, "task", taskName)
+				t.setTaskHealth(false, true)
+				return
+			}
+
 			if state.State == structs.TaskStatePending {
 				latestStartTime = time.Time{}
 				break
@@ -385,11 +447,48 @@ func (t *Tracker) watchTaskEvents() {
 			}
 			alloc = newAlloc
 		case <-waiter.C():
+			// Tasks with a readiness file are not healthy until they have
+			// written it, so keep polling for it.
+			if !t.readinessFilesExist() {
+				waiter.wait(t.checkLookupInterval)
+				continue
+			}
 			t.setTaskHealth(true, false)
 		}
 	}
 }
 
+// readinessFilesExist returns whether every running task which sets a
+// readiness_file has written it into the shared alloc dir.
+func (t *Tracker) readinessFilesExist() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, state := range t.taskHealth {
+		if !state.readinessFileMissing() {
+			continue
+		}
+		t.logger.Trace("waiting for readiness file", "task", state.task.Name, "path", state.task.ReadinessFile)
+		return false
+	}
+	return true
+}
+
+// markedUnhealthy returns the event of the task marking itself unhealthy
+// through the task API since it last started, or nil if it didn't.
+func markedUnhealthy(state *structs.TaskState) *structs.TaskEvent {
+	for i := len(state.Events) - 1; i >= 0; i-- {
+		event := state.Events[i]
+		if event.Time < state.StartedAt.UnixNano() {
+			return nil
+		}
+		if event.Type == structs.TaskMarkedUnhealthy {
+			return event
+		}
+	}
+	return nil
+}
+
 // healthyFuture is used to fire after checks have been healthy for MinHealthyTime
 type healthyFuture struct {
 	timer *time.Timer
@@ -498,13 +597,15 @@ OUTER:
 		}
 		t.lock.Unlock()
 
-		// Detect if all the checks are passing
+		// Detect if all the checks are passing, recording the failing ones so
+		// they can be reported even if they pass later on
 		passed := true
+		registered := 0
 
-	CHECKS:
 		for _, treg := range allocReg.Tasks {
 			for _, sreg := range treg.Services {
 				for _, check := range sreg.Checks {
+					registered++
 					onUpdate := sreg.CheckOnUpdate[check.CheckID]
 					switch check.Status {
 					case api.HealthPassing:
@@ -521,12 +622,21 @@ OUTER:
 					}
 
 					passed = false
-					t.setCheckHealth(false)
-					break CHECKS
+					t.recordFailedCheck(check.ServiceName, check.Name, check.Status)
 				}
 			}
 		}
 
+		// All checks, including the ones of sidecar proxies, must be
+		// registered before the allocation can be healthy
+		if registered < t.consulCheckCount {
+			passed = false
+		}
+
+		if !passed {
+			t.setCheckHealth(false)
+		}
+
 		if !passed {
 			// Reset the timer since we have transitioned back to unhealthy
 			if primed {
@@ -597,14 +707,11 @@ func (t *Tracker) watchNomadEvents() {
 					continue
 				}
 				passing = false
+				t.recordFailedCheck(result.Service, result.Check, string(result.Status))
 			default:
 				// i.e. pending check; do not consider healthy or ready
 				passing = false
 			}
-
-			if !passing {
-				break // 1+ check is failing; no need to continue
-			}
 		}
 
 		if !passing {
@@ -629,6 +736,20 @@ type taskHealthState struct {
 	task              *structs.Task
 	state             *structs.TaskState
 	taskRegistrations *serviceregistration.ServiceRegistrations
+
+	// allocDir is the shared alloc dir against which the readiness file of
+	// the task is resolved
+	allocDir string
+}
+
+// readinessFileMissing returns true if the task is running and has not yet
+// written its readiness file.
+func (t *taskHealthState) readinessFileMissing() bool {
+	if t.task.ReadinessFile == "" || t.state == nil || t.state.State != structs.TaskStateRunning {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(t.allocDir, t.task.ReadinessFile))
+	return err != nil
 }
 
 // event takes the deadline time for the allocation to be healthy and the update
@@ -647,6 +768,12 @@ func (t *taskHealthState) event(deadline time.Time, healthyDeadline, minHealthyT
 		if t.state.Failed {
 			return "Unhealthy because of failed task", true
 		}
+		if event := markedUnhealthy(t.state); event != nil {
+			if event.Message != "" {
+				return fmt.Sprintf("Task marked itself unhealthy: %s", event.Message), true
+			}
+			return "Task marked itself unhealthy", true
+		}
 
 		switch t.state.State {
 		case structs.TaskStatePending:
@@ -661,6 +788,9 @@ func (t *taskHealthState) event(deadline time.Time, healthyDeadline, minHealthyT
 			if t.state.StartedAt.Add(minHealthyTime).After(deadline) {
 				return fmt.Sprintf("Task not running for min_healthy_time of %v by healthy_deadline of %v", minHealthyTime, healthyDeadline), true
 			}
+			if t.readinessFileMissing() {
+				return fmt.Sprintf("Task did not write readiness file %q by healthy_deadline of %v", t.task.ReadinessFile, healthyDeadline), true
+			}
 		}
 	}
 
@@ -3,6 +3,8 @@ package allochealth
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -81,7 +83,7 @@ func TestTracker_ConsulChecks_Healthy(t *testing.T) {
 
 	checks := checkstore.NewStore(logger, state.NewMemDB(logger))
 	checkInterval := 10 * time.Millisecond
-	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, time.Millisecond, true)
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), time.Millisecond, true)
 	tracker.checkLookupInterval = checkInterval
 	tracker.Start()
 
@@ -93,6 +95,114 @@ func TestTracker_ConsulChecks_Healthy(t *testing.T) {
 	}
 }
 
+func TestTracker_ConsulChecks_SidecarChecks(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].Migrate.MinHealthyTime = 1 // let's speed things up
+	alloc.Job.TaskGroups[0].Services = []*structs.Service{{
+		Name:      "api",
+		PortLabel: "9999",
+		Connect: &structs.ConsulConnect{
+			SidecarService: &structs.ConsulSidecarService{},
+		},
+	}}
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+
+	// Synthesize running alloc and tasks
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	alloc.TaskStates = map[string]*structs.TaskState{
+		task.Name: {
+			State:     structs.TaskStateRunning,
+			StartedAt: time.Now(),
+		},
+	}
+
+	// Make Consul response, first without the checks of the sidecar proxy,
+	// then with the sidecar listening check failing, then passing
+	taskRegs := map[string]*serviceregistration.ServiceRegistrations{
+		task.Name: {
+			Services: map[string]*serviceregistration.ServiceRegistration{
+				task.Services[0].Name: {
+					Service: &consulapi.AgentService{
+						ID:      "foo",
+						Service: task.Services[0].Name,
+					},
+					Checks: []*consulapi.AgentCheck{{
+						Name:        task.Services[0].Checks[0].Name,
+						ServiceName: task.Services[0].Name,
+						Status:      consulapi.HealthPassing,
+					}},
+				},
+			},
+		},
+	}
+	sidecarReg := func(status string) *serviceregistration.ServiceRegistrations {
+		return &serviceregistration.ServiceRegistrations{
+			Services: map[string]*serviceregistration.ServiceRegistration{
+				"api": {
+					Service: &consulapi.AgentService{
+						ID:      "api",
+						Service: "api",
+					},
+					Checks: []*consulapi.AgentCheck{{
+						Name:        "Connect Sidecar Aliasing api",
+						ServiceName: "api-sidecar-proxy",
+						Status:      consulapi.HealthPassing,
+					}, {
+						Name:        "Connect Sidecar Listening",
+						ServiceName: "api-sidecar-proxy",
+						Status:      status,
+					}},
+				},
+			},
+		}
+	}
+
+	logger := testlog.HCLogger(t)
+	b := cstructs.NewAllocBroadcaster(logger)
+	defer b.Close()
+
+	var called uint64
+	consul := regmock.NewServiceRegistrationHandler(logger)
+	consul.AllocRegistrationsFn = func(string) (*serviceregistration.AllocRegistration, error) {
+		reg := &serviceregistration.AllocRegistration{
+			Tasks: map[string]*serviceregistration.ServiceRegistrations{
+				task.Name: taskRegs[task.Name],
+			},
+		}
+		switch n := atomic.AddUint64(&called, 1); {
+		case n <= 5:
+		case n <= 10:
+			reg.Tasks["group-web"] = sidecarReg(consulapi.HealthCritical)
+		default:
+			reg.Tasks["group-web"] = sidecarReg(consulapi.HealthPassing)
+		}
+		return reg, nil
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	checks := checkstore.NewStore(logger, state.NewMemDB(logger))
+	checkInterval := 10 * time.Millisecond
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), time.Millisecond, true)
+	tracker.checkLookupInterval = checkInterval
+	tracker.Start()
+
+	select {
+	case <-time.After(30 * checkInterval):
+		require.Fail(t, "timed out while waiting for health")
+	case h := <-tracker.HealthyCh():
+		require.True(t, h)
+	}
+
+	// Health must not be set before the sidecar checks are registered and
+	// passing, and the failure seen along the way must be reported
+	require.Greater(t, atomic.LoadUint64(&called), uint64(10))
+	require.Equal(t, []string{"api-sidecar-proxy/Connect Sidecar Listening: critical"}, tracker.FailedChecks())
+}
+
 func TestTracker_NomadChecks_Healthy(t *testing.T) {
 	ci.Parallel(t)
 
@@ -132,7 +242,7 @@ func TestTracker_NomadChecks_Healthy(t *testing.T) {
 
 	consul := regmock.NewServiceRegistrationHandler(logger)
 	checkInterval := 10 * time.Millisecond
-	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, time.Millisecond, true)
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), time.Millisecond, true)
 	tracker.checkLookupInterval = checkInterval
 	tracker.Start()
 
@@ -199,7 +309,7 @@ func TestTracker_NomadChecks_Unhealthy(t *testing.T) {
 
 	consul := regmock.NewServiceRegistrationHandler(logger)
 	checkInterval := 10 * time.Millisecond
-	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, time.Millisecond, true)
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), time.Millisecond, true)
 	tracker.checkLookupInterval = checkInterval
 	tracker.Start()
 
@@ -258,7 +368,7 @@ func TestTracker_Checks_PendingPostStop_Healthy(t *testing.T) {
 
 	checks := checkstore.NewStore(logger, state.NewMemDB(logger))
 	checkInterval := 10 * time.Millisecond
-	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, time.Millisecond, true)
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), time.Millisecond, true)
 	tracker.checkLookupInterval = checkInterval
 	tracker.Start()
 
@@ -270,6 +380,111 @@ func TestTracker_Checks_PendingPostStop_Healthy(t *testing.T) {
 	}
 }
 
+func TestTracker_ReadinessFile(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].Migrate.MinHealthyTime = 1 // let's speed things up
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	alloc.Job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	task.Services = nil
+	task.ReadinessFile = "ready/web"
+
+	// Synthesize running alloc and tasks
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	alloc.TaskStates = map[string]*structs.TaskState{
+		task.Name: {
+			State:     structs.TaskStateRunning,
+			StartedAt: time.Now(),
+		},
+	}
+
+	logger := testlog.HCLogger(t)
+	b := cstructs.NewAllocBroadcaster(logger)
+	defer b.Close()
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelFn()
+
+	allocDir := t.TempDir()
+	checkInterval := 10 * time.Millisecond
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), nil, nil, allocDir, time.Millisecond, false)
+	tracker.checkLookupInterval = checkInterval
+	tracker.Start()
+
+	// the task is not healthy until it writes its readiness file
+	select {
+	case h := <-tracker.HealthyCh():
+		t.Fatalf("unexpected health event: %v", h)
+	case <-time.After(4 * checkInterval):
+	}
+
+	events := tracker.TaskEvents()
+	must.MapLen(t, 1, events)
+	must.Eq(t, fmt.Sprintf("Task did not write readiness file %q by healthy_deadline of %v",
+		"ready/web", alloc.Job.TaskGroups[0].Update.HealthyDeadline), events[task.Name].Message)
+
+	must.NoError(t, os.MkdirAll(filepath.Join(allocDir, "ready"), 0755))
+	must.NoError(t, os.WriteFile(filepath.Join(allocDir, "ready", "web"), nil, 0644))
+
+	select {
+	case <-time.After(4 * checkInterval):
+		t.Fatal("timed out while waiting for health")
+	case h := <-tracker.HealthyCh():
+		must.True(t, h)
+	}
+}
+
+func TestTracker_MarkedUnhealthy(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].Migrate.MinHealthyTime = 1 // let's speed things up
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	alloc.Job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	task.Services = nil
+
+	// Synthesize running alloc and tasks, and the task marking itself
+	// unhealthy since it started
+	started := time.Now()
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	alloc.TaskStates = map[string]*structs.TaskState{
+		task.Name: {
+			State:     structs.TaskStateRunning,
+			StartedAt: started,
+			Events: []*structs.TaskEvent{
+				structs.NewTaskEvent(structs.TaskMarkedUnhealthy).SetMessage("database unreachable"),
+			},
+		},
+	}
+
+	logger := testlog.HCLogger(t)
+	b := cstructs.NewAllocBroadcaster(logger)
+	defer b.Close()
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelFn()
+
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), nil, nil, t.TempDir(), time.Millisecond, false)
+	tracker.Start()
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatal("timed out while waiting for health")
+	case h := <-tracker.HealthyCh():
+		must.False(t, h)
+	}
+
+	events := tracker.TaskEvents()
+	must.MapLen(t, 1, events)
+	must.Eq(t, "Task marked itself unhealthy: database unreachable", events[task.Name].Message)
+
+	// Events from before the task last started are ignored
+	state := alloc.TaskStates[task.Name]
+	state.Events[0].Time = started.Add(-time.Minute).UnixNano()
+	must.Nil(t, markedUnhealthy(state))
+}
+
 func TestTracker_Succeeded_PostStart_Healthy(t *testing.T) {
 	ci.Parallel(t)
 
@@ -299,7 +514,7 @@ func TestTracker_Succeeded_PostStart_Healthy(t *testing.T) {
 
 	checks := checkstore.NewStore(logger, state.NewMemDB(logger))
 	checkInterval := 10 * time.Millisecond
-	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, alloc.Job.TaskGroups[0].Migrate.MinHealthyTime, true)
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), alloc.Job.TaskGroups[0].Migrate.MinHealthyTime, true)
 	tracker.checkLookupInterval = checkInterval
 	tracker.Start()
 
@@ -378,7 +593,7 @@ func TestTracker_ConsulChecks_Unhealthy(t *testing.T) {
 
 	checks := checkstore.NewStore(logger, state.NewMemDB(logger))
 	checkInterval := 10 * time.Millisecond
-	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, time.Millisecond, true)
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), time.Millisecond, true)
 	tracker.checkLookupInterval = checkInterval
 	tracker.Start()
 
@@ -410,7 +625,7 @@ func TestTracker_Healthy_IfBothTasksAndConsulChecksAreHealthy(t *testing.T) {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
 
-	tracker := NewTracker(ctx, logger, alloc, nil, nil, nil, time.Millisecond, true)
+	tracker := NewTracker(ctx, logger, alloc, nil, nil, nil, "", time.Millisecond, true)
 
 	assertNoHealth := func() {
 		require.NoError(t, tracker.ctx.Err())
@@ -519,7 +734,7 @@ func TestTracker_Checks_Healthy_Before_TaskHealth(t *testing.T) {
 
 	checks := checkstore.NewStore(logger, state.NewMemDB(logger))
 	checkInterval := 10 * time.Millisecond
-	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, time.Millisecond, true)
+	tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), time.Millisecond, true)
 	tracker.checkLookupInterval = checkInterval
 	tracker.Start()
 
@@ -661,7 +876,7 @@ func TestTracker_ConsulChecks_OnUpdate(t *testing.T) {
 
 			checks := checkstore.NewStore(logger, state.NewMemDB(logger))
 			checkInterval := 10 * time.Millisecond
-			tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, time.Millisecond, true)
+			tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), time.Millisecond, true)
 			tracker.checkLookupInterval = checkInterval
 			tracker.Start()
 
@@ -778,7 +993,7 @@ func TestTracker_NomadChecks_OnUpdate(t *testing.T) {
 
 			consul := regmock.NewServiceRegistrationHandler(logger)
 			minHealthyTime := 1 * time.Millisecond
-			tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, minHealthyTime, true)
+			tracker := NewTracker(ctx, logger, alloc, b.Listen(), consul, checks, t.TempDir(), minHealthyTime, true)
 			tracker.checkLookupInterval = 10 * time.Millisecond
 			tracker.Start()
 
@@ -17,8 +17,10 @@ import (
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
+	"github.com/hashicorp/nomad/client/envprovider"
 	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/client/mesh"
 	"github.com/hashicorp/nomad/client/pluginmanager/csimanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	"github.com/hashicorp/nomad/client/serviceregistration"
@@ -190,6 +192,13 @@ type allocRunner struct {
 
 	// getter is an interface for retrieving artifacts.
 	getter cinterfaces.ArtifactGetter
+
+	// envProviders runs the environment providers for tasks.
+	envProviders *envprovider.Manager
+
+	// meshHooks runs the hooks integrating the task group with an external
+	// service mesh.
+	meshHooks *mesh.Manager
 }
 
 // RPCer is the interface needed by hooks to make RPC calls.
@@ -205,6 +214,13 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		return nil, fmt.Errorf("failed to lookup task group %q", alloc.TaskGroup)
 	}
 
+	// Render the sidecar task of the mesh the group is integrated with
+	alloc, err := renderMeshSidecar(config.MeshHooks, alloc, nil)
+	if err != nil {
+		return nil, err
+	}
+	tg = alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+
 	ar := &allocRunner{
 		id:                       alloc.ID,
 		alloc:                    alloc,
@@ -236,6 +252,8 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		serviceRegWrapper:        config.ServiceRegWrapper,
 		checkStore:               config.CheckStore,
 		getter:                   config.Getter,
+		envProviders:             config.EnvProviders,
+		meshHooks:                config.MeshHooks,
 	}
 
 	// Create the logger based on the allocation ID
@@ -291,6 +309,8 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			ShutdownDelayCtx:     ar.shutdownDelayCtx,
 			ServiceRegWrapper:    ar.serviceRegWrapper,
 			Getter:               ar.getter,
+			EnvProviders:         ar.envProviders,
+			RPCClient:            ar.rpcClient,
 		}
 
 		if ar.cpusetManager != nil {
@@ -910,6 +930,13 @@ func (ar *allocRunner) handleAllocUpdate(update *structs.Allocation) {
 	// Detect Stop updates
 	stopping := !ar.Alloc().TerminalStatus() && update.TerminalStatus()
 
+	// Render the sidecar task of the mesh. The current sidecar task is kept
+	// if the hook fails, so the update is still applied.
+	update, err := renderMeshSidecar(ar.meshHooks, update, ar.Alloc())
+	if err != nil {
+		ar.logger.Error("failed to render mesh sidecar task", "error", err)
+	}
+
 	// Update ar.alloc
 	ar.setAlloc(update)
 
@@ -1010,6 +1037,39 @@ func (ar *allocRunner) PersistState() error {
 	return ar.stateDB.PutAllocation(ar.Alloc(), cstate.WithBatchMode())
 }
 
+// RestoreStatus returns the number of running tasks Restore reattached to and
+// the errors for the tasks it couldn't reattach to, by task name.
+func (ar *allocRunner) RestoreStatus() (int, map[string]error) {
+	reattached := 0
+	failed := map[string]error{}
+	for name, tr := range ar.tasks {
+		ok, err := tr.RestoreStatus()
+		if err != nil {
+			failed[name] = err
+		} else if ok {
+			reattached++
+		}
+	}
+	return reattached, failed
+}
+
+// VerifyRecoverable returns the number of running tasks a client restarted
+// from the persisted state would reattach to, and the errors for the tasks it
+// wouldn't, by task name.
+func (ar *allocRunner) VerifyRecoverable() (int, map[string]error) {
+	recoverable := 0
+	failed := map[string]error{}
+	for name, tr := range ar.tasks {
+		running, err := tr.VerifyRecoverable()
+		if err != nil {
+			failed[name] = err
+		} else if running {
+			recoverable++
+		}
+	}
+	return recoverable, failed
+}
+
 // Destroy the alloc runner by stopping it if it is still running and cleaning
 // up all of its resources.
 //
@@ -1330,6 +1390,27 @@ func (ar *allocRunner) GetTaskExecHandler(taskName string) drivermanager.TaskExe
 	return tr.TaskExecHandler()
 }
 
+// TemplateStatus returns the rendering state of the templates of the tasks of
+// the allocation, keyed by task. If taskName is set, only the templates of the
+// task are returned.
+func (ar *allocRunner) TemplateStatus(taskName string) (map[string][]*cstructs.TemplateStatus, error) {
+	if taskName != "" {
+		tr, ok := ar.tasks[taskName]
+		if !ok {
+			return nil, fmt.Errorf("task %q not found", taskName)
+		}
+		return map[string][]*cstructs.TemplateStatus{taskName: tr.TemplateStatus()}, nil
+	}
+
+	status := make(map[string][]*cstructs.TemplateStatus, len(ar.tasks))
+	for name, tr := range ar.tasks {
+		if tmpls := tr.TemplateStatus(); len(tmpls) > 0 {
+			status[name] = tmpls
+		}
+	}
+	return status, nil
+}
+
 func (ar *allocRunner) GetTaskDriverCapabilities(taskName string) (*drivers.Capabilities, error) {
 	tr, ok := ar.tasks[taskName]
 	if !ok {
@@ -70,12 +70,12 @@ func (a *allocHealthSetter) ClearHealth() {
 // deployment/migration health and emit task events.
 //
 // Only for use by health hook.
-func (a *allocHealthSetter) SetHealth(healthy, isDeploy bool, trackerTaskEvents map[string]*structs.TaskEvent) {
+func (a *allocHealthSetter) SetHealth(healthy, isDeploy bool, trackerTaskEvents map[string]*structs.TaskEvent, failedChecks []string) {
 	// Updating alloc deployment state is tricky because it may be nil, but
 	// if it's not then we need to maintain the values of Canary and
 	// ModifyIndex as they're only mutated by the server.
 	a.ar.stateLock.Lock()
-	a.ar.state.SetDeploymentStatus(time.Now(), healthy)
+	a.ar.state.SetDeploymentStatus(time.Now(), healthy, failedChecks)
 	a.ar.persistDeploymentStatus(a.ar.state.DeploymentStatus)
 	terminalDesiredState := a.ar.Alloc().ServerTerminalStatus()
 	a.ar.stateLock.Unlock()
@@ -133,6 +133,12 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 		return fmt.Errorf("failed to initialize network configurator: %v", err)
 	}
 
+	// run the mesh hook along the network configuration if the group is
+	// integrated with an external service mesh
+	if tg := ar.Alloc().Job.LookupTaskGroup(ar.Alloc().TaskGroup); tg != nil && tg.Mesh != nil {
+		nc = &meshNetworkConfigurator{nc: nc, hooks: ar.meshHooks, networkStatus: ar}
+	}
+
 	// Create a new taskenv.Builder which is used and mutated by networkHook.
 	envBuilder := taskenv.NewBuilder(
 		config.Node, ar.Alloc(), nil, config.Region).SetAllocDir(ar.allocDir.AllocDir)
@@ -149,7 +155,7 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 		newCgroupHook(ar.Alloc(), ar.cpusetManager),
 		newUpstreamAllocsHook(hookLogger, ar.prevAllocWatcher),
 		newDiskMigrationHook(hookLogger, ar.prevAllocMigrator, ar.allocDir),
-		newAllocHealthWatcherHook(hookLogger, alloc, hs, ar.Listener(), ar.consulClient, ar.checkStore),
+		newAllocHealthWatcherHook(hookLogger, alloc, hs, ar.Listener(), ar.consulClient, ar.checkStore, ar.allocDir.SharedDir),
 		newNetworkHook(hookLogger, ns, alloc, nm, nc, ar, builtTaskEnv),
 		newGroupServiceHook(groupServiceHookConfig{
 			alloc:             alloc,
@@ -164,7 +170,9 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 		newConsulGRPCSocketHook(hookLogger, alloc, ar.allocDir, config.ConsulConfig),
 		newConsulHTTPSocketHook(hookLogger, alloc, ar.allocDir, config.ConsulConfig),
 		newCSIHook(alloc, hookLogger, ar.csiManager, ar.rpcClient, ar, hrs, ar.clientConfig.Node.SecretID),
-		newChecksHook(hookLogger, alloc, ar.checkStore, ar),
+		newChecksHook(hookLogger, alloc, ar.checkStore, ar, ar.allocDir.SharedDir),
+		newPeersHook(alloc, hookLogger, ar.rpcClient, hrs, builtTaskEnv,
+			ar.allocDir.SharedDir, config.Region, config.Node.SecretID),
 	}
 
 	return nil
@@ -80,6 +80,10 @@ type checksHook struct {
 	checker checks.Checker
 	allocID string
 
+	// allocDir is the shared alloc dir, against which readiness_file checks
+	// are resolved
+	allocDir string
+
 	// fields that get re-initialized on allocation update
 	lock      sync.RWMutex
 	ctx       context.Context
@@ -93,14 +97,16 @@ func newChecksHook(
 	alloc *structs.Allocation,
 	shim checkstore.Shim,
 	network structs.NetworkStatus,
+	allocDir string,
 ) *checksHook {
 	h := &checksHook{
-		logger:  logger.Named(checksHookName),
-		allocID: alloc.ID,
-		alloc:   alloc,
-		shim:    shim,
-		network: network,
-		checker: checks.New(logger),
+		logger:   logger.Named(checksHookName),
+		allocID:  alloc.ID,
+		alloc:    alloc,
+		shim:     shim,
+		network:  network,
+		allocDir: allocDir,
+		checker:  checks.New(logger),
 	}
 	h.initialize(alloc)
 	return h
@@ -172,6 +178,7 @@ func (h *checksHook) observe(alloc *structs.Allocation, services []*structs.Serv
 					Ports:            ports,
 					Networks:         networks,
 					NetworkStatus:    h.network,
+					AllocDir:         h.allocDir,
 					Group:            alloc.Name,
 					Task:             service.TaskName,
 					Service:          service.Name,
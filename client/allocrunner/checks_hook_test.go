@@ -166,7 +166,7 @@ func TestCheckHook_Checks_ResultsSet(t *testing.T) {
 
 		alloc := allocWithNomadChecks(addr, port, tc.onGroup)
 
-		h := newChecksHook(logger, alloc, checkStore, network)
+		h := newChecksHook(logger, alloc, checkStore, network, t.TempDir())
 
 		// initialize is called; observers are created but not started yet
 		must.MapEmpty(t, h.observers)
@@ -231,7 +231,7 @@ func TestCheckHook_Checks_UpdateSet(t *testing.T) {
 
 	alloc := allocWithNomadChecks(addr, port, true)
 
-	h := newChecksHook(logger, alloc, shim, network)
+	h := newChecksHook(logger, alloc, shim, network, t.TempDir())
 
 	// calling pre-run starts the observers
 	err := h.Prerun()
@@ -7,8 +7,10 @@ import (
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
+	"github.com/hashicorp/nomad/client/envprovider"
 	"github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/client/mesh"
 	"github.com/hashicorp/nomad/client/pluginmanager/csimanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	"github.com/hashicorp/nomad/client/serviceregistration"
@@ -93,4 +95,11 @@ type Config struct {
 
 	// Getter is an interface for retrieving artifacts.
 	Getter interfaces.ArtifactGetter
+
+	// EnvProviders runs the environment providers for tasks.
+	EnvProviders *envprovider.Manager
+
+	// MeshHooks runs the hooks integrating task groups with external service
+	// meshes.
+	MeshHooks *mesh.Manager
 }
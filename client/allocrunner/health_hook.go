@@ -20,8 +20,9 @@ type healthSetter interface {
 	// HasHealth returns true if health is already set.
 	HasHealth() bool
 
-	// SetHealth via the mutator.
-	SetHealth(healthy, isDeploy bool, taskEvents map[string]*structs.TaskEvent)
+	// SetHealth via the mutator, along with the checks seen failing while
+	// determining it.
+	SetHealth(healthy, isDeploy bool, taskEvents map[string]*structs.TaskEvent, failedChecks []string)
 
 	// ClearHealth for when the deployment ID changes.
 	ClearHealth()
@@ -39,6 +40,10 @@ type allocHealthWatcherHook struct {
 	// checkStore is used to monitor Nomad service health checks
 	checkStore checkstore.Shim
 
+	// allocDir is the shared alloc dir in which tasks write their readiness
+	// files
+	allocDir string
+
 	// listener is given to trackers to listen for alloc updates and closed
 	// when the alloc is destroyed.
 	listener *cstructs.AllocListener
@@ -72,11 +77,13 @@ type allocHealthWatcherHook struct {
 }
 
 func newAllocHealthWatcherHook(logger hclog.Logger, alloc *structs.Allocation, hs healthSetter,
-	listener *cstructs.AllocListener, consul serviceregistration.Handler, checkStore checkstore.Shim) interfaces.RunnerHook {
+	listener *cstructs.AllocListener, consul serviceregistration.Handler, checkStore checkstore.Shim,
+	allocDir string) interfaces.RunnerHook {
 
 	// Neither deployments nor migrations care about the health of
-	// non-service jobs so never watch their health
-	if alloc.Job.Type != structs.JobTypeService {
+	// non-service jobs so never watch their health, unless it gates the
+	// rolling update of a system job.
+	if alloc.Job.Type != structs.JobTypeService && !systemUpdateHealthGated(alloc) {
 		return noopAllocHealthWatcherHook{}
 	}
 
@@ -90,6 +97,7 @@ func newAllocHealthWatcherHook(logger hclog.Logger, alloc *structs.Allocation, h
 		watchDone:    closedDone,
 		consul:       consul,
 		checkStore:   checkStore,
+		allocDir:     allocDir,
 		healthSetter: hs,
 		listener:     listener,
 	}
@@ -120,16 +128,20 @@ func (h *allocHealthWatcherHook) init() error {
 
 	h.isDeploy = h.alloc.DeploymentID != ""
 
+	// System jobs aren't deployed, but the health of their allocations
+	// gates the rolling updates defined by their update strategy.
+	useUpdate := h.isDeploy || h.alloc.Job.Type == structs.JobTypeSystem
+
 	// No need to watch allocs for deployments that rely on operators
 	// manually setting health
-	if h.isDeploy && (tg.Update.IsEmpty() || tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Manual) {
+	if useUpdate && (tg.Update.IsEmpty() || tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Manual) {
 		return nil
 	}
 
 	// Define the deadline, health method, min healthy time from the
-	// deployment if this is a deployment; otherwise from the migration
-	// strategy.
-	deadline, useChecks, minHealthyTime := getHealthParams(time.Now(), tg, h.isDeploy)
+	// update strategy if this is a deployment or a system job; otherwise
+	// from the migration strategy.
+	deadline, useChecks, minHealthyTime := getHealthParams(time.Now(), tg, useUpdate)
 
 	// Create a context that is canceled when the tracker should shutdown.
 	ctx := context.Background()
@@ -138,7 +150,7 @@ func (h *allocHealthWatcherHook) init() error {
 	h.logger.Trace("watching", "deadline", deadline, "checks", useChecks, "min_healthy_time", minHealthyTime)
 	// Create a new tracker, start it, and watch for health results.
 	tracker := allochealth.NewTracker(
-		ctx, h.logger, h.alloc, h.listener, h.consul, h.checkStore, minHealthyTime, useChecks,
+		ctx, h.logger, h.alloc, h.listener, h.consul, h.checkStore, h.allocDir, minHealthyTime, useChecks,
 	)
 	tracker.Start()
 
@@ -237,13 +249,24 @@ func (h *allocHealthWatcherHook) watchHealth(ctx context.Context, deadline time.
 		taskEvents = tracker.TaskEvents()
 	}
 
-	h.healthSetter.SetHealth(healthy, h.isDeploy, taskEvents)
+	h.healthSetter.SetHealth(healthy, h.isDeploy, taskEvents, tracker.FailedChecks())
+}
+
+// systemUpdateHealthGated returns whether the allocation belongs to a system
+// job whose rolling updates wait for the allocations to be healthy.
+func systemUpdateHealthGated(alloc *structs.Allocation) bool {
+	if alloc.Job.Type != structs.JobTypeSystem {
+		return false
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	return tg != nil && !tg.Update.IsEmpty() && tg.Update.HealthCheck != structs.UpdateStrategyHealthCheck_Manual
 }
 
 // getHealthParams returns the health watcher parameters which vary based on
-// whether this allocation is in a deployment or migration.
-func getHealthParams(now time.Time, tg *structs.TaskGroup, isDeploy bool) (deadline time.Time, useChecks bool, minHealthyTime time.Duration) {
-	if isDeploy {
+// whether the health is defined by the update strategy of the group, as in
+// deployments and system job updates, or by its migration strategy.
+func getHealthParams(now time.Time, tg *structs.TaskGroup, useUpdate bool) (deadline time.Time, useChecks bool, minHealthyTime time.Duration) {
+	if useUpdate {
 		deadline = now.Add(tg.Update.HealthyDeadline)
 		minHealthyTime = tg.Update.MinHealthyTime
 		useChecks = tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Checks
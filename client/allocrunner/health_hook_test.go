@@ -52,7 +52,7 @@ func newMockHealthSetter() *mockHealthSetter {
 	}
 }
 
-func (m *mockHealthSetter) SetHealth(healthy, isDeploy bool, taskEvents map[string]*structs.TaskEvent) {
+func (m *mockHealthSetter) SetHealth(healthy, isDeploy bool, taskEvents map[string]*structs.TaskEvent, failedChecks []string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -97,7 +97,7 @@ func TestHealthHook_PrerunPostrun(t *testing.T) {
 	hs := &mockHealthSetter{}
 
 	checks := new(mock.CheckShim)
-	h := newAllocHealthWatcherHook(logger, mock.Alloc(), hs, b.Listen(), consul, checks)
+	h := newAllocHealthWatcherHook(logger, mock.Alloc(), hs, b.Listen(), consul, checks, t.TempDir())
 
 	// Assert we implemented the right interfaces
 	prerunh, ok := h.(interfaces.RunnerPrerunHook)
@@ -136,7 +136,7 @@ func TestHealthHook_PrerunUpdatePostrun(t *testing.T) {
 	hs := &mockHealthSetter{}
 
 	checks := new(mock.CheckShim)
-	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, checks).(*allocHealthWatcherHook)
+	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, checks, t.TempDir()).(*allocHealthWatcherHook)
 
 	// Prerun
 	require.NoError(h.Prerun())
@@ -176,7 +176,7 @@ func TestHealthHook_UpdatePrerunPostrun(t *testing.T) {
 	hs := &mockHealthSetter{}
 
 	checks := new(mock.CheckShim)
-	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, checks).(*allocHealthWatcherHook)
+	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, checks, t.TempDir()).(*allocHealthWatcherHook)
 
 	// Set a DeploymentID to cause ClearHealth to be called
 	alloc.DeploymentID = uuid.Generate()
@@ -218,7 +218,7 @@ func TestHealthHook_Postrun(t *testing.T) {
 	hs := &mockHealthSetter{}
 
 	checks := new(mock.CheckShim)
-	h := newAllocHealthWatcherHook(logger, mock.Alloc(), hs, b.Listen(), consul, checks).(*allocHealthWatcherHook)
+	h := newAllocHealthWatcherHook(logger, mock.Alloc(), hs, b.Listen(), consul, checks, t.TempDir()).(*allocHealthWatcherHook)
 
 	// Postrun
 	require.NoError(h.Postrun())
@@ -285,7 +285,7 @@ func TestHealthHook_SetHealth_healthy(t *testing.T) {
 	hs := newMockHealthSetter()
 
 	checks := new(mock.CheckShim)
-	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, checks).(*allocHealthWatcherHook)
+	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, checks, t.TempDir()).(*allocHealthWatcherHook)
 
 	// Prerun
 	require.NoError(h.Prerun())
@@ -374,7 +374,7 @@ func TestHealthHook_SetHealth_unhealthy(t *testing.T) {
 	hs := newMockHealthSetter()
 
 	checks := new(mock.CheckShim)
-	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, checks).(*allocHealthWatcherHook)
+	h := newAllocHealthWatcherHook(logger, alloc.Copy(), hs, b.Listen(), consul, checks, t.TempDir()).(*allocHealthWatcherHook)
 
 	// Prerun
 	require.NoError(h.Prerun())
@@ -395,7 +395,7 @@ func TestHealthHook_SetHealth_unhealthy(t *testing.T) {
 func TestHealthHook_SystemNoop(t *testing.T) {
 	ci.Parallel(t)
 
-	h := newAllocHealthWatcherHook(testlog.HCLogger(t), mock.SystemAlloc(), nil, nil, nil, nil)
+	h := newAllocHealthWatcherHook(testlog.HCLogger(t), mock.SystemAlloc(), nil, nil, nil, nil, "")
 
 	// Assert that it's the noop impl
 	_, ok := h.(noopAllocHealthWatcherHook)
@@ -412,11 +412,30 @@ func TestHealthHook_SystemNoop(t *testing.T) {
 	require.False(t, ok)
 }
 
+// TestHealthHook_SystemUpdate asserts that the health of system jobs is
+// watched when it gates their rolling updates.
+func TestHealthHook_SystemUpdate(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.SystemAlloc()
+	alloc.Job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+
+	h := newAllocHealthWatcherHook(testlog.HCLogger(t), alloc, nil, nil, nil, nil, "")
+	_, ok := h.(*allocHealthWatcherHook)
+	require.True(t, ok)
+
+	// Health set manually doesn't gate the updates of system jobs
+	alloc.Job.TaskGroups[0].Update.HealthCheck = structs.UpdateStrategyHealthCheck_Manual
+	h = newAllocHealthWatcherHook(testlog.HCLogger(t), alloc, nil, nil, nil, nil, "")
+	_, ok = h.(noopAllocHealthWatcherHook)
+	require.True(t, ok)
+}
+
 // TestHealthHook_BatchNoop asserts that batch jobs return the noop tracker.
 func TestHealthHook_BatchNoop(t *testing.T) {
 	ci.Parallel(t)
 
-	h := newAllocHealthWatcherHook(testlog.HCLogger(t), mock.BatchAlloc(), nil, nil, nil, nil)
+	h := newAllocHealthWatcherHook(testlog.HCLogger(t), mock.BatchAlloc(), nil, nil, nil, nil, "")
 
 	// Assert that it's the noop impl
 	_, ok := h.(noopAllocHealthWatcherHook)
@@ -0,0 +1,90 @@
+package allocrunner
+
+import (
+	"context"
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/client/mesh"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// renderMeshSidecar returns a copy of the allocation whose mesh sidecar task,
+// injected by the server with no driver configuration, is rendered by the
+// mesh hook. The allocation is returned as is if its group isn't integrated
+// with a mesh.
+//
+// The sidecar task rendered for the previous version of the allocation is
+// reused if the job didn't change. It is also used if the hook fails, in which
+// case the error is returned along with the allocation.
+func renderMeshSidecar(hooks *mesh.Manager, alloc, prev *structs.Allocation) (*structs.Allocation, error) {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return alloc, nil
+	}
+	sidecar := tg.LookupMeshSidecar()
+	if sidecar == nil {
+		return alloc, nil
+	}
+
+	var prevSidecar *structs.Task
+	if prev != nil {
+		prevSidecar = prev.LookupTask(sidecar.Name)
+	}
+
+	var rendered *structs.Task
+	var renderErr error
+	if prevSidecar != nil && prev.Job.JobModifyIndex == alloc.Job.JobModifyIndex {
+		rendered = prevSidecar
+	} else {
+		rendered, renderErr = hooks.RenderSidecar(context.TODO(), alloc, sidecar)
+		if renderErr != nil {
+			if prevSidecar == nil {
+				return nil, fmt.Errorf("failed to render mesh sidecar task: %v", renderErr)
+			}
+			rendered = prevSidecar
+		}
+	}
+
+	alloc = alloc.Copy()
+	tg = alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	for i, t := range tg.Tasks {
+		if t.Name == rendered.Name {
+			tg.Tasks[i] = rendered.Copy()
+		}
+	}
+	return alloc, renderErr
+}
+
+// meshNetworkConfigurator wraps the NetworkConfigurator of an allocation whose
+// group is integrated with an external service mesh, to run the mesh hook once
+// the network namespace is configured and before it is torn down.
+type meshNetworkConfigurator struct {
+	nc            NetworkConfigurator
+	hooks         *mesh.Manager
+	networkStatus structs.NetworkStatus
+}
+
+func (m *meshNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Allocation, spec *drivers.NetworkIsolationSpec) (*structs.AllocNetworkStatus, error) {
+	status, err := m.nc.Setup(ctx, alloc, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.hooks.SetupNetwork(ctx, alloc, spec, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (m *meshNetworkConfigurator) Teardown(ctx context.Context, alloc *structs.Allocation, spec *drivers.NetworkIsolationSpec) error {
+	var mErr multierror.Error
+	if err := m.hooks.TeardownNetwork(ctx, alloc, spec, m.networkStatus.NetworkStatus()); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if err := m.nc.Teardown(ctx, alloc, spec); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
@@ -0,0 +1,80 @@
+package allocrunner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/mesh"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMeshSidecar(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("mesh hook tests use shell scripts")
+	}
+
+	// The hook renders the image from a file, so the test can change it
+	dir := t.TempDir()
+	image := filepath.Join(dir, "image")
+	require.NoError(t, ioutil.WriteFile(image, []byte("proxy:1"), 0644))
+	hook := filepath.Join(dir, "hook")
+	require.NoError(t, ioutil.WriteFile(hook, []byte(`#!/bin/sh
+img=$(cat `+image+`) || exit 1
+echo "{\"Config\":{\"image\":\"$img\"}}"
+`), 0755))
+	hooks := mesh.NewManager(testlog.HCLogger(t), []*config.MeshHookConfig{
+		{Name: "istio", Command: hook, Timeout: 5 * time.Second},
+	})
+
+	alloc := mock.Alloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	tg.Mesh = &structs.Mesh{Type: "istio", Driver: "docker"}
+	tg.Tasks = append(tg.Tasks, &structs.Task{
+		Name:   "mesh-sidecar-istio",
+		Kind:   structs.NewTaskKind(structs.MeshSidecarPrefix, "istio"),
+		Driver: "docker",
+	})
+
+	// Allocations without a mesh are returned as is
+	other := mock.Alloc()
+	out, err := renderMeshSidecar(hooks, other, nil)
+	require.NoError(t, err)
+	require.Same(t, other, out)
+
+	rendered, err := renderMeshSidecar(hooks, alloc, nil)
+	require.NoError(t, err)
+	require.Equal(t, "proxy:1", rendered.LookupTask("mesh-sidecar-istio").Config["image"])
+	require.Nil(t, alloc.LookupTask("mesh-sidecar-istio").Config)
+
+	// The sidecar is not rendered again for the same job
+	require.NoError(t, ioutil.WriteFile(image, []byte("proxy:2"), 0644))
+	update, err := renderMeshSidecar(hooks, alloc.Copy(), rendered)
+	require.NoError(t, err)
+	require.Equal(t, "proxy:1", update.LookupTask("mesh-sidecar-istio").Config["image"])
+
+	// but is for a new version of the job
+	alloc.Job.JobModifyIndex++
+	update, err = renderMeshSidecar(hooks, alloc.Copy(), rendered)
+	require.NoError(t, err)
+	require.Equal(t, "proxy:2", update.LookupTask("mesh-sidecar-istio").Config["image"])
+
+	// The previous sidecar is kept if the hook fails
+	require.NoError(t, os.Remove(image))
+	alloc.Job.JobModifyIndex++
+	update, err = renderMeshSidecar(hooks, alloc.Copy(), rendered)
+	require.Error(t, err)
+	require.Equal(t, "proxy:1", update.LookupTask("mesh-sidecar-istio").Config["image"])
+
+	_, err = renderMeshSidecar(hooks, alloc.Copy(), nil)
+	require.ErrorContains(t, err, "failed to render mesh sidecar task")
+}
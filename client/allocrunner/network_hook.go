@@ -167,6 +167,14 @@ func (h *networkHook) Prerun() error {
 				Address:  status.Address,
 				Hostname: hostname,
 			}
+		} else if status != nil && status.Address != "" {
+
+			// The network namespace was created by the client, so there is no
+			// sandbox hostname. Still pass the address on so drivers can
+			// report the IP of their tasks.
+			h.spec.HostsConfig = &drivers.HostsConfig{
+				Address: status.Address,
+			}
 		}
 
 		h.networkStatusSetter.SetNetworkStatus(status)
@@ -0,0 +1,279 @@
+package allocrunner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// peersFile is the name of the file in the shared alloc dir listing the
+	// peers of the allocation.
+	peersFile = "peers.json"
+
+	// peersMaxQueryTime is the maximum time a blocking query for the
+	// registrations of a service waits for a change.
+	peersMaxQueryTime = 5 * time.Minute
+)
+
+// allocPeer is a registration of a Nomad service by another allocation of the
+// job, as rendered in the peers file.
+type allocPeer struct {
+	AllocID string
+	NodeID  string
+	Address string
+	Port    int
+}
+
+// peersHook watches the registrations of the Nomad services of the task group
+// and keeps track of the other allocations of the job registering them, so
+// clustered applications can discover their peers without templates. The
+// peers are rendered to a file in the shared alloc dir whenever they change
+// and are provided to the task runners, which set them in the task
+// environment.
+//
+// It is a noop for allocs whose group doesn't use Nomad services.
+type peersHook struct {
+	alloc     *structs.Allocation
+	logger    hclog.Logger
+	rpcClient RPCer
+	updater   hookResourceSetter
+
+	// path is the host path of the peers file.
+	path string
+
+	// services are the interpolated names of the Nomad services of the
+	// task group.
+	services []string
+
+	region     string
+	nodeSecret string
+
+	// peers are the registrations of the other allocations by service.
+	peers     map[string][]*allocPeer
+	peersLock sync.Mutex
+
+	minBackoffInterval time.Duration
+	maxBackoffInterval time.Duration
+
+	shutdownCtx      context.Context
+	shutdownCancelFn context.CancelFunc
+}
+
+func newPeersHook(alloc *structs.Allocation, logger hclog.Logger, rpcClient RPCer,
+	updater hookResourceSetter, env *taskenv.TaskEnv, sharedDir, region, nodeSecret string) *peersHook {
+
+	shutdownCtx, shutdownCancelFn := context.WithCancel(context.Background())
+
+	h := &peersHook{
+		alloc:              alloc,
+		rpcClient:          rpcClient,
+		updater:            updater,
+		path:               filepath.Join(sharedDir, peersFile),
+		region:             region,
+		nodeSecret:         nodeSecret,
+		peers:              map[string][]*allocPeer{},
+		minBackoffInterval: time.Second,
+		maxBackoffInterval: time.Minute,
+		shutdownCtx:        shutdownCtx,
+		shutdownCancelFn:   shutdownCancelFn,
+	}
+	h.logger = logger.Named(h.Name())
+
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil {
+		seen := map[string]struct{}{}
+		for _, service := range tg.NomadServices() {
+			name := env.ReplaceEnv(service.Name)
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				h.services = append(h.services, name)
+			}
+		}
+		sort.Strings(h.services)
+	}
+	return h
+}
+
+func (*peersHook) Name() string {
+	return "peers_hook"
+}
+
+// Prerun fetches the current peers, so they are available when the tasks
+// start, and starts watching them for changes.
+func (h *peersHook) Prerun() error {
+	if len(h.services) == 0 {
+		return nil
+	}
+
+	indexes := make(map[string]uint64, len(h.services))
+	for _, service := range h.services {
+		registrations, index, err := h.query(service, 0)
+		if err != nil {
+			return fmt.Errorf("failed to query peers of service %q: %v", service, err)
+		}
+		h.setPeers(service, registrations)
+		indexes[service] = index
+	}
+	if err := h.render(); err != nil {
+		return err
+	}
+
+	// Propagate the hook resources to the task runners, which are created
+	// after the hooks. Later updates of the peers are made in place.
+	h.updater.SetAllocHookResources(h.updater.GetAllocHookResources())
+
+	for _, service := range h.services {
+		go h.watch(service, indexes[service])
+	}
+	return nil
+}
+
+// watch runs blocking queries for the registrations of the service and
+// updates the peers until the hook is shut down.
+func (h *peersHook) watch(service string, index uint64) {
+	backoff := h.minBackoffInterval
+	for {
+		registrations, newIndex, err := h.query(service, index)
+		if h.shutdownCtx.Err() != nil {
+			return
+		}
+		if err != nil {
+			h.logger.Warn("failed to query peers", "service", service, "error", err, "retry_in", backoff)
+			select {
+			case <-h.shutdownCtx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < h.maxBackoffInterval {
+				backoff = backoff * 2
+				if backoff > h.maxBackoffInterval {
+					backoff = h.maxBackoffInterval
+				}
+			}
+			continue
+		}
+		backoff = h.minBackoffInterval
+
+		// Blocking queries return when they time out without changes
+		if newIndex <= index {
+			continue
+		}
+		index = newIndex
+
+		if h.setPeers(service, registrations) {
+			if err := h.render(); err != nil {
+				h.logger.Error("failed to render peers", "error", err)
+			}
+		}
+	}
+}
+
+// query returns the registrations of the service, blocking until they
+// change past the given index if it's set.
+func (h *peersHook) query(service string, index uint64) ([]*structs.ServiceRegistration, uint64, error) {
+	req := &structs.ServiceRegistrationByNameRequest{
+		ServiceName: service,
+		QueryOptions: structs.QueryOptions{
+			Region:        h.region,
+			Namespace:     h.alloc.ServiceProviderNamespace(),
+			AuthToken:     h.nodeSecret,
+			AllowStale:    true,
+			MinQueryIndex: index,
+			MaxQueryTime:  peersMaxQueryTime,
+		},
+	}
+	var resp structs.ServiceRegistrationByNameResponse
+	if err := h.rpcClient.RPC(structs.ServiceRegistrationGetServiceRPCMethod, req, &resp); err != nil {
+		return nil, 0, err
+	}
+	return resp.Services, resp.Index, nil
+}
+
+// setPeers updates the peers of the service from its registrations and
+// returns whether they changed.
+func (h *peersHook) setPeers(service string, registrations []*structs.ServiceRegistration) bool {
+	peers := []*allocPeer{}
+	for _, reg := range registrations {
+		if reg.JobID != h.alloc.JobID || reg.AllocID == h.alloc.ID {
+			continue
+		}
+		peers = append(peers, &allocPeer{
+			AllocID: reg.AllocID,
+			NodeID:  reg.NodeID,
+			Address: reg.Address,
+			Port:    reg.Port,
+		})
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].AllocID < peers[j].AllocID
+	})
+
+	h.peersLock.Lock()
+	defer h.peersLock.Unlock()
+
+	changed := len(peers) != len(h.peers[service])
+	for i := 0; !changed && i < len(peers); i++ {
+		changed = *peers[i] != *h.peers[service][i]
+	}
+	h.peers[service] = peers
+	return changed
+}
+
+// render writes the peers file and updates the peers of the alloc hook
+// resources, which are read by the task runners.
+func (h *peersHook) render() error {
+	h.peersLock.Lock()
+	defer h.peersLock.Unlock()
+
+	addrs := make(map[string][]string, len(h.peers))
+	for service, peers := range h.peers {
+		addrs[service] = make([]string, len(peers))
+		for i, p := range peers {
+			addrs[service][i] = fmt.Sprintf("%s:%d", p.Address, p.Port)
+		}
+	}
+
+	h.updater.GetAllocHookResources().SetPeers(addrs)
+
+	buf, err := json.MarshalIndent(h.peers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode peers: %v", err)
+	}
+
+	// Write the file atomically, so tasks never read a partial file
+	tmp := h.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf, 0644); err != nil {
+		return fmt.Errorf("failed to write peers file: %v", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return fmt.Errorf("failed to write peers file: %v", err)
+	}
+	return nil
+}
+
+// Postrun stops watching the peers once the tasks have exited.
+func (h *peersHook) Postrun() error {
+	h.shutdownCancelFn()
+	return nil
+}
+
+// Shutdown stops watching the peers when the client shuts down.
+func (h *peersHook) Shutdown() {
+	h.shutdownCancelFn()
+}
+
+// Destroy stops watching the peers when the allocation is garbage collected.
+func (h *peersHook) Destroy() error {
+	h.shutdownCancelFn()
+	return nil
+}
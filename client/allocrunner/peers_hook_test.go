@@ -0,0 +1,140 @@
+package allocrunner
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+var _ interfaces.RunnerPrerunHook = (*peersHook)(nil)
+var _ interfaces.RunnerPostrunHook = (*peersHook)(nil)
+var _ interfaces.RunnerDestroyHook = (*peersHook)(nil)
+var _ interfaces.ShutdownHook = (*peersHook)(nil)
+
+// peersRPCer mocks the ServiceRegistration.GetService RPC, blocking queries
+// return when the registrations change.
+type peersRPCer struct {
+	lock          sync.Mutex
+	index         uint64
+	registrations []*structs.ServiceRegistration
+}
+
+func (r *peersRPCer) RPC(method string, args interface{}, reply interface{}) error {
+	req := args.(*structs.ServiceRegistrationByNameRequest)
+	resp := reply.(*structs.ServiceRegistrationByNameResponse)
+
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for {
+		r.lock.Lock()
+		if r.index > req.MinQueryIndex || time.Now().After(deadline) {
+			for _, reg := range r.registrations {
+				if reg.ServiceName == req.ServiceName {
+					resp.Services = append(resp.Services, reg)
+				}
+			}
+			resp.Index = r.index
+			r.lock.Unlock()
+			return nil
+		}
+		r.lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (r *peersRPCer) register(reg *structs.ServiceRegistration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.registrations = append(r.registrations, reg)
+	r.index++
+}
+
+func TestPeersHook(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].Services = []*structs.Service{{
+		Name:      "${NOMAD_JOB_NAME}-db",
+		PortLabel: "db",
+		Provider:  structs.ServiceProviderNomad,
+	}}
+	service := alloc.Job.Name + "-db"
+
+	rpc := &peersRPCer{}
+	rpc.register(&structs.ServiceRegistration{
+		ServiceName: service, JobID: alloc.JobID, AllocID: alloc.ID,
+		Address: "10.0.0.1", Port: 5432,
+	})
+	rpc.register(&structs.ServiceRegistration{
+		ServiceName: service, JobID: alloc.JobID, AllocID: "b",
+		Address: "10.0.0.2", Port: 5432,
+	})
+	rpc.register(&structs.ServiceRegistration{
+		ServiceName: service, JobID: "other", AllocID: "c",
+		Address: "10.0.0.3", Port: 5432,
+	})
+
+	dir := t.TempDir()
+	ar := &mockAllocRunner{res: &cstructs.AllocHookResources{}}
+	env := taskenv.NewBuilder(mock.Node(), alloc, nil, "global").Build()
+
+	hook := newPeersHook(alloc, testlog.HCLogger(t), rpc, ar, env, dir, "global", "")
+	hook.minBackoffInterval = 10 * time.Millisecond
+	require.Equal(t, []string{service}, hook.services)
+
+	require.NoError(t, hook.Prerun())
+	defer hook.Postrun()
+
+	require.Equal(t, map[string][]string{service: {"10.0.0.2:5432"}}, ar.res.GetPeers())
+
+	readFile := func() map[string][]*allocPeer {
+		buf, err := ioutil.ReadFile(filepath.Join(dir, peersFile))
+		require.NoError(t, err)
+		var peers map[string][]*allocPeer
+		require.NoError(t, json.Unmarshal(buf, &peers))
+		return peers
+	}
+	require.Equal(t, map[string][]*allocPeer{
+		service: {{AllocID: "b", Address: "10.0.0.2", Port: 5432}},
+	}, readFile())
+
+	// New peers are picked up by the watch
+	rpc.register(&structs.ServiceRegistration{
+		ServiceName: service, JobID: alloc.JobID, AllocID: "d",
+		Address: "10.0.0.4", Port: 5432,
+	})
+	require.Eventually(t, func() bool {
+		return len(ar.res.GetPeers()[service]) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"10.0.0.2:5432", "10.0.0.4:5432"}, ar.res.GetPeers()[service])
+	require.Len(t, readFile()[service], 2)
+}
+
+func TestPeersHook_NoNomadServices(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	dir := t.TempDir()
+	ar := &mockAllocRunner{res: &cstructs.AllocHookResources{}}
+	env := taskenv.NewBuilder(mock.Node(), alloc, nil, "global").Build()
+
+	hook := newPeersHook(alloc, testlog.HCLogger(t), &peersRPCer{}, ar, env, dir, "global", "")
+	require.NoError(t, hook.Prerun())
+	require.NoError(t, hook.Postrun())
+
+	require.Nil(t, ar.res.GetPeers())
+	_, err := os.Stat(filepath.Join(dir, peersFile))
+	require.True(t, os.IsNotExist(err))
+}
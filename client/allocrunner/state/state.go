@@ -26,20 +26,21 @@ type State struct {
 }
 
 // SetDeploymentStatus is a helper for updating the client-controlled
-// DeploymentStatus fields: Healthy and Timestamp. The Canary and ModifyIndex
-// fields should only be updated by the server.
-func (s *State) SetDeploymentStatus(timestamp time.Time, healthy bool) {
+// DeploymentStatus fields: Healthy, Timestamp and FailedChecks. The Canary and
+// ModifyIndex fields should only be updated by the server.
+func (s *State) SetDeploymentStatus(timestamp time.Time, healthy bool, failedChecks []string) {
 	if s.DeploymentStatus == nil {
 		s.DeploymentStatus = &structs.AllocDeploymentStatus{}
 	}
 
 	s.DeploymentStatus.Healthy = &healthy
 	s.DeploymentStatus.Timestamp = timestamp
+	s.DeploymentStatus.FailedChecks = failedChecks
 }
 
 // ClearDeploymentStatus is a helper to clear the client-controlled
-// DeploymentStatus fields: Healthy and Timestamp. The Canary and ModifyIndex
-// fields should only be updated by the server.
+// DeploymentStatus fields: Healthy, Timestamp and FailedChecks. The Canary and
+// ModifyIndex fields should only be updated by the server.
 func (s *State) ClearDeploymentStatus() {
 	if s.DeploymentStatus == nil {
 		return
@@ -47,6 +48,7 @@ func (s *State) ClearDeploymentStatus() {
 
 	s.DeploymentStatus.Healthy = nil
 	s.DeploymentStatus.Timestamp = time.Time{}
+	s.DeploymentStatus.FailedChecks = nil
 }
 
 // Copy returns a deep copy of State.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/dustin/go-humanize"
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
@@ -28,7 +29,7 @@ func newArtifactHook(e ti.EventEmitter, getter ci.ArtifactGetter, logger log.Log
 	return h
 }
 
-func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse, jobs chan *structs.TaskArtifact, errorChannel chan error, wg *sync.WaitGroup, responseStateMutex *sync.Mutex) {
+func (h *artifactHook) doWork(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse, jobs chan *structs.TaskArtifact, errorChannel chan error, wg *sync.WaitGroup, responseStateMutex *sync.Mutex) {
 	defer wg.Done()
 	for artifact := range jobs {
 		aid := artifact.Hash()
@@ -41,14 +42,15 @@ func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfa
 		}
 
 		h.logger.Debug("downloading artifact", "artifact", artifact.GetterSource, "aid", aid)
-		//XXX add ctx to GetArtifact to allow cancelling long downloads
-		if err := h.getter.GetArtifact(req.TaskEnv, artifact); err != nil {
+		if err := h.getter.GetArtifact(ctx, req.TaskEnv, artifact, h.progressFunc(artifact)); err != nil {
 
 			wrapped := structs.NewRecoverableError(
 				fmt.Errorf("failed to download artifact %q: %v", artifact.GetterSource, err),
 				true,
 			)
-			herr := NewHookError(wrapped, structs.NewTaskEvent(structs.TaskArtifactDownloadFailed).SetDownloadError(wrapped))
+			herr := NewHookError(wrapped, structs.NewTaskEvent(structs.TaskArtifactDownloadFailed).
+				SetDownloadError(wrapped).
+				SetArtifactURL(artifact.GetterSource))
 
 			errorChannel <- herr
 			continue
@@ -63,6 +65,23 @@ func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfa
 	}
 }
 
+// progressFunc returns a func emitting task events with the progress of the
+// download of the artifact, so that large downloads are visible to users.
+func (h *artifactHook) progressFunc(artifact *structs.TaskArtifact) ci.ArtifactProgressFunc {
+	return func(downloaded, total int64) {
+		var msg string
+		if total > 0 {
+			msg = fmt.Sprintf("Downloaded %s of %s (%d%%)",
+				humanize.IBytes(uint64(downloaded)), humanize.IBytes(uint64(total)), downloaded*100/total)
+		} else {
+			msg = fmt.Sprintf("Downloaded %s", humanize.IBytes(uint64(downloaded)))
+		}
+		h.eventEmitter.EmitEvent(structs.NewTaskEvent(structs.TaskArtifactDownloadProgress).
+			SetArtifactURL(artifact.GetterSource).
+			SetMessage(msg))
+	}
+}
+
 func (*artifactHook) Name() string {
 	// Copied in client/state when upgrading from <0.9 schemas, so if you
 	// change it here you also must change it there.
@@ -98,7 +117,7 @@ func (h *artifactHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 		var wg sync.WaitGroup
 		for i := 0; i < maxConcurrency; i++ {
 			wg.Add(1)
-			go h.doWork(req, resp, jobsChannel, errorChannel, &wg, responseStateMutex)
+			go h.doWork(ctx, req, resp, jobsChannel, errorChannel, &wg, responseStateMutex)
 		}
 		wg.Wait()
 	}()
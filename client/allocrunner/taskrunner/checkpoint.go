@@ -0,0 +1,97 @@
+package taskrunner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// checkpointDirName is the directory in the task local dir the task is
+	// checkpointed to. It is in the local dir so it is migrated along with
+	// the ephemeral disk of the allocation.
+	checkpointDirName = ".checkpoint"
+)
+
+// checkpointDir returns the host path the task is checkpointed to.
+func (tr *TaskRunner) checkpointDir() string {
+	return filepath.Join(tr.taskDir.LocalDir, checkpointDirName)
+}
+
+// checkpointDriver returns the driver as a CheckpointDriver if it supports
+// checkpointing tasks.
+func (tr *TaskRunner) checkpointDriver() (drivers.CheckpointDriver, bool) {
+	if tr.driverCapabilities == nil || !tr.driverCapabilities.Checkpoint {
+		return nil, false
+	}
+	d, ok := tr.driver.(drivers.CheckpointDriver)
+	return d, ok
+}
+
+// shouldCheckpoint returns true if the task is being stopped because its
+// allocation is migrated off a draining node, and the ephemeral disk the
+// checkpoint is written to is migrated to the replacement allocation.
+func (tr *TaskRunner) shouldCheckpoint() bool {
+	if _, ok := tr.checkpointDriver(); !ok {
+		return false
+	}
+
+	alloc := tr.Alloc()
+	if !alloc.DesiredTransition.ShouldMigrate() {
+		return false
+	}
+
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	return tg != nil && tg.EphemeralDisk != nil && tg.EphemeralDisk.Migrate
+}
+
+// checkpointTask checkpoints the task before it is killed so the allocation
+// replacing it can restore it. Failing to checkpoint isn't fatal, the task is
+// killed as usual and the replacement starts it from scratch.
+func (tr *TaskRunner) checkpointTask(handle *DriverHandle) {
+	d, ok := tr.checkpointDriver()
+	if !ok {
+		return
+	}
+
+	dir := tr.checkpointDir()
+	if err := d.CheckpointTask(handle.ID(), dir); err != nil {
+		tr.logger.Warn("failed to checkpoint task, killing it instead", "error", err)
+		os.RemoveAll(dir)
+		return
+	}
+
+	tr.logger.Info("checkpointed task for migration", "dir", dir)
+	tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointed).
+		SetMessage("Task checkpointed for migration"))
+}
+
+// startTask starts the task, restoring it from the checkpoint migrated from
+// the previous allocation if there is one. The checkpoint is removed once
+// used, and the task is started from scratch if it can't be restored.
+func (tr *TaskRunner) startTask(taskConfig *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	dir := tr.checkpointDir()
+	if _, err := os.Stat(dir); err != nil {
+		return tr.driver.StartTask(taskConfig)
+	}
+	defer os.RemoveAll(dir)
+
+	d, ok := tr.checkpointDriver()
+	if !ok {
+		tr.logger.Debug("driver does not support restoring checkpoints, ignoring checkpoint")
+		return tr.driver.StartTask(taskConfig)
+	}
+
+	handle, net, err := d.RestoreTask(taskConfig, dir)
+	if err != nil {
+		tr.logger.Warn("failed to restore task from checkpoint, starting it instead", "error", err)
+		return tr.driver.StartTask(taskConfig)
+	}
+
+	tr.logger.Info("restored task from checkpoint")
+	tr.EmitEvent(structs.NewTaskEvent(structs.TaskRestoredCheckpoint).
+		SetMessage("Task restored from the checkpoint of the previous allocation"))
+	return handle, net, nil
+}
@@ -0,0 +1,125 @@
+package taskrunner
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCheckpointDriver records checkpoints and restores. Other driver methods
+// are not implemented.
+type mockCheckpointDriver struct {
+	drivers.DriverPlugin
+
+	checkpointed []string
+	restored     []string
+	started      int
+	restoreErr   error
+}
+
+func (d *mockCheckpointDriver) StartTask(*drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	d.started++
+	return drivers.NewTaskHandle(1), nil, nil
+}
+
+func (d *mockCheckpointDriver) CheckpointTask(taskID string, dir string) error {
+	d.checkpointed = append(d.checkpointed, taskID)
+	return os.MkdirAll(dir, 0700)
+}
+
+func (d *mockCheckpointDriver) RestoreTask(_ *drivers.TaskConfig, dir string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	if d.restoreErr != nil {
+		return nil, nil, d.restoreErr
+	}
+	d.restored = append(d.restored, dir)
+	return drivers.NewTaskHandle(1), nil, nil
+}
+
+func testCheckpointTaskRunner(t *testing.T, alloc *structs.Allocation) (*TaskRunner, *mockCheckpointDriver, func()) {
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	conf, cleanup := testTaskRunnerConfig(t, alloc, task.Name)
+
+	tr, err := NewTaskRunner(conf)
+	require.NoError(t, err)
+
+	driver := &mockCheckpointDriver{}
+	tr.driver = driver
+	tr.driverCapabilities = &drivers.Capabilities{Checkpoint: true}
+	return tr, driver, cleanup
+}
+
+func TestTaskRunner_Checkpoint_ShouldCheckpoint(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].EphemeralDisk.Sticky = true
+	alloc.Job.TaskGroups[0].EphemeralDisk.Migrate = true
+	alloc.DesiredTransition.Migrate = helper.BoolToPtr(true)
+
+	tr, _, cleanup := testCheckpointTaskRunner(t, alloc)
+	defer cleanup()
+	require.True(t, tr.shouldCheckpoint())
+
+	// Not checkpointed without the driver capability
+	tr.driverCapabilities = &drivers.Capabilities{}
+	require.False(t, tr.shouldCheckpoint())
+	tr.driverCapabilities = &drivers.Capabilities{Checkpoint: true}
+
+	// Not checkpointed if the alloc isn't migrated
+	noMigrate := alloc.Copy()
+	noMigrate.DesiredTransition.Migrate = nil
+	tr.setAlloc(noMigrate, tr.Task())
+	require.False(t, tr.shouldCheckpoint())
+
+	// Not checkpointed if the ephemeral disk isn't migrated
+	noDisk := alloc.Copy()
+	noDisk.Job.TaskGroups[0].EphemeralDisk.Migrate = false
+	tr.setAlloc(noDisk, tr.Task())
+	require.False(t, tr.shouldCheckpoint())
+}
+
+func TestTaskRunner_Checkpoint_Restore(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	tr, driver, cleanup := testCheckpointTaskRunner(t, alloc)
+	defer cleanup()
+
+	// Checkpointing writes to the checkpoint dir of the task
+	handle := NewDriverHandle(driver, "task-id", tr.Task(), 0, nil)
+	tr.checkpointTask(handle)
+	require.Equal(t, []string{"task-id"}, driver.checkpointed)
+	require.DirExists(t, tr.checkpointDir())
+
+	// The checkpoint is restored once and removed
+	_, _, err := tr.startTask(tr.buildTaskConfig())
+	require.NoError(t, err)
+	require.Equal(t, []string{tr.checkpointDir()}, driver.restored)
+	require.Zero(t, driver.started)
+	require.NoDirExists(t, tr.checkpointDir())
+
+	events := tr.TaskState().Events
+	require.Equal(t, structs.TaskCheckpointed, events[len(events)-2].Type)
+	require.Equal(t, structs.TaskRestoredCheckpoint, events[len(events)-1].Type)
+
+	// Without a checkpoint the task is started
+	_, _, err = tr.startTask(tr.buildTaskConfig())
+	require.NoError(t, err)
+	require.Len(t, driver.restored, 1)
+	require.Equal(t, 1, driver.started)
+
+	// The task is started if the checkpoint can't be restored
+	require.NoError(t, os.MkdirAll(tr.checkpointDir(), 0700))
+	driver.restoreErr = fmt.Errorf("criu failed")
+	_, _, err = tr.startTask(tr.buildTaskConfig())
+	require.NoError(t, err)
+	require.Equal(t, 2, driver.started)
+	require.NoDirExists(t, tr.checkpointDir())
+}
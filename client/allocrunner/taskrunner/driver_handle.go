@@ -63,13 +63,15 @@ func (h *DriverHandle) Signal(s string) error {
 }
 
 // Exec is the handled used by client endpoint handler to invoke the appropriate task driver exec.
+// The returned output combines the stdout and stderr of the command.
 func (h *DriverHandle) Exec(timeout time.Duration, cmd string, args []string) ([]byte, int, error) {
 	command := append([]string{cmd}, args...)
 	res, err := h.driver.ExecTask(h.taskID, command, timeout)
 	if err != nil {
 		return nil, 0, err
 	}
-	return res.Stdout, res.ExitResult.ExitCode, res.ExitResult.Err
+	out := append(res.Stdout, res.Stderr...)
+	return out, res.ExitResult.ExitCode, res.ExitResult.Err
 }
 
 // ExecStreaming is the handled used by client endpoint handler to invoke the appropriate task driver exec.
@@ -0,0 +1,156 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/envprovider"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// envProviderHook runs the environment providers of the client before the
+// task starts and sets the environment variables and files they return.
+type envProviderHook struct {
+	alloc     *structs.Allocation
+	providers *envprovider.Manager
+	logger    log.Logger
+}
+
+func newEnvProviderHook(alloc *structs.Allocation, providers *envprovider.Manager, logger log.Logger) *envProviderHook {
+	h := &envProviderHook{
+		alloc:     alloc,
+		providers: providers,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*envProviderHook) Name() string {
+	return "env_providers"
+}
+
+func (h *envProviderHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	// The providers are run on every start of the task so that short-lived
+	// credentials are refreshed on restarts, subject to their caching.
+	results, err := h.providers.Run(ctx, &envprovider.Request{
+		Region:    h.alloc.Job.Region,
+		Namespace: h.alloc.Namespace,
+		JobID:     h.alloc.JobID,
+		TaskGroup: h.alloc.TaskGroup,
+		Task:      req.Task.Name,
+		AllocID:   h.alloc.ID,
+		NodeID:    h.alloc.NodeID,
+		Driver:    req.Task.Driver,
+		Meta:      h.alloc.Job.CombinedTaskMeta(h.alloc.TaskGroup, req.Task.Name),
+	})
+	if err != nil {
+		return structs.NewRecoverableError(err, true)
+	}
+
+	env := make(map[string]string)
+	for _, result := range results {
+		for k, v := range result.Env {
+			env[k] = v
+		}
+		for _, f := range result.Files {
+			if err := writeEnvProviderFile(req.TaskDir.Dir, req.Task.User, f); err != nil {
+				return fmt.Errorf("env provider %q: %v", result.Provider, err)
+			}
+		}
+	}
+
+	resp.Env = env
+	return nil
+}
+
+// writeEnvProviderFile writes a file returned by a provider into the task
+// directory. The task can replace the contents of its task directory with
+// symlinks, so none are followed: the file is written to a temporary file
+// which is then renamed over the target. The file is owned by the task user
+// so that restrictive permissions don't make it unreadable to the task.
+func writeEnvProviderFile(taskDir, taskUser string, f *envprovider.File) error {
+	path := filepath.Join(taskDir, f.Path)
+	if filepath.IsAbs(f.Path) || helper.PathEscapesSandbox(taskDir, path) {
+		return fmt.Errorf("file path %q escapes the task directory", f.Path)
+	}
+
+	perms := os.FileMode(0644)
+	if f.Perms != "" {
+		p, err := strconv.ParseUint(f.Perms, 8, 12)
+		if err != nil {
+			return fmt.Errorf("invalid permissions %q for file %q", f.Perms, f.Path)
+		}
+		perms = os.FileMode(p)
+	}
+
+	dir := filepath.Dir(path)
+	if err := mkdirNoSymlinks(taskDir, dir); err != nil {
+		return fmt.Errorf("failed to create directory for file %q: %v", f.Path, err)
+	}
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("failed to write file %q: file is a symlink", f.Path)
+	}
+
+	tmp, err := ioutil.TempFile(dir, ".env-provider-")
+	if err != nil {
+		return fmt.Errorf("failed to write file %q: %v", f.Path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.WriteString(f.Data)
+	if err == nil {
+		err = tmp.Chmod(perms)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = setTaskFileOwner(tmp.Name(), taskUser)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write file %q: %v", f.Path, err)
+	}
+	return nil
+}
+
+// mkdirNoSymlinks creates the directory dir and its parents below base,
+// failing if any of them is a symlink.
+func mkdirNoSymlinks(base, dir string) error {
+	rel, err := filepath.Rel(base, dir)
+	if err != nil {
+		return err
+	}
+	if rel == "." {
+		return nil
+	}
+
+	path := base
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		path = filepath.Join(path, part)
+		fi, err := os.Lstat(path)
+		switch {
+		case os.IsNotExist(err):
+			if err := os.Mkdir(path, 0755); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case fi.Mode()&os.ModeSymlink != 0:
+			return fmt.Errorf("%q is a symlink", path)
+		case !fi.IsDir():
+			return fmt.Errorf("%q is not a directory", path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/envprovider"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the env provider hook implements the expected interface
+var _ interfaces.TaskPrestartHook = (*envProviderHook)(nil)
+
+func testEnvProviderHook(t *testing.T, output string) *envProviderHook {
+	if runtime.GOOS == "windows" {
+		t.Skip("env provider tests use shell scripts")
+	}
+
+	path := filepath.Join(t.TempDir(), "provider")
+	script := "#!/bin/sh\ncat <<'EOF'\n" + output + "\nEOF\n"
+	require.NoError(t, ioutil.WriteFile(path, []byte(script), 0755))
+
+	providers := envprovider.NewManager(testlog.HCLogger(t), []*config.EnvProviderConfig{{
+		Name:    "provider",
+		Command: path,
+		Timeout: 5 * time.Second,
+	}})
+	return newEnvProviderHook(mock.Alloc(), providers, testlog.HCLogger(t))
+}
+
+func TestTaskRunner_EnvProviderHook(t *testing.T) {
+	ci.Parallel(t)
+
+	h := testEnvProviderHook(t, `{"Env":{"TOKEN":"s3cr3t"},"Files":[
+		{"Path":"secrets/token","Data":"s3cr3t","Perms":"0600"}]}`)
+
+	taskDir := t.TempDir()
+	req := &interfaces.TaskPrestartRequest{
+		Task:    h.alloc.Job.TaskGroups[0].Tasks[0],
+		TaskDir: &allocdir.TaskDir{Dir: taskDir},
+	}
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+
+	require.Equal(t, map[string]string{"TOKEN": "s3cr3t"}, resp.Env)
+	require.False(t, resp.Done)
+
+	path := filepath.Join(taskDir, "secrets", "token")
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", string(b))
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+}
+
+func TestTaskRunner_EnvProviderHook_FileEscapes(t *testing.T) {
+	ci.Parallel(t)
+
+	h := testEnvProviderHook(t, `{"Files":[{"Path":"../escape","Data":"x"}]}`)
+
+	taskDir := t.TempDir()
+	req := &interfaces.TaskPrestartRequest{
+		Task:    h.alloc.Job.TaskGroups[0].Tasks[0],
+		TaskDir: &allocdir.TaskDir{Dir: taskDir},
+	}
+	var resp interfaces.TaskPrestartResponse
+	err := h.Prestart(context.Background(), req, &resp)
+	require.ErrorContains(t, err, "escapes the task directory")
+	require.NoFileExists(t, filepath.Join(filepath.Dir(taskDir), "escape"))
+}
+
+func TestTaskRunner_EnvProviderHook_Symlinks(t *testing.T) {
+	ci.Parallel(t)
+
+	h := testEnvProviderHook(t, `{"Files":[{"Path":"secrets/token","Data":"s3cr3t","Perms":"0600"}]}`)
+
+	outside := t.TempDir()
+	target := filepath.Join(outside, "target")
+	require.NoError(t, ioutil.WriteFile(target, []byte("original"), 0644))
+
+	req := &interfaces.TaskPrestartRequest{
+		Task: h.alloc.Job.TaskGroups[0].Tasks[0],
+	}
+	var resp interfaces.TaskPrestartResponse
+
+	// A symlink planted in place of the file isn't followed
+	taskDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(taskDir, "secrets"), 0755))
+	require.NoError(t, os.Symlink(target, filepath.Join(taskDir, "secrets", "token")))
+	req.TaskDir = &allocdir.TaskDir{Dir: taskDir}
+	err := h.Prestart(context.Background(), req, &resp)
+	require.ErrorContains(t, err, "is a symlink")
+
+	// Nor is a symlink planted in place of a directory
+	taskDir = t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(taskDir, "secrets")))
+	req.TaskDir = &allocdir.TaskDir{Dir: taskDir}
+	err = h.Prestart(context.Background(), req, &resp)
+	require.ErrorContains(t, err, "is a symlink")
+
+	b, err := ioutil.ReadFile(target)
+	require.NoError(t, err)
+	require.Equal(t, "original", string(b))
+	fi, err := os.Stat(target)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), fi.Mode().Perm())
+	require.NoFileExists(t, filepath.Join(outside, "token"))
+}
+
+func TestTaskRunner_EnvProviderHook_Failure(t *testing.T) {
+	ci.Parallel(t)
+
+	h := testEnvProviderHook(t, `not json`)
+
+	req := &interfaces.TaskPrestartRequest{
+		Task:    h.alloc.Job.TaskGroups[0].Tasks[0],
+		TaskDir: &allocdir.TaskDir{Dir: t.TempDir()},
+	}
+	var resp interfaces.TaskPrestartResponse
+	err := h.Prestart(context.Background(), req, &resp)
+	require.ErrorContains(t, err, `env provider "provider" failed`)
+
+	// Provider failures are retried
+	rerr, ok := err.(interface{ IsRecoverable() bool })
+	require.True(t, ok)
+	require.True(t, rerr.IsRecoverable())
+}
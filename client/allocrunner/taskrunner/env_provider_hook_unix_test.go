@@ -0,0 +1,43 @@
+//go:build !windows
+// +build !windows
+
+package taskrunner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRunner_EnvProviderHook_FileOwner(t *testing.T) {
+	ci.Parallel(t)
+	if syscall.Geteuid() != 0 {
+		t.Skip("must be run as root to change file owners")
+	}
+
+	h := testEnvProviderHook(t, `{"Files":[{"Path":"secrets/token","Data":"s3cr3t","Perms":"0600"}]}`)
+
+	taskDir := t.TempDir()
+	task := h.alloc.Job.TaskGroups[0].Tasks[0].Copy()
+	task.User = "1234:5678"
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: &allocdir.TaskDir{Dir: taskDir},
+	}
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+
+	// The file is only readable by the task user
+	fi, err := os.Stat(filepath.Join(taskDir, "secrets", "token"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	require.Equal(t, uint32(1234), fi.Sys().(*syscall.Stat_t).Uid)
+	require.Equal(t, uint32(5678), fi.Sys().(*syscall.Stat_t).Gid)
+}
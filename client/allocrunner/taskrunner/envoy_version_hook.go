@@ -68,11 +68,24 @@ func (h *envoyVersionHook) Prestart(_ context.Context, request *ifs.TaskPrestart
 	// but could be a no-op or some other value if so configured.
 	h.interpolateImage(request.Task, request.TaskEnv)
 
+	// Lookup the envoy_version constraint of the sidecar task, if any.
+	constraint, err := h.versionConstraint(request.Task)
+	if err != nil {
+		return err
+	}
+
 	// Detect whether this hook needs to run and return early if not. Only run if:
 	// - task uses docker driver
 	// - task is a connect sidecar or gateway
 	// - task image needs ${NOMAD_envoy_version} resolved
 	if h.skip(request) {
+		// An image pinned to a specific version must still satisfy the
+		// envoy_version constraint.
+		if constraint != nil && request.Task.Driver == "docker" {
+			if err := checkImageVersion(h.taskImage(request.Task.Config), constraint); err != nil {
+				return err
+			}
+		}
 		response.Done = true
 		return nil
 	}
@@ -87,7 +100,7 @@ func (h *envoyVersionHook) Prestart(_ context.Context, request *ifs.TaskPrestart
 	// Second [pseudo] interpolation of task image. This determines the concrete
 	// Envoy image identifier by applying version string substitution of
 	// ${NOMAD_envoy_version} acquired from Consul.
-	image, err := h.tweakImage(h.taskImage(request.Task.Config), proxies)
+	image, err := h.tweakImage(h.taskImage(request.Task.Config), proxies, constraint)
 	if err != nil {
 		return fmt.Errorf("error interpreting desired Envoy version from Consul: %w", err)
 	}
@@ -162,15 +175,68 @@ func (h *envoyVersionHook) needsVersion(config map[string]interface{}) bool {
 	return strings.Contains(image, envoy.VersionVar)
 }
 
+// versionConstraint returns the envoy_version constraint set in the
+// sidecar_task block of the service the task is the sidecar of, if any.
+func (h *envoyVersionHook) versionConstraint(task *structs.Task) (version.Constraints, error) {
+	if h.alloc == nil || h.alloc.Job == nil || !task.UsesConnectSidecar() {
+		return nil, nil
+	}
+
+	tg := h.alloc.Job.LookupTaskGroup(h.alloc.TaskGroup)
+	if tg == nil {
+		return nil, nil
+	}
+
+	for _, service := range tg.Services {
+		if service.Name != task.Kind.Value() || !service.Connect.HasSidecar() {
+			continue
+		}
+		sidecar := service.Connect.SidecarTask
+		if sidecar == nil || sidecar.EnvoyVersion == "" {
+			return nil, nil
+		}
+		constraint, err := version.NewConstraint(sidecar.EnvoyVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid envoy_version constraint %q: %w", sidecar.EnvoyVersion, err)
+		}
+		return constraint, nil
+	}
+	return nil, nil
+}
+
 // tweakImage determines the best Envoy version to use. If supported is nil or empty
-// Nomad will fallback to the legacy envoy image used before Nomad v1.0.
-func (h *envoyVersionHook) tweakImage(configured string, supported map[string][]string) (string, error) {
+// Nomad will fallback to the legacy envoy image used before Nomad v1.0. If a
+// constraint is given, the newest supported version satisfying it is used.
+func (h *envoyVersionHook) tweakImage(configured string, supported map[string][]string, constraint version.Constraints) (string, error) {
 	versions := supported["envoy"]
 	if len(versions) == 0 {
+		if err := checkImageVersion(envoy.FallbackImage, constraint); err != nil {
+			return "", err
+		}
 		return envoy.FallbackImage, nil
 	}
 
-	latest, err := semver(versions[0])
+	// Consul lists the supported versions from newest to oldest.
+	chosen := versions[0]
+	if constraint != nil {
+		chosen = ""
+		for _, v := range versions {
+			sv, err := version.NewVersion(v)
+			if err != nil {
+				return "", fmt.Errorf("unexpected envoy version format: %w", err)
+			}
+			if constraint.Check(sv) {
+				chosen = v
+				break
+			}
+		}
+		if chosen == "" {
+			return "", fmt.Errorf("no Envoy version supported by Consul (%s) satisfies constraint %q",
+				strings.Join(versions, ", "), constraint)
+		}
+	}
+
+	latest, err := semver(chosen)
 	if err != nil {
 		return "", err
 	}
@@ -178,6 +244,32 @@ func (h *envoyVersionHook) tweakImage(configured string, supported map[string][]
 	return strings.ReplaceAll(configured, envoy.VersionVar, latest), nil
 }
 
+// checkImageVersion returns an error if the version in the tag of the given
+// image does not satisfy the constraint.
+func checkImageVersion(image string, constraint version.Constraints) error {
+	if constraint == nil {
+		return nil
+	}
+
+	// Strip the digest, then take the tag following the repository.
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+
+	v, err := version.NewVersion(strings.TrimPrefix(tag, "v"))
+	if err != nil {
+		return fmt.Errorf("unable to determine Envoy version of image %q to check constraint %q", image, constraint)
+	}
+	if !constraint.Check(v) {
+		return fmt.Errorf("Envoy version %s of image %q does not satisfy constraint %q", v, image, constraint)
+	}
+	return nil
+}
+
 // semver sanitizes the envoy version string coming from Consul into the format
 // used by the Envoy project when publishing images (i.e. proper semver). This
 // resulting string value does NOT contain the 'v' prefix for 2 reasons:
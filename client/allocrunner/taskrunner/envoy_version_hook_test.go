@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/allocdir"
 	ifs "github.com/hashicorp/nomad/client/allocrunner/interfaces"
@@ -76,7 +77,7 @@ func TestEnvoyVersionHook_tweakImage(t *testing.T) {
 	image := envoy.ImageFormat
 
 	t.Run("legacy", func(t *testing.T) {
-		result, err := (*envoyVersionHook)(nil).tweakImage(image, nil)
+		result, err := (*envoyVersionHook)(nil).tweakImage(image, nil, nil)
 		require.NoError(t, err)
 		require.Equal(t, envoy.FallbackImage, result)
 	})
@@ -84,14 +85,14 @@ func TestEnvoyVersionHook_tweakImage(t *testing.T) {
 	t.Run("unexpected", func(t *testing.T) {
 		_, err := (*envoyVersionHook)(nil).tweakImage(image, map[string][]string{
 			"envoy": {"foo", "bar", "baz"},
-		})
+		}, nil)
 		require.EqualError(t, err, "unexpected envoy version format: Malformed version: foo")
 	})
 
 	t.Run("standard envoy", func(t *testing.T) {
 		result, err := (*envoyVersionHook)(nil).tweakImage(image, map[string][]string{
 			"envoy": {"1.15.0", "1.14.4", "1.13.4", "1.12.6"},
-		})
+		}, nil)
 		require.NoError(t, err)
 		require.Equal(t, "envoyproxy/envoy:v1.15.0", result)
 	})
@@ -100,10 +101,44 @@ func TestEnvoyVersionHook_tweakImage(t *testing.T) {
 		custom := "custom-${NOMAD_envoy_version}/envoy:${NOMAD_envoy_version}"
 		result, err := (*envoyVersionHook)(nil).tweakImage(custom, map[string][]string{
 			"envoy": {"1.15.0", "1.14.4", "1.13.4", "1.12.6"},
-		})
+		}, nil)
 		require.NoError(t, err)
 		require.Equal(t, "custom-1.15.0/envoy:1.15.0", result)
 	})
+
+	t.Run("constraint", func(t *testing.T) {
+		constraint, err := version.NewConstraint("< 1.15, >= 1.13")
+		require.NoError(t, err)
+		result, err := (*envoyVersionHook)(nil).tweakImage(image, map[string][]string{
+			"envoy": {"1.15.0", "1.14.4", "1.13.4", "1.12.6"},
+		}, constraint)
+		require.NoError(t, err)
+		require.Equal(t, "envoyproxy/envoy:v1.14.4", result)
+	})
+
+	t.Run("constraint unsatisfied", func(t *testing.T) {
+		constraint, err := version.NewConstraint(">= 1.16")
+		require.NoError(t, err)
+		_, err = (*envoyVersionHook)(nil).tweakImage(image, map[string][]string{
+			"envoy": {"1.15.0", "1.14.4"},
+		}, constraint)
+		require.EqualError(t, err, `no Envoy version supported by Consul (1.15.0, 1.14.4) satisfies constraint ">= 1.16"`)
+	})
+}
+
+func TestEnvoyVersionHook_checkImageVersion(t *testing.T) {
+	ci.Parallel(t)
+
+	constraint, err := version.NewConstraint(">= 1.14")
+	require.NoError(t, err)
+
+	require.NoError(t, checkImageVersion("envoyproxy/envoy:v1.15.0", nil))
+	require.NoError(t, checkImageVersion("envoyproxy/envoy:v1.15.0", constraint))
+	require.NoError(t, checkImageVersion("registry:5000/envoy:1.14.1@sha256:abc", constraint))
+	require.EqualError(t, checkImageVersion(envoy.FallbackImage, constraint),
+		`Envoy version 1.11.2 of image "envoyproxy/envoy:v1.11.2" does not satisfy constraint ">= 1.14"`)
+	require.EqualError(t, checkImageVersion("registry:5000/envoy", constraint),
+		`unable to determine Envoy version of image "registry:5000/envoy" to check constraint ">= 1.14"`)
 }
 
 func TestEnvoyVersionHook_interpolateImage(t *testing.T) {
@@ -264,6 +299,54 @@ func TestTaskRunner_EnvoyVersionHook_Prestart_standard(t *testing.T) {
 	require.Equal(t, "envoyproxy/envoy:v1.15.0", request.Task.Config["image"])
 }
 
+func TestTaskRunner_EnvoyVersionHook_Prestart_envoyVersion(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+
+	// Setup an Allocation with an envoy_version constraint
+	alloc := mock.ConnectAlloc()
+	alloc.Job.TaskGroups[0].Tasks[0] = mock.ConnectSidecarTask()
+	alloc.Job.TaskGroups[0].Services[0].Name = "mysidecar"
+	alloc.Job.TaskGroups[0].Services[0].Connect.SidecarTask = &structs.SidecarTask{
+		EnvoyVersion: "< 1.15",
+	}
+	allocDir, cleanupDir := allocdir.TestAllocDir(t, logger, "EnvoyVersionHook", alloc.ID)
+	defer cleanupDir()
+
+	// Setup a mock for Consul API
+	spAPI := consul.MockSupportedProxiesAPI{
+		Value: map[string][]string{
+			"envoy": {"1.15.0", "1.14.4"},
+		},
+		Error: nil,
+	}
+
+	// Run envoy_version hook
+	h := newEnvoyVersionHook(newEnvoyVersionHookConfig(alloc, spAPI, logger))
+
+	// Create a prestart request
+	request := &ifs.TaskPrestartRequest{
+		Task:    alloc.Job.TaskGroups[0].Tasks[0],
+		TaskDir: allocDir.NewTaskDir(alloc.Job.TaskGroups[0].Tasks[0].Name),
+		TaskEnv: taskEnvDefault,
+	}
+	require.NoError(t, request.TaskDir.Build(false, nil))
+
+	// Run the hook and assert the newest version satisfying the constraint
+	// is used
+	var response ifs.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), request, &response))
+	require.True(t, response.Done)
+	require.Equal(t, "envoyproxy/envoy:v1.14.4", request.Task.Config["image"])
+
+	// A pinned image must satisfy the constraint as well
+	request.Task.Config["image"] = "envoyproxy/envoy:v1.15.0"
+	response = ifs.TaskPrestartResponse{}
+	require.EqualError(t, h.Prestart(context.Background(), request, &response),
+		`Envoy version 1.15.0 of image "envoyproxy/envoy:v1.15.0" does not satisfy constraint "< 1.15"`)
+}
+
 func TestTaskRunner_EnvoyVersionHook_Prestart_custom(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,211 @@
+package getter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gg "github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/client/config"
+)
+
+const (
+	// maxFetcherStderrLen is the maximum length of the stderr output of a
+	// fetcher included in errors.
+	maxFetcherStderrLen = 1024
+)
+
+// FetchRequest is the artifact information passed to a fetcher on stdin.
+//
+// An artifact fetcher is a binary configured on the client that downloads
+// the artifacts with a URL scheme go-getter doesn't support. The fetcher must
+// write the artifact to Dest and exit with a zero status. It is run with only
+// the environment configured for it and in an empty staging directory, and
+// the artifact is only moved into the task directory once the fetcher
+// succeeded. Fetchers are trusted like the client: unless a user is
+// configured, they run as the client user with its filesystem and network
+// access.
+type FetchRequest struct {
+	// URL is the URL of the artifact, including its options.
+	URL string
+
+	// Dir is true if the artifact is a directory, and false if it is a
+	// single file.
+	Dir bool
+
+	// Dest is the path the artifact must be written to. It is a directory
+	// if Dir is true, and a file otherwise.
+	Dest string
+
+	// Headers are the headers of the artifact.
+	Headers http.Header
+
+	// Config is the configuration of the fetcher on the client.
+	Config map[string]string
+}
+
+// fetcherGetter is a go-getter Getter running an artifact fetcher, so that
+// checksums and archives are handled for fetched artifacts like for any
+// other artifact.
+type fetcherGetter struct {
+	config  *config.ArtifactFetcherConfig
+	headers http.Header
+	client  *gg.Client
+}
+
+func (f *fetcherGetter) ClientMode(*url.URL) (gg.ClientMode, error) {
+	return gg.ClientModeFile, nil
+}
+
+func (f *fetcherGetter) SetClient(c *gg.Client) {
+	f.client = c
+}
+
+func (f *fetcherGetter) Get(dst string, u *url.URL) error {
+	return f.fetch(dst, u, true)
+}
+
+func (f *fetcherGetter) GetFile(dst string, u *url.URL) error {
+	return f.fetch(dst, u, false)
+}
+
+// fetch runs the fetcher to download the artifact into a staging directory
+// next to dst, then moves it to dst. Only regular files and directories are
+// moved, so the fetcher can't use symlinks to write outside of the task
+// directory.
+func (f *fetcherGetter) fetch(dst string, u *url.URL, dir bool) error {
+	ctx := context.Background()
+	if f.client != nil && f.client.Ctx != nil {
+		ctx = f.client.Ctx
+	}
+	ctx, cancel := context.WithTimeout(ctx, f.config.Timeout)
+	defer cancel()
+
+	parent := filepath.Dir(dst)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	staging, err := os.MkdirTemp(parent, ".fetch-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(staging)
+
+	req := &FetchRequest{
+		URL:     u.String(),
+		Dir:     dir,
+		Dest:    filepath.Join(staging, "artifact"),
+		Headers: f.headers,
+		Config:  f.config.Config,
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, f.config.Command, f.config.Args...)
+	cmd.Dir = staging
+	cmd.Env = fetcherEnv(f.config.Env)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = &stderr
+	if f.config.User != "" {
+		if err := setFetcherUser(cmd, staging, f.config.User); err != nil {
+			return fmt.Errorf("artifact fetcher %q: %v", f.config.Scheme, err)
+		}
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("artifact fetcher %q timed out after %s", f.config.Scheme, f.config.Timeout)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxFetcherStderrLen {
+			msg = msg[:maxFetcherStderrLen]
+		}
+		if msg != "" {
+			return fmt.Errorf("artifact fetcher %q failed: %v: %s", f.config.Scheme, err, msg)
+		}
+		return fmt.Errorf("artifact fetcher %q failed: %v", f.config.Scheme, err)
+	}
+
+	if err := moveFetched(req.Dest, dst, dir); err != nil {
+		return fmt.Errorf("artifact fetcher %q: %v", f.config.Scheme, err)
+	}
+	return nil
+}
+
+// fetcherEnv returns the environment of a fetcher, sorted for consistency.
+func fetcherEnv(env map[string]string) []string {
+	out := make([]string, 0, len(env))
+	for k, v := range env {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// moveFetched moves the artifact written by a fetcher from src to dst. If dir
+// is true, src must be a directory whose content is merged into dst.
+// Otherwise src must be a regular file.
+func moveFetched(src, dst string, dir bool) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return fmt.Errorf("artifact not written: %v", err)
+	}
+
+	if !dir {
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("artifact is not a regular file")
+		}
+		return os.Rename(src, dst)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("artifact is not a directory")
+	}
+
+	// Check the whole tree before moving anything so that a rejected
+	// artifact doesn't leave partial content in the task directory.
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			rel, _ := filepath.Rel(src, path)
+			return fmt.Errorf("artifact contains %q which is not a regular file", rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			// Never write through a symlink in the task directory
+			if info, err := os.Lstat(target); err == nil && !info.IsDir() {
+				return fmt.Errorf("destination %q is not a directory", rel)
+			}
+			return os.MkdirAll(target, 0755)
+		}
+		return os.Rename(path, target)
+	})
+}
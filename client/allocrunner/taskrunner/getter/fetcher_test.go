@@ -0,0 +1,188 @@
+package getter
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	clientconfig "github.com/hashicorp/nomad/client/config"
+	ctestutil "github.com/hashicorp/nomad/client/testutil"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// destFromRequest is a shell snippet setting $dest to the Dest of the request
+// read from stdin into $input.
+const destFromRequest = `input=$(cat)
+dest=$(echo "$input" | sed 's/.*"Dest":"\([^"]*\)".*/\1/')
+`
+
+// testFetcherGetter writes a shell script fetcher for the "store" scheme and
+// returns a Getter using it.
+func testFetcherGetter(t *testing.T, script string) *Getter {
+	if runtime.GOOS == "windows" {
+		t.Skip("artifact fetcher tests use shell scripts")
+	}
+
+	path := filepath.Join(t.TempDir(), "fetcher")
+	require.NoError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"+destFromRequest+script), 0755))
+
+	getter := TestDefaultGetter(t)
+	getter.config.Fetchers = []*clientconfig.ArtifactFetcherConfig{{
+		Scheme:  "store",
+		Command: path,
+		Env:     map[string]string{"PATH": "/usr/bin:/bin", "FOO": "bar"},
+		Config:  map[string]string{"endpoint": "https://store.internal"},
+		Timeout: 5 * time.Second,
+	}}
+	return getter
+}
+
+func TestGetArtifact_Fetcher(t *testing.T) {
+	ci.Parallel(t)
+
+	// The fetcher writes its request as the artifact
+	getter := testFetcherGetter(t, `echo "$input" > "$dest"`)
+
+	taskDir := t.TempDir()
+	artifact := &structs.TaskArtifact{
+		GetterSource:  "store://bucket/request.json",
+		GetterHeaders: map[string]string{"X-Token": "secret"},
+		RelativeDest:  "local/",
+	}
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil))
+
+	data, err := ioutil.ReadFile(filepath.Join(taskDir, "local", "request.json"))
+	require.NoError(t, err)
+
+	var req FetchRequest
+	require.NoError(t, json.Unmarshal(data, &req))
+	require.Equal(t, "store://bucket/request.json", req.URL)
+	require.False(t, req.Dir)
+	require.Equal(t, "secret", req.Headers.Get("X-Token"))
+	require.Equal(t, map[string]string{"endpoint": "https://store.internal"}, req.Config)
+
+	// The staging directory is removed
+	entries, err := ioutil.ReadDir(filepath.Join(taskDir, "local"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestGetArtifact_Fetcher_EnvAndChecksum(t *testing.T) {
+	ci.Parallel(t)
+
+	// The fetcher is only given its configured environment
+	getter := testFetcherGetter(t, `echo "$FOO:${HOME:-unset}" > "$dest"`)
+
+	taskDir := t.TempDir()
+	artifact := &structs.TaskArtifact{
+		GetterSource: "store://bucket/env.txt",
+		GetterOptions: map[string]string{
+			// md5 of "bar:unset\n"
+			"checksum": "md5:42f884fc284037a6c7c6ded7e7a200f9",
+		},
+		RelativeDest: "local/",
+	}
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil))
+
+	data, err := ioutil.ReadFile(filepath.Join(taskDir, "local", "env.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "bar:unset\n", string(data))
+}
+
+func TestGetArtifact_Fetcher_Dir(t *testing.T) {
+	ci.Parallel(t)
+
+	getter := testFetcherGetter(t, `mkdir -p "$dest/sub" && echo a > "$dest/sub/a" && echo b > "$dest/b"`)
+
+	taskDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(taskDir, "local"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(taskDir, "local", "existing"), []byte("x"), 0644))
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: "store://bucket/dir",
+		GetterMode:   structs.GetterModeDir,
+		RelativeDest: "local/",
+	}
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil))
+
+	require.FileExists(t, filepath.Join(taskDir, "local", "sub", "a"))
+	require.FileExists(t, filepath.Join(taskDir, "local", "b"))
+	require.FileExists(t, filepath.Join(taskDir, "local", "existing"))
+}
+
+func TestGetArtifact_Fetcher_Symlink(t *testing.T) {
+	ci.Parallel(t)
+
+	// Symlinks written by the fetcher are rejected
+	getter := testFetcherGetter(t, `mkdir -p "$dest" && echo b > "$dest/b" && ln -s /etc/passwd "$dest/passwd"`)
+
+	taskDir := t.TempDir()
+	artifact := &structs.TaskArtifact{
+		GetterSource: "store://bucket/dir",
+		GetterMode:   structs.GetterModeDir,
+		RelativeDest: "local/",
+	}
+	err := getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"passwd" which is not a regular file`)
+	require.NoFileExists(t, filepath.Join(taskDir, "local", "b"))
+}
+
+func TestGetArtifact_Fetcher_Error(t *testing.T) {
+	ci.Parallel(t)
+
+	getter := testFetcherGetter(t, `echo "access denied" >&2; exit 1`)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: "store://bucket/app.txt",
+		RelativeDest: "local/",
+	}
+	err := getter.GetArtifact(context.Background(), noopTaskEnv(t.TempDir()), artifact, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `artifact fetcher "store" failed`)
+	require.Contains(t, err.Error(), "access denied")
+
+	getErr, ok := err.(*GetError)
+	require.True(t, ok)
+	require.True(t, getErr.IsRecoverable())
+}
+
+func TestGetArtifact_Fetcher_User(t *testing.T) {
+	ci.Parallel(t)
+	ctestutil.RequireRoot(t)
+
+	nobody, err := user.Lookup("nobody")
+	require.NoError(t, err)
+
+	// The fetcher writes the id of the user it runs as
+	getter := testFetcherGetter(t, `id -u > "$dest"`)
+	getter.config.Fetchers[0].User = "nobody"
+
+	// The fetcher and the task directory must be accessible by the user
+	taskDir := t.TempDir()
+	for _, dir := range []string{
+		filepath.Dir(getter.config.Fetchers[0].Command),
+		taskDir,
+	} {
+		require.NoError(t, os.Chmod(dir, 0755))
+		require.NoError(t, os.Chmod(filepath.Dir(dir), 0755))
+	}
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: "store://bucket/uid.txt",
+		RelativeDest: "local/",
+	}
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil))
+
+	data, err := ioutil.ReadFile(filepath.Join(taskDir, "local", "uid.txt"))
+	require.NoError(t, err)
+	require.Equal(t, nobody.Uid+"\n", string(data))
+}
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package getter
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// setFetcherUser makes cmd run as the given user and gives the user
+// ownership of the staging directory the fetcher writes the artifact to.
+func setFetcherUser(cmd *exec.Cmd, staging, username string) error {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return fmt.Errorf("failed to identify user %q: %v", username, err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return fmt.Errorf("unable to convert uid of user %q: %v", username, err)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return fmt.Errorf("unable to convert gid of user %q: %v", username, err)
+	}
+
+	if err := os.Chown(staging, int(uid), int(gid)); err != nil {
+		return fmt.Errorf("failed to change owner of staging directory: %v", err)
+	}
+
+	// Supplementary groups are cleared so that the fetcher doesn't keep the
+	// groups of the client
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: &syscall.Credential{
+			Uid: uint32(uid),
+			Gid: uint32(gid),
+		},
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package getter
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// setFetcherUser is not supported on Windows, where fetchers run as the
+// client user.
+func setFetcherUser(*exec.Cmd, string, string) error {
+	return errors.New("running artifact fetchers as another user is not supported on Windows")
+}
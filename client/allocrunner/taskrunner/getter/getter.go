@@ -1,11 +1,14 @@
 package getter
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
 	gg "github.com/hashicorp/go-getter"
@@ -13,11 +16,17 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/nomad/structs"
+	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
+	"golang.org/x/time/rate"
 )
 
 const (
 	// gitSSHPrefix is the prefix for downloading via git using ssh
 	gitSSHPrefix = "git@github.com:"
+
+	// progressInterval is the interval at which the progress of a download
+	// is reported.
+	progressInterval = time.Minute
 )
 
 // Getter wraps go-getter calls in an artifact configuration.
@@ -28,21 +37,59 @@ type Getter struct {
 	// connections when clients are downloading lots of artifacts.
 	httpClient *http.Client
 	config     *config.ArtifactConfig
+
+	// limiter caps the bandwidth used by all downloads of the client. It
+	// is nil if the bandwidth is unlimited.
+	limiter *rate.Limiter
+
+	// downloads is a semaphore limiting the number of concurrent downloads
+	// of the client. It is nil if the concurrency is unlimited.
+	downloads chan struct{}
+
+	progressInterval time.Duration
 }
 
 // NewGetter returns a new Getter instance. This function is called once per
-// client and shared across alloc and task runners.
-func NewGetter(config *config.ArtifactConfig) *Getter {
-	return &Getter{
+// client and shared across alloc and task runners. Clients without an
+// artifact config use the default one.
+func NewGetter(conf *config.ArtifactConfig) *Getter {
+	if conf == nil {
+		// The default config is always valid
+		conf, _ = config.ArtifactConfigFromAgent(sconfig.DefaultArtifactConfig())
+	}
+
+	g := &Getter{
 		httpClient: &http.Client{
 			Transport: cleanhttp.DefaultPooledTransport(),
 		},
-		config: config,
+		config:           conf,
+		progressInterval: progressInterval,
+	}
+	if conf.BandwidthLimit > 0 {
+		g.limiter = newBandwidthLimiter(conf.BandwidthLimit)
+	}
+	if conf.MaxConcurrentDownloads > 0 {
+		g.downloads = make(chan struct{}, conf.MaxConcurrentDownloads)
+	}
+	return g
+}
+
+// newBandwidthLimiter returns a limiter allowing limit bytes per second. The
+// burst is bounded so that a single read never exceeds a second's worth of
+// bandwidth.
+func newBandwidthLimiter(limit int64) *rate.Limiter {
+	burst := 32 * 1024
+	if limit < int64(burst) {
+		burst = int(limit)
 	}
+	return rate.NewLimiter(rate.Limit(limit), burst)
 }
 
-// GetArtifact downloads an artifact into the specified task directory.
-func (g *Getter) GetArtifact(taskEnv interfaces.EnvReplacer, artifact *structs.TaskArtifact) error {
+// GetArtifact downloads an artifact into the specified task directory. The
+// progress func, if not nil, is called periodically during HTTP and S3
+// downloads. The download is aborted once ctx is done, including while it is
+// waiting for a download slot.
+func (g *Getter) GetArtifact(ctx context.Context, taskEnv interfaces.EnvReplacer, artifact *structs.TaskArtifact, progress interfaces.ArtifactProgressFunc) error {
 	ggURL, err := getGetterUrl(taskEnv, artifact)
 	if err != nil {
 		return newGetError(artifact.GetterSource, err, false)
@@ -65,8 +112,36 @@ func (g *Getter) GetArtifact(taskEnv interfaces.EnvReplacer, artifact *structs.T
 		mode = gg.ClientModeDir
 	}
 
+	// Only resume partial downloads of artifacts with a checksum, so that a
+	// file which changed since the previous attempt is detected.
+	resume := artifact.GetterOptions["checksum"] != ""
+
+	if g.downloads != nil {
+		select {
+		case g.downloads <- struct{}{}:
+			defer func() { <-g.downloads }()
+		case <-ctx.Done():
+			return newGetError(ggURL, ctx.Err(), true)
+		}
+	}
+
 	headers := getHeaders(taskEnv, artifact.GetterHeaders)
-	if err := g.getClient(ggURL, headers, mode, dest).Get(); err != nil {
+	client := g.getClient(ggURL, headers, mode, dest, resume)
+	client.Ctx = ctx
+	client.ProgressListener = &progressTracker{
+		ctx:      ctx,
+		limiter:  g.limiter,
+		maxBytes: g.config.HTTPMaxBytes,
+		interval: g.progressInterval,
+		progress: progress,
+	}
+	if err := client.Get(); err != nil {
+		// Remove a file that failed its checksum so the next attempt
+		// downloads it from the start instead of resuming a corrupt file.
+		var cerr *gg.ChecksumError
+		if errors.As(err, &cerr) && cerr.File != "" {
+			os.Remove(cerr.File)
+		}
 		return newGetError(ggURL, err, true)
 	}
 
@@ -74,20 +149,20 @@ func (g *Getter) GetArtifact(taskEnv interfaces.EnvReplacer, artifact *structs.T
 }
 
 // getClient returns a client that is suitable for Nomad downloading artifacts.
-func (g *Getter) getClient(src string, headers http.Header, mode gg.ClientMode, dst string) *gg.Client {
+func (g *Getter) getClient(src string, headers http.Header, mode gg.ClientMode, dst string, resume bool) *gg.Client {
 	return &gg.Client{
 		Src:     src,
 		Dst:     dst,
 		Mode:    mode,
 		Umask:   060000000,
-		Getters: g.createGetters(headers),
+		Getters: g.createGetters(headers, resume),
 
 		// This will prevent copying or writing files through symlinks
 		DisableSymlinks: true,
 	}
 }
 
-func (g *Getter) createGetters(header http.Header) map[string]gg.Getter {
+func (g *Getter) createGetters(header http.Header, resume bool) map[string]gg.Getter {
 	httpGetter := &gg.HttpGetter{
 		Netrc:  true,
 		Client: g.httpClient,
@@ -100,7 +175,11 @@ func (g *Getter) createGetters(header http.Header) map[string]gg.Getter {
 		// Disable HEAD requests as they can produce corrupt files when
 		// retrying a download of a resource that has changed.
 		// hashicorp/go-getter#219
-		DoNotCheckHeadFirst: true,
+		//
+		// The HEAD request is what allows go-getter to resume a partial
+		// download with a range request, so it is only made when the
+		// artifact has a checksum to verify the resumed file against.
+		DoNotCheckHeadFirst: !resume,
 
 		// Read timeout for HTTP operations. Must be long enough to
 		// accommodate large/slow downloads.
@@ -115,8 +194,9 @@ func (g *Getter) createGetters(header http.Header) map[string]gg.Getter {
 	// go-getter is not thread-safe. Use a shared HTTP client for http/https Getter,
 	// with pooled transport which is thread-safe.
 	//
-	// If a getter type is not listed here, it is not supported (e.g. file).
-	return map[string]gg.Getter{
+	// If a getter type is not listed here, it is not supported (e.g. file),
+	// unless an artifact fetcher is configured for it.
+	getters := map[string]gg.Getter{
 		"git": &gg.GitGetter{
 			Timeout: g.config.GitTimeout,
 		},
@@ -132,6 +212,15 @@ func (g *Getter) createGetters(header http.Header) map[string]gg.Getter {
 		"http":  httpGetter,
 		"https": httpGetter,
 	}
+
+	for _, f := range g.config.Fetchers {
+		getters[f.Scheme] = &fetcherGetter{
+			config:  f,
+			headers: header,
+		}
+	}
+
+	return getters
 }
 
 // getGetterUrl returns the go-getter URL to download the artifact.
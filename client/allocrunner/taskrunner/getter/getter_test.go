@@ -1,6 +1,9 @@
 package getter
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,6 +15,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -22,7 +26,9 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 )
 
 // noopReplacer is a noop version of taskenv.TaskEnv.ReplaceEnv.
@@ -80,7 +86,7 @@ func TestGetter_getClient(t *testing.T) {
 		HgTimeout:       3 * time.Minute,
 		S3Timeout:       4 * time.Minute,
 	})
-	client := getter.getClient("src", nil, gg.ClientModeAny, "dst")
+	client := getter.getClient("src", nil, gg.ClientModeAny, "dst", false)
 
 	t.Run("check symlink config", func(t *testing.T) {
 		require.True(t, client.DisableSymlinks)
@@ -90,6 +96,7 @@ func TestGetter_getClient(t *testing.T) {
 		require.True(t, client.Getters["http"].(*gg.HttpGetter).XTerraformGetDisabled)
 		require.Equal(t, time.Minute, client.Getters["http"].(*gg.HttpGetter).ReadTimeout)
 		require.Equal(t, int64(100_000), client.Getters["http"].(*gg.HttpGetter).MaxBytes)
+		require.True(t, client.Getters["http"].(*gg.HttpGetter).DoNotCheckHeadFirst)
 	})
 
 	t.Run("check https config", func(t *testing.T) {
@@ -172,7 +179,7 @@ func TestGetArtifact_Headers(t *testing.T) {
 		taskDir: taskDir,
 	}
 
-	err := getter.GetArtifact(taskEnv, artifact)
+	err := getter.GetArtifact(context.Background(), taskEnv, artifact, nil)
 	require.NoError(t, err)
 
 	// Verify artifact exists.
@@ -203,7 +210,7 @@ func TestGetArtifact_FileAndChecksum(t *testing.T) {
 
 	// Download the artifact
 	getter := TestDefaultGetter(t)
-	if err := getter.GetArtifact(noopTaskEnv(taskDir), artifact); err != nil {
+	if err := getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil); err != nil {
 		t.Fatalf("GetArtifact failed: %v", err)
 	}
 
@@ -213,6 +220,263 @@ func TestGetArtifact_FileAndChecksum(t *testing.T) {
 	}
 }
 
+// newRangeServer returns a test server serving content at /file and
+// supporting range requests. The range headers of the GET requests it
+// receives are sent on the returned channel.
+func newRangeServer(t *testing.T, content []byte) (*httptest.Server, chan string) {
+	ranges := make(chan string, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			ranges <- r.Header.Get("Range")
+		}
+		http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, ranges
+}
+
+func TestGetArtifact_ResumeWithChecksum(t *testing.T) {
+	content := bytes.Repeat([]byte("nomad"), 1000)
+	ts, ranges := newRangeServer(t, content)
+
+	// Leave a partial download in the destination
+	taskDir := t.TempDir()
+	dst := filepath.Join(taskDir, "local", "file")
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0755))
+	require.NoError(t, ioutil.WriteFile(dst, content[:2000], 0644))
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: ts.URL + "/file",
+		GetterOptions: map[string]string{
+			"checksum": fmt.Sprintf("md5:%x", md5.Sum(content)),
+		},
+		GetterMode:   structs.GetterModeFile,
+		RelativeDest: "local/file",
+	}
+
+	getter := TestDefaultGetter(t)
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil))
+
+	// Only the missing part of the file was requested
+	require.Equal(t, "bytes=2000-", <-ranges)
+
+	b, err := ioutil.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, content, b)
+}
+
+func TestGetArtifact_ResumeChecksumMismatch(t *testing.T) {
+	content := bytes.Repeat([]byte("nomad"), 1000)
+	ts, ranges := newRangeServer(t, content)
+
+	// Leave a partial download of a different file in the destination
+	taskDir := t.TempDir()
+	dst := filepath.Join(taskDir, "local", "file")
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0755))
+	require.NoError(t, ioutil.WriteFile(dst, bytes.Repeat([]byte("x"), 2000), 0644))
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: ts.URL + "/file",
+		GetterOptions: map[string]string{
+			"checksum": fmt.Sprintf("md5:%x", md5.Sum(content)),
+		},
+		GetterMode:   structs.GetterModeFile,
+		RelativeDest: "local/file",
+	}
+
+	// The resumed file fails the checksum and is removed
+	getter := TestDefaultGetter(t)
+	err := getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Checksums did not match")
+	require.Equal(t, "bytes=2000-", <-ranges)
+	require.NoFileExists(t, dst)
+
+	// So the next attempt downloads the whole file
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil))
+	require.Equal(t, "", <-ranges)
+
+	b, err := ioutil.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, content, b)
+}
+
+func TestGetArtifact_NoResumeWithoutChecksum(t *testing.T) {
+	content := bytes.Repeat([]byte("nomad"), 1000)
+	ts, ranges := newRangeServer(t, content)
+
+	taskDir := t.TempDir()
+	dst := filepath.Join(taskDir, "local", "file")
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0755))
+	require.NoError(t, ioutil.WriteFile(dst, content[:2000], 0644))
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: ts.URL + "/file",
+		GetterMode:   structs.GetterModeFile,
+		RelativeDest: "local/file",
+	}
+
+	getter := TestDefaultGetter(t)
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil))
+	require.Equal(t, "", <-ranges)
+
+	b, err := ioutil.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, content, b)
+}
+
+func TestGetArtifact_Progress(t *testing.T) {
+	content := bytes.Repeat([]byte("nomad"), 1000)
+	ts, _ := newRangeServer(t, content)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: ts.URL + "/file",
+		GetterMode:   structs.GetterModeFile,
+		RelativeDest: "local/file",
+	}
+
+	var downloaded, total int64
+	progress := func(d, t int64) {
+		downloaded, total = d, t
+	}
+
+	getter := TestDefaultGetter(t)
+	getter.progressInterval = 0
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(t.TempDir()), artifact, progress))
+	require.Equal(t, int64(len(content)), downloaded)
+	require.Equal(t, int64(len(content)), total)
+}
+
+func TestGetArtifact_BandwidthLimit(t *testing.T) {
+	content := bytes.Repeat([]byte("nomad"), 2000)
+	ts, _ := newRangeServer(t, content)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: ts.URL + "/file",
+		GetterMode:   structs.GetterModeFile,
+		RelativeDest: "local/file",
+	}
+
+	getterConf, err := clientconfig.ArtifactConfigFromAgent(config.DefaultArtifactConfig())
+	require.NoError(t, err)
+	getterConf.BandwidthLimit = 5000
+	getter := NewGetter(getterConf)
+
+	// 10KB at 5KB/s with a 5KB burst takes at least a second
+	start := time.Now()
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(t.TempDir()), artifact, nil))
+	require.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+}
+
+func TestProgressTracker_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tracker := &progressTracker{
+		ctx:     ctx,
+		limiter: rate.NewLimiter(1, 10),
+	}
+
+	content := bytes.Repeat([]byte("nomad"), 4)
+	r := tracker.TrackProgress("", 0, int64(len(content)), io.NopCloser(bytes.NewReader(content)))
+
+	// The first read uses up the burst, the next one would wait for 10
+	// seconds unless the wait is aborted with the download
+	b := make([]byte, 10)
+	_, err := r.Read(b)
+	require.NoError(t, err)
+
+	cancel()
+	start := time.Now()
+	_, err = r.Read(b)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestGetArtifact_MaxConcurrentDownloads(t *testing.T) {
+	var active, maxActive int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			m := atomic.LoadInt32(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("nomad"))
+	}))
+	defer ts.Close()
+
+	getterConf, err := clientconfig.ArtifactConfigFromAgent(config.DefaultArtifactConfig())
+	require.NoError(t, err)
+	getterConf.MaxConcurrentDownloads = 1
+	getter := NewGetter(getterConf)
+
+	errCh := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		artifact := &structs.TaskArtifact{
+			GetterSource: ts.URL + "/file",
+			GetterMode:   structs.GetterModeFile,
+			RelativeDest: fmt.Sprintf("local/file%d", i),
+		}
+		go func() {
+			errCh <- getter.GetArtifact(context.Background(), noopTaskEnv(t.TempDir()), artifact, nil)
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, <-errCh)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&maxActive))
+}
+
+func TestGetArtifact_MaxConcurrentDownloads_Cancel(t *testing.T) {
+	ts, _ := newRangeServer(t, []byte("nomad"))
+
+	getterConf, err := clientconfig.ArtifactConfigFromAgent(config.DefaultArtifactConfig())
+	require.NoError(t, err)
+	getterConf.MaxConcurrentDownloads = 1
+	getter := NewGetter(getterConf)
+
+	// Hold the only download slot
+	getter.downloads <- struct{}{}
+	defer func() { <-getter.downloads }()
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: ts.URL + "/file",
+		GetterMode:   structs.GetterModeFile,
+		RelativeDest: "local/file",
+	}
+
+	// A download waiting for a slot is aborted once its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = getter.GetArtifact(ctx, noopTaskEnv(t.TempDir()), artifact, nil)
+	require.Error(t, err)
+	getErr, ok := err.(*GetError)
+	require.True(t, ok)
+	require.Equal(t, context.DeadlineExceeded, getErr.Err)
+}
+
+func TestGetArtifact_NilConfig(t *testing.T) {
+	content := []byte("nomad")
+	ts, _ := newRangeServer(t, content)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: ts.URL + "/file",
+		GetterMode:   structs.GetterModeFile,
+		RelativeDest: "local/file",
+	}
+
+	// A client without an artifact config uses the default one
+	getter := NewGetter(nil)
+	taskDir := t.TempDir()
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil))
+
+	data, err := ioutil.ReadFile(filepath.Join(taskDir, "local", "file"))
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
 func TestGetArtifact_File_RelativeDest(t *testing.T) {
 	// Create the test server hosting the file to download
 	ts := httptest.NewServer(http.FileServer(http.Dir(filepath.Dir("./test-fixtures/"))))
@@ -234,7 +498,7 @@ func TestGetArtifact_File_RelativeDest(t *testing.T) {
 
 	// Download the artifact
 	getter := TestDefaultGetter(t)
-	if err := getter.GetArtifact(noopTaskEnv(taskDir), artifact); err != nil {
+	if err := getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil); err != nil {
 		t.Fatalf("GetArtifact failed: %v", err)
 	}
 
@@ -265,7 +529,7 @@ func TestGetArtifact_File_EscapeDest(t *testing.T) {
 
 	// attempt to download the artifact
 	getter := TestDefaultGetter(t)
-	err := getter.GetArtifact(noopTaskEnv(taskDir), artifact)
+	err := getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil)
 	if err == nil || !strings.Contains(err.Error(), "escapes") {
 		t.Fatalf("expected GetArtifact to disallow sandbox escape: %v", err)
 	}
@@ -312,7 +576,7 @@ func TestGetArtifact_InvalidChecksum(t *testing.T) {
 
 	// Download the artifact and expect an error
 	getter := TestDefaultGetter(t)
-	if err := getter.GetArtifact(noopTaskEnv(taskDir), artifact); err == nil {
+	if err := getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil); err == nil {
 		t.Fatalf("GetArtifact should have failed")
 	}
 }
@@ -374,7 +638,7 @@ func TestGetArtifact_Archive(t *testing.T) {
 	}
 
 	getter := TestDefaultGetter(t)
-	if err := getter.GetArtifact(noopTaskEnv(taskDir), artifact); err != nil {
+	if err := getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil); err != nil {
 		t.Fatalf("GetArtifact failed: %v", err)
 	}
 
@@ -406,7 +670,7 @@ func TestGetArtifact_Setuid(t *testing.T) {
 	}
 
 	getter := TestDefaultGetter(t)
-	require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+	require.NoError(t, getter.GetArtifact(context.Background(), noopTaskEnv(taskDir), artifact, nil))
 
 	var expected map[string]int
 
@@ -0,0 +1,81 @@
+package getter
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/hashicorp/nomad/client/interfaces"
+	"golang.org/x/time/rate"
+)
+
+// progressTracker implements go-getter's ProgressTracker interface. It
+// throttles downloads to the client's bandwidth limit and periodically
+// reports the progress of a download. go-getter only tracks the progress of
+// HTTP and S3 downloads.
+type progressTracker struct {
+	// ctx is the context of the download. Waiting on the limiter is
+	// aborted once it is done.
+	ctx context.Context
+
+	// limiter is shared by all downloads of the client, nil if the
+	// bandwidth is unlimited.
+	limiter *rate.Limiter
+
+	// maxBytes is the maximum size of an HTTP download. go-getter drops its
+	// own size limit when a progress tracker is set, so it is enforced
+	// here instead.
+	maxBytes int64
+
+	interval time.Duration
+	progress interfaces.ArtifactProgressFunc
+}
+
+func (p *progressTracker) TrackProgress(_ string, currentSize, totalSize int64, stream io.ReadCloser) io.ReadCloser {
+	r := &trackedReader{
+		ReadCloser: stream,
+		reader:     stream,
+		tracker:    p,
+		downloaded: currentSize,
+		total:      totalSize,
+		lastReport: time.Now(),
+	}
+	if p.maxBytes > 0 {
+		r.reader = io.LimitReader(stream, p.maxBytes)
+	}
+	return r
+}
+
+// trackedReader wraps the body of a download to apply the bandwidth limit
+// and report progress as it is read.
+type trackedReader struct {
+	io.ReadCloser
+	reader  io.Reader
+	tracker *progressTracker
+
+	downloaded int64
+	total      int64
+	lastReport time.Time
+}
+
+func (r *trackedReader) Read(b []byte) (int, error) {
+	limiter := r.tracker.limiter
+	if limiter != nil && len(b) > limiter.Burst() {
+		// Never read more than the limiter allows to wait for at once
+		b = b[:limiter.Burst()]
+	}
+
+	n, err := r.reader.Read(b)
+	if n > 0 && limiter != nil {
+		if werr := limiter.WaitN(r.tracker.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+
+	r.downloaded += int64(n)
+	if r.tracker.progress != nil && time.Since(r.lastReport) >= r.tracker.interval {
+		r.tracker.progress(r.downloaded, r.total)
+		r.lastReport = time.Now()
+	}
+	return n, err
+}
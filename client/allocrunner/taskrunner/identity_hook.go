@@ -2,6 +2,9 @@ package taskrunner
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 
 	log "github.com/hashicorp/go-hclog"
@@ -9,13 +12,22 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 )
 
+const (
+	// nomadTokenFile is the name of the file holding the Nomad workload
+	// identity token inside the task's secret directory
+	nomadTokenFile = "nomad_token"
+)
+
 // identityHook sets the task runner's Nomad workload identity token
-// based on the signed identity stored on the Allocation
+// based on the signed identity stored on the Allocation, and writes it to
+// the task's secret directory if the task's identity block enables file
 type identityHook struct {
-	tr       *TaskRunner
-	logger   log.Logger
-	taskName string
-	lock     sync.Mutex
+	tr        *TaskRunner
+	logger    log.Logger
+	taskName  string
+	tokenPath string
+	taskUser  string
+	lock      sync.Mutex
 }
 
 func newIdentityHook(tr *TaskRunner, logger log.Logger) *identityHook {
@@ -35,9 +47,13 @@ func (h *identityHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 	h.lock.Lock()
 	defer h.lock.Unlock()
 
+	if identity := req.Task.Identity; identity != nil && identity.File {
+		h.tokenPath = filepath.Join(req.TaskDir.SecretsDir, nomadTokenFile)
+		h.taskUser = req.Task.User
+	}
 	token := h.tr.alloc.SignedIdentities[h.taskName]
 	h.tr.setNomadToken(token)
-	return nil
+	return h.writeToken(token)
 }
 
 func (h *identityHook) Update(_ context.Context, req *interfaces.TaskUpdateRequest, _ *interfaces.TaskUpdateResponse) error {
@@ -46,5 +62,40 @@ func (h *identityHook) Update(_ context.Context, req *interfaces.TaskUpdateReque
 
 	token := h.tr.alloc.SignedIdentities[h.taskName]
 	h.tr.setNomadToken(token)
+	return h.writeToken(token)
+}
+
+// writeToken writes the token to the task's secret directory, readable only
+// by the task user. It is a no-op until the hook ran its prestart, if the
+// task doesn't enable the token file, or if the allocation has no identity
+// for the task.
+func (h *identityHook) writeToken(token string) error {
+	if h.tokenPath == "" || token == "" {
+		return nil
+	}
+
+	// Write to a temporary file that is renamed over the token, so the task
+	// never reads a partially written token.
+	tmpPath := h.tokenPath + ".tmp"
+	if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove nomad token: %v", err)
+	}
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write nomad token: %v", err)
+	}
+	_, err = f.WriteString(token)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write nomad token: %v", err)
+	}
+	if err := setTaskFileOwner(tmpPath, h.taskUser); err != nil {
+		return fmt.Errorf("failed to write nomad token: %v", err)
+	}
+	if err := os.Rename(tmpPath, h.tokenPath); err != nil {
+		return fmt.Errorf("failed to write nomad token: %v", err)
+	}
 	return nil
 }
@@ -0,0 +1,66 @@
+//go:build !windows
+
+package taskrunner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityHook_TokenFile(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	alloc.SignedIdentities = map[string]string{task.Name: "workload-identity"}
+
+	tr := &TaskRunner{alloc: alloc, taskName: task.Name}
+	hook := newIdentityHook(tr, testlog.HCLogger(t))
+
+	dir := t.TempDir()
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: &allocdir.TaskDir{SecretsDir: dir},
+	}
+	path := filepath.Join(dir, nomadTokenFile)
+
+	// The token file isn't written unless the task enables it
+	require.NoError(t, hook.Prestart(context.Background(), req, nil))
+	require.Equal(t, "workload-identity", tr.getNomadToken())
+	require.NoFileExists(t, path)
+
+	task.Identity = &structs.WorkloadIdentity{File: true}
+	require.NoError(t, hook.Prestart(context.Background(), req, nil))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "workload-identity", string(b))
+
+	// The token is only readable by the task user, nobody by default
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	if syscall.Geteuid() == 0 {
+		uid, _, err := lookupTaskUser("")
+		require.NoError(t, err)
+		require.Equal(t, uint32(uid), fi.Sys().(*syscall.Stat_t).Uid)
+	}
+
+	// Updating the identity rewrites the token
+	alloc.SignedIdentities[task.Name] = "new-identity"
+	require.NoError(t, hook.Update(context.Background(), &interfaces.TaskUpdateRequest{}, nil))
+	b, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "new-identity", string(b))
+}
@@ -0,0 +1,157 @@
+package taskrunner
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/lib/fifo"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// logStreamHook relays the logs streamed by drivers with the LogStreaming
+// capability into the stdout and stderr fifos read by logmon.
+type logStreamHook struct {
+	runner *TaskRunner
+	driver drivers.LogStreamingDriver
+
+	// cancel is called by Exited
+	cancel context.CancelFunc
+
+	// doneCh is closed when the relay goroutine exits
+	doneCh chan struct{}
+
+	mu sync.Mutex
+
+	logger hclog.Logger
+}
+
+func newLogStreamHook(tr *TaskRunner, driver drivers.LogStreamingDriver, logger hclog.Logger) *logStreamHook {
+	h := &logStreamHook{
+		runner: tr,
+		driver: driver,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*logStreamHook) Name() string {
+	return "logstream"
+}
+
+func (h *logStreamHook) Poststart(_ context.Context, _ *interfaces.TaskPoststartRequest, _ *interfaces.TaskPoststartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel != nil {
+		h.logger.Debug("poststart called twice without exiting between")
+		h.cancel()
+	}
+
+	handle := h.runner.getDriverHandle()
+	if handle == nil {
+		return nil
+	}
+
+	// Use a new context as the poststart context is canceled when the task
+	// is killed, and logs written while the task shuts down must be kept.
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.doneCh = make(chan struct{})
+	go h.relay(ctx, handle.ID(), h.doneCh)
+
+	return nil
+}
+
+func (h *logStreamHook) Exited(context.Context, *interfaces.TaskExitedRequest, *interfaces.TaskExitedResponse) error {
+	h.stop()
+	return nil
+}
+
+func (h *logStreamHook) Shutdown() {
+	h.stop()
+}
+
+func (h *logStreamHook) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel == nil {
+		return
+	}
+
+	h.cancel()
+	h.cancel = nil
+}
+
+// relay copies the logs streamed by the driver into the logmon fifos until
+// the driver closes the stream or the context is canceled.
+func (h *logStreamHook) relay(ctx context.Context, taskID string, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	ch, err := h.driver.TaskLogs(ctx, taskID)
+	if err != nil {
+		h.logger.Error("failed to stream task logs from driver", "error", err)
+		return
+	}
+
+	writers := map[string]io.WriteCloser{}
+	defer func() {
+		for _, w := range writers {
+			w.Close()
+		}
+	}()
+
+	for {
+		select {
+		case frame, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			w, err := h.writer(writers, frame.Stream)
+			if err != nil {
+				h.logger.Error("failed to open log fifo", "stream", frame.Stream, "error", err)
+				return
+			}
+			if w == nil {
+				h.logger.Warn("dropping logs of unknown stream", "stream", frame.Stream)
+				continue
+			}
+			if _, err := w.Write(frame.Data); err != nil {
+				h.logger.Error("failed to write task logs", "stream", frame.Stream, "error", err)
+				return
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// writer returns the fifo writer of the stream, opening it on first use, or
+// nil if the stream is unknown.
+func (h *logStreamHook) writer(writers map[string]io.WriteCloser, stream string) (io.WriteCloser, error) {
+	if w, ok := writers[stream]; ok {
+		return w, nil
+	}
+
+	var path string
+	switch stream {
+	case drivers.TaskLogStreamStdout:
+		path = h.runner.logmonHookConfig.stdoutFifo
+	case drivers.TaskLogStreamStderr:
+		path = h.runner.logmonHookConfig.stderrFifo
+	default:
+		return nil, nil
+	}
+
+	w, err := fifo.OpenWriter(path)
+	if err != nil {
+		return nil, err
+	}
+	writers[stream] = w
+	return w, nil
+}
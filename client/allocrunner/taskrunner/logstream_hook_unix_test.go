@@ -0,0 +1,87 @@
+//go:build !windows
+// +build !windows
+
+package taskrunner
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/lib/fifo"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLogStreamingDriver struct {
+	taskID string
+	frames []*drivers.TaskLogFrame
+}
+
+func (d *mockLogStreamingDriver) TaskLogs(_ context.Context, taskID string) (<-chan *drivers.TaskLogFrame, error) {
+	d.taskID = taskID
+	ch := make(chan *drivers.TaskLogFrame, len(d.frames))
+	for _, f := range d.frames {
+		ch <- f
+	}
+	close(ch)
+	return ch, nil
+}
+
+// TestTaskRunner_LogStreamHook asserts the logs streamed by the driver are
+// written to the logmon fifos.
+func TestTaskRunner_LogStreamHook(t *testing.T) {
+	ci.Parallel(t)
+
+	hookConf := newLogMonHookConfig("web", t.TempDir())
+	runner := &TaskRunner{
+		logmonHookConfig: hookConf,
+		handle:           &DriverHandle{taskID: "task-id"},
+	}
+
+	openStdout, err := fifo.CreateAndRead(hookConf.stdoutFifo)
+	require.NoError(t, err)
+	openStderr, err := fifo.CreateAndRead(hookConf.stderrFifo)
+	require.NoError(t, err)
+
+	read := func(open func() (io.ReadCloser, error)) <-chan string {
+		ch := make(chan string, 1)
+		go func() {
+			r, err := open()
+			if err != nil {
+				ch <- err.Error()
+				return
+			}
+			defer r.Close()
+			b, _ := ioutil.ReadAll(r)
+			ch <- string(b)
+		}()
+		return ch
+	}
+	stdoutCh := read(openStdout)
+	stderrCh := read(openStderr)
+
+	driver := &mockLogStreamingDriver{
+		frames: []*drivers.TaskLogFrame{
+			{Stream: drivers.TaskLogStreamStdout, Data: []byte("hello ")},
+			{Stream: drivers.TaskLogStreamStderr, Data: []byte("oops")},
+			{Stream: "other", Data: []byte("dropped")},
+			{Stream: drivers.TaskLogStreamStdout, Data: []byte("world")},
+		},
+	}
+	hook := newLogStreamHook(runner, driver, testlog.HCLogger(t))
+
+	req := &interfaces.TaskPoststartRequest{}
+	require.NoError(t, hook.Poststart(context.Background(), req, nil))
+
+	require.Equal(t, "hello world", <-stdoutCh)
+	require.Equal(t, "oops", <-stderrCh)
+	<-hook.doneCh
+	require.Equal(t, "task-id", driver.taskID)
+
+	require.NoError(t, hook.Exited(context.Background(), nil, nil))
+}
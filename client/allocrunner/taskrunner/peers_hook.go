@@ -0,0 +1,49 @@
+package taskrunner
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+)
+
+// peersHook sets the addresses of the other allocations of the job
+// registering the Nomad services of the task group, as tracked by the alloc
+// runner peers hook, in the task environment.
+type peersHook struct {
+	runner *TaskRunner
+	logger log.Logger
+}
+
+func newPeersHook(runner *TaskRunner, logger log.Logger) *peersHook {
+	h := &peersHook{
+		runner: runner,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*peersHook) Name() string {
+	return "peers"
+}
+
+func (h *peersHook) Prestart(_ context.Context, _ *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	// The hook isn't marked as done so the peers are refreshed when the
+	// task restarts.
+	res := h.runner.allocHookResources
+	if res == nil {
+		return nil
+	}
+	peers := res.GetPeers()
+	if len(peers) == 0 {
+		return nil
+	}
+
+	resp.Env = make(map[string]string, len(peers))
+	for service, addrs := range peers {
+		resp.Env[taskenv.PeersPrefix+service] = strings.Join(addrs, ",")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package taskrunner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+var _ interfaces.TaskPrestartHook = (*peersHook)(nil)
+
+func TestTaskRunner_PeersHook(t *testing.T) {
+	ci.Parallel(t)
+
+	tr := &TaskRunner{}
+	hook := newPeersHook(tr, testlog.HCLogger(t))
+
+	// No alloc hook resources
+	resp := new(interfaces.TaskPrestartResponse)
+	require.NoError(t, hook.Prestart(context.Background(), &interfaces.TaskPrestartRequest{}, resp))
+	require.Nil(t, resp.Env)
+
+	res := &cstructs.AllocHookResources{}
+	res.SetPeers(map[string][]string{
+		"db":  {"10.0.0.1:5432", "10.0.0.2:5432"},
+		"web": {},
+	})
+	tr.SetAllocHookResources(res)
+
+	resp = new(interfaces.TaskPrestartResponse)
+	require.NoError(t, hook.Prestart(context.Background(), &interfaces.TaskPrestartRequest{}, resp))
+	require.False(t, resp.Done)
+	require.Equal(t, map[string]string{
+		"NOMAD_PEERS_db":  "10.0.0.1:5432,10.0.0.2:5432",
+		"NOMAD_PEERS_web": "",
+	}, resp.Env)
+}
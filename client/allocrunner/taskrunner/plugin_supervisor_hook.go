@@ -46,6 +46,10 @@ type csiPluginSupervisorHook struct {
 	shutdownCancelFn context.CancelFunc
 	runOnce          sync.Once
 
+	// startedCh is notified by Poststart every time the task is started so
+	// the supervisor can wait for the task to be running after a restart.
+	startedCh chan struct{}
+
 	// previousHealthstate is used by the supervisor goroutine to track historic
 	// health states for gating task events.
 	previousHealthState bool
@@ -107,6 +111,10 @@ func newCSIPluginSupervisorHook(config *csiPluginSupervisorHookConfig) *csiPlugi
 		task.CSIPluginConfig.HealthTimeout = 30 * time.Second
 	}
 
+	if task.CSIPluginConfig.ProbeInterval == 0 {
+		task.CSIPluginConfig.ProbeInterval = 30 * time.Second
+	}
+
 	shutdownCtx, cancelFn := context.WithCancel(context.Background())
 
 	hook := &csiPluginSupervisorHook{
@@ -120,6 +128,7 @@ func newCSIPluginSupervisorHook(config *csiPluginSupervisorHookConfig) *csiPlugi
 		caps:             config.capabilities,
 		shutdownCtx:      shutdownCtx,
 		shutdownCancelFn: cancelFn,
+		startedCh:        make(chan struct{}, 1),
 		eventEmitter:     config.events,
 	}
 
@@ -231,6 +240,11 @@ func (h *csiPluginSupervisorHook) Poststart(_ context.Context, _ *interfaces.Tas
 		go h.ensureSupervisorLoop(h.shutdownCtx)
 	})
 
+	select {
+	case h.startedCh <- struct{}{}:
+	default:
+	}
+
 	return nil
 }
 
@@ -245,6 +259,10 @@ func (h *csiPluginSupervisorHook) Poststart(_ context.Context, _ *interfaces.Tas
 // - We then perform a more lightweight check, simply probing the plugin on a less
 //   frequent interval to ensure it is still alive, emitting task events when this
 //   status changes.
+// - If max_probe_failures is set and the plugin fails that many consecutive
+//   probes, it is deregistered from the catalog so no volume operations are
+//   sent to it while the task is restarted, and the supervisor starts over
+//   once the task is running again.
 //
 // Deeper fingerprinting of the plugin is implemented by the csimanager.
 func (h *csiPluginSupervisorHook) ensureSupervisorLoop(ctx context.Context) {
@@ -253,7 +271,46 @@ func (h *csiPluginSupervisorHook) ensureSupervisorLoop(ctx context.Context) {
 		"plugin.type", h.task.CSIPluginConfig.Type))
 	defer client.Close()
 
+	for {
+		// Wait for the task to be running, including after a restart
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.startedCh:
+		}
+
+		// Step 1: Wait for the plugin to initially become available.
+		if !h.waitForReady(ctx, client) {
+			return
+		}
+
+		// Step 2: Register the plugin with the catalog.
+		deregisterPluginFn, err := h.registerPlugin(client, h.socketPath)
+		if err != nil {
+			h.kill(ctx, fmt.Errorf("CSI plugin failed to register: %v", err))
+			return
+		}
+
+		// Step 3: Start the lightweight supervisor loop.
+		err = h.probeLoop(ctx, client)
+
+		// De-register plugins on task shutdown, or to fence off volume
+		// operations while the task is restarted
+		deregisterPluginFn()
+		if err == nil {
+			return
+		}
+
+		h.restart(ctx, err)
+	}
+}
+
+// waitForReady probes the plugin until it is healthy. The task is killed if
+// the plugin isn't healthy before the health timeout, in which case false is
+// returned.
+func (h *csiPluginSupervisorHook) waitForReady(ctx context.Context, client csi.CSIPlugin) bool {
 	t := time.NewTimer(0)
+	defer t.Stop()
 
 	// We're in Poststart at this point, so if we can't connect within
 	// this deadline, assume it's broken so we can restart the task
@@ -263,13 +320,11 @@ func (h *csiPluginSupervisorHook) ensureSupervisorLoop(ctx context.Context) {
 	var err error
 	var pluginHealthy bool
 
-	// Step 1: Wait for the plugin to initially become available.
-WAITFORREADY:
 	for {
 		select {
 		case <-startCtx.Done():
 			h.kill(ctx, fmt.Errorf("CSI plugin failed probe: %v", err))
-			return
+			return false
 		case <-t.C:
 			pluginHealthy, err = h.supervisorLoopOnce(startCtx, client)
 			if err != nil || !pluginHealthy {
@@ -286,29 +341,29 @@ WAITFORREADY:
 			event := structs.NewTaskEvent(structs.TaskPluginHealthy)
 			event.SetMessage(fmt.Sprintf("plugin: %s", h.task.CSIPluginConfig.ID))
 			h.eventEmitter.EmitEvent(event)
-
-			break WAITFORREADY
+			return true
 		}
 	}
+}
 
-	// Step 2: Register the plugin with the catalog.
-	deregisterPluginFn, err := h.registerPlugin(client, h.socketPath)
-	if err != nil {
-		h.kill(ctx, fmt.Errorf("CSI plugin failed to register: %v", err))
-		return
-	}
-	// De-register plugins on task shutdown
-	defer deregisterPluginFn()
+// probeLoop probes the plugin on the probe interval, emitting task events when
+// its health changes. It returns nil once the context is done, or an error once
+// the plugin has failed the maximum number of consecutive probes. If no maximum
+// is set, failed probes are only reported.
+func (h *csiPluginSupervisorHook) probeLoop(ctx context.Context, client csi.CSIPlugin) error {
+	t := time.NewTimer(0)
+	defer t.Stop()
 
-	// Step 3: Start the lightweight supervisor loop. At this point,
-	// probe failures don't cause the task to restart
-	t.Reset(0)
+	failures := 0
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			return nil
 		case <-t.C:
 			pluginHealthy, err := h.supervisorLoopOnce(ctx, client)
+			if ctx.Err() != nil {
+				return nil
+			}
 			if err != nil {
 				h.logger.Error("CSI plugin fingerprinting failed", "error", err)
 			}
@@ -333,13 +388,46 @@ WAITFORREADY:
 
 			h.previousHealthState = pluginHealthy
 
+			if pluginHealthy {
+				failures = 0
+			} else {
+				failures++
+				maxFailures := h.task.CSIPluginConfig.MaxProbeFailures
+				if maxFailures > 0 && failures >= maxFailures {
+					if err == nil {
+						err = fmt.Errorf("plugin reported it is not ready")
+					}
+					return fmt.Errorf("CSI plugin failed %d consecutive probes: %v", failures, err)
+				}
+			}
+
 			// This loop is informational and in some plugins this may be expensive to
-			// validate. We use a longer timeout (30s) to avoid causing undue work.
-			t.Reset(30 * time.Second)
+			// validate. We use a longer interval (30s by default) to avoid causing
+			// undue work.
+			t.Reset(h.task.CSIPluginConfig.ProbeInterval)
 		}
 	}
 }
 
+// restart restarts the task after the plugin failed its probes. The restart
+// counts as a failure against the restart policy of the task.
+func (h *csiPluginSupervisorHook) restart(ctx context.Context, reason error) {
+	h.logger.Warn("restarting task because plugin failed probes", "error", reason)
+
+	// Discard any notification from a restart the supervisor didn't trigger,
+	// so that it waits for the task to be running again.
+	select {
+	case <-h.startedCh:
+	default:
+	}
+
+	event := structs.NewTaskEvent(structs.TaskRestartSignal).
+		SetRestartReason(reason.Error())
+	if err := h.lifecycle.Restart(ctx, event, true); err != nil {
+		h.logger.Error("failed to restart task", "restart_reason", reason, "error", err)
+	}
+}
+
 func (h *csiPluginSupervisorHook) registerPlugin(client csi.CSIPlugin, socketPath string) (func(), error) {
 	// At this point we know the plugin is ready and we can fingerprint it
 	// to get its vendor name and version
@@ -0,0 +1,80 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/csi/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func testPluginSupervisorHook(t *testing.T) (*csiPluginSupervisorHook, *mockEmitter) {
+	emitter := &mockEmitter{}
+	hook := &csiPluginSupervisorHook{
+		logger: testlog.HCLogger(t),
+		task: &structs.Task{
+			CSIPluginConfig: &structs.TaskCSIPluginConfig{
+				ID:               "org.hashicorp.csi",
+				Type:             structs.CSIPluginTypeNode,
+				ProbeInterval:    10 * time.Millisecond,
+				MaxProbeFailures: 3,
+			},
+		},
+		eventEmitter:        emitter,
+		previousHealthState: true,
+	}
+	return hook, emitter
+}
+
+func TestCSIPluginSupervisorHook_ProbeLoop_Failures(t *testing.T) {
+	ci.Parallel(t)
+
+	hook, emitter := testPluginSupervisorHook(t)
+	client := &fake.Client{NextPluginProbeErr: fmt.Errorf("deadline exceeded")}
+
+	// The loop returns once the plugin failed too many consecutive probes
+	err := hook.probeLoop(context.Background(), client)
+	require.EqualError(t, err, "CSI plugin failed 3 consecutive probes: deadline exceeded")
+	require.Equal(t, int64(3), client.PluginProbeCallCount)
+
+	// Only the transition to unhealthy emits an event
+	require.Len(t, emitter.events, 1)
+	require.Equal(t, structs.TaskPluginUnhealthy, emitter.events[0].Type)
+	require.False(t, hook.previousHealthState)
+}
+
+func TestCSIPluginSupervisorHook_ProbeLoop_Healthy(t *testing.T) {
+	ci.Parallel(t)
+
+	hook, emitter := testPluginSupervisorHook(t)
+	client := &fake.Client{NextPluginProbeResponse: true}
+
+	// The loop runs until the task is stopped while the plugin is healthy
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	require.NoError(t, hook.probeLoop(ctx, client))
+	require.Greater(t, client.PluginProbeCallCount, int64(3))
+	require.Empty(t, emitter.events)
+}
+
+func TestCSIPluginSupervisorHook_ProbeLoop_ReportOnly(t *testing.T) {
+	ci.Parallel(t)
+
+	hook, emitter := testPluginSupervisorHook(t)
+	hook.task.CSIPluginConfig.MaxProbeFailures = 0
+	client := &fake.Client{NextPluginProbeErr: fmt.Errorf("deadline exceeded")}
+
+	// Without a maximum the loop keeps probing and never asks for a restart
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	require.NoError(t, hook.probeLoop(ctx, client))
+	require.Greater(t, client.PluginProbeCallCount, int64(3))
+
+	require.Len(t, emitter.events, 1)
+	require.Equal(t, structs.TaskPluginUnhealthy, emitter.events[0].Type)
+}
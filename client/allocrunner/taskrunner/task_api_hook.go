@@ -0,0 +1,315 @@
+package taskrunner
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// taskAPISocketName is the name of the task API socket inside the task's
+	// secret directory.
+	taskAPISocketName = "api.sock"
+
+	// maxTaskAPISocketPathLen is the maximum length of the path of the task
+	// API socket. Unix socket paths are limited to 104 bytes on some
+	// platforms and 108 on others.
+	maxTaskAPISocketPathLen = 104
+
+	// taskAPIMaxBodySize is the maximum size of request bodies accepted by
+	// the task API.
+	taskAPIMaxBodySize = 64 * 1024
+)
+
+// taskAPIRunner is the interface required by the taskAPIHook.
+// Satisfied by TaskRunner.
+type taskAPIRunner interface {
+	ti.EventEmitter
+	Alloc() *structs.Allocation
+	getNomadToken() string
+}
+
+type taskAPIHookConfig struct {
+	runner   taskAPIRunner
+	rpc      RPCer
+	taskName string
+	node     *structs.Node
+	region   string
+	logger   log.Logger
+}
+
+// taskAPIHook serves the task API on a unix socket inside the task's secret
+// directory, if the task's identity block enables task_api. The task API lets
+// the task read the metadata and service registrations of its allocation and
+// mark itself unhealthy without an ACL token. Requests are authenticated by
+// the workload identity of the task.
+type taskAPIHook struct {
+	runner   taskAPIRunner
+	rpc      RPCer
+	taskName string
+	node     *structs.Node
+	region   string
+
+	// srv serves the task API. It is kept across task restarts.
+	srv *http.Server
+
+	// unhealthyMsg is the message the task last marked itself unhealthy with
+	// since it started, or nil if it didn't. Repeated requests with the same
+	// message don't emit an event so that they don't flood the task events.
+	unhealthyMsg *string
+
+	mu sync.Mutex
+
+	logger log.Logger
+}
+
+func newTaskAPIHook(c *taskAPIHookConfig) *taskAPIHook {
+	h := &taskAPIHook{
+		runner:   c.runner,
+		rpc:      c.rpc,
+		taskName: c.taskName,
+		node:     c.node,
+		region:   c.region,
+	}
+	h.logger = c.logger.Named(h.Name())
+	return h
+}
+
+func (*taskAPIHook) Name() string {
+	return "task_api"
+}
+
+func (h *taskAPIHook) Prestart(_ context.Context, req *interfaces.TaskPrestartRequest, _ *interfaces.TaskPrestartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The task is starting, so it can mark itself unhealthy again
+	h.unhealthyMsg = nil
+
+	// The hook isn't marked as done so the socket is created again when the
+	// client restarts, but it is kept when the task restarts.
+	if h.srv != nil {
+		return nil
+	}
+
+	path := filepath.Join(req.TaskDir.SecretsDir, taskAPISocketName)
+	if len(path) > maxTaskAPISocketPathLen {
+		h.logger.Warn("task API socket path is too long, not serving the task API", "path", path)
+		return nil
+	}
+
+	// Remove the socket left by a previous client process
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove task API socket: %v", err)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to create task API socket: %v", err)
+	}
+
+	// Only the task user may connect to the socket.
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return fmt.Errorf("failed to set task API socket permissions: %v", err)
+	}
+	if err := setTaskFileOwner(path, req.Task.User); err != nil {
+		ln.Close()
+		return fmt.Errorf("failed to set task API socket owner: %v", err)
+	}
+
+	h.srv = &http.Server{
+		Handler:           h.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func(srv *http.Server) {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			h.logger.Error("task API server failed", "error", err)
+		}
+	}(h.srv)
+
+	h.logger.Trace("serving task API", "path", path)
+	return nil
+}
+
+// Stop closes the task API once the task exited for good.
+func (h *taskAPIHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
+	h.close()
+	return nil
+}
+
+// Shutdown closes the task API when the client shuts down. It is served again
+// when the client restores the task.
+func (h *taskAPIHook) Shutdown() {
+	h.close()
+}
+
+func (h *taskAPIHook) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.srv != nil {
+		h.srv.Close()
+		h.srv = nil
+	}
+}
+
+// handler returns the HTTP handler of the task API.
+func (h *taskAPIHook) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/task/allocation", h.authenticated(h.allocationRequest))
+	mux.HandleFunc("/v1/task/services", h.authenticated(h.servicesRequest))
+	mux.HandleFunc("/v1/task/unhealthy", h.authenticated(h.unhealthyRequest))
+	return mux
+}
+
+// authenticated wraps a handler of the task API to only serve requests made
+// with the workload identity of the task, set as the X-Nomad-Token header or
+// as a bearer token. The identity is passed to the handler.
+func (h *taskAPIHook) authenticated(fn func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Nomad-Token")
+		if token == "" {
+			token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+
+		identity := h.runner.getNomadToken()
+		if identity == "" || subtle.ConstantTimeCompare([]byte(token), []byte(identity)) != 1 {
+			http.Error(w, structs.ErrPermissionDenied.Error(), http.StatusForbidden)
+			return
+		}
+		fn(w, r, identity)
+	}
+}
+
+// taskAPIAllocation is the allocation metadata returned by the task API.
+type taskAPIAllocation struct {
+	ID         string
+	Name       string
+	Namespace  string
+	JobID      string
+	JobVersion uint64
+	TaskGroup  string
+	Task       string
+	NodeID     string
+	NodeName   string
+	Datacenter string
+	Region     string
+	Meta       map[string]string
+}
+
+func (h *taskAPIHook) allocationRequest(w http.ResponseWriter, r *http.Request, _ string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	alloc := h.runner.Alloc()
+	out := &taskAPIAllocation{
+		ID:         alloc.ID,
+		Name:       alloc.Name,
+		Namespace:  alloc.Namespace,
+		JobID:      alloc.JobID,
+		JobVersion: alloc.Job.Version,
+		TaskGroup:  alloc.TaskGroup,
+		Task:       h.taskName,
+		NodeID:     alloc.NodeID,
+		NodeName:   alloc.NodeName,
+		Region:     h.region,
+		Meta:       alloc.Job.CombinedTaskMeta(alloc.TaskGroup, h.taskName),
+	}
+	if h.node != nil {
+		out.Datacenter = h.node.Datacenter
+	}
+	writeTaskAPIResponse(w, out)
+}
+
+func (h *taskAPIHook) servicesRequest(w http.ResponseWriter, r *http.Request, identity string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.rpc == nil {
+		http.Error(w, "task API not connected to the servers", http.StatusServiceUnavailable)
+		return
+	}
+
+	// The servers authorize the workload identity to read the service
+	// registrations of its own allocation.
+	alloc := h.runner.Alloc()
+	req := &structs.AllocServiceRegistrationsRequest{
+		AllocID: alloc.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:     h.region,
+			Namespace:  alloc.Namespace,
+			AuthToken:  identity,
+			AllowStale: true,
+		},
+	}
+	var resp structs.AllocServiceRegistrationsResponse
+	if err := h.rpc.RPC(structs.AllocServiceRegistrationsRPCMethod, req, &resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if resp.Services == nil {
+		resp.Services = make([]*structs.ServiceRegistration, 0)
+	}
+	writeTaskAPIResponse(w, resp.Services)
+}
+
+// taskAPIUnhealthyRequest is the optional body of requests marking the task
+// unhealthy.
+type taskAPIUnhealthyRequest struct {
+	Message string
+}
+
+func (h *taskAPIHook) unhealthyRequest(w http.ResponseWriter, r *http.Request, _ string) {
+	if r.Method != http.MethodPut && r.Method != http.MethodPost {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req taskAPIUnhealthyRequest
+	if r.ContentLength != 0 {
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, taskAPIMaxBodySize))
+		if err := dec.Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode request: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	h.mu.Lock()
+	emit := h.unhealthyMsg == nil || *h.unhealthyMsg != req.Message
+	h.unhealthyMsg = &req.Message
+	h.mu.Unlock()
+
+	if emit {
+		h.logger.Info("task marked itself unhealthy", "message", req.Message)
+		h.runner.EmitEvent(structs.NewTaskEvent(structs.TaskMarkedUnhealthy).
+			SetMessage(req.Message))
+	}
+	writeTaskAPIResponse(w, struct{}{})
+}
+
+// writeTaskAPIResponse writes the JSON encoded response of a task API request.
+func writeTaskAPIResponse(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
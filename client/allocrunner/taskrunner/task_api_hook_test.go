@@ -0,0 +1,197 @@
+package taskrunner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the task API hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*taskAPIHook)(nil)
+var _ interfaces.TaskStopHook = (*taskAPIHook)(nil)
+var _ interfaces.ShutdownHook = (*taskAPIHook)(nil)
+
+type mockTaskAPIRunner struct {
+	mockEmitter
+	mu    sync.Mutex
+	alloc *structs.Allocation
+	token string
+}
+
+func (m *mockTaskAPIRunner) Alloc() *structs.Allocation { return m.alloc }
+
+func (m *mockTaskAPIRunner) getNomadToken() string { return m.token }
+
+func (m *mockTaskAPIRunner) EmitEvent(ev *structs.TaskEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mockEmitter.EmitEvent(ev)
+}
+
+type mockTaskAPIRPC struct {
+	services []*structs.ServiceRegistration
+	req      *structs.AllocServiceRegistrationsRequest
+}
+
+func (m *mockTaskAPIRPC) RPC(method string, args interface{}, reply interface{}) error {
+	if method != structs.AllocServiceRegistrationsRPCMethod {
+		return fmt.Errorf("unexpected RPC %q", method)
+	}
+	m.req = args.(*structs.AllocServiceRegistrationsRequest)
+	reply.(*structs.AllocServiceRegistrationsResponse).Services = m.services
+	return nil
+}
+
+// testTaskAPIClient returns an HTTP client connecting to the task API socket.
+func testTaskAPIClient(path string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+func testTaskAPIRequest(t *testing.T, client *http.Client, method, path, token, body string) (int, string) {
+	req, err := http.NewRequest(method, "http://task"+path, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("X-Nomad-Token", token)
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	out, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(out)
+}
+
+func TestTaskRunner_TaskAPIHook(t *testing.T) {
+	ci.Parallel(t)
+
+	// Use a short directory to stay under the unix socket path limit
+	dir, err := ioutil.TempDir("", "taskapi")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Meta = map[string]string{"version": "2"}
+	task.Identity = &structs.WorkloadIdentity{File: true, TaskAPI: true}
+
+	runner := &mockTaskAPIRunner{alloc: alloc, token: "workload-identity"}
+	rpc := &mockTaskAPIRPC{services: []*structs.ServiceRegistration{{ServiceName: "web", AllocID: alloc.ID}}}
+	hook := newTaskAPIHook(&taskAPIHookConfig{
+		runner:   runner,
+		rpc:      rpc,
+		taskName: task.Name,
+		node:     mock.Node(),
+		region:   "global",
+		logger:   testlog.HCLogger(t),
+	})
+
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: &allocdir.TaskDir{SecretsDir: dir},
+	}
+	resp := new(interfaces.TaskPrestartResponse)
+	require.NoError(t, hook.Prestart(context.Background(), req, resp))
+	require.False(t, resp.Done)
+	defer hook.Shutdown()
+
+	// Only the task user may connect to the socket
+	path := filepath.Join(dir, taskAPISocketName)
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	client := testTaskAPIClient(path)
+
+	// Requests without the workload identity are rejected
+	code, _ := testTaskAPIRequest(t, client, "GET", "/v1/task/allocation", "", "")
+	require.Equal(t, http.StatusForbidden, code)
+	code, _ = testTaskAPIRequest(t, client, "GET", "/v1/task/allocation", "bad-token", "")
+	require.Equal(t, http.StatusForbidden, code)
+
+	// Read the allocation metadata
+	code, body := testTaskAPIRequest(t, client, "GET", "/v1/task/allocation", runner.token, "")
+	require.Equal(t, http.StatusOK, code, body)
+	var out taskAPIAllocation
+	require.NoError(t, json.Unmarshal([]byte(body), &out))
+	require.Equal(t, alloc.ID, out.ID)
+	require.Equal(t, task.Name, out.Task)
+	require.Equal(t, "global", out.Region)
+	require.Equal(t, "dc1", out.Datacenter)
+	require.Equal(t, "2", out.Meta["version"])
+
+	// Read the service registrations with the bearer token
+	httpReq, err := http.NewRequest("GET", "http://task/v1/task/services", nil)
+	require.NoError(t, err)
+	httpReq.Header.Set("Authorization", "Bearer "+runner.token)
+	httpResp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	var services []*structs.ServiceRegistration
+	require.NoError(t, json.NewDecoder(httpResp.Body).Decode(&services))
+	httpResp.Body.Close()
+	require.Len(t, services, 1)
+	require.Equal(t, "web", services[0].ServiceName)
+	require.Equal(t, alloc.ID, rpc.req.AllocID)
+	require.Equal(t, runner.token, rpc.req.AuthToken)
+
+	// Mark the task unhealthy
+	code, _ = testTaskAPIRequest(t, client, "GET", "/v1/task/unhealthy", runner.token, "")
+	require.Equal(t, http.StatusMethodNotAllowed, code)
+	code, body = testTaskAPIRequest(t, client, "PUT", "/v1/task/unhealthy", runner.token, `{"Message":"db unreachable"}`)
+	require.Equal(t, http.StatusOK, code, body)
+
+	runner.mu.Lock()
+	require.Len(t, runner.events, 1)
+	require.Equal(t, structs.TaskMarkedUnhealthy, runner.events[0].Type)
+	require.Equal(t, "db unreachable", runner.events[0].Message)
+	runner.mu.Unlock()
+
+	// Marking the task unhealthy again only emits an event if the message
+	// changed
+	code, body = testTaskAPIRequest(t, client, "PUT", "/v1/task/unhealthy", runner.token, `{"Message":"db unreachable"}`)
+	require.Equal(t, http.StatusOK, code, body)
+	code, body = testTaskAPIRequest(t, client, "PUT", "/v1/task/unhealthy", runner.token, `{"Message":"disk full"}`)
+	require.Equal(t, http.StatusOK, code, body)
+
+	runner.mu.Lock()
+	require.Len(t, runner.events, 2)
+	require.Equal(t, "disk full", runner.events[1].Message)
+	runner.mu.Unlock()
+
+	// The socket is kept across task restarts and closed on stop
+	require.NoError(t, hook.Prestart(context.Background(), req, resp))
+
+	// Once the task restarted, it can mark itself unhealthy again
+	code, body = testTaskAPIRequest(t, client, "PUT", "/v1/task/unhealthy", runner.token, `{"Message":"disk full"}`)
+	require.Equal(t, http.StatusOK, code, body)
+
+	runner.mu.Lock()
+	require.Len(t, runner.events, 3)
+	runner.mu.Unlock()
+
+	require.NoError(t, hook.Stop(context.Background(), nil, nil))
+	_, err = client.Get("http://task/v1/task/allocation")
+	require.Error(t, err)
+}
@@ -0,0 +1,66 @@
+//go:build !windows
+
+package taskrunner
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// setTaskFileOwner gives the user the task runs as ownership of a file the
+// client created for the task, so that the file can be accessible to the task
+// only. Tasks that don't set a user own the file as nobody, like the task
+// directories. The user may be given as a name or as a numeric uid[:gid]. It
+// is a no-op if the client is not running as root.
+func setTaskFileOwner(path, username string) error {
+	if syscall.Geteuid() != 0 {
+		return nil
+	}
+
+	uid, gid, err := lookupTaskUser(username)
+	if err != nil {
+		return err
+	}
+	if err := os.Lchown(path, uid, gid); err != nil {
+		return fmt.Errorf("failed to change owner of %s: %v", path, err)
+	}
+	return nil
+}
+
+// lookupTaskUser returns the uid and gid of the task user.
+func lookupTaskUser(username string) (int, int, error) {
+	if username == "" {
+		username = "nobody"
+	}
+
+	// Numeric users, as commonly used by containers, don't need to exist on
+	// the host.
+	uidStr, gidStr, hasGid := strings.Cut(username, ":")
+	if uid, err := strconv.Atoi(uidStr); err == nil {
+		gid := uid
+		if hasGid {
+			if gid, err = strconv.Atoi(gidStr); err != nil {
+				return 0, 0, fmt.Errorf("invalid gid in task user %q", username)
+			}
+		}
+		return uid, gid, nil
+	}
+
+	u, err := user.Lookup(uidStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to look up task user %q: %v", uidStr, err)
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid uid %q for task user %q", u.Uid, uidStr)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid gid %q for task user %q", u.Gid, uidStr)
+	}
+	return uid, gid, nil
+}
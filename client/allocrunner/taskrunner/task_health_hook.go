@@ -0,0 +1,174 @@
+package taskrunner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// taskHealthInterval is the interval at which the health of the task is
+	// polled from the driver
+	taskHealthInterval = 10 * time.Second
+)
+
+// taskHealthRunner is the interface required by the taskHealthHook.
+// Satisfied by TaskRunner.
+type taskHealthRunner interface {
+	ti.TaskLifecycle
+	ti.EventEmitter
+	getDriverHandle() *DriverHandle
+}
+
+// taskHealthHook polls the health of the task from drivers with the
+// TaskHealth capability. It emits task events when the health changes and
+// restarts the task when the driver reports it as unhealthy, counting the
+// restart against the restart policy of the task.
+type taskHealthHook struct {
+	runner   taskHealthRunner
+	driver   drivers.TaskHealthDriver
+	interval time.Duration
+
+	// health is the last health reported by the driver. It is kept across
+	// restarts so a task becoming healthy again after being restarted for
+	// being unhealthy emits an event.
+	health drivers.HealthState
+
+	// cancel is called by Exited
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+
+	logger hclog.Logger
+}
+
+func newTaskHealthHook(runner taskHealthRunner, driver drivers.TaskHealthDriver, logger hclog.Logger) *taskHealthHook {
+	h := &taskHealthHook{
+		runner:   runner,
+		driver:   driver,
+		interval: taskHealthInterval,
+		health:   drivers.HealthStateUndetected,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*taskHealthHook) Name() string {
+	return "task_health"
+}
+
+func (h *taskHealthHook) Poststart(_ context.Context, _ *interfaces.TaskPoststartRequest, _ *interfaces.TaskPoststartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel != nil {
+		h.logger.Debug("poststart called twice without exiting between")
+		h.cancel()
+	}
+
+	handle := h.runner.getDriverHandle()
+	if handle == nil {
+		return nil
+	}
+
+	// Use a new context as the poststart context is scoped to the request
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go h.watch(ctx, handle.ID())
+
+	return nil
+}
+
+func (h *taskHealthHook) Exited(context.Context, *interfaces.TaskExitedRequest, *interfaces.TaskExitedResponse) error {
+	h.stop()
+	return nil
+}
+
+func (h *taskHealthHook) Shutdown() {
+	h.stop()
+}
+
+func (h *taskHealthHook) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel == nil {
+		return
+	}
+
+	h.cancel()
+	h.cancel = nil
+}
+
+// watch polls the health of the task until the context is canceled.
+func (h *taskHealthHook) watch(ctx context.Context, taskID string) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		status, err := h.driver.TaskHealth(ctx, taskID)
+		if err != nil {
+			if ctx.Err() == nil {
+				h.logger.Debug("failed to get task health from driver", "error", err)
+			}
+			continue
+		}
+
+		if h.update(ctx, status) {
+			// The task is being restarted and a new watch starts once it
+			// is running again.
+			return
+		}
+	}
+}
+
+// update records the health reported by the driver, emitting an event if it
+// changed. It returns true if the task was restarted.
+func (h *taskHealthHook) update(ctx context.Context, status *drivers.TaskHealthStatus) bool {
+	h.mu.Lock()
+	prev := h.health
+	if status.Health != drivers.HealthStateUndetected {
+		h.health = status.Health
+	}
+	h.mu.Unlock()
+
+	switch {
+	case status.Health == drivers.HealthStateUnhealthy:
+		msg := status.HealthDescription
+		if msg == "" {
+			msg = "Driver reported task as unhealthy"
+		}
+		h.logger.Info("restarting unhealthy task", "reason", msg)
+
+		// Restart emits the event
+		event := structs.NewTaskEvent(structs.TaskDriverUnhealthy).
+			SetMessage(msg).
+			SetRestartReason(msg)
+		if err := h.runner.Restart(ctx, event, true); err != nil {
+			h.logger.Warn("failed to restart unhealthy task", "error", err)
+			return false
+		}
+		return true
+
+	case status.Health == drivers.HealthStateHealthy && prev == drivers.HealthStateUnhealthy:
+		msg := status.HealthDescription
+		if msg == "" {
+			msg = "Driver reported task as healthy"
+		}
+		h.runner.EmitEvent(structs.NewTaskEvent(structs.TaskDriverHealthy).SetMessage(msg))
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+package taskrunner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the task health hook implements the expected interfaces
+var _ interfaces.TaskPoststartHook = (*taskHealthHook)(nil)
+var _ interfaces.TaskExitedHook = (*taskHealthHook)(nil)
+var _ interfaces.ShutdownHook = (*taskHealthHook)(nil)
+var _ taskHealthRunner = (*TaskRunner)(nil)
+
+type mockTaskHealthRunner struct {
+	handle *DriverHandle
+
+	mu       sync.Mutex
+	events   []*structs.TaskEvent
+	restarts []bool
+}
+
+func (m *mockTaskHealthRunner) getDriverHandle() *DriverHandle {
+	return m.handle
+}
+
+func (m *mockTaskHealthRunner) Restart(_ context.Context, event *structs.TaskEvent, failure bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+	m.restarts = append(m.restarts, failure)
+	return nil
+}
+
+func (m *mockTaskHealthRunner) Signal(*structs.TaskEvent, string) error { return nil }
+
+func (m *mockTaskHealthRunner) Kill(context.Context, *structs.TaskEvent) error { return nil }
+
+func (m *mockTaskHealthRunner) IsRunning() bool { return m.handle != nil }
+
+func (m *mockTaskHealthRunner) EmitEvent(event *structs.TaskEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+}
+
+func (m *mockTaskHealthRunner) eventTypes() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var types []string
+	for _, e := range m.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+type mockTaskHealthDriver struct {
+	mu     sync.Mutex
+	taskID string
+	health []*drivers.TaskHealthStatus
+}
+
+func (d *mockTaskHealthDriver) TaskHealth(_ context.Context, taskID string) (*drivers.TaskHealthStatus, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.taskID = taskID
+	status := d.health[0]
+	if len(d.health) > 1 {
+		d.health = d.health[1:]
+	}
+	return status, nil
+}
+
+// TestTaskRunner_TaskHealthHook asserts the task is restarted when the driver
+// reports it as unhealthy, and an event is emitted when it is healthy again.
+func TestTaskRunner_TaskHealthHook(t *testing.T) {
+	ci.Parallel(t)
+
+	runner := &mockTaskHealthRunner{
+		handle: &DriverHandle{taskID: "task-id"},
+	}
+	driver := &mockTaskHealthDriver{
+		health: []*drivers.TaskHealthStatus{
+			{Health: drivers.HealthStateHealthy},
+			{Health: drivers.HealthStateUnhealthy, HealthDescription: "container unhealthy"},
+			{Health: drivers.HealthStateUndetected},
+			{Health: drivers.HealthStateHealthy},
+		},
+	}
+	hook := newTaskHealthHook(runner, driver, testlog.HCLogger(t))
+	hook.interval = 10 * time.Millisecond
+
+	// The first poll is healthy, the second restarts the task
+	require.NoError(t, hook.Poststart(context.Background(), nil, nil))
+	require.Eventually(t, func() bool {
+		return len(runner.eventTypes()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, []bool{true}, runner.restarts)
+	require.Equal(t, "container unhealthy", runner.events[0].Message)
+	require.Equal(t, "task-id", driver.taskID)
+	require.NoError(t, hook.Exited(context.Background(), nil, nil))
+
+	// Once restarted the task becoming healthy again emits an event
+	require.NoError(t, hook.Poststart(context.Background(), nil, nil))
+	require.Eventually(t, func() bool {
+		return len(runner.eventTypes()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, []string{
+		structs.TaskDriverUnhealthy,
+		structs.TaskDriverHealthy,
+	}, runner.eventTypes())
+	require.Len(t, runner.restarts, 1)
+
+	hook.Shutdown()
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
+	"github.com/hashicorp/nomad/client/envprovider"
 	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/pluginmanager/csimanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
@@ -241,6 +242,10 @@ type TaskRunner struct {
 	// closed.
 	waitOnServers bool
 
+	// restoreErr is the error recovering the driver handle of a running task
+	// when the task runner was restored, if any.
+	restoreErr error
+
 	networkIsolationLock sync.Mutex
 	networkIsolationSpec *drivers.NetworkIsolationSpec
 
@@ -252,6 +257,17 @@ type TaskRunner struct {
 
 	// getter is an interface for retrieving artifacts.
 	getter cinterfaces.ArtifactGetter
+
+	// envProviders runs the environment providers for the task.
+	envProviders *envprovider.Manager
+
+	// rpcClient is used by hooks to make RPC calls to the servers.
+	rpcClient RPCer
+}
+
+// RPCer is the interface needed by hooks to make RPC calls.
+type RPCer interface {
+	RPC(method string, args interface{}, reply interface{}) error
 }
 
 type Config struct {
@@ -320,6 +336,12 @@ type Config struct {
 
 	// Getter is an interface for retrieving artifacts.
 	Getter cinterfaces.ArtifactGetter
+
+	// EnvProviders runs the environment providers for the task.
+	EnvProviders *envprovider.Manager
+
+	// RPCClient is used by hooks to make RPC calls to the servers.
+	RPCClient RPCer
 }
 
 func NewTaskRunner(config *Config) (*TaskRunner, error) {
@@ -379,6 +401,8 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		shutdownDelayCancelFn:  config.ShutdownDelayCancelFn,
 		serviceRegWrapper:      config.ServiceRegWrapper,
 		getter:                 config.Getter,
+		envProviders:           config.EnvProviders,
+		rpcClient:              config.RPCClient,
 	}
 
 	// Create the logger based on the allocation ID
@@ -716,7 +740,7 @@ func (tr *TaskRunner) handleTaskExitResult(result *drivers.ExitResult) (retryWai
 		h := tr.localState.TaskHandle
 		net := tr.localState.DriverNetwork
 		tr.stateLock.RUnlock()
-		if !tr.restoreHandle(h, net) {
+		if err := tr.restoreHandle(h, net); err != nil {
 			tr.logger.Error("failed to restore handle on driver after it exited unexpectedly", "driver", dn)
 			return false
 		}
@@ -851,7 +875,7 @@ func (tr *TaskRunner) runDriver() error {
 	}
 
 	// Start the job if there's no existing handle (or if RecoverTask failed)
-	handle, net, err := tr.driver.StartTask(taskConfig)
+	handle, net, err := tr.startTask(taskConfig)
 	if err != nil {
 		// The plugin has died, try relaunching it
 		if err == bstructs.ErrPluginShutdown {
@@ -960,6 +984,11 @@ func (tr *TaskRunner) handleKill(resultCh <-chan *drivers.ExitResult) *drivers.E
 		return nil
 	}
 
+	// Checkpoint the task so the allocation replacing it can restore it
+	if tr.shouldCheckpoint() {
+		tr.checkpointTask(handle)
+	}
+
 	// Kill the task using an exponential backoff in-case of failures.
 	result, killErr := tr.killTask(handle, resultCh)
 	if killErr != nil {
@@ -1073,6 +1102,16 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 		cpusetCpus[i] = fmt.Sprintf("%d", v)
 	}
 
+	var process *drivers.ProcessConfig
+	if p := task.Process; p != nil {
+		process = &drivers.ProcessConfig{
+			Umask:       p.Umask,
+			Nice:        p.Nice,
+			IOniceClass: p.IOniceClass,
+			Rlimits:     helper.CopyMap(p.Rlimits),
+		}
+	}
+
 	return &drivers.TaskConfig{
 		ID:            fmt.Sprintf("%s/%s/%s", alloc.ID, task.Name, invocationid),
 		Name:          task.Name,
@@ -1103,6 +1142,7 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 		AllocID:          tr.allocID,
 		NetworkIsolation: tr.networkIsolationSpec,
 		DNS:              dns,
+		Process:          process,
 	}
 }
 
@@ -1128,14 +1168,15 @@ func (tr *TaskRunner) Restore() error {
 	if taskHandle := tr.localState.TaskHandle; taskHandle != nil {
 		//TODO if RecoverTask returned the DriverNetwork we wouldn't
 		//     have to persist it at all!
-		restored := tr.restoreHandle(taskHandle, tr.localState.DriverNetwork)
+		err := tr.restoreHandle(taskHandle, tr.localState.DriverNetwork)
 
 		// If the handle could not be restored, the alloc is
 		// non-terminal, and the task isn't a system job: wait until
 		// servers have been contacted before running. #1795
-		if restored {
+		if err == nil {
 			return nil
 		}
+		tr.restoreErr = err
 
 		alloc := tr.Alloc()
 		if tr.state.State == structs.TaskStateDead || alloc.TerminalStatus() || alloc.Job.Type == structs.JobTypeSystem {
@@ -1153,23 +1194,55 @@ func (tr *TaskRunner) Restore() error {
 	return nil
 }
 
+// RestoreStatus returns whether Restore reattached to a running task, and the
+// error recovering its driver handle if it couldn't.
+func (tr *TaskRunner) RestoreStatus() (bool, error) {
+	return tr.getDriverHandle() != nil, tr.restoreErr
+}
+
+// VerifyRecoverable returns an error if a client restarted from the persisted
+// state would be unable to reattach to the running task. It returns false if
+// the task isn't running.
+func (tr *TaskRunner) VerifyRecoverable() (bool, error) {
+	if tr.TaskState().State != structs.TaskStateRunning {
+		return false, nil
+	}
+
+	handle := tr.getDriverHandle()
+	if handle == nil {
+		return true, fmt.Errorf("task has no driver handle")
+	}
+
+	ls, _, err := tr.stateDB.GetTaskRunnerState(tr.allocID, tr.taskName)
+	if err != nil {
+		return true, fmt.Errorf("failed to read task state: %v", err)
+	}
+	if ls == nil || ls.TaskHandle == nil || ls.TaskHandle.Config == nil {
+		return true, fmt.Errorf("driver handle was not persisted")
+	}
+	if id := ls.TaskHandle.Config.ID; id != handle.ID() {
+		return true, fmt.Errorf("persisted driver handle %q doesn't match running task %q", id, handle.ID())
+	}
+	return true, nil
+}
+
 // restoreHandle ensures a TaskHandle is valid by calling Driver.RecoverTask
 // and sets the driver handle. If the TaskHandle is not valid, DestroyTask is
-// called.
-func (tr *TaskRunner) restoreHandle(taskHandle *drivers.TaskHandle, net *drivers.DriverNetwork) (success bool) {
+// called and the recovery error is returned.
+func (tr *TaskRunner) restoreHandle(taskHandle *drivers.TaskHandle, net *drivers.DriverNetwork) error {
 	// Ensure handle is well-formed
 	if taskHandle.Config == nil {
-		return true
+		return nil
 	}
 
-	if err := tr.driver.RecoverTask(taskHandle); err != nil {
+	if recoverErr := tr.driver.RecoverTask(taskHandle); recoverErr != nil {
 		if tr.TaskState().State != structs.TaskStateRunning {
 			// RecoverTask should fail if the Task wasn't running
-			return true
+			return nil
 		}
 
 		tr.logger.Error("error recovering task; cleaning up",
-			"error", err, "task_id", taskHandle.Config.ID)
+			"error", recoverErr, "task_id", taskHandle.Config.ID)
 
 		// Try to cleanup any existing task state in the plugin before restarting
 		if err := tr.driver.DestroyTask(taskHandle.Config.ID, true); err != nil {
@@ -1183,12 +1256,12 @@ func (tr *TaskRunner) restoreHandle(taskHandle *drivers.TaskHandle, net *drivers
 
 		}
 
-		return false
+		return recoverErr
 	}
 
 	// Update driver handle on task runner
 	tr.setDriverHandle(NewDriverHandle(tr.driver, taskHandle.Config.ID, tr.Task(), tr.clientConfig.MaxKillTimeout, net))
-	return true
+	return nil
 }
 
 // UpdateState sets the task runners allocation state and triggers a server
@@ -1439,6 +1512,8 @@ func (tr *TaskRunner) setGaugeForMemory(ru *cstructs.TaskResourceUsage) {
 	publishMetric(ms.MaxUsage, "max_usage", "Max Usage")
 	publishMetric(ms.KernelUsage, "kernel_usage", "Kernel Usage")
 	publishMetric(ms.KernelMaxUsage, "kernel_max_usage", "Kernel Max Usage")
+	publishMetric(ms.Limit, "limit", "Limit")
+	publishMetric(ms.Reservation, "reservation", "Reservation")
 	if allocatedMem > 0 {
 		metrics.SetGaugeWithLabels([]string{"client", "allocs", "memory", "allocated"},
 			allocatedMem, tr.baseLabels)
@@ -1518,6 +1593,17 @@ func (tr *TaskRunner) TaskExecHandler() drivermanager.TaskExecHandler {
 	return handle.ExecStreaming
 }
 
+// TemplateStatus returns the rendering state of the templates of the task, or
+// nil if the task has no templates being rendered.
+func (tr *TaskRunner) TemplateStatus() []*cstructs.TemplateStatus {
+	for _, hook := range tr.runnerHooks {
+		if h, ok := hook.(*templateHook); ok {
+			return h.Status()
+		}
+	}
+	return nil
+}
+
 func (tr *TaskRunner) DriverCapabilities() (*drivers.Capabilities, error) {
 	return tr.driver.Capabilities()
 }
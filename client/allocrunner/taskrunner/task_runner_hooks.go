@@ -65,9 +65,48 @@ func (tr *TaskRunner) initHooks() {
 		newLogMonHook(tr, hookLogger),
 		newDispatchHook(alloc, hookLogger),
 		newVolumeHook(tr, hookLogger),
+		newPeersHook(tr, hookLogger),
+	}
+
+	// If the task enables the task API, serve it on a socket in the task's
+	// secrets directory.
+	if task.Identity != nil && task.Identity.TaskAPI {
+		tr.runnerHooks = append(tr.runnerHooks, newTaskAPIHook(&taskAPIHookConfig{
+			runner:   tr,
+			rpc:      tr.rpcClient,
+			taskName: task.Name,
+			node:     tr.clientConfig.Node,
+			region:   tr.clientConfig.Region,
+			logger:   hookLogger,
+		}))
+	}
+
+	// If the client has environment providers, add the hook. It runs
+	// before the artifact and template hooks so that they can interpolate
+	// the variables it sets.
+	if tr.envProviders.Enabled() {
+		tr.runnerHooks = append(tr.runnerHooks, newEnvProviderHook(alloc, tr.envProviders, hookLogger))
+	}
+
+	tr.runnerHooks = append(tr.runnerHooks,
 		newArtifactHook(tr, tr.getter, hookLogger),
 		newStatsHook(tr, tr.clientConfig.StatsCollectionInterval, hookLogger),
 		newDeviceHook(tr.devicemanager, hookLogger),
+	)
+
+	// If the driver streams task logs over the plugin RPC, add the hook
+	// relaying them to logmon.
+	if tr.driverCapabilities.LogStreaming {
+		if d, ok := tr.driver.(drivers.LogStreamingDriver); ok {
+			tr.runnerHooks = append(tr.runnerHooks, newLogStreamHook(tr, d, hookLogger))
+		}
+	}
+
+	// If the driver reports the health of tasks, add the hook acting on it.
+	if tr.driverCapabilities.TaskHealth {
+		if d, ok := tr.driver.(drivers.TaskHealthDriver); ok {
+			tr.runnerHooks = append(tr.runnerHooks, newTaskHealthHook(tr, d, hookLogger))
+		}
 	}
 
 	// If the task has a CSI stanza, add the hook.
@@ -106,6 +145,7 @@ func (tr *TaskRunner) initHooks() {
 
 	// If there are templates is enabled, add the hook
 	if len(task.Templates) != 0 {
+		uid, gid := driverTemplateOwner(tr.clientConfig.Node, task.Driver)
 		tr.runnerHooks = append(tr.runnerHooks, newTemplateHook(&templateHookConfig{
 			logger:          hookLogger,
 			lifecycle:       tr,
@@ -115,6 +155,8 @@ func (tr *TaskRunner) initHooks() {
 			envBuilder:      tr.envBuilder,
 			consulNamespace: consulNamespace,
 			nomadNamespace:  tr.alloc.Job.Namespace,
+			uid:             uid,
+			gid:             gid,
 		}))
 	}
 
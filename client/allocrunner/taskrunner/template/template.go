@@ -13,12 +13,14 @@ import (
 	"time"
 
 	ctconf "github.com/hashicorp/consul-template/config"
+	dep "github.com/hashicorp/consul-template/dependency"
 	"github.com/hashicorp/consul-template/manager"
 	"github.com/hashicorp/consul-template/signals"
 	envparse "github.com/hashicorp/go-envparse"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -36,6 +38,12 @@ const (
 	// DefaultMaxTemplateEventRate is the default maximum rate at which a
 	// template event should be fired.
 	DefaultMaxTemplateEventRate = 3 * time.Second
+
+	// renderRetryBaseBackoff and renderRetryMaxBackoff bound the wait before
+	// rendering the templates again after a render error, when the templates
+	// allow retrying.
+	renderRetryBaseBackoff = 1 * time.Second
+	renderRetryMaxBackoff  = 1 * time.Minute
 )
 
 var (
@@ -64,6 +72,22 @@ type TaskTemplateManager struct {
 	// shutdown marks whether the manager has been shutdown
 	shutdown     bool
 	shutdownLock sync.Mutex
+
+	// degradedSince is the time the templates first failed to render since
+	// they last rendered, or zero if they render. The following fields track
+	// the retries while degraded.
+	degradedSince time.Time
+	renderRetries int
+
+	// lastRenderErr is the last error of the runner, reported by Status.
+	lastRenderErr     error
+	lastRenderErrLock sync.Mutex
+
+	// retryTimer fires when the runner should be restarted to render the
+	// templates again, and staleTimer when the templates failed to render for
+	// longer than their render_max_stale.
+	retryTimer *time.Timer
+	staleTimer *time.Timer
 }
 
 // TaskTemplateManagerConfig is used to configure an instance of the
@@ -108,6 +132,11 @@ type TaskTemplateManagerConfig struct {
 
 	// NomadToken is the Nomad token or identity claim for the task
 	NomadToken string
+
+	// UID and GID are the host user and group rendered templates are owned
+	// by. If nil, templates are owned by the user running the client.
+	UID *int
+	GID *int
 }
 
 // Validate validates the configuration.
@@ -228,8 +257,9 @@ func (tm *TaskTemplateManager) run() {
 	// Unblock the task
 	close(tm.config.UnblockCh)
 
-	// If all our templates are change mode no-op, then we can exit here
-	if tm.allTemplatesNoop() {
+	// If all our templates are change mode no-op, then we can exit here,
+	// unless render errors must be handled to retry rendering
+	if tm.allTemplatesNoop() && tm.renderErrorPolicy() == structs.TemplateOnRenderErrorFail {
 		return
 	}
 
@@ -264,10 +294,15 @@ WAIT:
 				continue
 			}
 
-			tm.config.Lifecycle.Kill(context.Background(),
-				structs.NewTaskEvent(structs.TaskKilling).
-					SetFailsTask().
-					SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
+			if tm.handleRenderError(err) && tm.canKeepLast() {
+				tm.config.Events.EmitEvent(structs.NewTaskEvent(structs.TaskTemplateDegraded).
+					SetDisplayMessage("Starting task with the templates rendered by a previous run"))
+				break WAIT
+			}
+		case <-timerCh(tm.retryTimer):
+			tm.retryRender()
+		case <-timerCh(tm.staleTimer):
+			tm.failStale()
 		case <-tm.runner.TemplateRenderedCh():
 			// A template has been rendered, figure out what to do
 			events := tm.runner.RenderEvents()
@@ -295,6 +330,7 @@ WAIT:
 				tm.onTemplateRendered(handledRenders, time.Time{})
 			}
 
+			tm.renderRecovered()
 			break WAIT
 		case <-tm.runner.RenderEventCh():
 			events := tm.runner.RenderEvents()
@@ -378,19 +414,279 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 				continue
 			}
 
-			tm.config.Lifecycle.Kill(context.Background(),
-				structs.NewTaskEvent(structs.TaskKilling).
-					SetFailsTask().
-					SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
+			tm.handleRenderError(err)
+		case <-timerCh(tm.retryTimer):
+			tm.retryRender()
+		case <-timerCh(tm.staleTimer):
+			tm.failStale()
+		case <-tm.runner.RenderEventCh():
+			if tm.allRendered() {
+				tm.renderRecovered()
+			}
 		case <-tm.runner.TemplateRenderedCh():
 			tm.onTemplateRendered(handledRenders, allRenderedTime)
 		}
 	}
 }
 
+// renderErrorPolicy returns the action to take when the templates fail to
+// render. A single runner renders all the templates of the task, so rendering
+// is only retried if every template allows it, and the task only starts with
+// the templates rendered by a previous run if every template is keep_last.
+func (tm *TaskTemplateManager) renderErrorPolicy() string {
+	policy := structs.TemplateOnRenderErrorKeepLast
+	for _, tmpl := range tm.config.Templates {
+		switch tmpl.OnRenderError {
+		case structs.TemplateOnRenderErrorRetry:
+			policy = structs.TemplateOnRenderErrorRetry
+		case structs.TemplateOnRenderErrorKeepLast:
+		default:
+			return structs.TemplateOnRenderErrorFail
+		}
+	}
+	return policy
+}
+
+// renderMaxStale returns the smallest render_max_stale of the templates, or
+// zero if none is set.
+func (tm *TaskTemplateManager) renderMaxStale() time.Duration {
+	var maxStale time.Duration
+	for _, tmpl := range tm.config.Templates {
+		if tmpl.RenderMaxStale > 0 && (maxStale == 0 || tmpl.RenderMaxStale < maxStale) {
+			maxStale = tmpl.RenderMaxStale
+		}
+	}
+	return maxStale
+}
+
+// handleRenderError handles an error of the runner, which stops rendering the
+// templates on errors. Depending on the render error policy of the templates,
+// it either kills the task or schedules the runner to be restarted, in which
+// case it returns true.
+func (tm *TaskTemplateManager) handleRenderError(err error) bool {
+	tm.lastRenderErrLock.Lock()
+	tm.lastRenderErr = err
+	tm.lastRenderErrLock.Unlock()
+
+	if tm.renderErrorPolicy() == structs.TemplateOnRenderErrorFail {
+		tm.config.Lifecycle.Kill(context.Background(),
+			structs.NewTaskEvent(structs.TaskKilling).
+				SetFailsTask().
+				SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
+		return false
+	}
+
+	if tm.degradedSince.IsZero() {
+		tm.degradedSince = time.Now()
+		if maxStale := tm.renderMaxStale(); maxStale > 0 {
+			tm.staleTimer = time.NewTimer(maxStale)
+		}
+	}
+
+	backoff := renderRetryBaseBackoff << tm.renderRetries
+	if backoff > renderRetryMaxBackoff || backoff <= 0 {
+		backoff = renderRetryMaxBackoff
+	} else {
+		tm.renderRetries++
+	}
+	tm.retryTimer = time.NewTimer(backoff)
+
+	tm.config.Events.EmitEvent(structs.NewTaskEvent(structs.TaskTemplateDegraded).
+		SetDisplayMessage(fmt.Sprintf("Template failed, retrying in %v: %v", backoff, err)))
+	return true
+}
+
+// canKeepLast returns whether the task may start with the templates rendered
+// by a previous run of the task, because every template is keep_last and was
+// rendered before.
+func (tm *TaskTemplateManager) canKeepLast() bool {
+	if tm.renderErrorPolicy() != structs.TemplateOnRenderErrorKeepLast {
+		return false
+	}
+
+	taskEnv := tm.config.EnvBuilder.Build()
+	for _, tmpl := range tm.config.Templates {
+		dest, _ := taskEnv.ClientPath(tmpl.DestPath, true)
+		if _, err := os.Stat(dest); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// retryRender restarts the runner to render the templates again.
+func (tm *TaskTemplateManager) retryRender() {
+	tm.retryTimer = nil
+
+	runner, lookup, err := templateRunner(tm.config)
+	if err != nil {
+		tm.config.Lifecycle.Kill(context.Background(),
+			structs.NewTaskEvent(structs.TaskKilling).
+				SetFailsTask().
+				SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
+		return
+	}
+
+	tm.shutdownLock.Lock()
+	defer tm.shutdownLock.Unlock()
+	if tm.shutdown {
+		return
+	}
+
+	tm.runner.Stop()
+	tm.runner = runner
+	tm.lookup = lookup
+	go tm.runner.Start()
+}
+
+// failStale kills the task once the templates failed to render for longer
+// than their render_max_stale.
+func (tm *TaskTemplateManager) failStale() {
+	tm.staleTimer = nil
+	tm.config.Lifecycle.Kill(context.Background(),
+		structs.NewTaskEvent(structs.TaskKilling).
+			SetFailsTask().
+			SetDisplayMessage(fmt.Sprintf("Template failed to render for longer than %v: %v",
+				tm.renderMaxStale(), tm.lastRenderErr)))
+}
+
+// allRendered returns whether the runner rendered all the templates.
+func (tm *TaskTemplateManager) allRendered() bool {
+	events := tm.runner.RenderEvents()
+	if len(events) < len(tm.lookup) {
+		return false
+	}
+	for _, event := range events {
+		if event.LastWouldRender.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// renderRecovered clears the degraded state once the templates render again.
+func (tm *TaskTemplateManager) renderRecovered() {
+	if tm.degradedSince.IsZero() {
+		return
+	}
+
+	tm.config.Events.EmitEvent(structs.NewTaskEvent(structs.TaskTemplateRecovered).
+		SetDisplayMessage(fmt.Sprintf("Template rendered after failing for %v",
+			time.Since(tm.degradedSince).Round(time.Second))))
+
+	if tm.staleTimer != nil {
+		tm.staleTimer.Stop()
+	}
+	tm.lastRenderErrLock.Lock()
+	tm.lastRenderErr = nil
+	tm.lastRenderErrLock.Unlock()
+	tm.degradedSince = time.Time{}
+	tm.renderRetries = 0
+	tm.staleTimer = nil
+}
+
+// Status returns the rendering state of the templates and of the dependencies
+// they watch.
+func (tm *TaskTemplateManager) Status() []*cstructs.TemplateStatus {
+	tm.shutdownLock.Lock()
+	runner, lookup := tm.runner, tm.lookup
+	tm.shutdownLock.Unlock()
+
+	if runner == nil {
+		return nil
+	}
+
+	tm.lastRenderErrLock.Lock()
+	renderErr := tm.lastRenderErr
+	tm.lastRenderErrLock.Unlock()
+
+	events := runner.RenderEvents()
+	var status []*cstructs.TemplateStatus
+	for id, tmpls := range lookup {
+		for _, tmpl := range tmpls {
+			status = append(status, templateStatus(tmpl, events[id], renderErr))
+		}
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].DestPath < status[j].DestPath
+	})
+	return status
+}
+
+// templateStatus returns the state of a template from its last render event,
+// which is nil if the runner didn't evaluate the template yet. Errors of the
+// runner fetching a dependency are prefixed with the dependency, so they are
+// reported on the dependency.
+func templateStatus(tmpl *structs.Template, event *manager.RenderEvent, renderErr error) *cstructs.TemplateStatus {
+	status := &cstructs.TemplateStatus{
+		DestPath: tmpl.DestPath,
+	}
+
+	var renderErrReported bool
+	if event != nil {
+		status.Rendered = !event.LastWouldRender.IsZero()
+		status.LastRendered = event.LastDidRender
+		status.LastUpdate = event.UpdatedAt
+		if event.Error != nil {
+			status.Error = event.Error.Error()
+		}
+
+		var deps []dep.Dependency
+		if event.UsedDeps != nil {
+			deps = event.UsedDeps.List()
+		}
+		for _, d := range deps {
+			dependency := &cstructs.TemplateDependency{
+				Type:   dependencyType(d.Type()),
+				Name:   d.String(),
+				Status: cstructs.TemplateDependencyStatusOK,
+			}
+			if event.UnwatchedDeps != nil && event.UnwatchedDeps.Get(d.String()) != nil {
+				dependency.Status = cstructs.TemplateDependencyStatusUnwatched
+			} else if event.MissingDeps != nil && event.MissingDeps.Get(d.String()) != nil {
+				dependency.Status = cstructs.TemplateDependencyStatusMissing
+			}
+			if renderErr != nil && strings.HasPrefix(renderErr.Error(), d.String()) {
+				dependency.Error = renderErr.Error()
+				renderErrReported = true
+			}
+			status.Dependencies = append(status.Dependencies, dependency)
+		}
+	}
+
+	if renderErr != nil && !renderErrReported && status.Error == "" {
+		status.Error = renderErr.Error()
+	}
+	return status
+}
+
+// dependencyType returns the name of the source of a dependency.
+func dependencyType(t dep.Type) string {
+	switch t {
+	case dep.TypeConsul:
+		return "consul"
+	case dep.TypeVault:
+		return "vault"
+	case dep.TypeNomad:
+		return "nomad"
+	case dep.TypeLocal:
+		return "local"
+	default:
+		return "unknown"
+	}
+}
+
+// timerCh returns the channel of the timer, or nil if the timer isn't set.
+func timerCh(t *time.Timer) <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.C
+}
+
 func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time.Time, allRenderedTime time.Time) {
 
-	var handling []string
+	var handling, paths []string
 	signals := make(map[string]struct{})
 	restart := false
 	var splay time.Duration
@@ -443,6 +739,7 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 			if tmpl.Splay > splay {
 				splay = tmpl.Splay
 			}
+			paths = append(paths, tmpl.DestPath)
 		}
 
 		handling = append(handling, id)
@@ -469,12 +766,16 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 		if restart {
 			tm.config.Lifecycle.Restart(context.Background(),
 				structs.NewTaskEvent(structs.TaskRestartSignal).
+					SetTemplatePaths(paths).
 					SetDisplayMessage("Template with change_mode restart re-rendered"), false)
 		} else if len(signals) != 0 {
 			var mErr multierror.Error
 			for signal := range signals {
 				s := tm.signals[signal]
-				event := structs.NewTaskEvent(structs.TaskSignaling).SetTaskSignal(s).SetDisplayMessage("Template re-rendered")
+				event := structs.NewTaskEvent(structs.TaskSignaling).
+					SetTaskSignal(s).
+					SetTemplatePaths(paths).
+					SetDisplayMessage("Template re-rendered")
 				if err := tm.config.Lifecycle.Signal(event, signal); err != nil {
 					_ = multierror.Append(&mErr, err)
 				}
@@ -489,6 +790,7 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 				tm.config.Lifecycle.Kill(context.Background(),
 					structs.NewTaskEvent(structs.TaskKilling).
 						SetFailsTask().
+						SetTemplatePaths(paths).
 						SetDisplayMessage(fmt.Sprintf("Template failed to send signals %v: %v", flat, err)))
 			}
 		}
@@ -626,6 +928,10 @@ func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[*ctconf.Templa
 			m := os.FileMode(v)
 			ct.Perms = &m
 		}
+
+		// Set the owner on every render, including re-renders
+		ct.Uid = config.UID
+		ct.Gid = config.GID
 		ct.Finalize()
 
 		ctmpls[ct] = tmpl
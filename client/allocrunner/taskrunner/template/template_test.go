@@ -24,8 +24,10 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/bufconndialer"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -1562,6 +1564,45 @@ func TestTaskTemplateManager_Config_VaultNamespace_TaskOverride(t *testing.T) {
 	assert.Equal(overriddenNS, *ctconf.Vault.Namespace, "Vault Namespace Value")
 }
 
+// TestTaskTemplateManager_Config_Owner asserts the owner of rendered templates
+// is propagated to consul-template's configuration.
+func TestTaskTemplateManager_Config_Owner(t *testing.T) {
+	ci.Parallel(t)
+
+	c := config.DefaultConfig()
+	c.Node = mock.Node()
+	c.TemplateConfig.DisableSandbox = true
+
+	alloc := mock.Alloc()
+	config := &TaskTemplateManagerConfig{
+		ClientConfig: c,
+		Templates: []*structs.Template{{
+			EmbeddedTmpl: "hello",
+			DestPath:     "local/hello.txt",
+		}},
+		EnvBuilder: taskenv.NewBuilder(c.Node, alloc, alloc.Job.TaskGroups[0].Tasks[0], c.Region),
+		UID:        helper.IntToPtr(100000),
+		GID:        helper.IntToPtr(100001),
+	}
+
+	ctmplMapping, err := parseTemplateConfigs(config)
+	require.NoError(t, err)
+	require.Len(t, ctmplMapping, 1)
+	for ct := range ctmplMapping {
+		require.Equal(t, "100000", *ct.User)
+		require.Equal(t, "100001", *ct.Group)
+	}
+
+	// Templates are owned by the client by default
+	config.UID, config.GID = nil, nil
+	ctmplMapping, err = parseTemplateConfigs(config)
+	require.NoError(t, err)
+	for ct := range ctmplMapping {
+		require.Nil(t, ct.User)
+		require.Nil(t, ct.Group)
+	}
+}
+
 // TestTaskTemplateManager_Escapes asserts that when sandboxing is enabled
 // interpolated paths are not incorrectly treated as escaping the alloc dir.
 func TestTaskTemplateManager_Escapes(t *testing.T) {
@@ -1911,6 +1952,187 @@ WAIT_LOOP:
 	}
 }
 
+// TestTaskTemplateManager_OnRenderError_Retry asserts that a template with
+// on_render_error retry blocks the task without killing it while it fails to
+// render, and renders again once the error is resolved.
+func TestTaskTemplateManager_OnRenderError_Retry(t *testing.T) {
+	ci.Parallel(t)
+
+	harness := newTestHarness(t, nil, false, false)
+	src := filepath.Join(harness.taskDir, "src.txt")
+	require.NoError(t, ioutil.WriteFile(src, []byte("abc"), 0644))
+
+	harness.templates = []*structs.Template{{
+		EmbeddedTmpl:  fmt.Sprintf(`{{ file "%s" | parseInt }}`, src),
+		DestPath:      "my.tmpl",
+		ChangeMode:    structs.TemplateChangeModeNoop,
+		OnRenderError: structs.TemplateOnRenderErrorRetry,
+	}}
+	harness.start(t)
+	defer harness.stop()
+
+	select {
+	case <-harness.mockHooks.UnblockCh:
+		t.Fatalf("Task unblock should have not have been called")
+	case e := <-harness.mockHooks.KillCh:
+		t.Fatalf("Task should not have been killed: %v", e.DisplayMessage)
+	case e := <-harness.mockHooks.EmitEventCh:
+		require.Equal(t, structs.TaskTemplateDegraded, e.Type)
+		require.Contains(t, e.DisplayMessage, "retrying in 1s")
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("timeout")
+	}
+
+	// Fix the template source; the next retry renders the template
+	require.NoError(t, ioutil.WriteFile(src, []byte("42"), 0644))
+
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case e := <-harness.mockHooks.KillCh:
+		t.Fatalf("Task should not have been killed: %v", e.DisplayMessage)
+	case <-time.After(time.Duration(10*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(harness.taskDir, "my.tmpl"))
+	require.NoError(t, err)
+	require.Equal(t, "42", string(raw))
+
+	select {
+	case e := <-harness.mockHooks.EmitEventCh:
+		require.Equal(t, structs.TaskTemplateRecovered, e.Type)
+	default:
+		t.Fatalf("Template recovered event should have been emitted")
+	}
+}
+
+// TestTaskTemplateManager_OnRenderError_KeepLast asserts that a template with
+// on_render_error keep_last lets the task start with the template rendered by
+// a previous run when it fails to render.
+func TestTaskTemplateManager_OnRenderError_KeepLast(t *testing.T) {
+	ci.Parallel(t)
+
+	file := "my.tmpl"
+	template := &structs.Template{
+		EmbeddedTmpl:  `{{ "abc" | parseInt }}`,
+		DestPath:      file,
+		ChangeMode:    structs.TemplateChangeModeNoop,
+		OnRenderError: structs.TemplateOnRenderErrorKeepLast,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	path := filepath.Join(harness.taskDir, file)
+	require.NoError(t, ioutil.WriteFile(path, []byte("last"), 0644))
+
+	harness.start(t)
+	defer harness.stop()
+
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case e := <-harness.mockHooks.KillCh:
+		t.Fatalf("Task should not have been killed: %v", e.DisplayMessage)
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	select {
+	case e := <-harness.mockHooks.EmitEventCh:
+		require.Equal(t, structs.TaskTemplateDegraded, e.Type)
+		require.Contains(t, e.DisplayMessage, "rendered by a previous run")
+	default:
+		t.Fatalf("Template degraded event should have been emitted")
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "last", string(raw))
+}
+
+// TestTaskTemplateManager_OnRenderError_MaxStale asserts that the task is
+// killed once its templates failed to render for longer than their
+// render_max_stale.
+func TestTaskTemplateManager_OnRenderError_MaxStale(t *testing.T) {
+	ci.Parallel(t)
+
+	template := &structs.Template{
+		EmbeddedTmpl:   `{{ "abc" | parseInt }}`,
+		DestPath:       "my.tmpl",
+		ChangeMode:     structs.TemplateChangeModeNoop,
+		OnRenderError:  structs.TemplateOnRenderErrorRetry,
+		RenderMaxStale: 500 * time.Millisecond,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	harness.start(t)
+	defer harness.stop()
+
+	select {
+	case <-harness.mockHooks.UnblockCh:
+		t.Fatalf("Task unblock should have not have been called")
+	case e := <-harness.mockHooks.KillCh:
+		require.True(t, e.FailsTask)
+		require.Contains(t, e.DisplayMessage, "Template failed to render for longer than 500ms")
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("timeout")
+	}
+}
+
+// TestTaskTemplateManager_Status asserts that the state of the templates
+// reports the dependencies they watch, whether their data was fetched or not.
+func TestTaskTemplateManager_Status(t *testing.T) {
+	ci.Parallel(t)
+
+	harness := newTestHarness(t, nil, false, false)
+	src := filepath.Join(harness.taskDir, "src.txt")
+	require.NoError(t, ioutil.WriteFile(src, []byte("abc"), 0644))
+
+	// Nothing serves the connections to the Nomad API, so the service is
+	// never fetched.
+	ln, dialer := bufconndialer.New()
+	defer ln.Close()
+	harness.config.TemplateDialer = dialer
+
+	harness.templates = []*structs.Template{{
+		EmbeddedTmpl: fmt.Sprintf(`{{ file "%s" }}{{ range nomadService "web" }}{{ .Address }}{{ end }}`, src),
+		DestPath:     "my.tmpl",
+		ChangeMode:   structs.TemplateChangeModeNoop,
+	}}
+	harness.start(t)
+	defer harness.stop()
+
+	var status []*cstructs.TemplateStatus
+	testutil.WaitForResult(func() (bool, error) {
+		status = harness.manager.Status()
+		if len(status) != 1 || len(status[0].Dependencies) != 2 {
+			return false, fmt.Errorf("expected one template with two dependencies")
+		}
+		for _, dep := range status[0].Dependencies {
+			if dep.Type == "local" && dep.Status != cstructs.TemplateDependencyStatusOK {
+				return false, fmt.Errorf("expected file to be fetched: %#v", dep)
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	require.Equal(t, "my.tmpl", status[0].DestPath)
+	require.False(t, status[0].Rendered)
+	require.True(t, status[0].LastRendered.IsZero())
+	require.False(t, status[0].LastUpdate.IsZero())
+	require.Empty(t, status[0].Error)
+
+	deps := status[0].Dependencies
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Type < deps[j].Type })
+	require.Equal(t, &cstructs.TemplateDependency{
+		Type:   "local",
+		Name:   fmt.Sprintf("file(%s)", src),
+		Status: cstructs.TemplateDependencyStatusOK,
+	}, deps[0])
+	require.Equal(t, "nomad", deps[1].Type)
+	require.Equal(t, cstructs.TemplateDependencyStatusMissing, deps[1].Status)
+}
+
 // TestTaskTemplateManager_ClientTemplateConfig_Set asserts that all client level
 // configuration is accurately mapped from the client to the TaskTemplateManager
 // and that any operator defined boundaries are enforced.
@@ -3,6 +3,7 @@ package taskrunner
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 
 	log "github.com/hashicorp/go-hclog"
@@ -10,6 +11,7 @@ import (
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/template"
 	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -42,6 +44,11 @@ type templateHookConfig struct {
 
 	// nomadNamespace is the job's Nomad namespace
 	nomadNamespace string
+
+	// uid and gid are the host user and group templates are owned by, or
+	// nil to keep the owner of the client
+	uid *int
+	gid *int
 }
 
 type templateHook struct {
@@ -131,6 +138,8 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 		MaxTemplateEventRate: template.DefaultMaxTemplateEventRate,
 		NomadNamespace:       h.config.nomadNamespace,
 		NomadToken:           h.nomadToken,
+		UID:                  h.config.uid,
+		GID:                  h.config.gid,
 	})
 	if err != nil {
 		h.logger.Error("failed to create template manager", "error", err)
@@ -153,6 +162,18 @@ func (h *templateHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest
 	return nil
 }
 
+// Status returns the rendering state of the templates of the task, or nil if
+// the templates are not being rendered.
+func (h *templateHook) Status() []*cstructs.TemplateStatus {
+	h.managerLock.Lock()
+	defer h.managerLock.Unlock()
+
+	if h.templateManager == nil {
+		return nil
+	}
+	return h.templateManager.Status()
+}
+
 // Handle new Vault token
 func (h *templateHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequest, resp *interfaces.TaskUpdateResponse) error {
 	h.managerLock.Lock()
@@ -187,3 +208,28 @@ func (h *templateHook) Update(ctx context.Context, req *interfaces.TaskUpdateReq
 
 	return nil
 }
+
+// driverTemplateOwner returns the host user and group that root in a
+// container of the driver is mapped to, as fingerprinted by the driver, so
+// that templates rendered for the task remain readable by it. It returns nil
+// if the driver doesn't run tasks in a user namespace.
+func driverTemplateOwner(node *structs.Node, driver string) (*int, *int) {
+	if node == nil {
+		return nil, nil
+	}
+	info, ok := node.Drivers[driver]
+	if !ok || info == nil {
+		return nil, nil
+	}
+
+	prefix := "driver." + driver + ".userns_root_"
+	uid, err := strconv.Atoi(info.Attributes[prefix+"uid"])
+	if err != nil {
+		return nil, nil
+	}
+	gid, err := strconv.Atoi(info.Attributes[prefix+"gid"])
+	if err != nil {
+		return nil, nil
+	}
+	return &uid, &gid
+}
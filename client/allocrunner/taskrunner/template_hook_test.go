@@ -0,0 +1,38 @@
+package taskrunner
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateHook_DriverTemplateOwner(t *testing.T) {
+	ci.Parallel(t)
+
+	node := mock.Node()
+	node.Drivers["docker"] = &structs.DriverInfo{
+		Attributes: map[string]string{
+			"driver.docker":                 "true",
+			"driver.docker.userns_root_uid": "100000",
+			"driver.docker.userns_root_gid": "100001",
+		},
+		Detected: true,
+		Healthy:  true,
+	}
+
+	uid, gid := driverTemplateOwner(node, "docker")
+	require.Equal(t, 100000, *uid)
+	require.Equal(t, 100001, *gid)
+
+	// Drivers that don't report a user namespace keep the client as owner
+	uid, gid = driverTemplateOwner(node, "exec")
+	require.Nil(t, uid)
+	require.Nil(t, gid)
+
+	uid, gid = driverTemplateOwner(nil, "docker")
+	require.Nil(t, uid)
+	require.Nil(t, gid)
+}
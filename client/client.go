@@ -29,12 +29,15 @@ import (
 	consulApi "github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
+	"github.com/hashicorp/nomad/client/envprovider"
 	"github.com/hashicorp/nomad/client/fingerprint"
 	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/client/mesh"
 	"github.com/hashicorp/nomad/client/pluginmanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/csimanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
+	"github.com/hashicorp/nomad/client/pluginmanager/fingerprintmanager"
 	"github.com/hashicorp/nomad/client/servers"
 	"github.com/hashicorp/nomad/client/serviceregistration"
 	"github.com/hashicorp/nomad/client/serviceregistration/checks/checkstore"
@@ -108,6 +111,11 @@ const (
 	// the status of the allocation
 	allocSyncRetryIntv = 5 * time.Second
 
+	// allocDeltaProbeIntv is the interval after which delta allocation
+	// updates are tried again once the servers didn't support them, so that
+	// they are used after the servers are upgraded.
+	allocDeltaProbeIntv = 5 * time.Minute
+
 	// defaultConnectLogLevel is the log level set in the node meta by default
 	// to be used by Consul Connect sidecar tasks.
 	defaultConnectLogLevel = "info"
@@ -157,6 +165,8 @@ type AllocRunner interface {
 	Signal(taskName, signal string) error
 	GetTaskEventHandler(taskName string) drivermanager.EventHandler
 	PersistState() error
+	RestoreStatus() (int, map[string]error)
+	VerifyRecoverable() (int, map[string]error)
 
 	RestartTask(taskName string, taskEvent *structs.TaskEvent) error
 	RestartAll(taskEvent *structs.TaskEvent) error
@@ -164,6 +174,7 @@ type AllocRunner interface {
 
 	GetTaskExecHandler(taskName string) drivermanager.TaskExecHandler
 	GetTaskDriverCapabilities(taskName string) (*drivers.Capabilities, error)
+	TemplateStatus(taskName string) (map[string][]*cstructs.TemplateStatus, error)
 }
 
 // Client is used to implement the client interaction with Nomad. Clients
@@ -329,6 +340,13 @@ type Client struct {
 
 	// getter is an interface for retrieving artifacts.
 	getter cinterfaces.ArtifactGetter
+
+	// envProviders runs the environment providers for tasks.
+	envProviders *envprovider.Manager
+
+	// meshHooks runs the hooks integrating task groups with external service
+	// meshes.
+	meshHooks *mesh.Manager
 }
 
 var (
@@ -388,6 +406,8 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		serversContactedOnce: sync.Once{},
 		cpusetManager:        cgutil.CreateCPUSetManager(cfg.CgroupParent, logger),
 		getter:               getter.NewGetter(cfg.Artifact),
+		envProviders:         envprovider.NewManager(logger, cfg.EnvProviders),
+		meshHooks:            mesh.NewManager(logger, cfg.MeshHooks),
 		EnterpriseClient:     newEnterpriseClient(logger),
 	}
 
@@ -493,6 +513,15 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	c.devicemanager = devManager
 	c.pluginManagers.RegisterAndRun(devManager)
 
+	// Setup the fingerprint plugin manager
+	fpPluginConfig := &fingerprintmanager.Config{
+		Logger:       c.logger,
+		Loader:       c.configCopy.PluginSingletonLoader,
+		PluginConfig: c.configCopy.NomadPluginConfig(),
+		Updater:      c.updateNodeFromFingerprint,
+	}
+	c.pluginManagers.RegisterAndRun(fingerprintmanager.New(fpPluginConfig))
+
 	// Set up the service registration wrapper using the Consul and Nomad
 	// implementations. The Nomad implementation is only ever used on the
 	// client, so we do that here rather than within the agent.
@@ -812,6 +841,17 @@ func (c *Client) Shutdown() error {
 			arGroup.AddCh(ar.DestroyCh())
 		}
 	} else {
+		// Flush the state and warn about running tasks the client won't be
+		// able to reattach to when it restarts
+		if resp, err := c.PrepareUpgrade(); err != nil {
+			c.logger.Error("failed to verify tasks are recoverable", "error", err)
+		} else {
+			for _, f := range resp.Unrecoverable {
+				c.logger.Warn("task won't be reattached to after restart",
+					"alloc_id", f.AllocID, "task", f.TaskName, "error", f.Error)
+			}
+		}
+
 		// In normal mode call shutdown
 		for _, ar := range c.getAllocRunners() {
 			ar.Shutdown()
@@ -1145,6 +1185,8 @@ func (c *Client) restoreState() error {
 		// Send to server with clientstatus=failed
 	}
 
+	report := &structs.NodeRestoreReport{}
+
 	// Load each alloc back
 	for _, alloc := range allocs {
 
@@ -1187,6 +1229,8 @@ func (c *Client) restoreState() error {
 			CheckStore:          c.checkStore,
 			RPCClient:           c,
 			Getter:              c.getter,
+			EnvProviders:        c.envProviders,
+			MeshHooks:           c.meshHooks,
 		}
 		c.configLock.RUnlock()
 
@@ -1194,6 +1238,7 @@ func (c *Client) restoreState() error {
 		if err != nil {
 			c.logger.Error("error running alloc", "error", err, "alloc_id", alloc.ID)
 			c.handleInvalidAllocs(alloc, err)
+			report.Failed = append(report.Failed, allocRestoreFailures(alloc, err)...)
 			continue
 		}
 
@@ -1204,9 +1249,20 @@ func (c *Client) restoreState() error {
 			ar.SetClientStatus(structs.AllocClientStatusFailed)
 			// Destroy the alloc runner since this is a failed restore
 			ar.Destroy()
+			report.Failed = append(report.Failed, allocRestoreFailures(alloc, err)...)
 			continue
 		}
 
+		reattached, failed := ar.RestoreStatus()
+		report.Reattached += reattached
+		for taskName, err := range failed {
+			report.Failed = append(report.Failed, &structs.NodeTaskRestoreFailure{
+				AllocID:  alloc.ID,
+				TaskName: taskName,
+				Error:    err.Error(),
+			})
+		}
+
 		// Maybe mark the alloc for halt on missing server heartbeats
 		if c.heartbeatStop.shouldStop(alloc) {
 			err = c.heartbeatStop.stopAlloc(alloc.ID)
@@ -1230,9 +1286,74 @@ func (c *Client) restoreState() error {
 		go ar.Run()
 	}
 	c.allocLock.Unlock()
+
+	// Report the tasks that couldn't be reattached to the servers
+	sortRestoreFailures(report.Failed)
+	report.RestoredAt = time.Now()
+	if len(report.Failed) > 0 {
+		c.logger.Warn("failed to reattach to tasks", "reattached", report.Reattached, "failed", len(report.Failed))
+	}
+
+	c.configLock.Lock()
+	c.config.Node.LastRestore = report
+	c.updateNodeLocked()
+	c.configLock.Unlock()
 	return nil
 }
 
+// allocRestoreFailures returns a restore failure for each task of an alloc
+// that couldn't be restored.
+func allocRestoreFailures(alloc *structs.Allocation, err error) []*structs.NodeTaskRestoreFailure {
+	var failures []*structs.NodeTaskRestoreFailure
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil {
+		for _, task := range tg.Tasks {
+			failures = append(failures, &structs.NodeTaskRestoreFailure{
+				AllocID:  alloc.ID,
+				TaskName: task.Name,
+				Error:    err.Error(),
+			})
+		}
+	}
+	return failures
+}
+
+// sortRestoreFailures sorts restore failures by alloc ID and task name.
+func sortRestoreFailures(failures []*structs.NodeTaskRestoreFailure) {
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].AllocID != failures[j].AllocID {
+			return failures[i].AllocID < failures[j].AllocID
+		}
+		return failures[i].TaskName < failures[j].TaskName
+	})
+}
+
+// PrepareUpgrade flushes the state of all allocations to the client state
+// database and verifies that a client restarted in place from it would
+// reattach to every running task.
+func (c *Client) PrepareUpgrade() (*cstructs.PrepareUpgradeResponse, error) {
+	if c.config.DevMode {
+		return nil, fmt.Errorf("client state isn't persisted in dev mode")
+	}
+	if err := c.saveState(); err != nil {
+		return nil, fmt.Errorf("failed to save client state: %v", err)
+	}
+
+	resp := &cstructs.PrepareUpgradeResponse{}
+	for allocID, ar := range c.getAllocRunners() {
+		recoverable, failed := ar.VerifyRecoverable()
+		resp.Recoverable += recoverable
+		for taskName, err := range failed {
+			resp.Unrecoverable = append(resp.Unrecoverable, &structs.NodeTaskRestoreFailure{
+				AllocID:  allocID,
+				TaskName: taskName,
+				Error:    err.Error(),
+			})
+		}
+	}
+	sortRestoreFailures(resp.Unrecoverable)
+	return resp, nil
+}
+
 // hasLocalState returns true if we have any other associated state
 // with alloc beyond the task itself
 //
@@ -1863,6 +1984,7 @@ func (c *Client) registerNode() error {
 	node := c.Node()
 	req := structs.NodeRegisterRequest{
 		Node:         node,
+		IntroToken:   c.config.IntroToken,
 		WriteRequest: structs.WriteRequest{Region: c.Region()},
 	}
 	var resp structs.NodeUpdateResponse
@@ -1892,9 +2014,12 @@ func (c *Client) registerNode() error {
 func (c *Client) updateNodeStatus() error {
 	start := time.Now()
 	req := structs.NodeUpdateStatusRequest{
-		NodeID:       c.NodeID(),
-		Status:       structs.NodeStatusReady,
-		WriteRequest: structs.WriteRequest{Region: c.Region()},
+		NodeID: c.NodeID(),
+		Status: structs.NodeStatusReady,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.Region(),
+			AuthToken: c.secretNodeID(),
+		},
 	}
 	var resp structs.NodeUpdateResponse
 	if err := c.RPC("Node.UpdateStatus", &req, &resp); err != nil {
@@ -2006,6 +2131,16 @@ func (c *Client) PutAllocation(alloc *structs.Allocation) error {
 func (c *Client) allocSync() {
 	syncTicker := time.NewTicker(allocSyncIntv)
 	updates := make(map[string]*structs.Allocation)
+
+	// acked holds the last update of each allocation acknowledged by the
+	// servers, so that the next update can be sent as a delta of it.
+	acked := make(map[string]*structs.Allocation)
+
+	// useDeltas is unset if the servers don't support delta updates yet,
+	// until they are probed again at deltasDisabledAt+allocDeltaProbeIntv.
+	useDeltas := true
+	var deltasDisabledAt time.Time
+
 	for {
 		select {
 		case <-c.shutdownCh:
@@ -2020,19 +2155,29 @@ func (c *Client) allocSync() {
 				continue
 			}
 
-			sync := make([]*structs.Allocation, 0, len(updates))
-			for _, alloc := range updates {
-				sync = append(sync, alloc)
+			var mismatched []string
+			var err error
+			if !useDeltas && time.Since(deltasDisabledAt) >= allocDeltaProbeIntv {
+				useDeltas = true
 			}
-
-			// Send to server.
-			args := structs.AllocUpdateRequest{
-				Alloc:        sync,
-				WriteRequest: structs.WriteRequest{Region: c.Region()},
+			if useDeltas {
+				mismatched, err = c.syncAllocDeltas(updates, acked)
+				if structs.IsErrUnknownMethod(err) || isErrUnknownRPCMethod(err) {
+					c.logger.Debug("servers don't support delta allocation updates")
+					useDeltas = false
+					deltasDisabledAt = time.Now()
+
+					// Updates sent in full aren't tracked, so the
+					// acknowledged updates would be stale by the time
+					// deltas are used again
+					for id := range acked {
+						delete(acked, id)
+					}
+				}
+			}
+			if !useDeltas {
+				err = c.syncAllocs(updates)
 			}
-
-			var resp structs.GenericResponse
-			err := c.RPC("Node.UpdateAlloc", &args, &resp)
 			if err != nil {
 				// Error updating allocations, do *not* clear
 				// updates and retry after backoff
@@ -2042,18 +2187,92 @@ func (c *Client) allocSync() {
 				continue
 			}
 
-			// Successfully updated allocs, reset map and ticker.
-			// Always reset ticker to give loop time to receive
-			// alloc updates. If the RPC took the ticker interval
-			// we may call it in a tight loop before draining
+			// Successfully updated allocs, track them as acknowledged
+			// unless their delta didn't apply, in which case they are sent
+			// in full with the next sync.
+			next := make(map[string]*structs.Allocation, len(updates))
+			if useDeltas {
+				for id, alloc := range updates {
+					acked[id] = alloc
+				}
+				for _, id := range mismatched {
+					delete(acked, id)
+					next[id] = updates[id]
+				}
+				c.pruneAckedAllocs(acked)
+			}
+
+			// Reset map and ticker. Always reset ticker to give loop
+			// time to receive alloc updates. If the RPC took the ticker
+			// interval we may call it in a tight loop before draining
 			// buffered updates.
-			updates = make(map[string]*structs.Allocation, len(updates))
+			updates = next
 			syncTicker.Stop()
 			syncTicker = time.NewTicker(allocSyncIntv)
 		}
 	}
 }
 
+// syncAllocs sends allocation updates to the servers in full.
+func (c *Client) syncAllocs(updates map[string]*structs.Allocation) error {
+	sync := make([]*structs.Allocation, 0, len(updates))
+	for _, alloc := range updates {
+		sync = append(sync, alloc)
+	}
+
+	args := structs.AllocUpdateRequest{
+		Alloc:        sync,
+		WriteRequest: structs.WriteRequest{Region: c.Region()},
+	}
+
+	var resp structs.GenericResponse
+	return c.RPC("Node.UpdateAlloc", &args, &resp)
+}
+
+// syncAllocDeltas sends allocation updates to the servers, as deltas of their
+// last acknowledged update when possible. It returns the IDs of the
+// allocations whose delta didn't apply to their status on the servers.
+func (c *Client) syncAllocDeltas(updates, acked map[string]*structs.Allocation) ([]string, error) {
+	args := structs.AllocDeltaUpdateRequest{
+		WriteRequest: structs.WriteRequest{Region: c.Region()},
+	}
+	for id, alloc := range updates {
+		if delta := structs.NewAllocDelta(acked[id], alloc); delta != nil {
+			args.Deltas = append(args.Deltas, delta)
+		} else {
+			args.Alloc = append(args.Alloc, alloc)
+		}
+	}
+
+	var resp structs.AllocDeltaUpdateResponse
+	if err := c.RPC("Node.UpdateAllocDelta", &args, &resp); err != nil {
+		return nil, err
+	}
+	if n := len(resp.Mismatched); n > 0 {
+		c.logger.Debug("allocation deltas mismatched, sending them in full", "num_allocs", n)
+	}
+	return resp.Mismatched, nil
+}
+
+// pruneAckedAllocs removes the acknowledged updates of the allocations that
+// are no longer running on the client.
+func (c *Client) pruneAckedAllocs(acked map[string]*structs.Allocation) {
+	c.allocLock.RLock()
+	defer c.allocLock.RUnlock()
+
+	for id := range acked {
+		if _, ok := c.allocs[id]; !ok {
+			delete(acked, id)
+		}
+	}
+}
+
+// isErrUnknownRPCMethod returns whether the error is due to the servers not
+// having the RPC method, such as when they run an older version.
+func isErrUnknownRPCMethod(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "can't find method")
+}
+
 // allocUpdates holds the results of receiving updated allocations from the
 // servers.
 type allocUpdates struct {
@@ -2526,6 +2745,8 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		CheckStore:          c.checkStore,
 		RPCClient:           c,
 		Getter:              c.getter,
+		EnvProviders:        c.envProviders,
+		MeshHooks:           c.meshHooks,
 	}
 	c.configLock.RUnlock()
 
@@ -3183,3 +3404,54 @@ func (g *group) AddCh(ch <-chan struct{}) {
 func (g *group) Wait() {
 	g.wg.Wait()
 }
+
+// taskInventory returns the tasks running on the client, sorted by allocation
+// and task name.
+func (c *Client) taskInventory() []*cstructs.TaskInventory {
+	tasks := []*cstructs.TaskInventory{}
+	for _, ar := range c.getAllocRunners() {
+		if ar.IsDestroyed() {
+			continue
+		}
+		alloc := ar.Alloc()
+		if alloc.ClientTerminalStatus() {
+			continue
+		}
+
+		for name, ts := range ar.AllocState().TaskStates {
+			if ts.State != structs.TaskStateRunning {
+				continue
+			}
+
+			task := &cstructs.TaskInventory{
+				Namespace: alloc.Namespace,
+				JobID:     alloc.JobID,
+				TaskGroup: alloc.TaskGroup,
+				Task:      name,
+				AllocID:   alloc.ID,
+				AllocName: alloc.Name,
+				StartedAt: ts.StartedAt,
+			}
+			if alloc.AllocatedResources != nil {
+				if tr := alloc.AllocatedResources.Tasks[name]; tr != nil {
+					task.CPU = tr.Cpu.CpuShares
+					task.Cores = tr.Cpu.ReservedCores
+					task.MemoryMB = tr.Memory.MemoryMB
+					task.MemoryMaxMB = tr.Memory.MemoryMaxMB
+				}
+			}
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].AllocName != tasks[j].AllocName {
+			return tasks[i].AllocName < tasks[j].AllocName
+		}
+		if tasks[i].AllocID != tasks[j].AllocID {
+			return tasks[i].AllocID < tasks[j].AllocID
+		}
+		return tasks[i].Task < tasks[j].Task
+	})
+	return tasks
+}
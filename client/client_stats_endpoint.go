@@ -4,6 +4,7 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/structs"
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
 )
@@ -28,3 +29,34 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	reply.HostStats = clientStats.LatestHostStats()
 	return nil
 }
+
+// Inventory is used to retrieve the tasks running on the client.
+func (s *ClientStats) Inventory(args *nstructs.NodeSpecificRequest, reply *structs.ClientInventoryResponse) error {
+	defer metrics.MeasureSince([]string{"client", "client_stats", "inventory"}, time.Now())
+
+	// Check node read permissions
+	aclObj, err := s.c.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	// Only list the tasks of the namespaces the token can read
+	tasks := s.c.taskInventory()
+	if aclObj != nil {
+		filtered := make([]*structs.TaskInventory, 0, len(tasks))
+		for _, task := range tasks {
+			if aclObj.AllowNsOp(task.Namespace, acl.NamespaceCapabilityReadJob) {
+				filtered = append(filtered, task)
+			}
+		}
+		tasks = filtered
+	}
+
+	reply.Inventory = &structs.ClientInventory{
+		NodeID: s.c.NodeID(),
+		Tasks:  tasks,
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/nomad/acl"
@@ -9,6 +10,7 @@ import (
 	"github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/mock"
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,6 +29,55 @@ func TestClientStats_Stats(t *testing.T) {
 	require.NotZero(resp.HostStats.Uptime)
 }
 
+func TestClientStats_Inventory(t *testing.T) {
+	ci.Parallel(t)
+
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// No task is running
+	req := &nstructs.NodeSpecificRequest{}
+	var resp structs.ClientInventoryResponse
+	require.NoError(t, client.ClientRPC("ClientStats.Inventory", &req, &resp))
+	require.Equal(t, client.NodeID(), resp.Inventory.NodeID)
+	require.Empty(t, resp.Inventory.Tasks)
+
+	a := mock.Alloc()
+	a.Job.TaskGroups[0].Tasks[0].Driver = "mock_driver"
+	a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+	require.NoError(t, client.addAlloc(a, ""))
+
+	testutil.WaitForResult(func() (bool, error) {
+		var resp structs.ClientInventoryResponse
+		if err := client.ClientRPC("ClientStats.Inventory", &req, &resp); err != nil {
+			return false, err
+		}
+		if n := len(resp.Inventory.Tasks); n != 1 {
+			return false, fmt.Errorf("expected 1 running task, found %d", n)
+		}
+
+		task := resp.Inventory.Tasks[0]
+		require.Equal(t, &structs.TaskInventory{
+			Namespace:   a.Namespace,
+			JobID:       a.JobID,
+			TaskGroup:   "web",
+			Task:        "web",
+			AllocID:     a.ID,
+			AllocName:   a.Name,
+			StartedAt:   task.StartedAt,
+			CPU:         500,
+			MemoryMB:    256,
+			MemoryMaxMB: 0,
+		}, task)
+		require.False(t, task.StartedAt.IsZero())
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
 func TestClientStats_Stats_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -87,3 +138,75 @@ func TestClientStats_Stats_ACL(t *testing.T) {
 		require.NotNil(resp.HostStats)
 	}
 }
+
+func TestClientStats_Inventory_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	server, addr, root, cleanupS := testACLServer(t, nil)
+	defer cleanupS()
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer cleanupC()
+
+	// Run a task in the default namespace and one in another namespace
+	ns := mock.Namespace()
+	require.NoError(t, server.State().UpsertNamespaces(1000, []*nstructs.Namespace{ns}))
+
+	allocs := []*nstructs.Allocation{mock.Alloc(), mock.Alloc()}
+	allocs[1].Namespace = ns.Name
+	allocs[1].Job.Namespace = ns.Name
+	for _, a := range allocs {
+		a.Job.TaskGroups[0].Tasks[0].Driver = "mock_driver"
+		a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+			"run_for": "10s",
+		}
+		require.NoError(t, client.addAlloc(a, ""))
+	}
+
+	inventory := func(token string) ([]*structs.TaskInventory, error) {
+		req := &nstructs.NodeSpecificRequest{}
+		req.AuthToken = token
+		var resp structs.ClientInventoryResponse
+		err := client.ClientRPC("ClientStats.Inventory", &req, &resp)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Inventory.Tasks, nil
+	}
+
+	testutil.WaitForResult(func() (bool, error) {
+		tasks, err := inventory(root.SecretID)
+		if err != nil {
+			return false, err
+		}
+		if n := len(tasks); n != 2 {
+			return false, fmt.Errorf("expected 2 running tasks, found %d", n)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// A token without node read access is denied
+	token := mock.CreatePolicyAndToken(t, server.State(), 1005, "invalid",
+		mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	_, err := inventory(token.SecretID)
+	require.EqualError(t, err, nstructs.ErrPermissionDenied.Error())
+
+	// A node read token only lists the tasks of the namespaces it can read
+	policy := mock.NodePolicy(acl.PolicyRead) +
+		mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	token = mock.CreatePolicyAndToken(t, server.State(), 1007, "default", policy)
+	tasks, err := inventory(token.SecretID)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, allocs[0].ID, tasks[0].AllocID)
+
+	token = mock.CreatePolicyAndToken(t, server.State(), 1009, "node", mock.NodePolicy(acl.PolicyRead))
+	tasks, err = inventory(token.SecretID)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+}
@@ -608,6 +608,22 @@ func TestClient_SaveRestoreState(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	})
 
+	// Flush the state and verify the task can be reattached to once running
+	taskName := alloc1.Job.TaskGroups[0].Tasks[0].Name
+	require.Eventually(t, func() bool {
+		ar, err := c1.getAllocRunner(alloc1.ID)
+		if err != nil {
+			return false
+		}
+		ts := ar.AllocState().TaskStates[taskName]
+		return ts != nil && ts.State == structs.TaskStateRunning
+	}, 10*time.Second, 50*time.Millisecond)
+
+	upgrade, err := c1.PrepareUpgrade()
+	require.NoError(t, err)
+	require.Equal(t, 1, upgrade.Recoverable)
+	require.Empty(t, upgrade.Unrecoverable)
+
 	// Shutdown the client, saves state
 	if err := c1.Shutdown(); err != nil {
 		t.Fatalf("err: %v", err)
@@ -629,6 +645,17 @@ func TestClient_SaveRestoreState(t *testing.T) {
 	}
 	defer c2.Shutdown()
 
+	// The restore of the task is reported
+	report := c2.Node().LastRestore
+	require.NotNil(t, report)
+	require.False(t, report.RestoredAt.IsZero())
+	require.Equal(t, 1, report.Reattached+len(report.Failed))
+	for _, f := range report.Failed {
+		require.Equal(t, alloc1.ID, f.AllocID)
+		require.Equal(t, taskName, f.TaskName)
+		require.NotEmpty(t, f.Error)
+	}
+
 	// Ensure the allocation is running
 	testutil.WaitForResult(func() (bool, error) {
 		c2.allocLock.RLock()
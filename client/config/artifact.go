@@ -5,9 +5,16 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
+const (
+	// DefaultArtifactFetcherTimeout is the duration an artifact fetcher is
+	// given to download an artifact when no timeout is configured.
+	DefaultArtifactFetcherTimeout = 30 * time.Minute
+)
+
 // ArtifactConfig is the internal readonly copy of the client agent's
 // ArtifactConfig.
 type ArtifactConfig struct {
@@ -18,6 +25,30 @@ type ArtifactConfig struct {
 	GitTimeout time.Duration
 	HgTimeout  time.Duration
 	S3Timeout  time.Duration
+
+	// BandwidthLimit is the maximum download rate in bytes per second, or
+	// 0 for unlimited.
+	BandwidthLimit int64
+
+	// MaxConcurrentDownloads is the maximum number of concurrent artifact
+	// downloads, or 0 for unlimited.
+	MaxConcurrentDownloads int
+
+	// Fetchers are the artifact fetchers for the URL schemes go-getter
+	// doesn't support.
+	Fetchers []*ArtifactFetcherConfig
+}
+
+// ArtifactFetcherConfig is the internal readonly copy of the configuration of
+// an artifact fetcher.
+type ArtifactFetcherConfig struct {
+	Scheme  string
+	Command string
+	Args    []string
+	User    string
+	Env     map[string]string
+	Config  map[string]string
+	Timeout time.Duration
 }
 
 // ArtifactConfigFromAgent creates a new internal readonly copy of the client
@@ -61,6 +92,34 @@ func ArtifactConfigFromAgent(c *config.ArtifactConfig) (*ArtifactConfig, error)
 	}
 	newConfig.S3Timeout = t
 
+	s, err = humanize.ParseBytes(*c.BandwidthLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing BandwidthLimit: %w", err)
+	}
+	newConfig.BandwidthLimit = int64(s)
+
+	newConfig.MaxConcurrentDownloads = *c.MaxConcurrentDownloads
+
+	for _, f := range c.Fetchers {
+		fetcher := &ArtifactFetcherConfig{
+			Scheme:  f.Scheme,
+			Command: f.Command,
+			Args:    helper.CopySliceString(f.Args),
+			User:    f.User,
+			Env:     helper.CopyMapStringString(f.Env),
+			Config:  helper.CopyMapStringString(f.Config),
+			Timeout: DefaultArtifactFetcherTimeout,
+		}
+		if f.Timeout != "" {
+			t, err = time.ParseDuration(f.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing Timeout of fetcher %q: %w", f.Scheme, err)
+			}
+			fetcher.Timeout = t
+		}
+		newConfig.Fetchers = append(newConfig.Fetchers, fetcher)
+	}
+
 	return newConfig, nil
 }
 
@@ -70,5 +129,23 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 	}
 
 	newCopy := *a
+	if a.Fetchers != nil {
+		newCopy.Fetchers = make([]*ArtifactFetcherConfig, len(a.Fetchers))
+		for i, f := range a.Fetchers {
+			newCopy.Fetchers[i] = f.Copy()
+		}
+	}
+	return &newCopy
+}
+
+func (f *ArtifactFetcherConfig) Copy() *ArtifactFetcherConfig {
+	if f == nil {
+		return nil
+	}
+
+	newCopy := *f
+	newCopy.Args = helper.CopySliceString(f.Args)
+	newCopy.Env = helper.CopyMapStringString(f.Env)
+	newCopy.Config = helper.CopyMapStringString(f.Config)
 	return &newCopy
 }
@@ -31,6 +31,77 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 				S3Timeout:       30 * time.Minute,
 			},
 		},
+		{
+			name: "with limits",
+			config: &config.ArtifactConfig{
+				HTTPReadTimeout:        helper.StringToPtr("30m"),
+				HTTPMaxSize:            helper.StringToPtr("100GB"),
+				GCSTimeout:             helper.StringToPtr("30m"),
+				GitTimeout:             helper.StringToPtr("30m"),
+				HgTimeout:              helper.StringToPtr("30m"),
+				S3Timeout:              helper.StringToPtr("30m"),
+				BandwidthLimit:         helper.StringToPtr("10MB"),
+				MaxConcurrentDownloads: helper.IntToPtr(2),
+			},
+			expected: &ArtifactConfig{
+				HTTPReadTimeout:        30 * time.Minute,
+				HTTPMaxBytes:           100_000_000_000,
+				GCSTimeout:             30 * time.Minute,
+				GitTimeout:             30 * time.Minute,
+				HgTimeout:              30 * time.Minute,
+				S3Timeout:              30 * time.Minute,
+				BandwidthLimit:         10_000_000,
+				MaxConcurrentDownloads: 2,
+			},
+		},
+		{
+			name: "with fetchers",
+			config: &config.ArtifactConfig{
+				HTTPReadTimeout:        helper.StringToPtr("30m"),
+				HTTPMaxSize:            helper.StringToPtr("100GB"),
+				GCSTimeout:             helper.StringToPtr("30m"),
+				GitTimeout:             helper.StringToPtr("30m"),
+				HgTimeout:              helper.StringToPtr("30m"),
+				S3Timeout:              helper.StringToPtr("30m"),
+				BandwidthLimit:         helper.StringToPtr("0"),
+				MaxConcurrentDownloads: helper.IntToPtr(0),
+				Fetchers: []*config.ArtifactFetcherConfig{
+					{
+						Scheme:  "store",
+						Command: "/usr/local/bin/store-fetch",
+						User:    "nobody",
+						Env:     map[string]string{"PATH": "/usr/bin"},
+						Timeout: "5m",
+					},
+					{
+						Scheme:  "oci",
+						Command: "/usr/local/bin/oci-fetch",
+					},
+				},
+			},
+			expected: &ArtifactConfig{
+				HTTPReadTimeout: 30 * time.Minute,
+				HTTPMaxBytes:    100_000_000_000,
+				GCSTimeout:      30 * time.Minute,
+				GitTimeout:      30 * time.Minute,
+				HgTimeout:       30 * time.Minute,
+				S3Timeout:       30 * time.Minute,
+				Fetchers: []*ArtifactFetcherConfig{
+					{
+						Scheme:  "store",
+						Command: "/usr/local/bin/store-fetch",
+						User:    "nobody",
+						Env:     map[string]string{"PATH": "/usr/bin"},
+						Timeout: 5 * time.Minute,
+					},
+					{
+						Scheme:  "oci",
+						Command: "/usr/local/bin/oci-fetch",
+						Timeout: DefaultArtifactFetcherTimeout,
+					},
+				},
+			},
+		},
 		{
 			name: "invalid http read timeout",
 			config: &config.ArtifactConfig{
@@ -103,6 +174,20 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 			},
 			expectedError: "error parsing S3Timeout",
 		},
+		{
+			name: "invalid bandwidth limit",
+			config: &config.ArtifactConfig{
+				HTTPReadTimeout:        helper.StringToPtr("30m"),
+				HTTPMaxSize:            helper.StringToPtr("100GB"),
+				GCSTimeout:             helper.StringToPtr("30m"),
+				GitTimeout:             helper.StringToPtr("30m"),
+				HgTimeout:              helper.StringToPtr("30m"),
+				S3Timeout:              helper.StringToPtr("30m"),
+				BandwidthLimit:         helper.StringToPtr("invalid"),
+				MaxConcurrentDownloads: helper.IntToPtr(0),
+			},
+			expectedError: "error parsing BandwidthLimit",
+		},
 	}
 
 	for _, tc := range testCases {
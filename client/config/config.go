@@ -131,6 +131,10 @@ type Config struct {
 	// Node provides the base node
 	Node *structs.Node
 
+	// IntroToken is the node introduction token used to register the node
+	// for the first time when servers require node introduction.
+	IntroToken string
+
 	// ClientMaxPort is the upper range of the ports that the client uses for
 	// communicating with plugin subsystems over loopback
 	ClientMaxPort uint
@@ -218,6 +222,11 @@ type Config struct {
 	// DisableRemoteExec disables remote exec targeting tasks on this client
 	DisableRemoteExec bool
 
+	// DebugShellPath is the path to a static, busybox compatible binary that
+	// is copied into tasks for debug shell sessions. Debug shells are
+	// disabled if empty.
+	DebugShellPath string
+
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *ClientTemplateConfig
 
@@ -293,6 +302,14 @@ type Config struct {
 
 	// Artifact configuration from the agent's config file.
 	Artifact *ArtifactConfig
+
+	// EnvProviders are the environment providers run before tasks start,
+	// in the order they are configured.
+	EnvProviders []*EnvProviderConfig
+
+	// MeshHooks are the hooks integrating task groups with external service
+	// meshes, by mesh type.
+	MeshHooks []*MeshHookConfig
 }
 
 // ClientTemplateConfig is configuration on the client specific to template
@@ -699,6 +716,18 @@ func (c *Config) Copy() *Config {
 		copy(nc.ReservableCores, c.ReservableCores)
 	}
 	nc.Artifact = c.Artifact.Copy()
+	if c.EnvProviders != nil {
+		nc.EnvProviders = make([]*EnvProviderConfig, len(c.EnvProviders))
+		for i, p := range c.EnvProviders {
+			nc.EnvProviders[i] = p.Copy()
+		}
+	}
+	if c.MeshHooks != nil {
+		nc.MeshHooks = make([]*MeshHookConfig, len(c.MeshHooks))
+		for i, m := range c.MeshHooks {
+			nc.MeshHooks[i] = m.Copy()
+		}
+	}
 	return nc
 }
 
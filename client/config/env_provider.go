@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// DefaultEnvProviderTimeout is the duration an environment provider is
+	// given to complete when no timeout is configured.
+	DefaultEnvProviderTimeout = 10 * time.Second
+)
+
+// EnvProviderConfig is the internal readonly copy of the configuration of an
+// environment provider.
+type EnvProviderConfig struct {
+	Name     string
+	Command  string
+	Args     []string
+	Timeout  time.Duration
+	CacheTTL time.Duration
+}
+
+// EnvProviderConfigFromAgent creates a new internal readonly copy of the
+// configuration of an environment provider. The config should have already
+// been validated.
+func EnvProviderConfigFromAgent(c *config.EnvProviderConfig) (*EnvProviderConfig, error) {
+	newConfig := &EnvProviderConfig{
+		Name:    c.Name,
+		Command: c.Command,
+		Args:    helper.CopySliceString(c.Args),
+		Timeout: DefaultEnvProviderTimeout,
+	}
+
+	if c.Timeout != "" {
+		t, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing Timeout: %w", err)
+		}
+		newConfig.Timeout = t
+	}
+
+	if c.CacheTTL != "" {
+		t, err := time.ParseDuration(c.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing CacheTTL: %w", err)
+		}
+		newConfig.CacheTTL = t
+	}
+
+	return newConfig, nil
+}
+
+func (e *EnvProviderConfig) Copy() *EnvProviderConfig {
+	if e == nil {
+		return nil
+	}
+
+	newCopy := *e
+	newCopy.Args = helper.CopySliceString(e.Args)
+	return &newCopy
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// DefaultMeshHookTimeout is the duration a mesh hook is given to complete
+	// when no timeout is configured.
+	DefaultMeshHookTimeout = 30 * time.Second
+)
+
+// MeshHookConfig is the internal readonly copy of the configuration of a mesh
+// hook.
+type MeshHookConfig struct {
+	Name    string
+	Command string
+	Args    []string
+	Timeout time.Duration
+}
+
+// MeshHookConfigFromAgent creates a new internal readonly copy of the
+// configuration of a mesh hook. The config should have already been
+// validated.
+func MeshHookConfigFromAgent(c *config.MeshHookConfig) (*MeshHookConfig, error) {
+	newConfig := &MeshHookConfig{
+		Name:    c.Name,
+		Command: c.Command,
+		Args:    helper.CopySliceString(c.Args),
+		Timeout: DefaultMeshHookTimeout,
+	}
+
+	if c.Timeout != "" {
+		t, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing Timeout: %w", err)
+		}
+		newConfig.Timeout = t
+	}
+
+	return newConfig, nil
+}
+
+func (m *MeshHookConfig) Copy() *MeshHookConfig {
+	if m == nil {
+		return nil
+	}
+
+	newCopy := *m
+	newCopy.Args = helper.CopySliceString(m.Args)
+	return &newCopy
+}
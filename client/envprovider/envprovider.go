@@ -0,0 +1,183 @@
+// Package envprovider runs the environment providers configured on a client.
+//
+// An environment provider is a binary that contributes environment variables
+// and files to tasks before they start, for example to fetch short-lived
+// credentials from an external broker. The provider is given a JSON encoded
+// Request on stdin and must write a JSON encoded Response to stdout and exit
+// with a zero status.
+package envprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/config"
+)
+
+const (
+	// maxStderrLen is the maximum length of the stderr output of a provider
+	// included in errors.
+	maxStderrLen = 1024
+)
+
+// Request is the task information passed to a provider on stdin.
+type Request struct {
+	Region    string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	Task      string
+	AllocID   string
+	NodeID    string
+	Driver    string
+	Meta      map[string]string
+}
+
+// Response is the output a provider writes to stdout.
+type Response struct {
+	// Env is the environment variables to set for the task.
+	Env map[string]string
+
+	// Files are the files to write into the task directory.
+	Files []*File
+}
+
+// File is a file a provider writes into the task directory.
+type File struct {
+	// Path is the path of the file relative to the task directory.
+	Path string
+
+	// Data is the content of the file.
+	Data string
+
+	// Perms is the octal permissions of the file. Defaults to "0644".
+	Perms string
+}
+
+// Result is the response of a single provider.
+type Result struct {
+	Provider string
+	*Response
+}
+
+type cacheEntry struct {
+	resp    *Response
+	expires time.Time
+}
+
+// Manager runs the environment providers of a client and caches their
+// responses. It is shared by all the task runners of a client.
+type Manager struct {
+	logger    log.Logger
+	providers []*config.EnvProviderConfig
+
+	cache     map[string]*cacheEntry
+	cacheLock sync.Mutex
+}
+
+// NewManager returns a Manager for the given providers.
+func NewManager(logger log.Logger, providers []*config.EnvProviderConfig) *Manager {
+	return &Manager{
+		logger:    logger.Named("env_provider"),
+		providers: providers,
+		cache:     make(map[string]*cacheEntry),
+	}
+}
+
+// Enabled returns true if any providers are configured.
+func (m *Manager) Enabled() bool {
+	return m != nil && len(m.providers) != 0
+}
+
+// Run runs every provider for the task described by the request, in the
+// order they are configured, and returns their results. A cached response
+// is used for a provider if it was run for the same request within its
+// cache TTL.
+func (m *Manager) Run(ctx context.Context, req *Request) ([]*Result, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	results := make([]*Result, 0, len(m.providers))
+	for _, p := range m.providers {
+		key := p.Name + "\x00" + string(input)
+
+		resp := m.cached(key)
+		if resp == nil {
+			resp, err = m.run(ctx, p, input)
+			if err != nil {
+				return nil, fmt.Errorf("env provider %q failed: %v", p.Name, err)
+			}
+			if p.CacheTTL > 0 {
+				m.setCached(key, resp, p.CacheTTL)
+			}
+		}
+
+		results = append(results, &Result{Provider: p.Name, Response: resp})
+	}
+	return results, nil
+}
+
+// run executes the provider with the encoded request on stdin.
+func (m *Manager) run(ctx context.Context, p *config.EnvProviderConfig, input []byte) (*Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	m.logger.Trace("running env provider", "provider", p.Name, "command", p.Command)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timed out after %s", p.Timeout)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxStderrLen {
+			msg = msg[:maxStderrLen]
+		}
+		if msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	var resp Response
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode output: %v", err)
+	}
+	return &resp, nil
+}
+
+func (m *Manager) cached(key string) *Response {
+	m.cacheLock.Lock()
+	defer m.cacheLock.Unlock()
+
+	now := time.Now()
+	for k, e := range m.cache {
+		if now.After(e.expires) {
+			delete(m.cache, k)
+		}
+	}
+
+	if e, ok := m.cache[key]; ok {
+		return e.resp
+	}
+	return nil
+}
+
+func (m *Manager) setCached(key string, resp *Response, ttl time.Duration) {
+	m.cacheLock.Lock()
+	defer m.cacheLock.Unlock()
+	m.cache[key] = &cacheEntry{resp: resp, expires: time.Now().Add(ttl)}
+}
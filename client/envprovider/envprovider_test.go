@@ -0,0 +1,131 @@
+package envprovider
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// testProvider writes a shell script provider and returns its config.
+func testProvider(t *testing.T, name, script string) *config.EnvProviderConfig {
+	if runtime.GOOS == "windows" {
+		t.Skip("env provider tests use shell scripts")
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+	return &config.EnvProviderConfig{
+		Name:    name,
+		Command: path,
+		Timeout: 5 * time.Second,
+	}
+}
+
+func TestManager_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// The provider echoes the task name from its input
+	p1 := testProvider(t, "p1", `sed 's/.*"Task":"\([^"]*\)".*/{"Env":{"TASK":"\1","A":"1"}}/'`)
+	p2 := testProvider(t, "p2", `echo '{"Env":{"A":"2"},"Files":[{"Path":"secrets/creds","Data":"hunter2"}]}'`)
+
+	m := NewManager(testlog.HCLogger(t), []*config.EnvProviderConfig{p1, p2})
+	require.True(t, m.Enabled())
+
+	results, err := m.Run(context.Background(), &Request{Task: "web"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "p1", results[0].Provider)
+	require.Equal(t, map[string]string{"TASK": "web", "A": "1"}, results[0].Env)
+
+	require.Equal(t, "p2", results[1].Provider)
+	require.Equal(t, map[string]string{"A": "2"}, results[1].Env)
+	require.Equal(t, []*File{{Path: "secrets/creds", Data: "hunter2"}}, results[1].Files)
+}
+
+func TestManager_Run_Cache(t *testing.T) {
+	ci.Parallel(t)
+
+	// The provider counts its invocations
+	counter := filepath.Join(t.TempDir(), "counter")
+	p := testProvider(t, "p", `echo x >> `+counter+`; echo '{"Env":{"A":"1"}}'`)
+	p.CacheTTL = time.Hour
+
+	m := NewManager(testlog.HCLogger(t), []*config.EnvProviderConfig{p})
+	invocations := func() int {
+		b, err := ioutil.ReadFile(counter)
+		require.NoError(t, err)
+		return strings.Count(string(b), "x")
+	}
+
+	_, err := m.Run(context.Background(), &Request{Task: "web"})
+	require.NoError(t, err)
+	_, err = m.Run(context.Background(), &Request{Task: "web"})
+	require.NoError(t, err)
+	require.Equal(t, 1, invocations())
+
+	// A different task is not served from the cache
+	_, err = m.Run(context.Background(), &Request{Task: "api"})
+	require.NoError(t, err)
+	require.Equal(t, 2, invocations())
+
+	// Nor is an expired response
+	for _, e := range m.cache {
+		e.expires = time.Now().Add(-time.Second)
+	}
+	_, err = m.Run(context.Background(), &Request{Task: "web"})
+	require.NoError(t, err)
+	require.Equal(t, 3, invocations())
+}
+
+func TestManager_Run_Errors(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("exit status", func(t *testing.T) {
+		p := testProvider(t, "p", `echo "broker unavailable" >&2; exit 1`)
+		m := NewManager(testlog.HCLogger(t), []*config.EnvProviderConfig{p})
+
+		_, err := m.Run(context.Background(), &Request{})
+		require.ErrorContains(t, err, `env provider "p" failed`)
+		require.ErrorContains(t, err, "broker unavailable")
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		p := testProvider(t, "p", `echo "not json"`)
+		m := NewManager(testlog.HCLogger(t), []*config.EnvProviderConfig{p})
+
+		_, err := m.Run(context.Background(), &Request{})
+		require.ErrorContains(t, err, "failed to decode output")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		p := testProvider(t, "p", `exec sleep 10`)
+		p.Timeout = 100 * time.Millisecond
+		m := NewManager(testlog.HCLogger(t), []*config.EnvProviderConfig{p})
+
+		_, err := m.Run(context.Background(), &Request{})
+		require.ErrorContains(t, err, "timed out after 100ms")
+	})
+
+	t.Run("missing command", func(t *testing.T) {
+		p := &config.EnvProviderConfig{
+			Name:    "p",
+			Command: filepath.Join(os.TempDir(), "does-not-exist"),
+			Timeout: time.Second,
+		}
+		m := NewManager(testlog.HCLogger(t), []*config.EnvProviderConfig{p})
+
+		_, err := m.Run(context.Background(), &Request{})
+		require.Error(t, err)
+	})
+}
@@ -35,6 +35,7 @@ var (
 		"cpu":     NewCPUFingerprint,
 		"host":    NewHostFingerprint,
 		"memory":  NewMemoryFingerprint,
+		"mesh":    NewMeshFingerprint,
 		"network": NewNetworkFingerprint,
 		"nomad":   NewNomadFingerprint,
 		"signal":  NewSignalFingerprint,
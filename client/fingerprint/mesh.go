@@ -0,0 +1,27 @@
+package fingerprint
+
+import (
+	log "github.com/hashicorp/go-hclog"
+)
+
+// MeshFingerprint is used to fingerprint the mesh hooks configured on the
+// client, so that the groups integrated with a mesh are only placed on
+// clients able to run them.
+type MeshFingerprint struct {
+	StaticFingerprinter
+	logger log.Logger
+}
+
+// NewMeshFingerprint is used to create a mesh fingerprint
+func NewMeshFingerprint(logger log.Logger) Fingerprint {
+	f := &MeshFingerprint{logger: logger.Named("mesh")}
+	return f
+}
+
+func (f *MeshFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintResponse) error {
+	for _, h := range req.Config.MeshHooks {
+		resp.AddAttribute("plugins.mesh."+h.Name, "true")
+	}
+	resp.Detected = true
+	return nil
+}
@@ -0,0 +1,32 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeshFingerprint(t *testing.T) {
+	ci.Parallel(t)
+
+	f := NewMeshFingerprint(testlog.HCLogger(t))
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg := &config.Config{
+		MeshHooks: []*config.MeshHookConfig{
+			{Name: "istio", Command: "/usr/local/bin/nomad-istio"},
+		},
+	}
+
+	request := &FingerprintRequest{Config: cfg, Node: node}
+	var response FingerprintResponse
+	require.NoError(t, f.Fingerprint(request, &response))
+	require.True(t, response.Detected)
+
+	assertNodeAttributeContains(t, response.Attributes, "plugins.mesh.istio")
+}
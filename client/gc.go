@@ -344,10 +344,11 @@ func (a *AllocGarbageCollector) MarkForCollection(allocID string, ar AllocRunner
 // GCAlloc wraps an allocation runner and an index enabling it to be used within
 // a PQ
 type GCAlloc struct {
-	timeStamp   time.Time
-	allocID     string
-	allocRunner AllocRunner
-	index       int
+	timeStamp    time.Time
+	killPriority int
+	allocID      string
+	allocRunner  AllocRunner
+	index        int
 }
 
 type GCAllocPQImpl []*GCAlloc
@@ -356,7 +357,12 @@ func (pq GCAllocPQImpl) Len() int {
 	return len(pq)
 }
 
+// Less orders the allocations by kill priority, so that the allocations with a
+// lower kill priority are collected first, and then by termination time.
 func (pq GCAllocPQImpl) Less(i, j int) bool {
+	if pq[i].killPriority != pq[j].killPriority {
+		return pq[i].killPriority < pq[j].killPriority
+	}
 	return pq[i].timeStamp.Before(pq[j].timeStamp)
 }
 
@@ -383,7 +389,7 @@ func (pq *GCAllocPQImpl) Pop() interface{} {
 }
 
 // IndexedGCAllocPQ is an indexed PQ which maintains a list of allocation runner
-// based on their termination time.
+// based on their kill priority and termination time.
 type IndexedGCAllocPQ struct {
 	index map[string]*GCAlloc
 	heap  GCAllocPQImpl
@@ -409,9 +415,10 @@ func (i *IndexedGCAllocPQ) Push(allocID string, ar AllocRunner) bool {
 		return false
 	}
 	gcAlloc := &GCAlloc{
-		timeStamp:   time.Now(),
-		allocID:     allocID,
-		allocRunner: ar,
+		timeStamp:    time.Now(),
+		killPriority: ar.Alloc().KillPriority(),
+		allocID:      allocID,
+		allocRunner:  ar,
 	}
 	i.index[allocID] = gcAlloc
 	heap.Push(&i.heap, gcAlloc)
@@ -82,6 +82,30 @@ func TestIndexedGCAllocPQ(t *testing.T) {
 	}
 }
 
+func TestIndexedGCAllocPQ_KillPriority(t *testing.T) {
+	ci.Parallel(t)
+
+	pq := NewIndexedGCAllocPQ()
+
+	// Create allocs whose kill priority is the reverse of their termination
+	// order
+	var ars []AllocRunner
+	for _, priority := range []int{70, 50, 50, 30} {
+		alloc := mock.Alloc()
+		alloc.Job.Priority = priority
+		ar, cleanup := allocrunner.TestAllocRunnerFromAlloc(t, alloc)
+		defer cleanup()
+		pq.Push(alloc.ID, ar)
+		ars = append(ars, ar)
+	}
+
+	// Allocs are collected by kill priority, then termination time
+	for _, i := range []int{3, 1, 2, 0} {
+		require.Equal(t, ars[i].Alloc().ID, pq.Pop().allocID)
+	}
+	require.Nil(t, pq.Pop())
+}
+
 // MockAllocCounter implements AllocCounter interface.
 type MockAllocCounter struct {
 	allocs int
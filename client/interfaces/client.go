@@ -1,6 +1,8 @@
 package interfaces
 
 import (
+	"context"
+
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/device"
 )
@@ -34,5 +36,10 @@ type EnvReplacer interface {
 
 // ArtifactGetter is an interface satisfied by the helper/getter package.
 type ArtifactGetter interface {
-	GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact) error
+	GetArtifact(ctx context.Context, taskEnv EnvReplacer, artifact *structs.TaskArtifact, progress ArtifactProgressFunc) error
 }
+
+// ArtifactProgressFunc is called periodically while an artifact is being
+// downloaded with the number of bytes downloaded so far and the total size
+// of the artifact, which is 0 if it is not known.
+type ArtifactProgressFunc func(downloaded, total int64)
@@ -4,8 +4,10 @@ package cgutil
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -115,6 +117,38 @@ func FindCgroupMountpointDir() (string, error) {
 	return mount[0].Mountpoint, nil
 }
 
+// RequiredControllersV2 are the cgroups.v2 controllers Nomad requires to
+// enforce the resource limits of tasks.
+var RequiredControllersV2 = []string{"cpu", "cpuset", "memory"}
+
+// MissingControllersV2 returns the controllers of RequiredControllersV2 and
+// the given optional controllers which are not available in the cgroups.v2
+// hierarchy, such as when the kernel was built without them.
+func MissingControllersV2(optional ...string) ([]string, error) {
+	controllers := append([]string{}, RequiredControllersV2...)
+	return missingControllersV2(CgroupRoot, append(controllers, optional...))
+}
+
+func missingControllersV2(root string, controllers []string) ([]string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(root, "cgroup.controllers"))
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[string]struct{})
+	for _, c := range strings.Fields(string(b)) {
+		available[c] = struct{}{}
+	}
+
+	var missing []string
+	for _, c := range controllers {
+		if _, ok := available[c]; !ok {
+			missing = append(missing, c)
+		}
+	}
+	return missing, nil
+}
+
 // CopyCpuset copies the cpuset.cpus value from source into destination.
 func CopyCpuset(source, destination string) error {
 	correct, err := cgroups.ReadFile(source, "cpuset.cpus")
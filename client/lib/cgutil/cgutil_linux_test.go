@@ -3,6 +3,7 @@
 package cgutil
 
 import (
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -124,3 +125,22 @@ func TestUtil_CopyCpuset(t *testing.T) {
 		require.Equal(t, "0-1", strings.TrimSpace(value))
 	})
 }
+
+func TestUtil_MissingControllersV2(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "cgroup.controllers"),
+		[]byte("cpuset cpu io memory pids\n"), 0644))
+
+	missing, err := missingControllersV2(root, []string{"cpu", "cpuset", "memory", "io"})
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	missing, err = missingControllersV2(root, []string{"cpu", "hugetlb", "memory", "rdma"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"hugetlb", "rdma"}, missing)
+
+	_, err = missingControllersV2(t.TempDir(), RequiredControllersV2)
+	require.Error(t, err)
+}
@@ -26,6 +26,11 @@ func FindCgroupMountpointDir() (string, error) {
 	return "", nil
 }
 
+// MissingControllersV2 returns nothing for non-Linux operating systems.
+func MissingControllersV2(...string) ([]string, error) {
+	return nil, nil
+}
+
 // GetCgroupParent returns nothing for non-Linux operating systems.
 func GetCgroupParent(string) string {
 	return DefaultCgroupParent
@@ -30,6 +30,11 @@ var (
 // the freezer cgroup subsystem.
 type GroupKiller interface {
 	KillGroup(cgroup *configs.Cgroup) error
+
+	// SignalGroup sends sig to every process present in cgroup, using the
+	// freezer subsystem to prevent processes from forking children which
+	// would not receive the signal.
+	SignalGroup(cgroup *configs.Cgroup, sig os.Signal) error
 }
 
 // NewGroupKiller creates a GroupKiller with executor PID pid.
@@ -59,21 +64,38 @@ func (d *killer) v1(cgroup *configs.Cgroup) error {
 		return errors.New("missing cgroup")
 	}
 
+	d.logger.Trace("killing processes", "cgroup_path", cgroup.Paths[freezer], "cgroup_version", "v1", "executor_pid", d.pid)
+
+	path, freeze, thaw, err := d.freezerV1(cgroup)
+	if err != nil {
+		return err
+	}
+
+	// do the common kill logic
+	if err = d.kill(path, freeze, thaw); err != nil {
+		return err
+	}
+
+	// remove the cgroup from disk
+	return cgroups.RemovePath(path)
+}
+
+// freezerV1 moves the executor pid out of the v1 cgroup and returns the path
+// of the freezer cgroup along with the ability to freeze and thaw it.
+func (d *killer) freezerV1(cgroup *configs.Cgroup) (string, func(), func(), error) {
 	// the actual path to our tasks freezer cgroup
 	path := cgroup.Paths[freezer]
 
-	d.logger.Trace("killing processes", "cgroup_path", path, "cgroup_version", "v1", "executor_pid", d.pid)
-
 	// move executor PID into the init freezer cgroup so we can kill the task
 	// pids without killing the executor (which is the process running this code,
 	// doing the killing)
 	initPath, err := cgroups.GetInitCgroupPath(freezer)
 	if err != nil {
-		return fmt.Errorf("failed to find init cgroup: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to find init cgroup: %w", err)
 	}
 	m := map[string]string{freezer: initPath}
 	if err = cgroups.EnterPid(m, d.pid); err != nil {
-		return fmt.Errorf("failed to add executor pid to init cgroup: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to add executor pid to init cgroup: %w", err)
 	}
 
 	// ability to freeze the cgroup
@@ -86,13 +108,7 @@ func (d *killer) v1(cgroup *configs.Cgroup) error {
 		_ = new(fs.FreezerGroup).Set(path, thawed)
 	}
 
-	// do the common kill logic
-	if err = d.kill(path, freeze, thaw); err != nil {
-		return err
-	}
-
-	// remove the cgroup from disk
-	return cgroups.RemovePath(path)
+	return path, freeze, thaw, nil
 }
 
 func (d *killer) v2(cgroup *configs.Cgroup) error {
@@ -109,9 +125,31 @@ func (d *killer) v2(cgroup *configs.Cgroup) error {
 
 	d.logger.Trace("killing processes", "cgroup_path", path, "cgroup_version", "v2", "executor_pid", d.pid, "existing_pids", existingPIDs)
 
+	path, freeze, thaw, err := d.freezerV2(cgroup)
+	if err != nil {
+		return err
+	}
+
+	// do the common kill logic
+
+	if err = d.kill(path, freeze, thaw); err != nil {
+		return err
+	}
+
+	// note: do NOT remove the cgroup from disk; leave that to the alloc-level
+	// cpuset mananager.
+
+	return nil
+}
+
+// freezerV2 moves the executor pid out of the v2 cgroup and returns the path
+// of the cgroup along with the ability to freeze and thaw it.
+func (d *killer) freezerV2(cgroup *configs.Cgroup) (string, func(), func(), error) {
+	path := filepath.Join(CgroupRoot, cgroup.Path)
+
 	mgr, err := fs2.NewManager(cgroup, "", rootless)
 	if err != nil {
-		return fmt.Errorf("failed to create v2 cgroup manager: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to create v2 cgroup manager: %w", err)
 	}
 
 	// move executor PID into the root init.scope so we can kill the task pids
@@ -119,10 +157,10 @@ func (d *killer) v2(cgroup *configs.Cgroup) error {
 	// the killing)
 	init, err := fs2.NewManager(nil, filepath.Join(CgroupRoot, "init.scope"), rootless)
 	if err != nil {
-		return fmt.Errorf("failed to create v2 init cgroup manager: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to create v2 init cgroup manager: %w", err)
 	}
 	if err = init.Apply(d.pid); err != nil {
-		return fmt.Errorf("failed to move executor pid into init.scope cgroup: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to move executor pid into init.scope cgroup: %w", err)
 	}
 
 	d.logger.Trace("move of executor pid into init.scope complete", "pid", d.pid)
@@ -137,14 +175,56 @@ func (d *killer) v2(cgroup *configs.Cgroup) error {
 		_ = mgr.Freeze(configs.Thawed)
 	}
 
-	// do the common kill logic
+	return path, freeze, thaw, nil
+}
 
-	if err = d.kill(path, freeze, thaw); err != nil {
+// SignalGroup will send sig to the process tree present in cgroup, using the
+// freezer subsystem to prevent further forking. Unlike KillGroup, it doesn't
+// wait for the processes to exit nor remove the cgroup.
+//
+// The order of operations is
+// 1. move the executor pid outside of cgroup
+// 2. freeze cgroup (so processes cannot fork further)
+// 3. scan the cgroup to collect all pids
+// 4. issue sig to each pid found
+// 5. thaw the cgroup so processes can handle the signal
+func (d *killer) SignalGroup(cgroup *configs.Cgroup, sig os.Signal) error {
+	if cgroup == nil {
+		return errors.New("missing cgroup")
+	}
+
+	var path string
+	var freeze, thaw func()
+	var err error
+	if UseV2 {
+		path, freeze, thaw, err = d.freezerV2(cgroup)
+	} else {
+		path, freeze, thaw, err = d.freezerV1(cgroup)
+	}
+	if err != nil {
 		return err
 	}
 
-	// note: do NOT remove the cgroup from disk; leave that to the alloc-level
-	// cpuset mananager.
+	freeze()
+	defer thaw()
+
+	pids, err := cgroups.GetPids(path)
+	if err != nil {
+		return fmt.Errorf("failed to find pids: %w", err)
+	}
+
+	d.logger.Trace("send signal to frozen processes", "cgroup", path, "signal", sig, "pids", pids)
+
+	for _, pid := range pids {
+		p, findErr := os.FindProcess(pid)
+		if findErr != nil {
+			d.logger.Trace("failed to find process of pid to signal", "pid", pid, "error", findErr)
+			continue
+		}
+		if sigErr := p.Signal(sig); sigErr != nil {
+			d.logger.Trace("failed to signal process", "pid", pid, "error", sigErr)
+		}
+	}
 
 	return nil
 }
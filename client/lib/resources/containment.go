@@ -1,5 +1,7 @@
 package resources
 
+import "os"
+
 // A Containment will cleanup resources created by an executor.
 type Containment interface {
 	// Apply enables containment on pid.
@@ -8,6 +10,10 @@ type Containment interface {
 	// Cleanup will purge executor resources like cgroups.
 	Cleanup() error
 
+	// Signal sends sig to every process overseen by the Containment, without
+	// letting them fork processes escaping the signal.
+	Signal(sig os.Signal) error
+
 	// GetPIDs will return the processes overseen by the Containment
 	GetPIDs() PIDs
 }
@@ -75,6 +75,19 @@ func (c *containment) Cleanup() error {
 	return destroyer.KillGroup(c.cgroup)
 }
 
+func (c *containment) Signal(sig os.Signal) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// the current pid is of the executor, which must not be frozen nor
+	// signaled along with the task processes
+	executorPID := os.Getpid()
+	c.logger.Trace("signal on", "cgroup", c.cgroup, "signal", sig, "executor_pid", executorPID)
+
+	signaler := cgutil.NewGroupKiller(c.logger, executorPID)
+	return signaler.SignalGroup(c.cgroup, sig)
+}
+
 func (c *containment) GetPIDs() PIDs {
 	c.lock.Lock()
 	defer c.lock.Unlock()
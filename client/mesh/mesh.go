@@ -0,0 +1,259 @@
+// Package mesh runs the mesh hooks configured on a client.
+//
+// A mesh hook integrates the task groups declaring a mesh of its type with an
+// external service mesh, such as Istio, without changes to Nomad. It is a
+// binary given a JSON encoded Request on stdin, which must write a JSON encoded
+// Response to stdout and exit with a zero status. The hook is run for the
+// following operations:
+//
+//   - render: renders the driver configuration and environment of the sidecar
+//     task injected in the group.
+//   - setup: configures the network namespace of the allocation once it is
+//     created, for example to redirect its traffic through the sidecar.
+//   - teardown: reverts the setup when the allocation stops.
+package mesh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// OperationRender renders the sidecar task of the group.
+	OperationRender = "render"
+
+	// OperationSetup configures the network namespace of the allocation.
+	OperationSetup = "setup"
+
+	// OperationTeardown reverts the setup of the network namespace.
+	OperationTeardown = "teardown"
+
+	// maxStderrLen is the maximum length of the stderr output of a hook
+	// included in errors.
+	maxStderrLen = 1024
+)
+
+// Request is the allocation information passed to a hook on stdin.
+type Request struct {
+	Operation string
+	Type      string
+	Region    string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	AllocID   string
+	NodeID    string
+
+	// Task is the name of the sidecar task. Set for the render operation.
+	Task string
+
+	// Config is the configuration of the mesh block of the group.
+	Config map[string]interface{}
+
+	// Network is the network of the allocation. Set for the setup and
+	// teardown operations.
+	Network *Network
+}
+
+// Network describes the network namespace of an allocation.
+type Network struct {
+	// NetNSPath is the path of the network namespace.
+	NetNSPath string
+
+	// InterfaceName and Address are the interface and the address of the
+	// allocation in its network namespace.
+	InterfaceName string
+	Address       string
+
+	// Ports are the ports allocated to the group.
+	Ports []*Port
+}
+
+// Port is a port allocated to a group.
+type Port struct {
+	Label  string
+	Value  int
+	To     int
+	HostIP string
+}
+
+// Response is the output a hook writes to stdout. Only the render operation
+// uses it; the output of the other operations may be empty.
+type Response struct {
+	// Config is the driver configuration of the sidecar task.
+	Config map[string]interface{}
+
+	// Env are the environment variables to set for the sidecar task.
+	Env map[string]string
+}
+
+// Manager runs the mesh hooks of a client. It is shared by all the alloc
+// runners of a client.
+type Manager struct {
+	logger log.Logger
+	hooks  map[string]*config.MeshHookConfig
+}
+
+// NewManager returns a Manager for the given hooks.
+func NewManager(logger log.Logger, hooks []*config.MeshHookConfig) *Manager {
+	m := &Manager{
+		logger: logger.Named("mesh_hook"),
+		hooks:  make(map[string]*config.MeshHookConfig, len(hooks)),
+	}
+	for _, h := range hooks {
+		m.hooks[h.Name] = h
+	}
+	return m
+}
+
+// Types returns the sorted mesh types a hook is configured for.
+func (m *Manager) Types() []string {
+	if m == nil {
+		return nil
+	}
+
+	types := make([]string, 0, len(m.hooks))
+	for t := range m.hooks {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// RenderSidecar returns a copy of the sidecar task of the allocation's mesh
+// with the driver configuration and environment rendered by the hook.
+func (m *Manager) RenderSidecar(ctx context.Context, alloc *structs.Allocation, task *structs.Task) (*structs.Task, error) {
+	req := newRequest(OperationRender, alloc)
+	req.Task = task.Name
+
+	resp, err := m.run(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := task.Copy()
+	rendered.Config = resp.Config
+	if len(resp.Env) != 0 {
+		rendered.Env = helper.MergeMapStringString(rendered.Env, resp.Env)
+	}
+	return rendered, nil
+}
+
+// SetupNetwork configures the network namespace of the allocation.
+func (m *Manager) SetupNetwork(ctx context.Context, alloc *structs.Allocation,
+	spec *drivers.NetworkIsolationSpec, status *structs.AllocNetworkStatus) error {
+	req := newRequest(OperationSetup, alloc)
+	req.Network = newNetwork(alloc, spec, status)
+	_, err := m.run(ctx, req)
+	return err
+}
+
+// TeardownNetwork reverts the configuration of the network namespace of the
+// allocation.
+func (m *Manager) TeardownNetwork(ctx context.Context, alloc *structs.Allocation,
+	spec *drivers.NetworkIsolationSpec, status *structs.AllocNetworkStatus) error {
+	req := newRequest(OperationTeardown, alloc)
+	req.Network = newNetwork(alloc, spec, status)
+	_, err := m.run(ctx, req)
+	return err
+}
+
+func newRequest(op string, alloc *structs.Allocation) *Request {
+	req := &Request{
+		Operation: op,
+		Region:    alloc.Job.Region,
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+		TaskGroup: alloc.TaskGroup,
+		AllocID:   alloc.ID,
+		NodeID:    alloc.NodeID,
+	}
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil && tg.Mesh != nil {
+		req.Type = tg.Mesh.Type
+		req.Config = tg.Mesh.Config
+	}
+	return req
+}
+
+func newNetwork(alloc *structs.Allocation, spec *drivers.NetworkIsolationSpec, status *structs.AllocNetworkStatus) *Network {
+	n := &Network{}
+	if spec != nil {
+		n.NetNSPath = spec.Path
+	}
+	if status != nil {
+		n.InterfaceName = status.InterfaceName
+		n.Address = status.Address
+	}
+	if ar := alloc.AllocatedResources; ar != nil {
+		for _, p := range ar.Shared.Ports {
+			n.Ports = append(n.Ports, &Port{
+				Label:  p.Label,
+				Value:  p.Value,
+				To:     p.To,
+				HostIP: p.HostIP,
+			})
+		}
+	}
+	return n
+}
+
+// run executes the hook of the mesh type of the request with the encoded
+// request on stdin.
+func (m *Manager) run(ctx context.Context, req *Request) (*Response, error) {
+	var h *config.MeshHookConfig
+	if m != nil {
+		h = m.hooks[req.Type]
+	}
+	if h == nil {
+		return nil, fmt.Errorf("no mesh hook configured for mesh type %q", req.Type)
+	}
+
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.Command, h.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	m.logger.Trace("running mesh hook", "type", req.Type, "operation", req.Operation, "command", h.Command)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("mesh hook %q %s timed out after %s", h.Name, req.Operation, h.Timeout)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxStderrLen {
+			msg = msg[:maxStderrLen]
+		}
+		if msg != "" {
+			return nil, fmt.Errorf("mesh hook %q %s failed: %v: %s", h.Name, req.Operation, err, msg)
+		}
+		return nil, fmt.Errorf("mesh hook %q %s failed: %v", h.Name, req.Operation, err)
+	}
+
+	var resp Response
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return &resp, nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("mesh hook %q %s: failed to decode output: %v", h.Name, req.Operation, err)
+	}
+	return &resp, nil
+}
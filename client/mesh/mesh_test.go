@@ -0,0 +1,134 @@
+package mesh
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+// testHook writes a shell script hook and returns its config.
+func testHook(t *testing.T, name, script string) *config.MeshHookConfig {
+	if runtime.GOOS == "windows" {
+		t.Skip("mesh hook tests use shell scripts")
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+	return &config.MeshHookConfig{
+		Name:    name,
+		Command: path,
+		Timeout: 5 * time.Second,
+	}
+}
+
+// testMeshAlloc returns an allocation whose group is integrated with a mesh
+// of the given type.
+func testMeshAlloc(meshType string) (*structs.Allocation, *structs.Task) {
+	alloc := mock.Alloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	tg.Mesh = &structs.Mesh{
+		Type:   meshType,
+		Driver: "docker",
+		Config: map[string]interface{}{"revision": "stable"},
+	}
+	sidecar := &structs.Task{
+		Name:   "mesh-sidecar-" + meshType,
+		Kind:   structs.NewTaskKind(structs.MeshSidecarPrefix, meshType),
+		Driver: "docker",
+		Env:    map[string]string{"A": "1"},
+	}
+	tg.Tasks = append(tg.Tasks, sidecar)
+	return alloc, sidecar
+}
+
+func TestManager_RenderSidecar(t *testing.T) {
+	ci.Parallel(t)
+
+	// The hook echoes the sidecar task name and mesh config from its input
+	h := testHook(t, "istio", `sed 's/.*"Task":"\([^"]*\)".*"revision":"\([^"]*\)".*/{"Config":{"image":"proxy:\2"},"Env":{"TASK":"\1"}}/'`)
+	m := NewManager(testlog.HCLogger(t), []*config.MeshHookConfig{h})
+	require.Equal(t, []string{"istio"}, m.Types())
+
+	alloc, sidecar := testMeshAlloc("istio")
+	rendered, err := m.RenderSidecar(context.Background(), alloc, sidecar)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"image": "proxy:stable"}, rendered.Config)
+	require.Equal(t, map[string]string{"A": "1", "TASK": "mesh-sidecar-istio"}, rendered.Env)
+
+	// The task of the allocation is not modified
+	require.Nil(t, sidecar.Config)
+	require.Equal(t, map[string]string{"A": "1"}, sidecar.Env)
+}
+
+func TestManager_SetupNetwork(t *testing.T) {
+	ci.Parallel(t)
+
+	// The hook writes its input to a file
+	out := filepath.Join(t.TempDir(), "request.json")
+	h := testHook(t, "istio", `cat > `+out)
+	m := NewManager(testlog.HCLogger(t), []*config.MeshHookConfig{h})
+
+	alloc, _ := testMeshAlloc("istio")
+	spec := &drivers.NetworkIsolationSpec{Path: "/var/run/netns/test"}
+	status := &structs.AllocNetworkStatus{InterfaceName: "eth0", Address: "172.26.64.2"}
+	require.NoError(t, m.SetupNetwork(context.Background(), alloc, spec, status))
+
+	b, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"Operation":"setup"`)
+	require.Contains(t, string(b), `"Type":"istio"`)
+	require.Contains(t, string(b), `"NetNSPath":"/var/run/netns/test"`)
+	require.Contains(t, string(b), `"Address":"172.26.64.2"`)
+}
+
+func TestManager_Errors(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("no hook", func(t *testing.T) {
+		m := NewManager(testlog.HCLogger(t), nil)
+		alloc, sidecar := testMeshAlloc("istio")
+
+		_, err := m.RenderSidecar(context.Background(), alloc, sidecar)
+		require.ErrorContains(t, err, `no mesh hook configured for mesh type "istio"`)
+	})
+
+	t.Run("exit status", func(t *testing.T) {
+		h := testHook(t, "istio", `echo "iptables failed" >&2; exit 1`)
+		m := NewManager(testlog.HCLogger(t), []*config.MeshHookConfig{h})
+		alloc, _ := testMeshAlloc("istio")
+
+		err := m.TeardownNetwork(context.Background(), alloc, nil, nil)
+		require.ErrorContains(t, err, `mesh hook "istio" teardown failed`)
+		require.ErrorContains(t, err, "iptables failed")
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		h := testHook(t, "istio", `echo "not json"`)
+		m := NewManager(testlog.HCLogger(t), []*config.MeshHookConfig{h})
+		alloc, sidecar := testMeshAlloc("istio")
+
+		_, err := m.RenderSidecar(context.Background(), alloc, sidecar)
+		require.ErrorContains(t, err, "failed to decode output")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		h := testHook(t, "istio", `exec sleep 10`)
+		h.Timeout = 100 * time.Millisecond
+		m := NewManager(testlog.HCLogger(t), []*config.MeshHookConfig{h})
+		alloc, sidecar := testMeshAlloc("istio")
+
+		_, err := m.RenderSidecar(context.Background(), alloc, sidecar)
+		require.ErrorContains(t, err, "timed out after 100ms")
+	})
+}
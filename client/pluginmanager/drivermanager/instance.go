@@ -362,6 +362,13 @@ func (i *instanceManager) handleFingerprint(fp *drivers.Fingerprint) {
 	for key, attr := range fp.Attributes {
 		attrs[key] = attr.GoString()
 	}
+
+	// Expose the negotiated plugin API version so operators can verify the
+	// plugin is compatible with the version of Nomad they upgrade to.
+	if v := i.apiVersion(); v != "" {
+		attrs[fmt.Sprintf("driver.%s.api_version", i.id.Name)] = v
+	}
+
 	di := &structs.DriverInfo{
 		Attributes:        attrs,
 		Detected:          fp.Health != drivers.HealthStateUndetected,
@@ -389,6 +396,17 @@ func (i *instanceManager) handleFingerprint(fp *drivers.Fingerprint) {
 	}
 }
 
+// apiVersion returns the API version negotiated with the plugin or an empty
+// string if the plugin hasn't been dispensed.
+func (i *instanceManager) apiVersion() string {
+	i.pluginLock.Lock()
+	defer i.pluginLock.Unlock()
+	if i.plugin == nil {
+		return ""
+	}
+	return i.plugin.ApiVersion()
+}
+
 // getLastHealth returns the most recent HealthState from fingerprinting
 func (i *instanceManager) getLastHealth() drivers.HealthState {
 	i.lastHealthStateMu.Lock()
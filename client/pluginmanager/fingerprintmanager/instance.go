@@ -0,0 +1,299 @@
+package fingerprintmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/fingerprint"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/helper/pluginutils/singleton"
+	"github.com/hashicorp/nomad/plugins/base"
+	bstructs "github.com/hashicorp/nomad/plugins/base/structs"
+	pfingerprint "github.com/hashicorp/nomad/plugins/fingerprint"
+)
+
+// instanceManagerConfig configures a fingerprint plugin instance manager
+type instanceManagerConfig struct {
+	// Logger is the logger used by the instance manager
+	Logger log.Logger
+
+	// Ctx is used to shutdown the instance manager
+	Ctx context.Context
+
+	// Loader is the plugin loader
+	Loader loader.PluginCatalog
+
+	// PluginConfig is the config passed to the launched plugins
+	PluginConfig *base.AgentConfig
+
+	// Id is the ID of the plugin being managed
+	Id *loader.PluginID
+
+	// Updater is used to update the node with the fingerprinted attributes
+	Updater UpdateNodeFn
+}
+
+// instanceManager is used to manage a single fingerprint plugin
+type instanceManager struct {
+	// logger is the logger used by the instance manager
+	logger log.Logger
+
+	// ctx is used to shutdown the instance manager
+	ctx context.Context
+
+	// cancel is used to shutdown management of this fingerprint plugin
+	cancel context.CancelFunc
+
+	// loader is the plugin loader
+	loader loader.PluginCatalog
+
+	// pluginConfig is the config passed to the launched plugins
+	pluginConfig *base.AgentConfig
+
+	// id is the ID of the plugin being managed
+	id *loader.PluginID
+
+	// updater is used to update the node with the fingerprinted attributes
+	updater UpdateNodeFn
+
+	// plugin is the plugin instance being managed
+	plugin loader.PluginInstance
+
+	// fingerprinter is the fingerprint plugin being managed
+	fingerprinter pfingerprint.FingerprintPlugin
+
+	// pluginLock locks access to the fingerprinter and plugin
+	pluginLock sync.Mutex
+
+	// shutdownLock is used to serialize attempts to shutdown
+	shutdownLock sync.Mutex
+
+	// attributes and links are the ones last set on the node by the plugin.
+	// They are only accessed by the fingerprint goroutine.
+	attributes map[string]string
+	links      map[string]string
+
+	// firstFingerprintCh is used to trigger that we have successfully
+	// fingerprinted once.
+	firstFingerprintCh chan struct{}
+	hasFingerprinted   bool
+}
+
+// newInstanceManager returns a new fingerprint plugin instance manager. It is
+// expected that the context passed in the configuration is cancelled in order
+// to shutdown launched goroutines.
+func newInstanceManager(c *instanceManagerConfig) *instanceManager {
+	ctx, cancel := context.WithCancel(c.Ctx)
+	i := &instanceManager{
+		logger:             c.Logger.With("plugin", c.Id.Name),
+		ctx:                ctx,
+		cancel:             cancel,
+		loader:             c.Loader,
+		pluginConfig:       c.PluginConfig,
+		id:                 c.Id,
+		updater:            c.Updater,
+		firstFingerprintCh: make(chan struct{}),
+	}
+
+	go i.run()
+	return i
+}
+
+// WaitForFirstFingerprint waits until either the plugin fingerprints, the
+// passed context is done, or the plugin instance manager is shutdown.
+func (i *instanceManager) WaitForFirstFingerprint(ctx context.Context) {
+	select {
+	case <-i.ctx.Done():
+	case <-ctx.Done():
+	case <-i.firstFingerprintCh:
+	}
+}
+
+// run is a long lived goroutine that fingerprints the node with the plugin
+// and then shutsdown the plugin on exit.
+func (i *instanceManager) run() {
+	// Dispense once to ensure we are given a valid plugin
+	if _, err := i.dispense(); err != nil {
+		i.logger.Error("dispensing initial plugin failed", "error", err)
+		i.cancel()
+		return
+	}
+
+	i.fingerprint()
+	i.cleanup()
+}
+
+// dispense is used to dispense a plugin.
+func (i *instanceManager) dispense() (plugin pfingerprint.FingerprintPlugin, err error) {
+	i.pluginLock.Lock()
+	defer i.pluginLock.Unlock()
+
+	// See if we already have a running instance
+	if i.plugin != nil && !i.plugin.Exited() {
+		return i.fingerprinter, nil
+	}
+
+	// Get an instance of the plugin
+	pluginInstance, err := i.loader.Dispense(i.id.Name, i.id.PluginType, i.pluginConfig, i.logger)
+	if err != nil {
+		// Retry as the error just indicates the singleton has exited
+		if err == singleton.SingletonPluginExited {
+			pluginInstance, err = i.loader.Dispense(i.id.Name, i.id.PluginType, i.pluginConfig, i.logger)
+		}
+
+		// If we still have an error there is a real problem
+		if err != nil {
+			return nil, fmt.Errorf("failed to start plugin: %v", err)
+		}
+	}
+
+	// Convert to a fingerprint plugin
+	fingerprinter, ok := pluginInstance.Plugin().(pfingerprint.FingerprintPlugin)
+	if !ok {
+		pluginInstance.Kill()
+		return nil, fmt.Errorf("plugin loaded does not implement the fingerprint interface")
+	}
+
+	// Store the plugin and fingerprinter
+	i.plugin = pluginInstance
+	i.fingerprinter = fingerprinter
+
+	return fingerprinter, nil
+}
+
+// cleanup shutsdown the plugin
+func (i *instanceManager) cleanup() {
+	i.shutdownLock.Lock()
+	i.pluginLock.Lock()
+	defer i.pluginLock.Unlock()
+	defer i.shutdownLock.Unlock()
+
+	if i.plugin != nil && !i.plugin.Exited() {
+		i.plugin.Kill()
+	}
+}
+
+// fingerprint is a long lived routine used to fingerprint the node with the
+// plugin
+func (i *instanceManager) fingerprint() {
+START:
+	// Get a fingerprint plugin
+	fingerprinter, err := i.dispense()
+	if err != nil {
+		i.logger.Error("dispensing plugin failed", "error", err)
+		i.handleFingerprintError()
+		return
+	}
+
+	// Start fingerprinting
+	fingerprintCh, err := fingerprinter.Fingerprint(i.ctx)
+	if err == pfingerprint.ErrPluginDisabled {
+		i.logger.Info("fingerprinting failed: plugin is not enabled")
+		i.handleFingerprintError()
+		return
+	} else if err != nil {
+		i.logger.Error("fingerprinting failed", "error", err)
+		i.handleFingerprintError()
+		return
+	}
+
+	var fresp *pfingerprint.FingerprintResponse
+	var ok bool
+	for {
+		select {
+		case <-i.ctx.Done():
+			return
+		case fresp, ok = <-fingerprintCh:
+		}
+
+		if !ok {
+			i.logger.Trace("exiting since fingerprinting gracefully shutdown")
+			i.handleFingerprintError()
+			return
+		}
+
+		// Guard against error by the plugin
+		if fresp == nil {
+			continue
+		}
+
+		// Handle any errors
+		if fresp.Error != nil {
+			if fresp.Error == bstructs.ErrPluginShutdown {
+				i.logger.Error("plugin exited unexpectedly")
+				goto START
+			}
+
+			i.logger.Error("fingerprinting returned an error", "error", fresp.Error)
+			i.handleFingerprintError()
+			return
+		}
+
+		i.handleFingerprint(fresp)
+	}
+}
+
+// handleFingerprintError removes the attributes set by the plugin from the
+// node and exits the instance manager.
+func (i *instanceManager) handleFingerprintError() {
+	if i.hasFingerprinted {
+		i.update(nil, nil)
+	}
+
+	// Cancel the context so we cleanup all goroutines
+	i.cancel()
+}
+
+// handleFingerprint updates the node with the fingerprinted attributes and
+// links.
+func (i *instanceManager) handleFingerprint(f *pfingerprint.FingerprintResponse) {
+	attributes := make(map[string]string, len(f.Attributes))
+	for k, v := range f.Attributes {
+		if v == nil {
+			continue
+		}
+		attributes[k] = v.GoString()
+	}
+	i.update(attributes, f.Links)
+
+	// Mark that we have received data
+	if !i.hasFingerprinted {
+		close(i.firstFingerprintCh)
+		i.hasFingerprinted = true
+	}
+}
+
+// update sets the attributes and links on the node, removing the ones set by
+// a previous fingerprint that are now missing.
+func (i *instanceManager) update(attributes, links map[string]string) {
+	resp := &fingerprint.FingerprintResponse{
+		Attributes: diffValues(i.attributes, attributes),
+		Links:      diffValues(i.links, links),
+	}
+	i.attributes = attributes
+	i.links = links
+
+	if len(resp.Attributes) == 0 && len(resp.Links) == 0 {
+		return
+	}
+	i.updater(resp)
+}
+
+// diffValues returns the values to set to go from old to new. Removed values
+// are set to an empty string.
+func diffValues(old, new map[string]string) map[string]string {
+	out := make(map[string]string)
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			out[k] = ""
+		}
+	}
+	for k, v := range new {
+		if old[k] != v {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+// Package fingerprintmanager is used to manage fingerprint plugins
+package fingerprintmanager
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/fingerprint"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+)
+
+// UpdateNodeFn is a callback for updating the attributes and links of a node.
+// Attributes and links set to an empty value are removed from the node.
+type UpdateNodeFn func(*fingerprint.FingerprintResponse) *structs.Node
+
+// Config is used to configure a fingerprint manager
+type Config struct {
+	// Logger is the logger used by the fingerprint manager
+	Logger log.Logger
+
+	// Loader is the plugin loader
+	Loader loader.PluginCatalog
+
+	// PluginConfig is the config passed to the launched plugins
+	PluginConfig *base.AgentConfig
+
+	// Updater is used to update the node when the fingerprinted attributes
+	// change
+	Updater UpdateNodeFn
+}
+
+// manager is used to manage a set of fingerprint plugins
+type manager struct {
+	// logger is the logger used by the fingerprint manager
+	logger log.Logger
+
+	// ctx is used to shutdown the fingerprint manager
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// loader is the plugin loader
+	loader loader.PluginCatalog
+
+	// pluginConfig is the config passed to the launched plugins
+	pluginConfig *base.AgentConfig
+
+	// updater is used to update the node when the fingerprinted attributes
+	// change
+	updater UpdateNodeFn
+
+	// instances is the list of managed fingerprint plugins
+	instances map[loader.PluginID]*instanceManager
+}
+
+// New returns a new fingerprint plugin manager
+func New(c *Config) *manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &manager{
+		logger:       c.Logger.Named("fingerprint_plugin_mgr"),
+		ctx:          ctx,
+		cancel:       cancel,
+		loader:       c.Loader,
+		pluginConfig: c.PluginConfig,
+		updater:      c.Updater,
+		instances:    make(map[loader.PluginID]*instanceManager),
+	}
+}
+
+// PluginType identifies this manager to the plugin manager and satisfies the PluginManager interface.
+func (*manager) PluginType() string { return base.PluginTypeFingerprint }
+
+// Run starts the fingerprint manager, launching and fingerprinting all the
+// fingerprint plugins.
+func (m *manager) Run() {
+	plugins := m.loader.Catalog()[base.PluginTypeFingerprint]
+	if len(plugins) == 0 {
+		m.logger.Debug("exiting since there are no fingerprint plugins")
+		m.cancel()
+		return
+	}
+
+	for _, p := range plugins {
+		id := loader.PluginInfoID(p)
+		m.instances[id] = newInstanceManager(&instanceManagerConfig{
+			Logger:       m.logger,
+			Ctx:          m.ctx,
+			Loader:       m.loader,
+			PluginConfig: m.pluginConfig,
+			Id:           &id,
+			Updater:      m.updater,
+		})
+	}
+}
+
+// Shutdown cleans up all the plugins
+func (m *manager) Shutdown() {
+	// Cancel the context to stop any requests
+	m.cancel()
+
+	// Go through and shut everything down
+	for _, i := range m.instances {
+		i.cleanup()
+	}
+}
+
+func (m *manager) WaitForFirstFingerprint(ctx context.Context) <-chan struct{} {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		var wg sync.WaitGroup
+		for i := range m.instances {
+			wg.Add(1)
+			go func(instance *instanceManager) {
+				instance.WaitForFirstFingerprint(ctx)
+				wg.Done()
+			}(m.instances[i])
+		}
+		wg.Wait()
+		cancel()
+	}()
+	return ctx.Done()
+}
@@ -0,0 +1,161 @@
+package fingerprintmanager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/fingerprint"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	pfingerprint "github.com/hashicorp/nomad/plugins/fingerprint"
+	pstructs "github.com/hashicorp/nomad/plugins/shared/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testManager(t *testing.T, plugins map[string]*pfingerprint.MockFingerprintPlugin) (
+	*manager, chan *fingerprint.FingerprintResponse, map[string]loader.PluginInstance) {
+
+	instances := make(map[string]loader.PluginInstance, len(plugins))
+	infos := make([]*base.PluginInfoResponse, 0, len(plugins))
+	for name, p := range plugins {
+		instances[name] = loader.MockBasicExternalPlugin(p, pfingerprint.ApiVersion010)
+		infos = append(infos, &base.PluginInfoResponse{
+			Type:              base.PluginTypeFingerprint,
+			PluginApiVersions: []string{pfingerprint.ApiVersion010},
+			PluginVersion:     "v0.0.1",
+			Name:              name,
+		})
+	}
+
+	catalog := &loader.MockCatalog{
+		DispenseF: func(name, _ string, _ *base.AgentConfig, _ log.Logger) (loader.PluginInstance, error) {
+			if inst, ok := instances[name]; ok {
+				return inst, nil
+			}
+			return nil, fmt.Errorf("no matching plugin")
+		},
+		CatalogF: func() map[string][]*base.PluginInfoResponse {
+			return map[string][]*base.PluginInfoResponse{
+				base.PluginTypeFingerprint: infos,
+			}
+		},
+	}
+
+	updates := make(chan *fingerprint.FingerprintResponse, 10)
+	m := New(&Config{
+		Logger:       testlog.HCLogger(t),
+		Loader:       catalog,
+		PluginConfig: &base.AgentConfig{},
+		Updater: func(resp *fingerprint.FingerprintResponse) *structs.Node {
+			updates <- resp
+			return nil
+		},
+	})
+	return m, updates, instances
+}
+
+func waitForUpdate(t *testing.T, updates chan *fingerprint.FingerprintResponse) *fingerprint.FingerprintResponse {
+	select {
+	case resp := <-updates:
+		return resp
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for node update")
+	}
+	return nil
+}
+
+func TestManager_Fingerprint(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	respCh := make(chan *pfingerprint.FingerprintResponse)
+	plugins := map[string]*pfingerprint.MockFingerprintPlugin{
+		"numa": {
+			FingerprintF: func(context.Context) (<-chan *pfingerprint.FingerprintResponse, error) {
+				return respCh, nil
+			},
+		},
+	}
+
+	m, updates, instances := testManager(t, plugins)
+	m.Run()
+	defer m.Shutdown()
+
+	// The first fingerprint sets the attributes and links
+	respCh <- &pfingerprint.FingerprintResponse{
+		Attributes: map[string]*pstructs.Attribute{
+			"numa.nodes":   pstructs.NewIntAttribute(2, ""),
+			"numa.enabled": pstructs.NewBoolAttribute(true),
+		},
+		Links: map[string]string{"inventory": "rack-12"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	<-m.WaitForFirstFingerprint(ctx)
+	require.NoError(ctx.Err())
+
+	resp := waitForUpdate(t, updates)
+	require.Equal(map[string]string{"numa.nodes": "2", "numa.enabled": "true"}, resp.Attributes)
+	require.Equal(map[string]string{"inventory": "rack-12"}, resp.Links)
+
+	// Attributes missing from the next fingerprint are removed
+	respCh <- &pfingerprint.FingerprintResponse{
+		Attributes: map[string]*pstructs.Attribute{
+			"numa.nodes": pstructs.NewIntAttribute(4, ""),
+		},
+		Links: map[string]string{"inventory": "rack-12"},
+	}
+	resp = waitForUpdate(t, updates)
+	require.Equal(map[string]string{"numa.nodes": "4", "numa.enabled": ""}, resp.Attributes)
+	require.Empty(resp.Links)
+
+	// A fingerprinting error removes all the attributes and links
+	respCh <- pfingerprint.NewFingerprintError(fmt.Errorf("failed"))
+	resp = waitForUpdate(t, updates)
+	require.Equal(map[string]string{"numa.nodes": ""}, resp.Attributes)
+	require.Equal(map[string]string{"inventory": ""}, resp.Links)
+
+	// The plugin is killed once it stops fingerprinting
+	require.Eventually(func() bool {
+		return instances["numa"].Exited()
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_Shutdown(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	plugins := map[string]*pfingerprint.MockFingerprintPlugin{
+		"numa": {
+			FingerprintF: pfingerprint.StaticFingerprinter(map[string]*pstructs.Attribute{
+				"numa.nodes": pstructs.NewIntAttribute(2, ""),
+			}),
+		},
+		"topology": {
+			FingerprintF: pfingerprint.StaticFingerprinter(map[string]*pstructs.Attribute{
+				"topology.zone": pstructs.NewStringAttribute("a"),
+			}),
+		},
+	}
+
+	m, _, instances := testManager(t, plugins)
+	m.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	<-m.WaitForFirstFingerprint(ctx)
+	require.NoError(ctx.Err())
+
+	// Call shutdown and assert that we killed the plugins
+	m.Shutdown()
+	for name, inst := range instances {
+		require.True(inst.Exited(), name)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -30,7 +32,8 @@ type Checker interface {
 	Do(context.Context, *QueryContext, *Query) *structs.CheckQueryResult
 }
 
-// New creates a new Checker capable of executing HTTP and TCP checks.
+// New creates a new Checker capable of executing HTTP, TCP and readiness file
+// checks.
 func New(log hclog.Logger) Checker {
 	httpClient := cleanhttp.DefaultPooledClient()
 	httpClient.Timeout = maxTimeoutHTTP
@@ -61,6 +64,8 @@ func (c *checker) Do(ctx context.Context, qc *QueryContext, q *Query) *structs.C
 	switch q.Type {
 	case "http":
 		qr = c.checkHTTP(timeout, qc, q)
+	case structs.ServiceCheckReadinessFile:
+		qr = c.checkReadinessFile(qc, q)
 	default:
 		qr = c.checkTCP(timeout, qc, q)
 	}
@@ -192,6 +197,24 @@ func (c *checker) checkHTTP(ctx context.Context, qc *QueryContext, q *Query) *st
 	return qr
 }
 
+func (c *checker) checkReadinessFile(qc *QueryContext, q *Query) *structs.CheckQueryResult {
+	qr := &structs.CheckQueryResult{
+		Mode:      q.Mode,
+		Timestamp: c.now(),
+		Status:    structs.CheckPending,
+	}
+
+	if _, err := os.Stat(filepath.Join(qc.AllocDir, q.Path)); err != nil {
+		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	qr.Output = "nomad: readiness file ok"
+	qr.Status = structs.CheckSuccess
+	return qr
+}
+
 const (
 	// outputSizeLimit is the maximum number of bytes to read and store of an http
 	// check output. Set to 3kb which fits in 1 page with room for other fields.
@@ -7,6 +7,8 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -327,6 +329,48 @@ func TestChecker_Do_TCP(t *testing.T) {
 	}
 }
 
+func TestChecker_Do_ReadinessFile(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	clock := libtimetest.NewClockMock(t).NowMock.Return(now)
+
+	allocDir := t.TempDir()
+	must.NoError(t, os.MkdirAll(filepath.Join(allocDir, "ready"), 0755))
+	must.NoError(t, os.WriteFile(filepath.Join(allocDir, "ready", "web"), nil, 0644))
+
+	qc := &QueryContext{
+		ID:       "abc123",
+		AllocDir: allocDir,
+		Group:    "group",
+		Task:     "task",
+		Service:  "service",
+		Check:    "check",
+	}
+
+	makeQuery := func(path string) *Query {
+		return &Query{
+			Mode:    structs.Readiness,
+			Type:    structs.ServiceCheckReadinessFile,
+			Timeout: 100 * time.Millisecond,
+			Path:    path,
+		}
+	}
+
+	c := New(testlog.HCLogger(t))
+	c.(*checker).clock = clock
+
+	result := c.Do(context.Background(), qc, makeQuery("ready/web"))
+	must.Eq(t, structs.CheckSuccess, result.Status)
+	must.Eq(t, "nomad: readiness file ok", result.Output)
+	must.Eq(t, now.Unix(), result.Timestamp)
+	must.Eq(t, "check", result.Check)
+
+	result = c.Do(context.Background(), qc, makeQuery("ready/api"))
+	must.Eq(t, structs.CheckFailure, result.Status)
+	must.True(t, strings.Contains(result.Output, "no such file or directory"))
+}
+
 func tcpServer(ctx context.Context, port int) {
 	var lc net.ListenConfig
 	l, _ := lc.Listen(ctx, "tcp", net.JoinHostPort(
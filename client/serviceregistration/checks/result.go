@@ -28,7 +28,7 @@ func GetCheckQuery(c *structs.ServiceCheck) *Query {
 // amount of information needed to actually execute that check.
 type Query struct {
 	Mode structs.CheckMode // readiness or healthiness
-	Type string            // tcp or http
+	Type string            // tcp, http or readiness_file
 
 	Timeout time.Duration // connection / request timeout
 
@@ -36,7 +36,7 @@ type Query struct {
 	PortLabel   string // label or value
 
 	Protocol string // http checks only (http or https)
-	Path     string // http checks only
+	Path     string // http and readiness_file checks only
 	Method   string // http checks only
 }
 
@@ -49,6 +49,7 @@ type QueryContext struct {
 	Networks         structs.Networks
 	NetworkStatus    structs.NetworkStatus
 	Ports            structs.AllocatedPorts
+	AllocDir         string // shared alloc dir on the host
 
 	Group   string
 	Task    string
@@ -11,6 +11,10 @@ import (
 type AllocHookResources struct {
 	CSIMounts map[string]*csimanager.MountInfo
 
+	// Peers are the addresses of the other allocations of the job, keyed by
+	// the name of the Nomad service of the task group they register.
+	Peers map[string][]string
+
 	mu sync.RWMutex
 }
 
@@ -27,3 +31,17 @@ func (a *AllocHookResources) SetCSIMounts(m map[string]*csimanager.MountInfo) {
 
 	a.CSIMounts = m
 }
+
+func (a *AllocHookResources) GetPeers() map[string][]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.Peers
+}
+
+func (a *AllocHookResources) SetPeers(p map[string][]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.Peers = p
+}
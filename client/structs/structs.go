@@ -34,6 +34,54 @@ type ClientStatsResponse struct {
 	structs.QueryMeta
 }
 
+// ClientInventoryResponse is used to return the tasks running on a node.
+type ClientInventoryResponse struct {
+	Inventory *ClientInventory
+	structs.QueryMeta
+}
+
+// ClientInventory is the inventory of the tasks running on a node.
+type ClientInventory struct {
+	NodeID string
+	Tasks  []*TaskInventory
+}
+
+// TaskInventory describes a task running on a node: the labels identifying it
+// and the resources allocated to it.
+type TaskInventory struct {
+	Namespace string
+	JobID     string
+	TaskGroup string
+	Task      string
+	AllocID   string
+	AllocName string
+
+	// StartedAt is the time the task was last started.
+	StartedAt time.Time
+
+	// CPU is the CPU allocated to the task in MHz, and Cores the cores
+	// reserved for it.
+	CPU   int64
+	Cores []uint16
+
+	// MemoryMB is the memory allocated to the task, and MemoryMaxMB the
+	// memory it may use if oversubscription is enabled.
+	MemoryMB    int64
+	MemoryMaxMB int64
+}
+
+// PrepareUpgradeResponse is the result of flushing the client state before the
+// client is restarted in place.
+type PrepareUpgradeResponse struct {
+	// Recoverable is the number of running tasks a restarted client would
+	// reattach to.
+	Recoverable int
+
+	// Unrecoverable are the running tasks a restarted client wouldn't be
+	// able to reattach to.
+	Unrecoverable []*structs.NodeTaskRestoreFailure
+}
+
 // MonitorRequest is used to request and stream logs from a client node.
 type MonitorRequest struct {
 	// LogLevel is the log level filter we want to stream logs on
@@ -179,6 +227,10 @@ type AllocExecRequest struct {
 	// Cmd is the command to be executed
 	Cmd []string
 
+	// DebugShell runs Cmd with the debug shell binary of the client instead
+	// of a binary of the task, so tasks without a shell can be debugged.
+	DebugShell bool
+
 	structs.QueryOptions
 }
 
@@ -196,6 +248,82 @@ type AllocChecksResponse struct {
 	Results map[structs.CheckID]*structs.CheckQueryResult
 }
 
+// AllocTemplatesRequest is used to request the state of the templates of a
+// given allocation, potentially filtering by task.
+type AllocTemplatesRequest struct {
+	// AllocID is the allocation to retrieve the templates of
+	AllocID string
+
+	// Task is an optional filter to only request the templates of the task.
+	Task string
+
+	structs.QueryOptions
+}
+
+// AllocTemplatesResponse is used to return the state of the templates of an
+// allocation.
+type AllocTemplatesResponse struct {
+	// Tasks maps the names of the tasks to the state of their templates.
+	Tasks map[string][]*TemplateStatus
+
+	structs.QueryMeta
+}
+
+const (
+	// TemplateDependencyStatusOK is the status of a dependency whose data was
+	// fetched.
+	TemplateDependencyStatusOK = "ok"
+
+	// TemplateDependencyStatusMissing is the status of a dependency whose
+	// data is being fetched.
+	TemplateDependencyStatusMissing = "missing"
+
+	// TemplateDependencyStatusUnwatched is the status of a dependency that
+	// isn't being fetched yet.
+	TemplateDependencyStatusUnwatched = "unwatched"
+)
+
+// TemplateStatus is the rendering state of a template and of the
+// dependencies it watches.
+type TemplateStatus struct {
+	// DestPath is the destination of the template.
+	DestPath string
+
+	// Rendered is true once the data of all the dependencies of the template
+	// was fetched and the template rendered.
+	Rendered bool
+
+	// LastRendered is the last time the template was written to disk.
+	LastRendered time.Time
+
+	// LastUpdate is the last time the template was evaluated with newly
+	// fetched data.
+	LastUpdate time.Time
+
+	// Error is the last error rendering the template which isn't specific
+	// to one of its dependencies.
+	Error string
+
+	// Dependencies are the Consul, Vault and Nomad dependencies used by the
+	// template.
+	Dependencies []*TemplateDependency
+}
+
+// TemplateDependency is the state of a dependency watched by a template.
+type TemplateDependency struct {
+	// Type is the source of the dependency: consul, vault, nomad or local.
+	Type string
+
+	// Name identifies the dependency, such as "kv.block(app/config)".
+	Name string
+
+	// Status is either ok, missing or unwatched.
+	Status string
+
+	// Error is the last error fetching the dependency.
+	Error string
+}
+
 // AllocStatsRequest is used to request the resource usage of a given
 // allocation, potentially filtering by task
 type AllocStatsRequest struct {
@@ -226,6 +354,12 @@ type MemoryStats struct {
 	KernelUsage    uint64
 	KernelMaxUsage uint64
 
+	// Limit and Reservation are the hard and soft memory limits of the task
+	// in bytes, when enforced by the driver. The reservation is only set
+	// when memory oversubscription allows the task to exceed it.
+	Limit       uint64
+	Reservation uint64
+
 	// A list of fields whose values were actually sampled
 	Measured []string
 }
@@ -243,6 +377,8 @@ func (ms *MemoryStats) Add(other *MemoryStats) {
 	ms.MaxUsage += other.MaxUsage
 	ms.KernelUsage += other.KernelUsage
 	ms.KernelMaxUsage += other.KernelMaxUsage
+	ms.Limit += other.Limit
+	ms.Reservation += other.Reservation
 	ms.Measured = joinStringSet(ms.Measured, other.Measured)
 }
 
@@ -278,6 +414,19 @@ type ResourceUsage struct {
 	MemoryStats *MemoryStats
 	CpuStats    *CpuStats
 	DeviceStats []*device.DeviceGroupStats
+
+	// Process identifies the process the usage belongs to. It is only set
+	// for the per-process usages in TaskResourceUsage.Pids.
+	Process *ProcessInfo
+}
+
+// ProcessInfo identifies a process of a task
+type ProcessInfo struct {
+	// PPID is the pid of the parent process
+	PPID int
+
+	// Command is the command line of the process
+	Command string
 }
 
 func (ru *ResourceUsage) Add(other *ResourceUsage) {
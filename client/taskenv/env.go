@@ -60,6 +60,10 @@ const (
 	// GroupName is the environment variable for passing the task group name.
 	GroupName = "NOMAD_GROUP_NAME"
 
+	// GroupCount is the environment variable for passing the number of
+	// allocations of the task group.
+	GroupCount = "NOMAD_GROUP_COUNT"
+
 	// JobID is the environment variable for passing the job ID.
 	JobID = "NOMAD_JOB_ID"
 
@@ -116,6 +120,11 @@ const (
 	// UpstreamPrefix is the prefix for passing upstream IP and ports to the alloc
 	UpstreamPrefix = "NOMAD_UPSTREAM_"
 
+	// PeersPrefix is the prefix for passing the addresses of the other
+	// allocations of the job registering a Nomad service of the task group.
+	// E.g $NOMAD_PEERS_db=10.0.0.1:5432,10.0.0.2:5432
+	PeersPrefix = "NOMAD_PEERS_"
+
 	// VaultToken is the environment variable for passing the Vault token
 	VaultToken = "VAULT_TOKEN"
 
@@ -411,6 +420,7 @@ type Builder struct {
 	memMaxLimit      int64
 	taskName         string
 	allocIndex       int
+	groupCount       int
 	datacenter       string
 	cgroupParent     string
 	namespace        string
@@ -520,6 +530,9 @@ func (b *Builder) buildEnv(allocDir, localDir, secretsDir string,
 	if b.allocIndex != -1 {
 		envMap[AllocIndex] = strconv.Itoa(b.allocIndex)
 	}
+	if b.groupCount != 0 {
+		envMap[GroupCount] = strconv.Itoa(b.groupCount)
+	}
 	if b.taskName != "" {
 		envMap[TaskName] = b.taskName
 	}
@@ -608,7 +621,7 @@ func (b *Builder) buildEnv(allocDir, localDir, secretsDir string,
 	}
 
 	// Clean keys (see #2405)
-	prefixesToClean := [...]string{AddrPrefix, IpPrefix, PortPrefix, HostPortPrefix, MetaPrefix}
+	prefixesToClean := [...]string{AddrPrefix, IpPrefix, PortPrefix, HostPortPrefix, MetaPrefix, PeersPrefix}
 	cleanedEnv := make(map[string]string, len(envMap))
 	for k, v := range envMap {
 		cleanedK := k
@@ -746,6 +759,7 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 	}
 
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	b.groupCount = tg.Count
 
 	b.otherPorts = make(map[string]string, len(tg.Tasks)*2)
 
@@ -114,8 +114,8 @@ func (c *ACLBootstrapCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, token)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, token)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -22,14 +22,26 @@ Usage: nomad acl policy info <name>
 
 General Options:
 
-  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Info Options:
+
+  -json
+    Output the ACL policy in a JSON format.
+
+  -t
+    Format and display the ACL policy using a Go template.
+`
 
 	return strings.TrimSpace(helpText)
 }
 
 func (c *ACLPolicyInfoCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
-		complete.Flags{})
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
 }
 
 func (c *ACLPolicyInfoCommand) AutocompleteArgs() complete.Predictor {
@@ -43,8 +55,14 @@ func (c *ACLPolicyInfoCommand) Synopsis() string {
 func (c *ACLPolicyInfoCommand) Name() string { return "acl policy info" }
 
 func (c *ACLPolicyInfoCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -74,6 +92,17 @@ func (c *ACLPolicyInfoCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, policy)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
 	c.Ui.Output(formatKVPolicy(policy))
 	return 0
 }
@@ -90,8 +90,8 @@ func (c *ACLPolicyListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, policies)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, policies)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
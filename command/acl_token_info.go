@@ -19,14 +19,26 @@ Usage: nomad acl token info <token_accessor_id>
 
 General Options:
 
-  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Info Options:
+
+  -json
+    Output the ACL token in a JSON format.
+
+  -t
+    Format and display the ACL token using a Go template.
+`
 
 	return strings.TrimSpace(helpText)
 }
 
 func (c *ACLTokenInfoCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
-		complete.Flags{})
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
 }
 
 func (c *ACLTokenInfoCommand) AutocompleteArgs() complete.Predictor {
@@ -40,8 +52,14 @@ func (c *ACLTokenInfoCommand) Synopsis() string {
 func (c *ACLTokenInfoCommand) Name() string { return "acl token info" }
 
 func (c *ACLTokenInfoCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -71,6 +89,17 @@ func (c *ACLTokenInfoCommand) Run(args []string) int {
 	}
 
 	// Format the output
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, token)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
 	c.Ui.Output(formatKVACLToken(token))
 	return 0
 }
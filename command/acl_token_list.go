@@ -87,8 +87,8 @@ func (c *ACLTokenListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, tokens)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, tokens)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
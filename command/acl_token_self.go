@@ -19,13 +19,26 @@ Usage: nomad acl token self
 
 General Options:
 
-  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Self Options:
+
+  -json
+    Output the ACL token in a JSON format.
+
+  -t
+    Format and display the ACL token using a Go template.
+`
 
 	return strings.TrimSpace(helpText)
 }
 
 func (c *ACLTokenSelfCommand) AutocompleteFlags() complete.Flags {
-	return c.Meta.AutocompleteFlags(FlagSetClient)
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
 }
 
 func (c *ACLTokenSelfCommand) AutocompleteArgs() complete.Predictor {
@@ -39,8 +52,14 @@ func (c *ACLTokenSelfCommand) Synopsis() string {
 func (c *ACLTokenSelfCommand) Name() string { return "acl token self" }
 
 func (c *ACLTokenSelfCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -67,6 +86,17 @@ func (c *ACLTokenSelfCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, token)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
 	// Format the output
 	c.Ui.Output(formatKVACLToken(token))
 	return 0
@@ -71,6 +71,9 @@ type Agent struct {
 	httpLogger log.Logger
 	logOutput  io.Writer
 
+	// logLevels holds the log levels overridden at runtime per subsystem
+	logLevels *logLevelOverrides
+
 	// EnterpriseAgent holds information and methods for enterprise functionality
 	EnterpriseAgent *EnterpriseAgent
 
@@ -130,6 +133,7 @@ func NewAgent(config *Config, logger log.InterceptLogger, logOutput io.Writer, i
 	// Create the loggers
 	a.logger = logger
 	a.httpLogger = a.logger.ResetNamed("http")
+	a.logLevels = newLogLevelOverrides(a.logger, logOutput, config.LogJson, log.LevelFromString(config.LogLevel))
 
 	// Global logger should match internal logger as much as possible
 	golog.SetFlags(golog.LstdFlags | golog.Lmicroseconds)
@@ -247,9 +251,18 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.Server.NonVotingServer {
 		conf.NonVoter = true
 	}
+	if agentConfig.Server.ReadReplica {
+		if agentConfig.Server.NumSchedulers != nil && *agentConfig.Server.NumSchedulers != 0 {
+			return nil, fmt.Errorf("num_schedulers must be 0 on a read replica")
+		}
+		conf.ReadReplica = true
+		conf.NonVoter = true
+		conf.NumSchedulers = 0
+	}
 	if agentConfig.Server.RedundancyZone != "" {
 		conf.RedundancyZone = agentConfig.Server.RedundancyZone
 	}
+	conf.RequireNodeIntroToken = agentConfig.Server.RequireNodeIntroToken
 	if agentConfig.Server.UpgradeVersion != "" {
 		conf.UpgradeVersion = agentConfig.Server.UpgradeVersion
 	}
@@ -349,6 +362,15 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.JobGCThreshold = dur
 	}
+	if window := agentConfig.Server.JobTrashWindow; window != "" {
+		dur, err := time.ParseDuration(window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse job_trash_window: %v", err)
+		} else if dur < 0 {
+			return nil, fmt.Errorf("job_trash_window must not be negative: %s", window)
+		}
+		conf.JobTrashWindow = dur
+	}
 	if gcThreshold := agentConfig.Server.EvalGCThreshold; gcThreshold != "" {
 		dur, err := time.ParseDuration(gcThreshold)
 		if err != nil {
@@ -356,6 +378,18 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.EvalGCThreshold = dur
 	}
+	if max := agentConfig.Server.EvalGCMaxPerJob; max != 0 {
+		if max < 0 {
+			return nil, fmt.Errorf("eval_gc_max_per_job must be positive: %d", max)
+		}
+		conf.EvalGCMaxPerJob = max
+	}
+	if max := agentConfig.Server.AllocGCMaxPerJob; max != 0 {
+		if max < 0 {
+			return nil, fmt.Errorf("alloc_gc_max_per_job must be positive: %d", max)
+		}
+		conf.AllocGCMaxPerJob = max
+	}
 	if gcThreshold := agentConfig.Server.DeploymentGCThreshold; gcThreshold != "" {
 		dur, err := time.ParseDuration(gcThreshold)
 		if err != nil {
@@ -473,6 +507,16 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.RaftBoltNoFreelistSync = bolt.NoFreelistSync
 	}
 
+	conf.RPCCompression = agentConfig.Server.RPCCompression
+
+	// Set the snapshot agent configuration
+	if snapshotAgent := agentConfig.Server.SnapshotAgent; snapshotAgent != nil {
+		if err := snapshotAgent.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid snapshot_agent configuration: %v", err)
+		}
+		conf.SnapshotAgentConfig = snapshotAgent.Copy()
+	}
+
 	return conf, nil
 }
 
@@ -620,6 +664,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.MaxDynamicPort = agentConfig.Client.MaxDynamicPort
 	conf.MinDynamicPort = agentConfig.Client.MinDynamicPort
 	conf.DisableRemoteExec = agentConfig.Client.DisableRemoteExec
+	conf.DebugShellPath = agentConfig.Client.DebugShell
 
 	if agentConfig.Client.TemplateConfig != nil {
 		conf.TemplateConfig = agentConfig.Client.TemplateConfig.Copy()
@@ -637,6 +682,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.Node.Name = agentConfig.NodeName
 	conf.Node.Meta = agentConfig.Client.Meta
 	conf.Node.NodeClass = agentConfig.Client.NodeClass
+	conf.IntroToken = agentConfig.Client.IntroToken
 
 	// Set up the HTTP advertise address
 	conf.Node.HTTPAddr = agentConfig.AdvertiseAddrs.HTTP
@@ -738,6 +784,22 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 	conf.Artifact = artifactConfig
 
+	for _, ep := range agentConfig.Client.EnvProviders {
+		envProviderConfig, err := clientconfig.EnvProviderConfigFromAgent(ep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid env_provider %q config: %v", ep.Name, err)
+		}
+		conf.EnvProviders = append(conf.EnvProviders, envProviderConfig)
+	}
+
+	for _, mh := range agentConfig.Client.MeshHooks {
+		meshHookConfig, err := clientconfig.MeshHookConfigFromAgent(mh)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mesh_hook %q config: %v", mh.Name, err)
+		}
+		conf.MeshHooks = append(conf.MeshHooks, meshHookConfig)
+	}
+
 	return conf, nil
 }
 
@@ -1065,6 +1127,8 @@ func (a *Agent) Shutdown() error {
 		a.logger.Error("shutting down Consul client failed", "error", err)
 	}
 
+	a.logLevels.Shutdown()
+
 	a.logger.Info("shutdown complete")
 	a.shutdown = true
 	close(a.shutdownCh)
@@ -23,7 +23,6 @@ import (
 	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/serf/serf"
-	"github.com/mitchellh/copystructure"
 )
 
 type Member struct {
@@ -81,29 +80,100 @@ func (s *HTTPServer) AgentSelfRequest(resp http.ResponseWriter, req *http.Reques
 		Member: nomadMember(member),
 		Stats:  s.agent.Stats(),
 	}
-	if ac, err := copystructure.Copy(s.agent.config); err != nil {
+	if ac, err := s.agent.config.Redacted(); err != nil {
 		return nil, CodedError(500, err.Error())
 	} else {
-		self.Config = ac.(*Config)
+		self.Config = ac
 	}
 
-	if self.Config != nil && self.Config.Vault != nil && self.Config.Vault.Token != "" {
-		self.Config.Vault.Token = "<redacted>"
+	return self, nil
+}
+
+// AgentConfigRequest returns the redacted configuration of the agent. When
+// the effective query parameter is set, the configuration is flattened by
+// field path and each value is annotated with where it was set.
+func (s *HTTPServer) AgentConfigRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
 	}
 
-	if self.Config != nil && self.Config.ACL != nil && self.Config.ACL.ReplicationToken != "" {
-		self.Config.ACL.ReplicationToken = "<redacted>"
+	aclObj, err := s.ResolveToken(req)
+	if err != nil {
+		return nil, err
 	}
 
-	if self.Config != nil && self.Config.Consul != nil && self.Config.Consul.Token != "" {
-		self.Config.Consul.Token = "<redacted>"
+	// Check agent read permissions
+	if aclObj != nil && !aclObj.AllowAgentRead() {
+		return nil, structs.ErrPermissionDenied
 	}
 
-	if self.Config != nil && self.Config.Telemetry != nil && self.Config.Telemetry.CirconusAPIToken != "" {
-		self.Config.Telemetry.CirconusAPIToken = "<redacted>"
+	effective, err := parseBool(req, "effective")
+	if err != nil {
+		return nil, err
 	}
 
-	return self, nil
+	config := s.agent.GetConfig()
+	if effective != nil && *effective {
+		out, err := config.EffectiveConfig()
+		if err != nil {
+			return nil, CodedError(500, err.Error())
+		}
+		return out, nil
+	}
+
+	out, err := config.Redacted()
+	if err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+	return out, nil
+}
+
+// AgentLogLevelsRequest lists, sets and resets the log levels overridden at
+// runtime for subsystems of the agent.
+func (s *HTTPServer) AgentLogLevelsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	aclObj, err := s.ResolveToken(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Method {
+	case "GET":
+		// Check agent read permissions
+		if aclObj != nil && !aclObj.AllowAgentRead() {
+			return nil, structs.ErrPermissionDenied
+		}
+	case "PUT", "POST", "DELETE":
+		// Check agent write permissions
+		if aclObj != nil && !aclObj.AllowAgentWrite() {
+			return nil, structs.ErrPermissionDenied
+		}
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	query := req.URL.Query()
+	name := query.Get("name")
+
+	switch req.Method {
+	case "PUT", "POST":
+		var d time.Duration
+		if v := query.Get("duration"); v != "" {
+			d, err = time.ParseDuration(v)
+			if err != nil {
+				return nil, CodedError(400, fmt.Sprintf("invalid duration: %v", err))
+			}
+		}
+		if err := s.agent.logLevels.Set(name, query.Get("level"), d); err != nil {
+			return nil, CodedError(400, err.Error())
+		}
+	case "DELETE":
+		if name == "" {
+			return nil, CodedError(400, "missing logger name")
+		}
+		s.agent.logLevels.Reset(name)
+	}
+
+	return s.agent.logLevels.List(), nil
 }
 
 func (s *HTTPServer) AgentJoinRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -481,6 +551,34 @@ func (s *HTTPServer) updateServers(resp http.ResponseWriter, req *http.Request)
 	return nil, nil
 }
 
+// AgentPrepareUpgradeRequest flushes the client state before the agent is
+// restarted in place and reports the running tasks it won't reattach to.
+func (s *HTTPServer) AgentPrepareUpgradeRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	client := s.agent.Client()
+	if client == nil {
+		return nil, CodedError(501, ErrInvalidMethod)
+	}
+
+	var secret string
+	s.parseToken(req, &secret)
+
+	// Check agent write permissions
+	if aclObj, err := client.ResolveToken(secret); err != nil {
+		return nil, err
+	} else if aclObj != nil && !aclObj.AllowAgentWrite() {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	reply, err := client.PrepareUpgrade()
+	if err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+	return reply, nil
+}
+
 // KeyringOperationRequest allows an operator to install/delete/use keys
 func (s *HTTPServer) KeyringOperationRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	srv := s.agent.Server()
@@ -534,9 +632,10 @@ func (s *HTTPServer) KeyringOperationRequest(resp http.ResponseWriter, req *http
 		return nil, err
 	}
 	kresp := structs.KeyringResponse{
-		Messages: sresp.Messages,
-		Keys:     sresp.Keys,
-		NumNodes: sresp.NumNodes,
+		Messages:    sresp.Messages,
+		Keys:        sresp.Keys,
+		PrimaryKeys: sresp.PrimaryKeys,
+		NumNodes:    sresp.NumNodes,
 	}
 	return kresp, nil
 }
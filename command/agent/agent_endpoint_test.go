@@ -26,6 +26,7 @@ import (
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -91,6 +92,158 @@ func TestHTTP_AgentSelf(t *testing.T) {
 		require.NoError(err)
 		self = obj.(agentSelf)
 		require.Equal("<redacted>", self.Config.Telemetry.CirconusAPIToken)
+
+		// Assign an artifact fetcher environment and require it is redacted.
+		s.Config.Client.Artifact.Fetchers = []*config.ArtifactFetcherConfig{{
+			Scheme:  "store",
+			Command: "/usr/local/bin/store-fetch",
+			Env:     map[string]string{"STORE_TOKEN": "badc0deb-adc0-deba-dc0d-ebadc0debadc"},
+		}}
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.AgentSelfRequest(respW, req)
+		require.NoError(err)
+		self = obj.(agentSelf)
+		require.Equal(map[string]string{"STORE_TOKEN": "<redacted>"}, self.Config.Client.Artifact.Fetchers[0].Env)
+		require.Equal("badc0deb-adc0-deba-dc0d-ebadc0debadc", s.Config.Client.Artifact.Fetchers[0].Env["STORE_TOKEN"])
+	})
+}
+
+func TestHTTP_AgentConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		s.Config.Vault.Token = "badc0deb-adc0-deba-dc0d-ebadc0debadc"
+		s.Config.Sources = map[string]*ConfigSource{
+			"Datacenter":  {Type: ConfigSourceFile, Name: "/etc/nomad.d/nomad.hcl"},
+			"Vault.Token": {Type: ConfigSourceEnv, Name: "VAULT_TOKEN"},
+		}
+
+		// The redacted configuration is returned by default
+		req, err := http.NewRequest("GET", "/v1/agent/config", nil)
+		require.NoError(t, err)
+		obj, err := s.Server.AgentConfigRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		config := obj.(*Config)
+		require.Equal(t, s.Config.Datacenter, config.Datacenter)
+		require.Equal(t, "<redacted>", config.Vault.Token)
+
+		// The effective configuration annotates each value with its source
+		req, err = http.NewRequest("GET", "/v1/agent/config?effective=true", nil)
+		require.NoError(t, err)
+		obj, err = s.Server.AgentConfigRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		effective := obj.(map[string]*EffectiveConfigValue)
+		require.Equal(t, &EffectiveConfigValue{
+			Value:  s.Config.Datacenter,
+			Source: ConfigSourceFile,
+			Name:   "/etc/nomad.d/nomad.hcl",
+		}, effective["Datacenter"])
+		require.Equal(t, &EffectiveConfigValue{
+			Value:  "<redacted>",
+			Source: ConfigSourceEnv,
+			Name:   "VAULT_TOKEN",
+		}, effective["Vault.Token"])
+		require.Equal(t, ConfigSourceDefault, effective["LogLevel"].Source)
+		require.NotContains(t, effective, "Sources")
+
+		// Invalid parameters are rejected
+		req, err = http.NewRequest("GET", "/v1/agent/config?effective=nope", nil)
+		require.NoError(t, err)
+		_, err = s.Server.AgentConfigRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+	})
+}
+
+func TestHTTP_AgentConfig_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	httpACLTest(t, nil, func(s *TestAgent) {
+		state := s.Agent.server.State()
+
+		// Requests without a token with agent read are rejected
+		req, err := http.NewRequest("GET", "/v1/agent/config?effective=true", nil)
+		require.NoError(t, err)
+		_, err = s.Server.AgentConfigRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+		token := mock.CreatePolicyAndToken(t, state, 1005, "invalid", mock.NodePolicy(acl.PolicyWrite))
+		setToken(req, token)
+		_, err = s.Server.AgentConfigRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+		token = mock.CreatePolicyAndToken(t, state, 1007, "valid", mock.AgentPolicy(acl.PolicyRead))
+		setToken(req, token)
+		obj, err := s.Server.AgentConfigRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.NotEmpty(t, obj.(map[string]*EffectiveConfigValue))
+	})
+}
+
+func TestHTTP_AgentLogLevels(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		// Override the level of a subsystem
+		req, err := http.NewRequest("PUT", "/v1/agent/log-levels?name=nomad.deployments_watcher&level=trace&duration=5m", nil)
+		require.NoError(t, err)
+		obj, err := s.Server.AgentLogLevelsRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		overrides := obj.([]*LogLevelOverride)
+		require.Len(t, overrides, 1)
+		require.Equal(t, "nomad.deployments_watcher", overrides[0].Name)
+		require.Equal(t, "trace", overrides[0].Level)
+		require.WithinDuration(t, time.Now().Add(5*time.Minute), overrides[0].ExpiresAt, time.Minute)
+
+		// List the overrides
+		req, err = http.NewRequest("GET", "/v1/agent/log-levels", nil)
+		require.NoError(t, err)
+		obj, err = s.Server.AgentLogLevelsRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*LogLevelOverride), 1)
+
+		// Invalid parameters are rejected
+		for _, path := range []string{
+			"/v1/agent/log-levels?level=trace",
+			"/v1/agent/log-levels?name=nomad&level=loud",
+			"/v1/agent/log-levels?name=nomad&level=trace&duration=soon",
+			"/v1/agent/log-levels?name=nomad&level=trace&duration=48h",
+		} {
+			req, err = http.NewRequest("PUT", path, nil)
+			require.NoError(t, err)
+			_, err = s.Server.AgentLogLevelsRequest(httptest.NewRecorder(), req)
+			require.Error(t, err, path)
+		}
+
+		// Reset the override
+		req, err = http.NewRequest("DELETE", "/v1/agent/log-levels?name=nomad.deployments_watcher", nil)
+		require.NoError(t, err)
+		obj, err = s.Server.AgentLogLevelsRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.Empty(t, obj.([]*LogLevelOverride))
+	})
+}
+
+func TestHTTP_AgentLogLevels_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	httpACLTest(t, nil, func(s *TestAgent) {
+		state := s.Agent.server.State()
+
+		// Overriding log levels requires agent write
+		req, err := http.NewRequest("PUT", "/v1/agent/log-levels?name=nomad&level=debug", nil)
+		require.NoError(t, err)
+		_, err = s.Server.AgentLogLevelsRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+		token := mock.CreatePolicyAndToken(t, state, 1005, "read", mock.AgentPolicy(acl.PolicyRead))
+		setToken(req, token)
+		_, err = s.Server.AgentLogLevelsRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+		token = mock.CreatePolicyAndToken(t, state, 1007, "write", mock.AgentPolicy(acl.PolicyWrite))
+		setToken(req, token)
+		_, err = s.Server.AgentLogLevelsRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
 	})
 }
 
@@ -768,6 +921,22 @@ func TestHTTP_AgentSetServers(t *testing.T) {
 	})
 }
 
+func TestHTTP_AgentPrepareUpgrade(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/v1/agent/prepare-upgrade", nil)
+		require.NoError(t, err)
+		_, err = s.Server.AgentPrepareUpgradeRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, ErrInvalidMethod)
+
+		// The test agent runs in dev mode, which doesn't persist state
+		req, err = http.NewRequest("PUT", "/v1/agent/prepare-upgrade", nil)
+		require.NoError(t, err)
+		_, err = s.Server.AgentPrepareUpgradeRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, "client state isn't persisted in dev mode")
+	})
+}
+
 func TestHTTP_AgentSetServers_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
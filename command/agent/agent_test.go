@@ -174,6 +174,29 @@ func TestAgent_ServerConfig(t *testing.T) {
 	out, err = a.serverConfig()
 	require.NoError(t, err)
 	require.Equal(t, 3, out.BootstrapExpect)
+
+	// Properly handles the per job GC limits
+	conf.Server.EvalGCMaxPerJob = 10
+	conf.Server.AllocGCMaxPerJob = 20
+	out, err = a.serverConfig()
+	require.NoError(t, err)
+	require.Equal(t, 10, out.EvalGCMaxPerJob)
+	require.Equal(t, 20, out.AllocGCMaxPerJob)
+
+	conf.Server.AllocGCMaxPerJob = -1
+	_, err = a.serverConfig()
+	require.EqualError(t, err, "alloc_gc_max_per_job must be positive: -1")
+	conf.Server.AllocGCMaxPerJob = 20
+
+	// Properly handles the job trash window
+	conf.Server.JobTrashWindow = "24h"
+	out, err = a.serverConfig()
+	require.NoError(t, err)
+	require.Equal(t, 24*time.Hour, out.JobTrashWindow)
+
+	conf.Server.JobTrashWindow = "-1h"
+	_, err = a.serverConfig()
+	require.EqualError(t, err, "job_trash_window must not be negative: -1h")
 }
 
 func TestAgent_ServerConfig_SchedulerFlags(t *testing.T) {
@@ -442,6 +465,25 @@ func TestAgent_ServerConfig_PlanRejectionTracker(t *testing.T) {
 	}
 }
 
+func TestAgent_ServerConfig_ReadReplica(t *testing.T) {
+	ci.Parallel(t)
+
+	config := DevConfig(nil)
+	require.NoError(t, config.normalizeAddrs())
+	config.Server.ReadReplica = true
+
+	serverConfig, err := convertServerConfig(config)
+	require.NoError(t, err)
+	require.True(t, serverConfig.ReadReplica)
+	require.True(t, serverConfig.NonVoter)
+	require.Zero(t, serverConfig.NumSchedulers)
+
+	// A read replica can not run schedulers
+	config.Server.NumSchedulers = helper.IntToPtr(2)
+	_, err = convertServerConfig(config)
+	require.EqualError(t, err, "num_schedulers must be 0 on a read replica")
+}
+
 func TestAgent_ServerConfig_RaftMultiplier_Ok(t *testing.T) {
 	ci.Parallel(t)
 
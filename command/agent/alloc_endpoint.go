@@ -88,6 +88,10 @@ func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Re
 		return s.allocChecks(allocID, resp, req)
 	case "stop":
 		return s.allocStop(allocID, resp, req)
+	case "pin":
+		return s.allocPin(allocID, true, resp, req)
+	case "unpin":
+		return s.allocPin(allocID, false, resp, req)
 	case "services":
 		return s.allocServiceRegistrations(resp, req, allocID)
 	}
@@ -171,6 +175,29 @@ func (s *HTTPServer) allocStop(allocID string, resp http.ResponseWriter, req *ht
 	return &out, nil
 }
 
+func (s *HTTPServer) allocPin(allocID string, pinned bool, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if !(req.Method == "POST" || req.Method == "PUT") {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := &structs.AllocPinRequest{
+		AllocID: allocID,
+		Pinned:  pinned,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.AllocPinResponse
+	if err := s.agent.RPC("Alloc.Pin", &args, &out); err != nil {
+		if structs.IsErrUnknownAllocation(err) {
+			err = CodedError(404, allocNotFoundErr)
+		}
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return &out, nil
+}
+
 // allocServiceRegistrations returns a list of all service registrations
 // assigned to the job identifier. It is callable via the
 // /v1/allocation/:alloc_id/services HTTP API and uses the
@@ -217,6 +244,8 @@ func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Requ
 	switch tokens[1] {
 	case "checks":
 		return s.allocChecks(allocID, resp, req)
+	case "templates":
+		return s.allocTemplates(allocID, resp, req)
 	case "stats":
 		return s.allocStats(allocID, resp, req)
 	case "exec":
@@ -473,6 +502,41 @@ func (s *HTTPServer) allocChecks(allocID string, resp http.ResponseWriter, req *
 	return reply.Results, rpcErr
 }
 
+func (s *HTTPServer) allocTemplates(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+
+	// Build the request and parse the ACL token
+	task := req.URL.Query().Get("task")
+	args := cstructs.AllocTemplatesRequest{
+		AllocID: allocID,
+		Task:    task,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForAlloc(allocID)
+
+	// Make the RPC
+	var reply cstructs.AllocTemplatesResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("Allocations.Templates", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientAllocations.Templates", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientAllocations.Templates", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+	}
+
+	return reply.Tasks, rpcErr
+}
+
 func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// Build the request and parse the ACL token
 	task := req.URL.Query().Get("task")
@@ -492,11 +556,20 @@ func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *ht
 		}
 	}
 
+	debugShell := false
+	if v := req.URL.Query().Get("debug_shell"); v != "" {
+		debugShell, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("debug_shell value is not a boolean: %v", err)
+		}
+	}
+
 	args := cstructs.AllocExecRequest{
-		AllocID: allocID,
-		Task:    task,
-		Cmd:     command,
-		Tty:     ttyB,
+		AllocID:    allocID,
+		Task:       task,
+		Cmd:        command,
+		Tty:        ttyB,
+		DebugShell: debugShell,
 	}
 	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
 
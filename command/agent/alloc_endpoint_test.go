@@ -434,6 +434,42 @@ func TestHTTP_AllocStop(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocPin(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		require := require.New(t)
+		require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+		require.NoError(state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+		for _, action := range []string{"pin", "unpin"} {
+			req, err := http.NewRequest("PUT", "/v1/allocation/"+alloc.ID+"/"+action, nil)
+			require.NoError(err)
+			respW := httptest.NewRecorder()
+
+			obj, err := s.Server.AllocSpecificRequest(respW, req)
+			require.NoError(err)
+
+			a := obj.(*structs.AllocPinResponse)
+			require.NotEmpty(a.EvalID, "missing eval")
+			require.NotEmpty(a.Index, "missing index")
+
+			out, err := state.AllocByID(nil, alloc.ID)
+			require.NoError(err)
+			require.Equal(action == "pin", out.DesiredTransition.ShouldPin())
+		}
+
+		// Test that we 404 when the allocid is invalid
+		req, err := http.NewRequest("PUT", "/v1/allocation/"+uuid.Generate()+"/pin", nil)
+		require.NoError(err)
+		_, err = s.Server.AllocSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), allocNotFoundErr)
+	})
+}
+
 func TestHTTP_allocServiceRegistrations(t *testing.T) {
 	ci.Parallel(t)
 
@@ -54,6 +54,10 @@ type Command struct {
 	logFilter      *logutils.LevelFilter
 	logOutput      io.Writer
 	retryJoinErrCh chan struct{}
+
+	// logOverrideOutput writes the messages of the subsystems whose log
+	// level is overridden at runtime, bypassing logFilter.
+	logOverrideOutput io.Writer
 }
 
 func (c *Command) readConfig() *Config {
@@ -217,9 +221,11 @@ func (c *Command) readConfig() *Config {
 		c.Ui.Error(err.Error())
 		return nil
 	}
+	provenance := newConfigProvenance()
 	var config *Config
 	if dev != nil {
 		config = DevConfig(dev)
+		provenance.addDev(DefaultConfig(), config)
 	} else {
 		config = DefaultConfig()
 	}
@@ -240,6 +246,7 @@ func (c *Command) readConfig() *Config {
 		if current == nil || reflect.DeepEqual(current, &Config{}) {
 			c.Ui.Warn(fmt.Sprintf("No configuration loaded from %s", path))
 		}
+		provenance.addFiles(current)
 
 		if config == nil {
 			config = current
@@ -259,39 +266,18 @@ func (c *Command) readConfig() *Config {
 
 	// Merge any CLI options over config file options
 	config = config.Merge(cmdConfig)
+	provenance.addFlags(cmdConfig)
 
 	// Set the version info
 	config.Version = c.Version
 
-	// Normalize binds, ports, addresses, and advertise
-	if err := config.normalizeAddrs(); err != nil {
+	merged := flattenConfig(config)
+	if err := config.Finalize(); err != nil {
 		c.Ui.Error(err.Error())
 		return nil
 	}
-
-	// Check to see if we should read the Vault token from the environment
-	if config.Vault.Token == "" {
-		config.Vault.Token = os.Getenv("VAULT_TOKEN")
-	}
-
-	// Check to see if we should read the Vault namespace from the environment
-	if config.Vault.Namespace == "" {
-		config.Vault.Namespace = os.Getenv("VAULT_NAMESPACE")
-	}
-
-	// Default the plugin directory to be under that of the data directory if it
-	// isn't explicitly specified.
-	if config.PluginDir == "" && config.DataDir != "" {
-		config.PluginDir = filepath.Join(config.DataDir, "plugins")
-	}
-
-	// License configuration options
-	config.Server.LicenseEnv = os.Getenv("NOMAD_LICENSE")
-	if config.Server.LicensePath == "" {
-		config.Server.LicensePath = os.Getenv("NOMAD_LICENSE_PATH")
-	}
-
-	config.Server.DefaultSchedulerConfig.Canonicalize()
+	provenance.addEnv(merged, config)
+	config.Sources = provenance.sources
 
 	if !c.IsValidConfig(config, cmdConfig) {
 		return nil
@@ -301,23 +287,26 @@ func (c *Command) readConfig() *Config {
 }
 
 func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
+	// Keep checking after the first problem so that every error in the
+	// configuration is reported at once.
+	valid := true
 
 	// Check that the server is running in at least one mode.
 	if !(config.Server.Enabled || config.Client.Enabled) {
 		c.Ui.Error("Must specify either server, client or dev mode for the agent.")
-		return false
+		valid = false
 	}
 
 	// Check that the region does not contain invalid characters
 	if strings.ContainsAny(config.Region, "\000") {
 		c.Ui.Error("Region contains invalid characters")
-		return false
+		valid = false
 	}
 
 	// Check that the datacenter name does not contain invalid characters
 	if strings.ContainsAny(config.Datacenter, "\000") {
 		c.Ui.Error("Datacenter contains invalid characters")
-		return false
+		valid = false
 	}
 
 	// Set up the TLS configuration properly if we have one.
@@ -332,7 +321,7 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 	if config.Server.EncryptKey != "" {
 		if _, err := config.Server.EncryptBytes(); err != nil {
 			c.Ui.Error(fmt.Sprintf("Invalid encryption key: %s", err))
-			return false
+			valid = false
 		}
 		keyfile := filepath.Join(config.DataDir, serfKeyring)
 		if _, err := os.Stat(keyfile); err == nil {
@@ -354,7 +343,7 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 
 		if !filepath.IsAbs(dir) {
 			c.Ui.Error(fmt.Sprintf("%s must be given as an absolute path: got %v", k, dir))
-			return false
+			valid = false
 		}
 	}
 
@@ -362,27 +351,27 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 		for k := range config.Client.Meta {
 			if !helper.IsValidInterpVariable(k) {
 				c.Ui.Error(fmt.Sprintf("Invalid Client.Meta key: %v", k))
-				return false
+				valid = false
 			}
 		}
 	}
 
 	if err := config.Server.DefaultSchedulerConfig.Validate(); err != nil {
 		c.Ui.Error(err.Error())
-		return false
+		valid = false
 	}
 
 	if config.Client.MinDynamicPort < 0 || config.Client.MinDynamicPort > structs.MaxValidPort {
 		c.Ui.Error(fmt.Sprintf("Invalid dynamic port range: min_dynamic_port=%d", config.Client.MinDynamicPort))
-		return false
+		valid = false
 	}
 	if config.Client.MaxDynamicPort < 0 || config.Client.MaxDynamicPort > structs.MaxValidPort {
 		c.Ui.Error(fmt.Sprintf("Invalid dynamic port range: max_dynamic_port=%d", config.Client.MaxDynamicPort))
-		return false
+		valid = false
 	}
 	if config.Client.MinDynamicPort > config.Client.MaxDynamicPort {
 		c.Ui.Error(fmt.Sprintf("Invalid dynamic port range: min_dynamic_port=%d and max_dynamic_port=%d", config.Client.MinDynamicPort, config.Client.MaxDynamicPort))
-		return false
+		valid = false
 	}
 
 	if config.Client.Reserved == nil {
@@ -394,7 +383,7 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 	if ports := config.Client.Reserved.ReservedPorts; ports != "" {
 		if _, err := structs.ParsePortRanges(ports); err != nil {
 			c.Ui.Error(fmt.Sprintf("reserved.reserved_ports %q invalid: %v", ports, err))
-			return false
+			valid = false
 		}
 	}
 
@@ -403,20 +392,39 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 		if _, err := structs.ParsePortRanges(hn.ReservedPorts); err != nil {
 			c.Ui.Error(fmt.Sprintf("host_network[%q].reserved_ports %q invalid: %v",
 				hn.Name, hn.ReservedPorts, err))
-			return false
+			valid = false
 		}
 	}
 
 	if err := config.Client.Artifact.Validate(); err != nil {
 		c.Ui.Error(fmt.Sprintf("client.artifact stanza invalid: %v", err))
-		return false
+		valid = false
+	}
+
+	for _, ep := range config.Client.EnvProviders {
+		if err := ep.Validate(); err != nil {
+			c.Ui.Error(fmt.Sprintf("client.env_provider[%q] stanza invalid: %v", ep.Name, err))
+			valid = false
+		}
+	}
+
+	if err := config.HTTP.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("http stanza invalid: %v", err))
+		valid = false
+	}
+
+	for _, mh := range config.Client.MeshHooks {
+		if err := mh.Validate(); err != nil {
+			c.Ui.Error(fmt.Sprintf("client.mesh_hook[%q] stanza invalid: %v", mh.Name, err))
+			valid = false
+		}
 	}
 
 	if !config.DevMode {
 		// Ensure that we have the directories we need to run.
 		if config.Server.Enabled && config.DataDir == "" {
 			c.Ui.Error(`Must specify "data_dir" config option or "data-dir" CLI flag`)
-			return false
+			valid = false
 		}
 
 		// The config is valid if the top-level data-dir is set or if both
@@ -424,7 +432,7 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 		if config.Client.Enabled && config.DataDir == "" {
 			if config.Client.AllocDir == "" || config.Client.StateDir == "" || config.PluginDir == "" {
 				c.Ui.Error("Must specify the state, alloc dir, and plugin dir if data-dir is omitted.")
-				return false
+				valid = false
 			}
 		}
 
@@ -432,7 +440,7 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 		if !config.Server.Enabled && cmdConfig.Server.BootstrapExpect > 0 {
 			// report an error if BootstrapExpect is set in CLI but server is disabled
 			c.Ui.Error("Bootstrap requires server mode to be enabled")
-			return false
+			valid = false
 		}
 		if config.Server.Enabled && config.Server.BootstrapExpect == 1 {
 			c.Ui.Error("WARNING: Bootstrap mode enabled! Potentially unsafe operation.")
@@ -448,11 +456,13 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 		c.Ui.Warn("Please remove deprecated protocol_version field from config.")
 	}
 
-	return true
+	return valid
 }
 
-// SetupLoggers is used to set up the logGate, and our logOutput
-func SetupLoggers(ui cli.Ui, config *Config) (*logutils.LevelFilter, *gatedwriter.Writer, io.Writer) {
+// SetupLoggers is used to set up the logGate, and our logOutput. The last
+// writer writes to the same outputs without filtering by level, for the
+// subsystems whose log level is overridden at runtime.
+func SetupLoggers(ui cli.Ui, config *Config) (*logutils.LevelFilter, *gatedwriter.Writer, io.Writer, io.Writer) {
 	// Setup logging. First create the gated log writer, which will
 	// store logs until we're ready to show them. Then create the level
 	// filter, filtering logs of the specified level.
@@ -467,20 +477,22 @@ func SetupLoggers(ui cli.Ui, config *Config) (*logutils.LevelFilter, *gatedwrite
 		ui.Error(fmt.Sprintf(
 			"Invalid log level: %s. Valid log levels are: %v",
 			logFilter.MinLevel, logFilter.Levels))
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 
 	// Create a log writer, and wrap a logOutput around it
 	writers := []io.Writer{logFilter}
+	overrideWriters := []io.Writer{logGate}
 
 	// Check if syslog is enabled
 	if config.EnableSyslog {
 		l, err := gsyslog.NewLogger(gsyslog.LOG_NOTICE, config.SyslogFacility, "nomad")
 		if err != nil {
 			ui.Error(fmt.Sprintf("Syslog setup failed: %v", err))
-			return nil, nil, nil
+			return nil, nil, nil, nil
 		}
 		writers = append(writers, &SyslogWrapper{l, logFilter})
+		overrideWriters = append(overrideWriters, &SyslogWrapper{l, nil})
 	}
 
 	// Check if file logging is enabled
@@ -498,7 +510,7 @@ func SetupLoggers(ui cli.Ui, config *Config) (*logutils.LevelFilter, *gatedwrite
 			duration, err := time.ParseDuration(config.LogRotateDuration)
 			if err != nil {
 				ui.Error(fmt.Sprintf("Failed to parse log rotation duration: %v", err))
-				return nil, nil, nil
+				return nil, nil, nil, nil
 			}
 			logRotateDuration = duration
 		} else {
@@ -516,10 +528,11 @@ func SetupLoggers(ui cli.Ui, config *Config) (*logutils.LevelFilter, *gatedwrite
 		}
 
 		writers = append(writers, logFile)
+		overrideWriters = append(overrideWriters, unfilteredLogFile{logFile})
 	}
 
 	logOutput := io.MultiWriter(writers...)
-	return logFilter, logGate, logOutput
+	return logFilter, logGate, logOutput, io.MultiWriter(overrideWriters...)
 }
 
 // setupAgent is used to start the agent and various interfaces
@@ -535,6 +548,14 @@ func (c *Command) setupAgent(config *Config, logger hclog.InterceptLogger, logOu
 	}
 	c.agent = agent
 
+	// Write the messages of subsystems whose log level is overridden past the
+	// level filter, as long as neither the logger nor the filter would
+	// already write them.
+	level := hclog.LevelFromString(config.LogLevel)
+	agent.logLevels.setOutput(c.logOverrideOutput, config.LogJson, func(l hclog.Level) bool {
+		return l >= level && filterEmitted(c.logFilter, l)
+	})
+
 	// Setup the HTTP server
 	httpServers, err := NewHTTPServers(agent, config)
 	if err != nil {
@@ -685,9 +706,10 @@ func (c *Command) Run(args []string) int {
 	}
 
 	// Setup the log outputs
-	logFilter, logGate, logOutput := SetupLoggers(c.Ui, config)
+	logFilter, logGate, logOutput, logOverrideOutput := SetupLoggers(c.Ui, config)
 	c.logFilter = logFilter
 	c.logOutput = logOutput
+	c.logOverrideOutput = logOverrideOutput
 	if logGate == nil {
 		return 1
 	}
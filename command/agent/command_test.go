@@ -421,3 +421,34 @@ func TestIsValidConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestCommand_ReadConfig_Sources(t *testing.T) {
+	// t.Setenv can't be used in parallel tests
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "conf1.hcl")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`
+datacenter = "dc2"
+client {
+  enabled = true
+}
+`), 0600))
+	t.Setenv("VAULT_TOKEN", "badc0deb-adc0-deba-dc0d-ebadc0debadc")
+
+	cmd := &Command{
+		Version: version.GetVersion(),
+		Ui:      cli.NewMockUi(),
+		args:    []string{"-data-dir=" + tmpDir, "-config=" + configFile, "-region=east"},
+	}
+	config := cmd.readConfig()
+	require.NotNil(t, config)
+
+	effective, err := config.EffectiveConfig()
+	require.NoError(t, err)
+
+	require.Equal(t, &EffectiveConfigValue{Value: "dc2", Source: ConfigSourceFile, Name: configFile}, effective["Datacenter"])
+	require.Equal(t, &EffectiveConfigValue{Value: true, Source: ConfigSourceFile, Name: configFile}, effective["Client.Enabled"])
+	require.Equal(t, &EffectiveConfigValue{Value: "east", Source: ConfigSourceFlag}, effective["Region"])
+	require.Equal(t, &EffectiveConfigValue{Value: "<redacted>", Source: ConfigSourceEnv, Name: "VAULT_TOKEN"}, effective["Vault.Token"])
+	require.Equal(t, &EffectiveConfigValue{Value: "INFO", Source: ConfigSourceDefault}, effective["LogLevel"])
+}
@@ -16,16 +16,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-secure-stdlib/listenerutil"
 	sockaddr "github.com/hashicorp/go-sockaddr"
 	"github.com/hashicorp/go-sockaddr/template"
 	client "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/fingerprint"
 	"github.com/hashicorp/nomad/helper"
+	snapshotagent "github.com/hashicorp/nomad/helper/snapshot/agent"
 	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/version"
+	"github.com/mitchellh/copystructure"
 )
 
 // Config is the configuration for the Nomad agent.
@@ -154,6 +157,10 @@ type Config struct {
 	// List of config files that have been loaded (in order)
 	Files []string `hcl:"-"`
 
+	// Sources is where each configuration field was set, keyed by the path of
+	// its Go field names. It is set when the agent reads its configuration.
+	Sources map[string]*ConfigSource `hcl:"-" json:"-"`
+
 	// TLSConfig provides TLS related configuration for the Nomad server and
 	// client
 	TLSConfig *config.TLSConfig `hcl:"tls"`
@@ -162,6 +169,10 @@ type Config struct {
 	// set arbitrary headers on API responses
 	HTTPAPIResponseHeaders map[string]string `hcl:"http_api_response_headers"`
 
+	// HTTP configures the CORS and content security policy of the HTTP
+	// listeners
+	HTTP *config.HTTPConfig `hcl:"http"`
+
 	// Sentinel holds sentinel related settings
 	Sentinel *config.SentinelConfig `hcl:"sentinel"`
 
@@ -198,6 +209,10 @@ type ClientConfig struct {
 	// NodeClass is used to group the node by class
 	NodeClass string `hcl:"node_class"`
 
+	// IntroToken is the node introduction token used to register the node
+	// for the first time when servers require node introduction.
+	IntroToken string `hcl:"intro_token" json:"-"`
+
 	// Options is used for configuration of nomad internals,
 	// like fingerprinters and drivers. The format is:
 	//
@@ -279,6 +294,11 @@ type ClientConfig struct {
 	// DisableRemoteExec disables remote exec targeting tasks on this client
 	DisableRemoteExec bool `hcl:"disable_remote_exec"`
 
+	// DebugShell is the path to a static, busybox compatible binary used for
+	// debug shell sessions into allocations. Debug shells are disabled if
+	// not set.
+	DebugShell string `hcl:"debug_shell"`
+
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *client.ClientTemplateConfig `hcl:"template"`
 
@@ -328,6 +348,14 @@ type ClientConfig struct {
 	// Artifact contains the configuration for artifacts.
 	Artifact *config.ArtifactConfig `hcl:"artifact"`
 
+	// EnvProviders are the environment providers run by the client to
+	// contribute environment variables and files to tasks.
+	EnvProviders []*config.EnvProviderConfig `hcl:"env_provider"`
+
+	// MeshHooks are the hooks run by the client to integrate task groups
+	// with external service meshes.
+	MeshHooks []*config.MeshHookConfig `hcl:"mesh_hook"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -412,11 +440,24 @@ type ServerConfig struct {
 	// can be used to filter by age.
 	JobGCThreshold string `hcl:"job_gc_threshold"`
 
+	// JobTrashWindow controls how long purged jobs are kept in the job
+	// trash, where they can be restored, before they are permanently
+	// deleted. The job trash is disabled if unset.
+	JobTrashWindow string `hcl:"job_trash_window"`
+
 	// EvalGCThreshold controls how "old" an eval must be to be collected by GC.
 	// Age is not the only requirement for a eval to be GCed but the threshold
 	// can be used to filter by age.
 	EvalGCThreshold string `hcl:"eval_gc_threshold"`
 
+	// EvalGCMaxPerJob is the maximum number of terminal evaluations retained
+	// per job. Older evaluations are collected by GC regardless of their age.
+	EvalGCMaxPerJob int `hcl:"eval_gc_max_per_job"`
+
+	// AllocGCMaxPerJob is the maximum number of terminal allocations retained
+	// per job. Older allocations are collected by GC regardless of their age.
+	AllocGCMaxPerJob int `hcl:"alloc_gc_max_per_job"`
+
 	// DeploymentGCThreshold controls how "old" a deployment must be to be
 	// collected by GC.  Age is not the only requirement for a deployment to be
 	// GCed but the threshold can be used to filter by age.
@@ -499,6 +540,11 @@ type ServerConfig struct {
 	// non-voting member of the cluster to help provide read scalability.
 	NonVotingServer bool `hcl:"non_voting_server"`
 
+	// ReadReplica is whether this server will act as a read replica. A read
+	// replica is a non-voting server that does not run schedulers and serves
+	// the stale queries and event streams of the region.
+	ReadReplica bool `hcl:"read_replica"`
+
 	// (Enterprise-only) RedundancyZone is the redundancy zone to use for this server.
 	RedundancyZone string `hcl:"redundancy_zone"`
 
@@ -509,6 +555,11 @@ type ServerConfig struct {
 	// Encryption key to use for the Serf communication
 	EncryptKey string `hcl:"encrypt" json:"-"`
 
+	// RequireNodeIntroToken is whether clients must present a node
+	// introduction token to register for the first time. Registered clients
+	// must then present their node secret ID with their heartbeats.
+	RequireNodeIntroToken bool `hcl:"require_node_intro_token"`
+
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `hcl:"server_join"`
 
@@ -553,6 +604,14 @@ type ServerConfig struct {
 
 	// RaftBoltConfig configures boltdb as used by raft.
 	RaftBoltConfig *RaftBoltConfig `hcl:"raft_boltdb"`
+
+	// RPCCompression enables zstd compression of cross-region RPC
+	// connections. Compressed connections are only accepted over mTLS.
+	RPCCompression bool `hcl:"rpc_compression"`
+
+	// SnapshotAgent configures the leader to periodically save snapshots of
+	// the state to a storage.
+	SnapshotAgent *snapshotagent.Config `hcl:"snapshot_agent"`
 }
 
 // RaftBoltConfig is used in servers to configure parameters of the boltdb
@@ -1305,11 +1364,91 @@ func (c *Config) Merge(b *Config) *Config {
 		result.HTTPAPIResponseHeaders[k] = v
 	}
 
+	// Apply the HTTP listeners configuration
+	if result.HTTP == nil && b.HTTP != nil {
+		result.HTTP = b.HTTP.Copy()
+	} else if b.HTTP != nil {
+		result.HTTP = result.HTTP.Merge(b.HTTP)
+	}
+
 	result.Limits = c.Limits.Merge(b.Limits)
 
 	return &result
 }
 
+// Finalize performs the steps the agent applies to a fully merged
+// configuration before validating it: go-sockaddr templates in the bind,
+// address and advertise fields are resolved, and values that may be supplied
+// through the environment, such as VAULT_TOKEN and NOMAD_LICENSE, are read.
+func (c *Config) Finalize() error {
+	// Normalize binds, ports, addresses, and advertise
+	if err := c.normalizeAddrs(); err != nil {
+		return err
+	}
+
+	// Check to see if we should read the Vault token from the environment
+	if c.Vault.Token == "" {
+		c.Vault.Token = os.Getenv("VAULT_TOKEN")
+	}
+
+	// Check to see if we should read the Vault namespace from the environment
+	if c.Vault.Namespace == "" {
+		c.Vault.Namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+
+	// Default the plugin directory to be under that of the data directory if it
+	// isn't explicitly specified.
+	if c.PluginDir == "" && c.DataDir != "" {
+		c.PluginDir = filepath.Join(c.DataDir, "plugins")
+	}
+
+	// License configuration options
+	c.Server.LicenseEnv = os.Getenv("NOMAD_LICENSE")
+	if c.Server.LicensePath == "" {
+		c.Server.LicensePath = os.Getenv("NOMAD_LICENSE_PATH")
+	}
+
+	c.Server.DefaultSchedulerConfig.Canonicalize()
+	return nil
+}
+
+// Redacted returns a copy of the configuration with secrets such as tokens
+// and the enterprise license replaced by a placeholder, suitable for
+// displaying to operators.
+func (c *Config) Redacted() (*Config, error) {
+	raw, err := copystructure.Copy(c)
+	if err != nil {
+		return nil, err
+	}
+	rc := raw.(*Config)
+
+	const redacted = "<redacted>"
+	if rc.Vault != nil && rc.Vault.Token != "" {
+		rc.Vault.Token = redacted
+	}
+	if rc.ACL != nil && rc.ACL.ReplicationToken != "" {
+		rc.ACL.ReplicationToken = redacted
+	}
+	if rc.Consul != nil && rc.Consul.Token != "" {
+		rc.Consul.Token = redacted
+	}
+	if rc.Telemetry != nil && rc.Telemetry.CirconusAPIToken != "" {
+		rc.Telemetry.CirconusAPIToken = redacted
+	}
+	if rc.Server != nil && rc.Server.LicenseEnv != "" {
+		rc.Server.LicenseEnv = redacted
+	}
+	if rc.Client != nil && rc.Client.Artifact != nil {
+		// The environment of artifact fetchers is used to pass credentials
+		for _, f := range rc.Client.Artifact.Fetchers {
+			for k := range f.Env {
+				f.Env[k] = redacted
+			}
+		}
+	}
+	return rc, nil
+}
+
 // normalizeAddrs normalizes Addresses and AdvertiseAddrs to always be
 // initialized and have reasonable defaults.
 func (c *Config) normalizeAddrs() error {
@@ -1585,9 +1724,18 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.JobGCThreshold != "" {
 		result.JobGCThreshold = b.JobGCThreshold
 	}
+	if b.JobTrashWindow != "" {
+		result.JobTrashWindow = b.JobTrashWindow
+	}
 	if b.EvalGCThreshold != "" {
 		result.EvalGCThreshold = b.EvalGCThreshold
 	}
+	if b.EvalGCMaxPerJob != 0 {
+		result.EvalGCMaxPerJob = b.EvalGCMaxPerJob
+	}
+	if b.AllocGCMaxPerJob != 0 {
+		result.AllocGCMaxPerJob = b.AllocGCMaxPerJob
+	}
 	if b.DeploymentGCThreshold != "" {
 		result.DeploymentGCThreshold = b.DeploymentGCThreshold
 	}
@@ -1642,6 +1790,12 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.NonVotingServer {
 		result.NonVotingServer = true
 	}
+	if b.ReadReplica {
+		result.ReadReplica = true
+	}
+	if b.RequireNodeIntroToken {
+		result.RequireNodeIntroToken = true
+	}
 	if b.RedundancyZone != "" {
 		result.RedundancyZone = b.RedundancyZone
 	}
@@ -1698,6 +1852,14 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		}
 	}
 
+	if b.SnapshotAgent != nil {
+		result.SnapshotAgent = result.SnapshotAgent.Merge(b.SnapshotAgent)
+	}
+
+	if b.RPCCompression {
+		result.RPCCompression = true
+	}
+
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
@@ -1730,6 +1892,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.NodeClass != "" {
 		result.NodeClass = b.NodeClass
 	}
+	if b.IntroToken != "" {
+		result.IntroToken = b.IntroToken
+	}
 	if b.NetworkInterface != "" {
 		result.NetworkInterface = b.NetworkInterface
 	}
@@ -1793,6 +1958,10 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.DisableRemoteExec = b.DisableRemoteExec
 	}
 
+	if b.DebugShell != "" {
+		result.DebugShell = b.DebugShell
+	}
+
 	if b.TemplateConfig != nil {
 		result.TemplateConfig = b.TemplateConfig
 	}
@@ -1869,6 +2038,16 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 
 	result.Artifact = a.Artifact.Merge(b.Artifact)
 
+	result.EnvProviders = a.EnvProviders
+	if len(b.EnvProviders) != 0 {
+		result.EnvProviders = append(result.EnvProviders, b.EnvProviders...)
+	}
+
+	result.MeshHooks = a.MeshHooks
+	if len(b.MeshHooks) != 0 {
+		result.MeshHooks = append(result.MeshHooks, b.MeshHooks...)
+	}
+
 	return &result
 }
 
@@ -2113,11 +2292,15 @@ func LoadConfigDir(dir string) (*Config, error) {
 
 	sort.Strings(files)
 
+	// Parse every file before giving up so that all errors in the directory
+	// can be reported at once.
 	var result *Config
+	var mErr multierror.Error
 	for _, f := range files {
 		config, err := ParseConfigFile(f)
 		if err != nil {
-			return nil, fmt.Errorf("Error loading %s: %s", f, err)
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Error loading %s: %s", f, err))
+			continue
 		}
 		config.Files = append(config.Files, f)
 
@@ -2127,6 +2310,9 @@ func LoadConfigDir(dir string) (*Config, error) {
 			result = result.Merge(config)
 		}
 	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
@@ -127,6 +127,11 @@ func ParseConfigFile(path string) (*Config, error) {
 		},
 	}
 
+	if c.Server.SnapshotAgent != nil {
+		tds = append(tds, durationConversionMap{
+			"server.snapshot_agent.interval", &c.Server.SnapshotAgent.Interval, &c.Server.SnapshotAgent.IntervalHCL, nil})
+	}
+
 	// Add enterprise audit sinks for time.Duration parsing
 	for i, sink := range c.Audit.Sinks {
 		tds = append(tds, durationConversionMap{
@@ -219,6 +224,18 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, "host_network")
 	}
 
+	// Remove EnvProvider extra keys
+	for _, ep := range c.Client.EnvProviders {
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, ep.Name)
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, "env_provider")
+	}
+
+	// Remove MeshHook extra keys
+	for _, mh := range c.Client.MeshHooks {
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, mh.Name)
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, "mesh_hook")
+	}
+
 	// Remove AuditConfig extra keys
 	for _, f := range c.Audit.Filters {
 		helper.RemoveEqualFold(&c.Audit.ExtraKeysHCL, f.Name)
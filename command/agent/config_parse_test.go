@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper"
+	snapshotagent "github.com/hashicorp/nomad/helper/snapshot/agent"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/require"
@@ -102,8 +103,11 @@ var basicConfig = &Config{
 		EnabledSchedulers:         []string{"test"},
 		NodeGCThreshold:           "12h",
 		EvalGCThreshold:           "12h",
+		EvalGCMaxPerJob:           50,
+		AllocGCMaxPerJob:          100,
 		JobGCInterval:             "3m",
 		JobGCThreshold:            "12h",
+		JobTrashWindow:            "48h",
 		DeploymentGCThreshold:     "12h",
 		CSIVolumeClaimGCThreshold: "12h",
 		CSIPluginGCThreshold:      "12h",
@@ -132,6 +136,13 @@ var basicConfig = &Config{
 			NodeWindow:    41 * time.Minute,
 			NodeWindowHCL: "41m",
 		},
+		SnapshotAgent: &snapshotagent.Config{
+			Interval:     2 * time.Hour,
+			IntervalHCL:  "2h",
+			Retain:       helper.IntToPtr(10),
+			NamePrefix:   "backup",
+			LocalStorage: &snapshotagent.LocalStorageConfig{Path: "/tmp/snapshots"},
+		},
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 			RetryInterval:    time.Duration(15) * time.Second,
@@ -146,7 +157,8 @@ var basicConfig = &Config{
 				ServiceSchedulerEnabled: true,
 			},
 		},
-		LicensePath: "/tmp/nomad.hclic",
+		LicensePath:    "/tmp/nomad.hclic",
+		RPCCompression: true,
 	},
 	ACL: &ACLConfig{
 		Enabled:          true,
@@ -611,6 +623,84 @@ func TestConfig_ParseSliceExtra(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestConfig_ParseEnvProvider(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/env-provider.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, []*config.EnvProviderConfig{
+		{
+			Name:     "broker",
+			Command:  "/usr/local/bin/credentials",
+			Args:     []string{"-role", "web"},
+			Timeout:  "5s",
+			CacheTTL: "10m",
+		},
+		{
+			Name:    "site",
+			Command: "/usr/local/bin/site-env",
+		},
+	}, c.Client.EnvProviders)
+	require.Empty(t, c.Client.ExtraKeysHCL)
+}
+
+func TestConfig_ParseMeshHook(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/mesh-hook.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, []*config.MeshHookConfig{
+		{
+			Name:    "istio",
+			Command: "/usr/local/bin/nomad-istio",
+			Args:    []string{"-revision", "stable"},
+			Timeout: "1m",
+		},
+	}, c.Client.MeshHooks)
+	require.Empty(t, c.Client.ExtraKeysHCL)
+}
+
+func TestConfig_ParseHTTP(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/http.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, &config.HTTPConfig{
+		ContentSecurityPolicy: "default-src 'self'",
+		CORS: &config.CORSConfig{
+			AllowedOrigins:   []string{"https://*.example.com"},
+			AllowedHeaders:   []string{"Content-Type", "X-Nomad-Token", "X-Request-Id"},
+			AllowCredentials: helper.BoolToPtr(true),
+			MaxAge:           helper.IntToPtr(600),
+		},
+		Listeners: []*config.HTTPListenerConfig{
+			{
+				Address:               "127.0.0.1",
+				ContentSecurityPolicy: "default-src 'self'; frame-ancestors https://tools.example.com",
+				CORS: &config.CORSConfig{
+					AllowedOrigins: []string{"https://tools.example.com"},
+					AllowedMethods: []string{"GET"},
+				},
+			},
+			{
+				Address:   "127.0.0.1:9646",
+				Endpoints: []string{"read", "write"},
+				TLS: &config.HTTPListenerTLSConfig{
+					Enabled:           helper.BoolToPtr(true),
+					CAFile:            "/etc/nomad/admin-ca.pem",
+					CertFile:          "/etc/nomad/admin.pem",
+					KeyFile:           "/etc/nomad/admin-key.pem",
+					VerifyHTTPSClient: helper.BoolToPtr(true),
+				},
+			},
+		},
+	}, c.HTTP)
+	require.Empty(t, c.ExtraKeysHCL)
+}
+
 var sample0 = &Config{
 	Region:     "global",
 	Datacenter: "dc1",
@@ -891,3 +981,27 @@ func permutations(arr []string) [][]string {
 	helper(arr, len(arr))
 	return res
 }
+
+func TestConfig_ParseArtifactFetcher(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/artifact-fetcher.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, []*config.ArtifactFetcherConfig{
+		{
+			Scheme:  "store",
+			Command: "/usr/local/bin/store-fetch",
+			Args:    []string{"-region", "east"},
+			User:    "nobody",
+			Env:     map[string]string{"PATH": "/usr/bin:/bin"},
+			Config:  map[string]string{"endpoint": "https://store.internal"},
+			Timeout: "5m",
+		},
+		{
+			Scheme:  "oci",
+			Command: "/usr/local/bin/oci-fetch",
+		},
+	}, c.Client.Artifact.Fetchers)
+	require.Empty(t, c.Client.ExtraKeysHCL)
+}
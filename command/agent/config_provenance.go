@@ -0,0 +1,193 @@
+package agent
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+const (
+	// ConfigSourceDefault is the source of configuration values left to their
+	// default or computed by the agent.
+	ConfigSourceDefault = "default"
+
+	// ConfigSourceFile is the source of configuration values set in a
+	// configuration file.
+	ConfigSourceFile = "file"
+
+	// ConfigSourceEnv is the source of configuration values read from an
+	// environment variable.
+	ConfigSourceEnv = "env"
+
+	// ConfigSourceFlag is the source of configuration values set by a command
+	// line flag.
+	ConfigSourceFlag = "flag"
+)
+
+// configEnvVars maps the configuration fields that may be read from the
+// environment when finalizing the configuration to their variable.
+var configEnvVars = map[string]string{
+	"Vault.Token":        "VAULT_TOKEN",
+	"Vault.Namespace":    "VAULT_NAMESPACE",
+	"Server.LicenseEnv":  "NOMAD_LICENSE",
+	"Server.LicensePath": "NOMAD_LICENSE_PATH",
+}
+
+// ConfigSource is where the effective value of a configuration field was set.
+type ConfigSource struct {
+	// Type is one of default, file, env or flag.
+	Type string
+
+	// Name is the configuration file, environment variable or flag that set
+	// the value, if known.
+	Name string `json:",omitempty"`
+}
+
+// EffectiveConfigValue is the effective value of a configuration field along
+// with its source.
+type EffectiveConfigValue struct {
+	Value  interface{}
+	Source string
+	Name   string `json:",omitempty"`
+}
+
+// configProvenance records the source of each configuration field while the
+// agent merges its configuration. Fields are keyed by the path of their Go
+// field names, such as "Client.GCInterval", as in the agent self endpoint.
+type configProvenance struct {
+	sources map[string]*ConfigSource
+}
+
+func newConfigProvenance() *configProvenance {
+	return &configProvenance{
+		sources: make(map[string]*ConfigSource),
+	}
+}
+
+// addDev records the fields the -dev flag changed from their default.
+func (p *configProvenance) addDev(defaults, dev *Config) {
+	before := flattenConfig(defaults)
+	for key, value := range flattenConfig(dev) {
+		if !reflect.DeepEqual(before[key], value) {
+			p.sources[key] = &ConfigSource{Type: ConfigSourceFlag, Name: "-dev"}
+		}
+	}
+}
+
+// addFiles records the fields set by the configuration loaded from a path.
+// When the path is a directory, each of its files is loaded again to find
+// which one set each field.
+func (p *configProvenance) addFiles(loaded *Config) {
+	if loaded == nil {
+		return
+	}
+	if len(loaded.Files) == 1 {
+		p.add(loaded, ConfigSourceFile, loaded.Files[0])
+		return
+	}
+	for _, path := range loaded.Files {
+		c, err := LoadConfig(path)
+		if err != nil || c == nil {
+			continue
+		}
+		p.add(c, ConfigSourceFile, path)
+	}
+}
+
+// addFlags records the fields set by command line flags.
+func (p *configProvenance) addFlags(cmdConfig *Config) {
+	p.add(cmdConfig, ConfigSourceFlag, "")
+}
+
+// addEnv records the fields Finalize read from the environment, given the
+// configuration flattened before it was finalized.
+func (p *configProvenance) addEnv(before map[string]interface{}, finalized *Config) {
+	after := flattenConfig(finalized)
+	for key, env := range configEnvVars {
+		if _, ok := os.LookupEnv(env); !ok {
+			continue
+		}
+		if !reflect.DeepEqual(before[key], after[key]) {
+			p.sources[key] = &ConfigSource{Type: ConfigSourceEnv, Name: env}
+		}
+	}
+}
+
+// add records the fields set in c, as the last layer merged so far.
+func (p *configProvenance) add(c *Config, typ, name string) {
+	for key, value := range flattenConfig(c) {
+		if value != nil && !reflect.ValueOf(value).IsZero() {
+			p.sources[key] = &ConfigSource{Type: typ, Name: name}
+		}
+	}
+}
+
+// EffectiveConfig returns the redacted configuration of the agent flattened
+// by field path, with each value annotated by its source.
+func (c *Config) EffectiveConfig() (map[string]*EffectiveConfigValue, error) {
+	rc, err := c.Redacted()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]*EffectiveConfigValue)
+	for key, value := range flattenConfig(rc) {
+		v := &EffectiveConfigValue{Value: value, Source: ConfigSourceDefault}
+		if source, ok := c.Sources[key]; ok {
+			v.Source = source.Type
+			v.Name = source.Name
+		}
+		out[key] = v
+	}
+	return out, nil
+}
+
+// flattenConfig returns the fields of the configuration keyed by the path of
+// their Go field names. Structs are walked into, other values, including maps
+// and slices, are returned whole. Fields excluded from the JSON encoding of
+// the configuration are skipped.
+func flattenConfig(c *Config) map[string]interface{} {
+	out := make(map[string]interface{})
+	if c != nil {
+		flattenValue(out, "", reflect.ValueOf(c))
+	}
+	return out
+}
+
+func flattenValue(out map[string]interface{}, prefix string, v reflect.Value) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || strings.Split(field.Tag.Get("json"), ",")[0] == "-" {
+			continue
+		}
+
+		key := field.Name
+		if prefix != "" {
+			key = prefix + "." + field.Name
+		}
+
+		fv := v.Field(i)
+		if isConfigStruct(fv.Type()) {
+			flattenValue(out, key, fv)
+			continue
+		}
+		out[key] = fv.Interface()
+	}
+}
+
+// isConfigStruct returns whether the type is a struct, or a pointer to one,
+// that flattenConfig walks into.
+func isConfigStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t.PkgPath() != "time"
+}
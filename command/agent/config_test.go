@@ -336,6 +336,8 @@ func TestConfig_Merge(t *testing.T) {
 			NumSchedulers:          helper.IntToPtr(2),
 			EnabledSchedulers:      []string{structs.JobTypeBatch},
 			NodeGCThreshold:        "12h",
+			EvalGCMaxPerJob:        10,
+			AllocGCMaxPerJob:       20,
 			HeartbeatGrace:         2 * time.Minute,
 			MinHeartbeatTTL:        2 * time.Minute,
 			MaxHeartbeatsPerSecond: 200.0,
@@ -344,6 +346,8 @@ func TestConfig_Merge(t *testing.T) {
 			RetryJoin:              []string{"1.1.1.1"},
 			RetryInterval:          time.Second * 10,
 			NonVotingServer:        true,
+			ReadReplica:            true,
+			RequireNodeIntroToken:  true,
 			RedundancyZone:         "bar",
 			UpgradeVersion:         "bar",
 			EnableEventBroker:      helper.BoolToPtr(true),
@@ -1368,6 +1368,14 @@ func (c *ServiceClient) AllocRegistrations(allocID string) (*serviceregistration
 					sreg.Checks = append(sreg.Checks, check)
 				}
 			}
+
+			// Include the checks Consul registered for the sidecar proxy of
+			// the service, so they are considered for the alloc health
+			for _, check := range checks {
+				if check.ServiceID == serviceID+sidecarSuffix {
+					sreg.Checks = append(sreg.Checks, check)
+				}
+			}
 		}
 	}
 
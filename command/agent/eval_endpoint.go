@@ -1,7 +1,9 @@
 package agent
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -23,6 +25,28 @@ func (s *HTTPServer) EvalsRequest(resp http.ResponseWriter, req *http.Request) (
 	}
 }
 
+func (s *HTTPServer) EvalsBlockedResourcesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.EvalBlockedResourcesResponse
+	if err := s.agent.RPC("Eval.BlockedResources", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Demand == nil {
+		out.Demand = make([]*structs.BlockedResourcesDemand, 0)
+	}
+	return out.Demand, nil
+}
+
 func (s *HTTPServer) evalsListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 
 	args := structs.EvalListRequest{}
@@ -83,6 +107,9 @@ func (s *HTTPServer) EvalSpecificRequest(resp http.ResponseWriter, req *http.Req
 	case strings.HasSuffix(path, "/allocations"):
 		evalID := strings.TrimSuffix(path, "/allocations")
 		return s.evalAllocations(resp, req, evalID)
+	case strings.HasSuffix(path, "/retry"):
+		evalID := strings.TrimSuffix(path, "/retry")
+		return s.evalRetry(resp, req, evalID)
 	default:
 		return s.evalQuery(resp, req, path)
 	}
@@ -112,6 +139,33 @@ func (s *HTTPServer) evalAllocations(resp http.ResponseWriter, req *http.Request
 	return out.Allocations, nil
 }
 
+func (s *HTTPServer) evalRetry(resp http.ResponseWriter, req *http.Request, evalID string) (interface{}, error) {
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	// The request body is optional as it only carries the overrides.
+	var args structs.EvalRetryRequest
+	if req.Body != nil && req.Body != http.NoBody {
+		if err := json.NewDecoder(req.Body).Decode(&args); err != nil && err != io.EOF {
+			return nil, CodedError(http.StatusBadRequest, err.Error())
+		}
+	}
+	if args.EvalID == "" {
+		args.EvalID = evalID
+	} else if args.EvalID != evalID {
+		return nil, CodedError(http.StatusBadRequest, "EvalID not same as eval ID in path")
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.EvalRetryResponse
+	if err := s.agent.RPC(structs.EvalRetryRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) evalQuery(resp http.ResponseWriter, req *http.Request, evalID string) (interface{}, error) {
 	if req.Method != "GET" {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -14,6 +14,25 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestHTTP_EvalsBlockedResources(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// No evals are blocked
+		req, err := http.NewRequest("GET", "/v1/evaluations/blocked-resources", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.EvalsBlockedResourcesRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEqual(t, "", respW.Result().Header.Get("X-Nomad-Index"))
+		require.Equal(t, []*structs.BlockedResourcesDemand{}, obj)
+
+		req, err = http.NewRequest("PUT", "/v1/evaluations/blocked-resources", nil)
+		require.NoError(t, err)
+		_, err = s.Server.EvalsBlockedResourcesRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, ErrInvalidMethod)
+	})
+}
+
 func TestHTTP_EvalList(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -289,6 +308,46 @@ func TestHTTP_EvalAllocations(t *testing.T) {
 	})
 }
 
+func TestHTTP_EvalRetry(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		job := mock.Job()
+		require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, job))
+		eval := mock.Eval()
+		eval.JobID = job.ID
+		eval.Status = structs.EvalStatusFailed
+		require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval}))
+
+		// Make the HTTP request
+		args := api.EvalRetryRequest{
+			Overrides: &api.EvalOverrides{Datacenters: []string{"dc2"}},
+		}
+		req, err := http.NewRequest(http.MethodPut,
+			"/v1/evaluation/"+eval.ID+"/retry", encodeReq(args))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.EvalSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		out := obj.(structs.EvalRetryResponse)
+		newEval, err := state.EvalByID(nil, out.EvalID)
+		require.NoError(t, err)
+		require.NotNil(t, newEval)
+		require.Equal(t, eval.ID, newEval.PreviousEval)
+		require.Equal(t, []string{"dc2"}, newEval.Overrides.Datacenters)
+
+		// A GET is not allowed
+		req, err = http.NewRequest(http.MethodGet, "/v1/evaluation/"+eval.ID+"/retry", nil)
+		require.NoError(t, err)
+		_, err = s.Server.EvalSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+	})
+}
+
 func TestHTTP_EvalQuery(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -130,6 +131,76 @@ func (s *HTTPServer) EventStream(resp http.ResponseWriter, req *http.Request) (i
 	return nil, codedErr
 }
 
+// EventMetrics returns the metrics of the events published by the event
+// broker of a server, as JSON or in the Prometheus text format if the format
+// query parameter is "prometheus".
+func (s *HTTPServer) EventMetrics(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	var args structs.GenericRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.EventMetricsResponse
+	if err := s.agent.RPC("Event.Metrics", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	if req.URL.Query().Get("format") == "prometheus" {
+		resp.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writePrometheusEventMetrics(resp, reply.Metrics)
+		return nil, nil
+	}
+	return reply.Metrics, nil
+}
+
+// writePrometheusEventMetrics writes the event broker metrics in the
+// Prometheus text format.
+func writePrometheusEventMetrics(w io.Writer, m *structs.EventBrokerMetrics) {
+	metric := func(name, typ, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	metric("nomad_event_broker_events_total", "counter", "Number of events published by topic and type.")
+	topics := make([]string, 0, len(m.Published))
+	for topic := range m.Published {
+		topics = append(topics, string(topic))
+	}
+	sort.Strings(topics)
+	for _, topic := range topics {
+		byType := m.Published[structs.Topic(topic)]
+		types := make([]string, 0, len(byType))
+		for typ := range byType {
+			types = append(types, typ)
+		}
+		sort.Strings(types)
+		for _, typ := range types {
+			fmt.Fprintf(w, "nomad_event_broker_events_total{topic=%q,type=%q} %d\n", topic, typ, byType[typ])
+		}
+	}
+
+	for _, v := range []struct {
+		name, typ, help string
+		value           uint64
+	}{
+		{"nomad_event_broker_latest_index", "gauge", "Index of the latest published events.", m.LatestIndex},
+		{"nomad_event_broker_buffer_len", "gauge", "Number of indexes whose events are in the buffer.", uint64(m.BufferLen)},
+		{"nomad_event_broker_subscribers", "gauge", "Number of open subscriptions.", uint64(m.Subscribers)},
+		{"nomad_event_broker_subscribers_lagging", "gauge", "Number of subscribers whose next events were dropped from the buffer.", uint64(m.LaggingSubscribers)},
+		{"nomad_event_broker_subscribers_max_lag", "gauge", "Largest difference between the latest index and the index last read by a subscriber.", m.MaxSubscriberLag},
+		{"nomad_event_broker_subscriptions_total", "counter", "Number of subscriptions opened.", m.Subscriptions},
+		{"nomad_event_broker_subscriptions_dropped_total", "counter", "Number of subscriptions closed as they were too slow.", m.DroppedSubscriptions},
+		{"nomad_event_broker_subscriptions_closed_total", "counter", "Number of subscriptions closed because of their ACL token.", m.ClosedSubscriptions},
+	} {
+		metric(v.name, v.typ, v.help)
+		fmt.Fprintf(w, "%s %d\n", v.name, v.value)
+	}
+}
+
 func parseEventTopics(query url.Values) (map[structs.Topic][]string, error) {
 	raw, ok := query["topic"]
 	if !ok {
@@ -202,3 +202,41 @@ func TestEventStream_QueryParse(t *testing.T) {
 		})
 	}
 }
+
+func TestEventMetrics(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		pub, err := s.Agent.server.State().EventBroker()
+		require.NoError(t, err)
+		pub.Publish(&structs.Events{Index: 100, Events: []structs.Event{
+			{Topic: structs.TopicJob, Type: structs.TypeJobRegistered},
+		}})
+
+		testutil.WaitForResult(func() (bool, error) {
+			req, err := http.NewRequest("GET", "/v1/event/metrics", nil)
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+			obj, err := s.Server.EventMetrics(resp, req)
+			require.NoError(t, err)
+
+			m := obj.(*structs.EventBrokerMetrics)
+			if n := m.Published[structs.TopicJob][structs.TypeJobRegistered]; n < 1 {
+				return false, fmt.Errorf("expected published job event, got %#v", m.Published)
+			}
+			return true, nil
+		}, func(err error) {
+			require.NoError(t, err)
+		})
+
+		req, err := http.NewRequest("GET", "/v1/event/metrics?format=prometheus", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		obj, err := s.Server.EventMetrics(resp, req)
+		require.NoError(t, err)
+		require.Nil(t, obj)
+		require.Contains(t, resp.Body.String(), "# TYPE nomad_event_broker_events_total counter\n")
+		require.Contains(t, resp.Body.String(), `nomad_event_broker_events_total{topic="Job",type="JobRegistered"} `)
+		require.Contains(t, resp.Body.String(), "nomad_event_broker_subscribers 0\n")
+	})
+}
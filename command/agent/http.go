@@ -29,6 +29,7 @@ import (
 	"github.com/hashicorp/nomad/helper/noxssrw"
 	"github.com/hashicorp/nomad/helper/tlsutil"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
 const (
@@ -69,8 +70,18 @@ var (
 			AllowCredentials: true,
 		})
 	}
+
+	// defaultCORSMethods, defaultCORSHeaders and defaultCORSExposedHeaders
+	// are used by the CORS configuration of a listener unless it sets them
+	defaultCORSMethods        = []string{"HEAD", "GET", "POST", "PUT", "DELETE"}
+	defaultCORSHeaders        = []string{"Content-Type", "X-Nomad-Token"}
+	defaultCORSExposedHeaders = []string{"X-Nomad-Index", "X-Nomad-KnownLeader", "X-Nomad-LastContact"}
 )
 
+// defaultUIContentSecurityPolicy is the Content-Security-Policy header of the
+// web UI responses unless the listener configures one.
+const defaultUIContentSecurityPolicy = "default-src 'none'; connect-src *; img-src 'self' data:; script-src 'self'; style-src 'self' 'unsafe-inline'; form-action 'none'; frame-ancestors 'none'"
+
 type handlerFn func(resp http.ResponseWriter, req *http.Request) (interface{}, error)
 type handlerByteFn func(resp http.ResponseWriter, req *http.Request) ([]byte, error)
 
@@ -83,11 +94,15 @@ type HTTPServer struct {
 	logger     log.Logger
 	Addr       string
 
+	// listenerConfig is the CORS, content security policy, TLS and endpoints
+	// configuration of the listener
+	listenerConfig *config.HTTPListenerConfig
+
 	wsUpgrader *websocket.Upgrader
 }
 
 // NewHTTPServers starts an HTTP server for every address.http configured in
-// the agent.
+// the agent, and for every additional listener of the http stanza.
 func NewHTTPServers(agent *Agent, config *Config) ([]*HTTPServer, error) {
 	var srvs []*HTTPServer
 	var serverInitializationErrors error
@@ -109,18 +124,25 @@ func NewHTTPServers(agent *Agent, config *Config) ([]*HTTPServer, error) {
 		return srvs, fmt.Errorf("http_max_conns_per_client must be >= 0")
 	}
 
-	tlsConf, err := tlsutil.NewTLSConfiguration(config.TLSConfig, config.TLSConfig.VerifyHTTPSClient, true)
-	if err != nil && config.TLSConfig.EnableHTTP {
-		return srvs, fmt.Errorf("failed to initialize HTTP server TLS configuration: %s", err)
-	}
-
 	wsUpgrader := &websocket.Upgrader{
 		ReadBufferSize:  2048,
 		WriteBufferSize: 2048,
 	}
 
-	// Start the listener
-	for _, addr := range config.normalizedAddrs.HTTP {
+	// Start the listeners
+	addrs := config.normalizedAddrs.HTTP
+	addrs = append(addrs[:len(addrs):len(addrs)], config.HTTP.AdditionalListeners(addrs)...)
+	for _, addr := range addrs {
+		lnConfig := config.HTTP.ListenerConfig(addr)
+		tlsConfig := lnConfig.TLSConfig(config.TLSConfig)
+
+		tlsConf, err := tlsutil.NewTLSConfiguration(tlsConfig, tlsConfig.VerifyHTTPSClient, true)
+		if err != nil && tlsConfig.EnableHTTP {
+			serverInitializationErrors = multierror.Append(serverInitializationErrors,
+				fmt.Errorf("failed to initialize HTTP server TLS configuration for %s: %s", addr, err))
+			continue
+		}
+
 		lnAddr, err := net.ResolveTCPAddr("tcp", addr)
 		if err != nil {
 			serverInitializationErrors = multierror.Append(serverInitializationErrors, err)
@@ -133,32 +155,44 @@ func NewHTTPServers(agent *Agent, config *Config) ([]*HTTPServer, error) {
 		}
 
 		// If TLS is enabled, wrap the listener with a TLS listener
-		if config.TLSConfig.EnableHTTP {
-			tlsConfig, err := tlsConf.IncomingTLSConfig()
+		if tlsConfig.EnableHTTP {
+			incomingTLSConfig, err := tlsConf.IncomingTLSConfig()
 			if err != nil {
+				ln.Close()
 				serverInitializationErrors = multierror.Append(serverInitializationErrors, err)
 				continue
 			}
-			ln = tls.NewListener(tcpKeepAliveListener{ln.(*net.TCPListener)}, tlsConfig)
+			ln = tls.NewListener(tcpKeepAliveListener{ln.(*net.TCPListener)}, incomingTLSConfig)
 		}
 
 		// Create the server
 		srv := &HTTPServer{
-			agent:      agent,
-			mux:        http.NewServeMux(),
-			listener:   ln,
-			listenerCh: make(chan struct{}),
-			logger:     agent.httpLogger,
-			Addr:       ln.Addr().String(),
-			wsUpgrader: wsUpgrader,
+			agent:          agent,
+			mux:            http.NewServeMux(),
+			listener:       ln,
+			listenerCh:     make(chan struct{}),
+			logger:         agent.httpLogger,
+			Addr:           ln.Addr().String(),
+			listenerConfig: lnConfig,
+			wsUpgrader:     wsUpgrader,
 		}
 		srv.registerHandlers(config.EnableDebug)
 
+		// Only serve the endpoints allowed on the listener, and allow
+		// cross-origin requests to every endpoint if configured
+		handler := handlers.CompressHandler(srv.mux)
+		if len(lnConfig.Endpoints) != 0 {
+			handler = srv.handleAllowedEndpoints(handler)
+		}
+		if c := lnConfig.CORS; c.Enabled() {
+			handler = newListenerCORS(c).Handler(handler)
+		}
+
 		// Create HTTP server with timeouts
 		httpServer := http.Server{
 			Addr:      srv.Addr,
-			Handler:   handlers.CompressHandler(srv.mux),
-			ConnState: makeConnState(config.TLSConfig.EnableHTTP, handshakeTimeout, maxConns, srv.logger),
+			Handler:   handler,
+			ConnState: makeConnState(tlsConfig.EnableHTTP, handshakeTimeout, maxConns, srv.logger),
 			ErrorLog:  newHTTPServerLogger(srv.logger),
 		}
 
@@ -350,15 +384,18 @@ func (s *HTTPServer) ResolveToken(req *http.Request) (*acl.ACL, error) {
 func (s HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/jobs", s.wrap(s.JobsRequest))
 	s.mux.HandleFunc("/v1/jobs/parse", s.wrap(s.JobsParseRequest))
+	s.mux.HandleFunc("/v1/jobs/trash", s.wrap(s.JobsTrashRequest))
 	s.mux.HandleFunc("/v1/job/", s.wrap(s.JobSpecificRequest))
 
 	s.mux.HandleFunc("/v1/nodes", s.wrap(s.NodesRequest))
 	s.mux.HandleFunc("/v1/node/", s.wrap(s.NodeSpecificRequest))
+	s.mux.HandleFunc("/v1/node/intro-token", s.wrap(s.NodeIntroTokenRequest))
 
 	s.mux.HandleFunc("/v1/allocations", s.wrap(s.AllocsRequest))
 	s.mux.HandleFunc("/v1/allocation/", s.wrap(s.AllocSpecificRequest))
 
 	s.mux.HandleFunc("/v1/evaluations", s.wrap(s.EvalsRequest))
+	s.mux.HandleFunc("/v1/evaluations/blocked-resources", s.wrap(s.EvalsBlockedResourcesRequest))
 	s.mux.HandleFunc("/v1/evaluation/", s.wrap(s.EvalSpecificRequest))
 
 	s.mux.HandleFunc("/v1/deployments", s.wrap(s.DeploymentsRequest))
@@ -381,16 +418,20 @@ func (s HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/acl/token", s.wrap(s.ACLTokenSpecificRequest))
 	s.mux.HandleFunc("/v1/acl/token/", s.wrap(s.ACLTokenSpecificRequest))
 
-	s.mux.Handle("/v1/client/fs/", wrapCORS(s.wrap(s.FsRequest)))
+	s.mux.Handle("/v1/client/fs/", s.wrapCORS(s.wrap(s.FsRequest)))
 	s.mux.HandleFunc("/v1/client/gc", s.wrap(s.ClientGCRequest))
-	s.mux.Handle("/v1/client/stats", wrapCORS(s.wrap(s.ClientStatsRequest)))
-	s.mux.Handle("/v1/client/allocation/", wrapCORS(s.wrap(s.ClientAllocRequest)))
+	s.mux.Handle("/v1/client/stats", s.wrapCORS(s.wrap(s.ClientStatsRequest)))
+	s.mux.Handle("/v1/client/inventory", s.wrapCORS(s.wrap(s.ClientInventoryRequest)))
+	s.mux.Handle("/v1/client/allocation/", s.wrapCORS(s.wrap(s.ClientAllocRequest)))
 
 	s.mux.HandleFunc("/v1/agent/self", s.wrap(s.AgentSelfRequest))
+	s.mux.HandleFunc("/v1/agent/config", s.wrap(s.AgentConfigRequest))
+	s.mux.HandleFunc("/v1/agent/log-levels", s.wrap(s.AgentLogLevelsRequest))
 	s.mux.HandleFunc("/v1/agent/join", s.wrap(s.AgentJoinRequest))
 	s.mux.HandleFunc("/v1/agent/members", s.wrap(s.AgentMembersRequest))
 	s.mux.HandleFunc("/v1/agent/force-leave", s.wrap(s.AgentForceLeaveRequest))
 	s.mux.HandleFunc("/v1/agent/servers", s.wrap(s.AgentServersRequest))
+	s.mux.HandleFunc("/v1/agent/prepare-upgrade", s.wrap(s.AgentPrepareUpgradeRequest))
 	s.mux.HandleFunc("/v1/agent/schedulers", s.wrap(s.AgentSchedulerWorkerInfoRequest))
 	s.mux.HandleFunc("/v1/agent/schedulers/config", s.wrap(s.AgentSchedulerWorkerConfigRequest))
 	s.mux.HandleFunc("/v1/agent/keyring/", s.wrap(s.KeyringOperationRequest))
@@ -433,17 +474,21 @@ func (s HTTPServer) registerHandlers(enableDebug bool) {
 
 	s.mux.HandleFunc("/v1/system/gc", s.wrap(s.GarbageCollectRequest))
 	s.mux.HandleFunc("/v1/system/reconcile/summaries", s.wrap(s.ReconcileJobSummaries))
+	s.mux.HandleFunc("/v1/system/reconcile/deployments", s.wrap(s.ReconcileDeployments))
+	s.mux.HandleFunc("/v1/system/reconcile/services", s.wrap(s.ReconcileServiceRegistrations))
 
 	s.mux.HandleFunc("/v1/operator/scheduler/configuration", s.wrap(s.OperatorSchedulerConfiguration))
 
 	s.mux.HandleFunc("/v1/event/stream", s.wrap(s.EventStream))
+	s.mux.HandleFunc("/v1/event/metrics", s.wrap(s.EventMetrics))
 
 	s.mux.HandleFunc("/v1/namespaces", s.wrap(s.NamespacesRequest))
 	s.mux.HandleFunc("/v1/namespace", s.wrap(s.NamespaceCreateRequest))
 	s.mux.HandleFunc("/v1/namespace/", s.wrap(s.NamespaceSpecificRequest))
 
-	s.mux.Handle("/v1/vars", wrapCORS(s.wrap(s.SecureVariablesListRequest)))
-	s.mux.Handle("/v1/var/", wrapCORSWithAllowedMethods(s.wrap(s.SecureVariableSpecificRequest), "HEAD", "GET", "PUT", "DELETE"))
+	s.mux.Handle("/v1/vars", s.wrapCORS(s.wrap(s.SecureVariablesListRequest)))
+	s.mux.Handle("/v1/vars/txn", s.wrapCORSWithAllowedMethods(s.wrap(s.SecureVariablesTxnRequest), "PUT", "POST"))
+	s.mux.Handle("/v1/var/", s.wrapCORSWithAllowedMethods(s.wrap(s.SecureVariableSpecificRequest), "HEAD", "GET", "PUT", "DELETE"))
 
 	uiConfigEnabled := s.agent.config.UI != nil && s.agent.config.UI.Enabled
 
@@ -519,12 +564,50 @@ func (e *codedError) Code() int {
 
 func (s *HTTPServer) handleUI(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		csp := defaultUIContentSecurityPolicy
+		if s.listenerConfig != nil && s.listenerConfig.ContentSecurityPolicy != "" {
+			csp = s.listenerConfig.ContentSecurityPolicy
+		}
 		header := w.Header()
-		header.Add("Content-Security-Policy", "default-src 'none'; connect-src *; img-src 'self' data:; script-src 'self'; style-src 'self' 'unsafe-inline'; form-action 'none'; frame-ancestors 'none'")
+		header.Add("Content-Security-Policy", csp)
+		h.ServeHTTP(w, req)
+	})
+}
+
+// handleAllowedEndpoints rejects the requests to the classes of endpoints the
+// listener doesn't serve.
+func (s *HTTPServer) handleAllowedEndpoints(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		class := httpEndpointClass(req)
+		if !s.listenerConfig.AllowsEndpoints(class) {
+			s.logger.Debug("request to endpoint not allowed on listener",
+				"path", req.URL.Path, "method", req.Method, "class", class)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(fmt.Sprintf("%s endpoints are not served on this listener", class)))
+			return
+		}
 		h.ServeHTTP(w, req)
 	})
 }
 
+// httpEndpointClass returns the class of the endpoint of the request. Exec
+// sessions are write endpoints even though they start with a GET request.
+func httpEndpointClass(req *http.Request) string {
+	path := req.URL.Path
+	switch {
+	case path == "/" || strings.HasPrefix(path, "/ui/"):
+		return config.HTTPEndpointsUI
+	case path == "/v1/metrics":
+		return config.HTTPEndpointsMetrics
+	case strings.HasPrefix(path, "/v1/client/allocation/") && strings.HasSuffix(path, "/exec"):
+		return config.HTTPEndpointsWrite
+	case strings.HasPrefix(path, "/v1/") && (req.Method == http.MethodGet || req.Method == http.MethodHead):
+		return config.HTTPEndpointsRead
+	default:
+		return config.HTTPEndpointsWrite
+	}
+}
+
 func (s *HTTPServer) handleRootFallthrough() http.Handler {
 	return s.auditHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		if req.URL.Path == "/" {
@@ -937,12 +1020,43 @@ func (s *HTTPServer) wrapUntrustedContent(handler handlerFn) handlerFn {
 
 // wrapCORS wraps a HandlerFunc in allowCORS with read ("HEAD", "GET") methods
 // and returns a http.Handler
-func wrapCORS(f func(http.ResponseWriter, *http.Request)) http.Handler {
-	return wrapCORSWithAllowedMethods(f, "HEAD", "GET")
+func (s *HTTPServer) wrapCORS(f func(http.ResponseWriter, *http.Request)) http.Handler {
+	return s.wrapCORSWithAllowedMethods(f, "HEAD", "GET")
 }
 
 // wrapCORSWithAllowedMethods wraps a HandlerFunc in an allowCORS with the given
-// method list and returns a http.Handler
-func wrapCORSWithAllowedMethods(f func(http.ResponseWriter, *http.Request), methods ...string) http.Handler {
+// method list and returns a http.Handler. The handler isn't wrapped if the
+// listener configures CORS, since its configuration applies to every endpoint.
+func (s *HTTPServer) wrapCORSWithAllowedMethods(f func(http.ResponseWriter, *http.Request), methods ...string) http.Handler {
+	if s.listenerConfig != nil && s.listenerConfig.CORS.Enabled() {
+		return http.HandlerFunc(f)
+	}
 	return allowCORSWithMethods(methods...).Handler(http.HandlerFunc(f))
 }
+
+// newListenerCORS returns the CORS handler for the CORS configuration of a
+// listener.
+func newListenerCORS(c *config.CORSConfig) *cors.Cors {
+	opts := cors.Options{
+		AllowedOrigins: c.AllowedOrigins,
+		AllowedMethods: c.AllowedMethods,
+		AllowedHeaders: c.AllowedHeaders,
+		ExposedHeaders: c.ExposedHeaders,
+	}
+	if len(opts.AllowedMethods) == 0 {
+		opts.AllowedMethods = defaultCORSMethods
+	}
+	if len(opts.AllowedHeaders) == 0 {
+		opts.AllowedHeaders = defaultCORSHeaders
+	}
+	if len(opts.ExposedHeaders) == 0 {
+		opts.ExposedHeaders = defaultCORSExposedHeaders
+	}
+	if c.AllowCredentials != nil {
+		opts.AllowCredentials = *c.AllowCredentials
+	}
+	if c.MaxAge != nil {
+		opts.MaxAge = *c.MaxAge
+	}
+	return cors.New(opts)
+}
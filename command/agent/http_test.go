@@ -24,6 +24,7 @@ import (
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/freeport"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -151,6 +152,204 @@ func TestRootFallthrough(t *testing.T) {
 	}
 }
 
+func TestHTTP_ListenerCORS(t *testing.T) {
+	ci.Parallel(t)
+
+	s := makeHTTPServer(t, func(c *Config) {
+		c.Addresses.HTTP = "127.0.0.1 127.0.0.2"
+		c.HTTP = &config.HTTPConfig{
+			CORS: &config.CORSConfig{
+				AllowedOrigins: []string{"https://*.example.com"},
+				MaxAge:         helper.IntToPtr(600),
+			},
+			Listeners: []*config.HTTPListenerConfig{
+				{
+					Address: "127.0.0.2",
+					CORS: &config.CORSConfig{
+						AllowedOrigins: []string{"https://tools.example.com"},
+						AllowedMethods: []string{"GET"},
+					},
+				},
+			},
+		}
+	})
+	defer s.Shutdown()
+
+	preflight := func(ip, origin, method string) *http.Response {
+		req, err := http.NewRequest("OPTIONS", fmt.Sprintf("http://%s:%d/v1/jobs", ip, s.ports[0]), nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", "X-Nomad-Token")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	t.Run("preflight allowed", func(t *testing.T) {
+		resp := preflight("127.0.0.1", "https://ops.example.com", "POST")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		require.Equal(t, "https://ops.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "POST", resp.Header.Get("Access-Control-Allow-Methods"))
+		require.Equal(t, "X-Nomad-Token", resp.Header.Get("Access-Control-Allow-Headers"))
+		require.Equal(t, "600", resp.Header.Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("preflight origin denied", func(t *testing.T) {
+		resp := preflight("127.0.0.1", "https://evil.com", "POST")
+		require.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("listener override", func(t *testing.T) {
+		resp := preflight("127.0.0.2", "https://ops.example.com", "GET")
+		require.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+		resp = preflight("127.0.0.2", "https://tools.example.com", "POST")
+		require.Empty(t, resp.Header.Get("Access-Control-Allow-Methods"))
+
+		resp = preflight("127.0.0.2", "https://tools.example.com", "GET")
+		require.Equal(t, "https://tools.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("actual request", func(t *testing.T) {
+		// Endpoints with permissive CORS headers use the listener
+		// configuration instead
+		for _, path := range []string{"/v1/jobs", "/v1/vars"} {
+			req, err := http.NewRequest("GET", fmt.Sprintf("http://127.0.0.1:%d%s", s.ports[0], path), nil)
+			require.NoError(t, err)
+			req.Header.Set("Origin", "https://ops.example.com")
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, "https://ops.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+			require.Empty(t, resp.Header.Get("Access-Control-Allow-Credentials"))
+			require.Contains(t, resp.Header.Get("Access-Control-Expose-Headers"), "X-Nomad-Index")
+		}
+	})
+}
+
+func TestHTTP_AdditionalListeners(t *testing.T) {
+	ci.Parallel(t)
+
+	const (
+		foocert = "../../helper/tlsutil/testdata/nomad-foo.pem"
+		fookey  = "../../helper/tlsutil/testdata/nomad-foo-key.pem"
+	)
+
+	ports := freeport.MustTake(2)
+	defer freeport.Return(ports)
+	metricsAddr := fmt.Sprintf("127.0.0.1:%d", ports[0])
+	adminAddr := fmt.Sprintf("127.0.0.1:%d", ports[1])
+
+	s := makeHTTPServer(t, func(c *Config) {
+		c.HTTP = &config.HTTPConfig{
+			Listeners: []*config.HTTPListenerConfig{
+				{
+					Address:   metricsAddr,
+					Endpoints: []string{config.HTTPEndpointsMetrics},
+				},
+				{
+					Address:   adminAddr,
+					Endpoints: []string{config.HTTPEndpointsRead, config.HTTPEndpointsWrite},
+					TLS: &config.HTTPListenerTLSConfig{
+						Enabled:  helper.BoolToPtr(true),
+						CertFile: foocert,
+						KeyFile:  fookey,
+					},
+				},
+			},
+		}
+	})
+	defer s.Shutdown()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+	get := func(url string) int {
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The agent listener serves every endpoint
+	require.Equal(t, http.StatusOK, get(fmt.Sprintf("http://127.0.0.1:%d/v1/jobs", s.ports[0])))
+	require.Equal(t, http.StatusOK, get(fmt.Sprintf("http://127.0.0.1:%d/v1/metrics", s.ports[0])))
+
+	// The metrics listener only serves the metrics
+	require.Equal(t, http.StatusOK, get("http://"+metricsAddr+"/v1/metrics"))
+	require.Equal(t, http.StatusForbidden, get("http://"+metricsAddr+"/v1/jobs"))
+	require.Equal(t, http.StatusForbidden, get("http://"+metricsAddr+"/ui/"))
+
+	// The admin listener serves the API over TLS
+	require.Equal(t, http.StatusOK, get("https://"+adminAddr+"/v1/jobs"))
+	require.Equal(t, http.StatusForbidden, get("https://"+adminAddr+"/v1/metrics"))
+	require.Equal(t, http.StatusBadRequest, get("http://"+adminAddr+"/v1/jobs"))
+}
+
+func TestHTTP_EndpointClass(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{"GET", "/", config.HTTPEndpointsUI},
+		{"GET", "/ui/jobs", config.HTTPEndpointsUI},
+		{"GET", "/v1/metrics", config.HTTPEndpointsMetrics},
+		{"GET", "/v1/jobs", config.HTTPEndpointsRead},
+		{"HEAD", "/v1/jobs", config.HTTPEndpointsRead},
+		{"PUT", "/v1/jobs", config.HTTPEndpointsWrite},
+		{"GET", "/v1/client/allocation/123/exec", config.HTTPEndpointsWrite},
+		{"GET", "/debug/pprof/", config.HTTPEndpointsWrite},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		require.Equal(t, tc.expected, httpEndpointClass(req), "%s %s", tc.method, tc.path)
+	}
+}
+
+func TestHTTP_UIContentSecurityPolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	cases := []struct {
+		name     string
+		config   *config.HTTPListenerConfig
+		expected string
+	}{
+		{
+			name:     "default",
+			expected: defaultUIContentSecurityPolicy,
+		},
+		{
+			name: "listener",
+			config: &config.HTTPListenerConfig{
+				ContentSecurityPolicy: "default-src 'self'; frame-ancestors https://tools.example.com",
+			},
+			expected: "default-src 'self'; frame-ancestors https://tools.example.com",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &HTTPServer{listenerConfig: tc.config}
+			resp := httptest.NewRecorder()
+			s.handleUI(ok).ServeHTTP(resp, httptest.NewRequest("GET", "/ui/", nil))
+			require.Equal(t, []string{tc.expected}, resp.Header().Values("Content-Security-Policy"))
+		})
+	}
+}
+
 func TestSetIndex(t *testing.T) {
 	ci.Parallel(t)
 	resp := httptest.NewRecorder()
@@ -87,6 +87,15 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/stable"):
 		jobName := strings.TrimSuffix(path, "/stable")
 		return s.jobStable(resp, req, jobName)
+	case strings.HasSuffix(path, "/lineage"):
+		jobName := strings.TrimSuffix(path, "/lineage")
+		return s.jobLineage(resp, req, jobName)
+	case strings.HasSuffix(path, "/freeze"):
+		jobName := strings.TrimSuffix(path, "/freeze")
+		return s.jobFreeze(resp, req, jobName)
+	case strings.HasSuffix(path, "/restore"):
+		jobName := strings.TrimSuffix(path, "/restore")
+		return s.jobRestore(resp, req, jobName)
 	case strings.HasSuffix(path, "/scale"):
 		jobName := strings.TrimSuffix(path, "/scale")
 		return s.jobScale(resp, req, jobName)
@@ -161,8 +170,13 @@ func (s *HTTPServer) jobPlan(resp http.ResponseWriter, req *http.Request,
 		WriteRequest:   *writeReq,
 	}
 
+	method := "Job.Plan"
+	if args.Verbose {
+		method = "Job.PlanVerbose"
+	}
+
 	var out structs.JobPlanResponse
-	if err := s.agent.RPC("Job.Plan", &planReq, &out); err != nil {
+	if err := s.agent.RPC(method, &planReq, &out); err != nil {
 		return nil, err
 	}
 	setIndex(resp, out.Index)
@@ -301,6 +315,30 @@ func (s *HTTPServer) jobDeployments(resp http.ResponseWriter, req *http.Request,
 	return out.Deployments, nil
 }
 
+func (s *HTTPServer) jobLineage(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.JobSpecificRequest{
+		JobID: jobName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobLineageResponse
+	if err := s.agent.RPC("Job.Lineage", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Lineage == nil {
+		out.Lineage = make([]*structs.JobLineageNode, 0)
+	}
+	return out.Lineage, nil
+}
+
 func (s *HTTPServer) jobLatestDeployment(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 	if req.Method != "GET" {
@@ -473,6 +511,17 @@ func (s *HTTPServer) jobDelete(resp http.ResponseWriter, req *http.Request,
 	}
 	args.NoShutdownDelay = noShutdownDelay
 
+	// Identify the job_modify_index query param and parse. If set, the
+	// deregister is only applied if the job has not been modified since.
+	if jmiStr := req.URL.Query().Get("job_modify_index"); jmiStr != "" {
+		jmi, err := strconv.ParseUint(jmiStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse value of %q (%v) as a uint64: %v", "job_modify_index", jmiStr, err)
+		}
+		args.EnforceIndex = true
+		args.JobModifyIndex = jmi
+	}
+
 	// Validate the evaluation priority if the user supplied a non-default
 	// value. It's more efficient to do it here, within the agent rather than
 	// sending a bad request for the server to reject.
@@ -554,6 +603,8 @@ func (s *HTTPServer) jobScaleAction(resp http.ResponseWriter, req *http.Request,
 		Message:        args.Message,
 		Error:          args.Error,
 		Meta:           args.Meta,
+		EnforceIndex:   args.EnforceIndex,
+		JobModifyIndex: args.JobModifyIndex,
 	}
 	// parseWriteRequest overrides Namespace, Region and AuthToken
 	// based on values from the original http request
@@ -659,6 +710,79 @@ func (s *HTTPServer) jobStable(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobFreeze(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var freezeRequest structs.JobFreezeRequest
+	if err := decodeBody(req, &freezeRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if freezeRequest.JobID == "" {
+		return nil, CodedError(400, "JobID must be specified")
+	}
+	if freezeRequest.JobID != jobName {
+		return nil, CodedError(400, "Job ID does not match")
+	}
+
+	s.parseWriteRequest(req, &freezeRequest.WriteRequest)
+
+	var out structs.JobFreezeResponse
+	if err := s.agent.RPC("Job.Freeze", &freezeRequest, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) jobRestore(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.JobRestoreRequest{
+		JobID: jobName,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.JobRestoreResponse
+	if err := s.agent.RPC("Job.Restore", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+// JobsTrashRequest lists the purged jobs in the job trash.
+func (s *HTTPServer) JobsTrashRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.JobTrashListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobTrashListResponse
+	if err := s.agent.RPC("Job.ListTrash", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Jobs == nil {
+		out.Jobs = make([]*structs.TrashedJobListStub, 0)
+	}
+	return out.Jobs, nil
+}
+
 func (s *HTTPServer) jobSummaryRequest(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
 	args := structs.JobSummaryRequest{
 		JobID: name,
@@ -805,6 +929,7 @@ func (s *HTTPServer) apiJobAndRequestToStructs(job *api.Job, req *http.Request,
 	}
 
 	s.parseToken(req, &writeReq.AuthToken)
+	parseIdempotencyToken(req, &writeReq.IdempotencyToken)
 
 	queryRegion := req.URL.Query().Get("region")
 	requestRegion, jobRegion := regionForJob(
@@ -916,6 +1041,10 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Affinities:     ApiAffinitiesToStructs(job.Affinities),
 	}
 
+	if job.Deadline != nil {
+		j.Deadline = *job.Deadline
+	}
+
 	// Update has been pushed into the task groups. stagger and max_parallel are
 	// preserved at the job level, but all other values are discarded. The job.Update
 	// api value is merged into TaskGroups already in api.Canonicalize
@@ -956,6 +1085,16 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 			MetaRequired: job.ParameterizedJob.MetaRequired,
 			MetaOptional: job.ParameterizedJob.MetaOptional,
 		}
+
+		if d := job.ParameterizedJob.Dispatch; d != nil {
+			j.ParameterizedJob.Dispatch = &structs.DispatchConfig{}
+			if d.PurgeAfter != nil {
+				j.ParameterizedJob.Dispatch.PurgeAfter = *d.PurgeAfter
+			}
+			if d.KeepLast != nil {
+				j.ParameterizedJob.Dispatch.KeepLast = *d.KeepLast
+			}
+		}
 	}
 
 	if job.Multiregion != nil {
@@ -993,9 +1132,11 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 	tg.Meta = taskGroup.Meta
 	tg.Constraints = ApiConstraintsToStructs(taskGroup.Constraints)
 	tg.Affinities = ApiAffinitiesToStructs(taskGroup.Affinities)
+	tg.AntiAffinities = ApiAntiAffinitiesToStructs(taskGroup.AntiAffinities)
 	tg.Networks = ApiNetworkResourceToStructs(taskGroup.Networks)
 	tg.Services = ApiServicesToStructs(taskGroup.Services, true)
 	tg.Consul = apiConsulToStructs(taskGroup.Consul)
+	tg.Mesh = apiMeshToStructs(taskGroup.Mesh)
 
 	tg.RestartPolicy = &structs.RestartPolicy{
 		Attempts: *taskGroup.RestartPolicy.Attempts,
@@ -1016,6 +1157,14 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		tg.MaxClientDisconnect = taskGroup.MaxClientDisconnect
 	}
 
+	if taskGroup.ShutdownPriority != nil {
+		tg.ShutdownPriority = *taskGroup.ShutdownPriority
+	}
+
+	if taskGroup.Priority != nil {
+		tg.Priority = *taskGroup.Priority
+	}
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -1102,6 +1251,10 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		if taskGroup.Update.AutoPromote != nil {
 			tg.Update.AutoPromote = *taskGroup.Update.AutoPromote
 		}
+
+		if taskGroup.Update.PromotionGate != nil {
+			tg.Update.PromotionGate = *taskGroup.Update.PromotionGate
+		}
 	}
 
 	if len(taskGroup.Tasks) > 0 {
@@ -1135,6 +1288,7 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.KillTimeout = *apiTask.KillTimeout
 	structsTask.ShutdownDelay = apiTask.ShutdownDelay
 	structsTask.KillSignal = apiTask.KillSignal
+	structsTask.ReadinessFile = apiTask.ReadinessFile
 	structsTask.Kind = structs.TaskKind(apiTask.Kind)
 	structsTask.Constraints = ApiConstraintsToStructs(apiTask.Constraints)
 	structsTask.Affinities = ApiAffinitiesToStructs(apiTask.Affinities)
@@ -1211,18 +1365,20 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 		for _, template := range apiTask.Templates {
 			structsTask.Templates = append(structsTask.Templates,
 				&structs.Template{
-					SourcePath:   *template.SourcePath,
-					DestPath:     *template.DestPath,
-					EmbeddedTmpl: *template.EmbeddedTmpl,
-					ChangeMode:   *template.ChangeMode,
-					ChangeSignal: *template.ChangeSignal,
-					Splay:        *template.Splay,
-					Perms:        *template.Perms,
-					LeftDelim:    *template.LeftDelim,
-					RightDelim:   *template.RightDelim,
-					Envvars:      *template.Envvars,
-					VaultGrace:   *template.VaultGrace,
-					Wait:         ApiWaitConfigToStructsWaitConfig(template.Wait),
+					SourcePath:     *template.SourcePath,
+					DestPath:       *template.DestPath,
+					EmbeddedTmpl:   *template.EmbeddedTmpl,
+					ChangeMode:     *template.ChangeMode,
+					ChangeSignal:   *template.ChangeSignal,
+					Splay:          *template.Splay,
+					Perms:          *template.Perms,
+					LeftDelim:      *template.LeftDelim,
+					RightDelim:     *template.RightDelim,
+					Envvars:        *template.Envvars,
+					VaultGrace:     *template.VaultGrace,
+					Wait:           ApiWaitConfigToStructsWaitConfig(template.Wait),
+					OnRenderError:  *template.OnRenderError,
+					RenderMaxStale: *template.RenderMaxStale,
 				})
 		}
 	}
@@ -1239,6 +1395,22 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 			Sidecar: apiTask.Lifecycle.Sidecar,
 		}
 	}
+
+	if apiTask.Process != nil {
+		structsTask.Process = &structs.TaskProcess{
+			Umask:       apiTask.Process.Umask,
+			Nice:        apiTask.Process.Nice,
+			IOniceClass: apiTask.Process.IOniceClass,
+			Rlimits:     helper.CopyMap(apiTask.Process.Rlimits),
+		}
+	}
+
+	if apiTask.Identity != nil {
+		structsTask.Identity = &structs.WorkloadIdentity{
+			File:    apiTask.Identity.File,
+			TaskAPI: apiTask.Identity.TaskAPI,
+		}
+	}
 }
 
 // ApiWaitConfigToStructsWaitConfig is a copy and type conversion between the API
@@ -1264,6 +1436,8 @@ func ApiCSIPluginConfigToStructsCSIPluginConfig(apiConfig *api.TaskCSIPluginConf
 	sc.Type = structs.CSIPluginType(apiConfig.Type)
 	sc.MountDir = apiConfig.MountDir
 	sc.HealthTimeout = apiConfig.HealthTimeout
+	sc.ProbeInterval = apiConfig.ProbeInterval
+	sc.MaxProbeFailures = apiConfig.MaxProbeFailures
 	return sc
 }
 
@@ -1683,17 +1857,19 @@ func apiConnectSidecarTaskToStructs(in *api.SidecarTask) *structs.SidecarTask {
 		return nil
 	}
 	return &structs.SidecarTask{
-		Name:          in.Name,
-		Driver:        in.Driver,
-		User:          in.User,
-		Config:        in.Config,
-		Env:           in.Env,
-		Resources:     ApiResourcesToStructs(in.Resources),
-		Meta:          in.Meta,
-		ShutdownDelay: in.ShutdownDelay,
-		KillSignal:    in.KillSignal,
-		KillTimeout:   in.KillTimeout,
-		LogConfig:     apiLogConfigToStructs(in.LogConfig),
+		Name:               in.Name,
+		Driver:             in.Driver,
+		User:               in.User,
+		Config:             in.Config,
+		Env:                in.Env,
+		Resources:          ApiResourcesToStructs(in.Resources),
+		Meta:               in.Meta,
+		ShutdownDelay:      in.ShutdownDelay,
+		KillSignal:         in.KillSignal,
+		KillTimeout:        in.KillTimeout,
+		LogConfig:          apiLogConfigToStructs(in.LogConfig),
+		EnvoyVersion:       in.EnvoyVersion,
+		ScaleWithUpstreams: in.ScaleWithUpstreams,
 	}
 }
 
@@ -1706,6 +1882,18 @@ func apiConsulToStructs(in *api.Consul) *structs.Consul {
 	}
 }
 
+func apiMeshToStructs(in *api.Mesh) *structs.Mesh {
+	if in == nil {
+		return nil
+	}
+	return &structs.Mesh{
+		Type:      in.Type,
+		Driver:    in.Driver,
+		Resources: ApiResourcesToStructs(in.Resources),
+		Config:    helper.CopyMapStringInterface(in.Config),
+	}
+}
+
 func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 	if in == nil {
 		return nil
@@ -1770,6 +1958,22 @@ func ApiAffinityToStructs(a1 *api.Affinity) *structs.Affinity {
 	}
 }
 
+func ApiAntiAffinitiesToStructs(in []*api.AntiAffinity) []*structs.AntiAffinity {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]*structs.AntiAffinity, len(in))
+	for i, a := range in {
+		out[i] = &structs.AntiAffinity{
+			JobRegexp: a.JobRegexp,
+			Scope:     a.Scope,
+		}
+	}
+
+	return out
+}
+
 func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	ret := &structs.Spread{}
 	ret.Attribute = a1.Attribute
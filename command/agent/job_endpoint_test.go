@@ -1019,6 +1019,39 @@ func TestHTTP_JobDelete(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobDelete_EnforceIndex(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Deleting at a stale job modify index fails
+		url := fmt.Sprintf("/v1/job/%s?job_modify_index=%d", job.ID, resp.JobModifyIndex-1)
+		req, err := http.NewRequest("DELETE", url, nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Enforcing job modify index")
+
+		// Deleting at the current job modify index succeeds
+		url = fmt.Sprintf("/v1/job/%s?job_modify_index=%d", job.ID, resp.JobModifyIndex)
+		req, err = http.NewRequest("DELETE", url, nil)
+		require.NoError(t, err)
+		obj, err := s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.NotEmpty(t, obj.(structs.JobDeregisterResponse).EvalID)
+	})
+}
+
 func TestHTTP_JobDelete_EvalPriority(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1484,6 +1517,46 @@ func TestHTTP_JobDeployments(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobLineage(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job
+		j := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job: j,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/job/"+j.ID+"/lineage", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// The version of the job is the root of the evaluation it created
+		lineage := obj.([]*structs.JobLineageNode)
+		require.Len(t, lineage, 1)
+		require.Equal(t, structs.JobLineageNodeTypeJobVersion, lineage[0].Type)
+		require.Len(t, lineage[0].Children, 1)
+		require.Equal(t, resp.EvalID, lineage[0].Children[0].ID)
+
+		require.NotZero(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Only GET is allowed
+		req, err = http.NewRequest("PUT", "/v1/job/"+j.ID+"/lineage", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, ErrInvalidMethod)
+	})
+}
+
 func TestHTTP_JobDeployment(t *testing.T) {
 	ci.Parallel(t)
 	assert := assert.New(t)
@@ -1935,6 +2008,52 @@ func TestHTTP_JobStable(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobFreeze(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job
+		job := mock.Job()
+		regReq := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var regResp structs.JobRegisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Register", &regReq, &regResp))
+
+		args := structs.JobFreezeRequest{
+			JobID:  job.ID,
+			Frozen: true,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		buf := encodeReq(args)
+
+		// Make the HTTP request
+		req, err := http.NewRequest("PUT", "/v1/job/"+job.ID+"/freeze", buf)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check the response
+		freezeResp := obj.(structs.JobFreezeResponse)
+		require.NotZero(t, freezeResp.Index)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Check the job is frozen
+		out, err := s.Agent.Server().State().JobByID(nil, structs.DefaultNamespace, job.ID)
+		require.NoError(t, err)
+		require.True(t, out.Frozen)
+	})
+}
+
 func TestJobs_ParsingWriteRequest(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2419,6 +2538,10 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			Payload:      "payload",
 			MetaRequired: []string{"a", "b"},
 			MetaOptional: []string{"c", "d"},
+			Dispatch: &api.DispatchConfig{
+				PurgeAfter: helper.TimeToPtr(24 * time.Hour),
+				KeepLast:   helper.IntToPtr(100),
+			},
 		},
 		Payload: []byte("payload"),
 		Meta: map[string]string{
@@ -2562,6 +2685,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					},
 				},
 				MaxClientDisconnect: helper.TimeToPtr(30 * time.Second),
+				ShutdownPriority:    helper.IntToPtr(-10),
+				Priority:            helper.IntToPtr(30),
 				Tasks: []*api.Task{
 					{
 						Name:   "task1",
@@ -2813,6 +2938,10 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			Payload:      "payload",
 			MetaRequired: []string{"a", "b"},
 			MetaOptional: []string{"c", "d"},
+			Dispatch: &structs.DispatchConfig{
+				PurgeAfter: 24 * time.Hour,
+				KeepLast:   100,
+			},
 		},
 		Payload: []byte("payload"),
 		Meta: map[string]string{
@@ -2961,6 +3090,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					},
 				},
 				MaxClientDisconnect: helper.TimeToPtr(30 * time.Second),
+				ShutdownPriority:    -10,
+				Priority:            30,
 				Tasks: []*structs.Task{
 					{
 						Name:   "task1",
@@ -3145,6 +3276,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 									Min: helper.TimeToPtr(5 * time.Second),
 									Max: helper.TimeToPtr(10 * time.Second),
 								},
+								OnRenderError: "fail",
 							},
 						},
 						DispatchPayload: &structs.DispatchPayloadConfig{
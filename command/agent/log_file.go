@@ -138,7 +138,11 @@ func (l *logFile) Write(b []byte) (int, error) {
 	if !l.logFilter.Check(b) {
 		return 0, nil
 	}
+	return l.write(b)
+}
 
+// write writes the log entry to the file, rotating it if necessary.
+func (l *logFile) write(b []byte) (int, error) {
 	l.acquire.Lock()
 	defer l.acquire.Unlock()
 	//Create a new file if we have no file to write to
@@ -156,3 +160,14 @@ func (l *logFile) Write(b []byte) (int, error) {
 	l.BytesWritten += int64(n)
 	return n, err
 }
+
+// unfilteredLogFile writes log entries to the log file without filtering them
+// by level.
+type unfilteredLogFile struct {
+	*logFile
+}
+
+// Write is used to implement io.Writer
+func (l unfilteredLogFile) Write(b []byte) (int, error) {
+	return l.write(b)
+}
@@ -0,0 +1,239 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/logutils"
+)
+
+const (
+	// defaultLogLevelDuration is how long a log level override lasts when no
+	// duration is given.
+	defaultLogLevelDuration = 15 * time.Minute
+
+	// maxLogLevelDuration is the longest a log level override can last.
+	maxLogLevelDuration = 24 * time.Hour
+)
+
+// LogLevelOverride is a log level set at runtime for a subsystem of the agent.
+type LogLevelOverride struct {
+	// Name is the name of the logger the level applies to. It also applies to
+	// the loggers named after it, so "nomad" applies to
+	// "nomad.deployments_watcher".
+	Name string
+
+	// Level is the minimum level of the messages written for the logger.
+	Level string
+
+	// ExpiresAt is when the override is removed.
+	ExpiresAt time.Time
+}
+
+// logLevelOverride is a log level override along with the timer removing it.
+type logLevelOverride struct {
+	level     log.Level
+	expiresAt time.Time
+	timer     *time.Timer
+}
+
+// logLevelOverrides writes the messages of subsystems whose log level was
+// lowered at runtime, so a single subsystem can be debugged without setting
+// the whole agent to a more verbose level. The messages are received from a
+// sink registered on the agent logger while at least one override is set.
+type logLevelOverrides struct {
+	logger log.InterceptLogger
+
+	// formatter formats the messages like the agent logger and writes them
+	// to the agent log output.
+	formatter log.SinkAdapter
+
+	// emitted returns whether the agent logger already writes the messages
+	// of the given level, so they aren't written twice.
+	emitted func(log.Level) bool
+
+	// sinkLock serializes registering the sink with the agent logger.
+	sinkLock   sync.Mutex
+	registered bool
+
+	lock      sync.Mutex
+	overrides map[string]*logLevelOverride
+}
+
+func newLogLevelOverrides(logger log.InterceptLogger, output io.Writer, json bool, level log.Level) *logLevelOverrides {
+	o := &logLevelOverrides{
+		logger:    logger,
+		overrides: make(map[string]*logLevelOverride),
+	}
+	o.setOutput(output, json, func(l log.Level) bool { return l >= level })
+	return o
+}
+
+// setOutput sets the writer the messages are written to. It must be called
+// before any override is set.
+func (o *logLevelOverrides) setOutput(output io.Writer, json bool, emitted func(log.Level) bool) {
+	o.formatter = log.NewSinkAdapter(&log.LoggerOptions{
+		Level:      log.Trace,
+		Output:     output,
+		JSONFormat: json,
+	})
+	o.emitted = emitted
+}
+
+// Set overrides the log level of the named logger for the given duration.
+func (o *logLevelOverrides) Set(name, level string, d time.Duration) error {
+	if name == "" {
+		return fmt.Errorf("missing logger name")
+	}
+
+	l := log.LevelFromString(level)
+	if l == log.NoLevel || l == log.Off {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+
+	switch {
+	case d == 0:
+		d = defaultLogLevelDuration
+	case d < 0:
+		return fmt.Errorf("duration must be positive")
+	case d > maxLogLevelDuration:
+		return fmt.Errorf("duration must be at most %v", maxLogLevelDuration)
+	}
+
+	o.lock.Lock()
+	if old, ok := o.overrides[name]; ok {
+		old.timer.Stop()
+	}
+	override := &logLevelOverride{
+		level:     l,
+		expiresAt: time.Now().Add(d),
+	}
+	override.timer = time.AfterFunc(d, func() { o.expire(name, override) })
+	o.overrides[name] = override
+	o.lock.Unlock()
+
+	o.logger.Info("log level overridden", "logger", name, "level", l.String(), "duration", d)
+	o.updateSink()
+	return nil
+}
+
+// Reset removes the log level override of the named logger.
+func (o *logLevelOverrides) Reset(name string) {
+	o.lock.Lock()
+	if override, ok := o.overrides[name]; ok {
+		override.timer.Stop()
+		delete(o.overrides, name)
+	}
+	o.lock.Unlock()
+
+	o.updateSink()
+}
+
+// List returns the log level overrides sorted by logger name.
+func (o *logLevelOverrides) List() []*LogLevelOverride {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	out := make([]*LogLevelOverride, 0, len(o.overrides))
+	for name, override := range o.overrides {
+		out = append(out, &LogLevelOverride{
+			Name:      name,
+			Level:     override.level.String(),
+			ExpiresAt: override.expiresAt,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Shutdown removes all the log level overrides.
+func (o *logLevelOverrides) Shutdown() {
+	o.lock.Lock()
+	for name, override := range o.overrides {
+		override.timer.Stop()
+		delete(o.overrides, name)
+	}
+	o.lock.Unlock()
+
+	o.updateSink()
+}
+
+// expire removes the override once its duration has elapsed, unless it was
+// replaced since.
+func (o *logLevelOverrides) expire(name string, override *logLevelOverride) {
+	o.lock.Lock()
+	expired := o.overrides[name] == override
+	if expired {
+		delete(o.overrides, name)
+	}
+	o.lock.Unlock()
+
+	if expired {
+		o.logger.Info("log level override expired", "logger", name)
+		o.updateSink()
+	}
+}
+
+// updateSink registers the sink with the agent logger while there are
+// overrides, so logging isn't slowed down by the sink otherwise.
+func (o *logLevelOverrides) updateSink() {
+	o.sinkLock.Lock()
+	defer o.sinkLock.Unlock()
+
+	o.lock.Lock()
+	want := len(o.overrides) > 0
+	o.lock.Unlock()
+
+	if want == o.registered {
+		return
+	}
+	if want {
+		o.logger.RegisterSink(o)
+	} else {
+		o.logger.DeregisterSink(o)
+	}
+	o.registered = want
+}
+
+// Accept implements log.SinkAdapter. It writes the messages of overridden
+// loggers the agent logger did not write.
+func (o *logLevelOverrides) Accept(name string, level log.Level, msg string, args ...interface{}) {
+	if o.emitted(level) {
+		return
+	}
+
+	o.lock.Lock()
+	override := o.lookup(name)
+	o.lock.Unlock()
+
+	if override == nil || level < override.level {
+		return
+	}
+	o.formatter.Accept(name, level, msg, args...)
+}
+
+// lookup returns the override of the logger or of the closest logger it is
+// named after. The lock must be held.
+func (o *logLevelOverrides) lookup(name string) *logLevelOverride {
+	for {
+		if override, ok := o.overrides[name]; ok {
+			return override
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return nil
+		}
+		name = name[:i]
+	}
+}
+
+// filterEmitted returns whether the level filter of the agent log output
+// lets messages of the given level through.
+func filterEmitted(filter *logutils.LevelFilter, level log.Level) bool {
+	return filter.Check([]byte("[" + strings.ToUpper(level.String()) + "]"))
+}
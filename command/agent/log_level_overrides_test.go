@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLogLevelOverrides(t *testing.T) {
+	ci.Parallel(t)
+
+	var out syncBuffer
+	logger := log.NewInterceptLogger(&log.LoggerOptions{
+		Name:   "agent",
+		Level:  log.Info,
+		Output: &out,
+	})
+	overrides := newLogLevelOverrides(logger, &out, false, log.Info)
+	defer overrides.Shutdown()
+
+	server := logger.ResetNamedIntercept("nomad")
+	watcher := server.Named("deployments_watcher")
+	worker := server.ResetNamed("worker")
+
+	// Debug messages are dropped without an override
+	watcher.Debug("before override")
+	require.NotContains(t, out.String(), "before override")
+
+	// Override the deployment watcher and the loggers named after it
+	require.NoError(t, overrides.Set("nomad.deployments_watcher", "debug", time.Minute))
+	watcher.Debug("watching deployments")
+	watcher.Named("sub").Debug("sub logger")
+	watcher.Trace("too verbose")
+	worker.Debug("scheduling")
+	server.Debug("server debug")
+	watcher.Info("written once")
+
+	logs := out.String()
+	require.Contains(t, logs, "[DEBUG] nomad.deployments_watcher: watching deployments")
+	require.Contains(t, logs, "[DEBUG] nomad.deployments_watcher.sub: sub logger")
+	require.NotContains(t, logs, "too verbose")
+	require.NotContains(t, logs, "scheduling")
+	require.NotContains(t, logs, "server debug")
+	require.Equal(t, 1, strings.Count(logs, "written once"))
+
+	// Invalid overrides are rejected
+	require.Error(t, overrides.Set("", "debug", time.Minute))
+	require.Error(t, overrides.Set("nomad", "loud", time.Minute))
+	require.Error(t, overrides.Set("nomad", "debug", 48*time.Hour))
+
+	list := overrides.List()
+	require.Len(t, list, 1)
+	require.Equal(t, "debug", list[0].Level)
+
+	// Reset the override
+	overrides.Reset("nomad.deployments_watcher")
+	require.Empty(t, overrides.List())
+	watcher.Debug("after reset")
+	require.NotContains(t, out.String(), "after reset")
+}
+
+func TestLogLevelOverrides_Expire(t *testing.T) {
+	ci.Parallel(t)
+
+	var out syncBuffer
+	logger := log.NewInterceptLogger(&log.LoggerOptions{
+		Level:  log.Info,
+		Output: &out,
+	})
+	overrides := newLogLevelOverrides(logger, &out, false, log.Info)
+	defer overrides.Shutdown()
+
+	require.NoError(t, overrides.Set("nomad", "trace", 50*time.Millisecond))
+	require.Len(t, overrides.List(), 1)
+
+	require.Eventually(t, func() bool {
+		return len(overrides.List()) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	logger.Named("nomad").Trace("after expiry")
+	require.NotContains(t, out.String(), "after expiry")
+}
@@ -51,6 +51,29 @@ func (s *HTTPServer) NodesRequest(resp http.ResponseWriter, req *http.Request) (
 	return out.Nodes, nil
 }
 
+func (s *HTTPServer) NodeIntroTokenRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// the request body is optional, the server default TTL is used without
+	// it
+	var args structs.NodeIntroTokenCreateRequest
+	if req.ContentLength != 0 {
+		if err := decodeBody(req, &args); err != nil {
+			return nil, CodedError(400, err.Error())
+		}
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.NodeIntroTokenCreateResponse
+	if err := s.agent.RPC("Node.CreateIntroToken", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) NodeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/v1/node/")
 	switch {
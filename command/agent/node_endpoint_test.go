@@ -454,6 +454,35 @@ func TestHTTP_NodeEligible(t *testing.T) {
 	})
 }
 
+func TestHTTP_NodeIntroToken(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Only writes are allowed
+		req, err := http.NewRequest("GET", "/v1/node/intro-token", nil)
+		require.NoError(t, err)
+		_, err = s.Server.NodeIntroTokenRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), ErrInvalidMethod)
+
+		// The body is optional
+		req, err = http.NewRequest("PUT", "/v1/node/intro-token", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.NodeIntroTokenRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Header().Get("X-Nomad-Index"))
+
+		out := obj.(structs.NodeIntroTokenCreateResponse)
+		require.NotNil(t, out.Token)
+		require.WithinDuration(t, time.Now().Add(structs.DefaultNodeIntroTokenTTL), out.Token.ExpiresAt, time.Minute)
+
+		// The token can be looked up by its secret
+		token, err := s.Agent.server.State().NodeIntroTokenBySecret(nil, out.Token.SecretID)
+		require.NoError(t, err)
+		require.Equal(t, out.Token.AccessorID, token.AccessorID)
+	})
+}
+
 func TestHTTP_NodePurge(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -263,6 +263,18 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 		MemoryOversubscriptionEnabled: conf.MemoryOversubscriptionEnabled,
 		RejectJobRegistration:         conf.RejectJobRegistration,
 		PauseEvalBroker:               conf.PauseEvalBroker,
+		PauseScheduling:               conf.PauseScheduling,
+		PauseSchedulingDuration:       conf.PauseSchedulingDuration,
+		PauseSchedulingUntil:          conf.PauseSchedulingUntil,
+		ReservedHeadroom: structs.ReservedHeadroomConfig{
+			Percent:           conf.ReservedHeadroom.Percent,
+			NodeClassPercent:  conf.ReservedHeadroom.NodeClassPercent,
+			PriorityThreshold: conf.ReservedHeadroom.PriorityThreshold,
+		},
+		EvalFairness: structs.EvalFairnessConfig{
+			Enabled:         conf.EvalFairness.Enabled,
+			NamespaceShares: conf.EvalFairness.NamespaceShares,
+		},
 		PreemptionConfig: structs.PreemptionConfig{
 			SystemSchedulerEnabled:   conf.PreemptionConfig.SystemSchedulerEnabled,
 			SysBatchSchedulerEnabled: conf.PreemptionConfig.SysBatchSchedulerEnabled,
@@ -32,6 +32,43 @@ func (s *HTTPServer) SecureVariablesListRequest(resp http.ResponseWriter, req *h
 	return out.Data, nil
 }
 
+func (s *HTTPServer) SecureVariablesTxnRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	var ops []*structs.SecureVariableTxnOp
+	if err := decodeBody(req, &ops); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	args := structs.SecureVariablesTxnRequest{
+		Ops: ops,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.SecureVariablesTxnResponse
+	if err := s.agent.RPC(structs.SecureVariablesTxnRPCMethod, &args, &out); err != nil {
+		// The conflicts couldn't be returned, but the transaction still
+		// failed because of them.
+		if strings.HasPrefix(err.Error(), "cas error:") {
+			resp.WriteHeader(http.StatusConflict)
+		}
+		setIndex(resp, out.WriteMeta.Index)
+		return nil, err
+	}
+
+	// If any check index didn't match, none of the operations were applied.
+	// Write out a 409 Conflict response with the conflicting variables at
+	// the position of their operation.
+	setIndex(resp, out.WriteMeta.Index)
+	if out.Conflicts != nil {
+		resp.WriteHeader(http.StatusConflict)
+		return out.Conflicts, nil
+	}
+	return nil, nil
+}
+
 func (s *HTTPServer) SecureVariableSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/v1/var/")
 	if len(path) == 0 {
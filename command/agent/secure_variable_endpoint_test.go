@@ -455,6 +455,65 @@ func TestHTTP_SecureVariables(t *testing.T) {
 			require.NoError(t, err)
 			require.Nil(t, sv)
 		})
+		t.Run("txn", func(t *testing.T) {
+			sv1 := mock.SecureVariable()
+			require.NoError(t, rpcWriteSV(s, sv1))
+			sv2 := mock.SecureVariable()
+			stale := sv1.ModifyIndex - 1
+			current := sv1.ModifyIndex
+			zero := uint64(0)
+
+			// A stale check index fails the whole transaction
+			{
+				ops := []*structs.SecureVariableTxnOp{
+					{Op: structs.SecureVariableOpSet, Var: sv2, CheckIndex: &zero},
+					{Op: structs.SecureVariableOpDelete, Var: sv1, CheckIndex: &stale},
+				}
+				buf := encodeReq(ops)
+				req, err := http.NewRequest("PUT", "/v1/vars/txn", buf)
+				require.NoError(t, err)
+				respW := httptest.NewRecorder()
+
+				obj, err := s.Server.SecureVariablesTxnRequest(respW, req)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusConflict, respW.Result().StatusCode)
+
+				conflicts, ok := obj.([]*structs.SecureVariableDecrypted)
+				require.True(t, ok, "Expected []*structs.SecureVariableDecrypted, got %T", obj)
+				require.Len(t, conflicts, 2)
+				require.Nil(t, conflicts[0])
+				require.True(t, sv1.Equals(*conflicts[1]))
+
+				svChk, err := rpcReadSV(s, sv2.Namespace, sv2.Path)
+				require.NoError(t, err)
+				require.Nil(t, svChk)
+			}
+
+			// The current check index applies every operation
+			{
+				ops := []*structs.SecureVariableTxnOp{
+					{Op: structs.SecureVariableOpSet, Var: sv2, CheckIndex: &zero},
+					{Op: structs.SecureVariableOpDelete, Var: sv1, CheckIndex: &current},
+				}
+				buf := encodeReq(ops)
+				req, err := http.NewRequest("PUT", "/v1/vars/txn", buf)
+				require.NoError(t, err)
+				respW := httptest.NewRecorder()
+
+				obj, err := s.Server.SecureVariablesTxnRequest(respW, req)
+				require.NoError(t, err)
+				require.Nil(t, obj)
+				require.NotZero(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+				svChk, err := rpcReadSV(s, sv1.Namespace, sv1.Path)
+				require.NoError(t, err)
+				require.Nil(t, svChk)
+				svChk, err = rpcReadSV(s, sv2.Namespace, sv2.Path)
+				require.NoError(t, err)
+				require.NotNil(t, svChk)
+				require.Equal(t, sv2.Items, svChk.Items)
+			}
+		})
 	})
 }
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -77,6 +78,50 @@ func TestClientStatsRequest(t *testing.T) {
 	})
 }
 
+func TestClientInventoryRequest(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+
+		// Local node, local resp
+		{
+			req, err := http.NewRequest("GET", "/v1/client/inventory", nil)
+			require.NoError(t, err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.ClientInventoryRequest(respW, req)
+			require.NoError(t, err)
+			inventory := obj.(*cstructs.ClientInventory)
+			require.Equal(t, s.client.NodeID(), inventory.NodeID)
+			require.Empty(t, inventory.Tasks)
+		}
+
+		// no client, server resp
+		{
+			c := s.client
+			s.client = nil
+
+			testutil.WaitForResult(func() (bool, error) {
+				n, err := s.server.State().NodeByID(nil, c.NodeID())
+				if err != nil {
+					return false, err
+				}
+				return n != nil, nil
+			}, func(err error) {
+				t.Fatalf("should have client: %v", err)
+			})
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("/v1/client/inventory?node_id=%s", c.NodeID()), nil)
+			require.NoError(t, err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.ClientInventoryRequest(respW, req)
+			require.NoError(t, err)
+			require.Equal(t, c.NodeID(), obj.(*cstructs.ClientInventory).NodeID)
+			s.client = c
+		}
+	})
+}
+
 func TestClientStatsRequest_ACL(t *testing.T) {
 	ci.Parallel(t)
 	assert := assert.New(t)
@@ -20,7 +20,7 @@ var levelPriority = map[string]gsyslog.Priority{
 
 // SyslogWrapper is used to cleanup log messages before
 // writing them to a Syslogger. Implements the io.Writer
-// interface.
+// interface. Messages aren't filtered when filt is nil.
 type SyslogWrapper struct {
 	l    gsyslog.Syslogger
 	filt *logutils.LevelFilter
@@ -29,7 +29,7 @@ type SyslogWrapper struct {
 // Write is used to implement io.Writer
 func (s *SyslogWrapper) Write(p []byte) (int, error) {
 	// Skip syslog if the log level doesn't apply
-	if !s.filt.Check(p) {
+	if s.filt != nil && !s.filt.Check(p) {
 		return 0, nil
 	}
 
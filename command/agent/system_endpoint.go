@@ -24,18 +24,38 @@ func (s *HTTPServer) GarbageCollectRequest(resp http.ResponseWriter, req *http.R
 }
 
 func (s *HTTPServer) ReconcileJobSummaries(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return s.reconcileRequest(resp, req, "System.ReconcileJobSummaries")
+}
+
+func (s *HTTPServer) ReconcileDeployments(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return s.reconcileRequest(resp, req, "System.ReconcileDeployments")
+}
+
+func (s *HTTPServer) ReconcileServiceRegistrations(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return s.reconcileRequest(resp, req, "System.ReconcileServiceRegistrations")
+}
+
+// reconcileRequest makes a reconciliation request to the given System RPC,
+// only computing the changes if the dry_run query parameter is set.
+func (s *HTTPServer) reconcileRequest(resp http.ResponseWriter, req *http.Request, method string) (interface{}, error) {
 	if req.Method != "PUT" {
 		return nil, CodedError(405, ErrInvalidMethod)
 	}
 
-	var args structs.GenericRequest
-	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
-		return nil, nil
+	dryRun, err := parseBool(req, "dry_run")
+	if err != nil {
+		return nil, CodedError(400, err.Error())
 	}
 
-	var gResp structs.GenericResponse
-	if err := s.agent.RPC("System.ReconcileJobSummaries", &args, &gResp); err != nil {
+	args := structs.ReconcileRequest{
+		DryRun: dryRun != nil && *dryRun,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.ReconcileResponse
+	if err := s.agent.RPC(method, &args, &out); err != nil {
 		return nil, err
 	}
-	return nil, nil
+	setIndex(resp, out.Index)
+	return out, nil
 }
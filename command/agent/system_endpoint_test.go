@@ -6,6 +6,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_SystemGarbageCollect(t *testing.T) {
@@ -45,3 +48,52 @@ func TestHTTP_ReconcileJobSummaries(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTP_ReconcileDeployments(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Insert a deployment whose counts drift from its allocations
+		state := s.Agent.server.State()
+		d := mock.Deployment()
+		d.TaskGroups["web"].PlacedAllocs = 1
+		require.NoError(t, state.UpsertDeployment(1000, d))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("PUT", "/v1/system/reconcile/deployments?dry_run=true", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.ReconcileDeployments(respW, req)
+		require.NoError(t, err)
+		resp := obj.(structs.ReconcileResponse)
+		require.Len(t, resp.Changes, 1)
+		require.Equal(t, d.ID, resp.Changes[0].ID)
+
+		// The dry run doesn't update the deployment
+		out, err := state.DeploymentByID(nil, d.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, out.TaskGroups["web"].PlacedAllocs)
+	})
+}
+
+func TestHTTP_ReconcileServiceRegistrations(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Make the HTTP request
+		req, err := http.NewRequest("PUT", "/v1/system/reconcile/services?dry_run=maybe", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		// An invalid dry_run parameter is rejected
+		_, err = s.Server.ReconcileServiceRegistrations(respW, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "dry_run")
+
+		req, err = http.NewRequest("PUT", "/v1/system/reconcile/services", nil)
+		require.NoError(t, err)
+		obj, err := s.Server.ReconcileServiceRegistrations(respW, req)
+		require.NoError(t, err)
+		require.Empty(t, obj.(structs.ReconcileResponse).Changes)
+	})
+}
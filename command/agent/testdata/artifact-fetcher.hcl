@@ -0,0 +1,22 @@
+client {
+  artifact {
+    fetcher "store" {
+      command = "/usr/local/bin/store-fetch"
+      args    = ["-region", "east"]
+      user    = "nobody"
+      timeout = "5m"
+
+      env {
+        PATH = "/usr/bin:/bin"
+      }
+
+      config {
+        endpoint = "https://store.internal"
+      }
+    }
+
+    fetcher "oci" {
+      command = "/usr/local/bin/oci-fetch"
+    }
+  }
+}
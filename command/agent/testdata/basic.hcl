@@ -112,7 +112,10 @@ server {
   node_gc_threshold             = "12h"
   job_gc_interval               = "3m"
   job_gc_threshold              = "12h"
+  job_trash_window              = "48h"
   eval_gc_threshold             = "12h"
+  eval_gc_max_per_job           = 50
+  alloc_gc_max_per_job          = 100
   deployment_gc_threshold       = "12h"
   csi_volume_claim_gc_threshold = "12h"
   csi_plugin_gc_threshold       = "12h"
@@ -139,6 +142,16 @@ server {
     node_window    = "41m"
   }
 
+  snapshot_agent {
+    interval    = "2h"
+    retain      = 10
+    name_prefix = "backup"
+
+    local_storage {
+      path = "/tmp/snapshots"
+    }
+  }
+
   server_join {
     retry_join     = ["1.1.1.1", "2.2.2.2"]
     retry_max      = 3
@@ -155,7 +168,8 @@ server {
     }
   }
 
-  license_path = "/tmp/nomad.hclic"
+  license_path    = "/tmp/nomad.hclic"
+  rpc_compression = true
 }
 
 acl {
@@ -0,0 +1,12 @@
+client {
+  env_provider "broker" {
+    command   = "/usr/local/bin/credentials"
+    args      = ["-role", "web"]
+    timeout   = "5s"
+    cache_ttl = "10m"
+  }
+
+  env_provider "site" {
+    command = "/usr/local/bin/site-env"
+  }
+}
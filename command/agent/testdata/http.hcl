@@ -0,0 +1,31 @@
+http {
+  content_security_policy = "default-src 'self'"
+
+  cors {
+    allowed_origins   = ["https://*.example.com"]
+    allowed_headers   = ["Content-Type", "X-Nomad-Token", "X-Request-Id"]
+    allow_credentials = true
+    max_age           = 600
+  }
+
+  listener "127.0.0.1" {
+    content_security_policy = "default-src 'self'; frame-ancestors https://tools.example.com"
+
+    cors {
+      allowed_origins = ["https://tools.example.com"]
+      allowed_methods = ["GET"]
+    }
+  }
+
+  listener "127.0.0.1:9646" {
+    endpoints = ["read", "write"]
+
+    tls {
+      enabled             = true
+      ca_file             = "/etc/nomad/admin-ca.pem"
+      cert_file           = "/etc/nomad/admin.pem"
+      key_file            = "/etc/nomad/admin-key.pem"
+      verify_https_client = true
+    }
+  }
+}
@@ -0,0 +1,7 @@
+client {
+  mesh_hook "istio" {
+    command = "/usr/local/bin/nomad-istio"
+    args    = ["-revision", "stable"]
+    timeout = "1m"
+  }
+}
@@ -91,8 +91,8 @@ func (c *AgentInfoCommand) Run(args []string) int {
 	}
 
 	// If output format is specified, format and output the agent info
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, info)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, info)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error formatting output: %s", err))
 			return 1
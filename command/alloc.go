@@ -17,6 +17,10 @@ Usage: nomad alloc <subcommand> [options] [args]
   This command groups subcommands for interacting with allocations. Users can
   inspect the status, examine the filesystem or logs of an allocation.
 
+  List the allocations stopped because their node was drained:
+
+      $ nomad alloc list -filter 'StopReason == "drain"'
+
   Examine an allocations status:
 
       $ nomad alloc status <alloc-id>
@@ -220,7 +220,7 @@ func (l *AllocExecCommand) Run(args []string) int {
 		l.Stderr = os.Stderr
 	}
 
-	code, err := l.execImpl(client, alloc, task, ttyOpt, args[1:], escapeChar, l.Stdin, l.Stdout, l.Stderr)
+	code, err := l.execImpl(client, alloc, task, ttyOpt, false, args[1:], escapeChar, l.Stdin, l.Stdout, l.Stderr)
 	if err != nil {
 		l.Ui.Error(fmt.Sprintf("failed to exec into task: %v", err))
 		return 1
@@ -229,8 +229,9 @@ func (l *AllocExecCommand) Run(args []string) int {
 	return code
 }
 
-// execImpl invokes the Alloc Exec api call, it also prepares and restores terminal states as necessary.
-func (l *AllocExecCommand) execImpl(client *api.Client, alloc *api.Allocation, task string, tty bool,
+// execImpl invokes the Alloc Exec api call, or the Alloc Shell api call if
+// debugShell is set, it also prepares and restores terminal states as necessary.
+func (l *AllocExecCommand) execImpl(client *api.Client, alloc *api.Allocation, task string, tty, debugShell bool,
 	command []string, escapeChar string, stdin io.Reader, stdout, stderr io.WriteCloser) (int, error) {
 
 	sizeCh := make(chan api.TerminalSize, 1)
@@ -289,6 +290,10 @@ func (l *AllocExecCommand) execImpl(client *api.Client, alloc *api.Allocation, t
 		}
 	}()
 
+	if debugShell {
+		return client.Allocations().Shell(ctx,
+			alloc, task, tty, command, stdin, stdout, stderr, sizeCh, nil)
+	}
 	return client.Allocations().Exec(ctx,
 		alloc, task, tty, command, stdin, stdout, stderr, sizeCh, nil)
 }
@@ -0,0 +1,185 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type AllocListCommand struct {
+	Meta
+}
+
+func (c *AllocListCommand) Help() string {
+	helpText := `
+Usage: nomad alloc list [options]
+
+  List is used to list the allocations of the cluster. Use the -filter flag
+  to select allocations, for example the allocations stopped because their
+  node was drained:
+
+      $ nomad alloc list -filter 'StopReason == "drain"'
+
+  When ACLs are enabled, this command requires a token with the 'read-job'
+  capability for the namespaces of the allocations.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Alloc List Options:
+
+  -verbose
+    Show full information.
+
+  -per-page
+    How many results to show per page.
+
+  -page-token
+    Where to start pagination.
+
+  -filter
+    Specifies an expression used to filter query results.
+
+  -json
+    Output the allocations in their JSON format.
+
+  -t
+    Format and display allocations using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocListCommand) Synopsis() string {
+	return "List allocations"
+}
+
+func (c *AllocListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":       complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
+			"-filter":     complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
+		})
+}
+
+func (c *AllocListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *AllocListCommand) Name() string { return "alloc list" }
+
+func (c *AllocListCommand) Run(args []string) int {
+	var verbose, json bool
+	var perPage int
+	var tmpl, pageToken, filter string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.IntVar(&perPage, "per-page", 0, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
+	flags.StringVar(&filter, "filter", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	opts := &api.QueryOptions{
+		Filter:    filter,
+		PerPage:   int32(perPage),
+		NextToken: pageToken,
+	}
+
+	allocs, qm, err := client.Allocations().List(opts)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocations: %v", err))
+		return 1
+	}
+
+	// If output format is specified, format and output the allocations data
+	// list
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, allocs)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(allocs) == 0 {
+		c.Ui.Output("No allocations found")
+		return 0
+	}
+
+	c.Ui.Output(formatAllocListWithStopReason(allocs, verbose))
+
+	if qm.NextToken != "" {
+		c.Ui.Output(fmt.Sprintf(`
+Results have been paginated. To get the next page run:
+
+%s -page-token %s`, argsWithoutPageToken(os.Args), qm.NextToken))
+	}
+
+	return 0
+}
+
+// formatAllocListWithStopReason formats the allocations of any job along
+// with the reason they were stopped.
+func formatAllocListWithStopReason(stubs []*api.AllocationListStub, verbose bool) string {
+	// Truncate IDs unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	now := time.Now()
+	out := make([]string, len(stubs)+1)
+	out[0] = "ID|Node ID|Namespace|Job ID|Task Group|Desired|Status|Stop Reason|Modified"
+	for i, alloc := range stubs {
+		modified := prettyTimeDiff(time.Unix(0, alloc.ModifyTime), now)
+		if verbose {
+			modified = formatUnixNanoTime(alloc.ModifyTime)
+		}
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s",
+			limit(alloc.ID, length),
+			limit(alloc.NodeID, length),
+			alloc.Namespace,
+			alloc.JobID,
+			alloc.TaskGroup,
+			alloc.DesiredStatus,
+			alloc.ClientStatus,
+			alloc.StopReason,
+			modified,
+		)
+	}
+
+	return formatList(out)
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocListCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &AllocListCommand{}
+}
+
+func TestAllocListCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &AllocListCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(t, 1, cmd.Run([]string{"some", "bad", "args"}))
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	require.Equal(t, 1, cmd.Run([]string{"-address=nope"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error querying allocations")
+}
+
+func TestAllocListCommand_StopReasonFilter(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+	running := mock.Alloc()
+	drained := mock.Alloc()
+	drained.DesiredStatus = structs.AllocDesiredStatusStop
+	drained.StopReason = structs.AllocStopReasonDrain
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Allocation{running, drained}))
+
+	ui := cli.NewMockUi()
+	cmd := &AllocListCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "-verbose"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "Stop Reason")
+	require.Contains(t, out, running.ID)
+	require.Contains(t, out, drained.ID)
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-verbose", `-filter=StopReason == "drain"`})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	out = ui.OutputWriter.String()
+	require.NotContains(t, out, running.ID)
+	require.Contains(t, out, drained.ID)
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, `-filter=StopReason == "preempted"`})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "No allocations found")
+}
@@ -3,10 +3,14 @@ package command
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/posener/complete"
+	"github.com/ryanuber/go-glob"
 )
 
 type AllocRestartCommand struct {
@@ -16,11 +20,16 @@ type AllocRestartCommand struct {
 func (c *AllocRestartCommand) Help() string {
 	helpText := `
 Usage: nomad alloc restart [options] <allocation> <task>
+       nomad alloc restart [options] -all-allocs-of-job <job> <task>
 
   Restart an existing allocation. This command is used to restart a specific alloc
   and its tasks. If no task is provided then all of the allocation's tasks will
   be restarted.
 
+  The task name may contain '*' wildcards, in which case every task of the
+  allocation matching the pattern is restarted, including tasks started by
+  lifecycle hooks. Matched tasks that are not running are skipped.
+
   When ACLs are enabled, this command requires a token with the
   'alloc-lifecycle', 'read-job', and 'list-jobs' capabilities for the
   allocation's namespace.
@@ -34,6 +43,23 @@ Restart Specific Options:
   -task <task-name>
     Specify the individual task to restart. If task name is given with both an 
     argument and the '-task' option, preference is given to the '-task' option.
+    The task name may contain '*' wildcards.
+
+  -signal <signal>
+    Send the given signal to the tasks before restarting them, for example
+    to have them flush state to disk.
+
+  -grace <duration>
+    Time to wait between sending the signal given with '-signal' and
+    restarting the tasks. Defaults to 0.
+
+  -all-allocs-of-job
+    Treat the first argument as a job ID and restart all running allocations
+    of the job.
+
+  -max-parallel <n>
+    Maximum number of allocations restarted at the same time when
+    '-all-allocs-of-job' is used. Defaults to 1.
 
   -verbose
     Show full information.
@@ -44,13 +70,19 @@ Restart Specific Options:
 func (c *AllocRestartCommand) Name() string { return "alloc restart" }
 
 func (c *AllocRestartCommand) Run(args []string) int {
-	var verbose bool
-	var task string
+	var verbose, allAllocs bool
+	var task, signal string
+	var grace time.Duration
+	var maxParallel int
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.StringVar(&task, "task", "", "")
+	flags.StringVar(&signal, "signal", "", "")
+	flags.DurationVar(&grace, "grace", 0, "")
+	flags.BoolVar(&allAllocs, "all-allocs-of-job", false, "")
+	flags.IntVar(&maxParallel, "max-parallel", 1, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -64,7 +96,26 @@ func (c *AllocRestartCommand) Run(args []string) int {
 		return 1
 	}
 
-	allocID := args[0]
+	if grace != 0 && signal == "" {
+		c.Ui.Error("The -grace option requires -signal")
+		return 1
+	}
+	if maxParallel < 1 {
+		c.Ui.Error("The -max-parallel option must be at least 1")
+		return 1
+	}
+
+	// If -task isn't provided fallback to reading the task name
+	// from args.
+	if task == "" && len(args) >= 2 {
+		task = args[1]
+	}
+
+	opts := &allocRestartOpts{
+		task:   task,
+		signal: strings.ToUpper(signal),
+		grace:  grace,
+	}
 
 	// Truncate the id unless full length is requested
 	length := shortId
@@ -72,6 +123,19 @@ func (c *AllocRestartCommand) Run(args []string) int {
 		length = fullId
 	}
 
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if allAllocs {
+		return c.restartJobAllocs(client, args[0], opts, maxParallel, length)
+	}
+
+	allocID := args[0]
+
 	// Query the allocation info
 	if len(allocID) == 1 {
 		c.Ui.Error("Alloc ID must contain at least two characters.")
@@ -80,13 +144,6 @@ func (c *AllocRestartCommand) Run(args []string) int {
 
 	allocID = sanitizeUUIDPrefix(allocID)
 
-	// Get the HTTP client
-	client, err := c.Meta.Client()
-	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
-		return 1
-	}
-
 	allocs, _, err := client.Allocations().PrefixList(allocID)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
@@ -113,29 +170,176 @@ func (c *AllocRestartCommand) Run(args []string) int {
 		return 1
 	}
 
-	// If -task isn't provided fallback to reading the task name
-	// from args.
-	if task == "" && len(args) >= 2 {
-		task = args[1]
+	skipped, err := restartAlloc(client, alloc, opts)
+	for _, name := range skipped {
+		c.Ui.Warn(fmt.Sprintf("Skipping task %q: task is not running", name))
 	}
-
-	if task != "" {
-		err := validateTaskExistsInAllocation(task, alloc)
-		if err != nil {
-			c.Ui.Error(err.Error())
-			return 1
-		}
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
 	}
 
-	err = client.Allocations().Restart(alloc, task, nil)
+	return 0
+}
+
+// restartJobAllocs restarts all running allocations of a job, restarting at
+// most maxParallel allocations at the same time.
+func (c *AllocRestartCommand) restartJobAllocs(client *api.Client, jobID string,
+	opts *allocRestartOpts, maxParallel, length int) int {
+
+	stubs, _, err := client.Jobs().Allocations(jobID, false, nil)
 	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Failed to restart allocation:\n\n%s", err.Error()))
+		c.Ui.Error(fmt.Sprintf("Error querying job allocations: %s", err))
+		return 1
+	}
+
+	var running []*api.AllocationListStub
+	for _, stub := range stubs {
+		if stub.ClientStatus == api.AllocClientStatusRunning {
+			running = append(running, stub)
+		}
+	}
+	if len(running) == 0 {
+		c.Ui.Error(fmt.Sprintf("No running allocations found for job %q", jobID))
 		return 1
 	}
 
+	type result struct {
+		allocID string
+		skipped []string
+		err     error
+	}
+
+	sem := make(chan struct{}, maxParallel)
+	results := make(chan result, len(running))
+	var wg sync.WaitGroup
+	for _, stub := range running {
+		wg.Add(1)
+		go func(stub *api.AllocationListStub) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res := result{allocID: stub.ID}
+			q := &api.QueryOptions{Namespace: stub.Namespace}
+			alloc, _, err := client.Allocations().Info(stub.ID, q)
+			if err != nil {
+				res.err = fmt.Errorf("Error querying allocation: %s", err)
+			} else {
+				res.skipped, res.err = restartAlloc(client, alloc, opts)
+			}
+			results <- res
+		}(stub)
+	}
+	wg.Wait()
+	close(results)
+
+	failed := 0
+	for res := range results {
+		id := limit(res.allocID, length)
+		for _, name := range res.skipped {
+			c.Ui.Warn(fmt.Sprintf("Allocation %q: skipping task %q: task is not running", id, name))
+		}
+		if res.err != nil {
+			failed++
+			c.Ui.Error(fmt.Sprintf("Allocation %q: %s", id, res.err))
+			continue
+		}
+		c.Ui.Output(fmt.Sprintf("Restarted allocation %q", id))
+	}
+
+	if failed > 0 {
+		c.Ui.Error(fmt.Sprintf("Failed to restart %d of %d allocations", failed, len(running)))
+		return 1
+	}
 	return 0
 }
 
+// allocRestartOpts are the options used to restart the tasks of an
+// allocation.
+type allocRestartOpts struct {
+	// task is the name or glob pattern of the tasks to restart. If empty, all
+	// tasks of the allocation are restarted.
+	task string
+
+	// signal, if set, is sent to the tasks before they are restarted, and
+	// grace is the time waited between the two.
+	signal string
+	grace  time.Duration
+}
+
+// restartAlloc restarts the tasks of the allocation selected by opts. It
+// returns the names of the matched tasks that were skipped because they are
+// not running.
+func restartAlloc(client *api.Client, alloc *api.Allocation, opts *allocRestartOpts) ([]string, error) {
+	// An empty task name targets all tasks of the allocation
+	tasks := []string{""}
+	var skipped []string
+	if opts.task != "" {
+		matched, err := matchTasksInAllocation(opts.task, alloc)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = tasks[:0]
+		for _, name := range matched {
+			if state, ok := alloc.TaskStates[name]; ok && state.State != structs.TaskStateRunning {
+				skipped = append(skipped, name)
+				continue
+			}
+			tasks = append(tasks, name)
+		}
+		if len(tasks) == 0 {
+			return skipped, fmt.Errorf("No running tasks match %q", opts.task)
+		}
+	}
+
+	if opts.signal != "" {
+		for _, name := range tasks {
+			if err := client.Allocations().Signal(alloc, nil, name, opts.signal); err != nil {
+				return skipped, fmt.Errorf("Failed to signal allocation:\n\n%s", err.Error())
+			}
+		}
+		time.Sleep(opts.grace)
+	}
+
+	for _, name := range tasks {
+		if err := client.Allocations().Restart(alloc, name, nil); err != nil {
+			return skipped, fmt.Errorf("Failed to restart allocation:\n\n%s", err.Error())
+		}
+	}
+	return skipped, nil
+}
+
+// matchTasksInAllocation returns the names of the tasks of the allocation
+// matching the given pattern, which may contain '*' wildcards.
+func matchTasksInAllocation(pattern string, alloc *api.Allocation) ([]string, error) {
+	if !strings.Contains(pattern, "*") {
+		if err := validateTaskExistsInAllocation(pattern, alloc); err != nil {
+			return nil, err
+		}
+		return []string{pattern}, nil
+	}
+
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil, fmt.Errorf("Could not find allocation task group: %s", alloc.TaskGroup)
+	}
+
+	var matched []string
+	foundTaskNames := make([]string, len(tg.Tasks))
+	for i, task := range tg.Tasks {
+		foundTaskNames[i] = task.Name
+		if glob.Glob(pattern, task.Name) {
+			matched = append(matched, task.Name)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("Could not find tasks matching: %s, found:\n%s", pattern, formatList(foundTaskNames))
+	}
+	return matched, nil
+}
+
 func validateTaskExistsInAllocation(taskName string, alloc *api.Allocation) error {
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
 	if tg == nil {
@@ -157,6 +361,18 @@ func (c *AllocRestartCommand) Synopsis() string {
 	return "Restart a running allocation"
 }
 
+func (c *AllocRestartCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-task":              complete.PredictAnything,
+			"-signal":            complete.PredictAnything,
+			"-grace":             complete.PredictAnything,
+			"-all-allocs-of-job": complete.PredictNothing,
+			"-max-parallel":      complete.PredictAnything,
+			"-verbose":           complete.PredictNothing,
+		})
+}
+
 func (c *AllocRestartCommand) AutocompleteArgs() complete.Predictor {
 	// Here we attempt to autocomplete allocations for any position of arg.
 	// We should eventually try to auto complete the task name if the arg is
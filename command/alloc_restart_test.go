@@ -34,6 +34,11 @@ func TestAllocRestartCommand_Fails(t *testing.T) {
 	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd), "Expected help output")
 	ui.ErrorWriter.Reset()
 
+	// Fails on grace without signal
+	require.Equal(cmd.Run([]string{"-address=" + url, "-grace=1s", "foobar"}), 1)
+	require.Contains(ui.ErrorWriter.String(), "The -grace option requires -signal")
+	ui.ErrorWriter.Reset()
+
 	// Fails on connection failure
 	require.Equal(cmd.Run([]string{"-address=nope", "foobar"}), 1, "expected failure")
 	require.Contains(ui.ErrorWriter.String(), "Error querying allocation")
@@ -91,6 +96,16 @@ func TestAllocRestartCommand_Fails(t *testing.T) {
 	require.Equal(cmd.Run([]string{"-address=" + url, allocId1, "fooooobarrr"}), 1)
 	require.Contains(ui.ErrorWriter.String(), "Could not find task named")
 	ui.ErrorWriter.Reset()
+
+	// Fails on task pattern without matches
+	require.Equal(cmd.Run([]string{"-address=" + url, "-task=foo*", allocId1}), 1)
+	require.Contains(ui.ErrorWriter.String(), "Could not find tasks matching")
+	ui.ErrorWriter.Reset()
+
+	// Fails on missing job allocations
+	require.Equal(cmd.Run([]string{"-address=" + url, "-all-allocs-of-job", "nope"}), 1)
+	require.Contains(ui.ErrorWriter.String(), "No running allocations found")
+	ui.ErrorWriter.Reset()
 }
 
 func TestAllocRestartCommand_Run(t *testing.T) {
@@ -154,6 +169,15 @@ func TestAllocRestartCommand_Run(t *testing.T) {
 	require.Equal(cmd.Run([]string{"-address=" + url, allocId1}), 0, "expected successful exit code")
 
 	ui.OutputWriter.Reset()
+
+	// Restart the tasks matching a pattern after signaling them
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-task=task*", "-signal=SIGHUP", "-grace=10ms", allocId1}),
+		ui.ErrorWriter.String())
+
+	// Restart all allocations of the job
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-all-allocs-of-job", "-max-parallel=2", jobID}),
+		ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Restarted allocation")
 }
 
 func TestAllocRestartCommand_AutocompleteArgs(t *testing.T) {
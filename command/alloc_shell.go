@@ -0,0 +1,228 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocShellCommand struct {
+	Meta
+
+	Stdin  io.Reader
+	Stdout io.WriteCloser
+	Stderr io.WriteCloser
+}
+
+func (l *AllocShellCommand) Help() string {
+	helpText := `
+Usage: nomad alloc shell [options] <allocation> [<args>...]
+
+  Start a debug shell inside the environment of the given allocation and task.
+
+  Unlike 'nomad alloc exec', the shell binary is provided by the Nomad client
+  instead of the task, so tasks whose image has no shell can be debugged. The
+  client copies its debug shell binary into the task's local directory for the
+  duration of the session and runs it with the task driver. Additional
+  arguments are passed to the debug shell binary, and default to "sh". The
+  client must be configured with a debug shell.
+
+  Each session is recorded in the client logs and as an event of the task.
+
+  When ACLs are enabled, this command requires a token with the
+  'alloc-debug-shell', 'read-job', and 'list-jobs' capabilities for the
+  allocation's namespace. If the task driver does not have file system
+  isolation (as with 'raw_exec'), this command also requires the
+  'alloc-node-exec' capability for the allocation's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Shell Specific Options:
+
+  -task <task-name>
+    Sets the task to start the shell in
+
+  -job
+    Use a random allocation from the specified job ID.
+
+  -i
+    Pass stdin to the shell, defaults to true.  Pass -i=false to disable.
+
+  -t
+    Allocate a pseudo-tty, defaults to true if stdin is detected to be a tty session.
+    Pass -t=false to disable explicitly.
+
+  -e <escape_char>
+    Sets the escape character for sessions with a pty (default: '~').  The escape
+    character is only recognized at the beginning of a line.  The escape character
+    followed by a dot ('.') closes the connection.  Setting the character to
+    'none' disables any escapes and makes the session fully transparent.
+  `
+	return strings.TrimSpace(helpText)
+}
+
+func (l *AllocShellCommand) Synopsis() string {
+	return "Start a debug shell in task"
+}
+
+func (l *AllocShellCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(l.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"--task": complete.PredictAnything,
+			"-job":   complete.PredictAnything,
+			"-i":     complete.PredictNothing,
+			"-t":     complete.PredictNothing,
+			"-e":     complete.PredictSet("none", "~"),
+		})
+}
+
+func (l *AllocShellCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := l.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Allocs]
+	})
+}
+
+func (l *AllocShellCommand) Name() string { return "alloc shell" }
+
+func (l *AllocShellCommand) Run(args []string) int {
+	var job, stdinOpt, ttyOpt bool
+	var task, escapeChar string
+
+	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
+	flags.Usage = func() { l.Ui.Output(l.Help()) }
+	flags.BoolVar(&job, "job", false, "")
+	flags.BoolVar(&stdinOpt, "i", true, "")
+	flags.BoolVar(&ttyOpt, "t", isTty(), "")
+	flags.StringVar(&escapeChar, "e", "~", "")
+	flags.StringVar(&task, "task", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+
+	if len(args) < 1 {
+		if job {
+			l.Ui.Error("A job ID is required")
+		} else {
+			l.Ui.Error("An allocation ID is required")
+		}
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	}
+
+	if !job && len(args[0]) == 1 {
+		l.Ui.Error("Alloc ID must contain at least two characters")
+		return 1
+	}
+
+	if ttyOpt && !stdinOpt {
+		l.Ui.Error("-i must be enabled if running with tty")
+		return 1
+	}
+
+	if escapeChar == "none" {
+		escapeChar = ""
+	}
+
+	if len(escapeChar) > 1 {
+		l.Ui.Error("-e requires 'none' or a single character")
+		return 1
+	}
+
+	client, err := l.Meta.Client()
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	var allocStub *api.AllocationListStub
+	if job {
+		jobID := args[0]
+		allocStub, err = getRandomJobAlloc(client, jobID)
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Error fetching allocations: %v", err))
+			return 1
+		}
+	} else {
+		allocID := args[0]
+		allocs, _, err := client.Allocations().PrefixList(sanitizeUUIDPrefix(allocID))
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+			return 1
+		}
+
+		if len(allocs) == 0 {
+			l.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+			return 1
+		}
+
+		if len(allocs) > 1 {
+			out := formatAllocListStubs(allocs, false, shortId)
+			l.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+			return 1
+		}
+
+		allocStub = allocs[0]
+	}
+
+	q := &api.QueryOptions{Namespace: allocStub.Namespace}
+	alloc, _, err := client.Allocations().Info(allocStub.ID, q)
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	if task != "" {
+		err = validateTaskExistsInAllocation(task, alloc)
+	} else {
+		task, err = lookupAllocTask(alloc)
+	}
+	if err != nil {
+		l.Ui.Error(err.Error())
+		return 1
+	}
+
+	if !stdinOpt {
+		l.Stdin = bytes.NewReader(nil)
+	}
+
+	if l.Stdin == nil {
+		l.Stdin = os.Stdin
+	}
+
+	if l.Stdout == nil {
+		l.Stdout = os.Stdout
+	}
+
+	if l.Stderr == nil {
+		l.Stderr = os.Stderr
+	}
+
+	exec := &AllocExecCommand{Meta: l.Meta}
+	code, err := exec.execImpl(client, alloc, task, ttyOpt, true, args[1:], escapeChar, l.Stdin, l.Stdout, l.Stderr)
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("failed to start debug shell in task: %v", err))
+		return 1
+	}
+
+	return code
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+// static check
+var _ cli.Command = &AllocShellCommand{}
+
+func TestAllocShellCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	cases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			"alloc id missing",
+			[]string{},
+			`An allocation ID is required`,
+		},
+		{
+			"alloc id too short",
+			[]string{"-address=" + url, "2"},
+			`Alloc ID must contain at least two characters`,
+		},
+		{
+			"alloc not found",
+			[]string{"-address=" + url, "26470238-5CF2-438F-8772-DC67CFB0705C"},
+			`No allocation(s) with prefix or id "26470238-5CF2-438F-8772-DC67CFB0705C"`,
+		},
+		{
+			"job id missing",
+			[]string{"-job"},
+			`A job ID is required`,
+		},
+		{
+			"invalid escape char",
+			[]string{"-address=" + url, "-e", "es", "26470238-5CF2-438F-8772-DC67CFB0705C"},
+			"-e requires 'none' or a single character",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &AllocShellCommand{Meta: Meta{Ui: ui}}
+
+			code := cmd.Run(c.args)
+			require.Equal(t, 1, code)
+
+			require.Contains(t, ui.ErrorWriter.String(), c.expectedError)
+
+			ui.ErrorWriter.Reset()
+			ui.OutputWriter.Reset()
+		})
+	}
+}
+
+func TestAllocShellCommand_Help(t *testing.T) {
+	ci.Parallel(t)
+	cmd := &AllocShellCommand{}
+	require.True(t, strings.Contains(cmd.Help(), "alloc-debug-shell"))
+}
@@ -46,6 +46,10 @@ Alloc Status Options:
   -stats
     Display detailed resource usage statistics.
 
+  -templates
+    Display the rendering state of the templates of the tasks and the Consul,
+    Vault and Nomad dependencies they watch.
+
   -verbose
     Show full information.
 
@@ -66,10 +70,11 @@ func (c *AllocStatusCommand) Synopsis() string {
 func (c *AllocStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-short":   complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-t":       complete.PredictAnything,
+			"-short":     complete.PredictNothing,
+			"-verbose":   complete.PredictNothing,
+			"-templates": complete.PredictNothing,
+			"-json":      complete.PredictNothing,
+			"-t":         complete.PredictAnything,
 		})
 }
 
@@ -91,7 +96,7 @@ func (c *AllocStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *AllocStatusCommand) Name() string { return "alloc status" }
 
 func (c *AllocStatusCommand) Run(args []string) int {
-	var short, displayStats, verbose, json bool
+	var short, displayStats, displayTemplates, verbose, json bool
 	var tmpl string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
@@ -99,6 +104,7 @@ func (c *AllocStatusCommand) Run(args []string) int {
 	flags.BoolVar(&short, "short", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&displayStats, "stats", false, "")
+	flags.BoolVar(&displayTemplates, "templates", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 
@@ -117,14 +123,14 @@ func (c *AllocStatusCommand) Run(args []string) int {
 	}
 
 	// If args not specified but output format is specified, format and output the allocations data list
-	if len(args) == 0 && (json || len(tmpl) > 0) {
+	if len(args) == 0 && c.formatRequested(json, tmpl) {
 		allocs, _, err := client.Allocations().List(nil)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying allocations: %v", err))
 			return 1
 		}
 
-		out, err := Format(json, tmpl, allocs)
+		out, err := c.formatData(json, tmpl, allocs)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -179,8 +185,8 @@ func (c *AllocStatusCommand) Run(args []string) int {
 	}
 
 	// If output format is specified, format and output the data
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, alloc)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, alloc)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -224,6 +230,20 @@ func (c *AllocStatusCommand) Run(args []string) int {
 		c.outputTaskDetails(alloc, stats, displayStats, verbose)
 	}
 
+	if displayTemplates {
+		templates, err := client.Allocations().Templates(alloc, "", nil)
+		if err != nil {
+			c.Ui.Output("")
+			if err != api.NodeDownErr {
+				c.Ui.Error(fmt.Sprintf("Couldn't retrieve templates: %v", err))
+			} else {
+				c.Ui.Output("Omitting templates since the node is down.")
+			}
+		} else {
+			c.outputTemplates(templates)
+		}
+	}
+
 	// Format the detailed status
 	if verbose {
 		c.Ui.Output(c.Colorize().Color("\n[bold]Placement Metrics[reset]"))
@@ -266,17 +286,26 @@ func formatAllocBasicInfo(alloc *api.Allocation, client *api.Client, uuidLength
 		fmt.Sprintf("Node Name|%s", alloc.NodeName),
 		fmt.Sprintf("Job ID|%s", alloc.JobID),
 		fmt.Sprintf("Job Version|%d", *alloc.Job.Version),
+		fmt.Sprintf("Kill Priority|%d", alloc.KillPriority()),
 		fmt.Sprintf("Client Status|%s", alloc.ClientStatus),
 		fmt.Sprintf("Client Description|%s", alloc.ClientDescription),
 		fmt.Sprintf("Desired Status|%s", alloc.DesiredStatus),
 		fmt.Sprintf("Desired Description|%s", alloc.DesiredDescription),
+	}
+
+	if alloc.StopReason != "" {
+		basic = append(basic, fmt.Sprintf("Stop Reason|%s", alloc.StopReason))
+	}
+
+	basic = append(basic,
 		fmt.Sprintf("Created|%s", formattedCreateTime),
 		fmt.Sprintf("Modified|%s", formattedModifyTime),
-	}
+	)
 
 	if alloc.DeploymentID != "" {
 		health := "unset"
 		canary := false
+		var failedChecks []string
 		if alloc.DeploymentStatus != nil {
 			if alloc.DeploymentStatus.Healthy != nil {
 				if *alloc.DeploymentStatus.Healthy {
@@ -287,6 +316,7 @@ func formatAllocBasicInfo(alloc *api.Allocation, client *api.Client, uuidLength
 			}
 
 			canary = alloc.DeploymentStatus.Canary
+			failedChecks = alloc.DeploymentStatus.FailedChecks
 		}
 
 		basic = append(basic,
@@ -295,6 +325,9 @@ func formatAllocBasicInfo(alloc *api.Allocation, client *api.Client, uuidLength
 		if canary {
 			basic = append(basic, fmt.Sprintf("Canary|%v", true))
 		}
+		if len(failedChecks) > 0 {
+			basic = append(basic, fmt.Sprintf("Failed Checks|%s", strings.Join(failedChecks, ", ")))
+		}
 	}
 
 	if alloc.RescheduleTracker != nil && len(alloc.RescheduleTracker.Events) > 0 {
@@ -396,6 +429,39 @@ func (c *AllocStatusCommand) outputTaskDetails(alloc *api.Allocation, stats *api
 	}
 }
 
+// outputTemplates prints the rendering state of the templates of each task and
+// the dependencies they watch.
+func (c *AllocStatusCommand) outputTemplates(templates map[string][]*api.TemplateStatus) {
+	tasks := make([]string, 0, len(templates))
+	for task := range templates {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+
+	for _, task := range tasks {
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("\n[bold]Task %q Templates[reset]", task)))
+
+		tmpls := make([]string, 1, len(templates[task])+1)
+		tmpls[0] = "Destination|Rendered|Last Rendered|Last Update|Error"
+		deps := []string{"Destination|Type|Dependency|Status|Error"}
+		for _, tmpl := range templates[task] {
+			tmpls = append(tmpls, fmt.Sprintf("%s|%t|%s|%s|%s",
+				tmpl.DestPath, tmpl.Rendered, formatTaskTimes(tmpl.LastRendered),
+				formatTaskTimes(tmpl.LastUpdate), tmpl.Error))
+			for _, dep := range tmpl.Dependencies {
+				deps = append(deps, fmt.Sprintf("%s|%s|%s|%s|%s",
+					tmpl.DestPath, dep.Type, dep.Name, dep.Status, dep.Error))
+			}
+		}
+		c.Ui.Output(formatList(tmpls))
+
+		if len(deps) > 1 {
+			c.Ui.Output("")
+			c.Ui.Output(formatList(deps))
+		}
+	}
+}
+
 func formatTaskTimes(t time.Time) string {
 	if t.IsZero() {
 		return "N/A"
@@ -470,6 +536,8 @@ func buildDisplayMessage(event *api.TaskEvent) string {
 		} else {
 			desc = "Failed to download artifacts"
 		}
+	case api.TaskArtifactDownloadProgress:
+		desc = fmt.Sprintf("%s: %s", event.Details["artifact_url"], event.Message)
 	case api.TaskKilling:
 		if event.KillReason != "" {
 			desc = fmt.Sprintf("Killing task: %v", event.KillReason)
@@ -628,8 +696,59 @@ func (c *AllocStatusCommand) outputTaskResources(alloc *api.Allocation, task str
 		if ru, ok := stats.Tasks[task]; ok && ru != nil && displayStats && ru.ResourceUsage != nil {
 			c.Ui.Output("")
 			c.outputVerboseResourceUsage(task, ru.ResourceUsage)
+
+			if len(ru.Pids) > 0 {
+				c.Ui.Output("")
+				c.Ui.Output("Process Stats")
+				c.Ui.Output(formatList(formatProcessStats(ru.Pids)))
+			}
+		}
+	}
+}
+
+// formatProcessStats returns the table rows of the per-process resource usage
+// of a task, ordered by pid
+func formatProcessStats(pids map[string]*api.ResourceUsage) []string {
+	keys := make([]string, 0, len(pids))
+	for pid := range pids {
+		keys = append(keys, pid)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA != nil || errB != nil {
+			return keys[i] < keys[j]
 		}
+		return a < b
+	})
+
+	out := make([]string, 0, len(pids)+1)
+	out = append(out, "PID|PPID|RSS|CPU|Command")
+	for _, pid := range keys {
+		ru := pids[pid]
+		if ru == nil {
+			continue
+		}
+
+		ppid, command := "", ""
+		if ru.Process != nil {
+			ppid = strconv.Itoa(ru.Process.PPID)
+			command = limit(strings.ReplaceAll(ru.Process.Command, "|", " "), 60)
+		}
+
+		rss := ""
+		if ru.MemoryStats != nil {
+			rss = humanize.IBytes(ru.MemoryStats.RSS)
+		}
+
+		cpu := ""
+		if ru.CpuStats != nil {
+			cpu = fmt.Sprintf("%s%%", strconv.FormatFloat(ru.CpuStats.Percent, 'f', 2, 64))
+		}
+
+		out = append(out, fmt.Sprintf("%s|%s|%s|%s|%s", pid, ppid, rss, cpu, command))
 	}
+	return out
 }
 
 // outputVerboseResourceUsage outputs the verbose resource usage for the passed
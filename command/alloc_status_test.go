@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/command/agent"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -519,3 +520,31 @@ func TestAllocStatusCommand_CSIVolumes(t *testing.T) {
 	require.Contains(t, out, fmt.Sprintf("%s  minnie", vol0))
 	require.NotContains(t, out, "Host Volumes")
 }
+
+func TestAllocStatusCommand_formatProcessStats(t *testing.T) {
+	ci.Parallel(t)
+
+	pids := map[string]*api.ResourceUsage{
+		"100": {
+			MemoryStats: &api.MemoryStats{RSS: 2 * 1024 * 1024},
+			CpuStats:    &api.CpuStats{Percent: 97.5},
+			Process:     &api.ProcessInfo{PPID: 9, Command: "/bin/runaway | grep x"},
+		},
+		"9": {
+			MemoryStats: &api.MemoryStats{RSS: 1024},
+			CpuStats:    &api.CpuStats{Percent: 0.25},
+			Process:     &api.ProcessInfo{PPID: 1, Command: "/bin/sh -c start"},
+		},
+		"12": {
+			MemoryStats: &api.MemoryStats{RSS: 512},
+		},
+	}
+
+	out := formatProcessStats(pids)
+	require.Equal(t, []string{
+		"PID|PPID|RSS|CPU|Command",
+		"9|1|1.0 KiB|0.25%|/bin/sh -c start",
+		"12||512 B||",
+		"100|9|2.0 MiB|97.50%|/bin/runaway   grep x",
+	}, out)
+}
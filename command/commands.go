@@ -152,6 +152,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"alloc shell": func() (cli.Command, error) {
+			return &AllocShellCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc signal": func() (cli.Command, error) {
 			return &AllocSignalCommand{
 				Meta: meta,
@@ -167,6 +172,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"alloc list": func() (cli.Command, error) {
+			return &AllocListCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc logs": func() (cli.Command, error) {
 			return &AllocLogsCommand{
 				Meta: meta,
@@ -275,6 +285,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"eval retry": func() (cli.Command, error) {
+			return &EvalRetryCommand{
+				Meta: meta,
+			}, nil
+		},
 		"eval status": func() (cli.Command, error) {
 			return &EvalStatusCommand{
 				Meta: meta,
@@ -290,6 +305,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"fmt": func() (cli.Command, error) {
+			return &FormatCommand{
+				Meta: meta,
+			}, nil
+		},
 		"fs": func() (cli.Command, error) {
 			return &AllocFSCommand{
 				Meta: meta,
@@ -330,6 +350,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job freeze": func() (cli.Command, error) {
+			return &JobFreezeCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job history": func() (cli.Command, error) {
 			return &JobHistoryCommand{
 				Meta: meta,
@@ -345,11 +370,21 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job lineage": func() (cli.Command, error) {
+			return &JobLineageCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job periodic": func() (cli.Command, error) {
 			return &JobPeriodicCommand{
 				Meta: meta,
 			}, nil
 		},
+		"job lint": func() (cli.Command, error) {
+			return &JobLintCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job periodic force": func() (cli.Command, error) {
 			return &JobPeriodicForceCommand{
 				Meta: meta,
@@ -365,6 +400,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job restore": func() (cli.Command, error) {
+			return &JobRestoreCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job revert": func() (cli.Command, error) {
 			return &JobRevertCommand{
 				Meta: meta,
@@ -475,6 +515,16 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"node intro-token": func() (cli.Command, error) {
+			return &NodeIntroTokenCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node intro-token create": func() (cli.Command, error) {
+			return &NodeIntroTokenCreateCommand{
+				Meta: meta,
+			}, nil
+		},
 		"node-status": func() (cli.Command, error) {
 			return &NodeStatusCommand{
 				Meta: meta,
@@ -561,6 +611,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator gossip keyring rotate": func() (cli.Command, error) {
+			return &OperatorGossipKeyringRotateCommand{
+				Meta: meta,
+			}, nil
+		},
 
 		"operator metrics": func() (cli.Command, error) {
 			return &OperatorMetricsCommand{
@@ -599,6 +654,16 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator upgrade": func() (cli.Command, error) {
+			return &OperatorUpgradeCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator upgrade check": func() (cli.Command, error) {
+			return &OperatorUpgradeCheckCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator scheduler": func() (cli.Command, error) {
 			return &OperatorSchedulerCommand{
 				Meta: meta,
@@ -649,6 +714,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator snapshot diff": func() (cli.Command, error) {
+			return &OperatorSnapshotDiffCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator snapshot inspect": func() (cli.Command, error) {
 			return &OperatorSnapshotInspectCommand{
 				Meta: meta,
@@ -659,6 +729,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator snapshot redact": func() (cli.Command, error) {
+			return &OperatorSnapshotRedactCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator snapshot restore": func() (cli.Command, error) {
 			return &OperatorSnapshotRestoreCommand{
 				Meta: meta,
@@ -886,6 +961,16 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"system reconcile deployments": func() (cli.Command, error) {
+			return &SystemReconcileDeploymentsCommand{
+				Meta: meta,
+			}, nil
+		},
+		"system reconcile services": func() (cli.Command, error) {
+			return &SystemReconcileServicesCommand{
+				Meta: meta,
+			}, nil
+		},
 		"system reconcile summaries": func() (cli.Command, error) {
 			return &SystemReconcileSummariesCommand{
 				Meta: meta,
@@ -1,6 +1,8 @@
 package command
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
@@ -15,7 +17,7 @@ type ConfigValidateCommand struct {
 
 func (c *ConfigValidateCommand) Help() string {
 	helpText := `
-Usage: nomad config validate <config_path> [<config_path...>]
+Usage: nomad config validate [options] <config_path> [<config_path...>]
 
   Perform validation on a set of Nomad configuration files. This is useful
   to test the Nomad configuration without starting the agent.
@@ -31,7 +33,17 @@ Usage: nomad config validate <config_path> [<config_path...>]
   those won't pass the full agent validation. This command does not
   require an ACL token.
 
+  The configuration is resolved as the agent would resolve it at startup,
+  including go-sockaddr address templates and values read from the
+  environment such as VAULT_TOKEN. All problems found are reported at once.
+
   Returns 0 if the configuration is valid, or 1 if there are problems.
+
+Config Validate Options:
+
+  -render
+    Print the final merged configuration as JSON instead of a success
+    message. Secrets such as tokens are redacted from the output.
 `
 
 	return strings.TrimSpace(helpText)
@@ -45,8 +57,10 @@ func (c *ConfigValidateCommand) Name() string { return "config validate" }
 
 func (c *ConfigValidateCommand) Run(args []string) int {
 	var mErr multierror.Error
+	var render bool
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&render, "render", false, "")
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(err.Error())
 		return 1
@@ -77,6 +91,14 @@ func (c *ConfigValidateCommand) Run(args []string) int {
 		c.Ui.Error(err.Error())
 		return 1
 	}
+
+	// Resolve go-sockaddr templates and environment values the same way the
+	// agent does at startup.
+	if err := config.Finalize(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	cmd := agent.Command{Ui: c.Ui}
 	valid := cmd.IsValidConfig(config, agent.DefaultConfig())
 	if !valid {
@@ -84,6 +106,24 @@ func (c *ConfigValidateCommand) Run(args []string) int {
 		return 1
 	}
 
+	if render {
+		redacted, err := config.Redacted()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error redacting configuration: %s", err))
+			return 1
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(redacted); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error rendering configuration: %s", err))
+			return 1
+		}
+		c.Ui.Output(strings.TrimSpace(buf.String()))
+		return 0
+	}
+
 	c.Ui.Output("Configuration is valid!")
 	return 0
 }
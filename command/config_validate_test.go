@@ -1,12 +1,14 @@
 package command
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfigValidateCommand_FailWithEmptyDir(t *testing.T) {
@@ -88,3 +90,76 @@ func TestConfigValidateCommand_FailOnValidateParsableConfigFile(t *testing.T) {
 		t.Fatalf("expected exit 1, actual: %d", code)
 	}
 }
+
+func TestConfigValidateCommand_ReportsAllErrors(t *testing.T) {
+	ci.Parallel(t)
+	fh := t.TempDir()
+
+	fp := filepath.Join(fh, "config.hcl")
+	err := ioutil.WriteFile(fp, []byte(`data_dir="../"
+	client {
+		enabled = true
+		min_dynamic_port = -1
+	}`), 0644)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &ConfigValidateCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{fh})
+	require.Equal(t, 1, code)
+
+	out := ui.ErrorWriter.String()
+	require.Contains(t, out, "data-dir must be given as an absolute path")
+	require.Contains(t, out, "Invalid dynamic port range")
+}
+
+func TestConfigValidateCommand_FailOnBadAddressTemplate(t *testing.T) {
+	ci.Parallel(t)
+	fh := t.TempDir()
+
+	fp := filepath.Join(fh, "config.hcl")
+	err := ioutil.WriteFile(fp, []byte(`data_dir="/"
+	bind_addr = "{{ GetNoSuchIP }}"
+	client {
+		enabled = true
+	}`), 0644)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &ConfigValidateCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{fh})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Bind address resolution failed")
+}
+
+func TestConfigValidateCommand_Render(t *testing.T) {
+	ci.Parallel(t)
+	fh := t.TempDir()
+
+	fp := filepath.Join(fh, "config.hcl")
+	err := ioutil.WriteFile(fp, []byte(`data_dir="/"
+	region = "west"
+	client {
+		enabled = true
+	}
+	consul {
+		token = "supersecret"
+	}`), 0644)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &ConfigValidateCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-render", fh})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.NotContains(t, out, "supersecret")
+	require.Contains(t, out, "<redacted>")
+
+	var rendered map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &rendered))
+	require.Equal(t, "west", rendered["Region"])
+}
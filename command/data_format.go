@@ -4,9 +4,19 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
 	"text/template"
 
 	"github.com/hashicorp/go-msgpack/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// The output formats of the -format flag.
+const (
+	formatTable    = "table"
+	formatJSON     = "json"
+	formatYAML     = "yaml"
+	formatTemplate = "template"
 )
 
 var (
@@ -30,6 +40,11 @@ func DataFormat(format, tmpl string) (DataFormatter, error) {
 			return nil, fmt.Errorf("json format does not support template option.")
 		}
 		return &JSONFormat{}, nil
+	case "yaml":
+		if len(tmpl) > 0 {
+			return nil, fmt.Errorf("yaml format does not support template option.")
+		}
+		return &YAMLFormat{}, nil
 	case "template":
 		return &TemplateFormat{tmpl}, nil
 	}
@@ -51,6 +66,40 @@ func (p *JSONFormat) TransformData(data interface{}) (string, error) {
 	return buf.String(), nil
 }
 
+type YAMLFormat struct {
+}
+
+// TransformData returns YAML format string data. The data is encoded as JSON
+// first, so that the fields are named and ordered as in the JSON format.
+func (p *YAMLFormat) TransformData(data interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := codec.NewEncoder(&buf, jsonHandlePretty)
+	if err := enc.Encode(data); err != nil {
+		return "", err
+	}
+
+	// JSON is valid YAML, so decode it as a YAML document and drop the JSON
+	// styles of its nodes to output it in the block style
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		return "", err
+	}
+	resetYAMLStyle(&doc)
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		resetYAMLStyle(n)
+	}
+}
+
 type TemplateFormat struct {
 	tmpl string
 }
@@ -98,3 +147,65 @@ func Format(json bool, template string, data interface{}) (string, error) {
 
 	return out, nil
 }
+
+// formatRequested returns whether a command outputs formatted data rather
+// than its default output, given its -json and -t flags, if it has them, and
+// the -format flag.
+func (m *Meta) formatRequested(json bool, tmpl string) bool {
+	return json || len(tmpl) > 0 || (m.format != "" && m.format != formatTable)
+}
+
+// formatData formats the data output by a command, given its -json and -t
+// flags, if it has them, and the -format flag.
+func (m *Meta) formatData(json bool, tmpl string, data interface{}) (string, error) {
+	format, err := m.outputFormat(json, tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := DataFormat(format, tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := f.TransformData(data)
+	if err != nil {
+		return "", fmt.Errorf("Error formatting the data: %s", err)
+	}
+
+	return out, nil
+}
+
+// outputFormat returns the format of the output of a command given its -json
+// and -t flags, if it has them, and the -format flag. The -json and -t flags
+// take precedence over the default table format.
+func (m *Meta) outputFormat(json bool, tmpl string) (string, error) {
+	format := m.format
+	if format == "" {
+		format = formatTable
+	}
+
+	switch format {
+	case formatTable, formatJSON, formatYAML, formatTemplate:
+	default:
+		return "", fmt.Errorf("Unsupported format %q, must be one of table, json, yaml or template", format)
+	}
+
+	switch {
+	case json && len(tmpl) > 0:
+		return "", fmt.Errorf("Both json and template formatting are not allowed")
+	case json:
+		if format != formatTable && format != formatJSON {
+			return "", fmt.Errorf("The -json flag conflicts with -format=%s", format)
+		}
+		return formatJSON, nil
+	case len(tmpl) > 0:
+		if format != formatTable && format != formatTemplate {
+			return "", fmt.Errorf("The -t flag conflicts with -format=%s", format)
+		}
+		return formatTemplate, nil
+	case format == formatTemplate:
+		return "", fmt.Errorf("The template format requires a template given with -t")
+	}
+	return format, nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
 )
 
 type testData struct {
@@ -19,10 +20,14 @@ const expectJSON = `{
     "Region": "global"
 }`
 
+const expectYAML = `ID: "1"
+Name: example
+Region: global`
+
 var (
 	tData        = testData{"global", "1", "example"}
-	testFormat   = map[string]string{"json": "", "template": "{{.Region}}"}
-	expectOutput = map[string]string{"json": expectJSON, "template": "global"}
+	testFormat   = map[string]string{"json": "", "yaml": "", "template": "{{.Region}}"}
+	expectOutput = map[string]string{"json": expectJSON, "yaml": expectYAML, "template": "global"}
 )
 
 func TestDataFormat(t *testing.T) {
@@ -66,3 +71,46 @@ func TestInvalidJSONTemplate(t *testing.T) {
 		t.Fatalf("expected not specified template error, got: %s", err.Error())
 	}
 }
+
+func TestMeta_FormatData(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name      string
+		format    string
+		json      bool
+		tmpl      string
+		requested bool
+		expected  string
+		err       string
+	}{
+		{name: "default", requested: false, err: "Unsupported format"},
+		{name: "json flag", json: true, requested: true, expected: expectJSON},
+		{name: "template flag", tmpl: "{{.Region}}", requested: true, expected: "global"},
+		{name: "json format", format: "json", requested: true, expected: expectJSON},
+		{name: "yaml format", format: "yaml", requested: true, expected: expectYAML},
+		{name: "template format", format: "template", tmpl: "{{.Name}}", requested: true, expected: "example"},
+		{name: "table format with json flag", format: "table", json: true, requested: true, expected: expectJSON},
+		{name: "json format with json flag", format: "json", json: true, requested: true, expected: expectJSON},
+		{name: "yaml format with json flag", format: "yaml", json: true, requested: true, err: "conflicts with -format=yaml"},
+		{name: "json format with template flag", format: "json", tmpl: "{{.Name}}", requested: true, err: "conflicts with -format=json"},
+		{name: "json and template flags", json: true, tmpl: "{{.Name}}", requested: true, err: "not allowed"},
+		{name: "template format without template", format: "template", requested: true, err: "requires a template"},
+		{name: "invalid format", format: "xml", requested: true, err: "Unsupported format"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Meta{format: tc.format}
+			require.Equal(t, tc.requested, m.formatRequested(tc.json, tc.tmpl))
+
+			out, err := m.formatData(tc.json, tc.tmpl, tData)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, out)
+		})
+	}
+}
@@ -107,8 +107,8 @@ func (c *DeploymentListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploys)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, deploys)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
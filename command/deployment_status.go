@@ -102,7 +102,7 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 	}
 
 	// Check that json or tmpl isn't set with monitor
-	if monitor && (json || len(tmpl) > 0) {
+	if monitor && c.formatRequested(json, tmpl) {
 		c.Ui.Error("The monitor flag cannot be used with the '-json' or '-t' flags")
 		return 1
 	}
@@ -153,8 +153,8 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploy)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, deploy)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -145,8 +145,8 @@ func (c *EvalListCommand) Run(args []string) int {
 
 	// If args not specified but output format is specified, format
 	// and output the evaluations data list
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, evals)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, evals)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
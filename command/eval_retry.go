@@ -0,0 +1,162 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type EvalRetryCommand struct {
+	Meta
+}
+
+func (c *EvalRetryCommand) Help() string {
+	helpText := `
+Usage: nomad eval retry [options] <evaluation>
+
+  Retry a blocked or failed evaluation. A new evaluation is created for the
+  job of the given evaluation and is monitored until it completes. Placement
+  options of the job can be overridden for the new evaluation only; the
+  overrides are recorded on the new evaluation for auditing.
+
+  When ACLs are enabled, this command requires a token with the 'submit-job'
+  capability for the evaluation's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Eval Retry Options:
+
+  -datacenters=<dc1,dc2>
+    Place allocations in the given comma-separated datacenters instead of
+    the datacenters of the job. Only supported for service and batch jobs.
+
+  -ignore-soft-constraints
+    Ignore the affinities and spreads of the job when placing allocations.
+    Only supported for service and batch jobs.
+
+  -detach
+    Return immediately instead of entering monitor mode. After the new
+    evaluation is created, its ID will be printed to the screen.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalRetryCommand) Synopsis() string {
+	return "Retry a blocked or failed evaluation"
+}
+
+func (c *EvalRetryCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-datacenters":             complete.PredictAnything,
+			"-detach":                  complete.PredictNothing,
+			"-ignore-soft-constraints": complete.PredictNothing,
+			"-verbose":                 complete.PredictNothing,
+		})
+}
+
+func (c *EvalRetryCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Evals, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Evals]
+	})
+}
+
+func (c *EvalRetryCommand) Name() string { return "eval retry" }
+
+func (c *EvalRetryCommand) Run(args []string) int {
+	var detach, verbose, ignoreSoftConstraints bool
+	var datacenters string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&datacenters, "datacenters", "", "")
+	flags.BoolVar(&ignoreSoftConstraints, "ignore-soft-constraints", false, "")
+	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <evaluation>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	evalID := args[0]
+	if len(evalID) == 1 {
+		c.Ui.Error("Identifier must contain at least two characters.")
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	evalID = sanitizeUUIDPrefix(evalID)
+	evals, _, err := client.Evaluations().PrefixList(evalID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying evaluation: %v", err))
+		return 1
+	}
+	if len(evals) == 0 {
+		c.Ui.Error(fmt.Sprintf("No evaluation(s) with prefix or id %q found", evalID))
+		return 1
+	}
+	if len(evals) > 1 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple evaluations\n\n%s", formatEvalList(evals, verbose)))
+		return 1
+	}
+
+	var overrides *api.EvalOverrides
+	if datacenters != "" || ignoreSoftConstraints {
+		overrides = &api.EvalOverrides{
+			IgnoreSoftConstraints: ignoreSoftConstraints,
+		}
+		if datacenters != "" {
+			overrides.Datacenters = strings.Split(datacenters, ",")
+		}
+	}
+
+	resp, _, err := client.Evaluations().Retry(evals[0].ID, overrides, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrying evaluation: %s", err))
+		return 1
+	}
+
+	if detach {
+		c.Ui.Output(fmt.Sprintf("Created eval ID: %q", limit(resp.EvalID, length)))
+		return 0
+	}
+
+	mon := newMonitor(c.Ui, client, length)
+	return mon.monitor(resp.EvalID)
+}
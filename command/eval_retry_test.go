@@ -0,0 +1,82 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalRetryCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &EvalRetryCommand{}
+}
+
+func TestEvalRetryCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &EvalRetryCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	code = cmd.Run([]string{"-address=nope", "12345678-abcd-efab-cdef-123456789abc"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error querying evaluation")
+}
+
+func TestEvalRetryCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+
+	// Register a job with no clients so that its evaluation is blocked
+	job := testJob("job1")
+	resp, _, err := client.Jobs().Register(job, nil)
+	require.NoError(t, err)
+
+	var blockedID string
+	testutil.WaitForResult(func() (bool, error) {
+		eval, _, err := client.Evaluations().Info(resp.EvalID, nil)
+		if err != nil {
+			return false, err
+		}
+		if eval.BlockedEval == "" {
+			return false, fmt.Errorf("eval not blocked yet")
+		}
+		blockedID = eval.BlockedEval
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	ui := cli.NewMockUi()
+	cmd := &EvalRetryCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-detach", "-verbose",
+		"-datacenters=dc2", "-ignore-soft-constraints", blockedID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Created eval ID")
+
+	evals, _, err := client.Jobs().Evaluations(*job.ID, nil)
+	require.NoError(t, err)
+
+	var retried *api.Evaluation
+	for _, eval := range evals {
+		if eval.TriggeredBy == "eval-retry" {
+			retried = eval
+		}
+	}
+	require.NotNil(t, retried)
+	require.Equal(t, blockedID, retried.PreviousEval)
+	require.Equal(t, []string{"dc2"}, retried.Overrides.Datacenters)
+	require.True(t, retried.Overrides.IgnoreSoftConstraints)
+}
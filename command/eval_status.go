@@ -106,14 +106,14 @@ func (c *EvalStatusCommand) Run(args []string) int {
 	}
 
 	// If args not specified but output format is specified, format and output the evaluations data list
-	if len(args) == 0 && (json || len(tmpl) > 0) {
+	if len(args) == 0 && c.formatRequested(json, tmpl) {
 		evals, _, err := client.Evaluations().List(nil)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying evaluations: %v", err))
 			return 1
 		}
 
-		out, err := Format(json, tmpl, evals)
+		out, err := c.formatData(json, tmpl, evals)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -173,8 +173,8 @@ func (c *EvalStatusCommand) Run(args []string) int {
 	}
 
 	// If output format is specified, format and output the data
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, eval)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, eval)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -227,6 +227,14 @@ func (c *EvalStatusCommand) Run(args []string) int {
 			fmt.Sprintf("Wait Until|%s", formatTime(eval.WaitUntil)))
 	}
 
+	if eval.Overrides != nil && len(eval.Overrides.Datacenters) > 0 {
+		basic = append(basic,
+			fmt.Sprintf("Datacenters Override|%s", strings.Join(eval.Overrides.Datacenters, ",")))
+	}
+	if eval.Overrides != nil && eval.Overrides.IgnoreSoftConstraints {
+		basic = append(basic, "Soft Constraints|ignored")
+	}
+
 	if verbose {
 		// NextEval, PreviousEval, BlockedEval
 		basic = append(basic,
@@ -273,7 +281,7 @@ func getTriggerDetails(eval *api.Evaluation) (noun, subject string) {
 	switch eval.TriggeredBy {
 	case "node-update":
 		return "Node ID", eval.NodeID
-	case "max-plan-attempts":
+	case "max-plan-attempts", "eval-retry":
 		return "Previous Eval", eval.PreviousEval
 	default:
 		return "", ""
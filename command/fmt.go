@@ -0,0 +1,433 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/posener/complete"
+)
+
+// fmtExtensions are the extensions of the files formatted in directories.
+var fmtExtensions = []string{".nomad", ".hcl"}
+
+// fmtBlockOrder is the canonical order of the blocks in the body of each type
+// of block of job files and agent configuration files, where "" is the
+// top-level body. Blocks of other types follow these blocks, in the order
+// they were written.
+var fmtBlockOrder = map[string][]string{
+	"": {
+		// job files
+		"variable", "variables", "locals", "job",
+
+		// agent configuration
+		"addresses", "advertise", "ports", "server", "client", "acl",
+		"audit", "autopilot", "consul", "vault", "sentinel", "telemetry",
+		"tls", "ui", "limits", "plugin",
+	},
+	"job": {
+		"constraint", "affinity", "spread", "multiregion", "update",
+		"migrate", "reschedule", "periodic", "parameterized", "vault",
+		"meta", "group",
+	},
+	"group": {
+		"constraint", "affinity", "spread", "network", "service", "volume",
+		"ephemeral_disk", "restart", "reschedule", "update", "migrate",
+		"scaling", "consul", "vault", "meta", "task",
+	},
+	"task": {
+		"config", "env", "constraint", "affinity", "lifecycle", "artifact",
+		"dispatch_payload", "template", "volume_mount", "resources",
+		"service", "logs", "restart", "scaling", "vault", "csi_plugin",
+		"meta",
+	},
+}
+
+type FormatCommand struct {
+	Meta
+
+	// stdin is read when the path "-" is formatted, overriding os.Stdin.
+	stdin io.Reader
+}
+
+func (f *FormatCommand) Help() string {
+	helpText := `
+Usage: nomad fmt [options] [<path> ...]
+
+  Rewrites job files and agent configuration files to a canonical format.
+  The blocks of jobs, groups and tasks are sorted in a canonical order, and
+  attributes and indentation are aligned. Comments are kept with the blocks
+  they precede.
+
+  If a path is a directory, the files with the .nomad and .hcl extensions in
+  the directory are formatted. Without a path, the current directory is
+  formatted. If the path is "-", the file is read from stdin and the
+  formatted file is written to stdout.
+
+Format Options:
+
+  -check
+    Checks whether the files are formatted without rewriting them. The files
+    that are not formatted are listed, and the exit code is 3 if any is found,
+    so that this can be used in CI.
+
+  -list=<bool>
+    Lists the files whose formatting changed. Defaults to true.
+
+  -write=<bool>
+    Writes the formatted files in place rather than to stdout. Defaults to
+    true, unless the path is "-".
+
+  -recursive
+    Formats the files of the subdirectories of directories as well.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *FormatCommand) Synopsis() string {
+	return "Rewrites job and configuration files to a canonical format"
+}
+
+func (f *FormatCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-check":     complete.PredictNothing,
+		"-list":      complete.PredictNothing,
+		"-write":     complete.PredictNothing,
+		"-recursive": complete.PredictNothing,
+	}
+}
+
+func (f *FormatCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*.nomad"),
+		complete.PredictFiles("*.hcl"),
+	)
+}
+
+func (f *FormatCommand) Name() string { return "fmt" }
+
+func (f *FormatCommand) Run(args []string) int {
+	var check, list, write, recursive bool
+
+	flags := f.Meta.FlagSet(f.Name(), FlagSetNone)
+	flags.Usage = func() { f.Ui.Output(f.Help()) }
+	flags.BoolVar(&check, "check", false, "")
+	flags.BoolVar(&list, "list", true, "")
+	flags.BoolVar(&write, "write", true, "")
+	flags.BoolVar(&recursive, "recursive", false, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	if len(paths) == 1 && paths[0] == "-" {
+		return f.formatStdin(check)
+	}
+
+	if check {
+		write = false
+		list = true
+	}
+
+	var files []string
+	for _, path := range paths {
+		found, err := fmtFiles(path, recursive)
+		if err != nil {
+			f.Ui.Error(fmt.Sprintf("Error finding files to format: %s", err))
+			return 1
+		}
+		files = append(files, found...)
+	}
+
+	failed := false
+	unformatted := false
+	for _, file := range files {
+		changed, err := f.formatFile(file, write, list)
+		if err != nil {
+			f.Ui.Error(err.Error())
+			failed = true
+			continue
+		}
+		unformatted = unformatted || changed
+	}
+
+	switch {
+	case failed:
+		return 1
+	case check && unformatted:
+		return 3
+	}
+	return 0
+}
+
+// formatStdin formats the file read from stdin to stdout.
+func (f *FormatCommand) formatStdin(check bool) int {
+	stdin := f.stdin
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+
+	src, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		f.Ui.Error(fmt.Sprintf("Error reading stdin: %s", err))
+		return 1
+	}
+
+	out, diags := formatHCL(src, "<stdin>")
+	if diags.HasErrors() {
+		f.Ui.Error(fmt.Sprintf("Error parsing stdin: %s", diags))
+		return 1
+	}
+
+	if check {
+		if !bytes.Equal(src, out) {
+			return 3
+		}
+		return 0
+	}
+
+	f.Ui.Output(strings.TrimSuffix(string(out), "\n"))
+	return 0
+}
+
+// formatFile formats a file, either writing it in place or to stdout. It
+// returns whether the formatting of the file changed.
+func (f *FormatCommand) formatFile(path string, write, list bool) (bool, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("Error reading %s: %s", path, err)
+	}
+
+	out, diags := formatHCL(src, path)
+	if diags.HasErrors() {
+		return false, fmt.Errorf("Error parsing %s: %s", path, diags)
+	}
+
+	changed := !bytes.Equal(src, out)
+	if changed && list {
+		f.Ui.Output(path)
+	}
+
+	if !write {
+		if !list {
+			f.Ui.Output(strings.TrimSuffix(string(out), "\n"))
+		}
+		return changed, nil
+	}
+
+	if changed {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("Error writing %s: %s", path, err)
+		}
+		if err := ioutil.WriteFile(path, out, info.Mode()); err != nil {
+			return false, fmt.Errorf("Error writing %s: %s", path, err)
+		}
+	}
+	return changed, nil
+}
+
+// fmtFiles returns the files to format for a path given to the command.
+func fmtFiles(path string, recursive bool) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p != path && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, ext := range fmtExtensions {
+			if filepath.Ext(p) == ext {
+				files = append(files, p)
+				break
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// formatHCL returns the canonical format of an HCL file: the blocks of its
+// bodies are sorted by fmtBlockOrder, and it is formatted by hclwrite.
+func formatHCL(src []byte, filename string) ([]byte, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	body := file.Body.(*hclsyntax.Body)
+	out := sortBody(src, body, "", 0, len(src))
+	return hclwrite.Format(out), nil
+}
+
+// fmtItem is an attribute or a block of a body, spanning its lead comments
+// through the end of its last line.
+type fmtItem struct {
+	start, end int
+	block      *hclsyntax.Block
+	rank       int
+
+	// gap is the source between the previous item and the item, such as
+	// blank lines and comments separated from the item by a blank line.
+	gap []byte
+}
+
+// sortBody returns the source of the body spanning src[start:end], with its
+// attributes followed by its blocks in their canonical order, recursively.
+func sortBody(src []byte, body *hclsyntax.Body, typeName string, start, end int) []byte {
+	order := fmtBlockOrder[typeName]
+	rank := func(blockType string) int {
+		for i, t := range order {
+			if t == blockType {
+				return i
+			}
+		}
+		return len(order)
+	}
+
+	items := make([]*fmtItem, 0, len(body.Attributes)+len(body.Blocks))
+	for _, attr := range body.Attributes {
+		items = append(items, newFmtItem(src, attr.SrcRange, start, end))
+	}
+	for _, block := range body.Blocks {
+		item := newFmtItem(src, block.Range(), start, end)
+		item.block = block
+		item.rank = rank(block.Type)
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return src[start:end]
+	}
+
+	// Items are in source order, so each gap precedes its item
+	sort.Slice(items, func(i, j int) bool { return items[i].start < items[j].start })
+	pos := start
+	for _, item := range items {
+		item.gap = src[pos:item.start]
+		pos = item.end
+	}
+	tail := src[pos:end]
+
+	sorted := make([]*fmtItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if (a.block == nil) != (b.block == nil) {
+			return a.block == nil
+		}
+		return a.rank < b.rank
+	})
+
+	var buf bytes.Buffer
+	for i, item := range sorted {
+		switch {
+		case i == 0:
+			// The gap at the start of the body stays there
+			buf.Write(items[0].gap)
+			if item != items[0] {
+				buf.Write(trimLeadingBlankLines(item.gap))
+			}
+		case item == items[0]:
+			buf.WriteString("\n")
+		default:
+			buf.Write(item.gap)
+		}
+
+		if item.block == nil {
+			buf.Write(src[item.start:item.end])
+			continue
+		}
+
+		open := item.block.OpenBraceRange.End.Byte
+		close := item.block.CloseBraceRange.Start.Byte
+		buf.Write(src[item.start:open])
+		buf.Write(sortBody(src, item.block.Body, item.block.Type, open, close))
+		buf.Write(src[close:item.end])
+	}
+	buf.Write(tail)
+	return buf.Bytes()
+}
+
+// newFmtItem returns the item spanning the range of an attribute or block in
+// the body spanning src[start:end], extended to include the comments on the
+// lines preceding it and the rest of its last line.
+func newFmtItem(src []byte, rng hcl.Range, start, end int) *fmtItem {
+	item := &fmtItem{start: rng.Start.Byte, end: rng.End.Byte}
+
+	// Include the comment lines that immediately precede the item
+	lineStart := bytes.LastIndexByte(src[start:item.start], '\n') + 1 + start
+	isLineStart := lineStart > start || start == 0 || src[start-1] == '\n'
+	if isLineStart && len(bytes.TrimSpace(src[lineStart:item.start])) == 0 {
+		item.start = lineStart
+		for item.start > start {
+			prevStart := bytes.LastIndexByte(src[start:item.start-1], '\n') + 1 + start
+			if prevStart == start && (start == 0 || src[start-1] != '\n') {
+				// The previous line starts on the line opening the body
+				break
+			}
+			if !isCommentLine(src[prevStart:item.start]) {
+				break
+			}
+			item.start = prevStart
+		}
+	}
+
+	// Include the rest of the last line, such as a line comment, unless the
+	// item already ends a line as heredocs do
+	if item.end > 0 && src[item.end-1] != '\n' {
+		if i := bytes.IndexByte(src[item.end:end], '\n'); i >= 0 {
+			rest := bytes.TrimSpace(src[item.end : item.end+i])
+			if len(rest) == 0 || isCommentLine(rest) {
+				item.end += i + 1
+			}
+		}
+	}
+	return item
+}
+
+// isCommentLine returns whether a line of source holds only a comment.
+func isCommentLine(line []byte) bool {
+	line = bytes.TrimSpace(line)
+	switch {
+	case bytes.HasPrefix(line, []byte("#")), bytes.HasPrefix(line, []byte("//")):
+		return true
+	case bytes.HasPrefix(line, []byte("/*")) && bytes.HasSuffix(line, []byte("*/")):
+		return true
+	}
+	return false
+}
+
+// trimLeadingBlankLines removes the blank lines at the start of src.
+func trimLeadingBlankLines(src []byte) []byte {
+	for {
+		i := bytes.IndexByte(src, '\n')
+		if i < 0 || len(bytes.TrimSpace(src[:i])) != 0 {
+			return src
+		}
+		src = src[i+1:]
+	}
+}
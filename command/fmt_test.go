@@ -0,0 +1,165 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+const fmtTestUnformatted = `# Example job
+job "example" {
+  datacenters = ["dc1"]
+
+  group "cache" {
+    # The cache task
+    task "redis" {
+      # Resources of the task
+      resources {
+        cpu = 500
+        memory = 256
+      }
+
+      driver = "docker"
+
+      config {
+        image = "redis:3.2" # pinned
+      }
+    }
+
+    network {
+      port "db" { to = 6379 }
+    }
+    count = 1
+  }
+
+  update {
+    max_parallel = 1
+  }
+}
+`
+
+const fmtTestFormatted = `# Example job
+job "example" {
+  datacenters = ["dc1"]
+
+  update {
+    max_parallel = 1
+  }
+
+  group "cache" {
+    count = 1
+
+    network {
+      port "db" { to = 6379 }
+    }
+
+    # The cache task
+    task "redis" {
+      driver = "docker"
+
+      config {
+        image = "redis:3.2" # pinned
+      }
+
+      # Resources of the task
+      resources {
+        cpu    = 500
+        memory = 256
+      }
+    }
+  }
+}
+`
+
+func TestFormatCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &FormatCommand{}
+}
+
+func TestFormatHCL(t *testing.T) {
+	ci.Parallel(t)
+
+	out, diags := formatHCL([]byte(fmtTestUnformatted), "example.nomad")
+	require.False(t, diags.HasErrors(), diags.Error())
+	require.Equal(t, fmtTestFormatted, string(out))
+
+	// Formatting is idempotent
+	out, diags = formatHCL(out, "example.nomad")
+	require.False(t, diags.HasErrors(), diags.Error())
+	require.Equal(t, fmtTestFormatted, string(out))
+
+	// Heredocs are kept as they are
+	heredoc := "job \"example\" {\n  group \"web\" {\n    task \"web\" {\n      template {\n        data        = <<EOH\n  {{ key \"x\" }}\nEOH\n        destination = \"local/x\"\n      }\n      driver = \"exec\"\n    }\n  }\n}\n"
+	out, diags = formatHCL([]byte(heredoc), "example.nomad")
+	require.False(t, diags.HasErrors(), diags.Error())
+	require.Contains(t, string(out), "<<EOH\n  {{ key \"x\" }}\nEOH\n")
+	require.Less(t, strings.Index(string(out), "driver"), strings.Index(string(out), "template"))
+
+	_, diags = formatHCL([]byte(`job "example" {`), "example.nomad")
+	require.True(t, diags.HasErrors())
+}
+
+func TestFormatCommand_Files(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	unformatted := filepath.Join(dir, "unformatted.nomad")
+	formatted := filepath.Join(dir, "formatted.hcl")
+	nested := filepath.Join(dir, "nested", "nested.nomad")
+	ignored := filepath.Join(dir, "ignored.json")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0755))
+	require.NoError(t, os.WriteFile(unformatted, []byte(fmtTestUnformatted), 0644))
+	require.NoError(t, os.WriteFile(formatted, []byte(fmtTestFormatted), 0644))
+	require.NoError(t, os.WriteFile(nested, []byte(fmtTestUnformatted), 0644))
+	require.NoError(t, os.WriteFile(ignored, []byte(`{}`), 0644))
+
+	// Checking lists the unformatted files without rewriting them
+	ui := cli.NewMockUi()
+	cmd := &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Equal(t, 3, cmd.Run([]string{"-check", dir}))
+	require.Equal(t, unformatted+"\n", ui.OutputWriter.String())
+
+	src, err := os.ReadFile(unformatted)
+	require.NoError(t, err)
+	require.Equal(t, fmtTestUnformatted, string(src))
+
+	// Formatting rewrites them
+	ui = cli.NewMockUi()
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Zero(t, cmd.Run([]string{"-recursive", dir}), ui.ErrorWriter.String())
+	require.Equal(t, nested+"\n"+unformatted+"\n", ui.OutputWriter.String())
+
+	for _, path := range []string{unformatted, nested} {
+		src, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, fmtTestFormatted, string(src))
+	}
+
+	ui = cli.NewMockUi()
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Zero(t, cmd.Run([]string{"-check", "-recursive", dir}))
+	require.Empty(t, ui.OutputWriter.String())
+}
+
+func TestFormatCommand_Stdin(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &FormatCommand{Meta: Meta{Ui: ui}, stdin: strings.NewReader(fmtTestUnformatted)}
+	require.Zero(t, cmd.Run([]string{"-"}), ui.ErrorWriter.String())
+	require.Equal(t, fmtTestFormatted, ui.OutputWriter.String())
+
+	ui = cli.NewMockUi()
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}, stdin: strings.NewReader(fmtTestUnformatted)}
+	require.Equal(t, 3, cmd.Run([]string{"-check", "-"}))
+
+	ui = cli.NewMockUi()
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}, stdin: strings.NewReader(`job "example" {`)}
+	require.Equal(t, 1, cmd.Run([]string{"-"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error parsing stdin")
+}
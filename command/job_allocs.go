@@ -133,8 +133,8 @@ func (c *JobAllocsCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, allocs)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, allocs)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -145,8 +145,8 @@ func (c *JobDeploymentsCommand) Run(args []string) int {
 			return 1
 		}
 
-		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, deploy)
+		if c.formatRequested(json, tmpl) {
+			out, err := c.formatData(json, tmpl, deploy)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -166,8 +166,8 @@ func (c *JobDeploymentsCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploys)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, deploys)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
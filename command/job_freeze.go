@@ -0,0 +1,127 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type JobFreezeCommand struct {
+	Meta
+}
+
+func (c *JobFreezeCommand) Help() string {
+	helpText := `
+Usage: nomad job freeze [options] <job id>
+
+  Freeze is used to guard a job against changes, for example during incident
+  response. Updating, stopping, scaling, reverting, or dispatching a frozen
+  job is rejected unless the request is made with a token that has the
+  'unfreeze' capability. The freeze state of a job is shown by the
+  "nomad job status" command.
+
+  When ACLs are enabled, freezing a job requires a token with the 'submit-job'
+  capability for the job's namespace, and unfreezing it requires a token with
+  the 'unfreeze' capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Freeze Options:
+
+  -unfreeze
+    Unfreeze the job, allowing changes again.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobFreezeCommand) Synopsis() string {
+	return "Freeze or unfreeze a job"
+}
+
+func (c *JobFreezeCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-unfreeze": complete.PredictNothing,
+		})
+}
+
+func (c *JobFreezeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobFreezeCommand) Name() string { return "job freeze" }
+
+func (c *JobFreezeCommand) Run(args []string) int {
+	var unfreeze bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&unfreeze, "unfreeze", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <job id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobID := strings.TrimSpace(args[0])
+	jobs, _, err := client.Jobs().PrefixList(jobID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error freezing job: %s", err))
+		return 1
+	}
+	if len(jobs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No job(s) with prefix or id %q found", jobID))
+		return 1
+	}
+	if len(jobs) > 1 {
+		if (jobID != jobs[0].ID) || (c.allNamespaces() && jobs[0].ID == jobs[1].ID) {
+			c.Ui.Error(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs, c.allNamespaces())))
+			return 1
+		}
+	}
+	jobID = jobs[0].ID
+	q := &api.WriteOptions{Namespace: jobs[0].JobSummary.Namespace}
+
+	if _, _, err := client.Jobs().Freeze(jobID, !unfreeze, q); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error freezing job: %s", err))
+		return 1
+	}
+
+	if unfreeze {
+		c.Ui.Output(fmt.Sprintf("Job %q unfrozen", jobID))
+	} else {
+		c.Ui.Output(fmt.Sprintf("Job %q frozen", jobID))
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobFreezeCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobFreezeCommand{}
+}
+
+func TestJobFreezeCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobFreezeCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope", "12"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error freezing")
+}
+
+func TestJobFreezeCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+	j := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, j))
+
+	ui := cli.NewMockUi()
+	cmd := &JobFreezeCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, j.ID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "frozen")
+
+	out, err := state.JobByID(nil, j.Namespace, j.ID)
+	require.NoError(t, err)
+	require.True(t, out.Frozen)
+
+	// Frozen jobs are shown as such by job status
+	ui = cli.NewMockUi()
+	statusCmd := &JobStatusCommand{Meta: Meta{Ui: ui}}
+	code = statusCmd.Run([]string{"-address=" + url, j.ID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.True(t, strings.Contains(ui.OutputWriter.String(), "Frozen"))
+
+	ui = cli.NewMockUi()
+	cmd = &JobFreezeCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-unfreeze", j.ID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "unfrozen")
+
+	out, err = state.JobByID(nil, j.Namespace, j.ID)
+	require.NoError(t, err)
+	require.False(t, out.Frozen)
+}
+
+func TestJobFreezeCommand_AutocompleteArgs(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobFreezeCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Create a fake job
+	state := srv.Agent.Server().State()
+	j := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, j))
+
+	prefix := j.ID[:len(j.ID)-5]
+	args := complete.Args{Last: prefix}
+	predictor := cmd.AutocompleteArgs()
+
+	res := predictor.Predict(args)
+	require.Equal(t, []string{j.ID}, res)
+}
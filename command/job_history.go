@@ -109,7 +109,7 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		return 1
 	}
 
-	if (json || len(tmpl) != 0) && (diff || full) {
+	if c.formatRequested(json, tmpl) && (diff || full) {
 		c.Ui.Error("-json and -t are exclusive with -p and -full")
 		return 1
 	}
@@ -178,8 +178,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			}
 		}
 
-		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, job)
+		if c.formatRequested(json, tmpl) {
+			out, err := c.formatData(json, tmpl, job)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -195,8 +195,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		}
 
 	} else {
-		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, versions)
+		if c.formatRequested(json, tmpl) {
+			out, err := c.formatData(json, tmpl, versions)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
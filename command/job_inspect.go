@@ -94,14 +94,14 @@ func (c *JobInspectCommand) Run(args []string) int {
 	}
 
 	// If args not specified but output format is specified, format and output the jobs data list
-	if len(args) == 0 && (json || len(tmpl) > 0) {
+	if len(args) == 0 && c.formatRequested(json, tmpl) {
 		jobs, _, err := client.Jobs().List(nil)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying jobs: %v", err))
 			return 1
 		}
 
-		out, err := Format(json, tmpl, jobs)
+		out, err := c.formatData(json, tmpl, jobs)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -155,8 +155,8 @@ func (c *JobInspectCommand) Run(args []string) int {
 	}
 
 	// If output format is specified, format and output the data
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, job)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, job)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -0,0 +1,202 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+	"github.com/ryanuber/columnize"
+)
+
+type JobLineageCommand struct {
+	Meta
+}
+
+func (c *JobLineageCommand) Help() string {
+	helpText := `
+Usage: nomad job lineage [options] <job>
+
+  Lineage displays the chain of evaluations, deployments and allocations
+  caused by the versions of a job as a tree, to explain why allocations were
+  placed, replaced or stopped. Each object is displayed below the object that
+  caused it: the evaluations created by registering a job version, the
+  allocations placed by an evaluation, the evaluation rescheduling a failed
+  allocation and so on. Objects whose cause was garbage collected are
+  displayed at the top level.
+
+  When ACLs are enabled, this command requires a token with the 'read-job' and
+  'list-jobs' capabilities for the job's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Lineage Options:
+
+  -json
+    Output the lineage in a JSON format.
+
+  -t
+    Format and display the lineage using a Go template.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobLineageCommand) Synopsis() string {
+	return "Explain the evaluations, deployments and allocations of a job"
+}
+
+func (c *JobLineageCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *JobLineageCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobLineageCommand) Name() string { return "job lineage" }
+
+func (c *JobLineageCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	jobID := strings.TrimSpace(args[0])
+
+	// Check if the job exists
+	jobs, _, err := client.Jobs().PrefixList(jobID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing jobs: %s", err))
+		return 1
+	}
+	if len(jobs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No job(s) with prefix or id %q found", jobID))
+		return 1
+	}
+	if len(jobs) > 1 {
+		if (jobID != jobs[0].ID) || (c.allNamespaces() && jobs[0].ID == jobs[1].ID) {
+			c.Ui.Error(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs, c.allNamespaces())))
+			return 1
+		}
+	}
+
+	jobID = jobs[0].ID
+	q := &api.QueryOptions{Namespace: jobs[0].JobSummary.Namespace}
+
+	lineage, _, err := client.Jobs().Lineage(jobID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving lineage: %s", err))
+		return 1
+	}
+
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, lineage)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(lineage) == 0 {
+		c.Ui.Output("No lineage found")
+		return 0
+	}
+
+	// Truncate the ids unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	c.Ui.Output(formatJobLineage(lineage, length, verbose))
+	return 0
+}
+
+// formatJobLineage formats the lineage of a job as an indented tree, with
+// one object per line.
+func formatJobLineage(roots []*api.JobLineageNode, length int, verbose bool) string {
+	var rows []string
+	now := time.Now()
+
+	var format func(nodes []*api.JobLineageNode, depth int)
+	format = func(nodes []*api.JobLineageNode, depth int) {
+		for _, n := range nodes {
+			id := n.ID
+			if n.Type != api.JobLineageNodeTypeJobVersion {
+				id = limit(id, length)
+			}
+
+			status := n.Status
+			if status == "" {
+				status = "-"
+			}
+
+			created := "-"
+			if n.CreateTime != 0 {
+				created = prettyTimeDiff(time.Unix(0, n.CreateTime), now)
+				if verbose {
+					created = formatUnixNanoTime(n.CreateTime)
+				}
+			}
+
+			rows = append(rows, fmt.Sprintf("%s%s %s|%s|%s|%s",
+				strings.Repeat("  ", depth), n.Type, id, status, created, n.Description))
+			format(n.Children, depth+1)
+		}
+	}
+	format(roots, 0)
+
+	// Keep the indentation of the objects
+	columnConf := columnize.DefaultConfig()
+	columnConf.NoTrim = true
+	return columnize.Format(append([]string{"Object|Status|Created|Description"}, rows...), columnConf)
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobLineageCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobLineageCommand{}
+}
+
+func TestJobLineageCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobLineageCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(t, 1, cmd.Run([]string{"some", "bad", "args"}))
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	require.Equal(t, 1, cmd.Run([]string{"-address=nope", "foo"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error listing jobs")
+}
+
+func TestJobLineageCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobLineageCommand{Meta: Meta{Ui: ui}}
+
+	// Should return an error message for no job match
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "foo"}))
+	ui.ErrorWriter.Reset()
+
+	state := srv.Agent.Server().State()
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 100, job))
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	eval.TriggeredBy = structs.EvalTriggerJobRegister
+	eval.JobModifyIndex = job.JobModifyIndex
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 101, []*structs.Evaluation{eval}))
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.EvalID = eval.ID
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 102, []*structs.Allocation{alloc}))
+
+	code := cmd.Run([]string{"-address=" + url, "-verbose", job.ID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "job-version 0")
+	require.Contains(t, out, "  evaluation "+eval.ID)
+	require.Contains(t, out, "    allocation "+alloc.ID)
+	require.Contains(t, out, "Triggered by job-register")
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-json", job.ID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	var lineage []*api.JobLineageNode
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &lineage))
+	require.Len(t, lineage, 1)
+	require.Equal(t, eval.ID, lineage[0].Children[0].ID)
+}
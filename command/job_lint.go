@@ -0,0 +1,233 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/helper/joblint"
+	"github.com/posener/complete"
+)
+
+type JobLintCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *JobLintCommand) Help() string {
+	helpText := `
+Usage: nomad job lint [options] <path>
+
+  Checks a job file for practices that are valid but likely to cause problems
+  in production. Unlike "nomad job validate", lint does not contact a Nomad
+  agent, so it can be used to gate job files in CI pipelines.
+
+  The built-in rules are:
+
+    update-stanza: service groups without an update stanza (warning)
+    health-checks: service groups without health checks (warning)
+    image-tag:     docker and podman images without a tag or with the
+                   latest tag (error)
+    resources:     tasks without cpu or memory resources (warning)
+
+  Custom rules are added with the -plugin flag. A plugin is a program that is
+  given the job encoded as JSON on stdin, and prints a JSON array of findings
+  on stdout, with the Severity, Group, Task and Message fields. The rule of
+  the findings defaults to the name of the program.
+
+  If the supplied path is "-", the jobfile is read from stdin. Otherwise
+  it is read from the file at the supplied path or downloaded and
+  read from URL specified.
+
+  The exit code is 0 if no finding is at least as severe as -fail-level, 2 if
+  one is, and 1 if an error occurred.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Lint Options:
+
+  -json
+    Parses the job file as JSON. If the outer object has a Job field, such as
+    from "nomad job inspect" or "nomad run -output", the value of the field is
+    used as the job.
+
+  -hcl1
+    Parses the job file as HCLv1.
+
+  -hcl2-strict
+    Whether an error should be produced from the HCL2 parser where a variable
+    has been supplied which is not defined within the root variables. Defaults
+    to true.
+
+  -var 'key=value'
+    Variable for template, can be used multiple times.
+
+  -var-file=path
+    Path to HCL2 file containing user variables.
+
+  -plugin=path
+    Path to a rule plugin, can be used multiple times.
+
+  -disable=rule
+    Name of a rule to disable, can be used multiple times.
+
+  -fail-level=<info|warning|error>
+    Minimum severity of the findings which fail linting. Defaults to warning.
+
+  -t
+    Format and display the findings using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobLintCommand) Synopsis() string {
+	return "Checks a job specification for likely problems"
+}
+
+func (c *JobLintCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-json":        complete.PredictNothing,
+		"-hcl1":        complete.PredictNothing,
+		"-hcl2-strict": complete.PredictNothing,
+		"-var":         complete.PredictAnything,
+		"-var-file":    complete.PredictFiles("*.var"),
+		"-plugin":      complete.PredictFiles("*"),
+		"-disable":     complete.PredictSet("update-stanza", "health-checks", "image-tag", "resources"),
+		"-fail-level":  complete.PredictSet("info", "warning", "error"),
+		"-t":           complete.PredictAnything,
+	}
+}
+
+func (c *JobLintCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*.nomad"),
+		complete.PredictFiles("*.hcl"),
+		complete.PredictFiles("*.json"),
+	)
+}
+
+func (c *JobLintCommand) Name() string { return "job lint" }
+
+func (c *JobLintCommand) Run(args []string) int {
+	var plugins, disabled []string
+	var failLevel, tmpl string
+
+	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
+	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
+	flagSet.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	flagSet.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
+	flagSet.Var(&c.JobGetter.Vars, "var", "")
+	flagSet.Var(&c.JobGetter.VarFiles, "var-file", "")
+	flagSet.Var((*flaghelper.StringFlag)(&plugins), "plugin", "")
+	flagSet.Var((*flaghelper.StringFlag)(&disabled), "disable", "")
+	flagSet.StringVar(&failLevel, "fail-level", string(joblint.SeverityWarning), "")
+	flagSet.StringVar(&tmpl, "t", "", "")
+
+	if err := flagSet.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job file
+	args = flagSet.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	minSeverity, err := joblint.ParseSeverity(failLevel)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid -fail-level: %s", err))
+		return 1
+	}
+
+	if err := c.JobGetter.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid job options: %s", err))
+		return 1
+	}
+
+	// Get Job struct from Jobfile
+	job, err := c.JobGetter.Get(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+		return 1
+	}
+
+	rules := joblint.BuiltinRules()
+	for _, path := range plugins {
+		rules = append(rules, joblint.NewPluginRule(path))
+	}
+
+	// Disable the rules, erroring on unknown rules to catch typos
+	for _, name := range disabled {
+		found := false
+		for i, rule := range rules {
+			if rule.Name() == name {
+				rules = append(rules[:i], rules[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.Ui.Error(fmt.Sprintf("Unknown rule %q", name))
+			return 1
+		}
+	}
+
+	findings, err := joblint.Lint(job, rules)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error linting job: %s", err))
+		return 1
+	}
+
+	failed := false
+	for _, f := range findings {
+		if f.Severity.AtLeast(minSeverity) {
+			failed = true
+		}
+	}
+
+	if c.formatRequested(false, tmpl) {
+		// Always output an array, so that no findings is an empty array
+		if findings == nil {
+			findings = []*joblint.Finding{}
+		}
+		out, err := c.formatData(false, tmpl, findings)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+	} else {
+		c.Ui.Output(c.formatFindings(findings))
+	}
+
+	if failed {
+		return 2
+	}
+	return 0
+}
+
+// formatFindings formats the findings as a table followed by their count by
+// severity.
+func (c *JobLintCommand) formatFindings(findings []*joblint.Finding) string {
+	if len(findings) == 0 {
+		return c.Colorize().Color("[bold][green]No findings[reset]")
+	}
+
+	counts := map[joblint.Severity]int{}
+	rows := make([]string, len(findings)+1)
+	rows[0] = "Severity|Rule|Group|Task|Message"
+	for i, f := range findings {
+		counts[f.Severity]++
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s",
+			f.Severity, f.Rule, f.Group, f.Task, f.Message)
+	}
+
+	return fmt.Sprintf("%s\n\n%d findings: %d errors, %d warnings, %d info",
+		formatList(rows), len(findings),
+		counts[joblint.SeverityError], counts[joblint.SeverityWarning], counts[joblint.SeverityInfo])
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/joblint"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobLintCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobLintCommand{}
+}
+
+func TestJobLintCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// The example job has no update stanza nor health checks
+	ui := cli.NewMockUi()
+	cmd := &JobLintCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"testdata/example-basic.nomad"})
+	require.Equal(t, 2, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "update-stanza")
+	require.Contains(t, out, "health-checks")
+	require.Contains(t, out, "2 findings: 0 errors, 2 warnings, 0 info")
+
+	// Findings below the fail level don't fail linting
+	ui = cli.NewMockUi()
+	cmd = &JobLintCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-fail-level=error", "testdata/example-basic.nomad"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	// Disabled rules are not checked
+	ui = cli.NewMockUi()
+	cmd = &JobLintCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-disable=update-stanza", "-disable=health-checks", "testdata/example-basic.nomad"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "No findings")
+
+	// Findings are output as JSON
+	ui = cli.NewMockUi()
+	cmd = &JobLintCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-format=json", "-disable=update-stanza", "testdata/example-basic.nomad"})
+	require.Equal(t, 2, code, ui.ErrorWriter.String())
+	var findings []*joblint.Finding
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &findings))
+	require.Len(t, findings, 1)
+	require.Equal(t, "health-checks", findings[0].Rule)
+	require.Equal(t, "group1", findings[0].Group)
+}
+
+func TestJobLintCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"some", "bad", "args"}, err: commandErrorText(&JobLintCommand{})},
+		{args: []string{"/unicorns/leprechauns"}, err: "Error getting job struct"},
+		{args: []string{"-fail-level=fatal", "testdata/example-basic.nomad"}, err: "Invalid -fail-level"},
+		{args: []string{"-disable=unicorns", "testdata/example-basic.nomad"}, err: `Unknown rule "unicorns"`},
+		{args: []string{"-plugin=/unicorns/leprechauns", "testdata/example-basic.nomad"}, err: "Error linting job"},
+	}
+
+	for _, tc := range cases {
+		ui := cli.NewMockUi()
+		cmd := &JobLintCommand{Meta: Meta{Ui: ui}}
+		require.Equal(t, 1, cmd.Run(tc.args), tc.args)
+		require.Contains(t, ui.ErrorWriter.String(), tc.err)
+	}
+}
@@ -75,6 +75,11 @@ Plan Options:
     Determines whether the diff between the remote job and planned job is shown.
     Defaults to true.
 
+  -explain
+    Explains every placement of the plan: for each node evaluated, the
+    constraint or resource which rejected it, or its score. With -verbose, the
+    breakdown of the scores is shown as well.
+
   -json
     Parses the job file as JSON. If the outer object has a Job field, such as
     from "nomad job inspect" or "nomad run -output", the value of the field is
@@ -111,6 +116,7 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-diff":            complete.PredictNothing,
+			"-explain":         complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
 			"-verbose":         complete.PredictNothing,
 			"-json":            complete.PredictNothing,
@@ -131,11 +137,12 @@ func (c *JobPlanCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *JobPlanCommand) Name() string { return "job plan" }
 func (c *JobPlanCommand) Run(args []string) int {
-	var diff, policyOverride, verbose bool
+	var diff, explain, policyOverride, verbose bool
 
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
 	flagSet.BoolVar(&diff, "diff", true, "")
+	flagSet.BoolVar(&explain, "explain", false, "")
 	flagSet.BoolVar(&policyOverride, "policy-override", false, "")
 	flagSet.BoolVar(&verbose, "verbose", false, "")
 	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
@@ -194,6 +201,9 @@ func (c *JobPlanCommand) Run(args []string) int {
 	if policyOverride {
 		opts.PolicyOverride = true
 	}
+	if explain {
+		opts.Verbose = true
+	}
 
 	if job.IsMultiregion() {
 		return c.multiregionPlan(client, job, opts, diff, verbose)
@@ -277,9 +287,73 @@ func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanRespons
 		c.addPreemptions(resp)
 	}
 
+	// Print the placement explanations if there are any
+	if resp.Annotations != nil && len(resp.Annotations.PlacementExplanations) > 0 {
+		c.addPlacementExplanations(resp, verbose)
+	}
+
 	return getExitCode(resp)
 }
 
+// addPlacementExplanations shows the outcome of every node evaluated for the
+// placements of the plan.
+func (c *JobPlanCommand) addPlacementExplanations(resp *api.JobPlanResponse, verbose bool) {
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	c.Ui.Output(c.Colorize().Color("[bold]Placement Explanations:[reset]"))
+	for _, e := range resp.Annotations.PlacementExplanations {
+		placed := "[red]not placed[reset]"
+		if e.NodeID != "" {
+			placed = fmt.Sprintf("[green]placed on node %q[reset]", limit(e.NodeID, length))
+		}
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("\nAllocation %q (task group %q) %s:", e.AllocName, e.TaskGroup, placed)))
+
+		header := "Node ID|Node Name|Node Class|Result|Score"
+		if verbose {
+			header += "|Scores"
+		}
+		nodes := []string{header}
+		for _, n := range e.Nodes {
+			nodes = append(nodes, formatNodePlacementExplanation(n, e.NodeID, length, verbose))
+		}
+		c.Ui.Output(formatList(nodes))
+	}
+	c.Ui.Output("")
+}
+
+// formatNodePlacementExplanation formats the outcome of a node evaluated for a
+// placement as a list row.
+func formatNodePlacementExplanation(n *api.NodePlacementExplanation, selected string, length int, verbose bool) string {
+	result, score := "feasible", fmt.Sprintf("%.3g", n.NormScore)
+	switch {
+	case n.FilteredBy != "":
+		result, score = fmt.Sprintf("filtered by %s", n.FilteredBy), "-"
+	case n.ExhaustedDimension != "":
+		result, score = fmt.Sprintf("exhausted %s", n.ExhaustedDimension), "-"
+	case n.NodeID == selected:
+		result = "selected"
+	}
+
+	row := fmt.Sprintf("%s|%s|%s|%s|%s", limit(n.NodeID, length), n.NodeName, n.NodeClass, result, score)
+	if verbose {
+		scorers := make([]string, 0, len(n.Scores))
+		for name := range n.Scores {
+			scorers = append(scorers, name)
+		}
+		sort.Strings(scorers)
+
+		scores := make([]string, 0, len(scorers))
+		for _, name := range scorers {
+			scores = append(scores, fmt.Sprintf("%s=%.3g", name, n.Scores[name]))
+		}
+		row += "|" + strings.Join(scores, ", ")
+	}
+	return row
+}
+
 // addPreemptions shows details about preempted allocations
 func (c *JobPlanCommand) addPreemptions(resp *api.JobPlanResponse) {
 	c.Ui.Output(c.Colorize().Color("[bold][yellow]Preemptions:\n[reset]"))
@@ -271,3 +271,47 @@ func TestPlanCommad_JSON(t *testing.T) {
 	require.Equal(t, 255, code)
 	require.Contains(t, ui.ErrorWriter.String(), "Error during plan: Put")
 }
+
+func TestPlanCommand_PlacementExplanations(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobPlanCommand{Meta: Meta{Ui: ui}}
+
+	resp := &api.JobPlanResponse{
+		Annotations: &api.PlanAnnotations{
+			PlacementExplanations: []*api.PlacementExplanation{
+				{
+					TaskGroup: "web",
+					AllocName: "example.web[0]",
+					NodeID:    "5d8a6f3e-5f5e-4c25-9e1f-0b8a6a4f0f71",
+					Nodes: []*api.NodePlacementExplanation{
+						{
+							NodeID:     "0a7d0c3c-7f3a-4a55-8c5e-6f0e5b2c7d10",
+							NodeName:   "windows-1",
+							FilteredBy: "${attr.kernel.name} = linux",
+						},
+						{
+							NodeID:             "3c1f2a0b-2c7e-4b9e-9f1d-7e6a5b4c3d21",
+							NodeName:           "linux-1",
+							ExhaustedDimension: "memory",
+						},
+						{
+							NodeID:    "5d8a6f3e-5f5e-4c25-9e1f-0b8a6a4f0f71",
+							NodeName:  "linux-2",
+							Scores:    map[string]float64{"binpack": 0.5, "job-anti-affinity": 0},
+							NormScore: 0.25,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cmd.addPlacementExplanations(resp, true)
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, `Allocation "example.web[0]" (task group "web") placed on node "5d8a6f3e-5f5e-4c25-9e1f-0b8a6a4f0f71"`)
+	require.Contains(t, out, "filtered by ${attr.kernel.name} = linux")
+	require.Contains(t, out, "exhausted memory")
+	require.Contains(t, out, "selected")
+	require.Contains(t, out, "binpack=0.5, job-anti-affinity=0")
+}
@@ -0,0 +1,153 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type JobRestoreCommand struct {
+	Meta
+}
+
+func (c *JobRestoreCommand) Help() string {
+	helpText := `
+Usage: nomad job restore [options] <job id>
+
+  Restore is used to restore a job purged with "nomad job stop -purge" from
+  the job trash. Purged jobs are kept in the job trash, along with their
+  versions, for the job_trash_window configured on the servers before they
+  are permanently deleted.
+
+  The job is restored stopped, so that it isn't placed again until it is
+  run. The job ID must match exactly, and restoring fails if a job with the
+  same ID was registered since it was purged.
+
+  When ACLs are enabled, restoring a job requires a token with the
+  'submit-job' capability for the job's namespace, and listing the job trash
+  requires a token with the 'list-jobs' capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Restore Options:
+
+  -list
+    List the purged jobs in the job trash instead of restoring a job.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobRestoreCommand) Synopsis() string {
+	return "Restore a purged job from the job trash"
+}
+
+func (c *JobRestoreCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-list": complete.PredictNothing,
+		})
+}
+
+func (c *JobRestoreCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		jobs, _, err := client.Jobs().ListTrash(nil)
+		if err != nil {
+			return []string{}
+		}
+		var matches []string
+		for _, job := range jobs {
+			if strings.HasPrefix(job.ID, a.Last) {
+				matches = append(matches, job.ID)
+			}
+		}
+		return matches
+	})
+}
+
+func (c *JobRestoreCommand) Name() string { return "job restore" }
+
+func (c *JobRestoreCommand) Run(args []string) int {
+	var list bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&list, "list", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument, or none when listing
+	args = flags.Args()
+	if list && len(args) != 0 {
+		c.Ui.Error("This command takes no arguments with -list")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if !list && len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <job id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if list {
+		jobs, _, err := client.Jobs().ListTrash(nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error listing job trash: %s", err))
+			return 1
+		}
+		if len(jobs) == 0 {
+			c.Ui.Output("No purged jobs in the job trash")
+			return 0
+		}
+		c.Ui.Output(formatTrashedJobs(jobs, c.allNamespaces()))
+		return 0
+	}
+
+	jobID := strings.TrimSpace(args[0])
+	resp, _, err := client.Jobs().Restore(jobID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error restoring job: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf(
+		"Job %q restored stopped at index %d, run it again to place it", jobID, resp.JobModifyIndex))
+	return 0
+}
+
+// formatTrashedJobs returns the purged jobs in the job trash as a table.
+func formatTrashedJobs(jobs []*api.TrashedJobListStub, allNamespaces bool) string {
+	out := make([]string, 0, len(jobs)+1)
+	if allNamespaces {
+		out = append(out, "ID|Namespace|Type|Version|Versions|Expires At")
+	} else {
+		out = append(out, "ID|Type|Version|Versions|Expires At")
+	}
+	for _, job := range jobs {
+		if allNamespaces {
+			out = append(out, fmt.Sprintf("%s|%s|%s|%d|%d|%s",
+				job.ID, job.Namespace, job.Type, job.Version, job.Versions, formatTime(job.ExpiresAt)))
+		} else {
+			out = append(out, fmt.Sprintf("%s|%s|%d|%d|%s",
+				job.ID, job.Type, job.Version, job.Versions, formatTime(job.ExpiresAt)))
+		}
+	}
+	return formatList(out)
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRestoreCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobRestoreCommand{}
+}
+
+func TestJobRestoreCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobRestoreCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-list", "job"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope", "12"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error restoring job")
+}
+
+func TestJobRestoreCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, func(c *agent.Config) {
+		c.Server.JobTrashWindow = "1h"
+	})
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+	j := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, j))
+
+	_, _, err := client.Jobs().Deregister(j.ID, true, nil)
+	require.NoError(t, err)
+
+	// The purged job is listed in the job trash
+	ui := cli.NewMockUi()
+	cmd := &JobRestoreCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-list"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), j.ID)
+
+	ui = cli.NewMockUi()
+	cmd = &JobRestoreCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, j.ID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "restored stopped")
+
+	out, err := state.JobByID(nil, j.Namespace, j.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.True(t, out.Stop)
+
+	// The job is no longer in the job trash
+	ui = cli.NewMockUi()
+	cmd = &JobRestoreCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-list"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "No purged jobs")
+
+	ui = cli.NewMockUi()
+	cmd = &JobRestoreCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, j.ID})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "not found in the job trash")
+}
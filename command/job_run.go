@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/posener/complete"
 )
 
@@ -78,7 +79,9 @@ Run Options:
     zero is passed, the job is only registered if it does not yet exist. If a
     non-zero value is passed, it ensures that the job is being updated from a
     known state. The use of this flag is most common in conjunction with plan
-    command.
+    command. If not set, the index of the job currently registered is used, so
+    that concurrent modifications of the job are detected. The check is
+    skipped if the token can't read the job.
 
   -detach
     Return immediately instead of entering monitor mode. After job submission,
@@ -97,6 +100,11 @@ Run Options:
   -hcl1
     Parses the job file as HCLv1.
 
+  -idempotency-token
+    If set, the job is only registered once for the given token. Retrying the
+    submission with the same token returns the result of the original
+    registration instead of registering the job again.
+
   -hcl2-strict
     Whether an error should be produced from the HCL2 parser where a variable
     has been supplied which is not defined within the root variables. Defaults
@@ -153,21 +161,22 @@ func (c *JobRunCommand) Synopsis() string {
 func (c *JobRunCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-check-index":     complete.PredictNothing,
-			"-detach":          complete.PredictNothing,
-			"-verbose":         complete.PredictNothing,
-			"-consul-token":    complete.PredictNothing,
-			"-vault-token":     complete.PredictAnything,
-			"-vault-namespace": complete.PredictAnything,
-			"-output":          complete.PredictNothing,
-			"-policy-override": complete.PredictNothing,
-			"-preserve-counts": complete.PredictNothing,
-			"-json":            complete.PredictNothing,
-			"-hcl1":            complete.PredictNothing,
-			"-hcl2-strict":     complete.PredictNothing,
-			"-var":             complete.PredictAnything,
-			"-var-file":        complete.PredictFiles("*.var"),
-			"-eval-priority":   complete.PredictNothing,
+			"-check-index":       complete.PredictNothing,
+			"-idempotency-token": complete.PredictAnything,
+			"-detach":            complete.PredictNothing,
+			"-verbose":           complete.PredictNothing,
+			"-consul-token":      complete.PredictNothing,
+			"-vault-token":       complete.PredictAnything,
+			"-vault-namespace":   complete.PredictAnything,
+			"-output":            complete.PredictNothing,
+			"-policy-override":   complete.PredictNothing,
+			"-preserve-counts":   complete.PredictNothing,
+			"-json":              complete.PredictNothing,
+			"-hcl1":              complete.PredictNothing,
+			"-hcl2-strict":       complete.PredictNothing,
+			"-var":               complete.PredictAnything,
+			"-var-file":          complete.PredictFiles("*.var"),
+			"-eval-priority":     complete.PredictNothing,
 		})
 }
 
@@ -183,7 +192,7 @@ func (c *JobRunCommand) Name() string { return "job run" }
 
 func (c *JobRunCommand) Run(args []string) int {
 	var detach, verbose, output, override, preserveCounts bool
-	var checkIndexStr, consulToken, consulNamespace, vaultToken, vaultNamespace string
+	var checkIndexStr, consulToken, consulNamespace, vaultToken, vaultNamespace, idempotencyToken string
 	var evalPriority int
 
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
@@ -197,6 +206,7 @@ func (c *JobRunCommand) Run(args []string) int {
 	flagSet.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
 	flagSet.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
 	flagSet.StringVar(&checkIndexStr, "check-index", "", "")
+	flagSet.StringVar(&idempotencyToken, "idempotency-token", "", "")
 	flagSet.StringVar(&consulToken, "consul-token", "", "")
 	flagSet.StringVar(&consulNamespace, "consul-namespace", "", "")
 	flagSet.StringVar(&vaultToken, "vault-token", "", "")
@@ -314,13 +324,24 @@ func (c *JobRunCommand) Run(args []string) int {
 		PreserveCounts: preserveCounts,
 		EvalPriority:   evalPriority,
 	}
+	if !enforce {
+		// Default to the index of the registered job, so the job isn't
+		// updated if it has been modified since it was read. Tokens that may
+		// submit but not read the job register it without the check.
+		checkIndex, enforce, err = currentJobModifyIndex(client, job)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error submitting job: %s", err))
+			return 1
+		}
+	}
 	if enforce {
 		opts.EnforceIndex = true
 		opts.ModifyIndex = checkIndex
 	}
 
 	// Submit the job
-	resp, _, err := client.Jobs().RegisterOpts(job, opts, nil)
+	wq := &api.WriteOptions{IdempotencyToken: idempotencyToken}
+	resp, _, err := client.Jobs().RegisterOpts(job, opts, wq)
 	if err != nil {
 		if strings.Contains(err.Error(), api.RegisterEnforceIndexErrPrefix) {
 			// Format the error specially if the error is due to index
@@ -373,6 +394,27 @@ func (c *JobRunCommand) Run(args []string) int {
 
 }
 
+// currentJobModifyIndex returns the job modify index of the registered
+// version of the job, or zero if the job does not exist yet. The returned bool
+// is false if the token is not permitted to read the job, in which case the
+// index can't be enforced.
+func currentJobModifyIndex(client *api.Client, job *api.Job) (uint64, bool, error) {
+	if job.ID == nil {
+		return 0, false, nil
+	}
+	existing, _, err := client.Jobs().Info(*job.ID, nil)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "404"):
+			return 0, true, nil
+		case structs.IsErrPermissionDenied(err):
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return *existing.JobModifyIndex, true, nil
+}
+
 // parseCheckIndex parses the check-index flag and returns the index, whether it
 // was set and potentially an error during parsing.
 func parseCheckIndex(input string) (uint64, bool, error) {
@@ -10,7 +10,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
@@ -279,3 +283,44 @@ func TestRunCommand_JSON(t *testing.T) {
 	require.Empty(t, stderr)
 	require.NotEmpty(t, stdout)
 }
+
+func TestRunCommand_SubmitOnlyToken(t *testing.T) {
+	ci.Parallel(t)
+	srv, client, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Register the job using the management token so that it already exists.
+	client.SetSecretID(srv.RootToken.SecretID)
+	_, _, err := client.Jobs().Register(testJob("run_cmd_submit_only"), nil)
+	require.NoError(t, err)
+
+	// Create a token that may submit the job but not read it.
+	token := mock.CreatePolicyAndToken(t, srv.Agent.Server().State(), 1001, "submit",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+
+	jobFile := filepath.Join(t.TempDir(), "job.nomad")
+	require.NoError(t, os.WriteFile(jobFile, []byte(`
+job "run_cmd_submit_only" {
+  type        = "batch"
+  datacenters = ["dc1"]
+  group "group1" {
+    task "task1" {
+      driver = "mock_driver"
+      config {
+        run_for = "5s"
+      }
+    }
+  }
+}`), 0o640))
+
+	ui := cli.NewMockUi()
+	cmd := &JobRunCommand{Meta: Meta{Ui: ui}}
+
+	// The job modify index can't be looked up, so the job is registered
+	// without the check.
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-detach", jobFile})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Evaluation ID:")
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 )
@@ -46,6 +48,11 @@ Scale Options:
     the evaluation ID will be printed to the screen, which can be used to
     examine the evaluation using the eval-status command.
 
+  -idempotency-token
+    If set, the job is only scaled once for the given token. Retrying the
+    scaling request with the same token returns the result of the original
+    request.
+
   -verbose
     Display full information.
 `
@@ -60,8 +67,9 @@ func (j *JobScaleCommand) Synopsis() string {
 func (j *JobScaleCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(j.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-detach":  complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
+			"-detach":            complete.PredictNothing,
+			"-idempotency-token": complete.PredictAnything,
+			"-verbose":           complete.PredictNothing,
 		})
 }
 
@@ -71,11 +79,13 @@ func (j *JobScaleCommand) Name() string { return "job scale" }
 // Run satisfies the cli.Command Run function.
 func (j *JobScaleCommand) Run(args []string) int {
 	var detach, verbose bool
+	var idempotencyToken string
 
 	flags := j.Meta.FlagSet(j.Name(), FlagSetClient)
 	flags.Usage = func() { j.Ui.Output(j.Help()) }
 	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.StringVar(&idempotencyToken, "idempotency-token", "", "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -126,8 +136,32 @@ func (j *JobScaleCommand) Run(args []string) int {
 	// This is our default message added to scaling submissions.
 	msg := "submitted using the Nomad CLI"
 
+	req := &api.ScalingRequest{
+		Count: helper.Int64ToPtr(int64(count)),
+		Target: map[string]string{
+			"Job":   jobString,
+			"Group": groupString,
+		},
+		Message: msg,
+	}
+
+	// The scale status reports the modify index of the job object, so lookup
+	// the job modify index to ensure the job hasn't been modified since its
+	// status was read. Tokens limited to scaling the job may not read it, in
+	// which case the request is submitted without the check.
+	info, _, err := client.Jobs().Info(jobString, nil)
+	switch {
+	case err == nil:
+		req.EnforceIndex = true
+		req.JobModifyIndex = *info.JobModifyIndex
+	case !structs.IsErrPermissionDenied(err):
+		j.Ui.Error(fmt.Sprintf("Error querying job: %v", err))
+		return 1
+	}
+
 	// Perform the scaling action.
-	resp, _, err := client.Jobs().Scale(jobString, groupString, &count, msg, false, nil, nil)
+	wq := &api.WriteOptions{IdempotencyToken: idempotencyToken}
+	resp, _, err := client.Jobs().ScaleWithRequest(jobString, req, wq)
 	if err != nil {
 		j.Ui.Error(fmt.Sprintf("Error submitting scaling request: %s", err))
 		return 1
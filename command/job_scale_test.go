@@ -5,11 +5,16 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJobScaleCommand_SingleGroup(t *testing.T) {
@@ -122,3 +127,32 @@ func TestJobScaleCommand_MultiGroup(t *testing.T) {
 		t.Fatalf("Expected Evaluation ID within output: %v", out)
 	}
 }
+
+func TestJobScaleCommand_ScaleOnlyToken(t *testing.T) {
+	ci.Parallel(t)
+	srv, client, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Register a test job using the management token.
+	client.SetSecretID(srv.RootToken.SecretID)
+	_, _, err := client.Jobs().Register(testJob("scale_cmd_scale_only"), nil)
+	require.NoError(t, err)
+
+	// Create a token that may scale the job but not read it.
+	token := mock.CreatePolicyAndToken(t, srv.Agent.Server().State(), 1001, "scale",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{
+			acl.NamespaceCapabilityScaleJob,
+			acl.NamespaceCapabilityReadJobScaling,
+		}))
+
+	ui := cli.NewMockUi()
+	cmd := &JobScaleCommand{Meta: Meta{Ui: ui}}
+
+	// The job modify index can't be looked up, so the job is scaled without
+	// the check.
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-detach", "scale_cmd_scale_only", "2"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Evaluation ID:")
+}
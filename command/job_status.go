@@ -55,6 +55,12 @@ Status Options:
 
   -verbose
     Display full information.
+
+  -json
+    Output the job status in a JSON format.
+
+  -t
+    Format and display the job status using a Go template.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -70,6 +76,8 @@ func (c *JobStatusCommand) AutocompleteFlags() complete.Flags {
 			"-evals":      complete.PredictNothing,
 			"-short":      complete.PredictNothing,
 			"-verbose":    complete.PredictNothing,
+			"-json":       complete.PredictNothing,
+			"-t":          complete.PredictAnything,
 		})
 }
 
@@ -91,7 +99,8 @@ func (c *JobStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobStatusCommand) Name() string { return "status" }
 
 func (c *JobStatusCommand) Run(args []string) int {
-	var short bool
+	var short, json bool
+	var tmpl string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -99,6 +108,8 @@ func (c *JobStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.evals, "evals", false, "")
 	flags.BoolVar(&c.allAllocs, "all-allocs", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -136,6 +147,17 @@ func (c *JobStatusCommand) Run(args []string) int {
 			return 1
 		}
 
+		if c.formatRequested(json, tmpl) {
+			out, err := c.formatData(json, tmpl, jobs)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+
+			c.Ui.Output(out)
+			return 0
+		}
+
 		if len(jobs) == 0 {
 			// No output if we have no jobs
 			c.Ui.Output("No running jobs")
@@ -172,6 +194,23 @@ func (c *JobStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.formatRequested(json, tmpl) {
+		status, err := c.jobStatus(client, job)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		out, err := c.formatData(json, tmpl, status)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
 	periodic := job.IsPeriodic()
 	parameterized := job.IsParameterized()
 
@@ -193,6 +232,10 @@ func (c *JobStatusCommand) Run(args []string) int {
 		basic = append(basic, fmt.Sprintf("Idempotency Token|%v", *job.DispatchIdempotencyToken))
 	}
 
+	if job.Frozen != nil && *job.Frozen {
+		basic = append(basic, "Frozen|true")
+	}
+
 	if periodic && !parameterized {
 		if *job.Stop {
 			basic = append(basic, "Next Periodic Launch|none (job stopped)")
@@ -324,6 +367,45 @@ func (c *JobStatusCommand) outputParameterizedInfo(client *api.Client, job *api.
 	return nil
 }
 
+// jobStatus is the status of a job output by the -json and -t flags.
+type jobStatus struct {
+	Job              *api.Job
+	Summary          *api.JobSummary
+	Allocations      []*api.AllocationListStub
+	LatestDeployment *api.Deployment
+}
+
+// jobStatus queries the status of the passed job. If a request fails, an
+// error is returned.
+func (c *JobStatusCommand) jobStatus(client *api.Client, job *api.Job) (*jobStatus, error) {
+	var q *api.QueryOptions
+	if job.Namespace != nil {
+		q = &api.QueryOptions{Namespace: *job.Namespace}
+	}
+
+	summary, _, err := client.Jobs().Summary(*job.ID, q)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying job summary: %s", err)
+	}
+
+	jobAllocs, _, err := client.Jobs().Allocations(*job.ID, c.allAllocs, q)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying job allocations: %s", err)
+	}
+
+	latestDeployment, _, err := client.Jobs().LatestDeployment(*job.ID, q)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying latest job deployment: %s", err)
+	}
+
+	return &jobStatus{
+		Job:              job,
+		Summary:          summary,
+		Allocations:      jobAllocs,
+		LatestDeployment: latestDeployment,
+	}, nil
+}
+
 // outputJobInfo prints information about the passed non-periodic job. If a
 // request fails, an error is returned.
 func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error {
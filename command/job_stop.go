@@ -47,6 +47,10 @@ Stop Options:
     Stop a multi-region job in all its regions. By default job stop will stop
     only a single region at a time. Ignored for single-region jobs.
 
+  -idempotency-token
+    If set, the job is only stopped once for the given token. Retrying the
+    stop with the same token returns the result of the original request.
+
   -no-shutdown-delay
 	Ignore the the group and task shutdown_delay configuration so that there is no
     delay between service deregistration and task shutdown. Note that using
@@ -77,6 +81,7 @@ func (c *JobStopCommand) AutocompleteFlags() complete.Flags {
 			"-eval-priority":     complete.PredictNothing,
 			"-purge":             complete.PredictNothing,
 			"-global":            complete.PredictNothing,
+			"-idempotency-token": complete.PredictAnything,
 			"-no-shutdown-delay": complete.PredictNothing,
 			"-yes":               complete.PredictNothing,
 			"-verbose":           complete.PredictNothing,
@@ -103,6 +108,7 @@ func (c *JobStopCommand) Name() string { return "job stop" }
 func (c *JobStopCommand) Run(args []string) int {
 	var detach, purge, verbose, global, autoYes, noShutdownDelay bool
 	var evalPriority int
+	var idempotencyToken string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -113,6 +119,7 @@ func (c *JobStopCommand) Run(args []string) int {
 	flags.BoolVar(&autoYes, "yes", false, "")
 	flags.BoolVar(&purge, "purge", false, "")
 	flags.IntVar(&evalPriority, "eval-priority", 0, "")
+	flags.StringVar(&idempotencyToken, "idempotency-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -206,9 +213,16 @@ func (c *JobStopCommand) Run(args []string) int {
 		}
 	}
 
-	// Invoke the stop
-	opts := &api.DeregisterOptions{Purge: purge, Global: global, EvalPriority: evalPriority, NoShutdownDelay: noShutdownDelay}
-	wq := &api.WriteOptions{Namespace: jobs[0].JobSummary.Namespace}
+	// Invoke the stop, ensuring the job hasn't been modified since it was read
+	opts := &api.DeregisterOptions{
+		Purge:           purge,
+		Global:          global,
+		EvalPriority:    evalPriority,
+		NoShutdownDelay: noShutdownDelay,
+		EnforceIndex:    true,
+		ModifyIndex:     *job.JobModifyIndex,
+	}
+	wq := &api.WriteOptions{Namespace: jobs[0].JobSummary.Namespace, IdempotencyToken: idempotencyToken}
 	evalID, _, err := client.Jobs().DeregisterOpts(*job.ID, opts, wq)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error deregistering job: %s", err))
@@ -52,6 +52,9 @@ type Meta struct {
 	// token is used for ACLs to access privileged information
 	token string
 
+	// format is the output format of commands, see formatData
+	format string
+
 	caCert        string
 	caPath        string
 	clientCert    string
@@ -83,7 +86,7 @@ func (m *Meta) FlagSet(n string, fs FlagSetFlags) *flag.FlagSet {
 		f.StringVar(&m.tlsServerName, "tls-server-name", "", "")
 		f.BoolVar(&m.insecure, "tls-skip-verify", false, "")
 		f.StringVar(&m.token, "token", "", "")
-
+		f.StringVar(&m.format, "format", "", "")
 	}
 
 	f.SetOutput(&uiErrorWriter{ui: m.Ui})
@@ -111,6 +114,7 @@ func (m *Meta) AutocompleteFlags(fs FlagSetFlags) complete.Flags {
 		"-tls-server-name": complete.PredictNothing,
 		"-tls-skip-verify": complete.PredictNothing,
 		"-token":           complete.PredictAnything,
+		"-format":          complete.PredictSet(formatTable, formatJSON, formatYAML, formatTemplate),
 	}
 }
 
@@ -292,6 +296,11 @@ func generalOptionsUsage(usageOpts usageOptsFlags) string {
   -token
     The SecretID of an ACL token to use to authenticate API requests with.
     Overrides the NOMAD_TOKEN environment variable if set.
+
+  -format=<format>
+    The output format of commands which output data: "table", the default,
+    "json", "yaml", or "template" to format it with the template given with
+    the -t flag of the command.
 `
 
 	if usageOpts&usageOptsNoNamespace == 0 {
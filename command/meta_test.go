@@ -39,6 +39,7 @@ func TestMeta_FlagSet(t *testing.T) {
 				"tls-server-name",
 				"tls-skip-verify",
 				"token",
+				"format",
 			},
 		},
 	}
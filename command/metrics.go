@@ -28,7 +28,8 @@ Metrics Specific Options
     Pretty prints the JSON output
 
   -format <format>
-    Specify output format (prometheus)
+    Specify output format. In addition to the general output formats, the
+    metrics can be output in the "prometheus" format.
 
   -json
     Output the allocation in its JSON format.
@@ -49,7 +50,7 @@ func (c *OperatorMetricsCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-pretty": complete.PredictAnything,
-			"-format": complete.PredictAnything,
+			"-format": complete.PredictSet(formatTable, formatJSON, formatYAML, formatTemplate, "prometheus"),
 			"-json":   complete.PredictNothing,
 			"-t":      complete.PredictAnything,
 		})
@@ -59,12 +60,11 @@ func (c *OperatorMetricsCommand) Name() string { return "metrics" }
 
 func (c *OperatorMetricsCommand) Run(args []string) int {
 	var pretty, json bool
-	var format, tmpl string
+	var tmpl string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&pretty, "pretty", false, "")
-	flags.StringVar(&format, "format", "", "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 
@@ -92,22 +92,24 @@ func (c *OperatorMetricsCommand) Run(args []string) int {
 		params["pretty"] = "1"
 	}
 
-	if len(format) > 0 {
-		params["format"] = format
+	// The prometheus format is formatted by the agent rather than the CLI
+	if c.format == "prometheus" {
+		params["format"] = c.format
+		c.format = ""
 	}
 
 	query := &api.QueryOptions{
 		Params: params,
 	}
 
-	if json || len(tmpl) > 0 {
+	if c.formatRequested(json, tmpl) {
 		metrics, _, err := client.Operator().MetricsSummary(query)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying metrics: %v", err))
 			return 1
 		}
 
-		out, err := Format(json, tmpl, metrics)
+		out, err := c.formatData(json, tmpl, metrics)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -89,7 +89,9 @@ func (c *NamespaceInspectCommand) Run(args []string) int {
 		return 1
 	}
 
-	out, err := Format(len(tmpl) == 0, tmpl, ns)
+	// The JSON format is the default output of the command
+	json := len(tmpl) == 0 && !c.formatRequested(false, "")
+	out, err := c.formatData(json, tmpl, ns)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
@@ -90,8 +90,8 @@ func (c *NamespaceListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, namespaces)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, namespaces)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -24,13 +24,26 @@ Usage: nomad namespace status [options] <namespace>
 
 General Options:
 
-  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Status Options:
+
+  -json
+    Output the namespace in a JSON format.
+
+  -t
+    Format and display the namespace using a Go template.
+`
 
 	return strings.TrimSpace(helpText)
 }
 
 func (c *NamespaceStatusCommand) AutocompleteFlags() complete.Flags {
-	return c.Meta.AutocompleteFlags(FlagSetClient)
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
 }
 
 func (c *NamespaceStatusCommand) AutocompleteArgs() complete.Predictor {
@@ -44,8 +57,13 @@ func (c *NamespaceStatusCommand) Synopsis() string {
 func (c *NamespaceStatusCommand) Name() string { return "namespace status" }
 
 func (c *NamespaceStatusCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -80,6 +98,17 @@ func (c *NamespaceStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, ns)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
 	c.Ui.Output(formatNamespaceBasics(ns))
 
 	if len(ns.Meta) > 0 {
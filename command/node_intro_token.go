@@ -0,0 +1,40 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type NodeIntroTokenCommand struct {
+	Meta
+}
+
+func (f *NodeIntroTokenCommand) Help() string {
+	helpText := `
+Usage: nomad node intro-token <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with node introduction
+  tokens. When servers require node introduction, clients must present a
+  single-use introduction token to register for the first time, preventing
+  arbitrary hosts from joining the cluster as clients.
+
+  Create a node introduction token valid for an hour:
+
+      $ nomad node intro-token create -ttl 1h
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (f *NodeIntroTokenCommand) Synopsis() string {
+	return "Interact with node introduction tokens"
+}
+
+func (f *NodeIntroTokenCommand) Name() string { return "node intro-token" }
+
+func (f *NodeIntroTokenCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/posener/complete"
+)
+
+type NodeIntroTokenCreateCommand struct {
+	Meta
+}
+
+func (c *NodeIntroTokenCreateCommand) Help() string {
+	helpText := `
+Usage: nomad node intro-token create [options]
+
+  Create is used to issue a single-use node introduction token. A client uses
+  the token to register for the first time when servers require node
+  introduction, by setting the token as the intro_token of its client
+  configuration. The token is consumed by the registration, so a token must be
+  created for each new client.
+
+  When ACLs are enabled, this command requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Create Options:
+
+  -ttl=<duration>
+    Sets how long the token can be used for. Defaults to 15 minutes.
+
+  -json
+    Output the token in its JSON format.
+
+  -t
+    Format and display the token using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeIntroTokenCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-ttl":  complete.PredictAnything,
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *NodeIntroTokenCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeIntroTokenCreateCommand) Synopsis() string {
+	return "Create a node introduction token"
+}
+
+func (c *NodeIntroTokenCreateCommand) Name() string { return "node intro-token create" }
+
+func (c *NodeIntroTokenCreateCommand) Run(args []string) int {
+	var ttl time.Duration
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.DurationVar(&ttl, "ttl", 0, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if ttl < 0 {
+		c.Ui.Error("The -ttl flag must not be negative")
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	token, _, err := client.Nodes().CreateIntroToken(ttl, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating node introduction token: %s", err))
+		return 1
+	}
+
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, token)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Accessor ID|%s", token.AccessorID),
+		fmt.Sprintf("Secret ID|%s", token.SecretID),
+		fmt.Sprintf("Expires At|%s", token.ExpiresAt.Format(time.RFC3339)),
+	}))
+	return 0
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeIntroTokenCreateCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodeIntroTokenCreateCommand{}
+}
+
+func TestNodeIntroTokenCreateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NodeIntroTokenCreateCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "foo"}))
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-ttl=-1m"}))
+	require.Contains(t, ui.ErrorWriter.String(), "must not be negative")
+	ui.ErrorWriter.Reset()
+
+	code := cmd.Run([]string{"-address=" + url, "-ttl=1h"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "Secret ID")
+	require.Contains(t, out, "Expires At")
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-t={{ .AccessorID }}"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Len(t, ui.OutputWriter.String(), 37)
+}
@@ -214,8 +214,8 @@ func (c *NodeStatusCommand) Run(args []string) int {
 		}
 
 		// If output format is specified, format and output the node data list
-		if c.json || len(c.tmpl) > 0 {
-			out, err := Format(c.json, c.tmpl, nodes)
+		if c.formatRequested(c.json, c.tmpl) {
+			out, err := c.formatData(c.json, c.tmpl, nodes)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -348,8 +348,8 @@ Results have been paginated. To get the next page run:
 	}
 
 	// If output format is specified, format and output the data
-	if c.json || len(c.tmpl) > 0 {
-		out, err := Format(c.json, c.tmpl, node)
+	if c.formatRequested(c.json, c.tmpl) {
+		out, err := c.formatData(c.json, c.tmpl, node)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -533,6 +533,7 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		c.outputNodeNetworkInfo(node)
 		c.outputNodeCSIVolumeInfo(client, node, runningAllocs)
 		c.outputNodeDriverInfo(node)
+		c.outputNodeRestoreReport(node)
 	}
 
 	// Emit node events
@@ -543,6 +544,15 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 	c.Ui.Output(c.Colorize().Color("\n[bold]Allocated Resources[reset]"))
 	c.Ui.Output(formatList(allocatedResources))
 
+	// The headroom is only shown to tokens allowed to read the scheduler
+	// configuration.
+	if schedConfig, _, err := client.Operator().SchedulerGetConfiguration(nil); err == nil && schedConfig.SchedulerConfig != nil {
+		if headroom := getReservedHeadroom(schedConfig.SchedulerConfig.ReservedHeadroom, node); headroom != nil {
+			c.Ui.Output(c.Colorize().Color("\n[bold]Reserved Headroom[reset]"))
+			c.Ui.Output(formatList(headroom))
+		}
+	}
+
 	actualResources, err := getActualResources(client, runningAllocs, node)
 	if err == nil {
 		c.Ui.Output(c.Colorize().Color("\n[bold]Allocation Resource Utilization[reset]"))
@@ -738,6 +748,30 @@ func (c *NodeStatusCommand) outputNodeDriverInfo(node *api.Node) {
 	c.Ui.Output(formatList(nodeDrivers))
 }
 
+func (c *NodeStatusCommand) outputNodeRestoreReport(node *api.Node) {
+	report := node.LastRestore
+	if report == nil {
+		return
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Last Restore"))
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Restored At|%s", formatTime(report.RestoredAt)),
+		fmt.Sprintf("Reattached Tasks|%d", report.Reattached),
+		fmt.Sprintf("Failed Tasks|%d", len(report.Failed)),
+	}))
+
+	if len(report.Failed) == 0 {
+		return
+	}
+	failed := make([]string, 0, len(report.Failed)+1)
+	failed = append(failed, "Alloc ID|Task|Error")
+	for _, f := range report.Failed {
+		failed = append(failed, fmt.Sprintf("%s|%s|%s", limit(f.AllocID, c.length), f.TaskName, f.Error))
+	}
+	c.Ui.Output(formatList(failed))
+}
+
 func (c *NodeStatusCommand) outputNodeStatusEvents(node *api.Node) {
 	c.Ui.Output(c.Colorize().Color("\n[bold]Node Events"))
 	c.outputNodeEvent(node.Events)
@@ -933,6 +967,25 @@ func getAllocatedResources(client *api.Client, runningAllocs []*api.Allocation,
 	return resources
 }
 
+// getReservedHeadroom returns the resources of the node kept free for system
+// and high priority jobs, or nil if the node has no headroom.
+func getReservedHeadroom(headroom api.ReservedHeadroomConfig, node *api.Node) []string {
+	pct := headroom.NodePercent(node)
+	if pct <= 0 {
+		return nil
+	}
+	total := computeNodeTotalResources(node)
+
+	resources := make([]string, 2)
+	resources[0] = "Percent|CPU|Memory"
+	resources[1] = fmt.Sprintf("%d%%|%d MHz|%s",
+		pct,
+		*total.CPU*pct/100,
+		humanize.IBytes(uint64(*total.MemoryMB*pct/100*bytesPerMegabyte)))
+
+	return resources
+}
+
 // computeNodeTotalResources returns the total allocatable resources (resources
 // minus reserved)
 func computeNodeTotalResources(node *api.Node) api.Resources {
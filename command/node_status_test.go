@@ -294,3 +294,30 @@ func TestNodeStatusCommand_FormatDrain(t *testing.T) {
 	node.DrainStrategy.IgnoreSystemJobs = true
 	assert.Equal("true; 1970-01-01T00:00:01Z deadline; ignoring system jobs", formatDrain(node))
 }
+
+func TestNodeStatusCommand_RestoreReport(t *testing.T) {
+	ci.Parallel(t)
+	assert := assert.New(t)
+
+	ui := cli.NewMockUi()
+	cmd := &NodeStatusCommand{Meta: Meta{Ui: ui}, length: shortId}
+
+	// No output without a report
+	cmd.outputNodeRestoreReport(&api.Node{})
+	assert.Empty(ui.OutputWriter.String())
+
+	cmd.outputNodeRestoreReport(&api.Node{
+		LastRestore: &api.NodeRestoreReport{
+			RestoredAt: time.Unix(1, 0).UTC(),
+			Reattached: 3,
+			Failed: []*api.NodeTaskRestoreFailure{
+				{AllocID: "0d793b92-3c27-08c0-775a-b529dc60e3b8", TaskName: "web", Error: "task not found"},
+			},
+		},
+	})
+	out := ui.OutputWriter.String()
+	assert.Contains(out, "Last Restore")
+	assert.Contains(out, "Reattached Tasks = 3")
+	assert.Contains(out, "Failed Tasks     = 1")
+	assert.Contains(out, "0d793b92  web   task not found")
+}
@@ -12,7 +12,11 @@ type OperatorAutopilotGetCommand struct {
 }
 
 func (c *OperatorAutopilotGetCommand) AutocompleteFlags() complete.Flags {
-	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient))
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
 }
 
 func (c *OperatorAutopilotGetCommand) AutocompleteArgs() complete.Predictor {
@@ -21,8 +25,13 @@ func (c *OperatorAutopilotGetCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *OperatorAutopilotGetCommand) Name() string { return "operator autopilot get-config" }
 func (c *OperatorAutopilotGetCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
 	flags := c.Meta.FlagSet("autopilot", FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
@@ -42,6 +51,18 @@ func (c *OperatorAutopilotGetCommand) Run(args []string) int {
 		c.Ui.Error(fmt.Sprintf("Error querying Autopilot configuration: %s", err))
 		return 1
 	}
+
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, config)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
 	c.Ui.Output(fmt.Sprintf("CleanupDeadServers = %v", config.CleanupDeadServers))
 	c.Ui.Output(fmt.Sprintf("LastContactThreshold = %v", config.LastContactThreshold.String()))
 	c.Ui.Output(fmt.Sprintf("MaxTrailingLogs = %v", config.MaxTrailingLogs))
@@ -69,7 +90,16 @@ Usage: nomad operator autopilot get-config [options]
 
 General Options:
 
-  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Get Config Options:
+
+  -json
+    Output the Autopilot configuration in a JSON format.
+
+  -t
+    Format and display the Autopilot configuration using a Go template.
+`
 
 	return strings.TrimSpace(helpText)
 }
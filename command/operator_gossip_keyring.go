@@ -44,6 +44,10 @@ Usage: nomad operator gossip keyring [options]
 
       $ nomad operator gossip keyring use <key>
 
+  Rotate the encryption key of all servers:
+
+      $ nomad operator gossip keyring rotate
+
   Please see individual subcommand help for detailed usage information.
 
 General Options:
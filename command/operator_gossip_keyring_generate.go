@@ -34,17 +34,26 @@ func (c *OperatorGossipKeyringGenerateCommand) Name() string {
 }
 
 func (c *OperatorGossipKeyringGenerateCommand) Run(_ []string) int {
+	key, err := generateGossipKey()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	c.Ui.Output(key)
+	return 0
+}
+
+// generateGossipKey returns a new base64-encoded 32-byte gossip encryption
+// key.
+func generateGossipKey() (string, error) {
 	key := make([]byte, 32)
 	n, err := rand.Reader.Read(key)
 	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error reading random data: %s", err))
-		return 1
+		return "", fmt.Errorf("Error reading random data: %s", err)
 	}
 	if n != 32 {
-		c.Ui.Error("Couldn't read enough entropy. Generate more entropy!")
-		return 1
+		return "", fmt.Errorf("Couldn't read enough entropy. Generate more entropy!")
 	}
-
-	c.Ui.Output(base64.StdEncoding.EncodeToString(key))
-	return 0
+	return base64.StdEncoding.EncodeToString(key), nil
 }
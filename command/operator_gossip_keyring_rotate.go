@@ -0,0 +1,172 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+// OperatorGossipKeyringRotateCommand is a Command implementation that
+// replaces the gossip encryption key of every server in the cluster.
+type OperatorGossipKeyringRotateCommand struct {
+	Meta
+}
+
+func (c *OperatorGossipKeyringRotateCommand) Help() string {
+	helpText := `
+Usage: nomad operator gossip keyring rotate [options] [<key>]
+
+  Rotate the gossip encryption key of the cluster. If no key is given, a new
+  key is generated. The rotation is performed in steps, and the state of the
+  keyring on every server is checked before moving on to the next step:
+
+    1. Install the new key on all servers.
+    2. Verify that every server has the new key.
+    3. Change the primary key of all servers to the new key.
+    4. Verify that every server uses the new key as its primary key.
+    5. Remove all other keys from the keyring.
+
+  If any step fails, the rotation stops and the keyring is left in its
+  current state for the operator to inspect with the list command.
+
+  This command can only be run against server nodes. If ACLs are enabled,
+  this command requires a token with the 'agent:write' capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorGossipKeyringRotateCommand) Synopsis() string {
+	return "Rotate the gossip encryption key"
+}
+
+func (c *OperatorGossipKeyringRotateCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorGossipKeyringRotateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *OperatorGossipKeyringRotateCommand) Name() string {
+	return "operator gossip keyring rotate"
+}
+
+func (c *OperatorGossipKeyringRotateCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet("operator-gossip-keyring-rotate", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	c.Ui = &cli.PrefixedUi{
+		OutputPrefix: "",
+		InfoPrefix:   "==> ",
+		ErrorPrefix:  "",
+		Ui:           c.Ui,
+	}
+
+	args = flags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("This command takes at most one argument: [<key>]")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var newKey string
+	if len(args) == 1 {
+		newKey = args[0]
+	} else {
+		key, err := generateGossipKey()
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		newKey = key
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating nomad cli client: %s", err))
+		return 1
+	}
+	agent := client.Agent()
+
+	c.Ui.Output("Checking current gossip keyring...")
+	resp, err := agent.ListKeys()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+	if err := checkKeyringConsistent(resp); err != nil {
+		c.Ui.Error(fmt.Sprintf("Keyring is not in a consistent state: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("Installing new gossip encryption key...")
+	if _, err := agent.InstallKey(newKey); err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+	if resp, err = agent.ListKeys(); err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+	if n := resp.Keys[newKey]; n != resp.NumNodes {
+		c.Ui.Error(fmt.Sprintf(
+			"New key installed on %d of %d servers; rotation stopped", n, resp.NumNodes))
+		return 1
+	}
+
+	c.Ui.Output("Changing primary gossip encryption key...")
+	if _, err := agent.UseKey(newKey); err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+	if resp, err = agent.ListKeys(); err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+	if n := resp.PrimaryKeys[newKey]; n != resp.NumNodes {
+		c.Ui.Error(fmt.Sprintf(
+			"New key is primary on %d of %d servers; rotation stopped", n, resp.NumNodes))
+		return 1
+	}
+
+	for key := range resp.Keys {
+		if key == newKey {
+			continue
+		}
+		c.Ui.Output("Removing old gossip encryption key...")
+		if _, err := agent.RemoveKey(key); err != nil {
+			c.Ui.Error(fmt.Sprintf("error: %s", err))
+			return 1
+		}
+	}
+
+	c.Ui.Output(fmt.Sprintf("Gossip encryption key rotated to %s", newKey))
+	return 0
+}
+
+// checkKeyringConsistent returns an error if the servers in the keyring
+// response do not agree on the set of installed keys and the primary key.
+func checkKeyringConsistent(resp *api.KeyringResponse) error {
+	for node, msg := range resp.Messages {
+		return fmt.Errorf("server %s reported: %s", node, msg)
+	}
+	for key, n := range resp.Keys {
+		if n != resp.NumNodes {
+			return fmt.Errorf("key %s is installed on %d of %d servers", key, n, resp.NumNodes)
+		}
+	}
+	if len(resp.PrimaryKeys) > 1 {
+		return fmt.Errorf("servers are using %d different primary keys", len(resp.PrimaryKeys))
+	}
+	return nil
+}
@@ -4,8 +4,12 @@ import (
 	"encoding/base64"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/testutil"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGossipKeyringGenerateCommand(t *testing.T) {
@@ -28,3 +32,55 @@ func TestGossipKeyringGenerateCommand(t *testing.T) {
 		t.Fatalf("bad: %#v", result)
 	}
 }
+
+func TestGossipKeyringRotateCommand(t *testing.T) {
+	ci.Parallel(t)
+
+	oldKey, err := generateGossipKey()
+	require.NoError(t, err)
+	newKey, err := generateGossipKey()
+	require.NoError(t, err)
+
+	srv, client, url := testServer(t, false, func(c *agent.Config) {
+		c.Server.EncryptKey = oldKey
+	})
+	defer srv.Shutdown()
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorGossipKeyringRotateCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, newKey})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	resp, err := client.Agent().ListKeys()
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{newKey: resp.NumNodes}, resp.Keys)
+	require.Equal(t, map[string]int{newKey: resp.NumNodes}, resp.PrimaryKeys)
+}
+
+func TestGossipKeyringRotateCommand_checkKeyringConsistent(t *testing.T) {
+	ci.Parallel(t)
+
+	require.NoError(t, checkKeyringConsistent(&api.KeyringResponse{
+		Keys:        map[string]int{"a": 3, "b": 3},
+		PrimaryKeys: map[string]int{"a": 3},
+		NumNodes:    3,
+	}))
+
+	require.Error(t, checkKeyringConsistent(&api.KeyringResponse{
+		Keys:        map[string]int{"a": 3, "b": 2},
+		PrimaryKeys: map[string]int{"a": 3},
+		NumNodes:    3,
+	}))
+
+	require.Error(t, checkKeyringConsistent(&api.KeyringResponse{
+		Keys:        map[string]int{"a": 3, "b": 3},
+		PrimaryKeys: map[string]int{"a": 2, "b": 1},
+		NumNodes:    3,
+	}))
+
+	require.Error(t, checkKeyringConsistent(&api.KeyringResponse{
+		Messages: map[string]string{"server1": "timeout"},
+		NumNodes: 3,
+	}))
+}
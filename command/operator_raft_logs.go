@@ -1,15 +1,23 @@
 package command
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/hashicorp/nomad/helper/raftutil"
 	"github.com/posener/complete"
 )
 
+// raftLogsFollowInterval is the interval between two reads of the raft logs
+// when following them.
+const raftLogsFollowInterval = time.Second
+
 type OperatorRaftLogsCommand struct {
 	Meta
 }
@@ -23,7 +31,8 @@ Usage: nomad operator raft logs <path to nomad data dir>
 
   This command requires file system permissions to access the data directory on
   disk. The Nomad server locks access to the data directory, so this command
-  cannot be run on a data directory that is being used by a running Nomad server.
+  cannot be run on a data directory that is being used by a running Nomad server,
+  unless the -follow flag is passed.
 
   This is a low-level debugging tool and not subject to Nomad's usual backward
   compatibility guarantees.
@@ -33,12 +42,21 @@ Raft Logs Options:
   -pretty
     By default this command outputs newline delimited JSON. If the -pretty flag
     is passed, each entry will be pretty-printed.
+
+  -follow
+    Display the log entries as they are appended, until interrupted. The data
+    directory may be in use by a running Nomad server, as the raft logs are
+    copied to a temporary file whenever they change and read from the copy,
+    which needs as much disk space as the raft logs.
 `
 	return strings.TrimSpace(helpText)
 }
 
 func (c *OperatorRaftLogsCommand) AutocompleteFlags() complete.Flags {
-	return complete.Flags{}
+	return complete.Flags{
+		"-pretty": complete.PredictNothing,
+		"-follow": complete.PredictNothing,
+	}
 }
 
 func (c *OperatorRaftLogsCommand) AutocompleteArgs() complete.Predictor {
@@ -53,10 +71,11 @@ func (c *OperatorRaftLogsCommand) Name() string { return "operator raft logs" }
 
 func (c *OperatorRaftLogsCommand) Run(args []string) int {
 
-	var pretty bool
+	var pretty, follow bool
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
 	flagSet.BoolVar(&pretty, "pretty", false, "")
+	flagSet.BoolVar(&follow, "follow", false, "")
 
 	if err := flagSet.Parse(args); err != nil {
 		return 1
@@ -80,6 +99,10 @@ func (c *OperatorRaftLogsCommand) Run(args []string) int {
 		enc.SetIndent("", "  ")
 	}
 
+	if follow {
+		return c.follow(raftPath, enc)
+	}
+
 	logChan, warningsChan, err := raftutil.LogEntries(raftPath)
 	if err != nil {
 		c.Ui.Error(err.Error())
@@ -112,3 +135,44 @@ DONE:
 
 	return 0
 }
+
+// follow displays the log entries of the raft logs at raftPath as they are
+// appended, until interrupted.
+func (c *OperatorRaftLogsCommand) follow(raftPath string, enc *json.Encoder) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+	go func() {
+		select {
+		case <-signalCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	logChan, warningsChan, err := raftutil.TailLogEntries(ctx, raftPath, raftLogsFollowInterval)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Warnings are written to stderr as they happen, so they don't end up
+	// mixed into the JSON stream on stdout
+	for {
+		select {
+		case log, ok := <-logChan:
+			if !ok {
+				return 0
+			}
+			if err := enc.Encode(log); err != nil {
+				c.Ui.Error(fmt.Sprintf("failed to encode output: %v", err))
+				return 1
+			}
+		case warning := <-warningsChan:
+			c.Ui.Error(warning.Error())
+		}
+	}
+}
@@ -2,8 +2,11 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 )
@@ -61,8 +64,8 @@ func (o *OperatorSchedulerGetConfig) Run(args []string) int {
 	// If the user has specified to output the scheduler config as JSON or
 	// using a template, perform this action for the entire object and exit the
 	// command.
-	if o.json || len(o.tmpl) > 0 {
-		out, err := Format(o.json, o.tmpl, resp)
+	if o.formatRequested(o.json, o.tmpl) {
+		out, err := o.formatData(o.json, o.tmpl, resp)
 		if err != nil {
 			o.Ui.Error(err.Error())
 			return 1
@@ -79,6 +82,10 @@ func (o *OperatorSchedulerGetConfig) Run(args []string) int {
 		fmt.Sprintf("Memory Oversubscription|%v", schedConfig.MemoryOversubscriptionEnabled),
 		fmt.Sprintf("Reject Job Registration|%v", schedConfig.RejectJobRegistration),
 		fmt.Sprintf("Pause Eval Broker|%v", schedConfig.PauseEvalBroker),
+		fmt.Sprintf("Pause Scheduling|%s", formatSchedulingPause(schedConfig)),
+		fmt.Sprintf("Reserved Headroom|%s", formatReservedHeadroom(schedConfig.ReservedHeadroom)),
+		fmt.Sprintf("Reserved Headroom Priority|%v", schedConfig.ReservedHeadroom.PriorityThreshold),
+		fmt.Sprintf("Eval Fairness|%s", formatEvalFairness(schedConfig.EvalFairness)),
 		fmt.Sprintf("Preemption System Scheduler|%v", schedConfig.PreemptionConfig.SystemSchedulerEnabled),
 		fmt.Sprintf("Preemption Service Scheduler|%v", schedConfig.PreemptionConfig.ServiceSchedulerEnabled),
 		fmt.Sprintf("Preemption Batch Scheduler|%v", schedConfig.PreemptionConfig.BatchSchedulerEnabled),
@@ -116,3 +123,51 @@ Scheduler Get Config Options:
 
 	return strings.TrimSpace(helpText)
 }
+
+// formatReservedHeadroom returns the reserved headroom percentage followed by
+// the overrides of the node classes, if any.
+func formatReservedHeadroom(headroom api.ReservedHeadroomConfig) string {
+	out := fmt.Sprintf("%d%%", headroom.Percent)
+	if len(headroom.NodeClassPercent) == 0 {
+		return out
+	}
+
+	classes := make([]string, 0, len(headroom.NodeClassPercent))
+	for class, pct := range headroom.NodeClassPercent {
+		classes = append(classes, fmt.Sprintf("%s=%d%%", class, pct))
+	}
+	sort.Strings(classes)
+	return fmt.Sprintf("%s (%s)", out, strings.Join(classes, ", "))
+}
+
+// formatEvalFairness returns whether namespace fairness is enabled followed by
+// the namespace shares, if any.
+func formatEvalFairness(fairness api.EvalFairnessConfig) string {
+	out := fmt.Sprintf("%v", fairness.Enabled)
+	if len(fairness.NamespaceShares) == 0 {
+		return out
+	}
+
+	shares := make([]string, 0, len(fairness.NamespaceShares))
+	for ns, share := range fairness.NamespaceShares {
+		shares = append(shares, fmt.Sprintf("%s=%d", ns, share))
+	}
+	sort.Strings(shares)
+	return fmt.Sprintf("%s (%s)", out, strings.Join(shares, ", "))
+}
+
+// formatSchedulingPause returns a human readable description of the
+// scheduling pause state of the scheduler configuration.
+func formatSchedulingPause(config *api.SchedulerConfiguration) string {
+	if !config.PauseScheduling {
+		return "false"
+	}
+	if config.PauseSchedulingUntil == 0 {
+		return "true"
+	}
+	until := time.Unix(0, config.PauseSchedulingUntil)
+	if time.Now().After(until) {
+		return fmt.Sprintf("expired at %s", formatTime(until))
+	}
+	return fmt.Sprintf("true (until %s)", formatTime(until))
+}
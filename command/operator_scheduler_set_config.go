@@ -1,8 +1,10 @@
 package command
 
 import (
+	"flag"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	flagHelper "github.com/hashicorp/nomad/helper/flags"
@@ -24,6 +26,11 @@ type OperatorSchedulerSetConfig struct {
 	memoryOversubscription   flagHelper.BoolValue
 	rejectJobRegistration    flagHelper.BoolValue
 	pauseEvalBroker          flagHelper.BoolValue
+	pauseScheduling          flagHelper.BoolValue
+	pauseSchedulingDuration  time.Duration
+	headroomPercent          int
+	headroomPriority         int
+	evalFairness             flagHelper.BoolValue
 	preemptBatchScheduler    flagHelper.BoolValue
 	preemptServiceScheduler  flagHelper.BoolValue
 	preemptSysBatchScheduler flagHelper.BoolValue
@@ -41,6 +48,11 @@ func (o *OperatorSchedulerSetConfig) AutocompleteFlags() complete.Flags {
 			"-memory-oversubscription":    complete.PredictSet("true", "false"),
 			"-reject-job-registration":    complete.PredictSet("true", "false"),
 			"-pause-eval-broker":          complete.PredictSet("true", "false"),
+			"-pause-scheduling":           complete.PredictSet("true", "false"),
+			"-pause-scheduling-duration":  complete.PredictAnything,
+			"-reserved-headroom-percent":  complete.PredictAnything,
+			"-reserved-headroom-priority": complete.PredictAnything,
+			"-eval-fairness":              complete.PredictSet("true", "false"),
 			"-preempt-batch-scheduler":    complete.PredictSet("true", "false"),
 			"-preempt-service-scheduler":  complete.PredictSet("true", "false"),
 			"-preempt-sysbatch-scheduler": complete.PredictSet("true", "false"),
@@ -65,6 +77,11 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	flags.Var(&o.memoryOversubscription, "memory-oversubscription", "")
 	flags.Var(&o.rejectJobRegistration, "reject-job-registration", "")
 	flags.Var(&o.pauseEvalBroker, "pause-eval-broker", "")
+	flags.Var(&o.pauseScheduling, "pause-scheduling", "")
+	flags.DurationVar(&o.pauseSchedulingDuration, "pause-scheduling-duration", 0, "")
+	flags.IntVar(&o.headroomPercent, "reserved-headroom-percent", 0, "")
+	flags.IntVar(&o.headroomPriority, "reserved-headroom-priority", 0, "")
+	flags.Var(&o.evalFairness, "eval-fairness", "")
 	flags.Var(&o.preemptBatchScheduler, "preempt-batch-scheduler", "")
 	flags.Var(&o.preemptServiceScheduler, "preempt-service-scheduler", "")
 	flags.Var(&o.preemptSysBatchScheduler, "preempt-sysbatch-scheduler", "")
@@ -127,6 +144,26 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	o.memoryOversubscription.Merge(&schedulerConfig.MemoryOversubscriptionEnabled)
 	o.rejectJobRegistration.Merge(&schedulerConfig.RejectJobRegistration)
 	o.pauseEvalBroker.Merge(&schedulerConfig.PauseEvalBroker)
+	o.evalFairness.Merge(&schedulerConfig.EvalFairness.Enabled)
+
+	// Setting either pause scheduling flag starts a new pause, so clear the
+	// expiry of any current pause to have the servers compute it again.
+	var pauseSchedulingSet bool
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "pause-scheduling", "pause-scheduling-duration":
+			pauseSchedulingSet = true
+		case "reserved-headroom-percent":
+			schedulerConfig.ReservedHeadroom.Percent = o.headroomPercent
+		case "reserved-headroom-priority":
+			schedulerConfig.ReservedHeadroom.PriorityThreshold = o.headroomPriority
+		}
+	})
+	if pauseSchedulingSet {
+		o.pauseScheduling.Merge(&schedulerConfig.PauseScheduling)
+		schedulerConfig.PauseSchedulingDuration = o.pauseSchedulingDuration
+		schedulerConfig.PauseSchedulingUntil = 0
+	}
 	o.preemptBatchScheduler.Merge(&schedulerConfig.PreemptionConfig.BatchSchedulerEnabled)
 	o.preemptServiceScheduler.Merge(&schedulerConfig.PreemptionConfig.ServiceSchedulerEnabled)
 	o.preemptSysBatchScheduler.Merge(&schedulerConfig.PreemptionConfig.SysBatchSchedulerEnabled)
@@ -190,6 +227,32 @@ Scheduler Set Config Options:
     When set to true, the eval broker which usually runs on the leader will be
     disabled. This will prevent the scheduler workers from receiving new work.
 
+  -pause-scheduling=[true|false]
+    When set to true, the scheduler workers stop processing evaluations
+    cluster-wide. New evaluations are still created and queued, and are
+    processed once scheduling is resumed. This can be used to stabilize the
+    cluster during major incidents or upgrades.
+
+  -pause-scheduling-duration=<duration>
+    Specifies the duration after which a scheduling pause set with
+    -pause-scheduling expires and scheduling resumes automatically. If not
+    set, scheduling stays paused until -pause-scheduling=false is set.
+
+  -reserved-headroom-percent=<percent>
+    Specifies the percentage of the CPU and memory of every node that
+    placements of service and batch jobs may not use, so that system and high
+    priority jobs can still be placed on busy nodes. Per node class overrides
+    can only be set through the API. Set to 0 to disable the headroom.
+
+  -reserved-headroom-priority=<priority>
+    Specifies the job priority at or above which placements may use the
+    reserved headroom. System and sysbatch jobs may always use it.
+
+  -eval-fairness=[true|false]
+    Specifies whether the eval broker interleaves the pending evaluations of
+    namespaces proportionally to their shares instead of dequeuing them
+    strictly by priority. Namespace shares can only be set through the API.
+
   -preempt-batch-scheduler=[true|false]
     Specifies whether preemption for batch jobs is enabled. Note that if this
     is set to true, then batch jobs can preempt any other jobs.
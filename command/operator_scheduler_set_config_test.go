@@ -3,6 +3,7 @@ package command
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
@@ -42,6 +43,8 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 		"-address=" + addr,
 		"-scheduler-algorithm=spread",
 		"-pause-eval-broker=true",
+		"-pause-scheduling=true",
+		"-pause-scheduling-duration=1h",
 		"-memory-oversubscription=true",
 		"-reject-job-registration=true",
 		"-preempt-batch-scheduler=true",
@@ -66,7 +69,10 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 		MemoryOversubscriptionEnabled: true,
 		RejectJobRegistration:         true,
 		PauseEvalBroker:               true,
+		PauseScheduling:               true,
+		PauseSchedulingDuration:       time.Hour,
 	}, modifiedConfig.SchedulerConfig)
+	require.NotZero(t, modifiedConfig.SchedulerConfig.PauseSchedulingUntil)
 
 	ui.ErrorWriter.Reset()
 	ui.OutputWriter.Reset()
@@ -104,5 +110,7 @@ func schedulerConfigEquals(t *testing.T, expected, actual *api.SchedulerConfigur
 	require.Equal(t, expected.RejectJobRegistration, actual.RejectJobRegistration)
 	require.Equal(t, expected.MemoryOversubscriptionEnabled, actual.MemoryOversubscriptionEnabled)
 	require.Equal(t, expected.PauseEvalBroker, actual.PauseEvalBroker)
+	require.Equal(t, expected.PauseScheduling, actual.PauseScheduling)
+	require.Equal(t, expected.PauseSchedulingDuration, actual.PauseSchedulingDuration)
 	require.Equal(t, expected.PreemptionConfig, actual.PreemptionConfig)
 }
@@ -29,6 +29,14 @@ Usage: nomad operator snapshot <subcommand> [options]
 
       $ nomad operator snapshot inspect backup.snap
 
+  Compare the state of two snapshots:
+
+      $ nomad operator snapshot diff before.snap after.snap
+
+  Redact the secrets of a snapshot before sharing it:
+
+      $ nomad operator snapshot redact backup.snap redacted.snap
+
   Run a daemon process that locally saves a snapshot every hour (available only in
   Nomad Enterprise) :
 
@@ -0,0 +1,145 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/posener/complete"
+)
+
+type OperatorSnapshotDiffCommand struct {
+	Meta
+}
+
+func (c *OperatorSnapshotDiffCommand) Help() string {
+	helpText := `
+Usage: nomad operator snapshot diff [options] <file1> <file2>
+
+  Displays the differences between the jobs, allocations, nodes and ACL
+  objects of two snapshot files on disk, such as to find how the state of
+  servers diverged.
+
+  To compare the files "before.snap" and "after.snap":
+
+    $ nomad operator snapshot diff before.snap after.snap
+
+Snapshot Diff Options:
+
+  -json
+    Output the differences in their JSON format.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSnapshotDiffCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-json": complete.PredictNothing,
+	}
+}
+
+func (c *OperatorSnapshotDiffCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorSnapshotDiffCommand) Synopsis() string {
+	return "Displays the differences between two Nomad snapshot files"
+}
+
+func (c *OperatorSnapshotDiffCommand) Name() string { return "operator snapshot diff" }
+
+func (c *OperatorSnapshotDiffCommand) Run(args []string) int {
+	var jsonOutput bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&jsonOutput, "json", false, "")
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <file1> <file2>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	a, err := os.Open(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 1
+	}
+	defer a.Close()
+
+	b, err := os.Open(args[1])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 1
+	}
+	defer b.Close()
+
+	diff, err := raftutil.DiffSnapshots(a, b)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to compare snapshots: %s", err))
+		return 1
+	}
+
+	if jsonOutput {
+		out, err := json.MarshalIndent(diff, "", "    ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to encode output: %v", err))
+			return 1
+		}
+		c.Ui.Output(string(out))
+		return 0
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Snapshot 1|%s (index %d)", diff.A.ID, diff.A.Index),
+		fmt.Sprintf("Snapshot 2|%s (index %d)", diff.B.ID, diff.B.Index),
+	}))
+
+	if diff.Empty() {
+		c.Ui.Output("\nNo differences between the snapshots")
+		return 0
+	}
+
+	for _, table := range diff.Tables {
+		if table.Empty() {
+			continue
+		}
+
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("\n[bold]%s[reset]", table.Table)))
+		for _, key := range table.Added {
+			c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[green]+ %s[reset]", key)))
+		}
+		for _, key := range table.Removed {
+			c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[red]- %s[reset]", key)))
+		}
+		for _, obj := range table.Changed {
+			c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[yellow]~ %s[reset]", obj.Key)))
+			for _, field := range obj.Fields {
+				c.Ui.Output(fmt.Sprintf("    %s: %s => %s",
+					field.Path, formatDiffValue(field.A), formatDiffValue(field.B)))
+			}
+		}
+	}
+
+	return 0
+}
+
+// formatDiffValue formats a field value of a snapshot diff.
+func formatDiffValue(v interface{}) string {
+	if v == nil {
+		return "<none>"
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(out)
+}
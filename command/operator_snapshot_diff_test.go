@@ -0,0 +1,48 @@
+package command
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotDiff_Works(t *testing.T) {
+	ci.Parallel(t)
+
+	before := filepath.Join(t.TempDir(), "before.snap")
+	job := mock.Job()
+
+	after := generateSnapshotFile(t, func(srv *agent.TestAgent, client *api.Client, url string) {
+		ui := cli.NewMockUi()
+		cmd := &OperatorSnapshotSaveCommand{Meta: Meta{Ui: ui}}
+		require.Zero(t, cmd.Run([]string{"--address=" + url, before}))
+
+		state := srv.Agent.Server().State()
+		require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+	})
+
+	t.Run("no differences", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &OperatorSnapshotDiffCommand{Meta: Meta{Ui: ui}}
+
+		code := cmd.Run([]string{after, after})
+		require.Zero(t, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "No differences")
+	})
+
+	t.Run("added job", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &OperatorSnapshotDiffCommand{Meta: Meta{Ui: ui}}
+
+		code := cmd.Run([]string{before, after})
+		require.Zero(t, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "+ default/"+job.ID)
+	})
+}
@@ -2,10 +2,14 @@ package command
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/helper/raftutil"
 	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/raft"
 	"github.com/posener/complete"
 )
 
@@ -21,12 +25,38 @@ Usage: nomad operator snapshot inspect [options] <file>
 
   To inspect the file "backup.snap":
     $ nomad operator snapshot inspect backup.snap
+
+  To break the state of the snapshot down by state table:
+    $ nomad operator snapshot inspect -breakdown backup.snap
+
+Snapshot Inspect Options:
+
+  -breakdown
+    Restore the snapshot and display the number of objects of each state
+    table and their approximate size, as well as the largest jobs and
+    allocations. Restoring the snapshot requires memory in proportion to its
+    size.
+
+  -top=<n>
+    The number of largest jobs and allocations to display with -breakdown.
+    Defaults to 10.
+
+  -json
+    Output the snapshot information in its JSON format.
+
+  -t
+    Format and display the snapshot information using a Go template.
 `
 	return strings.TrimSpace(helpText)
 }
 
 func (c *OperatorSnapshotInspectCommand) AutocompleteFlags() complete.Flags {
-	return complete.Flags{}
+	return complete.Flags{
+		"-breakdown": complete.PredictNothing,
+		"-top":       complete.PredictAnything,
+		"-json":      complete.PredictNothing,
+		"-t":         complete.PredictAnything,
+	}
 }
 
 func (c *OperatorSnapshotInspectCommand) AutocompleteArgs() complete.Predictor {
@@ -40,7 +70,24 @@ func (c *OperatorSnapshotInspectCommand) Synopsis() string {
 func (c *OperatorSnapshotInspectCommand) Name() string { return "operator snapshot inspect" }
 
 func (c *OperatorSnapshotInspectCommand) Run(args []string) int {
+	var breakdown, json bool
+	var top int
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&breakdown, "breakdown", false, "")
+	flags.IntVar(&top, "top", 10, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
 	// Check that we either got no filename or exactly one.
+	args = flags.Args()
 	if len(args) != 1 {
 		c.Ui.Error("This command takes one argument: <filename>")
 		c.Ui.Error(commandErrorText(c))
@@ -61,6 +108,55 @@ func (c *OperatorSnapshotInspectCommand) Run(args []string) int {
 		return 1
 	}
 
+	if breakdown {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading snapshot file: %s", err))
+			return 1
+		}
+
+		stats, err := raftutil.InspectArchive(f, top)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error inspecting snapshot: %s", err))
+			return 1
+		}
+
+		if c.formatRequested(json, tmpl) {
+			out, err := c.formatData(json, tmpl, stats)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+
+			c.Ui.Output(out)
+			return 0
+		}
+
+		c.Ui.Output(formatSnapshotMeta(meta))
+		c.Ui.Output(c.Colorize().Color("\n[bold]Tables[reset]"))
+		c.Ui.Output(formatTableStats(stats.Tables))
+		c.Ui.Output(c.Colorize().Color("\n[bold]Largest Jobs[reset]"))
+		c.Ui.Output(formatObjectStats(stats.LargestJobs, "No jobs"))
+		c.Ui.Output(c.Colorize().Color("\n[bold]Largest Allocations[reset]"))
+		c.Ui.Output(formatObjectStats(stats.LargestAllocs, "No allocations"))
+		return 0
+	}
+
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, meta)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatSnapshotMeta(meta))
+	return 0
+}
+
+func formatSnapshotMeta(meta *raft.SnapshotMeta) string {
 	output := []string{
 		fmt.Sprintf("ID|%s", meta.ID),
 		fmt.Sprintf("Size|%d", meta.Size),
@@ -69,6 +165,27 @@ func (c *OperatorSnapshotInspectCommand) Run(args []string) int {
 		fmt.Sprintf("Version|%d", meta.Version),
 	}
 
-	c.Ui.Output(formatList(output))
-	return 0
+	return formatList(output)
+}
+
+func formatTableStats(tables []*raftutil.TableStats) string {
+	rows := make([]string, len(tables)+1)
+	rows[0] = "Table|Count|Size"
+	for i, t := range tables {
+		rows[i+1] = fmt.Sprintf("%s|%d|%s", t.Table, t.Count, humanize.IBytes(uint64(t.Size)))
+	}
+	return formatList(rows)
+}
+
+func formatObjectStats(objs []*raftutil.ObjectStats, empty string) string {
+	if len(objs) == 0 {
+		return empty
+	}
+
+	rows := make([]string, len(objs)+1)
+	rows[0] = "ID|Namespace|Name|Size"
+	for i, o := range objs {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s", o.ID, o.Namespace, o.Name, humanize.IBytes(uint64(o.Size)))
+	}
+	return formatList(rows)
 }
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"path/filepath"
 	"testing"
@@ -8,6 +9,9 @@ import (
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
 )
@@ -35,6 +39,48 @@ func TestOperatorSnapshotInspect_Works(t *testing.T) {
 	}
 }
 
+func TestOperatorSnapshotInspect_Breakdown(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	snapPath := generateSnapshotFile(t, func(srv *agent.TestAgent, client *api.Client, url string) {
+		state := srv.Agent.Server().State()
+		require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+	})
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSnapshotInspectCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-breakdown", snapPath})
+	require.Zero(t, code, ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	for _, key := range []string{
+		"Index",
+		"Tables",
+		"Jobs",
+		"Largest Jobs",
+		job.ID,
+		"Largest Allocations",
+		"No allocations",
+	} {
+		require.Contains(t, output, key)
+	}
+
+	// The breakdown can be output as JSON
+	ui = cli.NewMockUi()
+	cmd = &OperatorSnapshotInspectCommand{Meta: Meta{Ui: ui}}
+
+	code = cmd.Run([]string{"-breakdown", "-json", snapPath})
+	require.Zero(t, code, ui.ErrorWriter.String())
+
+	var stats raftutil.SnapshotStats
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &stats))
+	require.Len(t, stats.LargestJobs, 1)
+	require.Equal(t, job.ID, stats.LargestJobs[0].ID)
+	require.NotEmpty(t, stats.Tables)
+}
+
 func TestOperatorSnapshotInspect_HandlesFailure(t *testing.T) {
 	ci.Parallel(t)
 
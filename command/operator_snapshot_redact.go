@@ -0,0 +1,105 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad"
+	"github.com/posener/complete"
+)
+
+type OperatorSnapshotRedactCommand struct {
+	Meta
+}
+
+func (c *OperatorSnapshotRedactCommand) Help() string {
+	helpText := `
+Usage: nomad operator snapshot redact [options] <input> <output>
+
+  Writes a copy of a snapshot file on disk with its secrets redacted, so that
+  it can be shared without leaking them. The Vault and Consul tokens of jobs,
+  the payloads of secure variables, the secret IDs of ACL tokens and nodes,
+  and the secrets of CSI volumes are redacted.
+
+  To redact the file "backup.snap" into "redacted.snap":
+
+    $ nomad operator snapshot redact backup.snap redacted.snap
+
+Snapshot Redact Options:
+
+  -filter
+    Specifies an expression used to filter the objects written to the
+    redacted snapshot.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSnapshotRedactCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-filter": complete.PredictAnything,
+	}
+}
+
+func (c *OperatorSnapshotRedactCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorSnapshotRedactCommand) Synopsis() string {
+	return "Redacts the secrets of a Nomad snapshot file"
+}
+
+func (c *OperatorSnapshotRedactCommand) Name() string { return "operator snapshot redact" }
+
+func (c *OperatorSnapshotRedactCommand) Run(args []string) int {
+	var filterExpr flaghelper.StringFlag
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Var(&filterExpr, "filter", "")
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	filter, err := nomad.NewRedactingFSMFilter(filterExpr.String())
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid filter expression %q: %s", filterExpr, err))
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <input> <output>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	in, err := os.Open(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 1
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(args[1], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating redacted snapshot file: %s", err))
+		return 1
+	}
+
+	meta, err := raftutil.RewriteArchive(in, out, filter)
+	if closeErr := out.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close file: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(args[1])
+		c.Ui.Error(fmt.Sprintf("Failed to redact snapshot: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Redacted snapshot %s at index %d written to %s", meta.ID, meta.Index, args[1]))
+	return 0
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotRedact_Works(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.VaultToken = "vault-token"
+	snapPath := generateSnapshotFile(t, func(srv *agent.TestAgent, client *api.Client, url string) {
+		state := srv.Agent.Server().State()
+		require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+	})
+	redactedPath := filepath.Join(t.TempDir(), "redacted.snap")
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSnapshotRedactCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{snapPath, redactedPath})
+	require.Zero(t, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Redacted snapshot")
+
+	// The redacted snapshot holds the state without its secrets
+	f, err := os.Open(redactedPath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	state, _, err := raftutil.RestoreFromArchive(f, nil)
+	require.NoError(t, err)
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Empty(t, out.VaultToken)
+
+	// The output file is never overwritten
+	ui = cli.NewMockUi()
+	cmd = &OperatorSnapshotRedactCommand{Meta: Meta{Ui: ui}}
+	require.Equal(t, 1, cmd.Run([]string{snapPath, redactedPath}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error creating redacted snapshot file")
+}
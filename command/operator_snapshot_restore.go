@@ -3,9 +3,14 @@ package command
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/posener/complete"
 )
 
@@ -32,14 +37,40 @@ Usage: nomad operator snapshot restore [options] <file>
 
     $ nomad operator snapshot restore backup.snap
 
+  To restore a snapshot into a new cluster of Nomad servers:
+
+    $ nomad operator snapshot restore -to-empty-cluster -purge-nodes backup.snap
+
 General Options:
 
-  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Snapshot Restore Options:
+
+  -to-empty-cluster
+    Restore the snapshot into a new cluster. The cluster is first checked to
+    have no jobs, nodes, or CSI volumes. The restore is refused if the
+    snapshot has jobs of another region, secure variables, or nodes without
+    -purge-nodes, as those can't be carried over to a new cluster. After the
+    restore, the keyring is rotated and the root keys of the original cluster
+    are removed, as their key material is not part of the snapshot. The
+    objects of the snapshot that refer to external systems such as Vault,
+    Consul, and CSI plugins are reported so they can be reconciled manually.
+
+  -purge-nodes
+    Purge the nodes of the original cluster from the restored state, so that
+    only the clients of the new cluster are registered. Allocations on the
+    purged nodes are marked lost and rescheduled. Requires -to-empty-cluster.
+`
 	return strings.TrimSpace(helpText)
 }
 
 func (c *OperatorSnapshotRestoreCommand) AutocompleteFlags() complete.Flags {
-	return c.Meta.AutocompleteFlags(FlagSetClient)
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-to-empty-cluster": complete.PredictNothing,
+			"-purge-nodes":      complete.PredictNothing,
+		})
 }
 
 func (c *OperatorSnapshotRestoreCommand) AutocompleteArgs() complete.Predictor {
@@ -53,8 +84,12 @@ func (c *OperatorSnapshotRestoreCommand) Synopsis() string {
 func (c *OperatorSnapshotRestoreCommand) Name() string { return "operator snapshot restore" }
 
 func (c *OperatorSnapshotRestoreCommand) Run(args []string) int {
+	var toEmptyCluster, purgeNodes bool
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&toEmptyCluster, "to-empty-cluster", false, "")
+	flags.BoolVar(&purgeNodes, "purge-nodes", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
@@ -69,6 +104,12 @@ func (c *OperatorSnapshotRestoreCommand) Run(args []string) int {
 		return 1
 	}
 
+	if purgeNodes && !toEmptyCluster {
+		c.Ui.Error("The -purge-nodes flag requires -to-empty-cluster")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	snap, err := os.Open(args[0])
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error opening snapshot file: %q", err))
@@ -83,6 +124,51 @@ func (c *OperatorSnapshotRestoreCommand) Run(args []string) int {
 		return 1
 	}
 
+	var snapState *state.StateStore
+	var region string
+	if toEmptyCluster {
+		if err := checkEmptyCluster(client); err != nil {
+			c.Ui.Error(fmt.Sprintf("Cluster is not empty: %v", err))
+			return 1
+		}
+
+		region, err = client.Agent().Region()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying agent region: %v", err))
+			return 1
+		}
+
+		// Read the snapshot locally to find the objects of the original
+		// cluster before anything is restored.
+		localSnap, err := os.Open(args[0])
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error opening snapshot file: %q", err))
+			return 1
+		}
+		defer localSnap.Close()
+
+		snapState, _, err = raftutil.RestoreFromArchiveWithProgress(localSnap, nil, func(objects int) {
+			c.Ui.Output(fmt.Sprintf("Read %d objects from snapshot file", objects))
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read snapshot file: %v", err))
+			return 1
+		}
+
+		problems, err := snapshotRestoreProblems(snapState, region, purgeNodes)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read snapshot state: %v", err))
+			return 1
+		}
+		if len(problems) > 0 {
+			c.Ui.Error("Snapshot cannot be restored into an empty cluster:")
+			for _, problem := range problems {
+				c.Ui.Error(fmt.Sprintf("  * %s", problem))
+			}
+			return 1
+		}
+	}
+
 	// Call snapshot restore API with backup file.
 	_, err = client.Operator().SnapshotRestore(snap, &api.WriteOptions{})
 	if err != nil {
@@ -91,5 +177,240 @@ func (c *OperatorSnapshotRestoreCommand) Run(args []string) int {
 	}
 
 	c.Ui.Output("Snapshot Restored")
+	if !toEmptyCluster {
+		return 0
+	}
+
+	if purgeNodes {
+		purged, err := purgeSnapshotNodes(client, snapState)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to purge nodes: %v", err))
+			return 1
+		}
+		c.Ui.Output(fmt.Sprintf("Purged %d nodes of the original cluster", purged))
+	}
+
+	key, _, err := client.Keyring().Rotate(&api.KeyringRotateOptions{}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to rotate keyring: %v", err))
+		return 1
+	}
+	c.Ui.Output(fmt.Sprintf("Keyring rotated, new active key %q", key.KeyID))
+
+	removed, err := removeSnapshotRootKeys(client, snapState)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to remove root keys: %v", err))
+		return 1
+	}
+	c.Ui.Output(fmt.Sprintf("Removed %d root keys of the original cluster", removed))
+
+	items, err := snapshotReconcileItems(snapState)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read snapshot state: %v", err))
+		return 1
+	}
+	if len(items) == 0 {
+		c.Ui.Output("No objects require manual reconciliation")
+		return 0
+	}
+
+	out := make([]string, 0, len(items)+1)
+	out = append(out, "Kind|ID|Action")
+	for _, item := range items {
+		out = append(out, fmt.Sprintf("%s|%s|%s", item.Kind, item.ID, item.Action))
+	}
+	c.Ui.Output(c.Colorize().Color("\n[bold]Manual Reconciliation Required[reset]"))
+	c.Ui.Output(formatList(out))
 	return 0
 }
+
+// checkEmptyCluster returns an error describing the objects found if the
+// cluster has any jobs, nodes, or CSI volumes.
+func checkEmptyCluster(client *api.Client) error {
+	q := &api.QueryOptions{Namespace: api.AllNamespacesNamespace}
+
+	jobs, _, err := client.Jobs().List(q)
+	if err != nil {
+		return fmt.Errorf("error listing jobs: %v", err)
+	}
+	nodes, _, err := client.Nodes().List(nil)
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+	vols, _, err := client.CSIVolumes().List(q)
+	if err != nil {
+		return fmt.Errorf("error listing CSI volumes: %v", err)
+	}
+
+	if len(jobs) == 0 && len(nodes) == 0 && len(vols) == 0 {
+		return nil
+	}
+	return fmt.Errorf("found %d jobs, %d nodes, and %d CSI volumes", len(jobs), len(nodes), len(vols))
+}
+
+// snapshotRestoreProblems returns the reasons the snapshot state can't be
+// restored into an empty cluster in the given region.
+func snapshotRestoreProblems(snapState *state.StateStore, region string, purgeNodes bool) ([]string, error) {
+	var problems []string
+
+	jobs, err := snapState.Jobs(nil)
+	if err != nil {
+		return nil, err
+	}
+	for raw := jobs.Next(); raw != nil; raw = jobs.Next() {
+		job := raw.(*structs.Job)
+		if job.Region != "" && job.Region != region {
+			problems = append(problems, fmt.Sprintf(
+				"Job %q is registered for region %q. The region of a job can't be changed on restore, restore the snapshot into a cluster in region %q",
+				job.Namespace+"/"+job.ID, job.Region, job.Region))
+		}
+	}
+
+	if n, err := countIter(snapState.SecureVariables(nil)); err != nil {
+		return nil, err
+	} else if n > 0 {
+		problems = append(problems, fmt.Sprintf(
+			"Snapshot has %d secure variables encrypted with the root keys of the original cluster, whose key material is only stored in the keystore of the original servers",
+			n))
+	}
+
+	if !purgeNodes {
+		if n, err := countIter(snapState.Nodes(nil)); err != nil {
+			return nil, err
+		} else if n > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"Snapshot has %d nodes of the original cluster, which can't be mapped to the clients of the new cluster. Use -purge-nodes to purge them and reschedule their allocations",
+				n))
+		}
+	}
+
+	return problems, nil
+}
+
+// removeSnapshotRootKeys deletes the root keys of the snapshot state from the
+// keyring and returns the number of keys removed. The keyring must have been
+// rotated first, as the active key can't be deleted.
+func removeSnapshotRootKeys(client *api.Client, snapState *state.StateStore) (int, error) {
+	iter, err := snapState.RootKeyMetas(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		meta := raw.(*structs.RootKeyMeta)
+		if _, err := client.Keyring().Delete(&api.KeyringDeleteOptions{KeyID: meta.KeyID}, nil); err != nil {
+			return removed, fmt.Errorf("error deleting root key %q: %v", meta.KeyID, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// purgeSnapshotNodes purges the nodes of the snapshot state from the cluster
+// and returns the number of nodes purged.
+func purgeSnapshotNodes(client *api.Client, snapState *state.StateStore) (int, error) {
+	iter, err := snapState.Nodes(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if _, _, err := client.Nodes().Purge(node.ID, nil); err != nil {
+			return purged, fmt.Errorf("error purging node %q: %v", node.ID, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// snapshotReconcileItem is an object of a restored snapshot that refers to an
+// external system and must be reconciled manually.
+type snapshotReconcileItem struct {
+	Kind   string
+	ID     string
+	Action string
+}
+
+// snapshotReconcileItems returns the objects of the snapshot state that must
+// be reconciled manually after restoring it into a new cluster.
+func snapshotReconcileItems(snapState *state.StateStore) ([]*snapshotReconcileItem, error) {
+	var items []*snapshotReconcileItem
+
+	jobs, err := snapState.Jobs(nil)
+	if err != nil {
+		return nil, err
+	}
+	for raw := jobs.Next(); raw != nil; raw = jobs.Next() {
+		job := raw.(*structs.Job)
+		id := job.Namespace + "/" + job.ID
+
+		if len(job.Vault()) != 0 {
+			items = append(items, &snapshotReconcileItem{
+				Kind:   "Vault",
+				ID:     id,
+				Action: "Configure the Vault integration, tokens of the original cluster are not valid",
+			})
+		}
+		for _, tg := range job.TaskGroups {
+			if tg.UsesConnect() {
+				items = append(items, &snapshotReconcileItem{
+					Kind:   "Consul",
+					ID:     id,
+					Action: "Configure the Consul integration, Service Identity tokens of the original cluster are not valid",
+				})
+				break
+			}
+		}
+	}
+
+	vols, err := snapState.CSIVolumes(nil)
+	if err != nil {
+		return nil, err
+	}
+	for raw := vols.Next(); raw != nil; raw = vols.Next() {
+		vol := raw.(*structs.CSIVolume)
+		items = append(items, &snapshotReconcileItem{
+			Kind:   "CSI",
+			ID:     vol.Namespace + "/" + vol.ID,
+			Action: fmt.Sprintf("Run plugin %q and release the claims of the original cluster", vol.PluginID),
+		})
+	}
+
+	if n, err := countIter(snapState.VaultAccessors(nil)); err != nil {
+		return nil, err
+	} else if n > 0 {
+		items = append(items, &snapshotReconcileItem{
+			Kind:   "Vault",
+			ID:     "accessors",
+			Action: fmt.Sprintf("Revoke the %d Vault tokens issued to the original cluster", n),
+		})
+	}
+	if n, err := countIter(snapState.SITokenAccessors(nil)); err != nil {
+		return nil, err
+	} else if n > 0 {
+		items = append(items, &snapshotReconcileItem{
+			Kind:   "Consul",
+			ID:     "accessors",
+			Action: fmt.Sprintf("Revoke the %d Service Identity tokens issued to the original cluster", n),
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Kind < items[j].Kind
+	})
+	return items, nil
+}
+
+func countIter(iter memdb.ResultIterator, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		n++
+	}
+	return n, nil
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
 )
@@ -73,6 +75,134 @@ job "snapshot-test-job" {
 	require.Equal(t, "snapshot-test-job", foundJob.ID)
 }
 
+func TestOperatorSnapshotRestore_ToEmptyCluster(t *testing.T) {
+	ci.Parallel(t)
+
+	tmpDir := t.TempDir()
+
+	snapshotPath := generateSnapshotFile(t, func(srv *agent.TestAgent, client *api.Client, url string) {
+		state := srv.Agent.Server().State()
+
+		node := mock.Node()
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+		job := mock.Job()
+		job.ID = "snapshot-test-job"
+		job.TaskGroups[0].Tasks[0].Vault = &structs.Vault{Policies: []string{"default"}}
+		require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+
+		// Wait for the keyring to be initialized, so the snapshot has the
+		// root key of the original cluster
+		testutil.WaitForResult(func() (bool, error) {
+			keys, _, err := client.Keyring().List(nil)
+			return len(keys) > 0, err
+		}, func(err error) {
+			t.Fatalf("keyring was not initialized: %v", err)
+		})
+	})
+
+	srv, client, url := testServer(t, false, func(c *agent.Config) {
+		c.DevMode = false
+		c.DataDir = filepath.Join(tmpDir, "server1")
+
+		c.AdvertiseAddrs.HTTP = "127.0.0.1"
+		c.AdvertiseAddrs.RPC = "127.0.0.1"
+		c.AdvertiseAddrs.Serf = "127.0.0.1"
+	})
+	defer srv.Shutdown()
+
+	// -purge-nodes requires -to-empty-cluster
+	ui := cli.NewMockUi()
+	cmd := &OperatorSnapshotRestoreCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"--address=" + url, "-purge-nodes", snapshotPath})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "requires -to-empty-cluster")
+
+	// The nodes of the original cluster must be purged
+	ui = cli.NewMockUi()
+	cmd = &OperatorSnapshotRestoreCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"--address=" + url, "-to-empty-cluster", snapshotPath})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Snapshot has 1 nodes of the original cluster")
+	require.NotContains(t, ui.OutputWriter.String(), "Snapshot Restored")
+
+	ui = cli.NewMockUi()
+	cmd = &OperatorSnapshotRestoreCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"--address=" + url, "-to-empty-cluster", "-purge-nodes", snapshotPath})
+	require.Empty(t, ui.ErrorWriter.String())
+	require.Zero(t, code)
+
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "Snapshot Restored")
+	require.Contains(t, out, "Purged 1 nodes of the original cluster")
+	require.Contains(t, out, "Keyring rotated")
+	require.Contains(t, out, "Removed 1 root keys of the original cluster")
+	require.Contains(t, out, "Manual Reconciliation Required")
+	require.Contains(t, out, "Configure the Vault integration")
+
+	nodes, _, err := client.Nodes().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, nodes)
+
+	// Only the key created by the rotation remains
+	keys, _, err := client.Keyring().List(nil)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, api.RootKeyState(api.RootKeyStateActive), keys[0].State)
+
+	// The cluster is no longer empty
+	ui = cli.NewMockUi()
+	cmd = &OperatorSnapshotRestoreCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"--address=" + url, "-to-empty-cluster", snapshotPath})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Cluster is not empty: found 1 jobs")
+}
+
+func TestOperatorSnapshotRestore_ToEmptyCluster_Refused(t *testing.T) {
+	ci.Parallel(t)
+
+	tmpDir := t.TempDir()
+
+	snapshotPath := generateSnapshotFile(t, func(srv *agent.TestAgent, client *api.Client, url string) {
+		state := srv.Agent.Server().State()
+
+		job := mock.Job()
+		job.ID = "snapshot-test-job"
+		job.Region = "east"
+		require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+		sv := mock.SecureVariableEncrypted()
+		require.NoError(t, state.UpsertSecureVariables(structs.MsgTypeTestSetup, 1001,
+			[]*structs.SecureVariableEncrypted{sv}))
+	})
+
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.DevMode = false
+		c.DataDir = filepath.Join(tmpDir, "server1")
+
+		c.AdvertiseAddrs.HTTP = "127.0.0.1"
+		c.AdvertiseAddrs.RPC = "127.0.0.1"
+		c.AdvertiseAddrs.Serf = "127.0.0.1"
+	})
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSnapshotRestoreCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"--address=" + url, "-to-empty-cluster", "-purge-nodes", snapshotPath})
+	require.Equal(t, 1, code)
+
+	errOut := ui.ErrorWriter.String()
+	require.Contains(t, errOut, "Snapshot cannot be restored into an empty cluster")
+	require.Contains(t, errOut, `is registered for region "east"`)
+	require.Contains(t, errOut, "Snapshot has 1 secure variables")
+	require.NotContains(t, ui.OutputWriter.String(), "Snapshot Restored")
+
+	// Nothing was restored
+	j, err := srv.Agent.Server().State().JobByID(nil, structs.DefaultNamespace, "snapshot-test-job")
+	require.NoError(t, err)
+	require.Nil(t, j)
+}
+
 func TestOperatorSnapshotRestore_Fails(t *testing.T) {
 	ci.Parallel(t)
 
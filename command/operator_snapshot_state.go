@@ -3,7 +3,9 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 
 	flaghelper "github.com/hashicorp/nomad/helper/flags"
@@ -31,12 +33,26 @@ Snapshot State Options:
   -filter
     Specifies an expression used to filter query results.
 
+  -export-dir=<path>
+    Streams the state into the given directory instead of displaying it, as
+    one file of newline-delimited JSON per state table. The state is not
+    loaded into memory as a whole, so that large snapshots can be inspected
+    on machines with less memory than the servers.
+
+  -redact
+    Redacts the secrets of the state, such as the Vault and Consul tokens of
+    jobs and the payloads of secure variables, as with the "operator snapshot
+    redact" command.
 `
 	return strings.TrimSpace(helpText)
 }
 
 func (c *OperatorSnapshotStateCommand) AutocompleteFlags() complete.Flags {
-	return complete.Flags{}
+	return complete.Flags{
+		"-filter":     complete.PredictAnything,
+		"-export-dir": complete.PredictDirs("*"),
+		"-redact":     complete.PredictNothing,
+	}
 }
 
 func (c *OperatorSnapshotStateCommand) AutocompleteArgs() complete.Predictor {
@@ -51,17 +67,25 @@ func (c *OperatorSnapshotStateCommand) Name() string { return "operator snapshot
 
 func (c *OperatorSnapshotStateCommand) Run(args []string) int {
 	var filterExpr flaghelper.StringFlag
+	var exportDir string
+	var redact bool
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 
 	flags.Var(&filterExpr, "filter", "")
+	flags.StringVar(&exportDir, "export-dir", "", "")
+	flags.BoolVar(&redact, "redact", false, "")
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
 		return 1
 	}
 
-	filter, err := nomad.NewFSMFilter(filterExpr.String())
+	newFilter := nomad.NewFSMFilter
+	if redact {
+		newFilter = nomad.NewRedactingFSMFilter
+	}
+	filter, err := newFilter(filterExpr.String())
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Invalid filter expression %q: %s", filterExpr, err))
 		return 1
@@ -82,6 +106,10 @@ func (c *OperatorSnapshotStateCommand) Run(args []string) int {
 	}
 	defer f.Close()
 
+	if exportDir != "" {
+		return c.export(f, filter, exportDir)
+	}
+
 	state, meta, err := raftutil.RestoreFromArchive(f, filter)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to read archive file: %s", err))
@@ -100,3 +128,28 @@ func (c *OperatorSnapshotStateCommand) Run(args []string) int {
 
 	return 0
 }
+
+// export streams the state of the snapshot into files in dir.
+func (c *OperatorSnapshotStateCommand) export(f io.Reader, filter *nomad.FSMFilter, dir string) int {
+	meta, counts, err := raftutil.ExportFromArchive(f, filter, dir)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to export archive file: %s", err))
+		return 1
+	}
+
+	tables := make([]string, 0, len(counts))
+	for table := range counts {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	out := make([]string, 0, len(tables)+1)
+	out = append(out, "Table|Objects")
+	for _, table := range tables {
+		out = append(out, fmt.Sprintf("%s|%d", table, counts[table]))
+	}
+
+	c.Ui.Output(fmt.Sprintf("Exported snapshot %s at index %d to %s", meta.ID, meta.Index, dir))
+	c.Ui.Output(formatList(out))
+	return 0
+}
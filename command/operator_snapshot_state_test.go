@@ -0,0 +1,43 @@
+package command
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotState_Export(t *testing.T) {
+	ci.Parallel(t)
+
+	snapPath := generateSnapshotFile(t, nil)
+	exportDir := filepath.Join(t.TempDir(), "export")
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSnapshotStateCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-export-dir", exportDir, snapPath})
+	require.Zero(t, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Indexes")
+
+	// Every line of an exported table is a JSON object
+	f, err := os.Open(filepath.Join(exportDir, "Indexes.ndjson"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var obj map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &obj))
+		require.Contains(t, obj, "Key")
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	require.NotZero(t, lines)
+}
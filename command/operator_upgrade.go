@@ -0,0 +1,38 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorUpgradeCommand struct {
+	Meta
+}
+
+func (c *OperatorUpgradeCommand) Help() string {
+	helpText := `
+Usage: nomad operator upgrade <subcommand> [options]
+
+  This command groups subcommands for preparing the upgrade of a Nomad
+  cluster.
+
+  Check the cluster for issues blocking an upgrade to the version of this
+  binary:
+
+      $ nomad operator upgrade check
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorUpgradeCommand) Synopsis() string {
+	return "Provides tools for upgrading Nomad clusters"
+}
+
+func (c *OperatorUpgradeCommand) Name() string { return "operator upgrade" }
+
+func (c *OperatorUpgradeCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
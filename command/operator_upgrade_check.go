@@ -0,0 +1,360 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/version"
+	"github.com/posener/complete"
+)
+
+const (
+	// upgradeCheckRaftProtocol is the minimum Raft protocol version servers
+	// must use before being upgraded.
+	upgradeCheckRaftProtocol = 3
+
+	// upgradeCheckBlockingExitCode is returned when the check finds issues
+	// blocking the upgrade.
+	upgradeCheckBlockingExitCode = 2
+)
+
+type OperatorUpgradeCheckCommand struct {
+	Meta
+}
+
+func (c *OperatorUpgradeCheckCommand) Help() string {
+	helpText := `
+Usage: nomad operator upgrade check [options]
+
+  Inspects the cluster for issues to resolve before upgrading it to a new
+  version of Nomad. The check reports the version skew between servers and
+  clients, the Raft protocol of the servers, the plugin API versions of the
+  task drivers and the usage of deprecated fields in the registered jobs.
+
+  Blocking issues must be fixed before the upgrade is attempted, while
+  warnings should be reviewed. The command exits with code 2 if blocking
+  issues are found.
+
+  The plugin API versions are checked against the versions supported by the
+  binary running the command.
+
+  If ACLs are enabled, this command requires a token with the 'node:read',
+  'agent:read' and 'operator:read' capabilities, and the 'read-job' capability
+  in every namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Upgrade Check Options:
+
+  -target-version=<version>
+    The version of Nomad the cluster will be upgraded to. Defaults to the
+    version of the binary running the command.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorUpgradeCheckCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-target-version": complete.PredictAnything,
+		})
+}
+
+func (c *OperatorUpgradeCheckCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorUpgradeCheckCommand) Synopsis() string {
+	return "Check the cluster for issues blocking an upgrade"
+}
+
+func (c *OperatorUpgradeCheckCommand) Name() string { return "operator upgrade check" }
+
+func (c *OperatorUpgradeCheckCommand) Run(args []string) int {
+	var targetVersion string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&targetVersion, "target-version", version.Version, "")
+
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	target, err := goversion.NewVersion(targetVersion)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid target version %q: %v", targetVersion, err))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	members, err := client.Agent().Members()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying servers: %s", err))
+		return 1
+	}
+
+	nodes, _, err := client.Nodes().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying nodes: %s", err))
+		return 1
+	}
+
+	check := newUpgradeCheck(target)
+	check.checkServers(members.Members)
+	check.checkClients(nodes)
+
+	stubs, _, err := client.Jobs().List(&api.QueryOptions{Namespace: api.AllNamespacesNamespace})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying jobs: %s", err))
+		return 1
+	}
+	for _, stub := range stubs {
+		// Dispatched and periodic children share the spec of their parent
+		if stub.ParentID != "" {
+			continue
+		}
+		job, _, err := client.Jobs().Info(stub.ID, &api.QueryOptions{Namespace: stub.Namespace})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying job %q: %s", stub.ID, err))
+			return 1
+		}
+		check.checkJob(job)
+	}
+
+	c.Ui.Output(c.Colorize().Color(check.report()))
+	if len(check.blocking) > 0 {
+		return upgradeCheckBlockingExitCode
+	}
+	return 0
+}
+
+// upgradeCheck collects the issues found while inspecting a cluster before
+// upgrading it to the target version.
+type upgradeCheck struct {
+	target *goversion.Version
+
+	// serverVersions and clientVersions count the agents per version.
+	serverVersions map[string]int
+	clientVersions map[string]int
+
+	// minServer is the oldest version run by a server.
+	minServer *goversion.Version
+
+	blocking []string
+	warnings []string
+}
+
+func newUpgradeCheck(target *goversion.Version) *upgradeCheck {
+	return &upgradeCheck{
+		target:         target.Core(),
+		serverVersions: map[string]int{},
+		clientVersions: map[string]int{},
+	}
+}
+
+func (u *upgradeCheck) block(format string, a ...interface{}) {
+	u.blocking = append(u.blocking, fmt.Sprintf(format, a...))
+}
+
+func (u *upgradeCheck) warn(format string, a ...interface{}) {
+	u.warnings = append(u.warnings, fmt.Sprintf(format, a...))
+}
+
+// checkServers verifies that all the servers are alive and run the same
+// version of Nomad, which isn't newer than the target version, and a
+// supported Raft protocol.
+func (u *upgradeCheck) checkServers(members []*api.AgentMember) {
+	raftVersions := map[string]struct{}{}
+
+	for _, m := range members {
+		if m.Status != "alive" {
+			u.block("Server %q is %s", m.Name, m.Status)
+			continue
+		}
+
+		build := m.Tags["build"]
+		u.serverVersions[build]++
+		v, err := goversion.NewVersion(build)
+		if err != nil {
+			u.warn("Server %q reports an invalid version %q", m.Name, build)
+		} else {
+			v = v.Core()
+			if u.minServer == nil || v.LessThan(u.minServer) {
+				u.minServer = v
+			}
+			if v.GreaterThan(u.target) {
+				u.block("Server %q runs Nomad %s, newer than the target version %s", m.Name, v, u.target)
+			}
+		}
+
+		raftVsn := m.Tags["raft_vsn"]
+		raftVersions[raftVsn] = struct{}{}
+		if p, err := strconv.Atoi(raftVsn); err != nil {
+			u.warn("Server %q reports an invalid Raft protocol %q", m.Name, raftVsn)
+		} else if p < upgradeCheckRaftProtocol {
+			u.block("Server %q uses Raft protocol %d, upgrade the cluster to Raft protocol %d first",
+				m.Name, p, upgradeCheckRaftProtocol)
+		}
+	}
+
+	if len(u.serverVersions) > 1 {
+		u.block("Servers run mixed versions of Nomad (%s), complete the upgrade in progress first",
+			strings.Join(sortedVersions(u.serverVersions), ", "))
+	}
+	if len(raftVersions) > 1 {
+		u.block("Servers use mixed Raft protocols, all servers must use the same protocol")
+	}
+
+	if u.minServer != nil {
+		from, to := u.minServer.Segments(), u.target.Segments()
+		if from[0] != to[0] || to[1]-from[1] > 1 {
+			u.warn("Upgrading from Nomad %s to %s skips versions, review the upgrade guides of every version in between",
+				u.minServer, u.target)
+		}
+	}
+}
+
+// checkClients verifies that no client runs a newer version of Nomad than
+// the servers and that the task drivers use a plugin API supported by this
+// version of Nomad. It must be called after checkServers.
+func (u *upgradeCheck) checkClients(nodes []*api.NodeListStub) {
+	supported := loader.AgentSupportedApiVersions[base.PluginTypeDriver]
+
+	for _, n := range nodes {
+		if n.Status == api.NodeStatusDown {
+			continue
+		}
+
+		u.clientVersions[n.Version]++
+		v, err := goversion.NewVersion(n.Version)
+		if err != nil {
+			u.warn("Client %q reports an invalid version %q", n.Name, n.Version)
+		} else if u.minServer != nil && v.Core().GreaterThan(u.minServer) {
+			u.block("Client %q runs Nomad %s, newer than the servers (%s), servers must be upgraded before clients",
+				n.Name, v.Core(), u.minServer)
+		}
+
+		drivers := make([]string, 0, len(n.Drivers))
+		for name := range n.Drivers {
+			drivers = append(drivers, name)
+		}
+		sort.Strings(drivers)
+
+		for _, name := range drivers {
+			info := n.Drivers[name]
+			if info == nil || !info.Detected {
+				continue
+			}
+			if !info.Healthy {
+				u.warn("Driver %q on client %q is unhealthy: %s", name, n.Name, info.HealthDescription)
+			}
+			apiVersion, ok := info.Attributes[fmt.Sprintf("driver.%s.api_version", name)]
+			if ok && !helper.SliceStringContains(supported, apiVersion) {
+				u.block("Driver %q on client %q uses plugin API %s, which isn't supported by this version of Nomad (%s)",
+					name, n.Name, apiVersion, strings.Join(supported, ", "))
+			}
+		}
+	}
+}
+
+// checkJob reports the deprecated fields set by a job.
+func (u *upgradeCheck) checkJob(job *api.Job) {
+	deprecated := func(format string, a ...interface{}) {
+		u.warn("Job %q in namespace %q: %s", *job.ID, *job.Namespace, fmt.Sprintf(format, a...))
+	}
+
+	for _, tg := range job.TaskGroups {
+		for _, net := range tg.Networks {
+			if net.MBits != nil && *net.MBits > 0 {
+				deprecated("group %q network sets the deprecated mbits field", *tg.Name)
+			}
+		}
+
+		for _, task := range tg.Tasks {
+			if r := task.Resources; r != nil {
+				if len(r.Networks) > 0 {
+					deprecated("task %q sets the deprecated resources network block, use a group network instead", task.Name)
+				}
+				if r.IOPS != nil && *r.IOPS > 0 {
+					deprecated("task %q sets the deprecated resources iops field", task.Name)
+				}
+			}
+			for _, tmpl := range task.Templates {
+				if tmpl.VaultGrace != nil && *tmpl.VaultGrace > 0 {
+					deprecated("task %q template sets the deprecated vault_grace field", task.Name)
+				}
+			}
+		}
+	}
+}
+
+// report formats the versions found and the issues of the check.
+func (u *upgradeCheck) report() string {
+	var out strings.Builder
+	out.WriteString(formatKV([]string{
+		fmt.Sprintf("Target Version|%s", u.target),
+		fmt.Sprintf("Servers|%s", formatVersionCounts(u.serverVersions)),
+		fmt.Sprintf("Clients|%s", formatVersionCounts(u.clientVersions)),
+	}))
+
+	out.WriteString("\n\n[bold]Blocking Issues[reset]\n")
+	if len(u.blocking) == 0 {
+		out.WriteString("No blocking issues found\n")
+	}
+	for _, issue := range u.blocking {
+		out.WriteString(fmt.Sprintf("  * %s\n", issue))
+	}
+
+	out.WriteString("\n[bold]Warnings[reset]\n")
+	if len(u.warnings) == 0 {
+		out.WriteString("No warnings found\n")
+	}
+	for _, issue := range u.warnings {
+		out.WriteString(fmt.Sprintf("  * %s\n", issue))
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// formatVersionCounts formats the number of agents running each version,
+// such as "1.3.1 (2), 1.3.2 (1)".
+func formatVersionCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "<none>"
+	}
+	versions := sortedVersions(counts)
+	for i, v := range versions {
+		versions[i] = fmt.Sprintf("%s (%d)", v, counts[v])
+	}
+	return strings.Join(versions, ", ")
+}
+
+func sortedVersions(counts map[string]int) []string {
+	versions := make([]string, 0, len(counts))
+	for v := range counts {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
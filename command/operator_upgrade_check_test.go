@@ -0,0 +1,170 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorUpgradeCheckCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorUpgradeCheckCommand{}
+}
+
+func testUpgradeCheck(t *testing.T, target string) *upgradeCheck {
+	v, err := goversion.NewVersion(target)
+	require.NoError(t, err)
+	return newUpgradeCheck(v)
+}
+
+func TestOperatorUpgradeCheck_Servers(t *testing.T) {
+	ci.Parallel(t)
+
+	member := func(name, build, raft, status string) *api.AgentMember {
+		return &api.AgentMember{
+			Name:   name,
+			Status: status,
+			Tags:   map[string]string{"build": build, "raft_vsn": raft},
+		}
+	}
+
+	check := testUpgradeCheck(t, "1.4.0")
+	check.checkServers([]*api.AgentMember{
+		member("s1", "1.3.2", "3", "alive"),
+		member("s2", "1.3.2-dev", "3", "alive"),
+	})
+	require.Empty(t, check.warnings)
+	require.Len(t, check.blocking, 1)
+	require.Contains(t, check.blocking[0], "mixed versions")
+	require.Equal(t, "1.3.2", check.minServer.String())
+
+	check = testUpgradeCheck(t, "1.4.0")
+	check.checkServers([]*api.AgentMember{
+		member("s1", "1.2.6", "2", "alive"),
+		member("s2", "1.2.6", "3", "alive"),
+		member("s3", "1.2.6", "3", "failed"),
+	})
+	require.Equal(t, []string{
+		`Server "s1" uses Raft protocol 2, upgrade the cluster to Raft protocol 3 first`,
+		`Server "s3" is failed`,
+		"Servers use mixed Raft protocols, all servers must use the same protocol",
+	}, check.blocking)
+	require.Len(t, check.warnings, 1)
+	require.Contains(t, check.warnings[0], "skips versions")
+
+	check = testUpgradeCheck(t, "1.3.0")
+	check.checkServers([]*api.AgentMember{member("s1", "1.3.2", "3", "alive")})
+	require.Equal(t, []string{
+		`Server "s1" runs Nomad 1.3.2, newer than the target version 1.3.0`,
+	}, check.blocking)
+}
+
+func TestOperatorUpgradeCheck_Clients(t *testing.T) {
+	ci.Parallel(t)
+
+	check := testUpgradeCheck(t, "1.4.0")
+	check.checkServers([]*api.AgentMember{{
+		Name:   "s1",
+		Status: "alive",
+		Tags:   map[string]string{"build": "1.3.2", "raft_vsn": "3"},
+	}})
+	check.checkClients([]*api.NodeListStub{
+		{
+			Name:    "c1",
+			Version: "1.3.1",
+			Status:  api.NodeStatusReady,
+			Drivers: map[string]*api.DriverInfo{
+				"exec": {
+					Detected:   true,
+					Healthy:    true,
+					Attributes: map[string]string{"driver.exec.api_version": "v0.1.0"},
+				},
+				"custom": {
+					Detected:          true,
+					Healthy:           false,
+					HealthDescription: "broken",
+					Attributes:        map[string]string{"driver.custom.api_version": "v0.0.1"},
+				},
+				"qemu": {Detected: false},
+			},
+		},
+		{Name: "c2", Version: "1.4.0", Status: api.NodeStatusReady},
+		{Name: "c3", Version: "1.5.0", Status: api.NodeStatusDown},
+	})
+
+	require.Equal(t, []string{
+		`Driver "custom" on client "c1" uses plugin API v0.0.1, which isn't supported by this version of Nomad (v0.1.0)`,
+		`Client "c2" runs Nomad 1.4.0, newer than the servers (1.3.2), servers must be upgraded before clients`,
+	}, check.blocking)
+	require.Equal(t, []string{
+		`Driver "custom" on client "c1" is unhealthy: broken`,
+	}, check.warnings)
+	require.Equal(t, map[string]int{"1.3.1": 1, "1.4.0": 1}, check.clientVersions)
+}
+
+func TestOperatorUpgradeCheck_Job(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob("job1")
+	job.Namespace = helper.StringToPtr("default")
+	task := job.TaskGroups[0].Tasks[0]
+	task.Resources.IOPS = helper.IntToPtr(10)
+	task.Resources.Networks = []*api.NetworkResource{{MBits: helper.IntToPtr(10)}}
+	task.Templates = []*api.Template{{VaultGrace: helper.TimeToPtr(time.Minute)}}
+	job.TaskGroups[0].Networks = []*api.NetworkResource{{MBits: helper.IntToPtr(10)}}
+
+	check := testUpgradeCheck(t, "1.4.0")
+	check.checkJob(job)
+	require.Empty(t, check.blocking)
+	require.Equal(t, []string{
+		`Job "job1" in namespace "default": group "group1" network sets the deprecated mbits field`,
+		`Job "job1" in namespace "default": task "task1" sets the deprecated resources network block, use a group network instead`,
+		`Job "job1" in namespace "default": task "task1" sets the deprecated resources iops field`,
+		`Job "job1" in namespace "default": task "task1" template sets the deprecated vault_grace field`,
+	}, check.warnings)
+}
+
+func TestOperatorUpgradeCheckCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("client not ready: %v", err)
+	})
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorUpgradeCheckCommand{Meta: Meta{Ui: ui}}
+
+	// Invalid target version
+	code := cmd.Run([]string{"-address=" + url, "-target-version=invalid"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Invalid target version")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "No blocking issues found")
+	require.Contains(t, out, "Servers")
+	ui.OutputWriter.Reset()
+
+	// Servers are newer than the target version
+	code = cmd.Run([]string{"-address=" + url, "-target-version=0.1.0"})
+	require.Equal(t, upgradeCheckBlockingExitCode, code)
+	require.Contains(t, ui.OutputWriter.String(), "newer than the target version")
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func (c *PluginStatusCommand) csiBanner() {
-	if !(c.json || len(c.template) > 0) {
+	if !c.formatRequested(c.json, c.template) {
 		c.Ui.Output(c.Colorize().Color("[bold]Container Storage Interface[reset]"))
 	}
 }
@@ -81,8 +81,8 @@ func (c *PluginStatusCommand) csiFormatPlugins(plugs []*api.CSIPluginListStub) (
 	// Sort the output by quota name
 	sort.Slice(plugs, func(i, j int) bool { return plugs[i].ID < plugs[j].ID })
 
-	if c.json || len(c.template) > 0 {
-		out, err := Format(c.json, c.template, plugs)
+	if c.formatRequested(c.json, c.template) {
+		out, err := c.formatData(c.json, c.template, plugs)
 		if err != nil {
 			return "", fmt.Errorf("format error: %v", err)
 		}
@@ -105,8 +105,8 @@ func (c *PluginStatusCommand) csiFormatPlugins(plugs []*api.CSIPluginListStub) (
 }
 
 func (c *PluginStatusCommand) csiFormatPlugin(plug *api.CSIPlugin) (string, error) {
-	if c.json || len(c.template) > 0 {
-		out, err := Format(c.json, c.template, plug)
+	if c.formatRequested(c.json, c.template) {
+		out, err := c.formatData(c.json, c.template, plug)
 		if err != nil {
 			return "", fmt.Errorf("format error: %v", err)
 		}
@@ -149,6 +149,12 @@ func (c *PluginStatusCommand) csiFormatPlugin(plug *api.CSIPlugin) (string, erro
 
 	}
 
+	history := c.formatHealthHistory(plug.Allocations)
+	if history != "" {
+		full = append(full, c.Colorize().Color("\n[bold]Health History[reset]"))
+		full = append(full, history)
+	}
+
 	// Format the allocs
 	banner := c.Colorize().Color("\n[bold]Allocations[reset]")
 	allocs := formatAllocListStubs(plug.Allocations, c.verbose, c.length)
@@ -157,6 +163,57 @@ func (c *PluginStatusCommand) csiFormatPlugin(plug *api.CSIPlugin) (string, erro
 	return strings.Join(full, "\n"), nil
 }
 
+// formatHealthHistory formats the plugin health task events of the plugin
+// allocations, most recent first.
+func (c *PluginStatusCommand) formatHealthHistory(allocs []*api.AllocationListStub) string {
+	type healthEvent struct {
+		alloc *api.AllocationListStub
+		task  string
+		event *api.TaskEvent
+	}
+
+	history := []healthEvent{}
+	for _, alloc := range allocs {
+		for task, state := range alloc.TaskStates {
+			if state == nil {
+				continue
+			}
+			for _, event := range state.Events {
+				switch event.Type {
+				case api.TaskPluginHealthy, api.TaskPluginUnhealthy, api.TaskRestartSignal:
+					history = append(history, healthEvent{alloc, task, event})
+				}
+			}
+		}
+	}
+
+	if len(history) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].event.Time > history[j].event.Time
+	})
+
+	rows := make([]string, len(history)+1)
+	rows[0] = "Time|Alloc ID|Node ID|Task|Type|Description"
+	for i, h := range history {
+		msg := h.event.DisplayMessage
+		if msg == "" {
+			msg = buildDisplayMessage(h.event)
+		}
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+			formatUnixNanoTime(h.event.Time),
+			limit(h.alloc.ID, c.length),
+			limit(h.alloc.NodeID, c.length),
+			h.task,
+			h.event.Type,
+			msg,
+		)
+	}
+	return formatList(rows)
+}
+
 func (c *PluginStatusCommand) formatControllerCaps(controllers map[string]*api.CSIInfo) string {
 	caps := []string{}
 	for _, controller := range controllers {
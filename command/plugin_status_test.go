@@ -1,9 +1,11 @@
 package command
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/mitchellh/cli"
@@ -64,3 +66,40 @@ func TestPluginStatusCommand_AutocompleteArgs(t *testing.T) {
 	require.Equal(t, 1, len(res))
 	require.Equal(t, plug.ID, res[0])
 }
+
+func TestPluginStatusCommand_FormatHealthHistory(t *testing.T) {
+	ci.Parallel(t)
+
+	cmd := &PluginStatusCommand{Meta: Meta{Ui: cli.NewMockUi()}}
+	cmd.length = shortId
+
+	// No history without plugin health events
+	allocs := []*api.AllocationListStub{{
+		ID:     "11111111-2222-3333-4444-555555555555",
+		NodeID: "66666666-7777-8888-9999-000000000000",
+		TaskStates: map[string]*api.TaskState{
+			"plugin": {Events: []*api.TaskEvent{
+				{Type: api.TaskStarted, Time: 1},
+			}},
+		},
+	}}
+	require.Empty(t, cmd.formatHealthHistory(allocs))
+
+	events := allocs[0].TaskStates["plugin"]
+	events.Events = append(events.Events,
+		&api.TaskEvent{Type: api.TaskPluginHealthy, Time: 2, DisplayMessage: "plugin: foo"},
+		&api.TaskEvent{Type: api.TaskPluginUnhealthy, Time: 3, DisplayMessage: "Error: timeout"},
+		&api.TaskEvent{Type: api.TaskRestartSignal, Time: 4, DisplayMessage: "CSI plugin failed 3 consecutive probes"},
+	)
+
+	out := cmd.formatHealthHistory(allocs)
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[0], "Alloc ID")
+	require.Contains(t, lines[1], api.TaskRestartSignal)
+	require.Contains(t, lines[1], "11111111")
+	require.Contains(t, lines[1], "66666666")
+	require.Contains(t, lines[2], "Error: timeout")
+	require.Contains(t, lines[3], api.TaskPluginHealthy)
+	require.NotContains(t, out, api.TaskStarted)
+}
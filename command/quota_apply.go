@@ -198,6 +198,7 @@ func parseQuotaLimits(result *[]*api.QuotaLimit, list *ast.ObjectList) error {
 		valid := []string{
 			"region",
 			"region_limit",
+			"soft_limit",
 		}
 		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
 			return err
@@ -210,6 +211,7 @@ func parseQuotaLimits(result *[]*api.QuotaLimit, list *ast.ObjectList) error {
 
 		// Manually parse
 		delete(m, "region_limit")
+		delete(m, "soft_limit")
 
 		// Decode the rest
 		var limit api.QuotaLimit
@@ -232,6 +234,12 @@ func parseQuotaLimits(result *[]*api.QuotaLimit, list *ast.ObjectList) error {
 				return multierror.Prefix(err, "region_limit ->")
 			}
 		}
+		if o := listVal.Filter("soft_limit"); len(o.Items) > 0 {
+			limit.SoftLimit = new(api.Resources)
+			if err := parseQuotaResource(limit.SoftLimit, o); err != nil {
+				return multierror.Prefix(err, "soft_limit ->")
+			}
+		}
 
 		*result = append(*result, &limit)
 	}
@@ -239,14 +247,14 @@ func parseQuotaLimits(result *[]*api.QuotaLimit, list *ast.ObjectList) error {
 	return nil
 }
 
-// parseQuotaResource parses the region_limit resources
+// parseQuotaResource parses the region_limit and soft_limit resources
 func parseQuotaResource(result *api.Resources, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) == 0 {
 		return nil
 	}
 	if len(list.Items) > 1 {
-		return fmt.Errorf("only one 'region_limit' block allowed per limit")
+		return fmt.Errorf("only one block allowed per limit")
 	}
 
 	// Get our resource object
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestQuotaApplyCommand_Implements(t *testing.T) {
@@ -35,3 +36,26 @@ func TestQuotaApplyCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 }
+
+func TestQuotaApplyCommand_parseQuotaSpec_SoftLimit(t *testing.T) {
+	ci.Parallel(t)
+
+	spec, err := parseQuotaSpec([]byte(defaultHclQuotaSpec))
+	require.NoError(t, err)
+	require.Len(t, spec.Limits, 1)
+
+	limit := spec.Limits[0]
+	require.Equal(t, 2500, *limit.RegionLimit.CPU)
+	require.Equal(t, 2000, *limit.SoftLimit.CPU)
+	require.Equal(t, 800, *limit.SoftLimit.MemoryMB)
+	require.Nil(t, limit.SoftLimit.MemoryMaxMB)
+
+	_, err = parseQuotaSpec([]byte(`
+limit {
+  region = "global"
+  soft_limit {
+    disk = 100
+  }
+}`))
+	require.ErrorContains(t, err, "soft_limit ->")
+}
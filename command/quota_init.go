@@ -121,6 +121,13 @@ limit {
     memory     = 1000
     memory_max = 1000
   }
+
+  # Report usage exceeding the soft limit in quota status, without
+  # blocking placements.
+  soft_limit {
+    cpu    = 2000
+    memory = 800
+  }
 }
 `)
 
@@ -135,6 +142,10 @@ var defaultJsonQuotaSpec = strings.TrimSpace(`
 				"CPU": 2500,
 				"MemoryMB": 1000,
 				"MemoryMaxMB": 1000
+			},
+			"SoftLimit": {
+				"CPU": 2000,
+				"MemoryMB": 800
 			}
 		}
 	]
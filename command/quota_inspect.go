@@ -111,7 +111,9 @@ func (c *QuotaInspectCommand) Run(args []string) int {
 		Failures: failuresConverted,
 	}
 
-	out, err := Format(len(tmpl) == 0, tmpl, data)
+	// The JSON format is the default output of the command
+	json := len(tmpl) == 0 && !c.formatRequested(false, "")
+	out, err := c.formatData(json, tmpl, data)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
@@ -89,8 +89,8 @@ func (c *QuotaListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, quotas)
+	if c.formatRequested(json, tmpl) {
+		out, err := c.formatData(json, tmpl, quotas)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -26,13 +26,27 @@ Usage: nomad quota status [options] <quota>
 
 General Options:
 
-  ` + generalOptionsUsage(usageOptsDefault)
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Status Options:
+
+  -json
+    Output the quota specification and its usages in a JSON format.
+
+  -t
+    Format and display the quota specification and its usages using a Go
+    template.
+`
 
 	return strings.TrimSpace(helpText)
 }
 
 func (c *QuotaStatusCommand) AutocompleteFlags() complete.Flags {
-	return c.Meta.AutocompleteFlags(FlagSetClient)
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
 }
 
 func (c *QuotaStatusCommand) AutocompleteArgs() complete.Predictor {
@@ -46,8 +60,13 @@ func (c *QuotaStatusCommand) Synopsis() string {
 func (c *QuotaStatusCommand) Name() string { return "quota status" }
 
 func (c *QuotaStatusCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -83,16 +102,44 @@ func (c *QuotaStatusCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Format the basics
-	c.Ui.Output(formatQuotaSpecBasics(spec))
-
 	// Get the quota usages
 	usages, failures := quotaUsages(spec, quotas)
 
+	if c.formatRequested(json, tmpl) {
+		failuresConverted := make(map[string]string, len(failures))
+		for r, e := range failures {
+			failuresConverted[r] = e.Error()
+		}
+
+		out, err := c.formatData(json, tmpl, &inspectedQuota{
+			Spec:     spec,
+			Usages:   usages,
+			Failures: failuresConverted,
+		})
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	// Format the basics
+	c.Ui.Output(formatQuotaSpecBasics(spec))
+
 	// Format the limits
 	c.Ui.Output(c.Colorize().Color("\n[bold]Quota Limits[reset]"))
 	c.Ui.Output(formatQuotaLimits(spec, usages))
 
+	// Warn about any exceeded soft limits
+	if warnings := quotaSoftLimitWarnings(spec, usages); len(warnings) != 0 {
+		c.Ui.Warn(c.Colorize().Color("\n[bold][yellow]Soft Limits Exceeded[reset]"))
+		for _, w := range warnings {
+			c.Ui.Warn(fmt.Sprintf("  * %s", w))
+		}
+	}
+
 	// Display any failures
 	if len(failures) != 0 {
 		c.Ui.Error(c.Colorize().Color("\n[bold][red]Lookup Failures[reset]"))
@@ -163,23 +210,12 @@ func formatQuotaLimits(spec *api.QuotaSpec, usages map[string]*api.QuotaUsage) s
 	for _, specLimit := range spec.Limits {
 		i++
 
-		// lookupUsage returns the regions quota usage for the limit
-		lookupUsage := func() (*api.QuotaLimit, bool) {
-			usage, ok := usages[specLimit.Region]
-			if !ok {
-				return nil, false
-			}
-
-			used, ok := usage.Used[base64.StdEncoding.EncodeToString(specLimit.Hash)]
-			return used, ok
-		}
-
 		specBits := 0
 		if len(specLimit.RegionLimit.Networks) == 1 {
 			specBits = *specLimit.RegionLimit.Networks[0].MBits
 		}
 
-		used, ok := lookupUsage()
+		used, ok := quotaLimitUsage(specLimit, usages)
 		if !ok {
 			cpu := fmt.Sprintf("- / %s", formatQuotaLimitInt(specLimit.RegionLimit.CPU))
 			memory := fmt.Sprintf("- / %s", formatQuotaLimitInt(specLimit.RegionLimit.MemoryMB))
@@ -210,6 +246,48 @@ func formatQuotaLimits(spec *api.QuotaSpec, usages map[string]*api.QuotaUsage) s
 	return formatList(limits)
 }
 
+// quotaLimitUsage returns the usage of the quota limit from the quota usages
+// by region.
+func quotaLimitUsage(specLimit *api.QuotaLimit, usages map[string]*api.QuotaUsage) (*api.QuotaLimit, bool) {
+	usage, ok := usages[specLimit.Region]
+	if !ok {
+		return nil, false
+	}
+
+	used, ok := usage.Used[base64.StdEncoding.EncodeToString(specLimit.Hash)]
+	return used, ok
+}
+
+// quotaSoftLimitWarnings returns a warning for every resource of a quota limit
+// whose usage exceeds its soft limit.
+func quotaSoftLimitWarnings(spec *api.QuotaSpec, usages map[string]*api.QuotaUsage) []string {
+	var warnings []string
+	for _, specLimit := range spec.Limits {
+		soft := specLimit.SoftLimit
+		if soft == nil {
+			continue
+		}
+		used, ok := quotaLimitUsage(specLimit, usages)
+		if !ok || used.RegionLimit == nil {
+			continue
+		}
+
+		check := func(name string, usage, limit *int) {
+			if usage == nil || limit == nil || *limit <= 0 {
+				return
+			}
+			if *usage > *limit {
+				warnings = append(warnings, fmt.Sprintf(
+					"Region %q %s usage %d exceeds soft limit %d", specLimit.Region, name, *usage, *limit))
+			}
+		}
+		check("CPU", used.RegionLimit.CPU, soft.CPU)
+		check("memory", used.RegionLimit.MemoryMB, soft.MemoryMB)
+		check("memory max", used.RegionLimit.MemoryMaxMB, soft.MemoryMaxMB)
+	}
+	return warnings
+}
+
 // formatQuotaLimitInt takes a integer resource value and returns the
 // appropriate string for output.
 func formatQuotaLimitInt(value *int) string {
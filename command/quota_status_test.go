@@ -4,13 +4,17 @@
 package command
 
 import (
+	"encoding/base64"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestQuotaStatusCommand_Implements(t *testing.T) {
@@ -95,3 +99,46 @@ func TestQuotaStatusCommand_AutocompleteArgs(t *testing.T) {
 	assert.Equal(1, len(res))
 	assert.Equal(qs.Name, res[0])
 }
+
+func TestQuotaStatusCommand_SoftLimitWarnings(t *testing.T) {
+	ci.Parallel(t)
+
+	spec := &api.QuotaSpec{
+		Name: "default",
+		Limits: []*api.QuotaLimit{
+			{
+				Region: "global",
+				RegionLimit: &api.Resources{
+					CPU:      helper.IntToPtr(2500),
+					MemoryMB: helper.IntToPtr(1000),
+				},
+				SoftLimit: &api.Resources{
+					CPU:      helper.IntToPtr(2000),
+					MemoryMB: helper.IntToPtr(800),
+				},
+				Hash: []byte("hash"),
+			},
+		},
+	}
+	usages := map[string]*api.QuotaUsage{
+		"global": {
+			Name: "default",
+			Used: map[string]*api.QuotaLimit{
+				base64.StdEncoding.EncodeToString([]byte("hash")): {
+					Region: "global",
+					RegionLimit: &api.Resources{
+						CPU:      helper.IntToPtr(2100),
+						MemoryMB: helper.IntToPtr(500),
+					},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, []string{
+		`Region "global" CPU usage 2100 exceeds soft limit 2000`,
+	}, quotaSoftLimitWarnings(spec, usages))
+
+	// No warnings without usage
+	require.Empty(t, quotaSoftLimitWarnings(spec, nil))
+}
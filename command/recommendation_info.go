@@ -96,8 +96,8 @@ func (r *RecommendationInfoCommand) Run(args []string) int {
 	// If the user has specified to output the recommendation as JSON or using
 	// a template then perform this action for the entire object and exit the
 	// command.
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, rec)
+	if r.formatRequested(json, tmpl) {
+		out, err := r.formatData(json, tmpl, rec)
 		if err != nil {
 			r.Ui.Error(err.Error())
 			return 1
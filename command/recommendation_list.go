@@ -139,8 +139,8 @@ func (r *RecommendationListCommand) Run(args []string) int {
 		return 0
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, recommendations)
+	if r.formatRequested(json, tmpl) {
+		out, err := r.formatData(json, tmpl, recommendations)
 		if err != nil {
 			r.Ui.Error(err.Error())
 			return 1
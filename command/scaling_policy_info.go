@@ -109,13 +109,13 @@ func (s *ScalingPolicyInfoCommand) Run(args []string) int {
 	args = flags.Args()
 
 	// Formatted list mode if no policy ID
-	if len(args) == 0 && (json || len(tmpl) > 0) {
+	if len(args) == 0 && s.formatRequested(json, tmpl) {
 		policies, _, err := client.Scaling().ListPolicies(nil)
 		if err != nil {
 			s.Ui.Error(fmt.Sprintf("Error listing scaling policies: %v", err))
 			return 1
 		}
-		out, err := Format(json, tmpl, policies)
+		out, err := s.formatData(json, tmpl, policies)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
@@ -161,8 +161,8 @@ func (s *ScalingPolicyInfoCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, policy)
+	if s.formatRequested(json, tmpl) {
+		out, err := s.formatData(json, tmpl, policy)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
@@ -123,8 +123,8 @@ func (s *ScalingPolicyListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, policies)
+	if s.formatRequested(json, tmpl) {
+		out, err := s.formatData(json, tmpl, policies)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
@@ -129,8 +129,8 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 		return 0
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, serviceInfo)
+	if s.formatRequested(json, tmpl) {
+		out, err := s.formatData(json, tmpl, serviceInfo)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
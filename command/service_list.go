@@ -100,8 +100,8 @@ func (s *ServiceListCommand) Run(args []string) int {
 		return 0
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, list)
+	if s.formatRequested(json, tmpl) {
+		out, err := s.formatData(json, tmpl, list)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
@@ -1,8 +1,10 @@
 package command
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
 )
 
@@ -20,6 +22,14 @@ Usage: nomad system reconcile <subcommand> [options]
 
       $ nomad system reconcile summaries
 
+  Show the changes to the active deployments without applying them:
+
+      $ nomad system reconcile deployments -dry-run
+
+  Remove the service registrations of stopped allocations:
+
+      $ nomad system reconcile services
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -35,3 +45,42 @@ func (s *SystemReconcileCommand) Name() string { return "system reconcile" }
 func (s *SystemReconcileCommand) Run(args []string) int {
 	return cli.RunResultHelp
 }
+
+// outputReconcileChanges outputs the changes made by a reconciliation, or that
+// would be made on a dry run. Kind and plural name the objects reconciled.
+func outputReconcileChanges(m *Meta, kind, plural string, resp *api.ReconcileResponse, dryRun bool) {
+	n := len(resp.Changes)
+	if n == 0 {
+		m.Ui.Output(fmt.Sprintf("No %s to reconcile", plural))
+		return
+	}
+
+	noun := plural
+	if n == 1 {
+		noun = kind
+	}
+	if dryRun {
+		m.Ui.Output(fmt.Sprintf("Dry run: %d %s would be reconciled\n", n, noun))
+	} else {
+		m.Ui.Output(fmt.Sprintf("Reconciled %d %s\n", n, noun))
+	}
+	m.Ui.Output(m.Colorize().Color(formatReconcileChanges(kind, resp.Changes)))
+}
+
+// formatReconcileChanges formats the changes of a reconciliation, listing the
+// fields that change for each object like the diffs of job plan.
+func formatReconcileChanges(kind string, changes []*api.ReconcileChange) string {
+	out := make([]string, 0, len(changes))
+	for _, change := range changes {
+		marker, _ := getDiffString(change.Type)
+		lines := []string{fmt.Sprintf("%s%s %q (namespace %q)", marker, kind, change.ID, change.Namespace)}
+
+		longestField, longestMarker := getLongestPrefixes(change.Fields, nil)
+		for _, field := range change.Fields {
+			_, markerLen := getDiffString(field.Type)
+			lines = append(lines, formatFieldDiff(field, 2, longestMarker-markerLen, longestField-len(field.Name)))
+		}
+		out = append(out, strings.Join(lines, "\n"))
+	}
+	return strings.Join(out, "\n\n")
+}
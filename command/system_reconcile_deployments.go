@@ -0,0 +1,86 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type SystemReconcileDeploymentsCommand struct {
+	Meta
+}
+
+func (c *SystemReconcileDeploymentsCommand) Help() string {
+	helpText := `
+Usage: nomad system reconcile deployments [options]
+
+  Reconciles the placed, healthy and unhealthy allocation counts of the active
+  deployments with their allocations, and outputs the deployments which
+  changed.
+
+  If ACLs are enabled, this option requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Reconcile Options:
+
+  -dry-run
+    Output the changes to the deployments without applying them.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SystemReconcileDeploymentsCommand) Synopsis() string {
+	return "Reconciles the allocation counts of active deployments"
+}
+
+func (c *SystemReconcileDeploymentsCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-dry-run": complete.PredictNothing,
+		})
+}
+
+func (c *SystemReconcileDeploymentsCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *SystemReconcileDeploymentsCommand) Name() string { return "system reconcile deployments" }
+
+func (c *SystemReconcileDeploymentsCommand) Run(args []string) int {
+	var dryRun bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&dryRun, "dry-run", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	resp, err := client.System().ReconcileDeployments(&api.ReconcileOptions{DryRun: dryRun}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running system deployment reconciliation: %s", err))
+		return 1
+	}
+
+	outputReconcileChanges(&c.Meta, "deployment", "deployments", resp, dryRun)
+	return 0
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type SystemReconcileServicesCommand struct {
+	Meta
+}
+
+func (c *SystemReconcileServicesCommand) Help() string {
+	helpText := `
+Usage: nomad system reconcile services [options]
+
+  Reconciles the Nomad native service registrations with their allocations.
+  The registrations of allocations which are no longer in the cluster state, or
+  which are terminal on their client, are removed. This can happen when the
+  client of an allocation is lost or the cluster state is restored from a
+  snapshot. The removed registrations are output.
+
+  If ACLs are enabled, this option requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Reconcile Options:
+
+  -dry-run
+    Output the service registrations to remove without applying them.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SystemReconcileServicesCommand) Synopsis() string {
+	return "Removes the service registrations of stopped allocations"
+}
+
+func (c *SystemReconcileServicesCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-dry-run": complete.PredictNothing,
+		})
+}
+
+func (c *SystemReconcileServicesCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *SystemReconcileServicesCommand) Name() string { return "system reconcile services" }
+
+func (c *SystemReconcileServicesCommand) Run(args []string) int {
+	var dryRun bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&dryRun, "dry-run", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	resp, err := client.System().ReconcileServices(&api.ReconcileOptions{DryRun: dryRun}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running system service reconciliation: %s", err))
+		return 1
+	}
+
+	outputReconcileChanges(&c.Meta, "service registration", "service registrations", resp, dryRun)
+	return 0
+}
@@ -0,0 +1,28 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+)
+
+func TestSystemReconcileServicesCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &SystemReconcileServicesCommand{}
+}
+
+func TestSystemReconcileServicesCommand_Good(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &SystemReconcileServicesCommand{Meta: Meta{Ui: ui}}
+
+	if code := cmd.Run([]string{"-address=" + url, "-dry-run"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+}
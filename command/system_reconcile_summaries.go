@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
 )
 
@@ -15,13 +16,20 @@ func (c *SystemReconcileSummariesCommand) Help() string {
 	helpText := `
 Usage: nomad system reconcile summaries [options]
 
-  Reconciles the summaries of all registered jobs.
+  Reconciles the summaries of all registered jobs with their allocations, and
+  outputs the summaries which changed.
 
   If ACLs are enabled, this option requires a management token.
 
 General Options:
 
-  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Reconcile Options:
+
+  -dry-run
+    Output the changes to the job summaries without applying them.
+`
 	return strings.TrimSpace(helpText)
 }
 
@@ -30,7 +38,10 @@ func (c *SystemReconcileSummariesCommand) Synopsis() string {
 }
 
 func (c *SystemReconcileSummariesCommand) AutocompleteFlags() complete.Flags {
-	return c.Meta.AutocompleteFlags(FlagSetClient)
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-dry-run": complete.PredictNothing,
+		})
 }
 
 func (c *SystemReconcileSummariesCommand) AutocompleteArgs() complete.Predictor {
@@ -40,8 +51,11 @@ func (c *SystemReconcileSummariesCommand) AutocompleteArgs() complete.Predictor
 func (c *SystemReconcileSummariesCommand) Name() string { return "system reconcile summaries" }
 
 func (c *SystemReconcileSummariesCommand) Run(args []string) int {
+	var dryRun bool
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&dryRun, "dry-run", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -50,6 +64,7 @@ func (c *SystemReconcileSummariesCommand) Run(args []string) int {
 	if args = flags.Args(); len(args) > 0 {
 		c.Ui.Error("This command takes no arguments")
 		c.Ui.Error(commandErrorText(c))
+		return 1
 	}
 
 	// Get the HTTP client
@@ -59,9 +74,12 @@ func (c *SystemReconcileSummariesCommand) Run(args []string) int {
 		return 1
 	}
 
-	if err := client.System().ReconcileSummaries(); err != nil {
+	resp, err := client.System().ReconcileSummariesOpts(&api.ReconcileOptions{DryRun: dryRun}, nil)
+	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error running system summary reconciliation: %s", err))
 		return 1
 	}
+
+	outputReconcileChanges(&c.Meta, "job summary", "job summaries", resp, dryRun)
 	return 0
 }
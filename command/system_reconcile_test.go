@@ -3,11 +3,41 @@ package command
 import (
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSystemReconcileCommand_Implements(t *testing.T) {
 	ci.Parallel(t)
 	var _ cli.Command = &SystemCommand{}
 }
+
+func TestSystemReconcile_formatReconcileChanges(t *testing.T) {
+	ci.Parallel(t)
+
+	changes := []*api.ReconcileChange{
+		{
+			Type:      "Edited",
+			Namespace: "default",
+			ID:        "example",
+			Fields: []*api.FieldDiff{
+				{Type: "Edited", Name: "Summary[web].Running", Old: "2", New: "1"},
+				{Type: "Edited", Name: "Summary[web].Lost", Old: "0", New: "1"},
+			},
+		},
+		{
+			Type:      "Deleted",
+			Namespace: "prod",
+			ID:        "_nomad-task-web",
+		},
+	}
+
+	expected := `[light_yellow]+/-[reset] job summary "example" (namespace "default")
+  [light_yellow]+/-[reset] Summary[web].Running: "2" => "1"
+  [light_yellow]+/-[reset] Summary[web].Lost:    "0" => "1"
+
+[red]-[reset] job summary "_nomad-task-web" (namespace "prod")`
+	require.Equal(t, expected, formatReconcileChanges("job summary", changes))
+}
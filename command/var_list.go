@@ -133,7 +133,7 @@ func (c *VarListCommand) Run(args []string) int {
 	}
 
 	switch {
-	case json:
+	case json || c.format == formatJSON || c.format == formatYAML:
 
 		// obj and items enable us to rework the output before sending it
 		// to the Format method for transformation into JSON.
@@ -163,7 +163,7 @@ func (c *VarListCommand) Run(args []string) int {
 
 		// By this point, the output is ready to be transformed to JSON via
 		// the Format func.
-		out, err := Format(json, tmpl, obj)
+		out, err := c.formatData(json, tmpl, obj)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -180,8 +180,8 @@ func (c *VarListCommand) Run(args []string) int {
 			formatList(
 				dataToQuietStringSlice(vars, c.Meta.namespace)))
 
-	case len(tmpl) > 0:
-		out, err := Format(json, tmpl, vars)
+	case c.formatRequested(false, tmpl):
+		out, err := c.formatData(json, tmpl, vars)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
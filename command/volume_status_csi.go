@@ -12,7 +12,7 @@ import (
 )
 
 func (c *VolumeStatusCommand) csiBanner() {
-	if !(c.json || len(c.template) > 0) {
+	if !c.formatRequested(c.json, c.template) {
 		c.Ui.Output(c.Colorize().Color("[bold]Container Storage Interface[reset]"))
 	}
 }
@@ -171,8 +171,8 @@ func (c *VolumeStatusCommand) csiFormatVolumes(vols []*api.CSIVolumeListStub) (s
 	// Sort the output by volume id
 	sort.Slice(vols, func(i, j int) bool { return vols[i].ID < vols[j].ID })
 
-	if c.json || len(c.template) > 0 {
-		out, err := Format(c.json, c.template, vols)
+	if c.formatRequested(c.json, c.template) {
+		out, err := c.formatData(c.json, c.template, vols)
 		if err != nil {
 			return "", fmt.Errorf("format error: %v", err)
 		}
@@ -199,8 +199,8 @@ func csiFormatSortedVolumes(vols []*api.CSIVolumeListStub) (string, error) {
 }
 
 func (c *VolumeStatusCommand) formatBasic(vol *api.CSIVolume) (string, error) {
-	if c.json || len(c.template) > 0 {
-		out, err := Format(c.json, c.template, vol)
+	if c.formatRequested(c.json, c.template) {
+		out, err := c.formatData(c.json, c.template, vol)
 		if err != nil {
 			return "", fmt.Errorf("format error: %v", err)
 		}
@@ -11,6 +11,7 @@ import (
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/drivers/shared/capabilities"
+	"github.com/hashicorp/nomad/drivers/shared/proclimits"
 	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/plugins/base"
@@ -256,10 +257,15 @@ var (
 			"selinuxlabel": hclspec.NewAttr("selinuxlabel", "string", false),
 		})), hclspec.NewLiteral("{ enabled = false }")),
 		"allow_privileged": hclspec.NewAttr("allow_privileged", "bool", false),
+		// require_userns requires the daemon to run containers in a user
+		// namespace and prevents tasks from disabling it
+		"require_userns": hclspec.NewAttr("require_userns", "bool", false),
 		"allow_caps": hclspec.NewDefault(
 			hclspec.NewAttr("allow_caps", "list(string)", false),
 			hclspec.NewLiteral(capabilities.HCLSpecLiteral),
 		),
+		// process_limits restricts the rlimits of the task process block
+		"process_limits": proclimits.Spec,
 		"nvidia_runtime": hclspec.NewDefault(
 			hclspec.NewAttr("nvidia_runtime", "string", false),
 			hclspec.NewLiteral(`"nvidia"`),
@@ -617,6 +623,7 @@ type DriverConfig struct {
 	GC                            GCConfig      `codec:"gc"`
 	Volumes                       VolumeConfig  `codec:"volumes"`
 	AllowPrivileged               bool          `codec:"allow_privileged"`
+	RequireUserns                 bool          `codec:"require_userns"`
 	AllowCaps                     []string      `codec:"allow_caps"`
 	GPURuntimeName                string        `codec:"nvidia_runtime"`
 	InfraImage                    string        `codec:"infra_image"`
@@ -631,6 +638,8 @@ type DriverConfig struct {
 
 	AllowRuntimesList []string            `codec:"allow_runtimes"`
 	allowRuntimes     map[string]struct{} `codec:"-"`
+
+	ProcessLimits *proclimits.Limits `codec:"process_limits"`
 }
 
 type AuthConfig struct {
@@ -731,6 +740,10 @@ func (d *Driver) SetConfig(c *base.Config) error {
 		d.config.infraImagePullTimeoutDuration = dur
 	}
 
+	if err := d.config.ProcessLimits.Validate(); err != nil {
+		return fmt.Errorf("invalid process_limits: %v", err)
+	}
+
 	d.config.allowRuntimes = make(map[string]struct{}, len(d.config.AllowRuntimesList))
 	for _, r := range d.config.AllowRuntimesList {
 		d.config.allowRuntimes[r] = struct{}{}
@@ -113,6 +113,10 @@ type Driver struct {
 	// gpuRuntime indicates nvidia-docker runtime availability
 	gpuRuntime bool
 
+	// userns is the user namespace mode of the Docker daemon
+	userns     usernsInfo
+	usernsLock sync.RWMutex
+
 	// A tri-state boolean to know if the fingerprinting has happened and
 	// whether it has been successful
 	fingerprintSuccess *bool
@@ -258,6 +262,13 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("image name required for docker driver")
 	}
 
+	// Only the rlimits of the process block are applied to containers
+	if cfg.Process != nil {
+		if err := d.config.ProcessLimits.CheckRlimits(cfg.Process.Rlimits); err != nil {
+			return nil, nil, fmt.Errorf("failed process validation: %v", err)
+		}
+	}
+
 	driverConfig.Image = strings.TrimPrefix(driverConfig.Image, "https://")
 
 	handle := drivers.NewTaskHandle(taskHandleVersion)
@@ -281,6 +292,15 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		}
 	}
 
+	// Hand the files Nomad wrote for the task to root in the container if
+	// the daemon maps it to another host user.
+	if userns := d.usernsInfo(); userns.enabled() && userns.RootUID > 0 && driverConfig.UsernsMode != "host" {
+		taskDir := cfg.TaskDir()
+		if err := remapRootOwnership(userns.RootUID, userns.RootGID, taskDir.LocalDir, taskDir.SecretsDir); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	containerCfg, err := d.createContainerConfig(cfg, &driverConfig, driverConfig.Image)
 	if err != nil {
 		d.logger.Error("failed to create container configuration", "image_name", driverConfig.Image,
@@ -942,6 +962,11 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 	if driverConfig.Privileged && !d.config.AllowPrivileged {
 		return c, fmt.Errorf(`Docker privileged mode is disabled on this Nomad agent`)
 	}
+
+	// tasks may not opt out of user namespaces if they are required
+	if d.config.RequireUserns && driverConfig.UsernsMode == "host" {
+		return c, fmt.Errorf(`Docker userns_mode "host" is disabled on this Nomad agent`)
+	}
 	hostConfig.Privileged = driverConfig.Privileged
 
 	// set add/drop capabilities
@@ -1082,7 +1107,7 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 		return c, fmt.Errorf("failed to parse security_opt configuration: %v", err)
 	}
 
-	ulimits, err := sliceMergeUlimit(driverConfig.Ulimit)
+	ulimits, err := sliceMergeUlimit(mergeProcessUlimits(driverConfig.Ulimit, task.Process))
 	if err != nil {
 		return c, fmt.Errorf("failed to parse ulimit configuration: %v", err)
 	}
@@ -1726,6 +1751,24 @@ func (d *Driver) newDockerClient(timeout time.Duration) (*docker.Client, error)
 	return newClient, merr.ErrorOrNil()
 }
 
+// mergeProcessUlimits adds the rlimits of the task process block to the
+// ulimits of the driver config. Ulimits set in the driver config take
+// precedence.
+func mergeProcessUlimits(ulimits map[string]string, process *drivers.ProcessConfig) map[string]string {
+	if process == nil || len(process.Rlimits) == 0 {
+		return ulimits
+	}
+
+	merged := make(map[string]string, len(ulimits)+len(process.Rlimits))
+	for name, value := range process.Rlimits {
+		merged[name] = strconv.FormatUint(value, 10)
+	}
+	for name, value := range ulimits {
+		merged[name] = value
+	}
+	return merged
+}
+
 func sliceMergeUlimit(ulimitsRaw map[string]string) ([]docker.ULimit, error) {
 	var ulimits []docker.ULimit
 
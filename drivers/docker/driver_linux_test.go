@@ -103,3 +103,25 @@ func TestDockerDriver_PidsLimit(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestDockerDriver_remapRootOwnership(t *testing.T) {
+	ci.Parallel(t)
+	testutil.RequireRoot(t)
+
+	dir := t.TempDir()
+	rootFile := filepath.Join(dir, "root")
+	userFile := filepath.Join(dir, "user")
+	require.NoError(t, ioutil.WriteFile(rootFile, nil, 0600))
+	require.NoError(t, ioutil.WriteFile(userFile, nil, 0600))
+	require.NoError(t, os.Chown(userFile, 1000, 1000))
+
+	require.NoError(t, remapRootOwnership(100000, 100000, dir))
+
+	uid, gid := pathOwner(rootFile)
+	require.Equal(t, 100000, uid)
+	require.Equal(t, 100000, gid)
+
+	uid, gid = pathOwner(userFile)
+	require.Equal(t, 1000, uid)
+	require.Equal(t, 1000, gid)
+}
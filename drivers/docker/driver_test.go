@@ -1065,6 +1065,49 @@ func TestDockerDriver_CreateContainerConfig_User(t *testing.T) {
 	require.Equal(t, task.User, c.Config.User)
 }
 
+func TestDockerDriver_CreateContainerConfig_RequireUserns(t *testing.T) {
+	ci.Parallel(t)
+
+	task, cfg, ports := dockerTask(t)
+	defer freeport.Return(ports)
+	cfg.UsernsMode = "host"
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+
+	_, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.NoError(t, err)
+
+	driver.config.RequireUserns = true
+	_, err = driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.EqualError(t, err, `Docker userns_mode "host" is disabled on this Nomad agent`)
+}
+
+func TestDockerDriver_CreateContainerConfig_ProcessRlimits(t *testing.T) {
+	ci.Parallel(t)
+
+	task, cfg, ports := dockerTask(t)
+	defer freeport.Return(ports)
+	task.Process = &drivers.ProcessConfig{
+		Rlimits: map[string]uint64{"nofile": 1024, "nproc": 64},
+	}
+	cfg.Ulimit = map[string]string{"nofile": "2048:4096"}
+
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+
+	c, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []docker.ULimit{
+		{Name: "nofile", Soft: 2048, Hard: 4096},
+		{Name: "nproc", Soft: 64, Hard: 64},
+	}, c.HostConfig.Ulimits)
+}
+
 func TestDockerDriver_CreateContainerConfig_Labels(t *testing.T) {
 	ci.Parallel(t)
 
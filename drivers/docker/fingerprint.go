@@ -171,6 +171,37 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 			strings.Join(runtimeNames, ","))
 		fp.Attributes["driver.docker.os_type"] = pstructs.NewStringAttribute(dockerInfo.OSType)
 
+		userns := parseUsernsInfo(dockerInfo, isLocalEndpoint(client.Endpoint()))
+		d.setUsernsInfo(userns)
+		if userns.Rootless {
+			fp.Attributes["driver.docker.rootless"] = pstructs.NewBoolAttribute(true)
+		}
+		if userns.Remapped {
+			fp.Attributes["driver.docker.userns_remap"] = pstructs.NewBoolAttribute(true)
+		}
+
+		// Report the host user root in a container is mapped to, so that
+		// the client renders templates owned by it
+		if userns.enabled() && userns.RootUID > 0 {
+			fp.Attributes["driver.docker.userns_root_uid"] = pstructs.NewIntAttribute(int64(userns.RootUID), "")
+			fp.Attributes["driver.docker.userns_root_gid"] = pstructs.NewIntAttribute(int64(userns.RootGID), "")
+		}
+
+		// Tasks must not run unless containers are isolated in a user
+		// namespace
+		if d.config.RequireUserns && !userns.enabled() {
+			if d.fingerprintSuccessful() {
+				d.logger.Warn("require_userns is set but Docker is not running rootless or with userns-remap")
+			}
+
+			d.setFingerprintFailure()
+			return &drivers.Fingerprint{
+				Attributes:        fp.Attributes,
+				Health:            drivers.HealthStateUnhealthy,
+				HealthDescription: "Docker is not running rootless or with userns-remap",
+			}
+		}
+
 		// If this situations arises, we are running in Windows 10 with Linux Containers enabled via VM
 		if runtime.GOOS == "windows" && dockerInfo.OSType == "linux" {
 			if d.fingerprintSuccessful() {
@@ -0,0 +1,86 @@
+package docker
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	docker "github.com/fsouza/go-dockerclient"
+)
+
+// usernsInfo describes how the Docker daemon maps the users of containers to
+// users of the host.
+type usernsInfo struct {
+	// Rootless is true if the daemon runs as an unprivileged user.
+	Rootless bool
+
+	// Remapped is true if the daemon runs with userns-remap enabled.
+	Remapped bool
+
+	// RootUID and RootGID are the host IDs root in a container is mapped
+	// to, or -1 if they are unknown.
+	RootUID int
+	RootGID int
+}
+
+// enabled returns true if containers run in a user namespace by default.
+func (u usernsInfo) enabled() bool {
+	return u.Rootless || u.Remapped
+}
+
+// parseUsernsInfo detects the user namespace mode of the Docker daemon from
+// its system info. The owner of the root dir of a rootless daemon can only be
+// read if the daemon is local.
+func parseUsernsInfo(info *docker.DockerInfo, local bool) usernsInfo {
+	u := usernsInfo{RootUID: -1, RootGID: -1}
+	for _, opt := range info.SecurityOptions {
+		for _, kv := range strings.Split(opt, ",") {
+			switch kv {
+			case "name=rootless":
+				u.Rootless = true
+			case "name=userns":
+				u.Remapped = true
+			}
+		}
+	}
+
+	switch {
+	case u.Remapped:
+		// With userns-remap, the daemon keeps its data in a subdirectory of
+		// the root dir named after the remapped root, such as
+		// /var/lib/docker/100000.100000
+		ids := strings.SplitN(filepath.Base(info.DockerRootDir), ".", 2)
+		if len(ids) == 2 {
+			uid, uidErr := strconv.Atoi(ids[0])
+			gid, gidErr := strconv.Atoi(ids[1])
+			if uidErr == nil && gidErr == nil {
+				u.RootUID, u.RootGID = uid, gid
+			}
+		}
+	case u.Rootless && local:
+		// A rootless daemon maps root in a container to the user running
+		// the daemon, which owns the root dir of the daemon.
+		u.RootUID, u.RootGID = pathOwner(info.DockerRootDir)
+	}
+	return u
+}
+
+// isLocalEndpoint returns true if the Docker endpoint is a socket on this
+// host rather than a remote daemon.
+func isLocalEndpoint(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "unix://") || strings.HasPrefix(endpoint, "npipe://")
+}
+
+// usernsInfo returns the user namespace mode of the Docker daemon detected
+// during fingerprinting.
+func (d *Driver) usernsInfo() usernsInfo {
+	d.usernsLock.RLock()
+	defer d.usernsLock.RUnlock()
+	return d.userns
+}
+
+func (d *Driver) setUsernsInfo(u usernsInfo) {
+	d.usernsLock.Lock()
+	defer d.usernsLock.Unlock()
+	d.userns = u
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package docker
+
+// pathOwner returns -1 as user namespaces are only supported on Linux.
+func pathOwner(string) (int, int) {
+	return -1, -1
+}
+
+// remapRootOwnership is a no-op as user namespaces are only supported on
+// Linux.
+func remapRootOwnership(int, int, ...string) error {
+	return nil
+}
@@ -0,0 +1,55 @@
+//go:build linux
+
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// pathOwner returns the uid and gid owning the path, or -1 if the path can't
+// be read.
+func pathOwner(path string) (int, int) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return -1, -1
+	}
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return -1, -1
+	}
+	return int(stat.Uid), int(stat.Gid)
+}
+
+// remapRootOwnership changes the owner of the files in the directories that
+// are owned by root on the host to the user root in a container is mapped
+// to, so that files written by Nomad, such as templates and secrets, remain
+// accessible to root in the container.
+func remapRootOwnership(uid, gid int, dirs ...string) error {
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			stat, ok := fi.Sys().(*syscall.Stat_t)
+			if !ok || (stat.Uid != 0 && stat.Gid != 0) {
+				return nil
+			}
+
+			newUID, newGID := -1, -1
+			if stat.Uid == 0 {
+				newUID = uid
+			}
+			if stat.Gid == 0 {
+				newGID = gid
+			}
+			return os.Lchown(path, newUID, newGID)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remap ownership of %q: %v", dir, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"testing"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUsernsInfo(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name     string
+		info     *docker.DockerInfo
+		remote   bool
+		expected usernsInfo
+	}{
+		{
+			name: "disabled",
+			info: &docker.DockerInfo{
+				SecurityOptions: []string{"name=apparmor", "name=seccomp,profile=default"},
+				DockerRootDir:   "/var/lib/docker",
+			},
+			expected: usernsInfo{RootUID: -1, RootGID: -1},
+		},
+		{
+			name: "userns-remap",
+			info: &docker.DockerInfo{
+				SecurityOptions: []string{"name=seccomp,profile=default", "name=userns"},
+				DockerRootDir:   "/var/lib/docker/100000.100001",
+			},
+			expected: usernsInfo{Remapped: true, RootUID: 100000, RootGID: 100001},
+		},
+		{
+			name: "userns-remap unknown root",
+			info: &docker.DockerInfo{
+				SecurityOptions: []string{"name=userns"},
+				DockerRootDir:   "/srv/docker",
+			},
+			expected: usernsInfo{Remapped: true, RootUID: -1, RootGID: -1},
+		},
+		{
+			name: "rootless",
+			info: &docker.DockerInfo{
+				SecurityOptions: []string{"name=seccomp,profile=default", "name=rootless"},
+				DockerRootDir:   "/does/not/exist",
+			},
+			expected: usernsInfo{Rootless: true, RootUID: -1, RootGID: -1},
+		},
+		{
+			name: "rootless remote",
+			info: &docker.DockerInfo{
+				SecurityOptions: []string{"name=rootless"},
+				DockerRootDir:   "/",
+			},
+			remote:   true,
+			expected: usernsInfo{Rootless: true, RootUID: -1, RootGID: -1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := parseUsernsInfo(tc.info, !tc.remote)
+			require.Equal(t, tc.expected, u)
+			require.Equal(t, tc.expected.Rootless || tc.expected.Remapped, u.enabled())
+		})
+	}
+}
+
+func TestIsLocalEndpoint(t *testing.T) {
+	ci.Parallel(t)
+
+	require.True(t, isLocalEndpoint("unix:///var/run/docker.sock"))
+	require.True(t, isLocalEndpoint("npipe:////./pipe/docker_engine"))
+	require.False(t, isLocalEndpoint("tcp://10.0.0.1:2376"))
+}
@@ -0,0 +1,500 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/drivers/shared/eventer"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	pstructs "github.com/hashicorp/nomad/plugins/shared/structs"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "aws_ecs"
+
+	// fingerprintPeriod is the interval at which the driver will send fingerprint responses
+	fingerprintPeriod = 30 * time.Second
+
+	// taskHandleVersion is the version of task handle which this driver sets
+	// and understands how to decode driver state
+	taskHandleVersion = 1
+
+	// defaultPollInterval is the interval at which the status of remote
+	// tasks is polled
+	defaultPollInterval = 5 * time.Second
+)
+
+var (
+	// PluginID is the ecs plugin metadata registered in the plugin
+	// catalog.
+	PluginID = loader.PluginID{
+		Name:       pluginName,
+		PluginType: base.PluginTypeDriver,
+	}
+
+	// PluginConfig is the ecs factory function registered in the
+	// plugin catalog.
+	PluginConfig = &loader.InternalPluginConfig{
+		Config:  map[string]interface{}{},
+		Factory: func(ctx context.Context, l hclog.Logger) interface{} { return NewECSDriver(ctx, l) },
+	}
+
+	errDisabledDriver = fmt.Errorf("ecs is disabled")
+)
+
+var (
+	// pluginInfo is the response returned for the PluginInfo RPC
+	pluginInfo = &base.PluginInfoResponse{
+		Type:              base.PluginTypeDriver,
+		PluginApiVersions: []string{drivers.ApiVersion010},
+		PluginVersion:     "0.1.0",
+		Name:              pluginName,
+	}
+
+	// configSpec is the hcl specification returned by the ConfigSchema RPC
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"enabled": hclspec.NewDefault(
+			hclspec.NewAttr("enabled", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+		"cluster": hclspec.NewAttr("cluster", "string", false),
+		"region":  hclspec.NewAttr("region", "string", false),
+	})
+
+	// awsTaskSpec is the hcl specification of the ECS task to run
+	awsTaskSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"launch_type": hclspec.NewDefault(
+			hclspec.NewAttr("launch_type", "string", false),
+			hclspec.NewLiteral(`"FARGATE"`),
+		),
+		"task_definition":         hclspec.NewAttr("task_definition", "string", true),
+		"platform_version":        hclspec.NewAttr("platform_version", "string", false),
+		"enable_ecs_managed_tags": hclspec.NewAttr("enable_ecs_managed_tags", "bool", false),
+		"network_configuration": hclspec.NewBlock("network_configuration", false, hclspec.NewObject(map[string]*hclspec.Spec{
+			"aws_vpc_configuration": hclspec.NewBlock("aws_vpc_configuration", false, hclspec.NewObject(map[string]*hclspec.Spec{
+				"assign_public_ip": hclspec.NewAttr("assign_public_ip", "string", false),
+				"security_groups":  hclspec.NewAttr("security_groups", "list(string)", false),
+				"subnets":          hclspec.NewAttr("subnets", "list(string)", false),
+			})),
+		})),
+	})
+
+	// taskConfigSpec is the hcl specification for the driver config section of
+	// a task within a job. It is returned in the TaskConfigSchema RPC
+	taskConfigSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"task": hclspec.NewBlock("task", true, awsTaskSpec),
+	})
+
+	// capabilities is returned by the Capabilities RPC and indicates what
+	// optional features this driver supports
+	capabilities = &drivers.Capabilities{
+		SendSignals: false,
+		Exec:        false,
+		FSIsolation: drivers.FSIsolationImage,
+		NetIsolationModes: []drivers.NetIsolationMode{
+			drivers.NetIsolationModeNone,
+		},
+		MountConfigs: drivers.MountConfigSupportNone,
+		RemoteTasks:  true,
+	}
+)
+
+// Driver runs tasks on AWS ECS, including the Fargate launch type. The Nomad
+// client only acts as a proxy controlling the remote tasks: tasks keep
+// running if the client is drained or lost, and the client managing the
+// replacement allocation takes over their management.
+type Driver struct {
+	// eventer is used to handle multiplexing of TaskEvents calls such that an
+	// event can be broadcast to all callers
+	eventer *eventer.Eventer
+
+	// config is the driver configuration set by the SetConfig RPC
+	config *Config
+
+	// client is used to call the ECS API
+	client ecsClientInterface
+
+	// pollInterval is the interval at which the status of remote tasks is
+	// polled
+	pollInterval time.Duration
+
+	// tasks is the in memory datastore mapping taskIDs to driverHandles
+	tasks *taskStore
+
+	// ctx is the context for the driver. It is passed to other subsystems to
+	// coordinate shutdown
+	ctx context.Context
+
+	// logger will log to the Nomad agent
+	logger hclog.Logger
+}
+
+// Config is the driver configuration set by the SetConfig RPC call
+type Config struct {
+	// Enabled is set to true to enable the ecs driver
+	Enabled bool `codec:"enabled"`
+
+	// Cluster is the name of the ECS cluster tasks are run in
+	Cluster string `codec:"cluster"`
+
+	// Region is the AWS region of the cluster
+	Region string `codec:"region"`
+}
+
+// TaskConfig is the driver configuration of a task within a job
+type TaskConfig struct {
+	Task ECSTaskConfig `codec:"task"`
+}
+
+// ECSTaskConfig is the configuration of the ECS task to run
+type ECSTaskConfig struct {
+	LaunchType           string                   `codec:"launch_type"`
+	TaskDefinition       string                   `codec:"task_definition"`
+	PlatformVersion      string                   `codec:"platform_version"`
+	EnableECSManagedTags bool                     `codec:"enable_ecs_managed_tags"`
+	NetworkConfiguration TaskNetworkConfiguration `codec:"network_configuration"`
+}
+
+// TaskNetworkConfiguration is the network configuration of the ECS task
+type TaskNetworkConfiguration struct {
+	TaskAWSVPCConfiguration TaskAWSVPCConfiguration `codec:"aws_vpc_configuration"`
+}
+
+// TaskAWSVPCConfiguration is the VPC configuration of tasks using the awsvpc
+// network mode, which is required by the Fargate launch type
+type TaskAWSVPCConfiguration struct {
+	AssignPublicIP string   `codec:"assign_public_ip"`
+	SecurityGroups []string `codec:"security_groups"`
+	Subnets        []string `codec:"subnets"`
+}
+
+// TaskState is the state which is encoded in the handle returned in
+// StartTask. This information is needed to rebuild the task state and handler
+// during recovery.
+type TaskState struct {
+	TaskConfig *drivers.TaskConfig
+	ARN        string
+	StartedAt  time.Time
+}
+
+// NewECSDriver returns a new DriverPlugin implementation
+func NewECSDriver(ctx context.Context, logger hclog.Logger) drivers.DriverPlugin {
+	logger = logger.Named(pluginName)
+	return &Driver{
+		eventer:      eventer.NewEventer(ctx, logger),
+		config:       &Config{},
+		pollInterval: defaultPollInterval,
+		tasks:        newTaskStore(),
+		ctx:          ctx,
+		logger:       logger,
+	}
+}
+
+func (d *Driver) PluginInfo() (*base.PluginInfoResponse, error) {
+	return pluginInfo, nil
+}
+
+func (d *Driver) ConfigSchema() (*hclspec.Spec, error) {
+	return configSpec, nil
+}
+
+func (d *Driver) SetConfig(cfg *base.Config) error {
+	var config Config
+	if len(cfg.PluginConfig) != 0 {
+		if err := base.MsgPackDecode(cfg.PluginConfig, &config); err != nil {
+			return err
+		}
+	}
+
+	d.config = &config
+	if !config.Enabled {
+		return nil
+	}
+
+	if config.Cluster == "" {
+		return fmt.Errorf("cluster must be set when the ecs driver is enabled")
+	}
+
+	sess, err := session.NewSession(aws.NewConfig().WithRegion(config.Region))
+	if err != nil {
+		return fmt.Errorf("failed to create AWS session: %v", err)
+	}
+	d.client = &awsECSClient{
+		cluster:   config.Cluster,
+		ecsClient: ecs.New(sess),
+	}
+	return nil
+}
+
+func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
+	return taskConfigSpec, nil
+}
+
+func (d *Driver) Capabilities() (*drivers.Capabilities, error) {
+	return capabilities, nil
+}
+
+func (d *Driver) Fingerprint(ctx context.Context) (<-chan *drivers.Fingerprint, error) {
+	ch := make(chan *drivers.Fingerprint)
+	go d.handleFingerprint(ctx, ch)
+	return ch, nil
+}
+
+func (d *Driver) handleFingerprint(ctx context.Context, ch chan<- *drivers.Fingerprint) {
+	defer close(ch)
+	ticker := time.NewTimer(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(fingerprintPeriod)
+			ch <- d.buildFingerprint(ctx)
+		}
+	}
+}
+
+func (d *Driver) buildFingerprint(ctx context.Context) *drivers.Fingerprint {
+	if !d.config.Enabled || d.client == nil {
+		return &drivers.Fingerprint{
+			Attributes:        map[string]*pstructs.Attribute{},
+			Health:            drivers.HealthStateUndetected,
+			HealthDescription: "disabled",
+		}
+	}
+
+	if err := d.client.DescribeCluster(ctx); err != nil {
+		d.logger.Warn("failed to describe ecs cluster", "error", err)
+		return &drivers.Fingerprint{
+			Attributes:        map[string]*pstructs.Attribute{},
+			Health:            drivers.HealthStateUnhealthy,
+			HealthDescription: fmt.Sprintf("failed to describe ecs cluster: %v", err),
+		}
+	}
+
+	return &drivers.Fingerprint{
+		Attributes: map[string]*pstructs.Attribute{
+			"driver.aws_ecs":         pstructs.NewBoolAttribute(true),
+			"driver.aws_ecs.cluster": pstructs.NewStringAttribute(d.config.Cluster),
+			"driver.aws_ecs.region":  pstructs.NewStringAttribute(d.config.Region),
+		},
+		Health:            drivers.HealthStateHealthy,
+		HealthDescription: drivers.DriverHealthy,
+	}
+}
+
+func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
+	if handle == nil {
+		return fmt.Errorf("handle cannot be nil")
+	}
+
+	// If already attached to handle there's nothing to recover.
+	if _, ok := d.tasks.Get(handle.Config.ID); ok {
+		d.logger.Trace("nothing to recover; task already exists",
+			"task_id", handle.Config.ID,
+			"task_name", handle.Config.Name,
+		)
+		return nil
+	}
+
+	if d.client == nil {
+		return errDisabledDriver
+	}
+
+	var taskState TaskState
+	if err := handle.GetDriverState(&taskState); err != nil {
+		d.logger.Error("failed to decode task state from handle", "error", err, "task_id", handle.Config.ID)
+		return fmt.Errorf("failed to decode task state from handle: %v", err)
+	}
+
+	// The handle may have been propagated from a previous allocation, in
+	// which case its task config belongs to the previous allocation, so
+	// prefer the config of the task being recovered.
+	cfg := handle.Config
+	if cfg == nil {
+		cfg = taskState.TaskConfig
+	}
+
+	h := newTaskHandle(d.ctx, d, taskState.ARN, cfg, taskState.StartedAt)
+	d.tasks.Set(cfg.ID, h)
+
+	go h.run()
+	return nil
+}
+
+func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	if !d.config.Enabled || d.client == nil {
+		return nil, nil, errDisabledDriver
+	}
+
+	if _, ok := d.tasks.Get(cfg.ID); ok {
+		return nil, nil, fmt.Errorf("task with ID %q already started", cfg.ID)
+	}
+
+	var driverConfig TaskConfig
+	if err := cfg.DecodeDriverConfig(&driverConfig); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode driver config: %v", err)
+	}
+
+	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
+	handle := drivers.NewTaskHandle(taskHandleVersion)
+	handle.Config = cfg
+
+	tags := map[string]string{
+		"nomad_alloc_id": cfg.AllocID,
+		"nomad_job":      cfg.JobName,
+		"nomad_task":     cfg.Name,
+	}
+	arn, err := d.client.RunTask(d.ctx, driverConfig, tags)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start ecs task: %v", err)
+	}
+
+	h := newTaskHandle(d.ctx, d, arn, cfg, time.Now().Round(time.Millisecond))
+
+	driverState := TaskState{
+		TaskConfig: cfg,
+		ARN:        arn,
+		StartedAt:  h.startedAt,
+	}
+
+	if err := handle.SetDriverState(&driverState); err != nil {
+		d.logger.Error("failed to start task, error setting driver state", "error", err)
+		if err := d.client.StopTask(d.ctx, arn); err != nil {
+			d.logger.Error("failed to stop ecs task", "arn", arn, "error", err)
+		}
+		return nil, nil, fmt.Errorf("failed to set driver state: %v", err)
+	}
+
+	d.tasks.Set(cfg.ID, h)
+	go h.run()
+	return handle, nil, nil
+}
+
+func (d *Driver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	ch := make(chan *drivers.ExitResult)
+	go d.handleWait(ctx, handle, ch)
+
+	return ch, nil
+}
+
+func (d *Driver) handleWait(ctx context.Context, handle *taskHandle, ch chan *drivers.ExitResult) {
+	defer close(ch)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-d.ctx.Done():
+		return
+	case <-handle.doneCh:
+	}
+
+	// The handle stops polling without the task exiting when the driver
+	// shuts down, in which case the task will be recovered.
+	if handle.IsRunning() {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-d.ctx.Done():
+		return
+	case ch <- handle.TaskStatus().ExitResult:
+	}
+}
+
+func (d *Driver) StopTask(taskID string, timeout time.Duration, signal string) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	// Detaching leaves the remote task running so the client managing the
+	// replacement allocation can take over its management.
+	if signal == drivers.DetachSignal {
+		handle.detach()
+		<-handle.doneCh
+		return nil
+	}
+
+	if err := d.client.StopTask(d.ctx, handle.arn); err != nil {
+		return fmt.Errorf("failed to stop ecs task: %v", err)
+	}
+
+	// Wait for the remote task to stop
+	select {
+	case <-handle.doneCh:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out waiting for ecs task %q to stop", handle.arn)
+	}
+}
+
+func (d *Driver) DestroyTask(taskID string, force bool) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if handle.IsRunning() && !force {
+		return fmt.Errorf("cannot destroy running task")
+	}
+
+	if handle.IsRunning() && !handle.isDetached() {
+		if err := d.client.StopTask(d.ctx, handle.arn); err != nil {
+			handle.logger.Error("failed to stop ecs task", "error", err)
+		}
+	}
+
+	handle.cancel()
+	d.tasks.Delete(taskID)
+	return nil
+}
+
+func (d *Driver) InspectTask(taskID string) (*drivers.TaskStatus, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	return handle.TaskStatus(), nil
+}
+
+// TaskStats is not implemented as the ECS API doesn't expose the resource
+// usage of individual tasks.
+func (d *Driver) TaskStats(ctx context.Context, taskID string, interval time.Duration) (<-chan *drivers.TaskResourceUsage, error) {
+	if _, ok := d.tasks.Get(taskID); !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	return nil, drivers.DriverStatsNotImplemented
+}
+
+func (d *Driver) TaskEvents(ctx context.Context) (<-chan *drivers.TaskEvent, error) {
+	return d.eventer.TaskEvents(ctx)
+}
+
+func (d *Driver) SignalTask(taskID string, signal string) error {
+	return fmt.Errorf("ecs driver does not support signals")
+}
+
+func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
+	return nil, fmt.Errorf("ecs driver does not support exec")
+}
@@ -0,0 +1,250 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	dtestutil "github.com/hashicorp/nomad/plugins/drivers/testutils"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockECSClient is an in memory ecsClientInterface.
+type mockECSClient struct {
+	lock       sync.Mutex
+	clusterErr error
+	tasks      map[string]*remoteTaskStatus
+	runs       []TaskConfig
+	stops      []string
+}
+
+func newMockECSClient() *mockECSClient {
+	return &mockECSClient{tasks: map[string]*remoteTaskStatus{}}
+}
+
+func (c *mockECSClient) DescribeCluster(context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.clusterErr
+}
+
+func (c *mockECSClient) RunTask(_ context.Context, cfg TaskConfig, _ map[string]string) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	arn := "arn:aws:ecs:us-east-1:123456789012:task/" + uuid.Generate()
+	c.runs = append(c.runs, cfg)
+	c.tasks[arn] = &remoteTaskStatus{LastStatus: "RUNNING"}
+	return arn, nil
+}
+
+func (c *mockECSClient) DescribeTask(_ context.Context, arn string) (*remoteTaskStatus, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	status, ok := c.tasks[arn]
+	if !ok {
+		return nil, fmt.Errorf("ecs task %q not found", arn)
+	}
+	copy := *status
+	return &copy, nil
+}
+
+func (c *mockECSClient) StopTask(_ context.Context, arn string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stops = append(c.stops, arn)
+	c.stopLocked(arn, 143)
+	return nil
+}
+
+// stop marks the remote task as stopped with the exit code.
+func (c *mockECSClient) stop(arn string, exitCode int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stopLocked(arn, exitCode)
+}
+
+func (c *mockECSClient) stopLocked(arn string, exitCode int) {
+	c.tasks[arn] = &remoteTaskStatus{
+		LastStatus:    ecsTaskStatusStopped,
+		StoppedReason: "Essential container in task exited",
+		ExitCode:      &exitCode,
+	}
+}
+
+func (c *mockECSClient) stopped() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]string{}, c.stops...)
+}
+
+func newTestECSDriver(t *testing.T) (*Driver, *mockECSClient) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	client := newMockECSClient()
+	d := NewECSDriver(ctx, testlog.HCLogger(t)).(*Driver)
+	d.config = &Config{Enabled: true, Cluster: "nomad", Region: "us-east-1"}
+	d.client = client
+	d.pollInterval = 10 * time.Millisecond
+	return d, client
+}
+
+func testTaskConfig(t *testing.T) *drivers.TaskConfig {
+	task := &drivers.TaskConfig{
+		ID:      uuid.Generate(),
+		AllocID: uuid.Generate(),
+		Name:    "web",
+		JobName: "example",
+	}
+	taskConfig := TaskConfig{
+		Task: ECSTaskConfig{
+			LaunchType:     "FARGATE",
+			TaskDefinition: "web:1",
+			NetworkConfiguration: TaskNetworkConfiguration{
+				TaskAWSVPCConfiguration: TaskAWSVPCConfiguration{
+					Subnets: []string{"subnet-1"},
+				},
+			},
+		},
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&taskConfig))
+	return task
+}
+
+func TestECSDriver_Fingerprint(t *testing.T) {
+	ci.Parallel(t)
+
+	d, client := newTestECSDriver(t)
+	require.Equal(t, drivers.HealthStateHealthy, d.buildFingerprint(context.Background()).Health)
+
+	client.clusterErr = fmt.Errorf("cluster not found")
+	require.Equal(t, drivers.HealthStateUnhealthy, d.buildFingerprint(context.Background()).Health)
+
+	d.config.Enabled = false
+	fp := d.buildFingerprint(context.Background())
+	require.Equal(t, drivers.HealthStateUndetected, fp.Health)
+	require.Empty(t, fp.Attributes)
+}
+
+func TestECSDriver_StartWaitStop(t *testing.T) {
+	ci.Parallel(t)
+
+	d, client := newTestECSDriver(t)
+	harness := dtestutil.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	task := testTaskConfig(t)
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+	defer harness.DestroyTask(task.ID, true)
+
+	var state TaskState
+	require.NoError(t, handle.GetDriverState(&state))
+	require.NotEmpty(t, state.ARN)
+	require.Len(t, client.runs, 1)
+	require.Equal(t, "web:1", client.runs[0].Task.TaskDefinition)
+	require.Equal(t, []string{"subnet-1"}, client.runs[0].Task.NetworkConfiguration.TaskAWSVPCConfiguration.Subnets)
+
+	ch, err := harness.WaitTask(context.Background(), task.ID)
+	require.NoError(t, err)
+
+	status, err := harness.InspectTask(task.ID)
+	require.NoError(t, err)
+	require.Equal(t, drivers.TaskStateRunning, status.State)
+	require.Equal(t, state.ARN, status.DriverAttributes["arn"])
+
+	_, err = d.TaskStats(context.Background(), task.ID, time.Second)
+	require.EqualError(t, err, drivers.DriverStatsNotImplemented.Error())
+
+	require.NoError(t, harness.StopTask(task.ID, time.Second, "SIGTERM"))
+	require.Equal(t, []string{state.ARN}, client.stopped())
+
+	select {
+	case res := <-ch:
+		require.Equal(t, 143, res.ExitCode)
+	case <-time.After(time.Duration(testutil.TestMultiplier()*5) * time.Second):
+		require.Fail(t, "timeout waiting for task to exit")
+	}
+}
+
+func TestECSDriver_RemoteExit(t *testing.T) {
+	ci.Parallel(t)
+
+	d, client := newTestECSDriver(t)
+	harness := dtestutil.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	task := testTaskConfig(t)
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+	defer harness.DestroyTask(task.ID, true)
+
+	var state TaskState
+	require.NoError(t, handle.GetDriverState(&state))
+
+	ch, err := harness.WaitTask(context.Background(), task.ID)
+	require.NoError(t, err)
+
+	client.stop(state.ARN, 0)
+
+	select {
+	case res := <-ch:
+		require.True(t, res.Successful())
+	case <-time.After(time.Duration(testutil.TestMultiplier()*5) * time.Second):
+		require.Fail(t, "timeout waiting for task to exit")
+	}
+}
+
+// TestECSDriver_DetachRecover asserts detaching from a task doesn't stop the
+// remote task, which can be recovered by another driver from its handle.
+func TestECSDriver_DetachRecover(t *testing.T) {
+	ci.Parallel(t)
+
+	d, client := newTestECSDriver(t)
+	harness := dtestutil.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	task := testTaskConfig(t)
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+
+	var state TaskState
+	require.NoError(t, handle.GetDriverState(&state))
+
+	require.NoError(t, harness.StopTask(task.ID, time.Second, drivers.DetachSignal))
+	require.NoError(t, harness.DestroyTask(task.ID, true))
+	require.Empty(t, client.stopped())
+
+	// Recover the task in a new driver, as the client managing the
+	// replacement allocation would
+	d2, _ := newTestECSDriver(t)
+	d2.client = client
+	harness2 := dtestutil.NewDriverHarness(t, d2)
+	defer harness2.Kill()
+
+	handle.Config = testTaskConfig(t)
+	require.NoError(t, harness2.RecoverTask(handle))
+	defer harness2.DestroyTask(handle.Config.ID, true)
+
+	status, err := harness2.InspectTask(handle.Config.ID)
+	require.NoError(t, err)
+	require.Equal(t, drivers.TaskStateRunning, status.State)
+	require.Equal(t, state.ARN, status.DriverAttributes["arn"])
+}
+
+func TestECSDriver_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	d, _ := newTestECSDriver(t)
+	d.config.Enabled = false
+
+	_, _, err := d.StartTask(testTaskConfig(t))
+	require.Equal(t, errDisabledDriver, err)
+}
@@ -0,0 +1,155 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+)
+
+// ecsClientInterface encapsulates the calls the driver makes to the ECS API,
+// so they can be mocked in tests.
+type ecsClientInterface interface {
+	// DescribeCluster returns an error if the cluster of the driver can't be
+	// found or isn't active.
+	DescribeCluster(ctx context.Context) error
+
+	// RunTask starts a task in the cluster and returns its ARN.
+	RunTask(ctx context.Context, cfg TaskConfig, tags map[string]string) (string, error)
+
+	// DescribeTask returns the status of the task.
+	DescribeTask(ctx context.Context, taskARN string) (*remoteTaskStatus, error)
+
+	// StopTask stops the task.
+	StopTask(ctx context.Context, taskARN string) error
+}
+
+// remoteTaskStatus is the status of a task running in ECS.
+type remoteTaskStatus struct {
+	// LastStatus is the last known status of the task, such as RUNNING or
+	// STOPPED.
+	LastStatus string
+
+	// StoppedReason is why the task stopped, if it has.
+	StoppedReason string
+
+	// ExitCode is the first non-zero exit code of the containers of a
+	// stopped task, or nil if no container has exited.
+	ExitCode *int
+}
+
+// awsECSClient implements ecsClientInterface with the AWS SDK.
+type awsECSClient struct {
+	cluster   string
+	ecsClient ecsiface.ECSAPI
+}
+
+func (c *awsECSClient) DescribeCluster(ctx context.Context) error {
+	input := &ecs.DescribeClustersInput{
+		Clusters: []*string{aws.String(c.cluster)},
+	}
+
+	resp, err := c.ecsClient.DescribeClustersWithContext(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Clusters) != 1 {
+		return fmt.Errorf("ecs cluster %q not found", c.cluster)
+	}
+	if status := aws.StringValue(resp.Clusters[0].Status); status != "ACTIVE" {
+		return fmt.Errorf("ecs cluster %q is %s", c.cluster, strings.ToLower(status))
+	}
+	return nil
+}
+
+func (c *awsECSClient) RunTask(ctx context.Context, cfg TaskConfig, tags map[string]string) (string, error) {
+	input := &ecs.RunTaskInput{
+		Cluster:              aws.String(c.cluster),
+		Count:                aws.Int64(1),
+		LaunchType:           aws.String(cfg.Task.LaunchType),
+		TaskDefinition:       aws.String(cfg.Task.TaskDefinition),
+		EnableECSManagedTags: aws.Bool(cfg.Task.EnableECSManagedTags),
+	}
+	if cfg.Task.PlatformVersion != "" {
+		input.PlatformVersion = aws.String(cfg.Task.PlatformVersion)
+	}
+
+	vpc := cfg.Task.NetworkConfiguration.TaskAWSVPCConfiguration
+	if len(vpc.Subnets) > 0 {
+		input.NetworkConfiguration = &ecs.NetworkConfiguration{
+			AwsvpcConfiguration: &ecs.AwsVpcConfiguration{
+				Subnets:        aws.StringSlice(vpc.Subnets),
+				SecurityGroups: aws.StringSlice(vpc.SecurityGroups),
+			},
+		}
+		if vpc.AssignPublicIP != "" {
+			input.NetworkConfiguration.AwsvpcConfiguration.AssignPublicIp = aws.String(vpc.AssignPublicIP)
+		}
+	}
+
+	for k, v := range tags {
+		input.Tags = append(input.Tags, &ecs.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	resp, err := c.ecsClient.RunTaskWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.Failures) > 0 {
+		f := resp.Failures[0]
+		return "", fmt.Errorf("failed to run ecs task: %s: %s",
+			aws.StringValue(f.Reason), aws.StringValue(f.Detail))
+	}
+	if len(resp.Tasks) != 1 {
+		return "", fmt.Errorf("expected 1 ecs task to be started but got %d", len(resp.Tasks))
+	}
+	return aws.StringValue(resp.Tasks[0].TaskArn), nil
+}
+
+func (c *awsECSClient) DescribeTask(ctx context.Context, taskARN string) (*remoteTaskStatus, error) {
+	input := &ecs.DescribeTasksInput{
+		Cluster: aws.String(c.cluster),
+		Tasks:   []*string{aws.String(taskARN)},
+	}
+
+	resp, err := c.ecsClient.DescribeTasksWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Tasks) != 1 {
+		return nil, fmt.Errorf("ecs task %q not found", taskARN)
+	}
+
+	task := resp.Tasks[0]
+	status := &remoteTaskStatus{
+		LastStatus:    aws.StringValue(task.LastStatus),
+		StoppedReason: aws.StringValue(task.StoppedReason),
+	}
+	for _, c := range task.Containers {
+		if c.ExitCode == nil {
+			continue
+		}
+		code := int(aws.Int64Value(c.ExitCode))
+		if status.ExitCode == nil || *status.ExitCode == 0 {
+			status.ExitCode = &code
+		}
+	}
+	return status, nil
+}
+
+func (c *awsECSClient) StopTask(ctx context.Context, taskARN string) error {
+	input := &ecs.StopTaskInput{
+		Cluster: aws.String(c.cluster),
+		Task:    aws.String(taskARN),
+		Reason:  aws.String("stopped by Nomad"),
+	}
+
+	_, err := c.ecsClient.StopTaskWithContext(ctx, input)
+	return err
+}
@@ -0,0 +1,147 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// ecsTaskStatusStopped is the status of an ECS task that has exited
+	ecsTaskStatusStopped = "STOPPED"
+)
+
+type taskHandle struct {
+	arn          string
+	client       ecsClientInterface
+	pollInterval time.Duration
+	logger       hclog.Logger
+
+	// ctx is canceled to stop polling the remote task
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// stateLock syncs access to all fields below
+	stateLock sync.RWMutex
+
+	taskConfig  *drivers.TaskConfig
+	procState   drivers.TaskState
+	startedAt   time.Time
+	completedAt time.Time
+	exitResult  *drivers.ExitResult
+	lastStatus  string
+	detached    bool
+	doneCh      chan struct{}
+}
+
+func newTaskHandle(ctx context.Context, d *Driver, arn string, cfg *drivers.TaskConfig, startedAt time.Time) *taskHandle {
+	ctx, cancel := context.WithCancel(ctx)
+	return &taskHandle{
+		arn:          arn,
+		client:       d.client,
+		pollInterval: d.pollInterval,
+		logger:       d.logger.With("task_name", cfg.Name, "alloc_id", cfg.AllocID, "arn", arn),
+		ctx:          ctx,
+		cancel:       cancel,
+		taskConfig:   cfg,
+		procState:    drivers.TaskStateRunning,
+		startedAt:    startedAt,
+		exitResult:   &drivers.ExitResult{},
+		doneCh:       make(chan struct{}),
+	}
+}
+
+func (h *taskHandle) TaskStatus() *drivers.TaskStatus {
+	h.stateLock.RLock()
+	defer h.stateLock.RUnlock()
+
+	return &drivers.TaskStatus{
+		ID:          h.taskConfig.ID,
+		Name:        h.taskConfig.Name,
+		State:       h.procState,
+		StartedAt:   h.startedAt,
+		CompletedAt: h.completedAt,
+		ExitResult:  h.exitResult,
+		DriverAttributes: map[string]string{
+			"arn":    h.arn,
+			"status": h.lastStatus,
+		},
+	}
+}
+
+func (h *taskHandle) IsRunning() bool {
+	h.stateLock.RLock()
+	defer h.stateLock.RUnlock()
+	return h.procState == drivers.TaskStateRunning
+}
+
+// detach stops managing the remote task without stopping it, so another
+// Nomad client can take over its management.
+func (h *taskHandle) detach() {
+	h.stateLock.Lock()
+	h.detached = true
+	h.stateLock.Unlock()
+
+	h.cancel()
+}
+
+func (h *taskHandle) isDetached() bool {
+	h.stateLock.RLock()
+	defer h.stateLock.RUnlock()
+	return h.detached
+}
+
+// run polls the status of the remote task until it stops, the handle is
+// detached, or the driver shuts down.
+func (h *taskHandle) run() {
+	defer close(h.doneCh)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			h.stateLock.Lock()
+			if h.detached {
+				h.procState = drivers.TaskStateExited
+				h.completedAt = time.Now()
+			}
+			h.stateLock.Unlock()
+			return
+		case <-timer.C:
+			timer.Reset(h.pollInterval)
+		}
+
+		status, err := h.client.DescribeTask(h.ctx, h.arn)
+		if err != nil {
+			// Errors are likely transient failures to reach the ECS API,
+			// so keep polling
+			if h.ctx.Err() == nil {
+				h.logger.Warn("failed to describe ecs task", "error", err)
+			}
+			continue
+		}
+
+		h.stateLock.Lock()
+		h.lastStatus = status.LastStatus
+		if status.LastStatus == ecsTaskStatusStopped {
+			h.procState = drivers.TaskStateExited
+			h.completedAt = time.Now()
+			if status.ExitCode != nil {
+				h.exitResult.ExitCode = *status.ExitCode
+			} else {
+				h.exitResult.Err = fmt.Errorf("ecs task stopped: %s", status.StoppedReason)
+			}
+		}
+		h.stateLock.Unlock()
+
+		if status.LastStatus == ecsTaskStatusStopped {
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	cconfig "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// reservedChrootPaths are the paths of the task chroot managed by Nomad or
+// the executor, which chroot_env entries may not be mounted over.
+var reservedChrootPaths = []string{
+	"/",
+	"/dev",
+	"/proc",
+	"/sys",
+	allocdir.SharedAllocContainerPath,
+	allocdir.TaskLocalContainerPath,
+	allocdir.TaskSecretsContainerPath,
+}
+
+// validateChrootEnv returns an error if the chroot_env map has relative paths
+// or mounts over a path managed by Nomad.
+func validateChrootEnv(option string, chrootEnv map[string]string) error {
+	for src, dest := range chrootEnv {
+		if !filepath.IsAbs(src) {
+			return fmt.Errorf("%s host path %q must be absolute", option, src)
+		}
+		if !filepath.IsAbs(dest) {
+			return fmt.Errorf("%s task path %q must be absolute", option, dest)
+		}
+		for _, reserved := range reservedChrootPaths {
+			if filepath.Clean(dest) == reserved {
+				return fmt.Errorf("%s task path %q is reserved", option, dest)
+			}
+		}
+	}
+	return nil
+}
+
+// validateTaskChrootEnv returns an error if the chroot_env of a task binds a
+// host path that isn't part of the chroot of the driver, either the
+// chroot_env plugin option or the default chroot, nor allowed by the
+// allowed_host_paths plugin option.
+func (d *Driver) validateTaskChrootEnv(chrootEnv map[string]string) error {
+	base := d.config.ChrootEnv
+	if len(base) == 0 {
+		base = cconfig.DefaultChrootEnv
+	}
+
+	for src := range chrootEnv {
+		var allowed bool
+		for baseSrc := range base {
+			if isSubpath(baseSrc, src) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed && d.config.AllowedHostPaths != nil {
+			for _, p := range d.config.AllowedHostPaths {
+				if p.matches(src) {
+					allowed = true
+					break
+				}
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("chroot_env host path %q is not part of the driver chroot", src)
+		}
+	}
+	return nil
+}
+
+// chrootMounts returns the read-only bind mounts populating the task chroot
+// from the chroot_env plugin option and the chroot_env of the task. Entries
+// of the task replace entries of the plugin with the same task path. Host
+// paths that don't exist are skipped.
+func (d *Driver) chrootMounts(taskChrootEnv map[string]string) []*drivers.MountConfig {
+	byDest := map[string]string{}
+	for src, dest := range d.config.ChrootEnv {
+		byDest[filepath.Clean(dest)] = src
+	}
+	for src, dest := range taskChrootEnv {
+		byDest[filepath.Clean(dest)] = src
+	}
+
+	mounts := make([]*drivers.MountConfig, 0, len(byDest))
+	for dest, src := range byDest {
+		if _, err := os.Stat(src); err != nil {
+			d.logger.Debug("skipping missing chroot_env path", "path", src, "error", err)
+			continue
+		}
+		mounts = append(mounts, &drivers.MountConfig{
+			HostPath: src,
+			TaskPath: dest,
+			Readonly: true,
+		})
+	}
+
+	// Mount parents before their children
+	sort.Slice(mounts, func(i, j int) bool {
+		return mounts[i].TaskPath < mounts[j].TaskPath
+	})
+	return mounts
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	osexec "os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,8 +17,10 @@ import (
 	"github.com/hashicorp/nomad/drivers/shared/capabilities"
 	"github.com/hashicorp/nomad/drivers/shared/eventer"
 	"github.com/hashicorp/nomad/drivers/shared/executor"
+	"github.com/hashicorp/nomad/drivers/shared/proclimits"
 	"github.com/hashicorp/nomad/drivers/shared/resolvconf"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -35,6 +39,11 @@ const (
 	// taskHandleVersion is the version of task handle which this driver sets
 	// and understands how to decode driver state
 	taskHandleVersion = 1
+
+	// minIOWeight and maxIOWeight are the bounds of the io_weight of tasks,
+	// which follow the cgroups v1 blkio.weight range.
+	minIOWeight = 10
+	maxIOWeight = 1000
 )
 
 var (
@@ -78,17 +87,72 @@ var (
 			hclspec.NewAttr("allow_caps", "list(string)", false),
 			hclspec.NewLiteral(capabilities.HCLSpecLiteral),
 		),
+		"allowed_host_paths": hclspec.NewBlockList("allowed_host_paths", hclspec.NewObject(map[string]*hclspec.Spec{
+			"path": hclspec.NewAttr("path", "string", true),
+			"read_only": hclspec.NewDefault(
+				hclspec.NewAttr("read_only", "bool", false),
+				hclspec.NewLiteral("false"),
+			),
+		})),
+		"isolation": hclspec.NewDefault(
+			hclspec.NewAttr("isolation", "string", false),
+			hclspec.NewLiteral(`"chroot"`),
+		),
+		"unveil_defaults": hclspec.NewDefault(
+			hclspec.NewAttr("unveil_defaults", "bool", false),
+			hclspec.NewLiteral("true"),
+		),
+		"unveil_paths": hclspec.NewAttr("unveil_paths", "list(string)", false),
+		"unveil_by_task": hclspec.NewDefault(
+			hclspec.NewAttr("unveil_by_task", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+		"chroot_env": hclspec.NewAttr("chroot_env", "map(string)", false),
+		"rootless": hclspec.NewDefault(
+			hclspec.NewAttr("rootless", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+		"executor_log_level": hclspec.NewDefault(
+			hclspec.NewAttr("executor_log_level", "string", false),
+			hclspec.NewLiteral(`"debug"`),
+		),
+		"executor_log_max_file_size": hclspec.NewDefault(
+			hclspec.NewAttr("executor_log_max_file_size", "number", false),
+			hclspec.NewLiteral("10"),
+		),
+		"executor_log_max_files": hclspec.NewDefault(
+			hclspec.NewAttr("executor_log_max_files", "number", false),
+			hclspec.NewLiteral("2"),
+		),
+		"use_cgroup_freeze_on_stop": hclspec.NewDefault(
+			hclspec.NewAttr("use_cgroup_freeze_on_stop", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+		"process_limits": proclimits.Spec,
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
 	// a task within a job. It is returned in the TaskConfigSchema RPC
 	taskConfigSpec = hclspec.NewObject(map[string]*hclspec.Spec{
-		"command":  hclspec.NewAttr("command", "string", true),
-		"args":     hclspec.NewAttr("args", "list(string)", false),
-		"pid_mode": hclspec.NewAttr("pid_mode", "string", false),
-		"ipc_mode": hclspec.NewAttr("ipc_mode", "string", false),
-		"cap_add":  hclspec.NewAttr("cap_add", "list(string)", false),
-		"cap_drop": hclspec.NewAttr("cap_drop", "list(string)", false),
+		"command":    hclspec.NewAttr("command", "string", true),
+		"args":       hclspec.NewAttr("args", "list(string)", false),
+		"pid_mode":   hclspec.NewAttr("pid_mode", "string", false),
+		"ipc_mode":   hclspec.NewAttr("ipc_mode", "string", false),
+		"cap_add":    hclspec.NewAttr("cap_add", "list(string)", false),
+		"cap_drop":   hclspec.NewAttr("cap_drop", "list(string)", false),
+		"secret_env": hclspec.NewAttr("secret_env", "list(string)", false),
+		"unveil":     hclspec.NewAttr("unveil", "list(string)", false),
+		"chroot_env": hclspec.NewAttr("chroot_env", "list(map(string))", false),
+		"io_weight":  hclspec.NewAttr("io_weight", "number", false),
+		"tmpfs": hclspec.NewBlockList("tmpfs", hclspec.NewObject(map[string]*hclspec.Spec{
+			"path": hclspec.NewAttr("path", "string", true),
+			"size": hclspec.NewAttr("size", "number", false),
+		})),
+		"devices": hclspec.NewBlockList("devices", hclspec.NewObject(map[string]*hclspec.Spec{
+			"host_path":          hclspec.NewAttr("host_path", "string", true),
+			"task_path":          hclspec.NewAttr("task_path", "string", false),
+			"cgroup_permissions": hclspec.NewAttr("cgroup_permissions", "string", false),
+		})),
 	})
 
 	// driverCapabilities represents the RPC response for what features are
@@ -151,6 +215,57 @@ type Config struct {
 	// AllowCaps configures which Linux Capabilities are enabled for tasks
 	// running on this node.
 	AllowCaps []string `codec:"allow_caps"`
+
+	// AllowedHostPaths restricts the host paths tasks may bind-mount. If not
+	// set, any host path may be mounted.
+	AllowedHostPaths []*AllowedHostPath `codec:"allowed_host_paths"`
+
+	// Isolation selects how tasks are isolated from the host filesystem,
+	// either "chroot" or "landlock".
+	Isolation string `codec:"isolation"`
+
+	// UnveilDefaults grants tasks running with landlock isolation access
+	// to the system paths needed to run common binaries.
+	UnveilDefaults bool `codec:"unveil_defaults"`
+
+	// UnveilPaths are the mode:path grants given to every task running with
+	// landlock isolation.
+	UnveilPaths []string `codec:"unveil_paths"`
+
+	// UnveilByTask allows tasks to request additional path grants with the
+	// unveil task option.
+	UnveilByTask bool `codec:"unveil_by_task"`
+
+	// ChrootEnv maps host paths to the paths they are bind-mounted to in
+	// the chroot of tasks. If set, it replaces the chroot_env of the client
+	// for exec tasks.
+	ChrootEnv map[string]string `codec:"chroot_env"`
+
+	// Rootless runs tasks in a user namespace whose root user is mapped to
+	// the user running the client, so the client doesn't need to run as
+	// root.
+	Rootless bool `codec:"rootless"`
+
+	// ExecutorLogLevel is the level of the logs of the executors, written
+	// to the executor.out file of the tasks.
+	ExecutorLogLevel string `codec:"executor_log_level"`
+
+	// ExecutorLogMaxFileSize is the size in MB at which the executor log
+	// file is rotated. If zero, the file isn't rotated.
+	ExecutorLogMaxFileSize int `codec:"executor_log_max_file_size"`
+
+	// ExecutorLogMaxFiles is the number of rotated executor log files to
+	// keep.
+	ExecutorLogMaxFiles int `codec:"executor_log_max_files"`
+
+	// UseCgroupFreezeOnStop freezes the cgroup of tasks while signaling
+	// their processes on stop, so processes forked concurrently can't
+	// escape the signal.
+	UseCgroupFreezeOnStop bool `codec:"use_cgroup_freeze_on_stop"`
+
+	// ProcessLimits restricts the nice value, I/O scheduling class and
+	// resource limits the process block of tasks may request.
+	ProcessLimits *proclimits.Limits `codec:"process_limits"`
 }
 
 func (c *Config) validate() error {
@@ -171,6 +286,49 @@ func (c *Config) validate() error {
 		return fmt.Errorf("allow_caps configured with capabilities not supported by system: %s", badCaps)
 	}
 
+	for _, p := range c.AllowedHostPaths {
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+
+	switch c.Isolation {
+	case "", isolationChroot, isolationLandlock:
+	default:
+		return fmt.Errorf("isolation must be %q or %q, got %q", isolationChroot, isolationLandlock, c.Isolation)
+	}
+
+	for _, s := range c.UnveilPaths {
+		if _, err := parseUnveil(s); err != nil {
+			return err
+		}
+	}
+
+	if len(c.ChrootEnv) > 0 && c.Isolation == isolationLandlock {
+		return fmt.Errorf("chroot_env can't be used with landlock isolation")
+	}
+	if err := validateChrootEnv("chroot_env", c.ChrootEnv); err != nil {
+		return err
+	}
+
+	if c.Rootless && c.Isolation == isolationLandlock {
+		return fmt.Errorf("rootless can't be used with landlock isolation")
+	}
+
+	if c.ExecutorLogLevel != "" && hclog.LevelFromString(c.ExecutorLogLevel) == hclog.NoLevel {
+		return fmt.Errorf("executor_log_level %q is not a valid log level", c.ExecutorLogLevel)
+	}
+	if c.ExecutorLogMaxFileSize < 0 {
+		return fmt.Errorf("executor_log_max_file_size must not be negative, got %d", c.ExecutorLogMaxFileSize)
+	}
+	if c.ExecutorLogMaxFiles < 0 {
+		return fmt.Errorf("executor_log_max_files must not be negative, got %d", c.ExecutorLogMaxFiles)
+	}
+
+	if err := c.ProcessLimits.Validate(); err != nil {
+		return fmt.Errorf("invalid process_limits: %v", err)
+	}
+
 	return nil
 }
 
@@ -195,6 +353,85 @@ type TaskConfig struct {
 
 	// CapDrop is a set of linux capabilities to disable.
 	CapDrop []string `codec:"cap_drop"`
+
+	// SecretEnv is a list of environment variables that are passed to the
+	// task through an env file readable only by the task user, instead of
+	// through the process environment.
+	SecretEnv []string `codec:"secret_env"`
+
+	// Unveil is a list of mode:path grants for the task when the driver runs
+	// with landlock isolation.
+	Unveil []string `codec:"unveil"`
+
+	// ChrootEnv maps host paths to the paths they are bind-mounted to in
+	// the chroot of the task, in addition to the chroot of the driver.
+	ChrootEnv hclutils.MapStrStr `codec:"chroot_env"`
+
+	// IOWeight is the relative block IO weight of the task, between 10 and
+	// 1000.
+	IOWeight uint16 `codec:"io_weight"`
+
+	// Tmpfs are the tmpfs mounts in the chroot of the task.
+	Tmpfs []TmpfsMount `codec:"tmpfs"`
+
+	// Devices are the host devices made available in the chroot of the task.
+	Devices []TaskDevice `codec:"devices"`
+}
+
+// TmpfsMount is a tmpfs mounted in the chroot of the task.
+type TmpfsMount struct {
+	// Path is the absolute path of the mount in the chroot.
+	Path string `codec:"path"`
+
+	// SizeMB is the size limit of the tmpfs in MiB, or 0 for the default
+	// size of the kernel.
+	SizeMB int64 `codec:"size"`
+}
+
+// TaskDevice is a host device node created in the chroot of the task.
+type TaskDevice struct {
+	// HostPath is the path of the device on the host.
+	HostPath string `codec:"host_path"`
+
+	// TaskPath is the path of the device in the chroot, HostPath if empty.
+	TaskPath string `codec:"task_path"`
+
+	// CgroupPermissions are the device cgroup permissions of the task on the
+	// device, a combination of r, w and m. Defaults to rwm.
+	CgroupPermissions string `codec:"cgroup_permissions"`
+}
+
+// mounts returns the tmpfs mounts requested by the task.
+func (tc *TaskConfig) mounts() []*drivers.MountConfig {
+	mounts := make([]*drivers.MountConfig, 0, len(tc.Tmpfs))
+	for _, t := range tc.Tmpfs {
+		mounts = append(mounts, &drivers.MountConfig{
+			TaskPath:  t.Path,
+			Type:      drivers.MountTypeTmpfs,
+			TmpfsSize: t.SizeMB * 1024 * 1024,
+		})
+	}
+	return mounts
+}
+
+// devices returns the host devices requested by the task.
+func (tc *TaskConfig) devices() []*drivers.DeviceConfig {
+	devices := make([]*drivers.DeviceConfig, 0, len(tc.Devices))
+	for _, d := range tc.Devices {
+		dev := &drivers.DeviceConfig{
+			HostPath:    d.HostPath,
+			TaskPath:    d.TaskPath,
+			Permissions: d.CgroupPermissions,
+		}
+		if dev.TaskPath == "" {
+			dev.TaskPath = dev.HostPath
+		}
+		if dev.Permissions == "" {
+			dev.Permissions = "rwm"
+		}
+		devices = append(devices, dev)
+	}
+	return devices
 }
 
 func (tc *TaskConfig) validate() error {
@@ -220,9 +457,52 @@ func (tc *TaskConfig) validate() error {
 		return fmt.Errorf("cap_drop configured with capabilities not supported by system: %s", badDrops)
 	}
 
+	if err := validateChrootEnv("chroot_env", tc.ChrootEnv); err != nil {
+		return err
+	}
+
+	if tc.IOWeight != 0 && (tc.IOWeight < minIOWeight || tc.IOWeight > maxIOWeight) {
+		return fmt.Errorf("io_weight must be between %d and %d, got %d", minIOWeight, maxIOWeight, tc.IOWeight)
+	}
+
+	for _, t := range tc.Tmpfs {
+		if !filepath.IsAbs(t.Path) {
+			return fmt.Errorf("tmpfs path %q must be absolute", t.Path)
+		}
+		if t.SizeMB < 0 {
+			return fmt.Errorf("tmpfs size must not be negative, got %d", t.SizeMB)
+		}
+	}
+
+	for _, d := range tc.Devices {
+		if !filepath.IsAbs(d.HostPath) {
+			return fmt.Errorf("device host_path %q must be absolute", d.HostPath)
+		}
+		if d.TaskPath != "" && !filepath.IsAbs(d.TaskPath) {
+			return fmt.Errorf("device task_path %q must be absolute", d.TaskPath)
+		}
+		if !validCgroupPermissions(d.CgroupPermissions) {
+			return fmt.Errorf("invalid device cgroup_permissions %q", d.CgroupPermissions)
+		}
+	}
+
 	return nil
 }
 
+// validCgroupPermissions returns true if perms is empty or a combination of
+// r, w and m without repetition.
+func validCgroupPermissions(perms string) bool {
+	if len(perms) > 3 {
+		return false
+	}
+	for i, c := range perms {
+		if !strings.ContainsRune("rwm", c) || strings.ContainsRune(perms[:i], c) {
+			return false
+		}
+	}
+	return true
+}
+
 // TaskState is the state which is encoded in the handle returned in
 // StartTask. This information is needed to rebuild the task state and handler
 // during recovery.
@@ -300,7 +580,32 @@ func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
 // Capabilities is returned by the Capabilities RPC and indicates what
 // optional features this driver supports
 func (d *Driver) Capabilities() (*drivers.Capabilities, error) {
-	return driverCapabilities, nil
+	if d.config.Isolation == isolationLandlock {
+		// Tasks run on the host filesystem, so there is no chroot to build
+		// or to mount volumes into.
+		caps := *driverCapabilities
+		caps.FSIsolation = drivers.FSIsolationNone
+		caps.MountConfigs = drivers.MountConfigSupportNone
+		return &caps, nil
+	}
+
+	caps := *driverCapabilities
+	if len(d.config.ChrootEnv) > 0 {
+		// The driver populates the chroot with bind mounts, so the client
+		// must not build one.
+		caps.FSIsolation = drivers.FSIsolationImage
+	}
+
+	// Tasks can be checkpointed with CRIU, which requires root
+	caps.Checkpoint = !d.config.Rootless && criuAvailable()
+	return &caps, nil
+}
+
+// criuAvailable returns whether the criu binary used to checkpoint and restore
+// tasks is installed.
+func criuAvailable() bool {
+	_, err := osexec.LookPath("criu")
+	return err == nil
 }
 
 func (d *Driver) Fingerprint(ctx context.Context) (<-chan *drivers.Fingerprint, error) {
@@ -340,7 +645,18 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 		HealthDescription: drivers.DriverHealthy,
 	}
 
-	if !utils.IsUnixRoot() {
+	if d.config.Rootless {
+		if err := userNamespacesSupported(procRoot); err != nil {
+			fp.Health = drivers.HealthStateUndetected
+			fp.HealthDescription = fmt.Sprintf("rootless mode unavailable: %v", err)
+			if d.fingerprintSuccessful() {
+				d.logger.Warn(fp.HealthDescription)
+			}
+			d.setFingerprintFailure()
+			return fp
+		}
+		fp.Attributes["driver.exec.rootless"] = pstructs.NewBoolAttribute(true)
+	} else if !utils.IsUnixRoot() {
 		fp.Health = drivers.HealthStateUndetected
 		fp.HealthDescription = drivers.DriverRequiresRootMessage
 		d.setFingerprintFailure()
@@ -365,6 +681,41 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 		return fp
 	}
 
+	if cgutil.UseV2 {
+		// The unified hierarchy only enforces the limits of the controllers
+		// enabled on the system
+		missing, err := cgutil.MissingControllersV2()
+		if err == nil && len(missing) > 0 {
+			err = fmt.Errorf("missing controllers: %s", strings.Join(missing, ", "))
+		}
+		if err != nil {
+			fp.Health = drivers.HealthStateUnhealthy
+			fp.HealthDescription = fmt.Sprintf("cgroups v2 unavailable: %v", err)
+			if d.fingerprintSuccessful() {
+				d.logger.Warn(fp.HealthDescription)
+			}
+			d.setFingerprintFailure()
+			return fp
+		}
+		fp.Attributes["driver.exec.cgroups"] = pstructs.NewStringAttribute("v2")
+	} else {
+		fp.Attributes["driver.exec.cgroups"] = pstructs.NewStringAttribute("v1")
+	}
+
+	if d.config.Isolation == isolationLandlock {
+		abi, err := executor.LandlockABI()
+		if err != nil {
+			fp.Health = drivers.HealthStateUnhealthy
+			fp.HealthDescription = fmt.Sprintf("landlock isolation unavailable: %v", err)
+			if d.fingerprintSuccessful() {
+				d.logger.Warn(fp.HealthDescription)
+			}
+			d.setFingerprintFailure()
+			return fp
+		}
+		fp.Attributes["driver.exec.landlock"] = pstructs.NewIntAttribute(int64(abi), "")
+	}
+
 	fp.Attributes["driver.exec"] = pstructs.NewBoolAttribute(true)
 	d.setFingerprintSuccess()
 	return fp
@@ -414,6 +765,7 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 		startedAt:    taskState.StartedAt,
 		exitResult:   &drivers.ExitResult{},
 		logger:       d.logger,
+		eventer:      d.eventer,
 	}
 
 	d.tasks.Set(taskState.TaskConfig.ID, h)
@@ -423,6 +775,30 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 }
 
 func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	return d.startTask(cfg, "")
+}
+
+// RestoreTask starts the task from a checkpoint created by CheckpointTask.
+func (d *Driver) RestoreTask(cfg *drivers.TaskConfig, dir string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	if d.config.Isolation == isolationLandlock || d.config.Rootless {
+		return nil, nil, fmt.Errorf("restoring tasks requires chroot isolation and running as root")
+	}
+	return d.startTask(cfg, dir)
+}
+
+// CheckpointTask checkpoints the task with CRIU into dir, stopping the task.
+func (d *Driver) CheckpointTask(taskID string, dir string) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	return handle.exec.Checkpoint(dir)
+}
+
+// startTask starts the task, restoring it from the checkpoint in restoreDir
+// if set.
+func (d *Driver) startTask(cfg *drivers.TaskConfig, restoreDir string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
 	if _, ok := d.tasks.Get(cfg.ID); ok {
 		return nil, nil, fmt.Errorf("task with ID %q already started", cfg.ID)
 	}
@@ -436,15 +812,75 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
 	}
 
+	if err := validateHostPaths(d.config.AllowedHostPaths, cfg.AllocDir, cfg.Mounts); err != nil {
+		return nil, nil, fmt.Errorf("failed mount validation: %v", err)
+	}
+
+	if err := d.config.ProcessLimits.Check(cfg.Process); err != nil {
+		return nil, nil, fmt.Errorf("failed process validation: %v", err)
+	}
+
+	taskDevices := driverConfig.devices()
+	if err := validateHostDevices(d.config.AllowedHostPaths, taskDevices); err != nil {
+		return nil, nil, fmt.Errorf("failed device validation: %v", err)
+	}
+
+	landlock := d.config.Isolation == isolationLandlock
+	var sandbox *executor.SandboxConfig
+	if landlock {
+		if len(cfg.Mounts) > 0 || len(cfg.Devices) > 0 || cfg.DNS != nil {
+			return nil, nil, fmt.Errorf("volume mounts, devices and dns configuration are not supported with landlock isolation")
+		}
+		if len(driverConfig.Tmpfs) > 0 || len(driverConfig.Devices) > 0 {
+			return nil, nil, fmt.Errorf("failed driver config validation: tmpfs and devices can't be used with landlock isolation")
+		}
+
+		var err error
+		sandbox, err = d.sandboxConfig(cfg, driverConfig.Unveil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
+		}
+	} else if len(driverConfig.Unveil) > 0 {
+		return nil, nil, fmt.Errorf("failed driver config validation: unveil requires landlock isolation")
+	}
+
+	if driverConfig.IOWeight > 0 {
+		if landlock {
+			return nil, nil, fmt.Errorf("failed driver config validation: io_weight can't be used with landlock isolation")
+		}
+		if cgutil.UseV2 {
+			if missing, err := cgutil.MissingControllersV2("io"); err != nil {
+				return nil, nil, fmt.Errorf("failed to read cgroup controllers: %v", err)
+			} else if len(missing) > 0 {
+				return nil, nil, fmt.Errorf("io_weight requires the cgroup controllers: %s", strings.Join(missing, ", "))
+			}
+		}
+	}
+
+	if len(driverConfig.ChrootEnv) > 0 {
+		if landlock {
+			return nil, nil, fmt.Errorf("failed driver config validation: chroot_env can't be used with landlock isolation")
+		}
+		if err := d.validateTaskChrootEnv(driverConfig.ChrootEnv); err != nil {
+			return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
+		}
+	}
+
 	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
 	handle := drivers.NewTaskHandle(taskHandleVersion)
 	handle.Config = cfg
 
 	pluginLogFile := filepath.Join(cfg.TaskDir().Dir, "executor.out")
+	logLevel := d.config.ExecutorLogLevel
+	if logLevel == "" {
+		logLevel = "debug"
+	}
 	executorConfig := &executor.ExecutorConfig{
-		LogFile:     pluginLogFile,
-		LogLevel:    "debug",
-		FSIsolation: true,
+		LogFile:          pluginLogFile,
+		LogLevel:         logLevel,
+		LogMaxFileSizeMB: d.config.ExecutorLogMaxFileSize,
+		LogMaxFiles:      d.config.ExecutorLogMaxFiles,
+		FSIsolation:      !landlock,
 	}
 
 	exec, pluginClient, err := executor.CreateExecutor(
@@ -459,6 +895,28 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		user = "nobody"
 	}
 
+	// Files shared with the task are owned by the host user of the task
+	fileOwner := user
+	if d.config.Rootless {
+		// Tasks run as root in their user namespace, which is the user
+		// running the client on the host
+		if cfg.User != "" && cfg.User != "root" {
+			pluginClient.Kill()
+			return nil, nil, fmt.Errorf("failed driver config validation: user %q can't be used in rootless mode, tasks run as root in a user namespace", cfg.User)
+		}
+		user = "root"
+		if fileOwner, err = currentUser(); err != nil {
+			pluginClient.Kill()
+			return nil, nil, err
+		}
+	}
+
+	// Populate the chroot before any other mounts, which may be nested in
+	// the chroot_env paths
+	if !landlock && (len(d.config.ChrootEnv) > 0 || len(driverConfig.ChrootEnv) > 0) {
+		cfg.Mounts = append(d.chrootMounts(driverConfig.ChrootEnv), cfg.Mounts...)
+	}
+
 	if cfg.DNS != nil {
 		dnsMount, err := resolvconf.GenerateDNSMount(cfg.TaskDir().Dir, cfg.DNS)
 		if err != nil {
@@ -467,6 +925,9 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		cfg.Mounts = append(cfg.Mounts, dnsMount)
 	}
 
+	cfg.Mounts = append(cfg.Mounts, driverConfig.mounts()...)
+	cfg.Devices = append(cfg.Devices, taskDevices...)
+
 	caps, err := capabilities.Calculate(
 		capabilities.NomadDefaults(), d.config.AllowCaps, driverConfig.CapAdd, driverConfig.CapDrop,
 	)
@@ -475,23 +936,40 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 	}
 	d.logger.Debug("task capabilities", "capabilities", caps)
 
+	env := cfg.EnvList()
+	if len(driverConfig.SecretEnv) > 0 {
+		public, secret := splitSecretEnv(cfg.Env, driverConfig.SecretEnv)
+		envFile, err := writeEnvFile(cfg.TaskDir().SecretsDir, fileOwner, secret, !landlock)
+		if err != nil {
+			pluginClient.Kill()
+			return nil, nil, err
+		}
+		env = append(public, envFileVar+"="+envFile)
+	}
+
 	execCmd := &executor.ExecCommand{
-		Cmd:              driverConfig.Command,
-		Args:             driverConfig.Args,
-		Env:              cfg.EnvList(),
-		User:             user,
-		ResourceLimits:   true,
-		NoPivotRoot:      d.config.NoPivotRoot,
-		Resources:        cfg.Resources,
-		TaskDir:          cfg.TaskDir().Dir,
-		StdoutPath:       cfg.StdoutPath,
-		StderrPath:       cfg.StderrPath,
-		Mounts:           cfg.Mounts,
-		Devices:          cfg.Devices,
-		NetworkIsolation: cfg.NetworkIsolation,
-		ModePID:          executor.IsolationMode(d.config.DefaultModePID, driverConfig.ModePID),
-		ModeIPC:          executor.IsolationMode(d.config.DefaultModeIPC, driverConfig.ModeIPC),
-		Capabilities:     caps,
+		Cmd:                driverConfig.Command,
+		Args:               driverConfig.Args,
+		Env:                env,
+		User:               user,
+		ResourceLimits:     true,
+		NoPivotRoot:        d.config.NoPivotRoot,
+		Resources:          cfg.Resources,
+		TaskDir:            cfg.TaskDir().Dir,
+		StdoutPath:         cfg.StdoutPath,
+		StderrPath:         cfg.StderrPath,
+		Mounts:             cfg.Mounts,
+		Devices:            cfg.Devices,
+		NetworkIsolation:   cfg.NetworkIsolation,
+		ModePID:            executor.IsolationMode(d.config.DefaultModePID, driverConfig.ModePID),
+		ModeIPC:            executor.IsolationMode(d.config.DefaultModeIPC, driverConfig.ModeIPC),
+		Capabilities:       caps,
+		Process:            cfg.Process,
+		Sandbox:            sandbox,
+		UserNamespace:      d.config.Rootless,
+		IOWeight:           driverConfig.IOWeight,
+		CgroupFreezeOnStop: d.config.UseCgroupFreezeOnStop,
+		RestoreDir:         restoreDir,
 	}
 
 	ps, err := exec.Launch(execCmd)
@@ -508,6 +986,7 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		procState:    drivers.TaskStateRunning,
 		startedAt:    time.Now().Round(time.Millisecond),
 		logger:       d.logger,
+		eventer:      d.eventer,
 	}
 
 	driverState := TaskState{
@@ -526,7 +1005,21 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 
 	d.tasks.Set(cfg.ID, h)
 	go h.run()
-	return handle, nil, nil
+	return handle, driverNetwork(cfg.NetworkIsolation), nil
+}
+
+// driverNetwork returns the network of a task joining the network namespace
+// of its allocation, or nil if the task uses the host network.
+func driverNetwork(spec *drivers.NetworkIsolationSpec) *drivers.DriverNetwork {
+	if spec == nil || spec.Mode != drivers.NetIsolationModeGroup {
+		return nil
+	}
+	if spec.HostsConfig == nil || spec.HostsConfig.Address == "" {
+		return nil
+	}
+	return &drivers.DriverNetwork{
+		IP: spec.HostsConfig.Address,
+	}
 }
 
 func (d *Driver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
@@ -551,8 +1044,9 @@ func (d *Driver) handleWait(ctx context.Context, handle *taskHandle, ch chan *dr
 		}
 	} else {
 		result = &drivers.ExitResult{
-			ExitCode: ps.ExitCode,
-			Signal:   ps.Signal,
+			ExitCode:  ps.ExitCode,
+			Signal:    ps.Signal,
+			OOMKilled: ps.OOMKilled,
 		}
 	}
 
@@ -677,20 +1171,11 @@ func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*
 		args = cmd[1:]
 	}
 
-	out, exitCode, err := handle.exec.Exec(time.Now().Add(timeout), cmd[0], args)
-	if err != nil {
-		return nil, err
-	}
-
-	return &drivers.ExecTaskResult{
-		Stdout: out,
-		ExitResult: &drivers.ExitResult{
-			ExitCode: exitCode,
-		},
-	}, nil
+	return handle.exec.Exec(time.Now().Add(timeout), cmd[0], args)
 }
 
 var _ drivers.ExecTaskStreamingRawDriver = (*Driver)(nil)
+var _ drivers.CheckpointDriver = (*Driver)(nil)
 
 func (d *Driver) ExecTaskStreamingRaw(ctx context.Context,
 	taskID string,
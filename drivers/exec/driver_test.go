@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	osexec "os/exec"
+	"os/user"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -110,6 +112,13 @@ func TestExecDriver_Fingerprint(t *testing.T) {
 	case finger := <-fingerCh:
 		require.Equal(drivers.HealthStateHealthy, finger.Health)
 		require.True(finger.Attributes["driver.exec"].GetBool())
+		cgroups, ok := finger.Attributes["driver.exec.cgroups"].GetString()
+		require.True(ok)
+		if cgutil.UseV2 {
+			require.Equal("v2", cgroups)
+		} else {
+			require.Equal("v1", cgroups)
+		}
 	case <-time.After(time.Duration(testutil.TestMultiplier()*5) * time.Second):
 		require.Fail("timeout receiving fingerprint")
 	}
@@ -153,6 +162,54 @@ func TestExecDriver_StartWait(t *testing.T) {
 	require.NoError(t, harness.DestroyTask(task.ID, true))
 }
 
+func TestExecDriver_OOMKilled(t *testing.T) {
+	ci.Parallel(t)
+	ctestutils.ExecCompatible(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+	allocID := uuid.Generate()
+	task := &drivers.TaskConfig{
+		AllocID:   allocID,
+		ID:        uuid.Generate(),
+		Name:      "test",
+		Resources: testResources(allocID, "test"),
+	}
+
+	// Grow a string beyond the 128MB memory limit of the task
+	tc := &TaskConfig{
+		Command: "/bin/bash",
+		Args:    []string{"-c", "x=a; while true; do x=$x$x; done"},
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+
+	cleanup := harness.MkAllocDir(task, false)
+	defer cleanup()
+
+	events, err := harness.TaskEvents(ctx)
+	require.NoError(t, err)
+
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+	defer harness.DestroyTask(task.ID, true)
+
+	ch, err := harness.WaitTask(context.Background(), handle.Config.ID)
+	require.NoError(t, err)
+	result := <-ch
+	require.True(t, result.OOMKilled, "expected OOM kill: %v", result)
+
+	select {
+	case event := <-events:
+		require.Equal(t, task.ID, event.TaskID)
+		require.Equal(t, "OOM Killed", event.Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for OOM task event")
+	}
+}
+
 func TestExecDriver_StartWaitStopKill(t *testing.T) {
 	ci.Parallel(t)
 	ctestutils.ExecCompatible(t)
@@ -433,6 +490,9 @@ func TestExecDriver_Stats(t *testing.T) {
 		Resources: testResources(allocID, "test"),
 	}
 
+	// Oversubscribe memory, so both limits are reported
+	task.Resources.NomadResources.Memory.MemoryMaxMB = 256
+
 	tc := &TaskConfig{
 		Command: "/bin/sleep",
 		Args:    []string{"5"},
@@ -454,6 +514,8 @@ func TestExecDriver_Stats(t *testing.T) {
 	select {
 	case stats := <-statsCh:
 		require.NotEmpty(t, stats.ResourceUsage.MemoryStats.Measured)
+		require.EqualValues(t, 256*1024*1024, stats.ResourceUsage.MemoryStats.Limit)
+		require.EqualValues(t, 128*1024*1024, stats.ResourceUsage.MemoryStats.Reservation)
 		require.NotZero(t, stats.Timestamp)
 		require.WithinDuration(t, time.Now(), time.Unix(0, stats.Timestamp), time.Second)
 	case <-time.After(time.Second):
@@ -611,9 +673,10 @@ func TestExecDriver_HandlerExec(t *testing.T) {
 	res, err = harness.ExecTask(task.ID, []string{"/usr/bin/stat", "lkjhdsaflkjshowaisxmcvnlia"}, time.Second)
 	require.NoError(t, err)
 	require.False(t, res.ExitResult.Successful())
-	if expected := "No such file or directory"; !bytes.Contains(res.Stdout, []byte(expected)) {
-		t.Fatalf("expected output to contain %q but found: %q", expected, res.Stdout)
+	if expected := "No such file or directory"; !bytes.Contains(res.Stderr, []byte(expected)) {
+		t.Fatalf("expected stderr to contain %q but found: %q", expected, res.Stderr)
 	}
+	require.Empty(t, res.Stdout)
 
 	require.NoError(t, harness.DestroyTask(task.ID, true))
 }
@@ -719,11 +782,32 @@ func TestConfig_ParseAllHCL(t *testing.T) {
 config {
   command = "/bin/bash"
   args = ["-c", "echo hello"]
+  secret_env = ["DB_PASSWORD"]
+  io_weight = 500
+  tmpfs {
+    path = "/scratch"
+    size = 64
+  }
+  devices {
+    host_path = "/dev/fuse"
+  }
+  devices {
+    host_path          = "/dev/null"
+    task_path          = "/dev/empty"
+    cgroup_permissions = "rw"
+  }
 }`
 
 	expected := &TaskConfig{
-		Command: "/bin/bash",
-		Args:    []string{"-c", "echo hello"},
+		Command:   "/bin/bash",
+		Args:      []string{"-c", "echo hello"},
+		SecretEnv: []string{"DB_PASSWORD"},
+		IOWeight:  500,
+		Tmpfs:     []TmpfsMount{{Path: "/scratch", SizeMB: 64}},
+		Devices: []TaskDevice{
+			{HostPath: "/dev/fuse"},
+			{HostPath: "/dev/null", TaskPath: "/dev/empty", CgroupPermissions: "rw"},
+		},
 	}
 
 	var tc *TaskConfig
@@ -731,6 +815,44 @@ config {
 	require.EqualValues(t, expected, tc)
 }
 
+func TestExecDriver_SecretEnv(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("env file ownership is not supported on windows")
+	}
+
+	env := map[string]string{
+		"DB_PASSWORD": "it's a secret",
+		"HOME":        "/home/nobody",
+	}
+	public, secret := splitSecretEnv(env, []string{"DB_PASSWORD", "MISSING"})
+	require.Equal(t, []string{"HOME=/home/nobody"}, public)
+	require.Equal(t, map[string]string{"DB_PASSWORD": "it's a secret"}, secret)
+
+	u, err := user.Current()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	path, err := writeEnvFile(dir, u.Username, secret, true)
+	require.NoError(t, err)
+	require.Equal(t, "/secrets/.nomad_env", path)
+
+	// Writing the file again, as on task restart, replaces it. Without a
+	// chroot the task sees the file at its path on the host.
+	path, err = writeEnvFile(dir, u.Username, secret, false)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, envFileName), path)
+
+	fi, err := os.Stat(filepath.Join(dir, envFileName))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0400), fi.Mode().Perm())
+
+	out, err := osexec.Command("/bin/sh", "-c",
+		". "+filepath.Join(dir, envFileName)+` && printf %s "$DB_PASSWORD"`).Output()
+	require.NoError(t, err)
+	require.Equal(t, "it's a secret", string(out))
+}
+
 func TestExecDriver_NoPivotRoot(t *testing.T) {
 	ci.Parallel(t)
 	ctestutils.ExecCompatible(t)
@@ -813,6 +935,328 @@ func TestDriver_Config_validate(t *testing.T) {
 			}).validate())
 		}
 	})
+
+	t.Run("allowed_host_paths", func(t *testing.T) {
+		for _, tc := range []struct {
+			path string
+			exp  error
+		}{
+			{path: "/srv/data", exp: nil},
+			{path: "/srv/*/shared", exp: nil},
+			{path: "srv/data", exp: errors.New(`allowed_host_paths path "srv/data" must be absolute`)},
+			{path: "/srv/[", exp: errors.New(`allowed_host_paths path "/srv/[" is not a valid pattern: syntax error in pattern`)},
+		} {
+			require.Equal(t, tc.exp, (&Config{
+				DefaultModePID:   "private",
+				DefaultModeIPC:   "private",
+				AllowedHostPaths: []*AllowedHostPath{{Path: tc.path}},
+			}).validate())
+		}
+	})
+
+	t.Run("isolation", func(t *testing.T) {
+		for _, tc := range []struct {
+			isolation string
+			unveil    []string
+			exp       error
+		}{
+			{isolation: "chroot", exp: nil},
+			{isolation: "landlock", unveil: []string{"rx:/opt/bin", "rw:/var/run/app.sock"}, exp: nil},
+			{isolation: "jail", exp: errors.New(`isolation must be "chroot" or "landlock", got "jail"`)},
+			{isolation: "landlock", unveil: []string{"/opt/bin"}, exp: errors.New(`unveil path "/opt/bin" must be in the form mode:path`)},
+			{isolation: "landlock", unveil: []string{"rq:/opt/bin"}, exp: errors.New(`sandbox path "/opt/bin" has invalid mode "rq": must be a combination of r, w, x and c`)},
+		} {
+			require.Equal(t, tc.exp, (&Config{
+				DefaultModePID: "private",
+				DefaultModeIPC: "private",
+				Isolation:      tc.isolation,
+				UnveilPaths:    tc.unveil,
+			}).validate())
+		}
+	})
+
+	t.Run("rootless", func(t *testing.T) {
+		require.NoError(t, (&Config{
+			DefaultModePID: "private",
+			DefaultModeIPC: "private",
+			Rootless:       true,
+		}).validate())
+		require.EqualError(t, (&Config{
+			DefaultModePID: "private",
+			DefaultModeIPC: "private",
+			Isolation:      "landlock",
+			Rootless:       true,
+		}).validate(), "rootless can't be used with landlock isolation")
+	})
+
+	t.Run("executor_log", func(t *testing.T) {
+		for _, tc := range []struct {
+			level       string
+			size, files int
+			exp         error
+		}{
+			{level: "", exp: nil},
+			{level: "info", size: 10, files: 2, exp: nil},
+			{level: "TRACE", exp: nil},
+			{level: "verbose", exp: errors.New(`executor_log_level "verbose" is not a valid log level`)},
+			{level: "debug", size: -1, exp: errors.New("executor_log_max_file_size must not be negative, got -1")},
+			{level: "debug", files: -1, exp: errors.New("executor_log_max_files must not be negative, got -1")},
+		} {
+			require.Equal(t, tc.exp, (&Config{
+				DefaultModePID:         "private",
+				DefaultModeIPC:         "private",
+				ExecutorLogLevel:       tc.level,
+				ExecutorLogMaxFileSize: tc.size,
+				ExecutorLogMaxFiles:    tc.files,
+			}).validate())
+		}
+	})
+
+	t.Run("chroot_env", func(t *testing.T) {
+		for _, tc := range []struct {
+			isolation string
+			chrootEnv map[string]string
+			exp       error
+		}{
+			{chrootEnv: map[string]string{"/usr": "/usr", "/opt/tools/bin": "/usr/local/bin"}, exp: nil},
+			{chrootEnv: map[string]string{"usr": "/usr"}, exp: errors.New(`chroot_env host path "usr" must be absolute`)},
+			{chrootEnv: map[string]string{"/usr": "usr"}, exp: errors.New(`chroot_env task path "usr" must be absolute`)},
+			{chrootEnv: map[string]string{"/srv": "/local/"}, exp: errors.New(`chroot_env task path "/local/" is reserved`)},
+			{chrootEnv: map[string]string{"/dev": "/dev"}, exp: errors.New(`chroot_env task path "/dev" is reserved`)},
+			{isolation: "landlock", chrootEnv: map[string]string{"/usr": "/usr"}, exp: errors.New("chroot_env can't be used with landlock isolation")},
+		} {
+			require.Equal(t, tc.exp, (&Config{
+				DefaultModePID: "private",
+				DefaultModeIPC: "private",
+				Isolation:      tc.isolation,
+				ChrootEnv:      tc.chrootEnv,
+			}).validate())
+		}
+	})
+}
+
+func TestDriver_validateTaskChrootEnv(t *testing.T) {
+	ci.Parallel(t)
+
+	d := &Driver{}
+
+	// Without a chroot_env plugin option tasks may use the default chroot
+	require.NoError(t, d.validateTaskChrootEnv(map[string]string{"/usr/share/zoneinfo": "/zoneinfo"}))
+	require.EqualError(t, d.validateTaskChrootEnv(map[string]string{"/opt/tools": "/opt/tools"}),
+		`chroot_env host path "/opt/tools" is not part of the driver chroot`)
+
+	d.config.ChrootEnv = map[string]string{"/opt/tools": "/opt/tools"}
+	require.NoError(t, d.validateTaskChrootEnv(map[string]string{"/opt/tools/bin": "/bin"}))
+	require.EqualError(t, d.validateTaskChrootEnv(map[string]string{"/usr": "/usr"}),
+		`chroot_env host path "/usr" is not part of the driver chroot`)
+	require.EqualError(t, d.validateTaskChrootEnv(map[string]string{"/opt/tools/../../etc": "/etc"}),
+		`chroot_env host path "/opt/tools/../../etc" is not part of the driver chroot`)
+
+	// allowed_host_paths grants additional paths
+	d.config.AllowedHostPaths = []*AllowedHostPath{{Path: "/srv/*"}}
+	require.NoError(t, d.validateTaskChrootEnv(map[string]string{"/srv/data": "/data"}))
+}
+
+func TestDriver_chrootMounts(t *testing.T) {
+	ci.Parallel(t)
+
+	hostDir := t.TempDir()
+	bin := filepath.Join(hostDir, "bin")
+	lib := filepath.Join(hostDir, "lib")
+	require.NoError(t, os.Mkdir(bin, 0755))
+	require.NoError(t, os.Mkdir(lib, 0755))
+
+	d := &Driver{
+		logger: testlog.HCLogger(t),
+		config: Config{
+			ChrootEnv: map[string]string{
+				hostDir:                        "/opt",
+				lib:                            "/lib",
+				filepath.Join(hostDir, "nope"): "/nope",
+			},
+		},
+	}
+
+	mounts := d.chrootMounts(map[string]string{bin: "/opt/"})
+	require.Equal(t, []*drivers.MountConfig{
+		{HostPath: lib, TaskPath: "/lib", Readonly: true},
+		{HostPath: bin, TaskPath: "/opt", Readonly: true},
+	}, mounts)
+}
+
+func TestDriver_sandboxConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	task := &drivers.TaskConfig{
+		AllocID:  "123",
+		Name:     "web",
+		AllocDir: "/var/nomad/alloc/123",
+	}
+
+	d := &Driver{config: Config{
+		Isolation:      isolationLandlock,
+		UnveilDefaults: false,
+		UnveilPaths:    []string{"r:/opt/app"},
+	}}
+
+	_, err := d.sandboxConfig(task, []string{"rw:/srv/data"})
+	require.EqualError(t, err, "unveil is not allowed unless unveil_by_task is enabled in the plugin configuration")
+
+	d.config.UnveilByTask = true
+	sandbox, err := d.sandboxConfig(task, []string{"rw:/srv/data"})
+	require.NoError(t, err)
+	require.Equal(t, []*executor.SandboxPath{
+		{Path: "/var/nomad/alloc/123/web", Mode: "rwxc"},
+		{Path: "/var/nomad/alloc/123/alloc", Mode: "rwxc"},
+		{Path: "/opt/app", Mode: "r"},
+		{Path: "/srv/data", Mode: "rw"},
+	}, sandbox.Paths)
+
+	d.config.UnveilDefaults = true
+	sandbox, err = d.sandboxConfig(task, nil)
+	require.NoError(t, err)
+	require.Len(t, sandbox.Paths, 2+len(defaultUnveilPaths)+1)
+}
+
+func TestDriver_validateHostPaths(t *testing.T) {
+	ci.Parallel(t)
+
+	allowed := []*AllowedHostPath{
+		{Path: "/srv/data"},
+		{Path: "/etc/ssl/*", ReadOnly: true},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		allowed []*AllowedHostPath
+		mount   *drivers.MountConfig
+		exp     string
+	}{
+		{
+			name:  "unrestricted",
+			mount: &drivers.MountConfig{HostPath: "/etc"},
+		},
+		{
+			name:    "exact path",
+			allowed: allowed,
+			mount:   &drivers.MountConfig{HostPath: "/srv/data"},
+		},
+		{
+			name:    "subpath",
+			allowed: allowed,
+			mount:   &drivers.MountConfig{HostPath: "/srv/data/app"},
+		},
+		{
+			name:    "alloc dir",
+			allowed: allowed,
+			mount:   &drivers.MountConfig{HostPath: "/var/nomad/alloc/123/alloc/data"},
+		},
+		{
+			name:    "not allowed",
+			allowed: allowed,
+			mount:   &drivers.MountConfig{HostPath: "/srv/database"},
+			exp:     `host path "/srv/database" is not allowed by the driver configuration`,
+		},
+		{
+			name:    "escaping path",
+			allowed: allowed,
+			mount:   &drivers.MountConfig{HostPath: "/srv/data/../../etc"},
+			exp:     `host path "/srv/data/../../etc" is not allowed by the driver configuration`,
+		},
+		{
+			name:    "glob read-only",
+			allowed: allowed,
+			mount:   &drivers.MountConfig{HostPath: "/etc/ssl/certs", Readonly: true},
+		},
+		{
+			name:    "glob writable",
+			allowed: allowed,
+			mount:   &drivers.MountConfig{HostPath: "/etc/ssl/certs"},
+			exp:     `host path "/etc/ssl/certs" must be mounted read-only`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateHostPaths(tc.allowed, "/var/nomad/alloc/123", []*drivers.MountConfig{tc.mount})
+			if tc.exp == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.exp)
+			}
+		})
+	}
+}
+
+func TestDriver_validateHostDevices(t *testing.T) {
+	ci.Parallel(t)
+
+	allowed := []*AllowedHostPath{
+		{Path: "/dev/fuse"},
+		{Path: "/dev/video*", ReadOnly: true},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		allowed []*AllowedHostPath
+		device  *drivers.DeviceConfig
+		exp     string
+	}{
+		{
+			name:   "unrestricted",
+			device: &drivers.DeviceConfig{HostPath: "/dev/sda", Permissions: "rwm"},
+		},
+		{
+			name:    "allowed",
+			allowed: allowed,
+			device:  &drivers.DeviceConfig{HostPath: "/dev/fuse", Permissions: "rwm"},
+		},
+		{
+			name:    "not allowed",
+			allowed: allowed,
+			device:  &drivers.DeviceConfig{HostPath: "/dev/sda", Permissions: "r"},
+			exp:     `device "/dev/sda" is not allowed by the driver configuration`,
+		},
+		{
+			name:    "glob read-only",
+			allowed: allowed,
+			device:  &drivers.DeviceConfig{HostPath: "/dev/video0", Permissions: "r"},
+		},
+		{
+			name:    "glob writable",
+			allowed: allowed,
+			device:  &drivers.DeviceConfig{HostPath: "/dev/video0", Permissions: "rw"},
+			exp:     `device "/dev/video0" must be requested with read-only permissions`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateHostDevices(tc.allowed, []*drivers.DeviceConfig{tc.device})
+			if tc.exp == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.exp)
+			}
+		})
+	}
+}
+
+func TestDriver_driverNetwork(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Nil(t, driverNetwork(nil))
+	require.Nil(t, driverNetwork(&drivers.NetworkIsolationSpec{
+		Mode: drivers.NetIsolationModeHost,
+	}))
+	require.Nil(t, driverNetwork(&drivers.NetworkIsolationSpec{
+		Mode: drivers.NetIsolationModeGroup,
+		Path: "/var/run/netns/abc",
+	}))
+
+	net := driverNetwork(&drivers.NetworkIsolationSpec{
+		Mode:        drivers.NetIsolationModeGroup,
+		Path:        "/var/run/netns/abc",
+		HostsConfig: &drivers.HostsConfig{Address: "172.26.64.2"},
+	})
+	require.Equal(t, &drivers.DriverNetwork{IP: "172.26.64.2"}, net)
+	require.False(t, net.Advertise())
 }
 
 func TestDriver_TaskConfig_validate(t *testing.T) {
@@ -872,4 +1316,55 @@ func TestDriver_TaskConfig_validate(t *testing.T) {
 			}).validate())
 		}
 	})
+
+	t.Run("io_weight", func(t *testing.T) {
+		for _, tc := range []struct {
+			weight uint16
+			exp    error
+		}{
+			{weight: 0, exp: nil},
+			{weight: 10, exp: nil},
+			{weight: 1000, exp: nil},
+			{weight: 5, exp: errors.New("io_weight must be between 10 and 1000, got 5")},
+			{weight: 1001, exp: errors.New("io_weight must be between 10 and 1000, got 1001")},
+		} {
+			require.Equal(t, tc.exp, (&TaskConfig{
+				IOWeight: tc.weight,
+			}).validate())
+		}
+	})
+
+	t.Run("tmpfs", func(t *testing.T) {
+		for _, tc := range []struct {
+			tmpfs TmpfsMount
+			exp   error
+		}{
+			{tmpfs: TmpfsMount{Path: "/scratch"}, exp: nil},
+			{tmpfs: TmpfsMount{Path: "/scratch", SizeMB: 64}, exp: nil},
+			{tmpfs: TmpfsMount{Path: "scratch"}, exp: errors.New(`tmpfs path "scratch" must be absolute`)},
+			{tmpfs: TmpfsMount{Path: "/scratch", SizeMB: -1}, exp: errors.New("tmpfs size must not be negative, got -1")},
+		} {
+			require.Equal(t, tc.exp, (&TaskConfig{
+				Tmpfs: []TmpfsMount{tc.tmpfs},
+			}).validate())
+		}
+	})
+
+	t.Run("devices", func(t *testing.T) {
+		for _, tc := range []struct {
+			device TaskDevice
+			exp    error
+		}{
+			{device: TaskDevice{HostPath: "/dev/fuse"}, exp: nil},
+			{device: TaskDevice{HostPath: "/dev/null", TaskPath: "/dev/empty", CgroupPermissions: "rw"}, exp: nil},
+			{device: TaskDevice{HostPath: "fuse"}, exp: errors.New(`device host_path "fuse" must be absolute`)},
+			{device: TaskDevice{HostPath: "/dev/null", TaskPath: "empty"}, exp: errors.New(`device task_path "empty" must be absolute`)},
+			{device: TaskDevice{HostPath: "/dev/null", CgroupPermissions: "rx"}, exp: errors.New(`invalid device cgroup_permissions "rx"`)},
+			{device: TaskDevice{HostPath: "/dev/null", CgroupPermissions: "rr"}, exp: errors.New(`invalid device cgroup_permissions "rr"`)},
+		} {
+			require.Equal(t, tc.exp, (&TaskConfig{
+				Devices: []TaskDevice{tc.device},
+			}).validate())
+		}
+	})
 }
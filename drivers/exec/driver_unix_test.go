@@ -5,6 +5,9 @@ package exec
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -319,3 +322,272 @@ func TestExecDriver_Capabilities(t *testing.T) {
 		})
 	}
 }
+
+func TestExecDriver_Landlock(t *testing.T) {
+	ci.Parallel(t)
+	ctestutils.ExecCompatible(t)
+	if _, err := executor.LandlockABI(); err != nil {
+		t.Skipf("landlock not available: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	config := &Config{
+		DefaultModePID: executor.IsolationModePrivate,
+		DefaultModeIPC: executor.IsolationModePrivate,
+		Isolation:      isolationLandlock,
+		UnveilDefaults: true,
+	}
+	var data []byte
+	require.NoError(t, basePlug.MsgPackEncode(&data, config))
+	require.NoError(t, harness.SetConfig(&basePlug.Config{PluginConfig: data}))
+
+	caps, err := d.Capabilities()
+	require.NoError(t, err)
+	require.Equal(t, drivers.FSIsolationNone, caps.FSIsolation)
+	require.False(t, caps.Checkpoint)
+
+	// a file outside of the allocation isn't unveiled to the task
+	hidden := t.TempDir()
+
+	allocID := uuid.Generate()
+	task := &drivers.TaskConfig{
+		AllocID:   allocID,
+		ID:        uuid.Generate(),
+		Name:      "test",
+		Resources: testResources(allocID, "test"),
+		// the test binary isn't executable by nobody
+		User: "root",
+	}
+	cleanup := harness.MkAllocDir(task, false)
+	defer cleanup()
+
+	tc := &TaskConfig{
+		Command: "/bin/sh",
+		Args:    []string{"-c", fmt.Sprintf("echo ok > local/out.txt && ls %s", hidden)},
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+	defer harness.DestroyTask(task.ID, true)
+
+	ch, err := harness.WaitTask(context.Background(), handle.Config.ID)
+	require.NoError(t, err)
+	result := <-ch
+	require.NotZero(t, result.ExitCode)
+
+	out, err := os.ReadFile(filepath.Join(task.TaskDir().LocalDir, "out.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "ok\n", string(out))
+
+	// tasks can't unveil paths unless allowed by the plugin config
+	task.ID = uuid.Generate()
+	tc.Unveil = []string{"r:" + hidden}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+	_, _, err = harness.StartTask(task)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unveil is not allowed unless unveil_by_task is enabled")
+}
+
+func TestExecDriver_ChrootEnv(t *testing.T) {
+	ci.Parallel(t)
+	ctestutils.ExecCompatible(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	// a host directory only bind mounted into the chroot of the task
+	dataDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "data.txt"), []byte("hello\n"), 0644))
+
+	config := &Config{
+		DefaultModePID: executor.IsolationModePrivate,
+		DefaultModeIPC: executor.IsolationModePrivate,
+		ChrootEnv: map[string]string{
+			"/bin":   "/bin",
+			"/etc":   "/etc",
+			"/lib":   "/lib",
+			"/lib64": "/lib64",
+			"/usr":   "/usr",
+			dataDir:  "/opt/data",
+		},
+	}
+	var data []byte
+	require.NoError(t, basePlug.MsgPackEncode(&data, config))
+	require.NoError(t, harness.SetConfig(&basePlug.Config{PluginConfig: data}))
+
+	caps, err := d.Capabilities()
+	require.NoError(t, err)
+	require.Equal(t, drivers.FSIsolationImage, caps.FSIsolation)
+
+	allocID := uuid.Generate()
+	task := &drivers.TaskConfig{
+		AllocID:   allocID,
+		ID:        uuid.Generate(),
+		Name:      "test",
+		Resources: testResources(allocID, "test"),
+	}
+	cleanup := harness.MkAllocDir(task, false)
+	defer cleanup()
+
+	tc := &TaskConfig{
+		Command: "/bin/sh",
+		Args:    []string{"-c", "cat /opt/data/data.txt > local/out.txt && ! test -e /sbin"},
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+	defer harness.DestroyTask(task.ID, true)
+
+	ch, err := harness.WaitTask(context.Background(), handle.Config.ID)
+	require.NoError(t, err)
+	result := <-ch
+	require.Zero(t, result.ExitCode)
+
+	out, err := os.ReadFile(filepath.Join(task.TaskDir().LocalDir, "out.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello\n", string(out))
+
+	// tasks can't bind host paths outside of the driver chroot
+	task.ID = uuid.Generate()
+	tc.ChrootEnv = map[string]string{"/var": "/var"}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+	_, _, err = harness.StartTask(task)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `chroot_env host path "/var" is not part of the driver chroot`)
+}
+
+func TestExecDriver_TmpfsAndDevices(t *testing.T) {
+	ci.Parallel(t)
+	ctestutils.ExecCompatible(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	config := &Config{
+		DefaultModePID: executor.IsolationModePrivate,
+		DefaultModeIPC: executor.IsolationModePrivate,
+	}
+	var data []byte
+	require.NoError(t, basePlug.MsgPackEncode(&data, config))
+	require.NoError(t, harness.SetConfig(&basePlug.Config{PluginConfig: data}))
+
+	allocID := uuid.Generate()
+	task := &drivers.TaskConfig{
+		AllocID:   allocID,
+		ID:        uuid.Generate(),
+		Name:      "test",
+		Resources: testResources(allocID, "test"),
+	}
+	cleanup := harness.MkAllocDir(task, false)
+	defer cleanup()
+
+	tc := &TaskConfig{
+		Command: "/bin/sh",
+		Args: []string{"-c", `while read -r line; do
+  case "$line" in *" /scratch "*) echo "$line" > local/out.txt ;; esac
+done < /proc/mounts
+test -c /dev/zero-copy && : < /dev/zero-copy && echo readable >> local/out.txt`},
+		Tmpfs:   []TmpfsMount{{Path: "/scratch", SizeMB: 4}},
+		Devices: []TaskDevice{{HostPath: "/dev/zero", TaskPath: "/dev/zero-copy", CgroupPermissions: "r"}},
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+	defer harness.DestroyTask(task.ID, true)
+
+	ch, err := harness.WaitTask(context.Background(), handle.Config.ID)
+	require.NoError(t, err)
+	result := <-ch
+	require.Zero(t, result.ExitCode)
+
+	out, err := os.ReadFile(filepath.Join(task.TaskDir().LocalDir, "out.txt"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, 2)
+	require.Regexp(t, `^tmpfs /scratch tmpfs .*size=4096k`, lines[0])
+	require.Equal(t, "readable", lines[1])
+}
+
+func TestExecDriver_Rootless(t *testing.T) {
+	ci.Parallel(t)
+	ctestutils.ExecCompatible(t)
+	if err := userNamespacesSupported(procRoot); err != nil {
+		t.Skipf("user namespaces not available: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	config := &Config{
+		DefaultModePID: executor.IsolationModePrivate,
+		DefaultModeIPC: executor.IsolationModePrivate,
+		Rootless:       true,
+	}
+	var data []byte
+	require.NoError(t, basePlug.MsgPackEncode(&data, config))
+	require.NoError(t, harness.SetConfig(&basePlug.Config{PluginConfig: data}))
+
+	fp := d.(*Driver).buildFingerprint()
+	require.Equal(t, drivers.HealthStateHealthy, fp.Health)
+	rootless, ok := fp.Attributes["driver.exec.rootless"].GetBool()
+	require.True(t, ok)
+	require.True(t, rootless)
+
+	allocID := uuid.Generate()
+	task := &drivers.TaskConfig{
+		AllocID:   allocID,
+		ID:        uuid.Generate(),
+		Name:      "test",
+		Resources: testResources(allocID, "test"),
+	}
+	cleanup := harness.MkAllocDir(task, false)
+	defer cleanup()
+
+	tc := &TaskConfig{
+		Command: "/bin/sh",
+		Args:    []string{"-c", "cat /proc/self/uid_map > local/out.txt"},
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+	defer harness.DestroyTask(task.ID, true)
+
+	ch, err := harness.WaitTask(context.Background(), handle.Config.ID)
+	require.NoError(t, err)
+	result := <-ch
+	require.Zero(t, result.ExitCode)
+
+	// root in the task is mapped to the user running the client
+	out, err := os.ReadFile(filepath.Join(task.TaskDir().LocalDir, "out.txt"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"0", strconv.Itoa(os.Geteuid()), "1"}, strings.Fields(string(out)))
+
+	// tasks can't run as another user
+	task.ID = uuid.Generate()
+	task.User = "nobody"
+	_, _, err = harness.StartTask(task)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `user "nobody" can't be used in rootless mode`)
+}
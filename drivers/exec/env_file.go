@@ -0,0 +1,112 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+)
+
+const (
+	// envFileName is the name of the file in the task's secrets directory
+	// that holds the variables listed in the secret_env option.
+	envFileName = ".nomad_env"
+
+	// envFileVar is the environment variable pointing the task to the env
+	// file, as seen from inside the task.
+	envFileVar = "NOMAD_ENV_FILE"
+)
+
+// splitSecretEnv splits the task environment into the list of variables
+// passed through the process environment and the variables named in secret,
+// which must not be visible in the process environment.
+func splitSecretEnv(env map[string]string, secret []string) ([]string, map[string]string) {
+	names := make(map[string]struct{}, len(secret))
+	for _, name := range secret {
+		names[name] = struct{}{}
+	}
+
+	public := make([]string, 0, len(env))
+	hidden := make(map[string]string, len(secret))
+	for k, v := range env {
+		if _, ok := names[k]; ok {
+			hidden[k] = v
+			continue
+		}
+		public = append(public, k+"="+v)
+	}
+
+	sort.Strings(public)
+	return public, hidden
+}
+
+// writeEnvFile writes the environment variables to the env file in the
+// secrets directory of the task, readable only by the task user. The
+// secrets directory is backed by tmpfs, so the values never reach the disk.
+// Variables are written one per line, quoted so that the file can be sourced
+// by a POSIX shell. It returns the path of the file as seen by the task, which
+// is the path on the host if the task does not run in a chroot.
+func writeEnvFile(secretsDir, username string, env map[string]string, chroot bool) (string, error) {
+	uid, gid, err := lookupUser(username)
+	if err != nil {
+		return "", err
+	}
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s='%s'\n", k, strings.ReplaceAll(env[k], "'", `'\''`))
+	}
+
+	path := filepath.Join(secretsDir, envFileName)
+
+	// Remove a file left by a previous run of the task so that the mode of
+	// the new file is always applied.
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to remove env file: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0400)
+	if err != nil {
+		return "", fmt.Errorf("failed to create env file: %v", err)
+	}
+	defer f.Close()
+
+	if err := f.Chown(uid, gid); err != nil {
+		return "", fmt.Errorf("failed to set owner of env file: %v", err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		return "", fmt.Errorf("failed to write env file: %v", err)
+	}
+
+	if !chroot {
+		return path, nil
+	}
+	return filepath.Join("/", allocdir.TaskSecrets, envFileName), nil
+}
+
+// lookupUser returns the uid and gid of the given user.
+func lookupUser(username string) (int, int, error) {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to look up user %q: %v", username, err)
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid uid %q for user %q", u.Uid, username)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid gid %q for user %q", u.Gid, username)
+	}
+	return uid, gid, nil
+}
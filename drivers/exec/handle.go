@@ -8,6 +8,7 @@ import (
 
 	hclog "github.com/hashicorp/go-hclog"
 	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/drivers/shared/eventer"
 	"github.com/hashicorp/nomad/drivers/shared/executor"
 	"github.com/hashicorp/nomad/plugins/drivers"
 )
@@ -17,6 +18,7 @@ type taskHandle struct {
 	pid          int
 	pluginClient *plugin.Client
 	logger       hclog.Logger
+	eventer      *eventer.Eventer
 
 	// stateLock syncs access to all fields below
 	stateLock sync.RWMutex
@@ -74,6 +76,18 @@ func (h *taskHandle) run() {
 	h.exitResult.ExitCode = ps.ExitCode
 	h.exitResult.Signal = ps.Signal
 	h.completedAt = ps.Time
+	h.exitResult.OOMKilled = ps.OOMKilled
 
-	// TODO: detect if the task OOMed
+	if ps.OOMKilled {
+		h.eventer.EmitEvent(&drivers.TaskEvent{
+			TaskID:    h.taskConfig.ID,
+			AllocID:   h.taskConfig.AllocID,
+			TaskName:  h.taskConfig.Name,
+			Timestamp: ps.Time,
+			Message:   "OOM Killed",
+			Annotations: map[string]string{
+				"exit_code": strconv.Itoa(ps.ExitCode),
+			},
+		})
+	}
 }
@@ -0,0 +1,113 @@
+package exec
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// AllowedHostPath is a host path that tasks may bind-mount, configured with
+// the allowed_host_paths plugin option.
+type AllowedHostPath struct {
+	// Path is an absolute path or glob pattern. Mounts of a matching path or
+	// of any path below it are allowed.
+	Path string `codec:"path"`
+
+	// ReadOnly requires matching paths to be mounted read-only.
+	ReadOnly bool `codec:"read_only"`
+}
+
+func (p *AllowedHostPath) validate() error {
+	if !filepath.IsAbs(p.Path) {
+		return fmt.Errorf("allowed_host_paths path %q must be absolute", p.Path)
+	}
+	if _, err := filepath.Match(p.Path, ""); err != nil {
+		return fmt.Errorf("allowed_host_paths path %q is not a valid pattern: %v", p.Path, err)
+	}
+	return nil
+}
+
+// matches returns true if the host path, or one of its parent directories,
+// matches the allowed path.
+func (p *AllowedHostPath) matches(hostPath string) bool {
+	for path := filepath.Clean(hostPath); ; path = filepath.Dir(path) {
+		if ok, _ := filepath.Match(p.Path, path); ok {
+			return true
+		}
+		if path == "/" {
+			return false
+		}
+	}
+}
+
+// validateHostPaths returns an error if a mount requested by the task binds
+// a host path not allowed by the allowed_host_paths plugin option, or
+// mounts a read-only path as writable. Mounts are not restricted when the
+// option isn't set. Paths within the allocation directory are managed by
+// Nomad and are always allowed.
+func validateHostPaths(allowed []*AllowedHostPath, allocDir string, mounts []*drivers.MountConfig) error {
+	if allowed == nil {
+		return nil
+	}
+
+	for _, m := range mounts {
+		if allocDir != "" && isSubpath(allocDir, m.HostPath) {
+			continue
+		}
+
+		var found, writable bool
+		for _, p := range allowed {
+			if p.matches(m.HostPath) {
+				found = true
+				writable = writable || !p.ReadOnly
+			}
+		}
+
+		switch {
+		case !found:
+			return fmt.Errorf("host path %q is not allowed by the driver configuration", m.HostPath)
+		case !writable && !m.Readonly:
+			return fmt.Errorf("host path %q must be mounted read-only", m.HostPath)
+		}
+	}
+	return nil
+}
+
+// validateHostDevices returns an error if a device requested by the task is
+// not allowed by the allowed_host_paths plugin option, or is requested with
+// write permissions while allowed read-only. Devices are not restricted when
+// the option isn't set.
+func validateHostDevices(allowed []*AllowedHostPath, devices []*drivers.DeviceConfig) error {
+	if allowed == nil {
+		return nil
+	}
+
+	for _, d := range devices {
+		var found, writable bool
+		for _, p := range allowed {
+			if p.matches(d.HostPath) {
+				found = true
+				writable = writable || !p.ReadOnly
+			}
+		}
+
+		switch {
+		case !found:
+			return fmt.Errorf("device %q is not allowed by the driver configuration", d.HostPath)
+		case !writable && strings.ContainsAny(d.Permissions, "wm"):
+			return fmt.Errorf("device %q must be requested with read-only permissions", d.HostPath)
+		}
+	}
+	return nil
+}
+
+// isSubpath returns true if path is dir or is within dir.
+func isSubpath(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,79 @@
+package exec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/drivers/shared/executor"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// isolationChroot runs tasks in a chroot built from the chroot_env of
+	// the client.
+	isolationChroot = "chroot"
+
+	// isolationLandlock runs tasks on the host filesystem, restricted with
+	// Landlock to the paths unveiled to them.
+	isolationLandlock = "landlock"
+)
+
+// defaultUnveilPaths are the paths unveiled to every task when the
+// unveil_defaults plugin option is set, enough to run common binaries.
+var defaultUnveilPaths = []string{
+	"rx:/bin",
+	"rx:/sbin",
+	"rx:/usr",
+	"rx:/lib",
+	"rx:/lib64",
+	"r:/etc",
+	"rw:/dev/null",
+	"r:/dev/random",
+	"r:/dev/urandom",
+}
+
+// parseUnveil parses a path grant in the form mode:path, such as rx:/usr.
+func parseUnveil(s string) (*executor.SandboxPath, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("unveil path %q must be in the form mode:path", s)
+	}
+	p := &executor.SandboxPath{Mode: parts[0], Path: parts[1]}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// sandboxConfig returns the paths a task is restricted to when running with
+// landlock isolation: its task and shared alloc directories, the paths
+// configured for the plugin and the paths requested by the task itself.
+func (d *Driver) sandboxConfig(cfg *drivers.TaskConfig, taskUnveil []string) (*executor.SandboxConfig, error) {
+	if len(taskUnveil) > 0 && !d.config.UnveilByTask {
+		return nil, fmt.Errorf("unveil is not allowed unless unveil_by_task is enabled in the plugin configuration")
+	}
+
+	taskDir := cfg.TaskDir()
+	sandbox := &executor.SandboxConfig{
+		Paths: []*executor.SandboxPath{
+			{Path: taskDir.Dir, Mode: "rwxc"},
+			{Path: taskDir.SharedAllocDir, Mode: "rwxc"},
+		},
+	}
+
+	var grants []string
+	if d.config.UnveilDefaults {
+		grants = append(grants, defaultUnveilPaths...)
+	}
+	grants = append(grants, d.config.UnveilPaths...)
+	grants = append(grants, taskUnveil...)
+
+	for _, s := range grants {
+		p, err := parseUnveil(s)
+		if err != nil {
+			return nil, err
+		}
+		sandbox.Paths = append(sandbox.Paths, p)
+	}
+	return sandbox, nil
+}
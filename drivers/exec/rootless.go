@@ -0,0 +1,49 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// procRoot is the mount point of procfs, read to detect whether the kernel
+// allows unprivileged user namespaces.
+const procRoot = "/proc"
+
+// userNamespacesSupported returns an error if the kernel doesn't allow
+// unprivileged users to create user namespaces.
+func userNamespacesSupported(proc string) error {
+	if _, err := os.Stat(filepath.Join(proc, "self/ns/user")); err != nil {
+		return fmt.Errorf("kernel doesn't support user namespaces")
+	}
+
+	// Sysctls that disable unprivileged user namespaces, and the value that
+	// disables them. Some are only present on specific distributions.
+	for _, sysctl := range []struct {
+		name, disabled string
+	}{
+		{"user/max_user_namespaces", "0"},
+		{"kernel/unprivileged_userns_clone", "0"},
+		{"kernel/apparmor_restrict_unprivileged_userns", "1"},
+	} {
+		b, err := os.ReadFile(filepath.Join(proc, "sys", sysctl.name))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(b)) == sysctl.disabled {
+			return fmt.Errorf("user namespaces are disabled by %s", strings.ReplaceAll(sysctl.name, "/", "."))
+		}
+	}
+	return nil
+}
+
+// currentUser returns the name of the user running the client.
+func currentUser() (string, error) {
+	u, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to look up current user: %v", err)
+	}
+	return u.Username, nil
+}
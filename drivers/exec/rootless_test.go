@@ -0,0 +1,36 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecDriver_userNamespacesSupported(t *testing.T) {
+	ci.Parallel(t)
+
+	proc := t.TempDir()
+	require.EqualError(t, userNamespacesSupported(proc), "kernel doesn't support user namespaces")
+
+	writeFile := func(name, content string) {
+		path := filepath.Join(proc, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	writeFile("self/ns/user", "")
+	require.NoError(t, userNamespacesSupported(proc))
+
+	writeFile("sys/user/max_user_namespaces", "15000\n")
+	writeFile("sys/kernel/unprivileged_userns_clone", "0\n")
+	require.EqualError(t, userNamespacesSupported(proc), "user namespaces are disabled by kernel.unprivileged_userns_clone")
+
+	writeFile("sys/kernel/unprivileged_userns_clone", "1\n")
+	writeFile("sys/kernel/apparmor_restrict_unprivileged_userns", "1\n")
+	require.EqualError(t, userNamespacesSupported(proc), "user namespaces are disabled by kernel.apparmor_restrict_unprivileged_userns")
+
+	writeFile("sys/kernel/apparmor_restrict_unprivileged_userns", "0\n")
+	require.NoError(t, userNamespacesSupported(proc))
+}
@@ -16,6 +16,7 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/drivers/shared/eventer"
 	"github.com/hashicorp/nomad/drivers/shared/executor"
+	"github.com/hashicorp/nomad/drivers/shared/proclimits"
 	"github.com/hashicorp/nomad/drivers/shared/resolvconf"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/plugins/base"
@@ -78,6 +79,7 @@ var (
 			hclspec.NewAttr("allow_caps", "list(string)", false),
 			hclspec.NewLiteral(capabilities.HCLSpecLiteral),
 		),
+		"process_limits": proclimits.Spec,
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -133,6 +135,10 @@ type Config struct {
 	// AllowCaps configures which Linux Capabilities are enabled for tasks
 	// running on this node.
 	AllowCaps []string `codec:"allow_caps"`
+
+	// ProcessLimits restricts the nice value, I/O scheduling class and
+	// resource limits the process block of tasks may request.
+	ProcessLimits *proclimits.Limits `codec:"process_limits"`
 }
 
 func (c *Config) validate() error {
@@ -153,6 +159,10 @@ func (c *Config) validate() error {
 		return fmt.Errorf("allow_caps configured with capabilities not supported by system: %s", badCaps)
 	}
 
+	if err := c.ProcessLimits.Validate(); err != nil {
+		return fmt.Errorf("invalid process_limits: %v", err)
+	}
+
 	return nil
 }
 
@@ -437,6 +447,10 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
 	}
 
+	if err := d.config.ProcessLimits.Check(cfg.Process); err != nil {
+		return nil, nil, fmt.Errorf("failed process validation: %v", err)
+	}
+
 	if driverConfig.Class == "" && driverConfig.JarPath == "" {
 		return nil, nil, fmt.Errorf("jar_path or class must be specified")
 	}
@@ -504,6 +518,7 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		ModePID:          executor.IsolationMode(d.config.DefaultModePID, driverConfig.ModePID),
 		ModeIPC:          executor.IsolationMode(d.config.DefaultModeIPC, driverConfig.ModeIPC),
 		Capabilities:     caps,
+		Process:          cfg.Process,
 	}
 
 	ps, err := exec.Launch(execCmd)
@@ -693,17 +708,7 @@ func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*
 		return nil, drivers.ErrTaskNotFound
 	}
 
-	out, exitCode, err := handle.exec.Exec(time.Now().Add(timeout), cmd[0], cmd[1:])
-	if err != nil {
-		return nil, err
-	}
-
-	return &drivers.ExecTaskResult{
-		Stdout: out,
-		ExitResult: &drivers.ExitResult{
-			ExitCode: exitCode,
-		},
-	}, nil
+	return handle.exec.Exec(time.Now().Add(timeout), cmd[0], cmd[1:])
 }
 
 var _ drivers.ExecTaskStreamingRawDriver = (*Driver)(nil)
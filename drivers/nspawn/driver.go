@@ -0,0 +1,652 @@
+package nspawn
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/drivers/shared/eventer"
+	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/drivers/utils"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	pstructs "github.com/hashicorp/nomad/plugins/shared/structs"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "nspawn"
+
+	// fingerprintPeriod is the interval at which the driver will send fingerprint responses
+	fingerprintPeriod = 30 * time.Second
+
+	// taskHandleVersion is the version of task handle which this driver sets
+	// and understands how to decode driver state
+	taskHandleVersion = 1
+
+	// defaultPollInterval is the interval at which the status of the units
+	// running tasks is polled
+	defaultPollInterval = time.Second
+
+	// defaultKillSignal is the signal sent to the container leader when the
+	// task doesn't set a kill signal
+	defaultKillSignal = "SIGTERM"
+)
+
+var (
+	// PluginID is the nspawn plugin metadata registered in the plugin
+	// catalog.
+	PluginID = loader.PluginID{
+		Name:       pluginName,
+		PluginType: base.PluginTypeDriver,
+	}
+
+	// PluginConfig is the nspawn factory function registered in the
+	// plugin catalog.
+	PluginConfig = &loader.InternalPluginConfig{
+		Config:  map[string]interface{}{},
+		Factory: func(ctx context.Context, l hclog.Logger) interface{} { return NewNspawnDriver(ctx, l) },
+	}
+
+	errDisabledDriver = fmt.Errorf("nspawn is disabled")
+)
+
+var (
+	// pluginInfo is the response returned for the PluginInfo RPC
+	pluginInfo = &base.PluginInfoResponse{
+		Type:              base.PluginTypeDriver,
+		PluginApiVersions: []string{drivers.ApiVersion010},
+		PluginVersion:     "0.1.0",
+		Name:              pluginName,
+	}
+
+	// configSpec is the hcl specification returned by the ConfigSchema RPC
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"enabled": hclspec.NewDefault(
+			hclspec.NewAttr("enabled", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+	})
+
+	// taskConfigSpec is the hcl specification for the driver config section of
+	// a task within a job. It is returned in the TaskConfigSchema RPC
+	taskConfigSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"image":           hclspec.NewAttr("image", "string", true),
+		"command":         hclspec.NewAttr("command", "string", false),
+		"args":            hclspec.NewAttr("args", "list(string)", false),
+		"boot":            hclspec.NewAttr("boot", "bool", false),
+		"ephemeral":       hclspec.NewAttr("ephemeral", "bool", false),
+		"read_only":       hclspec.NewAttr("read_only", "bool", false),
+		"private_network": hclspec.NewAttr("private_network", "bool", false),
+		"bind":            hclspec.NewAttr("bind", "list(string)", false),
+		"bind_read_only":  hclspec.NewAttr("bind_read_only", "list(string)", false),
+		"properties":      hclspec.NewAttr("properties", "list(map(string))", false),
+	})
+
+	// capabilities is returned by the Capabilities RPC and indicates what
+	// optional features this driver supports
+	capabilities = &drivers.Capabilities{
+		SendSignals: true,
+		Exec:        false,
+		FSIsolation: drivers.FSIsolationImage,
+		NetIsolationModes: []drivers.NetIsolationMode{
+			drivers.NetIsolationModeHost,
+		},
+		MountConfigs: drivers.MountConfigSupportAll,
+	}
+
+	// machineNameRe matches the characters not allowed in machine names
+	machineNameRe = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// Driver runs tasks in systemd-nspawn containers. Each container runs in a
+// transient systemd service unit, which systemd supervises, sandboxes with
+// the unit properties set by the task, and accounts the resource usage of.
+// Containers are registered with systemd-machined so they can be managed
+// with machinectl.
+type Driver struct {
+	// eventer is used to handle multiplexing of TaskEvents calls such that an
+	// event can be broadcast to all callers
+	eventer *eventer.Eventer
+
+	// config is the driver configuration set by the SetConfig RPC
+	config *Config
+
+	// systemd is used to run and manage the units of tasks
+	systemd systemdClient
+
+	// pollInterval is the interval at which the status of the units running
+	// tasks is polled
+	pollInterval time.Duration
+
+	// tasks is the in memory datastore mapping taskIDs to driverHandles
+	tasks *taskStore
+
+	// ctx is the context for the driver. It is passed to other subsystems to
+	// coordinate shutdown
+	ctx context.Context
+
+	// logger will log to the Nomad agent
+	logger hclog.Logger
+}
+
+// Config is the driver configuration set by the SetConfig RPC call
+type Config struct {
+	// Enabled is set to true to enable the nspawn driver
+	Enabled bool `codec:"enabled"`
+}
+
+// TaskConfig is the driver configuration of a task within a job
+type TaskConfig struct {
+	// Image is the path of the directory tree or disk image of the
+	// container, relative to the task directory if not absolute.
+	Image string `codec:"image"`
+
+	Command string   `codec:"command"`
+	Args    []string `codec:"args"`
+
+	// Boot runs the init system of the image instead of a command.
+	Boot bool `codec:"boot"`
+
+	// Ephemeral runs the container with a temporary snapshot of the image
+	// which is removed when the container stops.
+	Ephemeral bool `codec:"ephemeral"`
+
+	// ReadOnly mounts the root directory of the container read-only.
+	ReadOnly bool `codec:"read_only"`
+
+	// PrivateNetwork disconnects the container from the host network.
+	PrivateNetwork bool `codec:"private_network"`
+
+	// Bind and BindReadOnly are host paths to bind-mount in the container,
+	// as "path" or "host_path:container_path".
+	Bind         []string `codec:"bind"`
+	BindReadOnly []string `codec:"bind_read_only"`
+
+	// Properties are the systemd unit properties, such as sandboxing
+	// directives, set on the unit running the container.
+	Properties hclutils.MapStrStr `codec:"properties"`
+}
+
+// TaskState is the state which is encoded in the handle returned in
+// StartTask. This information is needed to rebuild the task state and handler
+// during recovery.
+type TaskState struct {
+	TaskConfig *drivers.TaskConfig
+	Unit       string
+	Machine    string
+	StartedAt  time.Time
+}
+
+// NewNspawnDriver returns a new DriverPlugin implementation
+func NewNspawnDriver(ctx context.Context, logger hclog.Logger) drivers.DriverPlugin {
+	logger = logger.Named(pluginName)
+	return &Driver{
+		eventer:      eventer.NewEventer(ctx, logger),
+		config:       &Config{},
+		systemd:      execSystemdClient{},
+		pollInterval: defaultPollInterval,
+		tasks:        newTaskStore(),
+		ctx:          ctx,
+		logger:       logger,
+	}
+}
+
+func (d *Driver) PluginInfo() (*base.PluginInfoResponse, error) {
+	return pluginInfo, nil
+}
+
+func (d *Driver) ConfigSchema() (*hclspec.Spec, error) {
+	return configSpec, nil
+}
+
+func (d *Driver) SetConfig(cfg *base.Config) error {
+	var config Config
+	if len(cfg.PluginConfig) != 0 {
+		if err := base.MsgPackDecode(cfg.PluginConfig, &config); err != nil {
+			return err
+		}
+	}
+
+	d.config = &config
+	return nil
+}
+
+func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
+	return taskConfigSpec, nil
+}
+
+func (d *Driver) Capabilities() (*drivers.Capabilities, error) {
+	return capabilities, nil
+}
+
+func (d *Driver) Fingerprint(ctx context.Context) (<-chan *drivers.Fingerprint, error) {
+	ch := make(chan *drivers.Fingerprint)
+	go d.handleFingerprint(ctx, ch)
+	return ch, nil
+}
+
+func (d *Driver) handleFingerprint(ctx context.Context, ch chan<- *drivers.Fingerprint) {
+	defer close(ch)
+	ticker := time.NewTimer(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(fingerprintPeriod)
+			ch <- d.buildFingerprint(ctx)
+		}
+	}
+}
+
+func (d *Driver) buildFingerprint(ctx context.Context) *drivers.Fingerprint {
+	fp := &drivers.Fingerprint{
+		Attributes:        map[string]*pstructs.Attribute{},
+		Health:            drivers.HealthStateHealthy,
+		HealthDescription: drivers.DriverHealthy,
+	}
+
+	switch {
+	case !d.config.Enabled:
+		fp.Health = drivers.HealthStateUndetected
+		fp.HealthDescription = "disabled"
+		return fp
+	case runtime.GOOS != "linux":
+		fp.Health = drivers.HealthStateUndetected
+		fp.HealthDescription = "nspawn driver unsupported on client OS"
+		return fp
+	case !utils.IsUnixRoot():
+		fp.Health = drivers.HealthStateUndetected
+		fp.HealthDescription = drivers.DriverRequiresRootMessage
+		return fp
+	}
+
+	version, err := d.systemd.Version(ctx)
+	if err != nil {
+		d.logger.Debug("failed to detect systemd-nspawn", "error", err)
+		fp.Health = drivers.HealthStateUndetected
+		fp.HealthDescription = "systemd-nspawn not found"
+		return fp
+	}
+
+	fp.Attributes["driver.nspawn"] = pstructs.NewBoolAttribute(true)
+	fp.Attributes["driver.nspawn.version"] = pstructs.NewStringAttribute(version)
+	return fp
+}
+
+func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
+	if handle == nil {
+		return fmt.Errorf("handle cannot be nil")
+	}
+
+	// If already attached to handle there's nothing to recover.
+	if _, ok := d.tasks.Get(handle.Config.ID); ok {
+		d.logger.Trace("nothing to recover; task already exists",
+			"task_id", handle.Config.ID,
+			"task_name", handle.Config.Name,
+		)
+		return nil
+	}
+
+	var taskState TaskState
+	if err := handle.GetDriverState(&taskState); err != nil {
+		d.logger.Error("failed to decode task state from handle", "error", err, "task_id", handle.Config.ID)
+		return fmt.Errorf("failed to decode task state from handle: %v", err)
+	}
+
+	// Make sure the unit still exists, as it's unloaded when the task is
+	// destroyed or the host reboots
+	props, err := d.systemd.Show(d.ctx, taskState.Unit, unitStatusProperties...)
+	if err != nil {
+		return fmt.Errorf("failed to get status of unit %q: %v", taskState.Unit, err)
+	}
+	if parseUnitStatus(props).LoadState == "not-found" {
+		return fmt.Errorf("unit %q not found", taskState.Unit)
+	}
+
+	h := newTaskHandle(d, taskState.Unit, taskState.Machine, taskState.TaskConfig, taskState.StartedAt)
+	d.tasks.Set(taskState.TaskConfig.ID, h)
+
+	go h.run()
+	return nil
+}
+
+func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	if !d.config.Enabled {
+		return nil, nil, errDisabledDriver
+	}
+
+	if _, ok := d.tasks.Get(cfg.ID); ok {
+		return nil, nil, fmt.Errorf("task with ID %q already started", cfg.ID)
+	}
+
+	var driverConfig TaskConfig
+	if err := cfg.DecodeDriverConfig(&driverConfig); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode driver config: %v", err)
+	}
+
+	if driverConfig.Boot && driverConfig.Command != "" {
+		return nil, nil, fmt.Errorf("command can't be set when booting the container")
+	}
+
+	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
+	handle := drivers.NewTaskHandle(taskHandleVersion)
+	handle.Config = cfg
+
+	machine := machineName(cfg)
+	unit := machine + ".service"
+
+	nspawnCmd, err := nspawnCommand(cfg, &driverConfig, machine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := d.systemd.Run(d.ctx, unit, unitProperties(cfg, &driverConfig), nspawnCmd); err != nil {
+		return nil, nil, fmt.Errorf("failed to start unit: %v", err)
+	}
+
+	h := newTaskHandle(d, unit, machine, cfg, time.Now().Round(time.Millisecond))
+
+	driverState := TaskState{
+		TaskConfig: cfg,
+		Unit:       unit,
+		Machine:    machine,
+		StartedAt:  h.startedAt,
+	}
+
+	if err := handle.SetDriverState(&driverState); err != nil {
+		d.logger.Error("failed to start task, error setting driver state", "error", err)
+		d.destroyUnit(unit)
+		return nil, nil, fmt.Errorf("failed to set driver state: %v", err)
+	}
+
+	d.tasks.Set(cfg.ID, h)
+	go h.run()
+	return handle, nil, nil
+}
+
+// machineName returns the name the container of the task is registered with
+// in systemd-machined, which is unique for every run of the task.
+func machineName(cfg *drivers.TaskConfig) string {
+	name := machineNameRe.ReplaceAllString(strings.ToLower(cfg.Name), "-")
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	id := cfg.ID
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	return fmt.Sprintf("nomad-%s-%s-%s", cfg.AllocID[:8], name, id)
+}
+
+// nspawnCommand returns the systemd-nspawn command line running the task.
+func nspawnCommand(cfg *drivers.TaskConfig, driverConfig *TaskConfig, machine string) ([]string, error) {
+	image := driverConfig.Image
+	if !filepath.IsAbs(image) {
+		image = filepath.Join(cfg.TaskDir().Dir, image)
+	}
+	fi, err := os.Stat(image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find image: %v", err)
+	}
+
+	cmd := []string{
+		"systemd-nspawn",
+		"--quiet",
+		"--keep-unit",
+		"--register=yes",
+		"--machine=" + machine,
+	}
+	if fi.IsDir() {
+		cmd = append(cmd, "--directory="+image)
+	} else {
+		cmd = append(cmd, "--image="+image)
+	}
+
+	if driverConfig.Ephemeral {
+		cmd = append(cmd, "--ephemeral")
+	}
+	if driverConfig.ReadOnly {
+		cmd = append(cmd, "--read-only")
+	}
+	if driverConfig.PrivateNetwork {
+		cmd = append(cmd, "--private-network")
+	}
+	if cfg.User != "" {
+		cmd = append(cmd, "--user="+cfg.User)
+	}
+
+	// Bind the alloc, local, and secrets dirs at their usual paths
+	taskDir := cfg.TaskDir()
+	cmd = append(cmd,
+		fmt.Sprintf("--bind=%s:%s", taskDir.SharedAllocDir, allocdir.SharedAllocContainerPath),
+		fmt.Sprintf("--bind=%s:%s", taskDir.LocalDir, allocdir.TaskLocalContainerPath),
+		fmt.Sprintf("--bind=%s:%s", taskDir.SecretsDir, allocdir.TaskSecretsContainerPath),
+	)
+	for _, m := range cfg.Mounts {
+		flag := "--bind"
+		if m.Readonly {
+			flag = "--bind-ro"
+		}
+		cmd = append(cmd, fmt.Sprintf("%s=%s:%s", flag, m.HostPath, m.TaskPath))
+	}
+	for _, b := range driverConfig.Bind {
+		cmd = append(cmd, "--bind="+b)
+	}
+	for _, b := range driverConfig.BindReadOnly {
+		cmd = append(cmd, "--bind-ro="+b)
+	}
+
+	// Sort the environment so the command line is stable
+	env := make([]string, 0, len(cfg.Env))
+	for k, v := range cfg.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	for _, kv := range env {
+		cmd = append(cmd, "--setenv="+kv)
+	}
+
+	if driverConfig.Boot {
+		return append(cmd, "--boot"), nil
+	}
+
+	cmd = append(cmd, "--")
+	if driverConfig.Command != "" {
+		cmd = append(cmd, driverConfig.Command)
+		cmd = append(cmd, driverConfig.Args...)
+	}
+	return cmd, nil
+}
+
+// unitProperties returns the properties of the transient unit running the
+// task.
+func unitProperties(cfg *drivers.TaskConfig, driverConfig *TaskConfig) []string {
+	props := []string{
+		// Keep the unit loaded after the container exits, so its exit
+		// status can be read until the task is destroyed
+		"RemainAfterExit=yes",
+		"StandardOutput=file:" + cfg.StdoutPath,
+		"StandardError=file:" + cfg.StderrPath,
+		"CPUAccounting=yes",
+		"MemoryAccounting=yes",
+	}
+
+	if res := cfg.Resources; res != nil && res.LinuxResources != nil {
+		if limit := res.LinuxResources.MemoryLimitBytes; limit > 0 {
+			props = append(props, "MemoryMax="+strconv.FormatInt(limit, 10))
+		}
+		if shares := res.LinuxResources.CPUShares; shares > 0 {
+			props = append(props, "CPUWeight="+strconv.FormatUint(cpuSharesToWeight(uint64(shares)), 10))
+		}
+	}
+
+	keys := make([]string, 0, len(driverConfig.Properties))
+	for k := range driverConfig.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		props = append(props, k+"="+driverConfig.Properties[k])
+	}
+	return props
+}
+
+// cpuSharesToWeight converts cgroup v1 CPU shares to a systemd CPUWeight,
+// the same way runc converts them to a cgroup v2 weight.
+func cpuSharesToWeight(shares uint64) uint64 {
+	if shares < 2 {
+		shares = 2
+	}
+	if shares > 262144 {
+		shares = 262144
+	}
+	return 1 + ((shares-2)*9999)/262142
+}
+
+// destroyUnit stops the unit and unloads it, logging errors.
+func (d *Driver) destroyUnit(unit string) {
+	if err := d.systemd.Stop(d.ctx, unit); err != nil {
+		d.logger.Debug("failed to stop unit", "unit", unit, "error", err)
+	}
+	if err := d.systemd.ResetFailed(d.ctx, unit); err != nil {
+		d.logger.Trace("failed to reset unit", "unit", unit, "error", err)
+	}
+}
+
+func (d *Driver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	ch := make(chan *drivers.ExitResult)
+	go d.handleWait(ctx, handle, ch)
+
+	return ch, nil
+}
+
+func (d *Driver) handleWait(ctx context.Context, handle *taskHandle, ch chan *drivers.ExitResult) {
+	defer close(ch)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-d.ctx.Done():
+		return
+	case <-handle.doneCh:
+	}
+
+	// The handle stops polling without the task exiting when the driver
+	// shuts down, in which case the task will be recovered.
+	if handle.IsRunning() {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-d.ctx.Done():
+		return
+	case ch <- handle.TaskStatus().ExitResult:
+	}
+}
+
+func (d *Driver) StopTask(taskID string, timeout time.Duration, signal string) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if signal == "" {
+		signal = defaultKillSignal
+	}
+
+	// Send the kill signal to the container leader, and stop the unit to
+	// kill the container if it's still running after the timeout
+	if handle.IsRunning() {
+		if err := d.systemd.KillMachine(d.ctx, handle.machine, signal); err != nil {
+			d.logger.Debug("failed to signal container", "machine", handle.machine, "error", err)
+		}
+	}
+
+	select {
+	case <-handle.doneCh:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	if err := d.systemd.Stop(d.ctx, handle.unit); err != nil {
+		return fmt.Errorf("failed to stop unit: %v", err)
+	}
+
+	<-handle.doneCh
+	return nil
+}
+
+func (d *Driver) DestroyTask(taskID string, force bool) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if handle.IsRunning() && !force {
+		return fmt.Errorf("cannot destroy running task")
+	}
+
+	d.destroyUnit(handle.unit)
+	handle.cancel()
+	d.tasks.Delete(taskID)
+	return nil
+}
+
+func (d *Driver) InspectTask(taskID string) (*drivers.TaskStatus, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	return handle.TaskStatus(), nil
+}
+
+func (d *Driver) TaskStats(ctx context.Context, taskID string, interval time.Duration) (<-chan *drivers.TaskResourceUsage, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	ch := make(chan *drivers.TaskResourceUsage)
+	go handle.stats(ctx, interval, ch)
+	return ch, nil
+}
+
+func (d *Driver) TaskEvents(ctx context.Context) (<-chan *drivers.TaskEvent, error) {
+	return d.eventer.TaskEvents(ctx)
+}
+
+func (d *Driver) SignalTask(taskID string, signal string) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	return d.systemd.KillMachine(d.ctx, handle.machine, signal)
+}
+
+func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
+	return nil, fmt.Errorf("nspawn driver does not support exec")
+}
@@ -0,0 +1,378 @@
+package nspawn
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	dtestutil "github.com/hashicorp/nomad/plugins/drivers/testutils"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSystemdClient keeps the status of units in memory.
+type mockSystemdClient struct {
+	lock     sync.Mutex
+	units    map[string]map[string]string
+	machines map[string]string
+	commands map[string][]string
+	props    map[string][]string
+	signals  []string
+}
+
+func newMockSystemdClient() *mockSystemdClient {
+	return &mockSystemdClient{
+		units:    map[string]map[string]string{},
+		machines: map[string]string{},
+		commands: map[string][]string{},
+		props:    map[string][]string{},
+	}
+}
+
+func (c *mockSystemdClient) Version(context.Context) (string, error) {
+	return "249", nil
+}
+
+func (c *mockSystemdClient) Run(_ context.Context, unit string, props []string, cmd []string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.units[unit] = map[string]string{
+		"LoadState":     "loaded",
+		"ActiveState":   "active",
+		"SubState":      "running",
+		"MemoryCurrent": "1048576",
+		"CPUUsageNSec":  "1000000",
+	}
+	for _, arg := range cmd {
+		if len(arg) > 10 && arg[:10] == "--machine=" {
+			c.machines[arg[10:]] = unit
+		}
+	}
+	c.commands[unit] = cmd
+	c.props[unit] = props
+	return nil
+}
+
+func (c *mockSystemdClient) Show(_ context.Context, unit string, props ...string) (map[string]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	status, ok := c.units[unit]
+	if !ok {
+		return map[string]string{"LoadState": "not-found", "ActiveState": "inactive"}, nil
+	}
+	out := map[string]string{}
+	for _, p := range props {
+		out[p] = status[p]
+	}
+	return out, nil
+}
+
+func (c *mockSystemdClient) KillMachine(_ context.Context, machine, signal string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	unit, ok := c.machines[machine]
+	if !ok {
+		return fmt.Errorf("no machine %q", machine)
+	}
+	c.signals = append(c.signals, signal)
+	c.exitLocked(unit, cldKilled, 15)
+	return nil
+}
+
+func (c *mockSystemdClient) Stop(_ context.Context, unit string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.units, unit)
+	return nil
+}
+
+func (c *mockSystemdClient) ResetFailed(context.Context, string) error {
+	return nil
+}
+
+// exit marks the main process of the unit as exited.
+func (c *mockSystemdClient) exit(unit string, code, status int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.exitLocked(unit, code, status)
+}
+
+func (c *mockSystemdClient) exitLocked(unit string, code, status int) {
+	c.units[unit]["SubState"] = "exited"
+	c.units[unit]["ExecMainCode"] = fmt.Sprint(code)
+	c.units[unit]["ExecMainStatus"] = fmt.Sprint(status)
+}
+
+func newTestNspawnDriver(t *testing.T) (*Driver, *mockSystemdClient) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	systemd := newMockSystemdClient()
+	d := NewNspawnDriver(ctx, testlog.HCLogger(t)).(*Driver)
+	d.config.Enabled = true
+	d.systemd = systemd
+	d.pollInterval = 10 * time.Millisecond
+	return d, systemd
+}
+
+func testTaskConfig(t *testing.T, taskConfig *TaskConfig) *drivers.TaskConfig {
+	allocID := uuid.Generate()
+	task := &drivers.TaskConfig{
+		ID:         fmt.Sprintf("%s/web/%s", allocID, uuid.Generate()[:8]),
+		AllocID:    allocID,
+		Name:       "Web_Server",
+		AllocDir:   t.TempDir(),
+		Env:        map[string]string{"B": "2", "A": "1"},
+		StdoutPath: "/tmp/stdout",
+		StderrPath: "/tmp/stderr",
+		Resources: &drivers.Resources{
+			LinuxResources: &drivers.LinuxResources{
+				CPUShares:        1024,
+				MemoryLimitBytes: 256 * 1024 * 1024,
+			},
+		},
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(task.TaskDir().Dir, "rootfs"), 0755))
+	require.NoError(t, task.EncodeConcreteDriverConfig(taskConfig))
+	return task
+}
+
+func TestNspawnDriver_Fingerprint_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	d, _ := newTestNspawnDriver(t)
+	d.config.Enabled = false
+
+	fp := d.buildFingerprint(context.Background())
+	require.Equal(t, drivers.HealthStateUndetected, fp.Health)
+	require.Equal(t, "disabled", fp.HealthDescription)
+}
+
+func TestNspawnDriver_MachineName(t *testing.T) {
+	ci.Parallel(t)
+
+	cfg := &drivers.TaskConfig{
+		ID:      "6f3f7bd6-3b5f-4b6b-9a59-2b2c5a0e7b1e/Web_Server/4ea3bd1a",
+		AllocID: "6f3f7bd6-3b5f-4b6b-9a59-2b2c5a0e7b1e",
+		Name:    "Web_Server",
+	}
+	require.Equal(t, "nomad-6f3f7bd6-web-server-4ea3bd1a", machineName(cfg))
+}
+
+func TestNspawnDriver_Command(t *testing.T) {
+	ci.Parallel(t)
+
+	task := testTaskConfig(t, &TaskConfig{})
+	task.Mounts = []*drivers.MountConfig{{HostPath: "/srv/data", TaskPath: "/data", Readonly: true}}
+	taskDir := task.TaskDir()
+
+	cmd, err := nspawnCommand(task, &TaskConfig{
+		Image:     "rootfs",
+		Command:   "/bin/server",
+		Args:      []string{"-port", "8080"},
+		Ephemeral: true,
+		Bind:      []string{"/var/cache"},
+	}, "nomad-test")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"systemd-nspawn",
+		"--quiet",
+		"--keep-unit",
+		"--register=yes",
+		"--machine=nomad-test",
+		"--directory=" + filepath.Join(taskDir.Dir, "rootfs"),
+		"--ephemeral",
+		"--bind=" + taskDir.SharedAllocDir + ":/alloc",
+		"--bind=" + taskDir.LocalDir + ":/local",
+		"--bind=" + taskDir.SecretsDir + ":/secrets",
+		"--bind-ro=/srv/data:/data",
+		"--bind=/var/cache",
+		"--setenv=A=1",
+		"--setenv=B=2",
+		"--",
+		"/bin/server",
+		"-port",
+		"8080",
+	}, cmd)
+
+	// Booting runs the init system of the image
+	cmd, err = nspawnCommand(task, &TaskConfig{Image: "rootfs", Boot: true}, "nomad-test")
+	require.NoError(t, err)
+	require.Equal(t, "--boot", cmd[len(cmd)-1])
+
+	// Missing images are an error
+	_, err = nspawnCommand(task, &TaskConfig{Image: "missing"}, "nomad-test")
+	require.Error(t, err)
+}
+
+func TestNspawnDriver_UnitProperties(t *testing.T) {
+	ci.Parallel(t)
+
+	task := testTaskConfig(t, &TaskConfig{})
+	props := unitProperties(task, &TaskConfig{
+		Properties: map[string]string{
+			"ProtectSystem":   "strict",
+			"NoNewPrivileges": "yes",
+		},
+	})
+	require.Equal(t, []string{
+		"RemainAfterExit=yes",
+		"StandardOutput=file:/tmp/stdout",
+		"StandardError=file:/tmp/stderr",
+		"CPUAccounting=yes",
+		"MemoryAccounting=yes",
+		"MemoryMax=268435456",
+		"CPUWeight=39",
+		"NoNewPrivileges=yes",
+		"ProtectSystem=strict",
+	}, props)
+}
+
+func TestNspawnDriver_UnitStatus(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name     string
+		props    map[string]string
+		exited   bool
+		exitCode int
+		signal   int
+		err      bool
+	}{
+		{
+			name:  "running",
+			props: map[string]string{"LoadState": "loaded", "ActiveState": "active", "SubState": "running"},
+		},
+		{
+			name:     "exited",
+			props:    map[string]string{"LoadState": "loaded", "ActiveState": "active", "SubState": "exited", "ExecMainCode": "1", "ExecMainStatus": "3"},
+			exited:   true,
+			exitCode: 3,
+		},
+		{
+			name:     "killed",
+			props:    map[string]string{"LoadState": "loaded", "ActiveState": "failed", "SubState": "failed", "ExecMainCode": "2", "ExecMainStatus": "9"},
+			exited:   true,
+			exitCode: 137,
+			signal:   9,
+		},
+		{
+			name:   "not found",
+			props:  map[string]string{"LoadState": "not-found", "ActiveState": "inactive"},
+			exited: true,
+			err:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := parseUnitStatus(tc.props)
+			require.Equal(t, tc.exited, status.exited())
+			if !tc.exited {
+				return
+			}
+			exitCode, signal, err := status.exitResult()
+			require.Equal(t, tc.exitCode, exitCode)
+			require.Equal(t, tc.signal, signal)
+			require.Equal(t, tc.err, err != nil)
+		})
+	}
+
+	require.Equal(t,
+		map[string]string{"ActiveState": "active", "ExecMainStatus": "0"},
+		parseShowOutput("ActiveState=active\nExecMainStatus=0\n"))
+}
+
+func TestNspawnDriver_StartWaitStop(t *testing.T) {
+	ci.Parallel(t)
+
+	d, systemd := newTestNspawnDriver(t)
+	harness := dtestutil.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	task := testTaskConfig(t, &TaskConfig{
+		Image:   "rootfs",
+		Command: "/bin/server",
+	})
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+
+	var state TaskState
+	require.NoError(t, handle.GetDriverState(&state))
+	require.Equal(t, state.Machine+".service", state.Unit)
+	require.Contains(t, systemd.commands[state.Unit], "--machine="+state.Machine)
+
+	ch, err := harness.WaitTask(context.Background(), task.ID)
+	require.NoError(t, err)
+
+	statsCh, err := harness.TaskStats(context.Background(), task.ID, 10*time.Millisecond)
+	require.NoError(t, err)
+	usage := <-statsCh
+	require.Equal(t, uint64(1048576), usage.ResourceUsage.MemoryStats.Usage)
+
+	require.NoError(t, harness.StopTask(task.ID, time.Second, "SIGINT"))
+	require.Equal(t, []string{"SIGINT"}, systemd.signals)
+
+	select {
+	case res := <-ch:
+		require.Equal(t, 15, res.Signal)
+	case <-time.After(time.Duration(testutil.TestMultiplier()*5) * time.Second):
+		require.Fail(t, "timeout waiting for task to exit")
+	}
+
+	require.NoError(t, harness.DestroyTask(task.ID, false))
+	props, err := systemd.Show(context.Background(), state.Unit, "LoadState")
+	require.NoError(t, err)
+	require.Equal(t, "not-found", props["LoadState"])
+}
+
+func TestNspawnDriver_ExitAndRecover(t *testing.T) {
+	ci.Parallel(t)
+
+	d, systemd := newTestNspawnDriver(t)
+	harness := dtestutil.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	task := testTaskConfig(t, &TaskConfig{
+		Image:   "rootfs",
+		Command: "/bin/server",
+	})
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+
+	var state TaskState
+	require.NoError(t, handle.GetDriverState(&state))
+
+	// Recover the task in a new driver, as after a client restart
+	d2, _ := newTestNspawnDriver(t)
+	d2.systemd = systemd
+	harness2 := dtestutil.NewDriverHarness(t, d2)
+	defer harness2.Kill()
+	require.NoError(t, harness2.RecoverTask(handle))
+
+	ch, err := harness2.WaitTask(context.Background(), task.ID)
+	require.NoError(t, err)
+
+	systemd.exit(state.Unit, cldExited, 2)
+
+	select {
+	case res := <-ch:
+		require.Equal(t, 2, res.ExitCode)
+	case <-time.After(time.Duration(testutil.TestMultiplier()*5) * time.Second):
+		require.Fail(t, "timeout waiting for task to exit")
+	}
+
+	// Tasks whose unit is gone can't be recovered
+	require.NoError(t, harness2.DestroyTask(task.ID, true))
+	d3, _ := newTestNspawnDriver(t)
+	d3.systemd = systemd
+	require.Error(t, d3.RecoverTask(handle))
+}
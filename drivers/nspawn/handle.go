@@ -0,0 +1,175 @@
+package nspawn
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/stats"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+type taskHandle struct {
+	unit         string
+	machine      string
+	systemd      systemdClient
+	pollInterval time.Duration
+	logger       hclog.Logger
+
+	// ctx is canceled to stop polling the unit
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// stateLock syncs access to all fields below
+	stateLock sync.RWMutex
+
+	taskConfig  *drivers.TaskConfig
+	procState   drivers.TaskState
+	startedAt   time.Time
+	completedAt time.Time
+	exitResult  *drivers.ExitResult
+	doneCh      chan struct{}
+}
+
+func newTaskHandle(d *Driver, unit, machine string, cfg *drivers.TaskConfig, startedAt time.Time) *taskHandle {
+	ctx, cancel := context.WithCancel(d.ctx)
+	return &taskHandle{
+		unit:         unit,
+		machine:      machine,
+		systemd:      d.systemd,
+		pollInterval: d.pollInterval,
+		logger:       d.logger.With("task_name", cfg.Name, "alloc_id", cfg.AllocID, "unit", unit),
+		ctx:          ctx,
+		cancel:       cancel,
+		taskConfig:   cfg,
+		procState:    drivers.TaskStateRunning,
+		startedAt:    startedAt,
+		exitResult:   &drivers.ExitResult{},
+		doneCh:       make(chan struct{}),
+	}
+}
+
+func (h *taskHandle) TaskStatus() *drivers.TaskStatus {
+	h.stateLock.RLock()
+	defer h.stateLock.RUnlock()
+
+	return &drivers.TaskStatus{
+		ID:          h.taskConfig.ID,
+		Name:        h.taskConfig.Name,
+		State:       h.procState,
+		StartedAt:   h.startedAt,
+		CompletedAt: h.completedAt,
+		ExitResult:  h.exitResult,
+		DriverAttributes: map[string]string{
+			"unit":    h.unit,
+			"machine": h.machine,
+		},
+	}
+}
+
+func (h *taskHandle) IsRunning() bool {
+	h.stateLock.RLock()
+	defer h.stateLock.RUnlock()
+	return h.procState == drivers.TaskStateRunning
+}
+
+// run polls the status of the unit until its main process exits or the
+// driver shuts down.
+func (h *taskHandle) run() {
+	defer close(h.doneCh)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(h.pollInterval)
+		}
+
+		props, err := h.systemd.Show(h.ctx, h.unit, unitStatusProperties...)
+		if err != nil {
+			if h.ctx.Err() == nil {
+				h.logger.Warn("failed to get unit status", "error", err)
+			}
+			continue
+		}
+
+		status := parseUnitStatus(props)
+		if !status.exited() {
+			continue
+		}
+
+		h.stateLock.Lock()
+		h.procState = drivers.TaskStateExited
+		h.completedAt = time.Now()
+		h.exitResult.ExitCode, h.exitResult.Signal, h.exitResult.Err = status.exitResult()
+		h.stateLock.Unlock()
+		return
+	}
+}
+
+// measuredMemStats and measuredCpuStats are the stats read from the
+// accounting of the unit
+var (
+	measuredMemStats = []string{"Usage"}
+	measuredCpuStats = []string{"Percent"}
+)
+
+// stats emits the resource usage of the unit read from its cgroup accounting
+// at every interval.
+func (h *taskHandle) stats(ctx context.Context, interval time.Duration, ch chan<- *drivers.TaskResourceUsage) {
+	defer close(ch)
+
+	cpuStats := stats.NewCpuStats()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.doneCh:
+			return
+		case <-timer.C:
+			timer.Reset(interval)
+		}
+
+		props, err := h.systemd.Show(ctx, h.unit, "MemoryCurrent", "CPUUsageNSec")
+		if err != nil {
+			h.logger.Debug("failed to get unit resource usage", "error", err)
+			continue
+		}
+
+		// Properties of disabled accounting are shown as "[not set]" and
+		// fail to parse as zero
+		memory, _ := strconv.ParseUint(props["MemoryCurrent"], 10, 64)
+		cpuNanos, _ := strconv.ParseUint(props["CPUUsageNSec"], 10, 64)
+		percent := cpuStats.Percent(float64(cpuNanos))
+
+		usage := &drivers.TaskResourceUsage{
+			ResourceUsage: &drivers.ResourceUsage{
+				MemoryStats: &drivers.MemoryStats{
+					Usage:    memory,
+					Measured: measuredMemStats,
+				},
+				CpuStats: &drivers.CpuStats{
+					Percent:    percent,
+					TotalTicks: cpuStats.TicksConsumed(percent),
+					Measured:   measuredCpuStats,
+				},
+			},
+			Timestamp: time.Now().UTC().UnixNano(),
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- usage:
+		}
+	}
+}
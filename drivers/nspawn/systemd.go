@@ -0,0 +1,172 @@
+package nspawn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// systemdClient encapsulates the systemd commands the driver runs, so they
+// can be mocked in tests.
+type systemdClient interface {
+	// Version returns the version of systemd-nspawn.
+	Version(ctx context.Context) (string, error)
+
+	// Run starts the command in a transient service unit with the
+	// properties.
+	Run(ctx context.Context, unit string, properties []string, command []string) error
+
+	// Show returns the properties of the unit.
+	Show(ctx context.Context, unit string, properties ...string) (map[string]string, error)
+
+	// KillMachine sends the signal to the leader process of the machine.
+	KillMachine(ctx context.Context, machine, signal string) error
+
+	// Stop stops the unit, killing all of its processes.
+	Stop(ctx context.Context, unit string) error
+
+	// ResetFailed unloads the unit if it failed.
+	ResetFailed(ctx context.Context, unit string) error
+}
+
+// execSystemdClient implements systemdClient by running the systemd command
+// line tools.
+type execSystemdClient struct{}
+
+var nspawnVersionRe = regexp.MustCompile(`^systemd (\d+)`)
+
+func (execSystemdClient) Version(ctx context.Context) (string, error) {
+	out, err := runCommand(ctx, "systemd-nspawn", "--version")
+	if err != nil {
+		return "", err
+	}
+	m := nspawnVersionRe.FindStringSubmatch(out)
+	if m == nil {
+		return "", fmt.Errorf("unexpected systemd-nspawn version output: %q", out)
+	}
+	return m[1], nil
+}
+
+func (execSystemdClient) Run(ctx context.Context, unit string, properties []string, command []string) error {
+	args := []string{"--quiet", "--unit=" + unit}
+	for _, p := range properties {
+		args = append(args, "--property="+p)
+	}
+	args = append(args, "--")
+	args = append(args, command...)
+	_, err := runCommand(ctx, "systemd-run", args...)
+	return err
+}
+
+func (execSystemdClient) Show(ctx context.Context, unit string, properties ...string) (map[string]string, error) {
+	out, err := runCommand(ctx, "systemctl", "show", unit, "--property="+strings.Join(properties, ","))
+	if err != nil {
+		return nil, err
+	}
+	return parseShowOutput(out), nil
+}
+
+func (execSystemdClient) KillMachine(ctx context.Context, machine, signal string) error {
+	_, err := runCommand(ctx, "machinectl", "kill", machine, "--kill-who=leader", "--signal="+signal)
+	return err
+}
+
+func (execSystemdClient) Stop(ctx context.Context, unit string) error {
+	_, err := runCommand(ctx, "systemctl", "stop", unit)
+	return err
+}
+
+func (execSystemdClient) ResetFailed(ctx context.Context, unit string) error {
+	_, err := runCommand(ctx, "systemctl", "reset-failed", unit)
+	return err
+}
+
+func runCommand(ctx context.Context, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %v: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// parseShowOutput parses the key=value lines printed by systemctl show.
+func parseShowOutput(out string) map[string]string {
+	props := map[string]string{}
+	s := bufio.NewScanner(strings.NewReader(out))
+	for s.Scan() {
+		kv := strings.SplitN(s.Text(), "=", 2)
+		if len(kv) == 2 {
+			props[kv[0]] = kv[1]
+		}
+	}
+	return props
+}
+
+const (
+	// cldExited, cldKilled, and cldDumped are the values of the ExecMainCode
+	// unit property, telling whether ExecMainStatus is an exit code or a
+	// signal.
+	cldExited = 1
+	cldKilled = 2
+	cldDumped = 3
+)
+
+// unitStatusProperties are the unit properties parsed by parseUnitStatus.
+var unitStatusProperties = []string{"LoadState", "ActiveState", "SubState", "ExecMainCode", "ExecMainStatus"}
+
+// unitStatus is the status of the transient unit running a task.
+type unitStatus struct {
+	LoadState      string
+	ActiveState    string
+	SubState       string
+	ExecMainCode   int
+	ExecMainStatus int
+}
+
+func parseUnitStatus(props map[string]string) *unitStatus {
+	s := &unitStatus{
+		LoadState:   props["LoadState"],
+		ActiveState: props["ActiveState"],
+		SubState:    props["SubState"],
+	}
+	s.ExecMainCode, _ = strconv.Atoi(props["ExecMainCode"])
+	s.ExecMainStatus, _ = strconv.Atoi(props["ExecMainStatus"])
+	return s
+}
+
+// exited returns true if the main process of the unit has exited. Units are
+// started with RemainAfterExit so they stay loaded, and their exit status
+// can be read, until they are stopped.
+func (s *unitStatus) exited() bool {
+	switch s.ActiveState {
+	case "failed", "inactive":
+		return true
+	case "active":
+		return s.SubState == "exited"
+	default:
+		return false
+	}
+}
+
+// exitResult returns the exit code or signal of the main process of the
+// unit.
+func (s *unitStatus) exitResult() (int, int, error) {
+	switch {
+	case s.LoadState == "not-found":
+		return 0, 0, fmt.Errorf("unit not found")
+	case s.ExecMainCode == cldExited:
+		return s.ExecMainStatus, 0, nil
+	case s.ExecMainCode == cldKilled, s.ExecMainCode == cldDumped:
+		return 128 + s.ExecMainStatus, s.ExecMainStatus, nil
+	default:
+		return 0, 0, nil
+	}
+}
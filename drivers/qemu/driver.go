@@ -17,8 +17,10 @@ import (
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/drivers/shared/eventer"
 	"github.com/hashicorp/nomad/drivers/shared/executor"
+	"github.com/hashicorp/nomad/drivers/shared/proclimits"
 	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/hashicorp/nomad/plugins/shared/hclspec"
@@ -89,6 +91,11 @@ var (
 	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
 		"image_paths":    hclspec.NewAttr("image_paths", "list(string)", false),
 		"args_allowlist": hclspec.NewAttr("args_allowlist", "list(string)", false),
+		"task_overhead": hclspec.NewBlock("task_overhead", false, hclspec.NewObject(map[string]*hclspec.Spec{
+			"cpu":    hclspec.NewAttr("cpu", "number", false),
+			"memory": hclspec.NewAttr("memory", "number", false),
+		})),
+		"process_limits": proclimits.Spec,
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -149,6 +156,24 @@ type Config struct {
 	// include in arguments to qemu, so that cluster operators can can
 	// prevent access to devices
 	ArgsAllowList []string `codec:"args_allowlist"`
+
+	// TaskOverhead is the resource overhead of the hypervisor that every qemu
+	// task adds on top of the resources given to the virtual machine. It is
+	// reported to the scheduler so that it is accounted for at placement.
+	TaskOverhead TaskOverhead `codec:"task_overhead"`
+
+	// ProcessLimits restricts the nice value, I/O scheduling class and
+	// resource limits the process block of tasks may request.
+	ProcessLimits *proclimits.Limits `codec:"process_limits"`
+}
+
+// TaskOverhead is the per-task resource overhead of the qemu hypervisor.
+type TaskOverhead struct {
+	// CPU is the overhead in MHz.
+	CPU int64 `codec:"cpu"`
+
+	// MemoryMB is the overhead in MB.
+	MemoryMB int64 `codec:"memory"`
 }
 
 // Driver is a driver for running images via Qemu
@@ -199,6 +224,9 @@ func (d *Driver) SetConfig(cfg *base.Config) error {
 			return err
 		}
 	}
+	if err := config.ProcessLimits.Validate(); err != nil {
+		return fmt.Errorf("invalid process_limits: %v", err)
+	}
 
 	d.config = config
 	if cfg.AgentConfig != nil {
@@ -268,6 +296,13 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 	currentQemuVersion := matches[1]
 	fingerprint.Attributes[driverAttr] = pstructs.NewBoolAttribute(true)
 	fingerprint.Attributes[driverVersionAttr] = pstructs.NewStringAttribute(currentQemuVersion)
+
+	if cpu := d.config.TaskOverhead.CPU; cpu > 0 {
+		fingerprint.Attributes[structs.DriverOverheadCPUAttr(pluginName)] = pstructs.NewIntAttribute(cpu, "")
+	}
+	if mem := d.config.TaskOverhead.MemoryMB; mem > 0 {
+		fingerprint.Attributes[structs.DriverOverheadMemoryAttr(pluginName)] = pstructs.NewIntAttribute(mem, "")
+	}
 	return fingerprint
 }
 
@@ -390,6 +425,10 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("failed to decode driver config: %v", err)
 	}
 
+	if err := d.config.ProcessLimits.Check(cfg.Process); err != nil {
+		return nil, nil, fmt.Errorf("failed process validation: %v", err)
+	}
+
 	// ensure that PortMap variables are populated early on
 	cfg.Env = taskenv.SetPortMapEnvs(cfg.Env, driverConfig.PortMap)
 
@@ -568,6 +607,7 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		StdoutPath:       cfg.StdoutPath,
 		StderrPath:       cfg.StderrPath,
 		NetworkIsolation: cfg.NetworkIsolation,
+		Process:          cfg.Process,
 	}
 	ps, err := execImpl.Launch(execCmd)
 	if err != nil {
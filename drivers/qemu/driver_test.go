@@ -384,6 +384,22 @@ func TestQemuDriver_Fingerprint(t *testing.T) {
 	}
 }
 
+func TestQemuDriver_Fingerprint_TaskOverhead(t *testing.T) {
+	ci.Parallel(t)
+	ctestutil.QemuCompatible(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewQemuDriver(ctx, testlog.HCLogger(t)).(*Driver)
+	d.config.TaskOverhead = TaskOverhead{CPU: 100, MemoryMB: 256}
+
+	fp := d.buildFingerprint()
+	require.Equal(t, drivers.HealthStateHealthy, fp.Health)
+	require.Equal(t, "100", fp.Attributes["driver.qemu.overhead.cpu"].GoString())
+	require.Equal(t, "256", fp.Attributes["driver.qemu.overhead.memory"].GoString())
+}
+
 func TestConfig_ParseAllHCL(t *testing.T) {
 	ci.Parallel(t)
 
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/drivers/shared/eventer"
 	"github.com/hashicorp/nomad/drivers/shared/executor"
+	"github.com/hashicorp/nomad/drivers/shared/proclimits"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -82,6 +83,11 @@ var (
 			hclspec.NewAttr("no_cgroups", "bool", false),
 			hclspec.NewLiteral("false"),
 		),
+		"use_cgroup_freeze_on_stop": hclspec.NewDefault(
+			hclspec.NewAttr("use_cgroup_freeze_on_stop", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+		"process_limits": proclimits.Spec,
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -136,8 +142,17 @@ type Config struct {
 	// tree
 	NoCgroups bool `codec:"no_cgroups"`
 
+	// UseCgroupFreezeOnStop freezes the cgroup of tasks while signaling
+	// their processes on stop, so processes forked concurrently can't
+	// escape the signal. It requires cgroups.
+	UseCgroupFreezeOnStop bool `codec:"use_cgroup_freeze_on_stop"`
+
 	// Enabled is set to true to enable the raw_exec driver
 	Enabled bool `codec:"enabled"`
+
+	// ProcessLimits restricts the nice value, I/O scheduling class and
+	// resource limits the process block of tasks may request.
+	ProcessLimits *proclimits.Limits `codec:"process_limits"`
 }
 
 // TaskConfig is the driver configuration of a task within a job
@@ -184,6 +199,13 @@ func (d *Driver) SetConfig(cfg *base.Config) error {
 		}
 	}
 
+	if config.NoCgroups && config.UseCgroupFreezeOnStop {
+		return fmt.Errorf("use_cgroup_freeze_on_stop requires cgroups but no_cgroups is set")
+	}
+	if err := config.ProcessLimits.Validate(); err != nil {
+		return fmt.Errorf("invalid process_limits: %v", err)
+	}
+
 	d.config = &config
 	if cfg.AgentConfig != nil {
 		d.nomadConfig = cfg.AgentConfig.Driver
@@ -308,6 +330,10 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("failed to decode driver config: %v", err)
 	}
 
+	if err := d.config.ProcessLimits.Check(cfg.Process); err != nil {
+		return nil, nil, fmt.Errorf("failed process validation: %v", err)
+	}
+
 	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
 	handle := drivers.NewTaskHandle(taskHandleVersion)
 	handle.Config = cfg
@@ -334,10 +360,12 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		Env:                cfg.EnvList(),
 		User:               cfg.User,
 		BasicProcessCgroup: useCgroups,
+		CgroupFreezeOnStop: useCgroups && d.config.UseCgroupFreezeOnStop,
 		TaskDir:            cfg.TaskDir().Dir,
 		StdoutPath:         cfg.StdoutPath,
 		StderrPath:         cfg.StderrPath,
 		NetworkIsolation:   cfg.NetworkIsolation,
+		Process:            cfg.Process,
 	}
 
 	ps, err := exec.Launch(execCmd)
@@ -503,17 +531,7 @@ func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*
 		return nil, drivers.ErrTaskNotFound
 	}
 
-	out, exitCode, err := handle.exec.Exec(time.Now().Add(timeout), cmd[0], cmd[1:])
-	if err != nil {
-		return nil, err
-	}
-
-	return &drivers.ExecTaskResult{
-		Stdout: out,
-		ExitResult: &drivers.ExitResult{
-			ExitCode: exitCode,
-		},
-	}, nil
+	return handle.exec.Exec(time.Now().Add(timeout), cmd[0], cmd[1:])
 }
 
 var _ drivers.ExecTaskStreamingRawDriver = (*Driver)(nil)
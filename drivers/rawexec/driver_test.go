@@ -91,6 +91,13 @@ func TestRawExecDriver_SetConfig(t *testing.T) {
 	require.NoError(harness.SetConfig(bconfig))
 	require.Exactly(config, d.(*Driver).config)
 
+	// Freezing the cgroup on stop requires cgroups.
+	config.UseCgroupFreezeOnStop = true
+	data = []byte{}
+	require.NoError(basePlug.MsgPackEncode(&data, config))
+	bconfig.PluginConfig = data
+	require.Error(harness.SetConfig(bconfig))
+
 	// Enable raw_exec, enable cgroups.
 	config.NoCgroups = false
 	data = []byte{}
@@ -529,7 +536,7 @@ func TestRawExecDriver_Exec(t *testing.T) {
 		res, err = harness.ExecTask(task.ID, []string{"cmd.exe", "/c", "stat", "notarealfile123abc"}, 1*time.Second)
 		require.NoError(err)
 		require.False(res.ExitResult.Successful())
-		require.Contains(string(res.Stdout), "not recognized")
+		require.Contains(string(res.Stderr), "not recognized")
 	} else {
 		// Exec a command that should work
 		res, err := harness.ExecTask(task.ID, []string{"/usr/bin/stat", "/tmp"}, 1*time.Second)
@@ -541,7 +548,15 @@ func TestRawExecDriver_Exec(t *testing.T) {
 		res, err = harness.ExecTask(task.ID, []string{"/usr/bin/stat", "notarealfile123abc"}, 1*time.Second)
 		require.NoError(err)
 		require.False(res.ExitResult.Successful())
-		require.Contains(string(res.Stdout), "No such file or directory")
+		require.Contains(string(res.Stderr), "No such file or directory")
+		require.Empty(res.Stdout)
+
+		// Exec a command writing to both stdout and stderr
+		res, err = harness.ExecTask(task.ID, []string{"/bin/sh", "-c", "echo out; echo err >&2"}, 1*time.Second)
+		require.NoError(err)
+		require.True(res.ExitResult.Successful())
+		require.Equal("out\n", string(res.Stdout))
+		require.Equal("err\n", string(res.Stderr))
 	}
 
 	require.NoError(harness.DestroyTask(task.ID, true))
@@ -47,6 +47,10 @@ var (
 	// The statistics the basic executor exposes
 	ExecutorBasicMeasuredMemStats = []string{"RSS", "Swap"}
 	ExecutorBasicMeasuredCpuStats = []string{"System Mode", "User Mode", "Percent"}
+
+	// ErrCheckpointNotSupported is returned by executors that can't
+	// checkpoint or restore tasks
+	ErrCheckpointNotSupported = fmt.Errorf("executor does not support checkpointing tasks")
 )
 
 // Executor is the interface which allows a driver to launch and supervise
@@ -83,11 +87,16 @@ type Executor interface {
 	Signal(os.Signal) error
 
 	// Exec executes the given command and args inside the executor context
-	// and returns the output and exit code.
-	Exec(deadline time.Time, cmd string, args []string) ([]byte, int, error)
+	// and returns its stdout, stderr and exit code.
+	Exec(deadline time.Time, cmd string, args []string) (*drivers.ExecTaskResult, error)
 
 	ExecStreaming(ctx context.Context, cmd []string, tty bool,
 		stream drivers.ExecTaskStream) error
+
+	// Checkpoint writes the state of the user process to the given
+	// directory with CRIU and stops the process. Launch restores the process
+	// from the checkpoint when ExecCommand.RestoreDir is set.
+	Checkpoint(dir string) error
 }
 
 // ExecCommand holds the user command, args, and other isolation related
@@ -132,6 +141,16 @@ type ExecCommand struct {
 	// Using the cgroup does allow more precise cleanup of processes.
 	BasicProcessCgroup bool
 
+	// CgroupFreezeOnStop sends the stop signal to all the processes of the
+	// task cgroup while it is frozen, instead of only to the main process, so
+	// forked children can't escape the signal.
+	CgroupFreezeOnStop bool
+
+	// RestoreDir is the directory of a checkpoint created by
+	// Executor.Checkpoint. If set, the process is restored from the
+	// checkpoint instead of started from Cmd.
+	RestoreDir string
+
 	// NoPivotRoot disables using pivot_root for isolation, useful when the root
 	// partition is on a ramdisk which does not support pivot_root,
 	// see man 2 pivot_root
@@ -154,6 +173,26 @@ type ExecCommand struct {
 
 	// Capabilities are the linux capabilities to be enabled by the task driver.
 	Capabilities []string
+
+	// Process configures the umask, priority and resource limits of the
+	// process.
+	Process *drivers.ProcessConfig
+
+	// Sandbox restricts the filesystem access of the process to a set of
+	// paths instead of isolating it in a chroot. Only supported by the
+	// universal executor on Linux.
+	Sandbox *SandboxConfig
+
+	// UserNamespace runs the process in a user namespace whose root user is
+	// mapped to the user running the executor, so tasks can be isolated
+	// without root privileges on the host. Only supported by the
+	// libcontainer executor.
+	UserNamespace bool
+
+	// IOWeight is the relative block IO weight of the process, between 10
+	// and 1000, converted to an io.weight on cgroups.v2. Zero leaves the
+	// weight unset. Only supported by the libcontainer executor.
+	IOWeight uint16
 }
 
 // SetWriters sets the writer for the process stdout and stderr. This should
@@ -223,6 +262,9 @@ type ProcessState struct {
 	ExitCode int
 	Signal   int
 	Time     time.Time
+
+	// OOMKilled is true if the kernel OOM killed a process of the task.
+	OOMKilled bool
 }
 
 // ExecutorVersion is the version of the executor
@@ -283,6 +325,10 @@ func (e *UniversalExecutor) Version() (*ExecutorVersion, error) {
 func (e *UniversalExecutor) Launch(command *ExecCommand) (*ProcessState, error) {
 	e.logger.Trace("preparing to launch command", "command", command.Cmd, "args", strings.Join(command.Args, " "))
 
+	if command.RestoreDir != "" {
+		return nil, ErrCheckpointNotSupported
+	}
+
 	e.commandCfg = command
 
 	// setting the user of the process
@@ -333,10 +379,26 @@ func (e *UniversalExecutor) Launch(command *ExecCommand) (*ProcessState, error)
 	}
 
 	path := absPath
+	args := command.Args
+
+	// Run the command through the sandbox shim if requested
+	if command.Sandbox != nil {
+		path, args, err = sandboxCommand(command.Sandbox, absPath, args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Run the command through the process shim to apply the process config
+	// before it is executed
+	path, args, err = processCommand(command.Process, &e.childCmd, path, args)
+	if err != nil {
+		return nil, err
+	}
 
 	// Set the commands arguments
 	e.childCmd.Path = path
-	e.childCmd.Args = append([]string{e.childCmd.Path}, command.Args...)
+	e.childCmd.Args = append([]string{e.childCmd.Path}, args...)
 	e.childCmd.Env = e.commandCfg.Env
 
 	// Start the process
@@ -344,22 +406,30 @@ func (e *UniversalExecutor) Launch(command *ExecCommand) (*ProcessState, error)
 		return nil, fmt.Errorf("failed to start command path=%q --- args=%q: %v", path, e.childCmd.Args, err)
 	}
 
+	pid := e.childCmd.Process.Pid
 	go e.pidCollector.collectPids(e.processExited, e.getAllPids)
 	go e.wait()
-	return &ProcessState{Pid: e.childCmd.Process.Pid, ExitCode: -1, Time: time.Now()}, nil
+	return &ProcessState{Pid: pid, ExitCode: -1, Time: time.Now()}, nil
 }
 
 // Exec a command inside a container for exec and java drivers.
-func (e *UniversalExecutor) Exec(deadline time.Time, name string, args []string) ([]byte, int, error) {
+func (e *UniversalExecutor) Exec(deadline time.Time, name string, args []string) (*drivers.ExecTaskResult, error) {
 	ctx, cancel := context.WithDeadline(context.Background(), deadline)
 	defer cancel()
+	if e.commandCfg.Sandbox != nil {
+		var err error
+		name, args, err = sandboxCommand(e.commandCfg.Sandbox, name, args)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return ExecScript(ctx, e.childCmd.Dir, e.commandCfg.Env, e.childCmd.SysProcAttr, e.commandCfg.NetworkIsolation, name, args)
 }
 
-// ExecScript executes cmd with args and returns the output, exit code, and
-// error. Output is truncated to drivers/shared/structs.CheckBufSize
+// ExecScript executes cmd with args and returns its stdout, stderr and exit
+// code. Stdout and stderr are each truncated to drivers.CheckBufSize.
 func ExecScript(ctx context.Context, dir string, env []string, attrs *syscall.SysProcAttr,
-	netSpec *drivers.NetworkIsolationSpec, name string, args []string) ([]byte, int, error) {
+	netSpec *drivers.NetworkIsolationSpec, name string, args []string) (*drivers.ExecTaskResult, error) {
 
 	cmd := exec.CommandContext(ctx, name, args...)
 
@@ -369,16 +439,25 @@ func ExecScript(ctx context.Context, dir string, env []string, attrs *syscall.Sy
 	cmd.Env = env
 
 	// Capture output
-	buf, _ := circbuf.NewBuffer(int64(drivers.CheckBufSize))
-	cmd.Stdout = buf
-	cmd.Stderr = buf
+	stdout, _ := circbuf.NewBuffer(int64(drivers.CheckBufSize))
+	stderr, _ := circbuf.NewBuffer(int64(drivers.CheckBufSize))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	result := func(exitCode int) *drivers.ExecTaskResult {
+		return &drivers.ExecTaskResult{
+			Stdout:     stdout.Bytes(),
+			Stderr:     stderr.Bytes(),
+			ExitResult: &drivers.ExitResult{ExitCode: exitCode},
+		}
+	}
 
 	if err := withNetworkIsolation(cmd.Run, netSpec); err != nil {
 		exitErr, ok := err.(*exec.ExitError)
 		if !ok {
 			// Non-exit error, return it and let the caller treat
 			// it as a critical failure
-			return nil, 0, err
+			return nil, err
 		}
 
 		// Some kind of error happened; default to critical
@@ -389,9 +468,9 @@ func ExecScript(ctx context.Context, dir string, env []string, attrs *syscall.Sy
 
 		// Don't return the exitError as the caller only needs the
 		// output and code.
-		return buf.Bytes(), exitCode, nil
+		return result(exitCode), nil
 	}
-	return buf.Bytes(), 0, nil
+	return result(0), nil
 }
 
 func (e *UniversalExecutor) ExecStreaming(ctx context.Context, command []string, tty bool,
@@ -401,9 +480,21 @@ func (e *UniversalExecutor) ExecStreaming(ctx context.Context, command []string,
 		return fmt.Errorf("command is required")
 	}
 
+	if e.commandCfg.Sandbox != nil {
+		name, args, err := sandboxCommand(e.commandCfg.Sandbox, command[0], command[1:])
+		if err != nil {
+			return err
+		}
+		command = append([]string{name}, args...)
+	}
+
 	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
 
 	cmd.Dir = "/"
+	if e.commandCfg.Sandbox != nil {
+		// the host root isn't readable within the sandbox
+		cmd.Dir = e.childCmd.Dir
+	}
 	cmd.Env = e.childCmd.Env
 
 	execHelper := &execHelper{
@@ -546,7 +637,14 @@ func (e *UniversalExecutor) Shutdown(signal string, grace time.Duration) error {
 			return err
 		}
 
-		if err := e.shutdownProcess(sig, proc); err != nil {
+		if e.commandCfg.CgroupFreezeOnStop && e.containment != nil {
+			// signal every process of the task cgroup while it is frozen,
+			// so forked children can't escape the signal
+			if err := e.containment.Signal(sig); err != nil {
+				e.logger.Warn("failed to signal frozen processes", "error", err)
+				return err
+			}
+		} else if err := e.shutdownProcess(sig, proc); err != nil {
 			e.logger.Warn("failed to shutdown process", "pid", proc.Pid, "error", err)
 			return err
 		}
@@ -595,6 +693,11 @@ func (e *UniversalExecutor) Shutdown(signal string, grace time.Duration) error {
 	return nil
 }
 
+// Checkpoint is not supported without libcontainer isolation
+func (e *UniversalExecutor) Checkpoint(string) error {
+	return ErrCheckpointNotSupported
+}
+
 // Signal sends the passed signal to the task
 func (e *UniversalExecutor) Signal(s os.Signal) error {
 	if e.childCmd.Process == nil {
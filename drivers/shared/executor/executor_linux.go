@@ -11,7 +11,9 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -66,6 +68,10 @@ type LibcontainerExecutor struct {
 	userProc       *libcontainer.Process
 	userProcExited chan interface{}
 	exitState      *ProcessState
+
+	// oomKilled is set to 1 once the kernel OOM kills a process of the
+	// container.
+	oomKilled int32
 }
 
 func NewExecutorWithIsolation(logger hclog.Logger) Executor {
@@ -95,10 +101,16 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 
 	l.command = command
 
+	// cgroup errors are ignored when the executor isn't running as root
+	cgroupfs := libcontainer.Cgroupfs
+	if command.UserNamespace && os.Geteuid() != 0 {
+		cgroupfs = libcontainer.RootlessCgroupfs
+	}
+
 	// create a new factory which will store the container state in the allocDir
 	factory, err := libcontainer.New(
 		path.Join(command.TaskDir, "../alloc/container"),
-		libcontainer.Cgroupfs,
+		cgroupfs,
 		// note that os.Args[0] refers to the executor shim typically
 		// and first args arguments is ignored now due
 		// until https://github.com/opencontainers/runc/pull/1888 is merged
@@ -121,31 +133,34 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 	l.container = container
 
 	// Look up the binary path and make it executable
+	var path string
 	absPath, err := lookupTaskBin(command)
-
 	if err != nil {
-		return nil, err
-	}
-
-	if err := makeExecutable(absPath); err != nil {
-		return nil, err
-	}
+		// The binary may be in a host path bind mounted into the chroot
+		mounted, ok := lookupMountedBin(command)
+		if !ok {
+			return nil, err
+		}
+		path = mounted
+	} else {
+		if err := makeExecutable(absPath); err != nil {
+			return nil, err
+		}
 
-	path := absPath
+		// Ensure that the path is contained in the chroot, and find it relative to the container
+		rel, err := filepath.Rel(command.TaskDir, absPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine relative path base=%q target=%q: %v", command.TaskDir, absPath, err)
+		}
 
-	// Ensure that the path is contained in the chroot, and find it relative to the container
-	rel, err := filepath.Rel(command.TaskDir, path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine relative path base=%q target=%q: %v", command.TaskDir, path, err)
+		// Turn relative-to-chroot path into absolute path to avoid
+		// libcontainer trying to resolve the binary using $PATH.
+		// Do *not* use filepath.Join as it will translate ".."s returned by
+		// filepath.Rel. Prepending "/" will cause the path to be rooted in the
+		// chroot which is the desired behavior.
+		path = "/" + rel
 	}
 
-	// Turn relative-to-chroot path into absolute path to avoid
-	// libcontainer trying to resolve the binary using $PATH.
-	// Do *not* use filepath.Join as it will translate ".."s returned by
-	// filepath.Rel. Prepending "/" will cause the path to be rooted in the
-	// chroot which is the desired behavior.
-	path = "/" + rel
-
 	combined := append([]string{path}, command.Args...)
 	stdout, err := command.Stdout()
 	if err != nil {
@@ -176,8 +191,16 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 	l.userCpuStats = stats.NewCpuStats()
 	l.systemCpuStats = stats.NewCpuStats()
 
-	// Starts the task
-	if err := container.Run(process); err != nil {
+	// Starts the task, or restores it from a checkpoint. The priority of the
+	// process is inherited from the thread starting the container.
+	if command.RestoreDir != "" {
+		l.logger.Debug("restoring from checkpoint", "dir", command.RestoreDir)
+		restore := func() error { return container.Restore(process, l.criuOpts(command.RestoreDir)) }
+		if err := withProcessPriority(restore, command.Process); err != nil {
+			container.Destroy()
+			return nil, fmt.Errorf("failed to restore checkpoint: %v", err)
+		}
+	} else if err := withProcessPriority(func() error { return container.Run(process) }, command.Process); err != nil {
 		container.Destroy()
 		return nil, err
 	}
@@ -192,6 +215,7 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 	// be multiplexed
 	l.userProcExited = make(chan interface{})
 	go l.pidCollector.collectPids(l.userProcExited, l.getAllPids)
+	l.watchOOM()
 	go l.wait()
 
 	return &ProcessState{
@@ -253,11 +277,73 @@ func (l *LibcontainerExecutor) wait() {
 	}
 
 	l.exitState = &ProcessState{
-		Pid:      ps.Pid(),
-		ExitCode: exitCode,
-		Signal:   signal,
-		Time:     time.Now(),
+		Pid:       ps.Pid(),
+		ExitCode:  exitCode,
+		Signal:    signal,
+		Time:      time.Now(),
+		OOMKilled: l.wasOOMKilled(),
+	}
+}
+
+// watchOOM watches the OOM notifications of the memory cgroup of the
+// container, to report whether the task was OOM killed when it exits.
+func (l *LibcontainerExecutor) watchOOM() {
+	oomCh, err := l.container.NotifyOOM()
+	if err != nil {
+		l.logger.Warn("failed to watch OOM kills", "error", err)
+		return
 	}
+
+	go func() {
+		for range oomCh {
+			l.logger.Debug("process of task OOM killed")
+			atomic.StoreInt32(&l.oomKilled, 1)
+		}
+	}()
+}
+
+// wasOOMKilled returns whether the kernel OOM killed a process of the
+// container. As the notification may be delivered after the process exited,
+// the oom_kill counter of the memory cgroup is checked too.
+func (l *LibcontainerExecutor) wasOOMKilled() bool {
+	if atomic.LoadInt32(&l.oomKilled) == 1 {
+		return true
+	}
+
+	state, err := l.container.State()
+	if err != nil {
+		l.logger.Debug("failed to get container state", "error", err)
+		return false
+	}
+
+	file := filepath.Join(state.CgroupPaths["memory"], "memory.oom_control")
+	if cgutil.UseV2 {
+		file = filepath.Join(state.CgroupPaths[""], "memory.events")
+	}
+
+	count, err := oomKillCount(file)
+	if err != nil {
+		l.logger.Debug("failed to read OOM kill count", "file", file, "error", err)
+		return false
+	}
+	return count > 0
+}
+
+// oomKillCount returns the value of the oom_kill key of the memory.events
+// (cgroups v2) or memory.oom_control (cgroups v1) file.
+func oomKillCount(file string) (uint64, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("missing oom_kill in %s", file)
 }
 
 // Shutdown stops all processes started and cleans up any resources
@@ -288,9 +374,15 @@ func (l *LibcontainerExecutor) Shutdown(signal string, grace time.Duration) erro
 			return fmt.Errorf("error unknown signal given for shutdown: %s", signal)
 		}
 
-		// Signal initial container processes only during graceful
-		// shutdown; hence `false` arg.
-		err = l.container.Signal(sig, false)
+		if l.command.CgroupFreezeOnStop {
+			// Signal all container processes while the container cgroup
+			// is frozen, so forked children can't escape the signal.
+			err = l.signalFrozen(sig)
+		} else {
+			// Signal initial container processes only during graceful
+			// shutdown; hence `false` arg.
+			err = l.container.Signal(sig, false)
+		}
 		if err != nil {
 			return err
 		}
@@ -320,6 +412,35 @@ func (l *LibcontainerExecutor) Shutdown(signal string, grace time.Duration) erro
 	}
 }
 
+// signalFrozen freezes the container cgroup, sends sig to all the container
+// processes and thaws the cgroup so the processes can handle the signal.
+func (l *LibcontainerExecutor) signalFrozen(sig os.Signal) error {
+	if err := l.container.Pause(); err != nil {
+		return fmt.Errorf("failed to freeze container: %v", err)
+	}
+
+	pids, err := l.container.Processes()
+	if err != nil {
+		l.container.Resume()
+		return fmt.Errorf("failed to list container processes: %v", err)
+	}
+
+	for _, pid := range pids {
+		p, err := os.FindProcess(pid)
+		if err != nil {
+			continue
+		}
+		if err := p.Signal(sig); err != nil {
+			l.logger.Trace("failed to signal process", "pid", pid, "error", err)
+		}
+	}
+
+	if err := l.container.Resume(); err != nil {
+		return fmt.Errorf("failed to thaw container: %v", err)
+	}
+	return nil
+}
+
 // UpdateResources updates the resource isolation with new values to be enforced
 func (l *LibcontainerExecutor) UpdateResources(resources *drivers.Resources) error {
 	return nil
@@ -347,6 +468,14 @@ func (l *LibcontainerExecutor) handleStats(ch chan *cstructs.TaskResourceUsage,
 		measuredMemStats = ExecutorCgroupV2MeasuredMemStats
 	}
 
+	// The memory limits set by configureCgroups, reported along the usage so
+	// the client can account for oversubscribed memory
+	var memLimit, memReservation uint64
+	if cfg := l.container.Config(); cfg.Cgroups != nil && cfg.Cgroups.Resources != nil {
+		memLimit = uint64(cfg.Cgroups.Resources.Memory)
+		memReservation = uint64(cfg.Cgroups.Resources.MemoryReservation)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -386,6 +515,8 @@ func (l *LibcontainerExecutor) handleStats(ch chan *cstructs.TaskResourceUsage,
 			MaxUsage:       maxUsage,
 			KernelUsage:    stats.MemoryStats.KernelUsage.Usage,
 			KernelMaxUsage: stats.MemoryStats.KernelUsage.MaxUsage,
+			Limit:          memLimit,
+			Reservation:    memReservation,
 			Measured:       measuredMemStats,
 		}
 
@@ -423,26 +554,55 @@ func (l *LibcontainerExecutor) handleStats(ch chan *cstructs.TaskResourceUsage,
 }
 
 // Signal sends a signal to the process managed by the executor
+// Checkpoint writes the state of the container to dir with CRIU, stopping
+// the container once done.
+func (l *LibcontainerExecutor) Checkpoint(dir string) error {
+	if l.container == nil {
+		return fmt.Errorf("task not yet run")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %v", err)
+	}
+
+	l.logger.Debug("checkpointing task", "dir", dir)
+	if err := l.container.Checkpoint(l.criuOpts(dir)); err != nil {
+		return fmt.Errorf("failed to checkpoint task: %v", err)
+	}
+
+	return nil
+}
+
+// criuOpts returns the CRIU options used to checkpoint and restore tasks.
+func (l *LibcontainerExecutor) criuOpts(dir string) *libcontainer.CriuOpts {
+	return &libcontainer.CriuOpts{
+		ImagesDirectory: dir,
+		WorkDirectory:   filepath.Join(dir, "work"),
+		FileLocks:       true,
+	}
+}
+
 func (l *LibcontainerExecutor) Signal(s os.Signal) error {
 	return l.userProc.Signal(s)
 }
 
 // Exec starts an additional process inside the container
-func (l *LibcontainerExecutor) Exec(deadline time.Time, cmd string, args []string) ([]byte, int, error) {
+func (l *LibcontainerExecutor) Exec(deadline time.Time, cmd string, args []string) (*drivers.ExecTaskResult, error) {
 	combined := append([]string{cmd}, args...)
 	// Capture output
-	buf, _ := circbuf.NewBuffer(int64(drivers.CheckBufSize))
+	stdout, _ := circbuf.NewBuffer(int64(drivers.CheckBufSize))
+	stderr, _ := circbuf.NewBuffer(int64(drivers.CheckBufSize))
 
 	process := &libcontainer.Process{
 		Args:   combined,
 		Env:    l.command.Env,
-		Stdout: buf,
-		Stderr: buf,
+		Stdout: stdout,
+		Stderr: stderr,
 	}
 
 	err := l.container.Run(process)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	waitCh := make(chan *waitResult)
@@ -456,18 +616,22 @@ func (l *LibcontainerExecutor) Exec(deadline time.Time, cmd string, args []strin
 			if exitErr, ok := result.err.(*exec.ExitError); ok {
 				ps = exitErr.ProcessState
 			} else {
-				return nil, 0, result.err
+				return nil, result.err
 			}
 		}
 		var exitCode int
 		if status, ok := ps.Sys().(syscall.WaitStatus); ok {
 			exitCode = status.ExitStatus()
 		}
-		return buf.Bytes(), exitCode, nil
+		return &drivers.ExecTaskResult{
+			Stdout:     stdout.Bytes(),
+			Stderr:     stderr.Bytes(),
+			ExitResult: &drivers.ExitResult{ExitCode: exitCode},
+		}, nil
 
 	case <-time.After(time.Until(deadline)):
 		process.Signal(os.Kill)
-		return nil, 0, context.DeadlineExceeded
+		return nil, context.DeadlineExceeded
 	}
 
 }
@@ -605,6 +769,15 @@ func configureIsolation(cfg *lconfigs.Config, command *ExecCommand) error {
 			return err
 		}
 		cfg.Devices = append(cfg.Devices, devs...)
+
+		// The device cgroup only allows the default devices otherwise. Rules
+		// for the default devices must not be repeated, as overlapping rules
+		// are merged into narrower permissions.
+		for _, dev := range devs {
+			if !isDefaultDevice(dev) {
+				cfg.Cgroups.Resources.Devices = append(cfg.Cgroups.Resources.Devices, &dev.Rule)
+			}
+		}
 	}
 
 	cfg.Mounts = []*lconfigs.Mount{
@@ -709,6 +882,12 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 	cfg.Cgroups.Resources.CpuShares = uint64(cpuShares)
 	cfg.Cgroups.Resources.CpuWeight = cgroups.ConvertCPUSharesToCgroupV2Value(uint64(cpuShares))
 
+	// Set the relative block IO weight, which libcontainer converts to an
+	// io.weight for cgroupv2
+	if command.IOWeight > 0 {
+		cfg.Cgroups.Resources.BlkioWeight = command.IOWeight
+	}
+
 	if command.Resources.LinuxResources != nil && command.Resources.LinuxResources.CpusetCgroupPath != "" {
 		cfg.Hooks = lconfigs.Hooks{
 			lconfigs.CreateRuntime: lconfigs.HookList{
@@ -748,9 +927,61 @@ func newLibcontainerConfig(command *ExecCommand) (*lconfigs.Config, error) {
 		return nil, err
 	}
 
+	if err := configureProcess(cfg, command.Process); err != nil {
+		return nil, err
+	}
+
+	if command.UserNamespace {
+		configureUserNamespace(cfg)
+	}
+
 	return cfg, nil
 }
 
+// configureUserNamespace runs the container in a new user namespace whose root
+// user and group are mapped to the user running the executor. Mounts that
+// can't be created from an unprivileged user namespace are replaced by bind
+// mounts of the host paths, like runc does for rootless containers.
+func configureUserNamespace(cfg *lconfigs.Config) {
+	uid, gid := os.Geteuid(), os.Getegid()
+
+	cfg.Namespaces = append(cfg.Namespaces, lconfigs.Namespace{Type: lconfigs.NEWUSER})
+	cfg.UidMappings = []lconfigs.IDMap{{ContainerID: 0, HostID: uid, Size: 1}}
+	cfg.GidMappings = []lconfigs.IDMap{{ContainerID: 0, HostID: gid, Size: 1}}
+	cfg.RootlessEUID = uid != 0
+	cfg.RootlessCgroups = uid != 0
+
+	bindFlags := syscall.MS_BIND | syscall.MS_REC | syscall.MS_NOEXEC | syscall.MS_NOSUID | syscall.MS_NODEV
+	for _, m := range cfg.Mounts {
+		switch m.Device {
+		case "devpts":
+			// The tty group isn't mapped in the user namespace
+			m.Data = strings.ReplaceAll(m.Data, ",gid=5", "")
+		case "sysfs":
+			// sysfs can only be mounted in a network namespace owned by the
+			// user namespace
+			if !cfg.Namespaces.Contains(lconfigs.NEWNET) || cfg.Namespaces.PathOf(lconfigs.NEWNET) != "" {
+				m.Source, m.Device = "/sys", "bind"
+				m.Flags = bindFlags | syscall.MS_RDONLY
+			}
+		case "proc":
+			// procfs can only be mounted in a PID namespace owned by the user
+			// namespace
+			if !cfg.Namespaces.Contains(lconfigs.NEWPID) {
+				m.Source, m.Device = "/proc", "bind"
+				m.Flags = bindFlags
+			}
+		case "mqueue":
+			// mqueue can only be mounted in an IPC namespace owned by the
+			// user namespace
+			if !cfg.Namespaces.Contains(lconfigs.NEWIPC) {
+				m.Source, m.Device = "/dev/mqueue", "bind"
+				m.Flags = bindFlags
+			}
+		}
+	}
+}
+
 // cmdDevices converts a list of driver.DeviceConfigs into excutor.Devices.
 func cmdDevices(driverDevices []*drivers.DeviceConfig) ([]*devices.Device, error) {
 	if len(driverDevices) == 0 {
@@ -771,6 +1002,17 @@ func cmdDevices(driverDevices []*drivers.DeviceConfig) ([]*devices.Device, error
 	return r, nil
 }
 
+// isDefaultDevice returns true if the device is one of the devices available to
+// every task.
+func isDefaultDevice(dev *devices.Device) bool {
+	for _, d := range specconv.AllowedDevices {
+		if d.Type == dev.Type && d.Major == dev.Major && d.Minor == dev.Minor {
+			return true
+		}
+	}
+	return false
+}
+
 var userMountToUnixMount = map[string]int{
 	// Empty string maps to `rprivate` for backwards compatibility in restored
 	// older tasks, where mount propagation will not be present.
@@ -789,6 +1031,11 @@ func cmdMounts(mounts []*drivers.MountConfig) []*lconfigs.Mount {
 	r := make([]*lconfigs.Mount, len(mounts))
 
 	for i, m := range mounts {
+		if m.Type == drivers.MountTypeTmpfs {
+			r[i] = tmpfsMount(m)
+			continue
+		}
+
 		flags := unix.MS_BIND
 		if m.Readonly {
 			flags |= unix.MS_RDONLY
@@ -806,6 +1053,65 @@ func cmdMounts(mounts []*drivers.MountConfig) []*lconfigs.Mount {
 	return r
 }
 
+// tmpfsMount converts a tmpfs driver.MountConfig into an executor.Mount.
+func tmpfsMount(m *drivers.MountConfig) *lconfigs.Mount {
+	flags := unix.MS_NOSUID | unix.MS_NODEV
+	if m.Readonly {
+		flags |= unix.MS_RDONLY
+	}
+
+	data := "mode=1777"
+	if m.TmpfsSize > 0 {
+		data += fmt.Sprintf(",size=%d", m.TmpfsSize)
+	}
+
+	return &lconfigs.Mount{
+		Source:           "tmpfs",
+		Destination:      m.TaskPath,
+		Device:           "tmpfs",
+		Flags:            flags,
+		Data:             data,
+		PropagationFlags: []int{userMountToUnixMount[m.PropagationMode]},
+	}
+}
+
+// lookupMountedBin finds the file `bin` in the host paths bind mounted into
+// the chroot, performing a PATH search if bin isn't a path. It returns the path
+// of the file inside the chroot.
+func lookupMountedBin(command *ExecCommand) (string, bool) {
+	bin := command.Cmd
+
+	var candidates []string
+	switch {
+	case filepath.IsAbs(bin):
+		candidates = []string{filepath.Clean(bin)}
+	case strings.Contains(bin, "/"):
+		return "", false
+	default:
+		for _, dir := range filepath.SplitList("/usr/local/bin:/usr/bin:/bin") {
+			candidates = append(candidates, filepath.Join(dir, bin))
+		}
+	}
+
+	for _, candidate := range candidates {
+		// Later mounts are mounted over earlier ones
+		for i := len(command.Mounts) - 1; i >= 0; i-- {
+			m := command.Mounts[i]
+			if m.Type == drivers.MountTypeTmpfs {
+				continue
+			}
+			rel, err := filepath.Rel(m.TaskPath, candidate)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+				continue
+			}
+			if fi, err := os.Stat(filepath.Join(m.HostPath, rel)); err == nil && !fi.IsDir() {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
+
 // lookupTaskBin finds the file `bin` in taskDir/local, taskDir in that order, then performs
 // a PATH search inside taskDir. It returns an absolute path. See also executor.lookupBin
 func lookupTaskBin(command *ExecCommand) (string, error) {
@@ -128,6 +128,37 @@ func TestExecutor_configureNamespaces(t *testing.T) {
 	})
 }
 
+func TestExecutor_configureCgroups_IOWeight(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	cmd := &ExecCommand{
+		ResourceLimits: true,
+		Resources: &drivers.Resources{
+			NomadResources: alloc.AllocatedResources.Tasks[task.Name],
+			LinuxResources: &drivers.LinuxResources{
+				CpusetCgroupPath: filepath.Join(cgutil.CgroupRoot, "testing.scope", cgutil.CgroupScope(alloc.ID, task.Name)),
+			},
+		},
+	}
+
+	newConfig := func() *lconfigs.Config {
+		return &lconfigs.Config{
+			Cgroups: &lconfigs.Cgroup{Resources: &lconfigs.Resources{}},
+		}
+	}
+
+	cfg := newConfig()
+	require.NoError(t, configureCgroups(cfg, cmd))
+	require.Zero(t, cfg.Cgroups.Resources.BlkioWeight)
+
+	cmd.IOWeight = 500
+	cfg = newConfig()
+	require.NoError(t, configureCgroups(cfg, cmd))
+	require.Equal(t, uint16(500), cfg.Cgroups.Resources.BlkioWeight)
+}
+
 func TestExecutor_Isolation_PID_and_IPC_hostMode(t *testing.T) {
 	ci.Parallel(t)
 	r := require.New(t)
@@ -451,6 +482,41 @@ func TestUniversalExecutor_LookupTaskBin(t *testing.T) {
 	require.Error(err)
 }
 
+func TestUniversalExecutor_LookupMountedBin(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	hostDir := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(hostDir, "bin"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(hostDir, "bin", "tool"), []byte{1, 2}, 0755))
+
+	cmd := &ExecCommand{
+		TaskDir: t.TempDir(),
+		Mounts: []*drivers.MountConfig{
+			{HostPath: hostDir, TaskPath: "/usr"},
+		},
+	}
+
+	// Lookup with an absolute path inside the mount
+	cmd.Cmd = "/usr/bin/tool"
+	path, ok := lookupMountedBin(cmd)
+	require.True(ok)
+	require.Equal("/usr/bin/tool", path)
+
+	// Lookup with file name searches PATH
+	cmd.Cmd = "tool"
+	path, ok = lookupMountedBin(cmd)
+	require.True(ok)
+	require.Equal("/usr/bin/tool", path)
+
+	// Paths outside of mounts and directories aren't found
+	for _, bin := range []string{"/bin/tool", "/usr/bin", "bin/tool", "missing"} {
+		cmd.Cmd = bin
+		_, ok = lookupMountedBin(cmd)
+		require.False(ok, bin)
+	}
+}
+
 // Exec Launch looks for the binary only inside the chroot
 func TestExecutor_EscapeContainer(t *testing.T) {
 	ci.Parallel(t)
@@ -718,6 +784,11 @@ func TestExecutor_cmdMounts(t *testing.T) {
 			TaskPath: "/task/path-rw",
 			Readonly: false,
 		},
+		{
+			TaskPath:  "/task/scratch",
+			Type:      drivers.MountTypeTmpfs,
+			TmpfsSize: 64 * 1024 * 1024,
+		},
 	}
 
 	expected := []*lconfigs.Mount{
@@ -735,11 +806,150 @@ func TestExecutor_cmdMounts(t *testing.T) {
 			Device:           "bind",
 			PropagationFlags: []int{unix.MS_PRIVATE | unix.MS_REC},
 		},
+		{
+			Source:           "tmpfs",
+			Destination:      "/task/scratch",
+			Flags:            unix.MS_NOSUID | unix.MS_NODEV,
+			Device:           "tmpfs",
+			Data:             "mode=1777,size=67108864",
+			PropagationFlags: []int{unix.MS_PRIVATE | unix.MS_REC},
+		},
 	}
 
 	require.EqualValues(t, expected, cmdMounts(input))
 }
 
+func TestExecutor_configureProcess(t *testing.T) {
+	ci.Parallel(t)
+
+	cfg := &lconfigs.Config{}
+	require.NoError(t, configureProcess(cfg, nil))
+	require.Nil(t, cfg.Umask)
+	require.Nil(t, cfg.Rlimits)
+
+	err := configureProcess(cfg, &drivers.ProcessConfig{
+		Umask:   "027",
+		Rlimits: map[string]uint64{"nofile": 1024},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(0027), *cfg.Umask)
+	require.Equal(t, []lconfigs.Rlimit{
+		{Type: unix.RLIMIT_NOFILE, Hard: 1024, Soft: 1024},
+	}, cfg.Rlimits)
+
+	err = configureProcess(cfg, &drivers.ProcessConfig{Umask: "999"})
+	require.EqualError(t, err, `invalid umask "999"`)
+
+	err = configureProcess(cfg, &drivers.ProcessConfig{Rlimits: map[string]uint64{"bogus": 1}})
+	require.EqualError(t, err, `unknown rlimit "bogus"`)
+}
+
+// TestUniversalExecutor_Process asserts that the process config is applied
+// to the task process before it is executed, without changing the executor.
+func TestUniversalExecutor_Process(t *testing.T) {
+	ci.Parallel(t)
+	testutil.ExecCompatible(t)
+
+	testExecCmd := testExecutorCommand(t)
+	execCmd, allocDir := testExecCmd.command, testExecCmd.allocDir
+	defer allocDir.Destroy()
+
+	execCmd.Cmd = "/bin/sh"
+	execCmd.Args = []string{"-c", "umask && ulimit -Hn && cut -d' ' -f19 /proc/self/stat"}
+	execCmd.Process = &drivers.ProcessConfig{
+		Umask:   "027",
+		Nice:    5,
+		Rlimits: map[string]uint64{"nofile": 512},
+	}
+
+	executor := NewExecutor(testlog.HCLogger(t))
+	defer executor.Shutdown("SIGKILL", 0)
+
+	oldUmask := unix.Umask(022)
+	unix.Umask(oldUmask)
+
+	_, err := executor.Launch(execCmd)
+	require.NoError(t, err)
+
+	ps, err := executor.Wait(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, ps.ExitCode, "stderr: %s", testExecCmd.stderr.String())
+
+	require.Eventually(t, func() bool {
+		return strings.TrimSpace(testExecCmd.stdout.String()) == "0027\n512\n5"
+	}, 5*time.Second, 50*time.Millisecond, "stdout: %s stderr: %s",
+		testExecCmd.stdout.String(), testExecCmd.stderr.String())
+
+	// The umask of the executor is unchanged
+	umask := unix.Umask(022)
+	unix.Umask(umask)
+	require.Equal(t, oldUmask, umask)
+}
+
+func TestExecutor_configureUserNamespace(t *testing.T) {
+	ci.Parallel(t)
+
+	cfg := &lconfigs.Config{Namespaces: configureNamespaces("private", "host")}
+	require.NoError(t, configureIsolation(cfg, &ExecCommand{TaskDir: "/tmp/task"}))
+	configureUserNamespace(cfg)
+
+	require.Contains(t, cfg.Namespaces, lconfigs.Namespace{Type: lconfigs.NEWUSER})
+	require.Equal(t, []lconfigs.IDMap{{ContainerID: 0, HostID: os.Geteuid(), Size: 1}}, cfg.UidMappings)
+	require.Equal(t, []lconfigs.IDMap{{ContainerID: 0, HostID: os.Getegid(), Size: 1}}, cfg.GidMappings)
+
+	mounts := map[string]*lconfigs.Mount{}
+	for _, m := range cfg.Mounts {
+		mounts[m.Destination] = m
+	}
+	require.NotContains(t, mounts["/dev/pts"].Data, "gid=")
+	require.Equal(t, "bind", mounts["/sys"].Device)
+	require.Equal(t, "/sys", mounts["/sys"].Source)
+
+	// the IPC namespace is owned by the user namespace when private
+	cfg = &lconfigs.Config{}
+	require.NoError(t, configureIsolation(cfg, &ExecCommand{TaskDir: "/tmp/task", ModePID: "private", ModeIPC: "private"}))
+	configureUserNamespace(cfg)
+	for _, m := range cfg.Mounts {
+		if m.Destination == "/dev/mqueue" {
+			require.Equal(t, "mqueue", m.Device)
+		}
+	}
+}
+
+func TestExecutor_UserNamespace(t *testing.T) {
+	ci.Parallel(t)
+	testutil.ExecCompatible(t)
+
+	testExecCmd := testExecutorCommandWithChroot(t)
+	execCmd, allocDir := testExecCmd.command, testExecCmd.allocDir
+	defer allocDir.Destroy()
+
+	execCmd.Cmd = "/bin/cat"
+	execCmd.Args = []string{"/proc/self/uid_map"}
+	execCmd.User = "root"
+	execCmd.ResourceLimits = true
+	execCmd.ModePID = "private"
+	execCmd.ModeIPC = "private"
+	execCmd.UserNamespace = true
+
+	executor := NewExecutorWithIsolation(testlog.HCLogger(t))
+	defer executor.Shutdown("SIGKILL", 0)
+
+	_, err := executor.Launch(execCmd)
+	require.NoError(t, err)
+
+	state, err := executor.Wait(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, state.ExitCode)
+
+	// root in the container is mapped to the user running the executor
+	require.Eventually(t, func() bool {
+		fields := strings.Fields(testExecCmd.stdout.String())
+		return len(fields) == 3 && fields[0] == "0" && fields[1] == strconv.Itoa(os.Geteuid()) && fields[2] == "1"
+	}, 5*time.Second, 50*time.Millisecond, "stdout: %s stderr: %s",
+		testExecCmd.stdout.String(), testExecCmd.stderr.String())
+}
+
 // TestUniversalExecutor_NoCgroup asserts that commands are executed in the
 // same cgroup as parent process
 func TestUniversalExecutor_NoCgroup(t *testing.T) {
@@ -782,3 +992,28 @@ func TestUniversalExecutor_NoCgroup(t *testing.T) {
 	})
 
 }
+
+func TestExecutor_oomKillCount(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+
+	// cgroups v2 memory.events
+	events := filepath.Join(dir, "memory.events")
+	require.NoError(t, os.WriteFile(events, []byte("low 0\nhigh 0\nmax 12\noom 2\noom_kill 2\n"), 0644))
+	count, err := oomKillCount(events)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
+
+	// cgroups v1 memory.oom_control
+	control := filepath.Join(dir, "memory.oom_control")
+	require.NoError(t, os.WriteFile(control, []byte("oom_kill_disable 0\nunder_oom 0\noom_kill 0\n"), 0644))
+	count, err = oomKillCount(control)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	// older kernels don't count OOM kills
+	require.NoError(t, os.WriteFile(control, []byte("oom_kill_disable 0\nunder_oom 0\n"), 0644))
+	_, err = oomKillCount(control)
+	require.Error(t, err)
+}
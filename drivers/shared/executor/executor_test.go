@@ -411,6 +411,26 @@ func TestUniversalExecutor_MakeExecutable(t *testing.T) {
 	}
 }
 
+func TestUniversalExecutor_Checkpoint(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	testExecCmd := testExecutorCommand(t)
+	execCmd, allocDir := testExecCmd.command, testExecCmd.allocDir
+	defer allocDir.Destroy()
+	execCmd.Cmd = "/bin/sleep"
+	execCmd.Args = []string{"1"}
+	execCmd.RestoreDir = t.TempDir()
+
+	executor := NewExecutor(testlog.HCLogger(t))
+	defer executor.Shutdown("", 0)
+
+	// Checkpoints can't be created nor restored without libcontainer
+	_, err := executor.Launch(execCmd)
+	require.Equal(ErrCheckpointNotSupported, err)
+	require.Equal(ErrCheckpointNotSupported, executor.Checkpoint(t.TempDir()))
+}
+
 func TestUniversalExecutor_LookupPath(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
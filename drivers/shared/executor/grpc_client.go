@@ -50,6 +50,12 @@ func (c *grpcExecutorClient) Launch(cmd *ExecCommand) (*ProcessState, error) {
 		DefaultPidMode:     cmd.ModePID,
 		DefaultIpcMode:     cmd.ModeIPC,
 		Capabilities:       cmd.Capabilities,
+		Process:            drivers.ProcessConfigToProto(cmd.Process),
+		Sandbox:            sandboxToProto(cmd.Sandbox),
+		UserNamespace:      cmd.UserNamespace,
+		IoWeight:           uint32(cmd.IOWeight),
+		CgroupFreezeOnStop: cmd.CgroupFreezeOnStop,
+		RestoreDir:         cmd.RestoreDir,
 	}
 	resp, err := c.client.Launch(ctx, req)
 	if err != nil {
@@ -175,11 +181,23 @@ func (c *grpcExecutorClient) Signal(s os.Signal) error {
 	return nil
 }
 
-func (c *grpcExecutorClient) Exec(deadline time.Time, cmd string, args []string) ([]byte, int, error) {
+func (c *grpcExecutorClient) Checkpoint(dir string) error {
+	ctx := context.Background()
+	req := &proto.CheckpointRequest{
+		Dir: dir,
+	}
+	if _, err := c.client.Checkpoint(ctx, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *grpcExecutorClient) Exec(deadline time.Time, cmd string, args []string) (*drivers.ExecTaskResult, error) {
 	ctx := context.Background()
 	pbDeadline, err := ptypes.TimestampProto(deadline)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	req := &proto.ExecRequest{
 		Deadline: pbDeadline,
@@ -189,10 +207,16 @@ func (c *grpcExecutorClient) Exec(deadline time.Time, cmd string, args []string)
 
 	resp, err := c.client.Exec(ctx, req)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return resp.Output, int(resp.ExitCode), nil
+	return &drivers.ExecTaskResult{
+		Stdout: resp.Output,
+		Stderr: resp.Stderr,
+		ExitResult: &drivers.ExitResult{
+			ExitCode: int(resp.ExitCode),
+		},
+	}, nil
 }
 
 func (c *grpcExecutorClient) ExecStreaming(ctx context.Context,
@@ -38,6 +38,12 @@ func (s *grpcExecutorServer) Launch(ctx context.Context, req *proto.LaunchReques
 		ModePID:            req.DefaultPidMode,
 		ModeIPC:            req.DefaultIpcMode,
 		Capabilities:       req.Capabilities,
+		Process:            drivers.ProcessConfigFromProto(req.Process),
+		Sandbox:            sandboxFromProto(req.Sandbox),
+		UserNamespace:      req.UserNamespace,
+		IOWeight:           uint16(req.IoWeight),
+		CgroupFreezeOnStop: req.CgroupFreezeOnStop,
+		RestoreDir:         req.RestoreDir,
 	})
 
 	if err != nil {
@@ -144,20 +150,28 @@ func (s *grpcExecutorServer) Signal(ctx context.Context, req *proto.SignalReques
 	return &proto.SignalResponse{}, nil
 }
 
+func (s *grpcExecutorServer) Checkpoint(ctx context.Context, req *proto.CheckpointRequest) (*proto.CheckpointResponse, error) {
+	if err := s.impl.Checkpoint(req.Dir); err != nil {
+		return nil, err
+	}
+	return &proto.CheckpointResponse{}, nil
+}
+
 func (s *grpcExecutorServer) Exec(ctx context.Context, req *proto.ExecRequest) (*proto.ExecResponse, error) {
 	deadline, err := ptypes.Timestamp(req.Deadline)
 	if err != nil {
 		return nil, err
 	}
 
-	out, exit, err := s.impl.Exec(deadline, req.Cmd, req.Args)
+	res, err := s.impl.Exec(deadline, req.Cmd, req.Args)
 	if err != nil {
 		return nil, err
 	}
 
 	return &proto.ExecResponse{
-		Output:   out,
-		ExitCode: int32(exit),
+		Output:   res.Stdout,
+		Stderr:   res.Stderr,
+		ExitCode: int32(res.ExitResult.ExitCode),
 	}, nil
 }
 
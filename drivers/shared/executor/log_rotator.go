@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rotatingFile is a log file which is rotated when it exceeds a maximum size.
+// The file being written keeps its path, while rotated files are suffixed
+// with an index, the most recent being path.1. Rotated files beyond the
+// maximum number are removed.
+type rotatingFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	f    *os.File
+	size int64
+	lock sync.Mutex
+}
+
+// newRotatingFile opens the log file at path, appending to it if it
+// exists, and rotates it once it exceeds maxSize bytes, keeping maxFiles
+// rotated files.
+func newRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &rotatingFile{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+		f:        f,
+		size:     fi.Size(),
+	}, nil
+}
+
+// Write writes p to the log file, rotating it first if p would make it
+// exceed the maximum size. A single write larger than the maximum size
+// isn't split.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the rotated files, dropping the oldest one, and starts a
+// new log file.
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %v", err)
+	}
+
+	for i := r.maxFiles; i > 0; i-- {
+		src := r.path
+		if i > 1 {
+			src = fmt.Sprintf("%s.%d", r.path, i-1)
+		}
+		dst := fmt.Sprintf("%s.%d", r.path, i)
+		if err := os.Rename(src, dst); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log file: %v", err)
+		}
+	}
+
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %v", err)
+	}
+	r.f = f
+	r.size = 0
+	return nil
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "executor.out")
+	require.NoError(t, ioutil.WriteFile(path, []byte("0000\n"), 0666))
+
+	r, err := newRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	read := func(p string) string {
+		b, err := ioutil.ReadFile(p)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	// Appends to the existing file until it's full
+	_, err = r.Write([]byte("1111\n"))
+	require.NoError(t, err)
+	require.Equal(t, "0000\n1111\n", read(path))
+
+	// Rotates the full file
+	for _, line := range []string{"2222\n", "3333\n", "4444\n", "5555\n"} {
+		_, err = r.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.Equal(t, "4444\n5555\n", read(path))
+	require.Equal(t, "2222\n3333\n", read(path+".1"))
+	require.Equal(t, "0000\n1111\n", read(path+".2"))
+
+	// Drops the oldest file
+	_, err = r.Write([]byte("6666\n"))
+	require.NoError(t, err)
+	require.Equal(t, "6666\n", read(path))
+	require.Equal(t, "4444\n5555\n", read(path+".1"))
+	require.Equal(t, "2222\n3333\n", read(path+".2"))
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+
+	// Writes larger than the maximum size aren't split
+	long := strings.Repeat("7", 20) + "\n"
+	_, err = r.Write([]byte(long))
+	require.NoError(t, err)
+	require.Equal(t, long, read(path))
+	require.Equal(t, "6666\n", read(path+".1"))
+}
+
+func TestRotatingFile_NoRotatedFiles(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "executor.out")
+	r, err := newRotatingFile(path, 10, 0)
+	require.NoError(t, err)
+
+	for _, line := range []string{"1111\n", "2222\n", "3333\n"} {
+		_, err = r.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "3333\n", string(b))
+
+	matches, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
@@ -153,12 +153,31 @@ func (c *pidCollector) pidStats() (map[string]*drivers.ResourceUsage, error) {
 			// calculate cpu usage percent
 			cs.Percent = np.StatsTotalCPU.Percent(cpuStats.Total() * float64(time.Second))
 		}
-		stats[strconv.Itoa(pid)] = &drivers.ResourceUsage{MemoryStats: ms, CpuStats: cs}
+		stats[strconv.Itoa(pid)] = &drivers.ResourceUsage{
+			MemoryStats: ms,
+			CpuStats:    cs,
+			Process:     processInfo(p),
+		}
 	}
 
 	return stats, nil
 }
 
+// processInfo returns the parent and command line of a process, falling back
+// to the process name if the command line can't be read
+func processInfo(p *process.Process) *drivers.ProcessInfo {
+	info := &drivers.ProcessInfo{}
+	if ppid, err := p.Ppid(); err == nil {
+		info.PPID = int(ppid)
+	}
+	if cmdline, err := p.Cmdline(); err == nil && cmdline != "" {
+		info.Command = cmdline
+	} else if name, err := p.Name(); err == nil {
+		info.Command = name
+	}
+	return info
+}
+
 // aggregatedResourceUsage aggregates the resource usage of all the pids and
 // returns a TaskResourceUsage data point
 func aggregatedResourceUsage(systemCpuStats *stats.CpuStats, pidStats map[string]*drivers.ResourceUsage) *drivers.TaskResourceUsage {
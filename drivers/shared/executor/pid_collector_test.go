@@ -1,10 +1,13 @@
 package executor
 
 import (
+	"os"
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/mitchellh/go-ps"
+	"github.com/shirou/gopsutil/v3/process"
+	"github.com/stretchr/testify/require"
 )
 
 func TestScanPids(t *testing.T) {
@@ -24,6 +27,17 @@ func TestScanPids(t *testing.T) {
 	}
 }
 
+func TestProcessInfo(t *testing.T) {
+	ci.Parallel(t)
+
+	p, err := process.NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+
+	info := processInfo(p)
+	require.Equal(t, os.Getppid(), info.PPID)
+	require.Contains(t, info.Command, os.Args[0])
+}
+
 type FakeProcess struct {
 	pid  int
 	ppid int
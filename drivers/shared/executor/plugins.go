@@ -16,6 +16,13 @@ type ExecutorConfig struct {
 	// LogLevel is the level of the logs to putout
 	LogLevel string
 
+	// LogMaxFileSizeMB is the size in MB at which the log file is rotated.
+	// If zero, the log file isn't rotated.
+	LogMaxFileSizeMB int
+
+	// LogMaxFiles is the number of rotated log files to keep.
+	LogMaxFiles int
+
 	// FSIsolation if set will use an executor implementation that support
 	// filesystem isolation
 	FSIsolation bool
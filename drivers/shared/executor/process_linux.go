@@ -0,0 +1,251 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"syscall"
+
+	"github.com/hashicorp/nomad/drivers/shared/proclimits"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	lconfigs "github.com/opencontainers/runc/libcontainer/configs"
+	"golang.org/x/sys/unix"
+)
+
+// processShimArg is the argument the executor binary is re-executed with to
+// apply the process config before running the task command.
+const processShimArg = "process-shim"
+
+// ioprio classes as defined in linux/ioprio.h
+const (
+	ioprioClassShift = 13
+	ioprioWhoProcess = 1
+)
+
+var ioniceClasses = map[string]int{
+	structs.TaskProcessIOniceClassRealtime:   1,
+	structs.TaskProcessIOniceClassBestEffort: 2,
+	structs.TaskProcessIOniceClassIdle:       3,
+}
+
+// processShimConfig is passed to the process shim. The credential of the
+// task user is applied by the shim once the process config is set, as
+// raising limits or priority requires privileges.
+type processShimConfig struct {
+	Process    *drivers.ProcessConfig
+	Credential *syscall.Credential
+}
+
+// init is used when the UniversalExecutor starts a process with a process
+// config. The executor binary is re-executed with the process-shim argument,
+// applies the process config to itself and then execve's into the user
+// process, which inherits it.
+func init() {
+	if len(os.Args) > 1 && os.Args[1] == processShimArg {
+		// The nice value and I/O scheduling class apply to the calling
+		// thread only, so they must be set on the thread that calls execve.
+		runtime.LockOSThread()
+		if err := processShim(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to start process: %v\n", err)
+			os.Exit(1)
+		}
+		panic("--this line should have never been executed, congratulations--")
+	}
+}
+
+// hasProcessConfig returns whether the process config sets any attribute.
+func hasProcessConfig(process *drivers.ProcessConfig) bool {
+	return process != nil && (process.Umask != "" || process.Nice != 0 ||
+		process.IOniceClass != "" || len(process.Rlimits) != 0)
+}
+
+// processCommand returns the command and arguments that run path with args
+// after applying the process config. The user credential of cmd is moved to
+// the process shim, which drops privileges before running the command.
+func processCommand(process *drivers.ProcessConfig, cmd *exec.Cmd, path string, args []string) (string, []string, error) {
+	if !hasProcessConfig(process) {
+		return path, args, nil
+	}
+
+	cfg := processShimConfig{Process: process}
+	if cmd.SysProcAttr != nil && cmd.SysProcAttr.Credential != nil {
+		cfg.Credential = cmd.SysProcAttr.Credential
+		cmd.SysProcAttr.Credential = nil
+	}
+
+	encoded, err := json.Marshal(cfg)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode process config: %v", err)
+	}
+	shimArgs := append([]string{processShimArg, string(encoded), "--", path}, args...)
+	return "/proc/self/exe", shimArgs, nil
+}
+
+// processShim parses the arguments built by processCommand, applies the
+// process config and credential and executes the command.
+func processShim(args []string) error {
+	if len(args) < 3 || args[1] != "--" {
+		return errors.New("invalid arguments")
+	}
+
+	var cfg processShimConfig
+	if err := json.Unmarshal([]byte(args[0]), &cfg); err != nil {
+		return fmt.Errorf("invalid process config: %v", err)
+	}
+
+	if cfg.Process.Umask != "" {
+		mask, err := parseUmask(cfg.Process.Umask)
+		if err != nil {
+			return err
+		}
+		unix.Umask(int(mask))
+	}
+	if err := setProcessRlimits(cfg.Process); err != nil {
+		return err
+	}
+	if err := setProcessPriority(0, cfg.Process); err != nil {
+		return err
+	}
+
+	if cred := cfg.Credential; cred != nil {
+		groups := make([]int, len(cred.Groups))
+		for i, gid := range cred.Groups {
+			groups[i] = int(gid)
+		}
+		if err := syscall.Setgroups(groups); err != nil {
+			return fmt.Errorf("failed to set groups: %v", err)
+		}
+		if err := syscall.Setgid(int(cred.Gid)); err != nil {
+			return fmt.Errorf("failed to set gid: %v", err)
+		}
+		if err := syscall.Setuid(int(cred.Uid)); err != nil {
+			return fmt.Errorf("failed to set uid: %v", err)
+		}
+	}
+
+	return syscall.Exec(args[2], args[2:], os.Environ())
+}
+
+// parseUmask parses an octal umask string.
+func parseUmask(umask string) (uint32, error) {
+	mask, err := strconv.ParseUint(umask, 8, 32)
+	if err != nil || mask > 0777 {
+		return 0, fmt.Errorf("invalid umask %q", umask)
+	}
+	return uint32(mask), nil
+}
+
+// processRlimitsToLibcontainer converts the named rlimits of a process
+// config into libcontainer rlimits. The soft and hard limits are both set
+// to the configured value.
+func processRlimitsToLibcontainer(rlimits map[string]uint64) ([]lconfigs.Rlimit, error) {
+	if len(rlimits) == 0 {
+		return nil, nil
+	}
+
+	r := make([]lconfigs.Rlimit, 0, len(rlimits))
+	for name, value := range rlimits {
+		resource, ok := proclimits.RlimitResource(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown rlimit %q", name)
+		}
+		r = append(r, lconfigs.Rlimit{Type: resource, Hard: value, Soft: value})
+	}
+	return r, nil
+}
+
+// configureProcess sets the umask and rlimits of the task process on the
+// libcontainer config.
+func configureProcess(cfg *lconfigs.Config, process *drivers.ProcessConfig) error {
+	if process == nil {
+		return nil
+	}
+
+	if process.Umask != "" {
+		mask, err := parseUmask(process.Umask)
+		if err != nil {
+			return err
+		}
+		cfg.Umask = &mask
+	}
+
+	rlimits, err := processRlimitsToLibcontainer(process.Rlimits)
+	if err != nil {
+		return err
+	}
+	cfg.Rlimits = rlimits
+	return nil
+}
+
+// withProcessPriority runs f on a thread with the nice value and I/O
+// scheduling class of the process config, so that they are inherited by a
+// child forked in f. The thread is discarded once f returns, so the
+// priority of the executor is left unchanged.
+func withProcessPriority(f func() error, process *drivers.ProcessConfig) error {
+	if process == nil || (process.Nice == 0 && process.IOniceClass == "") {
+		return f()
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		// The thread is never unlocked, so the runtime terminates it when
+		// the goroutine exits
+		runtime.LockOSThread()
+		if err := setProcessPriority(unix.Gettid(), process); err != nil {
+			errCh <- err
+			return
+		}
+		errCh <- f()
+	}()
+	return <-errCh
+}
+
+// setProcessRlimits sets the rlimits of the process config on the calling
+// process.
+func setProcessRlimits(process *drivers.ProcessConfig) error {
+	for name, value := range process.Rlimits {
+		resource, ok := proclimits.RlimitResource(name)
+		if !ok {
+			return fmt.Errorf("unknown rlimit %q", name)
+		}
+		limit := &unix.Rlimit{Cur: value, Max: value}
+		if err := unix.Setrlimit(resource, limit); err != nil {
+			return fmt.Errorf("failed to set rlimit %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// setProcessPriority sets the nice value and I/O scheduling class of the
+// process config on the thread with the given id, or the calling thread if
+// it is 0.
+func setProcessPriority(tid int, process *drivers.ProcessConfig) error {
+	if process.Nice != 0 {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, tid, process.Nice); err != nil {
+			return fmt.Errorf("failed to set nice value: %v", err)
+		}
+	}
+
+	if process.IOniceClass != "" {
+		class, ok := ioniceClasses[process.IOniceClass]
+		if !ok {
+			return fmt.Errorf("invalid ionice class %q", process.IOniceClass)
+		}
+
+		// realtime and best-effort take a priority level, use the default
+		// level of 4 in the middle of the range
+		prio := class << ioprioClassShift
+		if class != ioniceClasses[structs.TaskProcessIOniceClassIdle] {
+			prio |= 4
+		}
+		if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), uintptr(prio)); errno != 0 {
+			return fmt.Errorf("failed to set ionice class: %v", errno)
+		}
+	}
+	return nil
+}
@@ -46,6 +46,12 @@ type LaunchRequest struct {
 	CpusetCgroup         string                       `protobuf:"bytes,17,opt,name=cpuset_cgroup,json=cpusetCgroup,proto3" json:"cpuset_cgroup,omitempty"`
 	AllowCaps            []string                     `protobuf:"bytes,18,rep,name=allow_caps,json=allowCaps,proto3" json:"allow_caps,omitempty"`
 	Capabilities         []string                     `protobuf:"bytes,19,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Process              *proto1.ProcessConfig        `protobuf:"bytes,20,opt,name=process,proto3" json:"process,omitempty"`
+	Sandbox              *Sandbox                     `protobuf:"bytes,21,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
+	UserNamespace        bool                         `protobuf:"varint,22,opt,name=user_namespace,json=userNamespace,proto3" json:"user_namespace,omitempty"`
+	IoWeight             uint32                       `protobuf:"varint,23,opt,name=io_weight,json=ioWeight,proto3" json:"io_weight,omitempty"`
+	CgroupFreezeOnStop   bool                         `protobuf:"varint,24,opt,name=cgroup_freeze_on_stop,json=cgroupFreezeOnStop,proto3" json:"cgroup_freeze_on_stop,omitempty"`
+	RestoreDir           string                       `protobuf:"bytes,25,opt,name=restore_dir,json=restoreDir,proto3" json:"restore_dir,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
 	XXX_unrecognized     []byte                       `json:"-"`
 	XXX_sizecache        int32                        `json:"-"`
@@ -209,6 +215,48 @@ func (m *LaunchRequest) GetCapabilities() []string {
 	return nil
 }
 
+func (m *LaunchRequest) GetProcess() *proto1.ProcessConfig {
+	if m != nil {
+		return m.Process
+	}
+	return nil
+}
+
+func (m *LaunchRequest) GetSandbox() *Sandbox {
+	if m != nil {
+		return m.Sandbox
+	}
+	return nil
+}
+
+func (m *LaunchRequest) GetUserNamespace() bool {
+	if m != nil {
+		return m.UserNamespace
+	}
+	return false
+}
+
+func (m *LaunchRequest) GetIoWeight() uint32 {
+	if m != nil {
+		return m.IoWeight
+	}
+	return 0
+}
+
+func (m *LaunchRequest) GetCgroupFreezeOnStop() bool {
+	if m != nil {
+		return m.CgroupFreezeOnStop
+	}
+	return false
+}
+
+func (m *LaunchRequest) GetRestoreDir() string {
+	if m != nil {
+		return m.RestoreDir
+	}
+	return ""
+}
+
 type LaunchResponse struct {
 	Process              *ProcessState `protobuf:"bytes,1,opt,name=process,proto3" json:"process,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
@@ -742,6 +790,7 @@ func (m *ExecRequest) GetArgs() []string {
 type ExecResponse struct {
 	Output               []byte   `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
 	ExitCode             int32    `protobuf:"varint,2,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Stderr               []byte   `protobuf:"bytes,3,opt,name=stderr,proto3" json:"stderr,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -786,11 +835,19 @@ func (m *ExecResponse) GetExitCode() int32 {
 	return 0
 }
 
+func (m *ExecResponse) GetStderr() []byte {
+	if m != nil {
+		return m.Stderr
+	}
+	return nil
+}
+
 type ProcessState struct {
 	Pid                  int32                `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
 	ExitCode             int32                `protobuf:"varint,2,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
 	Signal               int32                `protobuf:"varint,3,opt,name=signal,proto3" json:"signal,omitempty"`
 	Time                 *timestamp.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	OomKilled            bool                 `protobuf:"varint,5,opt,name=oom_killed,json=oomKilled,proto3" json:"oom_killed,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -849,6 +906,169 @@ func (m *ProcessState) GetTime() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *ProcessState) GetOomKilled() bool {
+	if m != nil {
+		return m.OomKilled
+	}
+	return false
+}
+
+type Sandbox struct {
+	Paths                []*SandboxPath `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Sandbox) Reset()         { *m = Sandbox{} }
+func (m *Sandbox) String() string { return proto.CompactTextString(m) }
+func (*Sandbox) ProtoMessage()    {}
+func (*Sandbox) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{17}
+}
+
+func (m *Sandbox) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Sandbox.Unmarshal(m, b)
+}
+func (m *Sandbox) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Sandbox.Marshal(b, m, deterministic)
+}
+func (m *Sandbox) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Sandbox.Merge(m, src)
+}
+func (m *Sandbox) XXX_Size() int {
+	return xxx_messageInfo_Sandbox.Size(m)
+}
+func (m *Sandbox) XXX_DiscardUnknown() {
+	xxx_messageInfo_Sandbox.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Sandbox proto.InternalMessageInfo
+
+func (m *Sandbox) GetPaths() []*SandboxPath {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+type SandboxPath struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Mode                 string   `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SandboxPath) Reset()         { *m = SandboxPath{} }
+func (m *SandboxPath) String() string { return proto.CompactTextString(m) }
+func (*SandboxPath) ProtoMessage()    {}
+func (*SandboxPath) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{18}
+}
+
+func (m *SandboxPath) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SandboxPath.Unmarshal(m, b)
+}
+func (m *SandboxPath) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SandboxPath.Marshal(b, m, deterministic)
+}
+func (m *SandboxPath) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SandboxPath.Merge(m, src)
+}
+func (m *SandboxPath) XXX_Size() int {
+	return xxx_messageInfo_SandboxPath.Size(m)
+}
+func (m *SandboxPath) XXX_DiscardUnknown() {
+	xxx_messageInfo_SandboxPath.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SandboxPath proto.InternalMessageInfo
+
+func (m *SandboxPath) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *SandboxPath) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+type CheckpointRequest struct {
+	Dir                  string   `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointRequest) Reset()         { *m = CheckpointRequest{} }
+func (m *CheckpointRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckpointRequest) ProtoMessage()    {}
+func (*CheckpointRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{19}
+}
+
+func (m *CheckpointRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointRequest.Unmarshal(m, b)
+}
+func (m *CheckpointRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckpointRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointRequest.Merge(m, src)
+}
+func (m *CheckpointRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckpointRequest.Size(m)
+}
+func (m *CheckpointRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointRequest proto.InternalMessageInfo
+
+func (m *CheckpointRequest) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+type CheckpointResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointResponse) Reset()         { *m = CheckpointResponse{} }
+func (m *CheckpointResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckpointResponse) ProtoMessage()    {}
+func (*CheckpointResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{20}
+}
+
+func (m *CheckpointResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointResponse.Unmarshal(m, b)
+}
+func (m *CheckpointResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckpointResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointResponse.Merge(m, src)
+}
+func (m *CheckpointResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckpointResponse.Size(m)
+}
+func (m *CheckpointResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*LaunchRequest)(nil), "hashicorp.nomad.plugins.executor.proto.LaunchRequest")
 	proto.RegisterType((*LaunchResponse)(nil), "hashicorp.nomad.plugins.executor.proto.LaunchResponse")
@@ -867,6 +1087,10 @@ func init() {
 	proto.RegisterType((*ExecRequest)(nil), "hashicorp.nomad.plugins.executor.proto.ExecRequest")
 	proto.RegisterType((*ExecResponse)(nil), "hashicorp.nomad.plugins.executor.proto.ExecResponse")
 	proto.RegisterType((*ProcessState)(nil), "hashicorp.nomad.plugins.executor.proto.ProcessState")
+	proto.RegisterType((*Sandbox)(nil), "hashicorp.nomad.plugins.executor.proto.Sandbox")
+	proto.RegisterType((*SandboxPath)(nil), "hashicorp.nomad.plugins.executor.proto.SandboxPath")
+	proto.RegisterType((*CheckpointRequest)(nil), "hashicorp.nomad.plugins.executor.proto.CheckpointRequest")
+	proto.RegisterType((*CheckpointResponse)(nil), "hashicorp.nomad.plugins.executor.proto.CheckpointResponse")
 }
 
 func init() {
@@ -874,74 +1098,89 @@ func init() {
 }
 
 var fileDescriptor_66b85426380683f3 = []byte{
-	// 1058 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0x6d, 0x6f, 0x1b, 0x45,
-	0x10, 0xee, 0xc5, 0x89, 0x5f, 0xc6, 0x76, 0xe2, 0x2e, 0xa8, 0x5c, 0x8d, 0x50, 0xcd, 0x21, 0x51,
-	0x0b, 0xca, 0x25, 0x4a, 0xdf, 0x90, 0x90, 0x28, 0x22, 0x2d, 0xa8, 0x52, 0x1a, 0x45, 0x97, 0x42,
-	0x25, 0x3e, 0x70, 0x6c, 0xee, 0xb6, 0xf6, 0x2a, 0xf6, 0xed, 0xb2, 0xbb, 0xe7, 0x04, 0x09, 0x89,
-	0x4f, 0xfc, 0x03, 0x90, 0xf8, 0x31, 0xfc, 0x38, 0xb4, 0x6f, 0x17, 0x3b, 0x2d, 0xd5, 0xb9, 0x88,
-	0x4f, 0xbe, 0x1d, 0x3f, 0xcf, 0xcc, 0xec, 0xce, 0xcc, 0x33, 0x70, 0x27, 0x17, 0x74, 0x41, 0x84,
-	0xdc, 0x95, 0x53, 0x2c, 0x48, 0xbe, 0x4b, 0x2e, 0x48, 0x56, 0x2a, 0x26, 0x76, 0xb9, 0x60, 0x8a,
-	0x55, 0xc7, 0xd8, 0x1c, 0xd1, 0xc7, 0x53, 0x2c, 0xa7, 0x34, 0x63, 0x82, 0xc7, 0x05, 0x9b, 0xe3,
-	0x3c, 0xe6, 0xb3, 0x72, 0x42, 0x0b, 0x19, 0xaf, 0xe2, 0x86, 0xb7, 0x26, 0x8c, 0x4d, 0x66, 0xc4,
-	0x3a, 0x39, 0x2d, 0x5f, 0xee, 0x2a, 0x3a, 0x27, 0x52, 0xe1, 0x39, 0x77, 0x80, 0xc8, 0x11, 0x77,
-	0x7d, 0x78, 0x1b, 0xce, 0x9e, 0x2c, 0x26, 0xfa, 0xbb, 0x09, 0xfd, 0x43, 0x5c, 0x16, 0xd9, 0x34,
-	0x21, 0x3f, 0x97, 0x44, 0x2a, 0x34, 0x80, 0x46, 0x36, 0xcf, 0xc3, 0x60, 0x14, 0x8c, 0x3b, 0x89,
-	0xfe, 0x44, 0x08, 0x36, 0xb1, 0x98, 0xc8, 0x70, 0x63, 0xd4, 0x18, 0x77, 0x12, 0xf3, 0x8d, 0x8e,
-	0xa0, 0x23, 0x88, 0x64, 0xa5, 0xc8, 0x88, 0x0c, 0x1b, 0xa3, 0x60, 0xdc, 0xdd, 0xdf, 0x8b, 0xff,
-	0x2d, 0x71, 0x17, 0xdf, 0x86, 0x8c, 0x13, 0xcf, 0x4b, 0x2e, 0x5d, 0xa0, 0x5b, 0xd0, 0x95, 0x2a,
-	0x67, 0xa5, 0x4a, 0x39, 0x56, 0xd3, 0x70, 0xd3, 0x44, 0x07, 0x6b, 0x3a, 0xc6, 0x6a, 0xea, 0x00,
-	0x44, 0x08, 0x0b, 0xd8, 0xaa, 0x00, 0x44, 0x08, 0x03, 0x18, 0x40, 0x83, 0x14, 0x8b, 0xb0, 0x69,
-	0x92, 0xd4, 0x9f, 0x3a, 0xef, 0x52, 0x12, 0x11, 0xb6, 0x0c, 0xd6, 0x7c, 0xa3, 0x9b, 0xd0, 0x56,
-	0x58, 0x9e, 0xa5, 0x39, 0x15, 0x61, 0xdb, 0xd8, 0x5b, 0xfa, 0xfc, 0x98, 0x0a, 0x74, 0x1b, 0x76,
-	0x7c, 0x3e, 0xe9, 0x8c, 0xce, 0xa9, 0x92, 0x61, 0x67, 0x14, 0x8c, 0xdb, 0xc9, 0xb6, 0x37, 0x1f,
-	0x1a, 0x2b, 0xda, 0x83, 0x77, 0x4f, 0xb1, 0xa4, 0x59, 0xca, 0x05, 0xcb, 0x88, 0x94, 0x69, 0x36,
-	0x11, 0xac, 0xe4, 0x21, 0x18, 0x34, 0x32, 0xff, 0x1d, 0xdb, 0xbf, 0x0e, 0xcc, 0x3f, 0xe8, 0x31,
-	0x34, 0xe7, 0xac, 0x2c, 0x94, 0x0c, 0xbb, 0xa3, 0xc6, 0xb8, 0xbb, 0x7f, 0xa7, 0xe6, 0x53, 0x3d,
-	0xd3, 0xa4, 0xc4, 0x71, 0xd1, 0xb7, 0xd0, 0xca, 0xc9, 0x82, 0xea, 0x17, 0xef, 0x19, 0x37, 0x9f,
-	0xd5, 0x74, 0xf3, 0xd8, 0xb0, 0x12, 0xcf, 0x46, 0x53, 0xb8, 0x5e, 0x10, 0x75, 0xce, 0xc4, 0x59,
-	0x4a, 0x25, 0x9b, 0x61, 0x45, 0x59, 0x11, 0xf6, 0x4d, 0x11, 0xbf, 0xa8, 0xe9, 0xf2, 0xc8, 0xf2,
-	0x9f, 0x7a, 0xfa, 0x09, 0x27, 0x59, 0x32, 0x28, 0xae, 0x58, 0x51, 0x04, 0xfd, 0x82, 0xa5, 0x9c,
-	0x2e, 0x98, 0x4a, 0x05, 0x63, 0x2a, 0xdc, 0x36, 0x6f, 0xd4, 0x2d, 0xd8, 0xb1, 0xb6, 0x25, 0x8c,
-	0x29, 0x34, 0x86, 0x41, 0x4e, 0x5e, 0xe2, 0x72, 0xa6, 0x52, 0x4e, 0xf3, 0x74, 0xce, 0x72, 0x12,
-	0xee, 0x98, 0xd2, 0x6c, 0x3b, 0xfb, 0x31, 0xcd, 0x9f, 0xb1, 0x9c, 0x2c, 0x23, 0x29, 0xcf, 0x2c,
-	0x72, 0xb0, 0x82, 0x7c, 0xca, 0x33, 0x83, 0xfc, 0x08, 0xfa, 0x19, 0x2f, 0x25, 0x51, 0xbe, 0x36,
-	0xd7, 0x0d, 0xac, 0x67, 0x8d, 0xae, 0x2a, 0x1f, 0x00, 0xe0, 0xd9, 0x8c, 0x9d, 0xa7, 0x19, 0xe6,
-	0x32, 0x44, 0xa6, 0x71, 0x3a, 0xc6, 0x72, 0x80, 0xb9, 0x44, 0x11, 0xf4, 0x32, 0xcc, 0xf1, 0x29,
-	0x9d, 0x51, 0x45, 0x89, 0x0c, 0xdf, 0x31, 0x80, 0x15, 0x5b, 0xf4, 0x13, 0x6c, 0xfb, 0xe9, 0x91,
-	0x9c, 0x15, 0x92, 0xa0, 0x23, 0x68, 0xb9, 0xb6, 0x30, 0x23, 0xd4, 0xdd, 0xbf, 0x17, 0xd7, 0x9b,
-	0xe7, 0xd8, 0xb5, 0xcc, 0x89, 0xc2, 0x8a, 0x24, 0xde, 0x49, 0xd4, 0x87, 0xee, 0x0b, 0x4c, 0x95,
-	0x9b, 0xce, 0xe8, 0x47, 0xe8, 0xd9, 0xe3, 0xff, 0x14, 0xee, 0x10, 0x76, 0x4e, 0xa6, 0xa5, 0xca,
-	0xd9, 0x79, 0xe1, 0x05, 0xe1, 0x06, 0x34, 0x25, 0x9d, 0x14, 0x78, 0xe6, 0x34, 0xc1, 0x9d, 0xd0,
-	0x87, 0xd0, 0x9b, 0x08, 0x9c, 0x91, 0x94, 0x13, 0x41, 0x59, 0x1e, 0x6e, 0x8c, 0x82, 0x71, 0x23,
-	0xe9, 0x1a, 0xdb, 0xb1, 0x31, 0x45, 0x08, 0x06, 0x97, 0xde, 0x6c, 0xc6, 0xd1, 0x14, 0x6e, 0x7c,
-	0xc7, 0x73, 0x1d, 0xb4, 0xd2, 0x01, 0x17, 0x68, 0x45, 0x53, 0x82, 0xff, 0xac, 0x29, 0xd1, 0x4d,
-	0x78, 0xef, 0x95, 0x48, 0x2e, 0x89, 0x01, 0x6c, 0x7f, 0x4f, 0x84, 0xa4, 0xcc, 0xdf, 0x32, 0xfa,
-	0x14, 0x76, 0x2a, 0x8b, 0x7b, 0xdb, 0x10, 0x5a, 0x0b, 0x6b, 0x72, 0x37, 0xf7, 0xc7, 0xe8, 0x13,
-	0xe8, 0xe9, 0x77, 0xab, 0x32, 0x1f, 0x42, 0x9b, 0x16, 0x8a, 0x88, 0x85, 0x7b, 0xa4, 0x46, 0x52,
-	0x9d, 0xa3, 0x17, 0xd0, 0x77, 0x58, 0xe7, 0xf6, 0x1b, 0xd8, 0x92, 0xda, 0xb0, 0xe6, 0x15, 0x9f,
-	0x63, 0x79, 0x66, 0x1d, 0x59, 0x7a, 0x74, 0x1b, 0xfa, 0x27, 0xa6, 0x12, 0xaf, 0x2f, 0xd4, 0x96,
-	0x2f, 0x94, 0xbe, 0xac, 0x07, 0xba, 0xeb, 0x9f, 0x41, 0xf7, 0xc9, 0x05, 0xc9, 0x3c, 0xf1, 0x01,
-	0xb4, 0x73, 0x82, 0xf3, 0x19, 0x2d, 0x88, 0x4b, 0x6a, 0x18, 0xdb, 0xe5, 0x12, 0xfb, 0xe5, 0x12,
-	0x3f, 0xf7, 0xcb, 0x25, 0xa9, 0xb0, 0x7e, 0x55, 0x6c, 0xbc, 0xba, 0x2a, 0x1a, 0x97, 0xab, 0x22,
-	0x3a, 0x80, 0x9e, 0x0d, 0xe6, 0xee, 0x7f, 0x03, 0x9a, 0xac, 0x54, 0xbc, 0x54, 0x26, 0x56, 0x2f,
-	0x71, 0x27, 0xf4, 0x3e, 0x74, 0xc8, 0x05, 0x55, 0x69, 0xa6, 0xc7, 0x7a, 0xc3, 0xdc, 0xa0, 0xad,
-	0x0d, 0x07, 0x2c, 0x27, 0xd1, 0xef, 0x01, 0xf4, 0x96, 0x3b, 0x56, 0xc7, 0xe6, 0x34, 0x77, 0x37,
-	0xd5, 0x9f, 0x6f, 0xe4, 0x2f, 0xbd, 0x4d, 0x63, 0xf9, 0x6d, 0x50, 0x0c, 0x9b, 0x7a, 0x6d, 0x9a,
-	0x85, 0xf3, 0xe6, 0x6b, 0x1b, 0xdc, 0xfe, 0x9f, 0x1d, 0x68, 0x3f, 0x71, 0x83, 0x84, 0x7e, 0x81,
-	0xa6, 0x9d, 0x7e, 0x74, 0xbf, 0xee, 0xd4, 0xad, 0xec, 0xda, 0xe1, 0x83, 0x75, 0x69, 0xae, 0x7e,
-	0xd7, 0x90, 0x84, 0x4d, 0xad, 0x03, 0xe8, 0x6e, 0x5d, 0x0f, 0x4b, 0x22, 0x32, 0xbc, 0xb7, 0x1e,
-	0xa9, 0x0a, 0xfa, 0x1b, 0xb4, 0xfd, 0x38, 0xa3, 0x87, 0x75, 0x7d, 0x5c, 0x91, 0x93, 0xe1, 0xe7,
-	0xeb, 0x13, 0xab, 0x04, 0xfe, 0x08, 0x60, 0xe7, 0xca, 0x48, 0xa3, 0x2f, 0xeb, 0xfa, 0x7b, 0xbd,
-	0xea, 0x0c, 0x1f, 0xbd, 0x35, 0xbf, 0x4a, 0xeb, 0x57, 0x68, 0x39, 0xed, 0x40, 0xb5, 0x2b, 0xba,
-	0x2a, 0x3f, 0xc3, 0x87, 0x6b, 0xf3, 0xaa, 0xe8, 0x17, 0xb0, 0x65, 0x74, 0x01, 0xd5, 0x2e, 0xeb,
-	0xb2, 0x76, 0x0d, 0xef, 0xaf, 0xc9, 0xf2, 0x71, 0xf7, 0x02, 0xdd, 0xff, 0x56, 0x58, 0xea, 0xf7,
-	0xff, 0x8a, 0x62, 0xd5, 0xef, 0xff, 0x2b, 0xfa, 0x65, 0xfa, 0x5f, 0x8f, 0x61, 0xfd, 0xfe, 0x5f,
-	0xd2, 0xbb, 0xfa, 0xfd, 0xbf, 0xac, 0x5b, 0xd1, 0x35, 0xf4, 0x57, 0x00, 0x7d, 0x6d, 0x3a, 0x51,
-	0x82, 0xe0, 0x39, 0x2d, 0x26, 0xe8, 0x51, 0x4d, 0xf1, 0xd6, 0x2c, 0x2b, 0xe0, 0x8e, 0xe9, 0x53,
-	0xf9, 0xea, 0xed, 0x1d, 0xf8, 0xb4, 0xc6, 0xc1, 0x5e, 0xf0, 0x75, 0xeb, 0x87, 0x2d, 0xab, 0x59,
-	0x4d, 0xf3, 0x73, 0xf7, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x2e, 0x63, 0xc4, 0xd3, 0x74, 0x0c,
-	0x00, 0x00,
+	// 1297 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x5b, 0x6f, 0x1b, 0x45,
+	0x14, 0x66, 0xe3, 0x24, 0xb6, 0x8f, 0xed, 0x24, 0x1d, 0xda, 0x74, 0x6b, 0x84, 0x6a, 0x16, 0x95,
+	0x5a, 0x50, 0x9c, 0xd0, 0x2b, 0x17, 0x89, 0x22, 0x92, 0x16, 0x45, 0xb4, 0x21, 0x5a, 0x17, 0x2a,
+	0x81, 0xc4, 0x32, 0xd9, 0x9d, 0xd8, 0xa3, 0xd8, 0x3b, 0xc3, 0xcc, 0x6c, 0x12, 0x10, 0x12, 0x4f,
+	0xfc, 0x03, 0x1e, 0x78, 0xe1, 0x8d, 0xff, 0xc7, 0x5f, 0x40, 0x73, 0xdb, 0xd8, 0x6d, 0x51, 0xd7,
+	0x45, 0x3c, 0xed, 0xcc, 0xb7, 0xe7, 0x7e, 0xce, 0x7e, 0x67, 0xe1, 0x46, 0x26, 0xe8, 0x09, 0x11,
+	0x72, 0x4b, 0x8e, 0xb1, 0x20, 0xd9, 0x16, 0x39, 0x23, 0x69, 0xa1, 0x98, 0xd8, 0xe2, 0x82, 0x29,
+	0x56, 0x5e, 0x07, 0xe6, 0x8a, 0xde, 0x19, 0x63, 0x39, 0xa6, 0x29, 0x13, 0x7c, 0x90, 0xb3, 0x29,
+	0xce, 0x06, 0x7c, 0x52, 0x8c, 0x68, 0x2e, 0x07, 0xf3, 0x72, 0xdd, 0xab, 0x23, 0xc6, 0x46, 0x13,
+	0x62, 0x8d, 0x1c, 0x16, 0x47, 0x5b, 0x8a, 0x4e, 0x89, 0x54, 0x78, 0xca, 0x9d, 0x40, 0xe4, 0x14,
+	0xb7, 0xbc, 0x7b, 0xeb, 0xce, 0xde, 0xac, 0x4c, 0xf4, 0x77, 0x03, 0x3a, 0x8f, 0x70, 0x91, 0xa7,
+	0xe3, 0x98, 0xfc, 0x58, 0x10, 0xa9, 0xd0, 0x06, 0xd4, 0xd2, 0x69, 0x16, 0x06, 0xbd, 0xa0, 0xdf,
+	0x8c, 0xf5, 0x11, 0x21, 0x58, 0xc6, 0x62, 0x24, 0xc3, 0xa5, 0x5e, 0xad, 0xdf, 0x8c, 0xcd, 0x19,
+	0xed, 0x43, 0x53, 0x10, 0xc9, 0x0a, 0x91, 0x12, 0x19, 0xd6, 0x7a, 0x41, 0xbf, 0x75, 0x73, 0x7b,
+	0xf0, 0x6f, 0x81, 0x3b, 0xff, 0xd6, 0xe5, 0x20, 0xf6, 0x7a, 0xf1, 0xb9, 0x09, 0x74, 0x15, 0x5a,
+	0x52, 0x65, 0xac, 0x50, 0x09, 0xc7, 0x6a, 0x1c, 0x2e, 0x1b, 0xef, 0x60, 0xa1, 0x03, 0xac, 0xc6,
+	0x4e, 0x80, 0x08, 0x61, 0x05, 0x56, 0x4a, 0x01, 0x22, 0x84, 0x11, 0xd8, 0x80, 0x1a, 0xc9, 0x4f,
+	0xc2, 0x55, 0x13, 0xa4, 0x3e, 0xea, 0xb8, 0x0b, 0x49, 0x44, 0x58, 0x37, 0xb2, 0xe6, 0x8c, 0xae,
+	0x40, 0x43, 0x61, 0x79, 0x9c, 0x64, 0x54, 0x84, 0x0d, 0x83, 0xd7, 0xf5, 0x7d, 0x97, 0x0a, 0x74,
+	0x1d, 0xd6, 0x7d, 0x3c, 0xc9, 0x84, 0x4e, 0xa9, 0x92, 0x61, 0xb3, 0x17, 0xf4, 0x1b, 0xf1, 0x9a,
+	0x87, 0x1f, 0x19, 0x14, 0x6d, 0xc3, 0xc5, 0x43, 0x2c, 0x69, 0x9a, 0x70, 0xc1, 0x52, 0x22, 0x65,
+	0x92, 0x8e, 0x04, 0x2b, 0x78, 0x08, 0x46, 0x1a, 0x99, 0x77, 0x07, 0xf6, 0xd5, 0x8e, 0x79, 0x83,
+	0x76, 0x61, 0x75, 0xca, 0x8a, 0x5c, 0xc9, 0xb0, 0xd5, 0xab, 0xf5, 0x5b, 0x37, 0x6f, 0x54, 0x2c,
+	0xd5, 0x63, 0xad, 0x14, 0x3b, 0x5d, 0xf4, 0x05, 0xd4, 0x33, 0x72, 0x42, 0x75, 0xc5, 0xdb, 0xc6,
+	0xcc, 0xfb, 0x15, 0xcd, 0xec, 0x1a, 0xad, 0xd8, 0x6b, 0xa3, 0x31, 0x5c, 0xc8, 0x89, 0x3a, 0x65,
+	0xe2, 0x38, 0xa1, 0x92, 0x4d, 0xb0, 0xa2, 0x2c, 0x0f, 0x3b, 0xa6, 0x89, 0x9f, 0x54, 0x34, 0xb9,
+	0x6f, 0xf5, 0xf7, 0xbc, 0xfa, 0x90, 0x93, 0x34, 0xde, 0xc8, 0x9f, 0x41, 0x51, 0x04, 0x9d, 0x9c,
+	0x25, 0x9c, 0x9e, 0x30, 0x95, 0x08, 0xc6, 0x54, 0xb8, 0x66, 0x6a, 0xd4, 0xca, 0xd9, 0x81, 0xc6,
+	0x62, 0xc6, 0x14, 0xea, 0xc3, 0x46, 0x46, 0x8e, 0x70, 0x31, 0x51, 0x09, 0xa7, 0x59, 0x32, 0x65,
+	0x19, 0x09, 0xd7, 0x4d, 0x6b, 0xd6, 0x1c, 0x7e, 0x40, 0xb3, 0xc7, 0x2c, 0x23, 0xb3, 0x92, 0x94,
+	0xa7, 0x56, 0x72, 0x63, 0x4e, 0x72, 0x8f, 0xa7, 0x46, 0xf2, 0x6d, 0xe8, 0xa4, 0xbc, 0x90, 0x44,
+	0xf9, 0xde, 0x5c, 0x30, 0x62, 0x6d, 0x0b, 0xba, 0xae, 0xbc, 0x09, 0x80, 0x27, 0x13, 0x76, 0x9a,
+	0xa4, 0x98, 0xcb, 0x10, 0x99, 0xc1, 0x69, 0x1a, 0x64, 0x07, 0x73, 0x89, 0x22, 0x68, 0xa7, 0x98,
+	0xe3, 0x43, 0x3a, 0xa1, 0x8a, 0x12, 0x19, 0xbe, 0x6e, 0x04, 0xe6, 0x30, 0xb4, 0x0f, 0x75, 0x37,
+	0x04, 0xe1, 0x45, 0x53, 0xbf, 0xdb, 0x15, 0xeb, 0xe7, 0xe7, 0x83, 0xe5, 0x47, 0x74, 0x14, 0x7b,
+	0x23, 0x68, 0x0f, 0xea, 0x12, 0xe7, 0xd9, 0x21, 0x3b, 0x0b, 0x2f, 0x19, 0x7b, 0x5b, 0x83, 0x6a,
+	0x6c, 0x30, 0x18, 0x5a, 0xb5, 0xd8, 0xeb, 0xa3, 0x6b, 0xb0, 0xa6, 0x27, 0x3e, 0xc9, 0xf1, 0x94,
+	0x48, 0x8e, 0x53, 0x12, 0x6e, 0x9a, 0xda, 0x77, 0x34, 0xba, 0xef, 0x41, 0xf4, 0x06, 0x34, 0x29,
+	0x4b, 0x4e, 0x09, 0x1d, 0x8d, 0x55, 0x78, 0xb9, 0x17, 0xf4, 0x3b, 0x71, 0x83, 0xb2, 0xa7, 0xe6,
+	0x8e, 0x3e, 0x80, 0x4b, 0xb6, 0x7e, 0xc9, 0x91, 0x20, 0xe4, 0x67, 0x92, 0xb0, 0x3c, 0x91, 0x8a,
+	0xf1, 0x30, 0xb4, 0xa3, 0x6e, 0x5f, 0x3e, 0x34, 0xef, 0xbe, 0xca, 0x87, 0x8a, 0x71, 0xfd, 0x9d,
+	0x0a, 0x22, 0x15, 0x13, 0xc4, 0x7c, 0x63, 0x57, 0xec, 0x77, 0xea, 0xa0, 0x5d, 0x2a, 0xa2, 0x1f,
+	0x60, 0xcd, 0x13, 0x8e, 0xe4, 0x2c, 0x97, 0x64, 0xb6, 0x88, 0xc1, 0x4b, 0x8a, 0xf8, 0x4c, 0xd2,
+	0xae, 0x8a, 0x43, 0x85, 0x15, 0x29, 0x8b, 0x18, 0x75, 0xa0, 0xf5, 0x14, 0x53, 0xe5, 0x08, 0x2d,
+	0xfa, 0x1e, 0xda, 0xf6, 0xfa, 0x3f, 0xb9, 0x7b, 0x04, 0xeb, 0xc3, 0x71, 0xa1, 0x32, 0x76, 0x9a,
+	0x7b, 0x0e, 0xdd, 0x84, 0x55, 0x49, 0x47, 0x39, 0x9e, 0x38, 0x1a, 0x75, 0x37, 0xf4, 0x16, 0xb4,
+	0x47, 0x02, 0xa7, 0x24, 0xe1, 0x44, 0x50, 0x96, 0x85, 0x4b, 0xbd, 0xa0, 0x5f, 0x8b, 0x5b, 0x06,
+	0x3b, 0x30, 0x50, 0x84, 0x60, 0xe3, 0xdc, 0x9a, 0x8d, 0x38, 0x1a, 0xc3, 0xe6, 0xd7, 0x3c, 0xd3,
+	0x4e, 0x4b, 0xea, 0x74, 0x8e, 0xe6, 0x68, 0x38, 0xf8, 0xcf, 0x34, 0x1c, 0x5d, 0x81, 0xcb, 0xcf,
+	0x79, 0x72, 0x41, 0x6c, 0xc0, 0xda, 0x37, 0x44, 0x48, 0xca, 0x7c, 0x96, 0xd1, 0x7b, 0xb0, 0x5e,
+	0x22, 0xae, 0xb6, 0x21, 0xd4, 0x4f, 0x2c, 0xe4, 0x32, 0xf7, 0xd7, 0xe8, 0x5d, 0x68, 0xeb, 0xba,
+	0x95, 0x91, 0x77, 0xa1, 0x41, 0x73, 0x45, 0xc4, 0x89, 0x2b, 0x52, 0x2d, 0x2e, 0xef, 0xd1, 0x53,
+	0xe8, 0x38, 0x59, 0x67, 0xf6, 0x21, 0xac, 0x48, 0x0d, 0x2c, 0x98, 0xe2, 0x13, 0x2c, 0x8f, 0xad,
+	0x21, 0xab, 0x1e, 0x5d, 0x87, 0xce, 0xd0, 0x74, 0xe2, 0xc5, 0x8d, 0x5a, 0xf1, 0x8d, 0xd2, 0xc9,
+	0x7a, 0x41, 0x97, 0xfe, 0x31, 0xb4, 0x1e, 0x9c, 0x91, 0xd4, 0x2b, 0xde, 0x85, 0x46, 0x46, 0x70,
+	0x36, 0xa1, 0x39, 0x71, 0x41, 0x75, 0x07, 0x76, 0x1f, 0x0f, 0xfc, 0x3e, 0x1e, 0x3c, 0xf1, 0xfb,
+	0x38, 0x2e, 0x65, 0xfd, 0x76, 0x5d, 0x7a, 0x7e, 0xbb, 0xd6, 0xce, 0xb7, 0x6b, 0xf4, 0x1d, 0xb4,
+	0xad, 0x33, 0x97, 0xff, 0x26, 0xac, 0xb2, 0x42, 0xf1, 0x42, 0x19, 0x5f, 0xed, 0xd8, 0xdd, 0xf4,
+	0xc7, 0x4b, 0xce, 0xa8, 0x4a, 0x52, 0xcd, 0x84, 0x4b, 0x26, 0x83, 0x86, 0x06, 0x76, 0x34, 0x07,
+	0xea, 0xdc, 0xcc, 0x7a, 0x34, 0xfb, 0xb9, 0x1d, 0xbb, 0x5b, 0xf4, 0x57, 0x00, 0xed, 0xd9, 0x49,
+	0xd6, 0x31, 0x71, 0x9a, 0xb9, 0x0a, 0xe8, 0xe3, 0xcb, 0xed, 0xda, 0x9a, 0xd5, 0x66, 0x6b, 0x86,
+	0x06, 0xb0, 0xac, 0xff, 0x40, 0xc2, 0xe5, 0x97, 0x96, 0xc3, 0xc8, 0x69, 0xfa, 0x65, 0x6c, 0x9a,
+	0x1c, 0xd3, 0xc9, 0x84, 0x64, 0x66, 0xa1, 0x37, 0xe2, 0x26, 0x63, 0xd3, 0x2f, 0x0d, 0x10, 0x3d,
+	0x81, 0xba, 0xe3, 0x34, 0xb4, 0x07, 0x2b, 0x7a, 0xe9, 0xeb, 0xf6, 0xeb, 0xb5, 0x77, 0x6b, 0x41,
+	0x4e, 0xd4, 0xbf, 0x07, 0xb1, 0xb5, 0x10, 0xdd, 0x81, 0xd6, 0x0c, 0xaa, 0x8b, 0xaf, 0x71, 0x37,
+	0xac, 0xcb, 0xdc, 0x61, 0x53, 0x9f, 0x77, 0x33, 0x36, 0xe7, 0xe8, 0x1a, 0x5c, 0xd8, 0x19, 0x93,
+	0xf4, 0x98, 0x33, 0x9a, 0xab, 0x99, 0x3f, 0x25, 0x4d, 0x71, 0xee, 0x4f, 0x29, 0xa3, 0x22, 0xba,
+	0x08, 0x68, 0x56, 0xcc, 0x76, 0xef, 0xe6, 0x9f, 0x00, 0x8d, 0x07, 0x2e, 0x32, 0xf4, 0x13, 0xac,
+	0x5a, 0xfa, 0x43, 0x77, 0xaa, 0xa6, 0x31, 0xf7, 0x7f, 0xd6, 0xbd, 0xbb, 0xa8, 0x9a, 0x1b, 0xe0,
+	0xd7, 0x90, 0x84, 0x65, 0x4d, 0x84, 0xa8, 0x72, 0xfd, 0x66, 0x58, 0xb4, 0x7b, 0x7b, 0x31, 0xa5,
+	0xd2, 0xe9, 0xaf, 0xd0, 0xf0, 0x7c, 0x86, 0xee, 0x55, 0x6e, 0xdc, 0x3c, 0x9f, 0x76, 0x3f, 0x5c,
+	0x5c, 0xb1, 0x0c, 0xe0, 0xf7, 0x00, 0xd6, 0x9f, 0xe1, 0x34, 0xf4, 0x69, 0x55, 0x7b, 0x2f, 0xa6,
+	0xdd, 0xee, 0xfd, 0x57, 0xd6, 0x2f, 0xc3, 0xfa, 0x05, 0xea, 0x8e, 0x3c, 0x51, 0xe5, 0x8e, 0xce,
+	0xf3, 0x6f, 0xf7, 0xde, 0xc2, 0x7a, 0xa5, 0xf7, 0x33, 0x58, 0x31, 0xc4, 0x88, 0x2a, 0xb7, 0x75,
+	0x96, 0xbc, 0xbb, 0x77, 0x16, 0xd4, 0xf2, 0x7e, 0xb7, 0x03, 0x3d, 0xff, 0x96, 0x59, 0xab, 0xcf,
+	0xff, 0x1c, 0x65, 0x77, 0xef, 0x2e, 0xaa, 0x36, 0x3b, 0xff, 0xfa, 0x33, 0xac, 0x3e, 0xff, 0x33,
+	0x84, 0xdf, 0xbd, 0xbd, 0x98, 0x52, 0xe9, 0xf4, 0x8f, 0x00, 0x3a, 0x1a, 0x1a, 0x2a, 0x41, 0xf0,
+	0x94, 0xe6, 0x23, 0x74, 0xbf, 0xe2, 0xf6, 0xd2, 0x5a, 0x76, 0x83, 0x39, 0x4d, 0x1f, 0xca, 0x67,
+	0xaf, 0x6e, 0xc0, 0x87, 0xd5, 0x0f, 0xb6, 0x03, 0xf4, 0x5b, 0x00, 0x70, 0x4e, 0x57, 0xe8, 0xa3,
+	0xaa, 0x19, 0x3e, 0xc7, 0x84, 0xdd, 0x8f, 0x5f, 0x45, 0xd5, 0xc7, 0xf2, 0x79, 0xfd, 0xdb, 0x15,
+	0xbb, 0x24, 0x56, 0xcd, 0xe3, 0xd6, 0x3f, 0x03, 0x00, 0x86, 0x8a, 0x09, 0x2f, 0x30, 0x0f, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -966,6 +1205,7 @@ type ExecutorClient interface {
 	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*ExecResponse, error)
 	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
 	ExecStreaming(ctx context.Context, opts ...grpc.CallOption) (Executor_ExecStreamingClient, error)
+	Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (*CheckpointResponse, error)
 }
 
 type executorClient struct {
@@ -1102,6 +1342,15 @@ func (x *executorExecStreamingClient) Recv() (*proto1.ExecTaskStreamingResponse,
 	return m, nil
 }
 
+func (c *executorClient) Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (*CheckpointResponse, error) {
+	out := new(CheckpointResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.executor.proto.Executor/Checkpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ExecutorServer is the server API for Executor service.
 type ExecutorServer interface {
 	Launch(context.Context, *LaunchRequest) (*LaunchResponse, error)
@@ -1114,6 +1363,7 @@ type ExecutorServer interface {
 	Exec(context.Context, *ExecRequest) (*ExecResponse, error)
 	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
 	ExecStreaming(Executor_ExecStreamingServer) error
+	Checkpoint(context.Context, *CheckpointRequest) (*CheckpointResponse, error)
 }
 
 // UnimplementedExecutorServer can be embedded to have forward compatible implementations.
@@ -1147,6 +1397,9 @@ func (*UnimplementedExecutorServer) Exec(ctx context.Context, req *ExecRequest)
 func (*UnimplementedExecutorServer) ExecStreaming(srv Executor_ExecStreamingServer) error {
 	return status.Errorf(codes.Unimplemented, "method ExecStreaming not implemented")
 }
+func (*UnimplementedExecutorServer) Checkpoint(ctx context.Context, req *CheckpointRequest) (*CheckpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Checkpoint not implemented")
+}
 
 func RegisterExecutorServer(s *grpc.Server, srv ExecutorServer) {
 	s.RegisterService(&_Executor_serviceDesc, srv)
@@ -1325,6 +1578,24 @@ func (x *executorExecStreamingServer) Recv() (*proto1.ExecTaskStreamingRequest,
 	return m, nil
 }
 
+func _Executor_Checkpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).Checkpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.executor.proto.Executor/Checkpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).Checkpoint(ctx, req.(*CheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Executor_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hashicorp.nomad.plugins.executor.proto.Executor",
 	HandlerType: (*ExecutorServer)(nil),
@@ -1357,6 +1628,10 @@ var _Executor_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Exec",
 			Handler:    _Executor_Exec_Handler,
 		},
+		{
+			MethodName: "Checkpoint",
+			Handler:    _Executor_Checkpoint_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
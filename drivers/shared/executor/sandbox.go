@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/drivers/shared/executor/proto"
+)
+
+// sandboxShimArg is the argument the executor binary is re-executed with to
+// apply the sandbox before running the task command.
+const sandboxShimArg = "sandbox-shim"
+
+// SandboxConfig restricts the filesystem access of a process started by the
+// universal executor to a set of paths, in the spirit of OpenBSD's unveil.
+// Access to any path not listed is denied.
+type SandboxConfig struct {
+	Paths []*SandboxPath
+}
+
+// SandboxPath grants access to a path and everything beneath it.
+type SandboxPath struct {
+	// Path is the absolute host path to grant access to.
+	Path string
+
+	// Mode is a combination of the letters r (read), w (write), x (execute)
+	// and c (create and remove files).
+	Mode string
+}
+
+// Validate returns an error if the path isn't absolute or the mode contains
+// unknown permissions.
+func (p *SandboxPath) Validate() error {
+	if !filepath.IsAbs(p.Path) {
+		return fmt.Errorf("sandbox path %q must be absolute", p.Path)
+	}
+	if p.Mode == "" {
+		return fmt.Errorf("sandbox path %q must have a mode", p.Path)
+	}
+	for _, c := range p.Mode {
+		switch c {
+		case 'r', 'w', 'x', 'c':
+		default:
+			return fmt.Errorf("sandbox path %q has invalid mode %q: must be a combination of r, w, x and c", p.Path, p.Mode)
+		}
+	}
+	return nil
+}
+
+func sandboxToProto(s *SandboxConfig) *proto.Sandbox {
+	if s == nil {
+		return nil
+	}
+	pb := &proto.Sandbox{
+		Paths: make([]*proto.SandboxPath, len(s.Paths)),
+	}
+	for i, p := range s.Paths {
+		pb.Paths[i] = &proto.SandboxPath{Path: p.Path, Mode: p.Mode}
+	}
+	return pb
+}
+
+func sandboxFromProto(pb *proto.Sandbox) *SandboxConfig {
+	if pb == nil {
+		return nil
+	}
+	s := &SandboxConfig{
+		Paths: make([]*SandboxPath, len(pb.Paths)),
+	}
+	for i, p := range pb.Paths {
+		s.Paths[i] = &SandboxPath{Path: p.Path, Mode: p.Mode}
+	}
+	return s
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package executor
+
+import "errors"
+
+var errSandboxUnsupported = errors.New("sandbox isolation is only supported on Linux")
+
+// LandlockABI returns an error as Landlock is only available on Linux.
+func LandlockABI() (int, error) {
+	return 0, errSandboxUnsupported
+}
+
+func sandboxCommand(*SandboxConfig, string, []string) (string, []string, error) {
+	return "", nil, errSandboxUnsupported
+}
@@ -0,0 +1,293 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// landlockFileAccess are the access rights that apply to regular
+	// files. Rules for paths that aren't directories may only use these.
+	landlockFileAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE
+
+	// landlockCreateAccess are the access rights granted by the c mode.
+	landlockCreateAccess = unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+	// landlockHandledAccess are all the access rights of the first Landlock
+	// ABI, which are denied unless granted by a rule.
+	landlockHandledAccess = landlockFileAccess |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR |
+		landlockCreateAccess
+)
+
+// seccomp return actions, see seccomp(2).
+const (
+	seccompRetKillProcess = 0x80000000
+	seccompRetErrno       = 0x00050000
+	seccompRetAllow       = 0x7fff0000
+
+	// x32SyscallBit is set in the number of syscalls made with the x32 ABI
+	// on amd64, which could otherwise be used to bypass the filter.
+	x32SyscallBit = 0x40000000
+)
+
+// seccompAuditArch is the AUDIT_ARCH_* value of the architectures the
+// seccomp filter supports.
+var seccompAuditArch = map[string]uint32{
+	"386":   0x40000003,
+	"amd64": 0xc000003e,
+	"arm":   0x40000028,
+	"arm64": 0xc00000b7,
+}
+
+// sandboxDeniedSyscalls are the syscalls a sandboxed process may not use, as
+// they would allow it to escape the sandbox or to change the state of the
+// host. They fail with EPERM.
+var sandboxDeniedSyscalls = []uint32{
+	unix.SYS_ADD_KEY,
+	unix.SYS_BPF,
+	unix.SYS_DELETE_MODULE,
+	unix.SYS_FINIT_MODULE,
+	unix.SYS_INIT_MODULE,
+	unix.SYS_KEXEC_LOAD,
+	unix.SYS_KEYCTL,
+	unix.SYS_MOUNT,
+	unix.SYS_OPEN_BY_HANDLE_AT,
+	unix.SYS_PERF_EVENT_OPEN,
+	unix.SYS_PIVOT_ROOT,
+	unix.SYS_PTRACE,
+	unix.SYS_REBOOT,
+	unix.SYS_REQUEST_KEY,
+	unix.SYS_SETNS,
+	unix.SYS_SWAPOFF,
+	unix.SYS_SWAPON,
+	unix.SYS_UMOUNT2,
+	unix.SYS_UNSHARE,
+}
+
+// init is used when the UniversalExecutor starts a sandboxed process. The
+// executor binary is re-executed with the sandbox-shim argument, restricts
+// itself with Landlock and seccomp and then execve's into the user process,
+// which inherits the restrictions.
+func init() {
+	if len(os.Args) > 1 && os.Args[1] == sandboxShimArg {
+		// Landlock and seccomp apply to the calling thread only, so the
+		// restrictions must be set up on the thread that calls execve.
+		runtime.LockOSThread()
+		if err := sandboxShim(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to start sandboxed process: %v\n", err)
+			os.Exit(1)
+		}
+		panic("--this line should have never been executed, congratulations--")
+	}
+}
+
+// sandboxCommand returns the command and arguments that run path with args
+// within the sandbox.
+func sandboxCommand(cfg *SandboxConfig, path string, args []string) (string, []string, error) {
+	encoded, err := json.Marshal(cfg)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode sandbox config: %v", err)
+	}
+	shimArgs := append([]string{sandboxShimArg, string(encoded), "--", path}, args...)
+	return "/proc/self/exe", shimArgs, nil
+}
+
+// sandboxShim parses the arguments built by sandboxCommand, applies the
+// sandbox and executes the command.
+func sandboxShim(args []string) error {
+	if len(args) < 3 || args[1] != "--" {
+		return errors.New("invalid arguments")
+	}
+
+	var cfg SandboxConfig
+	if err := json.Unmarshal([]byte(args[0]), &cfg); err != nil {
+		return fmt.Errorf("invalid sandbox config: %v", err)
+	}
+
+	// Resolve the command before the sandbox may deny reading PATH entries.
+	path, err := exec.LookPath(args[2])
+	if err != nil {
+		return err
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %v", err)
+	}
+	if err := applyLandlock(cfg.Paths); err != nil {
+		return err
+	}
+	if err := applySeccomp(); err != nil {
+		return err
+	}
+
+	return syscall.Exec(path, args[2:], os.Environ())
+}
+
+// LandlockABI returns the Landlock ABI version supported by the kernel, or an
+// error if Landlock is unavailable.
+func LandlockABI() (int, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		switch errno {
+		case unix.ENOSYS:
+			return 0, errors.New("landlock is not supported by the kernel")
+		case unix.EOPNOTSUPP:
+			return 0, errors.New("landlock is disabled")
+		default:
+			return 0, fmt.Errorf("failed to detect landlock: %v", errno)
+		}
+	}
+	return int(abi), nil
+}
+
+// landlockAccess converts a sandbox mode to Landlock access rights.
+func landlockAccess(mode string) uint64 {
+	var access uint64
+	for _, c := range mode {
+		switch c {
+		case 'r':
+			access |= unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR
+		case 'w':
+			access |= unix.LANDLOCK_ACCESS_FS_WRITE_FILE
+		case 'x':
+			access |= unix.LANDLOCK_ACCESS_FS_EXECUTE
+		case 'c':
+			access |= landlockCreateAccess
+		}
+	}
+	return access
+}
+
+// applyLandlock restricts the filesystem access of the calling thread to the
+// given paths. Paths that don't exist are skipped.
+func applyLandlock(paths []*SandboxPath) error {
+	if _, err := LandlockABI(); err != nil {
+		return err
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: landlockHandledAccess}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create landlock ruleset: %v", errno)
+	}
+	defer unix.Close(int(fd))
+
+	for _, p := range paths {
+		if err := addLandlockRule(int(fd), p); err != nil {
+			return err
+		}
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to apply landlock ruleset: %v", errno)
+	}
+	return nil
+}
+
+func addLandlockRule(rulesetFd int, p *SandboxPath) error {
+	fd, err := unix.Open(p.Path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to open sandbox path %q: %v", p.Path, err)
+	}
+	defer unix.Close(fd)
+
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return fmt.Errorf("failed to stat sandbox path %q: %v", p.Path, err)
+	}
+
+	access := landlockAccess(p.Mode)
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFileAccess
+	}
+	if access == 0 {
+		return nil
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFd),
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to add sandbox path %q: %v", p.Path, errno)
+	}
+	return nil
+}
+
+// seccompFilter builds a BPF program that fails the denied syscalls with
+// EPERM, allows all others and kills the process if a syscall is made with
+// an unexpected architecture.
+func seccompFilter(arch uint32, denied []uint32) []unix.SockFilter {
+	const (
+		offsetNr   = 0
+		offsetArch = 4
+	)
+	stmt := func(code uint16, k uint32) unix.SockFilter {
+		return unix.SockFilter{Code: code, K: k}
+	}
+	jump := func(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+		return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+	}
+
+	filter := []unix.SockFilter{
+		stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, offsetArch),
+		jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, arch, 1, 0),
+		stmt(unix.BPF_RET|unix.BPF_K, seccompRetKillProcess),
+		stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, offsetNr),
+	}
+
+	// Each check jumps over the remaining checks and the allow action to the
+	// errno action on a match.
+	checks := denied
+	if arch == seccompAuditArch["amd64"] {
+		filter = append(filter, jump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, x32SyscallBit, uint8(len(checks)+1), 0))
+	}
+	for i, nr := range checks {
+		filter = append(filter, jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, nr, uint8(len(checks)-i), 0))
+	}
+
+	return append(filter,
+		stmt(unix.BPF_RET|unix.BPF_K, seccompRetAllow),
+		stmt(unix.BPF_RET|unix.BPF_K, seccompRetErrno|uint32(unix.EPERM)),
+	)
+}
+
+// applySeccomp installs the seccomp filter on the calling thread. It requires
+// no_new_privs to be set.
+func applySeccomp() error {
+	arch, ok := seccompAuditArch[runtime.GOARCH]
+	if !ok {
+		return fmt.Errorf("seccomp filtering is not supported on %s", runtime.GOARCH)
+	}
+
+	filter := seccompFilter(arch, sandboxDeniedSyscalls)
+	prog := unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+	if err := unix.Prctl(unix.PR_SET_SECCOMP, unix.SECCOMP_MODE_FILTER, uintptr(unsafe.Pointer(&prog)), 0, 0); err != nil {
+		return fmt.Errorf("failed to install seccomp filter: %v", err)
+	}
+	return nil
+}
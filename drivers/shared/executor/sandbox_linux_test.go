@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestSandboxPath_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	require.NoError(t, (&SandboxPath{Path: "/usr", Mode: "rx"}).Validate())
+	require.NoError(t, (&SandboxPath{Path: "/tmp", Mode: "rwxc"}).Validate())
+	require.Error(t, (&SandboxPath{Path: "usr", Mode: "rx"}).Validate())
+	require.Error(t, (&SandboxPath{Path: "/usr", Mode: ""}).Validate())
+	require.Error(t, (&SandboxPath{Path: "/usr", Mode: "rz"}).Validate())
+}
+
+func TestSandbox_landlockAccess(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Equal(t, uint64(unix.LANDLOCK_ACCESS_FS_READ_FILE|unix.LANDLOCK_ACCESS_FS_READ_DIR|unix.LANDLOCK_ACCESS_FS_EXECUTE),
+		landlockAccess("rx"))
+	require.Equal(t, uint64(unix.LANDLOCK_ACCESS_FS_WRITE_FILE), landlockAccess("w"))
+	require.Equal(t, uint64(landlockHandledAccess), landlockAccess("rwxc"))
+}
+
+func TestSandbox_seccompFilter(t *testing.T) {
+	ci.Parallel(t)
+
+	denied := []uint32{1, 2, 3}
+	filter := seccompFilter(seccompAuditArch["arm64"], denied)
+	require.Len(t, filter, 4+len(denied)+2)
+
+	// every syscall check must jump to the errno action
+	errnoIdx := len(filter) - 1
+	require.Equal(t, uint32(seccompRetErrno|uint32(unix.EPERM)), filter[errnoIdx].K)
+	for i := 4; i < 4+len(denied); i++ {
+		require.Equal(t, denied[i-4], filter[i].K)
+		require.Equal(t, errnoIdx, i+1+int(filter[i].Jt))
+	}
+
+	// amd64 also rejects x32 syscalls
+	filter = seccompFilter(seccompAuditArch["amd64"], denied)
+	require.Len(t, filter, 4+1+len(denied)+2)
+	require.Equal(t, uint32(x32SyscallBit), filter[4].K)
+	require.Equal(t, len(filter)-1, 4+1+int(filter[4].Jt))
+}
+
+func TestUniversalExecutor_Sandbox(t *testing.T) {
+	ci.Parallel(t)
+	if _, err := LandlockABI(); err != nil {
+		t.Skipf("landlock not available: %v", err)
+	}
+
+	testExecCmd := testExecutorCommand(t)
+	execCmd, allocDir := testExecCmd.command, testExecCmd.allocDir
+	defer allocDir.Destroy()
+
+	execCmd.Cmd = "/bin/sh"
+	execCmd.Args = []string{"-c", "echo ok > out.txt && cat out.txt && cat /etc/hostname"}
+	execCmd.Sandbox = &SandboxConfig{
+		Paths: []*SandboxPath{
+			{Path: "/bin", Mode: "rx"},
+			{Path: "/usr", Mode: "rx"},
+			{Path: "/lib", Mode: "rx"},
+			{Path: "/lib64", Mode: "rx"},
+			{Path: execCmd.TaskDir, Mode: "rwc"},
+		},
+	}
+
+	executor := NewExecutor(testlog.HCLogger(t))
+	defer executor.Shutdown("SIGKILL", 0)
+
+	_, err := executor.Launch(execCmd)
+	require.NoError(t, err)
+
+	ps, err := executor.Wait(context.Background())
+	require.NoError(t, err)
+	require.NotZero(t, ps.ExitCode)
+
+	require.Eventually(t, func() bool {
+		return strings.TrimSpace(testExecCmd.stdout.String()) == "ok" &&
+			strings.Contains(testExecCmd.stderr.String(), "Permission denied")
+	}, 5*time.Second, 50*time.Millisecond, "stdout: %s stderr: %s",
+		testExecCmd.stdout.String(), testExecCmd.stderr.String())
+}
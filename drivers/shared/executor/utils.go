@@ -104,10 +104,11 @@ func processStateToProto(ps *ProcessState) (*proto.ProcessState, error) {
 		return nil, err
 	}
 	pb := &proto.ProcessState{
-		Pid:      int32(ps.Pid),
-		ExitCode: int32(ps.ExitCode),
-		Signal:   int32(ps.Signal),
-		Time:     timestamp,
+		Pid:       int32(ps.Pid),
+		ExitCode:  int32(ps.ExitCode),
+		Signal:    int32(ps.Signal),
+		Time:      timestamp,
+		OomKilled: ps.OOMKilled,
 	}
 
 	return pb, nil
@@ -120,10 +121,11 @@ func processStateFromProto(pb *proto.ProcessState) (*ProcessState, error) {
 	}
 
 	return &ProcessState{
-		Pid:      int(pb.Pid),
-		ExitCode: int(pb.ExitCode),
-		Signal:   int(pb.Signal),
-		Time:     timestamp,
+		Pid:       int(pb.Pid),
+		ExitCode:  int(pb.ExitCode),
+		Signal:    int(pb.Signal),
+		Time:      timestamp,
+		OOMKilled: pb.OomKilled,
 	}, nil
 }
 
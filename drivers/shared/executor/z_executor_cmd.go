@@ -2,6 +2,7 @@ package executor
 
 import (
 	"encoding/json"
+	"io"
 	"os"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -28,7 +29,14 @@ func init() {
 			os.Exit(1)
 		}
 
-		f, err := os.OpenFile(executorConfig.LogFile, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0666)
+		var f io.Writer
+		var err error
+		if executorConfig.LogMaxFileSizeMB > 0 {
+			f, err = newRotatingFile(executorConfig.LogFile,
+				int64(executorConfig.LogMaxFileSizeMB)*1024*1024, executorConfig.LogMaxFiles)
+		} else {
+			f, err = os.OpenFile(executorConfig.LogFile, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0666)
+		}
 		if err != nil {
 			hclog.L().Error(err.Error())
 			os.Exit(1)
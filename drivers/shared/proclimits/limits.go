@@ -0,0 +1,106 @@
+package proclimits
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+// Spec is the hcl specification of the process_limits block of a driver
+// plugin config.
+var Spec = hclspec.NewBlock("process_limits", false, hclspec.NewObject(map[string]*hclspec.Spec{
+	"min_nice": hclspec.NewDefault(
+		hclspec.NewAttr("min_nice", "number", false),
+		hclspec.NewLiteral("0"),
+	),
+	"allow_realtime_ionice": hclspec.NewDefault(
+		hclspec.NewAttr("allow_realtime_ionice", "bool", false),
+		hclspec.NewLiteral("false"),
+	),
+	"max_rlimits": hclspec.NewBlockAttrs("max_rlimits", "number", false),
+}))
+
+// Limits restricts the attributes the process block of a task may request.
+// The zero value only allows attributes that don't require privileges.
+type Limits struct {
+	// MinNice is the lowest nice value a task may request.
+	MinNice int `codec:"min_nice"`
+
+	// AllowRealtimeIOnice allows tasks to use the realtime I/O scheduling
+	// class.
+	AllowRealtimeIOnice bool `codec:"allow_realtime_ionice"`
+
+	// MaxRlimits are the highest values of resource limits a task may
+	// request above the hard limits of the driver.
+	MaxRlimits map[string]uint64 `codec:"max_rlimits"`
+}
+
+// Validate returns an error if the limits are invalid.
+func (l *Limits) Validate() error {
+	if l == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if l.MinNice < -20 || l.MinNice > 19 {
+		_ = multierror.Append(&mErr, fmt.Errorf("min_nice must be between -20 and 19, got %d", l.MinNice))
+	}
+	for name := range l.MaxRlimits {
+		if !knownRlimit(name) {
+			_ = multierror.Append(&mErr, fmt.Errorf("unknown rlimit %q in max_rlimits", name))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Check returns an error if the process config of a task requests a nice
+// value, I/O scheduling class or resource limit beyond the limits. A nil
+// Limits only allows attributes that don't require privileges.
+func (l *Limits) Check(process *drivers.ProcessConfig) error {
+	if process == nil {
+		return nil
+	}
+	if l == nil {
+		l = &Limits{}
+	}
+
+	var mErr multierror.Error
+	if process.Nice < l.MinNice {
+		_ = multierror.Append(&mErr, fmt.Errorf("nice %d is below the minimum of %d allowed by the driver", process.Nice, l.MinNice))
+	}
+	if process.IOniceClass == structs.TaskProcessIOniceClassRealtime && !l.AllowRealtimeIOnice {
+		_ = multierror.Append(&mErr, fmt.Errorf("ionice_class %q is not allowed by the driver", process.IOniceClass))
+	}
+	if err := l.CheckRlimits(process.Rlimits); err != nil {
+		_ = multierror.Append(&mErr, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CheckRlimits returns an error if a resource limit is above the hard limit
+// of the driver and above its maximum in MaxRlimits. Lowering a limit is
+// always allowed.
+func (l *Limits) CheckRlimits(rlimits map[string]uint64) error {
+	if l == nil {
+		l = &Limits{}
+	}
+
+	var mErr multierror.Error
+	for name, value := range rlimits {
+		hard, ok, err := HardLimit(name)
+		if err != nil {
+			_ = multierror.Append(&mErr, err)
+			continue
+		}
+		if !ok || value <= hard {
+			continue
+		}
+		if max, ok := l.MaxRlimits[name]; !ok || value > max {
+			_ = multierror.Append(&mErr, fmt.Errorf("rlimit %q of %d is above the hard limit of %d allowed by the driver", name, value, hard))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package proclimits
+
+// knownRlimit accepts any name as rlimits aren't applied on this platform.
+func knownRlimit(string) bool {
+	return true
+}
+
+// HardLimit always returns false as rlimits aren't applied on this platform.
+func HardLimit(string) (uint64, bool, error) {
+	return 0, false, nil
+}
@@ -0,0 +1,54 @@
+package proclimits
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// rlimitResources maps the rlimit names accepted in a task process block to
+// their resource constants.
+var rlimitResources = map[string]int{
+	"as":         unix.RLIMIT_AS,
+	"core":       unix.RLIMIT_CORE,
+	"cpu":        unix.RLIMIT_CPU,
+	"data":       unix.RLIMIT_DATA,
+	"fsize":      unix.RLIMIT_FSIZE,
+	"locks":      unix.RLIMIT_LOCKS,
+	"memlock":    unix.RLIMIT_MEMLOCK,
+	"msgqueue":   unix.RLIMIT_MSGQUEUE,
+	"nice":       unix.RLIMIT_NICE,
+	"nofile":     unix.RLIMIT_NOFILE,
+	"nproc":      unix.RLIMIT_NPROC,
+	"rss":        unix.RLIMIT_RSS,
+	"rtprio":     unix.RLIMIT_RTPRIO,
+	"rttime":     unix.RLIMIT_RTTIME,
+	"sigpending": unix.RLIMIT_SIGPENDING,
+	"stack":      unix.RLIMIT_STACK,
+}
+
+// RlimitResource returns the resource constant of the named rlimit.
+func RlimitResource(name string) (int, bool) {
+	resource, ok := rlimitResources[name]
+	return resource, ok
+}
+
+func knownRlimit(name string) bool {
+	_, ok := rlimitResources[name]
+	return ok
+}
+
+// HardLimit returns the hard limit of the named rlimit of the calling
+// process. Raising a limit above it requires privileges.
+func HardLimit(name string) (uint64, bool, error) {
+	resource, ok := rlimitResources[name]
+	if !ok {
+		return 0, false, fmt.Errorf("unknown rlimit %q", name)
+	}
+
+	var limit unix.Rlimit
+	if err := unix.Getrlimit(resource, &limit); err != nil {
+		return 0, false, fmt.Errorf("failed to get rlimit %q: %v", name, err)
+	}
+	return limit.Max, true, nil
+}
@@ -0,0 +1,90 @@
+package proclimits
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits_Spec(t *testing.T) {
+	ci.Parallel(t)
+
+	spec := hclspec.NewObject(map[string]*hclspec.Spec{
+		"process_limits": Spec,
+	})
+	type config struct {
+		ProcessLimits *Limits `codec:"process_limits"`
+	}
+
+	var c config
+	hclutils.NewConfigParser(spec).ParseHCL(t, `config {}`, &c)
+	require.Nil(t, c.ProcessLimits)
+
+	c = config{}
+	hclutils.NewConfigParser(spec).ParseHCL(t, `config {
+  process_limits {
+    min_nice              = -5
+    allow_realtime_ionice = true
+    max_rlimits {
+      nofile = 65536
+    }
+  }
+}`, &c)
+	require.Equal(t, &Limits{
+		MinNice:             -5,
+		AllowRealtimeIOnice: true,
+		MaxRlimits:          map[string]uint64{"nofile": 65536},
+	}, c.ProcessLimits)
+}
+
+func TestLimits_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	var l *Limits
+	require.NoError(t, l.Validate())
+	require.NoError(t, (&Limits{MinNice: -20}).Validate())
+	require.Error(t, (&Limits{MinNice: -21}).Validate())
+	require.Error(t, (&Limits{MaxRlimits: map[string]uint64{"bogus": 1}}).Validate())
+}
+
+func TestLimits_Check(t *testing.T) {
+	ci.Parallel(t)
+
+	// Without limits only unprivileged attributes are allowed
+	var l *Limits
+	require.NoError(t, l.Check(nil))
+	require.NoError(t, l.Check(&drivers.ProcessConfig{
+		Umask:       "027",
+		Nice:        10,
+		IOniceClass: structs.TaskProcessIOniceClassIdle,
+	}))
+	require.Error(t, l.Check(&drivers.ProcessConfig{Nice: -1}))
+	require.Error(t, l.Check(&drivers.ProcessConfig{IOniceClass: structs.TaskProcessIOniceClassRealtime}))
+
+	l = &Limits{MinNice: -5, AllowRealtimeIOnice: true}
+	require.NoError(t, l.Check(&drivers.ProcessConfig{
+		Nice:        -5,
+		IOniceClass: structs.TaskProcessIOniceClassRealtime,
+	}))
+	require.Error(t, l.Check(&drivers.ProcessConfig{Nice: -6}))
+
+	// Lowering a limit is always allowed, raising it above the hard limit
+	// requires a maximum
+	hard, ok, err := HardLimit("nofile")
+	require.NoError(t, err)
+	if !ok || hard == math.MaxUint64 {
+		t.Skip("nofile hard limit can't be raised")
+	}
+	require.NoError(t, l.CheckRlimits(map[string]uint64{"nofile": hard}))
+	require.Error(t, l.CheckRlimits(map[string]uint64{"nofile": hard + 1}))
+
+	l.MaxRlimits = map[string]uint64{"nofile": hard + 1}
+	require.NoError(t, l.CheckRlimits(map[string]uint64{"nofile": hard + 1}))
+	require.Error(t, l.CheckRlimits(map[string]uint64{"nofile": hard + 2}))
+}
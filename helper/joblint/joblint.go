@@ -0,0 +1,97 @@
+// Package joblint implements the rules of "nomad job lint", which check jobs
+// for practices that are valid but unwise, such as deploying the latest tag
+// of an image. Organizations can add their own rules as plugins.
+package joblint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+// Severity is the severity of a finding.
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// level returns the order of the severity, or -1 if it is unknown.
+func (s Severity) level() int {
+	switch s {
+	case SeverityInfo:
+		return 0
+	case SeverityWarning:
+		return 1
+	case SeverityError:
+		return 2
+	}
+	return -1
+}
+
+// AtLeast returns whether s is at least as severe as min.
+func (s Severity) AtLeast(min Severity) bool {
+	return s.level() >= min.level()
+}
+
+// ParseSeverity parses a severity.
+func ParseSeverity(s string) (Severity, error) {
+	if sev := Severity(s); sev.level() >= 0 {
+		return sev, nil
+	}
+	return "", fmt.Errorf("unknown severity %q, must be one of info, warning or error", s)
+}
+
+// Finding is a problem found by a rule in a job. Group and Task are set if
+// the finding is about a group or a task.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Group    string
+	Task     string
+	Message  string
+}
+
+// Rule checks jobs for a problem.
+type Rule interface {
+	// Name is the name of the rule, used to disable it.
+	Name() string
+
+	// Check returns the findings of the rule in job, which is not
+	// canonicalized so its fields may be nil.
+	Check(job *api.Job) ([]*Finding, error)
+}
+
+// Lint checks job with rules, and returns the findings by decreasing
+// severity. Findings which don't set their rule or their severity are
+// attributed to the rule that returned them, with a warning severity.
+func Lint(job *api.Job, rules []Rule) ([]*Finding, error) {
+	var findings []*Finding
+	for _, rule := range rules {
+		ruleFindings, err := rule.Check(job)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q failed: %w", rule.Name(), err)
+		}
+
+		for _, f := range ruleFindings {
+			if f.Rule == "" {
+				f.Rule = rule.Name()
+			}
+			if f.Severity == "" {
+				f.Severity = SeverityWarning
+			}
+			if f.Severity.level() < 0 {
+				return nil, fmt.Errorf("rule %q returned unknown severity %q", rule.Name(), f.Severity)
+			}
+		}
+		findings = append(findings, ruleFindings...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity.level() > findings[j].Severity.level()
+	})
+	return findings, nil
+}
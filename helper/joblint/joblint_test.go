@@ -0,0 +1,149 @@
+package joblint
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+// testJob returns a service job which passes the built-in rules.
+func testJob() *api.Job {
+	return &api.Job{
+		ID:     helper.StringToPtr("example"),
+		Update: &api.UpdateStrategy{MaxParallel: helper.IntToPtr(1)},
+		TaskGroups: []*api.TaskGroup{{
+			Name: helper.StringToPtr("web"),
+			Services: []*api.Service{{
+				Name:   "web",
+				Checks: []api.ServiceCheck{{Type: "http", Path: "/health"}},
+			}},
+			Tasks: []*api.Task{{
+				Name:   "server",
+				Driver: "docker",
+				Config: map[string]interface{}{"image": "registry.local:5000/web:1.2.3"},
+				Resources: &api.Resources{
+					CPU:      helper.IntToPtr(500),
+					MemoryMB: helper.IntToPtr(256),
+				},
+			}},
+		}},
+	}
+}
+
+func TestLint_BuiltinRules(t *testing.T) {
+	ci.Parallel(t)
+
+	findings, err := Lint(testJob(), BuiltinRules())
+	require.NoError(t, err)
+	require.Empty(t, findings)
+
+	job := testJob()
+	job.Update = nil
+	job.TaskGroups[0].Services = nil
+	job.TaskGroups[0].Tasks[0].Config["image"] = "registry.local:5000/web"
+	job.TaskGroups[0].Tasks[0].Resources = &api.Resources{CPU: helper.IntToPtr(500)}
+
+	findings, err = Lint(job, BuiltinRules())
+	require.NoError(t, err)
+	require.Equal(t, []*Finding{
+		{
+			Rule:     "image-tag",
+			Severity: SeverityError,
+			Group:    "web",
+			Task:     "server",
+			Message:  `Image "registry.local:5000/web" uses the latest tag, pin a version or a digest instead`,
+		},
+		{
+			Rule:     "update-stanza",
+			Severity: SeverityWarning,
+			Group:    "web",
+			Message:  "Service group has no update stanza, so all allocations are replaced at once on updates",
+		},
+		{
+			Rule:     "health-checks",
+			Severity: SeverityWarning,
+			Group:    "web",
+			Message:  "Service group has no health checks, so allocations are healthy as soon as their tasks run",
+		},
+		{
+			Rule:     "resources",
+			Severity: SeverityWarning,
+			Group:    "web",
+			Task:     "server",
+			Message:  "Task has no memory resources, so it uses the defaults",
+		},
+	}, findings)
+
+	// Batch jobs are not updated nor health checked
+	job.Type = helper.StringToPtr(api.JobTypeBatch)
+	findings, err = Lint(job, BuiltinRules())
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+}
+
+func TestImageTag(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := map[string]string{
+		"redis":                             "",
+		"redis:latest":                      "latest",
+		"redis:7":                           "7",
+		"localhost:5000/redis":              "",
+		"localhost:5000/library/redis:7":    "7",
+		"redis@sha256:0123456789abcdef0123": "@",
+	}
+	for image, tag := range cases {
+		require.Equal(t, tag, imageTag(image), image)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	ci.Parallel(t)
+
+	require.True(t, SeverityError.AtLeast(SeverityWarning))
+	require.True(t, SeverityWarning.AtLeast(SeverityWarning))
+	require.False(t, SeverityInfo.AtLeast(SeverityWarning))
+
+	_, err := ParseSeverity("fatal")
+	require.Error(t, err)
+}
+
+func TestPluginRule(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin script requires a shell")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owner.sh")
+	script := `#!/bin/sh
+if grep -q '"owner"'; then
+  echo '[]'
+else
+  echo '[{"Severity": "error", "Message": "Job has no owner meta"}]'
+fi
+`
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+
+	rule := NewPluginRule(path)
+	require.Equal(t, "owner", rule.Name())
+
+	findings, err := Lint(testJob(), []Rule{rule})
+	require.NoError(t, err)
+	require.Equal(t, []*Finding{{
+		Rule:     "owner",
+		Severity: SeverityError,
+		Message:  "Job has no owner meta",
+	}}, findings)
+
+	// Plugins failing fail linting
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho broken >&2\nexit 1\n"), 0755))
+	_, err = Lint(testJob(), []Rule{rule})
+	require.ErrorContains(t, err, "broken")
+}
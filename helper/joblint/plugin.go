@@ -0,0 +1,67 @@
+package joblint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+// pluginTimeout is the time a plugin has to check a job.
+const pluginTimeout = 30 * time.Second
+
+// pluginRule is a rule implemented by an external program, to add the rules
+// of an organization without building Nomad. The program is given the job
+// encoded as JSON on stdin, and must print the JSON array of its findings on
+// stdout and exit with 0, whether or not it found problems. A non-zero exit
+// code fails linting with the output of stderr.
+type pluginRule struct {
+	name string
+	path string
+}
+
+// NewPluginRule returns the rule implemented by the program at path, named
+// after the name of the program without its extension.
+func NewPluginRule(path string) Rule {
+	name := filepath.Base(path)
+	return &pluginRule{
+		name: strings.TrimSuffix(name, filepath.Ext(name)),
+		path: path,
+	}
+}
+
+func (r *pluginRule) Name() string { return r.name }
+
+func (r *pluginRule) Check(job *api.Job) ([]*Finding, error) {
+	input, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	var findings []*Finding
+	if err := json.Unmarshal(stdout.Bytes(), &findings); err != nil {
+		return nil, fmt.Errorf("failed to decode findings: %w", err)
+	}
+	return findings, nil
+}
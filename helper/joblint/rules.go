@@ -0,0 +1,181 @@
+package joblint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+// BuiltinRules returns the rules of "nomad job lint".
+func BuiltinRules() []Rule {
+	return []Rule{
+		&updateRule{},
+		&healthCheckRule{},
+		&imageTagRule{},
+		&resourcesRule{},
+	}
+}
+
+// isService returns whether job is a service job, which is the default type.
+func isService(job *api.Job) bool {
+	return job.Type == nil || *job.Type == "" || *job.Type == api.JobTypeService
+}
+
+// updateRule finds the groups of service jobs without an update stanza,
+// which are updated all at once without health checking the allocations.
+type updateRule struct{}
+
+func (r *updateRule) Name() string { return "update-stanza" }
+
+func (r *updateRule) Check(job *api.Job) ([]*Finding, error) {
+	if !isService(job) || job.Update != nil {
+		return nil, nil
+	}
+
+	var findings []*Finding
+	for _, tg := range job.TaskGroups {
+		if tg.Update != nil {
+			continue
+		}
+		findings = append(findings, &Finding{
+			Severity: SeverityWarning,
+			Group:    stringValue(tg.Name),
+			Message:  "Service group has no update stanza, so all allocations are replaced at once on updates",
+		})
+	}
+	return findings, nil
+}
+
+// healthCheckRule finds the groups of service jobs without a health check,
+// whose deployments can't tell healthy allocations apart.
+type healthCheckRule struct{}
+
+func (r *healthCheckRule) Name() string { return "health-checks" }
+
+func (r *healthCheckRule) Check(job *api.Job) ([]*Finding, error) {
+	if !isService(job) {
+		return nil, nil
+	}
+
+	var findings []*Finding
+	for _, tg := range job.TaskGroups {
+		if hasChecks(tg.Services) {
+			continue
+		}
+
+		checked := false
+		for _, task := range tg.Tasks {
+			if hasChecks(task.Services) {
+				checked = true
+				break
+			}
+		}
+		if !checked {
+			findings = append(findings, &Finding{
+				Severity: SeverityWarning,
+				Group:    stringValue(tg.Name),
+				Message:  "Service group has no health checks, so allocations are healthy as soon as their tasks run",
+			})
+		}
+	}
+	return findings, nil
+}
+
+func hasChecks(services []*api.Service) bool {
+	for _, s := range services {
+		if s != nil && len(s.Checks) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// imageTagRule finds the container images without a tag or with the latest
+// tag, which make the version of the task depend on when it is placed.
+type imageTagRule struct{}
+
+func (r *imageTagRule) Name() string { return "image-tag" }
+
+func (r *imageTagRule) Check(job *api.Job) ([]*Finding, error) {
+	var findings []*Finding
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			switch task.Driver {
+			case "docker", "podman":
+			default:
+				continue
+			}
+
+			image, ok := task.Config["image"].(string)
+			if !ok || image == "" {
+				continue
+			}
+
+			if tag := imageTag(image); tag == "" || tag == "latest" {
+				findings = append(findings, &Finding{
+					Severity: SeverityError,
+					Group:    stringValue(tg.Name),
+					Task:     task.Name,
+					Message:  fmt.Sprintf("Image %q uses the latest tag, pin a version or a digest instead", image),
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// imageTag returns the tag of a container image reference, or "@" if it is
+// pinned by digest.
+func imageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return "@"
+	}
+
+	// The registry may have a port, so only the last path segment has a tag
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+// resourcesRule finds the tasks without cpu or memory resources, which are
+// placed with default resources that may not fit them.
+type resourcesRule struct{}
+
+func (r *resourcesRule) Name() string { return "resources" }
+
+func (r *resourcesRule) Check(job *api.Job) ([]*Finding, error) {
+	var findings []*Finding
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			var missing []string
+			res := task.Resources
+			if res == nil || (res.CPU == nil && res.Cores == nil) {
+				missing = append(missing, "cpu")
+			}
+			if res == nil || res.MemoryMB == nil {
+				missing = append(missing, "memory")
+			}
+			if len(missing) == 0 {
+				continue
+			}
+
+			findings = append(findings, &Finding{
+				Severity: SeverityWarning,
+				Group:    stringValue(tg.Name),
+				Task:     task.Name,
+				Message:  fmt.Sprintf("Task has no %s resources, so it uses the defaults", strings.Join(missing, " or ")),
+			})
+		}
+	}
+	return findings, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -2,8 +2,10 @@ package catalog
 
 import (
 	"github.com/hashicorp/nomad/drivers/docker"
+	"github.com/hashicorp/nomad/drivers/ecs"
 	"github.com/hashicorp/nomad/drivers/exec"
 	"github.com/hashicorp/nomad/drivers/java"
+	"github.com/hashicorp/nomad/drivers/nspawn"
 	"github.com/hashicorp/nomad/drivers/qemu"
 	"github.com/hashicorp/nomad/drivers/rawexec"
 )
@@ -17,4 +19,6 @@ func init() {
 	Register(qemu.PluginID, qemu.PluginConfig)
 	Register(java.PluginID, java.PluginConfig)
 	RegisterDeferredConfig(docker.PluginID, docker.PluginConfig, docker.PluginLoader)
+	Register(ecs.PluginID, ecs.PluginConfig)
+	Register(nspawn.PluginID, nspawn.PluginConfig)
 }
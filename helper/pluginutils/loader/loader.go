@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/device"
 	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/fingerprint"
 	"github.com/hashicorp/nomad/plugins/shared/hclspec"
 )
 
@@ -272,6 +273,8 @@ func getPluginMap(pluginType string, logger log.Logger) map[string]plugin.Plugin
 		pmap[base.PluginTypeDevice] = &device.PluginDevice{}
 	case base.PluginTypeDriver:
 		pmap[base.PluginTypeDriver] = drivers.NewDriverPlugin(nil, logger)
+	case base.PluginTypeFingerprint:
+		pmap[base.PluginTypeFingerprint] = &fingerprint.PluginFingerprint{}
 	}
 
 	return pmap
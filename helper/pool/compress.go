@@ -0,0 +1,84 @@
+package pool
+
+import (
+	"net"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// zstdWindowSize is the window used to compress connections. The
+	// decoder refuses frames that declare a larger window, which bounds the
+	// memory a peer can make it allocate.
+	zstdWindowSize = 1 << 20
+
+	// zstdMaxDecoderMemory limits the decoded size of a single frame.
+	zstdMaxDecoderMemory = 4 * zstdWindowSize
+)
+
+// zstdConn wraps a connection to compress the data written to it and
+// decompress the data read from it with zstd. Every write is flushed so that
+// the peer can decode it without waiting for more data, which allows large
+// responses to be streamed in chunks over a multiplexed session.
+type zstdConn struct {
+	net.Conn
+
+	enc       *zstd.Encoder
+	dec       *zstd.Decoder
+	writeLock sync.Mutex
+}
+
+// NewZstdConn returns a connection that compresses the data written to conn
+// and decompresses the data read from it. Both ends of the connection must
+// be wrapped.
+func NewZstdConn(conn net.Conn) (net.Conn, error) {
+	enc, err := zstd.NewWriter(conn,
+		zstd.WithEncoderConcurrency(1),
+		zstd.WithEncoderLevel(zstd.SpeedFastest),
+		zstd.WithWindowSize(zstdWindowSize))
+	if err != nil {
+		return nil, err
+	}
+
+	dec, err := zstd.NewReader(conn,
+		zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderLowmem(true),
+		zstd.WithDecoderMaxWindow(zstdWindowSize),
+		zstd.WithDecoderMaxMemory(zstdMaxDecoderMemory))
+	if err != nil {
+		enc.Close()
+		return nil, err
+	}
+
+	return &zstdConn{
+		Conn: conn,
+		enc:  enc,
+		dec:  dec,
+	}, nil
+}
+
+func (c *zstdConn) Read(b []byte) (int, error) {
+	n, err := c.dec.Read(b)
+	if err != nil {
+		// The decoder is not safe to close concurrently with a read, so it
+		// is released by the reader once the connection fails or is closed.
+		c.dec.Close()
+	}
+	return n, err
+}
+
+func (c *zstdConn) Write(b []byte) (int, error) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	n, err := c.enc.Write(b)
+	if err != nil {
+		return n, err
+	}
+	return n, c.enc.Flush()
+}
+
+func (c *zstdConn) Close() error {
+	return c.Conn.Close()
+}
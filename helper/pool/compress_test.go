@@ -0,0 +1,45 @@
+package pool
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestZstdConn_RoundTrip(t *testing.T) {
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+
+	ca, err := NewZstdConn(a)
+	require.NoError(t, err)
+	cb, err := NewZstdConn(b)
+	require.NoError(t, err)
+
+	// Small writes must be readable without the writer closing the
+	// connection
+	go ca.Write([]byte("hello"))
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(cb, buf)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(buf))
+
+	// Large payloads are streamed in both directions
+	payload := make([]byte, 4<<20)
+	rand.New(rand.NewSource(1)).Read(payload[:len(payload)/2])
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := cb.Write(payload)
+		errCh <- err
+	}()
+	out := make([]byte, len(payload))
+	_, err = io.ReadFull(ca, out)
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+	require.True(t, bytes.Equal(payload, out))
+}
@@ -12,4 +12,9 @@ const (
 	// RpcMultiplexV2 allows a multiplexed connection to switch modes between
 	// RpcNomad and RpcStreaming per opened stream.
 	RpcMultiplexV2 = 0x06
+
+	// RpcMultiplexV2Zstd is a RpcMultiplexV2 connection whose data is
+	// compressed with zstd. It is used between servers that advertise
+	// support for it, such as for RPCs forwarded between regions.
+	RpcMultiplexV2Zstd = 0x07
 )
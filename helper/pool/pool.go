@@ -46,6 +46,7 @@ type Conn struct {
 	shouldClose int32
 
 	addr     net.Addr
+	key      string
 	session  *yamux.Session
 	lastUsed time.Time
 
@@ -275,14 +276,25 @@ func (p *ConnPool) SetConnListener(l chan<- *Conn) {
 	p.connListener = l
 }
 
+// connKey returns the key of the pooled connection to the address. Compressed
+// connections are pooled separately from uncompressed ones.
+func connKey(addr net.Addr, compress bool) string {
+	if compress {
+		return addr.String() + "/zstd"
+	}
+	return addr.String()
+}
+
 // Acquire is used to get a connection that is
 // pooled or to return a new connection
-func (p *ConnPool) acquire(region string, addr net.Addr) (*Conn, error) {
+func (p *ConnPool) acquire(region string, addr net.Addr, compress bool) (*Conn, error) {
+	key := connKey(addr, compress)
+
 	// Check to see if there's a pooled connection available. This is up
 	// here since it should the vastly more common case than the rest
 	// of the code here.
 	p.Lock()
-	c := p.pool[addr.String()]
+	c := p.pool[key]
 	if c != nil {
 		c.markForUse()
 		p.Unlock()
@@ -294,9 +306,9 @@ func (p *ConnPool) acquire(region string, addr net.Addr) (*Conn, error) {
 	// attempt is done.
 	var wait chan struct{}
 	var ok bool
-	if wait, ok = p.limiter[addr.String()]; !ok {
+	if wait, ok = p.limiter[key]; !ok {
 		wait = make(chan struct{})
-		p.limiter[addr.String()] = wait
+		p.limiter[key] = wait
 	}
 	isLeadThread := !ok
 	p.Unlock()
@@ -304,16 +316,16 @@ func (p *ConnPool) acquire(region string, addr net.Addr) (*Conn, error) {
 	// If we are the lead thread, make the new connection and then wake
 	// everybody else up to see if we got it.
 	if isLeadThread {
-		c, err := p.getNewConn(region, addr)
+		c, err := p.getNewConn(region, addr, compress)
 		p.Lock()
-		delete(p.limiter, addr.String())
+		delete(p.limiter, key)
 		close(wait)
 		if err != nil {
 			p.Unlock()
 			return nil, err
 		}
 
-		p.pool[addr.String()] = c
+		p.pool[key] = c
 
 		// If there is a connection listener, notify them of the new connection.
 		if p.connListener != nil {
@@ -337,7 +349,7 @@ func (p *ConnPool) acquire(region string, addr net.Addr) (*Conn, error) {
 
 	// See if the lead thread was able to get us a connection.
 	p.Lock()
-	if c := p.pool[addr.String()]; c != nil {
+	if c := p.pool[key]; c != nil {
 		c.markForUse()
 		p.Unlock()
 		return c, nil
@@ -347,8 +359,9 @@ func (p *ConnPool) acquire(region string, addr net.Addr) (*Conn, error) {
 	return nil, fmt.Errorf("rpc error: lead thread didn't get connection")
 }
 
-// getNewConn is used to return a new connection
-func (p *ConnPool) getNewConn(region string, addr net.Addr) (*Conn, error) {
+// getNewConn is used to return a new connection. If compress is set, the
+// connection is compressed with zstd.
+func (p *ConnPool) getNewConn(region string, addr net.Addr, compress bool) (*Conn, error) {
 	// Try to dial the conn
 	conn, err := net.DialTimeout("tcp", addr.String(), 10*time.Second)
 	if err != nil {
@@ -379,11 +392,24 @@ func (p *ConnPool) getNewConn(region string, addr net.Addr) (*Conn, error) {
 	}
 
 	// Write the multiplex byte to set the mode
-	if _, err := conn.Write([]byte{byte(RpcMultiplexV2)}); err != nil {
+	mode := RpcMultiplexV2
+	if compress {
+		mode = RpcMultiplexV2Zstd
+	}
+	if _, err := conn.Write([]byte{byte(mode)}); err != nil {
 		conn.Close()
 		return nil, err
 	}
 
+	if compress {
+		zconn, err := NewZstdConn(conn)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = zconn
+	}
+
 	// Setup the logger
 	conf := yamux.DefaultConfig()
 	conf.LogOutput = nil
@@ -400,6 +426,7 @@ func (p *ConnPool) getNewConn(region string, addr net.Addr) (*Conn, error) {
 	c := &Conn{
 		refCount: 1,
 		addr:     addr,
+		key:      connKey(addr, compress),
 		session:  session,
 		clients:  list.New(),
 		lastUsed: time.Now(),
@@ -416,8 +443,8 @@ func (p *ConnPool) clearConn(conn *Conn) {
 
 	// Clear from the cache
 	p.Lock()
-	if c, ok := p.pool[conn.addr.String()]; ok && c == conn {
-		delete(p.pool, conn.addr.String())
+	if c, ok := p.pool[conn.key]; ok && c == conn {
+		delete(p.pool, conn.key)
 	}
 	p.Unlock()
 
@@ -428,11 +455,11 @@ func (p *ConnPool) clearConn(conn *Conn) {
 }
 
 // getClient is used to get a usable client for an address
-func (p *ConnPool) getRPCClient(region string, addr net.Addr) (*Conn, *StreamClient, error) {
+func (p *ConnPool) getRPCClient(region string, addr net.Addr, compress bool) (*Conn, *StreamClient, error) {
 	retries := 0
 START:
 	// Try to get a conn first
-	conn, err := p.acquire(region, addr)
+	conn, err := p.acquire(region, addr, compress)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get conn: %v", err)
 	}
@@ -456,7 +483,18 @@ START:
 // StreamingRPC is used to make an streaming RPC call.  Callers must
 // close the connection when done.
 func (p *ConnPool) StreamingRPC(region string, addr net.Addr) (net.Conn, error) {
-	conn, err := p.acquire(region, addr)
+	return p.streamingRPC(region, addr, false)
+}
+
+// StreamingRPCCompressed is used to make a streaming RPC call over a
+// connection compressed with zstd. The remote host must support
+// RpcMultiplexV2Zstd. Callers must close the connection when done.
+func (p *ConnPool) StreamingRPCCompressed(region string, addr net.Addr) (net.Conn, error) {
+	return p.streamingRPC(region, addr, true)
+}
+
+func (p *ConnPool) streamingRPC(region string, addr net.Addr, compress bool) (net.Conn, error) {
+	conn, err := p.acquire(region, addr, compress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get conn: %v", err)
 	}
@@ -476,8 +514,19 @@ func (p *ConnPool) StreamingRPC(region string, addr net.Addr) (net.Conn, error)
 
 // RPC is used to make an RPC call to a remote host
 func (p *ConnPool) RPC(region string, addr net.Addr, method string, args interface{}, reply interface{}) error {
+	return p.rpc(region, addr, false, method, args, reply)
+}
+
+// RPCCompressed is used to make an RPC call to a remote host over a
+// connection compressed with zstd. The remote host must support
+// RpcMultiplexV2Zstd.
+func (p *ConnPool) RPCCompressed(region string, addr net.Addr, method string, args interface{}, reply interface{}) error {
+	return p.rpc(region, addr, true, method, args, reply)
+}
+
+func (p *ConnPool) rpc(region string, addr net.Addr, compress bool, method string, args interface{}, reply interface{}) error {
 	// Get a usable client
-	conn, sc, err := p.getRPCClient(region, addr)
+	conn, sc, err := p.getRPCClient(region, addr, compress)
 	if err != nil {
 		return fmt.Errorf("rpc error: %w", err)
 	}
@@ -49,7 +49,7 @@ func TestConnPool_ConnListener(t *testing.T) {
 	pool.SetConnListener(c)
 
 	// Make an RPC
-	_, err = pool.acquire("test", addr)
+	_, err = pool.acquire("test", addr, false)
 	require.Nil(err)
 
 	// Assert we get a connection.
@@ -64,3 +64,53 @@ func TestConnPool_ConnListener(t *testing.T) {
 	_, ok := <-c
 	require.False(ok)
 }
+
+func TestConnPool_Compressed(t *testing.T) {
+	require := require.New(t)
+
+	ports := freeport.MustTake(1)
+	defer freeport.Return(ports)
+
+	addrStr := fmt.Sprintf("127.0.0.1:%d", ports[0])
+	addr, err := net.ResolveTCPAddr("tcp", addrStr)
+	require.Nil(err)
+
+	modeCh := make(chan byte, 2)
+	exitCh := make(chan struct{})
+	defer close(exitCh)
+	go func() {
+		ln, err := net.Listen("tcp", addrStr)
+		require.Nil(err)
+		defer ln.Close()
+		for i := 0; i < 2; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			buf := make([]byte, 1)
+			conn.Read(buf)
+			modeCh <- buf[0]
+		}
+
+		<-exitCh
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	pool := newTestPool(t)
+	defer pool.Shutdown()
+
+	// Compressed and uncompressed connections are pooled separately
+	c1, err := pool.acquire("test", addr, false)
+	require.Nil(err)
+	require.Equal(byte(RpcMultiplexV2), <-modeCh)
+
+	c2, err := pool.acquire("test", addr, true)
+	require.Nil(err)
+	require.Equal(byte(RpcMultiplexV2Zstd), <-modeCh)
+
+	require.NotEqual(c1, c2)
+	require.Len(pool.pool, 2)
+}
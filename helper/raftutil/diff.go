@@ -0,0 +1,259 @@
+package raftutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/raft"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// SnapshotDiff is the difference between the state of two snapshots.
+type SnapshotDiff struct {
+	// A and B are the metadata of the compared snapshots.
+	A, B *raft.SnapshotMeta
+
+	// Tables are the differences of each compared state table, in a fixed
+	// order.
+	Tables []*TableDiff
+}
+
+// Empty returns whether the snapshots have the same state.
+func (d *SnapshotDiff) Empty() bool {
+	for _, t := range d.Tables {
+		if !t.Empty() {
+			return false
+		}
+	}
+	return true
+}
+
+// TableDiff is the difference between the objects of a state table in two
+// snapshots. Objects are identified by their key, such as their ID.
+type TableDiff struct {
+	Table string
+
+	// Added are the keys of the objects only in the second snapshot.
+	Added []string
+
+	// Removed are the keys of the objects only in the first snapshot.
+	Removed []string
+
+	// Changed are the objects that are in both snapshots but differ.
+	Changed []*ObjectDiff
+}
+
+// Empty returns whether the table has the same objects in both snapshots.
+func (t *TableDiff) Empty() bool {
+	return len(t.Added) == 0 && len(t.Removed) == 0 && len(t.Changed) == 0
+}
+
+// ObjectDiff is the difference between the two versions of an object.
+type ObjectDiff struct {
+	Key    string
+	Fields []*FieldDiff
+}
+
+// FieldDiff is the difference of a field of an object. The path of the field
+// is relative to the object, such as "TaskGroups[0].Count", and the values
+// are as they are encoded to JSON. A value is nil if the field is missing
+// from that version of the object.
+type FieldDiff struct {
+	Path string
+	A, B interface{}
+}
+
+// diffTables are the state tables compared by DiffSnapshots, how to list
+// their objects, and the key identifying each object.
+var diffTables = []struct {
+	name string
+	list func(*state.StateStore) (memdb.ResultIterator, error)
+	key  func(interface{}) string
+}{
+	{
+		name: "Jobs",
+		list: func(s *state.StateStore) (memdb.ResultIterator, error) { return s.Jobs(nil) },
+		key: func(raw interface{}) string {
+			job := raw.(*structs.Job)
+			return job.Namespace + "/" + job.ID
+		},
+	},
+	{
+		name: "Allocs",
+		list: func(s *state.StateStore) (memdb.ResultIterator, error) { return s.Allocs(nil, state.SortDefault) },
+		key:  func(raw interface{}) string { return raw.(*structs.Allocation).ID },
+	},
+	{
+		name: "Nodes",
+		list: func(s *state.StateStore) (memdb.ResultIterator, error) { return s.Nodes(nil) },
+		key:  func(raw interface{}) string { return raw.(*structs.Node).ID },
+	},
+	{
+		name: "ACLPolicies",
+		list: func(s *state.StateStore) (memdb.ResultIterator, error) { return s.ACLPolicies(nil) },
+		key:  func(raw interface{}) string { return raw.(*structs.ACLPolicy).Name },
+	},
+	{
+		name: "ACLTokens",
+		list: func(s *state.StateStore) (memdb.ResultIterator, error) { return s.ACLTokens(nil, state.SortDefault) },
+		key:  func(raw interface{}) string { return raw.(*structs.ACLToken).AccessorID },
+	},
+}
+
+// DiffSnapshots restores two snapshot archives and returns the differences
+// between their jobs, allocations, nodes and ACL objects, such as to find how
+// the state of servers diverged.
+func DiffSnapshots(a, b io.Reader) (*SnapshotDiff, error) {
+	stateA, metaA, err := RestoreFromArchive(a, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore first snapshot: %w", err)
+	}
+	stateB, metaB, err := RestoreFromArchive(b, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore second snapshot: %w", err)
+	}
+
+	diff := &SnapshotDiff{A: metaA, B: metaB}
+	for _, table := range diffTables {
+		objsA, err := keyedObjects(stateA, table.list, table.key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", table.name, err)
+		}
+		objsB, err := keyedObjects(stateB, table.list, table.key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", table.name, err)
+		}
+
+		tableDiff, err := diffObjects(table.name, objsA, objsB)
+		if err != nil {
+			return nil, err
+		}
+		diff.Tables = append(diff.Tables, tableDiff)
+	}
+	return diff, nil
+}
+
+// keyedObjects returns the objects of a state table by their key.
+func keyedObjects(s *state.StateStore, list func(*state.StateStore) (memdb.ResultIterator, error), key func(interface{}) string) (map[string]interface{}, error) {
+	iter, err := list(s)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := make(map[string]interface{})
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		objs[key(raw)] = raw
+	}
+	return objs, nil
+}
+
+// diffObjects returns the difference between the objects of a table in two
+// snapshots.
+func diffObjects(table string, a, b map[string]interface{}) (*TableDiff, error) {
+	diff := &TableDiff{Table: table}
+
+	for key, objA := range a {
+		objB, ok := b[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, key)
+			continue
+		}
+
+		fields, err := diffFields(objA, objB)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare %s %q: %w", table, key, err)
+		}
+		if len(fields) > 0 {
+			diff.Changed = append(diff.Changed, &ObjectDiff{Key: key, Fields: fields})
+		}
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			diff.Added = append(diff.Added, key)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Key < diff.Changed[j].Key
+	})
+	return diff, nil
+}
+
+// diffFields returns the fields that differ between two versions of an
+// object, sorted by path.
+func diffFields(a, b interface{}) ([]*FieldDiff, error) {
+	flatA, err := flattenJSON(a)
+	if err != nil {
+		return nil, err
+	}
+	flatB, err := flattenJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []*FieldDiff
+	for path, valA := range flatA {
+		valB, ok := flatB[path]
+		if !ok || !reflect.DeepEqual(valA, valB) {
+			fields = append(fields, &FieldDiff{Path: path, A: valA, B: valB})
+		}
+	}
+	for path, valB := range flatB {
+		if _, ok := flatA[path]; !ok {
+			fields = append(fields, &FieldDiff{Path: path, B: valB})
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Path < fields[j].Path
+	})
+	return fields, nil
+}
+
+// flattenJSON returns the scalar values of an object as it is encoded to JSON
+// by their path in the object. Null values are omitted.
+func flattenJSON(obj interface{}) (map[string]interface{}, error) {
+	buf, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(buf, &decoded); err != nil {
+		return nil, err
+	}
+
+	flat := make(map[string]interface{})
+	flattenValue(flat, "", decoded)
+	return flat, nil
+}
+
+func flattenValue(flat map[string]interface{}, path string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			flattenValue(flat, p, elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			flattenValue(flat, fmt.Sprintf("%s[%d]", path, i), elem)
+		}
+	case nil:
+		// Null values are treated as missing, so that a null and an empty
+		// map or list have no difference
+	default:
+		flat[path] = v
+	}
+}
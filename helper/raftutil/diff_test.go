@@ -0,0 +1,46 @@
+package raftutil
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffObjects(t *testing.T) {
+	ci.Parallel(t)
+
+	kept, removed, added := mock.Job(), mock.Job(), mock.Job()
+
+	changed := mock.Job()
+	changedB := changed.Copy()
+	changedB.TaskGroups[0].Count = 3
+	changedB.Meta = nil
+
+	a := map[string]interface{}{
+		kept.ID:    kept,
+		removed.ID: removed,
+		changed.ID: changed,
+	}
+	b := map[string]interface{}{
+		kept.ID:    kept.Copy(),
+		added.ID:   added,
+		changed.ID: changedB,
+	}
+
+	diff, err := diffObjects("Jobs", a, b)
+	require.NoError(t, err)
+	require.False(t, diff.Empty())
+	require.Equal(t, []string{added.ID}, diff.Added)
+	require.Equal(t, []string{removed.ID}, diff.Removed)
+
+	require.Len(t, diff.Changed, 1)
+	require.Equal(t, changed.ID, diff.Changed[0].Key)
+
+	// Fields are sorted by path, and missing fields have no value
+	require.Equal(t, []*FieldDiff{
+		{Path: "Meta.owner", A: "armon", B: nil},
+		{Path: "TaskGroups[0].Count", A: float64(10), B: float64(3)},
+	}, diff.Changed[0].Fields)
+}
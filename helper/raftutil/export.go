@@ -0,0 +1,143 @@
+package raftutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/nomad"
+)
+
+// exportTables names the state table each type of snapshot object is
+// exported to. The names match the keys of StateAsMap.
+var exportTables = map[nomad.SnapshotType]string{
+	nomad.NodeSnapshot:                         "Nodes",
+	nomad.JobSnapshot:                          "Jobs",
+	nomad.IndexSnapshot:                        "Indexes",
+	nomad.EvalSnapshot:                         "Evals",
+	nomad.AllocSnapshot:                        "Allocs",
+	nomad.PeriodicLaunchSnapshot:               "PeriodicLaunches",
+	nomad.JobSummarySnapshot:                   "JobSummaries",
+	nomad.VaultAccessorSnapshot:                "VaultAccessors",
+	nomad.ServiceIdentityTokenAccessorSnapshot: "SITokenAccessors",
+	nomad.JobVersionSnapshot:                   "JobVersions",
+	nomad.DeploymentSnapshot:                   "Deployments",
+	nomad.ACLPolicySnapshot:                    "ACLPolicies",
+	nomad.ACLTokenSnapshot:                     "ACLTokens",
+	nomad.SchedulerConfigSnapshot:              "SchedulerConfig",
+	nomad.ClusterMetadataSnapshot:              "ClusterMetadata",
+	nomad.ScalingEventsSnapshot:                "ScalingEvents",
+	nomad.ScalingPolicySnapshot:                "ScalingPolicies",
+	nomad.CSIPluginSnapshot:                    "CSIPlugins",
+	nomad.CSIVolumeSnapshot:                    "CSIVolumes",
+	nomad.NamespaceSnapshot:                    "Namespaces",
+	nomad.ServiceRegistrationSnapshot:          "ServiceRegistrations",
+	nomad.SecureVariablesSnapshot:              "SecureVariables",
+	nomad.SecureVariablesQuotaSnapshot:         "SecureVariablesQuotas",
+	nomad.RootKeyMetaSnapshot:                  "RootKeyMeta",
+	nomad.NodeIntroTokenSnapshot:               "NodeIntroTokens",
+	nomad.TrashedJobSnapshot:                   "TrashedJobs",
+}
+
+// ExportFromArchive streams the state of a snapshot archive into dir, as one
+// file of newline-delimited JSON per state table named after the table, such
+// as "Allocs.ndjson". Unlike RestoreFromArchive, the state is never held in
+// memory as a whole. It returns the number of objects exported per table.
+func ExportFromArchive(archive io.Reader, filter *nomad.FSMFilter, dir string) (*raft.SnapshotMeta, map[string]int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	exp := &exporter{
+		dir:    dir,
+		files:  make(map[string]*exportFile),
+		counts: make(map[string]int),
+	}
+
+	// r is closed by ReadSnapshotObjects, w is closed by CopySnapshot
+	r, w := io.Pipe()
+
+	errCh := make(chan error)
+	metaCh := make(chan *raft.SnapshotMeta)
+
+	go func() {
+		meta, err := snapshot.CopySnapshot(archive, w)
+		if err != nil {
+			errCh <- fmt.Errorf("failed to read snapshot: %w", err)
+		} else {
+			metaCh <- meta
+		}
+	}()
+
+	err := nomad.ReadSnapshotObjects(r, filter, exp.write)
+	if closeErr := exp.close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to export snapshot: %w", err)
+	}
+
+	select {
+	case err := <-errCh:
+		return nil, nil, err
+	case meta := <-metaCh:
+		return meta, exp.counts, nil
+	}
+}
+
+// exporter writes snapshot objects to the file of their table, opening the
+// files as their first object is read.
+type exporter struct {
+	dir    string
+	files  map[string]*exportFile
+	counts map[string]int
+}
+
+type exportFile struct {
+	f   *os.File
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (e *exporter) write(snapType nomad.SnapshotType, obj interface{}) error {
+	table, ok := exportTables[snapType]
+	if !ok {
+		return nil
+	}
+
+	file, ok := e.files[table]
+	if !ok {
+		f, err := os.Create(filepath.Join(e.dir, table+".ndjson"))
+		if err != nil {
+			return err
+		}
+		buf := bufio.NewWriter(f)
+		file = &exportFile{f: f, buf: buf, enc: json.NewEncoder(buf)}
+		e.files[table] = file
+	}
+
+	if err := file.enc.Encode(obj); err != nil {
+		return fmt.Errorf("failed to encode %s object: %w", table, err)
+	}
+	e.counts[table]++
+	return nil
+}
+
+func (e *exporter) close() error {
+	var firstErr error
+	for _, file := range e.files {
+		if err := file.buf.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := file.f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
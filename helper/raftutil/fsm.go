@@ -21,6 +21,7 @@ type nomadFSM interface {
 	State() *state.StateStore
 	Restore(io.ReadCloser) error
 	RestoreWithFilter(io.ReadCloser, *nomad.FSMFilter) error
+	RestoreWithProgress(io.ReadCloser, *nomad.FSMFilter, nomad.RestoreProgressFn) error
 }
 
 type FSMHelper struct {
@@ -188,23 +189,30 @@ func (f *FSMHelper) StateAsMap() map[string][]interface{} {
 // StateAsMap returns a json-able representation of the state
 func StateAsMap(store *state.StateStore) map[string][]interface{} {
 	result := map[string][]interface{}{
-		"ACLPolicies":      toArray(store.ACLPolicies(nil)),
-		"ACLTokens":        toArray(store.ACLTokens(nil, state.SortDefault)),
-		"Allocs":           toArray(store.Allocs(nil, state.SortDefault)),
-		"CSIPlugins":       toArray(store.CSIPlugins(nil)),
-		"CSIVolumes":       toArray(store.CSIVolumes(nil)),
-		"Deployments":      toArray(store.Deployments(nil, state.SortDefault)),
-		"Evals":            toArray(store.Evals(nil, state.SortDefault)),
-		"Indexes":          toArray(store.Indexes()),
-		"JobSummaries":     toArray(store.JobSummaries(nil)),
-		"JobVersions":      toArray(store.JobVersions(nil)),
-		"Jobs":             toArray(store.Jobs(nil)),
-		"Nodes":            toArray(store.Nodes(nil)),
-		"PeriodicLaunches": toArray(store.PeriodicLaunches(nil)),
-		"SITokenAccessors": toArray(store.SITokenAccessors(nil)),
-		"ScalingEvents":    toArray(store.ScalingEvents(nil)),
-		"ScalingPolicies":  toArray(store.ScalingPolicies(nil)),
-		"VaultAccessors":   toArray(store.VaultAccessors(nil)),
+		"ACLPolicies":           toArray(store.ACLPolicies(nil)),
+		"ACLTokens":             toArray(store.ACLTokens(nil, state.SortDefault)),
+		"Allocs":                toArray(store.Allocs(nil, state.SortDefault)),
+		"CSIPlugins":            toArray(store.CSIPlugins(nil)),
+		"CSIVolumes":            toArray(store.CSIVolumes(nil)),
+		"Deployments":           toArray(store.Deployments(nil, state.SortDefault)),
+		"Evals":                 toArray(store.Evals(nil, state.SortDefault)),
+		"Indexes":               toArray(store.Indexes()),
+		"JobSummaries":          toArray(store.JobSummaries(nil)),
+		"JobVersions":           toArray(store.JobVersions(nil)),
+		"Jobs":                  toArray(store.Jobs(nil)),
+		"Namespaces":            toArray(store.Namespaces(nil)),
+		"NodeIntroTokens":       toArray(store.NodeIntroTokens(nil)),
+		"TrashedJobs":           toArray(store.TrashedJobs(nil)),
+		"Nodes":                 toArray(store.Nodes(nil)),
+		"PeriodicLaunches":      toArray(store.PeriodicLaunches(nil)),
+		"RootKeyMeta":           toArray(store.RootKeyMetas(nil)),
+		"SITokenAccessors":      toArray(store.SITokenAccessors(nil)),
+		"ScalingEvents":         toArray(store.ScalingEvents(nil)),
+		"ScalingPolicies":       toArray(store.ScalingPolicies(nil)),
+		"SecureVariables":       toArray(store.SecureVariables(nil)),
+		"SecureVariablesQuotas": toArray(store.SecureVariablesQuotas(nil)),
+		"ServiceRegistrations":  toArray(store.GetServiceRegistrations(nil)),
+		"VaultAccessors":        toArray(store.VaultAccessors(nil)),
 	}
 
 	insertEnterpriseState(result, store)
@@ -0,0 +1,136 @@
+package raftutil
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/raft"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// SnapshotStats is the breakdown of the state of a snapshot by state table,
+// to find what makes up the size of a snapshot.
+type SnapshotStats struct {
+	Meta *raft.SnapshotMeta
+
+	// Tables are the statistics of each state table, by decreasing size.
+	Tables []*TableStats
+
+	// LargestJobs and LargestAllocs are the largest jobs and allocations, by
+	// decreasing size.
+	LargestJobs   []*ObjectStats
+	LargestAllocs []*ObjectStats
+}
+
+// TableStats are the number of objects of a state table and their size. The
+// size of an object is the size of its encoding in snapshots, so it only
+// approximates the space it takes in a snapshot.
+type TableStats struct {
+	Table string
+	Count int
+	Size  int
+}
+
+// ObjectStats is the size of an object, as for TableStats.
+type ObjectStats struct {
+	ID        string
+	Namespace string
+	Name      string
+	Size      int
+}
+
+// InspectArchive restores a snapshot archive and returns the breakdown of
+// its state, including its top largest jobs and allocations.
+func InspectArchive(archive io.Reader, top int) (*SnapshotStats, error) {
+	store, meta, err := RestoreFromArchive(archive, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &SnapshotStats{Meta: meta}
+	for table, objs := range StateAsMap(store) {
+		tableStats := &TableStats{Table: table, Count: len(objs)}
+		for _, obj := range objs {
+			if err, ok := obj.(error); ok {
+				return nil, fmt.Errorf("failed to list %s: %w", table, err)
+			}
+
+			size, err := encodedSize(obj)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode object of %s: %w", table, err)
+			}
+			tableStats.Size += size
+		}
+		stats.Tables = append(stats.Tables, tableStats)
+	}
+	sort.Slice(stats.Tables, func(i, j int) bool {
+		a, b := stats.Tables[i], stats.Tables[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.Table < b.Table
+	})
+
+	stats.LargestJobs, err = largestObjects(store.Jobs(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	stats.LargestAllocs, err = largestObjects(store.Allocs(nil, state.SortDefault))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+	if len(stats.LargestJobs) > top {
+		stats.LargestJobs = stats.LargestJobs[:top]
+	}
+	if len(stats.LargestAllocs) > top {
+		stats.LargestAllocs = stats.LargestAllocs[:top]
+	}
+
+	return stats, nil
+}
+
+// largestObjects returns the jobs or allocations of iter by decreasing size.
+func largestObjects(iter memdb.ResultIterator, err error) ([]*ObjectStats, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []*ObjectStats
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		size, err := encodedSize(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		obj := &ObjectStats{Size: size}
+		switch o := raw.(type) {
+		case *structs.Job:
+			obj.ID, obj.Namespace, obj.Name = o.ID, o.Namespace, o.Name
+		case *structs.Allocation:
+			obj.ID, obj.Namespace, obj.Name = o.ID, o.Namespace, o.Name
+		}
+		objs = append(objs, obj)
+	}
+
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].Size != objs[j].Size {
+			return objs[i].Size > objs[j].Size
+		}
+		return objs[i].ID < objs[j].ID
+	})
+	return objs, nil
+}
+
+// encodedSize returns the size of the encoding of obj in snapshots.
+func encodedSize(obj interface{}) (int, error) {
+	var buf []byte
+	if err := codec.NewEncoderBytes(&buf, structs.MsgpackHandle).Encode(obj); err != nil {
+		return 0, err
+	}
+	return len(buf), nil
+}
@@ -0,0 +1,34 @@
+package raftutil
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLargestObjects(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+
+	small := mock.Job()
+	large := mock.Job()
+	large.Meta = map[string]string{"large": string(make([]byte, 4096))}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, small))
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, large))
+
+	objs, err := largestObjects(store.Jobs(nil))
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+
+	// Objects are sorted by decreasing size
+	require.Equal(t, large.ID, objs[0].ID)
+	require.Equal(t, large.Namespace, objs[0].Namespace)
+	require.Equal(t, large.Name, objs[0].Name)
+	require.Equal(t, small.ID, objs[1].ID)
+	require.Greater(t, objs[0].Size, objs[1].Size)
+}
@@ -58,6 +58,16 @@ var msgTypeNames = map[structs.MessageType]string{
 	structs.SecureVariableDeleteRequestType:              "SecureVariableDeleteRequestType",
 	structs.RootKeyMetaUpsertRequestType:                 "RootKeyMetaUpsertRequestType",
 	structs.RootKeyMetaDeleteRequestType:                 "RootKeyMetaDeleteRequestType",
+	structs.JobFreezeRequestType:                         "JobFreezeRequestType",
+	structs.NodeIntroTokenUpsertRequestType:              "NodeIntroTokenUpsertRequestType",
+	structs.NodeIntroTokenConsumeRequestType:             "NodeIntroTokenConsumeRequestType",
+	structs.NodeIntroTokenExpireRequestType:              "NodeIntroTokenExpireRequestType",
+	structs.SecureVariablesTxnRequestType:                "SecureVariablesTxnRequestType",
+	structs.ReconcileDeploymentsRequestType:              "ReconcileDeploymentsRequestType",
+	structs.ReconcileServiceRegistrationsRequestType:     "ReconcileServiceRegistrationsRequestType",
+	structs.NodeBatchUpdateStatusRequestType:             "NodeBatchUpdateStatusRequestType",
+	structs.JobRestoreRequestType:                        "JobRestoreRequestType",
+	structs.JobTrashExpireRequestType:                    "JobTrashExpireRequestType",
 	structs.NamespaceUpsertRequestType:                   "NamespaceUpsertRequestType",
 	structs.NamespaceDeleteRequestType:                   "NamespaceDeleteRequestType",
 }
@@ -0,0 +1,73 @@
+package raftutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/nomad"
+)
+
+// RewriteArchive restores a snapshot archive with a filter and writes the
+// restored state to out as a new snapshot archive, at the index of the
+// original snapshot. With a filter created by nomad.NewRedactingFSMFilter,
+// the new snapshot has the secrets of the original redacted, so that it can
+// be shared, such as with support.
+func RewriteArchive(archive io.Reader, out io.Writer, filter *nomad.FSMFilter) (*raft.SnapshotMeta, error) {
+	fsm, meta, err := restoreFSM(archive, filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := fsm.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot state: %w", err)
+	}
+	defer snap.Release()
+
+	// The archive needs the size of the snapshot ahead of it, so persist it
+	// to a scratch file first
+	f, err := os.CreateTemp("", "snapshot")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	// The snapshot is encoded an object at a time, so buffer the writes
+	buf := bufio.NewWriter(f)
+	if err := snap.Persist(&snapshotSink{Writer: buf}); err != nil {
+		return nil, fmt.Errorf("failed to persist snapshot: %w", err)
+	}
+	if err := buf.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind snapshot file: %w", err)
+	}
+
+	newMeta := *meta
+	newMeta.Size = size
+	if err := snapshot.WriteArchive(out, &newMeta, f); err != nil {
+		return nil, err
+	}
+	return &newMeta, nil
+}
+
+// snapshotSink is a raft.SnapshotSink persisting a snapshot to a writer.
+type snapshotSink struct {
+	io.Writer
+}
+
+func (s *snapshotSink) ID() string    { return "" }
+func (s *snapshotSink) Cancel() error { return nil }
+func (s *snapshotSink) Close() error  { return nil }
@@ -13,6 +13,22 @@ import (
 )
 
 func RestoreFromArchive(archive io.Reader, filter *nomad.FSMFilter) (*state.StateStore, *raft.SnapshotMeta, error) {
+	return RestoreFromArchiveWithProgress(archive, filter, nil)
+}
+
+// RestoreFromArchiveWithProgress restores the state of a snapshot archive,
+// calling progress with the number of objects restored as the restore
+// proceeds.
+func RestoreFromArchiveWithProgress(archive io.Reader, filter *nomad.FSMFilter, progress nomad.RestoreProgressFn) (*state.StateStore, *raft.SnapshotMeta, error) {
+	fsm, meta, err := restoreFSM(archive, filter, progress)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fsm.State(), meta, nil
+}
+
+// restoreFSM restores a snapshot archive into a new FSM.
+func restoreFSM(archive io.Reader, filter *nomad.FSMFilter, progress nomad.RestoreProgressFn) (nomadFSM, *raft.SnapshotMeta, error) {
 	logger := hclog.L()
 
 	fsm, err := dummyFSM(logger)
@@ -35,7 +51,7 @@ func RestoreFromArchive(archive io.Reader, filter *nomad.FSMFilter) (*state.Stat
 		}
 	}()
 
-	err = fsm.RestoreWithFilter(r, filter)
+	err = fsm.RestoreWithProgress(r, filter, progress)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to restore from snapshot: %w", err)
 	}
@@ -44,6 +60,6 @@ func RestoreFromArchive(archive io.Reader, filter *nomad.FSMFilter) (*state.Stat
 	case err := <-errCh:
 		return nil, nil, err
 	case meta := <-metaCh:
-		return fsm.State(), meta, nil
+		return fsm, meta, nil
 	}
 }
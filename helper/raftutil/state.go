@@ -90,7 +90,7 @@ func LogEntries(p string) (<-chan interface{}, <-chan error, error) {
 	return entries, warnings, nil
 }
 
-type logMessage struct {
+type LogMessage struct {
 	LogType string
 	Term    uint64
 	Index   uint64
@@ -100,8 +100,8 @@ type logMessage struct {
 	Body                  interface{} `json:",omitempty"`
 }
 
-func decode(e *raft.Log) (*logMessage, error) {
-	m := &logMessage{
+func decode(e *raft.Log) (*LogMessage, error) {
+	m := &LogMessage{
 		LogType: logTypes[e.Type],
 		Term:    e.Term,
 		Index:   e.Index,
@@ -0,0 +1,256 @@
+package raftutil
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+const (
+	// boltPageHeaderSize is the size of the header of BoltDB pages, which
+	// precedes the meta of the meta pages.
+	boltPageHeaderSize = 16
+
+	// boltMetaSize is the size of the meta of BoltDB files, which is
+	// followed by its checksum.
+	boltMetaSize = 56
+
+	// boltMagic marks valid BoltDB meta pages.
+	boltMagic = 0xED0CDAED
+
+	// tailTornCopyWarning is the number of consecutive copies of raft.db
+	// that changed while being copied after which a warning is sent.
+	tailTornCopyWarning = 10
+)
+
+var errTornCopy = errors.New("raft.db changed while being copied")
+
+// TailLogEntries follows the raft logs of the raft.db file at the path p as
+// they are appended, sending the entries already in the logs followed by new
+// entries to the returned channel until ctx is done, polling for new entries
+// every interval. Non-fatal errors are sent to the warnings channel.
+//
+// Unlike LogEntries, the raft.db file may be in use by a running server. As
+// the server locks the file, the file is never opened as a BoltDB database.
+// Instead its last transaction is read from its meta pages on every poll,
+// and once it changed the file is copied and the new entries are read from
+// the copy. Copies that raced with a transaction are discarded, as a copy is
+// only consistent if no transaction was committed while it was made.
+func TailLogEntries(ctx context.Context, p string, interval time.Duration) (<-chan *LogMessage, <-chan error, error) {
+	if _, err := boltTxID(p); err != nil {
+		return nil, nil, fmt.Errorf("failed to open raft logs: %v", err)
+	}
+
+	entries := make(chan *LogMessage)
+	warnings := make(chan error)
+
+	go func() {
+		defer close(entries)
+
+		t := &logTailer{path: p, entries: entries}
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			if err := t.poll(ctx); err != nil {
+				select {
+				case warnings <- err:
+				case <-ctx.Done():
+					return
+				}
+			}
+			timer.Reset(interval)
+		}
+	}()
+
+	return entries, warnings, nil
+}
+
+// logTailer reads the entries appended to a raft.db file since it last
+// polled it.
+type logTailer struct {
+	path    string
+	entries chan<- *LogMessage
+
+	// txID is the last transaction of the file whose entries were read.
+	txID uint64
+
+	// next is the index of the next entry to read, or 0 before the first
+	// entries are read.
+	next uint64
+
+	// torn is the number of consecutive copies that raced a transaction.
+	torn int
+}
+
+// poll sends the entries appended to the file since the last poll.
+func (t *logTailer) poll(ctx context.Context) error {
+	txID, err := boltTxID(t.path)
+	if err != nil {
+		return fmt.Errorf("failed to read raft logs: %v", err)
+	}
+	if txID == t.txID {
+		return nil
+	}
+
+	copyPath, err := copyBoltFile(t.path, txID)
+	if err == errTornCopy {
+		t.torn++
+		if t.torn%tailTornCopyWarning == 0 {
+			return fmt.Errorf("raft.db changed while being copied %d times in a row, retrying", t.torn)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy raft logs: %v", err)
+	}
+	defer os.Remove(copyPath)
+	t.torn = 0
+
+	store, firstIdx, lastIdx, err := RaftStateInfo(copyPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var warning error
+	next := t.next
+	if next < firstIdx {
+		if next != 0 {
+			warning = fmt.Errorf("log entries %d to %d were compacted before being read", next, firstIdx-1)
+		}
+		next = firstIdx
+	}
+
+	for i := next; i <= lastIdx && lastIdx != 0; i++ {
+		var e raft.Log
+		if err := store.GetLog(i, &e); err != nil {
+			return fmt.Errorf("failed to read log entry at index %d (firstIdx: %d, lastIdx: %d): %v",
+				i, firstIdx, lastIdx, err)
+		}
+
+		entry, err := decode(&e)
+		if err != nil {
+			return fmt.Errorf("failed to decode log entry at index %d: %v", i, err)
+		}
+
+		select {
+		case t.entries <- entry:
+		case <-ctx.Done():
+			return nil
+		}
+		t.next = i + 1
+	}
+
+	t.txID = txID
+	return warning
+}
+
+// copyBoltFile copies the BoltDB file at path, whose last transaction is
+// txID, to a temporary file and returns its path. It returns errTornCopy if a
+// transaction was committed while copying.
+func copyBoltFile(path string, txID uint64) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp("", "raft-*.db")
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	if err == nil {
+		err = dst.Sync()
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+
+	// BoltDB never overwrites the pages of the last transaction until the
+	// next one is committed, so the copy is consistent if the last
+	// transaction is still the same.
+	afterTxID, err := boltTxID(path)
+	if err == nil && afterTxID != txID {
+		err = errTornCopy
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+	return dst.Name(), nil
+}
+
+// boltTxID returns the ID of the last transaction committed to the BoltDB
+// file at path, read from its meta pages without locking the file. Like
+// BoltDB, it assumes the byte order of the file is little endian.
+func boltTxID(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// The meta pages are the first two pages of the file, and the page size
+	// is recorded in the meta
+	meta0, ok0 := readBoltMeta(f, 0)
+	pageSize := int64(os.Getpagesize())
+	if ok0 {
+		pageSize = int64(binary.LittleEndian.Uint32(meta0[8:12]))
+	}
+	meta1, ok1 := readBoltMeta(f, pageSize)
+
+	var txID uint64
+	switch {
+	case ok0 && ok1:
+		txID = binary.LittleEndian.Uint64(meta0[48:56])
+		if tx1 := binary.LittleEndian.Uint64(meta1[48:56]); tx1 > txID {
+			txID = tx1
+		}
+	case ok0:
+		txID = binary.LittleEndian.Uint64(meta0[48:56])
+	case ok1:
+		txID = binary.LittleEndian.Uint64(meta1[48:56])
+	default:
+		return 0, fmt.Errorf("%s is not a valid BoltDB file", path)
+	}
+	return txID, nil
+}
+
+// readBoltMeta reads the meta of the meta page at offset, and returns whether
+// it is valid.
+func readBoltMeta(f *os.File, offset int64) ([]byte, bool) {
+	buf := make([]byte, boltMetaSize+8)
+	if _, err := f.ReadAt(buf, offset+boltPageHeaderSize); err != nil {
+		return nil, false
+	}
+
+	if binary.LittleEndian.Uint32(buf[0:4]) != boltMagic {
+		return nil, false
+	}
+
+	h := fnv.New64a()
+	h.Write(buf[:boltMetaSize])
+	checksum := binary.LittleEndian.Uint64(buf[boltMetaSize:])
+	if checksum != 0 && checksum != h.Sum64() {
+		return nil, false
+	}
+	return buf[:boltMetaSize], true
+}
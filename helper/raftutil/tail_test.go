@@ -0,0 +1,83 @@
+package raftutil
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailLogEntries(t *testing.T) {
+	ci.Parallel(t)
+
+	// The raft logs stay open, as by a running server
+	path := filepath.Join(t.TempDir(), "raft.db")
+	store, err := raftboltdb.NewBoltStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	storeLog := func(index uint64) {
+		req := &structs.NodeRegisterRequest{Node: mock.Node()}
+		data, err := structs.Encode(structs.NodeRegisterRequestType, req)
+		require.NoError(t, err)
+		require.NoError(t, store.StoreLog(&raft.Log{
+			Index: index,
+			Term:  2,
+			Type:  raft.LogCommand,
+			Data:  data,
+		}))
+	}
+	storeLog(1)
+	storeLog(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	entries, warnings, err := TailLogEntries(ctx, path, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	next := func() *LogMessage {
+		select {
+		case entry := <-entries:
+			return entry
+		case err := <-warnings:
+			t.Fatalf("unexpected warning: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for log entry")
+		}
+		return nil
+	}
+
+	// The existing entries are read, followed by the new ones
+	for _, index := range []uint64{1, 2} {
+		entry := next()
+		require.Equal(t, index, entry.Index)
+		require.EqualValues(t, 2, entry.Term)
+		require.Equal(t, "LogCommand", entry.LogType)
+		require.Equal(t, "NodeRegisterRequestType", entry.CommandType)
+		require.NotNil(t, entry.Body)
+	}
+
+	storeLog(3)
+	require.EqualValues(t, 3, next().Index)
+
+	// The entries channel is closed once the context is done
+	cancel()
+	require.Eventually(t, func() bool {
+		_, ok := <-entries
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTailLogEntries_InvalidFile(t *testing.T) {
+	ci.Parallel(t)
+
+	_, _, err := TailLogEntries(context.Background(), filepath.Join(t.TempDir(), "raft.db"), time.Second)
+	require.Error(t, err)
+}
@@ -0,0 +1,223 @@
+// Package agent implements the snapshot agent of servers, which periodically
+// saves snapshots of the state of the cluster to a storage, such as an S3
+// bucket, and deletes the snapshots beyond its retention.
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// snapshotSuffix is the suffix of the names of snapshots.
+	snapshotSuffix = ".snap"
+
+	// retryInterval is the interval before retrying a failed snapshot.
+	retryInterval = time.Minute
+)
+
+// SnapshotFn takes a snapshot of the state and returns its archive, which is
+// closed once stored.
+type SnapshotFn func() (io.ReadCloser, error)
+
+// Agent saves a snapshot every interval.
+type Agent struct {
+	logger   hclog.Logger
+	config   *Config
+	storage  Storage
+	snapshot SnapshotFn
+
+	// now returns the current time, and is replaced by tests.
+	now func() time.Time
+}
+
+// New returns a snapshot agent saving the snapshots taken by snapshot to the
+// storage configured by config.
+func New(logger hclog.Logger, config *Config, snapshot SnapshotFn) (*Agent, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	storage, err := NewStorage(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Agent{
+		logger:   logger,
+		config:   config,
+		storage:  storage,
+		snapshot: snapshot,
+		now:      time.Now,
+	}, nil
+}
+
+// Run saves snapshots until stopCh is closed. The first snapshot is due an
+// interval after the latest stored snapshot, so that snapshots are not saved
+// more often than the interval when Run is restarted, such as when the
+// leadership of the cluster changes.
+func (a *Agent) Run(stopCh <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if c, ok := a.storage.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	a.logger.Info("starting snapshot agent", "storage", a.storage, "interval", a.config.interval())
+
+	var wait time.Duration
+	latest, err := a.latestSnapshot(ctx)
+	if err != nil {
+		a.logger.Warn("failed to find latest snapshot", "error", err)
+	} else if !latest.IsZero() {
+		wait = latest.Add(a.config.interval()).Sub(a.now())
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		wait = a.config.interval()
+		if _, err := a.Snapshot(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			a.logger.Error("failed to save snapshot", "error", err)
+			wait = retryInterval
+		}
+		timer.Reset(wait)
+	}
+}
+
+// Snapshot takes a snapshot, saves it, and deletes the snapshots beyond the
+// retention. It returns the name of the snapshot.
+func (a *Agent) Snapshot(ctx context.Context) (string, error) {
+	defer metrics.MeasureSince([]string{"nomad", "snapshot_agent", "save"}, time.Now())
+
+	name, err := a.save(ctx)
+	if err != nil {
+		metrics.IncrCounter([]string{"nomad", "snapshot_agent", "failure"}, 1)
+		return "", err
+	}
+
+	metrics.IncrCounter([]string{"nomad", "snapshot_agent", "success"}, 1)
+	metrics.SetGauge([]string{"nomad", "snapshot_agent", "last_success"}, float32(a.now().Unix()))
+	a.logger.Info("saved snapshot", "name", name)
+
+	// Failing to delete old snapshots doesn't fail the snapshot, as they are
+	// deleted along with the next one
+	if err := a.deleteExpired(ctx); err != nil {
+		a.logger.Warn("failed to delete expired snapshots", "error", err)
+	}
+	return name, nil
+}
+
+func (a *Agent) save(ctx context.Context) (string, error) {
+	snap, err := a.snapshot()
+	if err != nil {
+		return "", fmt.Errorf("failed to take snapshot: %w", err)
+	}
+	defer snap.Close()
+
+	name := a.snapshotName(a.now())
+	if err := a.storage.Put(ctx, name, snap); err != nil {
+		return "", fmt.Errorf("failed to store snapshot %q: %w", name, err)
+	}
+	return name, nil
+}
+
+// snapshotName returns the name of a snapshot taken at t. Names sort in the
+// order snapshots were taken.
+func (a *Agent) snapshotName(t time.Time) string {
+	return fmt.Sprintf("%s-%d%s", a.config.namePrefix(), t.UnixNano(), snapshotSuffix)
+}
+
+// snapshots returns the names of the stored snapshots and the time they were
+// taken at, from the oldest to the latest. Other objects of the storage are
+// ignored.
+func (a *Agent) snapshots(ctx context.Context) ([]string, []time.Time, error) {
+	prefix := a.config.namePrefix() + "-"
+	objects, err := a.storage.List(ctx, prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	type snapshot struct {
+		name string
+		t    time.Time
+	}
+	var snaps []snapshot
+	for _, name := range objects {
+		if !strings.HasSuffix(name, snapshotSuffix) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimPrefix(name, prefix), snapshotSuffix)
+		nanos, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			continue
+		}
+		snaps = append(snaps, snapshot{name, time.Unix(0, nanos)})
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].t.Before(snaps[j].t) })
+
+	names := make([]string, len(snaps))
+	times := make([]time.Time, len(snaps))
+	for i, s := range snaps {
+		names[i], times[i] = s.name, s.t
+	}
+	return names, times, nil
+}
+
+// latestSnapshot returns the time the latest stored snapshot was taken at, or
+// the zero time if there is none.
+func (a *Agent) latestSnapshot(ctx context.Context) (time.Time, error) {
+	_, times, err := a.snapshots(ctx)
+	if err != nil || len(times) == 0 {
+		return time.Time{}, err
+	}
+	return times[len(times)-1], nil
+}
+
+// deleteExpired deletes the oldest snapshots beyond the retention.
+func (a *Agent) deleteExpired(ctx context.Context) error {
+	retain := a.config.retain()
+	if retain == 0 {
+		return nil
+	}
+
+	names, _, err := a.snapshots(ctx)
+	if err != nil {
+		return err
+	}
+
+	for len(names) > retain {
+		if err := a.storage.Delete(ctx, names[0]); err != nil {
+			return fmt.Errorf("failed to delete snapshot %q: %w", names[0], err)
+		}
+		a.logger.Debug("deleted expired snapshot", "name", names[0])
+		names = names[1:]
+	}
+	return nil
+}
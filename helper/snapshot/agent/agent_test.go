@@ -0,0 +1,155 @@
+package agent
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func testAgent(t *testing.T, config *Config) (*Agent, string) {
+	dir := t.TempDir()
+	config.LocalStorage = &LocalStorageConfig{Path: dir}
+
+	a, err := New(hclog.NewNullLogger(), config, func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("snapshot")), nil
+	})
+	require.NoError(t, err)
+	return a, dir
+}
+
+func TestAgent_Snapshot(t *testing.T) {
+	ci.Parallel(t)
+
+	a, dir := testAgent(t, &Config{Retain: helper.IntToPtr(2)})
+
+	// Other files of the storage are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nomad-snapshot-other.snap"), nil, 0600))
+
+	now := time.Unix(1000, 0)
+	a.now = func() time.Time { return now }
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		name, err := a.Snapshot(context.Background())
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(name, DefaultNamePrefix+"-"))
+		names = append(names, name)
+		now = now.Add(time.Hour)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, names[2]))
+	require.NoError(t, err)
+	require.Equal(t, "snapshot", string(data))
+
+	// The oldest snapshot is deleted beyond the retention
+	stored, _, err := a.snapshots(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, names[1:], stored)
+	require.FileExists(t, filepath.Join(dir, "nomad-snapshot-other.snap"))
+
+	latest, err := a.latestSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-time.Hour), latest)
+}
+
+func TestAgent_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	a, dir := testAgent(t, &Config{Interval: time.Hour})
+
+	// A snapshot is saved once started if none is stored, and the next one
+	// is due an interval later
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	go func() {
+		a.Run(stopCh)
+		close(doneCh)
+	}()
+
+	require.Eventually(t, func() bool {
+		entries, err := os.ReadDir(dir)
+		return err == nil && len(entries) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	close(stopCh)
+	select {
+	case <-doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot agent did not stop")
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	local := &LocalStorageConfig{Path: "/tmp"}
+	cases := []struct {
+		name   string
+		config *Config
+		err    string
+	}{
+		{name: "valid", config: &Config{LocalStorage: local}},
+		{name: "no storage", config: &Config{}, err: "exactly one storage"},
+		{
+			name:   "two storages",
+			config: &Config{LocalStorage: local, AWSStorage: &AWSStorageConfig{Bucket: "b"}},
+			err:    "exactly one storage",
+		},
+		{name: "negative retain", config: &Config{LocalStorage: local, Retain: helper.IntToPtr(-1)}, err: "retain"},
+		{name: "missing bucket", config: &Config{GoogleStorage: &GoogleStorageConfig{}}, err: "google_storage.bucket"},
+		{
+			name:   "missing account key",
+			config: &Config{AzureStorage: &AzureStorageConfig{AccountName: "a", ContainerName: "c"}},
+			err:    "azure_blob_storage",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &Config{
+		Interval:     time.Hour,
+		Retain:       helper.IntToPtr(5),
+		LocalStorage: &LocalStorageConfig{Path: "/tmp"},
+	}
+	b := &Config{
+		NamePrefix: "backup",
+		AWSStorage: &AWSStorageConfig{Bucket: "bucket"},
+	}
+
+	// The storage of b replaces the storage of a
+	require.Equal(t, &Config{
+		Interval:   time.Hour,
+		Retain:     helper.IntToPtr(5),
+		NamePrefix: "backup",
+		AWSStorage: &AWSStorageConfig{Bucket: "bucket"},
+	}, a.Merge(b))
+
+	require.Equal(t, a, a.Merge(nil))
+	require.Equal(t, b, (*Config)(nil).Merge(b))
+}
@@ -0,0 +1,223 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// DefaultInterval is the default interval between two snapshots.
+	DefaultInterval = time.Hour
+
+	// DefaultRetain is the default number of snapshots retained.
+	DefaultRetain = 30
+
+	// DefaultNamePrefix is the default prefix of the names of snapshots.
+	DefaultNamePrefix = "nomad-snapshot"
+)
+
+// Config configures the snapshot agent of servers, which periodically saves
+// snapshots of the state of the cluster to a storage. Exactly one storage
+// must be configured.
+type Config struct {
+	// Interval is the interval between two snapshots.
+	Interval    time.Duration `hcl:"-"`
+	IntervalHCL string        `hcl:"interval" json:"-"`
+
+	// Retain is the number of snapshots retained, or 0 to retain them all.
+	// The oldest snapshots are deleted once a snapshot is saved.
+	Retain *int `hcl:"retain"`
+
+	// NamePrefix is the prefix of the names of snapshots, which are followed
+	// by the time the snapshot was taken.
+	NamePrefix string `hcl:"name_prefix"`
+
+	// LocalStorage stores snapshots in a directory.
+	LocalStorage *LocalStorageConfig `hcl:"local_storage"`
+
+	// AWSStorage stores snapshots in an S3 bucket.
+	AWSStorage *AWSStorageConfig `hcl:"aws_storage"`
+
+	// GoogleStorage stores snapshots in a Google Cloud Storage bucket.
+	GoogleStorage *GoogleStorageConfig `hcl:"google_storage"`
+
+	// AzureStorage stores snapshots in an Azure Blob Storage container.
+	AzureStorage *AzureStorageConfig `hcl:"azure_blob_storage"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// LocalStorageConfig configures the storage of snapshots in a directory.
+type LocalStorageConfig struct {
+	Path string `hcl:"path"`
+}
+
+// AWSStorageConfig configures the storage of snapshots in an S3 bucket. The
+// credentials default to the credentials of the environment, such as of the
+// instance profile.
+type AWSStorageConfig struct {
+	Bucket          string `hcl:"bucket"`
+	KeyPrefix       string `hcl:"key_prefix"`
+	Region          string `hcl:"region"`
+	Endpoint        string `hcl:"endpoint"`
+	AccessKeyID     string `hcl:"access_key_id"`
+	SecretAccessKey string `hcl:"secret_access_key" json:"-"`
+	ForcePathStyle  bool   `hcl:"force_path_style"`
+}
+
+// GoogleStorageConfig configures the storage of snapshots in a Google Cloud
+// Storage bucket. The credentials default to the application default
+// credentials.
+type GoogleStorageConfig struct {
+	Bucket          string `hcl:"bucket"`
+	KeyPrefix       string `hcl:"key_prefix"`
+	CredentialsFile string `hcl:"credentials_file"`
+}
+
+// AzureStorageConfig configures the storage of snapshots in an Azure Blob
+// Storage container, authenticated with the key of the storage account.
+type AzureStorageConfig struct {
+	AccountName   string `hcl:"account_name"`
+	AccountKey    string `hcl:"account_key" json:"-"`
+	ContainerName string `hcl:"container_name"`
+	KeyPrefix     string `hcl:"key_prefix"`
+
+	// Endpoint is the endpoint of the Blob service, which defaults to the
+	// endpoint of the account in the public cloud.
+	Endpoint string `hcl:"endpoint"`
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c *Config) Validate() error {
+	if c.Interval < 0 {
+		return errors.New("interval must be positive")
+	}
+	if c.Retain != nil && *c.Retain < 0 {
+		return errors.New("retain must be positive")
+	}
+
+	storages := 0
+	if c.LocalStorage != nil {
+		storages++
+		if c.LocalStorage.Path == "" {
+			return errors.New("local_storage.path must be set")
+		}
+	}
+	if c.AWSStorage != nil {
+		storages++
+		if c.AWSStorage.Bucket == "" {
+			return errors.New("aws_storage.bucket must be set")
+		}
+	}
+	if c.GoogleStorage != nil {
+		storages++
+		if c.GoogleStorage.Bucket == "" {
+			return errors.New("google_storage.bucket must be set")
+		}
+	}
+	if c.AzureStorage != nil {
+		storages++
+		az := c.AzureStorage
+		if az.AccountName == "" || az.AccountKey == "" || az.ContainerName == "" {
+			return errors.New("azure_blob_storage.account_name, account_key and container_name must be set")
+		}
+	}
+
+	if storages != 1 {
+		return fmt.Errorf("exactly one storage must be configured, found %d", storages)
+	}
+	return nil
+}
+
+// interval returns the interval between two snapshots.
+func (c *Config) interval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultInterval
+	}
+	return c.Interval
+}
+
+// retain returns the number of snapshots retained, or 0 for all.
+func (c *Config) retain() int {
+	if c.Retain == nil {
+		return DefaultRetain
+	}
+	return *c.Retain
+}
+
+// namePrefix returns the prefix of the names of snapshots.
+func (c *Config) namePrefix() string {
+	if c.NamePrefix == "" {
+		return DefaultNamePrefix
+	}
+	return c.NamePrefix
+}
+
+// Copy returns a deep copy of the configuration.
+func (c *Config) Copy() *Config {
+	if c == nil {
+		return nil
+	}
+
+	nc := *c
+	if c.Retain != nil {
+		nc.Retain = helper.IntToPtr(*c.Retain)
+	}
+	if c.LocalStorage != nil {
+		local := *c.LocalStorage
+		nc.LocalStorage = &local
+	}
+	if c.AWSStorage != nil {
+		aws := *c.AWSStorage
+		nc.AWSStorage = &aws
+	}
+	if c.GoogleStorage != nil {
+		google := *c.GoogleStorage
+		nc.GoogleStorage = &google
+	}
+	if c.AzureStorage != nil {
+		azure := *c.AzureStorage
+		nc.AzureStorage = &azure
+	}
+	nc.ExtraKeysHCL = helper.CopySliceString(c.ExtraKeysHCL)
+	return &nc
+}
+
+// Merge merges two configurations. As only one storage may be configured,
+// the storage of b replaces the storage of c if b configures one.
+func (c *Config) Merge(b *Config) *Config {
+	if c == nil {
+		return b.Copy()
+	}
+
+	result := c.Copy()
+	if b == nil {
+		return result
+	}
+
+	if b.Interval != 0 {
+		result.Interval = b.Interval
+	}
+	if b.IntervalHCL != "" {
+		result.IntervalHCL = b.IntervalHCL
+	}
+	if b.Retain != nil {
+		result.Retain = helper.IntToPtr(*b.Retain)
+	}
+	if b.NamePrefix != "" {
+		result.NamePrefix = b.NamePrefix
+	}
+
+	if b.LocalStorage != nil || b.AWSStorage != nil || b.GoogleStorage != nil || b.AzureStorage != nil {
+		bc := b.Copy()
+		result.LocalStorage = bc.LocalStorage
+		result.AWSStorage = bc.AWSStorage
+		result.GoogleStorage = bc.GoogleStorage
+		result.AzureStorage = bc.AzureStorage
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage stores snapshots by name.
+type Storage interface {
+	// Put stores the snapshot archive read from r as name.
+	Put(ctx context.Context, name string, r io.Reader) error
+
+	// List returns the names of the stored snapshots that start with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// Delete deletes the stored snapshot name.
+	Delete(ctx context.Context, name string) error
+
+	// String describes the storage in logs.
+	String() string
+}
+
+// NewStorage returns the storage configured by config, which must be valid.
+func NewStorage(ctx context.Context, config *Config) (Storage, error) {
+	switch {
+	case config.LocalStorage != nil:
+		return newLocalStorage(config.LocalStorage)
+	case config.AWSStorage != nil:
+		return newAWSStorage(config.AWSStorage)
+	case config.GoogleStorage != nil:
+		return newGoogleStorage(ctx, config.GoogleStorage)
+	case config.AzureStorage != nil:
+		return newAzureStorage(config.AzureStorage)
+	}
+	return nil, fmt.Errorf("no snapshot storage configured")
+}
+
+// localStorage stores snapshots as files in a directory.
+type localStorage struct {
+	dir string
+}
+
+func newLocalStorage(config *LocalStorageConfig) (*localStorage, error) {
+	if err := os.MkdirAll(config.Path, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &localStorage{dir: config.Path}, nil
+}
+
+// Put writes the snapshot to a temporary file first, so that the directory
+// never holds partial snapshots.
+func (s *localStorage) Put(_ context.Context, name string, r io.Reader) error {
+	tmp, err := os.CreateTemp(s.dir, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+func (s *localStorage) List(_ context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), prefix) {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+func (s *localStorage) Delete(_ context.Context, name string) error {
+	err := os.Remove(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *localStorage) String() string {
+	return "local:" + s.dir
+}
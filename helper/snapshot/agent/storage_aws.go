@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// awsStorage stores snapshots as objects of an S3 bucket.
+type awsStorage struct {
+	client    *s3.S3
+	uploader  *s3manager.Uploader
+	bucket    string
+	keyPrefix string
+}
+
+func newAWSStorage(config *AWSStorageConfig) (*awsStorage, error) {
+	awsConfig := aws.NewConfig().WithS3ForcePathStyle(config.ForcePathStyle)
+	if config.Region != "" {
+		awsConfig = awsConfig.WithRegion(config.Region)
+	}
+	if config.Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(config.Endpoint)
+	}
+	if config.AccessKeyID != "" {
+		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(
+			config.AccessKeyID, config.SecretAccessKey, ""))
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	return &awsStorage{
+		client:    s3.New(sess),
+		uploader:  s3manager.NewUploader(sess),
+		bucket:    config.Bucket,
+		keyPrefix: config.KeyPrefix,
+	}, nil
+}
+
+func (s *awsStorage) Put(ctx context.Context, name string, r io.Reader) error {
+	_, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.keyPrefix + name),
+		Body:   r,
+	})
+	return err
+}
+
+func (s *awsStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.keyPrefix + prefix),
+	}
+
+	var names []string
+	err := s.client.ListObjectsV2PagesWithContext(ctx, input, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			names = append(names, strings.TrimPrefix(aws.StringValue(obj.Key), s.keyPrefix))
+		}
+		return true
+	})
+	return names, err
+}
+
+func (s *awsStorage) Delete(ctx context.Context, name string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.keyPrefix + name),
+	})
+	return err
+}
+
+func (s *awsStorage) String() string {
+	return "s3://" + s.bucket + "/" + s.keyPrefix
+}
@@ -0,0 +1,241 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// azureAPIVersion is the version of the Blob service REST API.
+	azureAPIVersion = "2019-12-12"
+
+	// azureBlockSize is the size of the blocks snapshots are uploaded in.
+	azureBlockSize = 4 << 20
+)
+
+// azureStorage stores snapshots as block blobs of an Azure Blob Storage
+// container, using the REST API of the Blob service with Shared Key
+// authorization.
+type azureStorage struct {
+	client    *http.Client
+	endpoint  string
+	account   string
+	key       []byte
+	container string
+	keyPrefix string
+}
+
+func newAzureStorage(config *AzureStorageConfig) (*azureStorage, error) {
+	key, err := base64.StdEncoding.DecodeString(config.AccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Azure account key: %w", err)
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", config.AccountName)
+	}
+
+	return &azureStorage{
+		client:    &http.Client{},
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		account:   config.AccountName,
+		key:       key,
+		container: config.ContainerName,
+		keyPrefix: config.KeyPrefix,
+	}, nil
+}
+
+// Put uploads the snapshot in blocks, as the size of the snapshot is unknown
+// until it is read, and commits the blocks as the blob once all of them are
+// uploaded.
+func (s *azureStorage) Put(ctx context.Context, name string, r io.Reader) error {
+	var blockIDs []string
+	buf := make([]byte, azureBlockSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			id := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", len(blockIDs))))
+			query := url.Values{"comp": {"block"}, "blockid": {id}}
+			if err := s.do(ctx, http.MethodPut, name, query, nil, buf[:n], nil); err != nil {
+				return fmt.Errorf("failed to upload block: %w", err)
+			}
+			blockIDs = append(blockIDs, id)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var blockList bytes.Buffer
+	blockList.WriteString(xml.Header + "<BlockList>")
+	for _, id := range blockIDs {
+		fmt.Fprintf(&blockList, "<Latest>%s</Latest>", id)
+	}
+	blockList.WriteString("</BlockList>")
+
+	header := http.Header{"Content-Type": {"application/xml"}}
+	if err := s.do(ctx, http.MethodPut, name, url.Values{"comp": {"blocklist"}}, header, blockList.Bytes(), nil); err != nil {
+		return fmt.Errorf("failed to commit blocks: %w", err)
+	}
+	return nil
+}
+
+// azureBlobList is a page of the response of the List Blobs operation.
+type azureBlobList struct {
+	Blobs []struct {
+		Name string `xml:"Name"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func (s *azureStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	marker := ""
+	for {
+		query := url.Values{
+			"restype": {"container"},
+			"comp":    {"list"},
+			"prefix":  {s.keyPrefix + prefix},
+		}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+
+		var page azureBlobList
+		if err := s.do(ctx, http.MethodGet, "", query, nil, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, blob := range page.Blobs {
+			names = append(names, strings.TrimPrefix(blob.Name, s.keyPrefix))
+		}
+
+		if page.NextMarker == "" {
+			return names, nil
+		}
+		marker = page.NextMarker
+	}
+}
+
+func (s *azureStorage) Delete(ctx context.Context, name string) error {
+	return s.do(ctx, http.MethodDelete, name, nil, nil, nil, nil)
+}
+
+func (s *azureStorage) String() string {
+	return fmt.Sprintf("azure://%s/%s/%s", s.account, s.container, s.keyPrefix)
+}
+
+// do sends a request for the blob name, or for the container if name is
+// empty, and decodes the XML response into out if it is set.
+func (s *azureStorage) do(ctx context.Context, method, name string, query url.Values, header http.Header, body []byte, out interface{}) error {
+	u := s.endpoint + "/" + s.container
+	if name != "" {
+		u += "/" + url.PathEscape(s.keyPrefix+name)
+	}
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	if method == http.MethodPut && query.Get("comp") == "" {
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+	}
+	req.Header.Set("Authorization", "SharedKey "+s.account+":"+s.sign(req))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodDelete {
+		return nil
+	}
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, msg)
+	}
+
+	if out != nil {
+		return xml.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// sign returns the Shared Key signature of req.
+func (s *azureStorage) sign(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	var msHeaders []string
+	for k := range req.Header {
+		if k := strings.ToLower(k); strings.HasPrefix(k, "x-ms-") {
+			msHeaders = append(msHeaders, k)
+		}
+	}
+	sort.Strings(msHeaders)
+
+	var b strings.Builder
+	for _, v := range []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, set as x-ms-date
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	} {
+		b.WriteString(v + "\n")
+	}
+	for _, k := range msHeaders {
+		b.WriteString(k + ":" + strings.TrimSpace(req.Header.Get(k)) + "\n")
+	}
+
+	b.WriteString("/" + s.account + req.URL.EscapedPath())
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		b.WriteString("\n" + strings.ToLower(k) + ":" + strings.Join(values, ","))
+	}
+
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// googleStorage stores snapshots as objects of a Google Cloud Storage bucket.
+type googleStorage struct {
+	client    *storage.Client
+	bucket    string
+	keyPrefix string
+}
+
+func newGoogleStorage(ctx context.Context, config *GoogleStorageConfig) (*googleStorage, error) {
+	var opts []option.ClientOption
+	if config.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(config.CredentialsFile))
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Cloud Storage client: %w", err)
+	}
+
+	return &googleStorage{
+		client:    client,
+		bucket:    config.Bucket,
+		keyPrefix: config.KeyPrefix,
+	}, nil
+}
+
+func (s *googleStorage) Put(ctx context.Context, name string, r io.Reader) error {
+	// The object is only created once the writer is closed, and canceling
+	// ctx aborts the upload
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := s.client.Bucket(s.bucket).Object(s.keyPrefix + name).NewWriter(ctx)
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (s *googleStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	it := s.client.Bucket(s.bucket).Objects(ctx, &storage.Query{Prefix: s.keyPrefix + prefix})
+
+	var names []string
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, strings.TrimPrefix(attrs.Name, s.keyPrefix))
+	}
+}
+
+func (s *googleStorage) Delete(ctx context.Context, name string) error {
+	err := s.client.Bucket(s.bucket).Object(s.keyPrefix + name).Delete(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil
+	}
+	return err
+}
+
+func (s *googleStorage) Close() error {
+	return s.client.Close()
+}
+
+func (s *googleStorage) String() string {
+	return "gs://" + s.bucket + "/" + s.keyPrefix
+}
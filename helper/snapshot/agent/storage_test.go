@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ci.Parallel(t)
+
+	s, err := NewStorage(context.Background(), &Config{
+		LocalStorage: &LocalStorageConfig{Path: t.TempDir()},
+	})
+	require.NoError(t, err)
+	testStorage(t, s)
+}
+
+func TestAzureStorage(t *testing.T) {
+	ci.Parallel(t)
+
+	// fakeAzure stores the committed blobs of the container and checks that
+	// requests are authorized by the account
+	var lock sync.Mutex
+	blocks := map[string][]byte{}
+	blobs := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey account:") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/container/")
+		query := r.URL.Query()
+		body, _ := io.ReadAll(r.Body)
+
+		switch {
+		case r.Method == http.MethodPut && query.Get("comp") == "block":
+			blocks[query.Get("blockid")] = body
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && query.Get("comp") == "blocklist":
+			var list struct {
+				Latest []string `xml:"Latest"`
+			}
+			require.NoError(t, xml.Unmarshal(body, &list))
+			var blob []byte
+			for _, id := range list.Latest {
+				blob = append(blob, blocks[id]...)
+			}
+			blobs[name] = blob
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && query.Get("comp") == "list":
+			var names []string
+			for name := range blobs {
+				if strings.HasPrefix(name, query.Get("prefix")) {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			fmt.Fprint(w, "<EnumerationResults><Blobs>")
+			for _, name := range names {
+				fmt.Fprintf(w, "<Blob><Name>%s</Name></Blob>", name)
+			}
+			fmt.Fprint(w, "</Blobs><NextMarker/></EnumerationResults>")
+		case r.Method == http.MethodDelete:
+			if _, ok := blobs[name]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(blobs, name)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	s, err := NewStorage(context.Background(), &Config{
+		AzureStorage: &AzureStorageConfig{
+			AccountName:   "account",
+			AccountKey:    base64.StdEncoding.EncodeToString([]byte("key")),
+			ContainerName: "container",
+			KeyPrefix:     "nomad/",
+			Endpoint:      srv.URL,
+		},
+	})
+	require.NoError(t, err)
+	testStorage(t, s)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Contains(t, blobs, "nomad/snap-2")
+}
+
+// testStorage tests the operations of a storage.
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "snap-1", strings.NewReader("1")))
+	require.NoError(t, s.Put(ctx, "snap-2", strings.NewReader("2")))
+	require.NoError(t, s.Put(ctx, "other", strings.NewReader("other")))
+
+	names, err := s.List(ctx, "snap-")
+	require.NoError(t, err)
+	sort.Strings(names)
+	require.Equal(t, []string{"snap-1", "snap-2"}, names)
+
+	require.NoError(t, s.Delete(ctx, "snap-1"))
+	names, err = s.List(ctx, "snap-")
+	require.NoError(t, err)
+	require.Equal(t, []string{"snap-2"}, names)
+
+	// Deleting a missing snapshot is not an error
+	require.NoError(t, s.Delete(ctx, "snap-1"))
+}
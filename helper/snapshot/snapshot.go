@@ -70,18 +70,8 @@ func New(logger hclog.Logger, r *raft.Raft) (*Snapshot, error) {
 
 	hash := sha256.New()
 	out := io.MultiWriter(hash, archive)
-
-	// Wrap the file writer in a gzip compressor.
-	compressor := gzip.NewWriter(out)
-
-	// Write the archive.
-	if err := write(compressor, metadata, snap); err != nil {
-		return nil, fmt.Errorf("failed to write snapshot file: %v", err)
-	}
-
-	// Finish the compressed stream.
-	if err := compressor.Close(); err != nil {
-		return nil, fmt.Errorf("failed to compress snapshot file: %v", err)
+	if err := WriteArchive(out, metadata, snap); err != nil {
+		return nil, err
 	}
 
 	// Sync the compressed file and rewind it so it's ready to be streamed
@@ -99,6 +89,25 @@ func New(logger hclog.Logger, r *raft.Raft) (*Snapshot, error) {
 	return &Snapshot{archive, metadata.Index, checksum}, nil
 }
 
+// WriteArchive writes a compressed snapshot archive to out, holding the
+// metadata and the FSM snapshot read from snap, whose length must be
+// metadata.Size.
+func WriteArchive(out io.Writer, metadata *raft.SnapshotMeta, snap io.Reader) error {
+	// Wrap the writer in a gzip compressor.
+	compressor := gzip.NewWriter(out)
+
+	// Write the archive.
+	if err := write(compressor, metadata, snap); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %v", err)
+	}
+
+	// Finish the compressed stream.
+	if err := compressor.Close(); err != nil {
+		return fmt.Errorf("failed to compress snapshot file: %v", err)
+	}
+	return nil
+}
+
 // Index returns the index of the snapshot. This is safe to call on a nil
 // snapshot, it will just return 0.
 func (s *Snapshot) Index() uint64 {
@@ -132,6 +132,7 @@ func parseConstraints(result *[]*api.Constraint, list *ast.ObjectList) error {
 			"attribute",
 			"distinct_hosts",
 			"distinct_property",
+			"distinct_topology",
 			"operator",
 			"regexp",
 			"set_contains",
@@ -199,6 +200,11 @@ func parseConstraints(result *[]*api.Constraint, list *ast.ObjectList) error {
 			m["LTarget"] = property
 		}
 
+		if topology, ok := m[api.ConstraintDistinctTopology]; ok {
+			m["Operand"] = api.ConstraintDistinctTopology
+			m["LTarget"] = topology
+		}
+
 		// Build the constraint
 		var c api.Constraint
 		if err := mapstructure.WeakDecode(m, &c); err != nil {
@@ -298,6 +304,34 @@ func parseAffinities(result *[]*api.Affinity, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseAntiAffinities(result *[]*api.AntiAffinity, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"job_regexp",
+			"scope",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		// Build the anti-affinity
+		var a api.AntiAffinity
+		if err := mapstructure.WeakDecode(m, &a); err != nil {
+			return err
+		}
+
+		*result = append(*result, &a)
+	}
+
+	return nil
+}
+
 func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -430,6 +464,7 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 		"progress_deadline",
 		"auto_revert",
 		"auto_promote",
+		"promotion_gate",
 		"canary",
 	}
 	if err := checkHCLKeys(o.Val, valid); err != nil {
@@ -42,6 +42,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"constraint",
 			"consul",
 			"affinity",
+			"anti_affinity",
 			"restart",
 			"meta",
 			"task",
@@ -52,12 +53,15 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"migrate",
 			"spread",
 			"shutdown_delay",
+			"shutdown_priority",
+			"priority",
 			"network",
 			"service",
 			"volume",
 			"scaling",
 			"stop_after_client_disconnect",
 			"max_client_disconnect",
+			"mesh",
 		}
 		if err := checkHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -71,6 +75,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "constraint")
 		delete(m, "consul")
 		delete(m, "affinity")
+		delete(m, "anti_affinity")
 		delete(m, "meta")
 		delete(m, "task")
 		delete(m, "restart")
@@ -83,6 +88,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "service")
 		delete(m, "volume")
 		delete(m, "scaling")
+		delete(m, "mesh")
 
 		// Build the group with the basic decode
 		var g api.TaskGroup
@@ -114,6 +120,13 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// Parse mesh
+		if o := listVal.Filter("mesh"); len(o.Items) > 0 {
+			if err := parseMesh(&g.Mesh, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', mesh ->", n))
+			}
+		}
+
 		// Parse affinities
 		if o := listVal.Filter("affinity"); len(o.Items) > 0 {
 			if err := parseAffinities(&g.Affinities, o); err != nil {
@@ -121,6 +134,13 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// Parse anti-affinities
+		if o := listVal.Filter("anti_affinity"); len(o.Items) > 0 {
+			if err := parseAntiAffinities(&g.AntiAffinities, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', anti_affinity ->", n))
+			}
+		}
+
 		// Parse restart policy
 		if o := listVal.Filter("restart"); len(o.Items) > 0 {
 			if err := parseRestartPolicy(&g.RestartPolicy, o); err != nil {
@@ -269,6 +289,72 @@ func parseConsul(result **api.Consul, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseMesh(result **api.Mesh, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'mesh' block allowed")
+	}
+
+	// Get our mesh object
+	obj := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"type",
+		"driver",
+		"resources",
+		"config",
+	}
+	if err := checkHCLKeys(obj.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, obj.Val); err != nil {
+		return err
+	}
+	delete(m, "resources")
+	delete(m, "config")
+
+	var mesh api.Mesh
+	if err := mapstructure.WeakDecode(m, &mesh); err != nil {
+		return err
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("mesh: should be an object")
+	}
+
+	// Parse the sidecar resources
+	if o := listVal.Filter("resources"); len(o.Items) > 0 {
+		var r api.Resources
+		if err := parseResources(&r, o); err != nil {
+			return multierror.Prefix(err, "resources ->")
+		}
+		mesh.Resources = &r
+	}
+
+	// Parse the config passed to the mesh hook
+	if o := listVal.Filter("config"); len(o.Items) > 0 {
+		for _, o := range o.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return err
+			}
+
+			if err := mapstructure.WeakDecode(m, &mesh.Config); err != nil {
+				return err
+			}
+		}
+	}
+
+	*result = &mesh
+	return nil
+}
+
 func parseEphemeralDisk(result **api.EphemeralDisk, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
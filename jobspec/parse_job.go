@@ -44,7 +44,15 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	result.Name = stringToPtr(*result.ID)
 
 	// Decode the rest
-	if err := mapstructure.WeakDecode(m, result); err != nil {
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           result,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
 		return err
 	}
 
@@ -63,6 +71,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"affinity",
 		"spread",
 		"datacenters",
+		"deadline",
 		"group",
 		"id",
 		"meta",
@@ -273,12 +282,14 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 	if err := hcl.DecodeObject(&m, o.Val); err != nil {
 		return err
 	}
+	delete(m, "dispatch")
 
 	// Check for invalid keys
 	valid := []string{
 		"payload",
 		"meta_required",
 		"meta_optional",
+		"dispatch",
 	}
 	if err := checkHCLKeys(o.Val, valid); err != nil {
 		return err
@@ -290,6 +301,55 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 		return err
 	}
 
+	// Parse the dispatch purge policy
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		if do := ot.List.Filter("dispatch"); len(do.Items) > 0 {
+			if err := parseDispatchConfig(&d.Dispatch, do); err != nil {
+				return multierror.Prefix(err, "dispatch ->")
+			}
+		}
+	}
+
+	*result = &d
+	return nil
+}
+
+func parseDispatchConfig(result **api.DispatchConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'dispatch' block allowed")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"purge_after",
+		"keep_last",
+	}
+	if err := checkHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	var d api.DispatchConfig
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &d,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
 	*result = &d
 	return nil
 }
@@ -734,14 +734,17 @@ func parseSidecarTask(item *ast.ObjectItem) (*api.SidecarTask, error) {
 		KillSignal:  task.KillSignal,
 	}
 
-	// Parse ShutdownDelay separatly to get pointer
+	// Parse ShutdownDelay separatly to get pointer, and EnvoyVersion and
+	// ScaleWithUpstreams which are not task fields
 	var m map[string]interface{}
 	if err := hcl.DecodeObject(&m, item.Val); err != nil {
 		return nil, err
 	}
 
 	m = map[string]interface{}{
-		"shutdown_delay": m["shutdown_delay"],
+		"shutdown_delay":       m["shutdown_delay"],
+		"envoy_version":        m["envoy_version"],
+		"scale_with_upstreams": m["scale_with_upstreams"],
 	}
 
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
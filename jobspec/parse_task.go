@@ -34,6 +34,7 @@ var (
 		"dispatch_payload",
 		"lifecycle",
 		"leader",
+		"readiness_file",
 		"restart",
 		"service",
 		"template",
@@ -41,10 +42,14 @@ var (
 		"kind",
 		"volume_mount",
 		"csi_plugin",
+		"process",
+		"identity",
 	)
 
 	sidecarTaskKeys = append(commonTaskKeys,
 		"name",
+		"envoy_version",
+		"scale_with_upstreams",
 	)
 )
 
@@ -113,6 +118,8 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 	delete(m, "volume_mount")
 	delete(m, "csi_plugin")
 	delete(m, "scaling")
+	delete(m, "process")
+	delete(m, "identity")
 
 	// Build the task
 	var t api.Task
@@ -361,9 +368,96 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 			return nil, err
 		}
 	}
+
+	// Parse process
+	if o := listVal.Filter("process"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return nil, fmt.Errorf("only one process block is allowed in a task. Number of process blocks found: %d", len(o.Items))
+		}
+		if err := parseTaskProcess(&t.Process, o.Items[0]); err != nil {
+			return nil, multierror.Prefix(err, "process ->")
+		}
+	}
+
+	// If we have an identity block parse that
+	if o := listVal.Filter("identity"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return nil, fmt.Errorf("only one identity block is allowed in a task. Number of identity blocks found: %d", len(o.Items))
+		}
+
+		var m map[string]interface{}
+		identityBlock := o.Items[0]
+
+		// Check for invalid keys
+		valid := []string{
+			"file",
+			"task_api",
+		}
+		if err := checkHCLKeys(identityBlock.Val, valid); err != nil {
+			return nil, multierror.Prefix(err, "identity ->")
+		}
+
+		if err := hcl.DecodeObject(&m, identityBlock.Val); err != nil {
+			return nil, err
+		}
+
+		t.Identity = &api.WorkloadIdentity{}
+		if err := mapstructure.WeakDecode(m, t.Identity); err != nil {
+			return nil, err
+		}
+	}
 	return &t, nil
 }
 
+func parseTaskProcess(result **api.TaskProcess, item *ast.ObjectItem) error {
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"umask",
+		"nice",
+		"ionice_class",
+		"rlimits",
+	}
+	if err := checkHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, item.Val); err != nil {
+		return err
+	}
+	delete(m, "rlimits")
+
+	var process api.TaskProcess
+	if err := mapstructure.WeakDecode(m, &process); err != nil {
+		return err
+	}
+
+	// Parse rlimits
+	if o := listVal.Filter("rlimits"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return fmt.Errorf("only one rlimits block is allowed")
+		}
+
+		var rm map[string]interface{}
+		if err := hcl.DecodeObject(&rm, o.Items[0].Val); err != nil {
+			return err
+		}
+		if err := mapstructure.WeakDecode(rm, &process.Rlimits); err != nil {
+			return err
+		}
+	}
+
+	*result = &process
+	return nil
+}
+
 func parseArtifacts(result *[]*api.TaskArtifact, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -445,6 +539,8 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 			"source",
 			"splay",
 			"env",
+			"on_render_error",
+			"render_max_stale",
 			"vault_grace", //COMPAT(0.12) not used; emits warning in 0.11.
 		}
 		if err := checkHCLKeys(o.Val, valid); err != nil {
@@ -98,6 +98,7 @@ func TestParse(t *testing.T) {
 					ProgressDeadline: timeToPtr(10 * time.Minute),
 					AutoRevert:       boolToPtr(true),
 					AutoPromote:      boolToPtr(true),
+					PromotionGate:    stringToPtr("error-rate"),
 					Canary:           intToPtr(1),
 				},
 
@@ -202,6 +203,8 @@ func TestParse(t *testing.T) {
 						},
 						StopAfterClientDisconnect: timeToPtr(120 * time.Second),
 						MaxClientDisconnect:       timeToPtr(120 * time.Hour),
+						ShutdownPriority:          intToPtr(-10),
+						Priority:                  intToPtr(30),
 						ReschedulePolicy: &api.ReschedulePolicy{
 							Interval: timeToPtr(12 * time.Hour),
 							Attempts: intToPtr(5),
@@ -374,6 +377,9 @@ func TestParse(t *testing.T) {
 										Perms:      stringToPtr("777"),
 										LeftDelim:  stringToPtr("--"),
 										RightDelim: stringToPtr("__"),
+
+										OnRenderError:  stringToPtr("keep_last"),
+										RenderMaxStale: timeToPtr(10 * time.Minute),
 									},
 								},
 								Leader:     true,
@@ -504,6 +510,37 @@ func TestParse(t *testing.T) {
 			false,
 		},
 
+		{
+			"anti-affinity.hcl",
+			&api.Job{
+				ID:   stringToPtr("foo"),
+				Name: stringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("web"),
+						AntiAffinities: []*api.AntiAffinity{
+							{
+								JobRegexp: "^cache-",
+								Scope:     "node",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+
+		{
+			"deadline.hcl",
+			&api.Job{
+				ID:       stringToPtr("foo"),
+				Name:     stringToPtr("foo"),
+				Type:     stringToPtr("batch"),
+				Deadline: timeToPtr(2 * time.Hour),
+			},
+			false,
+		},
+
 		{
 			"distinctProperty-constraint.hcl",
 			&api.Job{
@@ -519,6 +556,22 @@ func TestParse(t *testing.T) {
 			false,
 		},
 
+		{
+			"distinctTopology-constraint.hcl",
+			&api.Job{
+				ID:   stringToPtr("foo"),
+				Name: stringToPtr("foo"),
+				Constraints: []*api.Constraint{
+					{
+						Operand: api.ConstraintDistinctTopology,
+						LTarget: "${node.datacenter}/${meta.rack}",
+						RTarget: "2",
+					},
+				},
+			},
+			false,
+		},
+
 		{
 			"periodic-cron.hcl",
 			&api.Job{
@@ -626,10 +679,63 @@ func TestParse(t *testing.T) {
 								Name:   "binstore",
 								Driver: "docker",
 								CSIPluginConfig: &api.TaskCSIPluginConfig{
-									ID:            "org.hashicorp.csi",
-									Type:          api.CSIPluginTypeMonolith,
-									MountDir:      "/csi/test",
-									HealthTimeout: 1 * time.Minute,
+									ID:               "org.hashicorp.csi",
+									Type:             api.CSIPluginTypeMonolith,
+									MountDir:         "/csi/test",
+									HealthTimeout:    1 * time.Minute,
+									ProbeInterval:    10 * time.Second,
+									MaxProbeFailures: 5,
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"task-process.hcl",
+			&api.Job{
+				ID:   stringToPtr("batch"),
+				Name: stringToPtr("batch"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "exec",
+								Process: &api.TaskProcess{
+									Umask:       "027",
+									Nice:        10,
+									IOniceClass: "idle",
+									Rlimits: map[string]uint64{
+										"nofile": 65536,
+										"nproc":  4096,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"task-identity.hcl",
+			&api.Job{
+				ID:   stringToPtr("batch"),
+				Name: stringToPtr("batch"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "exec",
+								Identity: &api.WorkloadIdentity{
+									File:    true,
+									TaskAPI: true,
 								},
 							},
 						},
@@ -805,6 +911,10 @@ func TestParse(t *testing.T) {
 					Payload:      "required",
 					MetaRequired: []string{"foo", "bar"},
 					MetaOptional: []string{"baz", "bam"},
+					Dispatch: &api.DispatchConfig{
+						PurgeAfter: timeToPtr(24 * time.Hour),
+						KeepLast:   intToPtr(100),
+					},
 				},
 
 				TaskGroups: []*api.TaskGroup{
@@ -1291,6 +1401,29 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"tg-service-connect-sidecar_task-envoy_version.hcl",
+			&api.Job{
+				ID:   stringToPtr("sidecar_task_envoy_version"),
+				Name: stringToPtr("sidecar_task_envoy_version"),
+				Type: stringToPtr("service"),
+				TaskGroups: []*api.TaskGroup{{
+					Name: stringToPtr("group"),
+					Services: []*api.Service{{
+						Name: "example",
+						Connect: &api.ConsulConnect{
+							Native:         false,
+							SidecarService: &api.ConsulSidecarService{},
+							SidecarTask: &api.SidecarTask{
+								EnvoyVersion:       ">= 1.20, < 1.23",
+								ScaleWithUpstreams: true,
+							},
+						},
+					}},
+				}},
+			},
+			false,
+		},
 		{
 			"tg-service-connect-sidecar_disablecheck.hcl",
 			&api.Job{
@@ -1679,6 +1812,28 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"tg-mesh.hcl",
+			&api.Job{
+				ID:   stringToPtr("mesh"),
+				Name: stringToPtr("mesh"),
+				TaskGroups: []*api.TaskGroup{{
+					Name: stringToPtr("group"),
+					Mesh: &api.Mesh{
+						Type:   "istio",
+						Driver: "podman",
+						Resources: &api.Resources{
+							CPU:      intToPtr(100),
+							MemoryMB: intToPtr(64),
+						},
+						Config: map[string]interface{}{
+							"revision": "stable",
+						},
+					},
+				}},
+			},
+			false,
+		},
 		{
 			"tg-scaling-policy-missing-max.hcl",
 			nil,
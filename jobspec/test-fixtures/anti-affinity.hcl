@@ -0,0 +1,8 @@
+job "foo" {
+  group "web" {
+    anti_affinity {
+      job_regexp = "^cache-"
+      scope      = "node"
+    }
+  }
+}
@@ -52,6 +52,7 @@ job "binstore-storagelocker" {
     progress_deadline = "10m"
     auto_revert       = true
     auto_promote      = true
+    promotion_gate    = "error-rate"
     canary            = 1
   }
 
@@ -159,6 +160,8 @@ job "binstore-storagelocker" {
 
     stop_after_client_disconnect = "120s"
     max_client_disconnect        = "120h"
+    shutdown_priority            = -10
+    priority                     = 30
 
     task "binstore" {
       driver = "docker"
@@ -315,11 +318,13 @@ job "binstore-storagelocker" {
       }
 
       template {
-        source          = "bar"
-        destination     = "bar"
-        perms           = "777"
-        left_delimiter  = "--"
-        right_delimiter = "__"
+        source           = "bar"
+        destination      = "bar"
+        perms            = "777"
+        left_delimiter   = "--"
+        right_delimiter  = "__"
+        on_render_error  = "keep_last"
+        render_max_stale = "10m"
       }
     }
 
@@ -4,10 +4,12 @@ job "binstore-storagelocker" {
       driver = "docker"
 
       csi_plugin {
-        id             = "org.hashicorp.csi"
-        type           = "monolith"
-        mount_dir      = "/csi/test"
-        health_timeout = "1m"
+        id                 = "org.hashicorp.csi"
+        type               = "monolith"
+        mount_dir          = "/csi/test"
+        health_timeout     = "1m"
+        probe_interval     = "10s"
+        max_probe_failures = 5
       }
     }
   }
@@ -0,0 +1,4 @@
+job "foo" {
+  type     = "batch"
+  deadline = "2h"
+}
@@ -0,0 +1,6 @@
+job "foo" {
+  constraint {
+    distinct_topology = "${node.datacenter}/${meta.rack}"
+    value             = "2"
+  }
+}
@@ -3,6 +3,11 @@ job "parameterized_job" {
     payload       = "required"
     meta_required = ["foo", "bar"]
     meta_optional = ["baz", "bam"]
+
+    dispatch {
+      purge_after = "24h"
+      keep_last   = 100
+    }
   }
 
   group "foo" {
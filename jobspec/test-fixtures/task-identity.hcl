@@ -0,0 +1,12 @@
+job "batch" {
+  group "group" {
+    task "task" {
+      driver = "exec"
+
+      identity {
+        file     = true
+        task_api = true
+      }
+    }
+  }
+}
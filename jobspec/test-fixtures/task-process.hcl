@@ -0,0 +1,18 @@
+job "batch" {
+  group "group" {
+    task "task" {
+      driver = "exec"
+
+      process {
+        umask        = "027"
+        nice         = 10
+        ionice_class = "idle"
+
+        rlimits {
+          nofile = 65536
+          nproc  = 4096
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,17 @@
+job "mesh" {
+  group "group" {
+    mesh {
+      type   = "istio"
+      driver = "podman"
+
+      resources {
+        cpu    = 100
+        memory = 64
+      }
+
+      config {
+        revision = "stable"
+      }
+    }
+  }
+}
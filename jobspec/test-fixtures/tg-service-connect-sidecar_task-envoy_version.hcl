@@ -0,0 +1,18 @@
+job "sidecar_task_envoy_version" {
+  type = "service"
+
+  group "group" {
+    service {
+      name = "example"
+
+      connect {
+        sidecar_service {}
+
+        sidecar_task {
+          envoy_version        = ">= 1.20, < 1.23"
+          scale_with_upstreams = true
+        }
+      }
+    }
+  }
+}
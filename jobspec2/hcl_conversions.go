@@ -172,6 +172,7 @@ var constraintSpec = hcldec.ObjectSpec{
 	"operator":  &hcldec.AttrSpec{Name: "operator", Type: cty.String, Required: false},
 
 	api.ConstraintDistinctProperty:  &hcldec.AttrSpec{Name: api.ConstraintDistinctProperty, Type: cty.String, Required: false},
+	api.ConstraintDistinctTopology:  &hcldec.AttrSpec{Name: api.ConstraintDistinctTopology, Type: cty.String, Required: false},
 	api.ConstraintDistinctHosts:     &hcldec.AttrSpec{Name: api.ConstraintDistinctHosts, Type: cty.Bool, Required: false},
 	api.ConstraintRegex:             &hcldec.AttrSpec{Name: api.ConstraintRegex, Type: cty.String, Required: false},
 	api.ConstraintVersion:           &hcldec.AttrSpec{Name: api.ConstraintVersion, Type: cty.String, Required: false},
@@ -240,6 +241,11 @@ func decodeConstraint(body hcl.Body, ctx *hcl.EvalContext, val interface{}) hcl.
 		c.LTarget = property
 	}
 
+	if topology := attr(api.ConstraintDistinctTopology); topology != "" {
+		c.Operand = api.ConstraintDistinctTopology
+		c.LTarget = topology
+	}
+
 	if c.Operand == "" {
 		c.Operand = "="
 	}
@@ -8,7 +8,6 @@ import (
 	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
-	multierror "github.com/hashicorp/go-multierror"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
@@ -140,28 +139,12 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "alloc", "get_alloc"}, time.Now())
 
 	// Check namespace read-job permissions before performing blocking query.
+	// Nodes call this endpoint with their secret ID and may only read their
+	// own allocations.
 	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
-	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	aclObj, node, err := a.srv.resolveACLOrNode(args.AuthToken)
 	if err != nil {
-		// If ResolveToken had an unexpected error return that
-		if err != structs.ErrTokenNotFound {
-			return err
-		}
-
-		// Attempt to lookup AuthToken as a Node.SecretID since nodes
-		// call this endpoint and don't have an ACL token.
-		node, stateErr := a.srv.fsm.State().NodeBySecretID(nil, args.AuthToken)
-		if stateErr != nil {
-			// Return the original ResolveToken error with this err
-			var merr multierror.Error
-			merr.Errors = append(merr.Errors, err, stateErr)
-			return merr.ErrorOrNil()
-		}
-
-		// Not a node or a valid ACL token
-		if node == nil {
-			return structs.ErrTokenNotFound
-		}
+		return err
 	}
 
 	// Setup the blocking query
@@ -183,6 +166,17 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 					return structs.NewErrUnknownAllocation(args.AllocID)
 				}
 
+				if node != nil {
+					ok, err := nodeCanReadAlloc(ws, state, node, out)
+					if err != nil {
+						return err
+					}
+					if !ok {
+						a.srv.auditCrossNodeAccess(node, "Alloc.GetAlloc", "allocation", out.ID)
+						return structs.NewErrUnknownAllocation(args.AllocID)
+					}
+				}
+
 				reply.Index = out.ModifyIndex
 			} else {
 				// Use the last index that affected the allocs table
@@ -215,6 +209,16 @@ func (a *Alloc) GetAllocs(args *structs.AllocsGetRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "alloc", "get_allocs"}, time.Now())
 
+	// Nodes call this endpoint with their secret ID and may only read their
+	// own allocations.
+	aclObj, node, err := a.srv.resolveACLOrNode(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
 	allocs := make([]*structs.Allocation, len(args.AllocIDs))
 
 	// Setup the blocking query. We wait for at least one of the requested
@@ -238,6 +242,17 @@ func (a *Alloc) GetAllocs(args *structs.AllocsGetRequest,
 					break
 				}
 
+				if node != nil {
+					ok, err := nodeCanReadAlloc(ws, state, node, out)
+					if err != nil {
+						return err
+					}
+					if !ok {
+						a.srv.auditCrossNodeAccess(node, "Alloc.GetAllocs", "allocation", out.ID)
+						return structs.NewErrUnknownAllocation(out.ID)
+					}
+				}
+
 				// Store the pointer
 				allocs[i] = out
 
@@ -307,12 +322,84 @@ func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopRes
 		ModifyTime:     now,
 	}
 
+	transition := &structs.DesiredTransition{
+		Migrate:         helper.BoolToPtr(true),
+		NoShutdownDelay: helper.BoolToPtr(args.NoShutdownDelay),
+	}
+
+	// Stopping a pinned allocation explicitly unpins it
+	if alloc.DesiredTransition.ShouldPin() {
+		transition.Pinned = helper.BoolToPtr(false)
+	}
+
+	transitionReq := &structs.AllocUpdateDesiredTransitionRequest{
+		Evals: []*structs.Evaluation{eval},
+		Allocs: map[string]*structs.DesiredTransition{
+			args.AllocID: transition,
+		},
+	}
+
+	// Commit this update via Raft
+	_, index, err := a.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, transitionReq)
+	if err != nil {
+		a.logger.Error("AllocUpdateDesiredTransitionRequest failed", "error", err)
+		return err
+	}
+
+	// Setup the response
+	reply.Index = index
+	reply.EvalID = eval.ID
+	return nil
+}
+
+// Pin is used to pin an allocation to its node, preventing it from being
+// migrated off a draining node or replaced while its node is down, or to unpin
+// it.
+func (a *Alloc) Pin(args *structs.AllocPinRequest, reply *structs.AllocPinResponse) error {
+	if done, err := a.srv.forward("Alloc.Pin", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "pin"}, time.Now())
+
+	alloc, err := getAlloc(a.srv.State(), args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	// Check for namespace alloc-lifecycle permissions.
+	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityAllocLifecycle)
+	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if !allowNsOp(aclObj, alloc.Namespace) {
+		return structs.ErrPermissionDenied
+	}
+
+	if alloc.TerminalStatus() {
+		return fmt.Errorf("cannot pin terminal allocation %q", alloc.ID)
+	}
+
+	// Evaluate the job so that an unpinned allocation on a draining or down
+	// node is migrated.
+	now := time.Now().UTC().UnixNano()
+	eval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      alloc.Namespace,
+		Priority:       alloc.Job.Priority,
+		Type:           alloc.Job.Type,
+		TriggeredBy:    structs.EvalTriggerAllocPin,
+		JobID:          alloc.Job.ID,
+		JobModifyIndex: alloc.Job.ModifyIndex,
+		Status:         structs.EvalStatusPending,
+		CreateTime:     now,
+		ModifyTime:     now,
+	}
+
 	transitionReq := &structs.AllocUpdateDesiredTransitionRequest{
 		Evals: []*structs.Evaluation{eval},
 		Allocs: map[string]*structs.DesiredTransition{
 			args.AllocID: {
-				Migrate:         helper.BoolToPtr(true),
-				NoShutdownDelay: helper.BoolToPtr(args.NoShutdownDelay),
+				Pinned: helper.BoolToPtr(args.Pinned),
 			},
 		},
 	}
@@ -374,10 +461,17 @@ func (a *Alloc) GetServiceRegistrations(
 	defer metrics.MeasureSince([]string{"nomad", "alloc", "get_service_registrations"}, time.Now())
 
 	// If ACLs are enabled, ensure the caller has the read-job namespace
-	// capability.
+	// capability. A workload can also read the registrations of its own
+	// allocation using its workload identity.
 	aclObj, err := a.srv.ResolveToken(args.AuthToken)
 	if err != nil {
-		return err
+		if helper.IsUUID(args.AuthToken) {
+			return err
+		}
+		claims, claimErr := a.srv.VerifyClaim(args.AuthToken)
+		if claimErr != nil || claims.AllocationID != args.AllocID {
+			return structs.ErrPermissionDenied
+		}
 	} else if aclObj != nil {
 		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 			return structs.ErrPermissionDenied
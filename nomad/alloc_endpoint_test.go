@@ -824,6 +824,7 @@ func TestAllocEndpoint_GetAlloc_ACL(t *testing.T) {
 			Name: "valid-node-secret",
 			F: func(t *testing.T) {
 				node := mock.Node()
+				node.ID = alloc.NodeID
 				assert.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 1005, node))
 				get := getReq()
 				get.AuthToken = node.SecretID
@@ -835,6 +836,21 @@ func TestAllocEndpoint_GetAlloc_ACL(t *testing.T) {
 			},
 		},
 
+		// Try with the Node.SecretID of another node
+		{
+			Name: "other-node-secret",
+			F: func(t *testing.T) {
+				node := mock.Node()
+				assert.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 1006, node))
+				get := getReq()
+				get.AuthToken = node.SecretID
+				get.AllocID = alloc.ID
+				var resp structs.SingleAllocResponse
+				err := msgpackrpc.CallWithCodec(codec, "Alloc.GetAlloc", get, &resp)
+				require.True(t, structs.IsErrUnknownAllocation(err), "expected unknown alloc but found: %v", err)
+			},
+		},
+
 		// Try with a invalid token
 		{
 			Name: "invalid-token",
@@ -973,6 +989,48 @@ func TestAllocEndpoint_GetAllocs(t *testing.T) {
 	}
 }
 
+func TestAllocEndpoint_GetAllocs_NodeIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	other := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 997, node))
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 998, other))
+
+	// The alloc of the other node is replaced by an alloc of the node
+	prev := mock.Alloc()
+	prev.NodeID = other.ID
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.PreviousAllocation = prev.ID
+	unrelated := mock.Alloc()
+	unrelated.NodeID = other.ID
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Allocation{prev, alloc, unrelated}))
+
+	get := &structs.AllocsGetRequest{
+		AllocIDs: []string{alloc.ID, prev.ID},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: node.SecretID,
+		},
+	}
+	var resp structs.AllocsGetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.GetAllocs", get, &resp))
+	require.Len(t, resp.Allocs, 2)
+
+	// The allocs of other nodes can't be read
+	get.AllocIDs = []string{alloc.ID, unrelated.ID}
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.GetAllocs", get, &resp)
+	require.True(t, structs.IsErrUnknownAllocation(err), "expected unknown alloc but found: %v", err)
+}
+
 func TestAllocEndpoint_GetAllocs_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1175,6 +1233,64 @@ func TestAllocEndpoint_Stop_ACL(t *testing.T) {
 	require.True(*out2.DesiredTransition.Migrate)
 }
 
+func TestAllocEndpoint_Pin(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	alloc := mock.Alloc()
+	state := s1.fsm.State()
+	require.Nil(state.UpsertJobSummary(998, mock.JobSummary(alloc.JobID)))
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	req := &structs.AllocPinRequest{
+		AllocID: alloc.ID,
+		Pinned:  true,
+	}
+	req.Namespace = structs.DefaultNamespace
+	req.Region = alloc.Job.Region
+
+	// Try without permissions
+	var resp structs.AllocPinResponse
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.Pin", req, &resp)
+	require.True(structs.IsErrPermissionDenied(err), "expected permissions error, got: %v", err)
+
+	// Try with alloc-lifecycle permissions
+	validToken := mock.CreatePolicyAndToken(t, state, 1002, "valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+	req.WriteRequest.AuthToken = validToken.SecretID
+	var resp2 structs.AllocPinResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Alloc.Pin", req, &resp2))
+	require.NotZero(resp2.Index)
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.True(out.DesiredTransition.ShouldPin())
+	eval, err := state.EvalByID(nil, resp2.EvalID)
+	require.Nil(err)
+	require.NotNil(eval)
+	require.Equal(structs.EvalTriggerAllocPin, eval.TriggeredBy)
+
+	// Stopping the alloc unpins it
+	stopReq := &structs.AllocStopRequest{
+		AllocID: alloc.ID,
+	}
+	stopReq.Namespace = structs.DefaultNamespace
+	stopReq.Region = alloc.Job.Region
+	stopReq.AuthToken = validToken.SecretID
+	var stopResp structs.AllocStopResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Alloc.Stop", stopReq, &stopResp))
+
+	out, err = state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.False(out.DesiredTransition.ShouldPin())
+	require.True(out.DesiredTransition.ShouldMigrate())
+}
+
 func TestAllocEndpoint_List_AllNamespaces_ACL_OSS(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1660,6 +1776,50 @@ func TestAlloc_GetServiceRegistrations(t *testing.T) {
 			},
 			name: "ACLs enabled use incorrect capability",
 		},
+		{
+			serverFn: func(t *testing.T) (*Server, *structs.ACLToken, func()) {
+				return TestACLServer(t, nil)
+			},
+			testFn: func(t *testing.T, s *Server, _ *structs.ACLToken) {
+				codec := rpcClient(t, s)
+				testutil.WaitForLeader(t, s.RPC)
+
+				err, allocID, service := correctSetupFn(s)
+				require.NoError(t, err)
+
+				alloc, err := s.State().AllocByID(nil, allocID)
+				require.NoError(t, err)
+				idToken, err := s.encrypter.SignClaims(alloc.ToTaskIdentityClaims(nil, "web"))
+				require.NoError(t, err)
+
+				// Perform a lookup using the workload identity of the
+				// allocation.
+				serviceRegReq := &structs.AllocServiceRegistrationsRequest{
+					AllocID: allocID,
+					QueryOptions: structs.QueryOptions{
+						Namespace: service.Namespace,
+						Region:    s.Region(),
+						AuthToken: idToken,
+					},
+				}
+				var serviceRegResp structs.AllocServiceRegistrationsResponse
+				err = msgpackrpc.CallWithCodec(codec, structs.AllocServiceRegistrationsRPCMethod, serviceRegReq, &serviceRegResp)
+				require.NoError(t, err)
+				require.ElementsMatch(t, []*structs.ServiceRegistration{service}, serviceRegResp.Services)
+
+				// The workload identity of another allocation is rejected.
+				other := mock.Alloc()
+				require.NoError(t, s.State().UpsertAllocs(structs.MsgTypeTestSetup, 30, []*structs.Allocation{other}))
+				otherToken, err := s.encrypter.SignClaims(other.ToTaskIdentityClaims(nil, "web"))
+				require.NoError(t, err)
+
+				serviceRegReq.AuthToken = otherToken
+				err = msgpackrpc.CallWithCodec(codec, structs.AllocServiceRegistrationsRPCMethod, serviceRegReq, &serviceRegResp)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "Permission denied")
+			},
+			name: "ACLs enabled use workload identity",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -90,7 +90,18 @@ func (d *AutopilotDelegate) PromoteNonVoters(conf *autopilot.Config, health auto
 		return nil, fmt.Errorf("failed to get raft configuration: %v", err)
 	}
 
-	return autopilot.PromoteStableServers(conf, health, future.Configuration().Servers), nil
+	// Read replicas must remain non-voters
+	d.server.peerLock.RLock()
+	servers := make([]raft.Server, 0, len(future.Configuration().Servers))
+	for _, server := range future.Configuration().Servers {
+		if parts, ok := d.server.localPeers[server.Address]; ok && parts.ReadReplica {
+			continue
+		}
+		servers = append(servers, server)
+	}
+	d.server.peerLock.RUnlock()
+
+	return autopilot.PromoteStableServers(conf, health, servers), nil
 }
 
 func (d *AutopilotDelegate) Raft() *raft.Raft {
@@ -379,3 +379,53 @@ func TestAutopilot_PromoteNonVoter(t *testing.T) {
 		}
 	})
 }
+
+func TestAutopilot_ReadReplicaNotPromoted(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.RaftConfig.ProtocolVersion = 3
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 0
+		c.RaftConfig.ProtocolVersion = 3
+		c.ReadReplica = true
+		c.NonVoter = true
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+
+	// Wait until the replica has been stable for long enough to have been
+	// promoted if it were a regular server.
+	retry.Run(t, func(r *retry.R) {
+		future := s1.raft.GetConfiguration()
+		if err := future.Error(); err != nil {
+			r.Fatal(err)
+		}
+
+		servers := future.Configuration().Servers
+		if len(servers) != 2 {
+			r.Fatalf("bad: %v", servers)
+		}
+		health := s1.autopilot.GetServerHealth(string(servers[1].ID))
+		if health == nil || !health.Healthy {
+			r.Fatalf("bad: %v", health)
+		}
+		if time.Since(health.StableSince) < 3*s1.config.AutopilotConfig.ServerStabilizationTime {
+			r.Fatal("stable period not elapsed")
+		}
+	})
+
+	future := s1.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		t.Fatal(err)
+	}
+	for _, server := range future.Configuration().Servers {
+		if server.ID == raft.ServerID(s2.config.NodeID) && server.Suffrage != raft.Nonvoter {
+			t.Fatalf("read replica was promoted: %v", server)
+		}
+	}
+}
@@ -732,6 +732,7 @@ func (b *BlockedEvals) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 					{Name: "datacenter", Value: k.dc},
 					{Name: "node_class", Value: k.class},
 				}
+				metrics.SetGaugeWithLabels([]string{"nomad", "blocked_evals", "count"}, float32(v.Evals), labels)
 				metrics.SetGaugeWithLabels([]string{"nomad", "blocked_evals", "cpu"}, float32(v.CPU), labels)
 				metrics.SetGaugeWithLabels([]string{"nomad", "blocked_evals", "memory"}, float32(v.MemoryMB), labels)
 			}
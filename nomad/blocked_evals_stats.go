@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"sort"
 	"time"
 
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -80,6 +81,7 @@ func generateResourceStats(eval *structs.Evaluation) *BlockedResourcesStats {
 
 	resources := BlockedResourcesSummary{
 		Timestamp: time.Now().UTC(),
+		Evals:     1,
 	}
 
 	for _, allocMetrics := range eval.FailedTGAllocs {
@@ -182,6 +184,7 @@ func (b *BlockedResourcesStats) Subtract(a *BlockedResourcesStats) *BlockedResou
 // BlockedResourcesSummary stores resource values for blocked evals.
 type BlockedResourcesSummary struct {
 	Timestamp time.Time
+	Evals     int
 	CPU       int
 	MemoryMB  int
 }
@@ -191,6 +194,7 @@ type BlockedResourcesSummary struct {
 func (b BlockedResourcesSummary) Add(a BlockedResourcesSummary) BlockedResourcesSummary {
 	return BlockedResourcesSummary{
 		Timestamp: a.Timestamp,
+		Evals:     b.Evals + a.Evals,
 		CPU:       b.CPU + a.CPU,
 		MemoryMB:  b.MemoryMB + a.MemoryMB,
 	}
@@ -201,6 +205,7 @@ func (b BlockedResourcesSummary) Add(a BlockedResourcesSummary) BlockedResources
 func (b BlockedResourcesSummary) Subtract(a BlockedResourcesSummary) BlockedResourcesSummary {
 	return BlockedResourcesSummary{
 		Timestamp: a.Timestamp,
+		Evals:     b.Evals - a.Evals,
 		CPU:       b.CPU - a.CPU,
 		MemoryMB:  b.MemoryMB - a.MemoryMB,
 	}
@@ -208,5 +213,32 @@ func (b BlockedResourcesSummary) Subtract(a BlockedResourcesSummary) BlockedReso
 
 // IsZero returns true if all resource values are zero.
 func (b BlockedResourcesSummary) IsZero() bool {
-	return b.CPU == 0 && b.MemoryMB == 0
+	return b.Evals == 0 && b.CPU == 0 && b.MemoryMB == 0
+}
+
+// Demand returns the resources requested by blocked evaluations by
+// datacenter and node class, sorted by datacenter and node class.
+func (b *BlockedResourcesStats) Demand() []*structs.BlockedResourcesDemand {
+	demand := make([]*structs.BlockedResourcesDemand, 0, len(b.ByClassInDC))
+	for k, v := range b.ByClassInDC {
+		// Entries are pruned lazily once no eval is blocked on them
+		if v.IsZero() {
+			continue
+		}
+		demand = append(demand, &structs.BlockedResourcesDemand{
+			Datacenter: k.dc,
+			NodeClass:  k.class,
+			Evals:      v.Evals,
+			CPU:        v.CPU,
+			MemoryMB:   v.MemoryMB,
+		})
+	}
+
+	sort.Slice(demand, func(i, j int) bool {
+		if demand[i].Datacenter != demand[j].Datacenter {
+			return demand[i].Datacenter < demand[j].Datacenter
+		}
+		return demand[i].NodeClass < demand[j].NodeClass
+	})
+	return demand
 }
@@ -256,6 +256,24 @@ func TestBlockedResourcesStats_Add_NoClass(t *testing.T) {
 	}, result.ByClassInDC)
 }
 
+func TestBlockedResourcesStats_Demand(t *testing.T) {
+	a := NewBlockedResourcesStats()
+	a.ByClassInDC = map[classInDC]BlockedResourcesSummary{
+		node2:                      {Timestamp: now(1), Evals: 2, CPU: 300, MemoryMB: 400},
+		node1:                      {Timestamp: now(1), Evals: 1, CPU: 100, MemoryMB: 200},
+		node3:                      {Timestamp: now(1)},
+		{dc: "dc0", class: "beta"}: {Timestamp: now(1), Evals: 1, CPU: 500, MemoryMB: 600},
+	}
+
+	// Zero entries are skipped, and the demand is sorted by datacenter and
+	// node class
+	require.Equal(t, []*structs.BlockedResourcesDemand{
+		{Datacenter: "dc0", NodeClass: "beta", Evals: 1, CPU: 500, MemoryMB: 600},
+		{Datacenter: "dc1", NodeClass: "alpha", Evals: 1, CPU: 100, MemoryMB: 200},
+		{Datacenter: "dc1", NodeClass: "beta", Evals: 2, CPU: 300, MemoryMB: 400},
+	}, a.Demand())
+}
+
 func TestBlockedResourcesStats_Subtract(t *testing.T) {
 	a := NewBlockedResourcesStats()
 	a.ByJob = map[structs.NamespacedID]BlockedResourcesSummary{
@@ -30,6 +30,27 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client_stats", "stats"}, time.Now())
 
+	return s.nodeRPC("ClientStats.Stats", args, reply)
+}
+
+// Inventory is used to retrieve the tasks running on a client.
+func (s *ClientStats) Inventory(args *nstructs.NodeSpecificRequest, reply *structs.ClientInventoryResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := s.srv.forward("ClientStats.Inventory", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_stats", "inventory"}, time.Now())
+
+	return s.nodeRPC("ClientStats.Inventory", args, reply)
+}
+
+// nodeRPC checks the node read permissions of the request and makes the RPC
+// to the node, through the server connected to it if needed.
+func (s *ClientStats) nodeRPC(method string, args *nstructs.NodeSpecificRequest, reply interface{}) error {
 	// Check node read permissions
 	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
@@ -68,9 +89,9 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 			return nstructs.ErrNoNodeConn
 		}
 
-		return s.srv.forwardServer(srv, "ClientStats.Stats", args, reply)
+		return s.srv.forwardServer(srv, method, args, reply)
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "ClientStats.Stats", args, reply)
+	return NodeRpc(state.Session, method, args, reply)
 }
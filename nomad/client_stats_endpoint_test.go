@@ -58,6 +58,45 @@ func TestClientStats_Stats_Local(t *testing.T) {
 	require.NotNil(resp2.HostStats)
 }
 
+func TestClientStats_Inventory_Local(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	req := &structs.NodeSpecificRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp cstructs.ClientInventoryResponse
+	err := msgpackrpc.CallWithCodec(codec, "ClientStats.Inventory", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing")
+
+	// Fetch the response setting the node id
+	req.NodeID = c.NodeID()
+	var resp2 cstructs.ClientInventoryResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ClientStats.Inventory", req, &resp2))
+	require.NotNil(t, resp2.Inventory)
+	require.Equal(t, c.NodeID(), resp2.Inventory.NodeID)
+	require.Empty(t, resp2.Inventory.Tasks)
+}
+
 func TestClientStats_Stats_Local_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/memberlist"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	snapshotagent "github.com/hashicorp/nomad/helper/snapshot/agent"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -101,6 +102,17 @@ type Config struct {
 	// as a voting member of the Raft cluster.
 	NonVoter bool
 
+	// ReadReplica marks this server as a read replica. A read replica is a
+	// non-voting server that does not run schedulers and is never promoted
+	// to a voter by autopilot. Voters in the region route stale reads and
+	// stale event streams to read replicas to offload the quorum.
+	ReadReplica bool
+
+	// RequireNodeIntroToken is whether clients must present a node
+	// introduction token to register for the first time. Once registered,
+	// clients must present their node secret ID with their heartbeats.
+	RequireNodeIntroToken bool
+
 	// (Enterprise-only) RedundancyZone is the redundancy zone to use for this server.
 	RedundancyZone string
 
@@ -154,6 +166,18 @@ type Config struct {
 	// for GC. This gives users some time to debug a failed evaluation.
 	EvalGCThreshold time.Duration
 
+	// EvalGCMaxPerJob is the maximum number of terminal evaluations retained
+	// per job, counting the evaluations of dispatched and periodic child jobs
+	// against their parent. Older evaluations are eligible for GC regardless
+	// of EvalGCThreshold. Zero means no limit.
+	EvalGCMaxPerJob int
+
+	// AllocGCMaxPerJob is the maximum number of terminal allocations
+	// retained per job, counting the allocations of dispatched and periodic
+	// child jobs against their parent. Older allocations are eligible for GC
+	// regardless of EvalGCThreshold. Zero means no limit.
+	AllocGCMaxPerJob int
+
 	// JobGCInterval is how often we dispatch a job to GC jobs that are
 	// available for garbage collection.
 	JobGCInterval time.Duration
@@ -162,6 +186,15 @@ type Config struct {
 	// the user time to inspect the job.
 	JobGCThreshold time.Duration
 
+	// JobTrashWindow is how long purged jobs are kept in the job trash,
+	// where they can be restored, before they are permanently deleted. Zero
+	// disables the job trash.
+	JobTrashWindow time.Duration
+
+	// JobTrashGCInterval is how often we dispatch a job to delete the
+	// expired jobs from the job trash.
+	JobTrashGCInterval time.Duration
+
 	// NodeGCInterval is how often we dispatch a job to GC failed nodes.
 	NodeGCInterval time.Duration
 
@@ -196,6 +229,10 @@ type Config struct {
 	// one-time tokens.
 	OneTimeTokenGCInterval time.Duration
 
+	// NodeIntroTokenGCInterval is how often we dispatch a job to GC node
+	// introduction tokens.
+	NodeIntroTokenGCInterval time.Duration
+
 	// RootKeyGCInterval is how often we dispatch a job to GC
 	// encryption key metadata
 	RootKeyGCInterval time.Duration
@@ -369,6 +406,11 @@ type Config struct {
 	// RaftBoltNoFreelistSync configures whether freelist syncing is enabled.
 	RaftBoltNoFreelistSync bool
 
+	// RPCCompression enables zstd compression of cross-region RPC
+	// connections. It only takes effect when mTLS is enabled for RPC, since
+	// compressed connections are only accepted from authenticated peers.
+	RPCCompression bool
+
 	// AgentShutdown is used to call agent.Shutdown from the context of a Server
 	// It is used primarily for licensing
 	AgentShutdown func() error
@@ -376,6 +418,15 @@ type Config struct {
 	// DeploymentQueryRateLimit is in queries per second and is used by the
 	// DeploymentWatcher to throttle the amount of simultaneously deployments
 	DeploymentQueryRateLimit float64
+
+	// SnapshotAgentConfig configures the leader to periodically save
+	// snapshots of the state to a storage, if set.
+	SnapshotAgentConfig *snapshotagent.Config
+
+	// PromotionGates are the gates task groups can select to decide on the
+	// automatic promotion of their canaries. They are registered with the
+	// DeploymentWatcher.
+	PromotionGates []deploymentwatcher.PromotionGate
 }
 
 // DefaultConfig returns the default configuration. Only used as the basis for
@@ -403,6 +454,7 @@ func DefaultConfig() *Config {
 		EvalGCThreshold:                  1 * time.Hour,
 		JobGCInterval:                    5 * time.Minute,
 		JobGCThreshold:                   4 * time.Hour,
+		JobTrashGCInterval:               5 * time.Minute,
 		NodeGCInterval:                   5 * time.Minute,
 		NodeGCThreshold:                  24 * time.Hour,
 		DeploymentGCInterval:             5 * time.Minute,
@@ -412,6 +464,7 @@ func DefaultConfig() *Config {
 		CSIVolumeClaimGCInterval:         5 * time.Minute,
 		CSIVolumeClaimGCThreshold:        5 * time.Minute,
 		OneTimeTokenGCInterval:           10 * time.Minute,
+		NodeIntroTokenGCInterval:         10 * time.Minute,
 		RootKeyGCInterval:                10 * time.Minute,
 		RootKeyGCThreshold:               1 * time.Hour,
 		RootKeyRotationThreshold:         720 * time.Hour, // 30 days
@@ -485,3 +538,10 @@ func DefaultConfig() *Config {
 
 	return c
 }
+
+// RPCCompressionEnabled returns whether the server advertises and accepts
+// zstd compressed RPC connections. Compression requires RPC TLS so that the
+// decoder is only ever exposed to peers that presented a valid certificate.
+func (c *Config) RPCCompressionEnabled() bool {
+	return c.RPCCompression && c.TLSConfig != nil && c.TLSConfig.EnableRPC
+}
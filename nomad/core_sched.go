@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -52,6 +53,10 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.csiPluginGC(eval)
 	case structs.CoreJobOneTimeTokenGC:
 		return c.expiredOneTimeTokenGC(eval)
+	case structs.CoreJobNodeIntroTokenGC:
+		return c.expiredNodeIntroTokenGC(eval)
+	case structs.CoreJobJobTrashGC:
+		return c.expiredJobTrashGC(eval)
 	case structs.CoreJobRootKeyRotateOrGC:
 		return c.rootKeyRotateOrGC(eval)
 	case structs.CoreJobSecureVariablesRekey:
@@ -83,6 +88,12 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.expiredOneTimeTokenGC(eval); err != nil {
 		return err
 	}
+	if err := c.expiredNodeIntroTokenGC(eval); err != nil {
+		return err
+	}
+	if err := c.expiredJobTrashGC(eval); err != nil {
+		return err
+	}
 	if err := c.rootKeyRotateOrGC(eval); err != nil {
 		return err
 	}
@@ -103,16 +114,32 @@ func (c *CoreScheduler) jobGC(eval *structs.Evaluation) error {
 	oldThreshold := c.getThreshold(eval, "job",
 		"job_gc_threshold", c.srv.config.JobGCThreshold)
 
+	var jobs []*structs.Job
+	for i := iter.Next(); i != nil; i = iter.Next() {
+		jobs = append(jobs, i.(*structs.Job))
+	}
+
+	// Dispatched jobs of parameterized jobs with a dispatch block are purged
+	// according to the policy of their parent
+	dispatchThresholds, err := c.dispatchPurgeThresholds(eval, jobs, oldThreshold)
+	if err != nil {
+		return err
+	}
+
 	// Collect the allocations, evaluations and jobs to GC
 	var gcAlloc, gcEval []string
 	var gcJob []*structs.Job
 
 OUTER:
-	for i := iter.Next(); i != nil; i = iter.Next() {
-		job := i.(*structs.Job)
+	for _, job := range jobs {
+		threshold, index := oldThreshold, job.CreateIndex
+		if t, ok := dispatchThresholds[job.NamespacedID()]; ok {
+			// Dispatched jobs are aged from the time they completed
+			threshold, index = t, job.ModifyIndex
+		}
 
 		// Ignore new jobs.
-		if job.CreateIndex > oldThreshold {
+		if index > threshold {
 			continue
 		}
 
@@ -126,7 +153,7 @@ OUTER:
 		allEvalsGC := true
 		var jobAlloc, jobEval []string
 		for _, eval := range evals {
-			gc, allocs, err := c.gcEval(eval, oldThreshold, true)
+			gc, allocs, err := c.gcEval(eval, threshold, true)
 			if err != nil {
 				continue OUTER
 			} else if gc {
@@ -164,6 +191,67 @@ OUTER:
 	return c.jobReap(gcJob, eval.LeaderACL)
 }
 
+// dispatchPurgeThresholds returns the index before which each dead dispatched
+// job whose parameterized parent has a dispatch block may be purged. Jobs past
+// the keep_last most recently completed jobs of their parent may be purged
+// regardless of their age.
+func (c *CoreScheduler) dispatchPurgeThresholds(eval *structs.Evaluation, jobs []*structs.Job, oldThreshold uint64) (map[structs.NamespacedID]uint64, error) {
+	thresholds := make(map[structs.NamespacedID]uint64)
+
+	// A forced GC purges everything eligible
+	if eval.JobID == structs.CoreJobForceGC {
+		return thresholds, nil
+	}
+
+	policies := make(map[structs.NamespacedID]*structs.DispatchConfig)
+	children := make(map[structs.NamespacedID][]*structs.Job)
+	for _, job := range jobs {
+		if !job.Dispatched || job.ParentID == "" || job.Status != structs.JobStatusDead {
+			continue
+		}
+
+		key := structs.NamespacedID{ID: job.ParentID, Namespace: job.Namespace}
+		policy, ok := policies[key]
+		if !ok {
+			parent, err := c.snap.JobByID(nil, job.Namespace, job.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			if parent != nil && parent.IsParameterized() {
+				policy = parent.ParameterizedJob.Dispatch
+			}
+			policies[key] = policy
+		}
+		if policy == nil {
+			continue
+		}
+		children[key] = append(children[key], job)
+	}
+
+	for key, jobs := range children {
+		policy := policies[key]
+
+		threshold := oldThreshold
+		if policy.PurgeAfter > 0 {
+			threshold = c.getThreshold(eval, "dispatched job", "purge_after", policy.PurgeAfter)
+		}
+
+		// Keep the most recently completed jobs
+		sort.Slice(jobs, func(i, j int) bool {
+			return jobs[i].ModifyIndex > jobs[j].ModifyIndex
+		})
+		for i, job := range jobs {
+			if policy.KeepLast > 0 && i >= policy.KeepLast {
+				thresholds[job.NamespacedID()] = math.MaxUint64
+			} else {
+				thresholds[job.NamespacedID()] = threshold
+			}
+		}
+	}
+
+	return thresholds, nil
+}
+
 // jobReap contacts the leader and issues a reap on the passed jobs
 func (c *CoreScheduler) jobReap(jobs []*structs.Job, leaderACL string) error {
 	// Call to the leader to issue the reap
@@ -228,14 +316,27 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 	oldThreshold := c.getThreshold(eval, "eval",
 		"eval_gc_threshold", c.srv.config.EvalGCThreshold)
 
+	// Find the evaluations and allocations exceeding the per job limits,
+	// which are eligible for GC regardless of their age
+	excessEvals, excessAllocs, err := c.excessPerJob()
+	if err != nil {
+		return err
+	}
+
 	// Collect the allocations and evaluations to GC
 	var gcAlloc, gcEval []string
+	gcAllocSet := make(map[string]struct{})
 	for raw := iter.Next(); raw != nil; raw = iter.Next() {
 		eval := raw.(*structs.Evaluation)
 
 		// The Evaluation GC should not handle batch jobs since those need to be
-		// garbage collected in one shot
-		gc, allocs, err := c.gcEval(eval, oldThreshold, false)
+		// garbage collected in one shot, unless the evaluation exceeds the
+		// limit of its job and the job is dead
+		threshold, allowBatch := oldThreshold, false
+		if _, ok := excessEvals[eval.ID]; ok {
+			threshold, allowBatch = math.MaxUint64, true
+		}
+		gc, allocs, err := c.gcEval(eval, threshold, allowBatch)
 		if err != nil {
 			return err
 		}
@@ -243,9 +344,32 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 		if gc {
 			gcEval = append(gcEval, eval.ID)
 		}
+		for _, id := range allocs {
+			gcAllocSet[id] = struct{}{}
+		}
 		gcAlloc = append(gcAlloc, allocs...)
 	}
 
+	for _, alloc := range excessAllocs {
+		if _, ok := gcAllocSet[alloc.ID]; ok {
+			continue
+		}
+
+		job, err := c.snap.JobByID(nil, alloc.Namespace, alloc.JobID)
+		if err != nil {
+			return err
+		}
+
+		// Terminal allocations of running batch jobs are kept since the
+		// scheduler would otherwise run them again
+		if job != nil && job.Type == structs.JobTypeBatch && job.Status != structs.JobStatusDead {
+			continue
+		}
+		if allocGCEligible(alloc, job, time.Now(), math.MaxUint64) {
+			gcAlloc = append(gcAlloc, alloc.ID)
+		}
+	}
+
 	// Fast-path the nothing case
 	if len(gcEval) == 0 && len(gcAlloc) == 0 {
 		return nil
@@ -256,6 +380,98 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 	return c.evalReap(gcEval, gcAlloc)
 }
 
+// excessPerJob returns the terminal evaluations and allocations exceeding the
+// EvalGCMaxPerJob and AllocGCMaxPerJob limits, keeping the most recent ones of
+// each job. The evaluations and allocations of dispatched and periodic child
+// jobs count against their parent job.
+func (c *CoreScheduler) excessPerJob() (map[string]struct{}, []*structs.Allocation, error) {
+	maxEvals, maxAllocs := c.srv.config.EvalGCMaxPerJob, c.srv.config.AllocGCMaxPerJob
+
+	excessEvals := make(map[string]struct{})
+	if maxEvals > 0 {
+		iter, err := c.snap.Evals(nil, state.SortDefault)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Cache the parents of jobs to avoid looking up jobs for each of
+		// their evaluations
+		parents := make(map[structs.NamespacedID]string)
+
+		evals := make(map[structs.NamespacedID][]*structs.Evaluation)
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			eval := raw.(*structs.Evaluation)
+			if !eval.TerminalStatus() {
+				continue
+			}
+
+			id := structs.NamespacedID{ID: eval.JobID, Namespace: eval.Namespace}
+			parent, ok := parents[id]
+			if !ok {
+				job, err := c.snap.JobByID(nil, eval.Namespace, eval.JobID)
+				if err != nil {
+					return nil, nil, err
+				}
+				parent = eval.JobID
+				if job != nil && job.ParentID != "" {
+					parent = job.ParentID
+				}
+				parents[id] = parent
+			}
+
+			key := structs.NamespacedID{ID: parent, Namespace: eval.Namespace}
+			evals[key] = append(evals[key], eval)
+		}
+
+		for _, jobEvals := range evals {
+			if len(jobEvals) <= maxEvals {
+				continue
+			}
+			sort.Slice(jobEvals, func(i, j int) bool {
+				return jobEvals[i].CreateIndex > jobEvals[j].CreateIndex
+			})
+			for _, eval := range jobEvals[maxEvals:] {
+				excessEvals[eval.ID] = struct{}{}
+			}
+		}
+	}
+
+	var excessAllocs []*structs.Allocation
+	if maxAllocs > 0 {
+		iter, err := c.snap.Allocs(nil, state.SortDefault)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		allocs := make(map[structs.NamespacedID][]*structs.Allocation)
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			alloc := raw.(*structs.Allocation)
+			if !alloc.TerminalStatus() {
+				continue
+			}
+
+			parent := alloc.JobID
+			if alloc.Job != nil && alloc.Job.ParentID != "" {
+				parent = alloc.Job.ParentID
+			}
+			key := structs.NamespacedID{ID: parent, Namespace: alloc.Namespace}
+			allocs[key] = append(allocs[key], alloc)
+		}
+
+		for _, jobAllocs := range allocs {
+			if len(jobAllocs) <= maxAllocs {
+				continue
+			}
+			sort.Slice(jobAllocs, func(i, j int) bool {
+				return jobAllocs[i].CreateIndex > jobAllocs[j].CreateIndex
+			})
+			excessAllocs = append(excessAllocs, jobAllocs[maxAllocs:]...)
+		}
+	}
+
+	return excessEvals, excessAllocs, nil
+}
+
 // gcEval returns whether the eval should be garbage collected given a raft
 // threshold index. The eval disqualifies for garbage collection if it or its
 // allocs are not older than the threshold. If the eval should be garbage
@@ -781,6 +997,38 @@ func (c *CoreScheduler) expiredOneTimeTokenGC(eval *structs.Evaluation) error {
 	return c.srv.RPC("ACL.ExpireOneTimeTokens", req, &structs.GenericResponse{})
 }
 
+func (c *CoreScheduler) expiredNodeIntroTokenGC(eval *structs.Evaluation) error {
+	// No token can be created until all servers are upgraded, so there is
+	// nothing to expire before then
+	if !ServersMeetMinimumVersion(c.srv.Members(), minNodeIntroTokenVersion, false) {
+		return nil
+	}
+
+	req := &structs.NodeIntroTokenExpireRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	return c.srv.RPC("Node.ExpireIntroTokens", req, &structs.GenericResponse{})
+}
+
+func (c *CoreScheduler) expiredJobTrashGC(eval *structs.Evaluation) error {
+	// No job is trashed until all servers are upgraded, so there is nothing
+	// to expire before then
+	if !ServersMeetMinimumVersion(c.srv.Members(), minJobTrashVersion, false) {
+		return nil
+	}
+
+	req := &structs.JobTrashExpireRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	return c.srv.RPC("Job.ExpireTrash", req, &structs.GenericResponse{})
+}
+
 // rootKeyRotateOrGC is used to rotate or garbage collect root keys
 func (c *CoreScheduler) rootKeyRotateOrGC(eval *structs.Evaluation) error {
 
@@ -729,6 +729,144 @@ func TestCoreScheduler_EvalGC_Partial(t *testing.T) {
 	}
 }
 
+// TestCoreScheduler_EvalGC_MaxPerJob asserts that only the most recent
+// evaluations of the child jobs of a job are kept when exceeding the limit,
+// regardless of their age.
+func TestCoreScheduler_EvalGC_MaxPerJob(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.EvalGCMaxPerJob = 2
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	store := s1.fsm.State()
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, parent))
+
+	// Insert dead dispatched jobs, each with a complete eval and alloc
+	var evals []*structs.Evaluation
+	var allocs []*structs.Allocation
+	index := uint64(1000)
+	for i := 0; i < 4; i++ {
+		job := mock.BatchJob()
+		job.ParentID = parent.ID
+		job.Status = structs.JobStatusDead
+		index++
+		require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, job))
+
+		eval := mock.Eval()
+		eval.Status = structs.EvalStatusComplete
+		eval.Type = structs.JobTypeBatch
+		eval.JobID = job.ID
+		index++
+		require.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, index, []*structs.Evaluation{eval}))
+
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.EvalID = eval.ID
+		alloc.DesiredStatus = structs.AllocDesiredStatusRun
+		alloc.ClientStatus = structs.AllocClientStatusComplete
+		index++
+		require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, index, []*structs.Allocation{alloc}))
+
+		evals = append(evals, eval)
+		allocs = append(allocs, alloc)
+	}
+
+	// Create a core scheduler
+	snap, err := store.Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC, with none of the evals being old enough
+	gc := s1.coreJobEval(structs.CoreJobEvalGC, index+1)
+	require.NoError(t, core.Process(gc))
+
+	// Only the two most recent evals and their allocs should remain
+	for i := range evals {
+		outE, err := store.EvalByID(nil, evals[i].ID)
+		require.NoError(t, err)
+		outA, err := store.AllocByID(nil, allocs[i].ID)
+		require.NoError(t, err)
+
+		if i < 2 {
+			require.Nil(t, outE, "eval %d", i)
+			require.Nil(t, outA, "alloc %d", i)
+		} else {
+			require.NotNil(t, outE, "eval %d", i)
+			require.NotNil(t, outA, "alloc %d", i)
+		}
+	}
+}
+
+// TestCoreScheduler_EvalGC_AllocMaxPerJob asserts that only the most recent
+// terminal allocations of a job are kept when exceeding the limit, regardless
+// of their age.
+func TestCoreScheduler_EvalGC_AllocMaxPerJob(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.AllocGCMaxPerJob = 1
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	store := s1.fsm.State()
+	job := mock.Job()
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	eval := mock.Eval()
+	eval.Status = structs.EvalStatusComplete
+	eval.JobID = job.ID
+	require.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval}))
+
+	// Insert stopped allocs and a running one
+	var allocs []*structs.Allocation
+	for i := 0; i < 4; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.EvalID = eval.ID
+		alloc.DesiredStatus = structs.AllocDesiredStatusStop
+		alloc.ClientStatus = structs.AllocClientStatusComplete
+		if i == 3 {
+			alloc.DesiredStatus = structs.AllocDesiredStatusRun
+			alloc.ClientStatus = structs.AllocClientStatusRunning
+		}
+		require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, uint64(1002+i), []*structs.Allocation{alloc}))
+		allocs = append(allocs, alloc)
+	}
+
+	// Create a core scheduler
+	snap, err := store.Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC, with none of the allocs being old enough
+	gc := s1.coreJobEval(structs.CoreJobEvalGC, 2000)
+	require.NoError(t, core.Process(gc))
+
+	// The most recent stopped alloc and the running alloc should remain
+	for i, alloc := range allocs {
+		out, err := store.AllocByID(nil, alloc.ID)
+		require.NoError(t, err)
+		if i < 2 {
+			require.Nil(t, out, "alloc %d", i)
+		} else {
+			require.NotNil(t, out, "alloc %d", i)
+		}
+	}
+
+	// The eval isn't collected as one of its allocs is running
+	out, err := store.EvalByID(nil, eval.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
 func TestCoreScheduler_EvalGC_Force(t *testing.T) {
 	ci.Parallel(t)
 	for _, withAcl := range []bool{false, true} {
@@ -1662,6 +1800,138 @@ func TestCoreScheduler_JobGC_Parameterized(t *testing.T) {
 	}
 }
 
+// upsertDeadDispatchedJobs inserts count dead jobs dispatched from the parent,
+// each with a complete eval and alloc, returning them from oldest to newest.
+func upsertDeadDispatchedJobs(t *testing.T, store *state.StateStore, parent *structs.Job, index uint64, count int) []*structs.Job {
+	var jobs []*structs.Job
+	for i := 0; i < count; i++ {
+		job := mock.BatchJob()
+		job.ParentID = parent.ID
+		job.Dispatched = true
+		index++
+		require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, job))
+
+		eval := mock.Eval()
+		eval.Status = structs.EvalStatusComplete
+		eval.Type = structs.JobTypeBatch
+		eval.JobID = job.ID
+		index++
+		require.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, index, []*structs.Evaluation{eval}))
+
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.EvalID = eval.ID
+		alloc.DesiredStatus = structs.AllocDesiredStatusRun
+		alloc.ClientStatus = structs.AllocClientStatusComplete
+		index++
+		require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, index, []*structs.Allocation{alloc}))
+
+		out, err := store.JobByID(nil, job.Namespace, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, structs.JobStatusDead, out.Status)
+		jobs = append(jobs, out)
+	}
+	return jobs
+}
+
+// TestCoreScheduler_JobGC_DispatchKeepLast asserts that only the most recently
+// completed dispatched jobs are kept when the parent sets keep_last,
+// regardless of their age.
+func TestCoreScheduler_JobGC_DispatchKeepLast(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	store := s1.fsm.State()
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{
+		Dispatch: &structs.DispatchConfig{KeepLast: 2},
+	}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, parent))
+
+	jobs := upsertDeadDispatchedJobs(t, store, parent, 1000, 4)
+
+	// Create a core scheduler
+	snap, err := store.Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC, with none of the jobs being old enough
+	gc := s1.coreJobEval(structs.CoreJobJobGC, 2000)
+	require.NoError(t, core.Process(gc))
+
+	// Only the two most recent jobs should remain
+	for i, job := range jobs {
+		out, err := store.JobByID(nil, job.Namespace, job.ID)
+		require.NoError(t, err)
+		if i < 2 {
+			require.Nil(t, out, "job %d", i)
+		} else {
+			require.NotNil(t, out, "job %d", i)
+		}
+	}
+
+	out, err := store.JobByID(nil, parent.Namespace, parent.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
+// TestCoreScheduler_JobGC_DispatchPurgeAfter asserts that dead dispatched jobs
+// are purged once older than the purge_after of their parent instead of the
+// job GC threshold.
+func TestCoreScheduler_JobGC_DispatchPurgeAfter(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	store := s1.fsm.State()
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{
+		Dispatch: &structs.DispatchConfig{PurgeAfter: time.Hour},
+	}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, parent))
+
+	other := mock.BatchJob()
+	other.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, other))
+
+	purged := upsertDeadDispatchedJobs(t, store, parent, 1000, 2)
+	kept := upsertDeadDispatchedJobs(t, store, other, 1100, 2)
+
+	// Update the time tables so the jobs completed longer than purge_after
+	// ago but are younger than the job GC threshold
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-2*time.Hour))
+
+	// Create a core scheduler
+	snap, err := store.Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobJobGC, 2000)
+	require.NoError(t, core.Process(gc))
+
+	for _, job := range purged {
+		out, err := store.JobByID(nil, job.Namespace, job.ID)
+		require.NoError(t, err)
+		require.Nil(t, out)
+	}
+	for _, job := range kept {
+		out, err := store.JobByID(nil, job.Namespace, job.ID)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+	}
+}
+
 // This test ensures periodic jobs don't get GCd until they are stopped
 func TestCoreScheduler_JobGC_Periodic(t *testing.T) {
 	ci.Parallel(t)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -89,6 +90,8 @@ func (b *AllocUpdateBatcher) batcher() {
 				panic("no future")
 			}
 
+			metrics.AddSample([]string{"nomad", "deployment_watcher", "desired_transition_batch_size"}, float32(len(allocs)))
+
 			// Create the request
 			req := &structs.AllocUpdateDesiredTransitionRequest{
 				Allocs: allocs,
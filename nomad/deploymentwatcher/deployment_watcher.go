@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/helper"
@@ -19,6 +20,12 @@ const (
 	// perJobEvalBatchPeriod is the batching length before creating an evaluation to
 	// trigger the scheduler when allocations are marked as healthy.
 	perJobEvalBatchPeriod = 1 * time.Second
+
+	// allocHealthBatchPeriod is the batching length before committing the
+	// allocations marked as healthy through SetAllocHealth, so that a large
+	// rollout reporting health allocation by allocation results in a single
+	// Raft write and evaluation per period.
+	allocHealthBatchPeriod = 250 * time.Millisecond
 )
 
 var (
@@ -62,6 +69,10 @@ type deploymentWatcher struct {
 	// deployment
 	deploymentTriggers
 
+	// promotionGates looks up the promotion gates selected by the task
+	// groups of the deployment
+	promotionGates promotionGates
+
 	// DeploymentRPC holds methods for interacting with peer regions
 	// in enterprise edition
 	DeploymentRPC
@@ -98,17 +109,37 @@ type deploymentWatcher struct {
 	// by holding the lock or using the setter and getter methods.
 	latestEval uint64
 
+	// pendingHealth is the batch of allocations marked as healthy that have
+	// not been committed yet. Access should be done through the lock.
+	pendingHealth *allocHealthBatch
+
 	logger log.Logger
 	ctx    context.Context
 	exitFn context.CancelFunc
 	l      sync.RWMutex
 }
 
+// allocHealthBatch is a batch of allocations marked as healthy that are
+// committed together with a single evaluation.
+type allocHealthBatch struct {
+	// req is the request the batch is committed with
+	req structs.DeploymentAllocHealthRequest
+
+	// allocs is the set of healthy allocations
+	allocs map[string]struct{}
+
+	// eval is the evaluation created when the batch is committed
+	eval *structs.Evaluation
+
+	// future tracks the commit of the batch
+	future *BatchFuture
+}
+
 // newDeploymentWatcher returns a deployment watcher that is used to watch
 // deployments and trigger the scheduler as needed.
 func newDeploymentWatcher(parent context.Context, queryLimiter *rate.Limiter,
 	logger log.Logger, state *state.StateStore, d *structs.Deployment,
-	j *structs.Job, triggers deploymentTriggers,
+	j *structs.Job, triggers deploymentTriggers, gates promotionGates,
 	deploymentRPC DeploymentRPC, jobRPC JobRPC) *deploymentWatcher {
 
 	ctx, exitFn := context.WithCancel(parent)
@@ -120,6 +151,7 @@ func newDeploymentWatcher(parent context.Context, queryLimiter *rate.Limiter,
 		j:                  j,
 		state:              state,
 		deploymentTriggers: triggers,
+		promotionGates:     gates,
 		DeploymentRPC:      deploymentRPC,
 		JobRPC:             jobRPC,
 		logger:             logger.With("deployment_id", d.ID, "job", j.NamespacedID()),
@@ -157,6 +189,11 @@ func (w *deploymentWatcher) SetAllocHealth(
 	req *structs.DeploymentAllocHealthRequest,
 	resp *structs.DeploymentUpdateResponse) error {
 
+	// Allocations only being marked as healthy are batched
+	if len(req.UnhealthyAllocationIDs) == 0 {
+		return w.batchAllocHealth(req, resp)
+	}
+
 	// If we are failing the deployment, update the status and potentially
 	// rollback
 	var j *structs.Job
@@ -222,7 +259,25 @@ func (w *deploymentWatcher) SetAllocHealth(
 		Job:                          j,
 	}
 
+	// Commit any pending healthy allocations along with the request, as the
+	// deployment can no longer be updated once failed.
+	w.l.Lock()
+	pending := w.pendingHealth
+	w.pendingHealth = nil
+	w.l.Unlock()
+	if pending != nil {
+		healthy := make([]string, 0, len(req.HealthyAllocationIDs)+len(pending.allocs))
+		healthy = append(healthy, req.HealthyAllocationIDs...)
+		for id := range pending.allocs {
+			healthy = append(healthy, id)
+		}
+		areq.HealthyAllocationIDs = healthy
+	}
+
 	index, err := w.upsertDeploymentAllocHealth(areq)
+	if pending != nil {
+		pending.future.Set(index, err)
+	}
 	if err != nil {
 		return err
 	}
@@ -238,6 +293,107 @@ func (w *deploymentWatcher) SetAllocHealth(
 	return nil
 }
 
+// batchAllocHealth adds the healthy allocations of the request to the pending
+// batch and waits for the batch to be committed.
+func (w *deploymentWatcher) batchAllocHealth(
+	req *structs.DeploymentAllocHealthRequest,
+	resp *structs.DeploymentUpdateResponse) error {
+
+	// Check the allocations up front, so that an invalid request does not
+	// fail the whole batch.
+	if err := w.checkDeploymentAllocs(req.HealthyAllocationIDs); err != nil {
+		return err
+	}
+
+	eval := w.getEval()
+
+	w.l.Lock()
+	batch := w.pendingHealth
+	if batch == nil {
+		batch = &allocHealthBatch{
+			req:    *req,
+			allocs: make(map[string]struct{}, len(req.HealthyAllocationIDs)),
+			eval:   eval,
+			future: NewBatchFuture(),
+		}
+		w.pendingHealth = batch
+		time.AfterFunc(allocHealthBatchPeriod, w.commitAllocHealth)
+	}
+	for _, id := range req.HealthyAllocationIDs {
+		batch.allocs[id] = struct{}{}
+	}
+	w.l.Unlock()
+
+	index, err := batch.future.Results()
+	if err != nil {
+		return err
+	}
+
+	// Build the response
+	resp.EvalID = batch.eval.ID
+	resp.EvalCreateIndex = index
+	resp.DeploymentModifyIndex = index
+	resp.Index = index
+	return nil
+}
+
+// commitAllocHealth commits the pending batch of healthy allocations, if it
+// was not already committed with a request marking allocations as unhealthy.
+func (w *deploymentWatcher) commitAllocHealth() {
+	w.l.Lock()
+	batch := w.pendingHealth
+	w.pendingHealth = nil
+	w.l.Unlock()
+
+	if batch == nil {
+		return
+	}
+
+	// If the watcher was stopped before the batch was committed, the callers
+	// need to retry on the new leader.
+	if err := w.ctx.Err(); err != nil {
+		batch.future.Set(0, err)
+		return
+	}
+
+	healthy := make([]string, 0, len(batch.allocs))
+	for id := range batch.allocs {
+		healthy = append(healthy, id)
+	}
+	metrics.AddSample([]string{"nomad", "deployment_watcher", "alloc_health_batch_size"}, float32(len(healthy)))
+
+	batch.req.HealthyAllocationIDs = healthy
+	areq := &structs.ApplyDeploymentAllocHealthRequest{
+		DeploymentAllocHealthRequest: batch.req,
+		Timestamp:                    time.Now(),
+		Eval:                         batch.eval,
+	}
+	batch.future.Set(w.upsertDeploymentAllocHealth(areq))
+}
+
+// checkDeploymentAllocs returns an error if any of the allocations does not
+// exist or is not part of the deployment.
+func (w *deploymentWatcher) checkDeploymentAllocs(ids []string) error {
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		alloc, err := snap.AllocByID(nil, id)
+		if err != nil {
+			return fmt.Errorf("alloc %q lookup failed: %v", id, err)
+		}
+		if alloc == nil {
+			return fmt.Errorf("unknown alloc %q", id)
+		}
+		if alloc.DeploymentID != w.deploymentID {
+			return fmt.Errorf("alloc %q is not part of deployment %q", id, w.deploymentID)
+		}
+	}
+	return nil
+}
+
 // handleRollbackValidity checks if the job being rolled back to has the same spec as the existing job
 // Returns a modified description and job accordingly.
 func (w *deploymentWatcher) handleRollbackValidity(rollbackJob *structs.Job, desc string) (*structs.Job, string) {
@@ -276,16 +432,18 @@ func (w *deploymentWatcher) PromoteDeployment(
 	return nil
 }
 
-// autoPromoteDeployment creates a synthetic promotion request, and upserts it for processing
-func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStub) error {
+// autoPromoteDeployment creates a synthetic promotion request, and upserts it for processing.
+// If a promotion gate blocks the promotion, it returns the duration after which to try again.
+func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStub) (time.Duration, error) {
 	d := w.getDeployment()
 	if !d.HasPlacedCanaries() || !d.RequiresPromotion() {
-		return nil
+		return 0, nil
 	}
 
 	// AutoPromote iff every task group with canaries is marked auto_promote and is healthy. The whole
 	// job version has been incremented, so we promote together. See also AutoRevert
-	for _, dstate := range d.TaskGroups {
+	gated := make(map[string][]string)
+	for group, dstate := range d.TaskGroups {
 
 		// skip auto promote canary validation if the task group has no canaries
 		// to prevent auto promote hanging on mixed canary/non-canary taskgroup deploys
@@ -294,17 +452,35 @@ func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStu
 		}
 
 		if !dstate.AutoPromote || dstate.DesiredCanaries != len(dstate.PlacedCanaries) {
-			return nil
+			return 0, nil
 		}
 
 		// Find the health status of each canary
 		for _, c := range dstate.PlacedCanaries {
 			for _, a := range allocs {
 				if c == a.ID && !a.DeploymentStatus.IsHealthy() {
-					return nil
+					return 0, nil
 				}
 			}
 		}
+
+		if dstate.PromotionGate != "" {
+			gated[dstate.PromotionGate] = append(gated[dstate.PromotionGate], group)
+		}
+	}
+
+	// The canaries are healthy, so the promotion gates of the groups decide
+	if len(gated) != 0 {
+		retry, desc := w.checkPromotionGates(d, gated, allocs)
+		if retry != 0 {
+			if desc != d.StatusDescription {
+				u := w.getDeploymentStatusUpdate(structs.DeploymentStatusRunning, desc)
+				if _, err := w.upsertDeploymentStatusUpdate(u, nil, nil); err != nil {
+					return retry, err
+				}
+			}
+			return retry, nil
+		}
 	}
 
 	// Send the request
@@ -312,7 +488,7 @@ func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStu
 		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{DeploymentID: d.GetID(), All: true},
 		Eval:                     w.getEval(),
 	})
-	return err
+	return 0, err
 }
 
 func (w *deploymentWatcher) PauseDeployment(
@@ -422,6 +598,14 @@ func (w *deploymentWatcher) watch() {
 		deadlineTimer = time.NewTimer(time.Until(currentDeadline))
 	}
 
+	// The promotion timer fires when the promotion gates blocking the
+	// automatic promotion of the deployment should be checked again
+	promotionTimer := time.NewTimer(0)
+	if !promotionTimer.Stop() {
+		<-promotionTimer.C
+	}
+	defer promotionTimer.Stop()
+
 	allocIndex := uint64(1)
 	allocsCh := w.getAllocsCh(allocIndex)
 	var updates *allocUpdates
@@ -495,6 +679,19 @@ FAIL:
 				break FAIL
 			}
 
+		case <-promotionTimer.C:
+			// Check the promotion gates again with the latest allocations
+			if updates == nil {
+				continue
+			}
+			retry, err := w.autoPromoteDeployment(updates.allocs)
+			if err != nil {
+				w.logger.Error("failed to auto promote deployment", "error", err)
+			}
+			if retry != 0 {
+				promotionTimer.Reset(retry)
+			}
+
 		case updates = <-allocsCh:
 			if err := updates.err; err != nil {
 				if err == context.Canceled || w.ctx.Err() == context.Canceled {
@@ -530,11 +727,23 @@ FAIL:
 			}
 
 			// If permitted, automatically promote this canary deployment
-			err = w.autoPromoteDeployment(updates.allocs)
+			retry, err := w.autoPromoteDeployment(updates.allocs)
 			if err != nil {
 				w.logger.Error("failed to auto promote deployment", "error", err)
 			}
 
+			// Check the blocking promotion gates again later, unless a
+			// check is already scheduled
+			if !promotionTimer.Stop() {
+				select {
+				case <-promotionTimer.C:
+				default:
+				}
+			}
+			if retry != 0 {
+				promotionTimer.Reset(retry)
+			}
+
 			// Create an eval to push the deployment along
 			if res.createEval || len(res.allowReplacements) != 0 {
 				w.createBatchedUpdate(res.allowReplacements, allocIndex)
@@ -88,6 +88,11 @@ type Watcher struct {
 	// allocation desired transition updates
 	allocUpdateBatcher *AllocUpdateBatcher
 
+	// gates are the registered promotion gates by name, guarded by gatesLock
+	// as they are registered independently of the watcher being enabled.
+	gates     map[string]PromotionGate
+	gatesLock sync.RWMutex
+
 	// ctx and exitFn are used to cancel the watcher
 	ctx    context.Context
 	exitFn context.CancelFunc
@@ -269,7 +274,7 @@ func (w *Watcher) addLocked(d *structs.Deployment) (*deploymentWatcher, error) {
 	}
 
 	watcher := newDeploymentWatcher(w.ctx, w.queryLimiter, w.logger, w.state, d, job,
-		w, w.deploymentRPC, w.jobRPC)
+		w, w, w.deploymentRPC, w.jobRPC)
 	w.watchers[d.ID] = watcher
 	return watcher, nil
 }
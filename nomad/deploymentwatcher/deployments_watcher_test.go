@@ -1,7 +1,10 @@
 package deploymentwatcher
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -230,6 +233,73 @@ func TestWatcher_SetAllocHealth_Healthy(t *testing.T) {
 	m.AssertCalled(t, "UpdateDeploymentAllocHealth", mocker.MatchedBy(matcher))
 }
 
+// Test that concurrent calls setting allocations healthy are committed in a
+// single batch
+func TestWatcher_SetAllocHealth_Batched(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(func(args *structs.DeploymentStatusUpdateRequest) bool {
+		return true
+	})).Return(nil).Maybe()
+
+	// Create a job, allocs, and a deployment
+	j := mock.Job()
+	d := mock.Deployment()
+	d.JobID = j.ID
+	a1 := mock.Alloc()
+	a1.DeploymentID = d.ID
+	a2 := mock.Alloc()
+	a2.DeploymentID = d.ID
+	require.Nil(m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+	require.Nil(m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{a1, a2}), "UpsertAllocs")
+
+	// require that we get a single call to UpsertDeploymentAllocHealth
+	matchConfig := &matchDeploymentAllocHealthRequestConfig{
+		DeploymentID: d.ID,
+		Healthy:      []string{a1.ID, a2.ID},
+		Eval:         true,
+	}
+	matcher := matchDeploymentAllocHealthRequest(matchConfig)
+	m.On("UpdateDeploymentAllocHealth", mocker.MatchedBy(matcher)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == watchersCount(w), nil },
+		func(err error) { require.Equal(1, watchersCount(w), "Should have 1 deployment") })
+
+	// Call SetAllocHealth concurrently for each alloc
+	var wg sync.WaitGroup
+	resps := make([]structs.DeploymentUpdateResponse, 2)
+	errs := make([]error, 2)
+	for i, a := range []*structs.Allocation{a1, a2} {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			req := &structs.DeploymentAllocHealthRequest{
+				DeploymentID:         d.ID,
+				HealthyAllocationIDs: []string{id},
+			}
+			errs[i] = w.SetAllocHealth(req, &resps[i])
+		}(i, a.ID)
+	}
+	wg.Wait()
+
+	require.NoError(errs[0])
+	require.NoError(errs[1])
+	require.Equal(resps[0].EvalID, resps[1].EvalID)
+	m.AssertNumberOfCalls(t, "UpdateDeploymentAllocHealth", 1)
+
+	// Allocations outside of the deployment are rejected up front
+	req := &structs.DeploymentAllocHealthRequest{
+		DeploymentID:         d.ID,
+		HealthyAllocationIDs: []string{mock.Alloc().ID},
+	}
+	var resp structs.DeploymentUpdateResponse
+	require.ErrorContains(w.SetAllocHealth(req, &resp), "unknown alloc")
+}
+
 // Test setting allocation unhealthy
 func TestWatcher_SetAllocHealth_Unhealthy(t *testing.T) {
 	ci.Parallel(t)
@@ -696,6 +766,145 @@ func TestWatcher_AutoPromoteDeployment(t *testing.T) {
 	require.False(t, b1.DeploymentStatus.Canary)
 }
 
+// testPromotionGate is a promotion gate blocking the promotion of canaries
+// until it is opened.
+type testPromotionGate struct {
+	open   int32
+	checks int32
+}
+
+func (g *testPromotionGate) Name() string { return "error-rate" }
+
+func (g *testPromotionGate) Check(_ context.Context, req *PromotionGateRequest) (*PromotionDecision, error) {
+	atomic.AddInt32(&g.checks, 1)
+	if len(req.TaskGroups) != 1 || req.TaskGroups[0] != "web" || len(req.Canaries) != 2 {
+		return nil, fmt.Errorf("unexpected request for groups %v", req.TaskGroups)
+	}
+	if atomic.LoadInt32(&g.open) == 0 {
+		return &PromotionDecision{Reason: "error rate too high", RetryAfter: 50 * time.Millisecond}, nil
+	}
+	return &PromotionDecision{Promote: true}, nil
+}
+
+func TestWatcher_AutoPromoteDeployment_PromotionGate(t *testing.T) {
+	ci.Parallel(t)
+	w, m := defaultTestDeploymentWatcher(t)
+	gate := &testPromotionGate{}
+	w.RegisterPromotionGate(gate)
+
+	canaryUpd := structs.DefaultUpdateStrategy.Copy()
+	canaryUpd.AutoPromote = true
+	canaryUpd.PromotionGate = gate.Name()
+	canaryUpd.MaxParallel = 2
+	canaryUpd.Canary = 2
+	canaryUpd.ProgressDeadline = 5 * time.Second
+
+	j := mock.Job()
+	j.TaskGroups[0].Update = canaryUpd
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups = map[string]*structs.DeploymentState{
+		"web": {
+			AutoPromote:      canaryUpd.AutoPromote,
+			PromotionGate:    canaryUpd.PromotionGate,
+			ProgressDeadline: canaryUpd.ProgressDeadline,
+			DesiredTotal:     2,
+			DesiredCanaries:  2,
+		},
+	}
+
+	now := time.Now()
+	ca1, ca2 := mock.Alloc(), mock.Alloc()
+	for _, a := range []*structs.Allocation{ca1, ca2} {
+		a.DeploymentID = d.ID
+		a.CreateTime = now.UnixNano()
+		a.ModifyTime = now.UnixNano()
+		a.DeploymentStatus = &structs.AllocDeploymentStatus{Canary: true}
+	}
+	d.TaskGroups["web"].PlacedCanaries = []string{ca1.ID, ca2.ID}
+
+	require.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), j), "UpsertJob")
+	require.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+	require.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{ca1, ca2}), "UpsertAllocs")
+
+	// clear UpdateDeploymentStatus default expectation
+	m.Mock.ExpectedCalls = nil
+
+	blocked := structs.DeploymentStatusDescriptionPromotionGateBlocked(gate.Name(), "error rate too high")
+	matcher0 := matchDeploymentStatusUpdateRequest(&matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusRunning,
+		StatusDescription: blocked,
+	})
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher0)).Return(nil)
+
+	matcher1 := matchDeploymentAllocHealthRequest(&matchDeploymentAllocHealthRequestConfig{
+		DeploymentID: d.ID,
+		Healthy:      []string{ca1.ID, ca2.ID},
+		Eval:         true,
+	})
+	m.On("UpdateDeploymentAllocHealth", mocker.MatchedBy(matcher1)).Return(nil)
+
+	matcher2 := matchDeploymentPromoteRequest(&matchDeploymentPromoteRequestConfig{
+		Promotion: &structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			All:          true,
+		},
+		Eval: true,
+	})
+	m.On("UpdateDeploymentPromotion", mocker.MatchedBy(matcher2)).Return(nil)
+
+	// Start the deployment
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) {
+		w.l.RLock()
+		defer w.l.RUnlock()
+		return 1 == len(w.watchers), nil
+	}, func(err error) {
+		require.NoError(t, err, "Should have 1 deployment")
+	})
+
+	// Mark the canaries healthy
+	req := &structs.DeploymentAllocHealthRequest{
+		DeploymentID:         d.ID,
+		HealthyAllocationIDs: []string{ca1.ID, ca2.ID},
+	}
+	var resp structs.DeploymentUpdateResponse
+	require.NoError(t, w.SetAllocHealth(req, &resp))
+
+	// The gate blocks the promotion and is checked again until opened
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return false, err
+		}
+		if out.StatusDescription != blocked {
+			return false, fmt.Errorf("unexpected status description %q", out.StatusDescription)
+		}
+		if n := atomic.LoadInt32(&gate.checks); n < 3 {
+			return false, fmt.Errorf("gate checked %d times", n)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+	m.AssertNotCalled(t, "UpdateDeploymentPromotion", mocker.MatchedBy(matcher2))
+
+	// Open the gate and the canaries get promoted
+	atomic.StoreInt32(&gate.open, 1)
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return false, err
+		}
+		return out.TaskGroups["web"].Promoted, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+	m.AssertCalled(t, "UpdateDeploymentPromotion", mocker.MatchedBy(matcher2))
+}
+
 // Test pausing a deployment that is running
 func TestWatcher_PauseDeployment_Pause_Running(t *testing.T) {
 	ci.Parallel(t)
@@ -0,0 +1,149 @@
+package deploymentwatcher
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// defaultPromotionGateInterval is the interval at which the promotion
+	// gates blocking the promotion of a deployment are checked again, unless
+	// the gate specifies one.
+	defaultPromotionGateInterval = 30 * time.Second
+
+	// promotionGateTimeout is the maximum duration of a promotion gate check.
+	promotionGateTimeout = 30 * time.Second
+)
+
+// PromotionGate decides whether the canaries of a deployment may be promoted
+// automatically once they are all healthy, for example by comparing the error
+// rate of the canaries to the one of the other allocations in an external
+// metrics system. Task groups select a gate by name with the promotion_gate
+// field of their update block.
+type PromotionGate interface {
+	// Name returns the name task groups select the gate by.
+	Name() string
+
+	// Check decides whether the canaries may be promoted. It is called once
+	// all the canaries of the deployment are healthy, and again until it
+	// allows the promotion or the deployment stops being automatically
+	// promoted. An error blocks the promotion until the next check.
+	Check(ctx context.Context, req *PromotionGateRequest) (*PromotionDecision, error)
+}
+
+// PromotionGateRequest is the deployment whose canaries a promotion gate
+// decides on.
+type PromotionGateRequest struct {
+	Deployment *structs.Deployment
+	Job        *structs.Job
+
+	// TaskGroups are the task groups of the deployment which selected the
+	// gate.
+	TaskGroups []string
+
+	// Canaries are the canaries of the task groups which selected the gate.
+	Canaries []*structs.AllocListStub
+}
+
+// PromotionDecision is the decision of a promotion gate.
+type PromotionDecision struct {
+	// Promote allows the promotion of the canaries.
+	Promote bool
+
+	// Reason explains why the promotion is blocked. It is added to the status
+	// description of the deployment.
+	Reason string
+
+	// RetryAfter is the duration after which to check the gate again if the
+	// promotion is blocked. It defaults to 30 seconds.
+	RetryAfter time.Duration
+}
+
+// promotionGates looks up the promotion gates by name.
+type promotionGates interface {
+	// promotionGate returns the gate with the given name or nil if it is not
+	// registered.
+	promotionGate(name string) PromotionGate
+}
+
+// RegisterPromotionGate registers a promotion gate task groups may select by
+// its name. A gate registered with the name of another replaces it.
+func (w *Watcher) RegisterPromotionGate(gate PromotionGate) {
+	w.gatesLock.Lock()
+	defer w.gatesLock.Unlock()
+
+	if w.gates == nil {
+		w.gates = make(map[string]PromotionGate)
+	}
+	w.gates[gate.Name()] = gate
+}
+
+func (w *Watcher) promotionGate(name string) PromotionGate {
+	w.gatesLock.RLock()
+	defer w.gatesLock.RUnlock()
+	return w.gates[name]
+}
+
+// checkPromotionGates checks the promotion gates selected by the task groups
+// of the deployment, whose canaries are all healthy. The groups are given by
+// gate. If a gate blocks the promotion, it returns the duration after which to
+// check the gates again and the status description of the deployment.
+func (w *deploymentWatcher) checkPromotionGates(d *structs.Deployment, gated map[string][]string,
+	allocs []*structs.AllocListStub) (time.Duration, string) {
+
+	names := make([]string, 0, len(gated))
+	for name := range gated {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		gate := w.promotionGates.promotionGate(name)
+		if gate == nil {
+			return defaultPromotionGateInterval,
+				structs.DeploymentStatusDescriptionPromotionGateBlocked(name, "gate is not registered")
+		}
+
+		groups := gated[name]
+		sort.Strings(groups)
+		req := &PromotionGateRequest{
+			Deployment: d,
+			Job:        w.j,
+			TaskGroups: groups,
+		}
+		for _, group := range groups {
+			for _, id := range d.TaskGroups[group].PlacedCanaries {
+				for _, a := range allocs {
+					if a.ID == id {
+						req.Canaries = append(req.Canaries, a)
+					}
+				}
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(w.ctx, promotionGateTimeout)
+		decision, err := gate.Check(ctx, req)
+		cancel()
+		if err != nil {
+			w.logger.Warn("promotion gate check failed", "gate", name, "error", err)
+			return defaultPromotionGateInterval,
+				structs.DeploymentStatusDescriptionPromotionGateBlocked(name, err.Error())
+		}
+		if decision == nil || !decision.Promote {
+			retry, reason := defaultPromotionGateInterval, ""
+			if decision != nil {
+				reason = decision.Reason
+				if decision.RetryAfter > 0 {
+					retry = decision.RetryAfter
+				}
+			}
+			w.logger.Debug("promotion gate blocked promotion", "gate", name, "reason", reason)
+			return retry, structs.DeploymentStatusDescriptionPromotionGateBlocked(name, reason)
+		}
+	}
+
+	return 0, ""
+}
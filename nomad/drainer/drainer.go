@@ -395,9 +395,16 @@ func (n *NodeDrainer) drainAllocs(future *structs.BatchFuture, allocs []*structs
 	jobs := make(map[structs.NamespacedID]*structs.Allocation, 4)
 	transitions := make(map[string]*structs.DesiredTransition, len(allocs))
 	for _, alloc := range allocs {
-		transitions[alloc.ID] = &structs.DesiredTransition{
+		transition := &structs.DesiredTransition{
 			Migrate: helper.BoolToPtr(true),
 		}
+
+		// Pinned allocations are only drained once the node is deadlined, so
+		// the drain is forced and overrides the pin.
+		if alloc.DesiredTransition.ShouldPin() {
+			transition.Pinned = helper.BoolToPtr(false)
+		}
+		transitions[alloc.ID] = transition
 		jobs[alloc.JobNamespacedID()] = alloc
 	}
 
@@ -3,6 +3,7 @@ package drainer
 import (
 	"context"
 	"fmt"
+	"math"
 	"sync"
 
 	log "github.com/hashicorp/go-hclog"
@@ -338,12 +339,15 @@ func handleJob(snap *state.StateSnapshot, job *structs.Job, allocs []*structs.Al
 // handleTaskGroup takes the state of a draining task group and computes the
 // desired actions. For batch jobs we only notify when they have been migrated
 // and never mark them for drain. Batch jobs are allowed to complete up until
-// the deadline, after which they are force killed.
+// the deadline, after which they are force killed. Service allocations are
+// only marked for drain once the allocations of their node with a lower kill
+// priority have been.
 func handleTaskGroup(snap *state.StateSnapshot, batch bool, tg *structs.TaskGroup,
 	allocs []*structs.Allocation, lastHandledIndex uint64, result *jobResult) error {
 
 	// Determine how many allocations can be drained
 	drainingNodes := make(map[string]bool, 4)
+	lowestKillPriority := make(map[string]int, 4)
 	healthy := 0
 	remainingDrainingAlloc := false
 	var drainable []*structs.Allocation
@@ -392,9 +396,17 @@ func handleTaskGroup(snap *state.StateSnapshot, batch bool, tg *structs.TaskGrou
 		remainingDrainingAlloc = true
 
 		// If we haven't marked this allocation for migration already, capture
-		// it as eligible for draining.
-		if !batch && !alloc.DesiredTransition.ShouldMigrate() {
-			drainable = append(drainable, alloc)
+		// it as eligible for draining unless it must wait for allocations with
+		// a lower kill priority. Pinned allocations are only migrated once the
+		// drain deadline is reached.
+		if !batch && !alloc.DesiredTransition.ShouldMigrate() && !alloc.DesiredTransition.ShouldPin() {
+			blocked, err := killOrderBlocked(snap, alloc, lowestKillPriority)
+			if err != nil {
+				return err
+			}
+			if !blocked {
+				drainable = append(drainable, alloc)
+			}
 		}
 	}
 
@@ -420,6 +432,45 @@ func handleTaskGroup(snap *state.StateSnapshot, batch bool, tg *structs.TaskGrou
 	return nil
 }
 
+// killOrderBlocked returns whether the allocation must wait for allocations of
+// its node with a lower kill priority to be marked for migration before being
+// drained. lowest caches the lowest kill priority of the allocations of each
+// node that are pending migration.
+func killOrderBlocked(snap *state.StateSnapshot, alloc *structs.Allocation, lowest map[string]int) (bool, error) {
+	priority, ok := lowest[alloc.NodeID]
+	if !ok {
+		allocs, err := snap.AllocsByNode(nil, alloc.NodeID)
+		if err != nil {
+			return false, err
+		}
+
+		priority = math.MaxInt
+		for _, a := range allocs {
+			if pendingMigration(a) && a.KillPriority() < priority {
+				priority = a.KillPriority()
+			}
+		}
+		lowest[alloc.NodeID] = priority
+	}
+
+	return alloc.KillPriority() > priority, nil
+}
+
+// pendingMigration returns whether the allocation is yet to be marked for
+// migration by the drainer. Batch, system and plugin allocations are never
+// marked, as they are only stopped at the deadline or once the node is done
+// draining, and neither are groups without a migrate strategy.
+func pendingMigration(alloc *structs.Allocation) bool {
+	if alloc.TerminalStatus() || alloc.DesiredTransition.ShouldMigrate() {
+		return false
+	}
+	if alloc.Job == nil || alloc.Job.Type != structs.JobTypeService || alloc.Job.IsPlugin() {
+		return false
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	return tg != nil && tg.Migrate != nil
+}
+
 // getJobAllocs returns all allocations for draining jobs
 func (w *drainingJobWatcher) getJobAllocs(ctx context.Context, minIndex uint64) (map[structs.NamespacedID][]*structs.Allocation, uint64, error) {
 	if err := w.limiter.Wait(ctx); err != nil {
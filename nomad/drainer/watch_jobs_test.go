@@ -671,6 +671,135 @@ func TestHandleTaskGroup_Migrations(t *testing.T) {
 	require.True(res.done)
 }
 
+// This test asserts that pinned allocations of a draining node are not marked
+// for drain, and keep the drain from completing.
+func TestHandleTaskGroup_Pinned(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	state := state.TestStateStore(t)
+	n := mock.Node()
+	n.DrainStrategy = &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{
+			Deadline: 5 * time.Minute,
+		},
+		ForceDeadline: time.Now().Add(1 * time.Minute),
+	}
+	require.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 100, n))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	require.Nil(state.UpsertJob(structs.MsgTypeTestSetup, 101, job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 2; i++ {
+		a := mock.Alloc()
+		a.Job = job
+		a.TaskGroup = job.TaskGroups[0].Name
+		a.NodeID = n.ID
+		a.DeploymentStatus = &structs.AllocDeploymentStatus{
+			Healthy: helper.BoolToPtr(true),
+		}
+		allocs = append(allocs, a)
+	}
+	allocs[0].DesiredTransition.Pinned = helper.BoolToPtr(true)
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 102, allocs))
+
+	snap, err := state.Snapshot()
+	require.Nil(err)
+
+	res := newJobResult()
+	require.Nil(handleTaskGroup(snap, false, job.TaskGroups[0], allocs, 102, res))
+	require.Len(res.drain, 1)
+	require.Equal(allocs[1].ID, res.drain[0].ID)
+	require.False(res.done)
+}
+
+// This test asserts that allocations of a draining node are only marked for
+// drain once the allocations with a lower kill priority have been.
+func TestHandleTaskGroup_KillPriority(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a draining node
+	store := state.TestStateStore(t)
+	n := mock.Node()
+	n.DrainStrategy = &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{
+			Deadline: 5 * time.Minute,
+		},
+		ForceDeadline: time.Now().Add(1 * time.Minute),
+	}
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 100, n))
+
+	// Create a low and a high priority service job, and a batch job with an
+	// even lower priority that must not block the drain
+	newJob := func(job *structs.Job, priority int) *structs.Job {
+		job.Priority = priority
+		job.TaskGroups[0].Count = 1
+		return job
+	}
+	low := newJob(mock.Job(), 30)
+	high := newJob(mock.Job(), 70)
+	batch := newJob(mock.BatchJob(), 10)
+
+	allocFor := func(job *structs.Job) *structs.Allocation {
+		a := mock.Alloc()
+		a.Job = job
+		a.JobID = job.ID
+		a.TaskGroup = job.TaskGroups[0].Name
+		a.NodeID = n.ID
+		a.DeploymentStatus = &structs.AllocDeploymentStatus{
+			Healthy: helper.BoolToPtr(true),
+		}
+		return a
+	}
+
+	handle := func(job *structs.Job, alloc *structs.Allocation) *jobResult {
+		snap, err := store.Snapshot()
+		require.NoError(t, err)
+		res := newJobResult()
+		require.NoError(t, handleTaskGroup(snap, false, job.TaskGroups[0], []*structs.Allocation{alloc}, 100, res))
+		require.False(t, res.done)
+		return res
+	}
+
+	t.Run("job priority", func(t *testing.T) {
+		lowAlloc, highAlloc, batchAlloc := allocFor(low), allocFor(high), allocFor(batch)
+		require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 101,
+			[]*structs.Allocation{lowAlloc, highAlloc, batchAlloc}))
+
+		// The high priority alloc waits for the low priority one
+		require.Empty(t, handle(high, highAlloc).drain)
+		require.Len(t, handle(low, lowAlloc).drain, 1)
+
+		// Mark the low priority alloc for migration
+		lowAlloc = lowAlloc.Copy()
+		lowAlloc.DesiredTransition.Migrate = helper.BoolToPtr(true)
+		require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 102,
+			[]*structs.Allocation{lowAlloc}))
+		require.Len(t, handle(high, highAlloc).drain, 1)
+
+		require.NoError(t, store.DeleteEval(103, nil,
+			[]string{lowAlloc.ID, highAlloc.ID, batchAlloc.ID}, false))
+	})
+
+	t.Run("shutdown priority", func(t *testing.T) {
+		// The shutdown priority lowers the kill priority of the high priority
+		// job below the one of the low priority job
+		high = high.Copy()
+		high.TaskGroups[0].ShutdownPriority = -50
+
+		lowAlloc, highAlloc := allocFor(low), allocFor(high)
+		require.Equal(t, 30, lowAlloc.KillPriority())
+		require.Equal(t, 20, highAlloc.KillPriority())
+		require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 104,
+			[]*structs.Allocation{lowAlloc, highAlloc}))
+
+		require.Empty(t, handle(low, lowAlloc).drain)
+		require.Len(t, handle(high, highAlloc).drain, 1)
+	})
+}
+
 // This test asserts that handle task group works when an allocation is on a
 // garbage collected node
 func TestHandleTaskGroup_GarbageCollectedNode(t *testing.T) {
@@ -105,6 +105,18 @@ type EvalBroker struct {
 	// compounding after the first Nack.
 	subsequentNackDelay time.Duration
 
+	// fairness is the configuration of weighted fair queueing across
+	// namespaces. Evaluations are dequeued strictly by priority when it is
+	// nil.
+	fairness *structs.EvalFairnessConfig
+
+	// nsVirtualTime tracks the service each namespace has received relative
+	// to its share, and virtualTime is the virtual time of the last dequeue.
+	// Namespaces becoming active again start at virtualTime so that they
+	// cannot claim the service they missed while idle.
+	nsVirtualTime map[string]float64
+	virtualTime   float64
+
 	l sync.RWMutex
 }
 
@@ -149,6 +161,7 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		subsequentNackDelay:  subsequentNackDelay,
 		delayHeap:            delayheap.NewDelayHeap(),
 		delayedEvalsUpdateCh: make(chan struct{}, 1),
+		nsVirtualTime:        make(map[string]float64),
 	}
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
 	b.stats.DelayedEvals = make(map[string]*structs.Evaluation)
@@ -186,6 +199,22 @@ func (b *EvalBroker) SetEnabled(enabled bool) {
 	b.enabledNotifier.Notify("eval broker enabled status changed to " + strconv.FormatBool(enabled))
 }
 
+// SetFairness is used to configure weighted fair queueing of evaluations
+// across namespaces. Evaluations are dequeued strictly by priority if the
+// configuration is not enabled.
+func (b *EvalBroker) SetFairness(config *structs.EvalFairnessConfig) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if config == nil || !config.Enabled {
+		b.fairness = nil
+		b.nsVirtualTime = make(map[string]float64)
+		b.virtualTime = 0
+		return
+	}
+	b.fairness = config.Copy()
+}
+
 // Enqueue is used to enqueue a new evaluation
 func (b *EvalBroker) Enqueue(eval *structs.Evaluation) {
 	b.l.Lock()
@@ -438,10 +467,21 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string, error) {
 	// Get the pending queue
 	pending := b.ready[sched]
-	raw := heap.Pop(&pending)
+	var raw interface{}
+	if b.fairness != nil {
+		raw = heap.Remove(&pending, b.nextFair(pending))
+	} else {
+		raw = heap.Pop(&pending)
+	}
 	b.ready[sched] = pending
 	eval := raw.(*structs.Evaluation)
 
+	if b.fairness != nil {
+		start := b.nsStartTime(eval.Namespace)
+		b.nsVirtualTime[eval.Namespace] = start + 1/float64(b.fairness.Share(eval.Namespace))
+		b.virtualTime = start
+	}
+
 	// Generate a UUID for the token
 	token := uuid.Generate()
 
@@ -470,6 +510,32 @@ func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string,
 	return eval, token, nil
 }
 
+// nextFair returns the index of the evaluation to dequeue next under weighted
+// fair queueing. The namespace that received the least service relative to
+// its share goes first and ties are broken by the priority order. This
+// assumes locks are held and that the pending queue is not empty.
+func (b *EvalBroker) nextFair(pending PendingEvaluations) int {
+	next := 0
+	nextStart := b.nsStartTime(pending[0].Namespace)
+	for i := 1; i < len(pending); i++ {
+		start := b.nsStartTime(pending[i].Namespace)
+		if start < nextStart || (start == nextStart && pending.Less(i, next)) {
+			next = i
+			nextStart = start
+		}
+	}
+	return next
+}
+
+// nsStartTime returns the virtual time at which the next evaluation of the
+// namespace would start being served. This assumes locks are held.
+func (b *EvalBroker) nsStartTime(namespace string) float64 {
+	if vt := b.nsVirtualTime[namespace]; vt > b.virtualTime {
+		return vt
+	}
+	return b.virtualTime
+}
+
 // waitForSchedulers is used to wait for work on any of the scheduler or until a timeout.
 // Returns if there is work waiting potentially.
 func (b *EvalBroker) waitForSchedulers(schedulers []string, timeoutCh <-chan time.Time) bool {
@@ -742,6 +808,8 @@ func (b *EvalBroker) flush() {
 	b.unack = make(map[string]*unackEval)
 	b.timeWait = make(map[string]*time.Timer)
 	b.delayHeap = delayheap.NewDelayHeap()
+	b.nsVirtualTime = make(map[string]float64)
+	b.virtualTime = 0
 }
 
 // evalWrapper satisfies the HeapNode interface
@@ -904,9 +972,21 @@ func (p PendingEvaluations) Less(i, j int) bool {
 	if p[i].JobID != p[j].JobID && p[i].Priority != p[j].Priority {
 		return !(p[i].Priority < p[j].Priority)
 	}
+	if p[i].JobID != p[j].JobID && p[i].Deadline != p[j].Deadline {
+		return deadlineBefore(p[i].Deadline, p[j].Deadline)
+	}
 	return p[i].CreateIndex < p[j].CreateIndex
 }
 
+// deadlineBefore returns whether the deadline a is nearer than b, where a
+// zero deadline is never reached.
+func deadlineBefore(a, b int64) bool {
+	if a == 0 || b == 0 {
+		return b == 0
+	}
+	return a < b
+}
+
 // Swap is for the sorting interface
 func (p PendingEvaluations) Swap(i, j int) {
 	p[i], p[j] = p[j], p[i]
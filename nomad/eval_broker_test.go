@@ -806,6 +806,84 @@ func TestEvalBroker_Dequeue_Priority(t *testing.T) {
 	}
 }
 
+// Ensure evals with the nearest deadline are dequeued first at a fixed priority
+func TestEvalBroker_Dequeue_Deadline(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	now := time.Now().UnixNano()
+
+	eval1 := mock.Eval()
+	eval1.CreateIndex = 1
+	b.Enqueue(eval1)
+
+	eval2 := mock.Eval()
+	eval2.CreateIndex = 2
+	eval2.Deadline = now + int64(time.Hour)
+	b.Enqueue(eval2)
+
+	eval3 := mock.Eval()
+	eval3.CreateIndex = 3
+	eval3.Deadline = now + int64(time.Minute)
+	b.Enqueue(eval3)
+
+	for _, expected := range []*structs.Evaluation{eval3, eval2, eval1} {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, expected.ID, out.ID)
+	}
+}
+
+// Ensure evals are interleaved across namespaces by share when fairness is
+// enabled
+func TestEvalBroker_Dequeue_NamespaceFairness(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+	b.SetFairness(&structs.EvalFairnessConfig{
+		Enabled:         true,
+		NamespaceShares: map[string]int{"busy": 2},
+	})
+
+	for i := 0; i < 6; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "busy"
+		eval.Priority = 80
+		b.Enqueue(eval)
+	}
+	for i := 0; i < 3; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "quiet"
+		eval.Priority = 10
+		b.Enqueue(eval)
+	}
+
+	var namespaces []string
+	for i := 0; i < 9; i++ {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(t, err)
+		namespaces = append(namespaces, out.Namespace)
+	}
+	require.Equal(t, []string{
+		"busy", "quiet", "busy",
+		"busy", "quiet", "busy",
+		"busy", "quiet", "busy",
+	}, namespaces)
+
+	// Disabling fairness dequeues strictly by priority again
+	b.SetFairness(nil)
+	for _, ns := range []string{"quiet", "busy"} {
+		eval := mock.Eval()
+		eval.Namespace = ns
+		eval.Priority = map[string]int{"busy": 80, "quiet": 10}[ns]
+		b.Enqueue(eval)
+	}
+	out, _, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "busy", out.Namespace)
+}
+
 // Ensure FIFO at fixed priority
 func TestEvalBroker_Dequeue_FIFO(t *testing.T) {
 	ci.Parallel(t)
@@ -12,6 +12,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -101,6 +102,17 @@ func (e *Eval) GetEval(args *structs.EvalSpecificRequest,
 	return e.srv.blockingRPC(&opts)
 }
 
+// schedulingPaused returns whether scheduling is currently paused by the
+// scheduler configuration.
+func (e *Eval) schedulingPaused() bool {
+	_, schedConfig, err := e.srv.fsm.State().SchedulerConfig()
+	if err != nil {
+		e.logger.Error("failed to get scheduler config", "error", err)
+		return false
+	}
+	return schedConfig.SchedulingPaused(time.Now())
+}
+
 // Dequeue is used to dequeue a pending evaluation
 func (e *Eval) Dequeue(args *structs.EvalDequeueRequest,
 	reply *structs.EvalDequeueResponse) error {
@@ -149,6 +161,18 @@ func (e *Eval) Dequeue(args *structs.EvalDequeueRequest,
 		return nil
 	}
 
+	// If scheduling is paused, evaluations remain queued in the broker. Wait
+	// for the timeout before returning, which mimics the behaviour where
+	// there are no evals to process.
+	if e.schedulingPaused() {
+		metrics.IncrCounter([]string{"nomad", "eval", "dequeue_paused"}, 1)
+		select {
+		case <-time.After(args.Timeout):
+		case <-e.srv.shutdownCh:
+		}
+		return nil
+	}
+
 	// Attempt the dequeue
 	eval, token, err := e.srv.evalBroker.Dequeue(args.Schedulers, args.Timeout)
 	if err != nil {
@@ -499,6 +523,104 @@ func (e *Eval) Delete(
 	return nil
 }
 
+// Retry is used by operators to re-create a blocked or failed evaluation,
+// optionally overriding the placement options of the job for the new
+// evaluation.
+func (e *Eval) Retry(args *structs.EvalRetryRequest, reply *structs.EvalRetryResponse) error {
+	if done, err := e.srv.forward(structs.EvalRetryRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "retry"}, time.Now())
+
+	if args.EvalID == "" {
+		return errors.New("missing evaluation ID")
+	}
+
+	snap, err := e.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	ws := memdb.NewWatchSet()
+
+	eval, err := snap.EvalByID(ws, args.EvalID)
+	if err != nil {
+		return err
+	}
+	if eval == nil {
+		return errors.New("eval not found")
+	}
+
+	// Check for submit-job permissions in the namespace of the evaluation
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(eval.Namespace, acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	switch eval.Status {
+	case structs.EvalStatusBlocked, structs.EvalStatusFailed:
+	default:
+		return fmt.Errorf("eval %s has status %q; only blocked or failed evals can be retried",
+			eval.ID, eval.Status)
+	}
+
+	job, err := snap.JobByID(ws, eval.Namespace, eval.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("job %q not found", eval.JobID)
+	}
+
+	// Overriding the datacenters of system jobs would stop their allocations
+	// on the next evaluation, so only allow it for jobs where placements are
+	// not tied to the set of eligible nodes.
+	overrides := args.Overrides
+	if overrides.IsEmpty() {
+		overrides = nil
+	} else if job.Type != structs.JobTypeService && job.Type != structs.JobTypeBatch {
+		return fmt.Errorf("placement options can only be overridden for %s and %s jobs",
+			structs.JobTypeService, structs.JobTypeBatch)
+	}
+
+	now := time.Now().UnixNano()
+	newEval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      eval.Namespace,
+		Priority:       job.Priority,
+		Type:           job.Type,
+		TriggeredBy:    structs.EvalTriggerRetry,
+		JobID:          job.ID,
+		JobModifyIndex: job.ModifyIndex,
+		Status:         structs.EvalStatusPending,
+		PreviousEval:   eval.ID,
+		Overrides:      overrides.Copy(),
+		CreateTime:     now,
+		ModifyTime:     now,
+	}
+
+	update := &structs.EvalUpdateRequest{
+		Evals:        []*structs.Evaluation{newEval},
+		WriteRequest: structs.WriteRequest{Region: args.Region},
+	}
+	_, index, err := e.srv.raftApply(structs.EvalUpdateRequestType, update)
+	if err != nil {
+		return err
+	}
+
+	logger := e.logger.With("eval_id", eval.ID, "new_eval_id", newEval.ID)
+	if newEval.Overrides != nil {
+		logger = logger.With("datacenters", newEval.Overrides.Datacenters,
+			"ignore_soft_constraints", newEval.Overrides.IgnoreSoftConstraints)
+	}
+	logger.Info("evaluation retried")
+
+	reply.EvalID = newEval.ID
+	reply.EvalCreateIndex = index
+	reply.Index = index
+	return nil
+}
+
 // evalDeleteSafe ensures an evaluation is safe to delete based on its related
 // allocation and job information. This follows similar, but different rules to
 // the eval reap checking, to ensure evaluations for running allocs or allocs
@@ -562,6 +684,35 @@ func evalDeleteSafe(allocs []*structs.Allocation, job *structs.Job) bool {
 }
 
 // List is used to get a list of the evaluations in the system
+// BlockedResources is used to get the resources requested by blocked
+// evaluations by datacenter and node class, so autoscalers can add capacity
+// to the nodes the evaluations are blocked on.
+func (e *Eval) BlockedResources(args *structs.GenericRequest, reply *structs.EvalBlockedResourcesResponse) error {
+	// Blocked evaluations are only tracked by the leader
+	args.AllowStale = false
+	if done, err := e.srv.forward("Eval.BlockedResources", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "blocked_resources"}, time.Now())
+
+	// Check for node read permissions
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	index, err := e.srv.fsm.State().Index("evals")
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	reply.Demand = e.srv.blockedEvals.Stats().BlockedResources.Demand()
+
+	e.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 func (e *Eval) List(args *structs.EvalListRequest, reply *structs.EvalListResponse) error {
 	if done, err := e.srv.forward("Eval.List", args, args, reply); done {
 		return err
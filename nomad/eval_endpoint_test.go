@@ -481,6 +481,46 @@ func TestEvalEndpoint_Dequeue_BrokerDisabled(t *testing.T) {
 	require.Empty(t, resp.Eval)
 }
 
+func TestEvalEndpoint_Dequeue_SchedulingPaused(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue.
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Pause scheduling and enqueue an eval.
+	_, schedConfig, err := s1.fsm.State().SchedulerConfig()
+	require.NoError(t, err)
+	newConfig := *schedConfig
+	newConfig.PauseScheduling = true
+	require.NoError(t, s1.fsm.State().SchedulerSetConfig(1000, &newConfig))
+
+	eval1 := mock.Eval()
+	s1.evalBroker.Enqueue(eval1)
+
+	get := &structs.EvalDequeueRequest{
+		Schedulers:       defaultSched,
+		SchedulerVersion: scheduler.SchedulerVersion,
+		Timeout:          50 * time.Millisecond,
+		WriteRequest:     structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.EvalDequeueResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Dequeue", get, &resp))
+	require.Empty(t, resp.Eval)
+
+	// The eval remains queued and is dequeued once the pause expires.
+	require.Equal(t, 1, s1.evalBroker.Stats().TotalReady)
+
+	newConfig.PauseSchedulingUntil = time.Now().Add(-time.Second).UnixNano()
+	require.NoError(t, s1.fsm.State().SchedulerSetConfig(1001, &newConfig))
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Dequeue", get, &resp))
+	require.Equal(t, eval1.ID, resp.Eval.ID)
+}
+
 func TestEvalEndpoint_Ack(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1059,6 +1099,158 @@ func Test_evalDeleteSafe(t *testing.T) {
 	}
 }
 
+func TestEvalEndpoint_Retry(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	blocked := mock.Eval()
+	blocked.JobID = job.ID
+	blocked.Status = structs.EvalStatusBlocked
+	complete := mock.Eval()
+	complete.JobID = job.ID
+	complete.Status = structs.EvalStatusComplete
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1001,
+		[]*structs.Evaluation{complete}))
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1002,
+		[]*structs.Evaluation{blocked}))
+
+	// Only blocked or failed evals can be retried
+	req := &structs.EvalRetryRequest{
+		EvalID:       complete.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.EvalRetryResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.EvalRetryRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "only blocked or failed evals can be retried")
+
+	// Retry the blocked eval overriding the datacenters
+	req.EvalID = blocked.ID
+	req.Overrides = &structs.EvalOverrides{Datacenters: []string{"dc2"}}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.EvalRetryRPCMethod, req, &resp))
+	require.NotZero(t, resp.Index)
+
+	out, err := state.EvalByID(nil, resp.EvalID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, structs.EvalStatusPending, out.Status)
+	require.Equal(t, structs.EvalTriggerRetry, out.TriggeredBy)
+	require.Equal(t, blocked.ID, out.PreviousEval)
+	stateJob, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, stateJob.ModifyIndex, out.JobModifyIndex)
+	require.Equal(t, []string{"dc2"}, out.Overrides.Datacenters)
+
+	// Overrides are not allowed for system jobs
+	sysJob := mock.SystemJob()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1003, sysJob))
+	sysEval := mock.Eval()
+	sysEval.JobID = sysJob.ID
+	sysEval.Type = structs.JobTypeSystem
+	sysEval.Status = structs.EvalStatusFailed
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1004,
+		[]*structs.Evaluation{sysEval}))
+
+	req.EvalID = sysEval.ID
+	err = msgpackrpc.CallWithCodec(codec, structs.EvalRetryRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "placement options can only be overridden")
+}
+
+func TestEvalEndpoint_Retry_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	eval.Status = structs.EvalStatusFailed
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1001,
+		[]*structs.Evaluation{eval}))
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1002, "test-read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	submitToken := mock.CreatePolicyAndToken(t, state, 1003, "test-submit",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+
+	req := &structs.EvalRetryRequest{
+		EvalID:       eval.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.EvalRetryResponse
+
+	req.AuthToken = readToken.SecretID
+	err := msgpackrpc.CallWithCodec(codec, structs.EvalRetryRPCMethod, req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = submitToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.EvalRetryRPCMethod, req, &resp))
+
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.EvalRetryRPCMethod, req, &resp))
+}
+
+func TestEvalEndpoint_BlockedResources(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Block an eval on a class of nodes lacking capacity
+	eval := mock.Eval()
+	eval.Status = structs.EvalStatusBlocked
+	eval.FailedTGAllocs = map[string]*structs.AllocMetric{
+		"web": {
+			NodesAvailable: map[string]int{"dc1": 2},
+			ClassExhausted: map[string]int{"large": 2},
+			ResourcesExhausted: map[string]*structs.Resources{
+				"web": {CPU: 500, MemoryMB: 256},
+			},
+		},
+	}
+	s1.blockedEvals.Block(eval)
+
+	get := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     "global",
+			AllowStale: true,
+		},
+	}
+
+	// Lookup without node read permissions should fail
+	var resp structs.EvalBlockedResourcesResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.BlockedResources", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	get.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.BlockedResources", get, &resp))
+	require.Equal(t, []*structs.BlockedResourcesDemand{{
+		Datacenter: "dc1",
+		NodeClass:  "large",
+		Evals:      1,
+		CPU:        500,
+		MemoryMB:   256,
+	}}, resp.Demand)
+}
+
 func TestEvalEndpoint_List(t *testing.T) {
 	ci.Parallel(t)
 
@@ -41,6 +41,18 @@ func (e *Event) stream(conn io.ReadWriteCloser) {
 		return
 	}
 
+	// route stale streams received by a voter to a read replica
+	if args.AllowStale && !args.IsForwarded() && !e.srv.config.ReadReplica {
+		if replica := e.srv.getReadReplica(); replica != nil {
+			args.SetForwarded()
+			err := e.forwardStreamingRPCToServer(replica, "Event.Stream", args, conn)
+			if err != nil {
+				handleJsonResultError(err, helper.Int64ToPtr(500), encoder)
+			}
+			return
+		}
+	}
+
 	// Generate the subscription request
 	subReq := &stream.SubscribeRequest{
 		Token:     args.AuthToken,
@@ -147,6 +159,32 @@ OUTER:
 
 }
 
+// Metrics returns the metrics of the events published by the EventBroker of
+// the server, and of its subscribers. Stale requests are answered by the
+// server receiving them, others by the leader.
+func (e *Event) Metrics(args *structs.GenericRequest, reply *structs.EventMetricsResponse) error {
+	if done, err := e.srv.forward("Event.Metrics", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	publisher, err := e.srv.State().EventBroker()
+	if err != nil {
+		return err
+	}
+
+	reply.Metrics = publisher.Metrics()
+	e.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 func (e *Event) forwardStreamingRPC(region string, method string, args interface{}, in io.ReadWriteCloser) error {
 	server, err := e.srv.findRegionServer(region)
 	if err != nil {
@@ -625,3 +625,46 @@ OUTER:
 		}
 	}
 }
+
+func TestEvent_Metrics(t *testing.T) {
+	ci.Parallel(t)
+
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	publisher, err := s.State().EventBroker()
+	require.NoError(t, err)
+	publisher.Publish(&structs.Events{Index: 1000, Events: []structs.Event{
+		{Topic: structs.TopicJob, Type: structs.TypeJobRegistered},
+	}})
+
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policy)
+	tokenOperator := mock.CreatePolicyAndToken(t, s.State(), 1006, "operator", mock.OperatorPolicy("read"))
+
+	// Tokens without operator read are denied
+	args := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: tokenBad.SecretID},
+	}
+	var resp structs.EventMetricsResponse
+	err = msgpackrpc.CallWithCodec(codec, "Event.Metrics", args, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	for _, token := range []string{tokenOperator.SecretID, root.SecretID} {
+		args.AuthToken = token
+		testutil.WaitForResult(func() (bool, error) {
+			var resp structs.EventMetricsResponse
+			if err := msgpackrpc.CallWithCodec(codec, "Event.Metrics", args, &resp); err != nil {
+				return false, err
+			}
+			if n := resp.Metrics.Published[structs.TopicJob][structs.TypeJobRegistered]; n < 1 {
+				return false, fmt.Errorf("expected published job event, got %#v", resp.Metrics.Published)
+			}
+			return true, nil
+		}, func(err error) {
+			require.NoError(t, err)
+		})
+	}
+}
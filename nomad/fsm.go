@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"reflect"
@@ -58,6 +59,8 @@ const (
 	SecureVariablesSnapshot              SnapshotType = 22
 	SecureVariablesQuotaSnapshot         SnapshotType = 23
 	RootKeyMetaSnapshot                  SnapshotType = 24
+	NodeIntroTokenSnapshot               SnapshotType = 25
+	TrashedJobSnapshot                   SnapshotType = 26
 
 	// Namespace appliers were moved from enterprise and therefore start at 64
 	NamespaceSnapshot SnapshotType = 64
@@ -251,6 +254,8 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyDeploymentDelete(buf[1:], log.Index)
 	case structs.JobStabilityRequestType:
 		return n.applyJobStability(buf[1:], log.Index)
+	case structs.JobFreezeRequestType:
+		return n.applyJobFreeze(buf[1:], log.Index)
 	case structs.ACLPolicyUpsertRequestType:
 		return n.applyACLPolicyUpsert(msgType, buf[1:], log.Index)
 	case structs.ACLPolicyDeleteRequestType:
@@ -311,6 +316,16 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyOneTimeTokenDelete(msgType, buf[1:], log.Index)
 	case structs.OneTimeTokenExpireRequestType:
 		return n.applyOneTimeTokenExpire(msgType, buf[1:], log.Index)
+	case structs.NodeIntroTokenUpsertRequestType:
+		return n.applyNodeIntroTokenUpsert(msgType, buf[1:], log.Index)
+	case structs.NodeIntroTokenConsumeRequestType:
+		return n.applyNodeIntroTokenConsume(msgType, buf[1:], log.Index)
+	case structs.NodeIntroTokenExpireRequestType:
+		return n.applyNodeIntroTokenExpire(msgType, buf[1:], log.Index)
+	case structs.JobRestoreRequestType:
+		return n.applyJobRestore(msgType, buf[1:], log.Index)
+	case structs.JobTrashExpireRequestType:
+		return n.applyJobTrashExpire(msgType, buf[1:], log.Index)
 	case structs.ServiceRegistrationUpsertRequestType:
 		return n.applyUpsertServiceRegistrations(msgType, buf[1:], log.Index)
 	case structs.ServiceRegistrationDeleteByIDRequestType:
@@ -321,10 +336,18 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applySecureVariableUpsert(msgType, buf[1:], log.Index)
 	case structs.SecureVariableDeleteRequestType:
 		return n.applySecureVariableDelete(msgType, buf[1:], log.Index)
+	case structs.SecureVariablesTxnRequestType:
+		return n.applySecureVariablesTxn(msgType, buf[1:], log.Index)
 	case structs.RootKeyMetaUpsertRequestType:
 		return n.applyRootKeyMetaUpsert(msgType, buf[1:], log.Index)
 	case structs.RootKeyMetaDeleteRequestType:
 		return n.applyRootKeyMetaDelete(msgType, buf[1:], log.Index)
+	case structs.ReconcileDeploymentsRequestType:
+		return n.applyReconcileDeployments(msgType, buf[1:], log.Index)
+	case structs.ReconcileServiceRegistrationsRequestType:
+		return n.applyReconcileServiceRegistrations(msgType, buf[1:], log.Index)
+	case structs.NodeBatchUpdateStatusRequestType:
+		return n.applyBatchStatusUpdate(msgType, buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -442,6 +465,39 @@ func (n *nomadFSM) applyStatusUpdate(msgType structs.MessageType, buf []byte, in
 	return nil
 }
 
+func (n *nomadFSM) applyBatchStatusUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_batch_status_update"}, time.Now())
+	var req structs.NodeBatchUpdateStatusRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.BatchUpdateNodeStatus(msgType, index, req.Updates); err != nil {
+		n.logger.Error("BatchUpdateNodeStatus failed", "error", err)
+		return err
+	}
+
+	// Unblock evals for the computed node class of the nodes that are now in
+	// a ready state.
+	for _, update := range req.Updates {
+		if update.Status != structs.NodeStatusReady {
+			continue
+		}
+		node, err := n.state.NodeByID(nil, update.NodeID)
+		if err != nil {
+			n.logger.Error("looking up node failed", "node_id", update.NodeID, "error", err)
+			return err
+		}
+		if node == nil {
+			continue
+		}
+		n.blockedEvals.Unblock(node.ComputedClass, index)
+		n.blockedEvals.UnblockNode(update.NodeID, index)
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyDrainUpdate(reqType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_drain_update"}, time.Now())
 	var req structs.NodeUpdateDrainRequest
@@ -625,7 +681,16 @@ func (n *nomadFSM) applyDeregisterJob(msgType structs.MessageType, buf []byte, i
 	}
 
 	err := n.state.WithWriteTransaction(msgType, index, func(tx state.Txn) error {
-		err := n.handleJobDeregister(index, req.JobID, req.Namespace, req.Purge, req.NoShutdownDelay, tx)
+		// Keep the purged job in the trash so it can be restored
+		if req.Purge && !req.TrashExpiresAt.IsZero() {
+			if err := n.state.TrashJobTxn(index, req.Namespace, req.JobID, req.TrashExpiresAt, tx); err != nil {
+				n.logger.Error("trashing job failed",
+					"error", err, "job", req.JobID, "namespace", req.Namespace)
+				return err
+			}
+		}
+
+		err := n.handleJobDeregister(index, req.JobID, req.Namespace, req.Purge, req.NoShutdownDelay, req.IdempotencyToken, tx)
 
 		if err != nil {
 			n.logger.Error("deregistering job failed",
@@ -665,7 +730,7 @@ func (n *nomadFSM) applyBatchDeregisterJob(msgType structs.MessageType, buf []by
 	// evals for jobs whose deregistering didn't get committed yet.
 	err := n.state.WithWriteTransaction(msgType, index, func(tx state.Txn) error {
 		for jobNS, options := range req.Jobs {
-			if err := n.handleJobDeregister(index, jobNS.ID, jobNS.Namespace, options.Purge, false, tx); err != nil {
+			if err := n.handleJobDeregister(index, jobNS.ID, jobNS.Namespace, options.Purge, false, "", tx); err != nil {
 				n.logger.Error("deregistering job failed", "job", jobNS.ID, "error", err)
 				return err
 			}
@@ -690,7 +755,7 @@ func (n *nomadFSM) applyBatchDeregisterJob(msgType structs.MessageType, buf []by
 
 // handleJobDeregister is used to deregister a job. Leaves error logging up to
 // caller.
-func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, purge bool, noShutdownDelay bool, tx state.Txn) error {
+func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, purge bool, noShutdownDelay bool, idempotencyToken string, tx state.Txn) error {
 	// If it is periodic remove it from the dispatcher
 	if err := n.periodicDispatcher.Remove(namespace, jobID); err != nil {
 		return fmt.Errorf("periodicDispatcher.Remove failed: %w", err)
@@ -738,6 +803,7 @@ func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, pu
 
 		stopped := current.Copy()
 		stopped.Stop = true
+		stopped.IdempotencyToken = idempotencyToken
 
 		if err := n.state.UpsertJobTxn(index, stopped, tx); err != nil {
 			return fmt.Errorf("UpsertJob failed: %w", err)
@@ -934,6 +1000,28 @@ func (n *nomadFSM) applyReconcileSummaries(buf []byte, index uint64) interface{}
 	return n.reconcileQueuedAllocations(index)
 }
 
+// applyReconcileDeployments recomputes the allocation counts of the active
+// deployments.
+func (n *nomadFSM) applyReconcileDeployments(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "reconcile_deployments"}, time.Now())
+	if err := n.state.ReconcileDeployments(msgType, index); err != nil {
+		n.logger.Error("ReconcileDeployments failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyReconcileServiceRegistrations deletes the service registrations of
+// missing or terminal allocations.
+func (n *nomadFSM) applyReconcileServiceRegistrations(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "reconcile_service_registrations"}, time.Now())
+	if err := n.state.ReconcileServiceRegistrations(msgType, index); err != nil {
+		n.logger.Error("ReconcileServiceRegistrations failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 // applyUpsertNodeEvent tracks the given node events.
 func (n *nomadFSM) applyUpsertNodeEvent(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "upsert_node_events"}, time.Now())
@@ -1116,6 +1204,22 @@ func (n *nomadFSM) applyJobStability(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyJobFreeze is used to freeze or unfreeze a job
+func (n *nomadFSM) applyJobFreeze(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_freeze"}, time.Now())
+	var req structs.JobFreezeRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateJobFreeze(index, req.Namespace, req.JobID, req.Frozen); err != nil {
+		n.logger.Error("UpdateJobFreeze failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 // applyACLPolicyUpsert is used to upsert a set of policies
 func (n *nomadFSM) applyACLPolicyUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_policy_upsert"}, time.Now())
@@ -1236,6 +1340,83 @@ func (n *nomadFSM) applyOneTimeTokenExpire(msgType structs.MessageType, buf []by
 	return nil
 }
 
+// applyNodeIntroTokenUpsert is used to upsert a node introduction token
+func (n *nomadFSM) applyNodeIntroTokenUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_intro_token_upsert"}, time.Now())
+	var req structs.NodeIntroToken
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertNodeIntroToken(msgType, index, &req); err != nil {
+		n.logger.Error("UpsertNodeIntroToken failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyNodeIntroTokenConsume is used to consume a node introduction token
+func (n *nomadFSM) applyNodeIntroTokenConsume(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_intro_token_consume"}, time.Now())
+	var req structs.NodeIntroTokenConsumeRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.ConsumeNodeIntroToken(msgType, index, req.AccessorID); err != nil {
+		n.logger.Error("ConsumeNodeIntroToken failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyJobRestore is used to restore a purged job from the job trash
+func (n *nomadFSM) applyJobRestore(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_restore"}, time.Now())
+	var req structs.JobRestoreRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.RestoreTrashedJob(msgType, index, req.Namespace, req.JobID); err != nil {
+		n.logger.Error("RestoreTrashedJob failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyJobTrashExpire is used to permanently delete the expired jobs in the
+// job trash
+func (n *nomadFSM) applyJobTrashExpire(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_trash_expire"}, time.Now())
+	var req structs.JobTrashExpireRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.ExpireTrashedJobs(msgType, index, req.Timestamp); err != nil {
+		n.logger.Error("ExpireTrashedJobs failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyNodeIntroTokenExpire is used to delete the expired node introduction
+// tokens
+func (n *nomadFSM) applyNodeIntroTokenExpire(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_intro_token_expire"}, time.Now())
+	var req structs.NodeIntroTokenExpireRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.ExpireNodeIntroTokens(msgType, index, req.Timestamp); err != nil {
+		n.logger.Error("ExpireNodeIntroTokens failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
 	var req structs.AutopilotSetConfigRequest
 	if err := structs.Decode(buf, &req); err != nil {
@@ -1420,17 +1601,26 @@ func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
 // Restore implements the raft.FSM interface, which doesn't support a
 // filtering parameter
 func (n *nomadFSM) Restore(old io.ReadCloser) error {
-	return n.restoreImpl(old, nil)
+	return n.restoreImpl(old, nil, nil)
 }
 
 // RestoreWithFilter includes a set of bexpr filter evaluators, so
 // that we can create a FSM that excludes a portion of a snapshot
 // (typically for debugging and testing)
 func (n *nomadFSM) RestoreWithFilter(old io.ReadCloser, filter *FSMFilter) error {
-	return n.restoreImpl(old, filter)
+	return n.restoreImpl(old, filter, nil)
 }
 
-func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
+// RestoreWithProgress is RestoreWithFilter with a callback reporting the
+// progress of the restore, for restores of large snapshots by tools.
+func (n *nomadFSM) RestoreWithProgress(old io.ReadCloser, filter *FSMFilter, progress RestoreProgressFn) error {
+	return n.restoreImpl(old, filter, progress)
+}
+
+// restoreImpl restores the state from a snapshot. The objects of the snapshot
+// are decoded concurrently and restored in stream order, so that objects are
+// still restored after the objects they depend on.
+func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter, progress RestoreProgressFn) error {
 	defer old.Close()
 
 	// Create a new state store
@@ -1452,8 +1642,10 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 	}
 	defer restore.Abort()
 
-	// Create a decoder
-	dec := codec.NewDecoder(old, structs.MsgpackHandle)
+	// Create a decoder. The snapshot stream is buffered, as the decoder and
+	// the reads of the object types otherwise read it a byte at a time.
+	r := bufio.NewReader(old)
+	dec := codec.NewDecoder(r, structs.MsgpackHandle)
 
 	// Read in the header
 	var header snapshotHeader
@@ -1461,28 +1653,36 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 		return err
 	}
 
+	// Allocations in the snapshot each carry their own copy of their job, so
+	// deduplicate them as they are restored
+	interner := newAllocInterner()
+
 	// Populate the new state
-	msgType := make([]byte, 1)
-	for {
-		// Read the message type
-		_, err := old.Read(msgType)
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return err
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	var restored int
+	for item := range readSnapshot(r, dec, stopCh) {
+		<-item.decoded
+		if item.err != nil {
+			return item.err
+		}
+		filter.Redact(item.obj)
+
+		restored++
+		if progress != nil && restored%restoreProgressInterval == 0 {
+			progress(restored)
 		}
 
-		// Decode
-		snapType := SnapshotType(msgType[0])
+		snapType := item.snapType
 		switch snapType {
 		case TimeTableSnapshot:
-			if err := n.timetable.Deserialize(dec); err != nil {
+			if err := n.timetable.Deserialize(item.decoder()); err != nil {
 				return fmt.Errorf("time table deserialize failed: %v", err)
 			}
 
 		case NodeSnapshot:
 			node := new(structs.Node)
-			if err := dec.Decode(node); err != nil {
+			if err := item.Decode(node); err != nil {
 				return err
 			}
 			if filter.Include(node) {
@@ -1494,7 +1694,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case JobSnapshot:
 			job := new(structs.Job)
-			if err := dec.Decode(job); err != nil {
+			if err := item.Decode(job); err != nil {
 				return err
 			}
 			if filter.Include(job) {
@@ -1512,7 +1712,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case EvalSnapshot:
 			eval := new(structs.Evaluation)
-			if err := dec.Decode(eval); err != nil {
+			if err := item.Decode(eval); err != nil {
 				return err
 			}
 			if filter.Include(eval) {
@@ -1523,10 +1723,11 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case AllocSnapshot:
 			alloc := new(structs.Allocation)
-			if err := dec.Decode(alloc); err != nil {
+			if err := item.Decode(alloc); err != nil {
 				return err
 			}
 			if filter.Include(alloc) {
+				interner.intern(alloc)
 				alloc.Canonicalize() // Handle upgrade path
 				if err := restore.AllocRestore(alloc); err != nil {
 					return err
@@ -1535,7 +1736,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case IndexSnapshot:
 			idx := new(state.IndexEntry)
-			if err := dec.Decode(idx); err != nil {
+			if err := item.Decode(idx); err != nil {
 				return err
 			}
 			if err := restore.IndexRestore(idx); err != nil {
@@ -1544,7 +1745,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case PeriodicLaunchSnapshot:
 			launch := new(structs.PeriodicLaunch)
-			if err := dec.Decode(launch); err != nil {
+			if err := item.Decode(launch); err != nil {
 				return err
 			}
 			if filter.Include(launch) {
@@ -1555,7 +1756,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case JobSummarySnapshot:
 			summary := new(structs.JobSummary)
-			if err := dec.Decode(summary); err != nil {
+			if err := item.Decode(summary); err != nil {
 				return err
 			}
 			if filter.Include(summary) {
@@ -1566,7 +1767,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case VaultAccessorSnapshot:
 			accessor := new(structs.VaultAccessor)
-			if err := dec.Decode(accessor); err != nil {
+			if err := item.Decode(accessor); err != nil {
 				return err
 			}
 			if filter.Include(accessor) {
@@ -1577,7 +1778,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case ServiceIdentityTokenAccessorSnapshot:
 			accessor := new(structs.SITokenAccessor)
-			if err := dec.Decode(accessor); err != nil {
+			if err := item.Decode(accessor); err != nil {
 				return err
 			}
 			if filter.Include(accessor) {
@@ -1588,7 +1789,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case JobVersionSnapshot:
 			version := new(structs.Job)
-			if err := dec.Decode(version); err != nil {
+			if err := item.Decode(version); err != nil {
 				return err
 			}
 			if filter.Include(version) {
@@ -1599,7 +1800,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case DeploymentSnapshot:
 			deployment := new(structs.Deployment)
-			if err := dec.Decode(deployment); err != nil {
+			if err := item.Decode(deployment); err != nil {
 				return err
 			}
 			if filter.Include(deployment) {
@@ -1610,7 +1811,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case ACLPolicySnapshot:
 			policy := new(structs.ACLPolicy)
-			if err := dec.Decode(policy); err != nil {
+			if err := item.Decode(policy); err != nil {
 				return err
 			}
 			if filter.Include(policy) {
@@ -1621,7 +1822,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case ACLTokenSnapshot:
 			token := new(structs.ACLToken)
-			if err := dec.Decode(token); err != nil {
+			if err := item.Decode(token); err != nil {
 				return err
 			}
 			if filter.Include(token) {
@@ -1632,7 +1833,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case SchedulerConfigSnapshot:
 			schedConfig := new(structs.SchedulerConfiguration)
-			if err := dec.Decode(schedConfig); err != nil {
+			if err := item.Decode(schedConfig); err != nil {
 				return err
 			}
 			schedConfig.Canonicalize()
@@ -1642,7 +1843,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case ClusterMetadataSnapshot:
 			meta := new(structs.ClusterMetadata)
-			if err := dec.Decode(meta); err != nil {
+			if err := item.Decode(meta); err != nil {
 				return err
 			}
 			if err := restore.ClusterMetadataRestore(meta); err != nil {
@@ -1651,7 +1852,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case ScalingEventsSnapshot:
 			jobScalingEvents := new(structs.JobScalingEvents)
-			if err := dec.Decode(jobScalingEvents); err != nil {
+			if err := item.Decode(jobScalingEvents); err != nil {
 				return err
 			}
 			if filter.Include(jobScalingEvents) {
@@ -1662,7 +1863,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case ScalingPolicySnapshot:
 			scalingPolicy := new(structs.ScalingPolicy)
-			if err := dec.Decode(scalingPolicy); err != nil {
+			if err := item.Decode(scalingPolicy); err != nil {
 				return err
 			}
 			if filter.Include(scalingPolicy) {
@@ -1676,7 +1877,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case CSIPluginSnapshot:
 			plugin := new(structs.CSIPlugin)
-			if err := dec.Decode(plugin); err != nil {
+			if err := item.Decode(plugin); err != nil {
 				return err
 			}
 			if filter.Include(plugin) {
@@ -1687,7 +1888,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case CSIVolumeSnapshot:
 			volume := new(structs.CSIVolume)
-			if err := dec.Decode(volume); err != nil {
+			if err := item.Decode(volume); err != nil {
 				return err
 			}
 			if filter.Include(volume) {
@@ -1698,7 +1899,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case NamespaceSnapshot:
 			namespace := new(structs.Namespace)
-			if err := dec.Decode(namespace); err != nil {
+			if err := item.Decode(namespace); err != nil {
 				return err
 			}
 			if err := restore.NamespaceRestore(namespace); err != nil {
@@ -1711,7 +1912,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case ServiceRegistrationSnapshot:
 			serviceRegistration := new(structs.ServiceRegistration)
-			if err := dec.Decode(serviceRegistration); err != nil {
+			if err := item.Decode(serviceRegistration); err != nil {
 				return err
 			}
 			if filter.Include(serviceRegistration) {
@@ -1723,7 +1924,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case SecureVariablesSnapshot:
 			variable := new(structs.SecureVariableEncrypted)
-			if err := dec.Decode(variable); err != nil {
+			if err := item.Decode(variable); err != nil {
 				return err
 			}
 
@@ -1733,7 +1934,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case SecureVariablesQuotaSnapshot:
 			quota := new(structs.SecureVariablesQuota)
-			if err := dec.Decode(quota); err != nil {
+			if err := item.Decode(quota); err != nil {
 				return err
 			}
 
@@ -1743,7 +1944,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 
 		case RootKeyMetaSnapshot:
 			keyMeta := new(structs.RootKeyMeta)
-			if err := dec.Decode(keyMeta); err != nil {
+			if err := item.Decode(keyMeta); err != nil {
 				return err
 			}
 
@@ -1751,15 +1952,35 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case NodeIntroTokenSnapshot:
+			token := new(structs.NodeIntroToken)
+			if err := item.Decode(token); err != nil {
+				return err
+			}
+
+			if err := restore.NodeIntroTokenRestore(token); err != nil {
+				return err
+			}
+
+		case TrashedJobSnapshot:
+			trashed := new(structs.TrashedJob)
+			if err := item.Decode(trashed); err != nil {
+				return err
+			}
+
+			if err := restore.TrashedJobRestore(trashed); err != nil {
+				return err
+			}
+
 		default:
 			// Check if this is an enterprise only object being restored
 			restorer, ok := n.enterpriseRestorers[snapType]
 			if !ok {
-				return fmt.Errorf("Unrecognized snapshot type: %v", msgType)
+				return fmt.Errorf("Unrecognized snapshot type: %v", snapType)
 			}
 
 			// Restore the enterprise only object
-			if err := restorer(restore, dec); err != nil {
+			if err := restorer(restore, item.decoder()); err != nil {
 				return err
 			}
 		}
@@ -1768,6 +1989,9 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 	if err := restore.Commit(); err != nil {
 		return err
 	}
+	if progress != nil {
+		progress(restored)
+	}
 
 	// COMPAT Remove in 0.10
 	// Clean up active deployments that do not have a job
@@ -2012,6 +2236,10 @@ func (n *nomadFSM) applyDeleteServiceRegistrationByNodeID(msgType structs.Messag
 
 type FSMFilter struct {
 	evaluator *bexpr.Evaluator
+
+	// redact is whether the secrets of the objects are redacted as they are
+	// restored.
+	redact bool
 }
 
 func NewFSMFilter(expr string) (*FSMFilter, error) {
@@ -2025,8 +2253,24 @@ func NewFSMFilter(expr string) (*FSMFilter, error) {
 	return &FSMFilter{evaluator: evaluator}, nil
 }
 
+// NewRedactingFSMFilter returns a FSMFilter that, in addition to filtering
+// objects like NewFSMFilter, redacts the secrets of the objects it includes,
+// so that the state of a snapshot can be shared without leaking them. See
+// Redact for the secrets redacted.
+func NewRedactingFSMFilter(expr string) (*FSMFilter, error) {
+	filter := &FSMFilter{redact: true}
+	if expr != "" {
+		evaluator, err := bexpr.CreateEvaluator(expr)
+		if err != nil {
+			return nil, err
+		}
+		filter.evaluator = evaluator
+	}
+	return filter, nil
+}
+
 func (f *FSMFilter) Include(item interface{}) bool {
-	if f == nil {
+	if f == nil || f.evaluator == nil {
 		return true
 	}
 	ok, err := f.evaluator.Evaluate(item)
@@ -2036,6 +2280,68 @@ func (f *FSMFilter) Include(item interface{}) bool {
 	return true
 }
 
+// allocInterner deduplicates the jobs and the repeated identifier strings of
+// allocations as they are decoded from a snapshot. Every allocation in a
+// snapshot embeds a full copy of its job, so a large cluster would otherwise
+// hold one job per allocation in memory rather than one per job version.
+//
+// Task state histories and allocated resources are still decoded eagerly.
+// Decoding them lazily requires a new wire type for allocations in snapshots
+// and raft logs, which older servers can't decode, so it is not done here.
+type allocInterner struct {
+	jobs    map[allocJobKey]*structs.Job
+	strings map[string]string
+}
+
+// allocJobKey identifies a single version of a job. Jobs embedded in
+// allocations are never modified in place, so two allocations whose jobs
+// share a key hold identical jobs.
+type allocJobKey struct {
+	namespace   string
+	id          string
+	modifyIndex uint64
+}
+
+func newAllocInterner() *allocInterner {
+	return &allocInterner{
+		jobs:    make(map[allocJobKey]*structs.Job),
+		strings: make(map[string]string),
+	}
+}
+
+// intern replaces the job and identifier strings of the allocation with
+// previously seen copies.
+func (i *allocInterner) intern(alloc *structs.Allocation) {
+	if alloc.Job != nil {
+		key := allocJobKey{
+			namespace:   alloc.Job.Namespace,
+			id:          alloc.Job.ID,
+			modifyIndex: alloc.Job.ModifyIndex,
+		}
+		if job, ok := i.jobs[key]; ok {
+			alloc.Job = job
+		} else {
+			i.jobs[key] = alloc.Job
+		}
+	}
+
+	alloc.Namespace = i.string(alloc.Namespace)
+	alloc.EvalID = i.string(alloc.EvalID)
+	alloc.NodeID = i.string(alloc.NodeID)
+	alloc.NodeName = i.string(alloc.NodeName)
+	alloc.JobID = i.string(alloc.JobID)
+	alloc.TaskGroup = i.string(alloc.TaskGroup)
+	alloc.DeploymentID = i.string(alloc.DeploymentID)
+}
+
+func (i *allocInterner) string(s string) string {
+	if interned, ok := i.strings[s]; ok {
+		return interned
+	}
+	i.strings[s] = s
+	return s
+}
+
 func (n *nomadFSM) applySecureVariableUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_secure_variable_upsert"}, time.Now())
 	var req structs.SecureVariablesEncryptedUpsertRequest
@@ -2066,6 +2372,28 @@ func (n *nomadFSM) applySecureVariableDelete(msgType structs.MessageType, buf []
 	return nil
 }
 
+// applySecureVariablesTxn applies a secure variables transaction. It returns
+// the conflicting secure variables if the transaction wasn't applied because
+// of check index mismatches.
+func (n *nomadFSM) applySecureVariablesTxn(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_secure_variables_txn"}, time.Now())
+	var req structs.SecureVariablesEncryptedTxnRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	conflicts, err := n.state.SecureVariablesTxn(msgType, index, req.Ops)
+	if err != nil {
+		n.logger.Error("SecureVariablesTxn failed", "error", err)
+		return err
+	}
+	if conflicts != nil {
+		return conflicts
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyRootKeyMetaUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_meta_upsert"}, time.Now())
 
@@ -2218,6 +2546,14 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistNodeIntroTokens(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistTrashedJobs(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	return nil
 }
 
@@ -2845,6 +3181,52 @@ func (s *nomadSnapshot) persistRootKeyMeta(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistNodeIntroTokens(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	tokens, err := s.snap.NodeIntroTokens(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := tokens.Next()
+		if raw == nil {
+			break
+		}
+		token := raw.(*structs.NodeIntroToken)
+		sink.Write([]byte{byte(NodeIntroTokenSnapshot)})
+		if err := encoder.Encode(token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistTrashedJobs(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	trashed, err := s.snap.TrashedJobs(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := trashed.Next()
+		if raw == nil {
+			break
+		}
+		job := raw.(*structs.TrashedJob)
+		sink.Write([]byte{byte(TrashedJobSnapshot)})
+		if err := encoder.Encode(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Release is a no-op, as we just need to GC the pointer
 // to the state store snapshot. There is nothing to explicitly
 // cleanup.
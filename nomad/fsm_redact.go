@@ -0,0 +1,63 @@
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// redactedValue replaces the values of redacted secrets whose keys are kept,
+// matching how CSISecrets are redacted from logs.
+const redactedValue = "[REDACTED]"
+
+// Redact removes the secrets of a snapshot object in place if the filter
+// redacts secrets. The redacted secrets are:
+//
+//   - the Vault and Consul tokens of jobs, including the jobs of allocations
+//     and the jobs kept in the job trash
+//   - the encrypted payloads of secure variables
+//   - the secret IDs of ACL tokens, one-time tokens, nodes and node
+//     introduction tokens, which are replaced by random IDs as they are unique
+//     in the state store
+//   - the secret values of CSI volumes, whose keys are kept
+//
+// Objects of other types are left unchanged.
+func (f *FSMFilter) Redact(obj interface{}) {
+	if f == nil || !f.redact {
+		return
+	}
+
+	switch obj := obj.(type) {
+	case *structs.Job:
+		redactJob(obj)
+	case *structs.Allocation:
+		redactJob(obj.Job)
+	case *structs.TrashedJob:
+		redactJob(obj.Job)
+		for _, version := range obj.Versions {
+			redactJob(version)
+		}
+	case *structs.SecureVariableEncrypted:
+		obj.Data = nil
+	case *structs.ACLToken:
+		obj.SecretID = uuid.Generate()
+		obj.SetHash()
+	case *structs.OneTimeToken:
+		obj.OneTimeSecretID = uuid.Generate()
+	case *structs.Node:
+		obj.SecretID = uuid.Generate()
+	case *structs.NodeIntroToken:
+		obj.SecretID = uuid.Generate()
+	case *structs.CSIVolume:
+		for k := range obj.Secrets {
+			obj.Secrets[k] = redactedValue
+		}
+	}
+}
+
+func redactJob(job *structs.Job) {
+	if job == nil {
+		return
+	}
+	job.VaultToken = ""
+	job.ConsulToken = ""
+}
@@ -0,0 +1,238 @@
+package nomad
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"runtime"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// restoreQueueLength is the number of snapshot objects that may be read
+	// ahead of the object being restored.
+	restoreQueueLength = 1024
+
+	// restoreProgressInterval is the number of restored objects between two
+	// calls of a RestoreProgressFn.
+	restoreProgressInterval = 10000
+)
+
+// RestoreProgressFn is called with the number of objects restored so far
+// every restoreProgressInterval objects during a snapshot restore, and once
+// more when the restore completes.
+type RestoreProgressFn func(objects int)
+
+// snapshotObjects returns a new object to decode each type of snapshot object
+// into. Objects of these types are decoded concurrently ahead of being
+// restored. Other types, such as the time table or enterprise objects, are
+// decoded when they are restored.
+var snapshotObjects = map[SnapshotType]func() interface{}{
+	NodeSnapshot:                         func() interface{} { return new(structs.Node) },
+	JobSnapshot:                          func() interface{} { return new(structs.Job) },
+	EvalSnapshot:                         func() interface{} { return new(structs.Evaluation) },
+	AllocSnapshot:                        func() interface{} { return new(structs.Allocation) },
+	IndexSnapshot:                        func() interface{} { return new(state.IndexEntry) },
+	PeriodicLaunchSnapshot:               func() interface{} { return new(structs.PeriodicLaunch) },
+	JobSummarySnapshot:                   func() interface{} { return new(structs.JobSummary) },
+	VaultAccessorSnapshot:                func() interface{} { return new(structs.VaultAccessor) },
+	ServiceIdentityTokenAccessorSnapshot: func() interface{} { return new(structs.SITokenAccessor) },
+	JobVersionSnapshot:                   func() interface{} { return new(structs.Job) },
+	DeploymentSnapshot:                   func() interface{} { return new(structs.Deployment) },
+	ACLPolicySnapshot:                    func() interface{} { return new(structs.ACLPolicy) },
+	ACLTokenSnapshot:                     func() interface{} { return new(structs.ACLToken) },
+	SchedulerConfigSnapshot:              func() interface{} { return new(structs.SchedulerConfiguration) },
+	ClusterMetadataSnapshot:              func() interface{} { return new(structs.ClusterMetadata) },
+	ScalingEventsSnapshot:                func() interface{} { return new(structs.JobScalingEvents) },
+	ScalingPolicySnapshot:                func() interface{} { return new(structs.ScalingPolicy) },
+	CSIPluginSnapshot:                    func() interface{} { return new(structs.CSIPlugin) },
+	CSIVolumeSnapshot:                    func() interface{} { return new(structs.CSIVolume) },
+	NamespaceSnapshot:                    func() interface{} { return new(structs.Namespace) },
+	ServiceRegistrationSnapshot:          func() interface{} { return new(structs.ServiceRegistration) },
+	SecureVariablesSnapshot:              func() interface{} { return new(structs.SecureVariableEncrypted) },
+	SecureVariablesQuotaSnapshot:         func() interface{} { return new(structs.SecureVariablesQuota) },
+	RootKeyMetaSnapshot:                  func() interface{} { return new(structs.RootKeyMeta) },
+	NodeIntroTokenSnapshot:               func() interface{} { return new(structs.NodeIntroToken) },
+	TrashedJobSnapshot:                   func() interface{} { return new(structs.TrashedJob) },
+}
+
+// snapshotItem is a single object read from a snapshot stream.
+type snapshotItem struct {
+	snapType SnapshotType
+	raw      codec.Raw
+
+	// obj is the decoded object, if its type is in snapshotObjects. It is
+	// only set once decoded is closed.
+	obj interface{}
+
+	// err is either the error reading the object from the stream or the
+	// error decoding it.
+	err error
+
+	// decoded is closed once the object has been decoded.
+	decoded chan struct{}
+}
+
+// Decode sets out, a pointer to the type of the object, to the object.
+func (i *snapshotItem) Decode(out interface{}) error {
+	if i.obj == nil {
+		return i.decoder().Decode(out)
+	}
+	reflect.ValueOf(out).Elem().Set(reflect.ValueOf(i.obj).Elem())
+	return nil
+}
+
+// decoder returns a decoder over the encoded object.
+func (i *snapshotItem) decoder() *codec.Decoder {
+	return codec.NewDecoderBytes(i.raw, structs.MsgpackHandle)
+}
+
+// readSnapshot reads the objects of a snapshot stream following its header,
+// and returns them in stream order. The objects are decoded concurrently, so
+// a reader must wait for the decoded channel of an item before using it.
+//
+// The returned channel is closed once the stream is exhausted, after an item
+// with a read error, or once stopCh is closed.
+func readSnapshot(r io.Reader, dec *codec.Decoder, stopCh <-chan struct{}) <-chan *snapshotItem {
+	itemCh := make(chan *snapshotItem, restoreQueueLength)
+	decodeCh := make(chan *snapshotItem, restoreQueueLength)
+
+	workers := runtime.GOMAXPROCS(0)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for item := range decodeCh {
+				obj := snapshotObjects[item.snapType]()
+				item.err = item.decoder().Decode(obj)
+				item.obj = obj
+				close(item.decoded)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(itemCh)
+		defer close(decodeCh)
+
+		msgType := make([]byte, 1)
+		for {
+			item := &snapshotItem{decoded: make(chan struct{})}
+
+			// Read the message type
+			_, err := r.Read(msgType)
+			if err == io.EOF {
+				return
+			}
+			item.snapType = SnapshotType(msgType[0])
+
+			newObj, ok := snapshotObjects[item.snapType]
+			async := false
+			switch {
+			case err != nil:
+				item.err = err
+			case !ok:
+				item.err = dec.Decode(&item.raw)
+			case workers == 1:
+				// Without concurrency, decoding the object directly is
+				// cheaper than reading its raw bytes first.
+				item.obj = newObj()
+				item.err = dec.Decode(item.obj)
+			default:
+				item.err = dec.Decode(&item.raw)
+				async = item.err == nil
+			}
+
+			// Errors decoding an object concurrently are returned with the
+			// object, while reading errors also stop reading the stream.
+			failed := item.err != nil
+			if !async {
+				close(item.decoded)
+			} else {
+				select {
+				case decodeCh <- item:
+				case <-stopCh:
+					return
+				}
+			}
+
+			select {
+			case itemCh <- item:
+			case <-stopCh:
+				return
+			}
+
+			if failed {
+				return
+			}
+		}
+	}()
+
+	return itemCh
+}
+
+// snapshotFilterTypes are the types of snapshot objects that a FSMFilter is
+// applied to when restoring a snapshot.
+var snapshotFilterTypes = map[SnapshotType]bool{
+	NodeSnapshot:                         true,
+	JobSnapshot:                          true,
+	EvalSnapshot:                         true,
+	AllocSnapshot:                        true,
+	PeriodicLaunchSnapshot:               true,
+	JobSummarySnapshot:                   true,
+	VaultAccessorSnapshot:                true,
+	ServiceIdentityTokenAccessorSnapshot: true,
+	JobVersionSnapshot:                   true,
+	DeploymentSnapshot:                   true,
+	ACLPolicySnapshot:                    true,
+	ACLTokenSnapshot:                     true,
+	ScalingEventsSnapshot:                true,
+	ScalingPolicySnapshot:                true,
+	CSIPluginSnapshot:                    true,
+	CSIVolumeSnapshot:                    true,
+	ServiceRegistrationSnapshot:          true,
+}
+
+// SnapshotObjectFn is called by ReadSnapshotObjects with each object read
+// from a snapshot.
+type SnapshotObjectFn func(snapType SnapshotType, obj interface{}) error
+
+// ReadSnapshotObjects reads the objects of a snapshot stream and calls fn with
+// each of them in stream order, without restoring them into a state store, so
+// that tools can process snapshots larger than the memory available to them.
+// The filter applies to the same objects as when restoring the snapshot,
+// and redacts their secrets if it was created by NewRedactingFSMFilter.
+// Objects without a known type, such as the time table or enterprise
+// objects, are skipped.
+func ReadSnapshotObjects(old io.ReadCloser, filter *FSMFilter, fn SnapshotObjectFn) error {
+	defer old.Close()
+
+	r := bufio.NewReader(old)
+	dec := codec.NewDecoder(r, structs.MsgpackHandle)
+
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return err
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	for item := range readSnapshot(r, dec, stopCh) {
+		<-item.decoded
+		if item.err != nil {
+			return item.err
+		}
+		if item.obj == nil {
+			continue
+		}
+		filter.Redact(item.obj)
+		if snapshotFilterTypes[item.snapType] && !filter.Include(item.obj) {
+			continue
+		}
+		if err := fn(item.snapType, item.obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
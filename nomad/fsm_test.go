@@ -2571,6 +2571,228 @@ func TestFSM_SnapshotRestore_Allocs(t *testing.T) {
 	}
 }
 
+func TestFSM_SnapshotRestore_Allocs_SharedJob(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	job := mock.Job()
+	alloc1 := mock.Alloc()
+	alloc1.Job = job
+	alloc1.JobID = job.ID
+	alloc2 := mock.Alloc()
+	alloc2.Job = job
+	alloc2.JobID = job.ID
+	alloc2.NodeID = alloc1.NodeID
+	state.UpsertJobSummary(999, mock.JobSummary(job.ID))
+	state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc1, alloc2})
+
+	// Verify the restored allocations share a single job
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, err := state2.AllocByID(ws, alloc1.ID)
+	require.NoError(t, err)
+	out2, err := state2.AllocByID(ws, alloc2.ID)
+	require.NoError(t, err)
+	require.Equal(t, alloc1, out1)
+	require.Equal(t, alloc2, out2)
+	require.Same(t, out1.Job, out2.Job)
+}
+
+func TestFSM_RestoreWithProgress(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, mock.Job()))
+
+	snap, err := fsm.Snapshot()
+	require.NoError(t, err)
+	defer snap.Release()
+	buf := bytes.NewBuffer(nil)
+	sink := &MockSink{buf, false}
+	require.NoError(t, snap.Persist(sink))
+
+	// The progress is reported once the restore completes
+	var reported []int
+	fsm2 := testFSM(t)
+	require.NoError(t, fsm2.RestoreWithProgress(sink, nil, func(objects int) {
+		reported = append(reported, objects)
+	}))
+	require.Len(t, reported, 1)
+	require.Greater(t, reported[0], 2)
+
+	ws := memdb.NewWatchSet()
+	out, err := fsm2.State().NodeByID(ws, node.ID)
+	require.NoError(t, err)
+	require.Equal(t, node.ID, out.ID)
+}
+
+func TestFSM_ReadSnapshotObjects(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	job1 := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job1))
+	job2 := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, job2))
+
+	snap, err := fsm.Snapshot()
+	require.NoError(t, err)
+	defer snap.Release()
+	buf := bytes.NewBuffer(nil)
+	sink := &MockSink{buf, false}
+	require.NoError(t, snap.Persist(sink))
+
+	// The filter only applies to the objects it applies to on restore, so
+	// the index entries are still read
+	filter, err := NewFSMFilter(fmt.Sprintf("ID == %q", job1.ID))
+	require.NoError(t, err)
+
+	var jobs []*structs.Job
+	var indexes int
+	require.NoError(t, ReadSnapshotObjects(sink, filter, func(snapType SnapshotType, obj interface{}) error {
+		switch snapType {
+		case JobSnapshot:
+			jobs = append(jobs, obj.(*structs.Job))
+		case IndexSnapshot:
+			indexes++
+		case NodeSnapshot:
+			t.Fatalf("unexpected node %v", obj)
+		}
+		return nil
+	}))
+	require.Len(t, jobs, 1)
+	require.Equal(t, job1.ID, jobs[0].ID)
+	require.NotZero(t, indexes)
+}
+
+func TestFSM_RestoreWithFilter_Redact(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state with secrets
+	fsm := testFSM(t)
+	trashedJob := mock.Job()
+	trashedJob.VaultToken = "vault-token"
+	trashedJob.ConsulToken = "consul-token"
+	require.NoError(t, fsm.State().UpsertJob(structs.MsgTypeTestSetup, 990, trashedJob))
+	require.NoError(t, fsm.State().WithWriteTransaction(structs.MsgTypeTestSetup, 991, func(txn state.Txn) error {
+		return fsm.State().TrashJobTxn(991, trashedJob.Namespace, trashedJob.ID, time.Now().Add(time.Hour), txn)
+	}))
+	state := fsm.State()
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	job := mock.Job()
+	job.VaultToken = "vault-token"
+	job.ConsulToken = "consul-token"
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+	token := mock.ACLToken()
+	require.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1002, []*structs.ACLToken{token}))
+	sv := mock.SecureVariableEncrypted()
+	require.NoError(t, state.UpsertSecureVariables(structs.MsgTypeTestSetup, 1003, []*structs.SecureVariableEncrypted{sv}))
+
+	snap, err := fsm.Snapshot()
+	require.NoError(t, err)
+	defer snap.Release()
+	buf := bytes.NewBuffer(nil)
+	sink := &MockSink{buf, false}
+	require.NoError(t, snap.Persist(sink))
+
+	filter, err := NewRedactingFSMFilter("")
+	require.NoError(t, err)
+	fsm2 := testFSM(t)
+	require.NoError(t, fsm2.RestoreWithFilter(sink, filter))
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+
+	out, err := state2.JobByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Empty(t, out.VaultToken)
+	require.Empty(t, out.ConsulToken)
+
+	outNode, err := state2.NodeByID(ws, node.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, node.SecretID, outNode.SecretID)
+
+	outToken, err := state2.ACLTokenByAccessorID(ws, token.AccessorID)
+	require.NoError(t, err)
+	require.NotEqual(t, token.SecretID, outToken.SecretID)
+	require.Equal(t, outToken.Hash, outToken.SetHash())
+
+	outSV, err := state2.GetSecureVariable(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Nil(t, outSV.Data)
+	require.Equal(t, sv.KeyID, outSV.KeyID)
+
+	outTrashed, err := state2.TrashedJobByID(ws, trashedJob.Namespace, trashedJob.ID)
+	require.NoError(t, err)
+	require.Empty(t, outTrashed.Job.VaultToken)
+	require.Empty(t, outTrashed.Job.ConsulToken)
+	for _, version := range outTrashed.Versions {
+		require.Empty(t, version.VaultToken)
+		require.Empty(t, version.ConsulToken)
+	}
+}
+
+func TestFSMFilter_Redact_OneTimeToken(t *testing.T) {
+	ci.Parallel(t)
+
+	filter, err := NewRedactingFSMFilter("")
+	require.NoError(t, err)
+
+	secret := uuid.Generate()
+	token := &structs.OneTimeToken{
+		OneTimeSecretID: secret,
+		AccessorID:      uuid.Generate(),
+	}
+	filter.Redact(token)
+	require.NotEqual(t, secret, token.OneTimeSecretID)
+	require.NotEmpty(t, token.OneTimeSecretID)
+}
+
+func BenchmarkFSM_Restore_Allocs(b *testing.B) {
+	fsmConfig := &FSMConfig{
+		Logger: testlog.HCLogger(b),
+		Region: "global",
+	}
+	fsm, err := NewFSM(fsmConfig)
+	require.NoError(b, err)
+
+	// Spread allocations across a handful of jobs, as on a real cluster
+	allocs := make([]*structs.Allocation, 0, 5000)
+	for i := 0; i < 10; i++ {
+		job := mock.Job()
+		for j := 0; j < cap(allocs)/10; j++ {
+			alloc := mock.Alloc()
+			alloc.Job = job
+			alloc.JobID = job.ID
+			allocs = append(allocs, alloc)
+		}
+	}
+	require.NoError(b, fsm.State().UpsertAllocs(structs.MsgTypeTestSetup, 1000, allocs))
+
+	snap, err := fsm.Snapshot()
+	require.NoError(b, err)
+	defer snap.Release()
+	buf := bytes.NewBuffer(nil)
+	require.NoError(b, snap.Persist(&MockSink{buf, false}))
+	data := buf.Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sink := &MockSink{bytes.NewBuffer(data), false}
+		if err := fsm.Restore(sink); err != nil {
+			b.Fatalf("err: %v", err)
+		}
+	}
+}
+
 func TestFSM_SnapshotRestore_Allocs_Canonicalize(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
@@ -2893,6 +3115,102 @@ func TestFSM_SnapshotRestore_ServiceRegistrations(t *testing.T) {
 	require.ElementsMatch(t, restoredRegs, serviceRegs)
 }
 
+func TestFSM_SnapshotRestore_NodeIntroTokens(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	token := &structs.NodeIntroToken{
+		SecretID:   uuid.Generate(),
+		AccessorID: uuid.Generate(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	require.NoError(t, fsm.State().UpsertNodeIntroToken(structs.MsgTypeTestSetup, 10, token))
+
+	restoredFSM := testSnapshotRestore(t, fsm)
+	out, err := restoredFSM.State().NodeIntroTokenBySecret(nil, token.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, token.AccessorID, out.AccessorID)
+	require.True(t, token.ExpiresAt.Equal(out.ExpiresAt))
+}
+
+func TestFSM_DeregisterJob_PurgeTrash(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	job := mock.Job()
+	require.NoError(t, fsm.State().UpsertJob(structs.MsgTypeTestSetup, 10, job))
+
+	// Purging the job with an expiry keeps it in the job trash
+	req := structs.JobDeregisterRequest{
+		JobID:          job.ID,
+		Purge:          true,
+		TrashExpiresAt: time.Now().Add(time.Hour),
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.JobDeregisterRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	jobOut, err := fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Nil(t, jobOut)
+
+	trashed, err := fsm.State().TrashedJobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, trashed)
+
+	// Restoring the job registers it stopped
+	restoreReq := structs.JobRestoreRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err = structs.Encode(structs.JobRestoreRequestType, restoreReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	jobOut, err = fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, jobOut)
+	require.True(t, jobOut.Stop)
+
+	// Purging it again and expiring the job trash deletes it
+	buf, err = structs.Encode(structs.JobDeregisterRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	expireReq := structs.JobTrashExpireRequest{Timestamp: time.Now().Add(2 * time.Hour)}
+	buf, err = structs.Encode(structs.JobTrashExpireRequestType, expireReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	trashed, err = fsm.State().TrashedJobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Nil(t, trashed)
+}
+
+func TestFSM_SnapshotRestore_TrashedJobs(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	job := mock.Job()
+	require.NoError(t, fsm.State().UpsertJob(structs.MsgTypeTestSetup, 10, job))
+	err := fsm.State().WithWriteTransaction(structs.MsgTypeTestSetup, 11, func(txn state.Txn) error {
+		return fsm.State().TrashJobTxn(11, job.Namespace, job.ID, time.Now().Add(time.Hour), txn)
+	})
+	require.NoError(t, err)
+
+	restoredFSM := testSnapshotRestore(t, fsm)
+	out, err := restoredFSM.State().TrashedJobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, job.Name, out.Job.Name)
+}
+
 func TestFSM_ReconcileSummaries(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
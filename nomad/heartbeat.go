@@ -8,6 +8,7 @@ import (
 	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
+	version "github.com/hashicorp/go-version"
 
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -27,6 +28,10 @@ var (
 	// heartbeatNotLeaderErr is the error returned when the heartbeat request
 	// couldn't be completed since the server is not the leader.
 	heartbeatNotLeaderErr = errors.New(heartbeatNotLeader)
+
+	// minNodeBatchUpdateStatusVersion is the minimum version of the servers
+	// for node status updates to be batched in a single raft apply.
+	minNodeBatchUpdateStatusVersion = version.Must(version.NewVersion("1.4.0"))
 )
 
 // nodeHeartbeater is used to track expiration times of node heartbeats. If it
@@ -36,9 +41,19 @@ type nodeHeartbeater struct {
 	logger log.Logger
 
 	// heartbeatTimers track the expiration time of each heartbeat that has
-	// a TTL. On expiration, the node status is updated to be 'down'.
-	heartbeatTimers     map[string]*time.Timer
-	heartbeatTimersLock sync.Mutex
+	// a TTL. On expiration, the node status is updated to be 'down'. It is
+	// nil when the server is not tracking heartbeats, and is advanced until
+	// heartbeatTimersStopCh is closed.
+	heartbeatTimers       *heartbeatWheel
+	heartbeatTimersStopCh chan struct{}
+	heartbeatTimersLock   sync.Mutex
+
+	// statusUpdates holds the node status updates pending a batched raft
+	// apply, and statusUpdateFuture is used to wait for the pending batch.
+	// It may be nil if no batch is pending.
+	statusUpdates      []*structs.NodeUpdateStatusRequest
+	statusUpdateFuture *structs.BatchFuture
+	statusUpdatesLock  sync.Mutex
 }
 
 // newNodeHeartbeater returns a new node heartbeater used to detect and act on
@@ -100,7 +115,7 @@ func (h *nodeHeartbeater) resetHeartbeatTimer(id string) (time.Duration, error)
 	}
 
 	// Compute the target TTL value
-	n := len(h.heartbeatTimers)
+	n := h.heartbeatTimers.Len()
 	ttl := helper.RateScaledInterval(h.config.MaxHeartbeatsPerSecond, h.config.MinHeartbeatTTL, n)
 	ttl += helper.RandomStagger(ttl)
 
@@ -112,22 +127,41 @@ func (h *nodeHeartbeater) resetHeartbeatTimer(id string) (time.Duration, error)
 // resetHeartbeatTimerLocked is used to reset a heartbeat timer
 // assuming the heartbeatTimerLock is already held
 func (h *nodeHeartbeater) resetHeartbeatTimerLocked(id string, ttl time.Duration) {
-	// Ensure a timer map exists
+	// Ensure a timer wheel exists
 	if h.heartbeatTimers == nil {
-		h.heartbeatTimers = make(map[string]*time.Timer)
+		h.heartbeatTimers = newHeartbeatWheel(heartbeatWheelTick, heartbeatWheelSlots, time.Now())
+		h.heartbeatTimersStopCh = make(chan struct{})
+		go h.runHeartbeatTimers(h.heartbeatTimers, h.heartbeatTimersStopCh)
 	}
 
-	// Renew the heartbeat timer if it exists
-	if timer, ok := h.heartbeatTimers[id]; ok {
-		timer.Reset(ttl)
-		return
-	}
+	h.heartbeatTimers.Reset(id, time.Now().Add(ttl))
+}
+
+// runHeartbeatTimers is a long running routine advancing the heartbeat timer
+// wheel, and invalidating the heartbeats that expire until stopCh is closed.
+func (h *nodeHeartbeater) runHeartbeatTimers(wheel *heartbeatWheel, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(heartbeatWheelTick)
+	defer ticker.Stop()
 
-	// Create a new timer to track expiration of this heartbeat
-	timer := time.AfterFunc(ttl, func() {
-		h.invalidateHeartbeat(id)
-	})
-	h.heartbeatTimers[id] = timer
+	for {
+		select {
+		case now := <-ticker.C:
+			h.heartbeatTimersLock.Lock()
+			expired := wheel.Advance(now)
+			h.heartbeatTimersLock.Unlock()
+
+			for id, deadline := range expired {
+				metrics.AddSample([]string{"nomad", "heartbeat", "expiration_delay"},
+					float32(now.Sub(deadline))/float32(time.Millisecond))
+				go h.invalidateHeartbeat(id)
+			}
+
+		case <-stopCh:
+			return
+		case <-h.shutdownCh:
+			return
+		}
+	}
 }
 
 // invalidateHeartbeat is invoked when a heartbeat TTL is reached and we
@@ -136,10 +170,7 @@ func (h *nodeHeartbeater) invalidateHeartbeat(id string) {
 	defer metrics.MeasureSince([]string{"nomad", "heartbeat", "invalidate"}, time.Now())
 	// Clear the heartbeat timer
 	h.heartbeatTimersLock.Lock()
-	if timer, ok := h.heartbeatTimers[id]; ok {
-		timer.Stop()
-		delete(h.heartbeatTimers, id)
-	}
+	h.heartbeatTimers.Remove(id)
 	h.heartbeatTimersLock.Unlock()
 
 	// Do not invalidate the node since we are not the leader. This check avoids
@@ -222,10 +253,7 @@ func (h *nodeHeartbeater) clearHeartbeatTimer(id string) error {
 	h.heartbeatTimersLock.Lock()
 	defer h.heartbeatTimersLock.Unlock()
 
-	if timer, ok := h.heartbeatTimers[id]; ok {
-		timer.Stop()
-		delete(h.heartbeatTimers, id)
-	}
+	h.heartbeatTimers.Remove(id)
 	return nil
 }
 
@@ -235,13 +263,66 @@ func (h *nodeHeartbeater) clearAllHeartbeatTimers() error {
 	h.heartbeatTimersLock.Lock()
 	defer h.heartbeatTimersLock.Unlock()
 
-	for _, t := range h.heartbeatTimers {
-		t.Stop()
+	if h.heartbeatTimersStopCh != nil {
+		close(h.heartbeatTimersStopCh)
+		h.heartbeatTimersStopCh = nil
 	}
 	h.heartbeatTimers = nil
 	return nil
 }
 
+// batchUpdateNodeStatus commits a node status update via raft, batched with
+// the status updates of other nodes made within batchUpdateInterval, so that
+// many nodes changing status at once, such as when their heartbeats are
+// missed together, don't each need a raft apply. It returns the index the
+// update was committed at.
+func (h *nodeHeartbeater) batchUpdateNodeStatus(args *structs.NodeUpdateStatusRequest) (uint64, error) {
+	// Older servers can't apply batches, so commit the update on its own
+	if !ServersMeetMinimumVersion(h.Members(), minNodeBatchUpdateStatusVersion, false) {
+		_, index, err := h.raftApply(structs.NodeUpdateStatusRequestType, args)
+		return index, err
+	}
+
+	h.statusUpdatesLock.Lock()
+	h.statusUpdates = append(h.statusUpdates, args)
+
+	// Start a new batch if none
+	future := h.statusUpdateFuture
+	if future == nil {
+		future = structs.NewBatchFuture()
+		h.statusUpdateFuture = future
+		time.AfterFunc(batchUpdateInterval, h.applyStatusUpdates)
+	}
+	h.statusUpdatesLock.Unlock()
+
+	if err := future.Wait(); err != nil {
+		return 0, err
+	}
+	return future.Index(), nil
+}
+
+// applyStatusUpdates commits the pending batch of node status updates.
+func (h *nodeHeartbeater) applyStatusUpdates() {
+	h.statusUpdatesLock.Lock()
+	updates := h.statusUpdates
+	future := h.statusUpdateFuture
+	h.statusUpdates = nil
+	h.statusUpdateFuture = nil
+	h.statusUpdatesLock.Unlock()
+
+	defer metrics.MeasureSince([]string{"nomad", "heartbeat", "batch_update_status"}, time.Now())
+	metrics.AddSample([]string{"nomad", "heartbeat", "batch_update_status_size"}, float32(len(updates)))
+
+	req := &structs.NodeBatchUpdateStatusRequest{
+		Updates: updates,
+		WriteRequest: structs.WriteRequest{
+			Region: h.config.Region,
+		},
+	}
+	_, index, err := h.raftApply(structs.NodeBatchUpdateStatusRequestType, req)
+	future.Respond(index, err)
+}
+
 // heartbeatStats is a long running routine used to capture
 // the number of active heartbeats being tracked
 func (h *nodeHeartbeater) heartbeatStats() {
@@ -249,7 +330,7 @@ func (h *nodeHeartbeater) heartbeatStats() {
 		select {
 		case <-time.After(5 * time.Second):
 			h.heartbeatTimersLock.Lock()
-			num := len(h.heartbeatTimers)
+			num := h.heartbeatTimers.Len()
 			h.heartbeatTimersLock.Unlock()
 			metrics.SetGauge([]string{"nomad", "heartbeat", "active"}, float32(num))
 
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -36,7 +37,7 @@ func TestHeartbeat_InitializeHeartbeatTimers(t *testing.T) {
 	}
 
 	// Check that we have a timer
-	_, ok := s1.heartbeatTimers[node.ID]
+	ok := s1.heartbeatTimers.Has(node.ID)
 	if !ok {
 		t.Fatalf("missing heartbeat timer")
 	}
@@ -59,7 +60,7 @@ func TestHeartbeat_ResetHeartbeatTimer(t *testing.T) {
 	}
 
 	// Check that we have a timer
-	_, ok := s1.heartbeatTimers["test"]
+	ok := s1.heartbeatTimers.Has("test")
 	if !ok {
 		t.Fatalf("missing heartbeat timer")
 	}
@@ -93,13 +94,14 @@ func TestHeartbeat_ResetHeartbeatTimerLocked(t *testing.T) {
 	s1.resetHeartbeatTimerLocked("foo", 5*time.Millisecond)
 	s1.heartbeatTimersLock.Unlock()
 
-	if _, ok := s1.heartbeatTimers["foo"]; !ok {
+	if !s1.heartbeatTimers.Has("foo") {
 		t.Fatalf("missing timer")
 	}
 
-	time.Sleep(time.Duration(testutil.TestMultiplier()*10) * time.Millisecond)
+	// Heartbeats expire on the ticks of the timer wheel
+	time.Sleep(2*heartbeatWheelTick + time.Duration(testutil.TestMultiplier()*10)*time.Millisecond)
 
-	if _, ok := s1.heartbeatTimers["foo"]; ok {
+	if s1.heartbeatTimers.Has("foo") {
 		t.Fatalf("timer should be gone")
 	}
 }
@@ -115,7 +117,7 @@ func TestHeartbeat_ResetHeartbeatTimerLocked_Renew(t *testing.T) {
 	s1.resetHeartbeatTimerLocked("foo", 30*time.Millisecond)
 	s1.heartbeatTimersLock.Unlock()
 
-	if _, ok := s1.heartbeatTimers["foo"]; !ok {
+	if !s1.heartbeatTimers.Has("foo") {
 		t.Fatalf("missing timer")
 	}
 
@@ -128,9 +130,9 @@ func TestHeartbeat_ResetHeartbeatTimerLocked_Renew(t *testing.T) {
 	renew := time.Now()
 
 	// Watch for invalidation
-	for time.Now().Sub(renew) < time.Duration(testutil.TestMultiplier()*100)*time.Millisecond {
+	for time.Now().Sub(renew) < 2*heartbeatWheelTick+time.Duration(testutil.TestMultiplier()*100)*time.Millisecond {
 		s1.heartbeatTimersLock.Lock()
-		_, ok := s1.heartbeatTimers["foo"]
+		ok := s1.heartbeatTimers.Has("foo")
 		s1.heartbeatTimersLock.Unlock()
 		if !ok {
 			end := time.Now()
@@ -169,6 +171,102 @@ func TestHeartbeat_InvalidateHeartbeat(t *testing.T) {
 	require.Equal(NodeHeartbeatEventMissed, out.Events[1].Message)
 }
 
+func TestHeartbeat_InvalidateHeartbeat_Batched(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create many nodes
+	const numNodes = 500
+	state := s1.fsm.State()
+	nodes := make([]*structs.Node, numNodes)
+	for i := range nodes {
+		nodes[i] = mock.Node()
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), nodes[i]))
+	}
+
+	// Miss all their heartbeats at once
+	var wg sync.WaitGroup
+	start := time.Now()
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			s1.invalidateHeartbeat(id)
+		}(node.ID)
+	}
+	wg.Wait()
+	t.Logf("invalidated %d heartbeats in %v", numNodes, time.Since(start))
+
+	// The status updates are committed in far fewer raft applies than nodes
+	indexes := make(map[uint64]struct{})
+	for _, node := range nodes {
+		out, err := state.NodeByID(nil, node.ID)
+		require.NoError(t, err)
+		require.Equal(t, structs.NodeStatusDown, out.Status)
+		indexes[out.ModifyIndex] = struct{}{}
+	}
+	t.Logf("committed %d status updates in %d raft applies", numNodes, len(indexes))
+	require.Less(t, len(indexes), numNodes/10)
+}
+
+func TestHeartbeat_InvalidateHeartbeat_OldServers(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+	})
+	defer cleanupS1()
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+
+		// simulate a server that can't apply batched node status updates
+		c.Build = "1.3.3"
+	})
+	defer cleanupS2()
+
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Miss the heartbeats on the leader
+	if leader, _ := s1.getLeader(); !leader {
+		s1, s2 = s2, s1
+	}
+
+	const numNodes = 20
+	state := s1.fsm.State()
+	nodes := make([]*structs.Node, numNodes)
+	for i := range nodes {
+		nodes[i] = mock.Node()
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), nodes[i]))
+	}
+
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			s1.invalidateHeartbeat(id)
+		}(node.ID)
+	}
+	wg.Wait()
+
+	// Each status update is committed on its own, so the old server can
+	// apply it
+	indexes := make(map[uint64]struct{})
+	for _, node := range nodes {
+		out, err := state.NodeByID(nil, node.ID)
+		require.NoError(t, err)
+		require.Equal(t, structs.NodeStatusDown, out.Status)
+		indexes[out.ModifyIndex] = struct{}{}
+	}
+	require.Len(t, indexes, numNodes)
+}
+
 func TestHeartbeat_ClearHeartbeatTimer(t *testing.T) {
 	ci.Parallel(t)
 
@@ -185,7 +283,7 @@ func TestHeartbeat_ClearHeartbeatTimer(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	if _, ok := s1.heartbeatTimers["foo"]; ok {
+	if s1.heartbeatTimers.Has("foo") {
 		t.Fatalf("timer should be gone")
 	}
 }
@@ -208,7 +306,7 @@ func TestHeartbeat_ClearAllHeartbeatTimers(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	if len(s1.heartbeatTimers) != 0 {
+	if s1.heartbeatTimers.Len() != 0 {
 		t.Fatalf("timers should be gone")
 	}
 }
@@ -250,7 +348,7 @@ func TestHeartbeat_Server_HeartbeatTTL_Failover(t *testing.T) {
 	}
 
 	// Check that heartbeatTimers has the heartbeat ID
-	if _, ok := leader.heartbeatTimers[node.ID]; !ok {
+	if !leader.heartbeatTimers.Has(node.ID) {
 		t.Fatalf("missing heartbeat timer")
 	}
 
@@ -259,7 +357,7 @@ func TestHeartbeat_Server_HeartbeatTTL_Failover(t *testing.T) {
 
 	// heartbeatTimers should be cleared on leader shutdown
 	testutil.WaitForResult(func() (bool, error) {
-		return len(leader.heartbeatTimers) == 0, nil
+		return leader.heartbeatTimers.Len() == 0, nil
 	}, func(err error) {
 		t.Fatalf("heartbeat timers should be empty on the shutdown leader")
 	})
@@ -277,7 +375,7 @@ func TestHeartbeat_Server_HeartbeatTTL_Failover(t *testing.T) {
 		}
 
 		// Ensure heartbeat timer is restored
-		if _, ok := leader.heartbeatTimers[node.ID]; !ok {
+		if !leader.heartbeatTimers.Has(node.ID) {
 			return false, fmt.Errorf("missing heartbeat timer")
 		}
 
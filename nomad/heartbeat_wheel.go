@@ -0,0 +1,149 @@
+package nomad
+
+import (
+	"time"
+)
+
+const (
+	// heartbeatWheelTick is the resolution of the heartbeat timer wheel.
+	// Heartbeats expire up to two ticks after their TTL.
+	heartbeatWheelTick = 100 * time.Millisecond
+
+	// heartbeatWheelSlots is the number of slots of the heartbeat timer
+	// wheel. Heartbeats expiring further away than the wheel spans share
+	// their slot with the heartbeats of later rotations.
+	heartbeatWheelSlots = 512
+)
+
+// heartbeatWheel is a hashed timer wheel tracking the expiration of node
+// heartbeats. Each heartbeat is placed in the slot of the tick it expires on,
+// so that resetting a heartbeat is a map operation and a single ticker
+// expires all of them, rather than every node having its own runtime timer.
+//
+// The wheel is not safe for concurrent use. A nil wheel tracks no heartbeats.
+type heartbeatWheel struct {
+	tick time.Duration
+
+	// slots holds the heartbeats by node ID, in the slot of the tick they
+	// expire on.
+	slots []map[string]*heartbeatWheelEntry
+
+	// entries holds every heartbeat by node ID.
+	entries map[string]*heartbeatWheelEntry
+
+	// lastTick is the last tick whose heartbeats have been expired.
+	lastTick int64
+}
+
+// heartbeatWheelEntry is a heartbeat tracked by a heartbeatWheel.
+type heartbeatWheelEntry struct {
+	deadline time.Time
+	tick     int64
+}
+
+// newHeartbeatWheel returns an empty heartbeat timer wheel with the given
+// resolution and number of slots.
+func newHeartbeatWheel(tick time.Duration, slots int, now time.Time) *heartbeatWheel {
+	w := &heartbeatWheel{
+		tick:     tick,
+		slots:    make([]map[string]*heartbeatWheelEntry, slots),
+		entries:  make(map[string]*heartbeatWheelEntry),
+		lastTick: now.UnixNano()/int64(tick) - 1,
+	}
+	for i := range w.slots {
+		w.slots[i] = make(map[string]*heartbeatWheelEntry)
+	}
+	return w
+}
+
+// slot returns the slot of a tick.
+func (w *heartbeatWheel) slot(tick int64) map[string]*heartbeatWheelEntry {
+	return w.slots[tick%int64(len(w.slots))]
+}
+
+// Reset sets the heartbeat of a node to expire at the deadline, replacing its
+// previous deadline if any.
+func (w *heartbeatWheel) Reset(id string, deadline time.Time) {
+	// A deadline that has already passed expires on the next tick
+	tick := deadline.UnixNano() / int64(w.tick)
+	if tick <= w.lastTick {
+		tick = w.lastTick + 1
+	}
+
+	entry, ok := w.entries[id]
+	if ok {
+		delete(w.slot(entry.tick), id)
+	} else {
+		entry = &heartbeatWheelEntry{}
+		w.entries[id] = entry
+	}
+
+	entry.deadline = deadline
+	entry.tick = tick
+	w.slot(tick)[id] = entry
+}
+
+// Remove stops tracking the heartbeat of a node.
+func (w *heartbeatWheel) Remove(id string) {
+	if w == nil {
+		return
+	}
+	entry, ok := w.entries[id]
+	if !ok {
+		return
+	}
+	delete(w.entries, id)
+	delete(w.slot(entry.tick), id)
+}
+
+// Has returns whether the heartbeat of a node is tracked.
+func (w *heartbeatWheel) Has(id string) bool {
+	if w == nil {
+		return false
+	}
+	_, ok := w.entries[id]
+	return ok
+}
+
+// Len returns the number of heartbeats tracked.
+func (w *heartbeatWheel) Len() int {
+	if w == nil {
+		return 0
+	}
+	return len(w.entries)
+}
+
+// Advance expires the heartbeats of the ticks that ended by now, and returns
+// their deadlines by node ID.
+func (w *heartbeatWheel) Advance(now time.Time) map[string]time.Time {
+	endTick := now.UnixNano()/int64(w.tick) - 1
+
+	// Each slot only needs to be visited once, however far behind the wheel
+	// is, as every heartbeat of a slot expiring by endTick is expired.
+	from := w.lastTick + 1
+	if endTick-from >= int64(len(w.slots)) {
+		from = endTick - int64(len(w.slots)) + 1
+	}
+
+	var expired map[string]time.Time
+	for tick := from; tick <= endTick; tick++ {
+		slot := w.slot(tick)
+		for id, entry := range slot {
+			// Heartbeats of later rotations of the wheel share the slot
+			if entry.tick > endTick {
+				continue
+			}
+			if expired == nil {
+				expired = make(map[string]time.Time)
+			}
+			expired[id] = entry.deadline
+			delete(slot, id)
+			delete(w.entries, id)
+		}
+	}
+
+	if endTick > w.lastTick {
+		w.lastTick = endTick
+	}
+	return expired
+}
@@ -0,0 +1,109 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatWheel_Advance(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Unix(1000, 0)
+	w := newHeartbeatWheel(time.Second, 8, now)
+
+	w.Reset("a", now.Add(1500*time.Millisecond))
+	w.Reset("b", now.Add(3*time.Second))
+	require.Equal(t, 2, w.Len())
+
+	// Heartbeats never expire before their deadline
+	require.Empty(t, w.Advance(now.Add(1600*time.Millisecond)))
+
+	// Heartbeats expire once the tick of their deadline has ended
+	expired := w.Advance(now.Add(2 * time.Second))
+	require.Equal(t, map[string]time.Time{"a": now.Add(1500 * time.Millisecond)}, expired)
+	require.False(t, w.Has("a"))
+	require.True(t, w.Has("b"))
+
+	// Resetting a heartbeat replaces its deadline
+	w.Reset("b", now.Add(5*time.Second))
+	require.Empty(t, w.Advance(now.Add(4*time.Second)))
+	require.Contains(t, w.Advance(now.Add(6*time.Second)), "b")
+	require.Zero(t, w.Len())
+}
+
+func TestHeartbeatWheel_Rotations(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Unix(1000, 0)
+	w := newHeartbeatWheel(time.Second, 8, now)
+
+	// Both heartbeats share a slot, but on different rotations of the wheel
+	w.Reset("a", now.Add(2*time.Second))
+	w.Reset("b", now.Add(10*time.Second))
+
+	for i := 1; i <= 9; i++ {
+		expired := w.Advance(now.Add(time.Duration(i) * time.Second))
+		require.NotContains(t, expired, "b", "expired after %d seconds", i)
+	}
+	require.False(t, w.Has("a"))
+	require.Contains(t, w.Advance(now.Add(11*time.Second)), "b")
+
+	// A wheel far behind still expires every heartbeat due
+	w.Reset("c", now.Add(12*time.Second))
+	w.Reset("d", now.Add(30*time.Second))
+	expired := w.Advance(now.Add(time.Minute))
+	require.Len(t, expired, 2)
+	require.Zero(t, w.Len())
+}
+
+func TestHeartbeatWheel_Remove(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Unix(1000, 0)
+	w := newHeartbeatWheel(time.Second, 8, now)
+
+	w.Reset("a", now.Add(time.Second))
+	w.Remove("a")
+	w.Remove("unknown")
+	require.Empty(t, w.Advance(now.Add(time.Minute)))
+
+	// A deadline that has already passed expires on the next tick
+	w.Reset("b", now)
+	require.Contains(t, w.Advance(now.Add(time.Minute+time.Second)), "b")
+
+	// A nil wheel tracks no heartbeats
+	var nilWheel *heartbeatWheel
+	require.False(t, nilWheel.Has("a"))
+	require.Zero(t, nilWheel.Len())
+	nilWheel.Remove("a")
+}
+
+// BenchmarkHeartbeatWheel_Reset measures resetting the heartbeats of a large
+// cluster, as the leader does on each client heartbeat.
+func BenchmarkHeartbeatWheel_Reset(b *testing.B) {
+	const nodes = 50000
+
+	ids := make([]string, nodes)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("node-%d", i)
+	}
+
+	now := time.Now()
+	w := newHeartbeatWheel(heartbeatWheelTick, heartbeatWheelSlots, now)
+	for i, id := range ids {
+		w.Reset(id, now.Add(time.Duration(i%60)*time.Second))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Reset(ids[i%nodes], now.Add(time.Duration(i%60)*time.Second))
+		if i%1000 == 0 {
+			w.Advance(now.Add(time.Duration(i/1000) * heartbeatWheelTick))
+		}
+	}
+}
@@ -63,6 +63,7 @@ func NewJobEndpoints(s *Server) *Job {
 		mutators: []jobMutator{
 			jobCanonicalizer{},
 			jobConnectHook{},
+			jobMeshHook{},
 			jobExposeCheckHook{},
 			jobImpliedConstraints{},
 		},
@@ -193,6 +194,24 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Return the original result for retries of a register that already
+	// modified the job. This is checked before the index is enforced, as
+	// the original register has changed it.
+	if isIdempotentRetry(args.IdempotencyToken, existingJob) {
+		eval, err := idempotentRetryEval(snap, existingJob, structs.EvalTriggerJobRegister)
+		if err != nil {
+			return err
+		}
+		if eval != nil {
+			reply.EvalID = eval.ID
+			reply.EvalCreateIndex = eval.CreateIndex
+		}
+		reply.JobModifyIndex = existingJob.JobModifyIndex
+		reply.Index = existingJob.ModifyIndex
+		return nil
+	}
+	args.Job.IdempotencyToken = args.IdempotencyToken
+
 	// If EnforceIndex set, check it before trying to apply
 	if args.EnforceIndex {
 		jmi := args.JobModifyIndex
@@ -208,6 +227,13 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		}
 	}
 
+	// Reject updates of frozen jobs and carry over the freeze state, which
+	// can only be changed via Job.Freeze
+	if err := checkJobFrozen(aclObj, existingJob); err != nil {
+		return err
+	}
+	args.Job.Frozen = existingJob != nil && existingJob.Frozen
+
 	// Validate job transitions if its an update
 	if err := validateJobUpdate(existingJob, args.Job); err != nil {
 		return err
@@ -333,6 +359,7 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 			ID:          uuid.Generate(),
 			Namespace:   args.RequestNamespace(),
 			Priority:    evalPriority,
+			Deadline:    args.Job.DeadlineTime(),
 			Type:        args.Job.Type,
 			TriggeredBy: structs.EvalTriggerJobRegister,
 			JobID:       args.Job.ID,
@@ -669,6 +696,95 @@ func (j *Job) Stable(args *structs.JobStabilityRequest, reply *structs.JobStabil
 	return nil
 }
 
+// Freeze is used to freeze or unfreeze a job. Freezing a job requires the
+// submit-job capability, while unfreezing it requires the unfreeze capability.
+func (j *Job) Freeze(args *structs.JobFreezeRequest, reply *structs.JobFreezeResponse) error {
+	if done, err := j.srv.forward("Job.Freeze", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "freeze"}, time.Now())
+
+	if !ServersMeetMinimumVersion(j.srv.Members(), minJobFreezeVersion, false) {
+		return fmt.Errorf("All servers should be running version %v or later to freeze jobs", minJobFreezeVersion)
+	}
+
+	capability := acl.NamespaceCapabilitySubmitJob
+	if !args.Frozen {
+		capability = acl.NamespaceCapabilityUnfreeze
+	}
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), capability) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.JobID == "" {
+		return fmt.Errorf("missing job ID for freezing job")
+	}
+
+	// Lookup the job
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	job, err := snap.JobByID(nil, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return structs.NewErrRPCCoded(404, fmt.Sprintf("job %q not found", args.JobID))
+	}
+
+	// Commit this freeze request via Raft
+	_, modifyIndex, err := j.srv.raftApply(structs.JobFreezeRequestType, args)
+	if err != nil {
+		j.logger.Error("submitting job freeze request failed", "error", err)
+		return err
+	}
+
+	// Setup the reply
+	reply.Index = modifyIndex
+	return nil
+}
+
+// checkJobFrozen returns ErrJobFrozen if the job is frozen and the ACL object
+// is not allowed to modify frozen jobs. When ACLs are disabled a frozen job
+// must be unfrozen before it can be modified.
+func checkJobFrozen(aclObj *acl.ACL, job *structs.Job) error {
+	if job == nil || !job.Frozen {
+		return nil
+	}
+	if aclObj != nil && aclObj.AllowNsOp(job.Namespace, acl.NamespaceCapabilityUnfreeze) {
+		return nil
+	}
+	return structs.ErrJobFrozen
+}
+
+// isIdempotentRetry returns true if the job was last modified by a request
+// with the given idempotency token.
+func isIdempotentRetry(token string, job *structs.Job) bool {
+	return token != "" && job != nil && job.IdempotencyToken == token
+}
+
+// idempotentRetryEval returns the evaluation with the given trigger created
+// by the request that last modified the job, or nil if there is none.
+func idempotentRetryEval(snap *state.StateSnapshot, job *structs.Job, triggeredBy string) (*structs.Evaluation, error) {
+	evals, err := snap.EvalsByJob(nil, job.Namespace, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, eval := range evals {
+		if eval.TriggeredBy != triggeredBy {
+			continue
+		}
+		if eval.JobModifyIndex == job.JobModifyIndex || eval.CreateIndex == job.JobModifyIndex {
+			return eval, nil
+		}
+	}
+	return nil, nil
+}
+
 // Evaluate is used to force a job for re-evaluation
 func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegisterResponse) error {
 	if done, err := j.srv.forward("Job.Evaluate", args, args, reply); done {
@@ -736,6 +852,7 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 		ID:             uuid.Generate(),
 		Namespace:      args.RequestNamespace(),
 		Priority:       job.Priority,
+		Deadline:       job.DeadlineTime(),
 		Type:           job.Type,
 		TriggeredBy:    structs.EvalTriggerJobRegister,
 		JobID:          job.ID,
@@ -773,7 +890,8 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 	defer metrics.MeasureSince([]string{"nomad", "job", "deregister"}, time.Now())
 
 	// Check for submit-job permissions
-	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := j.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
 	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
@@ -795,6 +913,44 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 		return err
 	}
 
+	// Return the original result for retries of a deregister that already
+	// stopped the job
+	if job != nil && job.Stop && isIdempotentRetry(args.IdempotencyToken, job) {
+		eval, err := idempotentRetryEval(snap, job, structs.EvalTriggerJobDeregister)
+		if err != nil {
+			return err
+		}
+		if eval != nil {
+			reply.EvalID = eval.ID
+			reply.EvalCreateIndex = eval.CreateIndex
+		}
+		reply.JobModifyIndex = job.JobModifyIndex
+		reply.Index = job.ModifyIndex
+		return nil
+	}
+
+	// If EnforceIndex set, check it before trying to apply
+	if args.EnforceIndex {
+		if job == nil {
+			return fmt.Errorf("%s %d: job does not exist", RegisterEnforceIndexErrPrefix, args.JobModifyIndex)
+		} else if args.JobModifyIndex != job.JobModifyIndex {
+			return fmt.Errorf("%s %d: job exists with conflicting job modify index: %d",
+				RegisterEnforceIndexErrPrefix, args.JobModifyIndex, job.JobModifyIndex)
+		}
+	}
+
+	if err := checkJobFrozen(aclObj, job); err != nil {
+		return err
+	}
+
+	// Keep the purged job in the job trash if it is enabled and all servers
+	// support it. The expiry is set using the leader's clock.
+	args.TrashExpiresAt = time.Time{}
+	if args.Purge && job != nil && j.srv.config.JobTrashWindow > 0 &&
+		ServersMeetMinimumVersion(j.srv.Members(), minJobTrashVersion, false) {
+		args.TrashExpiresAt = time.Now().Add(j.srv.config.JobTrashWindow)
+	}
+
 	var eval *structs.Evaluation
 
 	// The job priority / type is strange for this, since it's not a high
@@ -963,6 +1119,119 @@ func (j *Job) BatchDeregister(args *structs.JobBatchDeregisterRequest, reply *st
 	return nil
 }
 
+// Restore is used to restore a purged job from the job trash. The job is
+// restored stopped along with its versions.
+func (j *Job) Restore(args *structs.JobRestoreRequest, reply *structs.JobRestoreResponse) error {
+	if done, err := j.srv.forward("Job.Restore", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "restore"}, time.Now())
+
+	if !ServersMeetMinimumVersion(j.srv.Members(), minJobTrashVersion, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use the job trash", minJobTrashVersion)
+	}
+
+	// Check for submit-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.JobID == "" {
+		return fmt.Errorf("missing job ID for restoring job")
+	}
+
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	trashed, err := snap.TrashedJobByID(nil, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if trashed == nil {
+		return structs.NewErrRPCCoded(404, fmt.Sprintf("job %q not found in the job trash", args.JobID))
+	}
+	job, err := snap.JobByID(nil, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job != nil {
+		return structs.NewErrRPCCoded(http.StatusConflict,
+			fmt.Sprintf("job %q was registered since it was purged", args.JobID))
+	}
+
+	// Commit this restore request via Raft
+	resp, index, err := j.srv.raftApply(structs.JobRestoreRequestType, args)
+	if err != nil {
+		j.logger.Error("restoring job failed", "error", err)
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	// Setup the reply
+	reply.JobModifyIndex = index
+	reply.Index = index
+	return nil
+}
+
+// ExpireTrash permanently deletes the expired jobs from the job trash. It is
+// called only by garbage collection.
+func (j *Job) ExpireTrash(args *structs.JobTrashExpireRequest, reply *structs.GenericResponse) error {
+	if done, err := j.srv.forward("Job.ExpireTrash", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "expire_trash"}, time.Now())
+
+	if !ServersMeetMinimumVersion(j.srv.Members(), minJobTrashVersion, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use the job trash", minJobTrashVersion)
+	}
+
+	// Check management level permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	args.Timestamp = time.Now() // use the leader's timestamp
+
+	// Avoid writing to raft on every GC interval when no job expired
+	expired, err := j.hasExpiredTrashedJobs(args.Timestamp)
+	if err != nil {
+		return err
+	}
+	if !expired {
+		return nil
+	}
+
+	_, index, err := j.srv.raftApply(structs.JobTrashExpireRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// hasExpiredTrashedJobs returns whether any job in the job trash expired
+// before the given timestamp.
+func (j *Job) hasExpiredTrashedJobs(timestamp time.Time) (bool, error) {
+	iter, err := j.srv.State().TrashedJobs(nil)
+	if err != nil {
+		return false, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.TrashedJob).ExpiresAt.Before(timestamp) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Scale is used to modify one of the scaling targets in the job
 func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterResponse) error {
 	if done, err := j.srv.forward("Job.Scale", args, args, reply); done {
@@ -1014,6 +1283,35 @@ func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterRes
 		return structs.NewErrRPCCoded(404, fmt.Sprintf("job %q not found", args.JobID))
 	}
 
+	// Return the original result for retries of a scale that already changed
+	// the count of the job
+	if args.Count != nil && isIdempotentRetry(args.IdempotencyToken, job) {
+		eval, err := idempotentRetryEval(snap, job, structs.EvalTriggerScaling)
+		if err != nil {
+			return err
+		}
+		if eval != nil {
+			reply.EvalID = eval.ID
+			reply.EvalCreateIndex = eval.CreateIndex
+		}
+		reply.JobModifyIndex = job.JobModifyIndex
+		reply.Index = job.ModifyIndex
+		return nil
+	}
+
+	// If EnforceIndex set, check it before trying to apply
+	if args.EnforceIndex && args.JobModifyIndex != job.JobModifyIndex {
+		return fmt.Errorf("%s %d: job exists with conflicting job modify index: %d",
+			RegisterEnforceIndexErrPrefix, args.JobModifyIndex, job.JobModifyIndex)
+	}
+
+	// Scaling events without a count change are still allowed on frozen jobs
+	if args.Count != nil {
+		if err := checkJobFrozen(aclObj, job); err != nil {
+			return err
+		}
+	}
+
 	// Find target group in job TaskGroups
 	groupName := args.Target[structs.ScalingTargetGroup]
 	var group *structs.TaskGroup
@@ -1063,6 +1361,7 @@ func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterRes
 
 		// Update group count
 		group.Count = int(*args.Count)
+		job.IdempotencyToken = args.IdempotencyToken
 
 		// Block scaling event if there's an active deployment
 		deployment, err := snap.LatestDeploymentByJobID(ws, namespace, args.JobID)
@@ -1394,6 +1693,70 @@ func (j *Job) List(args *structs.JobListRequest, reply *structs.JobListResponse)
 	return j.srv.blockingRPC(&opts)
 }
 
+// ListTrash is used to list the purged jobs in the job trash
+func (j *Job) ListTrash(args *structs.JobTrashListRequest, reply *structs.JobTrashListResponse) error {
+	if done, err := j.srv.forward("Job.ListTrash", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "list_trash"}, time.Now())
+
+	namespace := args.RequestNamespace()
+
+	// Check for list-job permissions
+	aclObj, err := j.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityListJobs) {
+		return structs.ErrPermissionDenied
+	}
+	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityListJobs)
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			reply.Jobs = make([]*structs.TrashedJobListStub, 0)
+
+			// Get the namespaces the user is allowed to access.
+			allowableNamespaces, err := allowedNSes(aclObj, state, allow)
+			if err != nil && err != structs.ErrPermissionDenied {
+				return err
+			} else if err == nil {
+				var iter memdb.ResultIterator
+				if namespace == structs.AllNamespacesSentinel {
+					iter, err = state.TrashedJobs(ws)
+				} else {
+					iter, err = state.TrashedJobsByNamespace(ws, namespace)
+				}
+				if err != nil {
+					return err
+				}
+
+				for raw := iter.Next(); raw != nil; raw = iter.Next() {
+					trashed := raw.(*structs.TrashedJob)
+					if allowableNamespaces != nil && !allowableNamespaces[trashed.Namespace] {
+						continue
+					}
+					reply.Jobs = append(reply.Jobs, trashed.Stub())
+				}
+			}
+
+			// Use the last index that affected the job trash table
+			index, err := state.Index("job_trash")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // Allocations is used to list the allocations for a job
 func (j *Job) Allocations(args *structs.JobSpecificRequest,
 	reply *structs.JobAllocationsResponse) error {
@@ -1533,6 +1896,68 @@ func (j *Job) Deployments(args *structs.JobSpecificRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// Lineage is used to explain the chain of evaluations, deployments and
+// allocations caused by the changes of a job
+func (j *Job) Lineage(args *structs.JobSpecificRequest,
+	reply *structs.JobLineageResponse) error {
+	if done, err := j.srv.forward("Job.Lineage", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "lineage"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			versions, err := state.JobVersionsByID(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+			evals, err := state.EvalsByJob(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+			deploys, err := state.DeploymentsByJobID(ws, args.RequestNamespace(), args.JobID, true)
+			if err != nil {
+				return err
+			}
+			allocs, err := state.AllocsByJob(ws, args.RequestNamespace(), args.JobID, true)
+			if err != nil {
+				return err
+			}
+
+			// Use the last index that affected any of the tables
+			index, err := state.Index("job_version")
+			if err != nil {
+				return err
+			}
+			for _, table := range []string{"evals", "deployment", "allocs"} {
+				i, err := state.Index(table)
+				if err != nil {
+					return err
+				}
+				if i > index {
+					index = i
+				}
+			}
+			reply.Index = index
+			reply.Lineage = structs.BuildJobLineage(versions, evals, deploys, allocs)
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // LatestDeployment is used to retrieve the latest deployment for a job
 func (j *Job) LatestDeployment(args *structs.JobSpecificRequest,
 	reply *structs.SingleDeploymentResponse) error {
@@ -1588,6 +2013,24 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "plan"}, time.Now())
 
+	return j.plan(args, reply, false)
+}
+
+// PlanVerbose is used to dry-run a job update like Plan, and additionally
+// explains every placement: the annotations of the plan hold, for every
+// candidate node, the check which rejected it or its scores.
+func (j *Job) PlanVerbose(args *structs.JobPlanRequest, reply *structs.JobPlanResponse) error {
+	if done, err := j.srv.forward("Job.PlanVerbose", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "plan_verbose"}, time.Now())
+
+	return j.plan(args, reply, true)
+}
+
+// plan dry-runs the job update of the request, explaining the placements if
+// explain is set.
+func (j *Job) plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse, explain bool) error {
 	// Validate the arguments
 	if args.Job == nil {
 		return fmt.Errorf("Job required for plan")
@@ -1678,15 +2121,16 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	// Create an eval and mark it as requiring annotations and insert that as well
 	now := time.Now().UnixNano()
 	eval := &structs.Evaluation{
-		ID:             uuid.Generate(),
-		Namespace:      args.RequestNamespace(),
-		Priority:       args.Job.Priority,
-		Type:           args.Job.Type,
-		TriggeredBy:    structs.EvalTriggerJobRegister,
-		JobID:          args.Job.ID,
-		JobModifyIndex: updatedIndex,
-		Status:         structs.EvalStatusPending,
-		AnnotatePlan:   true,
+		ID:                uuid.Generate(),
+		Namespace:         args.RequestNamespace(),
+		Priority:          args.Job.Priority,
+		Type:              args.Job.Type,
+		TriggeredBy:       structs.EvalTriggerJobRegister,
+		JobID:             args.Job.ID,
+		JobModifyIndex:    updatedIndex,
+		Status:            structs.EvalStatusPending,
+		AnnotatePlan:      true,
+		ExplainPlacements: explain,
 		// Timestamps are added for consistency but this eval is never persisted
 		CreateTime: now,
 		ModifyTime: now,
@@ -1834,6 +2278,10 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 		return fmt.Errorf("Specified job %q is stopped", args.JobID)
 	}
 
+	if err := checkJobFrozen(aclObj, parameterizedJob); err != nil {
+		return err
+	}
+
 	// Validate the arguments
 	if err := validateDispatchRequest(args, parameterizedJob); err != nil {
 		return err
@@ -1926,6 +2374,7 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 			ID:             uuid.Generate(),
 			Namespace:      args.RequestNamespace(),
 			Priority:       dispatchJob.Priority,
+			Deadline:       dispatchJob.DeadlineTime(),
 			Type:           dispatchJob.Type,
 			TriggeredBy:    structs.EvalTriggerJobRegister,
 			JobID:          dispatchJob.ID,
@@ -17,14 +17,21 @@ const (
 	// defaultConnectTimeout is the default amount of time a connect gateway will
 	// wait for a response from an upstream service (same as consul)
 	defaultConnectTimeout = 5 * time.Second
+
+	// connectUpstreamCPU and connectUpstreamMemoryMB are the resources added
+	// to the default resources of a sidecar task for each of its upstreams
+	connectUpstreamCPU      = 10
+	connectUpstreamMemoryMB = 8
 )
 
 // connectSidecarResources returns the set of resources used by default for
-// the Consul Connect sidecar task
-func connectSidecarResources() *structs.Resources {
+// the Consul Connect sidecar task. Envoy maintains a listener and a cluster
+// for each upstream, so sidecar tasks opting in with scale_with_upstreams are
+// given more resources for each upstream.
+func connectSidecarResources(upstreams int) *structs.Resources {
 	return &structs.Resources{
-		CPU:      250,
-		MemoryMB: 128,
+		CPU:      250 + connectUpstreamCPU*upstreams,
+		MemoryMB: 128 + connectUpstreamMemoryMB*upstreams,
 	}
 }
 
@@ -248,7 +255,12 @@ func groupConnectHook(job *structs.Job, g *structs.TaskGroup) error {
 
 			// If the task doesn't already exist, create a new one and add it to the job
 			if task == nil {
-				task = newConnectSidecarTask(service.Name)
+				var upstreams int
+				proxy := service.Connect.SidecarService.Proxy
+				if proxy != nil && service.Connect.SidecarTask != nil && service.Connect.SidecarTask.ScaleWithUpstreams {
+					upstreams = len(proxy.Upstreams)
+				}
+				task = newConnectSidecarTask(service.Name, upstreams)
 
 				// If there happens to be a task defined with the same name
 				// append an UUID fragment to the task name
@@ -466,12 +478,12 @@ func newConnectGatewayTask(prefix, service string, netHost, customizedTls bool)
 			MaxFiles:      2,
 			MaxFileSizeMB: 2,
 		},
-		Resources:   connectSidecarResources(),
+		Resources:   connectSidecarResources(0),
 		Constraints: constraints,
 	}
 }
 
-func newConnectSidecarTask(service string) *structs.Task {
+func newConnectSidecarTask(service string, upstreams int) *structs.Task {
 	return &structs.Task{
 		// Name is used in container name so must start with '[A-Za-z0-9]'
 		Name:          fmt.Sprintf("%s-%s", structs.ConnectProxyPrefix, service),
@@ -483,7 +495,7 @@ func newConnectSidecarTask(service string) *structs.Task {
 			MaxFiles:      2,
 			MaxFileSizeMB: 2,
 		},
-		Resources: connectSidecarResources(),
+		Resources: connectSidecarResources(upstreams),
 		Lifecycle: &structs.TaskLifecycleConfig{
 			Hook:    structs.TaskLifecycleHookPrestart,
 			Sidecar: true,
@@ -87,8 +87,8 @@ func TestJobEndpointConnect_groupConnectHook(t *testing.T) {
 	// Expected tasks
 	tgExp := job.TaskGroups[0].Copy()
 	tgExp.Tasks = []*structs.Task{
-		newConnectSidecarTask("backend"),
-		newConnectSidecarTask("admin"),
+		newConnectSidecarTask("backend", 0),
+		newConnectSidecarTask("admin", 0),
 	}
 	tgExp.Services[0].Name = "backend"
 	tgExp.Services[1].Name = "admin"
@@ -113,6 +113,36 @@ func TestJobEndpointConnect_groupConnectHook(t *testing.T) {
 	require.Exactly(t, tgExp, job.TaskGroups[0])
 }
 
+func TestJobEndpointConnect_groupConnectHook_UpstreamResources(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.ConnectJob()
+	job.TaskGroups[0].Services[0].Connect.SidecarService.Proxy = &structs.ConsulProxy{
+		Upstreams: []structs.ConsulUpstream{{
+			DestinationName: "db",
+			LocalBindPort:   9000,
+		}, {
+			DestinationName: "cache",
+			LocalBindPort:   9001,
+		}},
+	}
+
+	// Without opting in, the sidecar is given the fixed default resources
+	scaled := job.Copy()
+	require.NoError(t, groupConnectHook(job, job.TaskGroups[0]))
+	sidecar := job.TaskGroups[0].Tasks[1]
+	require.Equal(t, connectSidecarResources(0), sidecar.Resources)
+
+	// Opting in grows the default resources with the upstreams
+	scaled.TaskGroups[0].Services[0].Connect.SidecarTask = &structs.SidecarTask{
+		ScaleWithUpstreams: true,
+	}
+	require.NoError(t, groupConnectHook(scaled, scaled.TaskGroups[0]))
+	sidecar = scaled.TaskGroups[0].Tasks[1]
+	require.Equal(t, 250+2*connectUpstreamCPU, sidecar.Resources.CPU)
+	require.Equal(t, 128+2*connectUpstreamMemoryMB, sidecar.Resources.MemoryMB)
+}
+
 func TestJobEndpointConnect_groupConnectHook_IngressGateway_BridgeNetwork(t *testing.T) {
 	ci.Parallel(t)
 
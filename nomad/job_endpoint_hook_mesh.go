@@ -0,0 +1,95 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// meshSidecarResources returns the set of resources used by default for the
+// sidecar task of an external service mesh.
+func meshSidecarResources() *structs.Resources {
+	return &structs.Resources{
+		CPU:      250,
+		MemoryMB: 128,
+	}
+}
+
+// meshHookConstraint is used when building the sidecar task to ensure the
+// client has a mesh hook registered for the type of the mesh.
+func meshHookConstraint(meshType string) *structs.Constraint {
+	return &structs.Constraint{
+		LTarget: fmt.Sprintf("${attr.plugins.mesh.%s}", meshType),
+		Operand: structs.ConstraintAttributeIsSet,
+	}
+}
+
+// jobMeshHook implements a job Mutating admission controller injecting the
+// sidecar task of the task groups integrated with an external service mesh.
+type jobMeshHook struct{}
+
+func (jobMeshHook) Name() string {
+	return "mesh"
+}
+
+func (jobMeshHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	for _, g := range job.TaskGroups {
+		// Let validation report the missing mesh type
+		if g.Mesh == nil || g.Mesh.Type == "" {
+			continue
+		}
+
+		// Check to see if the sidecar task already exists
+		if g.LookupMeshSidecar() != nil {
+			continue
+		}
+
+		task := newMeshSidecarTask(g.Mesh)
+
+		// If there happens to be a task defined with the same name append an
+		// UUID fragment to the task name
+		for _, t := range g.Tasks {
+			if t.Name == task.Name {
+				task.Name = task.Name + "-" + uuid.Generate()[:6]
+				break
+			}
+		}
+
+		// Canonicalize task since this mutator runs after job canonicalization
+		task.Canonicalize(job, g)
+		g.Tasks = append(g.Tasks, task)
+	}
+
+	return job, nil, nil
+}
+
+// newMeshSidecarTask returns the sidecar task of the mesh. Its driver
+// configuration is rendered on the client by the mesh hook.
+func newMeshSidecarTask(mesh *structs.Mesh) *structs.Task {
+	resources := mesh.Resources.Copy()
+	if resources == nil {
+		resources = meshSidecarResources()
+	}
+
+	return &structs.Task{
+		// Name is used in container name so must start with '[A-Za-z0-9]'
+		Name:          fmt.Sprintf("%s-%s", structs.MeshSidecarPrefix, mesh.Type),
+		Kind:          structs.NewTaskKind(structs.MeshSidecarPrefix, mesh.Type),
+		Driver:        mesh.Driver,
+		ShutdownDelay: 5 * time.Second,
+		LogConfig: &structs.LogConfig{
+			MaxFiles:      2,
+			MaxFileSizeMB: 2,
+		},
+		Resources: resources,
+		Lifecycle: &structs.TaskLifecycleConfig{
+			Hook:    structs.TaskLifecycleHookPrestart,
+			Sidecar: true,
+		},
+		Constraints: structs.Constraints{
+			meshHookConstraint(mesh.Type),
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobEndpointMesh_Mutate(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Networks = structs.Networks{{Mode: "bridge"}}
+	tg.Mesh = &structs.Mesh{
+		Type:   "istio",
+		Driver: "docker",
+	}
+	tg.Tasks = append(tg.Tasks, &structs.Task{Name: "mesh-sidecar-istio"})
+
+	job, warnings, err := jobMeshHook{}.Mutate(job)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	// The sidecar is injected with a unique name
+	tg = job.TaskGroups[0]
+	require.Len(t, tg.Tasks, 3)
+	sidecar := tg.LookupMeshSidecar()
+	require.NotNil(t, sidecar)
+	require.Equal(t, structs.TaskKind("mesh-sidecar:istio"), sidecar.Kind)
+	require.NotEqual(t, "mesh-sidecar-istio", sidecar.Name)
+	require.Equal(t, "docker", sidecar.Driver)
+	require.Equal(t, meshSidecarResources(), sidecar.Resources)
+	require.Equal(t, structs.TaskLifecycleHookPrestart, sidecar.Lifecycle.Hook)
+	require.True(t, sidecar.Lifecycle.Sidecar)
+	require.Equal(t, []*structs.Constraint{{
+		LTarget: "${attr.plugins.mesh.istio}",
+		Operand: structs.ConstraintAttributeIsSet,
+	}}, sidecar.Constraints)
+
+	// Mutating the job again doesn't inject another sidecar
+	job, _, err = jobMeshHook{}.Mutate(job)
+	require.NoError(t, err)
+	require.Len(t, job.TaskGroups[0].Tasks, 3)
+}
+
+func TestJobEndpointMesh_Mutate_Resources(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Mesh = &structs.Mesh{
+		Type:      "istio",
+		Driver:    "exec",
+		Resources: &structs.Resources{CPU: 500, MemoryMB: 256},
+	}
+
+	job, _, err := jobMeshHook{}.Mutate(job)
+	require.NoError(t, err)
+
+	sidecar := job.TaskGroups[0].LookupMeshSidecar()
+	require.Equal(t, "mesh-sidecar-istio", sidecar.Name)
+	require.Equal(t, "exec", sidecar.Driver)
+	require.Equal(t, 500, sidecar.Resources.CPU)
+	require.Equal(t, 256, sidecar.Resources.MemoryMB)
+}
@@ -658,7 +658,7 @@ func TestJobEndpoint_Register_ConnectWithSidecarTask(t *testing.T) {
 	// Check that the correct fields were overridden from the sidecar_task stanza
 	require.Equal("test", sidecarTask.Meta["source"])
 	require.Equal(500, sidecarTask.Resources.CPU)
-	require.Equal(connectSidecarResources().MemoryMB, sidecarTask.Resources.MemoryMB)
+	require.Equal(connectSidecarResources(0).MemoryMB, sidecarTask.Resources.MemoryMB)
 	cfg := connectSidecarDriverConfig()
 	cfg["labels"] = map[string]interface{}{
 		"foo": "bar",
@@ -683,6 +683,66 @@ func TestJobEndpoint_Register_ConnectWithSidecarTask(t *testing.T) {
 
 }
 
+// TestJobEndpoint_Register_ConnectUpstreams_Upgrade asserts that resubmitting
+// a Connect job with upstreams registered before sidecar resources could scale
+// with the upstreams does not change its sidecar task.
+func TestJobEndpoint_Register_ConnectUpstreams_Upgrade(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.ConnectJob()
+	job.TaskGroups[0].Tasks[0].Services = nil
+	job.TaskGroups[0].Services[0].Connect.SidecarService.Proxy = &structs.ConsulProxy{
+		Upstreams: []structs.ConsulUpstream{{
+			DestinationName: "db",
+			LocalBindPort:   9000,
+		}},
+	}
+
+	// Store the job as registered by an older server, whose sidecar task
+	// was given fixed default resources.
+	existing := job.Copy()
+	existing.Canonicalize()
+	require.NoError(t, groupConnectHook(existing, existing.TaskGroups[0]))
+	sidecar := existing.TaskGroups[0].Tasks[1]
+	sidecar.Resources = &structs.Resources{CPU: 250, MemoryMB: 128}
+	require.NoError(t, s1.fsm.State().UpsertJob(structs.MsgTypeTestSetup, 1000, existing))
+
+	// Planning the resubmitted job must not show any changes
+	planReq := &structs.JobPlanRequest{
+		Job:  job,
+		Diff: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var planResp structs.JobPlanResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+	require.Equal(t, structs.DiffTypeNone, planResp.Diff.Type)
+
+	// Resubmitting the job keeps the sidecar task resources
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, sidecar.Resources, out.TaskGroups[0].Tasks[1].Resources)
+}
+
 func TestJobEndpoint_Register_Connect_ValidatesWithoutSidecarTask(t *testing.T) {
 	ci.Parallel(t)
 
@@ -734,6 +794,51 @@ func TestJobEndpoint_Register_Connect_ValidatesWithoutSidecarTask(t *testing.T)
 	require.Contains(t, err.Error(), "exposed_no_sidecar requires use of sidecar_proxy")
 }
 
+func TestJobEndpoint_Register_IdempotencyToken(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job:            job,
+		EnforceIndex:   true,
+		JobModifyIndex: 0,
+		WriteRequest: structs.WriteRequest{
+			Region:           "global",
+			Namespace:        job.Namespace,
+			IdempotencyToken: "foo",
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.NotEmpty(resp.EvalID)
+
+	// Retrying with the same token returns the original result, even though
+	// the enforced index is now stale
+	var retryResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &retryResp))
+	require.Equal(resp.EvalID, retryResp.EvalID)
+	require.Equal(resp.JobModifyIndex, retryResp.JobModifyIndex)
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal("foo", out.IdempotencyToken)
+	require.Equal(uint64(0), out.Version)
+
+	// A different token is subject to the enforced index
+	req.IdempotencyToken = "bar"
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &retryResp)
+	require.Error(err)
+	require.Contains(err.Error(), RegisterEnforceIndexErrPrefix)
+}
+
 func TestJobEndpoint_Register_ACL(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2798,7 +2903,450 @@ func TestJobEndpoint_Stable(t *testing.T) {
 	codec := rpcClient(t, s1)
 	testutil.WaitForLeader(t, s1.RPC)
 
-	// Create the initial register request
+	// Create the initial register request
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Fetch the response
+	var resp structs.JobRegisterResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp.Index == 0 {
+		t.Fatalf("bad index: %d", resp.Index)
+	}
+
+	// Create stability request
+	stableReq := &structs.JobStabilityRequest{
+		JobID:      job.ID,
+		JobVersion: 0,
+		Stable:     true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Fetch the response
+	var stableResp structs.JobStabilityResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &stableResp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if stableResp.Index == 0 {
+		t.Fatalf("bad index: %d", resp.Index)
+	}
+
+	// Check that the job is marked stable
+	state := s1.fsm.State()
+	ws := memdb.NewWatchSet()
+	out, err := state.JobByID(ws, job.Namespace, job.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out == nil {
+		t.Fatalf("expected job")
+	}
+	if !out.Stable {
+		t.Fatalf("Job is not marked stable")
+	}
+}
+
+func TestJobEndpoint_Stable_ACL(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	state := s1.fsm.State()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job
+	job := mock.Job()
+	err := state.UpsertJob(structs.MsgTypeTestSetup, 1000, job)
+	require.Nil(err)
+
+	// Create stability request
+	stableReq := &structs.JobStabilityRequest{
+		JobID:      job.ID,
+		JobVersion: 0,
+		Stable:     true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Attempt to fetch the token without a token
+	var stableResp structs.JobStabilityResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &stableResp)
+	require.NotNil(err)
+	require.Contains("Permission denied", err.Error())
+
+	// Expect failure for request with an invalid token
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+
+	stableReq.AuthToken = invalidToken.SecretID
+	var invalidStableResp structs.JobStabilityResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &invalidStableResp)
+	require.NotNil(err)
+	require.Contains("Permission denied", err.Error())
+
+	// Attempt to fetch with a management token
+	stableReq.AuthToken = root.SecretID
+	var validStableResp structs.JobStabilityResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &validStableResp)
+	require.Nil(err)
+
+	// Attempt to fetch with a valid token
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+
+	stableReq.AuthToken = validToken.SecretID
+	var validStableResp2 structs.JobStabilityResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &validStableResp2)
+	require.Nil(err)
+
+	// Check that the job is marked stable
+	ws := memdb.NewWatchSet()
+	out, err := state.JobByID(ws, job.Namespace, job.ID)
+	require.Nil(err)
+	require.NotNil(job)
+	require.Equal(true, out.Stable)
+}
+
+func TestJobEndpoint_Freeze(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Freeze the job
+	freezeReq := &structs.JobFreezeRequest{
+		JobID:  job.ID,
+		Frozen: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var freezeResp structs.JobFreezeResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Freeze", freezeReq, &freezeResp))
+	require.NotZero(freezeResp.Index)
+
+	state := s1.fsm.State()
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.True(out.Frozen)
+	require.Equal(uint64(0), out.Version)
+
+	// Updates, scaling, and stopping a frozen job are rejected
+	job2 := job.Copy()
+	job2.Priority = 90
+	req.Job = job2
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.EqualError(err, structs.ErrJobFrozen.Error())
+
+	count := int64(3)
+	scaleReq := &structs.JobScaleRequest{
+		JobID: job.ID,
+		Target: map[string]string{
+			structs.ScalingTargetGroup: job.TaskGroups[0].Name,
+		},
+		Count: &count,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Job.Scale", scaleReq, &resp)
+	require.EqualError(err, structs.ErrJobFrozen.Error())
+
+	deregReq := &structs.JobDeregisterRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var deregResp structs.JobDeregisterResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Deregister", deregReq, &deregResp)
+	require.EqualError(err, structs.ErrJobFrozen.Error())
+
+	// Unfreeze the job and update it
+	freezeReq.Frozen = false
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Freeze", freezeReq, &freezeResp))
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	out, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.False(out.Frozen)
+	require.Equal(90, out.Priority)
+}
+
+func TestJobEndpoint_Freeze_ACL(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	state := s1.fsm.State()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job
+	job := mock.Job()
+	require.NoError(state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	submitToken := mock.CreatePolicyAndToken(t, state, 1001, "test-submit",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+	unfreezeToken := mock.CreatePolicyAndToken(t, state, 1002, "test-unfreeze",
+		mock.NamespacePolicy(structs.DefaultNamespace, "",
+			[]string{acl.NamespaceCapabilitySubmitJob, acl.NamespaceCapabilityUnfreeze}))
+
+	freezeReq := &structs.JobFreezeRequest{
+		JobID:  job.ID,
+		Frozen: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Expect failure without a token
+	var freezeResp structs.JobFreezeResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Freeze", freezeReq, &freezeResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Freezing requires submit-job
+	freezeReq.AuthToken = submitToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Freeze", freezeReq, &freezeResp))
+
+	// Updating a frozen job requires the unfreeze capability
+	job2 := job.Copy()
+	job2.Priority = 90
+	req := &structs.JobRegisterRequest{
+		Job: job2,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+			AuthToken: submitToken.SecretID,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.EqualError(err, structs.ErrJobFrozen.Error())
+
+	req.AuthToken = unfreezeToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// The job stays frozen across updates
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.True(out.Frozen)
+	require.Equal(90, out.Priority)
+
+	// Unfreezing requires the unfreeze capability
+	freezeReq.Frozen = false
+	err = msgpackrpc.CallWithCodec(codec, "Job.Freeze", freezeReq, &freezeResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	freezeReq.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Freeze", freezeReq, &freezeResp))
+
+	out, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.False(out.Frozen)
+}
+
+func TestJobEndpoint_Freeze_OldServers(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+
+		// simulate a server that can't apply job freeze requests
+		c.Build = "1.3.3"
+	})
+	defer cleanupS2()
+
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+	codec := rpcClient(t, s1)
+
+	job := mock.Job()
+	require.NoError(t, s1.fsm.State().UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	req := &structs.JobFreezeRequest{
+		JobID:  job.ID,
+		Frozen: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobFreezeResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Freeze", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "All servers should be running version")
+}
+
+func TestJobEndpoint_Restore(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobTrashWindow = time.Hour
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job and purge it
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	dereg := &structs.JobDeregisterRequest{
+		JobID: job.ID,
+		Purge: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var deregResp structs.JobDeregisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp))
+
+	// The purged job is listed in the job trash
+	listReq := &structs.JobTrashListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var listResp structs.JobTrashListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.ListTrash", listReq, &listResp))
+	require.Len(listResp.Jobs, 1)
+	require.Equal(job.ID, listResp.Jobs[0].ID)
+	require.WithinDuration(time.Now().Add(time.Hour), listResp.Jobs[0].ExpiresAt, time.Minute)
+
+	// Restoring fails while a job with the same ID is registered
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	restoreReq := &structs.JobRestoreRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var restoreResp structs.JobRestoreResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Restore", restoreReq, &restoreResp)
+	require.Error(err)
+	require.Contains(err.Error(), "was registered since it was purged")
+
+	// Purging the job again replaces it in the job trash
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp))
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Restore", restoreReq, &restoreResp))
+	require.NotZero(restoreResp.JobModifyIndex)
+
+	state := s1.fsm.State()
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.True(out.Stop)
+
+	err = msgpackrpc.CallWithCodec(codec, "Job.Restore", restoreReq, &restoreResp)
+	require.Error(err)
+	require.Contains(err.Error(), "not found in the job trash")
+
+	// Expiring the job trash only deletes the expired jobs
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp))
+	expireReq := &structs.JobTrashExpireRequest{
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+	var expireResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.ExpireTrash", expireReq, &expireResp))
+	trashed, err := state.TrashedJobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(trashed)
+
+	// Nothing is written to raft when no job expired
+	require.Zero(expireResp.Index)
+}
+
+func TestJobEndpoint_Restore_OldServers(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobTrashWindow = time.Hour
+	})
+	defer cleanupS1()
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobTrashWindow = time.Hour
+
+		// simulate a server that doesn't support the job trash
+		c.Build = "1.3.3"
+	})
+	defer cleanupS2()
+
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+	codec := rpcClient(t, s1)
+
 	job := mock.Job()
 	req := &structs.JobRegisterRequest{
 		Job: job,
@@ -2807,116 +3355,144 @@ func TestJobEndpoint_Stable(t *testing.T) {
 			Namespace: job.Namespace,
 		},
 	}
-
-	// Fetch the response
 	var resp structs.JobRegisterResponse
-	if err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp); err != nil {
-		t.Fatalf("err: %v", err)
-	}
-	if resp.Index == 0 {
-		t.Fatalf("bad index: %d", resp.Index)
-	}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
 
-	// Create stability request
-	stableReq := &structs.JobStabilityRequest{
-		JobID:      job.ID,
-		JobVersion: 0,
-		Stable:     true,
+	// Purged jobs are deleted immediately until all servers are upgraded
+	dereg := &structs.JobDeregisterRequest{
+		JobID: job.ID,
+		Purge: true,
 		WriteRequest: structs.WriteRequest{
 			Region:    "global",
 			Namespace: job.Namespace,
 		},
 	}
+	var deregResp structs.JobDeregisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp))
 
-	// Fetch the response
-	var stableResp structs.JobStabilityResponse
-	if err := msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &stableResp); err != nil {
-		t.Fatalf("err: %v", err)
-	}
-	if stableResp.Index == 0 {
-		t.Fatalf("bad index: %d", resp.Index)
-	}
+	trashed, err := s1.fsm.State().TrashedJobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Nil(trashed)
 
-	// Check that the job is marked stable
-	state := s1.fsm.State()
-	ws := memdb.NewWatchSet()
-	out, err := state.JobByID(ws, job.Namespace, job.ID)
-	if err != nil {
-		t.Fatalf("err: %v", err)
-	}
-	if out == nil {
-		t.Fatalf("expected job")
+	restoreReq := &structs.JobRestoreRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
 	}
-	if !out.Stable {
-		t.Fatalf("Job is not marked stable")
+	var restoreResp structs.JobRestoreResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Restore", restoreReq, &restoreResp)
+	require.Error(err)
+	require.Contains(err.Error(), "All servers should be running version")
+}
+
+func TestJobEndpoint_Restore_Disabled(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	require.NoError(s1.fsm.State().UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	// Purged jobs are deleted immediately without a trash window
+	dereg := &structs.JobDeregisterRequest{
+		JobID: job.ID,
+		Purge: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
 	}
+	var deregResp structs.JobDeregisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp))
+
+	trashed, err := s1.fsm.State().TrashedJobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Nil(trashed)
 }
 
-func TestJobEndpoint_Stable_ACL(t *testing.T) {
+func TestJobEndpoint_Restore_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
 
 	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
 		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobTrashWindow = time.Hour
 	})
 	defer cleanupS1()
 	codec := rpcClient(t, s1)
 	state := s1.fsm.State()
 	testutil.WaitForLeader(t, s1.RPC)
 
-	// Register the job
 	job := mock.Job()
-	err := state.UpsertJob(structs.MsgTypeTestSetup, 1000, job)
-	require.Nil(err)
-
-	// Create stability request
-	stableReq := &structs.JobStabilityRequest{
-		JobID:      job.ID,
-		JobVersion: 0,
-		Stable:     true,
+	require.NoError(state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+	dereg := &structs.JobDeregisterRequest{
+		JobID: job.ID,
+		Purge: true,
 		WriteRequest: structs.WriteRequest{
 			Region:    "global",
 			Namespace: job.Namespace,
+			AuthToken: root.SecretID,
 		},
 	}
+	var deregResp structs.JobDeregisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp))
 
-	// Attempt to fetch the token without a token
-	var stableResp structs.JobStabilityResponse
-	err = msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &stableResp)
-	require.NotNil(err)
-	require.Contains("Permission denied", err.Error())
-
-	// Expect failure for request with an invalid token
-	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+	readToken := mock.CreatePolicyAndToken(t, state, 1002, "test-read",
 		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	submitToken := mock.CreatePolicyAndToken(t, state, 1003, "test-submit",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
 
-	stableReq.AuthToken = invalidToken.SecretID
-	var invalidStableResp structs.JobStabilityResponse
-	err = msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &invalidStableResp)
-	require.NotNil(err)
-	require.Contains("Permission denied", err.Error())
+	// Listing the job trash requires list-jobs
+	listReq := &structs.JobTrashListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var listResp structs.JobTrashListResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.ListTrash", listReq, &listResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
 
-	// Attempt to fetch with a management token
-	stableReq.AuthToken = root.SecretID
-	var validStableResp structs.JobStabilityResponse
-	err = msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &validStableResp)
-	require.Nil(err)
+	listReq.AuthToken = readToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.ListTrash", listReq, &listResp))
+	require.Len(listResp.Jobs, 1)
 
-	// Attempt to fetch with a valid token
-	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-invalid",
-		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+	// Restoring a job requires submit-job
+	restoreReq := &structs.JobRestoreRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var restoreResp structs.JobRestoreResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Restore", restoreReq, &restoreResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
 
-	stableReq.AuthToken = validToken.SecretID
-	var validStableResp2 structs.JobStabilityResponse
-	err = msgpackrpc.CallWithCodec(codec, "Job.Stable", stableReq, &validStableResp2)
-	require.Nil(err)
+	restoreReq.AuthToken = submitToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Restore", restoreReq, &restoreResp))
 
-	// Check that the job is marked stable
-	ws := memdb.NewWatchSet()
-	out, err := state.JobByID(ws, job.Namespace, job.ID)
-	require.Nil(err)
-	require.NotNil(job)
-	require.Equal(true, out.Stable)
+	// Expiring the job trash requires a management token
+	expireReq := &structs.JobTrashExpireRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: submitToken.SecretID,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Job.ExpireTrash", expireReq, &structs.GenericResponse{})
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	expireReq.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.ExpireTrash", expireReq, &structs.GenericResponse{}))
 }
 
 func TestJobEndpoint_Evaluate(t *testing.T) {
@@ -3531,6 +4107,59 @@ func TestJobEndpoint_Deregister_EvalPriority(t *testing.T) {
 	requireAssert.Equal(99, out.Priority)
 }
 
+func TestJobEndpoint_Deregister_EnforceIndex(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job
+	job := mock.Job()
+	var regResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}, &regResp))
+
+	// Deregistering at a stale index fails
+	deregReq := &structs.JobDeregisterRequest{
+		JobID:          job.ID,
+		EnforceIndex:   true,
+		JobModifyIndex: regResp.JobModifyIndex - 1,
+		WriteRequest: structs.WriteRequest{
+			Region:           "global",
+			Namespace:        job.Namespace,
+			IdempotencyToken: "foo",
+		},
+	}
+	var deregResp structs.JobDeregisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Deregister", deregReq, &deregResp)
+	require.Error(err)
+	require.Contains(err.Error(), RegisterEnforceIndexErrPrefix)
+
+	// Deregistering at the current index succeeds
+	deregReq.JobModifyIndex = regResp.JobModifyIndex
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", deregReq, &deregResp))
+	require.NotEmpty(deregResp.EvalID)
+
+	// Retrying with the same token returns the original evaluation
+	var retryResp structs.JobDeregisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", deregReq, &retryResp))
+	require.Equal(deregResp.EvalID, retryResp.EvalID)
+
+	evals, err := s1.fsm.State().EvalsByJob(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Len(evals, 2)
+}
+
 func TestJobEndpoint_Deregister_Periodic(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5768,6 +6397,61 @@ func TestJobEndpoint_Deployments_ACL(t *testing.T) {
 	require.Len(validResp2.Deployments, 2, "deployments for job")
 }
 
+func TestJobEndpoint_Lineage(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+	require := require.New(t)
+
+	// Create a job with an eval placing an alloc
+	j := mock.Job()
+	require.Nil(state.UpsertJob(structs.MsgTypeTestSetup, 1000, j))
+	eval := mock.Eval()
+	eval.JobID = j.ID
+	eval.TriggeredBy = structs.EvalTriggerJobRegister
+	eval.JobModifyIndex = j.JobModifyIndex
+	require.Nil(state.UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval}))
+	alloc := mock.Alloc()
+	alloc.Job = j
+	alloc.JobID = j.ID
+	alloc.EvalID = eval.ID
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{alloc}))
+
+	get := &structs.JobSpecificRequest{
+		JobID: j.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: j.Namespace,
+		},
+	}
+
+	// Lookup with a token without read-job should fail
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	get.AuthToken = invalidToken.SecretID
+	var resp structs.JobLineageResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Lineage", get, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "Permission denied")
+
+	get.AuthToken = root.SecretID
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Job.Lineage", get, &resp))
+	require.EqualValues(1002, resp.Index)
+	require.Len(resp.Lineage, 1)
+
+	version := resp.Lineage[0]
+	require.Equal(structs.JobLineageNodeTypeJobVersion, version.Type)
+	require.Equal("0", version.ID)
+	require.Len(version.Children, 1)
+	require.Equal(eval.ID, version.Children[0].ID)
+	require.Len(version.Children[0].Children, 1)
+	require.Equal(alloc.ID, version.Children[0].Children[0].ID)
+}
+
 func TestJobEndpoint_Deployments_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6065,6 +6749,51 @@ func TestJobEndpoint_Plan_WithDiff(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_PlanVerbose(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a node
+	node := mock.Node()
+	require.NoError(t, s1.State().UpsertNode(structs.MsgTypeTestSetup, 100, node))
+
+	// Create a plan request
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	planReq := &structs.JobPlanRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// A plan doesn't explain the placements
+	var planResp structs.JobPlanResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+	require.NotNil(t, planResp.Annotations)
+	require.Empty(t, planResp.Annotations.PlacementExplanations)
+
+	// A verbose plan does
+	var verboseResp structs.JobPlanResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.PlanVerbose", planReq, &verboseResp))
+	require.NotNil(t, verboseResp.Annotations)
+	require.Len(t, verboseResp.Annotations.PlacementExplanations, 1)
+
+	explanation := verboseResp.Annotations.PlacementExplanations[0]
+	require.Equal(t, "web", explanation.TaskGroup)
+	require.Equal(t, node.ID, explanation.NodeID)
+	require.Len(t, explanation.Nodes, 1)
+	require.Equal(t, node.Name, explanation.Nodes[0].NodeName)
+	require.False(t, explanation.Nodes[0].Rejected())
+}
+
 func TestJobEndpoint_Plan_NoDiff(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7130,6 +7859,62 @@ func TestJobEndpoint_Scale(t *testing.T) {
 	require.Equal(int64(originalCount), events[groupName][0].PreviousCount)
 }
 
+func TestJobEndpoint_Scale_EnforceIndex(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	originalCount := job.TaskGroups[0].Count
+	require.NoError(state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	scale := &structs.JobScaleRequest{
+		JobID: job.ID,
+		Target: map[string]string{
+			structs.ScalingTargetGroup: job.TaskGroups[0].Name,
+		},
+		Count:          helper.Int64ToPtr(int64(originalCount + 1)),
+		EnforceIndex:   true,
+		JobModifyIndex: 999,
+		WriteRequest: structs.WriteRequest{
+			Region:           "global",
+			Namespace:        job.Namespace,
+			IdempotencyToken: "foo",
+		},
+	}
+
+	// Scaling at a stale index fails
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), RegisterEnforceIndexErrPrefix)
+
+	// Scaling at the current index succeeds
+	scale.JobModifyIndex = 1000
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp))
+	require.NotEmpty(resp.EvalID)
+
+	// Retrying with the same token returns the original evaluation without
+	// scaling again
+	var retryResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &retryResp))
+	require.Equal(resp.EvalID, retryResp.EvalID)
+
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(originalCount+1, out.TaskGroups[0].Count)
+	require.Equal(uint64(1), out.Version)
+
+	events, _, err := state.ScalingEventsByJob(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Len(events[job.TaskGroups[0].Name], 1)
+}
+
 func TestJobEndpoint_Scale_DeploymentBlocking(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
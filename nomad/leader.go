@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"net"
 	"strings"
@@ -14,6 +15,8 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	snapshotagent "github.com/hashicorp/nomad/helper/snapshot/agent"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -48,6 +51,16 @@ var minJobRegisterAtomicEvalVersion = version.Must(version.NewVersion("0.12.1"))
 
 var minOneTimeAuthenticationTokenVersion = version.Must(version.NewVersion("1.1.0"))
 
+var minNodeIntroTokenVersion = version.Must(version.NewVersion("1.4.0"))
+
+var minJobTrashVersion = version.Must(version.NewVersion("1.4.0"))
+
+var minJobFreezeVersion = version.Must(version.NewVersion("1.4.0"))
+
+var minSecureVariablesTxnVersion = version.Must(version.NewVersion("1.4.0"))
+
+var minReconcileVersion = version.Must(version.NewVersion("1.4.0"))
+
 // monitorLeadership is used to monitor if we acquire or lose our role
 // as the leader in the Raft cluster. There is some work the leader is
 // expected to do, so we must react to changes
@@ -365,6 +378,17 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Periodically publish job status metrics
 	go s.publishJobStatusMetrics(stopCh)
 
+	// Periodically publish allocated resource metrics
+	go s.publishAllocatedResourceMetrics(stopCh)
+
+	// Periodically publish the scheduling pause state and expire it
+	go s.expireSchedulingPause(stopCh)
+
+	// Periodically save snapshots of the state if configured
+	if s.config.SnapshotAgentConfig != nil {
+		go s.runSnapshotAgent(stopCh)
+	}
+
 	// Setup the heartbeat timers. This is done both when starting up or when
 	// a leader fail over happens. Since the timers are maintained by the leader
 	// node, effectively this means all the timers are renewed at the time of failover.
@@ -767,6 +791,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer csiVolumeClaimGC.Stop()
 	oneTimeTokenGC := time.NewTicker(s.config.OneTimeTokenGCInterval)
 	defer oneTimeTokenGC.Stop()
+	nodeIntroTokenGC := time.NewTicker(s.config.NodeIntroTokenGCInterval)
+	defer nodeIntroTokenGC.Stop()
+	jobTrashGC := time.NewTicker(s.config.JobTrashGCInterval)
+	defer jobTrashGC.Stop()
 	rootKeyGC := time.NewTicker(s.config.RootKeyGCInterval)
 	defer rootKeyGC.Stop()
 	secureVariablesRekey := time.NewTicker(s.config.SecureVariablesRekeyInterval)
@@ -819,6 +847,23 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobOneTimeTokenGC, index))
 			}
+		case <-nodeIntroTokenGC.C:
+			if !ServersMeetMinimumVersion(s.Members(), minNodeIntroTokenVersion, false) {
+				continue
+			}
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobNodeIntroTokenGC, index))
+			}
+		case <-jobTrashGC.C:
+			if s.config.JobTrashWindow == 0 {
+				continue
+			}
+			if !ServersMeetMinimumVersion(s.Members(), minJobTrashVersion, false) {
+				continue
+			}
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobJobTrashGC, index))
+			}
 		case <-rootKeyGC.C:
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobRootKeyRotateOrGC, index))
@@ -1085,6 +1130,49 @@ func (s *Server) publishJobStatusMetrics(stopCh chan struct{}) {
 	}
 }
 
+// expireSchedulingPause periodically publishes whether scheduling is paused
+// and resumes scheduling once a pause with a duration has expired.
+func (s *Server) expireSchedulingPause(stopCh chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-timer.C:
+			timer.Reset(s.config.StatsCollectionInterval)
+			_, schedConfig, err := s.State().SchedulerConfig()
+			if err != nil {
+				s.logger.Error("failed to get scheduler config", "error", err)
+				continue
+			}
+
+			var paused float32
+			if schedConfig.SchedulingPaused(time.Now()) {
+				paused = 1
+			}
+			metrics.SetGauge([]string{"nomad", "scheduler", "paused"}, paused)
+
+			if paused == 1 || schedConfig == nil || !schedConfig.PauseScheduling {
+				continue
+			}
+
+			// The pause has expired, resume scheduling
+			newConfig := *schedConfig
+			newConfig.PauseScheduling = false
+			newConfig.PauseSchedulingDuration = 0
+			newConfig.PauseSchedulingUntil = 0
+			req := structs.SchedulerSetConfigRequest{Config: newConfig, CAS: true}
+			if _, _, err := s.raftApply(structs.SchedulerConfigRequestType, &req); err != nil {
+				s.logger.Error("failed to resume scheduling", "error", err)
+				continue
+			}
+			s.logger.Info("scheduling pause expired, scheduling resumed")
+		}
+	}
+}
+
 func (s *Server) iterateJobStatusMetrics(jobs *memdb.ResultIterator) {
 	var pending int64 // Sum of all jobs in 'pending' state
 	var running int64 // Sum of all jobs in 'running' state
@@ -1113,6 +1201,256 @@ func (s *Server) iterateJobStatusMetrics(jobs *memdb.ResultIterator) {
 	metrics.SetGauge([]string{"nomad", "job_status", "dead"}, float32(dead))
 }
 
+// runSnapshotAgent periodically saves snapshots of the state to the storage
+// of the snapshot agent until stopCh is closed.
+func (s *Server) runSnapshotAgent(stopCh chan struct{}) {
+	logger := s.logger.Named("snapshot_agent")
+	agent, err := snapshotagent.New(logger, s.config.SnapshotAgentConfig, func() (io.ReadCloser, error) {
+		snap, err := snapshot.New(logger, s.raft)
+		if err != nil {
+			return nil, err
+		}
+		return snap, nil
+	})
+	if err != nil {
+		logger.Error("failed to start snapshot agent", "error", err)
+		return
+	}
+
+	agent.Run(stopCh)
+}
+
+// publishAllocatedResourceMetrics publishes the resources allocated to
+// non-terminal allocations, aggregated by namespace, job, and node class.
+func (s *Server) publishAllocatedResourceMetrics(stopCh chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	// last is the usage published on the previous interval. It is reused
+	// while the allocations and nodes are unchanged, and is used to reset
+	// the gauges of label sets that are no longer reported.
+	var last *allocatedResourceMetrics
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-timer.C:
+			timer.Reset(s.config.StatsCollectionInterval)
+			state, err := s.State().Snapshot()
+			if err != nil {
+				s.logger.Error("failed to get state", "error", err)
+				continue
+			}
+
+			index, err := allocatedResourceIndex(state)
+			if err != nil {
+				s.logger.Error("failed to get allocated resources index", "error", err)
+				continue
+			}
+
+			usage := last
+			if last == nil || last.Index != index {
+				usage, err = allocatedResourceUsage(state)
+				if err != nil {
+					s.logger.Error("failed to get allocated resources", "error", err)
+					continue
+				}
+			}
+			usage.publish(last)
+			last = usage
+		}
+	}
+}
+
+// allocatedResources is the sum of the resources allocated to a set of
+// allocations.
+type allocatedResources struct {
+	CPU      int64
+	MemoryMB int64
+	DiskMB   int64
+}
+
+func (a *allocatedResources) add(r *structs.ComparableResources) {
+	a.CPU += r.Flattened.Cpu.CpuShares
+	a.MemoryMB += r.Flattened.Memory.MemoryMB
+	a.DiskMB += r.Shared.DiskMB
+}
+
+// nodeClassKey identifies the nodes of a class in a datacenter.
+type nodeClassKey struct {
+	Datacenter string
+	NodeClass  string
+}
+
+// allocatedResourceMetrics holds the allocated resources aggregated by
+// namespace, job, and node class.
+type allocatedResourceMetrics struct {
+	// Index is the highest index of the allocs and nodes tables the usage
+	// was aggregated at.
+	Index uint64
+
+	Namespaces  map[string]*allocatedResources
+	Jobs        map[structs.NamespacedID]*allocatedResources
+	NodeClasses map[nodeClassKey]*allocatedResources
+}
+
+// allocatedResourceUsage aggregates the resources of all non-terminal
+// allocations in the state. The allocations of dispatched and periodic jobs
+// are accounted to their parent job to bound the number of jobs reported.
+func allocatedResourceUsage(snap *state.StateSnapshot) (*allocatedResourceMetrics, error) {
+	index, err := allocatedResourceIndex(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &allocatedResourceMetrics{
+		Index:       index,
+		Namespaces:  make(map[string]*allocatedResources),
+		Jobs:        make(map[structs.NamespacedID]*allocatedResources),
+		NodeClasses: make(map[nodeClassKey]*allocatedResources),
+	}
+
+	iter, err := snap.Allocs(nil, state.SortDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*structs.Node)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		alloc := raw.(*structs.Allocation)
+		if alloc.TerminalStatus() {
+			continue
+		}
+		resources := alloc.ComparableResources()
+
+		ns, ok := usage.Namespaces[alloc.Namespace]
+		if !ok {
+			ns = &allocatedResources{}
+			usage.Namespaces[alloc.Namespace] = ns
+		}
+		ns.add(resources)
+
+		jobID := structs.NamespacedID{Namespace: alloc.Namespace, ID: alloc.JobID}
+		if alloc.Job != nil && alloc.Job.ParentID != "" {
+			jobID.ID = alloc.Job.ParentID
+		}
+		job, ok := usage.Jobs[jobID]
+		if !ok {
+			job = &allocatedResources{}
+			usage.Jobs[jobID] = job
+		}
+		job.add(resources)
+
+		node, ok := nodes[alloc.NodeID]
+		if !ok {
+			node, err = snap.NodeByID(nil, alloc.NodeID)
+			if err != nil {
+				return nil, err
+			}
+			nodes[alloc.NodeID] = node
+		}
+		if node == nil {
+			continue
+		}
+		key := nodeClassKey{Datacenter: node.Datacenter, NodeClass: node.NodeClass}
+		class, ok := usage.NodeClasses[key]
+		if !ok {
+			class = &allocatedResources{}
+			usage.NodeClasses[key] = class
+		}
+		class.add(resources)
+	}
+
+	return usage, nil
+}
+
+// allocatedResourceIndex returns the highest index of the tables the
+// allocated resources are aggregated from, so that the aggregation can be
+// skipped while they are unchanged.
+func allocatedResourceIndex(snap *state.StateSnapshot) (uint64, error) {
+	allocIndex, err := snap.Index("allocs")
+	if err != nil {
+		return 0, err
+	}
+	nodeIndex, err := snap.Index("nodes")
+	if err != nil {
+		return 0, err
+	}
+	if nodeIndex > allocIndex {
+		return nodeIndex, nil
+	}
+	return allocIndex, nil
+}
+
+// stale returns the label sets of prev that are missing from m, with no
+// resources allocated. Publishing them resets the gauges of namespaces, jobs,
+// and node classes that no longer have allocations.
+func (m *allocatedResourceMetrics) stale(prev *allocatedResourceMetrics) *allocatedResourceMetrics {
+	stale := &allocatedResourceMetrics{
+		Namespaces:  make(map[string]*allocatedResources),
+		Jobs:        make(map[structs.NamespacedID]*allocatedResources),
+		NodeClasses: make(map[nodeClassKey]*allocatedResources),
+	}
+	if prev == nil {
+		return stale
+	}
+
+	for ns := range prev.Namespaces {
+		if _, ok := m.Namespaces[ns]; !ok {
+			stale.Namespaces[ns] = &allocatedResources{}
+		}
+	}
+	for id := range prev.Jobs {
+		if _, ok := m.Jobs[id]; !ok {
+			stale.Jobs[id] = &allocatedResources{}
+		}
+	}
+	for key := range prev.NodeClasses {
+		if _, ok := m.NodeClasses[key]; !ok {
+			stale.NodeClasses[key] = &allocatedResources{}
+		}
+	}
+	return stale
+}
+
+// publish emits the allocated resources as gauges, and resets the gauges of
+// the label sets published by prev that are no longer present.
+func (m *allocatedResourceMetrics) publish(prev *allocatedResourceMetrics) {
+	m.stale(prev).emit()
+	m.emit()
+}
+
+// emit sets the gauges of the allocated resources.
+func (m *allocatedResourceMetrics) emit() {
+	emit := func(prefix string, r *allocatedResources, labels []metrics.Label) {
+		metrics.SetGaugeWithLabels([]string{"nomad", prefix, "allocated", "cpu"},
+			float32(r.CPU), labels)
+		metrics.SetGaugeWithLabels([]string{"nomad", prefix, "allocated", "memory"},
+			float32(r.MemoryMB), labels)
+		metrics.SetGaugeWithLabels([]string{"nomad", prefix, "allocated", "disk"},
+			float32(r.DiskMB), labels)
+	}
+
+	for ns, r := range m.Namespaces {
+		emit("namespace", r, []metrics.Label{
+			{Name: "namespace", Value: ns},
+		})
+	}
+	for id, r := range m.Jobs {
+		emit("job", r, []metrics.Label{
+			{Name: "namespace", Value: id.Namespace},
+			{Name: "job", Value: id.ID},
+		})
+	}
+	for key, r := range m.NodeClasses {
+		emit("node_class", r, []metrics.Label{
+			{Name: "datacenter", Value: key.Datacenter},
+			{Name: "node_class", Value: key.NodeClass},
+		})
+	}
+}
+
 // revokeLeadership is invoked once we step down as leader.
 // This is used to cleanup any state that may be specific to a leader.
 func (s *Server) revokeLeadership() error {
@@ -1796,8 +2134,10 @@ func (s *Server) handleEvalBrokerStateChange(schedConfig *structs.SchedulerConfi
 	switch schedConfig {
 	case nil:
 		enableBrokers = !s.config.DefaultSchedulerConfig.PauseEvalBroker
+		s.evalBroker.SetFairness(&s.config.DefaultSchedulerConfig.EvalFairness)
 	default:
 		enableBrokers = !schedConfig.PauseEvalBroker
+		s.evalBroker.SetFairness(&schedConfig.EvalFairness)
 	}
 
 	// If the evalBroker status is changing, set the new state.
@@ -1750,3 +1750,132 @@ func TestServer_handleEvalBrokerStateChange(t *testing.T) {
 		})
 	}
 }
+
+func TestLeader_expireSchedulingPause(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.StatsCollectionInterval = 50 * time.Millisecond
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Pause scheduling with an expiry in the past.
+	_, schedConfig, err := s1.State().SchedulerConfig()
+	require.NoError(t, err)
+	newConfig := *schedConfig
+	newConfig.PauseScheduling = true
+	newConfig.PauseSchedulingDuration = time.Second
+	newConfig.PauseSchedulingUntil = time.Now().Add(-time.Second).UnixNano()
+	req := structs.SchedulerSetConfigRequest{Config: newConfig}
+	_, _, err = s1.raftApply(structs.SchedulerConfigRequestType, &req)
+	require.NoError(t, err)
+
+	// The leader resumes scheduling.
+	testutil.WaitForResult(func() (bool, error) {
+		_, schedConfig, err := s1.State().SchedulerConfig()
+		if err != nil {
+			return false, err
+		}
+		if schedConfig.PauseScheduling {
+			return false, fmt.Errorf("scheduling still paused")
+		}
+		return schedConfig.PauseSchedulingUntil == 0, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestLeader_AllocatedResourceUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+
+	node1 := mock.Node()
+	node1.NodeClass = "compute"
+	node2 := mock.Node()
+	node2.Datacenter = "dc2"
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1000, node1))
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1001, node2))
+
+	job := mock.Job()
+	child := mock.Job()
+	child.ID = job.ID + "/dispatch-1234"
+	child.ParentID = job.ID
+	other := mock.Job()
+	other.Namespace = "other"
+
+	newAlloc := func(job *structs.Job, node *structs.Node) *structs.Allocation {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.Namespace = job.Namespace
+		alloc.NodeID = node.ID
+		return alloc
+	}
+
+	a1 := newAlloc(job, node1)
+	a2 := newAlloc(child, node1)
+	a3 := newAlloc(other, node2)
+
+	// Terminal allocations are not accounted
+	a4 := newAlloc(job, node2)
+	a4.DesiredStatus = structs.AllocDesiredStatusStop
+	a4.ClientStatus = structs.AllocClientStatusComplete
+
+	require.NoError(t, store.UpsertNamespaces(999, []*structs.Namespace{{Name: "other"}}))
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1002,
+		[]*structs.Allocation{a1, a2, a3, a4}))
+
+	snap, err := store.Snapshot()
+	require.NoError(t, err)
+	usage, err := allocatedResourceUsage(snap)
+	require.NoError(t, err)
+
+	// mock allocs have 500 CPU, 256MB of memory, and 150MB of disk
+	one := &allocatedResources{CPU: 500, MemoryMB: 256, DiskMB: 150}
+	two := &allocatedResources{CPU: 1000, MemoryMB: 512, DiskMB: 300}
+
+	require.Equal(t, map[string]*allocatedResources{
+		structs.DefaultNamespace: two,
+		"other":                  one,
+	}, usage.Namespaces)
+
+	require.Equal(t, map[structs.NamespacedID]*allocatedResources{
+		{Namespace: structs.DefaultNamespace, ID: job.ID}: two,
+		{Namespace: "other", ID: other.ID}:                one,
+	}, usage.Jobs)
+
+	require.Equal(t, map[nodeClassKey]*allocatedResources{
+		{Datacenter: "dc1", NodeClass: "compute"}:       two,
+		{Datacenter: "dc2", NodeClass: node2.NodeClass}: one,
+	}, usage.NodeClasses)
+	require.Equal(t, uint64(1002), usage.Index)
+	require.Empty(t, usage.stale(nil).Namespaces)
+
+	// Stopping the only allocation of a namespace, job, and node class
+	// resets their gauges on the next publish
+	a3 = a3.Copy()
+	a3.DesiredStatus = structs.AllocDesiredStatusStop
+	a3.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1003,
+		[]*structs.Allocation{a3}))
+
+	snap, err = store.Snapshot()
+	require.NoError(t, err)
+	next, err := allocatedResourceUsage(snap)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1003), next.Index)
+
+	stale := next.stale(usage)
+	require.Equal(t, map[string]*allocatedResources{
+		"other": {},
+	}, stale.Namespaces)
+	require.Equal(t, map[structs.NamespacedID]*allocatedResources{
+		{Namespace: "other", ID: other.ID}: {},
+	}, stale.Jobs)
+	require.Equal(t, map[nodeClassKey]*allocatedResources{
+		{Datacenter: "dc2", NodeClass: node2.NodeClass}: {},
+	}, stale.NodeClasses)
+}
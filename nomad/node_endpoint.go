@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
@@ -153,6 +154,17 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 		}
 	}
 
+	// Nodes registering for the first time must consume a node introduction
+	// token when node introduction is required. The secret ID of the node
+	// is then established and used to authenticate its heartbeats.
+	if originalNode == nil && n.srv.config.RequireNodeIntroToken {
+		if err := n.consumeIntroToken(snap, args.IntroToken); err != nil {
+			n.logger.Warn("node introduction failed", "node_id", args.Node.ID, "error", err)
+			return err
+		}
+	}
+	args.IntroToken = ""
+
 	// We have a valid node connection, so add the mapping to cache the
 	// connection and allow the server to send RPCs to the client. We only cache
 	// the connection if it is not being forwarded from another server.
@@ -457,6 +469,13 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 		return fmt.Errorf("node not found")
 	}
 
+	// When node introduction is required, heartbeats must be authenticated
+	// with the secret ID the node registered with. Updates made by the
+	// server itself, such as when a heartbeat is missed, have no RPC context.
+	if n.srv.config.RequireNodeIntroToken && n.ctx != nil && args.AuthToken != node.SecretID {
+		return structs.ErrPermissionDenied
+	}
+
 	// We have a valid node connection, so add the mapping to cache the
 	// connection and allow the server to send RPCs to the client. We only cache
 	// the connection if it is not being forwarded from another server.
@@ -465,9 +484,6 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 		n.srv.addNodeConn(n.ctx)
 	}
 
-	// XXX: Could use the SecretID here but have to update the heartbeat system
-	// to track SecretIDs.
-
 	// Update the timestamp of when the node status was updated
 	args.UpdatedAt = time.Now().Unix()
 
@@ -482,7 +498,7 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 				SetMessage(NodeHeartbeatEventReregistered)
 		}
 
-		_, index, err = n.srv.raftApply(structs.NodeUpdateStatusRequestType, args)
+		index, err = n.srv.batchUpdateNodeStatus(args)
 		if err != nil {
 			n.logger.Error("status update failed", "error", err)
 			return err
@@ -572,6 +588,135 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 	return nil
 }
 
+// consumeIntroToken validates the node introduction token with the given
+// secret ID and consumes it, so it can't be used to register another node.
+func (n *Node) consumeIntroToken(snap *state.StateSnapshot, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("missing node introduction token: %w", structs.ErrPermissionDenied)
+	}
+	if !helper.IsUUID(secret) {
+		return fmt.Errorf("invalid node introduction token: %w", structs.ErrPermissionDenied)
+	}
+
+	token, err := snap.NodeIntroTokenBySecret(nil, secret)
+	if err != nil {
+		return err
+	}
+	if token == nil || token.ExpiresAt.Before(time.Now()) {
+		// expired tokens are left for the garbage collection
+		return fmt.Errorf("invalid node introduction token: %w", structs.ErrPermissionDenied)
+	}
+
+	// Consuming the token fails if it was consumed by a concurrent
+	// registration since the snapshot was taken
+	_, _, err = n.srv.raftApply(structs.NodeIntroTokenConsumeRequestType,
+		&structs.NodeIntroTokenConsumeRequest{AccessorID: token.AccessorID})
+	if err != nil {
+		return fmt.Errorf("failed to consume node introduction token: %v", err)
+	}
+	return nil
+}
+
+// CreateIntroToken creates a single-use node introduction token, which
+// allows a client to register for the first time when node introduction is
+// required.
+func (n *Node) CreateIntroToken(args *structs.NodeIntroTokenCreateRequest, reply *structs.NodeIntroTokenCreateResponse) error {
+	if done, err := n.srv.forward("Node.CreateIntroToken", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "create_intro_token"}, time.Now())
+
+	if !ServersMeetMinimumVersion(n.srv.Members(), minNodeIntroTokenVersion, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use node introduction tokens", minNodeIntroTokenVersion)
+	}
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	ttl := args.TTL
+	if ttl < 0 {
+		return fmt.Errorf("node introduction token TTL must not be negative")
+	}
+	if ttl == 0 {
+		ttl = structs.DefaultNodeIntroTokenTTL
+	}
+
+	token := &structs.NodeIntroToken{
+		SecretID:   uuid.Generate(),
+		AccessorID: uuid.Generate(),
+		ExpiresAt:  time.Now().Add(ttl),
+	}
+
+	_, index, err := n.srv.raftApply(structs.NodeIntroTokenUpsertRequestType, token)
+	if err != nil {
+		n.logger.Error("node introduction token creation failed", "error", err)
+		return err
+	}
+
+	token.CreateIndex = index
+	token.ModifyIndex = index
+	reply.Token = token
+	reply.Index = index
+	return nil
+}
+
+// ExpireIntroTokens removes all the expired node introduction tokens from the
+// state store. It is called only by garbage collection.
+func (n *Node) ExpireIntroTokens(args *structs.NodeIntroTokenExpireRequest, reply *structs.GenericResponse) error {
+	if done, err := n.srv.forward("Node.ExpireIntroTokens", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "expire_intro_tokens"}, time.Now())
+
+	if !ServersMeetMinimumVersion(n.srv.Members(), minNodeIntroTokenVersion, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use node introduction tokens", minNodeIntroTokenVersion)
+	}
+
+	// Check management level permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	args.Timestamp = time.Now() // use the leader's timestamp
+
+	// Avoid writing to raft on every GC interval when no token expired
+	expired, err := n.hasExpiredIntroTokens(args.Timestamp)
+	if err != nil {
+		return err
+	}
+	if !expired {
+		return nil
+	}
+
+	_, index, err := n.srv.raftApply(structs.NodeIntroTokenExpireRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// hasExpiredIntroTokens returns whether any node introduction token expired
+// before the given timestamp.
+func (n *Node) hasExpiredIntroTokens(timestamp time.Time) (bool, error) {
+	iter, err := n.srv.State().NodeIntroTokens(nil)
+	if err != nil {
+		return false, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.NodeIntroToken).ExpiresAt.Before(timestamp) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // nodeStatusTransitionRequiresEval is a helper that takes a nodes new and old status and
 // returns whether it has transitioned to ready.
 func nodeStatusTransitionRequiresEval(newStatus, oldStatus string) bool {
@@ -833,28 +978,14 @@ func (n *Node) GetNode(args *structs.NodeSpecificRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client", "get_node"}, time.Now())
 
-	// Check node read permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
-		// If ResolveToken had an unexpected error return that
-		if err != structs.ErrTokenNotFound {
-			return err
-		}
-
-		// Attempt to lookup AuthToken as a Node.SecretID since nodes
-		// call this endpoint and don't have an ACL token.
-		node, stateErr := n.srv.fsm.State().NodeBySecretID(nil, args.AuthToken)
-		if stateErr != nil {
-			// Return the original ResolveToken error with this err
-			var merr multierror.Error
-			merr.Errors = append(merr.Errors, err, stateErr)
-			return merr.ErrorOrNil()
-		}
-
-		// Not a node or a valid ACL token
-		if node == nil {
-			return structs.ErrTokenNotFound
-		}
-	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+	// Check node read permissions. Nodes call this endpoint with their
+	// secret ID and may only read themselves and the nodes they migrate
+	// allocation data from.
+	aclObj, node, err := n.srv.resolveACLOrNode(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if aclObj != nil && !aclObj.AllowNodeRead() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -868,6 +999,17 @@ func (n *Node) GetNode(args *structs.NodeSpecificRequest,
 				return fmt.Errorf("missing node ID")
 			}
 
+			if node != nil {
+				ok, err := nodeCanReadNode(ws, state, node, args.NodeID)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					n.srv.auditCrossNodeAccess(node, "Node.GetNode", "node", args.NodeID)
+					return structs.ErrPermissionDenied
+				}
+			}
+
 			// Look for the node
 			out, err := state.NodeByID(ws, args.NodeID)
 			if err != nil {
@@ -1278,6 +1420,73 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 	return nil
 }
 
+// UpdateAllocDelta is used to update the client status of allocations from the
+// changes since their status the servers last acknowledged. The deltas are
+// merged into the allocations before being committed like the updates of
+// UpdateAlloc. Allocations whose delta doesn't apply to their current status
+// are returned for the client to send them in full.
+func (n *Node) UpdateAllocDelta(args *structs.AllocDeltaUpdateRequest, reply *structs.AllocDeltaUpdateResponse) error {
+	// Ensure the connection was initiated by another client if TLS is used.
+	err := validateTLSCertificateLevel(n.srv, n.ctx, tlsCertificateLevelClient)
+	if err != nil {
+		return err
+	}
+
+	if done, err := n.srv.forward("Node.UpdateAllocDelta", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "update_alloc_delta"}, time.Now())
+
+	if len(args.Alloc) == 0 && len(args.Deltas) == 0 {
+		return fmt.Errorf("must update at least one allocation")
+	}
+
+	snap, err := n.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	allocs := args.Alloc
+	for _, delta := range args.Deltas {
+		existing, err := snap.AllocByID(nil, delta.ID)
+		if err != nil {
+			return err
+		}
+
+		// Updates of unknown allocations are ignored, as by UpdateAlloc
+		if existing == nil {
+			continue
+		}
+
+		alloc, ok := delta.Apply(existing)
+		if !ok {
+			reply.Mismatched = append(reply.Mismatched, delta.ID)
+			continue
+		}
+		allocs = append(allocs, alloc)
+	}
+
+	metrics.IncrCounter([]string{"nomad", "client", "update_alloc_delta", "applied"}, float32(len(args.Deltas)-len(reply.Mismatched)))
+	metrics.IncrCounter([]string{"nomad", "client", "update_alloc_delta", "mismatched"}, float32(len(reply.Mismatched)))
+
+	if len(allocs) == 0 {
+		reply.Index, err = snap.LatestIndex()
+		return err
+	}
+
+	update := &structs.AllocUpdateRequest{
+		Alloc:        allocs,
+		WriteRequest: args.WriteRequest,
+	}
+	var resp structs.GenericResponse
+	if err := n.UpdateAlloc(update, &resp); err != nil {
+		return err
+	}
+
+	reply.Index = resp.Index
+	return nil
+}
+
 // batchUpdate is used to update all the allocations
 func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Allocation, evals []*structs.Evaluation) {
 	var mErr multierror.Error
@@ -1635,6 +1844,7 @@ func (n *Node) DeriveVaultToken(args *structs.DeriveVaultTokenRequest, reply *st
 		return nil
 	}
 	if alloc.NodeID != args.NodeID {
+		n.srv.auditCrossNodeAccess(node, "Node.DeriveVaultToken", "allocation", args.AllocID)
 		setError(fmt.Errorf("Allocation %q not running on Node %q", args.AllocID, args.NodeID), false)
 		return nil
 	}
@@ -1852,6 +2062,7 @@ func (n *Node) DeriveSIToken(args *structs.DeriveSITokenRequest, reply *structs.
 		return nil
 	}
 	if alloc.NodeID != args.NodeID {
+		n.srv.auditCrossNodeAccess(node, "Node.DeriveSIToken", "allocation", args.AllocID)
 		setError(fmt.Errorf("Allocation %q not running on node %q", args.AllocID, args.NodeID), false)
 		return nil
 	}
@@ -209,6 +209,211 @@ func TestClientEndpoint_Register_SecretMismatch(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_Register_IntroToken(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.RequireNodeIntroToken = true
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Registering without a token is rejected
+	node := mock.Node()
+	req := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp)
+	require.Error(t, err)
+	require.True(t, structs.IsErrPermissionDenied(err), err.Error())
+
+	// Registering with an unknown or expired token is rejected
+	req.IntroToken = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp)
+	require.Error(t, err)
+	require.True(t, structs.IsErrPermissionDenied(err), err.Error())
+
+	expired := &structs.NodeIntroToken{
+		SecretID:   uuid.Generate(),
+		AccessorID: uuid.Generate(),
+		ExpiresAt:  time.Now().Add(-time.Minute),
+	}
+	require.NoError(t, s1.fsm.State().UpsertNodeIntroToken(structs.MsgTypeTestSetup, 100, expired))
+	req.IntroToken = expired.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp)
+	require.Error(t, err)
+	require.True(t, structs.IsErrPermissionDenied(err), err.Error())
+
+	// Registering with a valid token consumes it
+	createReq := &structs.NodeIntroTokenCreateRequest{
+		TTL:          time.Hour,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var createResp structs.NodeIntroTokenCreateResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.CreateIntroToken", createReq, &createResp))
+	token := createResp.Token
+	require.NotNil(t, token)
+	require.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, time.Minute)
+
+	req.IntroToken = token.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp))
+
+	out, err := s1.fsm.State().NodeIntroTokenBySecret(nil, token.SecretID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// The registered node can register again without a token
+	req.IntroToken = ""
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp))
+
+	// The token can't be used to register another node
+	other := &structs.NodeRegisterRequest{
+		Node:         mock.Node(),
+		IntroToken:   token.SecretID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Node.Register", other, &resp)
+	require.Error(t, err)
+	require.True(t, structs.IsErrPermissionDenied(err), err.Error())
+
+	// Heartbeats must be authenticated with the node secret ID
+	update := &structs.NodeUpdateStatusRequest{
+		NodeID:       node.ID,
+		Status:       structs.NodeStatusReady,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Node.UpdateStatus", update, &resp)
+	require.Error(t, err)
+	require.True(t, structs.IsErrPermissionDenied(err), err.Error())
+
+	update.AuthToken = node.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateStatus", update, &resp))
+}
+
+func TestClientEndpoint_CreateIntroToken_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	req := &structs.NodeIntroTokenCreateRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeIntroTokenCreateResponse
+
+	// Without a token
+	err := msgpackrpc.CallWithCodec(codec, "Node.CreateIntroToken", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// With a token without node write
+	invalidToken := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1001, "test-invalid", mock.NodePolicy(acl.PolicyRead))
+	req.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Node.CreateIntroToken", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// With a token with node write, the default TTL is used
+	validToken := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1003, "test-valid", mock.NodePolicy(acl.PolicyWrite))
+	req.AuthToken = validToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.CreateIntroToken", req, &resp))
+	require.NotNil(t, resp.Token)
+	require.WithinDuration(t, time.Now().Add(structs.DefaultNodeIntroTokenTTL), resp.Token.ExpiresAt, time.Minute)
+
+	// With a management token
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.CreateIntroToken", req, &resp))
+}
+
+func TestClientEndpoint_ExpireIntroTokens(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	valid := &structs.NodeIntroToken{
+		SecretID:   uuid.Generate(),
+		AccessorID: uuid.Generate(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	require.NoError(t, state.UpsertNodeIntroToken(structs.MsgTypeTestSetup, 1000, valid))
+
+	// Nothing is written to raft when no token expired
+	req := &structs.NodeIntroTokenExpireRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.ExpireIntroTokens", req, &resp))
+	require.Zero(t, resp.Index)
+
+	expired := &structs.NodeIntroToken{
+		SecretID:   uuid.Generate(),
+		AccessorID: uuid.Generate(),
+		ExpiresAt:  time.Now().Add(-time.Minute),
+	}
+	require.NoError(t, state.UpsertNodeIntroToken(structs.MsgTypeTestSetup, 1001, expired))
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.ExpireIntroTokens", req, &resp))
+	require.NotZero(t, resp.Index)
+
+	out, err := state.NodeIntroTokenBySecret(nil, expired.SecretID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = state.NodeIntroTokenBySecret(nil, valid.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
+func TestClientEndpoint_IntroToken_OldServers(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+	})
+	defer cleanupS1()
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+
+		// simulate a server that can't apply node introduction tokens
+		c.Build = "1.3.3"
+	})
+	defer cleanupS2()
+
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+	codec := rpcClient(t, s1)
+
+	createReq := &structs.NodeIntroTokenCreateRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var createResp structs.NodeIntroTokenCreateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.CreateIntroToken", createReq, &createResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "All servers should be running version")
+
+	// The garbage collection doesn't fail on a mixed version cluster
+	leader := s1
+	if ok, _ := s1.getLeader(); !ok {
+		leader = s2
+	}
+	snap, err := leader.fsm.State().Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(leader, snap)
+	index, err := leader.fsm.State().LatestIndex()
+	require.NoError(t, err)
+	gc := leader.coreJobEval(structs.CoreJobNodeIntroTokenGC, index)
+	require.NoError(t, core.Process(gc))
+}
+
 // Test the deprecated single node deregistration path
 func TestClientEndpoint_DeregisterOne(t *testing.T) {
 	ci.Parallel(t)
@@ -1649,6 +1854,31 @@ func TestClientEndpoint_GetNode_ACL(t *testing.T) {
 		assert.Equal(node.ID, resp.Node.ID)
 	}
 
+	// Try with the Node.SecretID of another node
+	other := mock.Node()
+	assert.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 1005, other), "UpsertNode")
+	req.AuthToken = other.SecretID
+	{
+		var resp structs.SingleNodeResponse
+		err := msgpackrpc.CallWithCodec(codec, "Node.GetNode", req, &resp)
+		assert.NotNil(err, "RPC")
+		assert.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Once the other node runs an allocation replacing an allocation of the
+	// node, it may read the node to migrate the allocation data
+	prev := mock.Alloc()
+	prev.NodeID = node.ID
+	next := mock.Alloc()
+	next.NodeID = other.ID
+	next.PreviousAllocation = prev.ID
+	assert.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 1006, []*structs.Allocation{prev, next}))
+	{
+		var resp structs.SingleNodeResponse
+		assert.Nil(msgpackrpc.CallWithCodec(codec, "Node.GetNode", req, &resp), "RPC")
+		assert.Equal(node.ID, resp.Node.ID)
+	}
+
 	// Try with a invalid token
 	req.AuthToken = invalidToken.SecretID
 	{
@@ -2527,6 +2757,75 @@ func TestClientEndpoint_UpdateAlloc(t *testing.T) {
 
 }
 
+func TestClientEndpoint_UpdateAllocDelta(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	// Inject an allocation with the status its client last sent
+	state := s1.fsm.State()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	alloc.TaskStates = map[string]*structs.TaskState{
+		"web": {
+			State:  structs.TaskStateRunning,
+			Events: []*structs.TaskEvent{structs.NewTaskEvent(structs.TaskStarted)},
+		},
+	}
+	require.NoError(t, state.UpsertJobSummary(99, mock.JobSummary(alloc.JobID)))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 100, []*structs.Allocation{alloc}))
+
+	// The task restarted since
+	base := alloc.Copy()
+	update := alloc.Copy()
+	restart := structs.NewTaskEvent(structs.TaskRestarting)
+	restart.Time = base.TaskStates["web"].Events[0].Time + 1
+	update.TaskStates["web"].Restarts = 1
+	update.TaskStates["web"].Events = append(update.TaskStates["web"].Events, restart)
+
+	delta := structs.NewAllocDelta(base, update)
+	require.NotNil(t, delta)
+	require.Len(t, delta.TaskStates["web"].State.Events, 1)
+
+	req := &structs.AllocDeltaUpdateRequest{
+		Deltas:       []*structs.AllocDelta{delta},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var deltaResp structs.AllocDeltaUpdateResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateAllocDelta", req, &deltaResp))
+	require.Empty(t, deltaResp.Mismatched)
+	require.NotZero(t, deltaResp.Index)
+
+	// The delta is merged into the task state
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, out.TaskStates["web"].Restarts)
+	require.Len(t, out.TaskStates["web"].Events, 2)
+	require.Equal(t, structs.TaskRestarting, out.TaskStates["web"].Events[1].Type)
+
+	// A delta from a status the servers don't have is mismatched
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateAllocDelta", req, &deltaResp))
+	require.Equal(t, []string{alloc.ID}, deltaResp.Mismatched)
+
+	out, err = state.AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.Len(t, out.TaskStates["web"].Events, 2)
+}
+
 func TestClientEndpoint_BatchUpdate(t *testing.T) {
 	ci.Parallel(t)
 
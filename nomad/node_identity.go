@@ -0,0 +1,98 @@
+package nomad
+
+import (
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// resolveACLOrNode resolves the auth token of a request made either by a user
+// or by a client node. It returns the ACL object of the token or, if the token
+// is the secret ID of a node, the identity of that node. Requests made with a
+// node identity must only be allowed to access the data of that node.
+//
+// Both return values are nil if ACLs are disabled.
+func (s *Server) resolveACLOrNode(secretID string) (*acl.ACL, *structs.Node, error) {
+	aclObj, err := s.ResolveToken(secretID)
+	if err == nil {
+		return aclObj, nil, nil
+	}
+
+	// If ResolveToken had an unexpected error return that
+	if err != structs.ErrTokenNotFound {
+		return nil, nil, err
+	}
+
+	// Attempt to lookup AuthToken as a Node.SecretID since nodes call the
+	// endpoints and don't have an ACL token.
+	node, stateErr := s.fsm.State().NodeBySecretID(nil, secretID)
+	if stateErr != nil {
+		// Return the original ResolveToken error with this err
+		var merr multierror.Error
+		merr.Errors = append(merr.Errors, err, stateErr)
+		return nil, nil, merr.ErrorOrNil()
+	}
+
+	// Not a node or a valid ACL token
+	if node == nil {
+		return nil, nil, structs.ErrTokenNotFound
+	}
+	return nil, node, nil
+}
+
+// nodeCanReadAlloc returns whether the node may read the allocation: either
+// the allocation is running on the node, or it was replaced by an allocation
+// running on the node, which may need to migrate its data.
+func nodeCanReadAlloc(ws memdb.WatchSet, state *state.StateStore, node *structs.Node, alloc *structs.Allocation) (bool, error) {
+	if alloc.NodeID == node.ID {
+		return true, nil
+	}
+	if alloc.NextAllocation == "" {
+		return false, nil
+	}
+
+	next, err := state.AllocByID(ws, alloc.NextAllocation)
+	if err != nil {
+		return false, err
+	}
+	return next != nil && next.NodeID == node.ID, nil
+}
+
+// nodeCanReadNode returns whether the node may read the other node: either
+// it is the node itself, or the other node runs an allocation replaced by an
+// allocation running on the node, whose data may need to be migrated.
+func nodeCanReadNode(ws memdb.WatchSet, state *state.StateStore, node *structs.Node, otherID string) (bool, error) {
+	if otherID == node.ID {
+		return true, nil
+	}
+
+	allocs, err := state.AllocsByNode(ws, node.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, alloc := range allocs {
+		if alloc.PreviousAllocation == "" || alloc.TerminalStatus() {
+			continue
+		}
+		prev, err := state.AllocByID(ws, alloc.PreviousAllocation)
+		if err != nil {
+			return false, err
+		}
+		if prev != nil && prev.NodeID == otherID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// auditCrossNodeAccess records an attempt of a node to access data of another
+// node, which is denied.
+func (s *Server) auditCrossNodeAccess(node *structs.Node, method, objectType, objectID string) {
+	s.logger.Warn("denied node access to data of another node",
+		"node_id", node.ID, "method", method, "object_type", objectType, "object_id", objectID)
+	metrics.IncrCounterWithLabels([]string{"nomad", "client", "cross_node_access"}, 1,
+		[]metrics.Label{{Name: "method", Value: method}})
+}
@@ -320,6 +320,14 @@ func (op *Operator) SchedulerSetConfiguration(args *structs.SchedulerSetConfigRe
 		return fmt.Errorf("All servers should be running version %v to update scheduler config", minSchedulerConfigVersion)
 	}
 
+	// Compute the expiry of a scheduling pause on the leader, so that it is
+	// not affected by the clock of the requester.
+	if !args.Config.PauseScheduling {
+		args.Config.PauseSchedulingUntil = 0
+	} else if args.Config.PauseSchedulingUntil == 0 && args.Config.PauseSchedulingDuration > 0 {
+		args.Config.PauseSchedulingUntil = time.Now().Add(args.Config.PauseSchedulingDuration).UnixNano()
+	}
+
 	// Apply the update
 	resp, index, err := op.srv.raftApply(structs.SchedulerConfigRequestType, args)
 	if err != nil {
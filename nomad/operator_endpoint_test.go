@@ -440,6 +440,83 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 	require.False(t, s1.blockedEvals.Enabled())
 }
 
+func TestOperator_SchedulerSetConfiguration_PauseScheduling(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.Build = "0.9.0+unittest"
+	})
+	defer cleanupS1()
+	rpcCodec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Pause scheduling for an hour.
+	arg := structs.SchedulerSetConfigRequest{
+		Config: structs.SchedulerConfiguration{
+			PauseScheduling:         true,
+			PauseSchedulingDuration: time.Hour,
+		},
+	}
+	arg.Region = s1.config.Region
+
+	var setResponse structs.SchedulerSetConfigurationResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(rpcCodec, "Operator.SchedulerSetConfiguration", &arg, &setResponse))
+
+	_, schedConfig, err := s1.fsm.State().SchedulerConfig()
+	require.NoError(t, err)
+	require.True(t, schedConfig.PauseScheduling)
+	until := time.Unix(0, schedConfig.PauseSchedulingUntil)
+	require.WithinDuration(t, time.Now().Add(time.Hour), until, time.Minute)
+	require.True(t, schedConfig.SchedulingPaused(time.Now()))
+	require.False(t, schedConfig.SchedulingPaused(until))
+
+	// Resuming scheduling clears the expiry.
+	arg.Config.PauseScheduling = false
+	arg.Config.PauseSchedulingUntil = schedConfig.PauseSchedulingUntil
+	require.NoError(t, msgpackrpc.CallWithCodec(rpcCodec, "Operator.SchedulerSetConfiguration", &arg, &setResponse))
+
+	_, schedConfig, err = s1.fsm.State().SchedulerConfig()
+	require.NoError(t, err)
+	require.False(t, schedConfig.PauseScheduling)
+	require.Zero(t, schedConfig.PauseSchedulingUntil)
+}
+
+func TestOperator_SchedulerSetConfiguration_EvalFairness(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.Build = "0.9.0+unittest"
+	})
+	defer cleanupS1()
+	rpcCodec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	arg := structs.SchedulerSetConfigRequest{
+		Config: structs.SchedulerConfiguration{
+			EvalFairness: structs.EvalFairnessConfig{
+				Enabled:         true,
+				NamespaceShares: map[string]int{"prod": 0},
+			},
+		},
+	}
+	arg.Region = s1.config.Region
+	require.ErrorContains(t, arg.Config.Validate(), "must be at least 1")
+
+	// Enabling fairness configures the eval broker of the leader.
+	arg.Config.EvalFairness.NamespaceShares["prod"] = 4
+	require.NoError(t, arg.Config.Validate())
+
+	var setResponse structs.SchedulerSetConfigurationResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(rpcCodec, "Operator.SchedulerSetConfiguration", &arg, &setResponse))
+
+	s1.evalBroker.l.RLock()
+	fairness := s1.evalBroker.fairness
+	s1.evalBroker.l.RUnlock()
+	require.NotNil(t, fairness)
+	require.Equal(t, 4, fairness.Share("prod"))
+	require.Equal(t, 1, fairness.Share("default"))
+}
+
 func TestOperator_SchedulerGetConfiguration_ACL(t *testing.T) {
 	ci.Parallel(t)
 
@@ -46,11 +46,13 @@ type JobEvalDispatcher interface {
 // DispatchJob creates an evaluation for the passed job and commits both the
 // evaluation and the job to the raft log. It returns the eval.
 func (s *Server) DispatchJob(job *structs.Job) (*structs.Evaluation, error) {
+	job.SetSubmitTime()
 	now := time.Now().UTC().UnixNano()
 	eval := &structs.Evaluation{
 		ID:          uuid.Generate(),
 		Namespace:   job.Namespace,
 		Priority:    job.Priority,
+		Deadline:    job.DeadlineTime(),
 		Type:        job.Type,
 		TriggeredBy: structs.EvalTriggerPeriodicJob,
 		JobID:       job.ID,
@@ -60,7 +62,6 @@ func (s *Server) DispatchJob(job *structs.Job) (*structs.Evaluation, error) {
 	}
 
 	// Commit this update via Raft
-	job.SetSubmitTime()
 	req := structs.JobRegisterRequest{
 		Job:  job,
 		Eval: eval,
@@ -382,6 +382,7 @@ func normalizeStoppedAlloc(stoppedAlloc *structs.Allocation, now int64) *structs
 	return &structs.AllocationDiff{
 		ID:                 stoppedAlloc.ID,
 		DesiredDescription: stoppedAlloc.DesiredDescription,
+		StopReason:         stoppedAlloc.StopReason,
 		ClientStatus:       stoppedAlloc.ClientStatus,
 		ModifyTime:         now,
 		FollowupEvalID:     stoppedAlloc.FollowupEvalID,
@@ -277,6 +277,7 @@ func TestPlanApply_applyPlanWithNormalizedAllocs(t *testing.T) {
 	stoppedAllocDiff := &structs.Allocation{
 		ID:                 stoppedAlloc.ID,
 		DesiredDescription: "Desired Description",
+		StopReason:         structs.AllocStopReasonLost,
 		ClientStatus:       structs.AllocClientStatusLost,
 	}
 	preemptedAlloc := mock.Alloc()
@@ -359,6 +360,7 @@ func TestPlanApply_applyPlanWithNormalizedAllocs(t *testing.T) {
 	require.NotNil(updatedStoppedAlloc)
 	assert.True(updatedStoppedAlloc.ModifyTime > timestampBeforeCommit)
 	assert.Equal(updatedStoppedAlloc.DesiredDescription, stoppedAllocDiff.DesiredDescription)
+	assert.Equal(updatedStoppedAlloc.StopReason, stoppedAllocDiff.StopReason)
 	assert.Equal(updatedStoppedAlloc.ClientStatus, stoppedAllocDiff.ClientStatus)
 	assert.Equal(updatedStoppedAlloc.DesiredStatus, structs.AllocDesiredStatusStop)
 
@@ -369,6 +371,7 @@ func TestPlanApply_applyPlanWithNormalizedAllocs(t *testing.T) {
 	assert.True(updatedPreemptedAlloc.ModifyTime > timestampBeforeCommit)
 	assert.Equal(updatedPreemptedAlloc.DesiredDescription,
 		"Preempted by alloc ID "+preemptedAllocDiff.PreemptedByAllocation)
+	assert.Equal(updatedPreemptedAlloc.StopReason, structs.AllocStopReasonPreempted)
 	assert.Equal(updatedPreemptedAlloc.DesiredStatus, structs.AllocDesiredStatusEvict)
 
 	// Lookup the new deployment
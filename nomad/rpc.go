@@ -25,6 +25,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/raft"
+	"github.com/hashicorp/serf/serf"
 	"github.com/hashicorp/yamux"
 )
 
@@ -351,6 +352,24 @@ func (r *rpcHandler) handleConn(ctx context.Context, conn net.Conn, rpcCtx *RPCC
 	case pool.RpcMultiplexV2:
 		r.handleMultiplexV2(ctx, conn, rpcCtx)
 
+	case pool.RpcMultiplexV2Zstd:
+		// Only authenticated peers may make the server allocate a decoder,
+		// so compressed connections are refused unless they were opened
+		// over TLS with a verified client certificate.
+		if !r.config.RPCCompressionEnabled() || !rpcCtx.TLS || rpcCtx.Certificate() == nil {
+			r.logger.Warn("rejecting compressed RPC connection", "remote_addr", conn.RemoteAddr())
+			conn.Close()
+			return
+		}
+
+		zconn, err := pool.NewZstdConn(conn)
+		if err != nil {
+			r.logger.Error("failed to create compressed connection", "error", err)
+			conn.Close()
+			return
+		}
+		r.handleMultiplexV2(ctx, zconn, rpcCtx)
+
 	default:
 		r.logger.Error("unrecognized RPC byte", "byte", buf[0])
 		conn.Close()
@@ -548,9 +567,21 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 		return true, err
 	}
 
-	// Check if we can allow a stale read
+	// Check if we can allow a stale read. Stale reads received by a voter
+	// are routed to a read replica of the region if there is one.
 	if info.IsRead() && info.AllowStaleRead() {
-		return false, nil
+		if info.IsForwarded() || r.config.ReadReplica {
+			return false, nil
+		}
+		replica := r.getReadReplica()
+		if replica == nil {
+			return false, nil
+		}
+
+		info.SetForwarded()
+		metrics.IncrCounter([]string{"nomad", "rpc", "read_replica", "forward"}, 1)
+		err := r.forwardServer(replica, method, args, reply)
+		return true, err
 	}
 
 	remoteServer, err := r.getLeaderForRPC()
@@ -669,6 +700,24 @@ func (r *rpcHandler) findRegionServer(region string) (*serverParts, error) {
 	return servers[offset], nil
 }
 
+// getReadReplica returns a random alive read replica of the local region, or
+// nil if there are none.
+func (s *Server) getReadReplica() *serverParts {
+	s.peerLock.RLock()
+	defer s.peerLock.RUnlock()
+
+	var replicas []*serverParts
+	for _, server := range s.localPeers {
+		if server.ReadReplica && server.Status == serf.StatusAlive {
+			replicas = append(replicas, server)
+		}
+	}
+	if len(replicas) == 0 {
+		return nil
+	}
+	return replicas[rand.Intn(len(replicas))]
+}
+
 // forwardRegion is used to forward an RPC call to a remote region, or fail if no servers
 func (r *rpcHandler) forwardRegion(region, method string, args interface{}, reply interface{}) error {
 	server, err := r.findRegionServer(region)
@@ -676,8 +725,12 @@ func (r *rpcHandler) forwardRegion(region, method string, args interface{}, repl
 		return err
 	}
 
-	// Forward to remote Nomad
+	// Forward to remote Nomad, compressing the connection if both servers
+	// have compression enabled
 	metrics.IncrCounter([]string{"nomad", "rpc", "cross-region", region}, 1)
+	if r.config.RPCCompressionEnabled() && server.RPCCompression {
+		return r.connPool.RPCCompressed(region, server.Addr, method, args, reply)
+	}
 	return r.connPool.RPC(region, server.Addr, method, args, reply)
 }
 
@@ -706,7 +759,15 @@ func (r *rpcHandler) getServer(region, serverID string) (*serverParts, error) {
 // initial handshake, returning the connection or an error. It is the callers
 // responsibility to close the connection if there is no returned error.
 func (r *rpcHandler) streamingRpc(server *serverParts, method string) (net.Conn, error) {
-	c, err := r.connPool.StreamingRPC(r.config.Region, server.Addr)
+	var c net.Conn
+	var err error
+
+	// Compress streams to servers in other regions if they support it
+	if server.Region != r.config.Region && r.config.RPCCompressionEnabled() && server.RPCCompression {
+		c, err = r.connPool.StreamingRPCCompressed(r.config.Region, server.Addr)
+	} else {
+		c, err = r.connPool.StreamingRPC(r.config.Region, server.Addr)
+	}
 	if err != nil {
 		return nil, err
 	}
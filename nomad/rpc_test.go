@@ -132,6 +132,77 @@ func TestRPC_WaitForConsistentReads(t *testing.T) {
 
 }
 
+func TestRPC_forwardStaleReadToReplica(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 0
+		c.ReadReplica = true
+		c.NonVoter = true
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// The voter knows about the replica
+	testutil.WaitForResult(func() (bool, error) {
+		replica := s1.getReadReplica()
+		if replica == nil {
+			return false, fmt.Errorf("no read replica")
+		}
+		return replica.ID == s2.config.NodeID, fmt.Errorf("unexpected replica %v", replica)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	job := mock.Job()
+	regReq := &structs.JobRegisterRequest{
+		Job:          job,
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: job.Namespace},
+	}
+	var regResp structs.JobRegisterResponse
+	if err := s1.RPC("Job.Register", regReq, &regResp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// A stale read is forwarded to the replica and a consistent read is not
+	getReq := &structs.JobSpecificRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:        "global",
+			Namespace:     job.Namespace,
+			AllowStale:    true,
+			MinQueryIndex: regResp.JobModifyIndex - 1,
+		},
+	}
+	var getResp structs.SingleJobResponse
+	done, err := s1.forward("Job.GetJob", getReq, getReq, &getResp)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !done {
+		t.Fatalf("expected stale read to be forwarded")
+	}
+	if getResp.Job == nil || getResp.Job.ID != job.ID {
+		t.Fatalf("bad: %#v", getResp.Job)
+	}
+
+	getReq = &structs.JobSpecificRequest{
+		JobID:        job.ID,
+		QueryOptions: structs.QueryOptions{Region: "global", Namespace: job.Namespace},
+	}
+	done, err = s1.forward("Job.GetJob", getReq, getReq, &getResp)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if done {
+		t.Fatalf("expected consistent read to be served by the leader")
+	}
+}
+
 func TestRPC_forwardRegion(t *testing.T) {
 	ci.Parallel(t)
 
@@ -157,6 +228,46 @@ func TestRPC_forwardRegion(t *testing.T) {
 	}
 }
 
+// TestRPC_forwardRegion_Compressed asserts that RPCs are forwarded between
+// regions over a compressed connection when both servers enable it over mTLS.
+func TestRPC_forwardRegion_Compressed(t *testing.T) {
+	ci.Parallel(t)
+
+	const (
+		cafile  = "../helper/tlsutil/testdata/ca.pem"
+		foocert = "../helper/tlsutil/testdata/nomad-foo.pem"
+		fookey  = "../helper/tlsutil/testdata/nomad-foo-key.pem"
+	)
+	tlsConfig := &config.TLSConfig{
+		EnableRPC: true,
+		CAFile:    cafile,
+		CertFile:  foocert,
+		KeyFile:   fookey,
+	}
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.TLSConfig = tlsConfig
+		c.RPCCompression = true
+	})
+	defer cleanupS1()
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.Region = "global"
+		c.TLSConfig = tlsConfig
+		c.RPCCompression = true
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	server, err := s1.findRegionServer("global")
+	require.NoError(t, err)
+	require.True(t, server.RPCCompression)
+
+	var out struct{}
+	require.NoError(t, s1.forwardRegion("global", "Status.Ping", struct{}{}, &out))
+}
+
 func TestRPC_getServer(t *testing.T) {
 	ci.Parallel(t)
 
@@ -221,7 +332,7 @@ func TestRPC_PlaintextRPCSucceedsWhenInUpgradeMode(t *testing.T) {
 	assert.Nil(err)
 
 	// Check that heartbeatTimers has the heartbeat ID
-	_, ok := s1.heartbeatTimers[node.ID]
+	ok := s1.heartbeatTimers.Has(node.ID)
 	assert.True(ok)
 }
 
@@ -539,6 +650,54 @@ func TestRPC_handleMultiplexV2(t *testing.T) {
 
 }
 
+// TestRPC_handleMultiplexV2Zstd asserts that compressed connections are
+// refused unless compression is enabled and the peer is authenticated.
+func TestRPC_handleMultiplexV2Zstd(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name        string
+		compression bool
+	}{
+		{name: "disabled", compression: false},
+		{name: "enabled without tls", compression: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, cleanupS := TestServer(t, func(c *Config) {
+				c.RPCCompression = tc.compression
+			})
+			defer cleanupS()
+
+			_, ok := s.LocalMember().Tags["rpc_zstd"]
+			require.False(t, ok)
+
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+
+			doneCh := make(chan struct{})
+			go func() {
+				s.handleConn(context.Background(), p2, &RPCContext{Conn: p2})
+				close(doneCh)
+			}()
+
+			_, err := p1.Write([]byte{byte(pool.RpcMultiplexV2Zstd)})
+			require.NoError(t, err)
+
+			select {
+			case <-doneCh:
+			case <-time.After(5 * time.Second):
+				t.Fatal("compressed connection was not rejected")
+			}
+
+			p1.SetReadDeadline(time.Now().Add(time.Second))
+			_, err = p1.Read(make([]byte, 1))
+			require.Equal(t, io.EOF, err)
+		})
+	}
+}
+
 // TestRPC_TLS_in_TLS asserts that trying to nest TLS connections fails.
 func TestRPC_TLS_in_TLS(t *testing.T) {
 	ci.Parallel(t)
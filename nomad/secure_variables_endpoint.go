@@ -181,6 +181,142 @@ func (sv *SecureVariables) Delete(
 	return nil
 }
 
+// Txn applies multiple secure variable operations atomically. Either all
+// the operations are applied, or none if the check index of any of them
+// doesn't match, in which case the conflicting secure variables are returned.
+func (sv *SecureVariables) Txn(
+	args *structs.SecureVariablesTxnRequest,
+	reply *structs.SecureVariablesTxnResponse) error {
+
+	if done, err := sv.srv.forward(structs.SecureVariablesTxnRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "secure_variables", "txn"}, time.Now())
+
+	if !ServersMeetMinimumVersion(sv.srv.Members(), minSecureVariablesTxnVersion, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use secure variable transactions", minSecureVariablesTxnVersion)
+	}
+
+	if len(args.Ops) == 0 {
+		return fmt.Errorf("transaction requires at least one operation")
+	}
+	if len(args.Ops) > structs.MaxSecureVariablesTxnOps {
+		return fmt.Errorf("transaction has %d operations, the maximum is %d",
+			len(args.Ops), structs.MaxSecureVariablesTxnOps)
+	}
+
+	// Perform the ACL token resolution.
+	aclObj, err := sv.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Use a multierror, so we can capture all validation errors and pass this
+	// back so they can be addressed by the caller in a single pass.
+	var mErr multierror.Error
+	tArgs := structs.SecureVariablesEncryptedTxnRequest{
+		Ops:          make([]*structs.SecureVariableEncryptedTxnOp, len(args.Ops)),
+		WriteRequest: args.WriteRequest,
+	}
+	uArgs := structs.SecureVariablesEncryptedUpsertRequest{
+		WriteRequest: args.WriteRequest,
+	}
+	seen := make(map[[2]string]struct{}, len(args.Ops))
+
+	for i, op := range args.Ops {
+		if op.Var != nil && op.Var.Namespace == "" {
+			op.Var.Namespace = args.RequestNamespace()
+		}
+		if err := op.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("operation %d: %v", i, err))
+			continue
+		}
+
+		if aclObj != nil && !aclObj.AllowSecureVariableOperation(
+			op.Var.Namespace, op.Var.Path, acl.PolicyWrite) {
+			return structs.ErrPermissionDenied
+		}
+
+		key := [2]string{op.Var.Namespace, op.Var.Path}
+		if _, ok := seen[key]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"operation %d: variable %q is already used by another operation", i, op.Var.Path))
+			continue
+		}
+		seen[key] = struct{}{}
+
+		tOp := &structs.SecureVariableEncryptedTxnOp{
+			Op:         op.Op,
+			CheckIndex: op.CheckIndex,
+		}
+		if op.Op == structs.SecureVariableOpSet {
+			ev, err := sv.encrypt(op.Var)
+			if err != nil {
+				mErr.Errors = append(mErr.Errors, err)
+				continue
+			}
+			tOp.Var = ev
+			uArgs.Data = append(uArgs.Data, ev)
+		} else {
+			tOp.Var = &structs.SecureVariableEncrypted{
+				SecureVariableMetadata: structs.SecureVariableMetadata{
+					Namespace: op.Var.Namespace,
+					Path:      op.Var.Path,
+				},
+			}
+		}
+		tArgs.Ops[i] = tOp
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return &mErr
+	}
+
+	if err := sv.enforceQuota(uArgs); err != nil {
+		return err
+	}
+
+	// Update via Raft. The check indexes are verified by the FSM so the
+	// whole transaction is atomic.
+	out, index, err := sv.srv.raftApply(structs.SecureVariablesTxnRequestType, tArgs)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error or
+	// the conflicts which prevented the transaction to be applied.
+	switch resp := out.(type) {
+	case error:
+		if resp != nil {
+			return resp
+		}
+	case []*structs.SecureVariableEncrypted:
+		reply.Conflicts = make([]*structs.SecureVariableDecrypted, len(resp))
+		for i, conflict := range resp {
+			if conflict == nil {
+				continue
+			}
+			if conflict.ModifyIndex == 0 {
+				// The variable doesn't exist, there is nothing to decrypt
+				reply.Conflicts[i] = &structs.SecureVariableDecrypted{
+					SecureVariableMetadata: conflict.SecureVariableMetadata,
+				}
+				continue
+			}
+			dec, err := sv.decrypt(conflict)
+			if err != nil {
+				return fmt.Errorf("cas error: found index %v for variable %q, error decrypting conflict: %v",
+					conflict.ModifyIndex, conflict.Path, err)
+			}
+			reply.Conflicts[i] = dec
+		}
+	}
+
+	// Update the index. There is no need to floor this as we are writing to
+	// state and therefore will get a non-zero index response.
+	reply.Index = index
+	return nil
+}
+
 // Read is used to get a specific secure variable
 func (sv *SecureVariables) Read(args *structs.SecureVariablesReadRequest, reply *structs.SecureVariablesReadResponse) error {
 	if done, err := sv.srv.forward(structs.SecureVariablesReadRPCMethod, args, args, reply); done {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -255,3 +256,132 @@ func TestSecureVariablesEndpoint_auth(t *testing.T) {
 	}
 
 }
+
+func TestSecureVariablesEndpoint_Txn(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, rootToken, shutdown := TestACLServer(t, nil)
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+
+	sv1, sv2 := mock.SecureVariable(), mock.SecureVariable()
+	sv1.Path, sv2.Path = "app/config/a", "app/config/b"
+	cp := func(sv *structs.SecureVariableDecrypted) *structs.SecureVariableDecrypted {
+		out := sv.Copy()
+		return &out
+	}
+
+	txn := func(token string, ops ...*structs.SecureVariableTxnOp) (*structs.SecureVariablesTxnResponse, error) {
+		req := &structs.SecureVariablesTxnRequest{
+			Ops: ops,
+			WriteRequest: structs.WriteRequest{
+				Region:    srv.config.Region,
+				Namespace: structs.DefaultNamespace,
+				AuthToken: token,
+			},
+		}
+		var resp structs.SecureVariablesTxnResponse
+		err := srv.RPC(structs.SecureVariablesTxnRPCMethod, req, &resp)
+		return &resp, err
+	}
+
+	// Create both variables if they don't exist
+	resp, err := txn(rootToken.SecretID,
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpSet, Var: cp(sv1), CheckIndex: helper.Uint64ToPtr(0)},
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpSet, Var: cp(sv2), CheckIndex: helper.Uint64ToPtr(0)},
+	)
+	require.NoError(t, err)
+	require.Nil(t, resp.Conflicts)
+	createIndex := resp.Index
+
+	got, err := srv.fsm.State().GetSecureVariable(nil, structs.DefaultNamespace, sv2.Path)
+	require.NoError(t, err)
+	require.Equal(t, createIndex, got.CreateIndex)
+
+	// Retrying the creation conflicts, and the conflicts are decrypted
+	updated := cp(sv1)
+	updated.Items = structs.SecureVariableItems{"key": "updated"}
+	resp, err = txn(rootToken.SecretID,
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpSet, Var: updated, CheckIndex: helper.Uint64ToPtr(createIndex)},
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpSet, Var: cp(sv2), CheckIndex: helper.Uint64ToPtr(0)},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Conflicts, 2)
+	require.Nil(t, resp.Conflicts[0])
+	require.Equal(t, sv2.Items, resp.Conflicts[1].Items)
+	require.Equal(t, createIndex, resp.Conflicts[1].ModifyIndex)
+
+	got, err = srv.fsm.State().GetSecureVariable(nil, structs.DefaultNamespace, sv1.Path)
+	require.NoError(t, err)
+	require.Equal(t, createIndex, got.ModifyIndex)
+
+	// Update one variable and delete the other
+	resp, err = txn(rootToken.SecretID,
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpSet, Var: updated, CheckIndex: helper.Uint64ToPtr(createIndex)},
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpDelete, Var: &structs.SecureVariableDecrypted{
+			SecureVariableMetadata: structs.SecureVariableMetadata{Path: sv2.Path},
+		}},
+	)
+	require.NoError(t, err)
+	require.Nil(t, resp.Conflicts)
+
+	got, err = srv.fsm.State().GetSecureVariable(nil, structs.DefaultNamespace, sv1.Path)
+	require.NoError(t, err)
+	require.Equal(t, resp.Index, got.ModifyIndex)
+	got, err = srv.fsm.State().GetSecureVariable(nil, structs.DefaultNamespace, sv2.Path)
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	// Operations on the same variable are rejected
+	_, err = txn(rootToken.SecretID,
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpSet, Var: updated},
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpDelete, Var: updated},
+	)
+	require.ErrorContains(t, err, "already used by another operation")
+
+	// Write access is required on every path
+	token := mock.CreatePolicyAndToken(t, srv.fsm.State(), 1000, "app-a",
+		mock.NamespacePolicyWithSecureVariables(structs.DefaultNamespace, "", nil,
+			map[string][]string{"app/config/a": {"write"}}))
+	_, err = txn(token.SecretID,
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpSet, Var: updated},
+		&structs.SecureVariableTxnOp{Op: structs.SecureVariableOpSet, Var: cp(sv2)},
+	)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestSecureVariablesEndpoint_Txn_OldServers(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+	})
+	defer cleanupS1()
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+
+		// simulate a server that can't apply secure variable transactions
+		c.Build = "1.3.3"
+	})
+	defer cleanupS2()
+
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	sv := mock.SecureVariable()
+	req := &structs.SecureVariablesTxnRequest{
+		Ops: []*structs.SecureVariableTxnOp{
+			{Op: structs.SecureVariableOpSet, Var: sv, CheckIndex: helper.Uint64ToPtr(0)},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    s1.config.Region,
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var resp structs.SecureVariablesTxnResponse
+	err := s1.RPC(structs.SecureVariablesTxnRPCMethod, req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "All servers should be running version")
+}
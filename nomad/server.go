@@ -1076,6 +1076,9 @@ func (s *Server) setupDeploymentWatcher() error {
 		s.config.DeploymentQueryRateLimit,
 		deploymentwatcher.CrossDeploymentUpdateBatchDuration,
 	)
+	for _, gate := range s.config.PromotionGates {
+		s.deploymentWatcher.RegisterPromotionGate(gate)
+	}
 
 	return nil
 }
@@ -1255,6 +1258,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) error {
 	server.Register(s.staticEndpoints.ClientCSI)
 	server.Register(s.staticEndpoints.FileSystem)
 	server.Register(s.staticEndpoints.Agent)
+	server.Register(s.staticEndpoints.Event)
 	server.Register(s.staticEndpoints.Namespace)
 	server.Register(s.staticEndpoints.SecureVariables)
 
@@ -1525,6 +1529,13 @@ func (s *Server) setupSerf(conf *serf.Config, ch chan serf.Event, path string) (
 	if s.config.NonVoter {
 		conf.Tags["nonvoter"] = "1"
 	}
+	if s.config.ReadReplica {
+		conf.Tags["read_replica"] = "1"
+	}
+
+	if s.config.RPCCompressionEnabled() {
+		conf.Tags["rpc_zstd"] = "1"
+	}
 	if s.config.RedundancyZone != "" {
 		conf.Tags[AutopilotRZTag] = s.config.RedundancyZone
 	}
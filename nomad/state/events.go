@@ -14,6 +14,7 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.JobRegisterRequestType:                       structs.TypeJobRegistered,
 	structs.AllocUpdateRequestType:                       structs.TypeAllocationUpdated,
 	structs.NodeUpdateStatusRequestType:                  structs.TypeNodeEvent,
+	structs.NodeBatchUpdateStatusRequestType:             structs.TypeNodeEvent,
 	structs.JobDeregisterRequestType:                     structs.TypeJobDeregistered,
 	structs.JobBatchDeregisterRequestType:                structs.TypeJobBatchDeregistered,
 	structs.AllocUpdateDesiredTransitionRequestType:      structs.TypeAllocationUpdateDesiredStatus,
@@ -31,6 +32,8 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.ServiceRegistrationUpsertRequestType:         structs.TypeServiceRegistration,
 	structs.ServiceRegistrationDeleteByIDRequestType:     structs.TypeServiceDeregistration,
 	structs.ServiceRegistrationDeleteByNodeIDRequestType: structs.TypeServiceDeregistration,
+	structs.ReconcileDeploymentsRequestType:              structs.TypeDeploymentUpdate,
+	structs.ReconcileServiceRegistrationsRequestType:     structs.TypeServiceDeregistration,
 }
 
 func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
@@ -158,7 +161,7 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 		if !ok {
 			return structs.Event{}, false
 		}
-		alloc := after.Copy()
+		alloc := after.CopySkipJob()
 
 		filterKeys := []string{
 			alloc.JobID,
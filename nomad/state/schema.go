@@ -17,6 +17,8 @@ const (
 	TableSecureVariables       = "secure_variables"
 	TableSecureVariablesQuotas = "secure_variables_quota"
 	TableRootKeyMeta           = "secure_variables_root_key_meta"
+	TableNodeIntroTokens       = "node_intro_tokens"
+	TableJobTrash              = "job_trash"
 )
 
 const (
@@ -27,6 +29,7 @@ const (
 	indexServiceName = "service_name"
 	indexKeyID       = "key_id"
 	indexPath        = "path"
+	indexSecret      = "secret"
 )
 
 var (
@@ -78,6 +81,8 @@ func init() {
 		secureVariablesTableSchema,
 		secureVariablesQuotasTableSchema,
 		secureVariablesRootKeyMetaSchema,
+		nodeIntroTokensTableSchema,
+		jobTrashTableSchema,
 	}...)
 }
 
@@ -1338,3 +1343,54 @@ func secureVariablesRootKeyMetaSchema() *memdb.TableSchema {
 		},
 	}
 }
+
+// nodeIntroTokensTableSchema returns the MemDB schema for the node
+// introduction tokens table.
+func nodeIntroTokensTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableNodeIntroTokens,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "AccessorID",
+				},
+			},
+			indexSecret: {
+				Name:         indexSecret,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "SecretID",
+				},
+			},
+		},
+	}
+}
+
+// jobTrashTableSchema returns the MemDB schema for the job trash table, which
+// keeps purged jobs until they can no longer be restored.
+func jobTrashTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobTrash,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "ID",
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -431,9 +430,19 @@ func (s *StateStore) UpsertPlanResults(msgType structs.MessageType, index uint64
 	allocsToUpsert = append(allocsToUpsert, results.AllocsUpdated...)
 	allocsToUpsert = append(allocsToUpsert, allocsPreempted...)
 
-	// handle upgrade path
+	// handle upgrade path. The allocations of the plan share its job, so it
+	// is only canonicalized once. The stopped and preempted allocations share
+	// their job with the state store, so it must not be modified.
+	results.Job.Canonicalize()
 	for _, alloc := range allocsToUpsert {
-		alloc.Canonicalize()
+		alloc.CanonicalizeSkipJob()
+	}
+	for _, allocs := range [][]*structs.Allocation{results.Alloc, results.AllocsUpdated} {
+		for _, alloc := range allocs {
+			if alloc.Job != results.Job {
+				alloc.Job.Canonicalize()
+			}
+		}
 	}
 
 	if err := s.upsertAllocsImpl(index, allocsToUpsert, txn); err != nil {
@@ -800,6 +809,133 @@ func (s *StateStore) DeleteDeployment(index uint64, deploymentIDs []string) erro
 	return txn.Commit()
 }
 
+// ReconcileDeployments recomputes the placed, healthy and unhealthy allocation
+// counts of the active deployments from their allocations.
+func (s *StateStore) ReconcileDeployments(msgType structs.MessageType, index uint64) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	deployments, err := s.reconciledDeployments(txn)
+	if err != nil {
+		return err
+	}
+	if len(deployments) == 0 {
+		return nil
+	}
+
+	for _, deployment := range deployments {
+		deployment.ModifyIndex = index
+		if err := txn.Insert("deployment", deployment); err != nil {
+			return fmt.Errorf("deployment insert failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"deployment", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// DeploymentChanges returns the changes ReconcileDeployments would make to the
+// deployments.
+func (s *StateStore) DeploymentChanges() ([]*structs.ReconcileChange, error) {
+	txn := s.db.ReadTxn()
+
+	deployments, err := s.reconciledDeployments(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*structs.ReconcileChange
+	for _, deployment := range deployments {
+		existing, err := txn.First("deployment", "id", deployment.ID)
+		if err != nil {
+			return nil, fmt.Errorf("deployment lookup failed: %v", err)
+		}
+		if change := structs.NewReconcileChange(deployment.Namespace, deployment.ID,
+			existing.(*structs.Deployment), deployment); change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// reconciledDeployments returns a copy of the active deployments whose
+// allocation counts differ from the ones of their allocations, with the counts
+// corrected.
+func (s *StateStore) reconciledDeployments(txn ReadTxn) ([]*structs.Deployment, error) {
+	iter, err := txn.Get("deployment", "id")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*structs.Deployment
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		deployment := raw.(*structs.Deployment)
+		if !deployment.Active() {
+			continue
+		}
+
+		counts := make(map[string]*structs.DeploymentState, len(deployment.TaskGroups))
+		for tg := range deployment.TaskGroups {
+			counts[tg] = &structs.DeploymentState{}
+		}
+
+		allocs, err := txn.Get("allocs", "deployment", deployment.ID)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			raw := allocs.Next()
+			if raw == nil {
+				break
+			}
+			alloc := raw.(*structs.Allocation)
+
+			// Allocations of groups that aren't part of the deployment are
+			// not counted, as in updateDeploymentWithAlloc
+			state, ok := counts[alloc.TaskGroup]
+			if !ok {
+				continue
+			}
+			state.PlacedAllocs++
+			if alloc.DeploymentStatus.HasHealth() {
+				if *alloc.DeploymentStatus.Healthy {
+					state.HealthyAllocs++
+				} else {
+					state.UnhealthyAllocs++
+				}
+			}
+		}
+
+		var reconciled *structs.Deployment
+		for tg, state := range deployment.TaskGroups {
+			count := counts[tg]
+			if state.PlacedAllocs == count.PlacedAllocs &&
+				state.HealthyAllocs == count.HealthyAllocs &&
+				state.UnhealthyAllocs == count.UnhealthyAllocs {
+				continue
+			}
+
+			if reconciled == nil {
+				reconciled = deployment.Copy()
+			}
+			dstate := reconciled.TaskGroups[tg]
+			dstate.PlacedAllocs = count.PlacedAllocs
+			dstate.HealthyAllocs = count.HealthyAllocs
+			dstate.UnhealthyAllocs = count.UnhealthyAllocs
+		}
+		if reconciled != nil {
+			out = append(out, reconciled)
+		}
+	}
+	return out, nil
+}
+
 // UpsertScalingEvent is used to insert a new scaling event.
 // Only the most recent JobTrackedScalingEvents will be kept.
 func (s *StateStore) UpsertScalingEvent(index uint64, req *structs.ScalingEventRequest) error {
@@ -1004,6 +1140,30 @@ func (s *StateStore) UpdateNodeStatus(msgType structs.MessageType, index uint64,
 	return txn.Commit()
 }
 
+// BatchUpdateNodeStatus is used to update the status of a batch of nodes in a
+// single transaction. Updates for nodes that no longer exist are skipped, so
+// that a node deregistered while its update was batched doesn't fail the
+// updates of the other nodes.
+func (s *StateStore) BatchUpdateNodeStatus(msgType structs.MessageType, index uint64, updates []*structs.NodeUpdateStatusRequest) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, update := range updates {
+		existing, err := txn.First("nodes", "id", update.NodeID)
+		if err != nil {
+			return fmt.Errorf("node lookup failed: %v", err)
+		}
+		if existing == nil {
+			continue
+		}
+		if err := s.updateNodeStatusTxn(txn, update.NodeID, update.Status, update.UpdatedAt, update.NodeEvent); err != nil {
+			return err
+		}
+	}
+
+	return txn.Commit()
+}
+
 func (s *StateStore) updateNodeStatusTxn(txn *txn, nodeID, status string, updatedAt int64, event *structs.NodeEvent) error {
 
 	// Lookup the node
@@ -3471,7 +3631,7 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *txn, index uint64, alloc *
 	exist := existing.(*structs.Allocation)
 
 	// Copy everything from the existing allocation
-	copyAlloc := exist.Copy()
+	copyAlloc := exist.CopySkipJob()
 
 	// Pull in anything the client is the authority on
 	copyAlloc.ClientStatus = alloc.ClientStatus
@@ -3479,8 +3639,8 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *txn, index uint64, alloc *
 	copyAlloc.TaskStates = alloc.TaskStates
 	copyAlloc.NetworkStatus = alloc.NetworkStatus
 
-	// The client can only set its deployment health and timestamp, so just take
-	// those
+	// The client can only set its deployment health, timestamp and failed
+	// checks, so just take those
 	if copyAlloc.DeploymentStatus != nil && alloc.DeploymentStatus != nil {
 		oldHasHealthy := copyAlloc.DeploymentStatus.HasHealth()
 		newHasHealthy := alloc.DeploymentStatus.HasHealth()
@@ -3490,6 +3650,7 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *txn, index uint64, alloc *
 			// Updated deployment health and timestamp
 			copyAlloc.DeploymentStatus.Healthy = helper.BoolToPtr(*alloc.DeploymentStatus.Healthy)
 			copyAlloc.DeploymentStatus.Timestamp = alloc.DeploymentStatus.Timestamp
+			copyAlloc.DeploymentStatus.FailedChecks = helper.CopySliceString(alloc.DeploymentStatus.FailedChecks)
 			copyAlloc.DeploymentStatus.ModifyIndex = index
 		}
 	} else if alloc.DeploymentStatus != nil {
@@ -3642,7 +3803,7 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			}
 			existingPrevAlloc, _ := prevAlloc.(*structs.Allocation)
 			if existingPrevAlloc != nil {
-				prevAllocCopy := existingPrevAlloc.Copy()
+				prevAllocCopy := existingPrevAlloc.CopySkipJob()
 				prevAllocCopy.NextAllocation = alloc.ID
 				prevAllocCopy.ModifyIndex = index
 				if err := txn.Insert("allocs", prevAllocCopy); err != nil {
@@ -3725,7 +3886,7 @@ func (s *StateStore) UpdateAllocDesiredTransitionTxn(
 	exist := existing.(*structs.Allocation)
 
 	// Copy everything from the existing allocation
-	copyAlloc := exist.Copy()
+	copyAlloc := exist.CopySkipJob()
 
 	// Merge the desired transitions
 	copyAlloc.DesiredTransition.Merge(transition)
@@ -4380,6 +4541,34 @@ func (s *StateStore) updateJobStabilityImpl(index uint64, namespace, jobID strin
 	return s.upsertJobImpl(index, copy, true, txn)
 }
 
+// UpdateJobFreeze updates the freeze state of the latest version of the given
+// job.
+func (s *StateStore) UpdateJobFreeze(index uint64, namespace, jobID string, frozen bool) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	job, err := s.JobByIDTxn(nil, namespace, jobID, txn)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("job %q in namespace %q not found", jobID, namespace)
+	}
+
+	// If the job already has the desired freeze state, nothing to do
+	if job.Frozen == frozen {
+		return nil
+	}
+
+	copy := job.Copy()
+	copy.Frozen = frozen
+	if err := s.upsertJobImpl(index, copy, true, txn); err != nil {
+		return err
+	}
+
+	return txn.Commit()
+}
+
 // UpdateDeploymentPromotion is used to promote canaries in a deployment and
 // potentially make a evaluation
 func (s *StateStore) UpdateDeploymentPromotion(msgType structs.MessageType, index uint64, req *structs.ApplyDeploymentPromoteRequest) error {
@@ -4507,7 +4696,7 @@ func (s *StateStore) UpdateDeploymentPromotion(msgType structs.MessageType, inde
 
 	// For each promotable allocation remove the canary field
 	for _, alloc := range promotable {
-		promoted := alloc.Copy()
+		promoted := alloc.CopySkipJob()
 		promoted.DeploymentStatus.Canary = false
 		promoted.DeploymentStatus.ModifyIndex = index
 		promoted.ModifyIndex = index
@@ -4680,10 +4869,75 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 	txn := s.db.WriteTxn(index)
 	defer txn.Abort()
 
+	summaries, err := s.reconciledJobSummaries(txn)
+	if err != nil {
+		return err
+	}
+
+	for _, summary := range summaries {
+		// Set the modify index of the summary to the current index
+		summary.ModifyIndex = index
+
+		// Insert the job summary
+		if err := txn.Insert("job_summary", summary); err != nil {
+			return fmt.Errorf("error inserting job summary: %v", err)
+		}
+	}
+
+	// Update the indexes table for job summary
+	if err := txn.Insert("index", &IndexEntry{"job_summary", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// JobSummaryChanges returns the changes ReconcileJobSummaries would make to
+// the job summaries.
+func (s *StateStore) JobSummaryChanges() ([]*structs.ReconcileChange, error) {
+	txn := s.db.ReadTxn()
+
+	summaries, err := s.reconciledJobSummaries(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*structs.ReconcileChange
+	for _, summary := range summaries {
+		existing, err := txn.First("job_summary", "id", summary.Namespace, summary.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("job summary lookup failed: %v", err)
+		}
+
+		// The queued allocations are recomputed by the FSM once the summaries
+		// are reconciled, and summaries are created with empty children, so
+		// neither is a change
+		if existing != nil {
+			old := existing.(*structs.JobSummary)
+			for tg, tgSummary := range summary.Summary {
+				tgSummary.Queued = old.Summary[tg].Queued
+				summary.Summary[tg] = tgSummary
+			}
+			if summary.Children == nil && old.Children != nil && *old.Children == (structs.JobChildrenSummary{}) {
+				summary.Children = old.Children
+			}
+		}
+
+		// Missing summaries are passed as an untyped nil and are created
+		if change := structs.NewReconcileChange(summary.Namespace, summary.JobID, existing, summary); change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// reconciledJobSummaries re-creates the summaries of all jobs present in the
+// state store from their allocations, or from their children for parent jobs.
+// The create index of the summaries is set to the one of their job.
+func (s *StateStore) reconciledJobSummaries(txn ReadTxn) ([]*structs.JobSummary, error) {
 	// Get all the jobs
 	iter, err := txn.Get("jobs", "id")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// COMPAT: Remove after 0.11
 	// Iterate over jobs to build a list of parent jobs and their children
@@ -4704,9 +4958,10 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 	// Get all the jobs again
 	iter, err = txn.Get("jobs", "id")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var summaries []*structs.JobSummary
 	for {
 		rawJob := iter.Next()
 		if rawJob == nil {
@@ -4721,20 +4976,19 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 			// See https://github.com/hashicorp/nomad/issues/3886 for details
 			rawSummary, err := txn.First("job_summary", "id", job.Namespace, job.ID)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			if rawSummary == nil {
 				continue
 			}
 
-			oldSummary := rawSummary.(*structs.JobSummary)
-
 			// Create an empty summary
 			summary := &structs.JobSummary{
-				JobID:     job.ID,
-				Namespace: job.Namespace,
-				Summary:   make(map[string]structs.TaskGroupSummary),
-				Children:  &structs.JobChildrenSummary{},
+				JobID:       job.ID,
+				Namespace:   job.Namespace,
+				Summary:     make(map[string]structs.TaskGroupSummary),
+				Children:    &structs.JobChildrenSummary{},
+				CreateIndex: job.CreateIndex,
 			}
 
 			// Iterate over children of this job if any to fix summary counts
@@ -4750,27 +5004,17 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 				}
 			}
 
-			// Insert the job summary if its different
-			if !reflect.DeepEqual(summary, oldSummary) {
-				// Set the create index of the summary same as the job's create index
-				// and the modify index to the current index
-				summary.CreateIndex = job.CreateIndex
-				summary.ModifyIndex = index
-
-				if err := txn.Insert("job_summary", summary); err != nil {
-					return fmt.Errorf("error inserting job summary: %v", err)
-				}
-			}
-
 			// Done with handling a parent job, continue to next
+			summaries = append(summaries, summary)
 			continue
 		}
 
 		// Create a job summary for the job
 		summary := &structs.JobSummary{
-			JobID:     job.ID,
-			Namespace: job.Namespace,
-			Summary:   make(map[string]structs.TaskGroupSummary),
+			JobID:       job.ID,
+			Namespace:   job.Namespace,
+			Summary:     make(map[string]structs.TaskGroupSummary),
+			CreateIndex: job.CreateIndex,
 		}
 		for _, tg := range job.TaskGroups {
 			summary.Summary[tg.Name] = structs.TaskGroupSummary{}
@@ -4779,7 +5023,7 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 		// Find all the allocations for the jobs
 		iterAllocs, err := txn.Get("allocs", "job", job.Namespace, job.ID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		// Calculate the summary for the job
@@ -4816,22 +5060,10 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 			summary.Summary[alloc.TaskGroup] = tg
 		}
 
-		// Set the create index of the summary same as the job's create index
-		// and the modify index to the current index
-		summary.CreateIndex = job.CreateIndex
-		summary.ModifyIndex = index
-
-		// Insert the job summary
-		if err := txn.Insert("job_summary", summary); err != nil {
-			return fmt.Errorf("error inserting job summary: %v", err)
-		}
+		summaries = append(summaries, summary)
 	}
 
-	// Update the indexes table for job summary
-	if err := txn.Insert("index", &IndexEntry{"job_summary", index}); err != nil {
-		return fmt.Errorf("index update failed: %v", err)
-	}
-	return txn.Commit()
+	return summaries, nil
 }
 
 // setJobStatuses is a helper for calling setJobStatus on multiple jobs by ID.
@@ -6652,15 +6884,17 @@ func (s *StateSnapshot) DenormalizeAllocationDiffSlice(allocDiffs []*structs.All
 
 		// Merge the updates to the Allocation.  Don't update alloc.Job for terminal allocs
 		// so alloc refers to the latest Job view before destruction and to ease handler implementations
-		allocCopy := alloc.Copy()
+		allocCopy := alloc.CopySkipJob()
 
 		if allocDiff.PreemptedByAllocation != "" {
 			allocCopy.PreemptedByAllocation = allocDiff.PreemptedByAllocation
 			allocCopy.DesiredDescription = getPreemptedAllocDesiredDescription(allocDiff.PreemptedByAllocation)
+			allocCopy.StopReason = structs.AllocStopReasonPreempted
 			allocCopy.DesiredStatus = structs.AllocDesiredStatusEvict
 		} else {
 			// If alloc is a stopped alloc
 			allocCopy.DesiredDescription = allocDiff.DesiredDescription
+			allocCopy.StopReason = allocDiff.StopReason
 			allocCopy.DesiredStatus = structs.AllocDesiredStatusStop
 			if allocDiff.ClientStatus != "" {
 				allocCopy.ClientStatus = allocDiff.ClientStatus
@@ -0,0 +1,173 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// TrashJobTxn copies the job and its versions into the job trash before the
+// job is purged, so that it can be restored until the given expiry. A job
+// already in the trash with the same ID is replaced.
+func (s *StateStore) TrashJobTxn(index uint64, namespace, jobID string, expiresAt time.Time, txn Txn) error {
+	job, err := s.JobByIDTxn(nil, namespace, jobID, txn)
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	if job == nil {
+		return nil
+	}
+
+	versions, err := s.jobVersionByID(txn, nil, namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("job version lookup failed: %v", err)
+	}
+
+	trashed := &structs.TrashedJob{
+		Namespace:   namespace,
+		ID:          jobID,
+		Job:         job,
+		ExpiresAt:   expiresAt,
+		CreateIndex: index,
+		ModifyIndex: index,
+	}
+	for _, v := range versions {
+		if v.Version != job.Version {
+			trashed.Versions = append(trashed.Versions, v)
+		}
+	}
+
+	if err := txn.Insert(TableJobTrash, trashed); err != nil {
+		return fmt.Errorf("trashing job failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobTrash, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// RestoreTrashedJob restores a purged job and its versions from the job
+// trash. The job is restored stopped, so that it isn't placed again until an
+// operator decides to run it. It returns an error if the job isn't in the
+// trash or if a job with the same ID was registered since it was purged.
+func (s *StateStore) RestoreTrashedJob(msgType structs.MessageType, index uint64, namespace, jobID string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	raw, err := txn.First(TableJobTrash, indexID, namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("trashed job lookup failed: %v", err)
+	}
+	if raw == nil {
+		return fmt.Errorf("job %q in namespace %q is not in the job trash", jobID, namespace)
+	}
+	trashed := raw.(*structs.TrashedJob)
+
+	existing, err := s.JobByIDTxn(nil, namespace, jobID, txn)
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("job %q in namespace %q already exists", jobID, namespace)
+	}
+
+	// Restore the previous versions oldest first, so the most recent ones
+	// are kept if more versions than tracked were trashed.
+	for i := len(trashed.Versions) - 1; i >= 0; i-- {
+		if err := s.upsertJobVersion(index, trashed.Versions[i].Copy(), txn); err != nil {
+			return err
+		}
+	}
+
+	job := trashed.Job.Copy()
+	job.Stop = true
+	if err := s.upsertJobImpl(index, job, true, txn); err != nil {
+		return err
+	}
+
+	if err := txn.Delete(TableJobTrash, trashed); err != nil {
+		return fmt.Errorf("deleting trashed job failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobTrash, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// ExpireTrashedJobs permanently deletes the jobs in the job trash which
+// expired before the given timestamp.
+func (s *StateStore) ExpireTrashedJobs(msgType structs.MessageType, index uint64, timestamp time.Time) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	iter, err := txn.Get(TableJobTrash, indexID)
+	if err != nil {
+		return fmt.Errorf("trashed job lookup failed: %v", err)
+	}
+
+	// Collect the expired jobs before deleting them, since the iterator
+	// can't be used while the table is modified.
+	var expired []*structs.TrashedJob
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		trashed := raw.(*structs.TrashedJob)
+		if trashed.ExpiresAt.Before(timestamp) {
+			expired = append(expired, trashed)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	for _, trashed := range expired {
+		if err := txn.Delete(TableJobTrash, trashed); err != nil {
+			return fmt.Errorf("deleting trashed job failed: %v", err)
+		}
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobTrash, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// TrashedJobByID is used to lookup a purged job in the job trash.
+func (s *StateStore) TrashedJobByID(ws memdb.WatchSet, namespace, jobID string) (*structs.TrashedJob, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableJobTrash, indexID, namespace, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("trashed job lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.TrashedJob), nil
+	}
+	return nil, nil
+}
+
+// TrashedJobs returns an iterator over all the jobs in the job trash.
+func (s *StateStore) TrashedJobs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobTrash, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("trashed job lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// TrashedJobsByNamespace returns an iterator over the jobs in the job trash
+// of a namespace.
+func (s *StateStore) TrashedJobsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobTrash, indexID+"_prefix", namespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("trashed job lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
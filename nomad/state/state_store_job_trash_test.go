@@ -0,0 +1,121 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testTrashJob purges the job, keeping it in the job trash until expiresAt.
+func testTrashJob(t *testing.T, s *StateStore, index uint64, job *structs.Job, expiresAt time.Time) {
+	err := s.WithWriteTransaction(structs.MsgTypeTestSetup, index, func(txn Txn) error {
+		if err := s.TrashJobTxn(index, job.Namespace, job.ID, expiresAt, txn); err != nil {
+			return err
+		}
+		return s.DeleteJobTxn(index, job.Namespace, job.ID, txn)
+	})
+	require.NoError(t, err)
+}
+
+func TestStateStore_JobTrash(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	job := mock.Job()
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 10, job))
+	job = job.Copy()
+	job.Meta["version"] = "1"
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 11, job))
+
+	expiresAt := time.Now().Add(time.Hour)
+	testTrashJob(t, testState, 12, job, expiresAt)
+
+	// The purged job is kept in the trash with its previous versions
+	ws := memdb.NewWatchSet()
+	out, err := testState.TrashedJobByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, uint64(1), out.Job.Version)
+	require.Len(t, out.Versions, 1)
+	require.Equal(t, uint64(0), out.Versions[0].Version)
+	require.True(t, expiresAt.Equal(out.ExpiresAt))
+
+	stub := out.Stub()
+	require.Equal(t, job.Name, stub.Name)
+	require.Equal(t, 2, stub.Versions)
+
+	index, err := testState.Index(TableJobTrash)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), index)
+
+	iter, err := testState.TrashedJobsByNamespace(nil, "other")
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+
+	// Restoring the job fails if a job with the same ID was registered
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 13, job))
+	err = testState.RestoreTrashedJob(structs.MsgTypeTestSetup, 14, job.Namespace, job.ID)
+	require.EqualError(t, err, `job "`+job.ID+`" in namespace "default" already exists`)
+	require.NoError(t, testState.DeleteJob(15, job.Namespace, job.ID))
+
+	// The job is restored stopped along with its versions
+	require.NoError(t, testState.RestoreTrashedJob(structs.MsgTypeTestSetup, 16, job.Namespace, job.ID))
+	require.True(t, watchFired(ws))
+
+	restored, err := testState.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	require.True(t, restored.Stop)
+	require.Equal(t, uint64(1), restored.Version)
+
+	versions, err := testState.JobVersionsByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	summary, err := testState.JobSummaryByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+
+	out, err = testState.TrashedJobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	err = testState.RestoreTrashedJob(structs.MsgTypeTestSetup, 17, job.Namespace, job.ID)
+	require.EqualError(t, err, `job "`+job.ID+`" in namespace "default" is not in the job trash`)
+}
+
+func TestStateStore_ExpireTrashedJobs(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	now := time.Now()
+	valid := mock.Job()
+	expired := mock.Job()
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 10, valid))
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 11, expired))
+	testTrashJob(t, testState, 12, valid, now.Add(time.Hour))
+	testTrashJob(t, testState, 13, expired, now.Add(-time.Minute))
+
+	// Expiring the job trash only deletes the expired job
+	require.NoError(t, testState.ExpireTrashedJobs(structs.MsgTypeTestSetup, 14, now))
+
+	out, err := testState.TrashedJobByID(nil, expired.Namespace, expired.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	iter, err := testState.TrashedJobs(nil)
+	require.NoError(t, err)
+	raw := iter.Next()
+	require.NotNil(t, raw)
+	require.Equal(t, valid.ID, raw.(*structs.TrashedJob).ID)
+	require.Nil(t, iter.Next())
+
+	index, err := testState.Index(TableJobTrash)
+	require.NoError(t, err)
+	require.Equal(t, uint64(14), index)
+}
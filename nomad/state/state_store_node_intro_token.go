@@ -0,0 +1,125 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertNodeIntroToken is used to create or update a node introduction
+// token. Validating that we're not upserting an already-expired token is made
+// the responsibility of the caller to facilitate testing.
+func (s *StateStore) UpsertNodeIntroToken(msgType structs.MessageType, index uint64, token *structs.NodeIntroToken) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// we expect the RPC call to set the ExpiresAt
+	if token.ExpiresAt.IsZero() {
+		return fmt.Errorf("node introduction token must have an ExpiresAt time")
+	}
+
+	token.CreateIndex = index
+	token.ModifyIndex = index
+
+	if err := txn.Insert(TableNodeIntroTokens, token); err != nil {
+		return fmt.Errorf("upserting node introduction token failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodeIntroTokens, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// ConsumeNodeIntroToken deletes the node introduction token with the given
+// accessor ID. It returns an error if the token doesn't exist, so a token can
+// only be consumed once even if it's used by concurrent registrations.
+func (s *StateStore) ConsumeNodeIntroToken(msgType structs.MessageType, index uint64, accessorID string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First(TableNodeIntroTokens, indexID, accessorID)
+	if err != nil {
+		return fmt.Errorf("node introduction token lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("node introduction token %s not found", accessorID)
+	}
+
+	if err := txn.Delete(TableNodeIntroTokens, existing); err != nil {
+		return fmt.Errorf("deleting node introduction token failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodeIntroTokens, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// ExpireNodeIntroTokens deletes the node introduction tokens which expired
+// before the given timestamp.
+func (s *StateStore) ExpireNodeIntroTokens(msgType structs.MessageType, index uint64, timestamp time.Time) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	iter, err := txn.Get(TableNodeIntroTokens, indexID)
+	if err != nil {
+		return fmt.Errorf("node introduction token lookup failed: %v", err)
+	}
+
+	// Collect the expired tokens before deleting them, since the iterator
+	// can't be used while the table is modified.
+	var expired []*structs.NodeIntroToken
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.NodeIntroToken)
+		if token.ExpiresAt.Before(timestamp) {
+			expired = append(expired, token)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	for _, token := range expired {
+		if err := txn.Delete(TableNodeIntroTokens, token); err != nil {
+			return fmt.Errorf("deleting node introduction token failed: %v", err)
+		}
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodeIntroTokens, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// NodeIntroTokenBySecret is used to lookup a node introduction token by its
+// secret ID.
+func (s *StateStore) NodeIntroTokenBySecret(ws memdb.WatchSet, secret string) (*structs.NodeIntroToken, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("node introduction token lookup failed: missing secret")
+	}
+
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableNodeIntroTokens, indexSecret, secret)
+	if err != nil {
+		return nil, fmt.Errorf("node introduction token lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.NodeIntroToken), nil
+	}
+	return nil, nil
+}
+
+// NodeIntroTokens returns an iterator over all the node introduction tokens.
+func (s *StateStore) NodeIntroTokens(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableNodeIntroTokens, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("node introduction token lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
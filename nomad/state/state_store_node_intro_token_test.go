@@ -0,0 +1,64 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_NodeIntroTokens(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	newToken := func(expiresAt time.Time) *structs.NodeIntroToken {
+		return &structs.NodeIntroToken{
+			SecretID:   uuid.Generate(),
+			AccessorID: uuid.Generate(),
+			ExpiresAt:  expiresAt,
+		}
+	}
+
+	// Tokens must have an expiry
+	require.Error(t, testState.UpsertNodeIntroToken(structs.MsgTypeTestSetup, 10, newToken(time.Time{})))
+
+	now := time.Now()
+	valid := newToken(now.Add(time.Hour))
+	expired := newToken(now.Add(-time.Minute))
+	require.NoError(t, testState.UpsertNodeIntroToken(structs.MsgTypeTestSetup, 10, valid))
+	require.NoError(t, testState.UpsertNodeIntroToken(structs.MsgTypeTestSetup, 11, expired))
+
+	out, err := testState.NodeIntroTokenBySecret(memdb.NewWatchSet(), valid.SecretID)
+	require.NoError(t, err)
+	require.Equal(t, valid.AccessorID, out.AccessorID)
+	require.Equal(t, uint64(10), out.CreateIndex)
+
+	index, err := testState.Index(TableNodeIntroTokens)
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), index)
+
+	// Expiring the tokens only deletes the expired token
+	require.NoError(t, testState.ExpireNodeIntroTokens(structs.MsgTypeTestSetup, 12, now))
+	out, err = testState.NodeIntroTokenBySecret(nil, expired.SecretID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+	out, err = testState.NodeIntroTokenBySecret(nil, valid.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// A token can only be consumed once
+	require.NoError(t, testState.ConsumeNodeIntroToken(structs.MsgTypeTestSetup, 13, valid.AccessorID))
+	require.Error(t, testState.ConsumeNodeIntroToken(structs.MsgTypeTestSetup, 14, valid.AccessorID))
+
+	iter, err := testState.NodeIntroTokens(nil)
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+
+	index, err = testState.Index(TableNodeIntroTokens)
+	require.NoError(t, err)
+	require.Equal(t, uint64(13), index)
+}
@@ -224,3 +224,21 @@ func (r *StateRestore) RootKeyMetaRestore(quota *structs.RootKeyMeta) error {
 	}
 	return nil
 }
+
+// NodeIntroTokenRestore is used to restore a single node introduction token
+// into the node_intro_tokens table.
+func (r *StateRestore) NodeIntroTokenRestore(token *structs.NodeIntroToken) error {
+	if err := r.txn.Insert(TableNodeIntroTokens, token); err != nil {
+		return fmt.Errorf("node introduction token insert failed: %v", err)
+	}
+	return nil
+}
+
+// TrashedJobRestore is used to restore a single purged job into the job_trash
+// table.
+func (r *StateRestore) TrashedJobRestore(trashed *structs.TrashedJob) error {
+	if err := r.txn.Insert(TableJobTrash, trashed); err != nil {
+		return fmt.Errorf("trashed job insert failed: %v", err)
+	}
+	return nil
+}
@@ -285,6 +285,74 @@ func (s *StateStore) DeleteSecureVariableTxn(index uint64, namespace, path strin
 	return nil
 }
 
+// SecureVariablesTxn applies the operations of a secure variables
+// transaction atomically. If the check index of any operation doesn't match,
+// no operation is applied and the conflicting secure variables are returned
+// at the position of their operation, with a zero-valued secure variable
+// when it doesn't exist.
+func (s *StateStore) SecureVariablesTxn(msgType structs.MessageType, index uint64, ops []*structs.SecureVariableEncryptedTxnOp) ([]*structs.SecureVariableEncrypted, error) {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	var conflicts []*structs.SecureVariableEncrypted
+	for i, op := range ops {
+		if op.CheckIndex == nil {
+			continue
+		}
+
+		raw, err := txn.First(TableSecureVariables, indexID, op.Var.Namespace, op.Var.Path)
+		if err != nil {
+			return nil, fmt.Errorf("secure variable lookup failed: %v", err)
+		}
+
+		var conflict *structs.SecureVariableEncrypted
+		if raw == nil && *op.CheckIndex != 0 {
+			conflict = &structs.SecureVariableEncrypted{
+				SecureVariableMetadata: structs.SecureVariableMetadata{
+					Namespace: op.Var.Namespace,
+					Path:      op.Var.Path,
+				},
+			}
+		} else if raw != nil && raw.(*structs.SecureVariableEncrypted).ModifyIndex != *op.CheckIndex {
+			conflict = raw.(*structs.SecureVariableEncrypted)
+		}
+
+		if conflict != nil {
+			if conflicts == nil {
+				conflicts = make([]*structs.SecureVariableEncrypted, len(ops))
+			}
+			conflicts[i] = conflict
+		}
+	}
+	if conflicts != nil {
+		return conflicts, nil
+	}
+
+	for _, op := range ops {
+		switch op.Op {
+		case structs.SecureVariableOpSet:
+			// The table index is updated regardless of whether the
+			// variable changed, since deletes update it too.
+			var updated bool
+			if err := s.upsertSecureVariableImpl(index, txn, op.Var, &updated); err != nil {
+				return nil, err
+			}
+		case structs.SecureVariableOpDelete:
+			if err := s.DeleteSecureVariableTxn(index, op.Var.Namespace, op.Var.Path, txn); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown secure variable operation %q", op.Op)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableSecureVariables, index}); err != nil {
+		return nil, fmt.Errorf("index update failed: %v", err)
+	}
+
+	return nil, txn.Commit()
+}
+
 // SecureVariablesQuotas queries all the quotas and is used only for
 // snapshot/restore and key rotation
 func (s *StateStore) SecureVariablesQuotas(ws memdb.WatchSet) (memdb.ResultIterator, error) {
@@ -10,6 +10,7 @@ import (
 
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -314,6 +315,73 @@ func TestStateStore_DeleteSecureVariable(t *testing.T) {
 	})
 }
 
+func TestStateStore_SecureVariablesTxn(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	ws := memdb.NewWatchSet()
+
+	svs, _ := mockSecureVariables(2)
+	require.NoError(t, testState.UpsertSecureVariables(structs.MsgTypeTestSetup, 10, svs))
+	existing, deleted := svs[0], svs[1]
+
+	updated := existing.Copy()
+	updated.Data = []byte("updated")
+	created := mock.SecureVariableEncrypted()
+	deleteVar := &structs.SecureVariableEncrypted{SecureVariableMetadata: deleted.SecureVariableMetadata}
+
+	// A mismatched check index prevents the whole transaction
+	conflicts, err := testState.SecureVariablesTxn(structs.MsgTypeTestSetup, 20,
+		[]*structs.SecureVariableEncryptedTxnOp{
+			{Op: structs.SecureVariableOpSet, Var: &updated, CheckIndex: helper.Uint64ToPtr(10)},
+			{Op: structs.SecureVariableOpSet, Var: created, CheckIndex: helper.Uint64ToPtr(5)},
+			{Op: structs.SecureVariableOpDelete, Var: deleteVar, CheckIndex: helper.Uint64ToPtr(9)},
+		})
+	require.NoError(t, err)
+	require.Len(t, conflicts, 3)
+	require.Nil(t, conflicts[0])
+	require.Equal(t, created.Path, conflicts[1].Path)
+	require.Zero(t, conflicts[1].ModifyIndex)
+	require.Equal(t, uint64(10), conflicts[2].ModifyIndex)
+
+	index, err := testState.Index(TableSecureVariables)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), index)
+	got, err := testState.GetSecureVariable(ws, created.Namespace, created.Path)
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	// Matching check indexes apply all the operations
+	conflicts, err = testState.SecureVariablesTxn(structs.MsgTypeTestSetup, 20,
+		[]*structs.SecureVariableEncryptedTxnOp{
+			{Op: structs.SecureVariableOpSet, Var: &updated, CheckIndex: helper.Uint64ToPtr(10)},
+			{Op: structs.SecureVariableOpSet, Var: created, CheckIndex: helper.Uint64ToPtr(0)},
+			{Op: structs.SecureVariableOpDelete, Var: deleteVar},
+		})
+	require.NoError(t, err)
+	require.Nil(t, conflicts)
+
+	index, err = testState.Index(TableSecureVariables)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+
+	got, err = testState.GetSecureVariable(ws, existing.Namespace, existing.Path)
+	require.NoError(t, err)
+	require.Equal(t, []byte("updated"), got.Data)
+	require.Equal(t, uint64(20), got.ModifyIndex)
+
+	got, err = testState.GetSecureVariable(ws, created.Namespace, created.Path)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), got.CreateIndex)
+
+	got, err = testState.GetSecureVariable(ws, deleted.Namespace, deleted.Path)
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	quota, err := testState.SecureVariablesQuotaByNamespace(ws, structs.DefaultNamespace)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len("updated")+len(created.Data)), quota.Size)
+}
+
 func TestStateStore_GetSecureVariables(t *testing.T) {
 	ci.Parallel(t)
 	testState := testStateStore(t)
@@ -312,6 +312,59 @@ func TestStateStore_DeleteServiceRegistrationByNodeID(t *testing.T) {
 	require.Equal(t, 0, delete2Count, "unexpected number of registrations in table")
 }
 
+func TestStateStore_ReconcileServiceRegistrations(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Generate some test services, of which only the first one has its
+	// allocation in state.
+	services := mock.ServiceRegistrations()
+	alloc := mock.Alloc()
+	alloc.ID = services[0].AllocID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 10, alloc.Job))
+	require.NoError(t, testState.UpsertAllocs(structs.MsgTypeTestSetup, 20, []*structs.Allocation{alloc}))
+	require.NoError(t, testState.UpsertServiceRegistrations(structs.MsgTypeTestSetup, 30, services))
+
+	// The registration of the missing allocation is deleted.
+	changes, err := testState.ServiceRegistrationChanges()
+	require.NoError(t, err)
+	require.Equal(t, []*structs.ReconcileChange{{
+		Type:      structs.DiffTypeDeleted,
+		Namespace: services[1].Namespace,
+		ID:        services[1].ID,
+	}}, changes)
+
+	require.NoError(t, testState.ReconcileServiceRegistrations(structs.MsgTypeTestSetup, 40))
+
+	out, err := testState.GetServiceRegistrationByID(nil, services[1].Namespace, services[1].ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+	out, err = testState.GetServiceRegistrationByID(nil, services[0].Namespace, services[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	actualIndex, err := testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), actualIndex)
+
+	// Nothing changes if all the registrations are reconciled.
+	require.NoError(t, testState.ReconcileServiceRegistrations(structs.MsgTypeTestSetup, 50))
+	actualIndex, err = testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), actualIndex)
+
+	// The registration of an allocation terminal on the client is deleted.
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, testState.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 60, []*structs.Allocation{update}))
+
+	changes, err = testState.ServiceRegistrationChanges()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, services[0].ID, changes[0].ID)
+}
+
 func TestStateStore_GetServiceRegistrations(t *testing.T) {
 	ci.Parallel(t)
 	testState := testStateStore(t)
@@ -145,6 +145,76 @@ func (s *StateStore) DeleteServiceRegistrationByNodeID(
 	return txn.Commit()
 }
 
+// ReconcileServiceRegistrations deletes the service registrations of
+// allocations which are no longer in state or are terminal on the client.
+// Such registrations are normally removed by the client, but can remain when
+// a client is lost or state is restored from a snapshot.
+func (s *StateStore) ReconcileServiceRegistrations(msgType structs.MessageType, index uint64) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	orphans, err := s.orphanedServiceRegistrations(txn)
+	if err != nil {
+		return err
+	}
+	if len(orphans) == 0 {
+		return nil
+	}
+
+	for _, service := range orphans {
+		if err := txn.Delete(TableServiceRegistrations, service); err != nil {
+			return fmt.Errorf("service registration deletion failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableServiceRegistrations, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// ServiceRegistrationChanges returns the changes ReconcileServiceRegistrations
+// would make to the service registrations.
+func (s *StateStore) ServiceRegistrationChanges() ([]*structs.ReconcileChange, error) {
+	orphans, err := s.orphanedServiceRegistrations(s.db.ReadTxn())
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*structs.ReconcileChange, 0, len(orphans))
+	for _, service := range orphans {
+		changes = append(changes, structs.NewReconcileChange(service.Namespace, service.ID, service, nil))
+	}
+	return changes, nil
+}
+
+// orphanedServiceRegistrations returns the service registrations whose
+// allocation is missing or terminal on the client.
+func (s *StateStore) orphanedServiceRegistrations(txn ReadTxn) ([]*structs.ServiceRegistration, error) {
+	iter, err := txn.Get(TableServiceRegistrations, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*structs.ServiceRegistration
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		service := raw.(*structs.ServiceRegistration)
+
+		alloc, err := txn.First("allocs", "id", service.AllocID)
+		if err != nil {
+			return nil, fmt.Errorf("alloc lookup failed: %v", err)
+		}
+		if alloc == nil || alloc.(*structs.Allocation).ClientTerminalStatus() {
+			out = append(out, service)
+		}
+	}
+	return out, nil
+}
+
 // GetServiceRegistrations returns an iterator that contains all service
 // registrations stored within state. This is primarily useful when performing
 // listings which use the namespace wildcard operator. The caller is
@@ -166,6 +166,7 @@ func TestStateStore_UpsertPlanResults_AllocationsDenormalized(t *testing.T) {
 	stoppedAllocDiff := &structs.AllocationDiff{
 		ID:                 stoppedAlloc.ID,
 		DesiredDescription: "desired desc",
+		StopReason:         structs.AllocStopReasonLost,
 		ClientStatus:       structs.AllocClientStatusLost,
 	}
 	preemptedAlloc := mock.Alloc()
@@ -219,6 +220,7 @@ func TestStateStore_UpsertPlanResults_AllocationsDenormalized(t *testing.T) {
 	updatedStoppedAlloc, err := state.AllocByID(ws, stoppedAlloc.ID)
 	require.NoError(err)
 	assert.Equal(stoppedAllocDiff.DesiredDescription, updatedStoppedAlloc.DesiredDescription)
+	assert.Equal(stoppedAllocDiff.StopReason, updatedStoppedAlloc.StopReason)
 	assert.Equal(structs.AllocDesiredStatusStop, updatedStoppedAlloc.DesiredStatus)
 	assert.Equal(stoppedAllocDiff.ClientStatus, updatedStoppedAlloc.ClientStatus)
 	assert.Equal(planModifyIndex, updatedStoppedAlloc.AllocModifyIndex)
@@ -1349,6 +1351,53 @@ func TestStateStore_UpdateNodeStatus_Node(t *testing.T) {
 	require.False(watchFired(ws))
 }
 
+func TestStateStore_BatchUpdateNodeStatus(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	state := testStateStore(t)
+	n1, n2 := mock.Node(), mock.Node()
+
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 800, n1))
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 801, n2))
+
+	ws := memdb.NewWatchSet()
+	_, err := state.NodeByID(ws, n1.ID)
+	require.NoError(err)
+
+	event := &structs.NodeEvent{
+		Message:   "Node heartbeat missed",
+		Subsystem: structs.NodeEventSubsystemCluster,
+		Timestamp: time.Now(),
+	}
+
+	// Updates of nodes that no longer exist are skipped
+	updates := []*structs.NodeUpdateStatusRequest{
+		{NodeID: n1.ID, Status: structs.NodeStatusDown, UpdatedAt: 70, NodeEvent: event},
+		{NodeID: n2.ID, Status: structs.NodeStatusDown, UpdatedAt: 70},
+		{NodeID: uuid.Generate(), Status: structs.NodeStatusDown, UpdatedAt: 70},
+	}
+	require.NoError(state.BatchUpdateNodeStatus(structs.MsgTypeTestSetup, 802, updates))
+	require.True(watchFired(ws))
+
+	for _, id := range []string{n1.ID, n2.ID} {
+		out, err := state.NodeByID(nil, id)
+		require.NoError(err)
+		require.Equal(structs.NodeStatusDown, out.Status)
+		require.EqualValues(802, out.ModifyIndex)
+		require.EqualValues(70, out.StatusUpdatedAt)
+	}
+
+	out, err := state.NodeByID(nil, n1.ID)
+	require.NoError(err)
+	require.Len(out.Events, 2)
+	require.Equal(event.Message, out.Events[1].Message)
+
+	index, err := state.Index("nodes")
+	require.NoError(err)
+	require.EqualValues(802, index)
+}
+
 func TestStateStore_BatchUpdateNodeDrain(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -5138,6 +5187,164 @@ func TestStateStore_UpdateMultipleAllocsFromClient(t *testing.T) {
 	}
 }
 
+// TestStateStore_UpdateAllocsFromClient_SharesJob asserts that client updates
+// do not copy the immutable job of the allocation.
+func TestStateStore_UpdateAllocsFromClient_SharesJob(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	alloc := mock.Alloc()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	update := &structs.Allocation{
+		ID:           alloc.ID,
+		ClientStatus: structs.AllocClientStatusRunning,
+		JobID:        alloc.JobID,
+		TaskGroup:    alloc.TaskGroup,
+	}
+	require.NoError(t, state.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{update}))
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.Equal(t, structs.AllocClientStatusRunning, out.ClientStatus)
+	require.True(t, out.Job == alloc.Job, "expected the job to be shared")
+}
+
+// BenchmarkStateStore_UpdateAllocsFromClient measures the allocations made by
+// client updates to the allocations of a large job.
+func BenchmarkStateStore_UpdateAllocsFromClient(b *testing.B) {
+	state := TestStateStore(b)
+
+	job := mock.Job()
+	for i := 0; i < 50; i++ {
+		tg := job.TaskGroups[0].Copy()
+		tg.Name = fmt.Sprintf("web-%d", i)
+		job.TaskGroups = append(job.TaskGroups, tg)
+	}
+	require.NoError(b, state.UpsertJob(structs.MsgTypeTestSetup, 999, job))
+
+	allocs := make([]*structs.Allocation, 100)
+	for i := range allocs {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		allocs[i] = alloc
+	}
+	require.NoError(b, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, allocs))
+
+	updates := make([]*structs.Allocation, len(allocs))
+	for i, alloc := range allocs {
+		updates[i] = &structs.Allocation{
+			ID:           alloc.ID,
+			ClientStatus: structs.AllocClientStatusRunning,
+			JobID:        alloc.JobID,
+			TaskGroup:    alloc.TaskGroup,
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := state.UpdateAllocsFromClient(structs.MsgTypeTestSetup, uint64(1001+i), updates); err != nil {
+			b.Fatalf("err: %v", err)
+		}
+	}
+}
+
+// TestStateStore_UpsertPlanResults_SharesJob asserts that stopping allocations
+// in a plan does not copy their immutable job.
+func TestStateStore_UpsertPlanResults_SharesJob(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	alloc := mock.Alloc()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	eval := mock.Eval()
+	eval.JobID = alloc.JobID
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval}))
+
+	res := &structs.ApplyPlanResultsRequest{
+		AllocUpdateRequest: structs.AllocUpdateRequest{
+			AllocsStopped: []*structs.AllocationDiff{{
+				ID:                 alloc.ID,
+				DesiredDescription: "stopped",
+			}},
+			Job: alloc.Job,
+		},
+		EvalID: eval.ID,
+	}
+	require.NoError(t, state.UpsertPlanResults(structs.MsgTypeTestSetup, 1002, res))
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.Equal(t, structs.AllocDesiredStatusStop, out.DesiredStatus)
+	require.True(t, out.Job == alloc.Job, "expected the job to be shared")
+}
+
+// BenchmarkStateStore_UpsertPlanResults measures the allocations made by
+// applying a plan that updates and stops the allocations of a large job.
+func BenchmarkStateStore_UpsertPlanResults(b *testing.B) {
+	state := TestStateStore(b)
+
+	job := mock.Job()
+	for i := 0; i < 50; i++ {
+		tg := job.TaskGroups[0].Copy()
+		tg.Name = fmt.Sprintf("web-%d", i)
+		job.TaskGroups = append(job.TaskGroups, tg)
+	}
+	require.NoError(b, state.UpsertJob(structs.MsgTypeTestSetup, 999, job))
+
+	allocs := make([]*structs.Allocation, 200)
+	for i := range allocs {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		allocs[i] = alloc
+	}
+	require.NoError(b, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, allocs))
+
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	require.NoError(b, state.UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval}))
+
+	// Update the first half of the allocations in place and stop the rest,
+	// with normalized allocations as sent by the plan applier
+	var updated []*structs.Allocation
+	var stopped []*structs.AllocationDiff
+	for i, alloc := range allocs {
+		if i < len(allocs)/2 {
+			update := alloc.CopySkipJob()
+			update.Job = nil
+			updated = append(updated, update)
+			continue
+		}
+		stopped = append(stopped, &structs.AllocationDiff{
+			ID:                 alloc.ID,
+			DesiredDescription: "stopped",
+			ClientStatus:       structs.AllocClientStatusLost,
+		})
+	}
+	res := &structs.ApplyPlanResultsRequest{
+		AllocUpdateRequest: structs.AllocUpdateRequest{
+			AllocsUpdated: updated,
+			AllocsStopped: stopped,
+			Job:           job,
+		},
+		EvalID: eval.ID,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := state.UpsertPlanResults(structs.MsgTypeTestSetup, uint64(1002+i), res); err != nil {
+			b.Fatalf("err: %v", err)
+		}
+	}
+}
+
 func TestStateStore_UpdateAllocsFromClient_Deployment(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -5958,6 +6165,119 @@ func TestStateStore_ReconcileJobSummary(t *testing.T) {
 	}
 }
 
+func TestStateStore_JobSummaryChanges(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	alloc := mock.Alloc()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 100, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 110, []*structs.Allocation{alloc}))
+
+	// Summaries matching the allocations don't change, whatever their indexes
+	changes, err := state.JobSummaryChanges()
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// Make the summary drift from the allocations
+	summary, err := state.JobSummaryByID(nil, alloc.Namespace, alloc.JobID)
+	require.NoError(t, err)
+	summary = summary.Copy()
+	tgSummary := summary.Summary["web"]
+	tgSummary.Starting = 0
+	tgSummary.Running = 3
+	summary.Summary["web"] = tgSummary
+	require.NoError(t, state.UpsertJobSummary(120, summary))
+
+	changes, err = state.JobSummaryChanges()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, structs.DiffTypeEdited, changes[0].Type)
+	require.Equal(t, alloc.JobID, changes[0].ID)
+	require.ElementsMatch(t, []*structs.FieldDiff{
+		{Type: structs.DiffTypeEdited, Name: "Summary[web].Running", Old: "3", New: "0"},
+		{Type: structs.DiffTypeEdited, Name: "Summary[web].Starting", Old: "0", New: "1"},
+	}, changes[0].Fields)
+
+	// Computing the changes doesn't modify the summary
+	out, err := state.JobSummaryByID(nil, alloc.Namespace, alloc.JobID)
+	require.NoError(t, err)
+	require.Equal(t, 3, out.Summary["web"].Running)
+
+	// Missing summaries are created
+	require.NoError(t, state.DeleteJobSummary(130, alloc.Namespace, alloc.JobID))
+	changes, err = state.JobSummaryChanges()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, structs.DiffTypeAdded, changes[0].Type)
+}
+
+func TestStateStore_ReconcileDeployments(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 100, job))
+
+	d := mock.Deployment()
+	d.JobID = job.ID
+	require.NoError(t, state.UpsertDeployment(110, d))
+
+	// A terminal deployment with drifting counts is left as is
+	done := mock.Deployment()
+	done.JobID = job.ID
+	done.Status = structs.DeploymentStatusSuccessful
+	done.TaskGroups["web"].PlacedAllocs = 5
+	require.NoError(t, state.UpsertDeployment(111, done))
+
+	healthy := mock.Alloc()
+	healthy.JobID = job.ID
+	healthy.Job = job
+	healthy.DeploymentID = d.ID
+	healthy.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+
+	unhealthy := healthy.Copy()
+	unhealthy.ID = uuid.Generate()
+	unhealthy.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(false)}
+
+	pending := healthy.Copy()
+	pending.ID = uuid.Generate()
+	pending.DeploymentStatus = nil
+
+	// The allocations are placed with their health set, so the deployment
+	// only counts their placements
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 120,
+		[]*structs.Allocation{healthy, unhealthy, pending}))
+
+	changes, err := state.DeploymentChanges()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, structs.DiffTypeEdited, changes[0].Type)
+	require.Equal(t, d.ID, changes[0].ID)
+	require.ElementsMatch(t, []*structs.FieldDiff{
+		{Type: structs.DiffTypeEdited, Name: "TaskGroups[web].HealthyAllocs", Old: "0", New: "1"},
+		{Type: structs.DiffTypeEdited, Name: "TaskGroups[web].UnhealthyAllocs", Old: "0", New: "1"},
+	}, changes[0].Fields)
+
+	require.NoError(t, state.ReconcileDeployments(structs.MsgTypeTestSetup, 130))
+
+	out, err := state.DeploymentByID(nil, d.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(130), out.ModifyIndex)
+	require.Equal(t, 3, out.TaskGroups["web"].PlacedAllocs)
+	require.Equal(t, 1, out.TaskGroups["web"].HealthyAllocs)
+	require.Equal(t, 1, out.TaskGroups["web"].UnhealthyAllocs)
+
+	out, err = state.DeploymentByID(nil, done.ID)
+	require.NoError(t, err)
+	require.Equal(t, 5, out.TaskGroups["web"].PlacedAllocs)
+
+	changes, err = state.DeploymentChanges()
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
 func TestStateStore_ReconcileParentJobSummary(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -7331,6 +7651,36 @@ func TestStateStore_UpdateJobStability(t *testing.T) {
 	require.False(t, jout.Stable)
 }
 
+func TestStateStore_UpdateJobFreeze(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1, job))
+
+	// Freeze the job, which must not create a new version
+	require.NoError(t, state.UpdateJobFreeze(2, job.Namespace, job.ID, true))
+
+	jout, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.True(t, jout.Frozen)
+	require.Equal(t, uint64(0), jout.Version)
+	require.Equal(t, uint64(2), jout.ModifyIndex)
+	require.Equal(t, uint64(1), jout.JobModifyIndex)
+
+	// Unfreeze the job
+	require.NoError(t, state.UpdateJobFreeze(3, job.Namespace, job.ID, false))
+
+	jout, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.False(t, jout.Frozen)
+
+	// Freezing a missing job fails
+	err = state.UpdateJobFreeze(4, job.Namespace, "missing", true)
+	require.Error(t, err)
+}
+
 // Test that nonexistent deployment can't be promoted
 func TestStateStore_UpsertDeploymentPromotion_Nonexistent(t *testing.T) {
 	ci.Parallel(t)
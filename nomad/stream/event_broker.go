@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/armon/go-metrics"
 	"github.com/hashicorp/go-memdb"
@@ -20,6 +21,10 @@ const (
 	ACLCheckNodeRead   = "node-read"
 	ACLCheckManagement = "management"
 	aclCacheSize       = 32
+
+	// metricsInterval is the interval between two emissions of the metrics
+	// of the subscribers.
+	metricsInterval = 10 * time.Second
 )
 
 type EventBrokerCfg struct {
@@ -45,6 +50,9 @@ type EventBroker struct {
 
 	aclCh chan *structs.Event
 
+	// metrics counts the published events and the closed subscriptions
+	metrics *brokerMetrics
+
 	logger hclog.Logger
 }
 
@@ -68,6 +76,7 @@ func NewEventBroker(ctx context.Context, aclDelegate ACLDelegate, cfg EventBroke
 	}
 
 	buffer := newEventBuffer(cfg.EventBufferSize)
+	brokerMetrics := newBrokerMetrics()
 	e := &EventBroker{
 		logger:      cfg.Logger.Named("event_broker"),
 		eventBuf:    buffer,
@@ -75,13 +84,16 @@ func NewEventBroker(ctx context.Context, aclDelegate ACLDelegate, cfg EventBroke
 		aclCh:       make(chan *structs.Event, 10),
 		aclDelegate: aclDelegate,
 		aclCache:    aclCache,
+		metrics:     brokerMetrics,
 		subscriptions: &subscriptions{
 			byToken: make(map[string]map[*SubscribeRequest]*Subscription),
+			metrics: brokerMetrics,
 		},
 	}
 
 	go e.handleUpdates(ctx)
 	go e.handleACLUpdates(ctx)
+	go e.handleMetrics(ctx)
 
 	return e, nil
 }
@@ -159,8 +171,13 @@ func (e *EventBroker) Subscribe(req *SubscribeRequest) (*Subscription, error) {
 	close(start.link.nextCh)
 
 	sub := newSubscription(req, start, e.subscriptions.unsubscribeFn(req))
+	sub.metrics = e.metrics
+	if head.Events != nil {
+		sub.index = head.Events.Index
+	}
 
 	e.subscriptions.add(req, sub)
+	e.metrics.subscribe()
 	return sub, nil
 }
 
@@ -177,6 +194,22 @@ func (e *EventBroker) handleUpdates(ctx context.Context) {
 			return
 		case update := <-e.publishCh:
 			e.eventBuf.Append(update)
+			e.metrics.publish(update)
+		}
+	}
+}
+
+// handleMetrics periodically emits the metrics of the subscribers.
+func (e *EventBroker) handleMetrics(ctx context.Context) {
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.emitMetrics()
 		}
 	}
 }
@@ -318,10 +351,13 @@ func aclAllowsSubscription(aclObj *acl.ACL, subReq *SubscribeRequest) bool {
 	return true
 }
 
-func (s *Subscription) forceClose() {
+// forceClose closes the subscription and returns whether it was open.
+func (s *Subscription) forceClose() bool {
 	if atomic.CompareAndSwapUint32(&s.state, subscriptionStateOpen, subscriptionStateClosed) {
 		close(s.forceClosed)
+		return true
 	}
+	return false
 }
 
 type subscriptions struct {
@@ -335,6 +371,9 @@ type subscriptions struct {
 	// reloaded.
 	// A subscription may be unsubscribed by using the pointer to the request.
 	byToken map[string]map[*SubscribeRequest]*Subscription
+
+	// metrics counts the subscriptions closed because of their token
+	metrics *brokerMetrics
 }
 
 func (s *subscriptions) add(req *SubscribeRequest, sub *Subscription) {
@@ -356,7 +395,9 @@ func (s *subscriptions) closeSubscriptionsForTokens(tokenSecretIDs []string) {
 	for _, secretID := range tokenSecretIDs {
 		if subs, ok := s.byToken[secretID]; ok {
 			for _, sub := range subs {
-				sub.forceClose()
+				if sub.forceClose() {
+					s.metrics.close()
+				}
 			}
 		}
 	}
@@ -367,8 +408,8 @@ func (s *subscriptions) closeSubscriptionFunc(tokenSecretID string, fn func(*Sub
 	defer s.mu.RUnlock()
 
 	for _, sub := range s.byToken[tokenSecretID] {
-		if fn(sub) {
-			sub.forceClose()
+		if fn(sub) && sub.forceClose() {
+			s.metrics.close()
 		}
 	}
 }
@@ -159,6 +159,7 @@ func TestEventBroker_handleACLUpdates_TokenDeleted(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, ErrSubscriptionClosed, err)
 	require.Equal(t, structs.Events{}, out)
+	require.Equal(t, uint64(1), publisher.Metrics().ClosedSubscriptions)
 }
 
 type fakeACLDelegate struct {
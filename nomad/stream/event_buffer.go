@@ -183,6 +183,10 @@ func (b *eventBuffer) Len() int {
 	return int(atomic.LoadInt64(b.size))
 }
 
+// errEventDropped is returned to readers too slow to read the next events
+// before they are dropped from the buffer.
+var errEventDropped = errors.New("event dropped from buffer")
+
 // bufferItem represents a set of events published by a single raft operation.
 // The first item returned by a newly constructed buffer will have nil Events.
 // It is a sentinel value which is used to wait on the next events via Next.
@@ -267,7 +271,7 @@ func (i *bufferItem) Next(ctx context.Context, forceClose <-chan struct{}) (*buf
 	// between linkCh and droppedCh
 	select {
 	case <-i.link.droppedCh:
-		return nil, fmt.Errorf("event dropped from buffer")
+		return nil, errEventDropped
 	default:
 	}
 
@@ -0,0 +1,146 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// brokerMetrics counts the events published by an EventBroker and the
+// subscriptions it closed, so operators can see what drives the churn of the
+// state and whether subscribers keep up with it. A nil brokerMetrics counts
+// nothing.
+type brokerMetrics struct {
+	mu sync.Mutex
+
+	// published is the number of events published by topic and type
+	published map[structs.Topic]map[string]uint64
+
+	// subscriptions is the number of subscriptions opened
+	subscriptions uint64
+
+	// dropped is the number of subscriptions closed because their next
+	// events were dropped from the buffer before they were read
+	dropped uint64
+
+	// closed is the number of subscriptions closed by the broker because
+	// their token no longer allows them
+	closed uint64
+}
+
+func newBrokerMetrics() *brokerMetrics {
+	return &brokerMetrics{
+		published: make(map[structs.Topic]map[string]uint64),
+	}
+}
+
+func (m *brokerMetrics) publish(events *structs.Events) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, event := range events.Events {
+		byType, ok := m.published[event.Topic]
+		if !ok {
+			byType = make(map[string]uint64)
+			m.published[event.Topic] = byType
+		}
+		byType[event.Type]++
+
+		metrics.IncrCounterWithLabels([]string{"nomad", "event_broker", "events"}, 1, []metrics.Label{
+			{Name: "topic", Value: string(event.Topic)},
+			{Name: "type", Value: event.Type},
+		})
+	}
+}
+
+func (m *brokerMetrics) subscribe() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.subscriptions++
+	m.mu.Unlock()
+	metrics.IncrCounter([]string{"nomad", "event_broker", "subscription", "opened"}, 1)
+}
+
+func (m *brokerMetrics) drop() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.dropped++
+	m.mu.Unlock()
+	metrics.IncrCounter([]string{"nomad", "event_broker", "subscription", "dropped"}, 1)
+}
+
+func (m *brokerMetrics) close() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.closed++
+	m.mu.Unlock()
+	metrics.IncrCounter([]string{"nomad", "event_broker", "subscription", "closed"}, 1)
+}
+
+// Metrics returns the metrics of the events published by the broker and of
+// its subscriptions.
+func (e *EventBroker) Metrics() *structs.EventBrokerMetrics {
+	m := &structs.EventBrokerMetrics{
+		Published: make(map[structs.Topic]map[string]uint64),
+		BufferLen: e.eventBuf.Len(),
+	}
+
+	e.metrics.mu.Lock()
+	for topic, byType := range e.metrics.published {
+		m.Published[topic] = make(map[string]uint64, len(byType))
+		for typ, n := range byType {
+			m.Published[topic][typ] = n
+		}
+	}
+	m.Subscriptions = e.metrics.subscriptions
+	m.DroppedSubscriptions = e.metrics.dropped
+	m.ClosedSubscriptions = e.metrics.closed
+	e.metrics.mu.Unlock()
+
+	if events := e.eventBuf.Tail().Events; events != nil {
+		m.LatestIndex = events.Index
+	}
+	var oldest uint64
+	if events := e.eventBuf.Head().Events; events != nil {
+		oldest = events.Index
+	}
+
+	e.subscriptions.mu.RLock()
+	defer e.subscriptions.mu.RUnlock()
+	for _, subs := range e.subscriptions.byToken {
+		for _, sub := range subs {
+			m.Subscribers++
+
+			index := sub.Index()
+			if index < m.LatestIndex && m.LatestIndex-index > m.MaxSubscriberLag {
+				m.MaxSubscriberLag = m.LatestIndex - index
+			}
+			if index < oldest {
+				m.LaggingSubscribers++
+			}
+		}
+	}
+	return m
+}
+
+// emitMetrics emits the gauges of the subscribers of the broker.
+func (e *EventBroker) emitMetrics() {
+	m := e.Metrics()
+	metrics.SetGauge([]string{"nomad", "event_broker", "subscribers"}, float32(m.Subscribers))
+	metrics.SetGauge([]string{"nomad", "event_broker", "subscribers", "lagging"}, float32(m.LaggingSubscribers))
+	metrics.SetGauge([]string{"nomad", "event_broker", "subscribers", "max_lag"}, float32(m.MaxSubscriberLag))
+}
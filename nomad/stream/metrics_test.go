@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBroker_Metrics(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	publisher, err := NewEventBroker(ctx, nil, EventBrokerCfg{EventBufferSize: 2})
+	require.NoError(t, err)
+
+	slow, err := publisher.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{structs.TopicAll: {"*"}},
+	})
+	require.NoError(t, err)
+	defer slow.Unsubscribe()
+
+	for i := uint64(1); i <= 4; i++ {
+		publisher.Publish(&structs.Events{Index: i, Events: []structs.Event{
+			{Topic: structs.TopicJob, Type: structs.TypeJobRegistered},
+			{Topic: structs.TopicNode, Type: structs.TypeNodeRegistration},
+		}})
+	}
+
+	// The subscriber hasn't read any event, and its next events were dropped
+	// from the buffer
+	var m *structs.EventBrokerMetrics
+	require.Eventually(t, func() bool {
+		m = publisher.Metrics()
+		return m.LatestIndex == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, &structs.EventBrokerMetrics{
+		Published: map[structs.Topic]map[string]uint64{
+			structs.TopicJob:  {structs.TypeJobRegistered: 4},
+			structs.TopicNode: {structs.TypeNodeRegistration: 4},
+		},
+		LatestIndex:        4,
+		BufferLen:          2,
+		Subscribers:        1,
+		LaggingSubscribers: 1,
+		MaxSubscriberLag:   4,
+		Subscriptions:      1,
+	}, m)
+
+	// The subscriber is dropped once it reads its next events
+	_, err = slow.Next(ctx)
+	require.ErrorIs(t, err, errEventDropped)
+
+	// A subscriber keeping up doesn't lag
+	sub, err := publisher.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{structs.TopicJob: {"*"}},
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	for sub.Index() < 4 {
+		_, err := sub.Next(ctx)
+		require.NoError(t, err)
+	}
+
+	slow.Unsubscribe()
+	m = publisher.Metrics()
+	require.Equal(t, 1, m.Subscribers)
+	require.Zero(t, m.LaggingSubscribers)
+	require.Zero(t, m.MaxSubscriberLag)
+	require.Equal(t, uint64(2), m.Subscriptions)
+	require.Equal(t, uint64(1), m.DroppedSubscriptions)
+}
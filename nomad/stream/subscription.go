@@ -25,6 +25,10 @@ var ErrSubscriptionClosed = errors.New("subscription closed by server, client sh
 var ErrACLInvalid = errors.New("Provided ACL token is invalid for requested topics")
 
 type Subscription struct {
+	// index is the index of the events last read by the subscription. It
+	// must be accessed atomically, and is first to be 64-bit aligned.
+	index uint64
+
 	// state must be accessed atomically 0 means open, 1 means closed with reload
 	state uint32
 
@@ -43,6 +47,10 @@ type Subscription struct {
 	// It must be safe to call the function from multiple goroutines and the function
 	// must be idempotent.
 	unsub func()
+
+	// metrics counts the subscription if it is dropped, and is set by
+	// EventBroker.
+	metrics *brokerMetrics
 }
 
 type SubscribeRequest struct {
@@ -78,10 +86,13 @@ func (s *Subscription) Next(ctx context.Context) (structs.Events, error) {
 		switch {
 		case err != nil && atomic.LoadUint32(&s.state) == subscriptionStateClosed:
 			return structs.Events{}, ErrSubscriptionClosed
+		case errors.Is(err, errEventDropped):
+			s.metrics.drop()
+			return structs.Events{}, err
 		case err != nil:
 			return structs.Events{}, err
 		}
-		s.currentItem = next
+		s.setCurrentItem(next)
 
 		events := filter(s.req, next.Events.Events)
 		if len(events) == 0 {
@@ -101,7 +112,7 @@ func (s *Subscription) NextNoBlock() ([]structs.Event, error) {
 		if next == nil {
 			return nil, nil
 		}
-		s.currentItem = next
+		s.setCurrentItem(next)
 
 		events := filter(s.req, next.Events.Events)
 		if len(events) == 0 {
@@ -111,6 +122,21 @@ func (s *Subscription) NextNoBlock() ([]structs.Event, error) {
 	}
 }
 
+// setCurrentItem sets the item the subscription is on, and the index of the
+// events it last read.
+func (s *Subscription) setCurrentItem(item *bufferItem) {
+	s.currentItem = item
+	if item.Events != nil {
+		atomic.StoreUint64(&s.index, item.Events.Index)
+	}
+}
+
+// Index returns the index of the events last read by the subscription,
+// whether or not they matched its topics.
+func (s *Subscription) Index() uint64 {
+	return atomic.LoadUint64(&s.index)
+}
+
 func (s *Subscription) Unsubscribe() {
 	s.unsub()
 }
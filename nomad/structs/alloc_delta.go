@@ -0,0 +1,210 @@
+package structs
+
+import (
+	"reflect"
+)
+
+// AllocDeltaUpdateRequest is used by clients to update the client status of
+// allocations by only sending what changed since the servers last
+// acknowledged their status.
+type AllocDeltaUpdateRequest struct {
+	// Alloc are full client updates of allocations, as sent by
+	// Node.UpdateAlloc, for allocations that have no acknowledged status the
+	// deltas could apply to.
+	Alloc []*Allocation
+
+	// Deltas are the changes of allocations since their acknowledged status.
+	Deltas []*AllocDelta
+
+	WriteRequest
+}
+
+// AllocDeltaUpdateResponse is the response to an AllocDeltaUpdateRequest.
+type AllocDeltaUpdateResponse struct {
+	// Mismatched are the IDs of the allocations whose delta didn't apply to
+	// their status on the servers. Their clients must send them in full.
+	Mismatched []string
+
+	WriteMeta
+}
+
+// AllocDelta is the change of the client status of an allocation. The fields
+// the client is the authority on are sent in full, except for the task
+// states that only include the tasks that changed and their new events.
+type AllocDelta struct {
+	ID                string
+	NodeID            string
+	ClientStatus      string
+	ClientDescription string
+	DeploymentStatus  *AllocDeploymentStatus
+	NetworkStatus     *AllocNetworkStatus
+
+	// TaskStates are the changes of the tasks whose state changed.
+	TaskStates map[string]*TaskStateDelta
+}
+
+// TaskStateDelta is the change of the state of a task.
+type TaskStateDelta struct {
+	// State is the new state of the task, with only its new events.
+	State *TaskState
+
+	// BaseEvents is the number of events of the state the delta applies to,
+	// and BaseEventTime the time of the last of them, used to verify that the
+	// delta applies to the expected state.
+	BaseEvents    int
+	BaseEventTime int64
+
+	// DroppedEvents is the number of the oldest events of the state the delta
+	// applies to that are no longer kept.
+	DroppedEvents int
+}
+
+// NewAllocDelta returns the change of the client update of an allocation from
+// a previous update of it, or nil if the change can't be expressed as a
+// delta, such as when the tasks of the allocation changed.
+func NewAllocDelta(base, alloc *Allocation) *AllocDelta {
+	if base == nil || len(base.TaskStates) != len(alloc.TaskStates) {
+		return nil
+	}
+
+	delta := &AllocDelta{
+		ID:                alloc.ID,
+		NodeID:            alloc.NodeID,
+		ClientStatus:      alloc.ClientStatus,
+		ClientDescription: alloc.ClientDescription,
+		DeploymentStatus:  alloc.DeploymentStatus,
+		NetworkStatus:     alloc.NetworkStatus,
+		TaskStates:        make(map[string]*TaskStateDelta),
+	}
+
+	for name, state := range alloc.TaskStates {
+		baseState, ok := base.TaskStates[name]
+		if !ok || baseState == nil || state == nil {
+			return nil
+		}
+
+		taskDelta := newTaskStateDelta(baseState, state)
+		if taskDelta == nil {
+			return nil
+		}
+		if taskDelta.State == nil {
+			// Unchanged
+			continue
+		}
+		delta.TaskStates[name] = taskDelta
+	}
+
+	return delta
+}
+
+// newTaskStateDelta returns the change of a task state from a previous state,
+// with a nil State if it didn't change, or nil if the events of the state
+// aren't the events of the previous state followed by new events.
+func newTaskStateDelta(base, state *TaskState) *TaskStateDelta {
+	// Find how many of the oldest events of the base were dropped, as the
+	// remaining ones must start the events of the state
+	dropped := -1
+	for k := 0; k <= len(base.Events); k++ {
+		kept := base.Events[k:]
+		if len(kept) > len(state.Events) {
+			continue
+		}
+		if sameTaskEvents(kept, state.Events[:len(kept)]) {
+			dropped = k
+			break
+		}
+	}
+	if dropped < 0 {
+		return nil
+	}
+
+	delta := &TaskStateDelta{
+		BaseEvents:    len(base.Events),
+		DroppedEvents: dropped,
+	}
+	if n := len(base.Events); n > 0 {
+		delta.BaseEventTime = base.Events[n-1].Time
+	}
+
+	newEvents := state.Events[len(base.Events)-dropped:]
+	if len(newEvents) == 0 && dropped == 0 && sameTaskStateFields(base, state) {
+		return delta
+	}
+
+	delta.State = state.Copy()
+	delta.State.Events = newEvents
+	return delta
+}
+
+// sameTaskEvents returns whether two lists of task events are the same
+// events, which are identified by their type and time.
+func sameTaskEvents(a, b []*TaskEvent) bool {
+	for i := range a {
+		if a[i].Type != b[i].Type || a[i].Time != b[i].Time {
+			return false
+		}
+	}
+	return true
+}
+
+// sameTaskStateFields returns whether two task states are equal, apart from
+// their events.
+func sameTaskStateFields(a, b *TaskState) bool {
+	return a.State == b.State &&
+		a.Failed == b.Failed &&
+		a.Restarts == b.Restarts &&
+		a.LastRestart.Equal(b.LastRestart) &&
+		a.StartedAt.Equal(b.StartedAt) &&
+		a.FinishedAt.Equal(b.FinishedAt) &&
+		reflect.DeepEqual(a.TaskHandle, b.TaskHandle)
+}
+
+// Apply returns the client update of the allocation the delta describes,
+// given the current state of the allocation. It returns false if the delta
+// doesn't apply to the task states of the allocation.
+func (d *AllocDelta) Apply(existing *Allocation) (*Allocation, bool) {
+	alloc := &Allocation{
+		ID:                d.ID,
+		NodeID:            d.NodeID,
+		ClientStatus:      d.ClientStatus,
+		ClientDescription: d.ClientDescription,
+		DeploymentStatus:  d.DeploymentStatus,
+		NetworkStatus:     d.NetworkStatus,
+		TaskStates:        make(map[string]*TaskState, len(existing.TaskStates)),
+	}
+
+	for name, state := range existing.TaskStates {
+		alloc.TaskStates[name] = state
+	}
+
+	for name, taskDelta := range d.TaskStates {
+		if taskDelta == nil || taskDelta.State == nil {
+			return nil, false
+		}
+
+		state, ok := existing.TaskStates[name]
+		if !ok || state == nil {
+			if taskDelta.BaseEvents != 0 {
+				return nil, false
+			}
+			state = &TaskState{}
+		}
+
+		n := len(state.Events)
+		if n != taskDelta.BaseEvents || taskDelta.DroppedEvents > n {
+			return nil, false
+		}
+		if n > 0 && state.Events[n-1].Time != taskDelta.BaseEventTime {
+			return nil, false
+		}
+
+		merged := taskDelta.State.Copy()
+		kept := state.Events[taskDelta.DroppedEvents:]
+		merged.Events = make([]*TaskEvent, 0, len(kept)+len(taskDelta.State.Events))
+		merged.Events = append(merged.Events, kept...)
+		merged.Events = append(merged.Events, taskDelta.State.Events...)
+		alloc.TaskStates[name] = merged
+	}
+
+	return alloc, true
+}
@@ -0,0 +1,99 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeltaAlloc(events ...string) *Allocation {
+	state := &TaskState{State: TaskStateRunning}
+	for i, typ := range events {
+		event := NewTaskEvent(typ)
+		event.Time = int64(i + 1)
+		state.Events = append(state.Events, event)
+	}
+	return &Allocation{
+		ID:           "alloc",
+		ClientStatus: AllocClientStatusRunning,
+		TaskStates: map[string]*TaskState{
+			"web":     state,
+			"sidecar": {State: TaskStateRunning},
+		},
+	}
+}
+
+func TestAllocDelta_RoundTrip(t *testing.T) {
+	ci.Parallel(t)
+
+	base := testDeltaAlloc(TaskReceived, TaskStarted)
+
+	// The oldest event is dropped as a new one is added
+	alloc := testDeltaAlloc(TaskReceived, TaskStarted, TaskRestarting)
+	alloc.TaskStates["web"].Events = alloc.TaskStates["web"].Events[1:]
+	alloc.TaskStates["web"].Restarts = 1
+
+	delta := NewAllocDelta(base, alloc)
+	require.NotNil(t, delta)
+
+	// Only the changed task and its new events are sent
+	require.Len(t, delta.TaskStates, 1)
+	webDelta := delta.TaskStates["web"]
+	require.Len(t, webDelta.State.Events, 1)
+	require.Equal(t, TaskRestarting, webDelta.State.Events[0].Type)
+	require.Equal(t, 2, webDelta.BaseEvents)
+	require.EqualValues(t, 2, webDelta.BaseEventTime)
+	require.Equal(t, 1, webDelta.DroppedEvents)
+
+	merged, ok := delta.Apply(base)
+	require.True(t, ok)
+	require.Equal(t, alloc.TaskStates, merged.TaskStates)
+	require.Equal(t, alloc.ClientStatus, merged.ClientStatus)
+}
+
+func TestAllocDelta_Mismatch(t *testing.T) {
+	ci.Parallel(t)
+
+	base := testDeltaAlloc(TaskReceived)
+	alloc := testDeltaAlloc(TaskReceived, TaskStarted)
+	delta := NewAllocDelta(base, alloc)
+	require.NotNil(t, delta)
+
+	// The servers have more events than the base of the delta
+	_, ok := delta.Apply(alloc)
+	require.False(t, ok)
+
+	// The servers have different events than the base of the delta
+	other := testDeltaAlloc(TaskSetup)
+	other.TaskStates["web"].Events[0].Time = 42
+	_, ok = delta.Apply(other)
+	require.False(t, ok)
+}
+
+func TestNewAllocDelta_Unexpressible(t *testing.T) {
+	ci.Parallel(t)
+
+	// No base
+	require.Nil(t, NewAllocDelta(nil, testDeltaAlloc()))
+
+	// Tasks changed
+	alloc := testDeltaAlloc()
+	delete(alloc.TaskStates, "sidecar")
+	require.Nil(t, NewAllocDelta(testDeltaAlloc(), alloc))
+
+	// Events were rewritten rather than appended
+	base := testDeltaAlloc(TaskReceived, TaskStarted)
+	alloc = testDeltaAlloc(TaskReceived, TaskStarted)
+	alloc.TaskStates["web"].Events[1].Time = 42
+	alloc.TaskStates["web"].Events = append(alloc.TaskStates["web"].Events, NewTaskEvent(TaskKilled))
+	delta := NewAllocDelta(base, alloc)
+	require.NotNil(t, delta)
+	require.Equal(t, 2, delta.TaskStates["web"].DroppedEvents)
+	require.Len(t, delta.TaskStates["web"].State.Events, 3)
+
+	// No change
+	delta = NewAllocDelta(testDeltaAlloc(TaskStarted), testDeltaAlloc(TaskStarted))
+	require.NotNil(t, delta)
+	require.Empty(t, delta.TaskStates)
+}
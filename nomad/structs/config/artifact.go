@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"math"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -34,6 +36,20 @@ type ArtifactConfig struct {
 	// S3Timeout is the duration in which an S3 operation must complete or
 	// it will be canceled. Defaults to 30m.
 	S3Timeout *string `hcl:"s3_timeout"`
+
+	// BandwidthLimit is the maximum rate, in bytes per second, at which a
+	// client downloads HTTP and S3 artifacts, shared across all
+	// allocations. Defaults to 0, which is unlimited.
+	BandwidthLimit *string `hcl:"bandwidth_limit"`
+
+	// MaxConcurrentDownloads is the maximum number of artifacts a client
+	// downloads at the same time across all allocations. Defaults to 0,
+	// which is unlimited.
+	MaxConcurrentDownloads *int `hcl:"max_concurrent_downloads"`
+
+	// Fetchers are the artifact fetchers used to download artifacts with a
+	// URL scheme go-getter doesn't support.
+	Fetchers []*ArtifactFetcherConfig `hcl:"fetcher"`
 }
 
 func (a *ArtifactConfig) Copy() *ArtifactConfig {
@@ -60,6 +76,18 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 	if a.S3Timeout != nil {
 		newCopy.S3Timeout = helper.StringToPtr(*a.S3Timeout)
 	}
+	if a.BandwidthLimit != nil {
+		newCopy.BandwidthLimit = helper.StringToPtr(*a.BandwidthLimit)
+	}
+	if a.MaxConcurrentDownloads != nil {
+		newCopy.MaxConcurrentDownloads = helper.IntToPtr(*a.MaxConcurrentDownloads)
+	}
+	if a.Fetchers != nil {
+		newCopy.Fetchers = make([]*ArtifactFetcherConfig, len(a.Fetchers))
+		for i, f := range a.Fetchers {
+			newCopy.Fetchers[i] = f.Copy()
+		}
+	}
 
 	return newCopy
 }
@@ -91,6 +119,15 @@ func (a *ArtifactConfig) Merge(o *ArtifactConfig) *ArtifactConfig {
 	if o.S3Timeout != nil {
 		newCopy.S3Timeout = helper.StringToPtr(*o.S3Timeout)
 	}
+	if o.BandwidthLimit != nil {
+		newCopy.BandwidthLimit = helper.StringToPtr(*o.BandwidthLimit)
+	}
+	if o.MaxConcurrentDownloads != nil {
+		newCopy.MaxConcurrentDownloads = helper.IntToPtr(*o.MaxConcurrentDownloads)
+	}
+	for _, f := range o.Fetchers {
+		newCopy.Fetchers = append(newCopy.Fetchers, f.Copy())
+	}
 
 	return newCopy
 }
@@ -154,6 +191,33 @@ func (a *ArtifactConfig) Validate() error {
 		return fmt.Errorf("s3_timeout must be > 0")
 	}
 
+	if a.BandwidthLimit == nil {
+		return fmt.Errorf("bandwidth_limit must be set")
+	}
+	if v, err := humanize.ParseBytes(*a.BandwidthLimit); err != nil {
+		return fmt.Errorf("bandwidth_limit not a valid size: %w", err)
+	} else if v > math.MaxInt64 {
+		return fmt.Errorf("bandwidth_limit must be < %d but found %d", int64(math.MaxInt64), v)
+	}
+
+	if a.MaxConcurrentDownloads == nil {
+		return fmt.Errorf("max_concurrent_downloads must be set")
+	}
+	if *a.MaxConcurrentDownloads < 0 {
+		return fmt.Errorf("max_concurrent_downloads must be >= 0")
+	}
+
+	schemes := make(map[string]struct{}, len(a.Fetchers))
+	for _, f := range a.Fetchers {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("fetcher[%q] invalid: %w", f.Scheme, err)
+		}
+		if _, ok := schemes[f.Scheme]; ok {
+			return fmt.Errorf("fetcher[%q] is defined more than once", f.Scheme)
+		}
+		schemes[f.Scheme] = struct{}{}
+	}
+
 	return nil
 }
 
@@ -182,5 +246,90 @@ func DefaultArtifactConfig() *ArtifactConfig {
 		// Timeout for S3 operations. Must be long enough to
 		// accommodate large/slow downloads.
 		S3Timeout: helper.StringToPtr("30m"),
+
+		// No limit on the download rate of artifacts.
+		BandwidthLimit: helper.StringToPtr("0"),
+
+		// No limit on the number of concurrent artifact downloads.
+		MaxConcurrentDownloads: helper.IntToPtr(0),
+	}
+}
+
+// builtinArtifactSchemes are the URL schemes of the artifacts downloaded by
+// go-getter, which can't be handled by an artifact fetcher.
+var builtinArtifactSchemes = []string{"git", "hg", "gcs", "s3", "http", "https"}
+
+// ArtifactFetcherConfig is the configuration of an artifact fetcher. An
+// artifact fetcher is a binary run by the client to download the artifacts
+// with a URL scheme go-getter doesn't support, for example from an internal
+// artifact store.
+type ArtifactFetcherConfig struct {
+	// Scheme is the URL scheme of the artifacts downloaded by the fetcher.
+	Scheme string `hcl:",key"`
+
+	// Command is the path of the fetcher binary.
+	Command string `hcl:"command"`
+
+	// Args are the arguments passed to the fetcher binary.
+	Args []string `hcl:"args"`
+
+	// User is the user the fetcher is run as. Defaults to the user of the
+	// client, in which case the fetcher is fully trusted.
+	User string `hcl:"user"`
+
+	// Env are the environment variables the fetcher is run with. The
+	// fetcher doesn't inherit the environment of the client.
+	Env map[string]string `hcl:"env"`
+
+	// Config is passed to the fetcher along with every artifact to download.
+	Config map[string]string `hcl:"config"`
+
+	// Timeout is the duration in which the fetcher must download an
+	// artifact or it will be killed. Defaults to 30m.
+	Timeout string `hcl:"timeout"`
+}
+
+func (f *ArtifactFetcherConfig) Copy() *ArtifactFetcherConfig {
+	if f == nil {
+		return nil
+	}
+
+	newCopy := *f
+	newCopy.Args = helper.CopySliceString(f.Args)
+	newCopy.Env = helper.CopyMapStringString(f.Env)
+	newCopy.Config = helper.CopyMapStringString(f.Config)
+	return &newCopy
+}
+
+func (f *ArtifactFetcherConfig) Validate() error {
+	if f == nil {
+		return fmt.Errorf("fetcher must not be nil")
+	}
+
+	if f.Scheme == "" {
+		return fmt.Errorf("fetcher must have a scheme")
+	}
+	for _, s := range builtinArtifactSchemes {
+		if strings.EqualFold(f.Scheme, s) {
+			return fmt.Errorf("scheme %q is handled by go-getter", f.Scheme)
+		}
+	}
+
+	if f.Command == "" {
+		return fmt.Errorf("command must be set")
 	}
+
+	if f.User != "" && runtime.GOOS == "windows" {
+		return fmt.Errorf("user is not supported on Windows")
+	}
+
+	if f.Timeout != "" {
+		if v, err := time.ParseDuration(f.Timeout); err != nil {
+			return fmt.Errorf("timeout not a valid duration: %w", err)
+		} else if v <= 0 {
+			return fmt.Errorf("timeout must be > 0")
+		}
+	}
+
+	return nil
 }
@@ -34,28 +34,34 @@ func TestArtifactConfig_Merge(t *testing.T) {
 		{
 			name: "merge all fields",
 			source: &ArtifactConfig{
-				HTTPReadTimeout: helper.StringToPtr("30m"),
-				HTTPMaxSize:     helper.StringToPtr("100GB"),
-				GCSTimeout:      helper.StringToPtr("30m"),
-				GitTimeout:      helper.StringToPtr("30m"),
-				HgTimeout:       helper.StringToPtr("30m"),
-				S3Timeout:       helper.StringToPtr("30m"),
+				HTTPReadTimeout:        helper.StringToPtr("30m"),
+				HTTPMaxSize:            helper.StringToPtr("100GB"),
+				GCSTimeout:             helper.StringToPtr("30m"),
+				GitTimeout:             helper.StringToPtr("30m"),
+				HgTimeout:              helper.StringToPtr("30m"),
+				S3Timeout:              helper.StringToPtr("30m"),
+				BandwidthLimit:         helper.StringToPtr("0"),
+				MaxConcurrentDownloads: helper.IntToPtr(0),
 			},
 			other: &ArtifactConfig{
-				HTTPReadTimeout: helper.StringToPtr("5m"),
-				HTTPMaxSize:     helper.StringToPtr("2GB"),
-				GCSTimeout:      helper.StringToPtr("1m"),
-				GitTimeout:      helper.StringToPtr("2m"),
-				HgTimeout:       helper.StringToPtr("3m"),
-				S3Timeout:       helper.StringToPtr("4m"),
+				HTTPReadTimeout:        helper.StringToPtr("5m"),
+				HTTPMaxSize:            helper.StringToPtr("2GB"),
+				GCSTimeout:             helper.StringToPtr("1m"),
+				GitTimeout:             helper.StringToPtr("2m"),
+				HgTimeout:              helper.StringToPtr("3m"),
+				S3Timeout:              helper.StringToPtr("4m"),
+				BandwidthLimit:         helper.StringToPtr("10MB"),
+				MaxConcurrentDownloads: helper.IntToPtr(4),
 			},
 			expected: &ArtifactConfig{
-				HTTPReadTimeout: helper.StringToPtr("5m"),
-				HTTPMaxSize:     helper.StringToPtr("2GB"),
-				GCSTimeout:      helper.StringToPtr("1m"),
-				GitTimeout:      helper.StringToPtr("2m"),
-				HgTimeout:       helper.StringToPtr("3m"),
-				S3Timeout:       helper.StringToPtr("4m"),
+				HTTPReadTimeout:        helper.StringToPtr("5m"),
+				HTTPMaxSize:            helper.StringToPtr("2GB"),
+				GCSTimeout:             helper.StringToPtr("1m"),
+				GitTimeout:             helper.StringToPtr("2m"),
+				HgTimeout:              helper.StringToPtr("3m"),
+				S3Timeout:              helper.StringToPtr("4m"),
+				BandwidthLimit:         helper.StringToPtr("10MB"),
+				MaxConcurrentDownloads: helper.IntToPtr(4),
 			},
 		},
 		{
@@ -331,6 +337,96 @@ func TestArtifactConfig_Validate(t *testing.T) {
 			},
 			expectedError: "s3_timeout not a valid duration",
 		},
+		{
+			name: "bandwidth limit is missing",
+			config: func(a *ArtifactConfig) {
+				a.BandwidthLimit = nil
+			},
+			expectedError: "bandwidth_limit must be set",
+		},
+		{
+			name: "bandwidth limit is invalid",
+			config: func(a *ArtifactConfig) {
+				a.BandwidthLimit = helper.StringToPtr("invalid")
+			},
+			expectedError: "bandwidth_limit not a valid size",
+		},
+		{
+			name: "bandwidth limit is too large",
+			config: func(a *ArtifactConfig) {
+				a.BandwidthLimit = helper.StringToPtr("9223372036854775808")
+			},
+			expectedError: "bandwidth_limit must be < 9223372036854775807",
+		},
+		{
+			name: "bandwidth limit is valid",
+			config: func(a *ArtifactConfig) {
+				a.BandwidthLimit = helper.StringToPtr("10MB")
+			},
+			expectedError: "",
+		},
+		{
+			name: "max concurrent downloads is missing",
+			config: func(a *ArtifactConfig) {
+				a.MaxConcurrentDownloads = nil
+			},
+			expectedError: "max_concurrent_downloads must be set",
+		},
+		{
+			name: "max concurrent downloads is negative",
+			config: func(a *ArtifactConfig) {
+				a.MaxConcurrentDownloads = helper.IntToPtr(-1)
+			},
+			expectedError: "max_concurrent_downloads must be >= 0",
+		},
+		{
+			name: "fetchers are valid",
+			config: func(a *ArtifactConfig) {
+				a.Fetchers = []*ArtifactFetcherConfig{
+					{Scheme: "store", Command: "/usr/local/bin/store-fetch", Timeout: "5m"},
+					{Scheme: "oci", Command: "/usr/local/bin/oci-fetch"},
+				}
+			},
+			expectedError: "",
+		},
+		{
+			name: "fetcher scheme is missing",
+			config: func(a *ArtifactConfig) {
+				a.Fetchers = []*ArtifactFetcherConfig{{Command: "/usr/local/bin/store-fetch"}}
+			},
+			expectedError: "fetcher must have a scheme",
+		},
+		{
+			name: "fetcher scheme is handled by go-getter",
+			config: func(a *ArtifactConfig) {
+				a.Fetchers = []*ArtifactFetcherConfig{{Scheme: "HTTPS", Command: "/usr/local/bin/store-fetch"}}
+			},
+			expectedError: `scheme "HTTPS" is handled by go-getter`,
+		},
+		{
+			name: "fetcher command is missing",
+			config: func(a *ArtifactConfig) {
+				a.Fetchers = []*ArtifactFetcherConfig{{Scheme: "store"}}
+			},
+			expectedError: "command must be set",
+		},
+		{
+			name: "fetcher timeout is invalid",
+			config: func(a *ArtifactConfig) {
+				a.Fetchers = []*ArtifactFetcherConfig{{Scheme: "store", Command: "/usr/local/bin/store-fetch", Timeout: "0s"}}
+			},
+			expectedError: "timeout must be > 0",
+		},
+		{
+			name: "fetcher scheme is duplicated",
+			config: func(a *ArtifactConfig) {
+				a.Fetchers = []*ArtifactFetcherConfig{
+					{Scheme: "store", Command: "/usr/local/bin/store-fetch"},
+					{Scheme: "store", Command: "/usr/local/bin/other-fetch"},
+				}
+			},
+			expectedError: `fetcher["store"] is defined more than once`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// EnvProviderConfig is the configuration of an environment provider. An
+// environment provider is a binary run by the client before a task starts to
+// contribute environment variables and files to the task.
+type EnvProviderConfig struct {
+	// Name is the name of the provider, used in logs and task events.
+	Name string `hcl:",key"`
+
+	// Command is the path of the provider binary.
+	Command string `hcl:"command"`
+
+	// Args are the arguments passed to the provider binary.
+	Args []string `hcl:"args"`
+
+	// Timeout is the duration in which the provider must complete or it
+	// will be killed. Defaults to 10s.
+	Timeout string `hcl:"timeout"`
+
+	// CacheTTL is the duration for which the output of the provider is
+	// reused for a task, for example when it is restarted. Defaults to 0,
+	// which disables caching.
+	CacheTTL string `hcl:"cache_ttl"`
+}
+
+func (e *EnvProviderConfig) Copy() *EnvProviderConfig {
+	if e == nil {
+		return nil
+	}
+
+	newCopy := *e
+	newCopy.Args = helper.CopySliceString(e.Args)
+	return &newCopy
+}
+
+func (e *EnvProviderConfig) Validate() error {
+	if e == nil {
+		return fmt.Errorf("env_provider must not be nil")
+	}
+
+	if e.Name == "" {
+		return fmt.Errorf("env_provider must have a name")
+	}
+
+	if e.Command == "" {
+		return fmt.Errorf("command must be set")
+	}
+
+	if e.Timeout != "" {
+		if v, err := time.ParseDuration(e.Timeout); err != nil {
+			return fmt.Errorf("timeout not a valid duration: %w", err)
+		} else if v <= 0 {
+			return fmt.Errorf("timeout must be > 0")
+		}
+	}
+
+	if e.CacheTTL != "" {
+		if v, err := time.ParseDuration(e.CacheTTL); err != nil {
+			return fmt.Errorf("cache_ttl not a valid duration: %w", err)
+		} else if v < 0 {
+			return fmt.Errorf("cache_ttl must be >= 0")
+		}
+	}
+
+	return nil
+}
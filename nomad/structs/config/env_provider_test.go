@@ -0,0 +1,102 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvProviderConfig_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &EnvProviderConfig{
+		Name:    "broker",
+		Command: "/usr/local/bin/credentials",
+		Args:    []string{"-role", "web"},
+	}
+	b := a.Copy()
+	require.Equal(t, a, b)
+
+	b.Args[0] = "-other"
+	require.Equal(t, "-role", a.Args[0])
+}
+
+func TestEnvProviderConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name          string
+		config        func(*EnvProviderConfig)
+		expectedError string
+	}{
+		{
+			name:          "valid",
+			config:        nil,
+			expectedError: "",
+		},
+		{
+			name: "name is missing",
+			config: func(e *EnvProviderConfig) {
+				e.Name = ""
+			},
+			expectedError: "env_provider must have a name",
+		},
+		{
+			name: "command is missing",
+			config: func(e *EnvProviderConfig) {
+				e.Command = ""
+			},
+			expectedError: "command must be set",
+		},
+		{
+			name: "timeout is invalid",
+			config: func(e *EnvProviderConfig) {
+				e.Timeout = "invalid"
+			},
+			expectedError: "timeout not a valid duration",
+		},
+		{
+			name: "timeout is zero",
+			config: func(e *EnvProviderConfig) {
+				e.Timeout = "0s"
+			},
+			expectedError: "timeout must be > 0",
+		},
+		{
+			name: "cache ttl is invalid",
+			config: func(e *EnvProviderConfig) {
+				e.CacheTTL = "invalid"
+			},
+			expectedError: "cache_ttl not a valid duration",
+		},
+		{
+			name: "cache ttl is negative",
+			config: func(e *EnvProviderConfig) {
+				e.CacheTTL = "-1m"
+			},
+			expectedError: "cache_ttl must be >= 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &EnvProviderConfig{
+				Name:     "broker",
+				Command:  "/usr/local/bin/credentials",
+				Timeout:  "5s",
+				CacheTTL: "10m",
+			}
+			if tc.config != nil {
+				tc.config(e)
+			}
+
+			err := e.Validate()
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package config
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// HTTPEndpointsUI is the class of the endpoints serving the web UI.
+	HTTPEndpointsUI = "ui"
+
+	// HTTPEndpointsMetrics is the class of the metrics endpoint.
+	HTTPEndpointsMetrics = "metrics"
+
+	// HTTPEndpointsRead is the class of the API endpoints which are called
+	// with a GET or HEAD request.
+	HTTPEndpointsRead = "read"
+
+	// HTTPEndpointsWrite is the class of the other API endpoints.
+	HTTPEndpointsWrite = "write"
+)
+
+// httpEndpointClasses are the valid endpoint classes of a listener.
+var httpEndpointClasses = []string{
+	HTTPEndpointsUI,
+	HTTPEndpointsMetrics,
+	HTTPEndpointsRead,
+	HTTPEndpointsWrite,
+}
+
+// HTTPConfig is the configuration of the HTTP listeners of the agent. The
+// CORS and content security policy configured at the top level apply to every
+// listener, unless overridden by the listener block for its address.
+type HTTPConfig struct {
+	// CORS allows browsers to call the HTTP API from other origins, such as
+	// internal tools embedding it.
+	CORS *CORSConfig `hcl:"cors"`
+
+	// ContentSecurityPolicy replaces the Content-Security-Policy header set
+	// on the responses of the web UI.
+	ContentSecurityPolicy string `hcl:"content_security_policy"`
+
+	// Listeners override the configuration for the listener with the given
+	// address.
+	Listeners []*HTTPListenerConfig `hcl:"listener"`
+}
+
+// HTTPListenerConfig is the configuration of the HTTP listener bound to an
+// address. A listener block with a host and port which is not one of the HTTP
+// addresses of the agent starts an additional listener.
+type HTTPListenerConfig struct {
+	// Address is the address the listener is bound to, e.g. "127.0.0.1" or
+	// "127.0.0.1:4646". Without a port, it matches the address on any port.
+	Address string `hcl:",key"`
+
+	// CORS overrides the CORS configuration of the listener.
+	CORS *CORSConfig `hcl:"cors"`
+
+	// ContentSecurityPolicy overrides the content security policy of the
+	// web UI served by the listener.
+	ContentSecurityPolicy string `hcl:"content_security_policy"`
+
+	// TLS overrides the TLS configuration of the agent for the listener.
+	TLS *HTTPListenerTLSConfig `hcl:"tls"`
+
+	// Endpoints are the classes of endpoints the listener serves. Defaults
+	// to every endpoint.
+	Endpoints []string `hcl:"endpoints"`
+}
+
+// HTTPListenerTLSConfig overrides the HTTP TLS configuration of the agent for a
+// listener.
+type HTTPListenerTLSConfig struct {
+	// Enabled enables or disables TLS for the listener.
+	Enabled *bool `hcl:"enabled"`
+
+	// CAFile, CertFile and KeyFile replace the CA, certificate and key of
+	// the agent.
+	CAFile   string `hcl:"ca_file"`
+	CertFile string `hcl:"cert_file"`
+	KeyFile  string `hcl:"key_file"`
+
+	// VerifyHTTPSClient enables or disables the verification of the client
+	// certificates.
+	VerifyHTTPSClient *bool `hcl:"verify_https_client"`
+}
+
+// CORSConfig is the cross-origin resource sharing configuration of an HTTP
+// listener.
+type CORSConfig struct {
+	// AllowedOrigins are the origins allowed to call the API. An origin may
+	// contain a single "*" wildcard, and "*" allows any origin.
+	AllowedOrigins []string `hcl:"allowed_origins"`
+
+	// AllowedMethods are the methods allowed in cross-origin requests.
+	// Defaults to the methods used by the API.
+	AllowedMethods []string `hcl:"allowed_methods"`
+
+	// AllowedHeaders are the request headers allowed in cross-origin
+	// requests. Defaults to Content-Type and X-Nomad-Token.
+	AllowedHeaders []string `hcl:"allowed_headers"`
+
+	// ExposedHeaders are the response headers browsers expose to the caller.
+	// Defaults to the headers of blocking queries.
+	ExposedHeaders []string `hcl:"exposed_headers"`
+
+	// AllowCredentials allows requests to include cookies and TLS client
+	// certificates.
+	AllowCredentials *bool `hcl:"allow_credentials"`
+
+	// MaxAge is the number of seconds browsers may cache the result of a
+	// preflight request.
+	MaxAge *int `hcl:"max_age"`
+}
+
+// Copy returns a copy of the HTTP configuration.
+func (c *HTTPConfig) Copy() *HTTPConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(HTTPConfig)
+	*nc = *c
+	nc.CORS = c.CORS.Copy()
+	if c.Listeners != nil {
+		nc.Listeners = make([]*HTTPListenerConfig, len(c.Listeners))
+		for i, l := range c.Listeners {
+			nc.Listeners[i] = l.Copy()
+		}
+	}
+	return nc
+}
+
+// Merge returns a new HTTP configuration by merging another one into this one.
+// Listener blocks with the same address are merged.
+func (c *HTTPConfig) Merge(o *HTTPConfig) *HTTPConfig {
+	result := c.Copy()
+	if result == nil {
+		result = new(HTTPConfig)
+	}
+	if o == nil {
+		return result
+	}
+
+	result.CORS = result.CORS.Merge(o.CORS)
+	if o.ContentSecurityPolicy != "" {
+		result.ContentSecurityPolicy = o.ContentSecurityPolicy
+	}
+
+OUTER:
+	for _, l := range o.Listeners {
+		for i, existing := range result.Listeners {
+			if existing.Address == l.Address {
+				result.Listeners[i] = existing.Merge(l)
+				continue OUTER
+			}
+		}
+		result.Listeners = append(result.Listeners, l.Copy())
+	}
+	return result
+}
+
+// Validate returns an error if the HTTP configuration is invalid.
+func (c *HTTPConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if err := c.CORS.Validate(); err != nil {
+		return fmt.Errorf("cors: %v", err)
+	}
+
+	seen := make(map[string]struct{}, len(c.Listeners))
+	for _, l := range c.Listeners {
+		if l.Address == "" {
+			return fmt.Errorf("listener must have an address")
+		}
+		if _, ok := seen[l.Address]; ok {
+			return fmt.Errorf("listener %q is defined more than once", l.Address)
+		}
+		seen[l.Address] = struct{}{}
+
+		if err := l.CORS.Validate(); err != nil {
+			return fmt.Errorf("listener %q cors: %v", l.Address, err)
+		}
+		if err := l.TLS.Validate(); err != nil {
+			return fmt.Errorf("listener %q tls: %v", l.Address, err)
+		}
+		for _, class := range l.Endpoints {
+			if !helper.SliceStringContains(httpEndpointClasses, class) {
+				return fmt.Errorf("listener %q has invalid endpoint class %q, must be one of %v",
+					l.Address, class, httpEndpointClasses)
+			}
+		}
+	}
+	return nil
+}
+
+// AdditionalListeners returns the addresses of the listener blocks which
+// start additional listeners: the blocks with a host and port which are not
+// one of the given HTTP addresses of the agent.
+func (c *HTTPConfig) AdditionalListeners(addrs []string) []string {
+	if c == nil {
+		return nil
+	}
+
+	var result []string
+	for _, l := range c.Listeners {
+		if _, _, err := net.SplitHostPort(l.Address); err != nil {
+			continue
+		}
+		if !helper.SliceStringContains(addrs, l.Address) {
+			result = append(result, l.Address)
+		}
+	}
+	return result
+}
+
+// ListenerConfig returns the configuration of the listener bound to the
+// host:port address: the top level configuration overridden by the listener
+// block for the host, then by the one for the host and port. It returns an
+// empty configuration if c is nil.
+func (c *HTTPConfig) ListenerConfig(addr string) *HTTPListenerConfig {
+	result := &HTTPListenerConfig{Address: addr}
+	if c == nil {
+		return result
+	}
+
+	result.CORS = c.CORS.Copy()
+	result.ContentSecurityPolicy = c.ContentSecurityPolicy
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	for _, match := range []string{host, addr} {
+		for _, l := range c.Listeners {
+			if l.Address == match {
+				result = result.Merge(l)
+				result.Address = addr
+			}
+		}
+	}
+	return result
+}
+
+// Copy returns a copy of the listener configuration.
+func (l *HTTPListenerConfig) Copy() *HTTPListenerConfig {
+	if l == nil {
+		return nil
+	}
+
+	nl := new(HTTPListenerConfig)
+	*nl = *l
+	nl.CORS = l.CORS.Copy()
+	nl.TLS = l.TLS.Copy()
+	nl.Endpoints = helper.CopySliceString(l.Endpoints)
+	return nl
+}
+
+// Merge returns a new listener configuration by merging another one into this
+// one. The CORS and TLS blocks of the other configuration replace the ones of
+// this one, so that a listener can restrict the origins allowed by default.
+func (l *HTTPListenerConfig) Merge(o *HTTPListenerConfig) *HTTPListenerConfig {
+	result := l.Copy()
+	if o == nil {
+		return result
+	}
+
+	if o.CORS != nil {
+		result.CORS = o.CORS.Copy()
+	}
+	if o.ContentSecurityPolicy != "" {
+		result.ContentSecurityPolicy = o.ContentSecurityPolicy
+	}
+	if o.TLS != nil {
+		result.TLS = o.TLS.Copy()
+	}
+	if len(o.Endpoints) != 0 {
+		result.Endpoints = helper.CopySliceString(o.Endpoints)
+	}
+	return result
+}
+
+// AllowsEndpoints returns whether the listener serves the class of endpoints.
+func (l *HTTPListenerConfig) AllowsEndpoints(class string) bool {
+	if l == nil || len(l.Endpoints) == 0 {
+		return true
+	}
+	return helper.SliceStringContains(l.Endpoints, class)
+}
+
+// TLSConfig returns the TLS configuration of the listener: the TLS
+// configuration of the agent overridden by the tls block of the listener.
+func (l *HTTPListenerConfig) TLSConfig(agent *TLSConfig) *TLSConfig {
+	if l == nil || l.TLS == nil {
+		return agent
+	}
+
+	result := agent.Copy()
+	if result == nil {
+		result = &TLSConfig{}
+	}
+	if l.TLS.Enabled != nil {
+		result.EnableHTTP = *l.TLS.Enabled
+	}
+	if l.TLS.VerifyHTTPSClient != nil {
+		result.VerifyHTTPSClient = *l.TLS.VerifyHTTPSClient
+	}
+	if l.TLS.CAFile != "" {
+		result.CAFile = l.TLS.CAFile
+	}
+	if l.TLS.CertFile != "" {
+		// Load the certificate of the listener rather than the agent's
+		result.CertFile = l.TLS.CertFile
+		result.KeyFile = l.TLS.KeyFile
+		result.KeyLoader = nil
+	}
+	result.SetChecksum()
+	return result
+}
+
+// Copy returns a copy of the listener TLS configuration.
+func (t *HTTPListenerTLSConfig) Copy() *HTTPListenerTLSConfig {
+	if t == nil {
+		return nil
+	}
+
+	nt := new(HTTPListenerTLSConfig)
+	*nt = *t
+	if t.Enabled != nil {
+		nt.Enabled = helper.BoolToPtr(*t.Enabled)
+	}
+	if t.VerifyHTTPSClient != nil {
+		nt.VerifyHTTPSClient = helper.BoolToPtr(*t.VerifyHTTPSClient)
+	}
+	return nt
+}
+
+// Validate returns an error if the listener TLS configuration is invalid.
+func (t *HTTPListenerTLSConfig) Validate() error {
+	if t == nil {
+		return nil
+	}
+
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	return nil
+}
+
+// Copy returns a copy of the CORS configuration.
+func (c *CORSConfig) Copy() *CORSConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(CORSConfig)
+	*nc = *c
+	nc.AllowedOrigins = helper.CopySliceString(c.AllowedOrigins)
+	nc.AllowedMethods = helper.CopySliceString(c.AllowedMethods)
+	nc.AllowedHeaders = helper.CopySliceString(c.AllowedHeaders)
+	nc.ExposedHeaders = helper.CopySliceString(c.ExposedHeaders)
+	if c.AllowCredentials != nil {
+		nc.AllowCredentials = helper.BoolToPtr(*c.AllowCredentials)
+	}
+	if c.MaxAge != nil {
+		nc.MaxAge = helper.IntToPtr(*c.MaxAge)
+	}
+	return nc
+}
+
+// Merge returns a new CORS configuration by merging another one into this one.
+func (c *CORSConfig) Merge(o *CORSConfig) *CORSConfig {
+	if c == nil {
+		return o.Copy()
+	}
+
+	result := c.Copy()
+	if o == nil {
+		return result
+	}
+
+	if len(o.AllowedOrigins) != 0 {
+		result.AllowedOrigins = helper.CopySliceString(o.AllowedOrigins)
+	}
+	if len(o.AllowedMethods) != 0 {
+		result.AllowedMethods = helper.CopySliceString(o.AllowedMethods)
+	}
+	if len(o.AllowedHeaders) != 0 {
+		result.AllowedHeaders = helper.CopySliceString(o.AllowedHeaders)
+	}
+	if len(o.ExposedHeaders) != 0 {
+		result.ExposedHeaders = helper.CopySliceString(o.ExposedHeaders)
+	}
+	if o.AllowCredentials != nil {
+		result.AllowCredentials = helper.BoolToPtr(*o.AllowCredentials)
+	}
+	if o.MaxAge != nil {
+		result.MaxAge = helper.IntToPtr(*o.MaxAge)
+	}
+	return result
+}
+
+// Enabled returns whether cross-origin requests are allowed.
+func (c *CORSConfig) Enabled() bool {
+	return c != nil && len(c.AllowedOrigins) != 0
+}
+
+// Validate returns an error if the CORS configuration is invalid.
+func (c *CORSConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if len(c.AllowedOrigins) == 0 {
+		return fmt.Errorf("allowed_origins must be set")
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" && c.AllowCredentials != nil && *c.AllowCredentials {
+			return fmt.Errorf("allow_credentials cannot be set when any origin is allowed")
+		}
+	}
+	if c.MaxAge != nil && *c.MaxAge < 0 {
+		return fmt.Errorf("max_age must be >= 0")
+	}
+	return nil
+}
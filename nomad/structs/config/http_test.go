@@ -0,0 +1,288 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &HTTPConfig{
+		CORS: &CORSConfig{
+			AllowedOrigins: []string{"https://*.example.com"},
+			MaxAge:         helper.IntToPtr(600),
+		},
+		Listeners: []*HTTPListenerConfig{
+			{
+				Address:               "127.0.0.1",
+				ContentSecurityPolicy: "default-src 'self'",
+			},
+		},
+	}
+	b := &HTTPConfig{
+		ContentSecurityPolicy: "default-src 'none'",
+		CORS: &CORSConfig{
+			AllowCredentials: helper.BoolToPtr(true),
+		},
+		Listeners: []*HTTPListenerConfig{
+			{
+				Address: "127.0.0.1",
+				CORS: &CORSConfig{
+					AllowedOrigins: []string{"https://tools.example.com"},
+				},
+			},
+			{
+				Address:               "10.0.0.1",
+				ContentSecurityPolicy: "default-src 'self'",
+			},
+		},
+	}
+
+	result := a.Merge(b)
+	require.Equal(t, &HTTPConfig{
+		ContentSecurityPolicy: "default-src 'none'",
+		CORS: &CORSConfig{
+			AllowedOrigins:   []string{"https://*.example.com"},
+			AllowCredentials: helper.BoolToPtr(true),
+			MaxAge:           helper.IntToPtr(600),
+		},
+		Listeners: []*HTTPListenerConfig{
+			{
+				Address:               "127.0.0.1",
+				ContentSecurityPolicy: "default-src 'self'",
+				CORS: &CORSConfig{
+					AllowedOrigins: []string{"https://tools.example.com"},
+				},
+			},
+			{
+				Address:               "10.0.0.1",
+				ContentSecurityPolicy: "default-src 'self'",
+			},
+		},
+	}, result)
+
+	// The merged configurations are not modified
+	require.Nil(t, a.Listeners[0].CORS)
+	require.Len(t, a.Listeners, 1)
+}
+
+func TestHTTPConfig_ListenerConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	var c *HTTPConfig
+	require.Equal(t, &HTTPListenerConfig{Address: "127.0.0.1:4646"}, c.ListenerConfig("127.0.0.1:4646"))
+
+	c = &HTTPConfig{
+		ContentSecurityPolicy: "default-src 'none'",
+		CORS: &CORSConfig{
+			AllowedOrigins: []string{"https://*.example.com"},
+		},
+		Listeners: []*HTTPListenerConfig{
+			{
+				Address:               "127.0.0.1:4646",
+				ContentSecurityPolicy: "default-src 'self'",
+			},
+			{
+				Address: "127.0.0.1",
+				CORS: &CORSConfig{
+					AllowedOrigins: []string{"https://tools.example.com"},
+				},
+				ContentSecurityPolicy: "default-src 'none'; img-src 'self'",
+			},
+		},
+	}
+
+	// The listener for the host and port overrides the one for the host
+	require.Equal(t, &HTTPListenerConfig{
+		Address:               "127.0.0.1:4646",
+		ContentSecurityPolicy: "default-src 'self'",
+		CORS: &CORSConfig{
+			AllowedOrigins: []string{"https://tools.example.com"},
+		},
+	}, c.ListenerConfig("127.0.0.1:4646"))
+
+	require.Equal(t, &HTTPListenerConfig{
+		Address:               "127.0.0.1:8080",
+		ContentSecurityPolicy: "default-src 'none'; img-src 'self'",
+		CORS: &CORSConfig{
+			AllowedOrigins: []string{"https://tools.example.com"},
+		},
+	}, c.ListenerConfig("127.0.0.1:8080"))
+
+	require.Equal(t, &HTTPListenerConfig{
+		Address:               "10.0.0.1:4646",
+		ContentSecurityPolicy: "default-src 'none'",
+		CORS: &CORSConfig{
+			AllowedOrigins: []string{"https://*.example.com"},
+		},
+	}, c.ListenerConfig("10.0.0.1:4646"))
+}
+
+func TestHTTPConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name          string
+		config        *HTTPConfig
+		expectedError string
+	}{
+		{
+			name:   "nil",
+			config: nil,
+		},
+		{
+			name: "valid",
+			config: &HTTPConfig{
+				CORS: &CORSConfig{
+					AllowedOrigins:   []string{"https://*.example.com"},
+					AllowCredentials: helper.BoolToPtr(true),
+				},
+				Listeners: []*HTTPListenerConfig{
+					{Address: "127.0.0.1"},
+				},
+			},
+		},
+		{
+			name: "origins missing",
+			config: &HTTPConfig{
+				CORS: &CORSConfig{},
+			},
+			expectedError: "cors: allowed_origins must be set",
+		},
+		{
+			name: "credentials with any origin",
+			config: &HTTPConfig{
+				CORS: &CORSConfig{
+					AllowedOrigins:   []string{"*"},
+					AllowCredentials: helper.BoolToPtr(true),
+				},
+			},
+			expectedError: "cors: allow_credentials cannot be set when any origin is allowed",
+		},
+		{
+			name: "negative max age",
+			config: &HTTPConfig{
+				Listeners: []*HTTPListenerConfig{
+					{
+						Address: "127.0.0.1",
+						CORS: &CORSConfig{
+							AllowedOrigins: []string{"*"},
+							MaxAge:         helper.IntToPtr(-1),
+						},
+					},
+				},
+			},
+			expectedError: `listener "127.0.0.1" cors: max_age must be >= 0`,
+		},
+		{
+			name: "invalid endpoint class",
+			config: &HTTPConfig{
+				Listeners: []*HTTPListenerConfig{
+					{Address: "127.0.0.1:9646", Endpoints: []string{"read", "admin"}},
+				},
+			},
+			expectedError: `listener "127.0.0.1:9646" has invalid endpoint class "admin", must be one of [ui metrics read write]`,
+		},
+		{
+			name: "certificate without key",
+			config: &HTTPConfig{
+				Listeners: []*HTTPListenerConfig{
+					{
+						Address: "127.0.0.1:9646",
+						TLS:     &HTTPListenerTLSConfig{CertFile: "admin.pem"},
+					},
+				},
+			},
+			expectedError: `listener "127.0.0.1:9646" tls: cert_file and key_file must be set together`,
+		},
+		{
+			name: "duplicate listener",
+			config: &HTTPConfig{
+				Listeners: []*HTTPListenerConfig{
+					{Address: "127.0.0.1"},
+					{Address: "127.0.0.1"},
+				},
+			},
+			expectedError: `listener "127.0.0.1" is defined more than once`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestHTTPConfig_AdditionalListeners(t *testing.T) {
+	ci.Parallel(t)
+
+	var c *HTTPConfig
+	require.Empty(t, c.AdditionalListeners([]string{"127.0.0.1:4646"}))
+
+	c = &HTTPConfig{
+		Listeners: []*HTTPListenerConfig{
+			{Address: "127.0.0.1"},
+			{Address: "127.0.0.1:4646"},
+			{Address: "127.0.0.1:9646"},
+		},
+	}
+	require.Equal(t, []string{"127.0.0.1:9646"}, c.AdditionalListeners([]string{"127.0.0.1:4646"}))
+}
+
+func TestHTTPListenerConfig_TLSConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	agent := &TLSConfig{
+		EnableHTTP: true,
+		EnableRPC:  true,
+		CAFile:     "ca.pem",
+		CertFile:   "agent.pem",
+		KeyFile:    "agent-key.pem",
+	}
+
+	// Without a tls block, the listener uses the agent configuration
+	l := &HTTPListenerConfig{Address: "127.0.0.1:9646"}
+	require.Same(t, agent, l.TLSConfig(agent))
+
+	l.TLS = &HTTPListenerTLSConfig{
+		CertFile:          "admin.pem",
+		KeyFile:           "admin-key.pem",
+		VerifyHTTPSClient: helper.BoolToPtr(true),
+	}
+	result := l.TLSConfig(agent)
+	require.True(t, result.EnableHTTP)
+	require.True(t, result.VerifyHTTPSClient)
+	require.Equal(t, "ca.pem", result.CAFile)
+	require.Equal(t, "admin.pem", result.CertFile)
+	require.Equal(t, "admin-key.pem", result.KeyFile)
+
+	// The agent configuration is not modified
+	require.Equal(t, "agent.pem", agent.CertFile)
+	require.False(t, agent.VerifyHTTPSClient)
+
+	l.TLS = &HTTPListenerTLSConfig{Enabled: helper.BoolToPtr(false)}
+	require.False(t, l.TLSConfig(agent).EnableHTTP)
+}
+
+func TestHTTPListenerConfig_AllowsEndpoints(t *testing.T) {
+	ci.Parallel(t)
+
+	var l *HTTPListenerConfig
+	require.True(t, l.AllowsEndpoints(HTTPEndpointsWrite))
+
+	l = &HTTPListenerConfig{Endpoints: []string{HTTPEndpointsRead, HTTPEndpointsMetrics}}
+	require.True(t, l.AllowsEndpoints(HTTPEndpointsRead))
+	require.True(t, l.AllowsEndpoints(HTTPEndpointsMetrics))
+	require.False(t, l.AllowsEndpoints(HTTPEndpointsWrite))
+	require.False(t, l.AllowsEndpoints(HTTPEndpointsUI))
+}
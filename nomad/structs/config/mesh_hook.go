@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// MeshHookConfig is the configuration of a mesh hook. A mesh hook is a binary
+// run by the client to integrate the task groups declaring a mesh of its type
+// with an external service mesh: it renders the configuration of their
+// sidecar task and configures the network namespace of their allocations.
+type MeshHookConfig struct {
+	// Name is the type of mesh handled by the hook.
+	Name string `hcl:",key"`
+
+	// Command is the path of the hook binary.
+	Command string `hcl:"command"`
+
+	// Args are the arguments passed to the hook binary.
+	Args []string `hcl:"args"`
+
+	// Timeout is the duration in which the hook must complete or it will be
+	// killed. Defaults to 30s.
+	Timeout string `hcl:"timeout"`
+}
+
+func (m *MeshHookConfig) Copy() *MeshHookConfig {
+	if m == nil {
+		return nil
+	}
+
+	newCopy := *m
+	newCopy.Args = helper.CopySliceString(m.Args)
+	return &newCopy
+}
+
+func (m *MeshHookConfig) Validate() error {
+	if m == nil {
+		return fmt.Errorf("mesh_hook must not be nil")
+	}
+
+	if m.Name == "" {
+		return fmt.Errorf("mesh_hook must have a name")
+	}
+
+	if m.Command == "" {
+		return fmt.Errorf("command must be set")
+	}
+
+	if m.Timeout != "" {
+		if v, err := time.ParseDuration(m.Timeout); err != nil {
+			return fmt.Errorf("timeout not a valid duration: %w", err)
+		} else if v <= 0 {
+			return fmt.Errorf("timeout must be > 0")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeshHookConfig_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &MeshHookConfig{
+		Name:    "istio",
+		Command: "/usr/local/bin/nomad-istio",
+		Args:    []string{"-revision", "stable"},
+	}
+	b := a.Copy()
+	require.Equal(t, a, b)
+
+	b.Args[0] = "-other"
+	require.Equal(t, "-revision", a.Args[0])
+}
+
+func TestMeshHookConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name          string
+		config        func(*MeshHookConfig)
+		expectedError string
+	}{
+		{
+			name:          "valid",
+			config:        nil,
+			expectedError: "",
+		},
+		{
+			name: "name is missing",
+			config: func(m *MeshHookConfig) {
+				m.Name = ""
+			},
+			expectedError: "mesh_hook must have a name",
+		},
+		{
+			name: "command is missing",
+			config: func(m *MeshHookConfig) {
+				m.Command = ""
+			},
+			expectedError: "command must be set",
+		},
+		{
+			name: "timeout is invalid",
+			config: func(m *MeshHookConfig) {
+				m.Timeout = "invalid"
+			},
+			expectedError: "timeout not a valid duration",
+		},
+		{
+			name: "timeout is zero",
+			config: func(m *MeshHookConfig) {
+				m.Timeout = "0s"
+			},
+			expectedError: "timeout must be > 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &MeshHookConfig{
+				Name:    "istio",
+				Command: "/usr/local/bin/nomad-istio",
+				Timeout: "10s",
+			}
+			if tc.config != nil {
+				tc.config(m)
+			}
+
+			err := m.Validate()
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -71,6 +71,14 @@ type TaskCSIPluginConfig struct {
 	// HealthTimeout is the time after which the CSI plugin tasks will be killed
 	// if the CSI Plugin is not healthy.
 	HealthTimeout time.Duration `mapstructure:"health_timeout" hcl:"health_timeout,optional"`
+
+	// ProbeInterval is the interval at which a healthy CSI plugin is probed.
+	ProbeInterval time.Duration `mapstructure:"probe_interval" hcl:"probe_interval,optional"`
+
+	// MaxProbeFailures is the number of consecutive failed probes after which
+	// the CSI plugin task is restarted. If 0, the task is never restarted and
+	// the plugin is only reported as unhealthy.
+	MaxProbeFailures int `mapstructure:"max_probe_failures" hcl:"max_probe_failures,optional"`
 }
 
 func (t *TaskCSIPluginConfig) Copy() *TaskCSIPluginConfig {
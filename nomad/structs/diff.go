@@ -68,8 +68,8 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 	// See agent.ApiJobToStructJob Update is a default for TaskGroups
 	diff := &JobDiff{Type: DiffTypeNone}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
-	filter := []string{"ID", "Status", "StatusDescription", "Version", "Stable", "CreateIndex",
-		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "NomadTokenID"}
+	filter := []string{"ID", "Status", "StatusDescription", "Version", "Stable", "Frozen", "CreateIndex",
+		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "NomadTokenID", "IdempotencyToken"}
 
 	if j == nil && other == nil {
 		return diff, nil
@@ -304,6 +304,17 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// AntiAffinities diff
+	antiAffinitiesDiff := primitiveObjectSetDiff(
+		interfaceSlice(tg.AntiAffinities),
+		interfaceSlice(other.AntiAffinities),
+		nil,
+		"AntiAffinity",
+		contextual)
+	if antiAffinitiesDiff != nil {
+		diff.Objects = append(diff.Objects, antiAffinitiesDiff...)
+	}
+
 	// Restart policy diff
 	rDiff := primitiveObjectDiff(tg.RestartPolicy, other.RestartPolicy, nil, "RestartPolicy", contextual)
 	if rDiff != nil {
@@ -327,6 +338,11 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, consulDiff)
 	}
 
+	// Mesh diff
+	if mDiff := meshDiff(tg.Mesh, other.Mesh, contextual); mDiff != nil {
+		diff.Objects = append(diff.Objects, mDiff)
+	}
+
 	// Update diff
 	// COMPAT: Remove "Stagger" in 0.7.0.
 	if uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual); uDiff != nil {
@@ -516,6 +532,18 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, dDiff)
 	}
 
+	// Process diff
+	pDiff := primitiveObjectDiff(t.Process, other.Process, nil, "Process", contextual)
+	if pDiff != nil {
+		diff.Objects = append(diff.Objects, pDiff)
+	}
+
+	// Identity diff
+	iDiff := primitiveObjectDiff(t.Identity, other.Identity, nil, "Identity", contextual)
+	if iDiff != nil {
+		diff.Objects = append(diff.Objects, iDiff)
+	}
+
 	// Artifacts diff
 	diffs := primitiveObjectSetDiff(
 		interfaceSlice(t.Artifacts),
@@ -921,6 +949,42 @@ func connectDiffs(old, new *ConsulConnect, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// meshDiff returns the diff of two mesh objects. If contextual diff is enabled,
+// all fields will be returned, even if no diff occurred.
+func meshDiff(old, new *Mesh, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Mesh"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &Mesh{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &Mesh{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Diff the sidecar resources and the mesh config.
+	if rDiff := old.Resources.Diff(new.Resources, contextual); rDiff != nil {
+		diff.Objects = append(diff.Objects, rDiff)
+	}
+	if cDiff := configDiff(old.Config, new.Config, contextual); cDiff != nil {
+		diff.Objects = append(diff.Objects, cDiff)
+	}
+
+	return diff
+}
+
 func connectGatewayDiff(prev, next *ConsulGateway, contextual bool) *ObjectDiff {
 	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Gateway"}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
@@ -1797,6 +1861,11 @@ func parameterizedJobDiff(old, new *ParameterizedJobConfig, contextual bool) *Ob
 		diff.Objects = append(diff.Objects, requiredDiff)
 	}
 
+	// Dispatch diff
+	if dDiff := primitiveObjectDiff(old.Dispatch, new.Dispatch, nil, "Dispatch", contextual); dDiff != nil {
+		diff.Objects = append(diff.Objects, dDiff)
+	}
+
 	return diff
 }
 
@@ -156,6 +156,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "true",
 						New:  "",
 					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "Deadline",
+						Old:  "0",
+						New:  "",
+					},
 					{
 						Type: DiffTypeDeleted,
 						Name: "Dispatched",
@@ -225,6 +231,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "",
 						New:  "true",
 					},
+					{
+						Type: DiffTypeAdded,
+						Name: "Deadline",
+						Old:  "",
+						New:  "0",
+					},
 					{
 						Type: DiffTypeAdded,
 						Name: "Dispatched",
@@ -902,6 +914,18 @@ func TestJobDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Priority",
+								Old:  "",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "ShutdownPriority",
+								Old:  "",
+								New:  "0",
+							},
 						},
 					},
 					{
@@ -930,6 +954,18 @@ func TestJobDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Priority",
+								Old:  "0",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "ShutdownPriority",
+								Old:  "0",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -1043,6 +1079,49 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Parameterized Job dispatch edited
+			Old: &Job{
+				ParameterizedJob: &ParameterizedJobConfig{
+					Payload: DispatchPayloadRequired,
+					Dispatch: &DispatchConfig{
+						PurgeAfter: time.Hour,
+					},
+				},
+			},
+			New: &Job{
+				ParameterizedJob: &ParameterizedJobConfig{
+					Payload: DispatchPayloadRequired,
+					Dispatch: &DispatchConfig{
+						PurgeAfter: time.Hour,
+						KeepLast:   10,
+					},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "ParameterizedJob",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Dispatch",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeEdited,
+										Name: "KeepLast",
+										Old:  "0",
+										New:  "10",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Parameterized Job edited
 			Old: &Job{
@@ -2476,6 +2555,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "30000000000",
 								New:  "30000000000",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "PromotionGate",
+								Old:  "",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -3175,6 +3260,12 @@ func TestTaskGroupDiff(t *testing.T) {
 												Old:  "sidecar",
 												New:  "",
 											},
+											{
+												Type: DiffTypeDeleted,
+												Name: "ScaleWithUpstreams",
+												Old:  "false",
+												New:  "",
+											},
 										},
 										Objects: []*ObjectDiff{
 											{
@@ -7160,6 +7251,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "0776",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "RenderMaxStale",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "SourcePath",
@@ -7240,6 +7337,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "0666",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "RenderMaxStale",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "SourcePath",
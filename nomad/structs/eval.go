@@ -1,5 +1,7 @@
 package structs
 
+import "github.com/hashicorp/nomad/helper"
+
 const (
 	// EvalDeleteRPCMethod is the RPC method for batch deleting evaluations
 	// using their IDs.
@@ -7,6 +9,13 @@ const (
 	// Args: EvalDeleteRequest
 	// Reply: EvalDeleteResponse
 	EvalDeleteRPCMethod = "Eval.Delete"
+
+	// EvalRetryRPCMethod is the RPC method for re-creating a blocked or
+	// failed evaluation, optionally with modified placement options.
+	//
+	// Args: EvalRetryRequest
+	// Reply: EvalRetryResponse
+	EvalRetryRPCMethod = "Eval.Retry"
 )
 
 // EvalDeleteRequest is the request object used when operators are manually
@@ -22,3 +31,50 @@ type EvalDeleteRequest struct {
 type EvalDeleteResponse struct {
 	WriteMeta
 }
+
+// EvalRetryRequest is the request object used when operators retry a blocked
+// or failed evaluation.
+type EvalRetryRequest struct {
+	EvalID string
+
+	// Overrides are the optional placement options which replace those of
+	// the job for the new evaluation.
+	Overrides *EvalOverrides
+
+	WriteRequest
+}
+
+// EvalRetryResponse is the response object when an evaluation is retried.
+type EvalRetryResponse struct {
+	EvalID          string
+	EvalCreateIndex uint64
+	WriteMeta
+}
+
+// EvalOverrides are placement options which replace those of the job when an
+// evaluation is processed. They are set when an operator retries an
+// evaluation and are kept on the evaluation for auditing.
+type EvalOverrides struct {
+	// Datacenters replaces the datacenters of the job when selecting nodes
+	// for placements.
+	Datacenters []string
+
+	// IgnoreSoftConstraints disables the affinities and spreads of the job,
+	// its task groups and tasks when scoring nodes for placements.
+	IgnoreSoftConstraints bool
+}
+
+func (o *EvalOverrides) Copy() *EvalOverrides {
+	if o == nil {
+		return nil
+	}
+	return &EvalOverrides{
+		Datacenters:           helper.CopySliceString(o.Datacenters),
+		IgnoreSoftConstraints: o.IgnoreSoftConstraints,
+	}
+}
+
+// IsEmpty returns true if no option is overridden.
+func (o *EvalOverrides) IsEmpty() bool {
+	return o == nil || (len(o.Datacenters) == 0 && !o.IgnoreSoftConstraints)
+}
@@ -13,6 +13,49 @@ type EventStreamWrapper struct {
 	Event *EventJson
 }
 
+// EventMetricsResponse is used to return the metrics of the EventBroker of a
+// server.
+type EventMetricsResponse struct {
+	Metrics *EventBrokerMetrics
+	QueryMeta
+}
+
+// EventBrokerMetrics summarizes the events published by the EventBroker of a
+// server since it started, and how its subscribers keep up with them.
+type EventBrokerMetrics struct {
+	// Published is the number of events published by topic and type.
+	Published map[Topic]map[string]uint64
+
+	// LatestIndex is the index of the latest published events, and BufferLen
+	// the number of indexes whose events are in the buffer.
+	LatestIndex uint64
+	BufferLen   int
+
+	// Subscribers is the number of open subscriptions.
+	Subscribers int
+
+	// LaggingSubscribers is the number of subscribers whose next events were
+	// dropped from the buffer before they read them. They are dropped once
+	// they read their next events.
+	LaggingSubscribers int
+
+	// MaxSubscriberLag is the largest difference between the latest index and
+	// the index of the events last read by a subscriber.
+	MaxSubscriberLag uint64
+
+	// Subscriptions is the number of subscriptions opened.
+	Subscriptions uint64
+
+	// DroppedSubscriptions is the number of subscriptions closed as they
+	// were too slow to read the events before they were dropped from the
+	// buffer.
+	DroppedSubscriptions uint64
+
+	// ClosedSubscriptions is the number of subscriptions closed because
+	// their ACL token was deleted or no longer allowed them.
+	ClosedSubscriptions uint64
+}
+
 type Topic string
 
 const (
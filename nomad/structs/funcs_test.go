@@ -626,6 +626,71 @@ func TestAllocsFit_MemoryOversubscription(t *testing.T) {
 	require.EqualValues(t, 12000, used.Flattened.Memory.MemoryMaxMB)
 }
 
+func TestAllocsFit_DriverOverhead(t *testing.T) {
+	ci.Parallel(t)
+
+	n := &Node{
+		NodeResources: &NodeResources{
+			Cpu: NodeCpuResources{
+				CpuShares: 2000,
+			},
+			Memory: NodeMemoryResources{
+				MemoryMB: 2048,
+			},
+		},
+	}
+
+	a1 := &Allocation{
+		AllocatedResources: &AllocatedResources{
+			Tasks: map[string]*AllocatedTaskResources{
+				"web": {
+					Cpu: AllocatedCpuResources{
+						CpuShares: 500,
+					},
+					Memory: AllocatedMemoryResources{
+						MemoryMB: 768,
+					},
+				},
+			},
+			Overhead: AllocatedOverheadResources{
+				CpuShares: 100,
+				MemoryMB:  256,
+			},
+		},
+	}
+
+	// Should fit two allocations including their overhead
+	fit, _, used, err := AllocsFit(n, []*Allocation{a1, a1}, nil, false)
+	require.NoError(t, err)
+	require.True(t, fit)
+	require.EqualValues(t, 1200, used.Flattened.Cpu.CpuShares)
+	require.EqualValues(t, 2048, used.Flattened.Memory.MemoryMB)
+
+	// Should not fit a third allocation, even though the task resources
+	// alone would fit
+	fit, dim, _, err := AllocsFit(n, []*Allocation{a1, a1, a1}, nil, false)
+	require.NoError(t, err)
+	require.False(t, fit)
+	require.Equal(t, "memory", dim)
+}
+
+func TestNode_DriverOverhead(t *testing.T) {
+	ci.Parallel(t)
+
+	n := &Node{
+		Attributes: map[string]string{
+			DriverOverheadCPUAttr("qemu"):    "200",
+			DriverOverheadMemoryAttr("qemu"): "128",
+			DriverOverheadMemoryAttr("bad"):  "lots",
+		},
+	}
+
+	require.Equal(t, AllocatedOverheadResources{CpuShares: 200, MemoryMB: 128}, n.DriverOverhead("qemu"))
+	require.Equal(t, AllocatedOverheadResources{}, n.DriverOverhead("bad"))
+	require.Equal(t, AllocatedOverheadResources{}, n.DriverOverhead("docker"))
+	require.Equal(t, "driver.qemu.overhead.cpu", DriverOverheadCPUAttr("qemu"))
+}
+
 // COMPAT(0.11): Remove in 0.11
 func TestScoreFitBinPack_Old(t *testing.T) {
 	ci.Parallel(t)
@@ -0,0 +1,289 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// JobLineageNodeTypeJobVersion is the type of the lineage nodes of the
+	// versions of a job.
+	JobLineageNodeTypeJobVersion = "job-version"
+
+	// JobLineageNodeTypeEvaluation is the type of the lineage nodes of
+	// evaluations.
+	JobLineageNodeTypeEvaluation = "evaluation"
+
+	// JobLineageNodeTypeDeployment is the type of the lineage nodes of
+	// deployments.
+	JobLineageNodeTypeDeployment = "deployment"
+
+	// JobLineageNodeTypeAllocation is the type of the lineage nodes of
+	// allocations.
+	JobLineageNodeTypeAllocation = "allocation"
+)
+
+// JobLineageResponse is used to return the lineage of a job.
+type JobLineageResponse struct {
+	// Lineage is the forest of the objects of the job, rooted at the
+	// objects without a known cause, such as the versions of the job.
+	Lineage []*JobLineageNode
+	QueryMeta
+}
+
+// JobLineageNode is an object of a job in its lineage: a job version, an
+// evaluation, a deployment or an allocation. Its children are the objects it
+// caused, such as the allocations placed by an evaluation or the evaluation
+// following up a failed allocation.
+type JobLineageNode struct {
+	// Type is the type of the object, one of the JobLineageNodeType
+	// constants.
+	Type string
+
+	// ID is the ID of the object. For job versions it is the version
+	// number.
+	ID string
+
+	// Status is the status of the object.
+	Status string
+
+	// Description explains what the object is and why it was created.
+	Description string
+
+	// CreateIndex is the Raft index at which the object was created.
+	CreateIndex uint64
+
+	// CreateTime is the time in nanoseconds at which the object was created,
+	// zero if unknown.
+	CreateTime int64
+
+	Children []*JobLineageNode
+}
+
+// BuildJobLineage builds the lineage of a job from its versions and its
+// evaluations, deployments and allocations. Every object is attached to the
+// object that caused it:
+//
+//   - evaluations created as a follow up of another evaluation are
+//     attached to it, evaluations following up a failed allocation to the
+//     allocation, evaluations created by the deployment watcher to their
+//     deployment and evaluations created by registering or scaling the job
+//     to the job version. Evaluations replacing allocations for other
+//     reasons, such as node updates, are attached to the oldest allocation
+//     they replace.
+//   - deployments are attached to the job version they deploy.
+//   - allocations are attached to the evaluation that placed them.
+//
+// Objects whose cause is unknown, for example because it was garbage
+// collected, are roots of the lineage. Children are sorted by create index.
+func BuildJobLineage(versions []*Job, evals []*Evaluation, deployments []*Deployment,
+	allocs []*Allocation) []*JobLineageNode {
+
+	nodes := map[string]*JobLineageNode{}
+	parents := map[string]string{}
+	key := func(typ, id string) string { return typ + "/" + id }
+
+	versionsByModifyIndex := make(map[uint64]string, len(versions))
+	versionsByVersion := make(map[uint64]string, len(versions))
+	for _, job := range versions {
+		k := key(JobLineageNodeTypeJobVersion, strconv.FormatUint(job.Version, 10))
+		nodes[k] = jobVersionLineageNode(job)
+		versionsByModifyIndex[job.JobModifyIndex] = k
+		versionsByVersion[job.Version] = k
+	}
+
+	for _, d := range deployments {
+		k := key(JobLineageNodeTypeDeployment, d.ID)
+		nodes[k] = &JobLineageNode{
+			Type:        JobLineageNodeTypeDeployment,
+			ID:          d.ID,
+			Status:      d.Status,
+			Description: fmt.Sprintf("Deployment of job version %d: %s", d.JobVersion, d.StatusDescription),
+			CreateIndex: d.CreateIndex,
+		}
+		if v, ok := versionsByVersion[d.JobVersion]; ok {
+			parents[k] = v
+		}
+	}
+
+	allocsByID := make(map[string]*Allocation, len(allocs))
+	followups := map[string]string{}
+	for _, alloc := range allocs {
+		allocsByID[alloc.ID] = alloc
+		k := key(JobLineageNodeTypeAllocation, alloc.ID)
+		nodes[k] = allocLineageNode(alloc)
+		parents[k] = key(JobLineageNodeTypeEvaluation, alloc.EvalID)
+		if alloc.FollowupEvalID != "" {
+			followups[alloc.FollowupEvalID] = k
+		}
+	}
+
+	for _, eval := range evals {
+		k := key(JobLineageNodeTypeEvaluation, eval.ID)
+		nodes[k] = evalLineageNode(eval)
+	}
+
+	// The cause of an evaluation is resolved once all the objects are known,
+	// since it may be any of them.
+	for _, eval := range evals {
+		k := key(JobLineageNodeTypeEvaluation, eval.ID)
+		prev := key(JobLineageNodeTypeEvaluation, eval.PreviousEval)
+		deployment := key(JobLineageNodeTypeDeployment, eval.DeploymentID)
+
+		if _, ok := nodes[prev]; ok && eval.PreviousEval != "" {
+			parents[k] = prev
+		} else if alloc, ok := followups[eval.ID]; ok {
+			parents[k] = alloc
+		} else if _, ok := nodes[deployment]; ok && eval.TriggeredBy == EvalTriggerDeploymentWatcher {
+			parents[k] = deployment
+		} else if v, ok := versionsByModifyIndex[eval.JobModifyIndex]; ok && isJobChangeTrigger(eval.TriggeredBy) {
+			parents[k] = v
+		} else if replaced := oldestReplacedAlloc(eval.ID, allocs, allocsByID); replaced != nil {
+			parents[k] = key(JobLineageNodeTypeAllocation, replaced.ID)
+		}
+	}
+
+	// Attach the nodes to their parents. Nodes are attached in create index
+	// order so the children are sorted.
+	sorted := make([]string, 0, len(nodes))
+	for k := range nodes {
+		sorted = append(sorted, k)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := nodes[sorted[i]], nodes[sorted[j]]
+		if a.CreateIndex != b.CreateIndex {
+			return a.CreateIndex < b.CreateIndex
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	var roots []*JobLineageNode
+	for _, k := range sorted {
+		node := nodes[k]
+		if parent, ok := nodes[parents[k]]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	// Objects caught in a cycle aren't reachable from any root, add the
+	// first of them as a root to break the cycle.
+	visited := map[*JobLineageNode]struct{}{}
+	var visit func(n *JobLineageNode)
+	visit = func(n *JobLineageNode) {
+		if _, ok := visited[n]; ok {
+			return
+		}
+		visited[n] = struct{}{}
+		for _, c := range n.Children {
+			visit(c)
+		}
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+	for _, k := range sorted {
+		node := nodes[k]
+		if _, ok := visited[node]; ok {
+			continue
+		}
+		parent := nodes[parents[k]]
+		for i, c := range parent.Children {
+			if c == node {
+				parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+				break
+			}
+		}
+		roots = append(roots, node)
+		visit(node)
+	}
+
+	return roots
+}
+
+// isJobChangeTrigger returns whether evaluations with the given trigger are
+// created by a change of the job.
+func isJobChangeTrigger(triggeredBy string) bool {
+	switch triggeredBy {
+	case EvalTriggerJobRegister, EvalTriggerJobDeregister, EvalTriggerScaling:
+		return true
+	}
+	return false
+}
+
+// oldestReplacedAlloc returns the oldest allocation replaced by an
+// allocation placed by the evaluation, or nil if it didn't replace any.
+func oldestReplacedAlloc(evalID string, allocs []*Allocation, allocsByID map[string]*Allocation) *Allocation {
+	var oldest *Allocation
+	for _, alloc := range allocs {
+		if alloc.EvalID != evalID || alloc.PreviousAllocation == "" {
+			continue
+		}
+		prev, ok := allocsByID[alloc.PreviousAllocation]
+		if ok && (oldest == nil || prev.CreateIndex < oldest.CreateIndex) {
+			oldest = prev
+		}
+	}
+	return oldest
+}
+
+func jobVersionLineageNode(job *Job) *JobLineageNode {
+	desc := fmt.Sprintf("Job version %d registered", job.Version)
+	if job.Stop {
+		desc = fmt.Sprintf("Job version %d stopped", job.Version)
+	}
+	status := ""
+	if job.Stable {
+		status = "stable"
+	}
+	return &JobLineageNode{
+		Type:        JobLineageNodeTypeJobVersion,
+		ID:          strconv.FormatUint(job.Version, 10),
+		Status:      status,
+		Description: desc,
+		CreateIndex: job.JobModifyIndex,
+		CreateTime:  job.SubmitTime,
+	}
+}
+
+func evalLineageNode(eval *Evaluation) *JobLineageNode {
+	desc := []string{fmt.Sprintf("Triggered by %s", eval.TriggeredBy)}
+	if eval.NodeID != "" {
+		desc = append(desc, fmt.Sprintf("node %s", eval.NodeID))
+	}
+	if eval.StatusDescription != "" {
+		desc = append(desc, eval.StatusDescription)
+	}
+	return &JobLineageNode{
+		Type:        JobLineageNodeTypeEvaluation,
+		ID:          eval.ID,
+		Status:      eval.Status,
+		Description: strings.Join(desc, ", "),
+		CreateIndex: eval.CreateIndex,
+		CreateTime:  eval.CreateTime,
+	}
+}
+
+func allocLineageNode(alloc *Allocation) *JobLineageNode {
+	desc := []string{alloc.Name}
+	if alloc.PreviousAllocation != "" {
+		desc = append(desc, fmt.Sprintf("replaces %s", alloc.PreviousAllocation))
+	}
+	if alloc.DeploymentID != "" {
+		desc = append(desc, fmt.Sprintf("deployment %s", alloc.DeploymentID))
+	}
+	if alloc.StopReason != "" {
+		desc = append(desc, fmt.Sprintf("stopped: %s", alloc.StopReason))
+	}
+	return &JobLineageNode{
+		Type:        JobLineageNodeTypeAllocation,
+		ID:          alloc.ID,
+		Status:      alloc.ClientStatus,
+		Description: strings.Join(desc, ", "),
+		CreateIndex: alloc.CreateIndex,
+		CreateTime:  alloc.CreateTime,
+	}
+}
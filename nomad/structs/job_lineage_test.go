@@ -0,0 +1,137 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildJobLineage(t *testing.T) {
+	ci.Parallel(t)
+
+	v0 := &Job{Version: 0, JobModifyIndex: 10}
+	v1 := &Job{Version: 1, JobModifyIndex: 20, Stable: true}
+
+	eval := func(index uint64, triggeredBy string) *Evaluation {
+		return &Evaluation{
+			ID:             uuid.Generate(),
+			TriggeredBy:    triggeredBy,
+			JobModifyIndex: index,
+			Status:         EvalStatusComplete,
+			CreateIndex:    index,
+		}
+	}
+	alloc := func(index uint64, eval *Evaluation) *Allocation {
+		return &Allocation{
+			ID:           uuid.Generate(),
+			Name:         "example.web[0]",
+			EvalID:       eval.ID,
+			ClientStatus: AllocClientStatusRunning,
+			CreateIndex:  index,
+		}
+	}
+
+	// Version 0 is placed, then its alloc fails and is rescheduled later
+	e0 := eval(10, EvalTriggerJobRegister)
+	a0 := alloc(11, e0)
+	a0.ClientStatus = AllocClientStatusFailed
+	followup := eval(12, EvalTriggerRetryFailedAlloc)
+	a0.FollowupEvalID = followup.ID
+	a1 := alloc(13, followup)
+	a1.PreviousAllocation = a0.ID
+
+	// Version 1 is deployed, the deployment watcher continues the deployment
+	e1 := eval(20, EvalTriggerJobRegister)
+	d := &Deployment{ID: uuid.Generate(), JobVersion: 1, Status: DeploymentStatusSuccessful, CreateIndex: 21}
+	a2 := alloc(21, e1)
+	a2.PreviousAllocation = a1.ID
+	a2.DeploymentID = d.ID
+	e2 := eval(22, EvalTriggerDeploymentWatcher)
+	e2.DeploymentID = d.ID
+
+	// A node update replaces the alloc
+	e3 := eval(30, EvalTriggerNodeUpdate)
+	e3.NodeID = "node1"
+	a3 := alloc(31, e3)
+	a3.PreviousAllocation = a2.ID
+
+	// An eval blocked by the node update
+	e4 := eval(32, EvalTriggerQueuedAllocs)
+	e4.PreviousEval = e3.ID
+
+	// An alloc whose eval was garbage collected
+	orphan := alloc(5, &Evaluation{ID: uuid.Generate()})
+
+	lineage := BuildJobLineage(
+		[]*Job{v1, v0},
+		[]*Evaluation{e4, e3, e2, e1, followup, e0},
+		[]*Deployment{d},
+		[]*Allocation{a3, a2, a1, a0, orphan},
+	)
+
+	type tree struct {
+		id       string
+		children []tree
+	}
+	var flatten func(nodes []*JobLineageNode) []tree
+	flatten = func(nodes []*JobLineageNode) []tree {
+		var out []tree
+		for _, n := range nodes {
+			out = append(out, tree{n.ID, flatten(n.Children)})
+		}
+		return out
+	}
+
+	expected := []tree{
+		{orphan.ID, nil},
+		{"0", []tree{
+			{e0.ID, []tree{
+				{a0.ID, []tree{
+					{followup.ID, []tree{
+						{a1.ID, nil},
+					}},
+				}},
+			}},
+		}},
+		{"1", []tree{
+			{e1.ID, []tree{
+				{a2.ID, []tree{
+					{e3.ID, []tree{
+						{a3.ID, nil},
+						{e4.ID, nil},
+					}},
+				}},
+			}},
+			{d.ID, []tree{
+				{e2.ID, nil},
+			}},
+		}},
+	}
+	require.Equal(t, expected, flatten(lineage))
+
+	require.Equal(t, JobLineageNodeTypeJobVersion, lineage[2].Type)
+	require.Equal(t, "stable", lineage[2].Status)
+	require.Equal(t, "Triggered by node-update, node node1", lineage[2].Children[0].Children[0].Children[0].Description)
+}
+
+func TestBuildJobLineage_Cycle(t *testing.T) {
+	ci.Parallel(t)
+
+	// Each eval placed an alloc replacing the alloc of the other eval
+	e0 := &Evaluation{ID: uuid.Generate(), TriggeredBy: EvalTriggerNodeUpdate, CreateIndex: 1}
+	e1 := &Evaluation{ID: uuid.Generate(), TriggeredBy: EvalTriggerNodeUpdate, CreateIndex: 2}
+	a0 := &Allocation{ID: uuid.Generate(), EvalID: e0.ID, CreateIndex: 3}
+	a1 := &Allocation{ID: uuid.Generate(), EvalID: e1.ID, CreateIndex: 4}
+	a0.PreviousAllocation = a1.ID
+	a1.PreviousAllocation = a0.ID
+
+	lineage := BuildJobLineage(nil, []*Evaluation{e0, e1}, nil, []*Allocation{a0, a1})
+	require.Len(t, lineage, 1)
+	require.Equal(t, e0.ID, lineage[0].ID)
+	require.Equal(t, a0.ID, lineage[0].Children[0].ID)
+	require.Equal(t, e1.ID, lineage[0].Children[0].Children[0].ID)
+	require.Equal(t, a1.ID, lineage[0].Children[0].Children[0].Children[0].ID)
+	require.Empty(t, lineage[0].Children[0].Children[0].Children[0].Children)
+}
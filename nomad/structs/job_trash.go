@@ -0,0 +1,86 @@
+package structs
+
+import (
+	"time"
+)
+
+// TrashedJob is a job purged while the job trash is enabled. It is kept
+// along with its versions until it expires, so that an accidental purge can
+// be undone by restoring the job.
+type TrashedJob struct {
+	Namespace string
+	ID        string
+
+	// Job is the job as it was when it was purged.
+	Job *Job
+
+	// Versions are the previous versions of the job, most recent first.
+	Versions []*Job
+
+	// ExpiresAt is the time after which the job is permanently deleted.
+	ExpiresAt time.Time
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Stub returns a summary of the trashed job.
+func (t *TrashedJob) Stub() *TrashedJobListStub {
+	return &TrashedJobListStub{
+		ID:          t.ID,
+		Namespace:   t.Namespace,
+		Name:        t.Job.Name,
+		Type:        t.Job.Type,
+		Version:     t.Job.Version,
+		Versions:    len(t.Versions) + 1,
+		ExpiresAt:   t.ExpiresAt,
+		CreateIndex: t.CreateIndex,
+		ModifyIndex: t.ModifyIndex,
+	}
+}
+
+// TrashedJobListStub is used to return a subset of trashed job information
+// for the trashed job list.
+type TrashedJobListStub struct {
+	ID          string
+	Namespace   string
+	Name        string
+	Type        string
+	Version     uint64
+	Versions    int
+	ExpiresAt   time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobRestoreRequest is used for Job.Restore endpoint to restore a purged job
+// from the job trash.
+type JobRestoreRequest struct {
+	JobID string
+	WriteRequest
+}
+
+// JobRestoreResponse is used to respond to a job restore.
+type JobRestoreResponse struct {
+	JobModifyIndex uint64
+	WriteMeta
+}
+
+// JobTrashListRequest is used for Job.ListTrash endpoint to list the purged
+// jobs in the job trash.
+type JobTrashListRequest struct {
+	QueryOptions
+}
+
+// JobTrashListResponse is used for the Job.ListTrash endpoint.
+type JobTrashListResponse struct {
+	Jobs []*TrashedJobListStub
+	QueryMeta
+}
+
+// JobTrashExpireRequest is a request to permanently delete the purged jobs
+// whose trash expired.
+type JobTrashExpireRequest struct {
+	Timestamp time.Time
+	WriteRequest
+}
@@ -0,0 +1,128 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/copystructure"
+)
+
+const (
+	// MeshSidecarPrefix is the prefix used for the kind of the sidecar tasks
+	// injected in the task groups integrated with an external service mesh.
+	MeshSidecarPrefix = "mesh-sidecar"
+
+	// MeshDefaultSidecarDriver is the driver of the mesh sidecar task unless
+	// set by the mesh block.
+	MeshDefaultSidecarDriver = "docker"
+)
+
+// validMeshType matches the mesh types, which are used in the name of the
+// sidecar task and in node attributes.
+var validMeshType = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+// Mesh integrates a task group with an external service mesh, such as Istio.
+// A sidecar task is injected in the group when the job is registered, and the
+// mesh hook registered on the client for the type of the mesh renders its
+// configuration and configures the network namespace of the allocations.
+type Mesh struct {
+	// Type is the type of the mesh, which selects the mesh hook used by the
+	// clients.
+	Type string
+
+	// Driver is the task driver of the sidecar task.
+	Driver string
+
+	// Resources are the resources of the sidecar task.
+	Resources *Resources
+
+	// Config is the opaque configuration passed to the mesh hook.
+	Config map[string]interface{}
+}
+
+// Copy the Mesh block.
+func (m *Mesh) Copy() *Mesh {
+	if m == nil {
+		return nil
+	}
+
+	nm := new(Mesh)
+	*nm = *m
+	nm.Resources = m.Resources.Copy()
+	if i, err := copystructure.Copy(m.Config); err == nil {
+		nm.Config = i.(map[string]interface{})
+	}
+	return nm
+}
+
+// Equals returns whether m and o are the same.
+func (m *Mesh) Equals(o *Mesh) bool {
+	if m == nil || o == nil {
+		return m == o
+	}
+	return reflect.DeepEqual(m, o)
+}
+
+// Canonicalize sets the default driver of the sidecar task.
+func (m *Mesh) Canonicalize() {
+	if m == nil {
+		return
+	}
+	if m.Driver == "" {
+		m.Driver = MeshDefaultSidecarDriver
+	}
+	if len(m.Config) == 0 {
+		m.Config = nil
+	}
+}
+
+// Validate returns whether m is valid for the task group.
+func (m *Mesh) Validate(tg *TaskGroup) error {
+	if m == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if m.Type == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Mesh type must be set"))
+	} else if !validMeshType.MatchString(m.Type) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Mesh type %q is invalid", m.Type))
+	}
+
+	// The mesh hooks configure the network namespace of the allocations
+	if len(tg.Networks) != 1 || !meshNetworkMode(tg.Networks[0].Mode) {
+		mErr.Errors = append(mErr.Errors, errors.New("Mesh requires a group network in bridge or CNI mode"))
+	}
+
+	if m.Resources != nil {
+		if err := m.Resources.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Mesh sidecar resources are invalid: %v", err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// meshNetworkMode returns whether the group network mode creates a network
+// namespace the mesh hooks can configure.
+func meshNetworkMode(mode string) bool {
+	return mode == "bridge" || strings.HasPrefix(mode, "cni/")
+}
+
+// LookupMeshSidecar returns the sidecar task injected for the mesh of the task
+// group, or nil if there is none.
+func (tg *TaskGroup) LookupMeshSidecar() *Task {
+	if tg.Mesh == nil {
+		return nil
+	}
+	kind := NewTaskKind(MeshSidecarPrefix, tg.Mesh.Type)
+	for _, t := range tg.Tasks {
+		if t.Kind == kind {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMesh_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	m := &Mesh{
+		Type:      "istio",
+		Driver:    "docker",
+		Resources: &Resources{CPU: 100, MemoryMB: 64},
+		Config:    map[string]interface{}{"revision": "stable"},
+	}
+	c := m.Copy()
+	require.True(t, m.Equals(c))
+
+	c.Resources.CPU = 200
+	c.Config["revision"] = "canary"
+	require.Equal(t, 100, m.Resources.CPU)
+	require.Equal(t, "stable", m.Config["revision"])
+	require.False(t, m.Equals(c))
+}
+
+func TestMesh_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		mesh   *Mesh
+		mode   string
+		expErr string
+	}{
+		{
+			name: "valid bridge",
+			mesh: &Mesh{Type: "istio"},
+			mode: "bridge",
+		},
+		{
+			name: "valid cni",
+			mesh: &Mesh{Type: "linkerd"},
+			mode: "cni/mesh",
+		},
+		{
+			name:   "missing type",
+			mesh:   &Mesh{},
+			mode:   "bridge",
+			expErr: "Mesh type must be set",
+		},
+		{
+			name:   "invalid type",
+			mesh:   &Mesh{Type: "istio mesh"},
+			mode:   "bridge",
+			expErr: `Mesh type "istio mesh" is invalid`,
+		},
+		{
+			name:   "host network",
+			mesh:   &Mesh{Type: "istio"},
+			mode:   "host",
+			expErr: "Mesh requires a group network in bridge or CNI mode",
+		},
+		{
+			name:   "invalid resources",
+			mesh:   &Mesh{Type: "istio", Resources: &Resources{CPU: 100, Cores: 1, MemoryMB: 64}},
+			mode:   "bridge",
+			expErr: "Mesh sidecar resources are invalid",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := &TaskGroup{
+				Networks: Networks{{Mode: tc.mode}},
+				Mesh:     tc.mesh,
+			}
+			err := tc.mesh.Validate(tg)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/raft"
 )
 
@@ -152,6 +153,14 @@ type SchedulerConfiguration struct {
 	// MemoryOversubscriptionEnabled specifies whether memory oversubscription is enabled
 	MemoryOversubscriptionEnabled bool `hcl:"memory_oversubscription_enabled"`
 
+	// ReservedHeadroom specifies the share of every node kept free for
+	// system and high priority jobs.
+	ReservedHeadroom ReservedHeadroomConfig `hcl:"reserved_headroom"`
+
+	// EvalFairness configures how the eval broker interleaves the pending
+	// evaluations of different namespaces.
+	EvalFairness EvalFairnessConfig `hcl:"eval_fairness"`
+
 	// RejectJobRegistration disables new job registrations except with a
 	// management ACL token
 	RejectJobRegistration bool `hcl:"reject_job_registration"`
@@ -162,11 +171,36 @@ type SchedulerConfiguration struct {
 	// during leadership transitions.
 	PauseEvalBroker bool `hcl:"pause_eval_broker"`
 
+	// PauseScheduling stops the scheduler workers from dequeuing evaluations
+	// while still allowing evaluations to be created and enqueued. Unlike
+	// PauseEvalBroker, queued evaluations are processed as soon as scheduling
+	// is resumed.
+	PauseScheduling bool `hcl:"pause_scheduling"`
+
+	// PauseSchedulingDuration is the duration after which a scheduling pause
+	// expires automatically. A zero duration pauses scheduling until the
+	// configuration is updated.
+	PauseSchedulingDuration time.Duration
+
+	// PauseSchedulingUntil is the time, as a UnixNano, at which the scheduling
+	// pause expires. It is computed by the leader from PauseSchedulingDuration
+	// when scheduling is paused and it is not already set.
+	PauseSchedulingUntil int64
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// SchedulingPaused returns whether the scheduler workers should stop
+// dequeuing evaluations at the given time.
+func (s *SchedulerConfiguration) SchedulingPaused(now time.Time) bool {
+	if s == nil || !s.PauseScheduling {
+		return false
+	}
+	return s.PauseSchedulingUntil == 0 || now.UnixNano() < s.PauseSchedulingUntil
+}
+
 func (s *SchedulerConfiguration) EffectiveSchedulerAlgorithm() SchedulerAlgorithm {
 	if s == nil || s.SchedulerAlgorithm == "" {
 		return SchedulerAlgorithmBinpack
@@ -192,7 +226,15 @@ func (s *SchedulerConfiguration) Validate() error {
 		return fmt.Errorf("invalid scheduler algorithm: %v", s.SchedulerAlgorithm)
 	}
 
-	return nil
+	if s.PauseSchedulingDuration < 0 {
+		return fmt.Errorf("pause scheduling duration must not be negative: %v", s.PauseSchedulingDuration)
+	}
+
+	if err := s.ReservedHeadroom.Validate(); err != nil {
+		return err
+	}
+
+	return s.EvalFairness.Validate()
 }
 
 // SchedulerConfigurationResponse is the response object that wraps SchedulerConfiguration
@@ -228,6 +270,94 @@ type PreemptionConfig struct {
 	ServiceSchedulerEnabled bool `hcl:"service_scheduler_enabled"`
 }
 
+// ReservedHeadroomConfig specifies the percentage of the CPU and memory of
+// the nodes that placements of routine jobs may not use, so that system and
+// high priority jobs can always be placed.
+type ReservedHeadroomConfig struct {
+	// Percent is the headroom kept on every node
+	Percent int `hcl:"percent"`
+
+	// NodeClassPercent overrides Percent for the nodes of a node class
+	NodeClassPercent map[string]int `hcl:"node_class_percent"`
+
+	// PriorityThreshold is the job priority at or above which placements may
+	// use the headroom. System and sysbatch jobs may always use it, and a zero
+	// threshold restricts the headroom to them.
+	PriorityThreshold int `hcl:"priority_threshold"`
+}
+
+// NodePercent returns the headroom percentage effective for a node.
+func (r *ReservedHeadroomConfig) NodePercent(node *Node) int {
+	if pct, ok := r.NodeClassPercent[node.NodeClass]; ok {
+		return pct
+	}
+	return r.Percent
+}
+
+// Exempts returns whether the placements of the job may use the headroom.
+func (r *ReservedHeadroomConfig) Exempts(job *Job) bool {
+	switch job.Type {
+	case JobTypeSystem, JobTypeSysBatch:
+		return true
+	}
+	return r.PriorityThreshold > 0 && job.Priority >= r.PriorityThreshold
+}
+
+func (r *ReservedHeadroomConfig) Validate() error {
+	if r.Percent < 0 || r.Percent >= 100 {
+		return fmt.Errorf("reserved headroom percent must be between 0 and 99: %d", r.Percent)
+	}
+	for class, pct := range r.NodeClassPercent {
+		if pct < 0 || pct >= 100 {
+			return fmt.Errorf("reserved headroom percent of node class %q must be between 0 and 99: %d", class, pct)
+		}
+	}
+	if r.PriorityThreshold < 0 || r.PriorityThreshold > JobMaxPriority {
+		return fmt.Errorf("reserved headroom priority threshold must be between 0 and %d: %d", JobMaxPriority, r.PriorityThreshold)
+	}
+	return nil
+}
+
+// EvalFairnessConfig configures weighted fair queueing of evaluations across
+// namespaces in the eval broker, so that a namespace with many pending
+// evaluations cannot starve the others.
+type EvalFairnessConfig struct {
+	// Enabled switches the eval broker from strict priority ordering to
+	// interleaving the evaluations of namespaces proportionally to their
+	// shares. Evaluations of a single namespace keep their priority order.
+	Enabled bool `hcl:"enabled"`
+
+	// NamespaceShares is the relative share of dequeued evaluations for each
+	// namespace. Namespaces without an entry have a share of 1.
+	NamespaceShares map[string]int `hcl:"namespace_shares"`
+}
+
+// Share returns the share of dequeued evaluations for the namespace.
+func (c *EvalFairnessConfig) Share(namespace string) int {
+	if share, ok := c.NamespaceShares[namespace]; ok {
+		return share
+	}
+	return 1
+}
+
+func (c *EvalFairnessConfig) Copy() *EvalFairnessConfig {
+	if c == nil {
+		return nil
+	}
+	nc := *c
+	nc.NamespaceShares = helper.CopyMapStringInt(c.NamespaceShares)
+	return &nc
+}
+
+func (c *EvalFairnessConfig) Validate() error {
+	for namespace, share := range c.NamespaceShares {
+		if share < 1 {
+			return fmt.Errorf("eval fairness share of namespace %q must be at least 1: %d", namespace, share)
+		}
+	}
+	return nil
+}
+
 // SchedulerSetConfigRequest is used by the Operator endpoint to update the
 // current Scheduler configuration of the cluster.
 type SchedulerSetConfigRequest struct {
@@ -0,0 +1,80 @@
+package structs
+
+// PlacementExplanation explains the placement of an allocation: the outcome
+// of every node the scheduler evaluated for it, and the selected node.
+type PlacementExplanation struct {
+	// TaskGroup and AllocName identify the placed allocation.
+	TaskGroup string
+	AllocName string
+
+	// NodeID is the ID of the selected node, or empty if the allocation
+	// couldn't be placed.
+	NodeID string
+
+	// Nodes are the outcomes of the nodes evaluated for the placement, in
+	// the order they were evaluated.
+	Nodes []*NodePlacementExplanation
+}
+
+// NodePlacementExplanation is the outcome of a node evaluated for a placement.
+// A node is rejected if FilteredBy or ExhaustedDimension is set, otherwise it
+// was feasible and scored.
+type NodePlacementExplanation struct {
+	NodeID    string
+	NodeName  string
+	NodeClass string
+
+	// FilteredBy is the constraint or feasibility check which rejected the
+	// node.
+	FilteredBy string
+
+	// ExhaustedDimension is the resource the node didn't have enough of.
+	ExhaustedDimension string
+
+	// Scores are the scores of the node by scorer, and NormScore its final
+	// normalized score.
+	Scores    map[string]float64
+	NormScore float64
+}
+
+// Rejected returns whether the node was rejected for the placement.
+func (n *NodePlacementExplanation) Rejected() bool {
+	return n.FilteredBy != "" || n.ExhaustedDimension != ""
+}
+
+// ExplainPlacement enables the explanation of the placement the metrics are
+// collected for.
+func (a *AllocMetric) ExplainPlacement() {
+	a.explanation = &PlacementExplanation{}
+}
+
+// PlacementExplanation returns the explanation of the placement, or nil if it
+// wasn't enabled.
+func (a *AllocMetric) PlacementExplanation() *PlacementExplanation {
+	if a == nil {
+		return nil
+	}
+	return a.explanation
+}
+
+// explainNode returns the explanation of the node to record its outcome in,
+// or nil if the placement isn't explained. Nodes are evaluated one at a time,
+// so the explanation of the last node evaluated is reused.
+func (a *AllocMetric) explainNode(node *Node) *NodePlacementExplanation {
+	if a.explanation == nil || node == nil {
+		return nil
+	}
+
+	nodes := a.explanation.Nodes
+	if n := len(nodes); n != 0 && nodes[n-1].NodeID == node.ID {
+		return nodes[n-1]
+	}
+
+	e := &NodePlacementExplanation{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		NodeClass: node.NodeClass,
+	}
+	a.explanation.Nodes = append(a.explanation.Nodes, e)
+	return e
+}
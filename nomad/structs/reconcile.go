@@ -0,0 +1,68 @@
+package structs
+
+import (
+	"github.com/hashicorp/nomad/helper/flatmap"
+)
+
+// ReconcileRequest is used to reconcile state derived from other objects, such
+// as job summaries or deployment counters, that has drifted from them. This
+// can happen after a snapshot restore.
+type ReconcileRequest struct {
+	// DryRun computes the changes without applying them.
+	DryRun bool
+
+	WriteRequest
+}
+
+// ReconcileResponse lists the changes made by a reconciliation, or that would
+// be made on a dry run.
+type ReconcileResponse struct {
+	Changes []*ReconcileChange
+	WriteMeta
+}
+
+// ReconcileChange describes the reconciliation of a single object.
+type ReconcileChange struct {
+	// Type is DiffTypeAdded if the object is created, DiffTypeEdited if it is
+	// updated and DiffTypeDeleted if it is deleted.
+	Type DiffType
+
+	// Namespace and ID identify the object: the job ID of a job summary, or
+	// the ID of a deployment or a service registration.
+	Namespace string
+	ID        string
+
+	// Fields are the fields of a created or updated object that change.
+	Fields []*FieldDiff
+}
+
+// NewReconcileChange returns the change between the old and reconciled object,
+// or nil if they don't differ. The object is created if old is nil and deleted
+// if reconciled is nil. The indexes of the objects are ignored.
+func NewReconcileChange(namespace, id string, old, reconciled interface{}) *ReconcileChange {
+	if reconciled == nil {
+		return &ReconcileChange{
+			Type:      DiffTypeDeleted,
+			Namespace: namespace,
+			ID:        id,
+		}
+	}
+
+	diffType := DiffTypeEdited
+	if old == nil {
+		diffType = DiffTypeAdded
+	}
+
+	filter := []string{"CreateIndex", "ModifyIndex"}
+	fields := fieldDiffs(flatmap.Flatten(old, filter, false), flatmap.Flatten(reconciled, filter, false), false)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &ReconcileChange{
+		Type:      diffType,
+		Namespace: namespace,
+		ID:        id,
+		Fields:    fields,
+	}
+}
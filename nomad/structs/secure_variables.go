@@ -45,10 +45,21 @@ const (
 	// Reply: SecureVariablesByNameResponse
 	SecureVariablesReadRPCMethod = "SecureVariables.Read"
 
+	// SecureVariablesTxnRPCMethod is the RPC method for applying multiple
+	// secure variable operations atomically.
+	//
+	// Args: SecureVariablesTxnRequest
+	// Reply: SecureVariablesTxnResponse
+	SecureVariablesTxnRPCMethod = "SecureVariables.Txn"
+
 	// maxVariableSize is the maximum size of the unencrypted contents of
 	// a variable. This size is deliberately set low and is not
 	// configurable, to discourage DoS'ing the cluster
 	maxVariableSize = 16384
+
+	// MaxSecureVariablesTxnOps is the maximum number of operations of a
+	// secure variables transaction.
+	MaxSecureVariablesTxnOps = 64
 )
 
 // SecureVariableMetadata is the metadata envelope for a Secure Variable, it
@@ -288,6 +299,83 @@ type SecureVariablesDeleteResponse struct {
 	WriteMeta
 }
 
+const (
+	// SecureVariableOpSet creates or updates a secure variable in a
+	// transaction.
+	SecureVariableOpSet = "set"
+
+	// SecureVariableOpDelete deletes a secure variable in a transaction.
+	SecureVariableOpDelete = "delete"
+)
+
+// SecureVariableTxnOp is an operation of a secure variables transaction.
+type SecureVariableTxnOp struct {
+	// Op is the operation, either SecureVariableOpSet or
+	// SecureVariableOpDelete.
+	Op string
+
+	// Var is the secure variable to set. Only its namespace and path are
+	// used by delete operations.
+	Var *SecureVariableDecrypted
+
+	// CheckIndex is the modify index the secure variable must have for the
+	// transaction to be applied, or 0 if it must not exist. If nil, the
+	// operation is applied regardless of the current secure variable.
+	CheckIndex *uint64
+}
+
+// Validate validates the operation. The namespace of its secure variable must
+// be set.
+func (op *SecureVariableTxnOp) Validate() error {
+	if op.Var == nil {
+		return errors.New("operation requires a variable")
+	}
+	switch op.Op {
+	case SecureVariableOpSet:
+		return op.Var.Validate()
+	case SecureVariableOpDelete:
+		if len(op.Var.Path) == 0 {
+			return fmt.Errorf("variable requires path")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// SecureVariablesTxnRequest is used to apply multiple secure variable
+// operations atomically: either all of them are applied, or none if any
+// check index doesn't match.
+type SecureVariablesTxnRequest struct {
+	Ops []*SecureVariableTxnOp
+	WriteRequest
+}
+
+// SecureVariablesTxnResponse is the response to a secure variables
+// transaction. If the transaction wasn't applied because of check index
+// mismatches, Conflicts has the current secure variable for each
+// conflicting operation, at the operation's position, and nil for the other
+// operations.
+type SecureVariablesTxnResponse struct {
+	Conflicts []*SecureVariableDecrypted
+	WriteMeta
+}
+
+// SecureVariableEncryptedTxnOp is an operation of a secure variables
+// transaction applied to the state store.
+type SecureVariableEncryptedTxnOp struct {
+	Op         string
+	Var        *SecureVariableEncrypted
+	CheckIndex *uint64
+}
+
+// SecureVariablesEncryptedTxnRequest is the raft request of a secure
+// variables transaction.
+type SecureVariablesEncryptedTxnRequest struct {
+	Ops []*SecureVariableEncryptedTxnOp
+	WriteRequest
+}
+
 // RootKey is used to encrypt and decrypt secure variables. It is
 // never stored in raft.
 type RootKey struct {
@@ -8,6 +8,7 @@ import (
 	"hash"
 	"io"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
@@ -17,8 +18,10 @@ import (
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/args"
+	"github.com/hashicorp/nomad/helper/escapingfs"
 	"github.com/mitchellh/copystructure"
 	"golang.org/x/exp/slices"
 )
@@ -31,6 +34,10 @@ const (
 	ServiceCheckScript = "script"
 	ServiceCheckGRPC   = "grpc"
 
+	// ServiceCheckReadinessFile checks whether the readiness file of a task
+	// exists. Only Nomad services support it.
+	ServiceCheckReadinessFile = "readiness_file"
+
 	OnUpdateRequireHealthy = "require_healthy"
 	OnUpdateIgnoreWarn     = "ignore_warnings"
 	OnUpdateIgnore         = "ignore"
@@ -315,11 +322,20 @@ func (sc *ServiceCheck) validateCommon(allowableTypes []string) error {
 
 // validate a Service's ServiceCheck in the context of the Nomad provider.
 func (sc *ServiceCheck) validateNomad() error {
-	allowable := []string{ServiceCheckTCP, ServiceCheckHTTP}
+	allowable := []string{ServiceCheckTCP, ServiceCheckHTTP, ServiceCheckReadinessFile}
 	if err := sc.validateCommon(allowable); err != nil {
 		return err
 	}
 
+	if sc.Type == ServiceCheckReadinessFile {
+		if sc.Path == "" {
+			return fmt.Errorf("readiness_file checks require a path or the readiness_file of the task")
+		}
+		if escaped, err := escapingfs.PathEscapesAllocViaRelative("alloc", sc.Path); err != nil || escaped || filepath.IsAbs(sc.Path) {
+			return fmt.Errorf("readiness_file check path must be relative to the allocation directory")
+		}
+	}
+
 	// expose is connect (consul) specific
 	if sc.Expose {
 		return fmt.Errorf("expose may only be set for Consul service checks")
@@ -1078,6 +1094,12 @@ func (c *ConsulConnect) Validate() error {
 		}
 	}
 
+	if c.SidecarTask != nil && c.SidecarTask.EnvoyVersion != "" {
+		if _, err := version.NewConstraint(c.SidecarTask.EnvoyVersion); err != nil {
+			return fmt.Errorf("Consul Connect sidecar_task envoy_version %q is invalid: %v", c.SidecarTask.EnvoyVersion, err)
+		}
+	}
+
 	// The Native and Sidecar cases are validated up at the service level.
 
 	return nil
@@ -1181,6 +1203,15 @@ type SidecarTask struct {
 	// KillSignal is the kill signal to use for the task. This is an optional
 	// specification and defaults to SIGINT
 	KillSignal string
+
+	// EnvoyVersion is a version constraint the Envoy version used by the
+	// sidecar must satisfy. It is checked by the client against the Envoy
+	// versions supported by the local Consul agent.
+	EnvoyVersion string
+
+	// ScaleWithUpstreams grows the default resources of the sidecar task
+	// with the number of upstreams of the proxy, if Resources is unset.
+	ScaleWithUpstreams bool
 }
 
 func (t *SidecarTask) Equals(o *SidecarTask) bool {
@@ -1233,6 +1264,14 @@ func (t *SidecarTask) Equals(o *SidecarTask) bool {
 		return false
 	}
 
+	if t.EnvoyVersion != o.EnvoyVersion {
+		return false
+	}
+
+	if t.ScaleWithUpstreams != o.ScaleWithUpstreams {
+		return false
+	}
+
 	return true
 }
 
@@ -198,8 +198,8 @@ func TestServiceCheck_validateNomad(t *testing.T) {
 		sc   *ServiceCheck
 		exp  string
 	}{
-		{name: "grpc", sc: &ServiceCheck{Type: ServiceCheckGRPC}, exp: `invalid check type ("grpc"), must be one of tcp, http`},
-		{name: "script", sc: &ServiceCheck{Type: ServiceCheckScript}, exp: `invalid check type ("script"), must be one of tcp, http`},
+		{name: "grpc", sc: &ServiceCheck{Type: ServiceCheckGRPC}, exp: `invalid check type ("grpc"), must be one of tcp, http, readiness_file`},
+		{name: "script", sc: &ServiceCheck{Type: ServiceCheckScript}, exp: `invalid check type ("script"), must be one of tcp, http, readiness_file`},
 		{
 			name: "expose",
 			sc: &ServiceCheck{
@@ -294,6 +294,34 @@ func TestServiceCheck_validateNomad(t *testing.T) {
 			},
 			exp: `http checks may not set Body in Nomad services`,
 		},
+		{
+			name: "readiness file",
+			sc: &ServiceCheck{
+				Type:     ServiceCheckReadinessFile,
+				Interval: 3 * time.Second,
+				Timeout:  1 * time.Second,
+				Path:     "ready/web",
+			},
+		},
+		{
+			name: "readiness file without path",
+			sc: &ServiceCheck{
+				Type:     ServiceCheckReadinessFile,
+				Interval: 3 * time.Second,
+				Timeout:  1 * time.Second,
+			},
+			exp: `readiness_file checks require a path or the readiness_file of the task`,
+		},
+		{
+			name: "readiness file escaping",
+			sc: &ServiceCheck{
+				Type:     ServiceCheckReadinessFile,
+				Interval: 3 * time.Second,
+				Timeout:  1 * time.Second,
+				Path:     "../../etc/ready",
+			},
+			exp: `readiness_file check path must be relative to the allocation directory`,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -441,6 +469,13 @@ func TestConsulConnect_Validate(t *testing.T) {
 
 	c.Native = false
 	require.NoError(t, c.Validate())
+
+	// An invalid envoy_version constraint is invalid
+	c.SidecarTask = &SidecarTask{EnvoyVersion: "1.15 or later"}
+	require.Error(t, c.Validate())
+
+	c.SidecarTask.EnvoyVersion = ">= 1.15"
+	require.NoError(t, c.Validate())
 }
 
 func TestConsulConnect_CopyEquals(t *testing.T) {
@@ -1895,7 +1930,7 @@ func TestService_validateNomadService(t *testing.T) {
 			},
 			inputErr: &multierror.Error{},
 			expectedOutputErrors: []error{
-				errors.New(`invalid check type (""), must be one of tcp, http`),
+				errors.New(`invalid check type (""), must be one of tcp, http, readiness_file`),
 			},
 			name: "bad nomad check",
 		},
@@ -17,6 +17,7 @@ import (
 	"math"
 	"net"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
@@ -113,6 +114,16 @@ const (
 	SecureVariableDeleteRequestType              MessageType = 51
 	RootKeyMetaUpsertRequestType                 MessageType = 52
 	RootKeyMetaDeleteRequestType                 MessageType = 53
+	JobFreezeRequestType                         MessageType = 54
+	NodeIntroTokenUpsertRequestType              MessageType = 55
+	NodeIntroTokenConsumeRequestType             MessageType = 56
+	NodeIntroTokenExpireRequestType              MessageType = 57
+	SecureVariablesTxnRequestType                MessageType = 58
+	ReconcileDeploymentsRequestType              MessageType = 59
+	ReconcileServiceRegistrationsRequestType     MessageType = 60
+	NodeBatchUpdateStatusRequestType             MessageType = 61
+	JobRestoreRequestType                        MessageType = 62
+	JobTrashExpireRequestType                    MessageType = 63
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
@@ -461,6 +472,11 @@ type WriteMeta struct {
 type NodeRegisterRequest struct {
 	Node      *Node
 	NodeEvent *NodeEvent
+
+	// IntroToken is the secret ID of the node introduction token used to
+	// register the node for the first time.
+	IntroToken string
+
 	WriteRequest
 }
 
@@ -507,6 +523,15 @@ type NodeUpdateStatusRequest struct {
 	WriteRequest
 }
 
+// NodeBatchUpdateStatusRequest is used to update the status of a batch of
+// nodes in a single raft apply.
+type NodeBatchUpdateStatusRequest struct {
+	// Updates are the status updates, applied in order
+	Updates []*NodeUpdateStatusRequest
+
+	WriteRequest
+}
+
 // NodeUpdateDrainRequest is used for updating the drain strategy
 type NodeUpdateDrainRequest struct {
 	NodeID        string
@@ -625,6 +650,11 @@ type JobDeregisterRequest struct {
 	// deregistered. It is ignored for single-region jobs.
 	Global bool
 
+	// If EnforceIndex is set then the job will only be deregistered if the
+	// passed JobModifyIndex matches the current Jobs index.
+	EnforceIndex   bool
+	JobModifyIndex uint64
+
 	// EvalPriority is an optional priority to use on any evaluation created as
 	// a result on this job deregistration. This value must be between 1-100
 	// inclusively, where a larger value corresponds to a higher priority. This
@@ -640,6 +670,11 @@ type JobDeregisterRequest struct {
 	// Eval is the evaluation to create that's associated with job deregister
 	Eval *Evaluation
 
+	// TrashExpiresAt is set by the server when the job is purged while the
+	// job trash is enabled. The purged job is kept in the trash until then
+	// so it can be restored.
+	TrashExpiresAt time.Time
+
 	WriteRequest
 }
 
@@ -708,6 +743,12 @@ type JobScaleRequest struct {
 	Meta    map[string]interface{}
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
+
+	// If EnforceIndex is set then the job will only be scaled if the passed
+	// JobModifyIndex matches the current Jobs index.
+	EnforceIndex   bool
+	JobModifyIndex uint64
+
 	WriteRequest
 }
 
@@ -816,6 +857,21 @@ type JobStabilityResponse struct {
 	WriteMeta
 }
 
+// JobFreezeRequest is used to freeze or unfreeze a job.
+type JobFreezeRequest struct {
+	// Job to freeze or unfreeze
+	JobID string
+
+	// Frozen is the desired freeze state of the job
+	Frozen bool
+	WriteRequest
+}
+
+// JobFreezeResponse is the response when freezing or unfreezing a job.
+type JobFreezeResponse struct {
+	WriteMeta
+}
+
 // NodeListRequest is used to parameterize a list request
 type NodeListRequest struct {
 	QueryOptions
@@ -997,6 +1053,22 @@ type AllocStopResponse struct {
 	WriteMeta
 }
 
+// AllocPinRequest is used to pin an allocation to its node, or unpin it.
+type AllocPinRequest struct {
+	AllocID string
+	Pinned  bool
+
+	WriteRequest
+}
+
+// AllocPinResponse is the response to an `AllocPinRequest`
+type AllocPinResponse struct {
+	// EvalID is the id of the follow up evaluation for the job of the alloc.
+	EvalID string
+
+	WriteMeta
+}
+
 // AllocListRequest is used to request a list of allocations
 type AllocListRequest struct {
 	QueryOptions
@@ -1577,6 +1649,25 @@ type DeploymentListResponse struct {
 	QueryMeta
 }
 
+// EvalBlockedResourcesResponse is used to return the unsatisfied demand of
+// the blocked evaluations
+type EvalBlockedResourcesResponse struct {
+	Demand []*BlockedResourcesDemand
+	QueryMeta
+}
+
+// BlockedResourcesDemand is the resources requested by the evaluations
+// blocked on the nodes of a node class in a datacenter, which could be placed
+// if the nodes had more capacity. An evaluation blocked on several
+// datacenters or node classes counts towards each of them.
+type BlockedResourcesDemand struct {
+	Datacenter string
+	NodeClass  string
+	Evals      int
+	CPU        int
+	MemoryMB   int
+}
+
 // EvalListResponse is used for a list request
 type EvalListResponse struct {
 	Evaluations []*Evaluation
@@ -1874,6 +1965,43 @@ func (m *DrainMetadata) Copy() *DrainMetadata {
 	return c
 }
 
+// NodeRestoreReport describes the outcome of a client reattaching to the tasks
+// it was running before it restarted, such as during an in-place upgrade.
+type NodeRestoreReport struct {
+	// RestoredAt is the time the client finished restoring its state.
+	RestoredAt time.Time
+
+	// Reattached is the number of running tasks the client reattached to.
+	Reattached int
+
+	// Failed are the tasks the client couldn't reattach to.
+	Failed []*NodeTaskRestoreFailure
+}
+
+func (r *NodeRestoreReport) Copy() *NodeRestoreReport {
+	if r == nil {
+		return nil
+	}
+	c := new(NodeRestoreReport)
+	*c = *r
+	if r.Failed != nil {
+		c.Failed = make([]*NodeTaskRestoreFailure, len(r.Failed))
+		for i, f := range r.Failed {
+			fc := *f
+			c.Failed[i] = &fc
+		}
+	}
+	return c
+}
+
+// NodeTaskRestoreFailure is a task a client couldn't reattach to after
+// restarting, or won't be able to reattach to if restarted.
+type NodeTaskRestoreFailure struct {
+	AllocID  string
+	TaskName string
+	Error    string
+}
+
 // Node is a representation of a schedulable client node
 type Node struct {
 	// ID is a unique identifier for the node. It can be constructed
@@ -1984,6 +2112,10 @@ type Node struct {
 	// LastDrain contains metadata about the most recent drain operation
 	LastDrain *DrainMetadata
 
+	// LastRestore reports the tasks the client reattached to when it last
+	// restarted.
+	LastRestore *NodeRestoreReport
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -2076,6 +2208,7 @@ func (n *Node) Copy() *Node {
 	nn.HostVolumes = copyNodeHostVolumes(n.HostVolumes)
 	nn.HostNetworks = copyNodeHostNetworks(n.HostNetworks)
 	nn.LastDrain = nn.LastDrain.Copy()
+	nn.LastRestore = nn.LastRestore.Copy()
 	return nn
 }
 
@@ -2223,6 +2356,41 @@ func (n *Node) ComparableResources() *ComparableResources {
 	}
 }
 
+// DriverOverhead returns the per-task resource overhead reported by the given
+// driver on the node, as CPU shares and memory in MB. Drivers report their
+// overhead through the node attributes named by DriverOverheadCPUAttr and
+// DriverOverheadMemoryAttr.
+func (n *Node) DriverOverhead(driver string) AllocatedOverheadResources {
+	var overhead AllocatedOverheadResources
+	if n == nil || driver == "" {
+		return overhead
+	}
+
+	if v, ok := n.Attributes[DriverOverheadCPUAttr(driver)]; ok {
+		if cpu, err := strconv.ParseInt(v, 10, 64); err == nil && cpu > 0 {
+			overhead.CpuShares = cpu
+		}
+	}
+	if v, ok := n.Attributes[DriverOverheadMemoryAttr(driver)]; ok {
+		if mem, err := strconv.ParseInt(v, 10, 64); err == nil && mem > 0 {
+			overhead.MemoryMB = mem
+		}
+	}
+	return overhead
+}
+
+// DriverOverheadCPUAttr returns the name of the node attribute a driver uses
+// to report the CPU overhead, in MHz, it adds to every task it runs.
+func DriverOverheadCPUAttr(driver string) string {
+	return fmt.Sprintf("driver.%s.overhead.cpu", driver)
+}
+
+// DriverOverheadMemoryAttr returns the name of the node attribute a driver
+// uses to report the memory overhead, in MB, it adds to every task it runs.
+func DriverOverheadMemoryAttr(driver string) string {
+	return fmt.Sprintf("driver.%s.overhead.memory", driver)
+}
+
 // Stub returns a summarized version of the node
 func (n *Node) Stub(fields *NodeStubFields) *NodeListStub {
 
@@ -2897,7 +3065,7 @@ func (r *RequestedDevice) Validate() error {
 	for idx, constr := range r.Constraints {
 		// Ensure that the constraint doesn't use an operand we do not allow
 		switch constr.Operand {
-		case ConstraintDistinctHosts, ConstraintDistinctProperty:
+		case ConstraintDistinctHosts, ConstraintDistinctProperty, ConstraintDistinctTopology:
 			outer := fmt.Errorf("Constraint %d validation failed: using unsupported operand %q", idx+1, constr.Operand)
 			_ = multierror.Append(&mErr, outer)
 		default:
@@ -3565,6 +3733,11 @@ type AllocatedResources struct {
 
 	// Shared is the set of resource that are shared by all tasks in the group.
 	Shared AllocatedSharedResources
+
+	// Overhead is the resource overhead reported by the task drivers on the
+	// node the allocation was placed on. It is accounted against the node's
+	// capacity but is not made available to the tasks.
+	Overhead AllocatedOverheadResources
 }
 
 func (a *AllocatedResources) Copy() *AllocatedResources {
@@ -3573,7 +3746,8 @@ func (a *AllocatedResources) Copy() *AllocatedResources {
 	}
 
 	out := AllocatedResources{
-		Shared: a.Shared.Copy(),
+		Shared:   a.Shared.Copy(),
+		Overhead: a.Overhead,
 	}
 
 	if a.Tasks != nil {
@@ -3630,6 +3804,12 @@ func (a *AllocatedResources) Comparable() *ComparableResources {
 	prestartSidecarTasks.Add(prestartEphemeralTasks)
 	c.Flattened.Add(prestartSidecarTasks)
 
+	// Add the driver overhead, which is held for the lifetime of the
+	// allocation
+	c.Flattened.Cpu.CpuShares += a.Overhead.CpuShares
+	c.Flattened.Memory.MemoryMB += a.Overhead.MemoryMB
+	c.Flattened.Memory.MemoryMaxMB += a.Overhead.MemoryMB
+
 	// Add network resources that are at the task group level
 	for _, network := range a.Shared.Networks {
 		c.Flattened.Add(&AllocatedTaskResources{
@@ -3795,6 +3975,20 @@ func (a *AllocatedTaskResources) Subtract(delta *AllocatedTaskResources) {
 	a.Memory.Subtract(&delta.Memory)
 }
 
+// AllocatedOverheadResources is the resource overhead that task drivers add
+// on top of the resources requested by the tasks they run, such as pause
+// containers, sidecar proxies or hypervisors.
+type AllocatedOverheadResources struct {
+	CpuShares int64
+	MemoryMB  int64
+}
+
+// Add adds the resources of the delta to the overhead.
+func (a *AllocatedOverheadResources) Add(delta AllocatedOverheadResources) {
+	a.CpuShares += delta.CpuShares
+	a.MemoryMB += delta.MemoryMB
+}
+
 // AllocatedSharedResources are the set of resources allocated to a task group.
 type AllocatedSharedResources struct {
 	Networks Networks
@@ -4139,6 +4333,11 @@ type Job struct {
 	// can preempt other jobs.
 	Priority int
 
+	// Deadline is the duration after the submission of a batch job by which
+	// its allocations should be placed. Queued evaluations of jobs with the
+	// nearest deadline are scheduled first among those of the same priority.
+	Deadline time.Duration
+
 	// AllAtOnce is used to control if incremental scheduling of task groups
 	// is allowed or if we must do a gang scheduling of the entire job. This
 	// can slow down larger jobs if resources are not available.
@@ -4184,6 +4383,12 @@ type Job struct {
 	// non-terminal siblings which have the same token value.
 	DispatchIdempotencyToken string
 
+	// IdempotencyToken is the idempotency token of the last register, scale,
+	// or deregister request that modified the job. Retries of the request
+	// with the same token return its original result instead of modifying
+	// the job again.
+	IdempotencyToken string
+
 	// Payload is the payload supplied when the job was dispatched.
 	Payload []byte
 
@@ -4226,6 +4431,12 @@ type Job struct {
 	// update stanza.
 	Stable bool
 
+	// Frozen marks a job as frozen. Updates, scaling and dispatches of a
+	// frozen job are rejected unless the token has the unfreeze capability.
+	// This field is set via the Job.Freeze RPC and is carried over between
+	// job versions.
+	Frozen bool
+
 	// Version is a monotonically increasing version number that is incremented
 	// on each job register.
 	Version uint64
@@ -4369,6 +4580,11 @@ func (j *Job) Validate() error {
 	if j.Priority < JobMinPriority || j.Priority > JobMaxPriority {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Job priority must be between [%d, %d]", JobMinPriority, JobMaxPriority))
 	}
+	if j.Deadline < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Job deadline must not be negative"))
+	} else if j.Deadline > 0 && j.Type != JobTypeBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Job deadline is only supported by %q jobs", JobTypeBatch))
+	}
 	if len(j.Datacenters) == 0 && !j.IsMultiregion() {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job datacenters"))
 	} else {
@@ -4428,6 +4644,14 @@ func (j *Job) Validate() error {
 			mErr.Errors = append(mErr.Errors, errors.New("ShutdownDelay must be a positive value"))
 		}
 
+		if tg.ShutdownPriority < -JobMaxPriority || tg.ShutdownPriority > JobMaxPriority {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("ShutdownPriority must be between [%d, %d]", -JobMaxPriority, JobMaxPriority))
+		}
+
+		if tg.Priority != 0 && (tg.Priority < JobMinPriority || tg.Priority > JobMaxPriority) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Task group %q priority must be between [%d, %d]", tg.Name, JobMinPriority, JobMaxPriority))
+		}
+
 		if tg.StopAfterClientDisconnect != nil && *tg.StopAfterClientDisconnect != 0 {
 			if *tg.StopAfterClientDisconnect > 0 &&
 				!(j.Type == JobTypeBatch || j.Type == JobTypeService) {
@@ -4527,6 +4751,15 @@ func (j *Job) LookupTaskGroup(name string) *TaskGroup {
 	return nil
 }
 
+// TaskGroupPriority returns the priority of the given task group for
+// preemption decisions, which defaults to the job priority.
+func (j *Job) TaskGroupPriority(name string) int {
+	if tg := j.LookupTaskGroup(name); tg != nil && tg.Priority != 0 {
+		return tg.Priority
+	}
+	return j.Priority
+}
+
 // CombinedTaskMeta takes a TaskGroup and Task name and returns the combined
 // meta data for the task. When joining Job, Group and Task Meta, the precedence
 // is by deepest scope (Task > Group > Job).
@@ -4748,11 +4981,13 @@ func (j *Job) SpecChanged(new *Job) bool {
 	c.Status = j.Status
 	c.StatusDescription = j.StatusDescription
 	c.Stable = j.Stable
+	c.Frozen = j.Frozen
 	c.Version = j.Version
 	c.CreateIndex = j.CreateIndex
 	c.ModifyIndex = j.ModifyIndex
 	c.JobModifyIndex = j.JobModifyIndex
 	c.SubmitTime = j.SubmitTime
+	c.IdempotencyToken = j.IdempotencyToken
 
 	// cgbaker: FINISH: probably need some consideration of scaling policy ID here
 
@@ -4764,6 +4999,15 @@ func (j *Job) SetSubmitTime() {
 	j.SubmitTime = time.Now().UTC().UnixNano()
 }
 
+// DeadlineTime returns the time, as a UnixNano, by which the allocations of
+// the job should be placed, or zero if the job has no deadline.
+func (j *Job) DeadlineTime() int64 {
+	if j.Deadline <= 0 {
+		return 0
+	}
+	return j.SubmitTime + j.Deadline.Nanoseconds()
+}
+
 // JobListStub is used to return a subset of job information
 // for the job list
 type JobListStub struct {
@@ -4918,6 +5162,11 @@ type UpdateStrategy struct {
 	// healthy
 	AutoPromote bool
 
+	// PromotionGate is the name of the promotion gate, registered with the
+	// deployment watcher, that must allow the promotion of healthy canaries
+	// before they are automatically promoted.
+	PromotionGate string
+
 	// Canary is the number of canaries to deploy when a change to the task
 	// group is detected.
 	Canary int
@@ -4954,6 +5203,9 @@ func (u *UpdateStrategy) Validate() error {
 	if u.Canary == 0 && u.AutoPromote {
 		_ = multierror.Append(&mErr, fmt.Errorf("Auto Promote requires a Canary count greater than zero"))
 	}
+	if u.PromotionGate != "" && !u.AutoPromote {
+		_ = multierror.Append(&mErr, fmt.Errorf("Promotion gate requires Auto Promote to be enabled"))
+	}
 	if u.MinHealthyTime < 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Minimum healthy time may not be less than zero: %v", u.MinHealthyTime))
 	}
@@ -5394,6 +5646,10 @@ type ParameterizedJobConfig struct {
 
 	// MetaOptional is metadata keys that may be specified by the dispatcher
 	MetaOptional []string
+
+	// Dispatch configures how jobs dispatched from the parameterized job are
+	// purged once they complete
+	Dispatch *DispatchConfig
 }
 
 func (d *ParameterizedJobConfig) Validate() error {
@@ -5410,6 +5666,12 @@ func (d *ParameterizedJobConfig) Validate() error {
 		_ = multierror.Append(&mErr, fmt.Errorf("Required and optional meta keys should be disjoint. Following keys exist in both: %v", offending))
 	}
 
+	if d.Dispatch != nil {
+		if err := d.Dispatch.Validate(); err != nil {
+			_ = multierror.Append(&mErr, err)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -5427,9 +5689,44 @@ func (d *ParameterizedJobConfig) Copy() *ParameterizedJobConfig {
 	*nd = *d
 	nd.MetaOptional = helper.CopySliceString(nd.MetaOptional)
 	nd.MetaRequired = helper.CopySliceString(nd.MetaRequired)
+	nd.Dispatch = nd.Dispatch.Copy()
+	return nd
+}
+
+// DispatchConfig configures the automatic purging of completed jobs
+// dispatched from a parameterized job. Dead dispatched jobs are purged by the
+// periodic job garbage collection.
+type DispatchConfig struct {
+	// PurgeAfter is how long a dispatched job must have been dead before it
+	// is purged. If zero, the job_gc_threshold of the servers is used.
+	PurgeAfter time.Duration
+
+	// KeepLast is the number of most recently completed dispatched jobs to
+	// keep. Older dead dispatched jobs are purged regardless of their age. If
+	// zero, dispatched jobs are only purged based on their age.
+	KeepLast int
+}
+
+func (d *DispatchConfig) Copy() *DispatchConfig {
+	if d == nil {
+		return nil
+	}
+	nd := new(DispatchConfig)
+	*nd = *d
 	return nd
 }
 
+func (d *DispatchConfig) Validate() error {
+	var mErr multierror.Error
+	if d.PurgeAfter < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Dispatch purge_after must be positive: %v", d.PurgeAfter))
+	}
+	if d.KeepLast < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Dispatch keep_last must be positive: %d", d.KeepLast))
+	}
+	return mErr.ErrorOrNil()
+}
+
 // DispatchedID returns an ID appropriate for a job dispatched against a
 // particular parameterized job
 func DispatchedID(templateID string, t time.Time) string {
@@ -5502,6 +5799,107 @@ func (d *TaskLifecycleConfig) Validate() error {
 	return nil
 }
 
+const (
+	TaskProcessIOniceClassRealtime   = "realtime"
+	TaskProcessIOniceClassBestEffort = "best-effort"
+	TaskProcessIOniceClassIdle       = "idle"
+)
+
+// taskProcessRlimits is the set of resource limits that can be configured
+// for a task process, named after the RLIMIT_* constants without the prefix.
+var taskProcessRlimits = map[string]struct{}{
+	"as": {}, "core": {}, "cpu": {}, "data": {}, "fsize": {}, "locks": {},
+	"memlock": {}, "msgqueue": {}, "nice": {}, "nofile": {}, "nproc": {},
+	"rss": {}, "rtprio": {}, "rttime": {}, "sigpending": {}, "stack": {},
+}
+
+// TaskProcess configures attributes of the task process.
+type TaskProcess struct {
+	// Umask is the octal file mode creation mask of the process.
+	Umask string
+
+	// Nice is the scheduling priority of the process, from -20 (highest) to
+	// 19 (lowest).
+	Nice int
+
+	// IOniceClass is the I/O scheduling class of the process.
+	IOniceClass string
+
+	// Rlimits are the resource limits of the process. Both the soft and hard
+	// limits are set to the given value.
+	Rlimits map[string]uint64
+}
+
+func (p *TaskProcess) Copy() *TaskProcess {
+	if p == nil {
+		return nil
+	}
+	np := new(TaskProcess)
+	*np = *p
+	np.Rlimits = helper.CopyMap(p.Rlimits)
+	return np
+}
+
+func (p *TaskProcess) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if p.Umask != "" {
+		if mask, err := strconv.ParseUint(p.Umask, 8, 32); err != nil || mask > 0777 {
+			_ = multierror.Append(&mErr, fmt.Errorf("invalid umask %q: must be an octal value between 000 and 777", p.Umask))
+		}
+	}
+	if p.Nice < -20 || p.Nice > 19 {
+		_ = multierror.Append(&mErr, fmt.Errorf("nice must be between -20 and 19, got %d", p.Nice))
+	}
+	switch p.IOniceClass {
+	case "", TaskProcessIOniceClassRealtime, TaskProcessIOniceClassBestEffort, TaskProcessIOniceClassIdle:
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("invalid ionice_class %q: must be one of %q, %q or %q", p.IOniceClass,
+			TaskProcessIOniceClassRealtime, TaskProcessIOniceClassBestEffort, TaskProcessIOniceClassIdle))
+	}
+	for name := range p.Rlimits {
+		if _, ok := taskProcessRlimits[name]; !ok {
+			_ = multierror.Append(&mErr, fmt.Errorf("unknown rlimit %q", name))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// WorkloadIdentity configures how the workload identity of a task is exposed
+// to the task.
+type WorkloadIdentity struct {
+	// File writes the workload identity token to the nomad_token file in
+	// the task's secrets directory.
+	File bool
+
+	// TaskAPI serves the task API on a unix socket in the task's secrets
+	// directory. Requests to it are authenticated with the token written
+	// by File.
+	TaskAPI bool
+}
+
+func (w *WorkloadIdentity) Copy() *WorkloadIdentity {
+	if w == nil {
+		return nil
+	}
+	nw := new(WorkloadIdentity)
+	*nw = *w
+	return nw
+}
+
+func (w *WorkloadIdentity) Validate() error {
+	if w == nil {
+		return nil
+	}
+	if w.TaskAPI && !w.File {
+		return fmt.Errorf("task_api requires file to be enabled")
+	}
+	return nil
+}
+
 var (
 	// These default restart policies needs to be in sync with
 	// Canonicalize in api/tasks.go
@@ -5887,7 +6285,7 @@ func NewRestartPolicy(jobType string) *RestartPolicy {
 const ReschedulePolicyMinInterval = 15 * time.Second
 const ReschedulePolicyMinDelay = 5 * time.Second
 
-var RescheduleDelayFunctions = [...]string{"constant", "exponential", "fibonacci"}
+var RescheduleDelayFunctions = [...]string{"constant", "exponential", "fibonacci", "exponential_jitter"}
 
 // ReschedulePolicy configures how Tasks are rescheduled  when they crash or fail.
 type ReschedulePolicy struct {
@@ -5902,7 +6300,8 @@ type ReschedulePolicy struct {
 	Delay time.Duration
 
 	// DelayFunction determines how the delay progressively changes on subsequent reschedule
-	// attempts. Valid values are "exponential", "constant", and "fibonacci".
+	// attempts. Valid values are "exponential", "constant", "fibonacci" and
+	// "exponential_jitter".
 	DelayFunction string
 
 	// MaxDelay is an upper bound on the delay.
@@ -6028,7 +6427,9 @@ func (r *ReschedulePolicy) viableAttempts() (bool, int, time.Duration) {
 			possibleAttempts = int(r.Interval / r.Delay)
 			valid = false
 		}
-	case "exponential":
+	case "exponential", "exponential_jitter":
+		// The jitter only shortens the delays, so the attempts viable with
+		// exponential delays are viable with jittered ones
 		for i := 0; i < r.Attempts; i++ {
 			nextDelay := time.Duration(math.Pow(2, float64(i))) * r.Delay
 			if nextDelay > r.MaxDelay {
@@ -6198,6 +6599,10 @@ type TaskGroup struct {
 	// scheduling preferences.
 	Affinities []*Affinity
 
+	// AntiAffinities prevent the allocations of the task group from being
+	// placed with the allocations of other jobs.
+	AntiAffinities []*AntiAffinity
+
 	// Spread can be specified at the task group level to express spreading
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
@@ -6209,6 +6614,9 @@ type TaskGroup struct {
 	// Consul configuration specific to this task group
 	Consul *Consul
 
+	// Mesh integrates the task group with an external service mesh
+	Mesh *Mesh
+
 	// Services this group provides
 	Services []*Service
 
@@ -6226,6 +6634,16 @@ type TaskGroup struct {
 	// MaxClientDisconnect, if set, configures the client to allow placed
 	// allocations for tasks in this group to attempt to resume running without a restart.
 	MaxClientDisconnect *time.Duration
+
+	// ShutdownPriority is added to the job priority to compute the kill
+	// priority of the allocations of the task group. When several allocations
+	// of a node must be stopped, those with a lower kill priority are stopped
+	// first.
+	ShutdownPriority int
+
+	// Priority overrides the job priority for the preemption decisions that
+	// involve the allocations of the task group. Zero uses the job priority.
+	Priority int
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -6239,10 +6657,12 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg.RestartPolicy = ntg.RestartPolicy.Copy()
 	ntg.ReschedulePolicy = ntg.ReschedulePolicy.Copy()
 	ntg.Affinities = CopySliceAffinities(ntg.Affinities)
+	ntg.AntiAffinities = CopySliceAntiAffinities(ntg.AntiAffinities)
 	ntg.Spreads = CopySliceSpreads(ntg.Spreads)
 	ntg.Volumes = CopyMapVolumeRequest(ntg.Volumes)
 	ntg.Scaling = ntg.Scaling.Copy()
 	ntg.Consul = ntg.Consul.Copy()
+	ntg.Mesh = ntg.Mesh.Copy()
 
 	// Copy the network objects
 	if tg.Networks != nil {
@@ -6316,6 +6736,12 @@ func (tg *TaskGroup) Canonicalize(job *Job) {
 		tg.EphemeralDisk = DefaultEphemeralDisk()
 	}
 
+	tg.Mesh.Canonicalize()
+
+	for _, a := range tg.AntiAffinities {
+		a.Canonicalize()
+	}
+
 	if tg.Scaling != nil {
 		tg.Scaling.Canonicalize()
 	}
@@ -6395,6 +6821,13 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	for idx, antiAffinity := range tg.AntiAffinities {
+		if err := antiAffinity.Validate(); err != nil {
+			outer := fmt.Errorf("Anti-affinity %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
 	if tg.RestartPolicy != nil {
 		if err := tg.RestartPolicy.Validate(); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
@@ -6503,6 +6936,12 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, outer)
 	}
 
+	// Validate the mesh integration
+	if err := tg.Mesh.Validate(tg); err != nil {
+		outer := fmt.Errorf("Task group mesh validation failed: %v", err)
+		mErr.Errors = append(mErr.Errors, outer)
+	}
+
 	// Validate task group and task services
 	if err := tg.validateServices(); err != nil {
 		outer := fmt.Errorf("Task group service validation failed: %v", err)
@@ -6822,8 +7261,9 @@ func (tg *TaskGroup) validateScalingPolicy(j *Job) error {
 func (tg *TaskGroup) Warnings(j *Job) error {
 	var mErr multierror.Error
 
-	// Validate the update strategy
-	if u := tg.Update; u != nil {
+	// Validate the update strategy. The max parallel of system jobs is a
+	// number of nodes, not bounded by the count.
+	if u := tg.Update; u != nil && j.Type != JobTypeSystem {
 		// Check the counts are appropriate
 		if u.MaxParallel > tg.Count && !(j.IsMultiregion() && tg.Count == 0) {
 			mErr.Errors = append(mErr.Errors,
@@ -7085,12 +7525,25 @@ type Task struct {
 	// specification and defaults to SIGINT
 	KillSignal string
 
+	// ReadinessFile is the path of the file, relative to the allocation
+	// directory, the task writes once it is ready. The task is not healthy
+	// until the file exists.
+	ReadinessFile string
+
 	// Used internally to manage tasks according to their TaskKind. Initial use case
 	// is for Consul Connect
 	Kind TaskKind
 
 	// CSIPluginConfig is used to configure the plugin supervisor for the task.
 	CSIPluginConfig *TaskCSIPluginConfig
+
+	// Process configures attributes of the task process, such as its umask,
+	// scheduling priority and resource limits.
+	Process *TaskProcess
+
+	// Identity configures how the workload identity of the task is exposed
+	// to the task.
+	Identity *WorkloadIdentity
 }
 
 // UsesConnect is for conveniently detecting if the Task is able to make use
@@ -7132,6 +7585,8 @@ func (t *Task) Copy() *Task {
 	nt.Meta = helper.CopyMapStringString(nt.Meta)
 	nt.DispatchPayload = nt.DispatchPayload.Copy()
 	nt.Lifecycle = nt.Lifecycle.Copy()
+	nt.Process = nt.Process.Copy()
+	nt.Identity = nt.Identity.Copy()
 
 	if t.Artifacts != nil {
 		artifacts := make([]*TaskArtifact, 0, len(t.Artifacts))
@@ -7174,6 +7629,14 @@ func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 
 	for _, service := range t.Services {
 		service.Canonicalize(job.Name, tg.Name, t.Name, job.Namespace)
+
+		// Readiness file checks check the readiness file of the task by
+		// default
+		for _, check := range service.Checks {
+			if check.Type == ServiceCheckReadinessFile && check.Path == "" {
+				check.Path = t.ReadinessFile
+			}
+		}
 	}
 
 	// If Resources are nil initialize them to defaults, otherwise canonicalize
@@ -7227,6 +7690,15 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 	if t.ShutdownDelay < 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("ShutdownDelay must be a positive value"))
 	}
+	if t.ReadinessFile != "" {
+		if filepath.IsAbs(t.ReadinessFile) {
+			mErr.Errors = append(mErr.Errors, errors.New("ReadinessFile must be relative to the allocation directory"))
+		} else if escaped, err := escapingfs.PathEscapesAllocViaRelative("alloc", t.ReadinessFile); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid ReadinessFile: %v", err))
+		} else if escaped {
+			mErr.Errors = append(mErr.Errors, errors.New("ReadinessFile escapes the allocation directory"))
+		}
+	}
 
 	// Validate the resources.
 	if t.Resources == nil {
@@ -7249,7 +7721,7 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 		}
 
 		switch constr.Operand {
-		case ConstraintDistinctHosts, ConstraintDistinctProperty:
+		case ConstraintDistinctHosts, ConstraintDistinctProperty, ConstraintDistinctTopology:
 			outer := fmt.Errorf("Constraint %d has disallowed Operand at task level: %s", idx+1, constr.Operand)
 			mErr.Errors = append(mErr.Errors, outer)
 		}
@@ -7317,6 +7789,16 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 		}
 	}
 
+	// Validate the Process block if there
+	if err := t.Process.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Process validation failed: %v", err))
+	}
+
+	// Validate the Identity block if there
+	if err := t.Identity.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Identity validation failed: %v", err))
+	}
+
 	// Validate the Lifecycle block if there
 	if t.Lifecycle != nil {
 		if err := t.Lifecycle.Validate(); err != nil {
@@ -7359,6 +7841,14 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("CSIPluginConfig PluginType must be one of 'node', 'controller', or 'monolith', got: \"%s\"", t.CSIPluginConfig.Type))
 		}
 
+		if t.CSIPluginConfig.ProbeInterval < 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("CSIPluginConfig ProbeInterval must not be negative"))
+		}
+
+		if t.CSIPluginConfig.MaxProbeFailures < 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("CSIPluginConfig MaxProbeFailures must not be negative"))
+		}
+
 		// TODO: Investigate validation of the PluginMountDir. Not much we can do apart from check IsAbs until after we understand its execution environment though :(
 	}
 
@@ -7596,6 +8086,11 @@ func (k TaskKind) IsConnectMesh() bool {
 	return k.hasPrefix(ConnectMeshPrefix)
 }
 
+// IsMeshSidecar returns true if the TaskKind is mesh-sidecar.
+func (k TaskKind) IsMeshSidecar() bool {
+	return k.hasPrefix(MeshSidecarPrefix)
+}
+
 // IsAnyConnectGateway returns true if the TaskKind represents any one of the
 // supported connect gateway types.
 func (k TaskKind) IsAnyConnectGateway() bool {
@@ -7679,6 +8174,22 @@ const (
 	TemplateChangeModeRestart = "restart"
 )
 
+const (
+	// TemplateOnRenderErrorFail marks that the task should be failed if the
+	// template fails to render
+	TemplateOnRenderErrorFail = "fail"
+
+	// TemplateOnRenderErrorRetry marks that rendering should be retried if the
+	// template fails to render, while the task keeps running with the last
+	// rendered template
+	TemplateOnRenderErrorRetry = "retry"
+
+	// TemplateOnRenderErrorKeepLast marks that rendering should be retried if
+	// the template fails to render, and that the task may also start with the
+	// template rendered by a previous run of the task
+	TemplateOnRenderErrorKeepLast = "keep_last"
+)
+
 var (
 	// TemplateChangeModeInvalidError is the error for when an invalid change
 	// mode is given
@@ -7737,14 +8248,25 @@ type Template struct {
 
 	// WaitConfig is used to override the global WaitConfig on a per-template basis
 	Wait *WaitConfig
+
+	// OnRenderError is the action taken if the template fails to render, one
+	// of TemplateOnRenderErrorFail, TemplateOnRenderErrorRetry or
+	// TemplateOnRenderErrorKeepLast. Empty is the same as fail.
+	OnRenderError string
+
+	// RenderMaxStale is the maximum duration the template may fail to render
+	// before the task is failed when OnRenderError is retry or keep_last.
+	// Zero means rendering is retried indefinitely.
+	RenderMaxStale time.Duration
 }
 
 // DefaultTemplate returns a default template.
 func DefaultTemplate() *Template {
 	return &Template{
-		ChangeMode: TemplateChangeModeRestart,
-		Splay:      5 * time.Second,
-		Perms:      "0644",
+		ChangeMode:    TemplateChangeModeRestart,
+		Splay:         5 * time.Second,
+		Perms:         "0644",
+		OnRenderError: TemplateOnRenderErrorFail,
 	}
 }
 
@@ -7808,6 +8330,20 @@ func (t *Template) Validate() error {
 		_ = multierror.Append(&mErr, fmt.Errorf("Must specify positive splay value"))
 	}
 
+	// Verify the render error policy
+	switch t.OnRenderError {
+	case "", TemplateOnRenderErrorFail:
+		if t.RenderMaxStale != 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("render_max_stale requires on_render_error retry or keep_last"))
+		}
+	case TemplateOnRenderErrorRetry, TemplateOnRenderErrorKeepLast:
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Invalid on_render_error %q. Must be one of the following: fail, retry, keep_last", t.OnRenderError))
+	}
+	if t.RenderMaxStale < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("render_max_stale must not be negative"))
+	}
+
 	// Verify the permissions
 	if t.Perms != "" {
 		if _, err := strconv.ParseUint(t.Perms, 8, 12); err != nil {
@@ -8075,6 +8611,10 @@ const (
 	// failed.
 	TaskArtifactDownloadFailed = "Failed Artifact Download"
 
+	// TaskArtifactDownloadProgress reports the progress of a long running
+	// artifact download.
+	TaskArtifactDownloadProgress = "Artifact Download Progress"
+
 	// TaskBuildingTaskDir indicates that the task directory/chroot is being
 	// built.
 	TaskBuildingTaskDir = "Building Task Directory"
@@ -8095,6 +8635,22 @@ const (
 	// downloading an image.
 	TaskDriverMessage = "Driver"
 
+	// TaskDriverUnhealthy indicates that the driver reported the task as
+	// unhealthy.
+	TaskDriverUnhealthy = "Driver Unhealthy"
+
+	// TaskDriverHealthy indicates that the driver reported the task as
+	// healthy again after being unhealthy.
+	TaskDriverHealthy = "Driver Healthy"
+
+	// TaskCheckpointed indicates that the driver checkpointed the task so it
+	// can be restored by the allocation replacing it.
+	TaskCheckpointed = "Checkpointed"
+
+	// TaskRestoredCheckpoint indicates that the task was restored from the
+	// checkpoint of the previous allocation instead of being started.
+	TaskRestoredCheckpoint = "Restored Checkpoint"
+
 	// TaskLeaderDead indicates that the leader task within the has finished.
 	TaskLeaderDead = "Leader Task Dead"
 
@@ -8114,8 +8670,133 @@ const (
 	// TaskPluginHealthy indicates that a plugin managed by Nomad became healthy
 	TaskPluginHealthy = "Plugin became healthy"
 
+	// TaskMarkedUnhealthy indicates that the task marked itself as unhealthy
+	// through the task API.
+	TaskMarkedUnhealthy = "Marked Unhealthy"
+
 	// TaskClientReconnected indicates that the client running the task disconnected.
 	TaskClientReconnected = "Reconnected"
+
+	// TaskTemplateDegraded indicates that the templates of the task failed to
+	// render and that rendering is retried.
+	TaskTemplateDegraded = "Template Degraded"
+
+	// TaskTemplateRecovered indicates that the templates of the task render
+	// again after being degraded.
+	TaskTemplateRecovered = "Template Recovered"
+)
+
+// TaskEventKind classifies task events into a small set of categories so that
+// consumers can filter and alert on events without matching every event type.
+type TaskEventKind string
+
+const (
+	// TaskEventKindLifecycle is used for events that mark the normal
+	// progression of a task, such as being received, started or terminated.
+	TaskEventKindLifecycle TaskEventKind = "lifecycle"
+
+	// TaskEventKindFailure is used for events that report the task failing
+	// to be set up, validated or started.
+	TaskEventKindFailure TaskEventKind = "failure"
+
+	// TaskEventKindRestart is used for events related to restarting a task.
+	TaskEventKindRestart TaskEventKind = "restart"
+
+	// TaskEventKindKill is used for events related to killing a task.
+	TaskEventKindKill TaskEventKind = "kill"
+
+	// TaskEventKindSignal is used for events related to signaling a task.
+	TaskEventKindSignal TaskEventKind = "signal"
+
+	// TaskEventKindArtifact is used for events related to downloading
+	// artifacts.
+	TaskEventKindArtifact TaskEventKind = "artifact"
+
+	// TaskEventKindTemplate is used for events emitted by the template
+	// runner.
+	TaskEventKindTemplate TaskEventKind = "template"
+
+	// TaskEventKindDriver is used for messages emitted by task drivers.
+	TaskEventKindDriver TaskEventKind = "driver"
+
+	// TaskEventKindPlugin is used for events about plugins managed by Nomad.
+	TaskEventKindPlugin TaskEventKind = "plugin"
+
+	// TaskEventKindOther is used for events that do not fit any other kind.
+	TaskEventKindOther TaskEventKind = "other"
+)
+
+// taskEventKinds maps known task event types to their kind. The template
+// runner emits events using its own source name as the event type.
+var taskEventKinds = map[string]TaskEventKind{
+	TaskReceived:                 TaskEventKindLifecycle,
+	TaskSetup:                    TaskEventKindLifecycle,
+	TaskStarted:                  TaskEventKindLifecycle,
+	TaskTerminated:               TaskEventKindLifecycle,
+	TaskLeaderDead:               TaskEventKindLifecycle,
+	TaskMainDead:                 TaskEventKindLifecycle,
+	TaskSiblingFailed:            TaskEventKindLifecycle,
+	TaskClientReconnected:        TaskEventKindLifecycle,
+	TaskSetupFailure:             TaskEventKindFailure,
+	TaskDriverFailure:            TaskEventKindFailure,
+	TaskFailedValidation:         TaskEventKindFailure,
+	TaskDiskExceeded:             TaskEventKindFailure,
+	TaskHookFailed:               TaskEventKindFailure,
+	TaskRestoreFailed:            TaskEventKindFailure,
+	TaskRestarting:               TaskEventKindRestart,
+	TaskNotRestarting:            TaskEventKindRestart,
+	TaskRestartSignal:            TaskEventKindRestart,
+	TaskKilling:                  TaskEventKindKill,
+	TaskKilled:                   TaskEventKindKill,
+	TaskSignaling:                TaskEventKindSignal,
+	TaskDownloadingArtifacts:     TaskEventKindArtifact,
+	TaskArtifactDownloadFailed:   TaskEventKindArtifact,
+	TaskArtifactDownloadProgress: TaskEventKindArtifact,
+	"Template":                   TaskEventKindTemplate,
+	TaskTemplateDegraded:         TaskEventKindTemplate,
+	TaskTemplateRecovered:        TaskEventKindTemplate,
+	TaskDriverMessage:            TaskEventKindDriver,
+	TaskDriverUnhealthy:          TaskEventKindDriver,
+	TaskDriverHealthy:            TaskEventKindDriver,
+	TaskCheckpointed:             TaskEventKindDriver,
+	TaskRestoredCheckpoint:       TaskEventKindDriver,
+	TaskPluginHealthy:            TaskEventKindPlugin,
+	TaskPluginUnhealthy:          TaskEventKindPlugin,
+}
+
+// TaskEventKindOf returns the kind of the given task event type.
+func TaskEventKindOf(eventType string) TaskEventKind {
+	if kind, ok := taskEventKinds[eventType]; ok {
+		return kind
+	}
+	return TaskEventKindOther
+}
+
+// Keys of the TaskEvent Details map.
+const (
+	TaskEventDetailMessage          = "message"
+	TaskEventDetailFailsTask        = "fails_task"
+	TaskEventDetailSetupError       = "setup_error"
+	TaskEventDetailDriverError      = "driver_error"
+	TaskEventDetailDriverMessage    = "driver_message"
+	TaskEventDetailExitCode         = "exit_code"
+	TaskEventDetailSignal           = "signal"
+	TaskEventDetailExitMessage      = "exit_message"
+	TaskEventDetailOOMKilled        = "oom_killed"
+	TaskEventDetailKillError        = "kill_error"
+	TaskEventDetailKillReason       = "kill_reason"
+	TaskEventDetailKillTimeout      = "kill_timeout"
+	TaskEventDetailStartDelay       = "start_delay"
+	TaskEventDetailRestartReason    = "restart_reason"
+	TaskEventDetailTaskSignal       = "task_signal"
+	TaskEventDetailTaskSignalReason = "task_signal_reason"
+	TaskEventDetailDownloadError    = "download_error"
+	TaskEventDetailArtifactURL      = "artifact_url"
+	TaskEventDetailTemplatePath     = "template_path"
+	TaskEventDetailValidationError  = "validation_error"
+	TaskEventDetailDiskLimit        = "disk_limit"
+	TaskEventDetailFailedSibling    = "failed_sibling"
+	TaskEventDetailVaultError       = "vault_renewal_error"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -8124,6 +8805,9 @@ type TaskEvent struct {
 	Type string
 	Time int64 // Unix Nanosecond timestamp
 
+	// Kind is the category of the event type.
+	Kind TaskEventKind
+
 	Message string // A possible message explaining the termination of the task.
 
 	// DisplayMessage is a human friendly message about the event
@@ -8219,6 +8903,11 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		return
 	}
 
+	// Events created by older clients do not have a kind.
+	if e.Kind == "" {
+		e.Kind = TaskEventKindOf(e.Type)
+	}
+
 	if e.DisplayMessage != "" {
 		return
 	}
@@ -8257,6 +8946,8 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		} else {
 			desc = "Failed to download artifacts"
 		}
+	case TaskArtifactDownloadProgress:
+		desc = fmt.Sprintf("%s: %s", e.Details[TaskEventDetailArtifactURL], e.Message)
 	case TaskKilling:
 		if e.KillReason != "" {
 			desc = e.KillReason
@@ -8349,7 +9040,7 @@ func (e *TaskEvent) SetDisplayMessage(msg string) *TaskEvent {
 // SetMessage sets the message of TaskEvent
 func (e *TaskEvent) SetMessage(msg string) *TaskEvent {
 	e.Message = msg
-	e.Details["message"] = msg
+	e.Details[TaskEventDetailMessage] = msg
 	return e
 }
 
@@ -8366,6 +9057,7 @@ func NewTaskEvent(event string) *TaskEvent {
 	return &TaskEvent{
 		Type:    event,
 		Time:    time.Now().UnixNano(),
+		Kind:    TaskEventKindOf(event),
 		Details: make(map[string]string),
 	}
 }
@@ -8375,46 +9067,46 @@ func NewTaskEvent(event string) *TaskEvent {
 func (e *TaskEvent) SetSetupError(err error) *TaskEvent {
 	if err != nil {
 		e.SetupError = err.Error()
-		e.Details["setup_error"] = err.Error()
+		e.Details[TaskEventDetailSetupError] = err.Error()
 	}
 	return e
 }
 
 func (e *TaskEvent) SetFailsTask() *TaskEvent {
 	e.FailsTask = true
-	e.Details["fails_task"] = "true"
+	e.Details[TaskEventDetailFailsTask] = "true"
 	return e
 }
 
 func (e *TaskEvent) SetDriverError(err error) *TaskEvent {
 	if err != nil {
 		e.DriverError = err.Error()
-		e.Details["driver_error"] = err.Error()
+		e.Details[TaskEventDetailDriverError] = err.Error()
 	}
 	return e
 }
 
 func (e *TaskEvent) SetExitCode(c int) *TaskEvent {
 	e.ExitCode = c
-	e.Details["exit_code"] = fmt.Sprintf("%d", c)
+	e.Details[TaskEventDetailExitCode] = fmt.Sprintf("%d", c)
 	return e
 }
 
 func (e *TaskEvent) SetSignal(s int) *TaskEvent {
 	e.Signal = s
-	e.Details["signal"] = fmt.Sprintf("%d", s)
+	e.Details[TaskEventDetailSignal] = fmt.Sprintf("%d", s)
 	return e
 }
 
 func (e *TaskEvent) SetSignalText(s string) *TaskEvent {
-	e.Details["signal"] = s
+	e.Details[TaskEventDetailSignal] = s
 	return e
 }
 
 func (e *TaskEvent) SetExitMessage(err error) *TaskEvent {
 	if err != nil {
 		e.Message = err.Error()
-		e.Details["exit_message"] = err.Error()
+		e.Details[TaskEventDetailExitMessage] = err.Error()
 	}
 	return e
 }
@@ -8422,45 +9114,45 @@ func (e *TaskEvent) SetExitMessage(err error) *TaskEvent {
 func (e *TaskEvent) SetKillError(err error) *TaskEvent {
 	if err != nil {
 		e.KillError = err.Error()
-		e.Details["kill_error"] = err.Error()
+		e.Details[TaskEventDetailKillError] = err.Error()
 	}
 	return e
 }
 
 func (e *TaskEvent) SetKillReason(r string) *TaskEvent {
 	e.KillReason = r
-	e.Details["kill_reason"] = r
+	e.Details[TaskEventDetailKillReason] = r
 	return e
 }
 
 func (e *TaskEvent) SetRestartDelay(delay time.Duration) *TaskEvent {
 	e.StartDelay = int64(delay)
-	e.Details["start_delay"] = fmt.Sprintf("%d", delay)
+	e.Details[TaskEventDetailStartDelay] = fmt.Sprintf("%d", delay)
 	return e
 }
 
 func (e *TaskEvent) SetRestartReason(reason string) *TaskEvent {
 	e.RestartReason = reason
-	e.Details["restart_reason"] = reason
+	e.Details[TaskEventDetailRestartReason] = reason
 	return e
 }
 
 func (e *TaskEvent) SetTaskSignalReason(r string) *TaskEvent {
 	e.TaskSignalReason = r
-	e.Details["task_signal_reason"] = r
+	e.Details[TaskEventDetailTaskSignalReason] = r
 	return e
 }
 
 func (e *TaskEvent) SetTaskSignal(s os.Signal) *TaskEvent {
 	e.TaskSignal = s.String()
-	e.Details["task_signal"] = s.String()
+	e.Details[TaskEventDetailTaskSignal] = s.String()
 	return e
 }
 
 func (e *TaskEvent) SetDownloadError(err error) *TaskEvent {
 	if err != nil {
 		e.DownloadError = err.Error()
-		e.Details["download_error"] = err.Error()
+		e.Details[TaskEventDetailDownloadError] = err.Error()
 	}
 	return e
 }
@@ -8468,7 +9160,7 @@ func (e *TaskEvent) SetDownloadError(err error) *TaskEvent {
 func (e *TaskEvent) SetValidationError(err error) *TaskEvent {
 	if err != nil {
 		e.ValidationError = err.Error()
-		e.Details["validation_error"] = err.Error()
+		e.Details[TaskEventDetailValidationError] = err.Error()
 	}
 	return e
 }
@@ -8476,38 +9168,51 @@ func (e *TaskEvent) SetValidationError(err error) *TaskEvent {
 func (e *TaskEvent) SetKillTimeout(timeout, maxTimeout time.Duration) *TaskEvent {
 	actual := helper.Min(timeout, maxTimeout)
 	e.KillTimeout = actual
-	e.Details["kill_timeout"] = actual.String()
+	e.Details[TaskEventDetailKillTimeout] = actual.String()
 	return e
 }
 
 func (e *TaskEvent) SetDiskLimit(limit int64) *TaskEvent {
 	e.DiskLimit = limit
-	e.Details["disk_limit"] = fmt.Sprintf("%d", limit)
+	e.Details[TaskEventDetailDiskLimit] = fmt.Sprintf("%d", limit)
 	return e
 }
 
 func (e *TaskEvent) SetFailedSibling(sibling string) *TaskEvent {
 	e.FailedSibling = sibling
-	e.Details["failed_sibling"] = sibling
+	e.Details[TaskEventDetailFailedSibling] = sibling
 	return e
 }
 
 func (e *TaskEvent) SetVaultRenewalError(err error) *TaskEvent {
 	if err != nil {
 		e.VaultError = err.Error()
-		e.Details["vault_renewal_error"] = err.Error()
+		e.Details[TaskEventDetailVaultError] = err.Error()
 	}
 	return e
 }
 
 func (e *TaskEvent) SetDriverMessage(m string) *TaskEvent {
 	e.DriverMessage = m
-	e.Details["driver_message"] = m
+	e.Details[TaskEventDetailDriverMessage] = m
 	return e
 }
 
 func (e *TaskEvent) SetOOMKilled(oom bool) *TaskEvent {
-	e.Details["oom_killed"] = strconv.FormatBool(oom)
+	e.Details[TaskEventDetailOOMKilled] = strconv.FormatBool(oom)
+	return e
+}
+
+// SetArtifactURL sets the source of the artifact the event refers to.
+func (e *TaskEvent) SetArtifactURL(url string) *TaskEvent {
+	e.Details[TaskEventDetailArtifactURL] = url
+	return e
+}
+
+// SetTemplatePaths sets the destination paths of the templates that caused
+// the event.
+func (e *TaskEvent) SetTemplatePaths(paths []string) *TaskEvent {
+	e.Details[TaskEventDetailTemplatePath] = strings.Join(paths, ",")
 	return e
 }
 
@@ -8671,6 +9376,7 @@ func (ta *TaskArtifact) validateChecksum() error {
 
 const (
 	ConstraintDistinctProperty  = "distinct_property"
+	ConstraintDistinctTopology  = "distinct_topology"
 	ConstraintDistinctHosts     = "distinct_hosts"
 	ConstraintRegex             = "regexp"
 	ConstraintVersion           = "version"
@@ -8757,6 +9463,10 @@ func (c *Constraint) Validate() error {
 				mErr.Errors = append(mErr.Errors, fmt.Errorf("Distinct Property must have an allowed count of 1 or greater: %d < 1", count))
 			}
 		}
+	case ConstraintDistinctTopology:
+		if _, _, err := c.TopologyLevels(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
 	case ConstraintAttributeIsSet, ConstraintAttributeIsNotSet:
 		if c.RTarget != "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Operator %q does not support an RTarget", c.Operand))
@@ -8777,6 +9487,56 @@ func (c *Constraint) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// TopologyLevels parses a distinct_topology constraint. The LTarget is a
+// hierarchy of targets separated by slashes, from the broadest level to the
+// narrowest, such as "${node.datacenter}/${meta.rack}". The RTarget is either
+// the number of allocations allowed at the narrowest level, or the number
+// allowed at each level separated by slashes, where "*" leaves a level
+// unlimited. It defaults to 1 allocation at the narrowest level.
+//
+// The returned limits match the returned levels, with a limit of 0 for
+// unlimited levels.
+func (c *Constraint) TopologyLevels() ([]string, []uint64, error) {
+	levels := strings.Split(c.LTarget, "/")
+	for _, level := range levels {
+		if !strings.HasPrefix(level, "${") || !strings.HasSuffix(level, "}") {
+			return nil, nil, fmt.Errorf("Distinct Topology level %q must be an interpolated target", level)
+		}
+	}
+
+	limits := make([]uint64, len(levels))
+	if c.RTarget == "" {
+		limits[len(limits)-1] = 1
+		return levels, limits, nil
+	}
+
+	counts := strings.Split(c.RTarget, "/")
+	if len(counts) != 1 && len(counts) != len(levels) {
+		return nil, nil, fmt.Errorf("Distinct Topology must have one allowed count or one per level: %d levels but %d counts", len(levels), len(counts))
+	}
+
+	// A single count applies to the narrowest level
+	offset := len(levels) - len(counts)
+	limited := false
+	for i, v := range counts {
+		if v == "*" {
+			continue
+		}
+		count, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to convert RTarget %q to uint64: %v", v, err)
+		} else if count < 1 {
+			return nil, nil, fmt.Errorf("Distinct Topology must have allowed counts of 1 or greater: %d < 1", count)
+		}
+		limits[offset+i] = count
+		limited = true
+	}
+	if !limited {
+		return nil, nil, errors.New("Distinct Topology must limit at least one level")
+	}
+	return levels, limits, nil
+}
+
 type Constraints []*Constraint
 
 // Equals compares Constraints as a set
@@ -8888,6 +9648,56 @@ func (a *Affinity) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+const (
+	// AntiAffinityScopeNode prevents allocations from sharing a node.
+	AntiAffinityScopeNode = "node"
+)
+
+// AntiAffinity is used to prevent the allocations of a task group from being
+// placed with the allocations of other jobs of the same namespace.
+type AntiAffinity struct {
+	// JobRegexp is matched against the IDs of the other jobs
+	JobRegexp string
+
+	// Scope is the placement level at which the jobs must not co-locate
+	Scope string
+}
+
+func (a *AntiAffinity) Copy() *AntiAffinity {
+	if a == nil {
+		return nil
+	}
+	na := new(AntiAffinity)
+	*na = *a
+	return na
+}
+
+func (a *AntiAffinity) String() string {
+	return fmt.Sprintf("%s %s", a.Scope, a.JobRegexp)
+}
+
+func (a *AntiAffinity) Canonicalize() {
+	if a.Scope == "" {
+		a.Scope = AntiAffinityScopeNode
+	}
+}
+
+func (a *AntiAffinity) Validate() error {
+	var mErr multierror.Error
+	if a.JobRegexp == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing job_regexp"))
+	} else if _, err := regexp.Compile(a.JobRegexp); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Regular expression failed to compile: %v", err))
+	}
+
+	switch a.Scope {
+	case AntiAffinityScopeNode:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Unknown anti-affinity scope %q", a.Scope))
+	}
+	return mErr.ErrorOrNil()
+}
+
 // Spread is used to specify desired distribution of allocations according to weight
 type Spread struct {
 	// Attribute is the node attribute used as the spread criteria
@@ -9157,6 +9967,7 @@ const (
 	DeploymentStatusDescriptionRunning               = "Deployment is running"
 	DeploymentStatusDescriptionRunningNeedsPromotion = "Deployment is running but requires manual promotion"
 	DeploymentStatusDescriptionRunningAutoPromotion  = "Deployment is running pending automatic promotion"
+	DeploymentStatusDescriptionRunningPromotionGate  = "Deployment is running pending automatic promotion by promotion gate"
 	DeploymentStatusDescriptionPaused                = "Deployment is paused"
 	DeploymentStatusDescriptionSuccessful            = "Deployment completed successfully"
 	DeploymentStatusDescriptionStoppedJob            = "Cancelled because job is stopped"
@@ -9190,6 +10001,16 @@ func DeploymentStatusDescriptionNoRollbackTarget(baseDescription string) string
 	return fmt.Sprintf("%s - no stable job version to auto revert to", baseDescription)
 }
 
+// DeploymentStatusDescriptionPromotionGateBlocked is used to get the status
+// description of a deployment whose healthy canaries are not yet allowed to
+// be promoted by a promotion gate.
+func DeploymentStatusDescriptionPromotionGateBlocked(gate, reason string) string {
+	if reason == "" {
+		return fmt.Sprintf("%s - promotion gate %q is blocking promotion", DeploymentStatusDescriptionRunningAutoPromotion, gate)
+	}
+	return fmt.Sprintf("%s - promotion gate %q is blocking promotion: %s", DeploymentStatusDescriptionRunningAutoPromotion, gate, reason)
+}
+
 // Deployment is the object that represents a job deployment which is used to
 // transition a job between versions.
 type Deployment struct {
@@ -9333,6 +10154,20 @@ func (d *Deployment) RequiresPromotion() bool {
 	return false
 }
 
+// HasPromotionGate determines if any taskgroup requires a promotion gate to
+// allow the automatic promotion of its canaries
+func (d *Deployment) HasPromotionGate() bool {
+	if d == nil {
+		return false
+	}
+	for _, group := range d.TaskGroups {
+		if group.DesiredCanaries > 0 && group.PromotionGate != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // HasAutoPromote determines if all taskgroups are marked auto_promote
 func (d *Deployment) HasAutoPromote() bool {
 	if d == nil || len(d.TaskGroups) == 0 || d.Status != DeploymentStatusRunning {
@@ -9364,6 +10199,11 @@ type DeploymentState struct {
 	// copied from TaskGroup UpdateStrategy in scheduler.reconcile
 	AutoPromote bool
 
+	// PromotionGate is the promotion gate that must allow the automatic
+	// promotion of healthy canaries, copied from TaskGroup UpdateStrategy in
+	// scheduler.reconcile
+	PromotionGate string
+
 	// ProgressDeadline is the deadline by which an allocation must transition
 	// to healthy before the deployment is considered failed. This value is set
 	// by the jobspec `update.progress_deadline` field.
@@ -9407,6 +10247,7 @@ func (d *DeploymentState) GoString() string {
 	base += fmt.Sprintf("\n\tUnhealthy: %d", d.UnhealthyAllocs)
 	base += fmt.Sprintf("\n\tAutoRevert: %v", d.AutoRevert)
 	base += fmt.Sprintf("\n\tAutoPromote: %v", d.AutoPromote)
+	base += fmt.Sprintf("\n\tPromotionGate: %v", d.PromotionGate)
 	return base
 }
 
@@ -9503,6 +10344,12 @@ type DesiredTransition struct {
 	// task shutdown_delay configuration and ignore the delay for any
 	// allocations stopped as a result of this Deregister call.
 	NoShutdownDelay *bool
+
+	// Pinned is used to indicate that this allocation must not be migrated
+	// off a draining node nor replaced while its node is down. Unpinning the
+	// allocation, stopping it or reaching the drain deadline of its node
+	// overrides it.
+	Pinned *bool
 }
 
 // Merge merges the two desired transitions, preferring the values from the
@@ -9523,6 +10370,10 @@ func (d *DesiredTransition) Merge(o *DesiredTransition) {
 	if o.NoShutdownDelay != nil {
 		d.NoShutdownDelay = o.NoShutdownDelay
 	}
+
+	if o.Pinned != nil {
+		d.Pinned = o.Pinned
+	}
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -9554,6 +10405,15 @@ func (d *DesiredTransition) ShouldIgnoreShutdownDelay() bool {
 	return d.NoShutdownDelay != nil && *d.NoShutdownDelay
 }
 
+// ShouldPin returns whether the transition object dictates that the
+// allocation stays on its node.
+func (d *DesiredTransition) ShouldPin() bool {
+	if d == nil {
+		return false
+	}
+	return d.Pinned != nil && *d.Pinned
+}
+
 const (
 	AllocDesiredStatusRun   = "run"   // Allocation should run
 	AllocDesiredStatusStop  = "stop"  // Allocation should stop
@@ -9569,6 +10429,42 @@ const (
 	AllocClientStatusUnknown  = "unknown"
 )
 
+// AllocStopReason* are the reasons for stopping an allocation, set by the
+// scheduler alongside the human readable DesiredDescription so the cause of
+// a stop can be queried without reconstructing the evaluation chain.
+const (
+	// AllocStopReasonDrain is set when the node of the allocation is drained.
+	AllocStopReasonDrain = "drain"
+
+	// AllocStopReasonPreempted is set when the allocation is preempted by
+	// an allocation of higher priority.
+	AllocStopReasonPreempted = "preempted"
+
+	// AllocStopReasonScaleIn is set when the count of the task group is
+	// decreased.
+	AllocStopReasonScaleIn = "scale-in"
+
+	// AllocStopReasonJobUpdate is set when the allocation is replaced or
+	// removed by an update of the job.
+	AllocStopReasonJobUpdate = "job-update"
+
+	// AllocStopReasonUserStop is set when the job or the allocation is
+	// stopped by a user.
+	AllocStopReasonUserStop = "user-stop"
+
+	// AllocStopReasonFailed is set when the allocation failed, including
+	// failed health checks, and is rescheduled.
+	AllocStopReasonFailed = "failed"
+
+	// AllocStopReasonLost is set when the node of the allocation is down.
+	AllocStopReasonLost = "lost"
+
+	// AllocStopReasonReconnect is set when either an allocation reconnecting
+	// after its client was disconnected or its replacement is no longer
+	// needed.
+	AllocStopReasonReconnect = "reconnect"
+)
+
 // Allocation is used to allocate the placement of a task group to a node.
 type Allocation struct {
 	// msgpack omit empty fields during serialization
@@ -9632,6 +10528,11 @@ type Allocation struct {
 	// DesiredStatusDescription is meant to provide more human useful information
 	DesiredDescription string
 
+	// StopReason is the machine readable reason the allocation was stopped,
+	// one of the AllocStopReason* constants. It is empty for allocations that
+	// aren't stopped or were stopped by an older version of Nomad.
+	StopReason string
+
 	// DesiredTransition is used to indicate that a state transition
 	// is desired for a given reason.
 	DesiredTransition DesiredTransition
@@ -9757,7 +10658,11 @@ func (a *Allocation) Copy() *Allocation {
 	return a.copyImpl(true)
 }
 
-// CopySkipJob provides a copy of the allocation but doesn't deep copy the job
+// CopySkipJob provides a copy of the allocation but doesn't deep copy the job.
+// The copy shares the job of the allocation, which is safe as long as the job
+// is not mutated: jobs are immutable once they are inserted into the state
+// store, so updates to allocations read from the state store that do not
+// modify their job should use CopySkipJob rather than Copy.
 func (a *Allocation) CopySkipJob() *Allocation {
 	return a.copyImpl(false)
 }
@@ -9767,6 +10672,14 @@ func (a *Allocation) CopySkipJob() *Allocation {
 // Allocations or receiving Allocations from Nomad agents potentially on an
 // older version of Nomad.
 func (a *Allocation) Canonicalize() {
+	a.CanonicalizeSkipJob()
+	a.Job.Canonicalize()
+}
+
+// CanonicalizeSkipJob canonicalizes the Allocation but not its job. It should
+// be used for allocations sharing a job that is already canonicalized, such as
+// the job of an allocation read from the state store.
+func (a *Allocation) CanonicalizeSkipJob() {
 	if a.AllocatedResources == nil && a.TaskResources != nil {
 		ar := AllocatedResources{}
 
@@ -9788,8 +10701,6 @@ func (a *Allocation) Canonicalize() {
 
 		a.AllocatedResources = &ar
 	}
-
-	a.Job.Canonicalize()
 }
 
 func (a *Allocation) copyImpl(job bool) *Allocation {
@@ -10084,12 +10995,19 @@ func (a *Allocation) NextDelay() time.Duration {
 	}
 	delayDur := policy.Delay
 	if a.RescheduleTracker == nil || a.RescheduleTracker.Events == nil || len(a.RescheduleTracker.Events) == 0 {
+		if policy.DelayFunction == "exponential_jitter" {
+			return jitterRescheduleDelay(delayDur, a.ID)
+		}
 		return delayDur
 	}
 	events := a.RescheduleTracker.Events
 	switch policy.DelayFunction {
 	case "exponential":
 		delayDur = a.RescheduleTracker.Events[len(a.RescheduleTracker.Events)-1].Delay * 2
+	case "exponential_jitter":
+		// Double the delay the last event was jittered from
+		last := events[len(events)-1]
+		delayDur = unjitterRescheduleDelay(last.Delay, last.PrevAllocID) * 2
 	case "fibonacci":
 		if len(events) >= 2 {
 			fibN1Delay := events[len(events)-1].Delay
@@ -10117,9 +11035,32 @@ func (a *Allocation) NextDelay() time.Duration {
 
 	}
 
+	if policy.DelayFunction == "exponential_jitter" {
+		delayDur = jitterRescheduleDelay(delayDur, a.ID)
+	}
 	return delayDur
 }
 
+// rescheduleJitter returns the jitter factor of the reschedule delay of an
+// allocation, between 0.5 and 1. It is derived from the allocation ID rather
+// than random so that the delay is the same every time it is computed, while
+// allocations failing at the same time are rescheduled at different times.
+func rescheduleJitter(allocID string) float64 {
+	return 0.5 + 0.5*float64(crc32.ChecksumIEEE([]byte(allocID)))/math.MaxUint32
+}
+
+// jitterRescheduleDelay returns the reschedule delay of the allocation,
+// jittered between half and the whole delay.
+func jitterRescheduleDelay(delay time.Duration, allocID string) time.Duration {
+	return time.Duration(float64(delay) * rescheduleJitter(allocID))
+}
+
+// unjitterRescheduleDelay returns the delay the reschedule delay of the
+// allocation was jittered from, rounded to the millisecond.
+func unjitterRescheduleDelay(delay time.Duration, allocID string) time.Duration {
+	return time.Duration(float64(delay) / rescheduleJitter(allocID)).Round(time.Millisecond)
+}
+
 // Terminated returns if the allocation is in a terminal state on a client.
 func (a *Allocation) Terminated() bool {
 	if a.ClientStatus == AllocClientStatusFailed ||
@@ -10259,6 +11200,33 @@ func (a *Allocation) LookupTask(name string) *Task {
 	return tg.LookupTask(name)
 }
 
+// KillPriority returns the priority of the allocation when several allocations
+// of a node must be stopped, such as when the node is drained or when the
+// client garbage collects allocations. Allocations with a lower kill priority
+// are stopped first. It is the job priority plus the shutdown priority of the
+// task group.
+func (a *Allocation) KillPriority() int {
+	if a.Job == nil {
+		return 0
+	}
+
+	priority := a.Job.Priority
+	if tg := a.Job.LookupTaskGroup(a.TaskGroup); tg != nil {
+		priority += tg.ShutdownPriority
+	}
+	return priority
+}
+
+// PreemptionPriority returns the priority of the allocation for preemption
+// decisions. It is the priority of its task group if set, and otherwise the
+// job priority.
+func (a *Allocation) PreemptionPriority() int {
+	if a.Job == nil {
+		return 0
+	}
+	return a.Job.TaskGroupPriority(a.TaskGroup)
+}
+
 // Stub returns a list stub for the allocation
 func (a *Allocation) Stub(fields *AllocStubFields) *AllocListStub {
 	s := &AllocListStub{
@@ -10272,8 +11240,10 @@ func (a *Allocation) Stub(fields *AllocStubFields) *AllocListStub {
 		JobType:               a.Job.Type,
 		JobVersion:            a.Job.Version,
 		TaskGroup:             a.TaskGroup,
+		KillPriority:          a.KillPriority(),
 		DesiredStatus:         a.DesiredStatus,
 		DesiredDescription:    a.DesiredDescription,
+		StopReason:            a.StopReason,
 		ClientStatus:          a.ClientStatus,
 		ClientDescription:     a.ClientDescription,
 		DesiredTransition:     a.DesiredTransition,
@@ -10436,9 +11406,11 @@ type AllocListStub struct {
 	JobType               string
 	JobVersion            uint64
 	TaskGroup             string
+	KillPriority          int
 	AllocatedResources    *AllocatedResources `json:",omitempty"`
 	DesiredStatus         string
 	DesiredDescription    string
+	StopReason            string
 	ClientStatus          string
 	ClientDescription     string
 	DesiredTransition     DesiredTransition
@@ -10539,6 +11511,10 @@ type AllocMetric struct {
 	// the highest normalized score
 	topScores *kheap.ScoreHeap
 
+	// explanation records the outcome of every node evaluated for the
+	// placement if placement explanations are enabled
+	explanation *PlacementExplanation
+
 	// AllocationTime is a measure of how long the allocation
 	// attempt took. This can affect performance and SLAs.
 	AllocationTime time.Duration
@@ -10572,6 +11548,9 @@ func (a *AllocMetric) EvaluateNode() {
 }
 
 func (a *AllocMetric) FilterNode(node *Node, constraint string) {
+	if e := a.explainNode(node); e != nil {
+		e.FilteredBy = constraint
+	}
 	a.NodesFiltered += 1
 	if node != nil && node.NodeClass != "" {
 		if a.ClassFiltered == nil {
@@ -10588,6 +11567,9 @@ func (a *AllocMetric) FilterNode(node *Node, constraint string) {
 }
 
 func (a *AllocMetric) ExhaustedNode(node *Node, dimension string) {
+	if e := a.explainNode(node); e != nil {
+		e.ExhaustedDimension = dimension
+	}
 	a.NodesExhausted += 1
 	if node != nil && node.NodeClass != "" {
 		if a.ClassExhausted == nil {
@@ -10642,6 +11624,17 @@ func (a *AllocMetric) ExhaustResources(tg *TaskGroup) {
 
 // ScoreNode is used to gather top K scoring nodes in a heap
 func (a *AllocMetric) ScoreNode(node *Node, name string, score float64) {
+	if e := a.explainNode(node); e != nil {
+		if name == NormScorerName {
+			e.NormScore = score
+		} else {
+			if e.Scores == nil {
+				e.Scores = make(map[string]float64)
+			}
+			e.Scores[name] = score
+		}
+	}
+
 	// Create nodeScoreMeta lazily if its the first time or if its a new node
 	if a.nodeScoreMeta == nil || a.nodeScoreMeta.NodeID != node.ID {
 		a.nodeScoreMeta = &NodeScoreMeta{
@@ -10764,6 +11757,11 @@ type AllocDeploymentStatus struct {
 	// been promoted will have this field set to false.
 	Canary bool
 
+	// FailedChecks are the service checks the client saw failing while
+	// determining the health of the allocation, even if they passed later on.
+	// They are formatted as "service/check: status".
+	FailedChecks []string
+
 	// ModifyIndex is the raft index in which the deployment status was last
 	// changed.
 	ModifyIndex uint64
@@ -10814,6 +11812,7 @@ func (a *AllocDeploymentStatus) Copy() *AllocDeploymentStatus {
 	if a.Healthy != nil {
 		c.Healthy = helper.BoolToPtr(*a.Healthy)
 	}
+	c.FailedChecks = helper.CopySliceString(a.FailedChecks)
 
 	return c
 }
@@ -10833,6 +11832,7 @@ const (
 	EvalTriggerNodeDrain            = "node-drain"
 	EvalTriggerNodeUpdate           = "node-update"
 	EvalTriggerAllocStop            = "alloc-stop"
+	EvalTriggerAllocPin             = "alloc-pin"
 	EvalTriggerScheduled            = "scheduled"
 	EvalTriggerRollingUpdate        = "rolling-update"
 	EvalTriggerDeploymentWatcher    = "deployment-watcher"
@@ -10844,6 +11844,7 @@ const (
 	EvalTriggerScaling              = "job-scaling"
 	EvalTriggerMaxDisconnectTimeout = "max-disconnect-timeout"
 	EvalTriggerReconnect            = "reconnect"
+	EvalTriggerRetry                = "eval-retry"
 )
 
 const (
@@ -10883,6 +11884,16 @@ const (
 	// tokens. We periodically scan for expired tokens and delete them.
 	CoreJobOneTimeTokenGC = "one-time-token-gc"
 
+	// CoreJobNodeIntroTokenGC is used for the garbage collection of node
+	// introduction tokens. We periodically scan for expired tokens and
+	// delete them.
+	CoreJobNodeIntroTokenGC = "node-intro-token-gc"
+
+	// CoreJobJobTrashGC is used for the garbage collection of purged jobs
+	// kept in the job trash. We periodically scan for expired jobs and
+	// delete them permanently.
+	CoreJobJobTrashGC = "job-trash-gc"
+
 	// CoreJobRootKeyRotateGC is used for periodic key rotation and
 	// garbage collection of unused encryption keys.
 	CoreJobRootKeyRotateOrGC = "root-key-rotate-gc"
@@ -10916,6 +11927,11 @@ type Evaluation struct {
 	// can preempt other jobs.
 	Priority int
 
+	// Deadline is the time, as a UnixNano, by which the allocations of the
+	// job should be placed. Evaluations with the nearest deadline are dequeued
+	// first among those of the same priority. Zero if the job has no deadline.
+	Deadline int64
+
 	// Type is used to control which schedulers are available to handle
 	// this evaluation.
 	Type string
@@ -10998,6 +12014,12 @@ type Evaluation struct {
 	// during the evaluation. This should not be set during normal operations.
 	AnnotatePlan bool
 
+	// ExplainPlacements triggers the scheduler to evaluate every candidate
+	// node of the placements and to record why each was rejected or how it
+	// was scored in the plan annotations. It requires AnnotatePlan and should
+	// not be set during normal operations.
+	ExplainPlacements bool
+
 	// QueuedAllocations is the number of unplaced allocations at the time the
 	// evaluation was processed. The map is keyed by Task Group names.
 	QueuedAllocations map[string]int
@@ -11007,6 +12029,12 @@ type Evaluation struct {
 	// active. This should not ever be exposed via the API.
 	LeaderACL string
 
+	// Overrides are placement options which replace those of the job when
+	// this evaluation is processed. They are only set when an operator
+	// retries an evaluation with modified options, and are carried over to
+	// any blocked or follow up evaluation created to complete the retry.
+	Overrides *EvalOverrides
+
 	// SnapshotIndex is the Raft index of the snapshot used to process the
 	// evaluation. The index will either be set when it has gone through the
 	// scheduler or if a blocked evaluation is being created. The index is set
@@ -11162,6 +12190,8 @@ func (e *Evaluation) Copy() *Evaluation {
 		ne.QueuedAllocations = queuedAllocations
 	}
 
+	ne.Overrides = e.Overrides.Copy()
+
 	return ne
 }
 
@@ -11215,6 +12245,7 @@ func (e *Evaluation) NextRollingEval(wait time.Duration) *Evaluation {
 		ID:             uuid.Generate(),
 		Namespace:      e.Namespace,
 		Priority:       e.Priority,
+		Deadline:       e.Deadline,
 		Type:           e.Type,
 		TriggeredBy:    EvalTriggerRollingUpdate,
 		JobID:          e.JobID,
@@ -11238,6 +12269,7 @@ func (e *Evaluation) CreateBlockedEval(classEligibility map[string]bool,
 		ID:                   uuid.Generate(),
 		Namespace:            e.Namespace,
 		Priority:             e.Priority,
+		Deadline:             e.Deadline,
 		Type:                 e.Type,
 		TriggeredBy:          EvalTriggerQueuedAllocs,
 		JobID:                e.JobID,
@@ -11248,6 +12280,7 @@ func (e *Evaluation) CreateBlockedEval(classEligibility map[string]bool,
 		ClassEligibility:     classEligibility,
 		EscapedComputedClass: escaped,
 		QuotaLimitReached:    quotaReached,
+		Overrides:            e.Overrides.Copy(),
 		CreateTime:           now,
 		ModifyTime:           now,
 	}
@@ -11263,6 +12296,7 @@ func (e *Evaluation) CreateFailedFollowUpEval(wait time.Duration) *Evaluation {
 		ID:             uuid.Generate(),
 		Namespace:      e.Namespace,
 		Priority:       e.Priority,
+		Deadline:       e.Deadline,
 		Type:           e.Type,
 		TriggeredBy:    EvalTriggerFailedFollowUp,
 		JobID:          e.JobID,
@@ -11270,6 +12304,7 @@ func (e *Evaluation) CreateFailedFollowUpEval(wait time.Duration) *Evaluation {
 		Status:         EvalStatusPending,
 		Wait:           wait,
 		PreviousEval:   e.ID,
+		Overrides:      e.Overrides.Copy(),
 		CreateTime:     now,
 		ModifyTime:     now,
 	}
@@ -11414,8 +12449,9 @@ func (p *Plan) GoString() string {
 }
 
 // AppendStoppedAlloc marks an allocation to be stopped. The clientStatus of the
-// allocation may be optionally set by passing in a non-empty value.
-func (p *Plan) AppendStoppedAlloc(alloc *Allocation, desiredDesc, clientStatus, followupEvalID string) {
+// allocation may be optionally set by passing in a non-empty value. The
+// stopReason is one of the AllocStopReason* constants.
+func (p *Plan) AppendStoppedAlloc(alloc *Allocation, desiredDesc, clientStatus, followupEvalID, stopReason string) {
 	newAlloc := new(Allocation)
 	*newAlloc = *alloc
 
@@ -11433,6 +12469,7 @@ func (p *Plan) AppendStoppedAlloc(alloc *Allocation, desiredDesc, clientStatus,
 
 	newAlloc.DesiredStatus = AllocDesiredStatusStop
 	newAlloc.DesiredDescription = desiredDesc
+	newAlloc.StopReason = stopReason
 
 	if clientStatus != "" {
 		newAlloc.ClientStatus = clientStatus
@@ -11461,6 +12498,7 @@ func (p *Plan) AppendPreemptedAlloc(alloc *Allocation, preemptingAllocID string)
 
 	desiredDesc := fmt.Sprintf("Preempted by alloc ID %v", preemptingAllocID)
 	newAlloc.DesiredDescription = desiredDesc
+	newAlloc.StopReason = AllocStopReasonPreempted
 
 	// TaskResources are needed by the plan applier to check if allocations fit
 	// after removing preempted allocations
@@ -11528,6 +12566,7 @@ func (p *Plan) NormalizeAllocations() {
 			allocs[i] = &Allocation{
 				ID:                 alloc.ID,
 				DesiredDescription: alloc.DesiredDescription,
+				StopReason:         alloc.StopReason,
 				ClientStatus:       alloc.ClientStatus,
 				FollowupEvalID:     alloc.FollowupEvalID,
 			}
@@ -11613,6 +12652,10 @@ type PlanAnnotations struct {
 
 	// PreemptedAllocs is the set of allocations to be preempted to make the placement successful.
 	PreemptedAllocs []*AllocListStub
+
+	// PlacementExplanations explains every placement attempted by the
+	// scheduler if the evaluation set ExplainPlacements.
+	PlacementExplanations []*PlacementExplanation
 }
 
 // DesiredUpdates is the set of changes the scheduler would like to make given
@@ -11670,7 +12713,11 @@ func Encode(t MessageType, msg interface{}) ([]byte, error) {
 type KeyringResponse struct {
 	Messages map[string]string
 	Keys     map[string]int
-	NumNodes int
+
+	// PrimaryKeys maps each key to the number of nodes using it as their
+	// primary encryption key.
+	PrimaryKeys map[string]int
+	NumNodes    int
 }
 
 // KeyringRequest is request objects for serf key operations.
@@ -12158,6 +13205,49 @@ type OneTimeTokenExpireRequest struct {
 	WriteRequest
 }
 
+// DefaultNodeIntroTokenTTL is how long node introduction tokens are valid
+// for when no TTL is given.
+const DefaultNodeIntroTokenTTL = 15 * time.Minute
+
+// NodeIntroToken is a single-use token which allows a client to register
+// for the first time when servers require node introduction.
+type NodeIntroToken struct {
+	SecretID    string
+	AccessorID  string
+	ExpiresAt   time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NodeIntroTokenCreateRequest is the request for a Node.CreateIntroToken RPC
+type NodeIntroTokenCreateRequest struct {
+	// TTL is how long the token is valid for. The server default is used if
+	// it is zero.
+	TTL time.Duration
+	WriteRequest
+}
+
+// NodeIntroTokenCreateResponse is the response to a Node.CreateIntroToken
+// RPC.
+type NodeIntroTokenCreateResponse struct {
+	Token *NodeIntroToken
+	WriteMeta
+}
+
+// NodeIntroTokenConsumeRequest is a request to consume a node introduction
+// token when registering a node
+type NodeIntroTokenConsumeRequest struct {
+	AccessorID string
+	WriteRequest
+}
+
+// NodeIntroTokenExpireRequest is a request to delete all expired node
+// introduction tokens
+type NodeIntroTokenExpireRequest struct {
+	Timestamp time.Time
+	WriteRequest
+}
+
 // RpcError is used for serializing errors with a potential error code
 type RpcError struct {
 	Message string
@@ -146,6 +146,50 @@ func TestJob_ValidateScaling(t *testing.T) {
 	)
 }
 
+func TestJob_ValidateShutdownPriority(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	job.TaskGroups[0].ShutdownPriority = -JobMaxPriority
+	require.NoError(t, job.Validate())
+
+	job.TaskGroups[0].ShutdownPriority = JobMaxPriority + 1
+	require.ErrorContains(t, job.Validate(), "ShutdownPriority must be between")
+}
+
+func TestJob_ValidateTaskGroupPriority(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	job.TaskGroups[0].Priority = JobMinPriority
+	require.NoError(t, job.Validate())
+	require.Equal(t, JobMinPriority, job.TaskGroupPriority(job.TaskGroups[0].Name))
+
+	job.TaskGroups[0].Priority = 0
+	require.Equal(t, job.Priority, job.TaskGroupPriority(job.TaskGroups[0].Name))
+
+	job.TaskGroups[0].Priority = JobMaxPriority + 1
+	require.ErrorContains(t, job.Validate(), "priority must be between")
+}
+
+func TestJob_ValidateDeadline(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	job.Type = JobTypeBatch
+	job.Deadline = time.Hour
+	job.SubmitTime = 10
+	require.NoError(t, job.Validate())
+	require.Equal(t, int64(10)+int64(time.Hour), job.DeadlineTime())
+
+	job.Deadline = -time.Hour
+	require.ErrorContains(t, job.Validate(), "must not be negative")
+
+	job.Type = JobTypeService
+	job.Deadline = time.Hour
+	require.ErrorContains(t, job.Validate(), "only supported")
+}
+
 func TestJob_ValidateNullChar(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1605,6 +1649,94 @@ func TestTask_Validate(t *testing.T) {
 	)
 }
 
+func TestTask_Validate_ReadinessFile(t *testing.T) {
+	ci.Parallel(t)
+
+	task := &Task{
+		Name:   "web",
+		Driver: "docker",
+		Resources: &Resources{
+			CPU:      100,
+			MemoryMB: 100,
+		},
+		LogConfig:     DefaultLogConfig(),
+		ReadinessFile: "ready/web",
+	}
+	ephemeralDisk := DefaultEphemeralDisk()
+	require.NoError(t, task.Validate(ephemeralDisk, JobTypeService, nil, nil))
+
+	task.ReadinessFile = "/tmp/ready"
+	requireErrors(t, task.Validate(ephemeralDisk, JobTypeService, nil, nil),
+		"ReadinessFile must be relative",
+	)
+
+	task.ReadinessFile = "../../etc/ready"
+	requireErrors(t, task.Validate(ephemeralDisk, JobTypeService, nil, nil),
+		"ReadinessFile escapes the allocation directory",
+	)
+}
+
+func TestTask_Canonicalize_ReadinessFileCheck(t *testing.T) {
+	ci.Parallel(t)
+
+	job := &Job{Name: "example", Namespace: DefaultNamespace}
+	tg := &TaskGroup{Name: "web"}
+	task := &Task{
+		Name:          "web",
+		ReadinessFile: "ready/web",
+		Services: []*Service{{
+			Name:     "web",
+			Provider: ServiceProviderNomad,
+			Checks: []*ServiceCheck{
+				{Name: "default", Type: ServiceCheckReadinessFile},
+				{Name: "custom", Type: ServiceCheckReadinessFile, Path: "ready/other"},
+				{Name: "tcp", Type: ServiceCheckTCP},
+			},
+		}},
+	}
+	task.Canonicalize(job, tg)
+
+	checks := task.Services[0].Checks
+	require.Equal(t, "ready/web", checks[0].Path)
+	require.Equal(t, "ready/other", checks[1].Path)
+	require.Equal(t, "", checks[2].Path)
+}
+
+func TestTaskProcess_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	p := &TaskProcess{
+		Umask:       "022",
+		Nice:        10,
+		IOniceClass: TaskProcessIOniceClassIdle,
+		Rlimits:     map[string]uint64{"nofile": 1024, "core": 0},
+	}
+	require.NoError(t, p.Validate())
+
+	p = &TaskProcess{
+		Umask:       "0888",
+		Nice:        20,
+		IOniceClass: "fast",
+		Rlimits:     map[string]uint64{"files": 1024},
+	}
+	requireErrors(t, p.Validate(),
+		"invalid umask",
+		"nice must be between",
+		"invalid ionice_class",
+		"unknown rlimit",
+	)
+}
+
+func TestWorkloadIdentity_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	require.NoError(t, (*WorkloadIdentity)(nil).Validate())
+	require.NoError(t, (&WorkloadIdentity{File: true}).Validate())
+	require.NoError(t, (&WorkloadIdentity{File: true, TaskAPI: true}).Validate())
+	require.EqualError(t, (&WorkloadIdentity{TaskAPI: true}).Validate(),
+		"task_api requires file to be enabled")
+}
+
 func TestTask_Validate_Resources(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2635,6 +2767,41 @@ func TestTemplate_Validate(t *testing.T) {
 				"destination escapes",
 			},
 		},
+		{
+			Tmpl: &Template{
+				SourcePath:    "foo",
+				DestPath:      "local/foo",
+				ChangeMode:    "noop",
+				OnRenderError: "ignore",
+			},
+			Fail: true,
+			ContainsErrs: []string{
+				`Invalid on_render_error "ignore"`,
+			},
+		},
+		{
+			Tmpl: &Template{
+				SourcePath:     "foo",
+				DestPath:       "local/foo",
+				ChangeMode:     "noop",
+				OnRenderError:  TemplateOnRenderErrorFail,
+				RenderMaxStale: time.Minute,
+			},
+			Fail: true,
+			ContainsErrs: []string{
+				"render_max_stale requires on_render_error retry or keep_last",
+			},
+		},
+		{
+			Tmpl: &Template{
+				SourcePath:     "foo",
+				DestPath:       "local/foo",
+				ChangeMode:     "noop",
+				OnRenderError:  TemplateOnRenderErrorKeepLast,
+				RenderMaxStale: time.Minute,
+			},
+			Fail: false,
+		},
 		{
 			Tmpl: &Template{
 				SourcePath: "foo",
@@ -2849,6 +3016,89 @@ func TestConstraint_Validate(t *testing.T) {
 	require.Error(t, err, "Unknown constraint type")
 }
 
+func TestConstraint_TopologyLevels(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name      string
+		lTarget   string
+		rTarget   string
+		expLevels []string
+		expLimits []uint64
+		expErr    string
+	}{
+		{
+			name:      "default count",
+			lTarget:   "${node.datacenter}/${meta.rack}",
+			expLevels: []string{"${node.datacenter}", "${meta.rack}"},
+			expLimits: []uint64{0, 1},
+		},
+		{
+			name:      "single count",
+			lTarget:   "${node.datacenter}/${meta.rack}",
+			rTarget:   "3",
+			expLevels: []string{"${node.datacenter}", "${meta.rack}"},
+			expLimits: []uint64{0, 3},
+		},
+		{
+			name:      "count per level",
+			lTarget:   "${node.datacenter}/${meta.rack}/${node.unique.id}",
+			rTarget:   "10/*/1",
+			expLevels: []string{"${node.datacenter}", "${meta.rack}", "${node.unique.id}"},
+			expLimits: []uint64{10, 0, 1},
+		},
+		{
+			name:    "literal level",
+			lTarget: "${node.datacenter}/rack",
+			expErr:  "must be an interpolated target",
+		},
+		{
+			name:    "count mismatch",
+			lTarget: "${node.datacenter}/${meta.rack}/${node.unique.id}",
+			rTarget: "2/1",
+			expErr:  "3 levels but 2 counts",
+		},
+		{
+			name:    "zero count",
+			lTarget: "${node.datacenter}/${meta.rack}",
+			rTarget: "*/0",
+			expErr:  "1 or greater",
+		},
+		{
+			name:    "invalid count",
+			lTarget: "${node.datacenter}/${meta.rack}",
+			rTarget: "-1",
+			expErr:  "to uint64",
+		},
+		{
+			name:    "unlimited",
+			lTarget: "${node.datacenter}/${meta.rack}",
+			rTarget: "*/*",
+			expErr:  "at least one level",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Constraint{
+				Operand: ConstraintDistinctTopology,
+				LTarget: tc.lTarget,
+				RTarget: tc.rTarget,
+			}
+			levels, limits, err := c.TopologyLevels()
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				require.ErrorContains(t, c.Validate(), tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, c.Validate())
+			require.Equal(t, tc.expLevels, levels)
+			require.Equal(t, tc.expLimits, limits)
+		})
+	}
+}
+
 func TestAffinity_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2937,6 +3187,49 @@ func TestAffinity_Validate(t *testing.T) {
 	}
 }
 
+func TestAntiAffinity_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	type tc struct {
+		antiAffinity *AntiAffinity
+		err          error
+		name         string
+	}
+	testCases := []tc{
+		{
+			antiAffinity: &AntiAffinity{Scope: AntiAffinityScopeNode},
+			err:          fmt.Errorf("Missing job_regexp"),
+			name:         "missing regexp",
+		},
+		{
+			antiAffinity: &AntiAffinity{JobRegexp: "\\K2.0", Scope: AntiAffinityScopeNode},
+			err:          fmt.Errorf("Regular expression failed to compile"),
+			name:         "invalid regexp",
+		},
+		{
+			antiAffinity: &AntiAffinity{JobRegexp: "^cache-", Scope: "rack"},
+			err:          fmt.Errorf("Unknown anti-affinity scope \"rack\""),
+			name:         "invalid scope",
+		},
+		{
+			antiAffinity: &AntiAffinity{JobRegexp: "^cache-", Scope: AntiAffinityScopeNode},
+			name:         "valid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.antiAffinity.Validate()
+			if tc.err != nil {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tc.err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
 func TestUpdateStrategy_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2947,6 +3240,7 @@ func TestUpdateStrategy_Validate(t *testing.T) {
 		HealthyDeadline:  -15,
 		ProgressDeadline: -25,
 		AutoRevert:       false,
+		PromotionGate:    "error-rate",
 		Canary:           -1,
 	}
 
@@ -2955,6 +3249,7 @@ func TestUpdateStrategy_Validate(t *testing.T) {
 		"Invalid health check given",
 		"Max parallel can not be less than zero",
 		"Canary count can not be less than zero",
+		"Promotion gate requires Auto Promote to be enabled",
 		"Minimum healthy time may not be less than zero",
 		"Healthy deadline must be greater than zero",
 		"Progress deadline must be zero or greater",
@@ -3250,6 +3545,28 @@ func BenchmarkEncodeDecode(b *testing.B) {
 	}
 }
 
+func BenchmarkAllocation_Copy(b *testing.B) {
+	alloc := MockAlloc()
+	for i := 0; i < 50; i++ {
+		tg := alloc.Job.TaskGroups[0].Copy()
+		tg.Name = fmt.Sprintf("web-%d", i)
+		alloc.Job.TaskGroups = append(alloc.Job.TaskGroups, tg)
+	}
+
+	b.Run("Copy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			alloc.Copy()
+		}
+	})
+	b.Run("CopySkipJob", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			alloc.CopySkipJob()
+		}
+	})
+}
+
 func TestInvalidServiceCheck(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3882,6 +4199,15 @@ func TestReschedulePolicy_Validate(t *testing.T) {
 				MaxDelay:      5 * time.Minute,
 				DelayFunction: "exponential"},
 		},
+		{
+			desc: "Valid Exponential Jitter Delay",
+			ReschedulePolicy: &ReschedulePolicy{
+				Attempts:      5,
+				Interval:      1 * time.Hour,
+				Delay:         30 * time.Second,
+				MaxDelay:      5 * time.Minute,
+				DelayFunction: "exponential_jitter"},
+		},
 		{
 			desc: "Valid Fibonacci Delay",
 			ReschedulePolicy: &ReschedulePolicy{
@@ -4034,6 +4360,28 @@ func TestReschedulePolicy_Validate(t *testing.T) {
 	}
 }
 
+func TestAllocation_KillPriority(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &Allocation{
+		TaskGroup: "cache",
+		Job: &Job{
+			Priority: 50,
+			TaskGroups: []*TaskGroup{
+				{Name: "cache", ShutdownPriority: -20},
+				{Name: "web"},
+			},
+		},
+	}
+	require.Equal(t, 30, a.KillPriority())
+
+	a.TaskGroup = "web"
+	require.Equal(t, 50, a.KillPriority())
+
+	a.Job = nil
+	require.Equal(t, 0, a.KillPriority())
+}
+
 func TestAllocation_Index(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4334,7 +4682,7 @@ func TestPlan_NormalizeAllocations(t *testing.T) {
 	}
 	stoppedAlloc := MockAlloc()
 	desiredDesc := "Desired desc"
-	plan.AppendStoppedAlloc(stoppedAlloc, desiredDesc, AllocClientStatusLost, "followup-eval-id")
+	plan.AppendStoppedAlloc(stoppedAlloc, desiredDesc, AllocClientStatusLost, "followup-eval-id", AllocStopReasonLost)
 	preemptedAlloc := MockAlloc()
 	preemptingAllocID := uuid.Generate()
 	plan.AppendPreemptedAlloc(preemptedAlloc, preemptingAllocID)
@@ -4345,6 +4693,7 @@ func TestPlan_NormalizeAllocations(t *testing.T) {
 	expectedStoppedAlloc := &Allocation{
 		ID:                 stoppedAlloc.ID,
 		DesiredDescription: desiredDesc,
+		StopReason:         AllocStopReasonLost,
 		ClientStatus:       AllocClientStatusLost,
 		FollowupEvalID:     "followup-eval-id",
 	}
@@ -4365,11 +4714,12 @@ func TestPlan_AppendStoppedAllocAppendsAllocWithUpdatedAttrs(t *testing.T) {
 	alloc := MockAlloc()
 	desiredDesc := "Desired desc"
 
-	plan.AppendStoppedAlloc(alloc, desiredDesc, AllocClientStatusLost, "")
+	plan.AppendStoppedAlloc(alloc, desiredDesc, AllocClientStatusLost, "", AllocStopReasonLost)
 
 	expectedAlloc := new(Allocation)
 	*expectedAlloc = *alloc
 	expectedAlloc.DesiredDescription = desiredDesc
+	expectedAlloc.StopReason = AllocStopReasonLost
 	expectedAlloc.DesiredStatus = AllocDesiredStatusStop
 	expectedAlloc.ClientStatus = AllocClientStatusLost
 	expectedAlloc.Job = nil
@@ -4404,6 +4754,7 @@ func TestPlan_AppendPreemptedAllocAppendsAllocWithUpdatedAttrs(t *testing.T) {
 		Namespace:             alloc.Namespace,
 		DesiredStatus:         AllocDesiredStatusEvict,
 		DesiredDescription:    fmt.Sprintf("Preempted by alloc ID %v", preemptingAllocID),
+		StopReason:            AllocStopReasonPreempted,
 		AllocatedResources:    alloc.AllocatedResources,
 		TaskResources:         alloc.TaskResources,
 		SharedResources:       alloc.SharedResources,
@@ -5153,6 +5504,60 @@ func TestAllocation_NextDelay(t *testing.T) {
 
 }
 
+func TestAllocation_NextDelay_ExponentialJitter(t *testing.T) {
+	ci.Parallel(t)
+
+	policy := &ReschedulePolicy{
+		DelayFunction: "exponential_jitter",
+		Delay:         5 * time.Second,
+		MaxDelay:      30 * time.Second,
+		Unlimited:     true,
+	}
+	job := &Job{TaskGroups: []*TaskGroup{{Name: "web", ReschedulePolicy: policy}}}
+	now := time.Now()
+
+	// Reschedule a chain of failed allocations
+	var events []*RescheduleEvent
+	for _, base := range []time.Duration{5, 10, 20, 30, 30} {
+		base *= time.Second
+		alloc := &Allocation{
+			ID:                uuid.Generate(),
+			Job:               job,
+			TaskGroup:         "web",
+			ClientStatus:      AllocClientStatusFailed,
+			ModifyTime:        now.UnixNano(),
+			RescheduleTracker: &RescheduleTracker{Events: events},
+		}
+
+		delay := alloc.NextDelay()
+		require.GreaterOrEqual(t, delay, base/2)
+		require.LessOrEqual(t, delay, base)
+
+		// The delay is the same every time it is computed
+		require.Equal(t, delay, alloc.NextDelay())
+
+		events = append(events, &RescheduleEvent{
+			RescheduleTime: now.UnixNano(),
+			PrevAllocID:    alloc.ID,
+			Delay:          delay,
+		})
+	}
+
+	// The delay is reset once the last reschedule is older than the maximum
+	// delay
+	alloc := &Allocation{
+		ID:                uuid.Generate(),
+		Job:               job,
+		TaskGroup:         "web",
+		ClientStatus:      AllocClientStatusFailed,
+		ModifyTime:        now.Add(time.Minute).UnixNano(),
+		RescheduleTracker: &RescheduleTracker{Events: events},
+	}
+	delay := alloc.NextDelay()
+	require.GreaterOrEqual(t, delay, policy.Delay/2)
+	require.LessOrEqual(t, delay, policy.Delay)
+}
+
 func TestAllocation_WaitClientStop(t *testing.T) {
 	ci.Parallel(t)
 	type testCase struct {
@@ -5707,6 +6112,17 @@ func TestParameterizedJobConfig_Validate(t *testing.T) {
 	if err := d.Validate(); err == nil || !strings.Contains(err.Error(), "disjoint") {
 		t.Fatalf("Expected meta not being disjoint error: %v", err)
 	}
+
+	d.MetaRequired = nil
+	d.Dispatch = &DispatchConfig{PurgeAfter: -1 * time.Hour, KeepLast: 10}
+	if err := d.Validate(); err == nil || !strings.Contains(err.Error(), "purge_after") {
+		t.Fatalf("Expected negative purge_after error: %v", err)
+	}
+
+	d.Dispatch = &DispatchConfig{PurgeAfter: time.Hour, KeepLast: -1}
+	if err := d.Validate(); err == nil || !strings.Contains(err.Error(), "keep_last") {
+		t.Fatalf("Expected negative keep_last error: %v", err)
+	}
 }
 
 func TestParameterizedJobConfig_Validate_NonBatch(t *testing.T) {
@@ -6167,6 +6583,61 @@ func TestTaskEventPopulate(t *testing.T) {
 	}
 }
 
+func TestTaskEvent_Kind(t *testing.T) {
+	ci.Parallel(t)
+
+	testcases := []struct {
+		event    *TaskEvent
+		expected TaskEventKind
+	}{
+		{NewTaskEvent(TaskStarted), TaskEventKindLifecycle},
+		{NewTaskEvent(TaskTerminated), TaskEventKindLifecycle},
+		{NewTaskEvent(TaskDriverFailure), TaskEventKindFailure},
+		{NewTaskEvent(TaskRestarting), TaskEventKindRestart},
+		{NewTaskEvent(TaskKilling), TaskEventKindKill},
+		{NewTaskEvent(TaskSignaling), TaskEventKindSignal},
+		{NewTaskEvent(TaskArtifactDownloadFailed), TaskEventKindArtifact},
+		{NewTaskEvent("Template"), TaskEventKindTemplate},
+		{NewTaskEvent(TaskDriverMessage), TaskEventKindDriver},
+		{NewTaskEvent(TaskDriverUnhealthy), TaskEventKindDriver},
+		{NewTaskEvent(TaskPluginUnhealthy), TaskEventKindPlugin},
+		{NewTaskEvent("Unknown Type"), TaskEventKindOther},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.event.Type, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.event.Kind)
+		})
+	}
+
+	// Events from older clients get their kind when populated
+	old := &TaskEvent{Type: TaskKilled}
+	old.PopulateEventDisplayMessage()
+	require.Equal(t, TaskEventKindKill, old.Kind)
+}
+
+func TestTaskEvent_Details(t *testing.T) {
+	ci.Parallel(t)
+
+	e := NewTaskEvent(TaskTerminated).
+		SetExitCode(137).
+		SetSignal(9).
+		SetOOMKilled(true)
+	require.Equal(t, map[string]string{
+		TaskEventDetailExitCode:  "137",
+		TaskEventDetailSignal:    "9",
+		TaskEventDetailOOMKilled: "true",
+	}, e.Details)
+
+	e = NewTaskEvent(TaskArtifactDownloadFailed).
+		SetArtifactURL("https://example.com/file.tgz")
+	require.Equal(t, "https://example.com/file.tgz", e.Details[TaskEventDetailArtifactURL])
+
+	e = NewTaskEvent(TaskRestartSignal).
+		SetTemplatePaths([]string{"local/a.conf", "secrets/b.env"})
+	require.Equal(t, "local/a.conf,secrets/b.env", e.Details[TaskEventDetailTemplatePath])
+}
+
 func TestNetworkResourcesEquals(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6444,6 +6915,13 @@ func TestNode_Copy(t *testing.T) {
 				UpdateTime:        time.Now(),
 			},
 		},
+		LastRestore: &NodeRestoreReport{
+			RestoredAt: time.Now(),
+			Reattached: 2,
+			Failed: []*NodeTaskRestoreFailure{
+				{AllocID: "a", TaskName: "web", Error: "task not found"},
+			},
+		},
 	}
 	node.ComputeClass()
 
@@ -6457,6 +6935,8 @@ func TestNode_Copy(t *testing.T) {
 	require.Equal(node.Events, node2.Events)
 	require.Equal(node.DrainStrategy, node2.DrainStrategy)
 	require.Equal(node.Drivers, node2.Drivers)
+	require.Equal(node.LastRestore, node2.LastRestore)
+	require.NotSame(node.LastRestore.Failed[0], node2.LastRestore.Failed[0])
 }
 
 func TestNode_GetID(t *testing.T) {
@@ -40,7 +40,7 @@ func (s *System) GarbageCollect(args *structs.GenericRequest, reply *structs.Gen
 
 // ReconcileJobSummaries reconciles the summaries of all the jobs in the state
 // store
-func (s *System) ReconcileJobSummaries(args *structs.GenericRequest, reply *structs.GenericResponse) error {
+func (s *System) ReconcileJobSummaries(args *structs.ReconcileRequest, reply *structs.ReconcileResponse) error {
 	if done, err := s.srv.forward("System.ReconcileJobSummaries", args, args, reply); done {
 		return err
 	}
@@ -52,6 +52,15 @@ func (s *System) ReconcileJobSummaries(args *structs.GenericRequest, reply *stru
 		return structs.ErrPermissionDenied
 	}
 
+	changes, err := s.srv.fsm.State().JobSummaryChanges()
+	if err != nil {
+		return fmt.Errorf("failed to compute job summary changes: %v", err)
+	}
+	reply.Changes = changes
+	if args.DryRun {
+		return nil
+	}
+
 	_, index, err := s.srv.raftApply(structs.ReconcileJobSummariesRequestType, args)
 	if err != nil {
 		return fmt.Errorf("reconciliation of job summaries failed: %v", err)
@@ -59,3 +68,73 @@ func (s *System) ReconcileJobSummaries(args *structs.GenericRequest, reply *stru
 	reply.Index = index
 	return nil
 }
+
+// ReconcileDeployments reconciles the allocation counts of the active
+// deployments with their allocations.
+func (s *System) ReconcileDeployments(args *structs.ReconcileRequest, reply *structs.ReconcileResponse) error {
+	if done, err := s.srv.forward("System.ReconcileDeployments", args, args, reply); done {
+		return err
+	}
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	changes, err := s.srv.fsm.State().DeploymentChanges()
+	if err != nil {
+		return fmt.Errorf("failed to compute deployment changes: %v", err)
+	}
+	reply.Changes = changes
+	if args.DryRun || len(changes) == 0 {
+		return nil
+	}
+
+	if !ServersMeetMinimumVersion(s.srv.Members(), minReconcileVersion, false) {
+		return fmt.Errorf("All servers should be running version %v or later to apply the reconciliation", minReconcileVersion)
+	}
+
+	_, index, err := s.srv.raftApply(structs.ReconcileDeploymentsRequestType, args)
+	if err != nil {
+		return fmt.Errorf("reconciliation of deployments failed: %v", err)
+	}
+	reply.Index = index
+	return nil
+}
+
+// ReconcileServiceRegistrations removes the native service registrations of
+// allocations which are missing or terminal on the client.
+func (s *System) ReconcileServiceRegistrations(args *structs.ReconcileRequest, reply *structs.ReconcileResponse) error {
+	if done, err := s.srv.forward("System.ReconcileServiceRegistrations", args, args, reply); done {
+		return err
+	}
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	changes, err := s.srv.fsm.State().ServiceRegistrationChanges()
+	if err != nil {
+		return fmt.Errorf("failed to compute service registration changes: %v", err)
+	}
+	reply.Changes = changes
+	if args.DryRun || len(changes) == 0 {
+		return nil
+	}
+
+	if !ServersMeetMinimumVersion(s.srv.Members(), minReconcileVersion, false) {
+		return fmt.Errorf("All servers should be running version %v or later to apply the reconciliation", minReconcileVersion)
+	}
+
+	_, index, err := s.srv.raftApply(structs.ReconcileServiceRegistrationsRequestType, args)
+	if err != nil {
+		return fmt.Errorf("reconciliation of service registrations failed: %v", err)
+	}
+	reply.Index = index
+	return nil
+}
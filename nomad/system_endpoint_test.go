@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSystemEndpoint_GarbageCollect(t *testing.T) {
@@ -218,3 +219,192 @@ func TestSystemEndpoint_ReconcileJobSummaries_ACL(t *testing.T) {
 		assert.Nil(msgpackrpc.CallWithCodec(codec, "System.ReconcileJobSummaries", req, &resp))
 	}
 }
+
+func TestSystemEndpoint_ReconcileSummaries_DryRun(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert a job and delete its summary
+	state := s1.fsm.State()
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+	require.NoError(t, state.DeleteJobSummary(1001, job.Namespace, job.ID))
+
+	// A dry run returns the summary without creating it
+	req := &structs.ReconcileRequest{
+		DryRun: true,
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+	var resp structs.ReconcileResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "System.ReconcileJobSummaries", req, &resp))
+	require.Len(t, resp.Changes, 1)
+	require.Equal(t, structs.DiffTypeAdded, resp.Changes[0].Type)
+	require.Equal(t, job.ID, resp.Changes[0].ID)
+	require.Zero(t, resp.Index)
+
+	summary, err := state.JobSummaryByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Nil(t, summary)
+
+	// Reconcile the summaries
+	req.DryRun = false
+	var resp2 structs.ReconcileResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "System.ReconcileJobSummaries", req, &resp2))
+	require.Len(t, resp2.Changes, 1)
+	require.NotZero(t, resp2.Index)
+
+	summary, err = state.JobSummaryByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+}
+
+func TestSystemEndpoint_ReconcileDeployments(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert a deployment whose counts drift from its allocations
+	state := s1.fsm.State()
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	d := mock.Deployment()
+	d.JobID = job.ID
+	d.TaskGroups["web"].HealthyAllocs = 2
+	require.NoError(t, state.UpsertDeployment(1001, d))
+
+	// A dry run returns the changes without applying them
+	req := &structs.ReconcileRequest{
+		DryRun: true,
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+	var resp structs.ReconcileResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "System.ReconcileDeployments", req, &resp))
+	require.Len(t, resp.Changes, 1)
+	require.Equal(t, d.ID, resp.Changes[0].ID)
+	require.Equal(t, []*structs.FieldDiff{
+		{Type: structs.DiffTypeEdited, Name: "TaskGroups[web].HealthyAllocs", Old: "2", New: "0"},
+	}, resp.Changes[0].Fields)
+
+	out, err := state.DeploymentByID(nil, d.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, out.TaskGroups["web"].HealthyAllocs)
+
+	// Reconcile the deployments
+	req.DryRun = false
+	var resp2 structs.ReconcileResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "System.ReconcileDeployments", req, &resp2))
+	require.Len(t, resp2.Changes, 1)
+	require.NotZero(t, resp2.Index)
+
+	out, err = state.DeploymentByID(nil, d.ID)
+	require.NoError(t, err)
+	require.Zero(t, out.TaskGroups["web"].HealthyAllocs)
+	require.Equal(t, resp2.Index, out.ModifyIndex)
+}
+
+func TestSystemEndpoint_ReconcileDeployments_OldServers(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+	})
+	defer cleanupS1()
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+
+		// simulate a server that can't apply the reconciliation
+		c.Build = "1.3.3"
+	})
+	defer cleanupS2()
+
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Insert the deployment on the leader which computes the changes
+	leader := s1
+	if ok, _ := s1.getLeader(); !ok {
+		leader = s2
+	}
+	codec := rpcClient(t, leader)
+
+	state := leader.fsm.State()
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	d := mock.Deployment()
+	d.JobID = job.ID
+	d.TaskGroups["web"].HealthyAllocs = 2
+	require.NoError(t, state.UpsertDeployment(1001, d))
+
+	// A dry run still returns the changes
+	req := &structs.ReconcileRequest{
+		DryRun: true,
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+	var resp structs.ReconcileResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "System.ReconcileDeployments", req, &resp))
+	require.Len(t, resp.Changes, 1)
+
+	// Applying the changes is rejected
+	req.DryRun = false
+	err := msgpackrpc.CallWithCodec(codec, "System.ReconcileDeployments", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "All servers should be running version")
+}
+
+func TestSystemEndpoint_ReconcileServiceRegistrations(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert service registrations whose allocations are missing
+	state := s1.fsm.State()
+	services := mock.ServiceRegistrations()
+	require.NoError(t, state.UpsertServiceRegistrations(structs.MsgTypeTestSetup, 1000, services))
+
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyWrite))
+
+	req := &structs.ReconcileRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: invalidToken.SecretID,
+		},
+	}
+
+	// Try with an invalid token and expect failure
+	var resp structs.ReconcileResponse
+	err := msgpackrpc.CallWithCodec(codec, "System.ReconcileServiceRegistrations", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Reconcile with a management token
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "System.ReconcileServiceRegistrations", req, &resp))
+	require.Len(t, resp.Changes, 2)
+	for _, change := range resp.Changes {
+		require.Equal(t, structs.DiffTypeDeleted, change.Type)
+	}
+
+	iter, err := state.GetServiceRegistrations(nil)
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+}
@@ -51,6 +51,11 @@ type serverParts struct {
 	RPCAddr     net.Addr
 	Status      serf.MemberStatus
 	NonVoter    bool
+	ReadReplica bool
+
+	// RPCCompression is whether the server accepts zstd compressed RPC
+	// connections.
+	RPCCompression bool
 
 	// Deprecated: Functionally unused but needs to always be set by 1 for
 	// compatibility with v1.2.x and earlier.
@@ -122,23 +127,31 @@ func isNomadServer(m serf.Member) (bool, *serverParts) {
 	// Check if the server is a non voter
 	_, nonVoter := m.Tags["nonvoter"]
 
+	// Check if the server is a read replica
+	_, readReplica := m.Tags["read_replica"]
+
+	// Check if the server accepts compressed RPC connections
+	_, rpcCompression := m.Tags["rpc_zstd"]
+
 	addr := &net.TCPAddr{IP: m.Addr, Port: port}
 	rpcAddr := &net.TCPAddr{IP: rpcIP, Port: port}
 	parts := &serverParts{
-		Name:         m.Name,
-		ID:           id,
-		Region:       region,
-		Datacenter:   datacenter,
-		Port:         port,
-		Bootstrap:    bootstrap,
-		Expect:       expect,
-		Addr:         addr,
-		RPCAddr:      rpcAddr,
-		Build:        *buildVersion,
-		RaftVersion:  raftVsn,
-		Status:       m.Status,
-		NonVoter:     nonVoter,
-		MajorVersion: deprecatedAPIMajorVersion,
+		Name:           m.Name,
+		ID:             id,
+		Region:         region,
+		Datacenter:     datacenter,
+		Port:           port,
+		Bootstrap:      bootstrap,
+		Expect:         expect,
+		Addr:           addr,
+		RPCAddr:        rpcAddr,
+		Build:          *buildVersion,
+		RaftVersion:    raftVsn,
+		Status:         m.Status,
+		NonVoter:       nonVoter,
+		ReadReplica:    readReplica,
+		RPCCompression: rpcCompression,
+		MajorVersion:   deprecatedAPIMajorVersion,
 	}
 	return true, parts
 }
@@ -28,6 +28,7 @@ func TestIsNomadServer(t *testing.T) {
 			"raft_vsn": "2",
 			"build":    "0.7.0+ent",
 			"nonvoter": "1",
+			"rpc_zstd": "1",
 		},
 	}
 	valid, parts := isNomadServer(m)
@@ -62,6 +63,9 @@ func TestIsNomadServer(t *testing.T) {
 	if !parts.NonVoter {
 		t.Fatalf("should be nonvoter")
 	}
+	if !parts.RPCCompression {
+		t.Fatalf("should support rpc compression")
+	}
 
 	m.Tags["bootstrap"] = "1"
 	valid, parts = isNomadServer(m)
@@ -84,6 +88,12 @@ func TestIsNomadServer(t *testing.T) {
 	if !valid || parts.NonVoter {
 		t.Fatalf("should be a voter")
 	}
+
+	delete(m.Tags, "rpc_zstd")
+	valid, parts = isNomadServer(m)
+	if !valid || parts.RPCCompression {
+		t.Fatalf("should not support rpc compression")
+	}
 }
 
 func TestServersMeetMinimumVersionExcludingFailed(t *testing.T) {
@@ -30,6 +30,8 @@ func (b *BasePluginClient) PluginInfo() (*PluginInfoResponse, error) {
 		ptype = PluginTypeDriver
 	case proto.PluginType_DEVICE:
 		ptype = PluginTypeDevice
+	case proto.PluginType_FINGERPRINT:
+		ptype = PluginTypeFingerprint
 	default:
 		return nil, fmt.Errorf("plugin is of unknown type: %q", presp.GetType().String())
 	}
@@ -20,6 +20,9 @@ const (
 
 	// PluginTypeDevice implements the device plugin interface
 	PluginTypeDevice = "device"
+
+	// PluginTypeFingerprint implements the fingerprint plugin interface
+	PluginTypeFingerprint = "fingerprint"
 )
 
 var (
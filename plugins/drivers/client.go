@@ -74,6 +74,9 @@ func (d *driverPluginClient) Capabilities() (*Capabilities, error) {
 
 		caps.MountConfigs = MountConfigSupport(resp.Capabilities.MountConfigs)
 		caps.RemoteTasks = resp.Capabilities.RemoteTasks
+		caps.LogStreaming = resp.Capabilities.LogStreaming
+		caps.TaskHealth = resp.Capabilities.TaskHealth
+		caps.Checkpoint = resp.Capabilities.Checkpoint
 	}
 
 	return caps, nil
@@ -145,28 +148,50 @@ func (d *driverPluginClient) StartTask(c *TaskConfig) (*TaskHandle, *DriverNetwo
 
 	resp, err := d.client.StartTask(d.doneCtx, req)
 	if err != nil {
-		st := status.Convert(err)
-		if len(st.Details()) > 0 {
-			if rec, ok := st.Details()[0].(*sproto.RecoverableError); ok {
-				return nil, nil, structs.NewRecoverableError(err, rec.Recoverable)
-			}
-		}
-		return nil, nil, grpcutils.HandleGrpcErr(err, d.doneCtx)
+		return nil, nil, d.startTaskErr(err)
 	}
 
-	var net *DriverNetwork
-	if resp.NetworkOverride != nil {
-		net = &DriverNetwork{
-			PortMap:       map[string]int{},
-			IP:            resp.NetworkOverride.Addr,
-			AutoAdvertise: resp.NetworkOverride.AutoAdvertise,
-		}
-		for k, v := range resp.NetworkOverride.PortMap {
-			net.PortMap[k] = int(v)
+	return taskHandleFromProto(resp.Handle), driverNetworkFromProto(resp.NetworkOverride), nil
+}
+
+// startTaskErr converts errors from starting a task, preserving whether they
+// are recoverable.
+func (d *driverPluginClient) startTaskErr(err error) error {
+	st := status.Convert(err)
+	if len(st.Details()) > 0 {
+		if rec, ok := st.Details()[0].(*sproto.RecoverableError); ok {
+			return structs.NewRecoverableError(err, rec.Recoverable)
 		}
 	}
+	return grpcutils.HandleGrpcErr(err, d.doneCtx)
+}
+
+var _ CheckpointDriver = (*driverPluginClient)(nil)
+
+// CheckpointTask checkpoints the task to the given directory, stopping it
+func (d *driverPluginClient) CheckpointTask(taskID string, dir string) error {
+	req := &proto.CheckpointTaskRequest{
+		TaskId:        taskID,
+		CheckpointDir: dir,
+	}
 
-	return taskHandleFromProto(resp.Handle), net, nil
+	_, err := d.client.CheckpointTask(d.doneCtx, req)
+	return grpcutils.HandleGrpcErr(err, d.doneCtx)
+}
+
+// RestoreTask starts the task from the checkpoint in the given directory
+func (d *driverPluginClient) RestoreTask(c *TaskConfig, dir string) (*TaskHandle, *DriverNetwork, error) {
+	req := &proto.RestoreTaskRequest{
+		Task:          taskConfigToProto(c),
+		CheckpointDir: dir,
+	}
+
+	resp, err := d.client.RestoreTask(d.doneCtx, req)
+	if err != nil {
+		return nil, nil, d.startTaskErr(err)
+	}
+
+	return taskHandleFromProto(resp.Handle), driverNetworkFromProto(resp.NetworkOverride), nil
 }
 
 // WaitTask returns a channel that will have an ExitResult pushed to it once when the task
@@ -318,6 +343,55 @@ func (d *driverPluginClient) handleStats(ctx context.Context, ch chan<- *cstruct
 	}
 }
 
+var _ LogStreamingDriver = (*driverPluginClient)(nil)
+
+// TaskLogs returns a channel of the output of the task streamed by the driver
+func (d *driverPluginClient) TaskLogs(ctx context.Context, taskID string) (<-chan *TaskLogFrame, error) {
+	req := &proto.TaskLogsRequest{
+		TaskId: taskID,
+	}
+	ctx, _ = joincontext.Join(ctx, d.doneCtx)
+	stream, err := d.client.TaskLogs(ctx, req)
+	if err != nil {
+		return nil, grpcutils.HandleGrpcErr(err, d.doneCtx)
+	}
+
+	ch := make(chan *TaskLogFrame, 1)
+	go d.handleLogs(ctx, ch, stream)
+
+	return ch, nil
+}
+
+func (d *driverPluginClient) handleLogs(ctx context.Context, ch chan<- *TaskLogFrame, stream proto.Driver_TaskLogsClient) {
+	defer close(ch)
+	for {
+		resp, err := stream.Recv()
+		if ctx.Err() != nil {
+			// Context canceled; exit gracefully
+			return
+		}
+
+		if err != nil {
+			if err != io.EOF {
+				d.logger.Error("error receiving stream from TaskLogs driver RPC, closing stream", "error", err)
+			}
+
+			// End of stream
+			return
+		}
+
+		frame := &TaskLogFrame{
+			Stream: resp.Stream,
+			Data:   resp.Data,
+		}
+		select {
+		case ch <- frame:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // TaskEvents returns a channel that will receive events from the driver about all
 // tasks such as lifecycle events, terminal errors, etc.
 func (d *driverPluginClient) TaskEvents(ctx context.Context) (<-chan *TaskEvent, error) {
@@ -368,6 +442,25 @@ func (d *driverPluginClient) handleTaskEvents(reqCtx context.Context, ch chan *T
 	}
 }
 
+var _ TaskHealthDriver = (*driverPluginClient)(nil)
+
+// TaskHealth returns the health of the task as reported by the driver
+func (d *driverPluginClient) TaskHealth(ctx context.Context, taskID string) (*TaskHealthStatus, error) {
+	req := &proto.TaskHealthRequest{
+		TaskId: taskID,
+	}
+	ctx, _ = joincontext.Join(ctx, d.doneCtx)
+	resp, err := d.client.TaskHealth(ctx, req)
+	if err != nil {
+		return nil, grpcutils.HandleGrpcErr(err, d.doneCtx)
+	}
+
+	return &TaskHealthStatus{
+		Health:            healthStateFromProto(resp.Health),
+		HealthDescription: resp.HealthDescription,
+	}, nil
+}
+
 // SignalTask will send the given signal to the specified task
 func (d *driverPluginClient) SignalTask(taskID string, signal string) error {
 	req := &proto.SignalTaskRequest{
@@ -17,6 +17,9 @@ type CpuStats = cstructs.CpuStats
 // ResourceUsage holds information related to cpu and memory stats
 type ResourceUsage = cstructs.ResourceUsage
 
+// ProcessInfo identifies a process of a task
+type ProcessInfo = cstructs.ProcessInfo
+
 // TaskResourceUsage holds aggregated resource usage of all processes in a Task
 // and the resource usage of the individual pids
 type TaskResourceUsage = cstructs.TaskResourceUsage
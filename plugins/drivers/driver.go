@@ -171,6 +171,24 @@ type Capabilities struct {
 	// adjust behavior such as propogating task handles between allocations
 	// to avoid downtime when a client is lost.
 	RemoteTasks bool
+
+	// LogStreaming indicates the driver implements LogStreamingDriver and
+	// delivers task logs over the plugin RPC instead of writing to the
+	// stdout and stderr FIFOs in the task directory. Nomad relays the
+	// streamed logs into the FIFOs so they reach logmon as usual.
+	LogStreaming bool
+
+	// TaskHealth indicates the driver implements TaskHealthDriver and
+	// reports the health of running tasks as seen by the driver or its
+	// runtime. Nomad polls the health of tasks and restarts unhealthy tasks
+	// according to their restart policy.
+	TaskHealth bool
+
+	// Checkpoint indicates the driver implements CheckpointDriver and can
+	// checkpoint running tasks and restore them from a checkpoint. Nomad
+	// checkpoints tasks of allocations migrated off a draining node into the
+	// migrated task directory and restores them on the new node.
+	Checkpoint bool
 }
 
 func (c *Capabilities) HasNetIsolationMode(m NetIsolationMode) bool {
@@ -262,6 +280,33 @@ func (c *DNSConfig) Copy() *DNSConfig {
 	return cfg
 }
 
+// ProcessConfig configures attributes of the task process.
+type ProcessConfig struct {
+	// Umask is the octal file mode creation mask of the process.
+	Umask string
+
+	// Nice is the scheduling priority of the process.
+	Nice int
+
+	// IOniceClass is the I/O scheduling class of the process.
+	IOniceClass string
+
+	// Rlimits are the resource limits of the process, keyed by the lowercase
+	// name of the limit without the RLIMIT_ prefix.
+	Rlimits map[string]uint64
+}
+
+func (c *ProcessConfig) Copy() *ProcessConfig {
+	if c == nil {
+		return nil
+	}
+
+	cfg := new(ProcessConfig)
+	*cfg = *c
+	cfg.Rlimits = helper.CopyMap(c.Rlimits)
+	return cfg
+}
+
 type TaskConfig struct {
 	ID               string
 	JobName          string
@@ -284,6 +329,7 @@ type TaskConfig struct {
 	AllocID          string
 	NetworkIsolation *NetworkIsolationSpec
 	DNS              *DNSConfig
+	Process          *ProcessConfig
 }
 
 func (tc *TaskConfig) Copy() *TaskConfig {
@@ -296,6 +342,7 @@ func (tc *TaskConfig) Copy() *TaskConfig {
 	c.DeviceEnv = helper.CopyMapStringString(c.DeviceEnv)
 	c.Resources = tc.Resources.Copy()
 	c.DNS = tc.DNS.Copy()
+	c.Process = tc.Process.Copy()
 
 	if c.Devices != nil {
 		dc := make([]*DeviceConfig, len(c.Devices))
@@ -431,18 +478,37 @@ func (d *DeviceConfig) Copy() *DeviceConfig {
 	return dc
 }
 
+const (
+	// MountTypeBind bind mounts the host path at the task path. It is the
+	// type of mounts with an empty Type.
+	MountTypeBind = "bind"
+
+	// MountTypeTmpfs mounts a tmpfs at the task path. The host path is
+	// ignored.
+	MountTypeTmpfs = "tmpfs"
+)
+
 type MountConfig struct {
 	TaskPath        string
 	HostPath        string
 	Readonly        bool
 	PropagationMode string
+
+	// Type is the type of the mount, MountTypeBind if empty.
+	Type string
+
+	// TmpfsSize is the size limit in bytes of a tmpfs mount, or 0 for the
+	// default size of the kernel.
+	TmpfsSize int64
 }
 
 func (m *MountConfig) IsEqual(o *MountConfig) bool {
 	return m.TaskPath == o.TaskPath &&
 		m.HostPath == o.HostPath &&
 		m.Readonly == o.Readonly &&
-		m.PropagationMode == o.PropagationMode
+		m.PropagationMode == o.PropagationMode &&
+		m.Type == o.Type &&
+		m.TmpfsSize == o.TmpfsSize
 }
 
 func (m *MountConfig) Copy() *MountConfig {
@@ -566,6 +632,67 @@ func (d *DriverNetwork) Hash() []byte {
 	return h.Sum(nil)
 }
 
+const (
+	// TaskLogStreamStdout and TaskLogStreamStderr identify the stream of a
+	// TaskLogFrame.
+	TaskLogStreamStdout = "stdout"
+	TaskLogStreamStderr = "stderr"
+)
+
+// TaskLogFrame is a chunk of task output sent by a LogStreamingDriver.
+type TaskLogFrame struct {
+	// Stream is either TaskLogStreamStdout or TaskLogStreamStderr.
+	Stream string
+
+	// Data is the raw output of the task.
+	Data []byte
+}
+
+// LogStreamingDriver is implemented by drivers that can't write task logs
+// to the FIFOs in the task directory, such as drivers for remote runtimes,
+// and instead stream them to the Nomad client. Drivers implementing it must
+// set the LogStreaming capability.
+type LogStreamingDriver interface {
+	// TaskLogs returns a channel of the output of the task. The channel is
+	// closed when the task exits or the context is canceled.
+	TaskLogs(ctx context.Context, taskID string) (<-chan *TaskLogFrame, error)
+}
+
+// TaskHealthStatus is the health of a task as reported by a
+// TaskHealthDriver.
+type TaskHealthStatus struct {
+	// Health is HealthStateHealthy or HealthStateUnhealthy, or
+	// HealthStateUndetected if the driver can't determine the health of the
+	// task.
+	Health HealthState
+
+	// HealthDescription is a human readable message describing the health
+	// of the task.
+	HealthDescription string
+}
+
+// TaskHealthDriver is implemented by drivers that can report the health of
+// a task independently of its service checks, such as when the container
+// runtime considers the task unhealthy. Drivers implementing it must set the
+// TaskHealth capability.
+type TaskHealthDriver interface {
+	// TaskHealth returns the current health of the task.
+	TaskHealth(ctx context.Context, taskID string) (*TaskHealthStatus, error)
+}
+
+// CheckpointDriver is implemented by drivers that can checkpoint the state of
+// a running task, such as with CRIU, and later restore it, possibly on
+// another node. Drivers implementing it must set the Checkpoint capability.
+type CheckpointDriver interface {
+	// CheckpointTask writes the state of the running task to dir and stops
+	// the task.
+	CheckpointTask(taskID string, dir string) error
+
+	// RestoreTask starts the task from the checkpoint in dir. It behaves
+	// like StartTask otherwise.
+	RestoreTask(config *TaskConfig, dir string) (*TaskHandle, *DriverNetwork, error)
+}
+
 //// helper types for operating on raw exec operation
 // we alias proto instances as much as possible to avoid conversion overhead
 
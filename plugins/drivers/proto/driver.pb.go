@@ -1848,7 +1848,16 @@ type DriverCapabilities struct {
 	MountConfigs DriverCapabilities_MountConfigs `protobuf:"varint,6,opt,name=mount_configs,json=mountConfigs,proto3,enum=hashicorp.nomad.plugins.drivers.proto.DriverCapabilities_MountConfigs" json:"mount_configs,omitempty"`
 	// remote_tasks indicates whether the driver executes tasks remotely such
 	// on cloud runtimes like AWS ECS.
-	RemoteTasks          bool     `protobuf:"varint,7,opt,name=remote_tasks,json=remoteTasks,proto3" json:"remote_tasks,omitempty"`
+	RemoteTasks bool `protobuf:"varint,7,opt,name=remote_tasks,json=remoteTasks,proto3" json:"remote_tasks,omitempty"`
+	// log_streaming indicates whether the driver delivers task logs with the
+	// TaskLogs rpc instead of writing them to the stdout and stderr paths.
+	LogStreaming bool `protobuf:"varint,8,opt,name=log_streaming,json=logStreaming,proto3" json:"log_streaming,omitempty"`
+	// task_health indicates whether the driver reports the health of tasks
+	// with the TaskHealth rpc.
+	TaskHealth bool `protobuf:"varint,9,opt,name=task_health,json=taskHealth,proto3" json:"task_health,omitempty"`
+	// checkpoint indicates whether the driver can checkpoint and restore
+	// tasks with the CheckpointTask and RestoreTask rpcs.
+	Checkpoint           bool     `protobuf:"varint,10,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1928,6 +1937,27 @@ func (m *DriverCapabilities) GetRemoteTasks() bool {
 	return false
 }
 
+func (m *DriverCapabilities) GetLogStreaming() bool {
+	if m != nil {
+		return m.LogStreaming
+	}
+	return false
+}
+
+func (m *DriverCapabilities) GetTaskHealth() bool {
+	if m != nil {
+		return m.TaskHealth
+	}
+	return false
+}
+
+func (m *DriverCapabilities) GetCheckpoint() bool {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return false
+}
+
 type NetworkIsolationSpec struct {
 	Mode                 NetworkIsolationSpec_NetworkIsolationMode `protobuf:"varint,1,opt,name=mode,proto3,enum=hashicorp.nomad.plugins.drivers.proto.NetworkIsolationSpec_NetworkIsolationMode" json:"mode,omitempty"`
 	Path                 string                                    `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
@@ -2133,10 +2163,12 @@ type TaskConfig struct {
 	// to use for the task. *Only supported on Linux
 	NetworkIsolationSpec *NetworkIsolationSpec `protobuf:"bytes,16,opt,name=network_isolation_spec,json=networkIsolationSpec,proto3" json:"network_isolation_spec,omitempty"`
 	// DNSConfig is the configuration for task DNS resolvers and other options
-	Dns                  *DNSConfig `protobuf:"bytes,17,opt,name=dns,proto3" json:"dns,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	Dns *DNSConfig `protobuf:"bytes,17,opt,name=dns,proto3" json:"dns,omitempty"`
+	// Process configures attributes of the task process
+	Process              *ProcessConfig `protobuf:"bytes,18,opt,name=process,proto3" json:"process,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *TaskConfig) Reset()         { *m = TaskConfig{} }
@@ -2283,6 +2315,13 @@ func (m *TaskConfig) GetDns() *DNSConfig {
 	return nil
 }
 
+func (m *TaskConfig) GetProcess() *ProcessConfig {
+	if m != nil {
+		return m.Process
+	}
+	return nil
+}
+
 type Resources struct {
 	// AllocatedResources are the resources set for the task
 	AllocatedResources *AllocatedTaskResources `protobuf:"bytes,1,opt,name=allocated_resources,json=allocatedResources,proto3" json:"allocated_resources,omitempty"`
@@ -2783,7 +2822,11 @@ type Mount struct {
 	// HostPath is the file path on the host to mount from
 	HostPath string `protobuf:"bytes,2,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"`
 	// Readonly if set true, mounts the path in readonly mode
-	Readonly             bool     `protobuf:"varint,3,opt,name=readonly,proto3" json:"readonly,omitempty"`
+	Readonly bool `protobuf:"varint,3,opt,name=readonly,proto3" json:"readonly,omitempty"`
+	// Type is the type of the mount, either bind (the default) or tmpfs
+	Type string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	// TmpfsSize is the size limit in bytes of a tmpfs mount
+	TmpfsSize            int64    `protobuf:"varint,5,opt,name=tmpfs_size,json=tmpfsSize,proto3" json:"tmpfs_size,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2835,6 +2878,20 @@ func (m *Mount) GetReadonly() bool {
 	return false
 }
 
+func (m *Mount) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Mount) GetTmpfsSize() int64 {
+	if m != nil {
+		return m.TmpfsSize
+	}
+	return 0
+}
+
 type Device struct {
 	// TaskPath is the file path within the task to mount the device to
 	TaskPath string `protobuf:"bytes,1,opt,name=task_path,json=taskPath,proto3" json:"task_path,omitempty"`
@@ -3285,10 +3342,16 @@ type TaskResourceUsage struct {
 	// CPU usage stats
 	Cpu *CPUUsage `protobuf:"bytes,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
 	// Memory usage stats
-	Memory               *MemoryUsage `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Memory *MemoryUsage `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	// Ppid is the pid of the parent of the process, for the usage of a
+	// process in TaskStats.resource_usage_by_pid
+	Ppid int64 `protobuf:"varint,3,opt,name=ppid,proto3" json:"ppid,omitempty"`
+	// Command is the command line of the process, for the usage of a
+	// process in TaskStats.resource_usage_by_pid
+	Command              string   `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TaskResourceUsage) Reset()         { *m = TaskResourceUsage{} }
@@ -3330,6 +3393,20 @@ func (m *TaskResourceUsage) GetMemory() *MemoryUsage {
 	return nil
 }
 
+func (m *TaskResourceUsage) GetPpid() int64 {
+	if m != nil {
+		return m.Ppid
+	}
+	return 0
+}
+
+func (m *TaskResourceUsage) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
 type CPUUsage struct {
 	SystemMode       float64 `protobuf:"fixed64,1,opt,name=system_mode,json=systemMode,proto3" json:"system_mode,omitempty"`
 	UserMode         float64 `protobuf:"fixed64,2,opt,name=user_mode,json=userMode,proto3" json:"user_mode,omitempty"`
@@ -3426,6 +3503,10 @@ type MemoryUsage struct {
 	KernelMaxUsage uint64 `protobuf:"varint,5,opt,name=kernel_max_usage,json=kernelMaxUsage,proto3" json:"kernel_max_usage,omitempty"`
 	Usage          uint64 `protobuf:"varint,7,opt,name=usage,proto3" json:"usage,omitempty"`
 	Swap           uint64 `protobuf:"varint,8,opt,name=swap,proto3" json:"swap,omitempty"`
+	// Limit is the hard memory limit of the task in bytes
+	Limit uint64 `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Reservation is the soft memory limit of the task in bytes
+	Reservation uint64 `protobuf:"varint,10,opt,name=reservation,proto3" json:"reservation,omitempty"`
 	// MeasuredFields indicates which fields were actually sampled
 	MeasuredFields       []MemoryUsage_Fields `protobuf:"varint,6,rep,packed,name=measured_fields,json=measuredFields,proto3,enum=hashicorp.nomad.plugins.drivers.proto.MemoryUsage_Fields" json:"measured_fields,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
@@ -3507,6 +3588,20 @@ func (m *MemoryUsage) GetSwap() uint64 {
 	return 0
 }
 
+func (m *MemoryUsage) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *MemoryUsage) GetReservation() uint64 {
+	if m != nil {
+		return m.Reservation
+	}
+	return 0
+}
+
 func (m *MemoryUsage) GetMeasuredFields() []MemoryUsage_Fields {
 	if m != nil {
 		return m.MeasuredFields
@@ -3599,6 +3694,433 @@ func (m *DriverTaskEvent) GetAnnotations() map[string]string {
 	return nil
 }
 
+type ProcessConfig struct {
+	// Umask is the octal file mode creation mask of the process
+	Umask string `protobuf:"bytes,1,opt,name=umask,proto3" json:"umask,omitempty"`
+	// Nice is the scheduling priority of the process
+	Nice int32 `protobuf:"varint,2,opt,name=nice,proto3" json:"nice,omitempty"`
+	// IoniceClass is the I/O scheduling class of the process
+	IoniceClass string `protobuf:"bytes,3,opt,name=ionice_class,json=ioniceClass,proto3" json:"ionice_class,omitempty"`
+	// Rlimits are the resource limits of the process, keyed by name
+	Rlimits              map[string]uint64 `protobuf:"bytes,4,rep,name=rlimits,proto3" json:"rlimits,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ProcessConfig) Reset()         { *m = ProcessConfig{} }
+func (m *ProcessConfig) String() string { return proto.CompactTextString(m) }
+func (*ProcessConfig) ProtoMessage()    {}
+func (*ProcessConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{57}
+}
+
+func (m *ProcessConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProcessConfig.Unmarshal(m, b)
+}
+func (m *ProcessConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProcessConfig.Marshal(b, m, deterministic)
+}
+func (m *ProcessConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProcessConfig.Merge(m, src)
+}
+func (m *ProcessConfig) XXX_Size() int {
+	return xxx_messageInfo_ProcessConfig.Size(m)
+}
+func (m *ProcessConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProcessConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProcessConfig proto.InternalMessageInfo
+
+func (m *ProcessConfig) GetUmask() string {
+	if m != nil {
+		return m.Umask
+	}
+	return ""
+}
+
+func (m *ProcessConfig) GetNice() int32 {
+	if m != nil {
+		return m.Nice
+	}
+	return 0
+}
+
+func (m *ProcessConfig) GetIoniceClass() string {
+	if m != nil {
+		return m.IoniceClass
+	}
+	return ""
+}
+
+func (m *ProcessConfig) GetRlimits() map[string]uint64 {
+	if m != nil {
+		return m.Rlimits
+	}
+	return nil
+}
+
+type TaskLogsRequest struct {
+	// TaskId is the ID of the target task
+	TaskId               string   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskLogsRequest) Reset()         { *m = TaskLogsRequest{} }
+func (m *TaskLogsRequest) String() string { return proto.CompactTextString(m) }
+func (*TaskLogsRequest) ProtoMessage()    {}
+func (*TaskLogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{58}
+}
+
+func (m *TaskLogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskLogsRequest.Unmarshal(m, b)
+}
+func (m *TaskLogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskLogsRequest.Marshal(b, m, deterministic)
+}
+func (m *TaskLogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskLogsRequest.Merge(m, src)
+}
+func (m *TaskLogsRequest) XXX_Size() int {
+	return xxx_messageInfo_TaskLogsRequest.Size(m)
+}
+func (m *TaskLogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskLogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskLogsRequest proto.InternalMessageInfo
+
+func (m *TaskLogsRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+type TaskLogsResponse struct {
+	// Stream is the name of the stream the data was written to, either
+	// "stdout" or "stderr"
+	Stream string `protobuf:"bytes,1,opt,name=stream,proto3" json:"stream,omitempty"`
+	// Data is the log data
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskLogsResponse) Reset()         { *m = TaskLogsResponse{} }
+func (m *TaskLogsResponse) String() string { return proto.CompactTextString(m) }
+func (*TaskLogsResponse) ProtoMessage()    {}
+func (*TaskLogsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{59}
+}
+
+func (m *TaskLogsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskLogsResponse.Unmarshal(m, b)
+}
+func (m *TaskLogsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskLogsResponse.Marshal(b, m, deterministic)
+}
+func (m *TaskLogsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskLogsResponse.Merge(m, src)
+}
+func (m *TaskLogsResponse) XXX_Size() int {
+	return xxx_messageInfo_TaskLogsResponse.Size(m)
+}
+func (m *TaskLogsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskLogsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskLogsResponse proto.InternalMessageInfo
+
+func (m *TaskLogsResponse) GetStream() string {
+	if m != nil {
+		return m.Stream
+	}
+	return ""
+}
+
+func (m *TaskLogsResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type TaskHealthRequest struct {
+	// TaskId is the ID of the target task
+	TaskId               string   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskHealthRequest) Reset()         { *m = TaskHealthRequest{} }
+func (m *TaskHealthRequest) String() string { return proto.CompactTextString(m) }
+func (*TaskHealthRequest) ProtoMessage()    {}
+func (*TaskHealthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{60}
+}
+
+func (m *TaskHealthRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskHealthRequest.Unmarshal(m, b)
+}
+func (m *TaskHealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskHealthRequest.Marshal(b, m, deterministic)
+}
+func (m *TaskHealthRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskHealthRequest.Merge(m, src)
+}
+func (m *TaskHealthRequest) XXX_Size() int {
+	return xxx_messageInfo_TaskHealthRequest.Size(m)
+}
+func (m *TaskHealthRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskHealthRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskHealthRequest proto.InternalMessageInfo
+
+func (m *TaskHealthRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+type TaskHealthResponse struct {
+	// Health is the health of the task as reported by the driver
+	Health FingerprintResponse_HealthState `protobuf:"varint,1,opt,name=health,proto3,enum=hashicorp.nomad.plugins.drivers.proto.FingerprintResponse_HealthState" json:"health,omitempty"`
+	// HealthDescription is a human readable message describing the health
+	// of the task
+	HealthDescription    string   `protobuf:"bytes,2,opt,name=health_description,json=healthDescription,proto3" json:"health_description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskHealthResponse) Reset()         { *m = TaskHealthResponse{} }
+func (m *TaskHealthResponse) String() string { return proto.CompactTextString(m) }
+func (*TaskHealthResponse) ProtoMessage()    {}
+func (*TaskHealthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{61}
+}
+
+func (m *TaskHealthResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskHealthResponse.Unmarshal(m, b)
+}
+func (m *TaskHealthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskHealthResponse.Marshal(b, m, deterministic)
+}
+func (m *TaskHealthResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskHealthResponse.Merge(m, src)
+}
+func (m *TaskHealthResponse) XXX_Size() int {
+	return xxx_messageInfo_TaskHealthResponse.Size(m)
+}
+func (m *TaskHealthResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskHealthResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskHealthResponse proto.InternalMessageInfo
+
+func (m *TaskHealthResponse) GetHealth() FingerprintResponse_HealthState {
+	if m != nil {
+		return m.Health
+	}
+	return FingerprintResponse_UNDETECTED
+}
+
+func (m *TaskHealthResponse) GetHealthDescription() string {
+	if m != nil {
+		return m.HealthDescription
+	}
+	return ""
+}
+
+type CheckpointTaskRequest struct {
+	// TaskId is the ID of the target task
+	TaskId string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// CheckpointDir is the directory the checkpoint is written to
+	CheckpointDir        string   `protobuf:"bytes,2,opt,name=checkpoint_dir,json=checkpointDir,proto3" json:"checkpoint_dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointTaskRequest) Reset()         { *m = CheckpointTaskRequest{} }
+func (m *CheckpointTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckpointTaskRequest) ProtoMessage()    {}
+func (*CheckpointTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{62}
+}
+
+func (m *CheckpointTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointTaskRequest.Unmarshal(m, b)
+}
+func (m *CheckpointTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckpointTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointTaskRequest.Merge(m, src)
+}
+func (m *CheckpointTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckpointTaskRequest.Size(m)
+}
+func (m *CheckpointTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointTaskRequest proto.InternalMessageInfo
+
+func (m *CheckpointTaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *CheckpointTaskRequest) GetCheckpointDir() string {
+	if m != nil {
+		return m.CheckpointDir
+	}
+	return ""
+}
+
+type CheckpointTaskResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointTaskResponse) Reset()         { *m = CheckpointTaskResponse{} }
+func (m *CheckpointTaskResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckpointTaskResponse) ProtoMessage()    {}
+func (*CheckpointTaskResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{63}
+}
+
+func (m *CheckpointTaskResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointTaskResponse.Unmarshal(m, b)
+}
+func (m *CheckpointTaskResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointTaskResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckpointTaskResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointTaskResponse.Merge(m, src)
+}
+func (m *CheckpointTaskResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckpointTaskResponse.Size(m)
+}
+func (m *CheckpointTaskResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointTaskResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointTaskResponse proto.InternalMessageInfo
+
+type RestoreTaskRequest struct {
+	// Task is the configuration of the task to restore
+	Task *TaskConfig `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// CheckpointDir is the directory containing the checkpoint to restore
+	CheckpointDir        string   `protobuf:"bytes,2,opt,name=checkpoint_dir,json=checkpointDir,proto3" json:"checkpoint_dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreTaskRequest) Reset()         { *m = RestoreTaskRequest{} }
+func (m *RestoreTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreTaskRequest) ProtoMessage()    {}
+func (*RestoreTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{64}
+}
+
+func (m *RestoreTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreTaskRequest.Unmarshal(m, b)
+}
+func (m *RestoreTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreTaskRequest.Merge(m, src)
+}
+func (m *RestoreTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreTaskRequest.Size(m)
+}
+func (m *RestoreTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreTaskRequest proto.InternalMessageInfo
+
+func (m *RestoreTaskRequest) GetTask() *TaskConfig {
+	if m != nil {
+		return m.Task
+	}
+	return nil
+}
+
+func (m *RestoreTaskRequest) GetCheckpointDir() string {
+	if m != nil {
+		return m.CheckpointDir
+	}
+	return ""
+}
+
+type RestoreTaskResponse struct {
+	// Handle is opaque to the client, but must be stored in order to recover
+	// the task.
+	Handle *TaskHandle `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	// NetworkOverride is set if the driver sets network settings and the service ip/port
+	// needs to be set differently.
+	NetworkOverride      *NetworkOverride `protobuf:"bytes,2,opt,name=network_override,json=networkOverride,proto3" json:"network_override,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RestoreTaskResponse) Reset()         { *m = RestoreTaskResponse{} }
+func (m *RestoreTaskResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreTaskResponse) ProtoMessage()    {}
+func (*RestoreTaskResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{65}
+}
+
+func (m *RestoreTaskResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreTaskResponse.Unmarshal(m, b)
+}
+func (m *RestoreTaskResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreTaskResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreTaskResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreTaskResponse.Merge(m, src)
+}
+func (m *RestoreTaskResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreTaskResponse.Size(m)
+}
+func (m *RestoreTaskResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreTaskResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreTaskResponse proto.InternalMessageInfo
+
+func (m *RestoreTaskResponse) GetHandle() *TaskHandle {
+	if m != nil {
+		return m.Handle
+	}
+	return nil
+}
+
+func (m *RestoreTaskResponse) GetNetworkOverride() *NetworkOverride {
+	if m != nil {
+		return m.NetworkOverride
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.TaskState", TaskState_name, TaskState_value)
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.FingerprintResponse_HealthState", FingerprintResponse_HealthState_name, FingerprintResponse_HealthState_value)
@@ -3675,6 +4197,16 @@ func init() {
 	proto.RegisterType((*MemoryUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.MemoryUsage")
 	proto.RegisterType((*DriverTaskEvent)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverTaskEvent")
 	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverTaskEvent.AnnotationsEntry")
+	proto.RegisterType((*ProcessConfig)(nil), "hashicorp.nomad.plugins.drivers.proto.ProcessConfig")
+	proto.RegisterMapType((map[string]uint64)(nil), "hashicorp.nomad.plugins.drivers.proto.ProcessConfig.RlimitsEntry")
+	proto.RegisterType((*TaskLogsRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskLogsRequest")
+	proto.RegisterType((*TaskLogsResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskLogsResponse")
+	proto.RegisterType((*TaskHealthRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskHealthRequest")
+	proto.RegisterType((*TaskHealthResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskHealthResponse")
+	proto.RegisterType((*CheckpointTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.CheckpointTaskRequest")
+	proto.RegisterType((*CheckpointTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.CheckpointTaskResponse")
+	proto.RegisterType((*RestoreTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.RestoreTaskRequest")
+	proto.RegisterType((*RestoreTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.RestoreTaskResponse")
 }
 
 func init() {
@@ -3682,243 +4214,267 @@ func init() {
 }
 
 var fileDescriptor_4a8f45747846a74d = []byte{
-	// 3776 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x5a, 0xcd, 0x6f, 0x1b, 0x49,
-	0x76, 0x57, 0xb3, 0xf9, 0xf9, 0x48, 0x51, 0xad, 0xb2, 0x6c, 0xd3, 0x9c, 0x24, 0xe3, 0xed, 0x60,
-	0x02, 0x61, 0x77, 0x86, 0x9e, 0xd5, 0x22, 0xe3, 0xf1, 0xac, 0x67, 0x3d, 0x1c, 0x8a, 0xb6, 0x34,
-	0x96, 0x28, 0xa5, 0x48, 0xc1, 0xeb, 0x38, 0x3b, 0x9d, 0x16, 0xbb, 0x4c, 0xb5, 0xc5, 0xfe, 0x98,
-	0xae, 0xa6, 0x2c, 0x6d, 0x10, 0x24, 0xd8, 0x00, 0xc1, 0x06, 0x48, 0x90, 0x5c, 0x26, 0x7b, 0xc9,
-	0x69, 0x81, 0x9c, 0xf2, 0x0f, 0x04, 0x1b, 0xec, 0x29, 0x87, 0xfc, 0x13, 0xb9, 0xe4, 0x96, 0x63,
-	0x72, 0xca, 0x35, 0xa8, 0x8f, 0x6e, 0x76, 0x93, 0xf4, 0xb8, 0x49, 0xf9, 0xc4, 0x7e, 0xf5, 0xf1,
-	0xab, 0xc7, 0xf7, 0x5e, 0xbd, 0x7a, 0xf5, 0xea, 0x81, 0xee, 0x8f, 0x27, 0x23, 0xdb, 0xa5, 0xf7,
-	0xac, 0xc0, 0xbe, 0x20, 0x01, 0xbd, 0xe7, 0x07, 0x5e, 0xe8, 0x49, 0xaa, 0xc5, 0x09, 0xf4, 0xc1,
-	0x99, 0x49, 0xcf, 0xec, 0xa1, 0x17, 0xf8, 0x2d, 0xd7, 0x73, 0x4c, 0xab, 0x25, 0xe7, 0xb4, 0xe4,
-	0x1c, 0x31, 0xac, 0xf9, 0x7b, 0x23, 0xcf, 0x1b, 0x8d, 0x89, 0x40, 0x38, 0x9d, 0xbc, 0xbc, 0x67,
-	0x4d, 0x02, 0x33, 0xb4, 0x3d, 0x57, 0xf6, 0xbf, 0x3f, 0xdb, 0x1f, 0xda, 0x0e, 0xa1, 0xa1, 0xe9,
-	0xf8, 0x72, 0xc0, 0x07, 0x11, 0x2f, 0xf4, 0xcc, 0x0c, 0x88, 0x75, 0xef, 0x6c, 0x38, 0xa6, 0x3e,
-	0x19, 0xb2, 0x5f, 0x83, 0x7d, 0xc8, 0x61, 0x1f, 0xce, 0x0c, 0xa3, 0x61, 0x30, 0x19, 0x86, 0x11,
-	0xe7, 0x66, 0x18, 0x06, 0xf6, 0xe9, 0x24, 0x24, 0x62, 0xb4, 0x7e, 0x07, 0x6e, 0x0f, 0x4c, 0x7a,
-	0xde, 0xf1, 0xdc, 0x97, 0xf6, 0xa8, 0x3f, 0x3c, 0x23, 0x8e, 0x89, 0xc9, 0x37, 0x13, 0x42, 0x43,
-	0xfd, 0x4f, 0xa0, 0x31, 0xdf, 0x45, 0x7d, 0xcf, 0xa5, 0x04, 0x7d, 0x01, 0x79, 0xb6, 0x64, 0x43,
-	0xb9, 0xab, 0x6c, 0x57, 0x77, 0x3e, 0x6c, 0xbd, 0x49, 0x04, 0x82, 0x87, 0x96, 0x64, 0xb5, 0xd5,
-	0xf7, 0xc9, 0x10, 0xf3, 0x99, 0xfa, 0x4d, 0xb8, 0xd1, 0x31, 0x7d, 0xf3, 0xd4, 0x1e, 0xdb, 0xa1,
-	0x4d, 0x68, 0xb4, 0xe8, 0x04, 0xb6, 0xd2, 0xcd, 0x72, 0xc1, 0x9f, 0x41, 0x6d, 0x98, 0x68, 0x97,
-	0x0b, 0x3f, 0x68, 0x65, 0x92, 0x7d, 0x6b, 0x97, 0x53, 0x29, 0xe0, 0x14, 0x9c, 0xbe, 0x05, 0xe8,
-	0xb1, 0xed, 0x8e, 0x48, 0xe0, 0x07, 0xb6, 0x1b, 0x46, 0xcc, 0xfc, 0x56, 0x85, 0x1b, 0xa9, 0x66,
-	0xc9, 0xcc, 0x2b, 0x80, 0x58, 0x8e, 0x8c, 0x15, 0x75, 0xbb, 0xba, 0xf3, 0x55, 0x46, 0x56, 0x16,
-	0xe0, 0xb5, 0xda, 0x31, 0x58, 0xd7, 0x0d, 0x83, 0x2b, 0x9c, 0x40, 0x47, 0x5f, 0x43, 0xf1, 0x8c,
-	0x98, 0xe3, 0xf0, 0xac, 0x91, 0xbb, 0xab, 0x6c, 0xd7, 0x77, 0x1e, 0x5f, 0x63, 0x9d, 0x3d, 0x0e,
-	0xd4, 0x0f, 0xcd, 0x90, 0x60, 0x89, 0x8a, 0x3e, 0x02, 0x24, 0xbe, 0x0c, 0x8b, 0xd0, 0x61, 0x60,
-	0xfb, 0xcc, 0x24, 0x1b, 0xea, 0x5d, 0x65, 0xbb, 0x82, 0x37, 0x45, 0xcf, 0xee, 0xb4, 0xa3, 0xe9,
-	0xc3, 0xc6, 0x0c, 0xb7, 0x48, 0x03, 0xf5, 0x9c, 0x5c, 0x71, 0x8d, 0x54, 0x30, 0xfb, 0x44, 0x4f,
-	0xa0, 0x70, 0x61, 0x8e, 0x27, 0x84, 0xb3, 0x5c, 0xdd, 0xf9, 0xe1, 0xdb, 0xcc, 0x43, 0x9a, 0xe8,
-	0x54, 0x0e, 0x58, 0xcc, 0xff, 0x2c, 0xf7, 0xa9, 0xa2, 0x3f, 0x80, 0x6a, 0x82, 0x6f, 0x54, 0x07,
-	0x38, 0xe9, 0xed, 0x76, 0x07, 0xdd, 0xce, 0xa0, 0xbb, 0xab, 0xad, 0xa1, 0x75, 0xa8, 0x9c, 0xf4,
-	0xf6, 0xba, 0xed, 0x83, 0xc1, 0xde, 0x73, 0x4d, 0x41, 0x55, 0x28, 0x45, 0x44, 0x4e, 0xbf, 0x04,
-	0x84, 0xc9, 0xd0, 0xbb, 0x20, 0x01, 0x33, 0x64, 0xa9, 0x55, 0x74, 0x1b, 0x4a, 0xa1, 0x49, 0xcf,
-	0x0d, 0xdb, 0x92, 0x3c, 0x17, 0x19, 0xb9, 0x6f, 0xa1, 0x7d, 0x28, 0x9e, 0x99, 0xae, 0x35, 0x7e,
-	0x3b, 0xdf, 0x69, 0x51, 0x33, 0xf0, 0x3d, 0x3e, 0x11, 0x4b, 0x00, 0x66, 0xdd, 0xa9, 0x95, 0x85,
-	0x02, 0xf4, 0xe7, 0xa0, 0xf5, 0x43, 0x33, 0x08, 0x93, 0xec, 0x74, 0x21, 0xcf, 0xd6, 0x97, 0x16,
-	0xbd, 0xcc, 0x9a, 0x62, 0x67, 0x62, 0x3e, 0x5d, 0xff, 0xdf, 0x1c, 0x6c, 0x26, 0xb0, 0xa5, 0xa5,
-	0x3e, 0x83, 0x62, 0x40, 0xe8, 0x64, 0x1c, 0x72, 0xf8, 0xfa, 0xce, 0xa3, 0x8c, 0xf0, 0x73, 0x48,
-	0x2d, 0xcc, 0x61, 0xb0, 0x84, 0x43, 0xdb, 0xa0, 0x89, 0x19, 0x06, 0x09, 0x02, 0x2f, 0x30, 0x1c,
-	0x3a, 0xe2, 0x52, 0xab, 0xe0, 0xba, 0x68, 0xef, 0xb2, 0xe6, 0x43, 0x3a, 0x4a, 0x48, 0x55, 0xbd,
-	0xa6, 0x54, 0x91, 0x09, 0x9a, 0x4b, 0xc2, 0xd7, 0x5e, 0x70, 0x6e, 0x30, 0xd1, 0x06, 0xb6, 0x45,
-	0x1a, 0x79, 0x0e, 0xfa, 0x49, 0x46, 0xd0, 0x9e, 0x98, 0x7e, 0x24, 0x67, 0xe3, 0x0d, 0x37, 0xdd,
-	0xa0, 0xff, 0x00, 0x8a, 0xe2, 0x9f, 0x32, 0x4b, 0xea, 0x9f, 0x74, 0x3a, 0xdd, 0x7e, 0x5f, 0x5b,
-	0x43, 0x15, 0x28, 0xe0, 0xee, 0x00, 0x33, 0x0b, 0xab, 0x40, 0xe1, 0x71, 0x7b, 0xd0, 0x3e, 0xd0,
-	0x72, 0xfa, 0xf7, 0x61, 0xe3, 0x99, 0x69, 0x87, 0x59, 0x8c, 0x4b, 0xf7, 0x40, 0x9b, 0x8e, 0x95,
-	0xda, 0xd9, 0x4f, 0x69, 0x27, 0xbb, 0x68, 0xba, 0x97, 0x76, 0x38, 0xa3, 0x0f, 0x0d, 0x54, 0x12,
-	0x04, 0x52, 0x05, 0xec, 0x53, 0x7f, 0x0d, 0x1b, 0xfd, 0xd0, 0xf3, 0x33, 0x59, 0xfe, 0x8f, 0xa0,
-	0xc4, 0x4e, 0x1b, 0x6f, 0x12, 0x4a, 0xd3, 0xbf, 0xd3, 0x12, 0xa7, 0x51, 0x2b, 0x3a, 0x8d, 0x5a,
-	0xbb, 0xf2, 0xb4, 0xc2, 0xd1, 0x48, 0x74, 0x0b, 0x8a, 0xd4, 0x1e, 0xb9, 0xe6, 0x58, 0x7a, 0x0b,
-	0x49, 0xe9, 0x88, 0x19, 0x79, 0xb4, 0xb0, 0x34, 0xfc, 0x0e, 0xa0, 0x5d, 0x42, 0xc3, 0xc0, 0xbb,
-	0xca, 0xc4, 0xcf, 0x16, 0x14, 0x5e, 0x7a, 0xc1, 0x50, 0x6c, 0xc4, 0x32, 0x16, 0x04, 0xdb, 0x54,
-	0x29, 0x10, 0x89, 0xfd, 0x11, 0xa0, 0x7d, 0x97, 0x9d, 0x29, 0xd9, 0x14, 0xf1, 0x0f, 0x39, 0xb8,
-	0x91, 0x1a, 0x2f, 0x95, 0xb1, 0xfa, 0x3e, 0x64, 0x8e, 0x69, 0x42, 0xc5, 0x3e, 0x44, 0x47, 0x50,
-	0x14, 0x23, 0xa4, 0x24, 0xef, 0x2f, 0x01, 0x24, 0x8e, 0x29, 0x09, 0x27, 0x61, 0x16, 0x1a, 0xbd,
-	0xfa, 0x6e, 0x8d, 0xfe, 0x35, 0x68, 0xd1, 0xff, 0xa0, 0x6f, 0xd5, 0xcd, 0x57, 0x70, 0x63, 0xe8,
-	0x8d, 0xc7, 0x64, 0xc8, 0xac, 0xc1, 0xb0, 0xdd, 0x90, 0x04, 0x17, 0xe6, 0xf8, 0xed, 0x76, 0x83,
-	0xa6, 0xb3, 0xf6, 0xe5, 0x24, 0xfd, 0x05, 0x6c, 0x26, 0x16, 0x96, 0x8a, 0x78, 0x0c, 0x05, 0xca,
-	0x1a, 0xa4, 0x26, 0x3e, 0x5e, 0x52, 0x13, 0x14, 0x8b, 0xe9, 0xfa, 0x0d, 0x01, 0xde, 0xbd, 0x20,
-	0x6e, 0xfc, 0xb7, 0xf4, 0x5d, 0xd8, 0xec, 0x73, 0x33, 0xcd, 0x64, 0x87, 0x53, 0x13, 0xcf, 0xa5,
-	0x4c, 0x7c, 0x0b, 0x50, 0x12, 0x45, 0x1a, 0xe2, 0x15, 0x6c, 0x74, 0x2f, 0xc9, 0x30, 0x13, 0x72,
-	0x03, 0x4a, 0x43, 0xcf, 0x71, 0x4c, 0xd7, 0x6a, 0xe4, 0xee, 0xaa, 0xdb, 0x15, 0x1c, 0x91, 0xc9,
-	0xbd, 0xa8, 0x66, 0xdd, 0x8b, 0xfa, 0xdf, 0x29, 0xa0, 0x4d, 0xd7, 0x96, 0x82, 0x64, 0xdc, 0x87,
-	0x16, 0x03, 0x62, 0x6b, 0xd7, 0xb0, 0xa4, 0x64, 0x7b, 0xe4, 0x2e, 0x44, 0x3b, 0x09, 0x82, 0x84,
-	0x3b, 0x52, 0xaf, 0xe9, 0x8e, 0xf4, 0x3d, 0xf8, 0x9d, 0x88, 0x9d, 0x7e, 0x18, 0x10, 0xd3, 0xb1,
-	0xdd, 0xd1, 0xfe, 0xd1, 0x91, 0x4f, 0x04, 0xe3, 0x08, 0x41, 0xde, 0x32, 0x43, 0x53, 0x32, 0xc6,
-	0xbf, 0xd9, 0xa6, 0x1f, 0x8e, 0x3d, 0x1a, 0x6f, 0x7a, 0x4e, 0xe8, 0xff, 0xa1, 0x42, 0x63, 0x0e,
-	0x2a, 0x12, 0xef, 0x0b, 0x28, 0x50, 0x12, 0x4e, 0x7c, 0x69, 0x2a, 0xdd, 0xcc, 0x0c, 0x2f, 0xc6,
-	0x6b, 0xf5, 0x19, 0x18, 0x16, 0x98, 0x68, 0x04, 0xe5, 0x30, 0xbc, 0x32, 0xa8, 0xfd, 0xf3, 0x28,
-	0x20, 0x38, 0xb8, 0x2e, 0xfe, 0x80, 0x04, 0x8e, 0xed, 0x9a, 0xe3, 0xbe, 0xfd, 0x73, 0x82, 0x4b,
-	0x61, 0x78, 0xc5, 0x3e, 0xd0, 0x73, 0x66, 0xf0, 0x96, 0xed, 0x4a, 0xb1, 0x77, 0x56, 0x5d, 0x25,
-	0x21, 0x60, 0x2c, 0x10, 0x9b, 0x07, 0x50, 0xe0, 0xff, 0x69, 0x15, 0x43, 0xd4, 0x40, 0x0d, 0xc3,
-	0x2b, 0xce, 0x54, 0x19, 0xb3, 0xcf, 0xe6, 0x43, 0xa8, 0x25, 0xff, 0x01, 0x33, 0xa4, 0x33, 0x62,
-	0x8f, 0xce, 0x84, 0x81, 0x15, 0xb0, 0xa4, 0x98, 0x26, 0x5f, 0xdb, 0x96, 0x0c, 0x59, 0x0b, 0x58,
-	0x10, 0xfa, 0xbf, 0xe6, 0xe0, 0xce, 0x02, 0xc9, 0x48, 0x63, 0x7d, 0x91, 0x32, 0xd6, 0x77, 0x24,
-	0x85, 0xc8, 0xe2, 0x5f, 0xa4, 0x2c, 0xfe, 0x1d, 0x82, 0xb3, 0x6d, 0x73, 0x0b, 0x8a, 0xe4, 0xd2,
-	0x0e, 0x89, 0x25, 0x45, 0x25, 0xa9, 0xc4, 0x76, 0xca, 0x5f, 0x77, 0x3b, 0x1d, 0xc2, 0x56, 0x27,
-	0x20, 0x66, 0x48, 0xa4, 0x2b, 0x8f, 0xec, 0xff, 0x0e, 0x94, 0xcd, 0xf1, 0xd8, 0x1b, 0x4e, 0xd5,
-	0x5a, 0xe2, 0xf4, 0xbe, 0x85, 0x9a, 0x50, 0x3e, 0xf3, 0x68, 0xe8, 0x9a, 0x0e, 0x91, 0xce, 0x2b,
-	0xa6, 0xf5, 0x6f, 0x15, 0xb8, 0x39, 0x83, 0x27, 0xb5, 0x70, 0x0a, 0x75, 0x9b, 0x7a, 0x63, 0xfe,
-	0x07, 0x8d, 0xc4, 0x0d, 0xef, 0xc7, 0xcb, 0x1d, 0x35, 0xfb, 0x11, 0x06, 0xbf, 0xf0, 0xad, 0xdb,
-	0x49, 0x92, 0x5b, 0x1c, 0x5f, 0xdc, 0x92, 0x3b, 0x3d, 0x22, 0xf5, 0x7f, 0x54, 0xe0, 0xa6, 0x3c,
-	0xe1, 0xb3, 0xff, 0xd1, 0x79, 0x96, 0x73, 0xef, 0x9a, 0x65, 0xbd, 0x01, 0xb7, 0x66, 0xf9, 0x92,
-	0x3e, 0xff, 0xff, 0xf2, 0x80, 0xe6, 0x6f, 0x97, 0xe8, 0x7b, 0x50, 0xa3, 0xc4, 0xb5, 0x0c, 0x71,
-	0x5e, 0x88, 0xa3, 0xac, 0x8c, 0xab, 0xac, 0x4d, 0x1c, 0x1c, 0x94, 0xb9, 0x40, 0x72, 0x29, 0xb9,
-	0x2d, 0x63, 0xfe, 0x8d, 0xce, 0xa0, 0xf6, 0x92, 0x1a, 0xf1, 0xda, 0xdc, 0xa0, 0xea, 0x99, 0xdd,
-	0xda, 0x3c, 0x1f, 0xad, 0xc7, 0xfd, 0xf8, 0x7f, 0xe1, 0xea, 0x4b, 0x1a, 0x13, 0xe8, 0x97, 0x0a,
-	0xdc, 0x8e, 0xc2, 0x8a, 0xa9, 0xf8, 0x1c, 0xcf, 0x22, 0xb4, 0x91, 0xbf, 0xab, 0x6e, 0xd7, 0x77,
-	0x8e, 0xaf, 0x21, 0xbf, 0xb9, 0xc6, 0x43, 0xcf, 0x22, 0xf8, 0xa6, 0xbb, 0xa0, 0x95, 0xa2, 0x16,
-	0xdc, 0x70, 0x26, 0x34, 0x34, 0x84, 0x15, 0x18, 0x72, 0x50, 0xa3, 0xc0, 0xe5, 0xb2, 0xc9, 0xba,
-	0x52, 0xb6, 0x8a, 0xce, 0x61, 0xdd, 0xf1, 0x26, 0x6e, 0x68, 0x0c, 0xf9, 0xfd, 0x87, 0x36, 0x8a,
-	0x4b, 0x5d, 0x8c, 0x17, 0x48, 0xe9, 0x90, 0xc1, 0x89, 0xdb, 0x14, 0xc5, 0x35, 0x27, 0x41, 0x31,
-	0x45, 0x06, 0xc4, 0xf1, 0x42, 0x62, 0x30, 0x7f, 0x49, 0x1b, 0x25, 0xa1, 0x48, 0xd1, 0xc6, 0x5c,
-	0x03, 0xd5, 0x5b, 0x50, 0x4d, 0x88, 0x19, 0x95, 0x21, 0xdf, 0x3b, 0xea, 0x75, 0xb5, 0x35, 0x04,
-	0x50, 0xec, 0xec, 0xe1, 0xa3, 0xa3, 0x81, 0xb8, 0x35, 0xec, 0x1f, 0xb6, 0x9f, 0x74, 0xb5, 0x9c,
-	0xde, 0x85, 0x5a, 0x72, 0x41, 0x84, 0xa0, 0x7e, 0xd2, 0x7b, 0xda, 0x3b, 0x7a, 0xd6, 0x33, 0x0e,
-	0x8f, 0x4e, 0x7a, 0x03, 0x76, 0xdf, 0xa8, 0x03, 0xb4, 0x7b, 0xcf, 0xa7, 0xf4, 0x3a, 0x54, 0x7a,
-	0x47, 0x11, 0xa9, 0x34, 0x73, 0x9a, 0xa2, 0xff, 0xbb, 0x0a, 0x5b, 0x8b, 0x64, 0x8f, 0x2c, 0xc8,
-	0x33, 0x3d, 0xca, 0x1b, 0xdf, 0xbb, 0x57, 0x23, 0x47, 0x67, 0xe6, 0xeb, 0x9b, 0xd2, 0xc5, 0x57,
-	0x30, 0xff, 0x46, 0x06, 0x14, 0xc7, 0xe6, 0x29, 0x19, 0xd3, 0x86, 0xca, 0x73, 0x22, 0x4f, 0xae,
-	0xb3, 0xf6, 0x01, 0x47, 0x12, 0x09, 0x11, 0x09, 0x8b, 0x06, 0x50, 0x65, 0x4e, 0x8c, 0x0a, 0xd1,
-	0x49, 0xbf, 0xba, 0x93, 0x71, 0x95, 0xbd, 0xe9, 0x4c, 0x9c, 0x84, 0x69, 0x3e, 0x80, 0x6a, 0x62,
-	0xb1, 0x05, 0xf9, 0x8c, 0xad, 0x64, 0x3e, 0xa3, 0x92, 0x4c, 0x4e, 0x3c, 0x9a, 0xd7, 0x01, 0x93,
-	0x11, 0x33, 0x82, 0xbd, 0xa3, 0xfe, 0x40, 0xdc, 0x1c, 0x9f, 0xe0, 0xa3, 0x93, 0x63, 0x4d, 0x61,
-	0x8d, 0x83, 0x76, 0xff, 0xa9, 0x96, 0x8b, 0x6d, 0x44, 0xd5, 0x3b, 0x50, 0x4d, 0xf0, 0x95, 0xf2,
-	0xda, 0x4a, 0xda, 0x6b, 0x33, 0xbf, 0x69, 0x5a, 0x56, 0x40, 0x28, 0x95, 0x7c, 0x44, 0xa4, 0xfe,
-	0x02, 0x2a, 0xbb, 0xbd, 0xbe, 0x84, 0x68, 0x40, 0x89, 0x92, 0x80, 0xfd, 0x6f, 0x9e, 0x99, 0xaa,
-	0xe0, 0x88, 0x64, 0xe0, 0x94, 0x98, 0xc1, 0xf0, 0x8c, 0x50, 0x79, 0xd6, 0xc7, 0x34, 0x9b, 0xe5,
-	0xf1, 0x0c, 0x8f, 0xd0, 0x5d, 0x05, 0x47, 0xa4, 0xfe, 0x3f, 0x25, 0x80, 0x69, 0xb6, 0x01, 0xd5,
-	0x21, 0x17, 0xfb, 0xe0, 0x9c, 0x6d, 0x31, 0x3b, 0x48, 0x9c, 0x31, 0xfc, 0x1b, 0xed, 0xc0, 0x4d,
-	0x87, 0x8e, 0x7c, 0x73, 0x78, 0x6e, 0xc8, 0x24, 0x81, 0xd8, 0xaa, 0xdc, 0x9f, 0xd5, 0xf0, 0x0d,
-	0xd9, 0x29, 0x77, 0xa2, 0xc0, 0x3d, 0x00, 0x95, 0xb8, 0x17, 0xdc, 0xf7, 0x54, 0x77, 0x3e, 0x5b,
-	0x3a, 0x0b, 0xd2, 0xea, 0xba, 0x17, 0xc2, 0x56, 0x18, 0x0c, 0x32, 0x00, 0x2c, 0x72, 0x61, 0x0f,
-	0x89, 0xc1, 0x40, 0x0b, 0x1c, 0xf4, 0x8b, 0xe5, 0x41, 0x77, 0x39, 0x46, 0x0c, 0x5d, 0xb1, 0x22,
-	0x1a, 0xf5, 0xa0, 0x12, 0x10, 0xea, 0x4d, 0x82, 0x21, 0x11, 0x0e, 0x28, 0xfb, 0x45, 0x05, 0x47,
-	0xf3, 0xf0, 0x14, 0x02, 0xed, 0x42, 0x91, 0xfb, 0x1d, 0xe6, 0x61, 0xd4, 0xef, 0x4c, 0xa9, 0xa6,
-	0xc1, 0xb8, 0x27, 0xc1, 0x72, 0x2e, 0x7a, 0x02, 0x25, 0xc1, 0x22, 0x6d, 0x94, 0x39, 0xcc, 0x47,
-	0x59, 0x9d, 0x22, 0x9f, 0x85, 0xa3, 0xd9, 0x4c, 0xab, 0x13, 0x4a, 0x82, 0x46, 0x45, 0x68, 0x95,
-	0x7d, 0xa3, 0xf7, 0xa0, 0x22, 0xce, 0x60, 0xcb, 0x0e, 0x1a, 0x20, 0x8c, 0x93, 0x37, 0xec, 0xda,
-	0x01, 0x7a, 0x1f, 0xaa, 0x22, 0xd6, 0x32, 0xb8, 0x57, 0xa8, 0xf2, 0x6e, 0x10, 0x4d, 0xc7, 0xcc,
-	0x37, 0x88, 0x01, 0x24, 0x08, 0xc4, 0x80, 0x5a, 0x3c, 0x80, 0x04, 0x01, 0x1f, 0xf0, 0x07, 0xb0,
-	0xc1, 0x23, 0xd4, 0x51, 0xe0, 0x4d, 0x7c, 0x83, 0xdb, 0xd4, 0x3a, 0x1f, 0xb4, 0xce, 0x9a, 0x9f,
-	0xb0, 0xd6, 0x1e, 0x33, 0xae, 0x3b, 0x50, 0x7e, 0xe5, 0x9d, 0x8a, 0x01, 0x75, 0xb1, 0x0f, 0x5e,
-	0x79, 0xa7, 0x51, 0x57, 0x1c, 0x25, 0x6c, 0xa4, 0xa3, 0x84, 0x6f, 0xe0, 0xd6, 0xfc, 0x71, 0xc7,
-	0xa3, 0x05, 0xed, 0xfa, 0xd1, 0xc2, 0x96, 0xbb, 0xc8, 0x0f, 0x7f, 0x09, 0xaa, 0xe5, 0xd2, 0xc6,
-	0xe6, 0x52, 0xc6, 0x11, 0xef, 0x63, 0xcc, 0x26, 0x37, 0x3f, 0x81, 0x72, 0x64, 0x7d, 0xcb, 0xf8,
-	0xa5, 0xe6, 0x43, 0xa8, 0xa7, 0x6d, 0x77, 0x29, 0xaf, 0xf6, 0xcf, 0x39, 0xa8, 0xc4, 0x56, 0x8a,
-	0x5c, 0xb8, 0xc1, 0xa5, 0xc8, 0x42, 0x34, 0x63, 0x6a, 0xf4, 0x22, 0x30, 0xfc, 0x3c, 0xe3, 0xff,
-	0x6a, 0x47, 0x08, 0xf2, 0x86, 0x2a, 0x77, 0x00, 0x8a, 0x91, 0xa7, 0xeb, 0x7d, 0x0d, 0x1b, 0x63,
-	0xdb, 0x9d, 0x5c, 0x26, 0xd6, 0x12, 0x11, 0xdd, 0x1f, 0x66, 0x5c, 0xeb, 0x80, 0xcd, 0x9e, 0xae,
-	0x51, 0x1f, 0xa7, 0x68, 0xb4, 0x07, 0x05, 0xdf, 0x0b, 0xc2, 0xe8, 0x90, 0xca, 0x7a, 0x7c, 0x1c,
-	0x7b, 0x41, 0x78, 0x68, 0xfa, 0x3e, 0xbb, 0xb4, 0x08, 0x00, 0xfd, 0xdb, 0x1c, 0xdc, 0x5a, 0xfc,
-	0xc7, 0x50, 0x0f, 0xd4, 0xa1, 0x3f, 0x91, 0x42, 0x7a, 0xb8, 0xac, 0x90, 0x3a, 0xfe, 0x64, 0xca,
-	0x3f, 0x03, 0x42, 0xcf, 0xa0, 0xe8, 0x10, 0xc7, 0x0b, 0xae, 0xa4, 0x2c, 0x1e, 0x2d, 0x0b, 0x79,
-	0xc8, 0x67, 0x4f, 0x51, 0x25, 0x1c, 0xc2, 0x50, 0x96, 0xd6, 0x4b, 0xa5, 0x9f, 0x5c, 0x32, 0xad,
-	0x14, 0x41, 0xe2, 0x18, 0x47, 0xff, 0x04, 0x6e, 0x2e, 0xfc, 0x2b, 0xe8, 0x77, 0x01, 0x86, 0xfe,
-	0xc4, 0xe0, 0x69, 0x7f, 0x61, 0x41, 0x2a, 0xae, 0x0c, 0xfd, 0x49, 0x9f, 0x37, 0xe8, 0x2f, 0xa0,
-	0xf1, 0x26, 0x7e, 0x99, 0xf7, 0x11, 0x1c, 0x1b, 0xce, 0x29, 0x97, 0x81, 0x8a, 0xcb, 0xa2, 0xe1,
-	0xf0, 0x14, 0xe9, 0xb0, 0x1e, 0x75, 0x9a, 0x97, 0x6c, 0x80, 0xca, 0x07, 0x54, 0xe5, 0x00, 0xf3,
-	0xf2, 0xf0, 0x54, 0xff, 0x55, 0x0e, 0x36, 0x66, 0x58, 0x66, 0x57, 0x37, 0xe1, 0xf1, 0xa2, 0x4b,
-	0xb1, 0xa0, 0x98, 0xfb, 0x1b, 0xda, 0x56, 0x94, 0x4e, 0xe5, 0xdf, 0xfc, 0xe0, 0xf3, 0x65, 0xaa,
-	0x33, 0x67, 0xfb, 0x6c, 0xfb, 0x38, 0xa7, 0x76, 0x48, 0x79, 0x14, 0x52, 0xc0, 0x82, 0x40, 0xcf,
-	0xa1, 0x1e, 0x10, 0x7e, 0xe0, 0x5a, 0x86, 0xb0, 0xb2, 0xc2, 0x52, 0x56, 0x26, 0x39, 0x64, 0xc6,
-	0x86, 0xd7, 0x23, 0x24, 0x46, 0x51, 0xf4, 0x0c, 0xd6, 0xad, 0x2b, 0xd7, 0x74, 0xec, 0xa1, 0x44,
-	0x2e, 0xae, 0x8c, 0x5c, 0x93, 0x40, 0x1c, 0x58, 0x7f, 0x00, 0xd5, 0x44, 0x27, 0xfb, 0x63, 0x3c,
-	0xdc, 0x92, 0x32, 0x11, 0x44, 0xda, 0x5b, 0x14, 0xa4, 0xb7, 0xd0, 0x4f, 0xa1, 0x9a, 0xd8, 0x17,
-	0xcb, 0x4c, 0x65, 0xf2, 0x0c, 0x3d, 0x2e, 0xcf, 0x02, 0xce, 0x85, 0x1e, 0xba, 0x0d, 0x25, 0x16,
-	0xea, 0x18, 0xb6, 0xcf, 0x25, 0x5a, 0xc1, 0x45, 0x46, 0xee, 0xfb, 0xfa, 0x6f, 0x72, 0x50, 0x4f,
-	0x6f, 0xe9, 0xc8, 0x8e, 0x7c, 0x12, 0xd8, 0x9e, 0x95, 0xb0, 0xa3, 0x63, 0xde, 0xc0, 0x6c, 0x85,
-	0x75, 0x7f, 0x33, 0xf1, 0x42, 0x33, 0xb2, 0x95, 0xa1, 0x3f, 0xf9, 0x23, 0x46, 0xcf, 0xd8, 0xa0,
-	0x3a, 0x63, 0x83, 0xe8, 0x43, 0x40, 0xd2, 0x94, 0xc6, 0xb6, 0x63, 0x87, 0xc6, 0xe9, 0x55, 0x48,
-	0x84, 0x8e, 0x55, 0xac, 0x89, 0x9e, 0x03, 0xd6, 0xf1, 0x25, 0x6b, 0x67, 0x86, 0xe7, 0x79, 0x8e,
-	0x41, 0x87, 0x5e, 0x40, 0x0c, 0xd3, 0x7a, 0xc5, 0x6f, 0x2d, 0x2a, 0xae, 0x7a, 0x9e, 0xd3, 0x67,
-	0x6d, 0x6d, 0xeb, 0x15, 0x3b, 0xf9, 0x86, 0xfe, 0x84, 0x92, 0xd0, 0x60, 0x3f, 0x3c, 0x58, 0xa8,
-	0x60, 0x10, 0x4d, 0x1d, 0x7f, 0x42, 0xd1, 0xef, 0xc3, 0x7a, 0x34, 0x80, 0x1f, 0x7e, 0xf2, 0xd4,
-	0xad, 0xc9, 0x21, 0xbc, 0x0d, 0xe9, 0x50, 0x3b, 0x26, 0xc1, 0x90, 0xb8, 0xe1, 0xc0, 0x1e, 0x9e,
-	0xb3, 0xf3, 0x5d, 0xd9, 0x56, 0x70, 0xaa, 0xed, 0xab, 0x7c, 0xb9, 0xa4, 0x95, 0x71, 0xb4, 0x9a,
-	0x43, 0x1c, 0xaa, 0xff, 0x0c, 0x0a, 0x3c, 0x44, 0x60, 0x32, 0xe1, 0xc7, 0x2b, 0x3f, 0x7d, 0x65,
-	0x68, 0xc9, 0x1a, 0xf8, 0xd9, 0xfb, 0x1e, 0x54, 0xb8, 0xec, 0x13, 0x11, 0x3d, 0x8f, 0x3b, 0x79,
-	0x67, 0x13, 0xca, 0x01, 0x31, 0x2d, 0xcf, 0x1d, 0x47, 0xc9, 0xa0, 0x98, 0xd6, 0xbf, 0x81, 0xa2,
-	0x38, 0x67, 0xae, 0x81, 0xff, 0x11, 0x20, 0xf1, 0xbf, 0x99, 0x3e, 0x1d, 0x9b, 0x52, 0x19, 0x85,
-	0xf2, 0x17, 0x48, 0xd1, 0x73, 0x3c, 0xed, 0xd0, 0xff, 0x53, 0x11, 0xf1, 0xa8, 0x78, 0x1b, 0x62,
-	0x81, 0x2b, 0x33, 0x72, 0x76, 0x5b, 0x16, 0x49, 0xa8, 0x88, 0x44, 0xfb, 0x50, 0x94, 0x61, 0x67,
-	0x6e, 0xd5, 0xa7, 0x35, 0x09, 0x10, 0xa5, 0xa4, 0x89, 0xbc, 0x90, 0x2f, 0x9b, 0x92, 0x26, 0x22,
-	0x25, 0x4d, 0xd8, 0x6d, 0x52, 0x06, 0xc4, 0x02, 0x2e, 0xcf, 0xe3, 0xe1, 0xaa, 0x15, 0xe7, 0xfd,
-	0x89, 0xfe, 0xdf, 0x4a, 0xec, 0xa6, 0xa2, 0xfc, 0x3c, 0xfa, 0x1a, 0xca, 0x6c, 0xc7, 0x1b, 0x8e,
-	0xe9, 0xcb, 0xd7, 0xe6, 0xce, 0x6a, 0xa9, 0xff, 0xe8, 0x10, 0x13, 0xe1, 0x6c, 0xc9, 0x17, 0x14,
-	0x73, 0x77, 0xec, 0x2a, 0x11, 0xb9, 0x3b, 0xf6, 0x8d, 0x3e, 0x80, 0xba, 0x39, 0x09, 0x3d, 0xc3,
-	0xb4, 0x2e, 0x48, 0x10, 0xda, 0x94, 0x48, 0xdd, 0xaf, 0xb3, 0xd6, 0x76, 0xd4, 0xd8, 0xfc, 0x0c,
-	0x6a, 0x49, 0xcc, 0xb7, 0x85, 0x19, 0x85, 0x64, 0x98, 0xf1, 0xa7, 0x00, 0xd3, 0x5c, 0x17, 0xb3,
-	0x11, 0x72, 0x69, 0xb3, 0x5b, 0xbd, 0xbc, 0xbb, 0x16, 0x70, 0x99, 0x35, 0x74, 0xd8, 0x7d, 0x2a,
-	0x9d, 0x88, 0x2f, 0x44, 0x89, 0x78, 0xb6, 0x99, 0xd9, 0xfe, 0x3b, 0xb7, 0xc7, 0xe3, 0x38, 0xff,
-	0x56, 0xf1, 0x3c, 0xe7, 0x29, 0x6f, 0xd0, 0x7f, 0x9b, 0x13, 0xb6, 0x22, 0x9e, 0x54, 0x32, 0xdd,
-	0x5d, 0xde, 0x95, 0xaa, 0x1f, 0x00, 0xd0, 0xd0, 0x0c, 0x58, 0xcc, 0x64, 0x46, 0x19, 0xc0, 0xe6,
-	0x5c, 0x26, 0x7f, 0x10, 0xd5, 0x78, 0xe0, 0x8a, 0x1c, 0xdd, 0x0e, 0xd1, 0xe7, 0x50, 0x1b, 0x7a,
-	0x8e, 0x3f, 0x26, 0x72, 0x72, 0xe1, 0xad, 0x93, 0xab, 0xf1, 0xf8, 0x76, 0x98, 0xc8, 0x3b, 0x16,
-	0xaf, 0x9b, 0x77, 0xfc, 0x8d, 0x22, 0x5e, 0x86, 0x92, 0x0f, 0x53, 0x68, 0xb4, 0xa0, 0xfa, 0xe1,
-	0xc9, 0x8a, 0xaf, 0x5c, 0xdf, 0x55, 0xfa, 0xd0, 0xfc, 0x3c, 0x4b, 0xad, 0xc1, 0x9b, 0xa3, 0xd8,
-	0x7f, 0x53, 0xa1, 0x12, 0x3f, 0x0a, 0xcd, 0xe9, 0xfe, 0x53, 0xa8, 0xc4, 0x05, 0x36, 0xd2, 0x41,
-	0x7c, 0xa7, 0x7a, 0xe2, 0xc1, 0xe8, 0x25, 0x20, 0x73, 0x34, 0x8a, 0xa3, 0x53, 0x63, 0x42, 0xcd,
-	0x51, 0xf4, 0x24, 0xf7, 0xe9, 0x12, 0x72, 0x88, 0x8e, 0xb3, 0x13, 0x36, 0x1f, 0x6b, 0xe6, 0x68,
-	0x94, 0x6a, 0x41, 0x7f, 0x06, 0x37, 0xd3, 0x6b, 0x18, 0xa7, 0x57, 0x86, 0x6f, 0x5b, 0xf2, 0x8e,
-	0xbc, 0xb7, 0xec, 0xbb, 0x58, 0x2b, 0x05, 0xff, 0xe5, 0xd5, 0xb1, 0x6d, 0x09, 0x99, 0xa3, 0x60,
-	0xae, 0xa3, 0xf9, 0x17, 0x70, 0xfb, 0x0d, 0xc3, 0x17, 0xe8, 0xa0, 0x97, 0xae, 0xf7, 0x58, 0x5d,
-	0x08, 0x09, 0xed, 0xfd, 0x5a, 0x11, 0xcf, 0x77, 0x69, 0x99, 0xb4, 0x93, 0x61, 0xf5, 0xbd, 0x8c,
-	0xeb, 0x74, 0x8e, 0x4f, 0x04, 0x3c, 0x8f, 0xa4, 0xbf, 0x9a, 0x89, 0xa4, 0xb3, 0xc6, 0x4f, 0x22,
-	0x20, 0x15, 0x40, 0x12, 0x41, 0xff, 0x17, 0x15, 0xca, 0x11, 0x3a, 0xbf, 0xe1, 0x5e, 0xd1, 0x90,
-	0x38, 0x46, 0x9c, 0x7e, 0x53, 0x30, 0x88, 0x26, 0x9e, 0x14, 0x7a, 0x0f, 0x2a, 0xec, 0x22, 0x2d,
-	0xba, 0x73, 0xbc, 0xbb, 0xcc, 0x1a, 0x78, 0xe7, 0xfb, 0x50, 0x0d, 0xbd, 0xd0, 0x1c, 0x1b, 0x21,
-	0x3f, 0xde, 0x55, 0x31, 0x9b, 0x37, 0xf1, 0xc3, 0x1d, 0xfd, 0x00, 0x36, 0xc3, 0xb3, 0xc0, 0x0b,
-	0xc3, 0x31, 0x0b, 0x2d, 0x79, 0xa0, 0x23, 0xe2, 0x92, 0x3c, 0xd6, 0xe2, 0x0e, 0x11, 0x00, 0x51,
-	0xe6, 0xbd, 0xa7, 0x83, 0x99, 0xe9, 0x72, 0x27, 0x92, 0xc7, 0xeb, 0x71, 0x2b, 0x33, 0x6d, 0x76,
-	0x78, 0xfa, 0x22, 0x80, 0xe0, 0xbe, 0x42, 0xc1, 0x11, 0x89, 0x0c, 0xd8, 0x70, 0x88, 0x49, 0x27,
-	0x01, 0xb1, 0x8c, 0x97, 0x36, 0x19, 0x5b, 0x22, 0x31, 0x51, 0xcf, 0x7c, 0x3b, 0x88, 0xc4, 0xd2,
-	0x7a, 0xcc, 0x67, 0xe3, 0x7a, 0x04, 0x27, 0x68, 0x16, 0x39, 0x88, 0x2f, 0xb4, 0x01, 0xd5, 0xfe,
-	0xf3, 0xfe, 0xa0, 0x7b, 0x68, 0x1c, 0x1e, 0xed, 0x76, 0x65, 0x49, 0x4f, 0xbf, 0x8b, 0x05, 0xa9,
-	0xb0, 0xfe, 0xc1, 0xd1, 0xa0, 0x7d, 0x60, 0x0c, 0xf6, 0x3b, 0x4f, 0xfb, 0x5a, 0x0e, 0xdd, 0x84,
-	0xcd, 0xc1, 0x1e, 0x3e, 0x1a, 0x0c, 0x0e, 0xba, 0xbb, 0xc6, 0x71, 0x17, 0xef, 0x1f, 0xed, 0xf6,
-	0x35, 0x15, 0x21, 0xa8, 0x4f, 0x9b, 0x07, 0xfb, 0x87, 0x5d, 0x2d, 0x8f, 0xaa, 0x50, 0x3a, 0xee,
-	0xe2, 0x4e, 0xb7, 0x37, 0xd0, 0x0a, 0xfa, 0xaf, 0x54, 0xa8, 0x26, 0xb4, 0xc8, 0x0c, 0x39, 0xa0,
-	0xe2, 0x1a, 0x92, 0xc7, 0xec, 0x93, 0x3f, 0x41, 0x9a, 0xc3, 0x33, 0xa1, 0x9d, 0x3c, 0x16, 0x04,
-	0xbf, 0x7a, 0x98, 0x97, 0x89, 0x7d, 0x9e, 0xc7, 0x65, 0xc7, 0xbc, 0x14, 0x20, 0xdf, 0x83, 0xda,
-	0x39, 0x09, 0x5c, 0x32, 0x96, 0xfd, 0x42, 0x23, 0x55, 0xd1, 0x26, 0x86, 0x6c, 0x83, 0x26, 0x87,
-	0x4c, 0x61, 0x84, 0x3a, 0xea, 0xa2, 0xfd, 0x30, 0x02, 0xdb, 0x82, 0x82, 0xe8, 0x2e, 0x89, 0xf5,
-	0x39, 0xc1, 0x8e, 0x29, 0xfa, 0xda, 0xf4, 0x79, 0xc8, 0x97, 0xc7, 0xfc, 0x1b, 0x9d, 0xce, 0xeb,
-	0xa7, 0xc8, 0xf5, 0xf3, 0x60, 0x79, 0x73, 0x7e, 0x93, 0x8a, 0xce, 0x62, 0x15, 0x95, 0x40, 0xc5,
-	0x51, 0x1d, 0x4c, 0xa7, 0xdd, 0xd9, 0x63, 0x6a, 0x59, 0x87, 0xca, 0x61, 0xfb, 0xa7, 0xc6, 0x49,
-	0x9f, 0x67, 0xb5, 0x91, 0x06, 0xb5, 0xa7, 0x5d, 0xdc, 0xeb, 0x1e, 0xc8, 0x16, 0x15, 0x6d, 0x81,
-	0x26, 0x5b, 0xa6, 0xe3, 0xf2, 0x0c, 0x41, 0x7c, 0x16, 0x50, 0x19, 0xf2, 0xfd, 0x67, 0xed, 0x63,
-	0xad, 0xa8, 0xff, 0x57, 0x0e, 0x36, 0xc4, 0xb1, 0x10, 0xbf, 0xd8, 0xbf, 0xf9, 0xc5, 0x32, 0x99,
-	0xe5, 0xc9, 0xa5, 0xb3, 0x3c, 0x51, 0x10, 0xca, 0x4f, 0x75, 0x75, 0x1a, 0x84, 0xf2, 0xec, 0x50,
-	0xca, 0xe3, 0xe7, 0x97, 0xf1, 0xf8, 0x0d, 0x28, 0x39, 0x84, 0xc6, 0x7a, 0xab, 0xe0, 0x88, 0x44,
-	0x36, 0x54, 0x4d, 0xd7, 0xf5, 0x42, 0x53, 0xa4, 0x4e, 0x8b, 0x4b, 0x1d, 0x86, 0x33, 0xff, 0xb8,
-	0xd5, 0x9e, 0x22, 0x09, 0xc7, 0x9c, 0xc4, 0x6e, 0xfe, 0x04, 0xb4, 0xd9, 0x01, 0xcb, 0x1c, 0x87,
-	0xdf, 0xff, 0xe1, 0xf4, 0x34, 0x24, 0x6c, 0x5f, 0xc8, 0x37, 0x07, 0x6d, 0x8d, 0x11, 0xf8, 0xa4,
-	0xd7, 0xdb, 0xef, 0x3d, 0xd1, 0x14, 0x04, 0x50, 0xec, 0xfe, 0x74, 0x7f, 0xd0, 0xdd, 0xd5, 0x72,
-	0x3b, 0xbf, 0xde, 0x84, 0xa2, 0x60, 0x12, 0x7d, 0x2b, 0x23, 0x81, 0x64, 0x35, 0x28, 0xfa, 0xc9,
-	0xd2, 0x11, 0x75, 0xaa, 0xc2, 0xb4, 0xf9, 0x68, 0xe5, 0xf9, 0xf2, 0xf5, 0x6d, 0x0d, 0xfd, 0x8d,
-	0x02, 0xb5, 0xd4, 0xcb, 0x5b, 0xd6, 0xd4, 0xf1, 0x82, 0xe2, 0xd3, 0xe6, 0x8f, 0x57, 0x9a, 0x1b,
-	0xf3, 0xf2, 0x4b, 0x05, 0xaa, 0x89, 0xb2, 0x4b, 0xf4, 0x60, 0x95, 0x52, 0x4d, 0xc1, 0xc9, 0x67,
-	0xab, 0x57, 0x79, 0xea, 0x6b, 0x1f, 0x2b, 0xe8, 0xaf, 0x15, 0xa8, 0x26, 0x0a, 0x10, 0x33, 0xb3,
-	0x32, 0x5f, 0x2e, 0x99, 0x99, 0x95, 0x45, 0xf5, 0x8e, 0x6b, 0xe8, 0x2f, 0x15, 0xa8, 0xc4, 0xc5,
-	0x84, 0xe8, 0xfe, 0xf2, 0xe5, 0x87, 0x82, 0x89, 0x4f, 0x57, 0xad, 0x5b, 0xd4, 0xd7, 0xd0, 0x9f,
-	0x43, 0x39, 0xaa, 0xbc, 0x43, 0x59, 0x4f, 0xaf, 0x99, 0xb2, 0xbe, 0xe6, 0xfd, 0xa5, 0xe7, 0x25,
-	0x97, 0x8f, 0xca, 0xe1, 0x32, 0x2f, 0x3f, 0x53, 0xb8, 0xd7, 0xbc, 0xbf, 0xf4, 0xbc, 0x78, 0x79,
-	0x66, 0x09, 0x89, 0xaa, 0xb9, 0xcc, 0x96, 0x30, 0x5f, 0xae, 0x97, 0xd9, 0x12, 0x16, 0x15, 0xe9,
-	0x09, 0x46, 0x12, 0x75, 0x77, 0x99, 0x19, 0x99, 0xaf, 0xed, 0xcb, 0xcc, 0xc8, 0x82, 0x32, 0x3f,
-	0x7d, 0x0d, 0xfd, 0x42, 0x49, 0xde, 0x0b, 0xee, 0x2f, 0x5d, 0x5e, 0xb6, 0xa4, 0x49, 0xce, 0x15,
-	0xb8, 0xf1, 0x0d, 0xfa, 0x0b, 0x99, 0xc5, 0x10, 0xd5, 0x69, 0x68, 0x19, 0xb0, 0x54, 0x41, 0x5b,
-	0xf3, 0x93, 0xd5, 0x0e, 0x1b, 0xce, 0xc4, 0x5f, 0x29, 0x00, 0xd3, 0x3a, 0xb6, 0xcc, 0x4c, 0xcc,
-	0x15, 0xd0, 0x35, 0x1f, 0xac, 0x30, 0x33, 0xb9, 0x41, 0xa2, 0x3a, 0x9b, 0xcc, 0x1b, 0x64, 0xa6,
-	0xce, 0x2e, 0xf3, 0x06, 0x99, 0xad, 0x91, 0xd3, 0xd7, 0xd0, 0x3f, 0x29, 0xb0, 0x39, 0x57, 0xe7,
-	0x83, 0x1e, 0x5d, 0xb3, 0xd4, 0xab, 0xf9, 0xc5, 0xea, 0x00, 0x11, 0x6b, 0xdb, 0xca, 0xc7, 0x0a,
-	0xfa, 0x5b, 0x05, 0xd6, 0xd3, 0xf5, 0x0f, 0x99, 0x4f, 0xa9, 0x05, 0x15, 0x43, 0xcd, 0x87, 0xab,
-	0x4d, 0x8e, 0xa5, 0xf5, 0xf7, 0x0a, 0xd4, 0xd3, 0xa5, 0x30, 0xe8, 0xe1, 0x72, 0x6e, 0x61, 0x86,
-	0xa1, 0xcf, 0x57, 0x9c, 0x1d, 0x71, 0xf4, 0x65, 0xe9, 0x8f, 0x0b, 0x22, 0x7a, 0x2b, 0xf2, 0x9f,
-	0x1f, 0xfd, 0x7f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x76, 0x8b, 0xb6, 0x52, 0xb4, 0x33, 0x00, 0x00,
+	// 4160 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5a, 0xcd, 0x6f, 0x1b, 0x49,
+	0x76, 0x77, 0xf3, 0x4b, 0xe4, 0xa3, 0x44, 0x51, 0x65, 0xc9, 0x43, 0x73, 0x92, 0x5d, 0x6f, 0x07,
+	0x13, 0x18, 0xb3, 0x33, 0xf4, 0xac, 0x36, 0x19, 0x8f, 0xbd, 0xf6, 0x78, 0x38, 0x14, 0x6d, 0x69,
+	0x2c, 0x51, 0x4a, 0x91, 0x82, 0xd7, 0x71, 0x32, 0x9d, 0x56, 0x77, 0x99, 0x6a, 0x8b, 0xcd, 0xee,
+	0xe9, 0x6a, 0xca, 0xd2, 0x06, 0xc1, 0x06, 0xb3, 0x40, 0xb0, 0x01, 0x12, 0x64, 0x2f, 0x93, 0x5c,
+	0x72, 0x0b, 0x72, 0xca, 0x3f, 0x10, 0x6c, 0xb0, 0x40, 0x80, 0x0d, 0x90, 0x63, 0xf2, 0x07, 0x24,
+	0x87, 0xdc, 0x72, 0xcd, 0x21, 0xf7, 0xa0, 0xbe, 0xfa, 0x83, 0x94, 0x57, 0x4d, 0xca, 0xd9, 0x13,
+	0xfb, 0xbd, 0xaa, 0xfa, 0xd5, 0xe3, 0xab, 0x57, 0xaf, 0x5e, 0xbd, 0x7a, 0xa0, 0xfb, 0xa3, 0xc9,
+	0xd0, 0x19, 0xd3, 0x3b, 0x76, 0xe0, 0x9c, 0x92, 0x80, 0xde, 0xf1, 0x03, 0x2f, 0xf4, 0x24, 0xd5,
+	0xe2, 0x04, 0x7a, 0xef, 0xd8, 0xa4, 0xc7, 0x8e, 0xe5, 0x05, 0x7e, 0x6b, 0xec, 0xb9, 0xa6, 0xdd,
+	0x92, 0x63, 0x5a, 0x72, 0x8c, 0xe8, 0xd6, 0xfc, 0xd6, 0xd0, 0xf3, 0x86, 0x23, 0x22, 0x10, 0x8e,
+	0x26, 0x2f, 0xef, 0xd8, 0x93, 0xc0, 0x0c, 0x1d, 0x6f, 0x2c, 0xdb, 0xbf, 0x3d, 0xdd, 0x1e, 0x3a,
+	0x2e, 0xa1, 0xa1, 0xe9, 0xfa, 0xb2, 0xc3, 0x7b, 0x4a, 0x16, 0x7a, 0x6c, 0x06, 0xc4, 0xbe, 0x73,
+	0x6c, 0x8d, 0xa8, 0x4f, 0x2c, 0xf6, 0x6b, 0xb0, 0x0f, 0xd9, 0xed, 0x83, 0xa9, 0x6e, 0x34, 0x0c,
+	0x26, 0x56, 0xa8, 0x24, 0x37, 0xc3, 0x30, 0x70, 0x8e, 0x26, 0x21, 0x11, 0xbd, 0xf5, 0x9b, 0xf0,
+	0xce, 0xc0, 0xa4, 0x27, 0x1d, 0x6f, 0xfc, 0xd2, 0x19, 0xf6, 0xad, 0x63, 0xe2, 0x9a, 0x98, 0x7c,
+	0x35, 0x21, 0x34, 0xd4, 0xff, 0x00, 0x1a, 0xb3, 0x4d, 0xd4, 0xf7, 0xc6, 0x94, 0xa0, 0xcf, 0xa0,
+	0xc0, 0xa6, 0x6c, 0x68, 0xb7, 0xb4, 0xdb, 0xd5, 0xcd, 0x0f, 0x5a, 0x6f, 0x52, 0x81, 0x90, 0xa1,
+	0x25, 0x45, 0x6d, 0xf5, 0x7d, 0x62, 0x61, 0x3e, 0x52, 0xdf, 0x80, 0xeb, 0x1d, 0xd3, 0x37, 0x8f,
+	0x9c, 0x91, 0x13, 0x3a, 0x84, 0xaa, 0x49, 0x27, 0xb0, 0x9e, 0x66, 0xcb, 0x09, 0xff, 0x10, 0x96,
+	0xad, 0x04, 0x5f, 0x4e, 0x7c, 0xaf, 0x95, 0x49, 0xf7, 0xad, 0x2d, 0x4e, 0xa5, 0x80, 0x53, 0x70,
+	0xfa, 0x3a, 0xa0, 0xc7, 0xce, 0x78, 0x48, 0x02, 0x3f, 0x70, 0xc6, 0xa1, 0x12, 0xe6, 0x17, 0x79,
+	0xb8, 0x9e, 0x62, 0x4b, 0x61, 0x5e, 0x01, 0x44, 0x7a, 0x64, 0xa2, 0xe4, 0x6f, 0x57, 0x37, 0xbf,
+	0xc8, 0x28, 0xca, 0x05, 0x78, 0xad, 0x76, 0x04, 0xd6, 0x1d, 0x87, 0xc1, 0x39, 0x4e, 0xa0, 0xa3,
+	0x2f, 0xa1, 0x74, 0x4c, 0xcc, 0x51, 0x78, 0xdc, 0xc8, 0xdd, 0xd2, 0x6e, 0xd7, 0x36, 0x1f, 0x5f,
+	0x61, 0x9e, 0x6d, 0x0e, 0xd4, 0x0f, 0xcd, 0x90, 0x60, 0x89, 0x8a, 0x3e, 0x04, 0x24, 0xbe, 0x0c,
+	0x9b, 0x50, 0x2b, 0x70, 0x7c, 0x66, 0x92, 0x8d, 0xfc, 0x2d, 0xed, 0x76, 0x05, 0xaf, 0x89, 0x96,
+	0xad, 0xb8, 0xa1, 0xe9, 0xc3, 0xea, 0x94, 0xb4, 0xa8, 0x0e, 0xf9, 0x13, 0x72, 0xce, 0x57, 0xa4,
+	0x82, 0xd9, 0x27, 0x7a, 0x02, 0xc5, 0x53, 0x73, 0x34, 0x21, 0x5c, 0xe4, 0xea, 0xe6, 0xf7, 0x2e,
+	0x33, 0x0f, 0x69, 0xa2, 0xb1, 0x1e, 0xb0, 0x18, 0x7f, 0x3f, 0xf7, 0x89, 0xa6, 0xdf, 0x83, 0x6a,
+	0x42, 0x6e, 0x54, 0x03, 0x38, 0xec, 0x6d, 0x75, 0x07, 0xdd, 0xce, 0xa0, 0xbb, 0x55, 0xbf, 0x86,
+	0x56, 0xa0, 0x72, 0xd8, 0xdb, 0xee, 0xb6, 0x77, 0x07, 0xdb, 0xcf, 0xeb, 0x1a, 0xaa, 0xc2, 0x92,
+	0x22, 0x72, 0xfa, 0x19, 0x20, 0x4c, 0x2c, 0xef, 0x94, 0x04, 0xcc, 0x90, 0xe5, 0xaa, 0xa2, 0x77,
+	0x60, 0x29, 0x34, 0xe9, 0x89, 0xe1, 0xd8, 0x52, 0xe6, 0x12, 0x23, 0x77, 0x6c, 0xb4, 0x03, 0xa5,
+	0x63, 0x73, 0x6c, 0x8f, 0x2e, 0x97, 0x3b, 0xad, 0x6a, 0x06, 0xbe, 0xcd, 0x07, 0x62, 0x09, 0xc0,
+	0xac, 0x3b, 0x35, 0xb3, 0x58, 0x00, 0xfd, 0x39, 0xd4, 0xfb, 0xa1, 0x19, 0x84, 0x49, 0x71, 0xba,
+	0x50, 0x60, 0xf3, 0x37, 0xb4, 0xb9, 0xe7, 0x14, 0x3b, 0x13, 0xf3, 0xe1, 0xfa, 0xff, 0xe4, 0x60,
+	0x2d, 0x81, 0x2d, 0x2d, 0xf5, 0x19, 0x94, 0x02, 0x42, 0x27, 0xa3, 0x90, 0xc3, 0xd7, 0x36, 0x1f,
+	0x65, 0x84, 0x9f, 0x41, 0x6a, 0x61, 0x0e, 0x83, 0x25, 0x1c, 0xba, 0x0d, 0x75, 0x31, 0xc2, 0x20,
+	0x41, 0xe0, 0x05, 0x86, 0x4b, 0x87, 0x5c, 0x6b, 0x15, 0x5c, 0x13, 0xfc, 0x2e, 0x63, 0xef, 0xd1,
+	0x61, 0x42, 0xab, 0xf9, 0x2b, 0x6a, 0x15, 0x99, 0x50, 0x1f, 0x93, 0xf0, 0xb5, 0x17, 0x9c, 0x18,
+	0x4c, 0xb5, 0x81, 0x63, 0x93, 0x46, 0x81, 0x83, 0x7e, 0x9c, 0x11, 0xb4, 0x27, 0x86, 0xef, 0xcb,
+	0xd1, 0x78, 0x75, 0x9c, 0x66, 0xe8, 0xdf, 0x85, 0x92, 0xf8, 0xa7, 0xcc, 0x92, 0xfa, 0x87, 0x9d,
+	0x4e, 0xb7, 0xdf, 0xaf, 0x5f, 0x43, 0x15, 0x28, 0xe2, 0xee, 0x00, 0x33, 0x0b, 0xab, 0x40, 0xf1,
+	0x71, 0x7b, 0xd0, 0xde, 0xad, 0xe7, 0xf4, 0xf7, 0x61, 0xf5, 0x99, 0xe9, 0x84, 0x59, 0x8c, 0x4b,
+	0xf7, 0xa0, 0x1e, 0xf7, 0x95, 0xab, 0xb3, 0x93, 0x5a, 0x9d, 0xec, 0xaa, 0xe9, 0x9e, 0x39, 0xe1,
+	0xd4, 0x7a, 0xd4, 0x21, 0x4f, 0x82, 0x40, 0x2e, 0x01, 0xfb, 0xd4, 0x5f, 0xc3, 0x6a, 0x3f, 0xf4,
+	0xfc, 0x4c, 0x96, 0xff, 0x7d, 0x58, 0x62, 0xa7, 0x8d, 0x37, 0x09, 0xa5, 0xe9, 0xdf, 0x6c, 0x89,
+	0xd3, 0xa8, 0xa5, 0x4e, 0xa3, 0xd6, 0x96, 0x3c, 0xad, 0xb0, 0xea, 0x89, 0x6e, 0x40, 0x89, 0x3a,
+	0xc3, 0xb1, 0x39, 0x92, 0xde, 0x42, 0x52, 0x3a, 0x82, 0x7a, 0x3c, 0xb1, 0x34, 0xfc, 0x0e, 0xa0,
+	0x2d, 0x42, 0xc3, 0xc0, 0x3b, 0xcf, 0x24, 0xcf, 0x3a, 0x14, 0x5f, 0x7a, 0x81, 0x25, 0x36, 0x62,
+	0x19, 0x0b, 0x82, 0x6d, 0xaa, 0x14, 0x88, 0xc4, 0xfe, 0x10, 0xd0, 0xce, 0x98, 0x9d, 0x29, 0xd9,
+	0x16, 0xe2, 0x67, 0x39, 0xb8, 0x9e, 0xea, 0x2f, 0x17, 0x63, 0xf1, 0x7d, 0xc8, 0x1c, 0xd3, 0x84,
+	0x8a, 0x7d, 0x88, 0xf6, 0xa1, 0x24, 0x7a, 0x48, 0x4d, 0xde, 0x9d, 0x03, 0x48, 0x1c, 0x53, 0x12,
+	0x4e, 0xc2, 0x5c, 0x68, 0xf4, 0xf9, 0xb7, 0x6b, 0xf4, 0xaf, 0xa1, 0xae, 0xfe, 0x07, 0xbd, 0x74,
+	0x6d, 0xbe, 0x80, 0xeb, 0x96, 0x37, 0x1a, 0x11, 0x8b, 0x59, 0x83, 0xe1, 0x8c, 0x43, 0x12, 0x9c,
+	0x9a, 0xa3, 0xcb, 0xed, 0x06, 0xc5, 0xa3, 0x76, 0xe4, 0x20, 0xfd, 0x05, 0xac, 0x25, 0x26, 0x96,
+	0x0b, 0xf1, 0x18, 0x8a, 0x94, 0x31, 0xe4, 0x4a, 0x7c, 0x34, 0xe7, 0x4a, 0x50, 0x2c, 0x86, 0xeb,
+	0xd7, 0x05, 0x78, 0xf7, 0x94, 0x8c, 0xa3, 0xbf, 0xa5, 0x6f, 0xc1, 0x5a, 0x9f, 0x9b, 0x69, 0x26,
+	0x3b, 0x8c, 0x4d, 0x3c, 0x97, 0x32, 0xf1, 0x75, 0x40, 0x49, 0x14, 0x69, 0x88, 0xe7, 0xb0, 0xda,
+	0x3d, 0x23, 0x56, 0x26, 0xe4, 0x06, 0x2c, 0x59, 0x9e, 0xeb, 0x9a, 0x63, 0xbb, 0x91, 0xbb, 0x95,
+	0xbf, 0x5d, 0xc1, 0x8a, 0x4c, 0xee, 0xc5, 0x7c, 0xd6, 0xbd, 0xa8, 0xff, 0xa5, 0x06, 0xf5, 0x78,
+	0x6e, 0xa9, 0x48, 0x26, 0x7d, 0x68, 0x33, 0x20, 0x36, 0xf7, 0x32, 0x96, 0x94, 0xe4, 0x2b, 0x77,
+	0x21, 0xf8, 0x24, 0x08, 0x12, 0xee, 0x28, 0x7f, 0x45, 0x77, 0xa4, 0x6f, 0xc3, 0x6f, 0x28, 0x71,
+	0xfa, 0x61, 0x40, 0x4c, 0xd7, 0x19, 0x0f, 0x77, 0xf6, 0xf7, 0x7d, 0x22, 0x04, 0x47, 0x08, 0x0a,
+	0xb6, 0x19, 0x9a, 0x52, 0x30, 0xfe, 0xcd, 0x36, 0xbd, 0x35, 0xf2, 0x68, 0xb4, 0xe9, 0x39, 0xa1,
+	0xff, 0x6b, 0x1e, 0x1a, 0x33, 0x50, 0x4a, 0xbd, 0x2f, 0xa0, 0x48, 0x49, 0x38, 0xf1, 0xa5, 0xa9,
+	0x74, 0x33, 0x0b, 0x7c, 0x31, 0x5e, 0xab, 0xcf, 0xc0, 0xb0, 0xc0, 0x44, 0x43, 0x28, 0x87, 0xe1,
+	0xb9, 0x41, 0x9d, 0x1f, 0xa9, 0x80, 0x60, 0xf7, 0xaa, 0xf8, 0x03, 0x12, 0xb8, 0xce, 0xd8, 0x1c,
+	0xf5, 0x9d, 0x1f, 0x11, 0xbc, 0x14, 0x86, 0xe7, 0xec, 0x03, 0x3d, 0x67, 0x06, 0x6f, 0x3b, 0x63,
+	0xa9, 0xf6, 0xce, 0xa2, 0xb3, 0x24, 0x14, 0x8c, 0x05, 0x62, 0x73, 0x17, 0x8a, 0xfc, 0x3f, 0x2d,
+	0x62, 0x88, 0x75, 0xc8, 0x87, 0xe1, 0x39, 0x17, 0xaa, 0x8c, 0xd9, 0x67, 0xf3, 0x01, 0x2c, 0x27,
+	0xff, 0x01, 0x33, 0xa4, 0x63, 0xe2, 0x0c, 0x8f, 0x85, 0x81, 0x15, 0xb1, 0xa4, 0xd8, 0x4a, 0xbe,
+	0x76, 0x6c, 0x19, 0xb2, 0x16, 0xb1, 0x20, 0xf4, 0x7f, 0xcc, 0xc1, 0xcd, 0x0b, 0x34, 0x23, 0x8d,
+	0xf5, 0x45, 0xca, 0x58, 0xdf, 0x92, 0x16, 0x94, 0xc5, 0xbf, 0x48, 0x59, 0xfc, 0x5b, 0x04, 0x67,
+	0xdb, 0xe6, 0x06, 0x94, 0xc8, 0x99, 0x13, 0x12, 0x5b, 0xaa, 0x4a, 0x52, 0x89, 0xed, 0x54, 0xb8,
+	0xea, 0x76, 0xda, 0x83, 0xf5, 0x4e, 0x40, 0xcc, 0x90, 0x48, 0x57, 0xae, 0xec, 0xff, 0x26, 0x94,
+	0xcd, 0xd1, 0xc8, 0xb3, 0xe2, 0x65, 0x5d, 0xe2, 0xf4, 0x8e, 0x8d, 0x9a, 0x50, 0x3e, 0xf6, 0x68,
+	0x38, 0x36, 0x5d, 0x22, 0x9d, 0x57, 0x44, 0xeb, 0xdf, 0x68, 0xb0, 0x31, 0x85, 0x27, 0x57, 0xe1,
+	0x08, 0x6a, 0x0e, 0xf5, 0x46, 0xfc, 0x0f, 0x1a, 0x89, 0x1b, 0xde, 0x0f, 0xe6, 0x3b, 0x6a, 0x76,
+	0x14, 0x06, 0xbf, 0xf0, 0xad, 0x38, 0x49, 0x92, 0x5b, 0x1c, 0x9f, 0xdc, 0x96, 0x3b, 0x5d, 0x91,
+	0xfa, 0x5f, 0x6b, 0xb0, 0x21, 0x4f, 0xf8, 0xec, 0x7f, 0x74, 0x56, 0xe4, 0xdc, 0xdb, 0x16, 0x59,
+	0x6f, 0xc0, 0x8d, 0x69, 0xb9, 0xa4, 0xcf, 0xff, 0xcf, 0x22, 0xa0, 0xd9, 0xdb, 0x25, 0xfa, 0x0e,
+	0x2c, 0x53, 0x32, 0xb6, 0x0d, 0x71, 0x5e, 0x88, 0xa3, 0xac, 0x8c, 0xab, 0x8c, 0x27, 0x0e, 0x0e,
+	0xca, 0x5c, 0x20, 0x39, 0x93, 0xd2, 0x96, 0x31, 0xff, 0x46, 0xc7, 0xb0, 0xfc, 0x92, 0x1a, 0xd1,
+	0xdc, 0xdc, 0xa0, 0x6a, 0x99, 0xdd, 0xda, 0xac, 0x1c, 0xad, 0xc7, 0xfd, 0xe8, 0x7f, 0xe1, 0xea,
+	0x4b, 0x1a, 0x11, 0xe8, 0xa7, 0x1a, 0xbc, 0xa3, 0xc2, 0x8a, 0x58, 0x7d, 0xae, 0x67, 0x13, 0xda,
+	0x28, 0xdc, 0xca, 0xdf, 0xae, 0x6d, 0x1e, 0x5c, 0x41, 0x7f, 0x33, 0xcc, 0x3d, 0xcf, 0x26, 0x78,
+	0x63, 0x7c, 0x01, 0x97, 0xa2, 0x16, 0x5c, 0x77, 0x27, 0x34, 0x34, 0x84, 0x15, 0x18, 0xb2, 0x53,
+	0xa3, 0xc8, 0xf5, 0xb2, 0xc6, 0x9a, 0x52, 0xb6, 0x8a, 0x4e, 0x60, 0xc5, 0xf5, 0x26, 0xe3, 0xd0,
+	0xb0, 0xf8, 0xfd, 0x87, 0x36, 0x4a, 0x73, 0x5d, 0x8c, 0x2f, 0xd0, 0xd2, 0x1e, 0x83, 0x13, 0xb7,
+	0x29, 0x8a, 0x97, 0xdd, 0x04, 0xc5, 0x16, 0x32, 0x20, 0xae, 0x17, 0x12, 0x83, 0xf9, 0x4b, 0xda,
+	0x58, 0x12, 0x0b, 0x29, 0x78, 0xcc, 0x35, 0x50, 0xf4, 0x5b, 0xb0, 0x32, 0xf2, 0x86, 0x06, 0x55,
+	0x3e, 0xa2, 0x51, 0xe6, 0x7d, 0x96, 0x47, 0xde, 0x30, 0xf2, 0x1b, 0xe8, 0xdb, 0x50, 0xe5, 0xfe,
+	0x57, 0xde, 0xe5, 0x2b, 0xbc, 0x0b, 0x30, 0x96, 0xb8, 0xdc, 0xa2, 0x6f, 0x01, 0x58, 0xc7, 0xc4,
+	0x3a, 0xf1, 0x3d, 0x67, 0x1c, 0x36, 0x40, 0xb4, 0xc7, 0x1c, 0xbd, 0x05, 0xd5, 0xc4, 0x62, 0xa2,
+	0x32, 0x14, 0x7a, 0xfb, 0xbd, 0x6e, 0xfd, 0x1a, 0x02, 0x28, 0x75, 0xb6, 0xf1, 0xfe, 0xfe, 0x40,
+	0xdc, 0x4d, 0x76, 0xf6, 0xda, 0x4f, 0xba, 0xf5, 0x9c, 0xde, 0x85, 0xe5, 0xe4, 0xdf, 0x42, 0x08,
+	0x6a, 0x87, 0xbd, 0xa7, 0xbd, 0xfd, 0x67, 0x3d, 0x63, 0x6f, 0xff, 0xb0, 0x37, 0x60, 0xb7, 0x9a,
+	0x1a, 0x40, 0xbb, 0xf7, 0x3c, 0xa6, 0x57, 0xa0, 0xd2, 0xdb, 0x57, 0xa4, 0xd6, 0xcc, 0xd5, 0x35,
+	0xfd, 0x97, 0x79, 0x58, 0xbf, 0x68, 0x85, 0x91, 0x0d, 0x05, 0x66, 0x2d, 0xf2, 0x5e, 0xf9, 0xf6,
+	0x8d, 0x85, 0xa3, 0xb3, 0x4d, 0xe2, 0x9b, 0xf2, 0x20, 0xa9, 0x60, 0xfe, 0x8d, 0x0c, 0x28, 0x8d,
+	0xcc, 0x23, 0x32, 0xa2, 0x8d, 0x3c, 0xcf, 0xbc, 0x3c, 0xb9, 0xca, 0xdc, 0xbb, 0x1c, 0x49, 0xa4,
+	0x5d, 0x24, 0x2c, 0x1a, 0x40, 0x95, 0xb9, 0x4a, 0x2a, 0x54, 0x27, 0xbd, 0xf7, 0x66, 0xc6, 0x59,
+	0xb6, 0xe3, 0x91, 0x38, 0x09, 0xd3, 0xbc, 0x07, 0xd5, 0xc4, 0x64, 0x17, 0x64, 0x4d, 0xd6, 0x93,
+	0x59, 0x93, 0x4a, 0x32, 0x05, 0xf2, 0x08, 0xd6, 0x2f, 0xd2, 0x11, 0x33, 0x82, 0xed, 0xfd, 0xfe,
+	0x40, 0xdc, 0x4f, 0x9f, 0xe0, 0xfd, 0xc3, 0x83, 0xba, 0xc6, 0x98, 0x83, 0x76, 0xff, 0x69, 0x3d,
+	0x17, 0xd9, 0x48, 0x5e, 0xef, 0x40, 0x35, 0x21, 0x57, 0xea, 0x6c, 0xd0, 0xd2, 0x67, 0x03, 0xf3,
+	0xce, 0xa6, 0x6d, 0x07, 0x84, 0x52, 0x29, 0x87, 0x22, 0xf5, 0x17, 0x50, 0xd9, 0xea, 0xf5, 0x25,
+	0x44, 0x03, 0x96, 0x28, 0x09, 0xd8, 0xff, 0xe6, 0xf9, 0xaf, 0x0a, 0x56, 0x24, 0x03, 0xa7, 0xc4,
+	0x0c, 0xac, 0x63, 0x42, 0x65, 0x44, 0x11, 0xd1, 0x6c, 0x94, 0xc7, 0xf3, 0x48, 0x62, 0xed, 0x2a,
+	0x58, 0x91, 0xfa, 0x3f, 0x97, 0x01, 0xe2, 0x9c, 0x06, 0xaa, 0x41, 0x2e, 0xf2, 0xf4, 0x39, 0xc7,
+	0x66, 0x76, 0x90, 0x38, 0xc9, 0xf8, 0x37, 0xda, 0x84, 0x0d, 0x97, 0x0e, 0x7d, 0xd3, 0x3a, 0x31,
+	0x64, 0x2a, 0x42, 0x38, 0x04, 0xee, 0x35, 0x97, 0xf1, 0x75, 0xd9, 0x28, 0xf7, 0xbb, 0xc0, 0xdd,
+	0x85, 0x3c, 0x19, 0x9f, 0x72, 0x0f, 0x57, 0xdd, 0xbc, 0x3f, 0x77, 0xae, 0xa5, 0xd5, 0x1d, 0x9f,
+	0x0a, 0x5b, 0x61, 0x30, 0xc8, 0x00, 0xb0, 0xc9, 0xa9, 0x63, 0x11, 0x83, 0x81, 0x16, 0x39, 0xe8,
+	0x67, 0xf3, 0x83, 0x6e, 0x71, 0x8c, 0x08, 0xba, 0x62, 0x2b, 0x1a, 0xf5, 0xa0, 0x12, 0x10, 0xea,
+	0x4d, 0x02, 0x8b, 0x08, 0x37, 0x97, 0xfd, 0x3a, 0x84, 0xd5, 0x38, 0x1c, 0x43, 0xa0, 0x2d, 0x28,
+	0x71, 0xef, 0xc6, 0xfc, 0x58, 0xfe, 0x57, 0x26, 0x6e, 0xd3, 0x60, 0xdc, 0x93, 0x60, 0x39, 0x16,
+	0x3d, 0x81, 0x25, 0x21, 0x22, 0x6d, 0x94, 0x39, 0xcc, 0x87, 0x59, 0x5d, 0x2f, 0x1f, 0x85, 0xd5,
+	0x68, 0xb6, 0xaa, 0x13, 0x4a, 0x02, 0xee, 0x0d, 0x2b, 0x98, 0x7f, 0xa3, 0x77, 0xa1, 0x22, 0x4e,
+	0x7a, 0xdb, 0x09, 0xb8, 0x1b, 0xac, 0x60, 0x71, 0xf4, 0x6f, 0x39, 0x01, 0xf3, 0xa2, 0x22, 0xa2,
+	0x33, 0xb8, 0x57, 0xa8, 0xf2, 0x66, 0x10, 0xac, 0x03, 0xe6, 0x1b, 0x44, 0x07, 0x12, 0x04, 0xa2,
+	0xc3, 0x72, 0xd4, 0x81, 0x04, 0x01, 0xef, 0xf0, 0xdb, 0xb0, 0xca, 0xfd, 0xf0, 0x30, 0xf0, 0x26,
+	0xbe, 0xc1, 0x6d, 0x6a, 0x85, 0x77, 0x5a, 0x61, 0xec, 0x27, 0x8c, 0xdb, 0x63, 0xc6, 0x75, 0x13,
+	0xca, 0xaf, 0xbc, 0x23, 0xd1, 0xa1, 0x26, 0xf6, 0xc1, 0x2b, 0xef, 0x48, 0x35, 0x45, 0xb1, 0xc8,
+	0x6a, 0x3a, 0x16, 0xf9, 0x0a, 0x6e, 0xcc, 0x1e, 0xaa, 0x3c, 0x26, 0xa9, 0x5f, 0x3d, 0x26, 0x59,
+	0x1f, 0x5f, 0xc0, 0x45, 0x9f, 0x43, 0xde, 0x1e, 0xd3, 0xc6, 0xda, 0x5c, 0xc6, 0x11, 0xed, 0x63,
+	0xcc, 0x06, 0xa3, 0x1e, 0x2c, 0xf9, 0x81, 0x67, 0xb1, 0x3d, 0x8f, 0x38, 0xce, 0xef, 0x64, 0xc4,
+	0x39, 0x10, 0xa3, 0x24, 0x96, 0x02, 0x69, 0x7e, 0x0c, 0x65, 0x65, 0xcd, 0xf3, 0xf8, 0xb9, 0xe6,
+	0x03, 0xa8, 0xa5, 0xf7, 0xc2, 0x5c, 0x5e, 0xf2, 0xef, 0x73, 0x50, 0x89, 0xac, 0x1e, 0x8d, 0xe1,
+	0x3a, 0x5f, 0x15, 0x33, 0x24, 0xb6, 0x11, 0x6f, 0x22, 0x11, 0xce, 0x3e, 0xcc, 0xf8, 0xff, 0xda,
+	0x0a, 0x41, 0xde, 0xab, 0xe5, 0x8e, 0x42, 0x11, 0x72, 0x3c, 0xdf, 0x97, 0xb0, 0x3a, 0x72, 0xc6,
+	0x93, 0xb3, 0xc4, 0x5c, 0x22, 0x0e, 0xfd, 0xdd, 0x8c, 0x73, 0xed, 0xb2, 0xd1, 0xf1, 0x1c, 0xb5,
+	0x51, 0x8a, 0x46, 0xdb, 0x50, 0xf4, 0xbd, 0x20, 0x54, 0x87, 0x5e, 0xd6, 0xe3, 0xe8, 0xc0, 0x0b,
+	0xc2, 0x3d, 0xd3, 0xf7, 0xd9, 0x55, 0x4b, 0x00, 0xe8, 0xdf, 0xe4, 0xe0, 0xc6, 0xc5, 0x7f, 0x0c,
+	0xf5, 0x20, 0x6f, 0xf9, 0x13, 0xa9, 0xa4, 0x07, 0xf3, 0x2a, 0xa9, 0xe3, 0x4f, 0x62, 0xf9, 0x19,
+	0x10, 0x4b, 0x3f, 0xbb, 0xc4, 0xf5, 0x82, 0x73, 0xa9, 0x8b, 0x47, 0xf3, 0x42, 0xee, 0xf1, 0xd1,
+	0x31, 0xaa, 0x84, 0x43, 0x18, 0xca, 0x72, 0x37, 0x50, 0xe9, 0x77, 0xe7, 0x4c, 0x86, 0x29, 0x48,
+	0x1c, 0xe1, 0xe8, 0x1f, 0xc3, 0xc6, 0x85, 0x7f, 0x05, 0xfd, 0x26, 0x80, 0xe5, 0x4f, 0x0c, 0xfe,
+	0x58, 0x21, 0x2c, 0x28, 0x8f, 0x2b, 0x96, 0x3f, 0xe9, 0x73, 0x86, 0xfe, 0x02, 0x1a, 0x6f, 0x92,
+	0x97, 0x79, 0x33, 0x21, 0xb1, 0xe1, 0x1e, 0x71, 0x1d, 0xe4, 0x71, 0x59, 0x30, 0xf6, 0x8e, 0x90,
+	0x0e, 0x2b, 0xaa, 0xd1, 0x3c, 0x63, 0x1d, 0xf2, 0xbc, 0x43, 0x55, 0x76, 0x30, 0xcf, 0xf6, 0x8e,
+	0xf4, 0xbf, 0xc9, 0xc1, 0xea, 0x94, 0xc8, 0xec, 0xc2, 0x29, 0x3c, 0xa8, 0xba, 0xca, 0x0b, 0x8a,
+	0xb9, 0x53, 0xcb, 0xb1, 0x55, 0x12, 0x98, 0x7f, 0xf3, 0x83, 0xd4, 0x97, 0x09, 0xda, 0x9c, 0xe3,
+	0xb3, 0xed, 0xe3, 0x1e, 0x39, 0x21, 0xe5, 0x51, 0x4d, 0x11, 0x0b, 0x02, 0x3d, 0x87, 0x5a, 0x40,
+	0xf8, 0x01, 0x6e, 0x1b, 0xc2, 0xca, 0x8a, 0x73, 0x59, 0x99, 0x94, 0x90, 0x19, 0x1b, 0x5e, 0x51,
+	0x48, 0x8c, 0xa2, 0xe8, 0x19, 0xac, 0xd8, 0xe7, 0x63, 0xd3, 0x75, 0x2c, 0x89, 0x5c, 0x5a, 0x18,
+	0x79, 0x59, 0x02, 0x71, 0x60, 0xf6, 0x2e, 0x94, 0x68, 0x64, 0x7f, 0x8c, 0x87, 0x6f, 0x52, 0x27,
+	0x82, 0x48, 0x7b, 0x8b, 0xa2, 0xf4, 0x16, 0xfa, 0x11, 0x54, 0x13, 0xfb, 0x62, 0x9e, 0xa1, 0x4c,
+	0x9f, 0xa1, 0xc7, 0xf5, 0x59, 0xc4, 0xb9, 0xd0, 0x63, 0x79, 0x15, 0x16, 0x3a, 0x19, 0x8e, 0xcf,
+	0x35, 0x5a, 0xc1, 0x25, 0x46, 0xee, 0xf8, 0xfa, 0xcf, 0x73, 0x50, 0x4b, 0x6f, 0x69, 0x65, 0x47,
+	0x3e, 0x09, 0x1c, 0xcf, 0x4e, 0xd8, 0xd1, 0x01, 0x67, 0x30, 0x5b, 0x61, 0xcd, 0x5f, 0x4d, 0xbc,
+	0xd0, 0x54, 0xb6, 0x62, 0xf9, 0x93, 0xdf, 0x63, 0xf4, 0x94, 0x0d, 0xe6, 0xa7, 0x6c, 0x10, 0x7d,
+	0x00, 0x48, 0x9a, 0xd2, 0xc8, 0x71, 0x9d, 0xd0, 0x38, 0x3a, 0x0f, 0x89, 0x58, 0xe3, 0x3c, 0xae,
+	0x8b, 0x96, 0x5d, 0xd6, 0xf0, 0x39, 0xe3, 0x33, 0xc3, 0xf3, 0x3c, 0xd7, 0xa0, 0x96, 0x17, 0x10,
+	0xc3, 0xb4, 0x5f, 0xf1, 0xbb, 0x56, 0x1e, 0x57, 0x3d, 0xcf, 0xed, 0x33, 0x5e, 0xdb, 0x7e, 0xc5,
+	0x4e, 0x52, 0xcb, 0x9f, 0x50, 0x12, 0x1a, 0xec, 0x87, 0x07, 0x1f, 0x15, 0x0c, 0x82, 0xd5, 0xf1,
+	0x27, 0xfc, 0xda, 0xa3, 0x3a, 0xf0, 0xc3, 0x54, 0x9e, 0xe2, 0xcb, 0xb2, 0x0b, 0xe7, 0x21, 0x1d,
+	0x96, 0x0f, 0x48, 0x60, 0x91, 0x71, 0x38, 0x70, 0xac, 0x13, 0xca, 0xaf, 0x46, 0x1a, 0x4e, 0xf1,
+	0xbe, 0x28, 0x94, 0x97, 0xea, 0x65, 0xac, 0x66, 0x73, 0x89, 0x4b, 0xf5, 0x9f, 0x69, 0x50, 0xe4,
+	0x31, 0x07, 0x53, 0x0a, 0x3f, 0xaf, 0xf9, 0x71, 0x2e, 0x63, 0x55, 0xc6, 0xe0, 0x87, 0xf9, 0xbb,
+	0x50, 0xe1, 0xca, 0x4f, 0x5c, 0x11, 0x78, 0x20, 0xcb, 0x1b, 0x9b, 0x50, 0x0e, 0x88, 0x69, 0x7b,
+	0xe3, 0x91, 0xca, 0x61, 0x45, 0x34, 0xdb, 0x29, 0xe1, 0xb9, 0x4f, 0xe4, 0x92, 0xf1, 0x6f, 0xa6,
+	0xe1, 0xd0, 0xf5, 0x5f, 0x52, 0x91, 0xf0, 0x13, 0x1a, 0xa9, 0x70, 0x0e, 0xcb, 0x75, 0xe9, 0x5f,
+	0x41, 0x49, 0x9c, 0x4d, 0x57, 0x10, 0xe9, 0x43, 0x40, 0x42, 0x57, 0xcc, 0x06, 0x5c, 0x87, 0x52,
+	0x19, 0x09, 0xf3, 0xb7, 0x56, 0xd1, 0x72, 0x10, 0x37, 0xe8, 0xff, 0xa1, 0x01, 0xc4, 0xaf, 0x60,
+	0x2c, 0x78, 0x66, 0x1b, 0x83, 0xe5, 0x05, 0x44, 0xba, 0x4d, 0x91, 0x2c, 0xd3, 0x24, 0x43, 0xdf,
+	0xdc, 0xa2, 0x8f, 0x88, 0x12, 0x40, 0x25, 0xdf, 0x89, 0x4c, 0x3d, 0xcc, 0x9b, 0x7c, 0x27, 0x22,
+	0xf9, 0x4e, 0xd8, 0xbd, 0x59, 0x06, 0xe5, 0x02, 0xae, 0xc0, 0x63, 0xf2, 0xaa, 0x1d, 0xbd, 0x70,
+	0x10, 0xfd, 0xbf, 0xb5, 0xc8, 0xb5, 0xa9, 0x97, 0x08, 0xf4, 0x25, 0x94, 0x99, 0x97, 0x30, 0x5c,
+	0xd3, 0x97, 0xef, 0xea, 0x9d, 0xc5, 0x1e, 0x39, 0xd4, 0xc1, 0x27, 0x42, 0xea, 0x25, 0x5f, 0x50,
+	0x6c, 0xe1, 0xd9, 0x75, 0x46, 0xb9, 0x48, 0xf6, 0x8d, 0xde, 0x83, 0x9a, 0x39, 0x09, 0x3d, 0xc3,
+	0xb4, 0x4f, 0x49, 0x10, 0x3a, 0x94, 0x48, 0x73, 0x59, 0x61, 0xdc, 0xb6, 0x62, 0x36, 0xef, 0xc3,
+	0x72, 0x12, 0xf3, 0xb2, 0xd0, 0xa4, 0x98, 0x0c, 0x4d, 0xfe, 0x08, 0x20, 0xce, 0xea, 0x31, 0x1b,
+	0x61, 0x29, 0x42, 0xc3, 0x52, 0xf7, 0xe7, 0x22, 0x2e, 0x33, 0x46, 0x87, 0xdd, 0xe9, 0xd2, 0x4f,
+	0x0e, 0x45, 0xf5, 0xe4, 0xc0, 0xcc, 0x93, 0xed, 0xd9, 0x13, 0x67, 0x34, 0x8a, 0x32, 0x8d, 0x15,
+	0xcf, 0x73, 0x9f, 0x72, 0x86, 0xfe, 0x8b, 0x9c, 0xb0, 0x15, 0xf1, 0x78, 0x94, 0xe9, 0xfe, 0xf4,
+	0xb6, 0x96, 0xfa, 0x1e, 0x00, 0x0d, 0xcd, 0x80, 0xc5, 0x59, 0xa6, 0xca, 0x75, 0x36, 0x67, 0xde,
+	0x2c, 0x06, 0xaa, 0x9a, 0x05, 0x57, 0x64, 0xef, 0x76, 0x88, 0x1e, 0xc2, 0xb2, 0xe5, 0xb9, 0xfe,
+	0x88, 0xc8, 0xc1, 0xc5, 0x4b, 0x07, 0x57, 0xa3, 0xfe, 0xed, 0x30, 0x91, 0x61, 0x2d, 0x5d, 0x35,
+	0xc3, 0xfa, 0x73, 0x4d, 0xbc, 0x81, 0x25, 0x9f, 0xe0, 0xd0, 0xf0, 0x82, 0x3a, 0x8f, 0x27, 0x0b,
+	0xbe, 0xe7, 0xfd, 0xaa, 0x22, 0x8f, 0xe6, 0xc3, 0x2c, 0x55, 0x15, 0x6f, 0x8e, 0x7c, 0xff, 0x29,
+	0x0f, 0x15, 0xb5, 0x2c, 0xb3, 0x6b, 0xff, 0x09, 0x54, 0xa2, 0x52, 0xa2, 0x46, 0xee, 0x52, 0x0d,
+	0xc7, 0x9d, 0xd1, 0x4b, 0x40, 0xe6, 0x70, 0x18, 0x45, 0xb4, 0xc6, 0x84, 0x9a, 0x43, 0xf5, 0xf8,
+	0xf8, 0xc9, 0x1c, 0x7a, 0x50, 0x47, 0xe0, 0x21, 0x1b, 0x8f, 0xeb, 0xe6, 0x70, 0x98, 0xe2, 0xa0,
+	0x3f, 0x86, 0x8d, 0xf4, 0x1c, 0xc6, 0xd1, 0xb9, 0xe1, 0x3b, 0xb6, 0xbc, 0xa7, 0x6f, 0xcf, 0xfb,
+	0x02, 0xd8, 0x4a, 0xc1, 0x7f, 0x7e, 0x7e, 0xe0, 0xd8, 0x42, 0xe7, 0x28, 0x98, 0x69, 0x68, 0xfe,
+	0x18, 0xde, 0x79, 0x43, 0xf7, 0x0b, 0xd6, 0xa0, 0x97, 0xae, 0x6c, 0x59, 0x5c, 0x09, 0x89, 0xd5,
+	0xfb, 0x77, 0x0d, 0xd6, 0x66, 0x3a, 0xa0, 0x76, 0x32, 0x14, 0xbf, 0x93, 0x71, 0x9e, 0xce, 0xc1,
+	0xa1, 0x80, 0x67, 0x63, 0xd1, 0x17, 0x53, 0xd1, 0x77, 0xd6, 0x98, 0x4b, 0x04, 0xb1, 0x02, 0x48,
+	0x05, 0xdc, 0x2c, 0x11, 0xc7, 0x56, 0x44, 0x44, 0x1e, 0xfc, 0x3b, 0xf9, 0x74, 0x24, 0x0e, 0x52,
+	0x45, 0xea, 0xff, 0x90, 0x87, 0xb2, 0x92, 0x85, 0xdf, 0xc9, 0xcf, 0x69, 0x48, 0x5c, 0x23, 0x4a,
+	0x18, 0x6a, 0x18, 0x04, 0x8b, 0xa7, 0xb1, 0xde, 0x85, 0x0a, 0xbb, 0xfa, 0x8b, 0xe6, 0x1c, 0x6f,
+	0x2e, 0x33, 0x06, 0x6f, 0x64, 0x89, 0x53, 0x2f, 0x34, 0x47, 0x46, 0xc8, 0x03, 0x88, 0xbc, 0x18,
+	0xcd, 0x59, 0x3c, 0x7c, 0x40, 0xdf, 0x85, 0xb5, 0xf0, 0x38, 0xf0, 0xc2, 0x70, 0xc4, 0x82, 0x57,
+	0x1e, 0x4a, 0x89, 0xc8, 0xa7, 0x80, 0xeb, 0x51, 0x83, 0x08, 0xb1, 0x28, 0xf3, 0xf5, 0x71, 0x67,
+	0x66, 0xe8, 0xdc, 0xe5, 0x14, 0xf0, 0x4a, 0xc4, 0x65, 0x1b, 0x81, 0xfd, 0x33, 0x5f, 0x84, 0x28,
+	0xdc, 0xb3, 0x68, 0x58, 0x91, 0xc8, 0x80, 0x55, 0x97, 0x98, 0x74, 0x12, 0x10, 0xdb, 0x78, 0xe9,
+	0x90, 0x91, 0x2d, 0x52, 0x29, 0xb5, 0xcc, 0xf7, 0x0f, 0xa5, 0x96, 0xd6, 0x63, 0x3e, 0x1a, 0xd7,
+	0x14, 0x9c, 0xa0, 0x59, 0x9c, 0x21, 0xbe, 0xd0, 0x2a, 0x54, 0xfb, 0xcf, 0xfb, 0x83, 0xee, 0x9e,
+	0xb1, 0xb7, 0xbf, 0xd5, 0x95, 0xa5, 0x4e, 0xfd, 0x2e, 0x16, 0xa4, 0xc6, 0xda, 0x07, 0xfb, 0x83,
+	0xf6, 0xae, 0x31, 0xd8, 0xe9, 0x3c, 0xed, 0xd7, 0x73, 0x68, 0x03, 0xd6, 0x06, 0xdb, 0x78, 0x7f,
+	0x30, 0xd8, 0xed, 0x6e, 0x19, 0x07, 0x5d, 0xbc, 0xb3, 0xbf, 0xd5, 0xaf, 0xe7, 0x59, 0xe6, 0x37,
+	0x66, 0x0f, 0x76, 0xf6, 0xba, 0xf5, 0x02, 0x2b, 0x6e, 0x39, 0xe8, 0xe2, 0x4e, 0xb7, 0x37, 0xa8,
+	0x17, 0xf5, 0x7f, 0xcb, 0x43, 0x35, 0xb1, 0xe6, 0xcc, 0xec, 0x03, 0x2a, 0x2e, 0x3a, 0x05, 0xcc,
+	0x3e, 0xf9, 0xd3, 0xac, 0x69, 0x1d, 0x8b, 0xd5, 0x29, 0x60, 0x41, 0xf0, 0xcb, 0x8d, 0x79, 0x96,
+	0xf0, 0x0a, 0x05, 0x5c, 0x76, 0xcd, 0x33, 0x01, 0xf2, 0x1d, 0x58, 0x3e, 0x21, 0xc1, 0x98, 0x8c,
+	0x64, 0xbb, 0x58, 0x91, 0xaa, 0xe0, 0x89, 0x2e, 0xb7, 0xa1, 0x2e, 0xbb, 0xc4, 0x30, 0x62, 0x39,
+	0x6a, 0x82, 0xbf, 0xa7, 0xc0, 0xd6, 0xa1, 0x28, 0x9a, 0x97, 0xc4, 0xfc, 0x9c, 0x60, 0x36, 0x49,
+	0x5f, 0x9b, 0x3e, 0x0f, 0x2a, 0x0b, 0x98, 0x7f, 0xa3, 0xa3, 0xd9, 0xf5, 0x29, 0xf1, 0xf5, 0xb9,
+	0x37, 0xbf, 0xf1, 0xbf, 0x61, 0x89, 0xf8, 0x7d, 0x81, 0x05, 0xd3, 0x3c, 0xe2, 0x2d, 0x60, 0x41,
+	0xa0, 0x5b, 0x50, 0x15, 0x37, 0x1f, 0xf1, 0x74, 0x03, 0xe2, 0xff, 0x26, 0x58, 0xfa, 0x71, 0xb4,
+	0xb4, 0x4b, 0x90, 0xc7, 0xaa, 0xae, 0xa8, 0xd3, 0xee, 0x6c, 0xb3, 0xe5, 0x5c, 0x81, 0xca, 0x5e,
+	0xfb, 0x87, 0xc6, 0x61, 0x9f, 0xe7, 0xef, 0x51, 0x1d, 0x96, 0x9f, 0x76, 0x71, 0xaf, 0xbb, 0x2b,
+	0x39, 0x79, 0xb4, 0x0e, 0x75, 0xc9, 0x89, 0xfb, 0x15, 0x18, 0x82, 0xf8, 0x2c, 0xb2, 0x7c, 0x6f,
+	0xff, 0x59, 0xfb, 0xa0, 0x5e, 0xd2, 0xff, 0x2b, 0x07, 0xab, 0xe2, 0xf0, 0x89, 0x2a, 0x20, 0xde,
+	0xfc, 0x02, 0x9c, 0xcc, 0x67, 0xe5, 0xd2, 0xf9, 0x2c, 0x15, 0xea, 0xf2, 0xd8, 0x21, 0x1f, 0x87,
+	0xba, 0x3c, 0x0f, 0x96, 0x3a, 0x57, 0x0a, 0xf3, 0x9c, 0x2b, 0x0d, 0x58, 0x72, 0x09, 0x8d, 0xd6,
+	0xbb, 0x82, 0x15, 0x89, 0x1c, 0xa8, 0x9a, 0xe3, 0xb1, 0x17, 0x9a, 0x22, 0x49, 0x5c, 0x9a, 0xeb,
+	0xc8, 0x9d, 0xfa, 0xc7, 0xad, 0x76, 0x8c, 0x24, 0xdc, 0x7f, 0x12, 0xbb, 0xf9, 0x29, 0xd4, 0xa7,
+	0x3b, 0xcc, 0x75, 0xe8, 0xfe, 0xaf, 0x06, 0x2b, 0xa9, 0xfc, 0x17, 0xb7, 0x52, 0x57, 0x95, 0x10,
+	0x55, 0xb0, 0x20, 0x78, 0xe8, 0xe5, 0x58, 0x2a, 0x28, 0xe4, 0xdf, 0x6c, 0x73, 0x38, 0x1e, 0xfb,
+	0x32, 0xac, 0x91, 0x49, 0xd5, 0x15, 0xa0, 0x2a, 0x78, 0x1d, 0xc6, 0x42, 0x2f, 0x60, 0x29, 0xe0,
+	0x86, 0x45, 0xe5, 0x29, 0xd8, 0x5e, 0x24, 0x27, 0xd7, 0xc2, 0x02, 0x43, 0x86, 0xc1, 0x12, 0x91,
+	0xc5, 0xb2, 0xc9, 0x86, 0xcb, 0xfe, 0x77, 0x21, 0xf9, 0xbf, 0xdf, 0x87, 0x55, 0xa6, 0xe2, 0x5d,
+	0x6f, 0x78, 0x69, 0xad, 0x90, 0xfe, 0x29, 0xd4, 0xe3, 0xbe, 0xc9, 0xaa, 0x94, 0x80, 0x98, 0xae,
+	0xea, 0x2b, 0xa8, 0xa8, 0x24, 0x24, 0x17, 0x97, 0x84, 0xe8, 0x1f, 0x88, 0x93, 0x51, 0x3c, 0x91,
+	0x5d, 0x3a, 0xdb, 0xdf, 0x69, 0x80, 0x92, 0xdd, 0xe5, 0x84, 0x71, 0x05, 0xad, 0xf6, 0x6b, 0xac,
+	0xa0, 0xcd, 0xbd, 0xa1, 0x82, 0x56, 0x7f, 0x06, 0x1b, 0x9d, 0xe8, 0x59, 0x2f, 0x53, 0xad, 0xd0,
+	0x7b, 0x50, 0x8b, 0x1f, 0x02, 0x79, 0x5e, 0x5c, 0x80, 0xaf, 0xc4, 0xdc, 0x2d, 0x27, 0x60, 0x8f,
+	0xd4, 0xd3, 0xc0, 0xf2, 0x91, 0xfa, 0x6b, 0x8d, 0x15, 0xc2, 0xd2, 0xd0, 0x0b, 0xc8, 0xdb, 0xaf,
+	0x3c, 0xcd, 0x2a, 0xde, 0x2f, 0x35, 0xb8, 0x9e, 0x12, 0x22, 0x2e, 0x82, 0x94, 0xf5, 0xa1, 0xda,
+	0xff, 0x47, 0x7d, 0x68, 0xee, 0xad, 0x96, 0xca, 0xbd, 0xff, 0xbd, 0x38, 0xd2, 0x26, 0xec, 0x14,
+	0x95, 0x6f, 0xaa, 0xf5, 0x6b, 0x8c, 0xc0, 0x87, 0xbd, 0xde, 0x4e, 0xef, 0x49, 0x5d, 0x63, 0x8f,
+	0xb2, 0xdd, 0x1f, 0xee, 0xb0, 0x0a, 0xe5, 0xdc, 0xe6, 0xbf, 0x6c, 0x40, 0x49, 0xb8, 0x26, 0xf4,
+	0x8d, 0xbc, 0x65, 0x24, 0x6b, 0xea, 0xd1, 0xa7, 0x73, 0x2b, 0x3e, 0x55, 0xa7, 0xdf, 0x7c, 0xb4,
+	0xf0, 0x78, 0x69, 0x1e, 0xd7, 0xd0, 0x9f, 0x6b, 0xb0, 0x9c, 0xaa, 0x5f, 0xc8, 0xfa, 0x34, 0x76,
+	0x41, 0x09, 0x7f, 0xf3, 0x07, 0x0b, 0x8d, 0x8d, 0x64, 0xf9, 0xa9, 0x06, 0xd5, 0xc4, 0xd6, 0x43,
+	0xf7, 0x16, 0xd9, 0xae, 0x42, 0x92, 0xfb, 0x8b, 0xef, 0x74, 0xfd, 0xda, 0x47, 0x1a, 0xfa, 0x33,
+	0x0d, 0xaa, 0x89, 0x32, 0xee, 0xcc, 0xa2, 0xcc, 0x16, 0x9d, 0x37, 0xef, 0x2f, 0x32, 0x34, 0xd2,
+	0xc9, 0x9f, 0x6a, 0x50, 0x89, 0x4a, 0xb2, 0xd1, 0xdd, 0xf9, 0x8b, 0xb8, 0x85, 0x10, 0x9f, 0x2c,
+	0x5a, 0xfd, 0xad, 0x5f, 0x43, 0x7f, 0x02, 0x65, 0x55, 0xbf, 0x8c, 0xb2, 0xee, 0xa6, 0xa9, 0xe2,
+	0xe8, 0xe6, 0xdd, 0xb9, 0xc7, 0x25, 0xa7, 0x57, 0x45, 0xc5, 0x99, 0xa7, 0x9f, 0x2a, 0x7f, 0x6e,
+	0xde, 0x9d, 0x7b, 0x5c, 0x34, 0x3d, 0xb3, 0x84, 0x44, 0xed, 0x71, 0x66, 0x4b, 0x98, 0x2d, 0x7a,
+	0x6e, 0xde, 0x5f, 0x64, 0x68, 0x4a, 0x90, 0x44, 0xf5, 0x72, 0x66, 0x41, 0x66, 0x2b, 0xa4, 0x9b,
+	0xf7, 0x17, 0x19, 0x1a, 0x09, 0xf2, 0xb5, 0x96, 0xcc, 0x39, 0xdc, 0x9d, 0xbb, 0x48, 0x77, 0x4e,
+	0x93, 0x9c, 0x29, 0x13, 0xe6, 0x1b, 0xf4, 0x6b, 0x99, 0x21, 0x15, 0x35, 0xbe, 0x68, 0x1e, 0xb0,
+	0x54, 0x59, 0x70, 0xf3, 0xe3, 0xc5, 0x42, 0x4c, 0x2e, 0xc4, 0x4f, 0x34, 0x80, 0xb8, 0x1a, 0x38,
+	0xb3, 0x10, 0x33, 0x65, 0xc8, 0xcd, 0x7b, 0x0b, 0x8c, 0x4c, 0x6e, 0x10, 0x55, 0xad, 0x98, 0x79,
+	0x83, 0x4c, 0x55, 0x2b, 0x37, 0xef, 0xce, 0x3d, 0x2e, 0x9a, 0xfe, 0x6f, 0x35, 0x58, 0x9b, 0xa9,
+	0x96, 0x44, 0x8f, 0xae, 0x58, 0x30, 0xdb, 0xfc, 0x6c, 0x71, 0x00, 0x25, 0xda, 0x6d, 0xed, 0x23,
+	0x0d, 0xfd, 0x85, 0x06, 0x2b, 0xe9, 0x2a, 0xb2, 0xcc, 0xa7, 0xd4, 0x05, 0x75, 0x97, 0xcd, 0x07,
+	0x8b, 0x0d, 0x8e, 0xb4, 0xf5, 0x57, 0x1a, 0xd4, 0xe4, 0xfe, 0x56, 0xf2, 0x3c, 0x98, 0xcf, 0x2d,
+	0x4c, 0x09, 0xf4, 0x70, 0xc1, 0xd1, 0x91, 0x44, 0x3f, 0x86, 0xb2, 0x8a, 0xd4, 0x33, 0x9b, 0xcf,
+	0xd4, 0x35, 0xa0, 0x79, 0x77, 0xee, 0x71, 0x89, 0xad, 0xfc, 0x13, 0xf5, 0xd8, 0x21, 0x82, 0xea,
+	0x79, 0xb6, 0x72, 0xea, 0x7a, 0xd0, 0xbc, 0xb7, 0xc0, 0xc8, 0xd4, 0xc2, 0xa4, 0x83, 0xe8, 0xcc,
+	0x0b, 0x73, 0x61, 0x50, 0xdf, 0x7c, 0xb8, 0xe0, 0xe8, 0x94, 0xc3, 0x4f, 0x84, 0xcd, 0x73, 0xc4,
+	0x20, 0xd3, 0xf1, 0x7e, 0xf3, 0xfe, 0x22, 0x43, 0x95, 0x20, 0x9f, 0x2f, 0xfd, 0x7e, 0x51, 0xdc,
+	0xea, 0x4b, 0xfc, 0xe7, 0xfb, 0xff, 0x37, 0x00, 0xd4, 0x17, 0x98, 0xc9, 0x1c, 0x3b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3986,6 +4542,21 @@ type DriverClient interface {
 	// DestroyNetwork destroys a previously created network. This rpc is only
 	// implemented if the driver needs to manage network namespace creation.
 	DestroyNetwork(ctx context.Context, in *DestroyNetworkRequest, opts ...grpc.CallOption) (*DestroyNetworkResponse, error)
+	// TaskLogs streams the stdout and stderr of the task. This rpc is only
+	// implemented if the driver sets the log_streaming capability.
+	TaskLogs(ctx context.Context, in *TaskLogsRequest, opts ...grpc.CallOption) (Driver_TaskLogsClient, error)
+	// TaskHealth returns the health of the task as reported by the driver or
+	// its runtime. This rpc is only implemented if the driver sets the
+	// task_health capability.
+	TaskHealth(ctx context.Context, in *TaskHealthRequest, opts ...grpc.CallOption) (*TaskHealthResponse, error)
+	// CheckpointTask checkpoints the state of a running task to the given
+	// directory, stopping the task. This rpc is only implemented if the
+	// driver sets the checkpoint capability.
+	CheckpointTask(ctx context.Context, in *CheckpointTaskRequest, opts ...grpc.CallOption) (*CheckpointTaskResponse, error)
+	// RestoreTask starts a task from a checkpoint created by CheckpointTask.
+	// This rpc is only implemented if the driver sets the checkpoint
+	// capability.
+	RestoreTask(ctx context.Context, in *RestoreTaskRequest, opts ...grpc.CallOption) (*RestoreTaskResponse, error)
 }
 
 type driverClient struct {
@@ -4231,6 +4802,65 @@ func (c *driverClient) DestroyNetwork(ctx context.Context, in *DestroyNetworkReq
 	return out, nil
 }
 
+func (c *driverClient) TaskLogs(ctx context.Context, in *TaskLogsRequest, opts ...grpc.CallOption) (Driver_TaskLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Driver_serviceDesc.Streams[4], "/hashicorp.nomad.plugins.drivers.proto.Driver/TaskLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &driverTaskLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Driver_TaskLogsClient interface {
+	Recv() (*TaskLogsResponse, error)
+	grpc.ClientStream
+}
+
+type driverTaskLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *driverTaskLogsClient) Recv() (*TaskLogsResponse, error) {
+	m := new(TaskLogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *driverClient) TaskHealth(ctx context.Context, in *TaskHealthRequest, opts ...grpc.CallOption) (*TaskHealthResponse, error) {
+	out := new(TaskHealthResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/TaskHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) CheckpointTask(ctx context.Context, in *CheckpointTaskRequest, opts ...grpc.CallOption) (*CheckpointTaskResponse, error) {
+	out := new(CheckpointTaskResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/CheckpointTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) RestoreTask(ctx context.Context, in *RestoreTaskRequest, opts ...grpc.CallOption) (*RestoreTaskResponse, error) {
+	out := new(RestoreTaskResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/RestoreTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DriverServer is the server API for Driver service.
 type DriverServer interface {
 	// TaskConfigSchema returns the schema for parsing the driver
@@ -4286,6 +4916,21 @@ type DriverServer interface {
 	// DestroyNetwork destroys a previously created network. This rpc is only
 	// implemented if the driver needs to manage network namespace creation.
 	DestroyNetwork(context.Context, *DestroyNetworkRequest) (*DestroyNetworkResponse, error)
+	// TaskLogs streams the stdout and stderr of the task. This rpc is only
+	// implemented if the driver sets the log_streaming capability.
+	TaskLogs(*TaskLogsRequest, Driver_TaskLogsServer) error
+	// TaskHealth returns the health of the task as reported by the driver or
+	// its runtime. This rpc is only implemented if the driver sets the
+	// task_health capability.
+	TaskHealth(context.Context, *TaskHealthRequest) (*TaskHealthResponse, error)
+	// CheckpointTask checkpoints the state of a running task to the given
+	// directory, stopping the task. This rpc is only implemented if the
+	// driver sets the checkpoint capability.
+	CheckpointTask(context.Context, *CheckpointTaskRequest) (*CheckpointTaskResponse, error)
+	// RestoreTask starts a task from a checkpoint created by CheckpointTask.
+	// This rpc is only implemented if the driver sets the checkpoint
+	// capability.
+	RestoreTask(context.Context, *RestoreTaskRequest) (*RestoreTaskResponse, error)
 }
 
 // UnimplementedDriverServer can be embedded to have forward compatible implementations.
@@ -4340,6 +4985,18 @@ func (*UnimplementedDriverServer) CreateNetwork(ctx context.Context, req *Create
 func (*UnimplementedDriverServer) DestroyNetwork(ctx context.Context, req *DestroyNetworkRequest) (*DestroyNetworkResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DestroyNetwork not implemented")
 }
+func (*UnimplementedDriverServer) TaskLogs(req *TaskLogsRequest, srv Driver_TaskLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method TaskLogs not implemented")
+}
+func (*UnimplementedDriverServer) TaskHealth(ctx context.Context, req *TaskHealthRequest) (*TaskHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TaskHealth not implemented")
+}
+func (*UnimplementedDriverServer) CheckpointTask(ctx context.Context, req *CheckpointTaskRequest) (*CheckpointTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckpointTask not implemented")
+}
+func (*UnimplementedDriverServer) RestoreTask(ctx context.Context, req *RestoreTaskRequest) (*RestoreTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreTask not implemented")
+}
 
 func RegisterDriverServer(s *grpc.Server, srv DriverServer) {
 	s.RegisterService(&_Driver_serviceDesc, srv)
@@ -4650,6 +5307,81 @@ func _Driver_DestroyNetwork_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Driver_TaskLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TaskLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DriverServer).TaskLogs(m, &driverTaskLogsServer{stream})
+}
+
+type Driver_TaskLogsServer interface {
+	Send(*TaskLogsResponse) error
+	grpc.ServerStream
+}
+
+type driverTaskLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *driverTaskLogsServer) Send(m *TaskLogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Driver_TaskHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).TaskHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.drivers.proto.Driver/TaskHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).TaskHealth(ctx, req.(*TaskHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_CheckpointTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckpointTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).CheckpointTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.drivers.proto.Driver/CheckpointTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).CheckpointTask(ctx, req.(*CheckpointTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_RestoreTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).RestoreTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.drivers.proto.Driver/RestoreTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).RestoreTask(ctx, req.(*RestoreTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Driver_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hashicorp.nomad.plugins.drivers.proto.Driver",
 	HandlerType: (*DriverServer)(nil),
@@ -4702,6 +5434,18 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DestroyNetwork",
 			Handler:    _Driver_DestroyNetwork_Handler,
 		},
+		{
+			MethodName: "TaskHealth",
+			Handler:    _Driver_TaskHealth_Handler,
+		},
+		{
+			MethodName: "CheckpointTask",
+			Handler:    _Driver_CheckpointTask_Handler,
+		},
+		{
+			MethodName: "RestoreTask",
+			Handler:    _Driver_RestoreTask_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -4725,6 +5469,11 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "TaskLogs",
+			Handler:       _Driver_TaskLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "plugins/drivers/proto/driver.proto",
 }
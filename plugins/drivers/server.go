@@ -3,7 +3,6 @@ package drivers
 import (
 	"fmt"
 	"io"
-	"math"
 
 	"github.com/golang/protobuf/ptypes"
 	plugin "github.com/hashicorp/go-plugin"
@@ -45,6 +44,9 @@ func (b *driverPluginServer) Capabilities(ctx context.Context, req *proto.Capabi
 			MustCreateNetwork:     caps.MustInitiateNetwork,
 			NetworkIsolationModes: []proto.NetworkIsolationSpec_NetworkIsolationMode{},
 			RemoteTasks:           caps.RemoteTasks,
+			LogStreaming:          caps.LogStreaming,
+			TaskHealth:            caps.TaskHealth,
+			Checkpoint:            caps.Checkpoint,
 		},
 	}
 
@@ -106,34 +108,67 @@ func (b *driverPluginServer) RecoverTask(ctx context.Context, req *proto.Recover
 func (b *driverPluginServer) StartTask(ctx context.Context, req *proto.StartTaskRequest) (*proto.StartTaskResponse, error) {
 	handle, net, err := b.impl.StartTask(taskConfigFromProto(req.Task))
 	if err != nil {
-		if rec, ok := err.(structs.Recoverable); ok {
-			st := status.New(codes.FailedPrecondition, rec.Error())
-			st, err := st.WithDetails(&sproto.RecoverableError{Recoverable: rec.IsRecoverable()})
-			if err != nil {
-				// If this error, it will always error
-				panic(err)
-			}
-			return nil, st.Err()
-		}
+		return nil, startTaskErr(err)
+	}
+
+	pbNet, err := driverNetworkToProto(net)
+	if err != nil {
 		return nil, err
 	}
 
-	var pbNet *proto.NetworkOverride
-	if net != nil {
-		pbNet = &proto.NetworkOverride{
-			PortMap:       map[string]int32{},
-			Addr:          net.IP,
-			AutoAdvertise: net.AutoAdvertise,
-		}
-		for k, v := range net.PortMap {
-			if v > math.MaxInt32 {
-				return nil, fmt.Errorf("port map out of bounds")
-			}
-			pbNet.PortMap[k] = int32(v)
+	resp := &proto.StartTaskResponse{
+		Handle:          taskHandleToProto(handle),
+		NetworkOverride: pbNet,
+	}
+
+	return resp, nil
+}
+
+// startTaskErr attaches whether errors from starting a task are recoverable
+// to the returned status.
+func startTaskErr(err error) error {
+	if rec, ok := err.(structs.Recoverable); ok {
+		st := status.New(codes.FailedPrecondition, rec.Error())
+		st, err := st.WithDetails(&sproto.RecoverableError{Recoverable: rec.IsRecoverable()})
+		if err != nil {
+			// If this error, it will always error
+			panic(err)
 		}
+		return st.Err()
+	}
+	return err
+}
+
+func (b *driverPluginServer) CheckpointTask(ctx context.Context, req *proto.CheckpointTaskRequest) (*proto.CheckpointTaskResponse, error) {
+	d, ok := b.impl.(CheckpointDriver)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "driver does not support checkpointing tasks")
 	}
 
-	resp := &proto.StartTaskResponse{
+	if err := d.CheckpointTask(req.TaskId, req.CheckpointDir); err != nil {
+		return nil, err
+	}
+
+	return &proto.CheckpointTaskResponse{}, nil
+}
+
+func (b *driverPluginServer) RestoreTask(ctx context.Context, req *proto.RestoreTaskRequest) (*proto.RestoreTaskResponse, error) {
+	d, ok := b.impl.(CheckpointDriver)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "driver does not support restoring tasks")
+	}
+
+	handle, net, err := d.RestoreTask(taskConfigFromProto(req.Task), req.CheckpointDir)
+	if err != nil {
+		return nil, startTaskErr(err)
+	}
+
+	pbNet, err := driverNetworkToProto(net)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.RestoreTaskResponse{
 		Handle:          taskHandleToProto(handle),
 		NetworkOverride: pbNet,
 	}
@@ -269,6 +304,49 @@ func (b *driverPluginServer) TaskStats(req *proto.TaskStatsRequest, srv proto.Dr
 	return nil
 }
 
+func (b *driverPluginServer) TaskLogs(req *proto.TaskLogsRequest, srv proto.Driver_TaskLogsServer) error {
+	d, ok := b.impl.(LogStreamingDriver)
+	if !ok {
+		return status.Error(codes.Unimplemented, "driver does not support log streaming")
+	}
+
+	ch, err := d.TaskLogs(srv.Context(), req.TaskId)
+	if err != nil {
+		return err
+	}
+
+	for frame := range ch {
+		resp := &proto.TaskLogsResponse{
+			Stream: frame.Stream,
+			Data:   frame.Data,
+		}
+		if err := srv.Send(resp); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *driverPluginServer) TaskHealth(ctx context.Context, req *proto.TaskHealthRequest) (*proto.TaskHealthResponse, error) {
+	d, ok := b.impl.(TaskHealthDriver)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "driver does not support task health")
+	}
+
+	health, err := d.TaskHealth(ctx, req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.TaskHealthResponse{
+		Health:            healthStateToProto(health.Health),
+		HealthDescription: health.HealthDescription,
+	}, nil
+}
+
 func (b *driverPluginServer) ExecTask(ctx context.Context, req *proto.ExecTaskRequest) (*proto.ExecTaskResponse, error) {
 	timeout, err := ptypes.Duration(req.Timeout)
 	if err != nil {
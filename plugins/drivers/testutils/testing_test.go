@@ -3,6 +3,7 @@ package testutils
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -265,6 +266,117 @@ func TestBaseDriver_TaskEvents(t *testing.T) {
 
 }
 
+func TestBaseDriver_TaskLogs(t *testing.T) {
+	ci.Parallel(t)
+
+	frames := []*drivers.TaskLogFrame{
+		{Stream: drivers.TaskLogStreamStdout, Data: []byte("hello")},
+		{Stream: drivers.TaskLogStreamStderr, Data: []byte("oops")},
+		{Stream: drivers.TaskLogStreamStdout, Data: []byte("world")},
+	}
+
+	impl := &MockDriver{
+		TaskLogsF: func(ctx context.Context, taskID string) (<-chan *drivers.TaskLogFrame, error) {
+			require.Equal(t, "abc", taskID)
+			ch := make(chan *drivers.TaskLogFrame)
+			go func() {
+				defer close(ch)
+				for _, frame := range frames {
+					ch <- frame
+				}
+			}()
+			return ch, nil
+		},
+	}
+
+	harness := NewDriverHarness(t, impl)
+	defer harness.Kill()
+
+	d, ok := harness.DriverPlugin.(drivers.LogStreamingDriver)
+	require.True(t, ok)
+
+	ch, err := d.TaskLogs(context.Background(), "abc")
+	require.NoError(t, err)
+
+	var actual []*drivers.TaskLogFrame
+	for frame := range ch {
+		actual = append(actual, frame)
+	}
+	require.Equal(t, frames, actual)
+}
+
+func TestBaseDriver_TaskHealth(t *testing.T) {
+	ci.Parallel(t)
+
+	impl := &MockDriver{
+		TaskHealthF: func(ctx context.Context, taskID string) (*drivers.TaskHealthStatus, error) {
+			require.Equal(t, "abc", taskID)
+			return &drivers.TaskHealthStatus{
+				Health:            drivers.HealthStateUnhealthy,
+				HealthDescription: "container is unhealthy",
+			}, nil
+		},
+	}
+
+	harness := NewDriverHarness(t, impl)
+	defer harness.Kill()
+
+	d, ok := harness.DriverPlugin.(drivers.TaskHealthDriver)
+	require.True(t, ok)
+
+	health, err := d.TaskHealth(context.Background(), "abc")
+	require.NoError(t, err)
+	require.Equal(t, &drivers.TaskHealthStatus{
+		Health:            drivers.HealthStateUnhealthy,
+		HealthDescription: "container is unhealthy",
+	}, health)
+}
+
+func TestBaseDriver_CheckpointRestore(t *testing.T) {
+	ci.Parallel(t)
+
+	impl := &MockDriver{
+		CheckpointTaskF: func(taskID, dir string) error {
+			require.Equal(t, "abc", taskID)
+			require.Equal(t, "/tmp/checkpoint", dir)
+			return nil
+		},
+		RestoreTaskF: func(c *drivers.TaskConfig, dir string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+			require.Equal(t, "/tmp/checkpoint", dir)
+			handle := drivers.NewTaskHandle(1)
+			handle.Config = c
+			handle.State = drivers.TaskStateRunning
+			return handle, &drivers.DriverNetwork{
+				IP:      "10.0.0.1",
+				PortMap: map[string]int{"http": 8080},
+			}, nil
+		},
+	}
+
+	harness := NewDriverHarness(t, impl)
+	defer harness.Kill()
+
+	d, ok := harness.DriverPlugin.(drivers.CheckpointDriver)
+	require.True(t, ok)
+
+	require.NoError(t, d.CheckpointTask("abc", "/tmp/checkpoint"))
+
+	handle, net, err := d.RestoreTask(&drivers.TaskConfig{ID: "abc"}, "/tmp/checkpoint")
+	require.NoError(t, err)
+	require.Equal(t, "abc", handle.Config.ID)
+	require.Equal(t, drivers.TaskStateRunning, handle.State)
+	require.Equal(t, "10.0.0.1", net.IP)
+	require.Equal(t, 8080, net.PortMap["http"])
+
+	// Errors from restoring are recoverable like those from starting tasks
+	impl.RestoreTaskF = func(*drivers.TaskConfig, string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+		return nil, nil, structs.NewRecoverableError(fmt.Errorf("checkpoint not found"), true)
+	}
+	_, _, err = d.RestoreTask(&drivers.TaskConfig{ID: "abc"}, "/tmp/checkpoint")
+	require.Error(t, err)
+	require.True(t, structs.IsRecoverable(err))
+}
+
 func TestBaseDriver_Capabilities(t *testing.T) {
 	ci.Parallel(t)
 
@@ -277,6 +389,9 @@ func TestBaseDriver_Capabilities(t *testing.T) {
 		SendSignals:         true,
 		Exec:                true,
 		FSIsolation:         drivers.FSIsolationNone,
+		LogStreaming:        true,
+		TaskHealth:          true,
+		Checkpoint:          true,
 	}
 	d := &MockDriver{
 		CapabilitiesF: func() (*drivers.Capabilities, error) {
@@ -1,6 +1,8 @@
 package drivers
 
 import (
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -66,6 +68,7 @@ func taskConfigFromProto(pb *proto.TaskConfig) *TaskConfig {
 		AllocID:          pb.AllocId,
 		NetworkIsolation: NetworkIsolationSpecFromProto(pb.NetworkIsolationSpec),
 		DNS:              dnsConfigFromProto(pb.Dns),
+		Process:          ProcessConfigFromProto(pb.Process),
 	}
 }
 
@@ -91,6 +94,7 @@ func taskConfigToProto(cfg *TaskConfig) *proto.TaskConfig {
 		AllocId:              cfg.AllocID,
 		NetworkIsolationSpec: NetworkIsolationSpecToProto(cfg.NetworkIsolation),
 		Dns:                  dnsConfigToProto(cfg.DNS),
+		Process:              ProcessConfigToProto(cfg.Process),
 	}
 	return pb
 }
@@ -279,9 +283,11 @@ func MountFromProto(mount *proto.Mount) *MountConfig {
 	}
 
 	return &MountConfig{
-		TaskPath: mount.TaskPath,
-		HostPath: mount.HostPath,
-		Readonly: mount.Readonly,
+		TaskPath:  mount.TaskPath,
+		HostPath:  mount.HostPath,
+		Readonly:  mount.Readonly,
+		Type:      mount.Type,
+		TmpfsSize: mount.TmpfsSize,
 	}
 }
 
@@ -329,12 +335,49 @@ func MountToProto(mount *MountConfig) *proto.Mount {
 	}
 
 	return &proto.Mount{
-		TaskPath: mount.TaskPath,
-		HostPath: mount.HostPath,
-		Readonly: mount.Readonly,
+		TaskPath:  mount.TaskPath,
+		HostPath:  mount.HostPath,
+		Readonly:  mount.Readonly,
+		Type:      mount.Type,
+		TmpfsSize: mount.TmpfsSize,
 	}
 }
 
+func driverNetworkFromProto(pb *proto.NetworkOverride) *DriverNetwork {
+	if pb == nil {
+		return nil
+	}
+
+	net := &DriverNetwork{
+		PortMap:       map[string]int{},
+		IP:            pb.Addr,
+		AutoAdvertise: pb.AutoAdvertise,
+	}
+	for k, v := range pb.PortMap {
+		net.PortMap[k] = int(v)
+	}
+	return net
+}
+
+func driverNetworkToProto(net *DriverNetwork) (*proto.NetworkOverride, error) {
+	if net == nil {
+		return nil, nil
+	}
+
+	pb := &proto.NetworkOverride{
+		PortMap:       map[string]int32{},
+		Addr:          net.IP,
+		AutoAdvertise: net.AutoAdvertise,
+	}
+	for k, v := range net.PortMap {
+		if v > math.MaxInt32 {
+			return nil, fmt.Errorf("port map out of bounds")
+		}
+		pb.PortMap[k] = int32(v)
+	}
+	return pb, nil
+}
+
 func taskHandleFromProto(pb *proto.TaskHandle) *TaskHandle {
 	if pb == nil {
 		return &TaskHandle{}
@@ -473,12 +516,20 @@ func resourceUsageToProto(ru *ResourceUsage) *proto.TaskResourceUsage {
 		MaxUsage:       ru.MemoryStats.MaxUsage,
 		KernelUsage:    ru.MemoryStats.KernelUsage,
 		KernelMaxUsage: ru.MemoryStats.KernelMaxUsage,
+		Limit:          ru.MemoryStats.Limit,
+		Reservation:    ru.MemoryStats.Reservation,
 	}
 
-	return &proto.TaskResourceUsage{
+	pb := &proto.TaskResourceUsage{
 		Cpu:    cpu,
 		Memory: memory,
 	}
+	if ru.Process != nil {
+		pb.Ppid = int64(ru.Process.PPID)
+		pb.Command = ru.Process.Command
+	}
+
+	return pb
 }
 
 func resourceUsageFromProto(pb *proto.TaskResourceUsage) *ResourceUsage {
@@ -506,13 +557,23 @@ func resourceUsageFromProto(pb *proto.TaskResourceUsage) *ResourceUsage {
 			MaxUsage:       pb.Memory.MaxUsage,
 			KernelUsage:    pb.Memory.KernelUsage,
 			KernelMaxUsage: pb.Memory.KernelMaxUsage,
+			Limit:          pb.Memory.Limit,
+			Reservation:    pb.Memory.Reservation,
 		}
 	}
 
-	return &ResourceUsage{
+	ru := &ResourceUsage{
 		CpuStats:    &cpu,
 		MemoryStats: &memory,
 	}
+	if pb.Ppid != 0 || pb.Command != "" {
+		ru.Process = &ProcessInfo{
+			PPID:    int(pb.Ppid),
+			Command: pb.Command,
+		}
+	}
+
+	return ru
 }
 
 func BytesToMB(bytes int64) int64 {
@@ -713,3 +774,29 @@ func dnsConfigFromProto(pb *proto.DNSConfig) *DNSConfig {
 		Options:  pb.Options,
 	}
 }
+
+func ProcessConfigToProto(cfg *ProcessConfig) *proto.ProcessConfig {
+	if cfg == nil {
+		return nil
+	}
+
+	return &proto.ProcessConfig{
+		Umask:       cfg.Umask,
+		Nice:        int32(cfg.Nice),
+		IoniceClass: cfg.IOniceClass,
+		Rlimits:     cfg.Rlimits,
+	}
+}
+
+func ProcessConfigFromProto(pb *proto.ProcessConfig) *ProcessConfig {
+	if pb == nil {
+		return nil
+	}
+
+	return &ProcessConfig{
+		Umask:       pb.Umask,
+		Nice:        int(pb.Nice),
+		IOniceClass: pb.IoniceClass,
+		Rlimits:     pb.Rlimits,
+	}
+}
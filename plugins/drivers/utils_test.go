@@ -3,6 +3,7 @@ package drivers
 import (
 	"testing"
 
+	pbproto "github.com/golang/protobuf/proto"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/drivers/proto"
@@ -28,6 +29,8 @@ func TestResourceUsageRoundTrip(t *testing.T) {
 			MaxUsage:       23,
 			KernelUsage:    34,
 			KernelMaxUsage: 45,
+			Limit:          512 * 1024 * 1024,
+			Reservation:    256 * 1024 * 1024,
 			Measured:       []string{"RSS", "Swap"},
 		},
 	}
@@ -35,6 +38,14 @@ func TestResourceUsageRoundTrip(t *testing.T) {
 	parsed := resourceUsageFromProto(resourceUsageToProto(input))
 
 	require.EqualValues(t, parsed, input)
+
+	input.Process = &ProcessInfo{
+		PPID:    42,
+		Command: "/bin/sleep 1000",
+	}
+	parsed = resourceUsageFromProto(resourceUsageToProto(input))
+
+	require.EqualValues(t, parsed, input)
 }
 
 func TestTaskConfigRoundTrip(t *testing.T) {
@@ -98,12 +109,24 @@ func TestTaskConfigRoundTrip(t *testing.T) {
 			Searches: []string{".consul"},
 			Options:  []string{"ndots:2"},
 		},
+		Process: &ProcessConfig{
+			Umask:       "027",
+			Nice:        10,
+			IOniceClass: "idle",
+			Rlimits:     map[string]uint64{"nofile": 65536},
+		},
 	}
 
 	parsed := taskConfigFromProto(taskConfigToProto(input))
 
 	require.EqualValues(t, input, parsed)
 
+	// Ensure the process config survives the wire encoding
+	buf, err := pbproto.Marshal(taskConfigToProto(input))
+	require.NoError(t, err)
+	var pb proto.TaskConfig
+	require.NoError(t, pbproto.Unmarshal(buf, &pb))
+	require.EqualValues(t, input.Process, taskConfigFromProto(&pb).Process)
 }
 
 func Test_networkCreateRequestFromProto(t *testing.T) {
@@ -0,0 +1,78 @@
+package fingerprint
+
+import (
+	"context"
+	"io"
+
+	"github.com/LK4D4/joincontext"
+	"github.com/hashicorp/nomad/helper/pluginutils/grpcutils"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/fingerprint/proto"
+	"github.com/hashicorp/nomad/plugins/shared/structs"
+)
+
+// fingerprintPluginClient implements the client side of a remote fingerprint
+// plugin, using gRPC to communicate to the remote plugin.
+type fingerprintPluginClient struct {
+	// basePluginClient is embedded to give access to the base plugin methods.
+	*base.BasePluginClient
+
+	client proto.FingerprintPluginClient
+
+	// doneCtx is closed when the plugin exits
+	doneCtx context.Context
+}
+
+// Fingerprint is used to retrieve the node attributes detected by the
+// fingerprint plugin. An error may be immediately returned if the fingerprint
+// call could not be made or as part of the streaming response. If the context
+// is cancelled, the error will be propagated.
+func (f *fingerprintPluginClient) Fingerprint(ctx context.Context) (<-chan *FingerprintResponse, error) {
+	// Join the passed context and the shutdown context
+	joinedCtx, _ := joincontext.Join(ctx, f.doneCtx)
+
+	var req proto.FingerprintRequest
+	stream, err := f.client.Fingerprint(joinedCtx, &req)
+	if err != nil {
+		return nil, grpcutils.HandleReqCtxGrpcErr(err, ctx, f.doneCtx)
+	}
+
+	out := make(chan *FingerprintResponse, 1)
+	go f.handleFingerprint(ctx, stream, out)
+	return out, nil
+}
+
+// handleFingerprint should be launched in a goroutine and handles converting
+// the gRPC stream to a channel. Exits either when context is cancelled or the
+// stream has an error.
+func (f *fingerprintPluginClient) handleFingerprint(
+	reqCtx context.Context,
+	stream proto.FingerprintPlugin_FingerprintClient,
+	out chan *FingerprintResponse) {
+
+	defer close(out)
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				out <- &FingerprintResponse{
+					Error: grpcutils.HandleReqCtxGrpcErr(err, reqCtx, f.doneCtx),
+				}
+			}
+
+			// End the stream
+			return
+		}
+
+		// Send the response
+		fresp := &FingerprintResponse{
+			Attributes: structs.ConvertProtoAttributeMap(resp.GetAttributes()),
+			Links:      resp.GetLinks(),
+		}
+		select {
+		case <-reqCtx.Done():
+			return
+		case out <- fresp:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package fingerprint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/shared/structs"
+)
+
+var (
+	// ErrPluginDisabled indicates that the fingerprint plugin is disabled
+	ErrPluginDisabled = fmt.Errorf("fingerprint is not enabled")
+)
+
+// FingerprintPlugin is the interface for a plugin that can contribute node
+// attributes detected outside of Nomad, such as GPU topology, NUMA layout or
+// custom hardware.
+type FingerprintPlugin interface {
+	base.BasePlugin
+
+	// Fingerprint returns a stream of detected node attributes. The plugin
+	// sends a new response whenever the attributes change.
+	Fingerprint(ctx context.Context) (<-chan *FingerprintResponse, error)
+}
+
+// FingerprintResponse includes the set of detected node attributes or an error
+// in the process of fingerprinting.
+type FingerprintResponse struct {
+	// Attributes are the detected node attributes. Attributes sent in a
+	// previous response but missing from this one are removed from the node.
+	Attributes map[string]*structs.Attribute
+
+	// Links are the detected node links, such as the ID of the node in an
+	// external inventory.
+	Links map[string]string
+
+	// Error is populated when fingerprinting has failed.
+	Error error
+}
+
+// NewFingerprint takes a set of attributes and returns a fingerprint response
+func NewFingerprint(attributes map[string]*structs.Attribute) *FingerprintResponse {
+	return &FingerprintResponse{
+		Attributes: attributes,
+	}
+}
+
+// NewFingerprintError takes an error and returns a fingerprint response
+func NewFingerprintError(err error) *FingerprintResponse {
+	return &FingerprintResponse{
+		Error: err,
+	}
+}
@@ -0,0 +1,42 @@
+package fingerprint
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/shared/structs"
+)
+
+type FingerprintFn func(context.Context) (<-chan *FingerprintResponse, error)
+
+// MockFingerprintPlugin is used for testing.
+// Each function can be set as a closure to make assertions about how data
+// is passed through the base plugin layer.
+type MockFingerprintPlugin struct {
+	*base.MockPlugin
+	FingerprintF FingerprintFn
+}
+
+func (p *MockFingerprintPlugin) Fingerprint(ctx context.Context) (<-chan *FingerprintResponse, error) {
+	return p.FingerprintF(ctx)
+}
+
+// Below are static implementations of the fingerprint functions
+
+// StaticFingerprinter fingerprints the passed attributes just once
+func StaticFingerprinter(attributes map[string]*structs.Attribute) FingerprintFn {
+	return func(_ context.Context) (<-chan *FingerprintResponse, error) {
+		outCh := make(chan *FingerprintResponse, 1)
+		outCh <- NewFingerprint(attributes)
+		return outCh, nil
+	}
+}
+
+// ErrorChFingerprinter returns an error fingerprinting over the channel
+func ErrorChFingerprinter(err error) FingerprintFn {
+	return func(_ context.Context) (<-chan *FingerprintResponse, error) {
+		outCh := make(chan *FingerprintResponse, 1)
+		outCh <- NewFingerprintError(err)
+		return outCh, nil
+	}
+}
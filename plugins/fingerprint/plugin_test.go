@@ -0,0 +1,164 @@
+package fingerprint
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/shared/structs"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/status"
+)
+
+func TestFingerprintPlugin_PluginInfo(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	mock := &MockFingerprintPlugin{
+		MockPlugin: &base.MockPlugin{
+			PluginInfoF: func() (*base.PluginInfoResponse, error) {
+				return &base.PluginInfoResponse{
+					Type:              base.PluginTypeFingerprint,
+					PluginApiVersions: []string{ApiVersion010},
+					PluginVersion:     "v0.1.0",
+					Name:              "mock_fingerprint",
+				}, nil
+			},
+		},
+	}
+
+	impl, cleanup := testFingerprintPlugin(t, mock)
+	defer cleanup()
+
+	resp, err := impl.PluginInfo()
+	require.NoError(err)
+	require.Equal(base.PluginTypeFingerprint, resp.Type)
+	require.Equal([]string{ApiVersion010}, resp.PluginApiVersions)
+	require.Equal("mock_fingerprint", resp.Name)
+}
+
+func TestFingerprintPlugin_Fingerprint(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	attrs1 := map[string]*structs.Attribute{
+		"numa.nodes":   structs.NewIntAttribute(2, ""),
+		"numa.enabled": structs.NewBoolAttribute(true),
+	}
+	attrs2 := map[string]*structs.Attribute{
+		"numa.nodes": structs.NewIntAttribute(4, ""),
+	}
+	links := map[string]string{"inventory": "rack-12"}
+
+	mock := &MockFingerprintPlugin{
+		FingerprintF: func(ctx context.Context) (<-chan *FingerprintResponse, error) {
+			outCh := make(chan *FingerprintResponse, 1)
+			go func() {
+				// Send two messages
+				for _, resp := range []*FingerprintResponse{
+					{Attributes: attrs1, Links: links},
+					{Attributes: attrs2},
+				} {
+					select {
+					case <-ctx.Done():
+						return
+					case outCh <- resp:
+					}
+				}
+				close(outCh)
+			}()
+			return outCh, nil
+		},
+	}
+
+	impl, cleanup := testFingerprintPlugin(t, mock)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := impl.Fingerprint(ctx)
+	require.NoError(err)
+
+	// Get the first message
+	var first *FingerprintResponse
+	select {
+	case <-time.After(1 * time.Second):
+		t.Fatal("timeout")
+	case first = <-stream:
+	}
+	require.NoError(first.Error)
+	require.EqualValues(attrs1, first.Attributes)
+	require.Equal(links, first.Links)
+
+	// Get the second message
+	var second *FingerprintResponse
+	select {
+	case <-time.After(1 * time.Second):
+		t.Fatal("timeout")
+	case second = <-stream:
+	}
+	require.NoError(second.Error)
+	require.EqualValues(attrs2, second.Attributes)
+	require.Empty(second.Links)
+
+	select {
+	case _, ok := <-stream:
+		require.False(ok)
+	case <-time.After(1 * time.Second):
+		t.Fatal("stream should be closed")
+	}
+}
+
+func TestFingerprintPlugin_Fingerprint_StreamErr(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	ferr := fmt.Errorf("mock fingerprinting failed")
+	mock := &MockFingerprintPlugin{
+		FingerprintF: ErrorChFingerprinter(ferr),
+	}
+
+	impl, cleanup := testFingerprintPlugin(t, mock)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := impl.Fingerprint(ctx)
+	require.NoError(err)
+
+	var first *FingerprintResponse
+	select {
+	case <-time.After(1 * time.Second):
+		t.Fatal("timeout")
+	case first = <-stream:
+	}
+
+	errStatus := status.Convert(ferr)
+	require.EqualError(first.Error, errStatus.Err().Error())
+}
+
+// testFingerprintPlugin serves the plugin over gRPC and returns the client side
+// of it.
+func testFingerprintPlugin(t *testing.T, mock *MockFingerprintPlugin) (FingerprintPlugin, func()) {
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		base.PluginTypeBase:        &base.PluginBase{Impl: mock},
+		base.PluginTypeFingerprint: &PluginFingerprint{Impl: mock},
+	})
+
+	raw, err := client.Dispense(base.PluginTypeFingerprint)
+	require.NoError(t, err)
+
+	impl, ok := raw.(FingerprintPlugin)
+	require.True(t, ok, "bad: %#v", raw)
+
+	return impl, func() {
+		client.Close()
+		server.Stop()
+	}
+}
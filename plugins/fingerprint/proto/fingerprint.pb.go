@@ -0,0 +1,257 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: plugins/fingerprint/proto/fingerprint.proto
+
+package proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	proto1 "github.com/hashicorp/nomad/plugins/shared/structs/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// FingerprintRequest is used to request for the node to be fingerprinted.
+type FingerprintRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FingerprintRequest) Reset()         { *m = FingerprintRequest{} }
+func (m *FingerprintRequest) String() string { return proto.CompactTextString(m) }
+func (*FingerprintRequest) ProtoMessage()    {}
+func (*FingerprintRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2862dfbcc10ff440, []int{0}
+}
+
+func (m *FingerprintRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FingerprintRequest.Unmarshal(m, b)
+}
+func (m *FingerprintRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FingerprintRequest.Marshal(b, m, deterministic)
+}
+func (m *FingerprintRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FingerprintRequest.Merge(m, src)
+}
+func (m *FingerprintRequest) XXX_Size() int {
+	return xxx_messageInfo_FingerprintRequest.Size(m)
+}
+func (m *FingerprintRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FingerprintRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FingerprintRequest proto.InternalMessageInfo
+
+// FingerprintResponse returns the node attributes detected by the plugin.
+type FingerprintResponse struct {
+	// attributes are the node attributes detected by the plugin. Attributes
+	// returned by a previous response but missing from this one are removed
+	// from the node.
+	Attributes map[string]*proto1.Attribute `protobuf:"bytes,1,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// links are the node links detected by the plugin, such as the ID of the
+	// node in an external inventory.
+	Links                map[string]string `protobuf:"bytes,2,rep,name=links,proto3" json:"links,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *FingerprintResponse) Reset()         { *m = FingerprintResponse{} }
+func (m *FingerprintResponse) String() string { return proto.CompactTextString(m) }
+func (*FingerprintResponse) ProtoMessage()    {}
+func (*FingerprintResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2862dfbcc10ff440, []int{1}
+}
+
+func (m *FingerprintResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FingerprintResponse.Unmarshal(m, b)
+}
+func (m *FingerprintResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FingerprintResponse.Marshal(b, m, deterministic)
+}
+func (m *FingerprintResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FingerprintResponse.Merge(m, src)
+}
+func (m *FingerprintResponse) XXX_Size() int {
+	return xxx_messageInfo_FingerprintResponse.Size(m)
+}
+func (m *FingerprintResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FingerprintResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FingerprintResponse proto.InternalMessageInfo
+
+func (m *FingerprintResponse) GetAttributes() map[string]*proto1.Attribute {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *FingerprintResponse) GetLinks() map[string]string {
+	if m != nil {
+		return m.Links
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*FingerprintRequest)(nil), "hashicorp.nomad.plugins.fingerprint.FingerprintRequest")
+	proto.RegisterType((*FingerprintResponse)(nil), "hashicorp.nomad.plugins.fingerprint.FingerprintResponse")
+	proto.RegisterMapType((map[string]*proto1.Attribute)(nil), "hashicorp.nomad.plugins.fingerprint.FingerprintResponse.AttributesEntry")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.fingerprint.FingerprintResponse.LinksEntry")
+}
+
+func init() {
+	proto.RegisterFile("plugins/fingerprint/proto/fingerprint.proto", fileDescriptor_2862dfbcc10ff440)
+}
+
+var fileDescriptor_2862dfbcc10ff440 = []byte{
+	// 305 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0x3f, 0x4f, 0xc3, 0x30,
+	0x14, 0xc4, 0x71, 0xab, 0x80, 0xfa, 0x32, 0x00, 0xa6, 0x43, 0x94, 0x29, 0x0a, 0x4b, 0x24, 0x90,
+	0x03, 0x61, 0x20, 0x62, 0x03, 0xc4, 0x9f, 0x81, 0x01, 0x65, 0x83, 0x2d, 0x6d, 0x4d, 0x63, 0x35,
+	0x38, 0xc6, 0x76, 0x90, 0xba, 0x23, 0x3e, 0x04, 0x9f, 0x16, 0xd5, 0x09, 0x8d, 0x41, 0x45, 0x82,
+	0x4e, 0x89, 0x4e, 0xbe, 0xdf, 0xbd, 0x77, 0x36, 0x1c, 0x88, 0xb2, 0x9e, 0x32, 0xae, 0xe2, 0x27,
+	0xc6, 0xa7, 0x54, 0x0a, 0xc9, 0xb8, 0x8e, 0x85, 0xac, 0x74, 0x65, 0x2b, 0xc4, 0x28, 0x78, 0xbf,
+	0xc8, 0x55, 0xc1, 0xc6, 0x95, 0x14, 0x84, 0x57, 0xcf, 0xf9, 0x84, 0xb4, 0x66, 0x62, 0x1d, 0xf5,
+	0x0f, 0xbf, 0x88, 0xaa, 0xc8, 0x25, 0x9d, 0xc4, 0x4a, 0xcb, 0x7a, 0xac, 0x55, 0x0b, 0xcd, 0xb5,
+	0x96, 0x6c, 0x54, 0x6b, 0xda, 0x20, 0xc3, 0x21, 0xe0, 0xeb, 0xce, 0x9c, 0xd1, 0x97, 0x9a, 0x2a,
+	0x1d, 0xbe, 0xf7, 0x61, 0xef, 0x9b, 0xac, 0x44, 0xc5, 0x15, 0xc5, 0x05, 0xc0, 0x12, 0xa0, 0x3c,
+	0x14, 0xf4, 0x23, 0x37, 0xb9, 0x25, 0x7f, 0x98, 0x8a, 0xac, 0xa0, 0x91, 0xf3, 0x25, 0xea, 0x8a,
+	0x6b, 0x39, 0xcf, 0x2c, 0x36, 0x7e, 0x00, 0xa7, 0x64, 0x7c, 0xa6, 0xbc, 0x9e, 0x09, 0xb9, 0x5c,
+	0x3b, 0xe4, 0x6e, 0x41, 0x69, 0xf8, 0x0d, 0xd1, 0x17, 0xb0, 0xfd, 0x23, 0x19, 0xef, 0x40, 0x7f,
+	0x46, 0xe7, 0x1e, 0x0a, 0x50, 0x34, 0xc8, 0x16, 0xbf, 0xf8, 0x06, 0x9c, 0xd7, 0xbc, 0xac, 0xa9,
+	0xd7, 0x0b, 0x50, 0xe4, 0x26, 0xc7, 0xbf, 0xe6, 0x37, 0x2d, 0x93, 0xb6, 0xe5, 0x6e, 0xa7, 0xac,
+	0xf1, 0x9f, 0xf5, 0x52, 0xe4, 0xa7, 0x00, 0xdd, 0x18, 0x2b, 0xc2, 0x86, 0x76, 0xd8, 0xc0, 0x72,
+	0x26, 0x1f, 0x08, 0x76, 0xad, 0xad, 0xee, 0x4d, 0x28, 0x7e, 0x43, 0xe0, 0x5a, 0x2a, 0x3e, 0xfd,
+	0x7f, 0x3b, 0xe6, 0x9e, 0xfd, 0x74, 0xdd, 0x5a, 0xc3, 0x8d, 0x23, 0x74, 0xb1, 0xf5, 0xe8, 0x98,
+	0x47, 0x34, 0xda, 0x34, 0x9f, 0x93, 0xcf, 0x01, 0x00, 0xd4, 0xc7, 0x86, 0xcc, 0xcd, 0x02, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// FingerprintPluginClient is the client API for FingerprintPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FingerprintPluginClient interface {
+	// Fingerprint allows the fingerprint plugin to return a set of node
+	// attributes and provide a mechanism to update them.
+	Fingerprint(ctx context.Context, in *FingerprintRequest, opts ...grpc.CallOption) (FingerprintPlugin_FingerprintClient, error)
+}
+
+type fingerprintPluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFingerprintPluginClient(cc grpc.ClientConnInterface) FingerprintPluginClient {
+	return &fingerprintPluginClient{cc}
+}
+
+func (c *fingerprintPluginClient) Fingerprint(ctx context.Context, in *FingerprintRequest, opts ...grpc.CallOption) (FingerprintPlugin_FingerprintClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FingerprintPlugin_serviceDesc.Streams[0], "/hashicorp.nomad.plugins.fingerprint.FingerprintPlugin/Fingerprint", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fingerprintPluginFingerprintClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FingerprintPlugin_FingerprintClient interface {
+	Recv() (*FingerprintResponse, error)
+	grpc.ClientStream
+}
+
+type fingerprintPluginFingerprintClient struct {
+	grpc.ClientStream
+}
+
+func (x *fingerprintPluginFingerprintClient) Recv() (*FingerprintResponse, error) {
+	m := new(FingerprintResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FingerprintPluginServer is the server API for FingerprintPlugin service.
+type FingerprintPluginServer interface {
+	// Fingerprint allows the fingerprint plugin to return a set of node
+	// attributes and provide a mechanism to update them.
+	Fingerprint(*FingerprintRequest, FingerprintPlugin_FingerprintServer) error
+}
+
+// UnimplementedFingerprintPluginServer can be embedded to have forward compatible implementations.
+type UnimplementedFingerprintPluginServer struct {
+}
+
+func (*UnimplementedFingerprintPluginServer) Fingerprint(req *FingerprintRequest, srv FingerprintPlugin_FingerprintServer) error {
+	return status.Errorf(codes.Unimplemented, "method Fingerprint not implemented")
+}
+
+func RegisterFingerprintPluginServer(s *grpc.Server, srv FingerprintPluginServer) {
+	s.RegisterService(&_FingerprintPlugin_serviceDesc, srv)
+}
+
+func _FingerprintPlugin_Fingerprint_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FingerprintRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FingerprintPluginServer).Fingerprint(m, &fingerprintPluginFingerprintServer{stream})
+}
+
+type FingerprintPlugin_FingerprintServer interface {
+	Send(*FingerprintResponse) error
+	grpc.ServerStream
+}
+
+type fingerprintPluginFingerprintServer struct {
+	grpc.ServerStream
+}
+
+func (x *fingerprintPluginFingerprintServer) Send(m *FingerprintResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _FingerprintPlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hashicorp.nomad.plugins.fingerprint.FingerprintPlugin",
+	HandlerType: (*FingerprintPluginServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Fingerprint",
+			Handler:       _FingerprintPlugin_Fingerprint_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "plugins/fingerprint/proto/fingerprint.proto",
+}
@@ -0,0 +1,49 @@
+package fingerprint
+
+import (
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/plugins/fingerprint/proto"
+	"github.com/hashicorp/nomad/plugins/shared/structs"
+)
+
+// fingerprintPluginServer wraps a fingerprint plugin and exposes it via gRPC.
+type fingerprintPluginServer struct {
+	broker *plugin.GRPCBroker
+	impl   FingerprintPlugin
+}
+
+func (f *fingerprintPluginServer) Fingerprint(req *proto.FingerprintRequest, stream proto.FingerprintPlugin_FingerprintServer) error {
+	ctx := stream.Context()
+	outCh, err := f.impl.Fingerprint(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case resp, ok := <-outCh:
+			// The output channel has been closed, end the stream
+			if !ok {
+				return nil
+			}
+
+			// Handle any error
+			if resp.Error != nil {
+				return resp.Error
+			}
+
+			// Build the response
+			presp := &proto.FingerprintResponse{
+				Attributes: structs.ConvertStructAttributeMap(resp.Attributes),
+				Links:      resp.Links,
+			}
+
+			// Send the attributes
+			if err := stream.Send(presp); err != nil {
+				return err
+			}
+		}
+	}
+}
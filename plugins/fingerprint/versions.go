@@ -0,0 +1,6 @@
+package fingerprint
+
+const (
+	// ApiVersion010 is the initial API version for the fingerprint plugins
+	ApiVersion010 = "v0.1.0"
+)
@@ -7,6 +7,7 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/plugins/device"
 	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/fingerprint"
 )
 
 // PluginFactory returns a new plugin instance
@@ -45,6 +46,8 @@ func serve(plugin interface{}, logger log.Logger) {
 		device.Serve(p, logger)
 	case drivers.DriverPlugin:
 		drivers.Serve(p, logger)
+	case fingerprint.FingerprintPlugin:
+		fingerprint.Serve(p, logger)
 	default:
 		fmt.Println("Unsupported plugin type")
 	}
@@ -98,7 +98,7 @@ func (ev *PortCollisionEvent) Copy() *PortCollisionEvent {
 	c.Node = ev.Node.Copy()
 	if len(ev.Allocations) > 0 {
 		for i, a := range ev.Allocations {
-			c.Allocations[i] = a.Copy()
+			c.Allocations[i] = a.CopySkipJob()
 		}
 
 	}
@@ -132,6 +132,9 @@ type EvalContext struct {
 	logger      log.Logger
 	metrics     *structs.AllocMetric
 	eligibility *EvalEligibility
+
+	// explain enables the explanation of the placements in the metrics
+	explain bool
 }
 
 // NewEvalContext constructs a new EvalContext
@@ -168,6 +171,16 @@ func (e *EvalContext) SetState(s State) {
 
 func (e *EvalContext) Reset() {
 	e.metrics = new(structs.AllocMetric)
+	if e.explain {
+		e.metrics.ExplainPlacement()
+	}
+}
+
+// ExplainPlacements enables the explanation of the placements made in the
+// context: the metrics record the outcome of every node evaluated.
+func (e *EvalContext) ExplainPlacements() {
+	e.explain = true
+	e.metrics.ExplainPlacement()
 }
 
 func (e *EvalContext) ProposedAllocs(nodeID string) ([]*structs.Allocation, error) {
@@ -284,15 +297,11 @@ func NewEvalEligibility() *EvalEligibility {
 // at the job and task group level.
 func (e *EvalEligibility) SetJob(job *structs.Job) {
 	// Determine whether the job has escaped constraints.
-	e.jobEscaped = len(structs.EscapedConstraints(job.Constraints)) != 0
+	e.jobEscaped = len(structs.EscapedConstraints(jobConstraints(job))) != 0
 
 	// Determine the escaped constraints per task group.
 	for _, tg := range job.TaskGroups {
-		constraints := tg.Constraints
-		for _, task := range tg.Tasks {
-			constraints = append(constraints, task.Constraints...)
-		}
-
+		constraints := taskGroupConstraints(tg).constraints
 		e.tgEscapedConstraints[tg.Name] = len(structs.EscapedConstraints(constraints)) != 0
 	}
 }
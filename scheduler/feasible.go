@@ -619,9 +619,100 @@ func (iter *DistinctHostsIterator) Reset() {
 	iter.source.Reset()
 }
 
+// AntiAffinityIterator is a FeasibleIterator which returns nodes that pass the
+// anti_affinity blocks of the task group. Nodes running allocations of other
+// jobs in the same namespace whose ID matches one of the job regexps are
+// filtered out.
+type AntiAffinityIterator struct {
+	ctx    Context
+	source FeasibleIterator
+	job    *structs.Job
+	tg     *structs.TaskGroup
+}
+
+// NewAntiAffinityIterator creates an AntiAffinityIterator from a source.
+func NewAntiAffinityIterator(ctx Context, source FeasibleIterator) *AntiAffinityIterator {
+	return &AntiAffinityIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *AntiAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+}
+
+func (iter *AntiAffinityIterator) SetJob(job *structs.Job) {
+	iter.job = job
+}
+
+func (iter *AntiAffinityIterator) Next() *structs.Node {
+	for {
+		// Get the next option from the source
+		option := iter.source.Next()
+
+		// Hot-path if the option is nil or there are no anti-affinities
+		if option == nil || iter.tg == nil || len(iter.tg.AntiAffinities) == 0 {
+			return option
+		}
+
+		if !iter.satisfiesAntiAffinities(option) {
+			iter.ctx.Metrics().FilterNode(option, "anti_affinity")
+			continue
+		}
+
+		return option
+	}
+}
+
+// satisfiesAntiAffinities checks that no allocation of a job matched by the
+// anti-affinities of the task group is proposed on the node.
+func (iter *AntiAffinityIterator) satisfiesAntiAffinities(option *structs.Node) bool {
+	proposed, err := iter.ctx.ProposedAllocs(option.ID)
+	if err != nil {
+		iter.ctx.Logger().Named("anti_affinity").Error("failed to get proposed allocations", "error", err)
+		return false
+	}
+
+	cache := iter.ctx.RegexpCache()
+	for _, aa := range iter.tg.AntiAffinities {
+		if aa.Scope != structs.AntiAffinityScopeNode {
+			continue
+		}
+
+		re, ok := cache[aa.JobRegexp]
+		if !ok {
+			re, err = regexp.Compile(aa.JobRegexp)
+			if err != nil {
+				return false
+			}
+			cache[aa.JobRegexp] = re
+		}
+
+		for _, alloc := range proposed {
+			if alloc.Namespace != iter.job.Namespace || alloc.JobID == iter.job.ID {
+				continue
+			}
+			if alloc.TerminalStatus() {
+				continue
+			}
+			if re.MatchString(alloc.JobID) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (iter *AntiAffinityIterator) Reset() {
+	iter.source.Reset()
+}
+
 // DistinctPropertyIterator is a FeasibleIterator which returns nodes that pass the
-// distinct_property constraint. The constraint ensures that multiple allocations
-// do not use the same value of the given property.
+// distinct_property and distinct_topology constraints. The constraints ensure
+// that multiple allocations do not use the same value of the given property, or
+// of each constrained level of the given topology.
 type DistinctPropertyIterator struct {
 	ctx    Context
 	source FeasibleIterator
@@ -648,13 +739,15 @@ func (iter *DistinctPropertyIterator) SetTaskGroup(tg *structs.TaskGroup) {
 	// Build the property set at the taskgroup level
 	if _, ok := iter.groupPropertySets[tg.Name]; !ok {
 		for _, c := range tg.Constraints {
-			if c.Operand != structs.ConstraintDistinctProperty {
-				continue
+			switch c.Operand {
+			case structs.ConstraintDistinctProperty:
+				pset := NewPropertySet(iter.ctx, iter.job)
+				pset.SetTGConstraint(c, tg.Name)
+				iter.groupPropertySets[tg.Name] = append(iter.groupPropertySets[tg.Name], pset)
+			case structs.ConstraintDistinctTopology:
+				psets := iter.topologyPropertySets(c, tg.Name)
+				iter.groupPropertySets[tg.Name] = append(iter.groupPropertySets[tg.Name], psets...)
 			}
-
-			pset := NewPropertySet(iter.ctx, iter.job)
-			pset.SetTGConstraint(c, tg.Name)
-			iter.groupPropertySets[tg.Name] = append(iter.groupPropertySets[tg.Name], pset)
 		}
 	}
 
@@ -667,14 +760,37 @@ func (iter *DistinctPropertyIterator) SetJob(job *structs.Job) {
 
 	// Build the property set at the job level
 	for _, c := range job.Constraints {
-		if c.Operand != structs.ConstraintDistinctProperty {
-			continue
+		switch c.Operand {
+		case structs.ConstraintDistinctProperty:
+			pset := NewPropertySet(iter.ctx, job)
+			pset.SetJobConstraint(c)
+			iter.jobPropertySets = append(iter.jobPropertySets, pset)
+		case structs.ConstraintDistinctTopology:
+			iter.jobPropertySets = append(iter.jobPropertySets, iter.topologyPropertySets(c, "")...)
 		}
+	}
+}
 
-		pset := NewPropertySet(iter.ctx, job)
-		pset.SetJobConstraint(c)
-		iter.jobPropertySets = append(iter.jobPropertySets, pset)
+// topologyPropertySets returns a property set for each constrained level of a
+// distinct_topology constraint. taskGroup is empty for a job level constraint.
+func (iter *DistinctPropertyIterator) topologyPropertySets(c *structs.Constraint, taskGroup string) []*propertySet {
+	levels, limits, err := c.TopologyLevels()
+	if err != nil {
+		pset := NewPropertySet(iter.ctx, iter.job)
+		pset.errorBuilding = err
+		return []*propertySet{pset}
 	}
+
+	var psets []*propertySet
+	for i, limit := range limits {
+		if limit == 0 {
+			continue
+		}
+		pset := NewPropertySet(iter.ctx, iter.job)
+		pset.SetTopologyConstraint(levels[:i+1], limit, taskGroup)
+		psets = append(psets, pset)
+	}
+	return psets
 }
 
 func (iter *DistinctPropertyIterator) Next() *structs.Node {
@@ -756,6 +872,11 @@ func (c *ConstraintChecker) Feasible(option *structs.Node) bool {
 }
 
 func (c *ConstraintChecker) meetsConstraint(constraint *structs.Constraint, option *structs.Node) bool {
+	// Device attributes are typed and compared with their units
+	if isNodeDeviceTarget(constraint.LTarget) {
+		return checkNodeDeviceConstraint(c.ctx, constraint.Operand, constraint.LTarget, constraint.RTarget, option)
+	}
+
 	// Resolve the targets. Targets that are not present are treated as `nil`.
 	// This is to allow for matching constraints where a target is not present.
 	lVal, lOk := resolveTarget(constraint.LTarget, option)
@@ -801,12 +922,76 @@ func resolveTarget(target string, node *structs.Node) (string, bool) {
 	}
 }
 
+// isNodeDeviceTarget returns whether the target references an attribute of the
+// devices of a node, in the form ${device.attr.<device>.<attribute>}.
+func isNodeDeviceTarget(target string) bool {
+	return strings.HasPrefix(target, "${device.attr.")
+}
+
+// resolveNodeDeviceTarget is used to resolve a target of the form
+// ${device.attr.<device>.<attribute>} against the devices of a node, where
+// <device> is matched like the name of a requested device (<type>,
+// <vendor>/<type> or <vendor>/<type>/<model>). It returns the attribute of
+// every matching device group that reports it.
+func resolveNodeDeviceTarget(target string, node *structs.Node) []*psstructs.Attribute {
+	if node.NodeResources == nil {
+		return nil
+	}
+
+	ref := strings.TrimSuffix(strings.TrimPrefix(target, "${device.attr."), "}")
+	idx := strings.Index(ref, ".")
+	if idx <= 0 || idx == len(ref)-1 {
+		return nil
+	}
+	id := (&structs.RequestedDevice{Name: ref[:idx]}).ID()
+	attr := ref[idx+1:]
+
+	var vals []*psstructs.Attribute
+	for _, d := range node.NodeResources.Devices {
+		if !d.ID().Matches(id) {
+			continue
+		}
+		if val, ok := d.Attributes[attr]; ok {
+			vals = append(vals, val)
+		}
+	}
+	return vals
+}
+
+// checkNodeDeviceConstraint checks if a constraint or affinity whose LTarget
+// references a device attribute is satisfied by any of the device groups of
+// the node.
+func checkNodeDeviceConstraint(ctx Context, operand, lTarget, rTarget string, node *structs.Node) bool {
+	var rVal *psstructs.Attribute
+	rOk := true
+	if strings.HasPrefix(rTarget, "${") {
+		var r string
+		r, rOk = resolveTarget(rTarget, node)
+		rVal = psstructs.ParseAttribute(r)
+	} else if rTarget != "" {
+		rVal = psstructs.ParseAttribute(rTarget)
+	} else {
+		rOk = false
+	}
+
+	lVals := resolveNodeDeviceTarget(lTarget, node)
+	if len(lVals) == 0 {
+		return checkAttributeConstraint(ctx, operand, nil, rVal, false, rOk)
+	}
+	for _, lVal := range lVals {
+		if checkAttributeConstraint(ctx, operand, lVal, rVal, true, rOk) {
+			return true
+		}
+	}
+	return false
+}
+
 // checkConstraint checks if a constraint is satisfied. The lVal and rVal
 // interfaces may be nil.
 func checkConstraint(ctx Context, operand string, lVal, rVal interface{}, lFound, rFound bool) bool {
 	// Check for constraints not handled by this checker.
 	switch operand {
-	case structs.ConstraintDistinctHosts, structs.ConstraintDistinctProperty:
+	case structs.ConstraintDistinctHosts, structs.ConstraintDistinctProperty, structs.ConstraintDistinctTopology:
 		return true
 	default:
 		break
@@ -1354,7 +1539,7 @@ func resolveDeviceTarget(target string, d *structs.NodeDeviceResource) (*psstruc
 func checkAttributeConstraint(ctx Context, operand string, lVal, rVal *psstructs.Attribute, lFound, rFound bool) bool {
 	// Check for constraints not handled by this checker.
 	switch operand {
-	case structs.ConstraintDistinctHosts, structs.ConstraintDistinctProperty:
+	case structs.ConstraintDistinctHosts, structs.ConstraintDistinctProperty, structs.ConstraintDistinctTopology:
 		return true
 	default:
 		break
@@ -913,6 +913,89 @@ func TestConstraintChecker(t *testing.T) {
 	}
 }
 
+func TestConstraintChecker_DeviceAttributes(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+
+	// the mock device group has 11 GiB of memory
+	gpuNode := mock.NvidiaNode()
+	plainNode := mock.Node()
+
+	cases := []struct {
+		name       string
+		constraint *structs.Constraint
+		gpuNode    bool
+		plainNode  bool
+	}{
+		{
+			name: "greater with units",
+			constraint: &structs.Constraint{
+				Operand: ">=",
+				LTarget: "${device.attr.nvidia/gpu.memory}",
+				RTarget: "8 GiB",
+			},
+			gpuNode: true,
+		},
+		{
+			name: "greater with units unmet",
+			constraint: &structs.Constraint{
+				Operand: ">",
+				LTarget: "${device.attr.gpu.memory}",
+				RTarget: "12000 MiB",
+			},
+		},
+		{
+			name: "by model",
+			constraint: &structs.Constraint{
+				Operand: "<",
+				LTarget: "${device.attr.nvidia/gpu/1080ti.memory}",
+				RTarget: "16 GiB",
+			},
+			gpuNode: true,
+		},
+		{
+			name: "other model",
+			constraint: &structs.Constraint{
+				Operand: "<",
+				LTarget: "${device.attr.nvidia/gpu/2080ti.memory}",
+				RTarget: "16 GiB",
+			},
+		},
+		{
+			name: "is set",
+			constraint: &structs.Constraint{
+				Operand: structs.ConstraintAttributeIsSet,
+				LTarget: "${device.attr.gpu.cuda_cores}",
+			},
+			gpuNode: true,
+		},
+		{
+			name: "is not set",
+			constraint: &structs.Constraint{
+				Operand: structs.ConstraintAttributeIsNotSet,
+				LTarget: "${device.attr.gpu.cuda_cores}",
+			},
+			plainNode: true,
+		},
+		{
+			name: "missing attribute name",
+			constraint: &structs.Constraint{
+				Operand: structs.ConstraintAttributeIsSet,
+				LTarget: "${device.attr.gpu}",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := NewConstraintChecker(ctx, []*structs.Constraint{tc.constraint})
+			require.Equal(t, tc.gpuNode, checker.Feasible(gpuNode))
+			require.Equal(t, tc.plainNode, checker.Feasible(plainNode))
+		})
+	}
+}
+
 func TestResolveConstraintTarget(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1524,6 +1607,64 @@ func TestDistinctHostsIterator_TaskGroupDistinctHosts(t *testing.T) {
 // This test puts creates allocations across task groups that use a property
 // value to detect if the constraint at the job level properly considers all
 // task groups.
+func TestAntiAffinityIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+	static := NewStaticIterator(ctx, nodes)
+
+	tg := &structs.TaskGroup{
+		Name: "web",
+		AntiAffinities: []*structs.AntiAffinity{
+			{JobRegexp: "^cache-", Scope: structs.AntiAffinityScopeNode},
+		},
+	}
+	job := &structs.Job{
+		ID:         "frontend",
+		Namespace:  structs.DefaultNamespace,
+		TaskGroups: []*structs.TaskGroup{tg},
+	}
+
+	// A proposed allocation of a matching job makes node0 infeasible, while
+	// the allocations of the job itself, of non matching jobs and of other
+	// namespaces are ignored.
+	plan := ctx.Plan()
+	plan.NodeAllocation[nodes[0].ID] = []*structs.Allocation{
+		{Namespace: structs.DefaultNamespace, JobID: "cache-redis", ID: uuid.Generate()},
+	}
+	plan.NodeAllocation[nodes[1].ID] = []*structs.Allocation{
+		{Namespace: structs.DefaultNamespace, JobID: job.ID, ID: uuid.Generate()},
+		{Namespace: structs.DefaultNamespace, JobID: "api", ID: uuid.Generate()},
+	}
+	plan.NodeAllocation[nodes[2].ID] = []*structs.Allocation{
+		{Namespace: "other", JobID: "cache-redis", ID: uuid.Generate()},
+	}
+
+	// An existing allocation of a matching job makes node3 infeasible.
+	alloc := mock.Alloc()
+	alloc.JobID = "cache-memcached"
+	alloc.Job.ID = alloc.JobID
+	alloc.NodeID = nodes[3].ID
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	proposed := NewAntiAffinityIterator(ctx, static)
+	proposed.SetJob(job)
+	proposed.SetTaskGroup(tg)
+
+	out := collectFeasible(proposed)
+	require.Len(t, out, 3)
+	require.Equal(t, nodes[1].ID, out[0].ID)
+	require.Equal(t, nodes[2].ID, out[1].ID)
+	require.Equal(t, nodes[4].ID, out[2].ID)
+}
+
 func TestDistinctPropertyIterator_JobDistinctProperty(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2172,6 +2313,96 @@ func TestDistinctPropertyIterator_JobDistinctProperty_Infeasible_Count(t *testin
 	}
 }
 
+func TestDistinctPropertyIterator_DistinctTopology(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name     string
+		rTarget  string
+		expNodes []int
+	}{
+		{
+			// Rack names are only distinct within a datacenter
+			name:     "default count",
+			rTarget:  "",
+			expNodes: []int{1, 2},
+		},
+		{
+			name:     "rack count",
+			rTarget:  "2",
+			expNodes: []int{0, 1, 2},
+		},
+		{
+			name:     "datacenter count",
+			rTarget:  "1/*",
+			expNodes: []int{1},
+		},
+		{
+			name:     "datacenter and rack count",
+			rTarget:  "2/1",
+			expNodes: []int{1, 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, ctx := testContext(t)
+			nodes := []*structs.Node{
+				mock.Node(),
+				mock.Node(),
+				mock.Node(),
+			}
+			nodes[0].Datacenter, nodes[0].Meta["rack"] = "dc1", "r1"
+			nodes[1].Datacenter, nodes[1].Meta["rack"] = "dc2", "r1"
+			nodes[2].Datacenter, nodes[2].Meta["rack"] = "dc1", "r2"
+			for i, n := range nodes {
+				require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), n))
+			}
+
+			static := NewStaticIterator(ctx, nodes)
+
+			tg := &structs.TaskGroup{
+				Name: "web",
+				Constraints: []*structs.Constraint{{
+					Operand: structs.ConstraintDistinctTopology,
+					LTarget: "${node.datacenter}/${meta.rack}",
+					RTarget: tc.rTarget,
+				}},
+			}
+			job := &structs.Job{
+				ID:         "foo",
+				Namespace:  structs.DefaultNamespace,
+				TaskGroups: []*structs.TaskGroup{tg},
+			}
+
+			// Propose an allocation on the first node
+			ctx.Plan().NodeAllocation[nodes[0].ID] = []*structs.Allocation{{
+				Namespace: structs.DefaultNamespace,
+				TaskGroup: tg.Name,
+				JobID:     job.ID,
+				Job:       job,
+				ID:        uuid.Generate(),
+				NodeID:    nodes[0].ID,
+			}}
+
+			proposed := NewDistinctPropertyIterator(ctx, static)
+			proposed.SetJob(job)
+			proposed.SetTaskGroup(tg)
+			proposed.Reset()
+
+			var expected []string
+			for _, i := range tc.expNodes {
+				expected = append(expected, nodes[i].ID)
+			}
+			var out []string
+			for _, n := range collectFeasible(proposed) {
+				out = append(out, n.ID)
+			}
+			require.ElementsMatch(t, expected, out)
+		})
+	}
+}
+
 // This test creates previous allocations selecting certain property values to
 // test if it detects infeasibility of property values correctly and picks the
 // only feasible one when the constraint is at the task group.
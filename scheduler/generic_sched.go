@@ -59,6 +59,10 @@ const (
 	// that are a result of failing to place all allocations.
 	blockedEvalFailedPlacements = "created to place remaining allocations"
 
+	// deadlineMissedDescFmt is the description used for evals of jobs whose
+	// deadline passed while allocations could not be placed.
+	deadlineMissedDescFmt = "job deadline of %s missed: remaining allocations could not be placed"
+
 	// reschedulingFollowupEvalDesc is the description used when creating follow
 	// up evals for delayed rescheduling
 	reschedulingFollowupEvalDesc = "created for delayed rescheduling"
@@ -164,7 +168,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
 		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
-		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect:
+		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect,
+		structs.EvalTriggerRetry:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -205,15 +210,37 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		newEval.EscapedComputedClass = e.HasEscaped()
 		newEval.ClassEligibility = e.GetClasses()
 		newEval.QuotaLimitReached = e.QuotaLimitReached()
+		if desc := s.deadlineMissedDesc(); desc != "" {
+			s.logger.Warn("job deadline missed due to lack of capacity")
+			newEval.StatusDescription = desc
+		}
 		return s.planner.ReblockEval(newEval)
 	}
 
 	// Update the status to complete
+	desc := s.deadlineMissedDesc()
+	if desc != "" {
+		s.logger.Warn("job deadline missed due to lack of capacity")
+	}
 	return setStatus(s.logger, s.planner, s.eval, nil, s.blocked,
-		s.failedTGAllocs, structs.EvalStatusComplete, "", s.queuedAllocs,
+		s.failedTGAllocs, structs.EvalStatusComplete, desc, s.queuedAllocs,
 		s.deployment.GetID())
 }
 
+// deadlineMissedDesc returns a status description if the evaluation has a
+// deadline that has passed while allocations remain unplaced, or an empty
+// string otherwise.
+func (s *GenericScheduler) deadlineMissedDesc() string {
+	if s.eval.Deadline == 0 || len(s.failedTGAllocs) == 0 {
+		return ""
+	}
+	deadline := time.Unix(0, s.eval.Deadline).UTC()
+	if time.Now().Before(deadline) {
+		return ""
+	}
+	return fmt.Sprintf(deadlineMissedDescFmt, deadline.Format(time.RFC3339))
+}
+
 // createBlockedEval creates a blocked eval and submits it to the planner. If
 // failure is set to true, the eval's trigger reason reflects that.
 func (s *GenericScheduler) createBlockedEval(planFailure bool) error {
@@ -230,6 +257,8 @@ func (s *GenericScheduler) createBlockedEval(planFailure bool) error {
 	if planFailure {
 		s.blocked.TriggeredBy = structs.EvalTriggerMaxPlans
 		s.blocked.StatusDescription = blockedEvalMaxPlanDesc
+	} else if desc := s.deadlineMissedDesc(); desc != "" {
+		s.blocked.StatusDescription = desc
 	} else {
 		s.blocked.StatusDescription = blockedEvalFailedPlacements
 	}
@@ -275,6 +304,13 @@ func (s *GenericScheduler) process() (bool, error) {
 
 	// Construct the placement stack
 	s.stack = NewGenericStack(s.batch, s.ctx)
+	if s.eval.ExplainPlacements {
+		s.ctx.ExplainPlacements()
+		s.stack.ExplainPlacements()
+	}
+	if s.eval.Overrides != nil && s.eval.Overrides.IgnoreSoftConstraints {
+		s.stack.IgnoreSoftConstraints()
+	}
 	if !s.job.Stopped() {
 		s.stack.SetJob(s.job)
 	}
@@ -407,7 +443,7 @@ func (s *GenericScheduler) computeJobAllocs() error {
 
 	// Handle the stop
 	for _, stop := range results.stop {
-		s.plan.AppendStoppedAlloc(stop.alloc, stop.statusDescription, stop.clientStatus, stop.followupEvalID)
+		s.plan.AppendStoppedAlloc(stop.alloc, stop.statusDescription, stop.clientStatus, stop.followupEvalID, stop.stopReason)
 	}
 
 	// Handle disconnect updates
@@ -497,8 +533,13 @@ func (s *GenericScheduler) downgradedJobForPlacement(p placementResult) (string,
 // computePlacements computes placements for allocations. It is given the set of
 // destructive updates to place and the set of new placements to place.
 func (s *GenericScheduler) computePlacements(destructive, place []placementResult) error {
-	// Get the base nodes
-	nodes, _, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters)
+	// Get the base nodes, honoring any datacenters the evaluation was
+	// retried with
+	datacenters := s.job.Datacenters
+	if s.eval.Overrides != nil && len(s.eval.Overrides.Datacenters) > 0 {
+		datacenters = s.eval.Overrides.Datacenters
+	}
+	nodes, _, byDC, err := readyNodesInDCs(s.state, datacenters)
 	if err != nil {
 		return err
 	}
@@ -567,10 +608,11 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 			// placement of its replacement. This allow atomic placements/stops. We
 			// stop the allocation before trying to find a replacement because this
 			// frees the resources currently used by the previous allocation.
+			// Only destructive updates stop their previous allocation.
 			stopPrevAlloc, stopPrevAllocDesc := missing.StopPreviousAlloc()
 			prevAllocation := missing.PreviousAllocation()
 			if stopPrevAlloc {
-				s.plan.AppendStoppedAlloc(prevAllocation, stopPrevAllocDesc, "", "")
+				s.plan.AppendStoppedAlloc(prevAllocation, stopPrevAllocDesc, "", "", structs.AllocStopReasonJobUpdate)
 			}
 
 			// Compute penalty nodes for rescheduled allocs
@@ -584,6 +626,9 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 			// Compute top K scoring node metadata
 			s.ctx.Metrics().PopulateScoreMetaData()
 
+			// Explain the placement if requested
+			explainPlacement(s.plan, s.ctx.Metrics(), tg.Name, missing.Name(), option)
+
 			// Restore stack job now that placement is done, to use plan job version
 			if downgradedJob != nil {
 				s.stack.SetJob(s.job)
@@ -594,6 +639,7 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 				resources := &structs.AllocatedResources{
 					Tasks:          option.TaskResources,
 					TaskLifecycles: option.TaskLifecycles,
+					Overhead:       option.Overhead,
 					Shared: structs.AllocatedSharedResources{
 						DiskMB: int64(tg.EphemeralDisk.SizeMB),
 					},
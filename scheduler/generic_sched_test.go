@@ -108,6 +108,119 @@ func TestServiceSched_JobRegister(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_EvalRetry_DatacenterOverride(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create nodes in a datacenter the job does not target
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		node.Datacenter = "dc2"
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create a retried evaluation overriding the datacenters of the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerRetry,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+		Overrides:   &structs.EvalOverrides{Datacenters: []string{"dc2"}},
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+	require.Len(t, h.Plans, 1)
+
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	require.Len(t, planned, 10)
+	require.Empty(t, h.CreateEvals)
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
+func TestServiceSched_EvalRetry_IgnoreSoftConstraints(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, ignore := range []bool{false, true} {
+		t.Run(fmt.Sprintf("ignore %v", ignore), func(t *testing.T) {
+			h := NewHarness(t)
+
+			var nodes []*structs.Node
+			for i := 0; i < 10; i++ {
+				node := mock.Node()
+				nodes = append(nodes, node)
+				require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+			}
+
+			// Create a job with an affinity and a spread that score the
+			// nodes
+			job := mock.Job()
+			job.Affinities = []*structs.Affinity{{
+				LTarget: "${node.unique.id}",
+				RTarget: nodes[0].ID,
+				Operand: "=",
+				Weight:  50,
+			}}
+			job.TaskGroups[0].Spreads = []*structs.Spread{{
+				Attribute: "${node.datacenter}",
+				Weight:    100,
+				SpreadTarget: []*structs.SpreadTarget{
+					{Value: "dc1", Percent: 50},
+					{Value: "dc2", Percent: 50},
+				},
+			}}
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    job.Priority,
+				TriggeredBy: structs.EvalTriggerRetry,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+				Overrides:   &structs.EvalOverrides{IgnoreSoftConstraints: ignore},
+			}
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+			require.NoError(t, h.Process(NewServiceScheduler, eval))
+			require.Len(t, h.Plans, 1)
+
+			var planned []*structs.Allocation
+			for _, allocList := range h.Plans[0].NodeAllocation {
+				planned = append(planned, allocList...)
+			}
+			require.Len(t, planned, 10)
+
+			// Check whether the nodes were scored by the affinity and spread
+			var affinityScored, spreadScored bool
+			for _, alloc := range planned {
+				for _, scoreMeta := range alloc.Metrics.ScoreMetaData {
+					if scoreMeta.Scores["node-affinity"] != 0 {
+						affinityScored = true
+					}
+					if _, ok := scoreMeta.Scores["allocation-spread"]; ok {
+						spreadScored = true
+					}
+				}
+			}
+			require.Equal(t, !ignore, affinityScored)
+			require.Equal(t, !ignore, spreadScored)
+
+			h.AssertEvalStatus(t, structs.EvalStatusComplete)
+		})
+	}
+}
+
 func TestServiceSched_JobRegister_MemoryMaxHonored(t *testing.T) {
 	ci.Parallel(t)
 
@@ -391,6 +504,68 @@ func TestServiceSched_JobRegister_DiskConstraints(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_InterpolatedNodeMeta(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Only some of the nodes set the interpolated meta
+	racks := map[string]struct{}{}
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		if i%2 == 0 {
+			node.Meta["rack"] = fmt.Sprintf("r%d", i)
+			node.ComputeClass()
+			racks[node.ID] = struct{}{}
+		}
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 5
+	job.TaskGroups[0].Tasks[0].Meta["rack"] = "${meta.rack}"
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	ws := memdb.NewWatchSet()
+	out, err := h.State.AllocsByJob(ws, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, out, 5)
+	for _, alloc := range out {
+		require.Contains(t, racks, alloc.NodeID)
+	}
+
+	// Interpolating meta no node sets fails on the implicit constraint
+	job = mock.Job()
+	job.TaskGroups[0].Tasks[0].Env["ROOM"] = "${meta.room}"
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+	eval = &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	outEval := h.Evals[len(h.Evals)-1]
+	require.Len(t, outEval.FailedTGAllocs, 1)
+	metrics := outEval.FailedTGAllocs[job.TaskGroups[0].Name]
+	require.Equal(t, 10, metrics.NodesFiltered)
+}
+
 func TestServiceSched_JobRegister_DistinctHosts(t *testing.T) {
 	ci.Parallel(t)
 
@@ -574,6 +749,79 @@ func TestServiceSched_JobRegister_DistinctProperty(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_DistinctTopology(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create 3 nodes in each of 2 racks of 2 datacenters, reusing the rack
+	// names across datacenters
+	for _, dc := range []string{"dc1", "dc2"} {
+		for _, rack := range []string{"r1", "r2"} {
+			for i := 0; i < 3; i++ {
+				node := mock.Node()
+				node.Datacenter = dc
+				node.Meta["rack"] = rack
+				require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+			}
+		}
+	}
+
+	// Create a job allowing at most 2 allocations per rack and 1 per node,
+	// with a count higher than what is possible.
+	job := mock.Job()
+	job.Datacenters = []string{"dc1", "dc2"}
+	job.TaskGroups[0].Count = 10
+	job.Constraints = append(job.Constraints,
+		&structs.Constraint{
+			Operand: structs.ConstraintDistinctTopology,
+			LTarget: "${node.datacenter}/${meta.rack}/${node.unique.id}",
+			RTarget: "*/2/1",
+		})
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Ensure a single plan and a blocked eval for the remaining allocations
+	require.Len(t, h.Plans, 1)
+	require.Len(t, h.CreateEvals, 1)
+	require.Len(t, h.Evals[0].FailedTGAllocs, 1)
+
+	// Lookup the allocations by JobID
+	ws := memdb.NewWatchSet()
+	out, err := h.State.AllocsByJob(ws, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, out, 8)
+
+	// Ensure each rack was used twice and each node once
+	racks := make(map[string]int)
+	nodes := make(map[string]int)
+	for _, alloc := range out {
+		node, err := h.State.NodeByID(ws, alloc.NodeID)
+		require.NoError(t, err)
+		racks[node.Datacenter+"/"+node.Meta["rack"]]++
+		nodes[node.ID]++
+	}
+	require.Equal(t, map[string]int{"dc1/r1": 2, "dc1/r2": 2, "dc2/r1": 2, "dc2/r2": 2}, racks)
+	for id, count := range nodes {
+		require.Equal(t, 1, count, "node %s", id)
+	}
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestServiceSched_JobRegister_DistinctProperty_TaskGroup(t *testing.T) {
 	ci.Parallel(t)
 
@@ -910,6 +1158,73 @@ func TestServiceSched_EvenSpread(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_ExplainPlacements(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes, three of them not matching the job constraint
+	windows := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		if i < 3 {
+			node.Attributes["kernel.name"] = "windows"
+			node.ComputeClass()
+			windows[node.ID] = true
+		}
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:         structs.DefaultNamespace,
+		ID:                uuid.Generate(),
+		Priority:          job.Priority,
+		TriggeredBy:       structs.EvalTriggerJobRegister,
+		JobID:             job.ID,
+		AnnotatePlan:      true,
+		ExplainPlacements: true,
+		Status:            structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+	require.Len(t, h.Plans, 1)
+	plan := h.Plans[0]
+	require.NotNil(t, plan.Annotations)
+
+	// Ensure the placement is explained for every node
+	require.Len(t, plan.Annotations.PlacementExplanations, 1)
+	explanation := plan.Annotations.PlacementExplanations[0]
+	require.Equal(t, "web", explanation.TaskGroup)
+	require.Equal(t, structs.AllocName(job.ID, "web", 0), explanation.AllocName)
+	require.Len(t, explanation.Nodes, 10)
+
+	var planned []*structs.Allocation
+	for _, allocList := range plan.NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	require.Len(t, planned, 1)
+	require.Equal(t, planned[0].NodeID, explanation.NodeID)
+
+	for _, n := range explanation.Nodes {
+		if windows[n.NodeID] {
+			require.True(t, n.Rejected())
+			require.NotEmpty(t, n.FilteredBy)
+			continue
+		}
+		require.False(t, n.Rejected())
+		require.Contains(t, n.Scores, "binpack")
+		require.NotZero(t, n.NormScore)
+	}
+}
+
 func TestServiceSched_JobRegister_Annotate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4488,6 +4803,41 @@ func TestBatchSched_Run_FailedAllocQueuedAllocations(t *testing.T) {
 	}
 }
 
+func TestBatchSched_Run_DeadlineMissed(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create a batch job without any nodes to place it on
+	job := mock.Job()
+	job.Type = structs.JobTypeBatch
+	job.Deadline = time.Minute
+	job.SubmitTime = time.Now().Add(-time.Hour).UnixNano()
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		Deadline:    job.DeadlineTime(),
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewBatchScheduler, eval))
+
+	// Ensure both the eval and the blocked eval report the missed deadline
+	require.Len(t, h.Evals, 1)
+	require.Contains(t, h.Evals[0].StatusDescription, "job deadline")
+	require.Len(t, h.CreateEvals, 1)
+	require.Equal(t, eval.Deadline, h.CreateEvals[0].Deadline)
+	require.Contains(t, h.CreateEvals[0].StatusDescription, "job deadline")
+}
+
 func TestBatchSched_ReRun_SuccessfullyFinishedAlloc(t *testing.T) {
 	ci.Parallel(t)
 
@@ -12,14 +12,21 @@ import (
 // number of allocations being preempted exceeds max_parallel value in the job's migrate stanza
 const maxParallelPenalty = 50.0
 
+// spreadPenalty is a score penalty applied to allocations whose preemption would
+// worsen the spread of their task group. It is scaled by the relative weight of the
+// spreads that would get worse, so that allocations whose removal keeps their job
+// evenly spread are preferred among allocations using similar resources.
+const spreadPenalty = 1.0
+
 type groupedAllocs struct {
 	priority int
 	allocs   []*structs.Allocation
 }
 
 type allocInfo struct {
-	maxParallel int
-	resources   *structs.ComparableResources
+	maxParallel   int
+	spreadPenalty float64
+	resources     *structs.ComparableResources
 }
 
 // PreemptionResource interface is implemented by different
@@ -104,7 +111,8 @@ type Preemptor struct {
 	// when scoring it for preemption
 	allocDetails map[string]*allocInfo
 
-	// jobPriority is the priority of the job being preempted
+	// jobPriority is the priority of the job being placed, or of its task
+	// group if set
 	jobPriority int
 
 	// jobID is the ID of the job being preempted
@@ -114,6 +122,13 @@ type Preemptor struct {
 	// accounting for running allocations
 	nodeRemainingResources *structs.ComparableResources
 
+	// node is the node allocations are preempted from
+	node *structs.Node
+
+	// groupNodes caches the nodes running the allocations of the task groups
+	// of candidates with spreads, with one entry per allocation
+	groupNodes map[structs.NamespacedID]map[string][]*structs.Node
+
 	// currentAllocs is the candidate set used to find preemptible allocations
 	currentAllocs []*structs.Allocation
 
@@ -127,6 +142,7 @@ func NewPreemptor(jobPriority int, ctx Context, jobID *structs.NamespacedID) *Pr
 		jobPriority:        jobPriority,
 		jobID:              jobID,
 		allocDetails:       make(map[string]*allocInfo),
+		groupNodes:         make(map[structs.NamespacedID]map[string][]*structs.Node),
 		ctx:                ctx,
 	}
 }
@@ -140,6 +156,7 @@ func (p *Preemptor) SetNode(node *structs.Node) {
 		nodeRemainingResources.Subtract(c)
 	}
 	p.nodeRemainingResources = nodeRemainingResources
+	p.node = node
 }
 
 // SetCandidates initializes the candidate set from which preemptions are chosen
@@ -154,11 +171,15 @@ func (p *Preemptor) SetCandidates(allocs []*structs.Allocation) {
 		}
 
 		maxParallel := 0
+		penalty := 0.0
 		tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
-		if tg != nil && tg.Migrate != nil {
-			maxParallel = tg.Migrate.MaxParallel
+		if tg != nil {
+			if tg.Migrate != nil {
+				maxParallel = tg.Migrate.MaxParallel
+			}
+			penalty = p.spreadScorePenalty(alloc, tg)
 		}
-		p.allocDetails[alloc.ID] = &allocInfo{maxParallel: maxParallel, resources: alloc.ComparableResources()}
+		p.allocDetails[alloc.ID] = &allocInfo{maxParallel: maxParallel, spreadPenalty: penalty, resources: alloc.ComparableResources()}
 		p.currentAllocs = append(p.currentAllocs, alloc)
 	}
 }
@@ -182,6 +203,147 @@ func (p *Preemptor) SetPreemptions(allocs []*structs.Allocation) {
 	}
 }
 
+// spreadScorePenalty returns the score penalty for preempting the allocation from
+// the node, based on how many of the spreads of its task group would get worse.
+func (p *Preemptor) spreadScorePenalty(alloc *structs.Allocation, tg *structs.TaskGroup) float64 {
+	spreads := make([]*structs.Spread, 0, len(tg.Spreads)+len(alloc.Job.Spreads))
+	spreads = append(spreads, tg.Spreads...)
+	spreads = append(spreads, alloc.Job.Spreads...)
+	if len(spreads) == 0 || p.node == nil {
+		return 0
+	}
+
+	var sumWeights int
+	for _, spread := range spreads {
+		sumWeights += int(spread.Weight)
+	}
+	if sumWeights == 0 {
+		return 0
+	}
+
+	nodes, err := p.getGroupNodes(alloc)
+	if err != nil {
+		p.ctx.Logger().Named("preemption").Error("failed to look up allocations of task group",
+			"job_id", alloc.JobID, "task_group", alloc.TaskGroup, "error", err)
+		return 0
+	}
+
+	penalty := 0.0
+	for _, spread := range spreads {
+		value, ok := getProperty(p.node, spread.Attribute)
+		if !ok {
+			continue
+		}
+
+		counts := make(map[string]int)
+		for _, node := range nodes {
+			if v, ok := getProperty(node, spread.Attribute); ok {
+				counts[v]++
+			}
+		}
+		if spreadWorsened(spread, tg.Count, counts, value) {
+			penalty += spreadPenalty * float64(spread.Weight) / float64(sumWeights)
+		}
+	}
+	return penalty
+}
+
+// getGroupNodes returns the nodes of the running allocations of the task group
+// of the allocation, ignoring allocations already preempted by the plan.
+func (p *Preemptor) getGroupNodes(alloc *structs.Allocation) ([]*structs.Node, error) {
+	id := structs.NewNamespacedID(alloc.JobID, alloc.Namespace)
+	if nodes, ok := p.groupNodes[id][alloc.TaskGroup]; ok {
+		return nodes, nil
+	}
+
+	preempted := make(map[string]struct{})
+	for _, allocs := range p.ctx.Plan().NodePreemptions {
+		for _, a := range allocs {
+			preempted[a.ID] = struct{}{}
+		}
+	}
+
+	allocs, err := p.ctx.State().AllocsByJob(nil, alloc.Namespace, alloc.JobID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	nodesByID := make(map[string]*structs.Node)
+	var nodes []*structs.Node
+	for _, a := range allocs {
+		if a.TaskGroup != alloc.TaskGroup || a.TerminalStatus() {
+			continue
+		}
+		if _, ok := preempted[a.ID]; ok {
+			continue
+		}
+
+		node, ok := nodesByID[a.NodeID]
+		if !ok {
+			node, err = p.ctx.State().NodeByID(nil, a.NodeID)
+			if err != nil {
+				return nil, err
+			}
+			nodesByID[a.NodeID] = node
+		}
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+
+	if _, ok := p.groupNodes[id]; !ok {
+		p.groupNodes[id] = make(map[string][]*structs.Node)
+	}
+	p.groupNodes[id][alloc.TaskGroup] = nodes
+	return nodes, nil
+}
+
+// spreadWorsened returns whether removing an allocation from a node with the
+// given value of the spread attribute worsens the spread, given the number of
+// allocations of the task group per attribute value.
+func spreadWorsened(spread *structs.Spread, count int, counts map[string]int, value string) bool {
+	if len(spread.SpreadTarget) == 0 {
+		// With even spread, removing an allocation from the least used value
+		// increases the skew between values.
+		if len(counts) < 2 {
+			return false
+		}
+		used := counts[value]
+		for _, c := range counts {
+			if c < used {
+				return false
+			}
+		}
+		return true
+	}
+
+	// With targets, removing an allocation from a value that doesn't have
+	// more allocations than desired moves the spread away from its targets.
+	// Values without targets share the remaining percentage.
+	targets := make(map[string]float64, len(spread.SpreadTarget))
+	sumDesired := 0.0
+	for _, st := range spread.SpreadTarget {
+		desired := float64(st.Percent) / 100 * float64(count)
+		targets[st.Value] = desired
+		sumDesired += desired
+	}
+	if desired, ok := targets[value]; ok {
+		return float64(counts[value]) <= desired
+	}
+
+	remaining := float64(count) - sumDesired
+	if remaining <= 0 {
+		return false
+	}
+	used := 0
+	for v, c := range counts {
+		if _, ok := targets[v]; !ok {
+			used += c
+		}
+	}
+	return float64(used) <= remaining
+}
+
 // getNumPreemptions counts the number of other allocations being preempted that match the job and task group of
 // the alloc under consideration. This is used as a scoring factor to minimize too many allocs of the same job being preempted at once
 func (p *Preemptor) getNumPreemptions(alloc *structs.Allocation) int {
@@ -224,7 +386,7 @@ func (p *Preemptor) PreemptForTaskGroup(resourceAsk *structs.AllocatedResources)
 				currentPreemptionCount := p.getNumPreemptions(alloc)
 				allocDetails := p.allocDetails[alloc.ID]
 				maxParallel := allocDetails.maxParallel
-				distance := scoreForTaskGroup(resourcesNeeded, allocDetails.resources, maxParallel, currentPreemptionCount)
+				distance := scoreForTaskGroup(resourcesNeeded, allocDetails.resources, maxParallel, currentPreemptionCount) + allocDetails.spreadPenalty
 				if distance < bestDistance {
 					bestDistance = distance
 					closestAllocIndex = index
@@ -307,7 +469,7 @@ func (p *Preemptor) PreemptForNetwork(networkResourceAsk *structs.NetworkResourc
 		net := networks[0]
 
 		// Filter out alloc that's ineligible due to priority
-		if p.jobPriority-alloc.Job.Priority < 10 {
+		if p.jobPriority-alloc.PreemptionPriority() < 10 {
 			// Populate any reserved ports used by
 			// this allocation that cannot be preempted
 			for _, port := range net.ReservedPorts {
@@ -589,10 +751,11 @@ func selectBestAllocs(preemptionOptions []*deviceGroupAllocs, neededCount int) [
 			instanceCount := devInst[alloc.ID]
 			preemptedInstanceCount += instanceCount
 			filteredAllocs = append(filteredAllocs, alloc)
-			_, ok := priorities[alloc.Job.Priority]
+			priority := alloc.PreemptionPriority()
+			_, ok := priorities[priority]
 			if !ok {
-				priorities[alloc.Job.Priority] = struct{}{}
-				netPriority += alloc.Job.Priority
+				priorities[priority] = struct{}{}
+				netPriority += priority
 			}
 		}
 		if netPriority < bestPriority {
@@ -670,15 +833,16 @@ func filterAndGroupPreemptibleAllocs(jobPriority int, current []*structs.Allocat
 		// Skip allocs whose priority is within a delta of 10
 		// This also skips any allocs of the current job
 		// for which we are attempting preemption
-		if jobPriority-alloc.Job.Priority < 10 {
+		priority := alloc.PreemptionPriority()
+		if jobPriority-priority < 10 {
 			continue
 		}
-		grpAllocs, ok := allocsByPriority[alloc.Job.Priority]
+		grpAllocs, ok := allocsByPriority[priority]
 		if !ok {
 			grpAllocs = make([]*structs.Allocation, 0)
 		}
 		grpAllocs = append(grpAllocs, alloc)
-		allocsByPriority[alloc.Job.Priority] = grpAllocs
+		allocsByPriority[priority] = grpAllocs
 	}
 
 	var groupedSortedAllocs []*groupedAllocs
@@ -754,7 +918,8 @@ func (p *Preemptor) distanceComparatorForNetwork(allocs []*structs.Allocation, n
 		firstAllocNetResourceUsed = firstAllocNetworks[0]
 	}
 
-	distance1 := scoreForNetwork(firstAllocNetResourceUsed, networkResourceAsk, maxParallel1, currentPreemptionCount1)
+	distance1 := scoreForNetwork(firstAllocNetResourceUsed, networkResourceAsk, maxParallel1, currentPreemptionCount1) +
+		p.allocDetails[firstAlloc.ID].spreadPenalty
 
 	secondAlloc := allocs[j]
 	currentPreemptionCount2 := p.getNumPreemptions(secondAlloc)
@@ -771,6 +936,7 @@ func (p *Preemptor) distanceComparatorForNetwork(allocs []*structs.Allocation, n
 		secondAllocNetResourceUsed = secondAllocNetworks[0]
 	}
 
-	distance2 := scoreForNetwork(secondAllocNetResourceUsed, networkResourceAsk, maxParallel2, currentPreemptionCount2)
+	distance2 := scoreForNetwork(secondAllocNetResourceUsed, networkResourceAsk, maxParallel2, currentPreemptionCount2) +
+		p.allocDetails[secondAlloc.ID].spreadPenalty
 	return distance1 < distance2
 }
@@ -153,6 +153,7 @@ func TestPreemption(t *testing.T) {
 		nodeCapacity         *structs.NodeResources
 		resourceAsk          *structs.Resources
 		jobPriority          int
+		groupPriority        int
 		currentPreemptions   []*structs.Allocation
 		preemptedAllocIDs    map[string]struct{}
 	}
@@ -166,6 +167,10 @@ func TestPreemption(t *testing.T) {
 	lowPrioJob2 := mock.Job()
 	lowPrioJob2.Priority = 40
 
+	lowPrioGroupJob := mock.Job()
+	lowPrioGroupJob.Priority = 100
+	lowPrioGroupJob.TaskGroups[0].Priority = 20
+
 	// Create some persistent alloc ids to use in test cases
 	allocIDs := []string{uuid.Generate(), uuid.Generate(), uuid.Generate(), uuid.Generate(), uuid.Generate(), uuid.Generate()}
 
@@ -1326,6 +1331,44 @@ func TestPreemption(t *testing.T) {
 				allocIDs[1]: {},
 			},
 		},
+		{
+			desc: "Preempting allocs of a low priority task group of a high priority job",
+			currentAllocations: []*structs.Allocation{
+				createAlloc(allocIDs[0], lowPrioGroupJob, &structs.Resources{
+					CPU:      3200,
+					MemoryMB: 7256,
+					DiskMB:   4 * 1024,
+				})},
+			nodeReservedCapacity: reservedNodeResources,
+			nodeCapacity:         defaultNodeResources,
+			jobPriority:          50,
+			resourceAsk: &structs.Resources{
+				CPU:      2000,
+				MemoryMB: 256,
+				DiskMB:   4 * 1024,
+			},
+			preemptedAllocIDs: map[string]struct{}{
+				allocIDs[0]: {},
+			},
+		},
+		{
+			desc: "No preemption because the task group priority is not high enough",
+			currentAllocations: []*structs.Allocation{
+				createAlloc(allocIDs[0], lowPrioJob2, &structs.Resources{
+					CPU:      3200,
+					MemoryMB: 7256,
+					DiskMB:   4 * 1024,
+				})},
+			nodeReservedCapacity: reservedNodeResources,
+			nodeCapacity:         defaultNodeResources,
+			jobPriority:          100,
+			groupPriority:        45,
+			resourceAsk: &structs.Resources{
+				CPU:      2000,
+				MemoryMB: 256,
+				DiskMB:   4 * 1024,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1360,6 +1403,7 @@ func TestPreemption(t *testing.T) {
 
 			taskGroup := &structs.TaskGroup{
 				EphemeralDisk: &structs.EphemeralDisk{},
+				Priority:      tc.groupPriority,
 				Tasks: []*structs.Task{
 					{
 						Name:      "web",
@@ -1385,6 +1429,134 @@ func TestPreemption(t *testing.T) {
 	}
 }
 
+// TestPreemption_Spread asserts that preemption prefers allocations whose
+// removal doesn't worsen the spread of their job.
+func TestPreemption_Spread(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+
+		// spreadDCs are the datacenters of the other allocations of the job
+		// with a spread
+		spreadDCs []string
+
+		// expectSpread is true if the allocation of the job with a spread
+		// should be preempted
+		expectSpread bool
+	}{
+		{
+			name:         "no other allocs",
+			expectSpread: true,
+		},
+		{
+			name:         "worsens spread",
+			spreadDCs:    []string{"dc2"},
+			expectSpread: false,
+		},
+		{
+			name:         "improves spread",
+			spreadDCs:    []string{"dc1", "dc2"},
+			expectSpread: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, ctx := testContext(t)
+
+			node := mock.Node()
+			node.NodeResources.Cpu.CpuShares = 2300
+			node.ReservedResources = nil
+			require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+			// The allocation of the job with a spread is the closest to the
+			// resources asked for.
+			spreadJob := mock.Job()
+			spreadJob.Priority = 30
+			spreadJob.Spreads = []*structs.Spread{{
+				Attribute: "${node.datacenter}",
+				Weight:    100,
+			}}
+			spreadAlloc := createAlloc(uuid.Generate(), spreadJob, &structs.Resources{CPU: 1000, MemoryMB: 256})
+			spreadAlloc.NodeID = node.ID
+
+			otherJob := mock.Job()
+			otherJob.Priority = 30
+			otherAlloc := createAlloc(uuid.Generate(), otherJob, &structs.Resources{CPU: 1200, MemoryMB: 256})
+			otherAlloc.NodeID = node.ID
+
+			allocs := []*structs.Allocation{spreadAlloc, otherAlloc}
+			for i, dc := range tc.spreadDCs {
+				other := mock.Node()
+				other.Datacenter = dc
+				require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(1001+i), other))
+
+				alloc := createAlloc(uuid.Generate(), spreadJob, &structs.Resources{CPU: 1000, MemoryMB: 256})
+				alloc.NodeID = other.ID
+				allocs = append(allocs, alloc)
+			}
+			require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1010, allocs))
+
+			static := NewStaticRankIterator(ctx, []*RankedNode{{Node: node}})
+			binPackIter := NewBinPackIterator(ctx, static, true, 100, testSchedulerConfig)
+			job := mock.Job()
+			job.Priority = 100
+			binPackIter.SetJob(job)
+			binPackIter.SetTaskGroup(&structs.TaskGroup{
+				EphemeralDisk: &structs.EphemeralDisk{},
+				Tasks: []*structs.Task{{
+					Name:      "web",
+					Resources: &structs.Resources{CPU: 1000, MemoryMB: 256},
+				}},
+			})
+
+			option := binPackIter.Next()
+			require.NotNil(t, option)
+			require.Len(t, option.PreemptedAllocs, 1)
+			if tc.expectSpread {
+				require.Equal(t, spreadAlloc.ID, option.PreemptedAllocs[0].ID)
+			} else {
+				require.Equal(t, otherAlloc.ID, option.PreemptedAllocs[0].ID)
+			}
+		})
+	}
+}
+
+func TestPreemption_spreadWorsened(t *testing.T) {
+	ci.Parallel(t)
+
+	targets := &structs.Spread{
+		Attribute: "${node.datacenter}",
+		SpreadTarget: []*structs.SpreadTarget{
+			{Value: "dc1", Percent: 50},
+			{Value: "dc2", Percent: 25},
+		},
+	}
+
+	cases := []struct {
+		name   string
+		spread *structs.Spread
+		counts map[string]int
+		value  string
+		exp    bool
+	}{
+		{"even single value", &structs.Spread{}, map[string]int{"dc1": 3}, "dc1", false},
+		{"even least used", &structs.Spread{}, map[string]int{"dc1": 2, "dc2": 2}, "dc1", true},
+		{"even most used", &structs.Spread{}, map[string]int{"dc1": 3, "dc2": 2}, "dc1", false},
+		{"target below desired", targets, map[string]int{"dc1": 2, "dc2": 2}, "dc1", true},
+		{"target above desired", targets, map[string]int{"dc1": 1, "dc2": 2}, "dc2", false},
+		{"implicit target below desired", targets, map[string]int{"dc1": 2, "dc3": 1}, "dc3", true},
+		{"implicit target above desired", targets, map[string]int{"dc3": 1, "dc4": 1}, "dc4", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, spreadWorsened(tc.spread, 4, tc.counts, tc.value))
+		})
+	}
+}
+
 // TestPreemptionMultiple tests evicting multiple allocations in the same time
 func TestPreemptionMultiple(t *testing.T) {
 	ci.Parallel(t)
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
@@ -30,6 +31,14 @@ type propertySet struct {
 	// targetAttribute is the attribute this property set is checking
 	targetAttribute string
 
+	// targetLevels are the levels of the topology this property set is
+	// checking for a distinct_topology constraint. The value of the property
+	// is the combination of the values of the levels.
+	targetLevels []string
+
+	// operand is the operand of the constraint, used in explanations
+	operand string
+
 	// allowedCount is the allowed number of allocations that can have the
 	// distinct property
 	allowedCount uint64
@@ -58,6 +67,7 @@ func NewPropertySet(ctx Context, job *structs.Job) *propertySet {
 		ctx:            ctx,
 		jobID:          job.ID,
 		namespace:      job.Namespace,
+		operand:        structs.ConstraintDistinctProperty,
 		existingValues: make(map[string]uint64),
 		logger:         ctx.Logger().Named("property_set"),
 	}
@@ -97,6 +107,16 @@ func (p *propertySet) setConstraint(constraint *structs.Constraint, taskGroup st
 	p.setTargetAttributeWithCount(constraint.LTarget, allowedCount, taskGroup)
 }
 
+// SetTopologyConstraint is used to parameterize the property set for a level of
+// a distinct_topology constraint. The levels are those of the topology down to
+// and including the constrained level, and taskGroup is empty for a job level
+// constraint.
+func (p *propertySet) SetTopologyConstraint(levels []string, allowedCount uint64, taskGroup string) {
+	p.operand = structs.ConstraintDistinctTopology
+	p.targetLevels = levels
+	p.setTargetAttributeWithCount(strings.Join(levels, "/"), allowedCount, taskGroup)
+}
+
 // SetTargetAttribute is used to populate this property set without also storing allowed count
 // This is used when evaluating spread stanzas
 func (p *propertySet) SetTargetAttribute(targetAttribute string, taskGroup string) {
@@ -222,7 +242,7 @@ func (p *propertySet) SatisfiesDistinctProperties(option *structs.Node, tg strin
 		return true, ""
 	}
 
-	return false, fmt.Sprintf("distinct_property: %s=%s used by %d allocs", p.targetAttribute, nValue, usedCount)
+	return false, fmt.Sprintf("%s: %s=%s used by %d allocs", p.operand, p.targetAttribute, nValue, usedCount)
 }
 
 // UsedCount returns the number of times the value of the attribute being tracked by this
@@ -235,7 +255,7 @@ func (p *propertySet) UsedCount(option *structs.Node, tg string) (string, string
 	}
 
 	// Get the nodes property value
-	nValue, ok := getProperty(option, p.targetAttribute)
+	nValue, ok := p.getNodeProperty(option)
 	if !ok {
 		return nValue, fmt.Sprintf("missing property %q", p.targetAttribute), 0
 	}
@@ -327,7 +347,7 @@ func (p *propertySet) populateProperties(allocs []*structs.Allocation, nodes map
 	properties map[string]uint64) {
 
 	for _, alloc := range allocs {
-		nProperty, ok := getProperty(nodes[alloc.NodeID], p.targetAttribute)
+		nProperty, ok := p.getNodeProperty(nodes[alloc.NodeID])
 		if !ok {
 			continue
 		}
@@ -336,6 +356,24 @@ func (p *propertySet) populateProperties(allocs []*structs.Allocation, nodes map
 	}
 }
 
+// getNodeProperty is used to lookup the value of the property being tracked on
+// the node. For a topology, it is the values of its levels joined by slashes.
+func (p *propertySet) getNodeProperty(n *structs.Node) (string, bool) {
+	if len(p.targetLevels) == 0 {
+		return getProperty(n, p.targetAttribute)
+	}
+
+	values := make([]string, len(p.targetLevels))
+	for i, level := range p.targetLevels {
+		v, ok := getProperty(n, level)
+		if !ok {
+			return "", false
+		}
+		values[i] = v
+	}
+	return strings.Join(values, "/"), true
+}
+
 // getProperty is used to lookup the property value on the node
 func getProperty(n *structs.Node, property string) (string, bool) {
 	if n == nil || property == "" {
@@ -26,6 +26,10 @@ type RankedNode struct {
 	TaskLifecycles map[string]*structs.TaskLifecycleConfig
 	AllocResources *structs.AllocatedSharedResources
 
+	// Overhead is the resource overhead the task drivers on the node add to
+	// the task group being placed.
+	Overhead structs.AllocatedOverheadResources
+
 	// Proposed is used to cache the proposed allocations on the
 	// node. This can be shared between iterators that require it.
 	Proposed []*structs.Allocation
@@ -153,10 +157,15 @@ type BinPackIterator struct {
 	source                 RankIterator
 	evict                  bool
 	priority               int
+	jobPriority            int
 	jobId                  structs.NamespacedID
 	taskGroup              *structs.TaskGroup
 	memoryOversubscription bool
 	scoreFit               func(*structs.Node, *structs.ComparableResources) float64
+
+	// headroom is the share of the nodes kept free for the jobs it exempts
+	headroom       structs.ReservedHeadroomConfig
+	headroomExempt bool
 }
 
 // NewBinPackIterator returns a BinPackIterator which tries to fit tasks
@@ -174,20 +183,32 @@ func NewBinPackIterator(ctx Context, source RankIterator, evict bool, priority i
 		source:                 source,
 		evict:                  evict,
 		priority:               priority,
+		jobPriority:            priority,
 		memoryOversubscription: schedConfig != nil && schedConfig.MemoryOversubscriptionEnabled,
 		scoreFit:               scoreFn,
 	}
+	if schedConfig != nil {
+		iter.headroom = schedConfig.ReservedHeadroom
+	}
 	iter.ctx.Logger().Named("binpack").Trace("NewBinPackIterator created", "algorithm", algorithm)
 	return iter
 }
 
 func (iter *BinPackIterator) SetJob(job *structs.Job) {
 	iter.priority = job.Priority
+	iter.jobPriority = job.Priority
 	iter.jobId = job.NamespacedID()
+	iter.headroomExempt = iter.headroom.Exempts(job)
 }
 
 func (iter *BinPackIterator) SetTaskGroup(taskGroup *structs.TaskGroup) {
 	iter.taskGroup = taskGroup
+
+	// The task group priority overrides the job priority for preemption
+	iter.priority = iter.jobPriority
+	if taskGroup.Priority != 0 {
+		iter.priority = taskGroup.Priority
+	}
 }
 
 func (iter *BinPackIterator) Next() *RankedNode {
@@ -228,7 +249,7 @@ OUTER:
 				Allocations: make([]*structs.Allocation, len(proposed)),
 			}
 			for i, alloc := range proposed {
-				event.Allocations[i] = alloc.Copy()
+				event.Allocations[i] = alloc.CopySkipJob()
 			}
 			iter.ctx.SendEvent(event)
 			iter.ctx.Metrics().ExhaustedNode(option.Node, "network: port collision")
@@ -495,7 +516,9 @@ OUTER:
 			// Accumulate the total resource requirement
 			total.Tasks[task.Name] = taskResources
 			total.TaskLifecycles[task.Name] = task.Lifecycle
+			total.Overhead.Add(option.Node.DriverOverhead(task.Driver))
 		}
+		option.Overhead = total.Overhead
 
 		// Store current set of running allocs before adding resources for the task group
 		current := proposed
@@ -531,6 +554,12 @@ OUTER:
 		}
 		if len(allocsToPreempt) > 0 {
 			option.PreemptedAllocs = allocsToPreempt
+		} else if !iter.headroomExempt {
+			// Skip the node if the placement would use its reserved headroom
+			if dim, ok := iter.withinHeadroom(option.Node, util); !ok {
+				iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
+				continue
+			}
 		}
 
 		// Score the fit normally otherwise
@@ -550,6 +579,27 @@ OUTER:
 	}
 }
 
+// withinHeadroom returns whether the utilization leaves the reserved headroom
+// of the node free, and otherwise the exhausted dimension.
+func (iter *BinPackIterator) withinHeadroom(node *structs.Node, util *structs.ComparableResources) (string, bool) {
+	pct := iter.headroom.NodePercent(node)
+	if pct <= 0 {
+		return "", true
+	}
+
+	available := node.ComparableResources()
+	available.Subtract(node.ComparableReservedResources())
+	usable := int64(100 - pct)
+
+	if util.Flattened.Cpu.CpuShares > available.Flattened.Cpu.CpuShares*usable/100 {
+		return "reserved headroom: cpu", false
+	}
+	if util.Flattened.Memory.MemoryMB > available.Flattened.Memory.MemoryMB*usable/100 {
+		return "reserved headroom: memory", false
+	}
+	return "", true
+}
+
 func (iter *BinPackIterator) Reset() {
 	iter.source.Reset()
 }
@@ -750,6 +800,11 @@ func (iter *NodeAffinityIterator) Next() *RankedNode {
 
 func matchesAffinity(ctx Context, affinity *structs.Affinity, option *structs.Node) bool {
 	//TODO(preetha): Add a step here that filters based on computed node class for potential speedup
+	// Device attributes are typed and compared with their units
+	if isNodeDeviceTarget(affinity.LTarget) {
+		return checkNodeDeviceConstraint(ctx, affinity.Operand, affinity.LTarget, affinity.RTarget, option)
+	}
+
 	// Resolve the targets
 	lVal, lOk := resolveTarget(affinity.LTarget, option)
 	rVal, rOk := resolveTarget(affinity.RTarget, option)
@@ -836,10 +891,11 @@ func netPriority(allocs []*structs.Allocation) float64 {
 	sumPriority := 0
 	max := 0.0
 	for _, alloc := range allocs {
-		if float64(alloc.Job.Priority) > max {
-			max = float64(alloc.Job.Priority)
+		priority := alloc.PreemptionPriority()
+		if float64(priority) > max {
+			max = float64(priority)
 		}
-		sumPriority += alloc.Job.Priority
+		sumPriority += priority
 	}
 	// We use the maximum priority across all allocations
 	// with an additional penalty that increases proportional to the
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	psstructs "github.com/hashicorp/nomad/plugins/shared/structs"
 	"github.com/stretchr/testify/require"
 )
 
@@ -133,6 +134,127 @@ func TestBinPackIterator_NoExistingAlloc(t *testing.T) {
 	}
 }
 
+// TestBinPackIterator_ReservedHeadroom asserts that placements only use the
+// reserved headroom of the nodes for exempted jobs.
+func TestBinPackIterator_ReservedHeadroom(t *testing.T) {
+	newNode := func(class string) *RankedNode {
+		return &RankedNode{
+			Node: &structs.Node{
+				NodeClass: class,
+				NodeResources: &structs.NodeResources{
+					Cpu:    structs.NodeCpuResources{CpuShares: 2000},
+					Memory: structs.NodeMemoryResources{MemoryMB: 2000},
+				},
+				ReservedResources: &structs.NodeReservedResources{},
+			},
+		}
+	}
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1900,
+					MemoryMB: 1000,
+				},
+			},
+		},
+	}
+
+	schedConfig := &structs.SchedulerConfiguration{
+		ReservedHeadroom: structs.ReservedHeadroomConfig{
+			Percent:           10,
+			NodeClassPercent:  map[string]int{"batch": 0},
+			PriorityThreshold: 70,
+		},
+	}
+
+	cases := []struct {
+		name     string
+		jobType  string
+		priority int
+		placed   []string
+	}{
+		{name: "routine job", jobType: structs.JobTypeService, priority: 50, placed: []string{"batch"}},
+		{name: "high priority job", jobType: structs.JobTypeService, priority: 70, placed: []string{"", "batch"}},
+		{name: "system job", jobType: structs.JobTypeSystem, priority: 50, placed: []string{"", "batch"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ctx := testContext(t)
+			static := NewStaticRankIterator(ctx, []*RankedNode{newNode(""), newNode("batch")})
+
+			job := mock.Job()
+			job.Type = tc.jobType
+			job.Priority = tc.priority
+
+			binp := NewBinPackIterator(ctx, static, false, 0, schedConfig)
+			binp.SetJob(job)
+			binp.SetTaskGroup(taskGroup)
+
+			out := collectRanked(binp)
+			classes := []string{}
+			for _, option := range out {
+				classes = append(classes, option.Node.NodeClass)
+			}
+			require.Equal(t, tc.placed, classes)
+		})
+	}
+}
+
+// TestBinPackIterator_DriverOverhead asserts that the overhead reported by a
+// task driver is added to the resources required on a node.
+func TestBinPackIterator_DriverOverhead(t *testing.T) {
+	_, ctx := testContext(t)
+	newNode := func(overhead string) *RankedNode {
+		return &RankedNode{
+			Node: &structs.Node{
+				Attributes: map[string]string{
+					structs.DriverOverheadMemoryAttr("qemu"): overhead,
+				},
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares: 2048,
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 2048,
+					},
+				},
+			},
+		}
+	}
+	nodes := []*RankedNode{
+		// Overloaded once the overhead is included
+		newNode("1024"),
+		newNode("512"),
+	}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name:   "vm",
+				Driver: "qemu",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1536,
+				},
+			},
+		},
+	}
+	binp := NewBinPackIterator(ctx, static, false, 0, testSchedulerConfig)
+	binp.SetTaskGroup(taskGroup)
+
+	out := collectRanked(binp)
+	require.Len(t, out, 1)
+	require.Equal(t, nodes[1], out[0])
+	require.Equal(t, structs.AllocatedOverheadResources{MemoryMB: 512}, out[0].Overhead)
+	require.Equal(t, 1, ctx.metrics.DimensionExhausted["memory"])
+}
+
 // TestBinPackIterator_NoExistingAlloc_MixedReserve asserts that node's with
 // reserved resources are scored equivalent to as if they had a lower amount of
 // resources.
@@ -2123,6 +2245,44 @@ func TestScoreNormalizationIterator(t *testing.T) {
 	require.Equal(out[1].FinalScore, 0.0)
 }
 
+func TestNodeAffinityIterator_DeviceAttributes(t *testing.T) {
+	_, ctx := testContext(t)
+
+	large := mock.NvidiaNode()
+	small := mock.NvidiaNode()
+	small.NodeResources.Devices[0].Attributes["memory"] = psstructs.NewIntAttribute(4, psstructs.UnitGiB)
+	nodes := []*RankedNode{
+		{Node: large},
+		{Node: small},
+		{Node: mock.Node()},
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Affinities = []*structs.Affinity{{
+		Operand: ">=",
+		LTarget: "${device.attr.nvidia/gpu.memory}",
+		RTarget: "8192 MiB",
+		Weight:  50,
+	}}
+
+	static := NewStaticRankIterator(ctx, nodes)
+	nodeAffinity := NewNodeAffinityIterator(ctx, static)
+	nodeAffinity.SetTaskGroup(tg)
+	scoreNorm := NewScoreNormalizationIterator(ctx, nodeAffinity)
+
+	expectedScores := map[string]float64{
+		large.ID:         1.0,
+		small.ID:         0,
+		nodes[2].Node.ID: 0,
+	}
+	out := collectRanked(scoreNorm)
+	require.Len(t, out, 3)
+	for _, n := range out {
+		require.Equal(t, expectedScores[n.Node.ID], n.FinalScore)
+	}
+}
+
 func TestNodeAffinityIterator(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*RankedNode{
@@ -256,7 +256,9 @@ func (a *allocReconciler) computeDeploymentUpdates(deploymentComplete bool) {
 	// Set the description of a created deployment
 	if d := a.result.deployment; d != nil {
 		if d.RequiresPromotion() {
-			if d.HasAutoPromote() {
+			if d.HasAutoPromote() && d.HasPromotionGate() {
+				d.StatusDescription = structs.DeploymentStatusDescriptionRunningPromotionGate
+			} else if d.HasAutoPromote() {
 				d.StatusDescription = structs.DeploymentStatusDescriptionRunningAutoPromotion
 			} else {
 				d.StatusDescription = structs.DeploymentStatusDescriptionRunningNeedsPromotion
@@ -336,49 +338,74 @@ func (a *allocReconciler) handleStop(m allocMatrix) {
 	for group, as := range m {
 		as = filterByTerminal(as)
 		desiredChanges := new(structs.DesiredUpdates)
-		desiredChanges.Stop = a.filterAndStopAll(as)
+		desiredChanges.Stop = a.filterAndStopAll(as, structs.AllocStopReasonUserStop)
 		a.result.desiredTGUpdates[group] = desiredChanges
 	}
 }
 
 // filterAndStopAll stops all allocations in an allocSet. This is useful in when
-// stopping an entire job or task group.
-func (a *allocReconciler) filterAndStopAll(set allocSet) uint64 {
+// stopping an entire job or task group. Allocations that aren't lost are
+// stopped with the given stop reason.
+func (a *allocReconciler) filterAndStopAll(set allocSet, stopReason string) uint64 {
 	untainted, migrate, lost, disconnecting, reconnecting, ignore := set.filterByTainted(a.taintedNodes, a.supportsDisconnectedClients, a.now)
-	a.markStop(untainted, "", allocNotNeeded)
-	a.markStop(migrate, "", allocNotNeeded)
-	a.markStop(lost, structs.AllocClientStatusLost, allocLost)
-	a.markStop(disconnecting, "", allocNotNeeded)
-	a.markStop(reconnecting, "", allocNotNeeded)
-	a.markStop(ignore.filterByClientStatus(structs.AllocClientStatusUnknown), "", allocNotNeeded)
+	a.markStop(untainted, "", allocNotNeeded, stopReason)
+	a.markStop(migrate, "", allocNotNeeded, stopReason)
+	a.markStop(lost, structs.AllocClientStatusLost, allocLost, structs.AllocStopReasonLost)
+	a.markStop(disconnecting, "", allocNotNeeded, stopReason)
+	a.markStop(reconnecting, "", allocNotNeeded, stopReason)
+	a.markStop(ignore.filterByClientStatus(structs.AllocClientStatusUnknown), "", allocNotNeeded, stopReason)
 	return uint64(len(set))
 }
 
 // markStop is a helper for marking a set of allocation for stop with a
-// particular client status and description.
-func (a *allocReconciler) markStop(allocs allocSet, clientStatus, statusDescription string) {
+// particular client status, description and stop reason.
+func (a *allocReconciler) markStop(allocs allocSet, clientStatus, statusDescription, stopReason string) {
 	for _, alloc := range allocs {
 		a.result.stop = append(a.result.stop, allocStopResult{
 			alloc:             alloc,
 			clientStatus:      clientStatus,
 			statusDescription: statusDescription,
+			stopReason:        stopReason,
 		})
 	}
 }
 
 // markDelayed does markStop, but optionally includes a FollowupEvalID so that we can update
 // the stopped alloc with its delayed rescheduling evalID
-func (a *allocReconciler) markDelayed(allocs allocSet, clientStatus, statusDescription string, followupEvals map[string]string) {
+func (a *allocReconciler) markDelayed(allocs allocSet, clientStatus, statusDescription, stopReason string, followupEvals map[string]string) {
 	for _, alloc := range allocs {
 		a.result.stop = append(a.result.stop, allocStopResult{
 			alloc:             alloc,
 			clientStatus:      clientStatus,
 			statusDescription: statusDescription,
+			stopReason:        stopReason,
 			followupEvalID:    followupEvals[alloc.ID],
 		})
 	}
 }
 
+// markMigrating is a helper for marking a set of allocations to be stopped
+// because they are migrated.
+func (a *allocReconciler) markMigrating(allocs allocSet) {
+	for _, alloc := range allocs {
+		a.result.stop = append(a.result.stop, allocStopResult{
+			alloc:             alloc,
+			statusDescription: allocMigrating,
+			stopReason:        a.migrateStopReason(alloc),
+		})
+	}
+}
+
+// migrateStopReason returns the stop reason of an allocation that is
+// migrated. Allocations are migrated when their node is drained or when they
+// are stopped by a user.
+func (a *allocReconciler) migrateStopReason(alloc *structs.Allocation) string {
+	if node, ok := a.taintedNodes[alloc.NodeID]; ok && node != nil && node.DrainStrategy != nil {
+		return structs.AllocStopReasonDrain
+	}
+	return structs.AllocStopReasonUserStop
+}
+
 // computeGroup reconciles state for a particular task group. It returns whether
 // the deployment it is for is complete with regards to the task group.
 func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
@@ -393,7 +420,7 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 	// If the task group is nil, then the task group has been removed so all we
 	// need to do is stop everything
 	if tg == nil {
-		desiredChanges.Stop = a.filterAndStopAll(all)
+		desiredChanges.Stop = a.filterAndStopAll(all, structs.AllocStopReasonJobUpdate)
 		return true
 	}
 
@@ -521,6 +548,7 @@ func (a *allocReconciler) initializeDeploymentState(group string, tg *structs.Ta
 		if !tg.Update.IsEmpty() {
 			dstate.AutoRevert = tg.Update.AutoRevert
 			dstate.AutoPromote = tg.Update.AutoPromote
+			dstate.PromotionGate = tg.Update.PromotionGate
 			dstate.ProgressDeadline = tg.Update.ProgressDeadline
 		}
 	}
@@ -605,7 +633,7 @@ func (a *allocReconciler) cancelUnneededCanaries(original allocSet, desiredChang
 	// stopSet is the allocSet that contains the canaries we desire to stop from
 	// above.
 	stopSet := all.fromKeys(stop)
-	a.markStop(stopSet, "", allocNotNeeded)
+	a.markStop(stopSet, "", allocNotNeeded, structs.AllocStopReasonJobUpdate)
 	desiredChanges.Stop += uint64(len(stopSet))
 	all = all.difference(stopSet)
 
@@ -622,8 +650,8 @@ func (a *allocReconciler) cancelUnneededCanaries(original allocSet, desiredChang
 		// We don't add these stops to desiredChanges because the deployment is
 		// still active. DesiredChanges is used to report deployment progress/final
 		// state. These transient failures aren't meaningful.
-		a.markStop(migrate, "", allocMigrating)
-		a.markStop(lost, structs.AllocClientStatusLost, allocLost)
+		a.markMigrating(migrate)
+		a.markStop(lost, structs.AllocClientStatusLost, allocLost, structs.AllocStopReasonLost)
 
 		canaries = untainted
 		all = all.difference(migrate, lost)
@@ -763,7 +791,7 @@ func (a *allocReconciler) computeReplacements(deploymentPlaceReady bool, desired
 		// turn relies on len(lostLater) == 0.
 		a.result.place = append(a.result.place, place...)
 
-		a.markStop(failed, "", allocRescheduled)
+		a.markStop(failed, "", allocRescheduled, structs.AllocStopReasonFailed)
 		desiredChanges.Stop += uint64(len(failed))
 
 		min := helper.IntMin(len(place), underProvisionedBy)
@@ -807,6 +835,7 @@ func (a *allocReconciler) computeReplacements(deploymentPlaceReady bool, desired
 			a.result.stop = append(a.result.stop, allocStopResult{
 				alloc:             prev,
 				statusDescription: allocRescheduled,
+				stopReason:        structs.AllocStopReasonFailed,
 			})
 			desiredChanges.Stop++
 		}
@@ -838,6 +867,7 @@ func (a *allocReconciler) computeMigrations(desiredChanges *structs.DesiredUpdat
 		a.result.stop = append(a.result.stop, allocStopResult{
 			alloc:             alloc,
 			statusDescription: allocMigrating,
+			stopReason:        a.migrateStopReason(alloc),
 		})
 		a.result.place = append(a.result.place, allocPlaceResult{
 			name:          alloc.Name,
@@ -921,12 +951,12 @@ func (a *allocReconciler) computeStop(group *structs.TaskGroup, nameIndex *alloc
 	// Mark all lost allocations for stop.
 	var stop allocSet
 	stop = stop.union(lost)
-	a.markDelayed(lost, structs.AllocClientStatusLost, allocLost, followupEvals)
+	a.markDelayed(lost, structs.AllocClientStatusLost, allocLost, structs.AllocStopReasonLost, followupEvals)
 
 	// Mark all failed reconnects for stop.
 	failedReconnects := reconnecting.filterByFailedReconnect()
 	stop = stop.union(failedReconnects)
-	a.markStop(failedReconnects, structs.AllocClientStatusFailed, allocRescheduled)
+	a.markStop(failedReconnects, structs.AllocClientStatusFailed, allocRescheduled, structs.AllocStopReasonFailed)
 	reconnecting = reconnecting.difference(failedReconnects)
 
 	// If we are still deploying or creating canaries, don't stop them
@@ -954,6 +984,7 @@ func (a *allocReconciler) computeStop(group *structs.TaskGroup, nameIndex *alloc
 				a.result.stop = append(a.result.stop, allocStopResult{
 					alloc:             alloc,
 					statusDescription: allocNotNeeded,
+					stopReason:        structs.AllocStopReasonJobUpdate,
 				})
 				delete(untainted, id)
 
@@ -976,6 +1007,7 @@ func (a *allocReconciler) computeStop(group *structs.TaskGroup, nameIndex *alloc
 			a.result.stop = append(a.result.stop, allocStopResult{
 				alloc:             alloc,
 				statusDescription: allocNotNeeded,
+				stopReason:        structs.AllocStopReasonScaleIn,
 			})
 			delete(migrate, id)
 			stop[id] = alloc
@@ -1004,6 +1036,7 @@ func (a *allocReconciler) computeStop(group *structs.TaskGroup, nameIndex *alloc
 			a.result.stop = append(a.result.stop, allocStopResult{
 				alloc:             alloc,
 				statusDescription: allocNotNeeded,
+				stopReason:        structs.AllocStopReasonScaleIn,
 			})
 			delete(untainted, id)
 
@@ -1021,6 +1054,7 @@ func (a *allocReconciler) computeStop(group *structs.TaskGroup, nameIndex *alloc
 		a.result.stop = append(a.result.stop, allocStopResult{
 			alloc:             alloc,
 			statusDescription: allocNotNeeded,
+			stopReason:        structs.AllocStopReasonScaleIn,
 		})
 		delete(untainted, id)
 
@@ -1054,6 +1088,7 @@ func (a *allocReconciler) computeStopByReconnecting(untainted, reconnecting, sto
 			a.result.stop = append(a.result.stop, allocStopResult{
 				alloc:             reconnectingAlloc,
 				statusDescription: allocNotNeeded,
+				stopReason:        structs.AllocStopReasonReconnect,
 			})
 			delete(reconnecting, reconnectingAlloc.ID)
 
@@ -1103,6 +1138,7 @@ func (a *allocReconciler) computeStopByReconnecting(untainted, reconnecting, sto
 			a.result.stop = append(a.result.stop, allocStopResult{
 				alloc:             stopAlloc,
 				statusDescription: statusDescription,
+				stopReason:        structs.AllocStopReasonReconnect,
 			})
 			delete(deleteSet, stopAlloc.ID)
 
@@ -1154,7 +1190,7 @@ func (a *allocReconciler) createRescheduleLaterEvals(rescheduleLater []*delayedR
 	// Create updates that will be applied to the allocs to mark the FollowupEvalID
 	for allocID, evalID := range allocIDToFollowupEvalID {
 		existingAlloc := all[allocID]
-		updatedAlloc := existingAlloc.Copy()
+		updatedAlloc := existingAlloc.CopySkipJob()
 		updatedAlloc.FollowupEvalID = evalID
 		a.result.attributeUpdates[updatedAlloc.ID] = updatedAlloc
 	}
@@ -1325,7 +1361,7 @@ func (a *allocReconciler) createTimeoutLaterEvals(disconnecting allocSet, tgName
 
 		// Create updates that will be applied to the allocs to mark the FollowupEvalID
 		// and the unknown ClientStatus and AllocState.
-		updatedAlloc := timeoutInfo.alloc.Copy()
+		updatedAlloc := timeoutInfo.alloc.CopySkipJob()
 		updatedAlloc.ClientStatus = structs.AllocClientStatusUnknown
 		updatedAlloc.AppendState(structs.AllocStateFieldClientStatus, structs.AllocClientStatusUnknown)
 		updatedAlloc.ClientDescription = allocUnknown
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// systemUpdateLimits is used to roll out the destructive updates of a system
+// job across nodes in batches. Each task group with an update strategy is
+// updated on at most max_parallel nodes at a time. Unless the group's health
+// is set manually, the allocations of the current job version that are not
+// healthy yet count against that limit, so that the next batch of nodes is only
+// updated once the previous one is healthy.
+type systemUpdateLimits struct {
+	// limits is the number of destructive updates that may be made for each
+	// task group with an update strategy.
+	limits map[string]int
+
+	// stagger is the shortest stagger of the task groups with an update
+	// strategy, used to re-evaluate the job once the limit is reached.
+	stagger time.Duration
+}
+
+// newSystemUpdateLimits computes the update limits of the system job given its
+// live allocations.
+func newSystemUpdateLimits(job *structs.Job, live []*structs.Allocation) *systemUpdateLimits {
+	l := &systemUpdateLimits{
+		limits: make(map[string]int),
+	}
+
+	gated := make(map[string]bool)
+	for _, tg := range job.TaskGroups {
+		if tg.Update.IsEmpty() {
+			continue
+		}
+		l.limits[tg.Name] = tg.Update.MaxParallel
+		gated[tg.Name] = tg.Update.HealthCheck != structs.UpdateStrategyHealthCheck_Manual
+		if l.stagger == 0 || tg.Update.Stagger < l.stagger {
+			l.stagger = tg.Update.Stagger
+		}
+	}
+
+	for _, alloc := range live {
+		if !gated[alloc.TaskGroup] || alloc.Job == nil {
+			continue
+		}
+		if alloc.Job.JobModifyIndex != job.JobModifyIndex {
+			continue
+		}
+		if !alloc.DeploymentStatus.IsHealthy() && l.limits[alloc.TaskGroup] > 0 {
+			l.limits[alloc.TaskGroup]--
+		}
+	}
+
+	return l
+}
+
+// empty returns whether no task group of the job has an update strategy, in
+// which case the job level stagger and max_parallel apply.
+func (l *systemUpdateLimits) empty() bool {
+	return len(l.limits) == 0
+}
+
+// evictAndPlace evicts and places the allocations to update within the limit
+// of their task group. It returns whether the limit of any task group was
+// reached.
+func (l *systemUpdateLimits) evictAndPlace(ctx Context, diff *diffResult, allocs []allocTuple) bool {
+	byGroup := make(map[string][]allocTuple)
+	var groups []string
+	for _, a := range allocs {
+		name := a.TaskGroup.Name
+		if _, ok := byGroup[name]; !ok {
+			groups = append(groups, name)
+		}
+		byGroup[name] = append(byGroup[name], a)
+	}
+
+	limitReached := false
+	for _, name := range groups {
+		limit, ok := l.limits[name]
+		if !ok {
+			limit = len(byGroup[name])
+		}
+		if evictAndPlace(ctx, diff, byGroup[name], allocUpdating, &limit) {
+			limitReached = true
+		}
+		if ok {
+			l.limits[name] = limit
+		}
+	}
+	return limitReached
+}
@@ -234,6 +234,13 @@ func stopResultsToNames(stop []allocStopResult) []string {
 	return names
 }
 
+func assertStopReasons(t *testing.T, reason string, stop []allocStopResult) {
+	t.Helper()
+	for _, s := range stop {
+		require.Equal(t, reason, s.stopReason, "alloc %s", s.alloc.Name)
+	}
+}
+
 func attributeUpdatesToNames(attributeUpdates map[string]*structs.Allocation) []string {
 	names := make([]string, 0, len(attributeUpdates))
 	for _, a := range attributeUpdates {
@@ -447,6 +454,7 @@ func TestReconciler_ScaleDown_Partial(t *testing.T) {
 	})
 
 	assertNamesHaveIndexes(t, intRange(10, 19), stopResultsToNames(r.stop))
+	assertStopReasons(t, structs.AllocStopReasonScaleIn, r.stop)
 }
 
 // Tests the reconciler properly handles stopping allocations for a job that has
@@ -1087,6 +1095,43 @@ func TestReconciler_DrainNode(t *testing.T) {
 	assertPlaceResultsHavePreviousAllocs(t, 2, r.place)
 	// These should not have the reschedule field set
 	assertPlacementsAreRescheduled(t, 0, r.place)
+	assertStopReasons(t, structs.AllocStopReasonDrain, r.stop)
+}
+
+// Tests the reconciler properly stops allocations stopped by users, which are
+// migrated without their node being drained.
+func TestReconciler_MigrateUserStop(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+
+	// Create 10 existing allocations
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		allocs = append(allocs, alloc)
+	}
+	allocs[0].DesiredTransition.Migrate = helper.BoolToPtr(true)
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job,
+		nil, allocs, nil, "", 50, true)
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		place: 1,
+		stop:  1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Migrate: 1,
+				Ignore:  9,
+			},
+		},
+	})
+	assertStopReasons(t, structs.AllocStopReasonUserStop, r.stop)
 }
 
 // Tests the reconciler properly handles draining nodes with allocations while
@@ -1306,6 +1351,7 @@ func TestReconciler_JobStopped(t *testing.T) {
 			})
 
 			assertNamesHaveIndexes(t, intRange(0, 9), stopResultsToNames(r.stop))
+			assertStopReasons(t, structs.AllocStopReasonUserStop, r.stop)
 		})
 	}
 }
@@ -2026,6 +2072,7 @@ func TestReconciler_RescheduleNow_Service(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(1, 1, 4, 4), placeResultsToNames(r.place))
 	assertPlaceResultsHavePreviousAllocs(t, 1, r.place)
 	assertPlacementsAreRescheduled(t, 1, r.place)
+	assertStopReasons(t, structs.AllocStopReasonFailed, r.stop)
 }
 
 // Tests rescheduling failed service allocations when there's clock drift (upto a second)
@@ -3488,6 +3535,57 @@ func TestReconciler_NewCanaries(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(0, 1), placeResultsToNames(r.place))
 }
 
+// Tests the reconciler copies the promotion gate of the group to the
+// deployment it creates for new canaries
+func TestReconciler_NewCanaries_PromotionGate(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Update = canaryUpdate.Copy()
+	job.TaskGroups[0].Update.AutoPromote = true
+	job.TaskGroups[0].Update.PromotionGate = "error-rate"
+
+	// Create 10 allocations from the old job
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		allocs = append(allocs, alloc)
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnDestructive, false, job.ID, job,
+		nil, allocs, nil, "", 50, true)
+	r := reconciler.Compute()
+
+	newD := structs.NewDeployment(job, 50)
+	newD.StatusDescription = structs.DeploymentStatusDescriptionRunningPromotionGate
+	newD.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		AutoPromote:     true,
+		PromotionGate:   "error-rate",
+		DesiredCanaries: 2,
+		DesiredTotal:    10,
+	}
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  newD,
+		deploymentUpdates: nil,
+		place:             2,
+		inplace:           0,
+		stop:              0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Canary: 2,
+				Ignore: 10,
+			},
+		},
+	})
+}
+
 // Tests the reconciler creates new canaries when the job changes and the
 // canary count is greater than the task group count
 func TestReconciler_NewCanaries_CountGreater(t *testing.T) {
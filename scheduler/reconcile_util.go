@@ -50,6 +50,7 @@ type allocStopResult struct {
 	clientStatus      string
 	statusDescription string
 	followupEvalID    string
+	stopReason        string
 }
 
 // allocPlaceResult contains the information required to place a single
@@ -291,6 +292,13 @@ func (a allocSet) filterByTainted(taintedNodes map[string]*structs.Node, serverS
 			continue
 		}
 
+		// Pinned allocs stay on draining or down nodes until they are
+		// unpinned, stopped, or their node reaches its drain deadline.
+		if nodeIsTainted && taintedNode != nil && alloc.DesiredTransition.ShouldPin() {
+			untainted[alloc.ID] = alloc
+			continue
+		}
+
 		// Non-terminal allocs that should migrate should always migrate
 		if alloc.DesiredTransition.ShouldMigrate() {
 			migrate[alloc.ID] = alloc
@@ -797,6 +797,84 @@ func TestAllocSet_filterByTainted(t *testing.T) {
 			ignore: allocSet{},
 			lost:   allocSet{},
 		},
+		{
+			name:                        "pinned",
+			supportsDisconnectedClients: false,
+			now:                         time.Now(),
+			taintedNodes:                nodes,
+			skipNilNodeTest:             true,
+			all: allocSet{
+				// Pinned allocs stay on draining nodes
+				"pinned1": {
+					ID:                "pinned1",
+					ClientStatus:      structs.AllocClientStatusRunning,
+					Job:               testJob,
+					NodeID:            "draining",
+					DesiredTransition: structs.DesiredTransition{Migrate: helper.BoolToPtr(true), Pinned: helper.BoolToPtr(true)},
+				},
+				// Pinned allocs are not lost on down nodes
+				"pinned2": {
+					ID:                "pinned2",
+					ClientStatus:      structs.AllocClientStatusRunning,
+					Job:               testJob,
+					NodeID:            "lost",
+					DesiredTransition: structs.DesiredTransition{Pinned: helper.BoolToPtr(true)},
+				},
+				// Pinned allocs on GC'd nodes are lost
+				"lost1": {
+					ID:                "lost1",
+					ClientStatus:      structs.AllocClientStatusRunning,
+					Job:               testJob,
+					NodeID:            "nil",
+					DesiredTransition: structs.DesiredTransition{Pinned: helper.BoolToPtr(true)},
+				},
+				// Unpinned allocs migrate
+				"migrating1": {
+					ID:                "migrating1",
+					ClientStatus:      structs.AllocClientStatusRunning,
+					Job:               testJob,
+					NodeID:            "draining",
+					DesiredTransition: structs.DesiredTransition{Migrate: helper.BoolToPtr(true), Pinned: helper.BoolToPtr(false)},
+				},
+			},
+			untainted: allocSet{
+				"pinned1": {
+					ID:                "pinned1",
+					ClientStatus:      structs.AllocClientStatusRunning,
+					Job:               testJob,
+					NodeID:            "draining",
+					DesiredTransition: structs.DesiredTransition{Migrate: helper.BoolToPtr(true), Pinned: helper.BoolToPtr(true)},
+				},
+				"pinned2": {
+					ID:                "pinned2",
+					ClientStatus:      structs.AllocClientStatusRunning,
+					Job:               testJob,
+					NodeID:            "lost",
+					DesiredTransition: structs.DesiredTransition{Pinned: helper.BoolToPtr(true)},
+				},
+			},
+			migrate: allocSet{
+				"migrating1": {
+					ID:                "migrating1",
+					ClientStatus:      structs.AllocClientStatusRunning,
+					Job:               testJob,
+					NodeID:            "draining",
+					DesiredTransition: structs.DesiredTransition{Migrate: helper.BoolToPtr(true), Pinned: helper.BoolToPtr(false)},
+				},
+			},
+			disconnecting: allocSet{},
+			reconnecting:  allocSet{},
+			ignore:        allocSet{},
+			lost: allocSet{
+				"lost1": {
+					ID:                "lost1",
+					ClientStatus:      structs.AllocClientStatusRunning,
+					Job:               testJob,
+					NodeID:            "nil",
+					DesiredTransition: structs.DesiredTransition{Pinned: helper.BoolToPtr(true)},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"fmt"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
@@ -43,6 +44,7 @@ type SystemScheduler struct {
 	nodesByDC     map[string]int
 
 	limitReached bool
+	stagger      time.Duration
 	nextEval     *structs.Evaluation
 
 	failedTGAllocs map[string]*structs.AllocMetric
@@ -149,6 +151,9 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Construct the placement stack
 	s.stack = NewSystemStack(s.sysbatch, s.ctx)
+	if s.eval.ExplainPlacements {
+		s.ctx.ExplainPlacements()
+	}
 	if !s.job.Stopped() {
 		s.stack.SetJob(s.job)
 	}
@@ -159,16 +164,11 @@ func (s *SystemScheduler) process() (bool, error) {
 		return false, err
 	}
 
-	// If the plan is a no-op, we can bail. If AnnotatePlan is set submit the plan
-	// anyways to get the annotations.
-	if s.plan.IsNoOp() && !s.eval.AnnotatePlan {
-		return true, nil
-	}
-
 	// If the limit of placements was reached we need to create an evaluation
-	// to pickup from here after the stagger period.
+	// to pickup from here after the stagger period. The
+	// plan may be a no-op while the updated allocations aren't healthy yet.
 	if s.limitReached && s.nextEval == nil {
-		s.nextEval = s.eval.NextRollingEval(s.job.Update.Stagger)
+		s.nextEval = s.eval.NextRollingEval(s.stagger)
 		if err := s.planner.CreateEval(s.nextEval); err != nil {
 			s.logger.Error("failed to make next eval for rolling update", "error", err)
 			return false, err
@@ -176,6 +176,12 @@ func (s *SystemScheduler) process() (bool, error) {
 		s.logger.Debug("rolling update limit reached, next eval created", "next_eval_id", s.nextEval.ID)
 	}
 
+	// If the plan is a no-op, we can bail. If AnnotatePlan is set submit the plan
+	// anyways to get the annotations.
+	if s.plan.IsNoOp() && !s.eval.AnnotatePlan {
+		return true, nil
+	}
+
 	// Submit the plan
 	result, newState, err := s.planner.SubmitPlan(s.plan)
 	s.planResult = result
@@ -238,19 +244,23 @@ func (s *SystemScheduler) computeJobAllocs() error {
 		"ignore", len(diff.ignore), "lost", len(diff.lost))
 
 	// Add all the allocs to stop
+	stopReason := structs.AllocStopReasonJobUpdate
+	if s.job == nil || s.job.Stopped() {
+		stopReason = structs.AllocStopReasonUserStop
+	}
 	for _, e := range diff.stop {
-		s.plan.AppendStoppedAlloc(e.Alloc, allocNotNeeded, "", "")
+		s.plan.AppendStoppedAlloc(e.Alloc, allocNotNeeded, "", "", stopReason)
 	}
 
 	// Add all the allocs to migrate
 	for _, e := range diff.migrate {
-		s.plan.AppendStoppedAlloc(e.Alloc, allocNodeTainted, "", "")
+		s.plan.AppendStoppedAlloc(e.Alloc, allocNodeTainted, "", "", structs.AllocStopReasonDrain)
 	}
 
 	// Lost allocations should be transitioned to desired status stop and client
 	// status lost.
 	for _, e := range diff.lost {
-		s.plan.AppendStoppedAlloc(e.Alloc, allocLost, structs.AllocClientStatusLost, "")
+		s.plan.AppendStoppedAlloc(e.Alloc, allocLost, structs.AllocClientStatusLost, "", structs.AllocStopReasonLost)
 	}
 
 	for _, e := range diff.disconnecting {
@@ -267,14 +277,25 @@ func (s *SystemScheduler) computeJobAllocs() error {
 		}
 	}
 
-	// Check if a rolling upgrade strategy is being used
-	limit := len(diff.update)
-	if !s.job.Stopped() && s.job.Update.Rolling() {
-		limit = s.job.Update.MaxParallel
+	// Check if a rolling upgrade strategy is being used, either per task
+	// group or for the whole job
+	var updateLimits *systemUpdateLimits
+	if !s.job.Stopped() {
+		updateLimits = newSystemUpdateLimits(s.job, live)
 	}
 
 	// Treat non in-place updates as an eviction and new placement.
-	s.limitReached = evictAndPlace(s.ctx, diff, diff.update, allocUpdating, &limit)
+	if updateLimits != nil && !updateLimits.empty() {
+		s.limitReached = updateLimits.evictAndPlace(s.ctx, diff, diff.update)
+		s.stagger = updateLimits.stagger
+	} else {
+		limit := len(diff.update)
+		if !s.job.Stopped() && s.job.Update.Rolling() {
+			limit = s.job.Update.MaxParallel
+			s.stagger = s.job.Update.Stagger
+		}
+		s.limitReached = evictAndPlace(s.ctx, diff, diff.update, allocUpdating, &limit)
+	}
 
 	// Nothing remaining to do if placement is not required
 	if len(diff.place) == 0 {
@@ -346,6 +367,9 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 		// Attempt to match the task group
 		option := s.stack.Select(missing.TaskGroup, &SelectOptions{AllocName: missing.Name})
 
+		// Explain the placement if requested
+		explainPlacement(s.plan, s.ctx.Metrics(), tgName, missing.Name, option)
+
 		if option == nil {
 			// If the task can't be placed on this node, update reporting data
 			// and continue to short circuit the loop
@@ -419,6 +443,7 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 		resources := &structs.AllocatedResources{
 			Tasks:          option.TaskResources,
 			TaskLifecycles: option.TaskLifecycles,
+			Overhead:       option.Overhead,
 			Shared: structs.AllocatedSharedResources{
 				DiskMB: int64(missing.TaskGroup.EphemeralDisk.SizeMB),
 			},
@@ -515,6 +540,7 @@ func (s *SystemScheduler) canHandle(trigger string) bool {
 	case structs.EvalTriggerQueuedAllocs:
 	case structs.EvalTriggerScaling:
 	case structs.EvalTriggerReconnect:
+	case structs.EvalTriggerRetry:
 	default:
 		switch s.sysbatch {
 		case true:
@@ -704,6 +704,131 @@ func TestSystemSched_JobModify_Rolling(t *testing.T) {
 	}
 }
 
+func TestSystemSched_JobModify_RollingHealthGated(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name        string
+		healthCheck string
+		healthy     []bool
+		expUpdates  int
+		expNextEval bool
+	}{
+		{
+			name:        "no updated allocs",
+			healthCheck: structs.UpdateStrategyHealthCheck_Checks,
+			expUpdates:  3,
+			expNextEval: true,
+		},
+		{
+			name:        "updated allocs healthy",
+			healthCheck: structs.UpdateStrategyHealthCheck_Checks,
+			healthy:     []bool{true, true, true},
+			expUpdates:  3,
+			expNextEval: true,
+		},
+		{
+			name:        "updated allocs not healthy yet",
+			healthCheck: structs.UpdateStrategyHealthCheck_Checks,
+			healthy:     []bool{true, false, false},
+			expUpdates:  1,
+			expNextEval: true,
+		},
+		{
+			name:        "batch in progress",
+			healthCheck: structs.UpdateStrategyHealthCheck_TaskStates,
+			healthy:     []bool{false, false, false},
+			expUpdates:  0,
+			expNextEval: true,
+		},
+		{
+			name:        "manual health",
+			healthCheck: structs.UpdateStrategyHealthCheck_Manual,
+			healthy:     []bool{false, false, false},
+			expUpdates:  3,
+			expNextEval: true,
+		},
+		{
+			name:        "last batch",
+			healthCheck: structs.UpdateStrategyHealthCheck_Checks,
+			healthy:     []bool{true, true, true, true, true, true, true, true},
+			expUpdates:  2,
+			expNextEval: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+			nodes := createNodes(t, h, 10)
+
+			job := mock.SystemJob()
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+			// Update the job with a destructive change and an update
+			// strategy for its group
+			job2 := job.Copy()
+			job2.TaskGroups[0].Update = &structs.UpdateStrategy{
+				Stagger:         20 * time.Second,
+				MaxParallel:     3,
+				HealthCheck:     tc.healthCheck,
+				MinHealthyTime:  10 * time.Second,
+				HealthyDeadline: 5 * time.Minute,
+			}
+			job2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job2))
+			job2, err := h.State.JobByID(nil, job.Namespace, job.ID)
+			require.NoError(t, err)
+
+			// The first nodes run allocations of the updated job
+			var allocs []*structs.Allocation
+			for i, node := range nodes {
+				alloc := mock.Alloc()
+				alloc.Job = job
+				alloc.JobID = job.ID
+				alloc.NodeID = node.ID
+				alloc.Name = "my-job.web[0]"
+				if i < len(tc.healthy) {
+					alloc.Job = job2
+					alloc.DeploymentStatus = &structs.AllocDeploymentStatus{
+						Healthy: helper.BoolToPtr(tc.healthy[i]),
+					}
+				}
+				allocs = append(allocs, alloc)
+			}
+			require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    50,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+			require.NoError(t, h.Process(NewSystemScheduler, eval))
+
+			var updates int
+			if len(h.Plans) > 0 {
+				for _, updateList := range h.Plans[0].NodeUpdate {
+					updates += len(updateList)
+				}
+			}
+			require.Equal(t, tc.expUpdates, updates)
+
+			if !tc.expNextEval {
+				require.Empty(t, h.CreateEvals)
+				return
+			}
+			require.Len(t, h.CreateEvals, 1)
+			next := h.CreateEvals[0]
+			require.Equal(t, structs.EvalTriggerRollingUpdate, next.TriggeredBy)
+			require.Equal(t, 20*time.Second, next.Wait)
+		})
+	}
+}
+
 func TestSystemSched_JobModify_InPlace(t *testing.T) {
 	ci.Parallel(t)
 
@@ -58,6 +58,7 @@ type GenericStack struct {
 
 	distinctHostsConstraint    *DistinctHostsIterator
 	distinctPropertyConstraint *DistinctPropertyIterator
+	antiAffinity               *AntiAffinityIterator
 	binPack                    *BinPackIterator
 	jobAntiAff                 *JobAntiAffinityIterator
 	nodeReschedulingPenalty    *NodeReschedulingPenaltyIterator
@@ -66,6 +67,26 @@ type GenericStack struct {
 	nodeAffinity               *NodeAffinityIterator
 	spread                     *SpreadIterator
 	scoreNorm                  *ScoreNormalizationIterator
+
+	// explain disables the limit on the number of nodes scored, so that the
+	// placements are explained for every candidate node
+	explain bool
+
+	// ignoreSoftConstraints disables scoring nodes by the affinities and
+	// spreads of the job
+	ignoreSoftConstraints bool
+}
+
+// ExplainPlacements makes the stack evaluate every candidate node rather than
+// a sample of them, so that the explanation of a placement covers all of them.
+func (s *GenericStack) ExplainPlacements() {
+	s.explain = true
+}
+
+// IgnoreSoftConstraints makes the stack ignore the affinities and spreads of
+// the job, its task groups and tasks when scoring nodes.
+func (s *GenericStack) IgnoreSoftConstraints() {
+	s.ignoreSoftConstraints = true
 }
 
 func (s *GenericStack) SetNodes(baseNodes []*structs.Node) {
@@ -88,6 +109,9 @@ func (s *GenericStack) SetNodes(baseNodes []*structs.Node) {
 			limit = logLimit
 		}
 	}
+	if s.explain && len(baseNodes) > limit {
+		limit = len(baseNodes)
+	}
 	s.limit.SetLimit(limit)
 }
 
@@ -99,13 +123,22 @@ func (s *GenericStack) SetJob(job *structs.Job) {
 	jobVer := job.Version
 	s.jobVersion = &jobVer
 
-	s.jobConstraint.SetConstraints(job.Constraints)
+	s.jobConstraint.SetConstraints(jobConstraints(job))
 	s.distinctHostsConstraint.SetJob(job)
 	s.distinctPropertyConstraint.SetJob(job)
+	s.antiAffinity.SetJob(job)
 	s.binPack.SetJob(job)
 	s.jobAntiAff.SetJob(job)
-	s.nodeAffinity.SetJob(job)
-	s.spread.SetJob(job)
+	if s.ignoreSoftConstraints {
+		softless := *job
+		softless.Affinities = nil
+		softless.Spreads = nil
+		s.nodeAffinity.SetJob(&softless)
+		s.spread.SetJob(&softless)
+	} else {
+		s.nodeAffinity.SetJob(job)
+		s.spread.SetJob(job)
+	}
 	s.ctx.Eligibility().SetJob(job)
 	s.taskGroupCSIVolumes.SetNamespace(job.Namespace)
 	s.taskGroupCSIVolumes.SetJobID(job.ID)
@@ -151,6 +184,7 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	}
 	s.distinctHostsConstraint.SetTaskGroup(tg)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
+	s.antiAffinity.SetTaskGroup(tg)
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.binPack.SetTaskGroup(tg)
 	if options != nil {
@@ -160,10 +194,16 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	if options != nil {
 		s.nodeReschedulingPenalty.SetPenaltyNodes(options.PenaltyNodeIDs)
 	}
-	s.nodeAffinity.SetTaskGroup(tg)
-	s.spread.SetTaskGroup(tg)
+	if s.ignoreSoftConstraints {
+		softless := withoutSoftConstraints(tg)
+		s.nodeAffinity.SetTaskGroup(softless)
+		s.spread.SetTaskGroup(softless)
+	} else {
+		s.nodeAffinity.SetTaskGroup(tg)
+		s.spread.SetTaskGroup(tg)
+	}
 
-	if s.nodeAffinity.hasAffinities() || s.spread.hasSpreads() {
+	if !s.explain && (s.nodeAffinity.hasAffinities() || s.spread.hasSpreads()) {
 		// scoring spread across all nodes has quadratic behavior, so
 		// we need to consider a subset of nodes to keep evaluaton times
 		// reasonable but enough to ensure spread is correct. this
@@ -186,6 +226,21 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	return option
 }
 
+// withoutSoftConstraints returns a shallow copy of the task group without the
+// affinities and spreads of the group and its tasks.
+func withoutSoftConstraints(tg *structs.TaskGroup) *structs.TaskGroup {
+	softless := *tg
+	softless.Affinities = nil
+	softless.Spreads = nil
+	softless.Tasks = make([]*structs.Task, len(tg.Tasks))
+	for i, task := range tg.Tasks {
+		t := *task
+		t.Affinities = nil
+		softless.Tasks[i] = &t
+	}
+	return &softless
+}
+
 // SystemStack is the Stack used for the System scheduler. It is designed to
 // attempt to make placements on all nodes.
 type SystemStack struct {
@@ -203,6 +258,7 @@ type SystemStack struct {
 	taskGroupNetwork     *NetworkChecker
 
 	distinctPropertyConstraint *DistinctPropertyIterator
+	antiAffinity               *AntiAffinityIterator
 	binPack                    *BinPackIterator
 	scoreNorm                  *ScoreNormalizationIterator
 }
@@ -259,12 +315,15 @@ func NewSystemStack(sysbatch bool, ctx Context) *SystemStack {
 	// Filter on distinct property constraints.
 	s.distinctPropertyConstraint = NewDistinctPropertyIterator(ctx, s.wrappedChecks)
 
+	// Filter on anti-affinities with other jobs.
+	s.antiAffinity = NewAntiAffinityIterator(ctx, s.distinctPropertyConstraint)
+
 	// Create the quota iterator to determine if placements would result in
 	// the quota attached to the namespace of the job to go over.
 	// Note: the quota iterator must be the last feasibility iterator before
 	// we upgrade to ranking, or our quota usage will include ineligible
 	// nodes!
-	s.quota = NewQuotaIterator(ctx, s.distinctPropertyConstraint)
+	s.quota = NewQuotaIterator(ctx, s.antiAffinity)
 
 	// Upgrade from feasible to rank iterator
 	rankSource := NewFeasibleRankIterator(ctx, s.quota)
@@ -296,8 +355,9 @@ func (s *SystemStack) SetNodes(baseNodes []*structs.Node) {
 }
 
 func (s *SystemStack) SetJob(job *structs.Job) {
-	s.jobConstraint.SetConstraints(job.Constraints)
+	s.jobConstraint.SetConstraints(jobConstraints(job))
 	s.distinctPropertyConstraint.SetJob(job)
+	s.antiAffinity.SetJob(job)
 	s.binPack.SetJob(job)
 	s.ctx.Eligibility().SetJob(job)
 
@@ -326,6 +386,7 @@ func (s *SystemStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ran
 	}
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
+	s.antiAffinity.SetTaskGroup(tg)
 	s.binPack.SetTaskGroup(tg)
 
 	if contextual, ok := s.quota.(ContextualIterator); ok {
@@ -395,12 +456,15 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Filter on distinct property constraints.
 	s.distinctPropertyConstraint = NewDistinctPropertyIterator(ctx, s.distinctHostsConstraint)
 
+	// Filter on anti-affinities with other jobs.
+	s.antiAffinity = NewAntiAffinityIterator(ctx, s.distinctPropertyConstraint)
+
 	// Create the quota iterator to determine if placements would result in
 	// the quota attached to the namespace of the job to go over.
 	// Note: the quota iterator must be the last feasibility iterator before
 	// we upgrade to ranking, or our quota usage will include ineligible
 	// nodes!
-	s.quota = NewQuotaIterator(ctx, s.distinctPropertyConstraint)
+	s.quota = NewQuotaIterator(ctx, s.antiAffinity)
 
 	// Upgrade from feasible to rank iterator
 	rankSource := NewFeasibleRankIterator(ctx, s.quota)
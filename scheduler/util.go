@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"regexp"
+	"sort"
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
@@ -13,6 +15,10 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
+// nodeInterpolationRe matches the interpolation of node attributes and node
+// meta, such as ${attr.kernel.name} or ${meta.rack}.
+var nodeInterpolationRe = regexp.MustCompile(`\$\{((?:attr|meta)\.[a-zA-Z0-9_\-\.]+)\}`)
+
 // allocTuple is a tuple of the allocation name and potential alloc ID
 type allocTuple struct {
 	Name      string
@@ -184,7 +190,7 @@ func diffSystemAllocsForNode(
 				node.Status == structs.NodeStatusDisconnected &&
 				exist.ClientStatus == structs.AllocClientStatusRunning {
 
-				disconnect := exist.Copy()
+				disconnect := exist.CopySkipJob()
 				disconnect.ClientStatus = structs.AllocClientStatusUnknown
 				disconnect.AppendState(structs.AllocStateFieldClientStatus, structs.AllocClientStatusUnknown)
 				disconnect.ClientDescription = allocUnknown
@@ -563,6 +569,12 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 		if !reflect.DeepEqual(at.VolumeMounts, bt.VolumeMounts) {
 			return true
 		}
+		if !reflect.DeepEqual(at.Process, bt.Process) {
+			return true
+		}
+		if !reflect.DeepEqual(at.Identity, bt.Identity) {
+			return true
+		}
 
 		// Check the metadata
 		if !reflect.DeepEqual(
@@ -871,7 +883,7 @@ func inplaceUpdate(ctx Context, eval *structs.Evaluation, job *structs.Job,
 		// the current allocation is discounted when checking for feasibility.
 		// Otherwise we would be trying to fit the tasks current resources and
 		// updated resources. After select is called we can remove the evict.
-		ctx.Plan().AppendStoppedAlloc(update.Alloc, allocInPlace, "", "")
+		ctx.Plan().AppendStoppedAlloc(update.Alloc, allocInPlace, "", "", "")
 
 		// Attempt to match the task group
 		option := stack.Select(update.TaskGroup,
@@ -917,6 +929,7 @@ func inplaceUpdate(ctx Context, eval *structs.Evaluation, job *structs.Job,
 		newAlloc.AllocatedResources = &structs.AllocatedResources{
 			Tasks:          option.TaskResources,
 			TaskLifecycles: option.TaskLifecycles,
+			Overhead:       option.Overhead,
 			Shared: structs.AllocatedSharedResources{
 				DiskMB:   int64(update.TaskGroup.EphemeralDisk.SizeMB),
 				Ports:    update.Alloc.AllocatedResources.Shared.Ports,
@@ -937,13 +950,14 @@ func inplaceUpdate(ctx Context, eval *structs.Evaluation, job *structs.Job,
 }
 
 // evictAndPlace is used to mark allocations for evicts and add them to the
-// placement queue. evictAndPlace modifies both the diffResult and the
-// limit. It returns true if the limit has been reached.
+// placement queue. The allocations are evicted because of a job update.
+// evictAndPlace modifies both the diffResult and the limit. It returns true
+// if the limit has been reached.
 func evictAndPlace(ctx Context, diff *diffResult, allocs []allocTuple, desc string, limit *int) bool {
 	n := len(allocs)
 	for i := 0; i < n && i < *limit; i++ {
 		a := allocs[i]
-		ctx.Plan().AppendStoppedAlloc(a.Alloc, desc, "", "")
+		ctx.Plan().AppendStoppedAlloc(a.Alloc, desc, "", "", structs.AllocStopReasonJobUpdate)
 		diff.place = append(diff.place, a)
 	}
 	if n <= *limit {
@@ -972,14 +986,61 @@ func taskGroupConstraints(tg *structs.TaskGroup) tgConstrainTuple {
 	}
 
 	c.constraints = append(c.constraints, tg.Constraints...)
+	interpolated := []map[string]string{tg.Meta}
 	for _, task := range tg.Tasks {
 		c.drivers[task.Driver] = struct{}{}
 		c.constraints = append(c.constraints, task.Constraints...)
+		interpolated = append(interpolated, task.Meta, task.Env)
 	}
+	c.constraints = append(c.constraints, interpolationConstraints(interpolated...)...)
 
 	return c
 }
 
+// jobConstraints returns the constraints of the job, including the implicit
+// constraints on the node attributes interpolated by the job meta.
+func jobConstraints(job *structs.Job) []*structs.Constraint {
+	implicit := interpolationConstraints(job.Meta)
+	if len(implicit) == 0 {
+		return job.Constraints
+	}
+
+	constraints := make([]*structs.Constraint, 0, len(job.Constraints)+len(implicit))
+	constraints = append(constraints, job.Constraints...)
+	return append(constraints, implicit...)
+}
+
+// interpolationConstraints returns an is_set constraint for each node
+// attribute or node meta interpolated by the keys or values of the maps, so
+// tasks are only placed on nodes where the interpolation resolves.
+func interpolationConstraints(maps ...map[string]string) []*structs.Constraint {
+	targets := make(map[string]struct{})
+	for _, m := range maps {
+		for k, v := range m {
+			for _, s := range []string{k, v} {
+				for _, match := range nodeInterpolationRe.FindAllString(s, -1) {
+					targets[match] = struct{}{}
+				}
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	constraints := make([]*structs.Constraint, 0, len(targets))
+	for target := range targets {
+		constraints = append(constraints, &structs.Constraint{
+			LTarget: target,
+			Operand: structs.ConstraintAttributeIsSet,
+		})
+	}
+	sort.Slice(constraints, func(i, j int) bool {
+		return constraints[i].LTarget < constraints[j].LTarget
+	})
+	return constraints
+}
+
 // desiredUpdates takes the diffResult as well as the set of inplace and
 // destructive updates and returns a map of task groups to their set of desired
 // updates.
@@ -1104,7 +1165,7 @@ func updateNonTerminalAllocsToLost(plan *structs.Plan, tainted map[string]*struc
 			alloc.DesiredStatus == structs.AllocDesiredStatusEvict) &&
 			(alloc.ClientStatus == structs.AllocClientStatusRunning ||
 				alloc.ClientStatus == structs.AllocClientStatusPending) {
-			plan.AppendStoppedAlloc(alloc, allocLost, structs.AllocClientStatusLost, "")
+			plan.AppendStoppedAlloc(alloc, allocLost, structs.AllocClientStatusLost, "", structs.AllocStopReasonLost)
 		}
 	}
 }
@@ -1159,7 +1220,7 @@ func genericAllocUpdateFn(ctx Context, stack Stack, evalID string) allocUpdateTy
 		// the current allocation is discounted when checking for feasibility.
 		// Otherwise we would be trying to fit the tasks current resources and
 		// updated resources. After select is called we can remove the evict.
-		ctx.Plan().AppendStoppedAlloc(existing, allocInPlace, "", "")
+		ctx.Plan().AppendStoppedAlloc(existing, allocInPlace, "", "", "")
 
 		// Attempt to match the task group
 		option := stack.Select(newTG, &SelectOptions{AllocName: existing.Name})
@@ -1204,6 +1265,7 @@ func genericAllocUpdateFn(ctx Context, stack Stack, evalID string) allocUpdateTy
 		newAlloc.AllocatedResources = &structs.AllocatedResources{
 			Tasks:          option.TaskResources,
 			TaskLifecycles: option.TaskLifecycles,
+			Overhead:       option.Overhead,
 			Shared: structs.AllocatedSharedResources{
 				DiskMB: int64(newTG.EphemeralDisk.SizeMB),
 			},
@@ -1229,3 +1291,20 @@ func genericAllocUpdateFn(ctx Context, stack Stack, evalID string) allocUpdateTy
 		return false, false, newAlloc
 	}
 }
+
+// explainPlacement adds the explanation of a placement to the annotations of
+// the plan, if the placement was explained. The option is the selected node,
+// or nil if the allocation couldn't be placed.
+func explainPlacement(plan *structs.Plan, metrics *structs.AllocMetric, tgName, allocName string, option *RankedNode) {
+	explanation := metrics.PlacementExplanation()
+	if explanation == nil || plan.Annotations == nil {
+		return
+	}
+
+	explanation.TaskGroup = tgName
+	explanation.AllocName = allocName
+	if option != nil {
+		explanation.NodeID = option.Node.ID
+	}
+	plan.Annotations.PlacementExplanations = append(plan.Annotations.PlacementExplanations, explanation)
+}
@@ -1406,6 +1406,40 @@ func TestTaskGroupConstraints(t *testing.T) {
 		"taskGroupConstraints(%v) returned %v; want %v", tg, actConstrains.drivers, expDrivers)
 }
 
+func TestTaskGroupConstraints_Interpolation(t *testing.T) {
+	ci.Parallel(t)
+
+	tg := &structs.TaskGroup{
+		Meta: map[string]string{"zone": "${meta.zone}"},
+		Tasks: []*structs.Task{
+			{
+				Driver: "exec",
+				Meta:   map[string]string{"${attr.os.name}": "${node.datacenter}"},
+				Env:    map[string]string{"ADDR": "${attr.unique.network.ip-address}:${NOMAD_PORT_http}"},
+			},
+			{
+				Driver: "exec",
+				Env:    map[string]string{"ZONE": "${meta.zone}"},
+			},
+		},
+	}
+
+	require.Equal(t, []*structs.Constraint{
+		{LTarget: "${attr.os.name}", Operand: structs.ConstraintAttributeIsSet},
+		{LTarget: "${attr.unique.network.ip-address}", Operand: structs.ConstraintAttributeIsSet},
+		{LTarget: "${meta.zone}", Operand: structs.ConstraintAttributeIsSet},
+	}, taskGroupConstraints(tg).constraints)
+
+	job := mock.Job()
+	constraints := jobConstraints(job)
+	require.Equal(t, job.Constraints, constraints)
+
+	job.Meta["dc"] = "${meta.dc}"
+	constraints = jobConstraints(job)
+	require.Len(t, constraints, len(job.Constraints)+1)
+	require.Equal(t, "${meta.dc}", constraints[len(constraints)-1].LTarget)
+}
+
 func TestProgressMade(t *testing.T) {
 	ci.Parallel(t)
 